@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"strings"
@@ -11,10 +12,13 @@ import (
 	"time"
 
 	apiFilters "github.com/Azure/open-service-broker-azure/pkg/api/filters"
+	redisAsync "github.com/Azure/open-service-broker-azure/pkg/async/redis"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
 	"github.com/Azure/open-service-broker-azure/pkg/broker"
-	"github.com/Azure/open-service-broker-azure/pkg/crypto/aes256"
 	"github.com/Azure/open-service-broker-azure/pkg/http/filter"
 	"github.com/Azure/open-service-broker-azure/pkg/http/filters"
+	"github.com/Azure/open-service-broker-azure/pkg/redaction"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
 	"github.com/Azure/open-service-broker-azure/pkg/version"
 	log "github.com/Sirupsen/logrus"
 	"github.com/go-redis/redis"
@@ -38,6 +42,13 @@ func init() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	redactionConfig, err := getRedactionConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+	// Secrets that find their way into log messages or fields (e.g. by way of
+	// errors returned by Azure) are redacted before anything is written out
+	log.SetFormatter(redaction.NewFormatter(formatter, redactionConfig.LogMode))
 	log.SetLevel(log.InfoLevel)
 	log.WithField(
 		"logLevel",
@@ -86,16 +97,36 @@ func main() {
 	}
 	storageRedisClient := redis.NewClient(storageRedisOpts)
 	asyncRedisClient := redis.NewClient(asyncRedisOpts)
-
-	// Crypto
-	cryptoConfig, err := getCryptoConfig()
+	secondaryRedisConfig, err := getSecondaryRedisConfig()
 	if err != nil {
 		log.Fatal(err)
 	}
-	codec, err := aes256.NewCodec([]byte(cryptoConfig.AES256Key))
+	var secondaryStorageRedisClient *redis.Client
+	if secondaryRedisConfig.Host != "" {
+		secondaryStorageRedisOpts := &redis.Options{
+			Addr: fmt.Sprintf(
+				"%s:%d",
+				secondaryRedisConfig.Host,
+				secondaryRedisConfig.Port,
+			),
+			Password:   secondaryRedisConfig.Password,
+			DB:         secondaryRedisConfig.StorageDB,
+			MaxRetries: 5,
+		}
+		if secondaryRedisConfig.EnableTLS {
+			secondaryStorageRedisOpts.TLSConfig = &tls.Config{
+				ServerName: secondaryRedisConfig.Host,
+			}
+		}
+		secondaryStorageRedisClient = redis.NewClient(secondaryStorageRedisOpts)
+	}
+
+	// Crypto
+	cryptoConfig, err := getCryptoConfig()
 	if err != nil {
 		log.Fatal(err)
 	}
+	codec := cryptoConfig.Codec
 
 	// Assemble the filter chain
 	basicAuthConfig, err := getBasicAuthConfig()
@@ -120,17 +151,241 @@ func main() {
 		log.Fatal(err)
 	}
 
+	subscriptionRoutingConfig, err :=
+		getSubscriptionRoutingConfig(azureConfig.SubscriptionID)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// Each additional subscription needs modules of its own to manage the
+	// resources provisioned into it
+	modulesBySubscription := map[string][]service.Module{}
+	routing := subscriptionRoutingConfig.Routing
+	for _, subscriptionID := range routing.GetSubscriptionIDs() {
+		modulesBySubscription[subscriptionID], err = getModules(subscriptionID)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	provisioningConfig, err := getProvisioningConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	deprovisioningConfig, err := getDeprovisioningConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	asyncConfig, err := getAsyncConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	notificationsConfig, err := getNotificationsConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	idleDetectionConfig, err := getIdleDetectionConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	driftDetectionConfig, err := getDriftDetectionConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	credentialRotationConfig, err := getCredentialRotationConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	keyRotationConfig, err := getKeyRotationConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	costEstimationConfig, err := getCostEstimationConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	redactionConfig, err := getRedactionConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	taggingConfig, err := getTaggingConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	approvalConfig, err := getApprovalConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	sandboxConfig, err := getSandboxConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	gitOpsConfig, err := getGitOpsConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	throttlingConfig, err := getThrottlingConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+	// Azure clients send their requests using the default transport, which is
+	// wrapped so that responses from Azure Resource Manager can be observed for
+	// signs of throttling
+	var resourceProviderThrottle service.ResourceProviderThrottle
+	if throttlingConfig.Enabled {
+		throttlingMonitor := az.NewThrottlingMonitor(
+			az.ThrottlingConfig{
+				MaxRate:                    throttlingConfig.MaxRate,
+				MinRate:                    throttlingConfig.MinRate,
+				RemainingRequestsThreshold: throttlingConfig.RemainingRequestsThreshold,
+				RecoveryPeriod:             throttlingConfig.RecoveryPeriod,
+				DefaultRetryAfter:          throttlingConfig.DefaultRetryAfter,
+			},
+		)
+		http.DefaultTransport = az.NewThrottlingTransport(
+			http.DefaultTransport,
+			throttlingMonitor,
+			throttlingConfig.ResourceManagerHost,
+		)
+		resourceProviderThrottle = throttlingMonitor
+	}
+	// Likewise, so that the requests of traced steps can be summarized
+	debugTracingConfig, err := getDebugTracingConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+	var azureRequestRecorder service.AzureRequestRecorder
+	if debugTracingConfig.Mode != service.DebugTracingModeOff {
+		requestRecorder := az.NewRequestRecorder()
+		http.DefaultTransport = az.NewRecordingTransport(
+			http.DefaultTransport,
+			requestRecorder,
+		)
+		azureRequestRecorder = requestRecorder
+	}
+
+	telemetryConfig, err := getTelemetryConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	// Create broker
-	broker, err := broker.NewBroker(
-		storageRedisClient,
-		asyncRedisClient,
-		codec,
-		filterChain,
-		modules,
-		modulesConfig.MinStability,
-		azureConfig.DefaultLocation,
-		azureConfig.DefaultResourceGroup,
-	)
+	broker, err := broker.NewBroker(broker.Config{
+		StorageRedisClient:        storageRedisClient,
+		AsyncRedisClient:          asyncRedisClient,
+		Codec:                     codec,
+		FilterChain:               filterChain,
+		Modules:                   modules,
+		MinStability:              modulesConfig.MinStability,
+		DefaultAzureLocation:      azureConfig.DefaultLocation,
+		DefaultAzureResourceGroup: azureConfig.DefaultResourceGroup,
+		ProvisioningLimits: broker.ProvisioningLimits{
+			AzureSubscriptionID:     azureConfig.SubscriptionID,
+			MaxConcurrentProvisions: provisioningConfig.MaxConcurrency,
+			PerSubscriptionMax:      provisioningConfig.MaxConcurrencyBySubscription,
+			SlotTTL:                 provisioningConfig.SlotTTL,
+		},
+		RetryPolicy:                   provisioningConfig.RetryPolicy,
+		ConnectivityValidationModules: provisioningConfig.ValidateConnectivityModules,
+		FairScheduling: redisAsync.FairSchedulingConfig{
+			Enabled: asyncConfig.FairScheduling,
+			Weights: asyncConfig.FairSchedulingWeights,
+		},
+		WorkerPool: redisAsync.WorkerPoolConfig{
+			MinWorkers:      asyncConfig.MinWorkers,
+			MaxWorkers:      asyncConfig.MaxWorkers,
+			ScalingInterval: asyncConfig.WorkerScalingInterval,
+		},
+		TenantPools: redisAsync.TenantPoolsConfig{
+			GetPools:             asyncConfig.GetTenantPools,
+			ReloadInterval:       asyncConfig.TenantPoolsReloadInterval,
+			DefaultQueueCapacity: asyncConfig.TenantQueueCapacity,
+		},
+		NotificationSubscribers:     notificationsConfig.Subscribers,
+		StepOrderOverrides:          provisioningConfig.StepOrderOverrides,
+		AuditProvisioningParameters: provisioningConfig.AuditParameters,
+		IdleDetection: broker.IdleDetectionConfig{
+			Enabled:         idleDetectionConfig.Enabled,
+			CheckInterval:   idleDetectionConfig.CheckInterval,
+			IdlePeriod:      idleDetectionConfig.IdlePeriod,
+			Policy:          idleDetectionConfig.Policy,
+			PolicyByService: idleDetectionConfig.PolicyByService,
+		},
+		PricingTable:              costEstimationConfig.PricingTable,
+		ResponseRedactionMode:     redactionConfig.ResponseMode,
+		MetadataTagging:           taggingConfig.MetadataTagging,
+		ProvisioningDeduplication: provisioningConfig.Deduplication,
+		TeardownRetryPolicy:       deprovisioningConfig.TeardownRetryPolicy,
+		ProvisioningSLA:           provisioningConfig.SLA,
+		FailureGrace: broker.FailureGraceConfig{
+			WindowByModule: provisioningConfig.FailureGraceWindowByModule,
+			RetryInterval:  provisioningConfig.FailureGraceRetryInterval,
+		},
+		Approval: broker.ApprovalConfig{
+			Approver:           approvalConfig.Approver,
+			Modules:            approvalConfig.Modules,
+			Plans:              approvalConfig.Plans,
+			Timeout:            approvalConfig.Timeout,
+			PollInterval:       approvalConfig.PollInterval,
+			CostThreshold:      approvalConfig.CostThreshold,
+			CostThresholdByOrg: approvalConfig.CostThresholdByOrg,
+		},
+		ResourceProviderThrottle: resourceProviderThrottle,
+		KeyRotation: broker.KeyRotationConfig{
+			Enabled:       keyRotationConfig.Enabled,
+			KeyVersion:    cryptoConfig.AES256KeyVersion,
+			BatchSize:     keyRotationConfig.BatchSize,
+			BatchInterval: keyRotationConfig.BatchInterval,
+		},
+		SecondaryStorageRedisClient: secondaryStorageRedisClient,
+		DriftDetection: broker.DriftDetectionConfig{
+			Enabled:         driftDetectionConfig.Enabled,
+			CheckInterval:   driftDetectionConfig.CheckInterval,
+			Policy:          driftDetectionConfig.Policy,
+			PolicyByService: driftDetectionConfig.PolicyByService,
+		},
+		CredentialRotation: broker.CredentialRotationConfig{
+			Enabled:       credentialRotationConfig.Enabled,
+			DefaultTTL:    credentialRotationConfig.DefaultTTL,
+			MinTTL:        credentialRotationConfig.MinTTL,
+			CheckInterval: credentialRotationConfig.CheckInterval,
+			SecretStore:   credentialRotationConfig.SecretStore,
+		},
+		ProvisioningOverdueFactor: provisioningConfig.OverdueFactor,
+		CostCenterLabel:           costEstimationConfig.CostCenterLabel,
+		SubscriptionRouting: broker.SubscriptionRoutingConfig{
+			Routing:               routing,
+			ModulesBySubscription: modulesBySubscription,
+		},
+		ExpiryPolicy: sandboxConfig.ExpiryPolicy,
+		ProvisioningCapacity: broker.ProvisioningCapacityConfig{
+			MaxConcurrencyByModule: provisioningConfig.MaxConcurrencyByModule,
+			QueueDepthByModule:     provisioningConfig.QueueDepthByModule,
+		},
+		ManifestWriter:         gitOpsConfig.ManifestWriter,
+		SkippedSuccessCriteria: provisioningConfig.SkippedSuccessCriteria,
+		RecordStepExecutions:   provisioningConfig.RecordStepExecutions,
+		DebugTracing: broker.DebugTracingConfig{
+			Mode:     debugTracingConfig.Mode,
+			TTL:      debugTracingConfig.TTL,
+			MaxSteps: debugTracingConfig.MaxSteps,
+			Recorder: azureRequestRecorder,
+		},
+		TelemetrySampler: telemetryConfig.Sampler,
+	})
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -1,10 +1,27 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"net/url"
 	"strings"
+	"time"
 
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/api"
+	"github.com/Azure/open-service-broker-azure/pkg/approval"
+	redisAsync "github.com/Azure/open-service-broker-azure/pkg/async/redis"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/broker"
+	"github.com/Azure/open-service-broker-azure/pkg/crypto"
+	"github.com/Azure/open-service-broker-azure/pkg/crypto/aes256"
+	"github.com/Azure/open-service-broker-azure/pkg/crypto/versioned"
+	"github.com/Azure/open-service-broker-azure/pkg/gitops"
+	"github.com/Azure/open-service-broker-azure/pkg/notification"
+	"github.com/Azure/open-service-broker-azure/pkg/redaction"
+	"github.com/Azure/open-service-broker-azure/pkg/secrets"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/telemetry"
 	log "github.com/Sirupsen/logrus"
 	"github.com/kelseyhightower/envconfig"
 )
@@ -15,6 +32,18 @@ type logConfig struct {
 	Level    log.Level
 }
 
+// redactionConfig represents how secrets, such as the passwords and keys in
+// connection strings, appear in the broker's logs and in responses that
+// describe problems or echo parameters back. A mode of "full" redacts secrets
+// entirely, "partial" leaves only their last few characters visible, and
+// "none", which is meant only for development, leaves them as they are.
+type redactionConfig struct {
+	LogModeStr      string `envconfig:"LOG_REDACTION_MODE" default:"full"`
+	ResponseModeStr string `envconfig:"RESPONSE_REDACTION_MODE" default:"full"`
+	LogMode         redaction.Mode
+	ResponseMode    redaction.Mode
+}
+
 // redisConfig represents details for connecting to the Redis instance that
 // the broker itself relies on for storing state and orchestrating asynchronous
 // processes
@@ -27,10 +56,28 @@ type redisConfig struct {
 	EnableTLS bool   `envconfig:"REDIS_ENABLE_TLS" default:"false"`
 }
 
+// secondaryRedisConfig represents details for connecting to an optional,
+// secondary Redis instance to which persisted instances and bindings are
+// replicated asynchronously. Replication is disabled unless a host is given.
+type secondaryRedisConfig struct {
+	Host      string `envconfig:"REDIS_SECONDARY_HOST" default:""`
+	Port      int    `envconfig:"REDIS_SECONDARY_PORT" default:"6379"`
+	Password  string `envconfig:"REDIS_SECONDARY_PASSWORD" default:""`
+	StorageDB int    `envconfig:"REDIS_SECONDARY_STORAGE_DB" default:"0"`
+	EnableTLS bool   `envconfig:"REDIS_SECONDARY_ENABLE_TLS" default:"false"`
+}
+
 // cryptoConfig represents details (e.g. key) for encrypting and decrypting any
-// (potentially) sensitive information
+// (potentially) sensitive information. With field-level encryption enabled,
+// only secret fields are encrypted, each recording the version of the key it
+// was encrypted with; retired keys, specified as a comma-delimited list of
+// version:key pairs, remain usable for decrypting fields encrypted with them.
 type cryptoConfig struct {
-	AES256Key string `envconfig:"AES256_KEY" required:"true"`
+	AES256Key            string            `envconfig:"AES256_KEY" required:"true"`             // nolint: lll
+	AES256KeyVersion     string            `envconfig:"AES256_KEY_VERSION" default:"1"`         // nolint: lll
+	AES256RetiredKeys    map[string]string `envconfig:"AES256_RETIRED_KEYS"`                    // nolint: lll
+	FieldLevelEncryption bool              `envconfig:"FIELD_LEVEL_ENCRYPTION" default:"false"` // nolint: lll
+	Codec                crypto.Codec
 }
 
 type basicAuthConfig struct {
@@ -44,10 +91,321 @@ type modulesConfig struct {
 }
 
 type azureConfig struct {
+	SubscriptionID       string `envconfig:"AZURE_SUBSCRIPTION_ID"`
 	DefaultLocation      string `envconfig:"AZURE_DEFAULT_LOCATION"`
 	DefaultResourceGroup string `envconfig:"AZURE_DEFAULT_RESOURCE_GROUP"`
 }
 
+// subscriptionRoutingConfig represents the Azure subscriptions, in addition to
+// the broker's own, that instances may be provisioned into, as a
+// comma-delimited list of subscription IDs. Unless a provisioning request
+// names a subscription explicitly, instances of the organizations and spaces
+// mapped to subscriptions, each specified as a comma-delimited list of
+// guid:subscriptionID pairs, are provisioned into those subscriptions, and all
+// others according to the routing policy: "default", which provisions them
+// into the broker's own subscription, or "round-robin", which provisions them
+// into each subscription in the pool in turn.
+type subscriptionRoutingConfig struct {
+	Pool           []string          `envconfig:"AZURE_SUBSCRIPTION_POOL"`
+	PolicyStr      string            `envconfig:"AZURE_SUBSCRIPTION_ROUTING_POLICY" default:"default"` // nolint: lll
+	ByOrganization map[string]string `envconfig:"AZURE_SUBSCRIPTION_ROUTING_BY_ORGANIZATION"`          // nolint: lll
+	BySpace        map[string]string `envconfig:"AZURE_SUBSCRIPTION_ROUTING_BY_SPACE"`                 // nolint: lll
+	Routing        api.SubscriptionRouting
+}
+
+// provisioningConfig represents caps on the number of provisioning operations
+// that may be in-flight at once per Azure subscription, and how failed
+// provisioning steps are retried. Per-subscription caps are specified as a
+// comma-delimited list of subscriptionID:cap pairs. A cap of zero means no cap.
+// Retry counts and delays are likewise specified as comma-delimited lists of
+// errorCategory:value pairs and override the broker's default retry policy for
+// the categories they name. Connectivity to new instances is validated only for
+// those modules named in a comma-delimited list. The order in which a service's
+// provisioning steps are executed may be overridden using a comma-delimited
+// list of serviceName:steps pairs, where steps is a semicolon-delimited list of
+// step names. Steps that are omitted are skipped. If parameter auditing is
+// enabled, each new instance records the parameters that were requested for it
+// alongside those, with defaults applied, that it was effectively provisioned
+// with. If a deduplication window is specified, identical requests for the
+// same instance arriving within it are collapsed into a single provisioning
+// operation. Requests are compared by a fingerprint derived from a
+// comma-delimited list of fields drawn from service, plan, parameters,
+// organization, and space. Instances taking longer to provision than the SLA
+// target are counted as breaches of it; per-plan targets are specified as a
+// comma-delimited list of serviceName/planName:duration pairs and override the
+// default target. A target of zero means there is none. Failure grace windows
+// are specified as a comma-delimited list of moduleName:duration pairs; within
+// its module's window, an instance whose provisioning step has failed, and
+// exhausted the retry policy, is held as degraded and retried at the grace
+// retry interval before provisioning is considered failed. Platforms polling
+// an operation that has run for longer than its estimated duration multiplied
+// by the overdue factor are told it is taking longer than expected; a factor
+// of zero means they never are. Per-module caps on concurrent provisions are
+// specified as a comma-delimited list of moduleName:max pairs. Requests for a
+// capped module are refused while it is at its cap, unless a queue depth is
+// specified for it, likewise as moduleName:depth pairs, in which case up to
+// that many requests are queued until capacity frees up. Optional success
+// criteria that new instances needn't meet are specified as a comma-delimited
+// list of serviceName/planName:criteria pairs, where criteria is a
+// semicolon-delimited list of criterion names. If step execution recording is
+// enabled, each new instance records the execution of each of its provisioning
+// steps, so that a step whose task is re-delivered after the broker restarts
+// is skipped if it already completed and resumed if it was interrupted. A
+// per-subscription provisioning slot that isn't renewed, by the execution of
+// a provisioning step, within the slot TTL is presumed abandoned and freed.
+type provisioningConfig struct {
+	MaxConcurrency               int                      `envconfig:"PROVISIONING_MAX_CONCURRENCY" default:"0"`               // nolint: lll
+	MaxConcurrencyBySubscription map[string]int           `envconfig:"PROVISIONING_MAX_CONCURRENCY_BY_SUBSCRIPTION"`           // nolint: lll
+	SlotTTL                      time.Duration            `envconfig:"PROVISIONING_SLOT_TTL" default:"1h"`                     // nolint: lll
+	MaxRetriesByErrorCategory    map[string]int           `envconfig:"PROVISIONING_MAX_RETRIES_BY_ERROR_CATEGORY"`             // nolint: lll
+	RetryDelayByErrorCategory    map[string]time.Duration `envconfig:"PROVISIONING_RETRY_DELAY_BY_ERROR_CATEGORY"`             // nolint: lll
+	ValidateConnectivityModules  []string                 `envconfig:"PROVISIONING_VALIDATE_CONNECTIVITY_MODULES"`             // nolint: lll
+	StepOrderOverridesStrs       map[string]string        `envconfig:"PROVISIONING_STEP_ORDER_OVERRIDES"`                      // nolint: lll
+	AuditParameters              bool                     `envconfig:"PROVISIONING_AUDIT_PARAMETERS" default:"false"`          // nolint: lll
+	DedupWindow                  time.Duration            `envconfig:"PROVISIONING_DEDUP_WINDOW" default:"0"`                  // nolint: lll
+	DedupFingerprintStrs         []string                 `envconfig:"PROVISIONING_DEDUP_FINGERPRINT"`                         // nolint: lll
+	SLATarget                    time.Duration            `envconfig:"PROVISIONING_SLA_TARGET" default:"0"`                    // nolint: lll
+	SLATargetByPlan              map[string]time.Duration `envconfig:"PROVISIONING_SLA_TARGET_BY_PLAN"`                        // nolint: lll
+	FailureGraceWindowByModule   map[string]time.Duration `envconfig:"PROVISIONING_FAILURE_GRACE_WINDOW_BY_MODULE"`            // nolint: lll
+	FailureGraceRetryInterval    time.Duration            `envconfig:"PROVISIONING_FAILURE_GRACE_RETRY_INTERVAL" default:"1m"` // nolint: lll
+	OverdueFactor                float64                  `envconfig:"PROVISIONING_OVERDUE_FACTOR" default:"1.5"`              // nolint: lll
+	MaxConcurrencyByModule       map[string]int           `envconfig:"PROVISIONING_MAX_CONCURRENCY_BY_MODULE"`                 // nolint: lll
+	QueueDepthByModule           map[string]int           `envconfig:"PROVISIONING_QUEUE_DEPTH_BY_MODULE"`                     // nolint: lll
+	SkippedSuccessCriteriaStrs   map[string]string        `envconfig:"PROVISIONING_SKIPPED_SUCCESS_CRITERIA_BY_PLAN"`          // nolint: lll
+	RecordStepExecutions         bool                     `envconfig:"PROVISIONING_RECORD_STEP_EXECUTIONS" default:"false"`    // nolint: lll
+	RetryPolicy                  broker.RetryPolicy
+	StepOrderOverrides           map[string][]string
+	SkippedSuccessCriteria       map[string][]string
+	Deduplication                api.ProvisioningDeduplication
+	SLA                          service.ProvisioningSLA
+}
+
+// deprovisioningConfig represents how long the broker keeps retrying a
+// deprovisioning step that can't delete a resource because resources deleted
+// by earlier steps haven't finished letting go of it
+type deprovisioningConfig struct {
+	TeardownMaxRetries  int           `envconfig:"DEPROVISIONING_TEARDOWN_MAX_RETRIES" default:"10"`  // nolint: lll
+	TeardownRetryDelay  time.Duration `envconfig:"DEPROVISIONING_TEARDOWN_RETRY_DELAY" default:"30s"` // nolint: lll
+	TeardownRetryPolicy broker.RetryPolicy
+}
+
+// asyncConfig represents configuration options for the broker's async engine.
+// With fair scheduling enabled, organizations take turns having their
+// asynchronous tasks executed. Weights, specified as a comma-delimited list of
+// organizationGUID:weight pairs, let the named organizations have more than
+// one task executed per turn. The engine runs between MinWorkers and
+// MaxWorkers workers, adding or removing them, every WorkerScalingInterval,
+// according to how many tasks are waiting. Organizations named in the
+// TenantPoolsFile are instead given workers of their own, which that file
+// sizes; it is read again every TenantPoolsReloadInterval, so organizations can
+// be given pools without the broker being restarted. TenantQueueCapacity
+// bounds the tasks each organization without a pool may have waiting.
+type asyncConfig struct {
+	FairScheduling            bool           `envconfig:"ASYNC_FAIR_SCHEDULING" default:"false"`            // nolint: lll
+	FairSchedulingWeights     map[string]int `envconfig:"ASYNC_FAIR_SCHEDULING_WEIGHTS"`                    // nolint: lll
+	MinWorkers                int            `envconfig:"ASYNC_MIN_WORKERS" default:"5"`                    // nolint: lll
+	MaxWorkers                int            `envconfig:"ASYNC_MAX_WORKERS" default:"5"`                    // nolint: lll
+	WorkerScalingInterval     time.Duration  `envconfig:"ASYNC_WORKER_SCALING_INTERVAL" default:"10s"`      // nolint: lll
+	TenantPoolsFile           string         `envconfig:"ASYNC_TENANT_POOLS_FILE"`                          // nolint: lll
+	TenantPoolsReloadInterval time.Duration  `envconfig:"ASYNC_TENANT_POOLS_RELOAD_INTERVAL" default:"30s"` // nolint: lll
+	TenantQueueCapacity       int            `envconfig:"ASYNC_TENANT_QUEUE_CAPACITY" default:"0"`          // nolint: lll
+	GetTenantPools            redisAsync.TenantPoolsFn
+}
+
+// throttlingConfig represents whether, and how, the broker slows the
+// execution of provisioning, updating and deprovisioning steps that make
+// requests to Azure resource providers when those providers throttle the
+// broker, or report that few requests remain before they will. A throttled
+// provider is paced at half its previous rate, in steps per minute, but never
+// less than MinRate, and recovers to MaxRate over RecoveryPeriod.
+type throttlingConfig struct {
+	Enabled                    bool          `envconfig:"AZURE_THROTTLING_BACKOFF_ENABLED" default:"false"`            // nolint: lll
+	MaxRate                    float64       `envconfig:"AZURE_THROTTLING_MAX_RATE" default:"60"`                      // nolint: lll
+	MinRate                    float64       `envconfig:"AZURE_THROTTLING_MIN_RATE" default:"1"`                       // nolint: lll
+	RemainingRequestsThreshold int           `envconfig:"AZURE_THROTTLING_REMAINING_REQUESTS_THRESHOLD" default:"100"` // nolint: lll
+	RecoveryPeriod             time.Duration `envconfig:"AZURE_THROTTLING_RECOVERY_PERIOD" default:"10m"`              // nolint: lll
+	DefaultRetryAfter          time.Duration `envconfig:"AZURE_THROTTLING_DEFAULT_RETRY_AFTER" default:"30s"`          // nolint: lll
+	ResourceManagerHost        string
+}
+
+// debugTracingConfig represents whether, and for which instances, the broker
+// traces the inputs and outputs of each provisioning and updating step, along
+// with summaries of the requests each step makes to Azure. A mode of "off"
+// traces nothing, "requested" traces only those instances whose provisioning
+// asked for it with the "debug" parameter, and "all" traces every instance.
+// Each instance's most recent MaxSteps traces are kept until TTL passes
+// without another being added.
+type debugTracingConfig struct {
+	ModeStr  string        `envconfig:"DEBUG_TRACING_MODE" default:"off"`
+	TTL      time.Duration `envconfig:"DEBUG_TRACING_TTL" default:"168h"`
+	MaxSteps int           `envconfig:"DEBUG_TRACING_MAX_STEPS" default:"200"`
+	Mode     service.DebugTracingMode
+}
+
+// telemetryConfig represents how much of the broker's telemetry is kept. When
+// DEBUG_TRACING_MODE is "all", the steps of only TraceSampleRate of the
+// instances that didn't ask for tracing are traced, although failed steps are
+// traced regardless. Metrics whose labels aren't bounded by the catalog, such
+// as those labelled by organization, subscription or resource provider, are
+// reported for at most MaxSeriesPerMetric label values, the remainder being
+// aggregated into a single series; zero means there is no limit. Both may be
+// overridden by SamplingFile, which is read again every SamplingReloadInterval,
+// so that sampling can be changed without the broker being restarted.
+type telemetryConfig struct {
+	TraceSampleRate        float64       `envconfig:"TELEMETRY_TRACE_SAMPLE_RATE" default:"1"`          // nolint: lll
+	MaxSeriesPerMetric     int           `envconfig:"TELEMETRY_MAX_SERIES_PER_METRIC" default:"0"`      // nolint: lll
+	SamplingFile           string        `envconfig:"TELEMETRY_SAMPLING_FILE"`                          // nolint: lll
+	SamplingReloadInterval time.Duration `envconfig:"TELEMETRY_SAMPLING_RELOAD_INTERVAL" default:"30s"` // nolint: lll
+	Sampler                telemetry.Sampler
+}
+
+// notificationsConfig represents the subscribers that are notified when an
+// instance finishes, or fails, provisioning. Subscribers are specified as a
+// JSON array; see notification.SubscriberConfig.
+type notificationsConfig struct {
+	SubscribersJSON string `envconfig:"NOTIFICATION_SUBSCRIBERS"`
+	Subscribers     []notification.Subscriber
+}
+
+// costEstimationConfig represents the pricing table the broker uses to
+// estimate the monthly cost of new instances. The table is specified as a JSON
+// array of entries; see service.PricingTableEntry. Costs are only estimated if
+// a table is specified. Estimated costs are charged back to the cost center
+// named by an instance's tag, or provisioning context label, named by
+// CostCenterLabel.
+type costEstimationConfig struct {
+	PricingTableJSON string `envconfig:"COST_ESTIMATION_PRICING_TABLE"`
+	Currency         string `envconfig:"COST_ESTIMATION_CURRENCY" default:"USD"`
+	CostCenterLabel  string `envconfig:"COST_ESTIMATION_COST_CENTER_LABEL" default:"cost-center"` // nolint: lll
+	PricingTable     *service.PricingTable
+}
+
+// idleDetectionConfig represents whether, and how often, the broker checks
+// for instances whose underlying resources have been idle for at least the
+// idle period, and what it does about them. A policy of "notify" notifies
+// subscribers, "suspend" additionally suspends instances of those services
+// that support it, and "ignore" does nothing. Per-service policies are
+// specified as a comma-delimited list of serviceName:policy pairs and override
+// the default policy.
+type idleDetectionConfig struct {
+	Enabled             bool              `envconfig:"IDLE_DETECTION_ENABLED" default:"false"`     // nolint: lll
+	CheckInterval       time.Duration     `envconfig:"IDLE_DETECTION_CHECK_INTERVAL" default:"1h"` // nolint: lll
+	IdlePeriod          time.Duration     `envconfig:"IDLE_DETECTION_IDLE_PERIOD" default:"72h"`   // nolint: lll
+	PolicyStr           string            `envconfig:"IDLE_DETECTION_POLICY" default:"notify"`     // nolint: lll
+	PolicyByServiceStrs map[string]string `envconfig:"IDLE_DETECTION_POLICY_BY_SERVICE"`           // nolint: lll
+	Policy              broker.IdlePolicy
+	PolicyByService     map[string]broker.IdlePolicy
+}
+
+// driftDetectionConfig represents whether, and how often, the broker checks
+// instances for drift of their underlying resources from the state the broker
+// put them in, and what it does about it. A policy of "report" records drift
+// for the admin API to report, "correct" additionally re-applies instances'
+// desired state, and "ignore" does nothing. Per-service policies are specified
+// as for idle detection.
+type driftDetectionConfig struct {
+	Enabled             bool              `envconfig:"DRIFT_DETECTION_ENABLED" default:"false"`     // nolint: lll
+	CheckInterval       time.Duration     `envconfig:"DRIFT_DETECTION_CHECK_INTERVAL" default:"6h"` // nolint: lll
+	PolicyStr           string            `envconfig:"DRIFT_DETECTION_POLICY" default:"report"`     // nolint: lll
+	PolicyByServiceStrs map[string]string `envconfig:"DRIFT_DETECTION_POLICY_BY_SERVICE"`           // nolint: lll
+	Policy              broker.DriftPolicy
+	PolicyByService     map[string]broker.DriftPolicy
+}
+
+// credentialRotationConfig represents whether, and how often, the broker
+// replaces the credentials of bindings to services that support it once their
+// TTL elapses, and the Azure Key Vault to which it delivers the new ones.
+// Bindings may request a TTL of their own, no shorter than the minimum, or opt
+// out of rotation altogether.
+type credentialRotationConfig struct {
+	Enabled       bool          `envconfig:"CREDENTIAL_ROTATION_ENABLED" default:"false"`      // nolint: lll
+	DefaultTTL    time.Duration `envconfig:"CREDENTIAL_ROTATION_DEFAULT_TTL" default:"720h"`   // nolint: lll
+	MinTTL        time.Duration `envconfig:"CREDENTIAL_ROTATION_MIN_TTL" default:"1h"`         // nolint: lll
+	CheckInterval time.Duration `envconfig:"CREDENTIAL_ROTATION_CHECK_INTERVAL" default:"15m"` // nolint: lll
+	KeyVaultName  string        `envconfig:"CREDENTIAL_ROTATION_KEY_VAULT_NAME"`               // nolint: lll
+	SecretStore   secrets.Store
+}
+
+// keyRotationConfig represents whether the broker re-encrypts, with the
+// current version of the encryption key, stored records whose secret fields
+// were encrypted with a retired version. Instances are re-encrypted, along with
+// their bindings, a batch at a time, with a pause of the batch interval between
+// batches.
+type keyRotationConfig struct {
+	Enabled       bool          `envconfig:"KEY_ROTATION_ENABLED" default:"false"`      // nolint: lll
+	BatchSize     int           `envconfig:"KEY_ROTATION_BATCH_SIZE" default:"50"`      // nolint: lll
+	BatchInterval time.Duration `envconfig:"KEY_ROTATION_BATCH_INTERVAL" default:"10s"` // nolint: lll
+}
+
+// taggingConfig represents the broker metadata that is applied, as tags, to
+// the Azure resources the broker provisions, in addition to any tags specified
+// when provisioning. Metadata is specified as a comma-delimited list drawn from
+// organizationGUID, spaceGUID, plan, provisioned, and brokerID. brokerID
+// requires the broker's ID to be configured as well.
+type taggingConfig struct {
+	MetadataStrs    []string `envconfig:"METADATA_TAGS"`
+	BrokerID        string   `envconfig:"METADATA_TAGS_BROKER_ID"`
+	MetadataTagging service.MetadataTagging
+}
+
+// approvalConfig represents the external approval service, if any, that must
+// approve the provisioning of new instances of the listed modules' services
+// and of the listed plans, which are identified as serviceName/planName. The
+// broker submits each such instance for approval by POSTing to the webhook
+// URL, then polls for the decision every poll interval, unless the approval
+// service calls back with it first. Instances that haven't been decided upon
+// before the timeout are denied. Instances of any plan whose estimated monthly
+// cost exceeds the cost threshold also require approval. Per-organization
+// thresholds, which override it, are specified as a comma-delimited list of
+// organizationGUID:threshold pairs. A threshold of zero means there is none.
+type approvalConfig struct {
+	WebhookURL         string             `envconfig:"APPROVAL_WEBHOOK_URL"`
+	WebhookToken       string             `envconfig:"APPROVAL_WEBHOOK_TOKEN"`
+	Modules            []string           `envconfig:"APPROVAL_REQUIRED_MODULES"`
+	Plans              []string           `envconfig:"APPROVAL_REQUIRED_PLANS"`
+	Timeout            time.Duration      `envconfig:"APPROVAL_TIMEOUT" default:"24h"`      // nolint: lll
+	PollInterval       time.Duration      `envconfig:"APPROVAL_POLL_INTERVAL" default:"1m"` // nolint: lll
+	CostThreshold      float64            `envconfig:"APPROVAL_COST_THRESHOLD" default:"0"` // nolint: lll
+	CostThresholdByOrg map[string]float64 `envconfig:"APPROVAL_COST_THRESHOLD_BY_ORG"`      // nolint: lll
+	Approver           approval.Approver
+}
+
+// sandboxConfig represents which plans, identified as serviceName/planName,
+// permit sandbox instances that are deprovisioned once their lifespan is
+// over, and each plan's lifespan. Updating a sandbox instance can put off its
+// expiry, but no sandbox instance lives longer than the maximum lifespan.
+// Notification subscribers are warned of an instance's expiry the warning
+// period before it happens.
+type sandboxConfig struct {
+	LifespanByPlan map[string]time.Duration `envconfig:"SANDBOX_LIFESPAN_BY_PLAN"`                   // nolint: lll
+	MaxLifespan    time.Duration            `envconfig:"SANDBOX_MAX_LIFESPAN" default:"168h"`        // nolint: lll
+	WarningPeriod  time.Duration            `envconfig:"SANDBOX_EXPIRY_WARNING_PERIOD" default:"1h"` // nolint: lll
+	ExpiryPolicy   service.ExpiryPolicy
+}
+
+// gitOpsConfig represents the Git repository, if any, to which a manifest
+// describing each new binding is committed, so that GitOps tools such as Flux
+// and Argo CD apply it to the clusters they manage. The repository, given as
+// owner/name, is written by way of the GitHub contents API at the API URL,
+// using the token. Manifests are Kubernetes ConfigMaps that hold no
+// credentials or Secrets that hold the binding's credentials, depending on the
+// format, unless a template is given to render them from instead. Manifests
+// that hold credentials commit them to the repository in the clear, so they
+// must be allowed explicitly. Each is written to
+// <directory>/<instanceID>/<bindingID>.yaml and removed on unbinding.
+type gitOpsConfig struct {
+	Repository                string `envconfig:"GITOPS_REPOSITORY"`                                  // nolint: lll
+	APIURL                    string `envconfig:"GITOPS_API_URL" default:"https://api.github.com"`    // nolint: lll
+	Branch                    string `envconfig:"GITOPS_BRANCH"`                                      // nolint: lll
+	Token                     string `envconfig:"GITOPS_TOKEN"`                                       // nolint: lll
+	ManifestFormat            string `envconfig:"GITOPS_MANIFEST_FORMAT" default:"configmap"`         // nolint: lll
+	ManifestTemplate          string `envconfig:"GITOPS_MANIFEST_TEMPLATE"`                           // nolint: lll
+	ManifestDirectory         string `envconfig:"GITOPS_MANIFEST_DIRECTORY" default:"osba"`           // nolint: lll
+	ManifestNamespace         string `envconfig:"GITOPS_MANIFEST_NAMESPACE" default:"default"`        // nolint: lll
+	AllowPlaintextCredentials bool   `envconfig:"GITOPS_ALLOW_PLAINTEXT_CREDENTIALS" default:"false"` // nolint: lll
+	ManifestWriter            gitops.ManifestWriter
+}
+
 func getLogConfig() (logConfig, error) {
 	lc := logConfig{}
 	err := envconfig.Process("", &lc)
@@ -58,15 +416,65 @@ func getLogConfig() (logConfig, error) {
 	return lc, err
 }
 
+func getRedactionConfig() (redactionConfig, error) {
+	rc := redactionConfig{}
+	err := envconfig.Process("", &rc)
+	if err != nil {
+		return rc, err
+	}
+	if rc.LogMode, err = redaction.ParseMode(rc.LogModeStr); err != nil {
+		return rc, fmt.Errorf("invalid LOG_REDACTION_MODE: %s", err)
+	}
+	if rc.ResponseMode, err = redaction.ParseMode(rc.ResponseModeStr); err != nil {
+		return rc, fmt.Errorf("invalid RESPONSE_REDACTION_MODE: %s", err)
+	}
+	return rc, nil
+}
+
 func getRedisConfig() (redisConfig, error) {
 	rc := redisConfig{}
 	err := envconfig.Process("", &rc)
 	return rc, err
 }
 
+func getSecondaryRedisConfig() (secondaryRedisConfig, error) {
+	src := secondaryRedisConfig{}
+	err := envconfig.Process("", &src)
+	return src, err
+}
+
 func getCryptoConfig() (cryptoConfig, error) {
 	cc := cryptoConfig{}
 	err := envconfig.Process("", &cc)
+	if err != nil {
+		return cc, err
+	}
+	codecs := map[string]crypto.Codec{}
+	for keyVersion, key := range cc.AES256RetiredKeys {
+		if keyVersion == cc.AES256KeyVersion {
+			return cc, fmt.Errorf(
+				`AES256_RETIRED_KEYS may not include the current key version "%s"`,
+				keyVersion,
+			)
+		}
+		if codecs[keyVersion], err = aes256.NewCodec([]byte(key)); err != nil {
+			return cc, fmt.Errorf(
+				`invalid AES256_RETIRED_KEYS key for version "%s": %s`,
+				keyVersion,
+				err,
+			)
+		}
+	}
+	if codecs[cc.AES256KeyVersion], err = aes256.NewCodec(
+		[]byte(cc.AES256Key),
+	); err != nil {
+		return cc, fmt.Errorf("invalid AES256_KEY: %s", err)
+	}
+	cc.Codec, err = versioned.NewCodec(
+		cc.AES256KeyVersion,
+		codecs,
+		cc.FieldLevelEncryption,
+	)
 	return cc, err
 }
 
@@ -104,3 +512,706 @@ func getAzureConfig() (azureConfig, error) {
 	err := envconfig.Process("", &ac)
 	return ac, err
 }
+
+func getSubscriptionRoutingConfig(
+	defaultSubscriptionID string,
+) (subscriptionRoutingConfig, error) {
+	sc := subscriptionRoutingConfig{}
+	err := envconfig.Process("", &sc)
+	if err != nil {
+		return sc, err
+	}
+	var checker az.SubscriptionChecker
+	if len(sc.Pool) > 0 {
+		if checker, err = az.NewSubscriptionChecker(); err != nil {
+			return sc, err
+		}
+	}
+	sc.Routing, err = api.NewSubscriptionRouting(
+		defaultSubscriptionID,
+		sc.Pool,
+		api.SubscriptionRoutingPolicy(strings.ToLower(sc.PolicyStr)),
+		sc.ByOrganization,
+		sc.BySpace,
+		checker,
+	)
+	if err != nil {
+		return sc, fmt.Errorf("invalid subscription routing: %s", err)
+	}
+	// Better to find out now that the broker can't provision into one of the
+	// subscriptions than when an instance is routed to it
+	for _, subscriptionID := range sc.Routing.GetSubscriptionIDs() {
+		if err = checker.CheckSubscription(subscriptionID); err != nil {
+			return sc, fmt.Errorf("invalid AZURE_SUBSCRIPTION_POOL: %s", err)
+		}
+	}
+	return sc, nil
+}
+
+func getProvisioningConfig() (provisioningConfig, error) {
+	pc := provisioningConfig{}
+	err := envconfig.Process("", &pc)
+	if err != nil {
+		return pc, err
+	}
+	if pc.MaxConcurrency < 0 {
+		return pc, fmt.Errorf(
+			"invalid PROVISIONING_MAX_CONCURRENCY: %d",
+			pc.MaxConcurrency,
+		)
+	}
+	for subscriptionID, max := range pc.MaxConcurrencyBySubscription {
+		if max < 0 {
+			return pc, fmt.Errorf(
+				`invalid PROVISIONING_MAX_CONCURRENCY_BY_SUBSCRIPTION for `+
+					`subscription "%s": %d`,
+				subscriptionID,
+				max,
+			)
+		}
+	}
+	if pc.SlotTTL <= 0 {
+		return pc, fmt.Errorf("invalid PROVISIONING_SLOT_TTL: %s", pc.SlotTTL)
+	}
+	pc.RetryPolicy = broker.NewDefaultRetryPolicy()
+	for categoryStr, maxRetries := range pc.MaxRetriesByErrorCategory {
+		category, err := getErrorCategory(categoryStr)
+		if err != nil {
+			return pc, fmt.Errorf(
+				"invalid PROVISIONING_MAX_RETRIES_BY_ERROR_CATEGORY: %s",
+				err,
+			)
+		}
+		if maxRetries < 0 {
+			return pc, fmt.Errorf(
+				`invalid PROVISIONING_MAX_RETRIES_BY_ERROR_CATEGORY for error `+
+					`category "%s": %d`,
+				categoryStr,
+				maxRetries,
+			)
+		}
+		behavior := pc.RetryPolicy[category]
+		behavior.MaxRetries = maxRetries
+		pc.RetryPolicy[category] = behavior
+	}
+	for categoryStr, delay := range pc.RetryDelayByErrorCategory {
+		category, err := getErrorCategory(categoryStr)
+		if err != nil {
+			return pc, fmt.Errorf(
+				"invalid PROVISIONING_RETRY_DELAY_BY_ERROR_CATEGORY: %s",
+				err,
+			)
+		}
+		if delay < 0 {
+			return pc, fmt.Errorf(
+				`invalid PROVISIONING_RETRY_DELAY_BY_ERROR_CATEGORY for error `+
+					`category "%s": %s`,
+				categoryStr,
+				delay,
+			)
+		}
+		behavior := pc.RetryPolicy[category]
+		behavior.Delay = delay
+		pc.RetryPolicy[category] = behavior
+	}
+	pc.StepOrderOverrides = map[string][]string{}
+	for serviceName, stepNamesStr := range pc.StepOrderOverridesStrs {
+		stepNames := []string{}
+		for _, stepName := range strings.Split(stepNamesStr, ";") {
+			if stepName = strings.TrimSpace(stepName); stepName != "" {
+				stepNames = append(stepNames, stepName)
+			}
+		}
+		if len(stepNames) == 0 {
+			return pc, fmt.Errorf(
+				`invalid PROVISIONING_STEP_ORDER_OVERRIDES for service "%s": no `+
+					`steps specified`,
+				serviceName,
+			)
+		}
+		pc.StepOrderOverrides[serviceName] = stepNames
+	}
+	pc.SkippedSuccessCriteria = map[string][]string{}
+	for planKey, criterionNamesStr := range pc.SkippedSuccessCriteriaStrs {
+		criterionNames := []string{}
+		for _, criterionName := range strings.Split(criterionNamesStr, ";") {
+			if criterionName = strings.TrimSpace(criterionName); criterionName != "" {
+				criterionNames = append(criterionNames, criterionName)
+			}
+		}
+		if len(criterionNames) == 0 {
+			return pc, fmt.Errorf(
+				`invalid PROVISIONING_SKIPPED_SUCCESS_CRITERIA_BY_PLAN for plan `+
+					`"%s": no success criteria specified`,
+				planKey,
+			)
+		}
+		pc.SkippedSuccessCriteria[planKey] = criterionNames
+	}
+	fingerprintFields := []api.FingerprintField{}
+	for _, fieldStr := range pc.DedupFingerprintStrs {
+		if fieldStr = strings.TrimSpace(fieldStr); fieldStr != "" {
+			fingerprintFields = append(
+				fingerprintFields,
+				api.FingerprintField(strings.ToLower(fieldStr)),
+			)
+		}
+	}
+	if len(fingerprintFields) == 0 {
+		fingerprintFields = api.DefaultFingerprintFields
+	}
+	pc.Deduplication, err = api.NewProvisioningDeduplication(
+		pc.DedupWindow,
+		fingerprintFields,
+	)
+	if err != nil {
+		return pc, fmt.Errorf("invalid PROVISIONING_DEDUP_FINGERPRINT: %s", err)
+	}
+	pc.SLA, err = service.NewProvisioningSLA(pc.SLATarget, pc.SLATargetByPlan)
+	if err != nil {
+		return pc, fmt.Errorf("invalid provisioning SLA target: %s", err)
+	}
+	if pc.OverdueFactor != 0 && pc.OverdueFactor < 1 {
+		return pc, fmt.Errorf(
+			"invalid PROVISIONING_OVERDUE_FACTOR: %g; the factor must be zero or "+
+				"at least 1",
+			pc.OverdueFactor,
+		)
+	}
+	for moduleName, window := range pc.FailureGraceWindowByModule {
+		if window < 0 {
+			return pc, fmt.Errorf(
+				`invalid PROVISIONING_FAILURE_GRACE_WINDOW_BY_MODULE for module `+
+					`"%s": %s`,
+				moduleName,
+				window,
+			)
+		}
+	}
+	if pc.FailureGraceRetryInterval <= 0 {
+		return pc, fmt.Errorf(
+			"invalid PROVISIONING_FAILURE_GRACE_RETRY_INTERVAL: %s",
+			pc.FailureGraceRetryInterval,
+		)
+	}
+	for moduleName, max := range pc.MaxConcurrencyByModule {
+		if max <= 0 {
+			return pc, fmt.Errorf(
+				`invalid PROVISIONING_MAX_CONCURRENCY_BY_MODULE for module "%s": %d`,
+				moduleName,
+				max,
+			)
+		}
+	}
+	for moduleName, depth := range pc.QueueDepthByModule {
+		if depth < 0 {
+			return pc, fmt.Errorf(
+				`invalid PROVISIONING_QUEUE_DEPTH_BY_MODULE for module "%s": %d`,
+				moduleName,
+				depth,
+			)
+		}
+		if _, ok := pc.MaxConcurrencyByModule[moduleName]; !ok {
+			return pc, fmt.Errorf(
+				`invalid PROVISIONING_QUEUE_DEPTH_BY_MODULE for module "%s": the `+
+					"module has no PROVISIONING_MAX_CONCURRENCY_BY_MODULE",
+				moduleName,
+			)
+		}
+	}
+	return pc, nil
+}
+
+func getDeprovisioningConfig() (deprovisioningConfig, error) {
+	dc := deprovisioningConfig{}
+	err := envconfig.Process("", &dc)
+	if err != nil {
+		return dc, err
+	}
+	if dc.TeardownMaxRetries < 0 {
+		return dc, fmt.Errorf(
+			"invalid DEPROVISIONING_TEARDOWN_MAX_RETRIES: %d",
+			dc.TeardownMaxRetries,
+		)
+	}
+	if dc.TeardownRetryDelay < 0 {
+		return dc, fmt.Errorf(
+			"invalid DEPROVISIONING_TEARDOWN_RETRY_DELAY: %s",
+			dc.TeardownRetryDelay,
+		)
+	}
+	dc.TeardownRetryPolicy = broker.NewTeardownRetryPolicy(
+		dc.TeardownMaxRetries,
+		dc.TeardownRetryDelay,
+	)
+	return dc, nil
+}
+
+func getAsyncConfig() (asyncConfig, error) {
+	ac := asyncConfig{}
+	err := envconfig.Process("", &ac)
+	if err != nil {
+		return ac, err
+	}
+	for organizationGUID, weight := range ac.FairSchedulingWeights {
+		if weight < 1 {
+			return ac, fmt.Errorf(
+				`invalid ASYNC_FAIR_SCHEDULING_WEIGHTS for organization "%s": %d`,
+				organizationGUID,
+				weight,
+			)
+		}
+	}
+	if ac.MinWorkers < 1 {
+		return ac, fmt.Errorf("invalid ASYNC_MIN_WORKERS: %d", ac.MinWorkers)
+	}
+	if ac.MaxWorkers < ac.MinWorkers {
+		return ac, fmt.Errorf(
+			"ASYNC_MAX_WORKERS (%d) may not be less than ASYNC_MIN_WORKERS (%d)",
+			ac.MaxWorkers,
+			ac.MinWorkers,
+		)
+	}
+	if ac.WorkerScalingInterval <= 0 {
+		return ac, fmt.Errorf(
+			"invalid ASYNC_WORKER_SCALING_INTERVAL: %s",
+			ac.WorkerScalingInterval,
+		)
+	}
+	if ac.TenantQueueCapacity < 0 {
+		return ac, fmt.Errorf(
+			"invalid ASYNC_TENANT_QUEUE_CAPACITY: %d",
+			ac.TenantQueueCapacity,
+		)
+	}
+	if ac.TenantPoolsFile == "" {
+		return ac, nil
+	}
+	if ac.TenantPoolsReloadInterval <= 0 {
+		return ac, fmt.Errorf(
+			"invalid ASYNC_TENANT_POOLS_RELOAD_INTERVAL: %s",
+			ac.TenantPoolsReloadInterval,
+		)
+	}
+	ac.GetTenantPools = getTenantPoolsFn(ac.TenantPoolsFile)
+	// Fail fast on a file that can't be loaded now, rather than when the async
+	// engine starts
+	if _, err := ac.GetTenantPools(); err != nil {
+		return ac, fmt.Errorf("invalid ASYNC_TENANT_POOLS_FILE: %s", err)
+	}
+	return ac, nil
+}
+
+func getDebugTracingConfig() (debugTracingConfig, error) {
+	dtc := debugTracingConfig{}
+	err := envconfig.Process("", &dtc)
+	if err != nil {
+		return dtc, err
+	}
+	if dtc.Mode, err = service.ParseDebugTracingMode(dtc.ModeStr); err != nil {
+		return dtc, fmt.Errorf("invalid DEBUG_TRACING_MODE: %s", err)
+	}
+	if dtc.TTL <= 0 {
+		return dtc, fmt.Errorf("invalid DEBUG_TRACING_TTL: %s", dtc.TTL)
+	}
+	if dtc.MaxSteps < 1 {
+		return dtc, fmt.Errorf(
+			"invalid DEBUG_TRACING_MAX_STEPS: %d",
+			dtc.MaxSteps,
+		)
+	}
+	return dtc, nil
+}
+
+func getTelemetryConfig() (telemetryConfig, error) {
+	tc := telemetryConfig{}
+	err := envconfig.Process("", &tc)
+	if err != nil {
+		return tc, err
+	}
+	sampling := telemetry.Sampling{
+		TraceSampleRate:    tc.TraceSampleRate,
+		MaxSeriesPerMetric: tc.MaxSeriesPerMetric,
+	}
+	if err = sampling.Validate(); err != nil {
+		return tc, fmt.Errorf("invalid telemetry sampling: %s", err)
+	}
+	var getSampling telemetry.SamplingFn
+	if tc.SamplingFile != "" {
+		if tc.SamplingReloadInterval <= 0 {
+			return tc, fmt.Errorf(
+				"invalid TELEMETRY_SAMPLING_RELOAD_INTERVAL: %s",
+				tc.SamplingReloadInterval,
+			)
+		}
+		getSampling = getTelemetrySamplingFn(tc.SamplingFile, sampling)
+		// Fail fast on a file that can't be loaded now, rather than when the
+		// sampling is first reloaded
+		if sampling, err = getSampling(); err != nil {
+			return tc, fmt.Errorf("invalid TELEMETRY_SAMPLING_FILE: %s", err)
+		}
+	}
+	tc.Sampler, err = telemetry.NewSampler(
+		sampling,
+		getSampling,
+		tc.SamplingReloadInterval,
+	)
+	return tc, err
+}
+
+func getThrottlingConfig() (throttlingConfig, error) {
+	tc := throttlingConfig{}
+	err := envconfig.Process("", &tc)
+	if err != nil || !tc.Enabled {
+		return tc, err
+	}
+	if tc.MaxRate <= 0 {
+		return tc, fmt.Errorf("invalid AZURE_THROTTLING_MAX_RATE: %g", tc.MaxRate)
+	}
+	if tc.MinRate <= 0 || tc.MinRate > tc.MaxRate {
+		return tc, fmt.Errorf(
+			"AZURE_THROTTLING_MIN_RATE (%g) must be positive and may not exceed "+
+				"AZURE_THROTTLING_MAX_RATE (%g)",
+			tc.MinRate,
+			tc.MaxRate,
+		)
+	}
+	if tc.RemainingRequestsThreshold < 1 {
+		return tc, fmt.Errorf(
+			"invalid AZURE_THROTTLING_REMAINING_REQUESTS_THRESHOLD: %d",
+			tc.RemainingRequestsThreshold,
+		)
+	}
+	if tc.RecoveryPeriod <= 0 {
+		return tc, fmt.Errorf(
+			"invalid AZURE_THROTTLING_RECOVERY_PERIOD: %s",
+			tc.RecoveryPeriod,
+		)
+	}
+	if tc.DefaultRetryAfter <= 0 {
+		return tc, fmt.Errorf(
+			"invalid AZURE_THROTTLING_DEFAULT_RETRY_AFTER: %s",
+			tc.DefaultRetryAfter,
+		)
+	}
+	// Only responses from Azure Resource Manager are observed, so its host in
+	// the configured Azure environment must be known
+	azureConfig, err := az.GetConfig()
+	if err != nil {
+		return tc, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return tc, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	resourceManagerURL, err := url.Parse(
+		azureEnvironment.ResourceManagerEndpoint,
+	)
+	if err != nil {
+		return tc, fmt.Errorf(
+			`error parsing Azure Resource Manager endpoint "%s": %s`,
+			azureEnvironment.ResourceManagerEndpoint,
+			err,
+		)
+	}
+	tc.ResourceManagerHost = resourceManagerURL.Hostname()
+	return tc, nil
+}
+
+func getNotificationsConfig() (notificationsConfig, error) {
+	nc := notificationsConfig{}
+	err := envconfig.Process("", &nc)
+	if err != nil || nc.SubscribersJSON == "" {
+		return nc, err
+	}
+	nc.Subscribers, err = notification.NewSubscribers([]byte(nc.SubscribersJSON))
+	if err != nil {
+		return nc, fmt.Errorf("invalid NOTIFICATION_SUBSCRIBERS: %s", err)
+	}
+	return nc, nil
+}
+
+func getCostEstimationConfig() (costEstimationConfig, error) {
+	cc := costEstimationConfig{}
+	err := envconfig.Process("", &cc)
+	if err != nil || cc.PricingTableJSON == "" {
+		return cc, err
+	}
+	cc.PricingTable, err = service.NewPricingTable(
+		cc.Currency,
+		[]byte(cc.PricingTableJSON),
+	)
+	if err != nil {
+		return cc, fmt.Errorf("invalid COST_ESTIMATION_PRICING_TABLE: %s", err)
+	}
+	return cc, nil
+}
+
+func getKeyRotationConfig() (keyRotationConfig, error) {
+	kc := keyRotationConfig{}
+	err := envconfig.Process("", &kc)
+	if err != nil {
+		return kc, err
+	}
+	if kc.BatchSize <= 0 {
+		return kc, fmt.Errorf(
+			"invalid KEY_ROTATION_BATCH_SIZE: %d",
+			kc.BatchSize,
+		)
+	}
+	if kc.BatchInterval <= 0 {
+		return kc, fmt.Errorf(
+			"invalid KEY_ROTATION_BATCH_INTERVAL: %s",
+			kc.BatchInterval,
+		)
+	}
+	return kc, nil
+}
+
+func getIdleDetectionConfig() (idleDetectionConfig, error) {
+	ic := idleDetectionConfig{}
+	err := envconfig.Process("", &ic)
+	if err != nil {
+		return ic, err
+	}
+	if ic.CheckInterval <= 0 {
+		return ic, fmt.Errorf(
+			"invalid IDLE_DETECTION_CHECK_INTERVAL: %s",
+			ic.CheckInterval,
+		)
+	}
+	if ic.IdlePeriod <= 0 {
+		return ic, fmt.Errorf(
+			"invalid IDLE_DETECTION_IDLE_PERIOD: %s",
+			ic.IdlePeriod,
+		)
+	}
+	if ic.Policy, err = getIdlePolicy(ic.PolicyStr); err != nil {
+		return ic, fmt.Errorf("invalid IDLE_DETECTION_POLICY: %s", err)
+	}
+	ic.PolicyByService = map[string]broker.IdlePolicy{}
+	for serviceName, policyStr := range ic.PolicyByServiceStrs {
+		policy, err := getIdlePolicy(policyStr)
+		if err != nil {
+			return ic, fmt.Errorf(
+				`invalid IDLE_DETECTION_POLICY_BY_SERVICE for service "%s": %s`,
+				serviceName,
+				err,
+			)
+		}
+		ic.PolicyByService[serviceName] = policy
+	}
+	return ic, nil
+}
+
+func getDriftDetectionConfig() (driftDetectionConfig, error) {
+	dc := driftDetectionConfig{}
+	err := envconfig.Process("", &dc)
+	if err != nil {
+		return dc, err
+	}
+	if dc.CheckInterval <= 0 {
+		return dc, fmt.Errorf(
+			"invalid DRIFT_DETECTION_CHECK_INTERVAL: %s",
+			dc.CheckInterval,
+		)
+	}
+	if dc.Policy, err = getDriftPolicy(dc.PolicyStr); err != nil {
+		return dc, fmt.Errorf("invalid DRIFT_DETECTION_POLICY: %s", err)
+	}
+	dc.PolicyByService = map[string]broker.DriftPolicy{}
+	for serviceName, policyStr := range dc.PolicyByServiceStrs {
+		policy, err := getDriftPolicy(policyStr)
+		if err != nil {
+			return dc, fmt.Errorf(
+				`invalid DRIFT_DETECTION_POLICY_BY_SERVICE for service "%s": %s`,
+				serviceName,
+				err,
+			)
+		}
+		dc.PolicyByService[serviceName] = policy
+	}
+	return dc, nil
+}
+
+func getCredentialRotationConfig() (credentialRotationConfig, error) {
+	cc := credentialRotationConfig{}
+	err := envconfig.Process("", &cc)
+	if err != nil {
+		return cc, err
+	}
+	if !cc.Enabled {
+		return cc, nil
+	}
+	if cc.DefaultTTL <= 0 {
+		return cc, fmt.Errorf(
+			"invalid CREDENTIAL_ROTATION_DEFAULT_TTL: %s",
+			cc.DefaultTTL,
+		)
+	}
+	if cc.CheckInterval <= 0 {
+		return cc, fmt.Errorf(
+			"invalid CREDENTIAL_ROTATION_CHECK_INTERVAL: %s",
+			cc.CheckInterval,
+		)
+	}
+	if cc.KeyVaultName == "" {
+		return cc, errors.New(
+			"CREDENTIAL_ROTATION_KEY_VAULT_NAME must be set when " +
+				"CREDENTIAL_ROTATION_ENABLED is",
+		)
+	}
+	cc.SecretStore, err = secrets.NewKeyVaultStore(cc.KeyVaultName)
+	return cc, err
+}
+
+func getTaggingConfig() (taggingConfig, error) {
+	tc := taggingConfig{}
+	err := envconfig.Process("", &tc)
+	if err != nil {
+		return tc, err
+	}
+	metadata := []service.TagMetadata{}
+	for _, metadataStr := range tc.MetadataStrs {
+		if metadataStr = strings.TrimSpace(metadataStr); metadataStr != "" {
+			metadata = append(metadata, service.TagMetadata(metadataStr))
+		}
+	}
+	tc.MetadataTagging, err = service.NewMetadataTagging(metadata, tc.BrokerID)
+	if err != nil {
+		return tc, fmt.Errorf("invalid METADATA_TAGS: %s", err)
+	}
+	return tc, nil
+}
+
+func getApprovalConfig() (approvalConfig, error) {
+	ac := approvalConfig{}
+	err := envconfig.Process("", &ac)
+	if err != nil {
+		return ac, err
+	}
+	if ac.Timeout <= 0 {
+		return ac, fmt.Errorf("invalid APPROVAL_TIMEOUT: %s", ac.Timeout)
+	}
+	if ac.PollInterval <= 0 {
+		return ac, fmt.Errorf(
+			"invalid APPROVAL_POLL_INTERVAL: %s",
+			ac.PollInterval,
+		)
+	}
+	if ac.WebhookURL == "" {
+		if len(ac.Modules) > 0 || len(ac.Plans) > 0 {
+			return ac, errors.New(
+				"APPROVAL_WEBHOOK_URL must be set when APPROVAL_REQUIRED_MODULES or " +
+					"APPROVAL_REQUIRED_PLANS is",
+			)
+		}
+		if ac.CostThreshold > 0 || len(ac.CostThresholdByOrg) > 0 {
+			return ac, errors.New(
+				"APPROVAL_WEBHOOK_URL must be set when APPROVAL_COST_THRESHOLD or " +
+					"APPROVAL_COST_THRESHOLD_BY_ORG is",
+			)
+		}
+		return ac, nil
+	}
+	ac.Approver = approval.NewWebhookApprover(ac.WebhookURL, ac.WebhookToken)
+	return ac, nil
+}
+
+func getSandboxConfig() (sandboxConfig, error) {
+	sc := sandboxConfig{}
+	err := envconfig.Process("", &sc)
+	if err != nil {
+		return sc, err
+	}
+	sc.ExpiryPolicy, err = service.NewExpiryPolicy(
+		sc.LifespanByPlan,
+		sc.MaxLifespan,
+		sc.WarningPeriod,
+	)
+	if err != nil {
+		return sc, fmt.Errorf("invalid sandbox configuration: %s", err)
+	}
+	return sc, nil
+}
+
+func getGitOpsConfig() (gitOpsConfig, error) {
+	gc := gitOpsConfig{}
+	err := envconfig.Process("", &gc)
+	if err != nil {
+		return gc, err
+	}
+	if gc.Repository == "" {
+		return gc, nil
+	}
+	if len(strings.Split(gc.Repository, "/")) != 2 {
+		return gc, fmt.Errorf(
+			`invalid GITOPS_REPOSITORY "%s"; it must be given as owner/name`,
+			gc.Repository,
+		)
+	}
+	if gc.Token == "" {
+		return gc, errors.New(
+			"GITOPS_TOKEN must be set when GITOPS_REPOSITORY is",
+		)
+	}
+	gc.ManifestWriter, err = gitops.NewManifestWriter(
+		gitops.NewGitHubRepository(
+			gc.APIURL,
+			gc.Repository,
+			gc.Branch,
+			gc.Token,
+		),
+		gitops.ManifestConfig{
+			Format:                    strings.ToLower(gc.ManifestFormat),
+			Template:                  []byte(gc.ManifestTemplate),
+			Directory:                 gc.ManifestDirectory,
+			Namespace:                 gc.ManifestNamespace,
+			AllowPlaintextCredentials: gc.AllowPlaintextCredentials,
+		},
+	)
+	if err != nil {
+		return gc, fmt.Errorf("invalid GitOps configuration: %s", err)
+	}
+	return gc, nil
+}
+
+func getIdlePolicy(policyStr string) (broker.IdlePolicy, error) {
+	policy := broker.IdlePolicy(strings.ToLower(policyStr))
+	switch policy {
+	case broker.IdlePolicyIgnore,
+		broker.IdlePolicyNotify,
+		broker.IdlePolicySuspend:
+		return policy, nil
+	default:
+		return "", fmt.Errorf(`unrecognized idle policy "%s"`, policyStr)
+	}
+}
+
+func getDriftPolicy(policyStr string) (broker.DriftPolicy, error) {
+	policy := broker.DriftPolicy(strings.ToLower(policyStr))
+	switch policy {
+	case broker.DriftPolicyIgnore,
+		broker.DriftPolicyReport,
+		broker.DriftPolicyCorrect:
+		return policy, nil
+	default:
+		return "", fmt.Errorf(`unrecognized drift policy "%s"`, policyStr)
+	}
+}
+
+func getErrorCategory(categoryStr string) (service.ErrorCategory, error) {
+	category := service.ErrorCategory(strings.ToLower(categoryStr))
+	switch category {
+	case service.ErrorCategoryThrottled,
+		service.ErrorCategoryTransient,
+		service.ErrorCategoryInvalid,
+		service.ErrorCategoryUnknown:
+		return category, nil
+	default:
+		return "", fmt.Errorf(`unrecognized error category "%s"`, categoryStr)
+	}
+}
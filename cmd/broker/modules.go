@@ -4,85 +4,352 @@ import (
 	"fmt"
 
 	ac "github.com/Azure/open-service-broker-azure/pkg/azure/aci"
+	ar "github.com/Azure/open-service-broker-azure/pkg/azure/arc"
 	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	aa "github.com/Azure/open-service-broker-azure/pkg/azure/automation"
+	as "github.com/Azure/open-service-broker-azure/pkg/azure/autoscale"
+	bk "github.com/Azure/open-service-broker-azure/pkg/azure/backup"
+	ba "github.com/Azure/open-service-broker-azure/pkg/azure/bastion"
+	ch "github.com/Azure/open-service-broker-azure/pkg/azure/chaosstudio"
+	cm "github.com/Azure/open-service-broker-azure/pkg/azure/communication"
+	cl "github.com/Azure/open-service-broker-azure/pkg/azure/confidentialledger"
+	ca "github.com/Azure/open-service-broker-azure/pkg/azure/containerapps"
 	cd "github.com/Azure/open-service-broker-azure/pkg/azure/cosmosdb"
+	db "github.com/Azure/open-service-broker-azure/pkg/azure/devbox"
+	dl "github.com/Azure/open-service-broker-azure/pkg/azure/devtestlabs"
+	dt "github.com/Azure/open-service-broker-azure/pkg/azure/digitaltwins"
+	dm "github.com/Azure/open-service-broker-azure/pkg/azure/dms"
+	es "github.com/Azure/open-service-broker-azure/pkg/azure/elasticsan"
 	eh "github.com/Azure/open-service-broker-azure/pkg/azure/eventhub"
+	fh "github.com/Azure/open-service-broker-azure/pkg/azure/fhir"
+	fp "github.com/Azure/open-service-broker-azure/pkg/azure/firewallpolicy"
+	fr "github.com/Azure/open-service-broker-azure/pkg/azure/fluidrelay"
+	dps "github.com/Azure/open-service-broker-azure/pkg/azure/iotdps"
 	kv "github.com/Azure/open-service-broker-azure/pkg/azure/keyvault"
+	ku "github.com/Azure/open-service-broker-azure/pkg/azure/kusto"
+	lb "github.com/Azure/open-service-broker-azure/pkg/azure/loadbalancer"
+	lt "github.com/Azure/open-service-broker-azure/pkg/azure/loadtesting"
+	mc "github.com/Azure/open-service-broker-azure/pkg/azure/maintenance"
+	mh "github.com/Azure/open-service-broker-azure/pkg/azure/managedhsm"
+	ml "github.com/Azure/open-service-broker-azure/pkg/azure/managedlustre"
+	ms "github.com/Azure/open-service-broker-azure/pkg/azure/mediaservices"
+	mt "github.com/Azure/open-service-broker-azure/pkg/azure/metrics"
+	mv "github.com/Azure/open-service-broker-azure/pkg/azure/mongovcore"
+	mw "github.com/Azure/open-service-broker-azure/pkg/azure/monitorworkspace"
 	ss "github.com/Azure/open-service-broker-azure/pkg/azure/mssql"
 	mg "github.com/Azure/open-service-broker-azure/pkg/azure/mysql"
+	anf "github.com/Azure/open-service-broker-azure/pkg/azure/netappfiles"
+	ob "github.com/Azure/open-service-broker-azure/pkg/azure/orbital"
+	pw "github.com/Azure/open-service-broker-azure/pkg/azure/playwright"
 	pg "github.com/Azure/open-service-broker-azure/pkg/azure/postgresql"
+	pb "github.com/Azure/open-service-broker-azure/pkg/azure/powerbiembedded"
+	qt "github.com/Azure/open-service-broker-azure/pkg/azure/quantum"
 	rc "github.com/Azure/open-service-broker-azure/pkg/azure/rediscache"
+	re "github.com/Azure/open-service-broker-azure/pkg/azure/redisenterprise"
 	se "github.com/Azure/open-service-broker-azure/pkg/azure/search"
 	sb "github.com/Azure/open-service-broker-azure/pkg/azure/servicebus"
+	spa "github.com/Azure/open-service-broker-azure/pkg/azure/springapps"
 	sa "github.com/Azure/open-service-broker-azure/pkg/azure/storage"
+	asa "github.com/Azure/open-service-broker-azure/pkg/azure/streamanalytics"
+	ts "github.com/Azure/open-service-broker-azure/pkg/azure/trustedsigning"
+	vm "github.com/Azure/open-service-broker-azure/pkg/azure/virtualmachine"
+	wp "github.com/Azure/open-service-broker-azure/pkg/azure/webpubsub"
 	"github.com/Azure/open-service-broker-azure/pkg/services/mysqldb"
 	"github.com/Azure/open-service-broker-azure/pkg/services/sqldb"
 
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 	"github.com/Azure/open-service-broker-azure/pkg/services/aci"
+	"github.com/Azure/open-service-broker-azure/pkg/services/arc"
+	"github.com/Azure/open-service-broker-azure/pkg/services/automation"
+	"github.com/Azure/open-service-broker-azure/pkg/services/backup"
+	"github.com/Azure/open-service-broker-azure/pkg/services/bastion"
+	"github.com/Azure/open-service-broker-azure/pkg/services/chaosstudio"
+	"github.com/Azure/open-service-broker-azure/pkg/services/communication"
+	"github.com/Azure/open-service-broker-azure/pkg/services/confidentialledger"
+	"github.com/Azure/open-service-broker-azure/pkg/services/containerapps"
 	"github.com/Azure/open-service-broker-azure/pkg/services/cosmosdb"
+	"github.com/Azure/open-service-broker-azure/pkg/services/devbox"
+	"github.com/Azure/open-service-broker-azure/pkg/services/devtestlabs"
+	"github.com/Azure/open-service-broker-azure/pkg/services/digitaltwins"
+	"github.com/Azure/open-service-broker-azure/pkg/services/dms"
+	"github.com/Azure/open-service-broker-azure/pkg/services/elasticsan"
 	"github.com/Azure/open-service-broker-azure/pkg/services/eventhubs"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fhir"
+	"github.com/Azure/open-service-broker-azure/pkg/services/firewallpolicy"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fluidrelay"
+	"github.com/Azure/open-service-broker-azure/pkg/services/iotdps"
 	"github.com/Azure/open-service-broker-azure/pkg/services/keyvault"
+	"github.com/Azure/open-service-broker-azure/pkg/services/kusto"
+	"github.com/Azure/open-service-broker-azure/pkg/services/loadbalancer"
+	"github.com/Azure/open-service-broker-azure/pkg/services/loadtesting"
+	"github.com/Azure/open-service-broker-azure/pkg/services/maintenance"
+	"github.com/Azure/open-service-broker-azure/pkg/services/managedhsm"
+	"github.com/Azure/open-service-broker-azure/pkg/services/managedlustre"
+	"github.com/Azure/open-service-broker-azure/pkg/services/mediaservices"
+	"github.com/Azure/open-service-broker-azure/pkg/services/mongovcore"
+	"github.com/Azure/open-service-broker-azure/pkg/services/monitorworkspace"
+	"github.com/Azure/open-service-broker-azure/pkg/services/netappfiles"
+	"github.com/Azure/open-service-broker-azure/pkg/services/orbital"
+	"github.com/Azure/open-service-broker-azure/pkg/services/playwright"
 	"github.com/Azure/open-service-broker-azure/pkg/services/postgresqldb"
+	"github.com/Azure/open-service-broker-azure/pkg/services/powerbiembedded"
+	"github.com/Azure/open-service-broker-azure/pkg/services/quantum"
 	"github.com/Azure/open-service-broker-azure/pkg/services/rediscache"
+	"github.com/Azure/open-service-broker-azure/pkg/services/redisenterprise"
 	"github.com/Azure/open-service-broker-azure/pkg/services/search"
 	"github.com/Azure/open-service-broker-azure/pkg/services/servicebus"
+	"github.com/Azure/open-service-broker-azure/pkg/services/springapps"
 	"github.com/Azure/open-service-broker-azure/pkg/services/storage"
+	"github.com/Azure/open-service-broker-azure/pkg/services/streamanalytics"
+	"github.com/Azure/open-service-broker-azure/pkg/services/trustedsigning"
+	"github.com/Azure/open-service-broker-azure/pkg/services/virtualmachine"
+	"github.com/Azure/open-service-broker-azure/pkg/services/webpubsub"
 )
 
 var modules []service.Module
 
 func initModules() error {
-	armDeployer, err := arm.NewDeployer()
+	var err error
+	modules, err = getModules("")
+	return err
+}
+
+// getModules returns every module, each managing resources in the specified
+// subscription
+func getModules(subscriptionID string) ([]service.Module, error) {
+	armDeployer, err := arm.NewDeployer(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing ARM template deployer: %s", err)
+	}
+	postgreSQLManager, err := pg.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing postgresql manager: %s", err)
+	}
+	mySQLManager, err := mg.NewManager(subscriptionID)
 	if err != nil {
-		return fmt.Errorf("error initializing ARM template deployer: %s", err)
+		return nil, fmt.Errorf("error initializing mysql manager: %s", err)
 	}
-	postgreSQLManager, err := pg.NewManager()
+	redisManager, err := rc.NewManager(subscriptionID)
 	if err != nil {
-		return fmt.Errorf("error initializing postgresql manager: %s", err)
+		return nil, fmt.Errorf("error initializing redis manager: %s", err)
 	}
-	mySQLManager, err := mg.NewManager()
+	serviceBusManager, err := sb.NewManager(subscriptionID)
 	if err != nil {
-		return fmt.Errorf("error initializing mysql manager: %s", err)
+		return nil, fmt.Errorf("error initializing service bus manager: %s", err)
 	}
-	redisManager, err := rc.NewManager()
+	eventHubManager, err := eh.NewManager(subscriptionID)
 	if err != nil {
-		return fmt.Errorf("error initializing redis manager: %s", err)
+		return nil, fmt.Errorf("error initializing event hub manager: %s", err)
 	}
-	serviceBusManager, err := sb.NewManager()
+	keyvaultManager, err := kv.NewManager(subscriptionID)
 	if err != nil {
-		return fmt.Errorf("error initializing service bus manager: %s", err)
+		return nil, fmt.Errorf("error initializing keyvault manager: %s", err)
 	}
-	eventHubManager, err := eh.NewManager()
+	msSQLManager, err := ss.NewManager(subscriptionID)
 	if err != nil {
-		return fmt.Errorf("error initializing event hub manager: %s", err)
+		return nil, fmt.Errorf("error initializing mssql manager: %s", err)
 	}
-	keyvaultManager, err := kv.NewManager()
+	cosmosDBManager, err := cd.NewManager(subscriptionID)
 	if err != nil {
-		return fmt.Errorf("error initializing keyvault manager: %s", err)
+		return nil, fmt.Errorf("error initializing cosmosdb manager: %s", err)
 	}
-	msSQLManager, err := ss.NewManager()
+	storageManager, err := sa.NewManager(subscriptionID)
 	if err != nil {
-		return fmt.Errorf("error initializing mssql manager: %s", err)
+		return nil, fmt.Errorf("error initializing storage manager: %s", err)
 	}
-	cosmosDBManager, err := cd.NewManager()
+	searchManager, err := se.NewManager(subscriptionID)
 	if err != nil {
-		return fmt.Errorf("error initializing cosmosdb manager: %s", err)
+		return nil, fmt.Errorf("error initializing search manager: %s", err)
 	}
-	storageManager, err := sa.NewManager()
+	aciManager, err := ac.NewManager(subscriptionID)
 	if err != nil {
-		return fmt.Errorf("error initializing storage manager: %s", err)
+		return nil, fmt.Errorf("error initializing aci manager: %s", err)
 	}
-	searchManager, err := se.NewManager()
+	communicationManager, err := cm.NewManager(subscriptionID)
 	if err != nil {
-		return fmt.Errorf("error initializing search manager: %s", err)
+		return nil, fmt.Errorf("error initializing communication manager: %s", err)
 	}
-	aciManager, err := ac.NewManager()
+	kustoManager, err := ku.NewManager(subscriptionID)
 	if err != nil {
-		return fmt.Errorf("error initializing aci manager: %s", err)
+		return nil, fmt.Errorf("error initializing kusto manager: %s", err)
+	}
+	autoscaleManager, err := as.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing autoscale manager: %s", err)
+	}
+	devTestLabsManager, err := dl.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing devtestlabs manager: %s", err)
+	}
+	dmsManager, err := dm.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing dms manager: %s", err)
+	}
+	bastionManager, err := ba.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing bastion manager: %s", err)
+	}
+	managedHSMManager, err := mh.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing managed hsm manager: %s", err)
+	}
+	metricsManager, err := mt.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing metrics manager: %s", err)
+	}
+	virtualMachineManager, err := vm.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing virtual machine manager: %s", err)
+	}
+	loadTestingManager, err := lt.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing load testing manager: %s", err)
+	}
+	streamAnalyticsManager, err := asa.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing stream analytics manager: %s", err)
+	}
+	fluidRelayManager, err := fr.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing fluid relay manager: %s", err)
+	}
+	elasticSANManager, err := es.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing elastic SAN manager: %s", err)
+	}
+	chaosStudioManager, err := ch.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing chaos studio manager: %s", err)
+	}
+	containerAppsManager, err := ca.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing container apps manager: %s", err)
+	}
+	fhirManager, err := fh.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing FHIR manager: %s", err)
+	}
+	digitalTwinsManager, err := dt.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing digital twins manager: %s", err)
+	}
+	powerBIEmbeddedManager, err := pb.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"error initializing Power BI Embedded manager: %s",
+			err,
+		)
+	}
+	mediaServicesManager, err := ms.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing media services manager: %s", err)
+	}
+	backupManager, err := bk.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing backup manager: %s", err)
+	}
+	arcManager, err := ar.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing arc manager: %s", err)
+	}
+	managedLustreManager, err := ml.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing managed lustre manager: %s", err)
+	}
+	loadBalancerManager, err := lb.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing load balancer manager: %s", err)
+	}
+	webPubSubManager, err := wp.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing web pubsub manager: %s", err)
+	}
+	devBoxManager, err := db.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing dev box manager: %s", err)
+	}
+	orbitalManager, err := ob.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing orbital manager: %s", err)
+	}
+	quantumManager, err := qt.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing quantum manager: %s", err)
+	}
+	dpsManager, err := dps.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing iot dps manager: %s", err)
+	}
+	automationManager, err := aa.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing automation manager: %s", err)
+	}
+	ledgerManager, err := cl.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"error initializing confidential ledger manager: %s",
+			err,
+		)
+	}
+	monitorWorkspaceManager, err := mw.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"error initializing monitor workspace manager: %s",
+			err,
+		)
+	}
+	netAppFilesManager, err := anf.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing netapp files manager: %s", err)
+	}
+	firewallPolicyManager, err := fp.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"error initializing firewall policy manager: %s",
+			err,
+		)
+	}
+	springAppsManager, err := spa.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing spring apps manager: %s", err)
+	}
+	mongoVCoreManager, err := mv.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"error initializing mongodb vcore manager: %s",
+			err,
+		)
+	}
+	redisEnterpriseManager, err := re.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"error initializing redis enterprise manager: %s",
+			err,
+		)
+	}
+	trustedSigningManager, err := ts.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"error initializing trusted signing manager: %s",
+			err,
+		)
+	}
+	playwrightManager, err := pw.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"error initializing playwright testing manager: %s",
+			err,
+		)
+	}
+	maintenanceManager, err := mc.NewManager(subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"error initializing maintenance configuration manager: %s",
+			err,
+		)
 	}
 
-	modules = []service.Module{
+	return []service.Module{
 		postgresqldb.New(armDeployer, postgreSQLManager),
 		rediscache.New(armDeployer, redisManager),
 		mysqldb.New(armDeployer, mySQLManager),
@@ -94,6 +361,46 @@ func initModules() error {
 		storage.New(armDeployer, storageManager),
 		search.New(armDeployer, searchManager),
 		aci.New(armDeployer, aciManager),
-	}
-	return nil
+		communication.New(armDeployer, communicationManager),
+		kusto.New(armDeployer, kustoManager, autoscaleManager),
+		devtestlabs.New(armDeployer, devTestLabsManager),
+		dms.New(armDeployer, dmsManager),
+		bastion.New(armDeployer, bastionManager),
+		managedhsm.New(armDeployer, managedHSMManager),
+		virtualmachine.New(
+			armDeployer,
+			virtualMachineManager,
+			metricsManager,
+		),
+		loadtesting.New(armDeployer, loadTestingManager),
+		streamanalytics.New(armDeployer, streamAnalyticsManager),
+		fluidrelay.New(armDeployer, fluidRelayManager),
+		elasticsan.New(armDeployer, elasticSANManager),
+		chaosstudio.New(armDeployer, chaosStudioManager),
+		containerapps.New(armDeployer, containerAppsManager),
+		fhir.New(armDeployer, fhirManager),
+		digitaltwins.New(armDeployer, digitalTwinsManager),
+		powerbiembedded.New(armDeployer, powerBIEmbeddedManager),
+		mediaservices.New(armDeployer, mediaServicesManager, storageManager),
+		backup.New(armDeployer, backupManager),
+		arc.New(armDeployer, arcManager),
+		managedlustre.New(armDeployer, managedLustreManager),
+		loadbalancer.New(armDeployer, loadBalancerManager),
+		webpubsub.New(armDeployer, webPubSubManager),
+		devbox.New(armDeployer, devBoxManager),
+		orbital.New(armDeployer, orbitalManager),
+		quantum.New(armDeployer, quantumManager, storageManager),
+		iotdps.New(armDeployer, dpsManager),
+		automation.New(armDeployer, automationManager),
+		confidentialledger.New(armDeployer, ledgerManager),
+		monitorworkspace.New(armDeployer, monitorWorkspaceManager),
+		netappfiles.New(armDeployer, netAppFilesManager),
+		firewallpolicy.New(armDeployer, firewallPolicyManager),
+		springapps.New(armDeployer, springAppsManager),
+		mongovcore.New(armDeployer, mongoVCoreManager),
+		redisenterprise.New(armDeployer, redisEnterpriseManager),
+		trustedsigning.New(armDeployer, trustedSigningManager),
+		playwright.New(armDeployer, playwrightManager),
+		maintenance.New(armDeployer, maintenanceManager),
+	}, nil
 }
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/Azure/open-service-broker-azure/pkg/telemetry"
+)
+
+// getTelemetrySamplingFn returns a function that reads the telemetry sampling
+// from the named JSON file, e.g.
+// {"traceSampleRate": 0.1, "maxSeriesPerMetric": 50}. Options the file omits
+// assume the given defaults. The file is read afresh on every call, so that it
+// can be edited, or replaced by an updated ConfigMap, while the broker is
+// running.
+func getTelemetrySamplingFn(
+	path string,
+	defaults telemetry.Sampling,
+) telemetry.SamplingFn {
+	return func() (telemetry.Sampling, error) {
+		samplingJSON, err := ioutil.ReadFile(path)
+		if err != nil {
+			return telemetry.Sampling{}, fmt.Errorf(
+				`error reading "%s": %s`,
+				path,
+				err,
+			)
+		}
+		sampling := defaults
+		if err := json.Unmarshal(samplingJSON, &sampling); err != nil {
+			return telemetry.Sampling{}, fmt.Errorf(
+				`error parsing "%s": %s`,
+				path,
+				err,
+			)
+		}
+		if err := sampling.Validate(); err != nil {
+			return telemetry.Sampling{}, fmt.Errorf(
+				`invalid sampling in "%s": %s`,
+				path,
+				err,
+			)
+		}
+		return sampling, nil
+	}
+}
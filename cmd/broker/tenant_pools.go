@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	redisAsync "github.com/Azure/open-service-broker-azure/pkg/async/redis"
+)
+
+// tenantPoolConfig represents the pool of workers dedicated to a single
+// organization, as specified in the tenant pools file
+type tenantPoolConfig struct {
+	Workers       int `json:"workers"`
+	QueueCapacity int `json:"queueCapacity"`
+}
+
+// getTenantPoolsFn returns a function that reads the pools dedicated to
+// organizations from the named JSON file, which maps organization GUIDs to
+// pools, e.g. {"<organizationGUID>": {"workers": 2, "queueCapacity": 20}}. The
+// file is read afresh on every call, so that it can be edited, or replaced by
+// an updated ConfigMap, while the broker is running.
+func getTenantPoolsFn(path string) redisAsync.TenantPoolsFn {
+	return func() (map[string]redisAsync.TenantPool, error) {
+		poolsJSON, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf(`error reading "%s": %s`, path, err)
+		}
+		pools := map[string]tenantPoolConfig{}
+		if err := json.Unmarshal(poolsJSON, &pools); err != nil {
+			return nil, fmt.Errorf(`error parsing "%s": %s`, path, err)
+		}
+		tenantPools := make(map[string]redisAsync.TenantPool, len(pools))
+		for organizationGUID, pool := range pools {
+			if pool.Workers < 1 {
+				return nil, fmt.Errorf(
+					`invalid workers for organization "%s": %d`,
+					organizationGUID,
+					pool.Workers,
+				)
+			}
+			if pool.QueueCapacity < 0 {
+				return nil, fmt.Errorf(
+					`invalid queueCapacity for organization "%s": %d`,
+					organizationGUID,
+					pool.QueueCapacity,
+				)
+			}
+			tenantPools[organizationGUID] = redisAsync.TenantPool{
+				Workers:       pool.Workers,
+				QueueCapacity: pool.QueueCapacity,
+			}
+		}
+		return tenantPools, nil
+	}
+}
@@ -13,6 +13,8 @@ import (
 	"github.com/Azure/open-service-broker-azure/pkg/crypto/noop"
 	"github.com/Azure/open-service-broker-azure/pkg/http/filter"
 	"github.com/Azure/open-service-broker-azure/pkg/http/filters"
+	"github.com/Azure/open-service-broker-azure/pkg/redaction"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
 	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
 	memoryStorage "github.com/Azure/open-service-broker-azure/pkg/storage/memory"
 	log "github.com/Sirupsen/logrus"
@@ -37,15 +39,17 @@ func main() {
 	)
 
 	noopCodec := noop.NewCodec()
-	server, err := api.NewServer(
-		8080,
-		memoryStorage.NewStore(fakeCatalog, noopCodec),
-		fakeAsync.NewEngine(),
-		filterChain,
-		fakeCatalog,
-		" ",
-		" ",
-	)
+	server, err := api.NewServer(api.ServerConfig{
+		Port:                      8080,
+		Store:                     memoryStorage.NewStore(fakeCatalog, noopCodec),
+		AsyncEngine:               fakeAsync.NewEngine(),
+		FilterChain:               filterChain,
+		Catalog:                   fakeCatalog,
+		DefaultAzureLocation:      " ",
+		DefaultAzureResourceGroup: " ",
+		ResponseRedactionMode:     redaction.ModeFull,
+		DebugTracingMode:          service.DebugTracingModeOff,
+	})
 
 	if err != nil {
 		log.Fatal(err)
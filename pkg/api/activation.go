@@ -0,0 +1,103 @@
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// getActivateAt returns the time, if any, at which the "activateAt" parameter
+// in the given (provisioning or updating) parameter map asks for an instance
+// to be activated
+func getActivateAt(parameters map[string]interface{}) (*time.Time, error) {
+	activateAtIface, ok := parameters["activateAt"]
+	if !ok {
+		return nil, nil
+	}
+	activateAtStr, ok := activateAtIface.(string)
+	if !ok {
+		return nil, service.NewValidationError(
+			"activateAt",
+			fmt.Sprintf(`"%v" is not a string`, activateAtIface),
+		)
+	}
+	activateAt, err := time.Parse(time.RFC3339, activateAtStr)
+	if err != nil {
+		return nil, service.NewValidationError(
+			"activateAt",
+			fmt.Sprintf(`"%s" is not an RFC 3339 timestamp`, activateAtStr),
+		)
+	}
+	activateAt = activateAt.UTC()
+	return &activateAt, nil
+}
+
+// validateActivateAt verifies that a new instance of the given service can be
+// provisioned with its activation deferred until the given time
+func validateActivateAt(svc service.Service, activateAt time.Time) error {
+	if _, ok := svc.GetServiceManager().(service.Activator); !ok {
+		return service.NewValidationError(
+			"activateAt",
+			fmt.Sprintf(
+				`service "%s" does not support deferred activation`,
+				svc.GetName(),
+			),
+		)
+	}
+	if !activateAt.After(time.Now()) {
+		return service.NewValidationError(
+			"activateAt",
+			fmt.Sprintf(
+				`activateAt "%s" is not in the future`,
+				activateAt.Format(time.RFC3339),
+			),
+		)
+	}
+	return nil
+}
+
+// rescheduleActivation changes when an instance that is pending activation
+// will be activated. If provisioning has already completed, a task is
+// submitted to activate the instance at the new time; otherwise, that happens
+// once provisioning completes. The task previously submitted, if any, does
+// nothing when it finds the instance's activation has been rescheduled.
+func (s *server) rescheduleActivation(
+	instance *service.Instance,
+	activateAt time.Time,
+) error {
+	instance.Activation.ActivateAt = activateAt
+	if err := s.store.WriteInstance(*instance); err != nil {
+		return fmt.Errorf("error persisting instance: %s", err)
+	}
+	if !instance.Activation.Deactivated {
+		return nil
+	}
+	task := async.NewScheduledTask(
+		"activateInstance",
+		map[string]string{
+			"instanceID": instance.InstanceID,
+			"activateAt": activateAt.Format(time.RFC3339Nano),
+		},
+		activateAt,
+	)
+	task.SetTenant(instance.OrganizationGUID)
+	if err := s.asyncEngine.SubmitTask(task); err != nil {
+		return fmt.Errorf("error submitting activation task: %s", err)
+	}
+	return nil
+}
+
+// isActivationRequested returns a bool indicating whether an existing instance
+// was provisioned with the deferred activation, if any, that a provisioning
+// request asks for
+func isActivationRequested(
+	instance service.Instance,
+	activateAt *time.Time,
+) bool {
+	if instance.Activation == nil || activateAt == nil {
+		return instance.Activation == nil && activateAt == nil
+	}
+	return instance.Activation.ActivateAt.Equal(*activateAt)
+}
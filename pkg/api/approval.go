@@ -0,0 +1,124 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/Azure/open-service-broker-azure/pkg/approval"
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	log "github.com/Sirupsen/logrus"
+	"github.com/gorilla/mux"
+)
+
+// ApprovalDecisionRequest represents an external approval service's callback
+// with its decision about an instance that awaits approval. Decision must be
+// either "approved" or "denied"; Reason is optional.
+type ApprovalDecisionRequest struct {
+	Decision string `json:"decision"`
+	Reason   string `json:"reason"`
+}
+
+// decideApproval accepts an external approval service's decision about an
+// instance that awaits approval. The decision is acted upon asynchronously,
+// just as if the broker had polled the approval service for it.
+func (s *server) decideApproval(w http.ResponseWriter, r *http.Request) {
+	instanceID := mux.Vars(r)["instance_id"]
+
+	logFields := log.Fields{
+		"instanceID": instanceID,
+	}
+
+	log.WithFields(logFields).Debug("received approval decision")
+
+	bodyBytes, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error(
+			"approval decision error: error reading request body",
+		)
+		s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
+		return
+	}
+	defer r.Body.Close() // nolint: errcheck
+	decisionRequest := ApprovalDecisionRequest{}
+	if err := json.Unmarshal(bodyBytes, &decisionRequest); err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Debug(
+			"bad approval decision: error unmarshaling request body",
+		)
+		s.writeResponse(w, http.StatusBadRequest, generateMalformedRequestResponse())
+		return
+	}
+	switch decisionRequest.Decision {
+	case approval.DecisionApproved, approval.DecisionDenied:
+	default:
+		logFields["decision"] = decisionRequest.Decision
+		log.WithFields(logFields).Debug("bad approval decision: unknown decision")
+		s.writeResponse(
+			w,
+			http.StatusBadRequest,
+			generateValidationFailedResponse(
+				service.NewValidationError(
+					"decision",
+					fmt.Sprintf(
+						`invalid decision: "%s"; it must be "%s" or "%s"`,
+						decisionRequest.Decision,
+						approval.DecisionApproved,
+						approval.DecisionDenied,
+					),
+				),
+			),
+		)
+		return
+	}
+
+	instance, ok, err := s.store.GetInstance(instanceID)
+	if err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error(
+			"approval decision error: error retrieving instance by id",
+		)
+		s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
+		return
+	}
+	if !ok {
+		log.WithFields(logFields).Debug(
+			"bad approval decision: the instance does not exist",
+		)
+		s.writeResponse(w, http.StatusNotFound, generateEmptyResponse())
+		return
+	}
+	if instance.Status != service.InstanceStateAwaitingApproval {
+		logFields["status"] = instance.Status
+		log.WithFields(logFields).Debug(
+			"bad approval decision: the instance is not awaiting approval",
+		)
+		s.writeResponse(w, http.StatusConflict, generateEmptyResponse())
+		return
+	}
+
+	task := async.NewTask(
+		"awaitApproval",
+		map[string]string{
+			"instanceID": instanceID,
+			"decision":   decisionRequest.Decision,
+			"reason":     decisionRequest.Reason,
+		},
+	)
+	// If approved, provisioning continues on the instance's tenant's behalf
+	task.SetTenant(instance.OrganizationGUID)
+	if err := s.asyncEngine.SubmitTask(task); err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error(
+			"approval decision error: error submitting task",
+		)
+		s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
+		return
+	}
+	s.writeResponse(w, http.StatusAccepted, generateEmptyResponse())
+	logFields["decision"] = decisionRequest.Decision
+	log.WithFields(logFields).Info("approval decision accepted")
+}
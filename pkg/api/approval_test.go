@@ -0,0 +1,207 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	fakeAsync "github.com/Azure/open-service-broker-azure/pkg/async/fake"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProvisioningPlanThatRequiresApproval(t *testing.T) {
+	s, _, err := getTestServer("eastus", "")
+	assert.Nil(t, err)
+	s.approvalPolicy = service.NewApprovalPolicy(
+		[]string{"fake/standard"},
+		0,
+		nil,
+	)
+	instanceID := getDisposableInstanceID()
+	req, err := getProvisionRequest(
+		instanceID,
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&ProvisioningRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+		},
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	instance, ok, err := s.store.GetInstance(instanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, service.InstanceStateAwaitingApproval, instance.Status)
+	tasks := s.asyncEngine.(*fakeAsync.Engine).SubmittedTasks
+	assert.Len(t, tasks, 1)
+	for _, task := range tasks {
+		assert.Equal(t, "awaitApproval", task.GetJobName())
+	}
+}
+
+func TestProvisioningCostlyInstanceRequiresApproval(t *testing.T) {
+	s, _, err := getTestServer("eastus", "")
+	assert.Nil(t, err)
+	s.pricingTable, err = service.NewPricingTable(
+		"USD",
+		[]byte(`[{"monthlyCost": 150}]`),
+	)
+	assert.Nil(t, err)
+	s.approvalPolicy = service.NewApprovalPolicy(
+		nil,
+		100,
+		map[string]float64{
+			"generous-org": 200,
+		},
+	)
+	instanceID := getDisposableInstanceID()
+	req, err := getProvisionRequest(
+		instanceID,
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&ProvisioningRequest{
+			ServiceID:        fake.ServiceID,
+			PlanID:           fake.StandardPlanID,
+			OrganizationGUID: "org",
+		},
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	instance, ok, err := s.store.GetInstance(instanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, service.InstanceStateAwaitingApproval, instance.Status)
+	assert.Equal(t, float64(100), instance.Approval.CostThreshold)
+
+	// The same instance is cheap enough for an organization with a higher
+	// threshold
+	instanceID = getDisposableInstanceID()
+	req, err = getProvisionRequest(
+		instanceID,
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&ProvisioningRequest{
+			ServiceID:        fake.ServiceID,
+			PlanID:           fake.StandardPlanID,
+			OrganizationGUID: "generous-org",
+		},
+	)
+	assert.Nil(t, err)
+	rr = httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	instance, ok, err = s.store.GetInstance(instanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, service.InstanceStateProvisioning, instance.Status)
+	assert.Nil(t, instance.Approval)
+}
+
+func TestDecidingApprovalWithInvalidDecision(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	instanceID := writeAwaitingApprovalInstance(t, s)
+	req, err := getApprovalDecisionRequest(
+		instanceID,
+		[]byte(`{"decision":"maybe"}`),
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Empty(t, s.asyncEngine.(*fakeAsync.Engine).SubmittedTasks)
+}
+
+func TestDecidingApprovalOfInstanceThatDoesNotExist(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	req, err := getApprovalDecisionRequest(
+		getDisposableInstanceID(),
+		[]byte(`{"decision":"approved"}`),
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestDecidingApprovalOfInstanceThatIsNotAwaitingApproval(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	err = s.store.WriteInstance(service.Instance{
+		InstanceID: instanceID,
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateProvisioning,
+	})
+	assert.Nil(t, err)
+	req, err := getApprovalDecisionRequest(
+		instanceID,
+		[]byte(`{"decision":"approved"}`),
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Empty(t, s.asyncEngine.(*fakeAsync.Engine).SubmittedTasks)
+}
+
+func TestDecidingApprovalOfInstanceThatIsAwaitingApproval(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	instanceID := writeAwaitingApprovalInstance(t, s)
+	req, err := getApprovalDecisionRequest(
+		instanceID,
+		[]byte(`{"decision":"denied","reason":"over budget"}`),
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	tasks := s.asyncEngine.(*fakeAsync.Engine).SubmittedTasks
+	assert.Len(t, tasks, 1)
+	for _, task := range tasks {
+		assert.Equal(t, "awaitApproval", task.GetJobName())
+		assert.Equal(t, instanceID, task.GetArgs()["instanceID"])
+		assert.Equal(t, "denied", task.GetArgs()["decision"])
+		assert.Equal(t, "over budget", task.GetArgs()["reason"])
+		assert.Equal(t, "org", task.GetTenant())
+	}
+}
+
+func writeAwaitingApprovalInstance(t *testing.T, s *server) string {
+	instanceID := getDisposableInstanceID()
+	err := s.store.WriteInstance(service.Instance{
+		InstanceID:       instanceID,
+		ServiceID:        fake.ServiceID,
+		PlanID:           fake.StandardPlanID,
+		Status:           service.InstanceStateAwaitingApproval,
+		OrganizationGUID: "org",
+	})
+	assert.Nil(t, err)
+	return instanceID
+}
+
+func getApprovalDecisionRequest(
+	instanceID string,
+	body []byte,
+) (*http.Request, error) {
+	return http.NewRequest(
+		http.MethodPost,
+		fmt.Sprintf("/admin/instances/%s/approval", instanceID),
+		bytes.NewBuffer(body),
+	)
+}
@@ -100,6 +100,30 @@ func (s *server) bind(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The broker's own credential rotation parameters are set aside before the
+	// remaining parameters are handed to the service
+	credentialRotation, validationErr :=
+		s.credentialRotation.getCredentialRotation(
+			instance.Service,
+			bindingRequest.Parameters,
+			time.Now(),
+		)
+	if validationErr != nil {
+		logFields["field"] = validationErr.Field
+		logFields["issue"] = validationErr.Issue
+		log.WithFields(logFields).Debug(
+			"bad binding request: validation error",
+		)
+		s.writeResponse(
+			w,
+			http.StatusBadRequest,
+			generateValidationFailedResponse(
+				s.redactValidationError(validationErr),
+			),
+		)
+		return
+	}
+
 	serviceManager := instance.Service.GetServiceManager()
 
 	// Unpack the parameter map in the request to a struct
@@ -222,7 +246,9 @@ func (s *server) bind(w http.ResponseWriter, r *http.Request) {
 			s.writeResponse(
 				w,
 				http.StatusBadRequest,
-				generateValidationFailedResponse(validationErr),
+				generateValidationFailedResponse(
+					s.redactValidationError(validationErr),
+				),
 			)
 			return
 		}
@@ -252,15 +278,63 @@ func (s *server) bind(w http.ResponseWriter, r *http.Request) {
 		// Storing the serviceID on the binding gives us a shortcut to finding
 		// the service and therefore the serviceManager later on-- even if the
 		// binding somehow gets orphaned and we can no longer find the instance.
-		ServiceID:         instance.ServiceID,
-		BindingID:         bindingID,
-		BindingParameters: bindingParameters,
-		Details:           bindingDetails,
-		Created:           time.Now(),
+		ServiceID:          instance.ServiceID,
+		BindingID:          bindingID,
+		BindingParameters:  bindingParameters,
+		Details:            bindingDetails,
+		Created:            time.Now(),
+		CredentialRotation: credentialRotation,
+	}
+
+	// Platforms that watch the GitOps repository learn of the binding only from
+	// its manifest, so the binding isn't complete until that has been written
+	if s.manifestWriter != nil {
+		var credentials service.Credentials
+		credentials, err = serviceManager.GetCredentials(instance, binding)
+		if err != nil {
+			s.handleBindingError(
+				binding,
+				err,
+				"error extracting credentials for GitOps manifest",
+				w,
+			)
+			return
+		}
+		binding.GitOpsManifest, err = s.manifestWriter.WriteManifest(
+			instance,
+			bindingID,
+			credentials,
+		)
+		if err != nil {
+			s.handleBindingError(
+				binding,
+				err,
+				"error writing GitOps manifest",
+				w,
+			)
+			return
+		}
 	}
 
 	binding.Status = service.BindingStateBound
 	if err = s.store.WriteBinding(binding); err != nil {
+		// The manifest would otherwise go on describing a binding that failed.
+		// If it can't be deleted, it's left on the binding so that unbinding
+		// deletes it.
+		if binding.GitOpsManifest != nil {
+			if deleteErr := s.manifestWriter.DeleteManifest(
+				*binding.GitOpsManifest,
+			); deleteErr != nil {
+				logFields["manifestPath"] = binding.GitOpsManifest.Path
+				logFields["error"] = deleteErr
+				log.WithFields(logFields).Error(
+					"binding error: error deleting GitOps manifest of binding that " +
+						"could not be persisted",
+				)
+			} else {
+				binding.GitOpsManifest = nil
+			}
+		}
 		s.handleBindingError(
 			binding,
 			err,
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	log "github.com/Sirupsen/logrus"
+)
+
+// startBlueGreenUpdate begins replacing the given instance with a green
+// instance that applies the given plan and updating parameters. The green
+// instance is persisted alongside the blue one and provisioned
+// asynchronously. The blue instance itself is left as it is, apart from being
+// marked as updating, until its bindings are cut over.
+func (s *server) startBlueGreenUpdate(
+	w http.ResponseWriter,
+	blueGreenUpdater service.BlueGreenUpdater,
+	instance service.Instance,
+	plan service.Plan,
+	updatingParameters service.UpdatingParameters,
+	logFields log.Fields,
+) {
+	greenInstanceID := service.GetGreenInstanceID(instance.InstanceID)
+	logFields["greenInstanceID"] = greenInstanceID
+	// An instance left behind by an earlier blue-green update whose rollback or
+	// tear down failed still holds resources that nothing else knows about
+	for _, instanceID := range []string{
+		greenInstanceID,
+		service.GetRetiredBlueInstanceID(instance.InstanceID),
+	} {
+		_, ok, err := s.store.GetInstance(instanceID)
+		if err != nil {
+			logFields["error"] = err
+			log.WithFields(logFields).Error(
+				"pre-updating error: error retrieving instance left behind by an " +
+					"earlier blue-green update",
+			)
+			s.writeResponse(
+				w,
+				http.StatusInternalServerError,
+				generateEmptyResponse(),
+			)
+			return
+		}
+		if ok {
+			logFields["leftoverInstanceID"] = instanceID
+			log.WithFields(logFields).Debug(
+				"bad updating request: an instance left behind by an earlier " +
+					"blue-green update still exists",
+			)
+			s.writeResponse(w, http.StatusConflict, generateEmptyResponse())
+			return
+		}
+	}
+	if instance.IsSuspended() {
+		log.WithFields(logFields).Debug(
+			"bad updating request: a suspended instance cannot be replaced",
+		)
+		s.writeResponse(w, http.StatusUnprocessableEntity, generateEmptyResponse())
+		return
+	}
+
+	provisioner, err := instance.Service.GetServiceManager().GetProvisioner(plan)
+	if err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error(
+			"pre-updating error: error retrieving provisioner for service and plan",
+		)
+		s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
+		return
+	}
+	firstStepName, ok := provisioner.GetFirstStepName()
+	if !ok {
+		log.WithFields(logFields).Error(
+			"pre-updating error: no steps found for provisioning service and plan",
+		)
+		s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
+		return
+	}
+	provisioningParameters, details, err := blueGreenUpdater.GetGreenInstance(
+		instance,
+		plan,
+		updatingParameters,
+	)
+	if err != nil {
+		s.handlePossibleValidationError(err, w, logFields)
+		return
+	}
+
+	now := time.Now()
+	green := service.Instance{
+		InstanceID:             greenInstanceID,
+		ServiceID:              instance.ServiceID,
+		PlanID:                 plan.GetID(),
+		ProvisioningParameters: provisioningParameters,
+		UpdatingParameters:     updatingParameters,
+		Status:                 service.InstanceStateProvisioning,
+		Location:               instance.Location,
+		ResourceGroup:          instance.ResourceGroup,
+		SubscriptionID:         instance.SubscriptionID,
+		ParentAlias:            instance.ParentAlias,
+		DependsOn:              instance.DependsOn,
+		Tags:                   instance.Tags,
+		OrganizationGUID:       instance.OrganizationGUID,
+		CostCenter:             instance.CostCenter,
+		Details:                details,
+		Created:                now,
+	}
+	if err := s.store.WriteInstance(green); err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error(
+			"updating error: error persisting green instance",
+		)
+		s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
+		return
+	}
+	instance.Status = service.InstanceStateUpdating
+	instance.StatusReason = ""
+	instance.BlueGreen = &service.BlueGreenState{
+		Phase:   service.BlueGreenPhaseProvisioningGreen,
+		Started: now,
+	}
+	if err := s.store.WriteInstance(instance); err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error(
+			"updating error: error persisting updated instance",
+		)
+		// Nothing has been provisioned into the green instance yet
+		if _, err := s.store.DeleteInstance(greenInstanceID); err != nil {
+			logFields["error"] = err
+			log.WithFields(logFields).Error(
+				"updating error: error deleting green instance",
+			)
+		}
+		s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
+		return
+	}
+
+	task := async.NewTask(
+		"executeGreenProvisioningStep",
+		map[string]string{
+			"stepName":   firstStepName,
+			"instanceID": instance.InstanceID,
+		},
+	)
+	task.SetTenant(instance.OrganizationGUID)
+	if err := s.asyncEngine.SubmitTask(task); err != nil {
+		logFields["step"] = firstStepName
+		logFields["error"] = err
+		log.WithFields(logFields).Error(
+			"updating error: error submitting green provisioning task",
+		)
+		s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
+		return
+	}
+
+	s.writeResponse(w, http.StatusAccepted, generateUpdateAcceptedResponse())
+
+	log.WithFields(logFields).Debug("asynchronous blue-green updating initiated")
+}
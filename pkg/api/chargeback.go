@@ -0,0 +1,351 @@
+package api
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	log "github.com/Sirupsen/logrus"
+)
+
+const (
+	chargebackFormatJSON = "json"
+	chargebackFormatCSV  = "csv"
+	// defaultChargebackPeriod is the time range a chargeback report covers if
+	// its start isn't specified
+	defaultChargebackPeriod = 30 * 24 * time.Hour
+	// hoursPerMonth is the average number of hours in a month, by which monthly
+	// cost estimates are prorated
+	hoursPerMonth = 730
+)
+
+// ServiceChargeback represents the instances of a single service that are
+// charged back to a cost center, and their estimated cost over the time range
+// of the report. Instances whose cost wasn't estimated when they were
+// provisioned are counted, but don't contribute to the estimated cost.
+type ServiceChargeback struct {
+	ServiceName          string  `json:"service_name"`
+	Instances            int64   `json:"instances"`
+	UnestimatedInstances int64   `json:"unestimated_instances"`
+	EstimatedCost        float64 `json:"estimated_cost"`
+}
+
+// CostCenterChargeback represents the instances charged back to a single cost
+// center, in total and by service. Instances not attributed to any cost center
+// are reported under an empty cost center.
+type CostCenterChargeback struct {
+	CostCenter           string              `json:"cost_center"`
+	Instances            int64               `json:"instances"`
+	UnestimatedInstances int64               `json:"unestimated_instances"`
+	EstimatedCost        float64             `json:"estimated_cost"`
+	Services             []ServiceChargeback `json:"services"`
+}
+
+// ChargebackReport represents the response to a request to fetch the broker's
+// chargeback report. It covers every instance that existed at some point
+// within the report's time range, in order of cost center. Costs are prorated
+// from instances' estimated monthly costs by the time each existed within the
+// range.
+type ChargebackReport struct {
+	From        time.Time              `json:"from"`
+	To          time.Time              `json:"to"`
+	Currency    string                 `json:"currency,omitempty"`
+	CostCenters []CostCenterChargeback `json:"cost_centers"`
+}
+
+// GetChargebackReportFromJSON returns a new ChargebackReport unmarshalled from
+// the provided JSON []byte
+func GetChargebackReportFromJSON(
+	jsonBytes []byte,
+	report *ChargebackReport,
+) error {
+	return json.Unmarshal(jsonBytes, report)
+}
+
+// ToJSON returns a []byte containing a JSON representation of the chargeback
+// report
+func (c *ChargebackReport) ToJSON() ([]byte, error) {
+	return json.Marshal(c)
+}
+
+// ToCSV returns a []byte containing a CSV representation of the chargeback
+// report, with one row for each service of each cost center
+func (c *ChargebackReport) ToCSV() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	writer := csv.NewWriter(buf)
+	rows := [][]string{
+		{
+			"from",
+			"to",
+			"cost_center",
+			"service_name",
+			"instances",
+			"unestimated_instances",
+			"estimated_cost",
+			"currency",
+		},
+	}
+	for _, costCenter := range c.CostCenters {
+		for _, svc := range costCenter.Services {
+			rows = append(rows, []string{
+				c.From.Format(time.RFC3339),
+				c.To.Format(time.RFC3339),
+				costCenter.CostCenter,
+				svc.ServiceName,
+				strconv.FormatInt(svc.Instances, 10),
+				strconv.FormatInt(svc.UnestimatedInstances, 10),
+				strconv.FormatFloat(svc.EstimatedCost, 'f', 2, 64),
+				c.Currency,
+			})
+		}
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *server) getChargebackReport(w http.ResponseWriter, r *http.Request) {
+	log.Debug("received request to fetch chargeback report")
+
+	now := time.Now().UTC()
+	from, to, err := getChargebackReportRange(r, now)
+	if err != nil {
+		if validationErr, ok := err.(*service.ValidationError); ok {
+			s.writeResponse(
+				w,
+				http.StatusBadRequest,
+				generateValidationFailedResponse(validationErr),
+			)
+			return
+		}
+		s.writeResponse(w, http.StatusBadRequest, generateMalformedRequestResponse())
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = chargebackFormatJSON
+	}
+	if format != chargebackFormatJSON && format != chargebackFormatCSV {
+		s.writeResponse(
+			w,
+			http.StatusBadRequest,
+			generateValidationFailedResponse(
+				service.NewValidationError(
+					"format",
+					fmt.Sprintf(
+						`invalid format "%s"; allowed values are "%s" and "%s"`,
+						format,
+						chargebackFormatJSON,
+						chargebackFormatCSV,
+					),
+				),
+			),
+		)
+		return
+	}
+
+	report, err := s.buildChargebackReport(from, to, now)
+	if err != nil {
+		log.WithField("error", err).Error(
+			"chargeback report error: error building report",
+		)
+		s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
+		return
+	}
+	if format == chargebackFormatCSV {
+		reportCSV, err := report.ToCSV()
+		if err != nil {
+			log.WithField("error", err).Error(
+				"chargeback report error: error writing report as CSV",
+			)
+			s.writeResponse(
+				w,
+				http.StatusInternalServerError,
+				generateEmptyResponse(),
+			)
+			return
+		}
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set(
+			"Content-Disposition",
+			`attachment; filename="chargeback.csv"`,
+		)
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write(reportCSV); err != nil {
+			log.WithField("error", err).Error(
+				"api server error: error writing response",
+			)
+		}
+		return
+	}
+	reportJSON, err := report.ToJSON()
+	if err != nil {
+		log.WithField("error", err).Error(
+			"chargeback report error: error marshaling report",
+		)
+		s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
+		return
+	}
+	s.writeResponse(w, http.StatusOK, reportJSON)
+}
+
+// getChargebackReportRange returns the time range a chargeback report is
+// requested for. The range ends now, and begins defaultChargebackPeriod before
+// its end, unless otherwise specified.
+func getChargebackReportRange(
+	r *http.Request,
+	now time.Time,
+) (time.Time, time.Time, error) {
+	to := now
+	if toStr := r.URL.Query().Get("to"); toStr != "" {
+		var err error
+		if to, err = time.Parse(time.RFC3339, toStr); err != nil {
+			return time.Time{}, time.Time{}, service.NewValidationError(
+				"to",
+				fmt.Sprintf(`invalid time "%s"; times must be RFC 3339`, toStr),
+			)
+		}
+	}
+	from := to.Add(-defaultChargebackPeriod)
+	if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+		var err error
+		if from, err = time.Parse(time.RFC3339, fromStr); err != nil {
+			return time.Time{}, time.Time{}, service.NewValidationError(
+				"from",
+				fmt.Sprintf(`invalid time "%s"; times must be RFC 3339`, fromStr),
+			)
+		}
+	}
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, service.NewValidationError(
+			"from",
+			"the start of the time range must precede its end",
+		)
+	}
+	return from.UTC(), to.UTC(), nil
+}
+
+func (s *server) buildChargebackReport(
+	from time.Time,
+	to time.Time,
+	now time.Time,
+) (*ChargebackReport, error) {
+	report := &ChargebackReport{
+		From:        from,
+		To:          to,
+		CostCenters: []CostCenterChargeback{},
+	}
+	if s.pricingTable != nil {
+		report.Currency = s.pricingTable.GetCurrency()
+	}
+	instanceIDs, err := s.store.GetInstanceIDs()
+	if err != nil {
+		return nil, fmt.Errorf("error listing instances: %s", err)
+	}
+	costCenters := map[string]map[string]*ServiceChargeback{}
+	for _, instanceID := range instanceIDs {
+		instance, ok, err := s.store.GetInstance(instanceID)
+		if err != nil {
+			return nil, fmt.Errorf(
+				`error retrieving instance "%s": %s`,
+				instanceID,
+				err,
+			)
+		}
+		// The instance may have been deprovisioned since it was listed
+		if !ok || instance.Status == service.InstanceStateAwaitingApproval {
+			continue
+		}
+		// Resources aren't provisioned until any approval has been given
+		started := instance.GetProvisioningStarted()
+		if started.IsZero() {
+			started = from
+		}
+		if !started.Before(to) {
+			continue
+		}
+		services, ok := costCenters[instance.CostCenter]
+		if !ok {
+			services = map[string]*ServiceChargeback{}
+			costCenters[instance.CostCenter] = services
+		}
+		serviceName := instance.ServiceID
+		if instance.Service != nil {
+			serviceName = instance.Service.GetName()
+		}
+		svc, ok := services[serviceName]
+		if !ok {
+			svc = &ServiceChargeback{
+				ServiceName: serviceName,
+			}
+			services[serviceName] = svc
+		}
+		svc.Instances++
+		if instance.CostEstimate == nil ||
+			instance.CostEstimate.Currency != report.Currency {
+			svc.UnestimatedInstances++
+			continue
+		}
+		svc.EstimatedCost += getProratedCost(
+			instance.CostEstimate.MonthlyCost,
+			started,
+			from,
+			to,
+			now,
+		)
+	}
+	for costCenter, services := range costCenters {
+		costCenterChargeback := CostCenterChargeback{
+			CostCenter: costCenter,
+			Services:   []ServiceChargeback{},
+		}
+		for _, svc := range services {
+			costCenterChargeback.Instances += svc.Instances
+			costCenterChargeback.UnestimatedInstances += svc.UnestimatedInstances
+			costCenterChargeback.EstimatedCost += svc.EstimatedCost
+			costCenterChargeback.Services = append(
+				costCenterChargeback.Services,
+				*svc,
+			)
+		}
+		sort.Slice(costCenterChargeback.Services, func(i, j int) bool {
+			return costCenterChargeback.Services[i].ServiceName <
+				costCenterChargeback.Services[j].ServiceName
+		})
+		report.CostCenters = append(report.CostCenters, costCenterChargeback)
+	}
+	sort.Slice(report.CostCenters, func(i, j int) bool {
+		return report.CostCenters[i].CostCenter < report.CostCenters[j].CostCenter
+	})
+	return report, nil
+}
+
+// getProratedCost returns the share of the given monthly cost incurred by an
+// instance, provisioned at the given time and still in existence, between the
+// given times. Costs aren't incurred in the future, so the range is cut short
+// if it extends beyond now.
+func getProratedCost(
+	monthlyCost float64,
+	started time.Time,
+	from time.Time,
+	to time.Time,
+	now time.Time,
+) float64 {
+	if started.After(from) {
+		from = started
+	}
+	if now.Before(to) {
+		to = now
+	}
+	if !from.Before(to) {
+		return 0
+	}
+	return monthlyCost * to.Sub(from).Hours() / hoursPerMonth
+}
@@ -0,0 +1,159 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeChargebackTestInstances(t *testing.T, s *server, now time.Time) {
+	for instanceID, instance := range map[string]service.Instance{
+		"finance-1": {
+			CostCenter: "finance",
+			Created:    now.Add(-60 * 24 * time.Hour),
+			CostEstimate: &service.CostEstimate{
+				MonthlyCost: 73,
+				Currency:    "USD",
+			},
+		},
+		"finance-2": {
+			CostCenter: "finance",
+			// Provisioned halfway through the report's time range
+			Created: now.Add(-5 * 24 * time.Hour),
+			CostEstimate: &service.CostEstimate{
+				MonthlyCost: 73,
+				Currency:    "USD",
+			},
+		},
+		"marketing": {
+			CostCenter: "marketing",
+			Created:    now.Add(-24 * time.Hour),
+		},
+		"unattributed": {
+			Created: now.Add(-24 * time.Hour),
+		},
+		"awaiting-approval": {
+			CostCenter: "finance",
+			Status:     service.InstanceStateAwaitingApproval,
+			Created:    now.Add(-24 * time.Hour),
+		},
+	} {
+		instance.InstanceID = instanceID
+		instance.ServiceID = fake.ServiceID
+		instance.PlanID = fake.StandardPlanID
+		if instance.Status == "" {
+			instance.Status = service.InstanceStateProvisioned
+		}
+		assert.Nil(t, s.store.WriteInstance(instance))
+	}
+}
+
+func TestGetChargebackReport(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	s.pricingTable, err = service.NewPricingTable("USD", []byte("[]"))
+	assert.Nil(t, err)
+	now := time.Now().UTC()
+	writeChargebackTestInstances(t, s, now)
+	from := now.Add(-10 * 24 * time.Hour).Format(time.RFC3339)
+	req, err := http.NewRequest(
+		http.MethodGet,
+		"/admin/chargeback?from="+from,
+		nil,
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	report := &ChargebackReport{}
+	err = GetChargebackReportFromJSON(rr.Body.Bytes(), report)
+	assert.Nil(t, err)
+	assert.Equal(t, "USD", report.Currency)
+	assert.Len(t, report.CostCenters, 3)
+	assert.Equal(t, "", report.CostCenters[0].CostCenter)
+	assert.Equal(t, int64(1), report.CostCenters[0].Instances)
+	finance := report.CostCenters[1]
+	assert.Equal(t, "finance", finance.CostCenter)
+	assert.Equal(t, int64(2), finance.Instances)
+	assert.Equal(t, int64(0), finance.UnestimatedInstances)
+	// Ten days of one instance and five of the other, at 0.1 per hour
+	assert.InDelta(t, 36, finance.EstimatedCost, 0.1)
+	assert.Len(t, finance.Services, 1)
+	assert.Equal(t, "fake", finance.Services[0].ServiceName)
+	marketing := report.CostCenters[2]
+	assert.Equal(t, "marketing", marketing.CostCenter)
+	assert.Equal(t, int64(1), marketing.UnestimatedInstances)
+	assert.Equal(t, float64(0), marketing.EstimatedCost)
+}
+
+func TestGetChargebackReportAsCSV(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	err = s.store.WriteInstance(service.Instance{
+		InstanceID: "finance",
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateProvisioned,
+		CostCenter: "finance",
+		Created:    time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+	})
+	assert.Nil(t, err)
+	req, err := http.NewRequest(
+		http.MethodGet,
+		"/admin/chargeback?format=csv&from=2026-02-01T00:00:00Z"+
+			"&to=2026-03-01T00:00:00Z",
+		nil,
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/csv", rr.Header().Get("Content-Type"))
+	assert.Equal(
+		t,
+		"from,to,cost_center,service_name,instances,unestimated_instances,"+
+			"estimated_cost,currency\n"+
+			"2026-02-01T00:00:00Z,2026-03-01T00:00:00Z,finance,fake,1,1,0.00,\n",
+		rr.Body.String(),
+	)
+}
+
+func TestGetChargebackReportWithInvalidParameters(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	for _, query := range []string{
+		"format=xml",
+		"from=yesterday",
+		"from=2026-03-01T00:00:00Z&to=2026-02-01T00:00:00Z",
+	} {
+		req, err := http.NewRequest(
+			http.MethodGet,
+			"/admin/chargeback?"+query,
+			nil,
+		)
+		assert.Nil(t, err)
+		rr := httptest.NewRecorder()
+		s.router.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+}
+
+func TestGetProratedCost(t *testing.T) {
+	now := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	from := now.Add(-730 * time.Hour)
+	assert.Equal(t, float64(10), getProratedCost(10, from, from, now, now))
+	assert.Equal(
+		t,
+		float64(5),
+		getProratedCost(10, now.Add(-365*time.Hour), from, now, now),
+	)
+	// Costs aren't incurred in the future
+	halfway := from.Add(365 * time.Hour)
+	assert.Equal(t, float64(5), getProratedCost(10, from, from, now, halfway))
+	assert.Equal(t, float64(0), getProratedCost(10, now, from, now, now))
+}
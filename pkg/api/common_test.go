@@ -6,6 +6,8 @@ import (
 	fakeAsync "github.com/Azure/open-service-broker-azure/pkg/async/fake"
 	"github.com/Azure/open-service-broker-azure/pkg/crypto/noop"
 	"github.com/Azure/open-service-broker-azure/pkg/http/filter"
+	"github.com/Azure/open-service-broker-azure/pkg/redaction"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
 	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
 	memoryStorage "github.com/Azure/open-service-broker-azure/pkg/storage/memory"
 	uuid "github.com/satori/go.uuid"
@@ -56,15 +58,18 @@ func getTestServer(
 	if err != nil {
 		return nil, nil, err
 	}
-	s, err := NewServer(
-		8080,
-		memoryStorage.NewStore(fakeCatalog, noop.NewCodec()),
-		fakeAsync.NewEngine(),
-		filter.NewChain(),
-		fakeCatalog,
-		defaultAzureLocation,
-		defaultAzureResourceGroup,
-	)
+	noopCodec := noop.NewCodec()
+	s, err := NewServer(ServerConfig{
+		Port:                      8080,
+		Store:                     memoryStorage.NewStore(fakeCatalog, noopCodec),
+		AsyncEngine:               fakeAsync.NewEngine(),
+		FilterChain:               filter.NewChain(),
+		Catalog:                   fakeCatalog,
+		DefaultAzureLocation:      defaultAzureLocation,
+		DefaultAzureResourceGroup: defaultAzureResourceGroup,
+		ResponseRedactionMode:     redaction.ModeFull,
+		DebugTracingMode:          service.DebugTracingModeOff,
+	})
 	if err != nil {
 		return nil, nil, err
 	}
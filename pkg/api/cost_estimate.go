@@ -0,0 +1,55 @@
+package api
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// getCostEstimate returns the estimated monthly cost of a new instance of the
+// given service and plan, provisioned in the given location using the given
+// provisioning parameters. If the service manager maps the instance to SKUs,
+// each SKU's cost is looked up in the pricing table; otherwise, the cost of
+// the plan itself is. No estimate (nil) is returned if any cost is missing
+// from the pricing table, since a partial estimate would understate the cost.
+func getCostEstimate(
+	pricingTable *service.PricingTable,
+	svc service.Service,
+	plan service.Plan,
+	location string,
+	provisioningParameters service.ProvisioningParameters,
+) (*service.CostEstimate, error) {
+	skuUsages := []service.SKUUsage{
+		{
+			Quantity: 1,
+		},
+	}
+	if skuMapper, ok := svc.GetServiceManager().(service.SKUMapper); ok {
+		var err error
+		skuUsages, err = skuMapper.GetSKUUsages(plan, provisioningParameters)
+		if err != nil {
+			return nil, err
+		}
+	}
+	estimate := &service.CostEstimate{
+		Currency: pricingTable.GetCurrency(),
+		Items:    []service.CostEstimateItem{},
+	}
+	for _, skuUsage := range skuUsages {
+		unitCost, ok := pricingTable.GetMonthlyCost(
+			svc.GetName(),
+			plan.GetName(),
+			skuUsage.SKU,
+			location,
+		)
+		if !ok {
+			return nil, nil
+		}
+		item := service.CostEstimateItem{
+			SKU:         skuUsage.SKU,
+			Quantity:    skuUsage.Quantity,
+			MonthlyCost: unitCost * skuUsage.Quantity,
+		}
+		estimate.Items = append(estimate.Items, item)
+		estimate.MonthlyCost += item.MonthlyCost
+	}
+	return estimate, nil
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+const (
+	// credentialTTLParameter is the binding parameter by which a binding
+	// overrides the default TTL of its credentials
+	credentialTTLParameter = "credentialTtl"
+	// rotateCredentialsParameter is the binding parameter by which a binding,
+	// e.g. one consumed by an application that can't pick up a new credential
+	// without being restaged, opts out of rotation
+	rotateCredentialsParameter = "rotateCredentials"
+)
+
+// CredentialRotationPolicy describes how long the credentials of new bindings
+// to services that can rotate them remain in use before the broker replaces
+// them. The zero value rotates no credentials.
+type CredentialRotationPolicy struct {
+	defaultTTL time.Duration
+	minTTL     time.Duration
+}
+
+// NewCredentialRotationPolicy returns a CredentialRotationPolicy that rotates
+// credentials once the given default TTL elapses, unless a binding requests a
+// TTL of its own, which may be no shorter than the given minimum. A default
+// TTL of zero disables rotation.
+func NewCredentialRotationPolicy(
+	defaultTTL time.Duration,
+	minTTL time.Duration,
+) (CredentialRotationPolicy, error) {
+	if defaultTTL < 0 || minTTL < 0 {
+		return CredentialRotationPolicy{}, errors.New(
+			"credential TTLs may not be negative",
+		)
+	}
+	if defaultTTL == 0 {
+		return CredentialRotationPolicy{}, nil
+	}
+	if defaultTTL < minTTL {
+		return CredentialRotationPolicy{}, fmt.Errorf(
+			"default credential TTL %s is shorter than the minimum %s",
+			defaultTTL,
+			minTTL,
+		)
+	}
+	return CredentialRotationPolicy{
+		defaultTTL: defaultTTL,
+		minTTL:     minTTL,
+	}, nil
+}
+
+// isEnabled returns a bool indicating whether any credentials are rotated
+func (c CredentialRotationPolicy) isEnabled() bool {
+	return c.defaultTTL > 0
+}
+
+// getCredentialRotation removes the broker's own credential rotation
+// parameters from the given binding parameters and returns the rotation
+// schedule of a new binding to the given service, or nil if the binding's
+// credentials won't be rotated
+func (c CredentialRotationPolicy) getCredentialRotation(
+	svc service.Service,
+	params map[string]interface{},
+	now time.Time,
+) (*service.CredentialRotation, *service.ValidationError) {
+	ttlParam, hasTTL := params[credentialTTLParameter]
+	rotateParam, hasRotate := params[rotateCredentialsParameter]
+	delete(params, credentialTTLParameter)
+	delete(params, rotateCredentialsParameter)
+	rotate := true
+	if hasRotate {
+		var ok bool
+		if rotate, ok = rotateParam.(bool); !ok {
+			return nil, service.NewValidationError(
+				rotateCredentialsParameter,
+				"must be a boolean",
+			)
+		}
+	}
+	_, canRotate := svc.GetServiceManager().(service.CredentialRotator)
+	if !hasTTL {
+		if !rotate || !canRotate || !c.isEnabled() {
+			return nil, nil
+		}
+		return &service.CredentialRotation{
+			TTL:          c.defaultTTL,
+			NextRotation: now.Add(c.defaultTTL),
+		}, nil
+	}
+	if !rotate {
+		return nil, service.NewValidationError(
+			credentialTTLParameter,
+			fmt.Sprintf(
+				"may not be specified when %s is false",
+				rotateCredentialsParameter,
+			),
+		)
+	}
+	if !canRotate || !c.isEnabled() {
+		return nil, service.NewValidationError(
+			credentialTTLParameter,
+			"credentials of bindings to this service aren't rotated",
+		)
+	}
+	ttlStr, ok := ttlParam.(string)
+	if !ok {
+		return nil, service.NewValidationError(
+			credentialTTLParameter,
+			`must be a duration, e.g. "24h"`,
+		)
+	}
+	ttl, err := time.ParseDuration(ttlStr)
+	if err != nil {
+		return nil, service.NewValidationError(
+			credentialTTLParameter,
+			fmt.Sprintf(`invalid duration "%s"`, ttlStr),
+		)
+	}
+	if ttl <= 0 {
+		return nil, service.NewValidationError(
+			credentialTTLParameter,
+			"must be positive",
+		)
+	}
+	if ttl < c.minTTL {
+		return nil, service.NewValidationError(
+			credentialTTLParameter,
+			fmt.Sprintf("may be no shorter than %s", c.minTTL),
+		)
+	}
+	return &service.CredentialRotation{
+		TTL:          ttl,
+		NextRotation: now.Add(ttl),
+	}, nil
+}
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCredentialRotationPolicy(t *testing.T) {
+	policy, err := NewCredentialRotationPolicy(0, time.Hour)
+	assert.Nil(t, err)
+	assert.False(t, policy.isEnabled())
+	policy, err = NewCredentialRotationPolicy(24*time.Hour, time.Hour)
+	assert.Nil(t, err)
+	assert.True(t, policy.isEnabled())
+	_, err = NewCredentialRotationPolicy(time.Minute, time.Hour)
+	assert.NotNil(t, err)
+	_, err = NewCredentialRotationPolicy(-time.Hour, 0)
+	assert.NotNil(t, err)
+}
+
+func TestGetCredentialRotation(t *testing.T) {
+	svc := getCredentialRotationTestService(t)
+	policy, err := NewCredentialRotationPolicy(24*time.Hour, time.Hour)
+	assert.Nil(t, err)
+	now := time.Now()
+	for name, tc := range map[string]struct {
+		params map[string]interface{}
+		ttl    time.Duration
+		valid  bool
+	}{
+		"default": {
+			params: map[string]interface{}{},
+			ttl:    24 * time.Hour,
+			valid:  true,
+		},
+		"own ttl": {
+			params: map[string]interface{}{"credentialTtl": "2h"},
+			ttl:    2 * time.Hour,
+			valid:  true,
+		},
+		"opted out": {
+			params: map[string]interface{}{"rotateCredentials": false},
+			valid:  true,
+		},
+		"ttl too short": {
+			params: map[string]interface{}{"credentialTtl": "30m"},
+		},
+		"ttl not a duration": {
+			params: map[string]interface{}{"credentialTtl": "daily"},
+		},
+		"ttl while opted out": {
+			params: map[string]interface{}{
+				"credentialTtl":     "2h",
+				"rotateCredentials": false,
+			},
+		},
+		"opt out not a boolean": {
+			params: map[string]interface{}{"rotateCredentials": "no"},
+		},
+	} {
+		tc.params["someParameter"] = "foo"
+		rotation, validationErr :=
+			policy.getCredentialRotation(svc, tc.params, now)
+		if !tc.valid {
+			assert.NotNil(t, validationErr, name)
+			continue
+		}
+		assert.Nil(t, validationErr, name)
+		if tc.ttl == 0 {
+			assert.Nil(t, rotation, name)
+		} else {
+			assert.Equal(t, tc.ttl, rotation.TTL, name)
+			assert.Equal(t, now.Add(tc.ttl), rotation.NextRotation, name)
+		}
+		// Only the broker's own parameters are set aside
+		assert.Equal(
+			t,
+			map[string]interface{}{"someParameter": "foo"},
+			tc.params,
+			name,
+		)
+	}
+}
+
+func TestGetCredentialRotationWhenDisabled(t *testing.T) {
+	svc := getCredentialRotationTestService(t)
+	rotation, validationErr := CredentialRotationPolicy{}.getCredentialRotation(
+		svc,
+		map[string]interface{}{},
+		time.Now(),
+	)
+	assert.Nil(t, validationErr)
+	assert.Nil(t, rotation)
+	_, validationErr = CredentialRotationPolicy{}.getCredentialRotation(
+		svc,
+		map[string]interface{}{"credentialTtl": "2h"},
+		time.Now(),
+	)
+	assert.NotNil(t, validationErr)
+}
+
+func TestBindingRecordsCredentialRotation(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	s.credentialRotation, err =
+		NewCredentialRotationPolicy(24*time.Hour, time.Hour)
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	err = s.store.WriteInstance(service.Instance{
+		InstanceID: instanceID,
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateProvisioned,
+	})
+	assert.Nil(t, err)
+	bindingID := getDisposableBindingID()
+	req, err := getBindingRequest(
+		instanceID,
+		bindingID,
+		&BindingRequest{
+			Parameters: map[string]interface{}{
+				"credentialTtl": "2h",
+			},
+		},
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	binding, ok, err := s.store.GetBinding(bindingID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.NotNil(t, binding.CredentialRotation)
+	assert.Equal(t, 2*time.Hour, binding.CredentialRotation.TTL)
+}
+
+func getCredentialRotationTestService(t *testing.T) service.Service {
+	fakeModule, err := fake.New()
+	assert.Nil(t, err)
+	catalog, err := fakeModule.GetCatalog()
+	assert.Nil(t, err)
+	svc, ok := catalog.GetService(fake.ServiceID)
+	assert.True(t, ok)
+	return svc
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	log "github.com/Sirupsen/logrus"
+	"github.com/gorilla/mux"
+)
+
+// StepTracesResponse represents the response to a request to fetch the traces
+// of an instance's provisioning and updating steps, oldest first
+type StepTracesResponse struct {
+	InstanceID string              `json:"instance_id"`
+	Steps      []service.StepTrace `json:"steps"`
+}
+
+// GetStepTracesResponseFromJSON returns a new StepTracesResponse unmarshalled
+// from the provided JSON []byte
+func GetStepTracesResponseFromJSON(
+	jsonBytes []byte,
+	stepTracesResponse *StepTracesResponse,
+) error {
+	return json.Unmarshal(jsonBytes, stepTracesResponse)
+}
+
+// ToJSON returns a []byte containing a JSON representation of the step traces
+// response
+func (s *StepTracesResponse) ToJSON() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+// getDebug returns a bool indicating whether the "debug" parameter in the
+// given provisioning parameter map asks for the new instance's steps to be
+// traced. Asking for that is only permitted if the broker traces the steps
+// of those instances that ask.
+func (s *server) getDebug(parameters map[string]interface{}) (bool, error) {
+	debugIface, ok := parameters["debug"]
+	if !ok {
+		return false, nil
+	}
+	debug, ok := debugIface.(bool)
+	if !ok {
+		return false, service.NewValidationError(
+			"debug",
+			fmt.Sprintf(`"%v" is not a boolean`, debugIface),
+		)
+	}
+	if debug && s.debugTracingMode == service.DebugTracingModeOff {
+		return false, service.NewValidationError(
+			"debug",
+			"the broker does not trace instances' steps",
+		)
+	}
+	return debug, nil
+}
+
+// getStepTraces responds with the traces of an instance's steps. Traces
+// outlive the instance for as long as they're kept, so an instance that no
+// longer exists is only reported as not found if none remain.
+func (s *server) getStepTraces(w http.ResponseWriter, r *http.Request) {
+	instanceID := mux.Vars(r)["instance_id"]
+
+	logFields := log.Fields{
+		"instanceID": instanceID,
+	}
+
+	log.WithFields(logFields).Debug(
+		"received request to fetch instance step traces",
+	)
+
+	traces, err := s.store.GetStepTraces(instanceID)
+	if err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error(
+			"step traces error: error retrieving step traces",
+		)
+		s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
+		return
+	}
+	if len(traces) == 0 {
+		_, ok, err := s.store.GetInstance(instanceID)
+		if err != nil {
+			logFields["error"] = err
+			log.WithFields(logFields).Error(
+				"step traces error: error retrieving instance by id",
+			)
+			s.writeResponse(
+				w,
+				http.StatusInternalServerError,
+				generateEmptyResponse(),
+			)
+			return
+		}
+		if !ok {
+			log.WithFields(logFields).Debug(
+				"step traces request for an instance that does not exist",
+			)
+			s.writeResponse(w, http.StatusNotFound, generateEmptyResponse())
+			return
+		}
+	}
+	stepTracesResponse := &StepTracesResponse{
+		InstanceID: instanceID,
+		Steps:      traces,
+	}
+	stepTracesJSON, err := stepTracesResponse.ToJSON()
+	if err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error(
+			"step traces error: error marshaling step traces response",
+		)
+		s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
+		return
+	}
+	s.writeResponse(w, http.StatusOK, stepTracesJSON)
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProvisioningDebugInstance(t *testing.T) {
+	s, _, err := getTestServer("eastus", "")
+	assert.Nil(t, err)
+	s.debugTracingMode = service.DebugTracingModeRequested
+	instanceID := getDisposableInstanceID()
+	req, err := getProvisionRequest(
+		instanceID,
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&ProvisioningRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+			Parameters: map[string]interface{}{
+				"debug": true,
+			},
+		},
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	instance, ok, err := s.store.GetInstance(instanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.True(t, instance.Debug)
+}
+
+func TestProvisioningDebugInstanceWithTracingOffFails(t *testing.T) {
+	s, _, err := getTestServer("eastus", "")
+	assert.Nil(t, err)
+	req, err := getProvisionRequest(
+		getDisposableInstanceID(),
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&ProvisioningRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+			Parameters: map[string]interface{}{
+				"debug": true,
+			},
+		},
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestGettingStepTracesOfInstanceThatDoesNotExist(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	req, err := getStepTracesRequest(getDisposableInstanceID())
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestGettingStepTracesOfUntracedInstance(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	err = s.store.WriteInstance(service.Instance{
+		InstanceID: instanceID,
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateProvisioned,
+	})
+	assert.Nil(t, err)
+	req, err := getStepTracesRequest(instanceID)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	stepTracesResponse := &StepTracesResponse{}
+	err = GetStepTracesResponseFromJSON(rr.Body.Bytes(), stepTracesResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, instanceID, stepTracesResponse.InstanceID)
+	assert.Empty(t, stepTracesResponse.Steps)
+}
+
+func TestGettingStepTracesOfDeletedInstance(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	for _, stepName := range []string{"run", "verify"} {
+		err = s.store.AppendStepTrace(
+			instanceID,
+			service.StepTrace{
+				Operation: "provisioning",
+				StepName:  stepName,
+			},
+			10,
+			time.Hour,
+		)
+		assert.Nil(t, err)
+	}
+	req, err := getStepTracesRequest(instanceID)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	stepTracesResponse := &StepTracesResponse{}
+	err = GetStepTracesResponseFromJSON(rr.Body.Bytes(), stepTracesResponse)
+	assert.Nil(t, err)
+	assert.Len(t, stepTracesResponse.Steps, 2)
+	assert.Equal(t, "run", stepTracesResponse.Steps[0].StepName)
+	assert.Equal(t, "verify", stepTracesResponse.Steps[1].StepName)
+}
+
+func getStepTracesRequest(instanceID string) (*http.Request, error) {
+	return http.NewRequest(
+		http.MethodGet,
+		fmt.Sprintf("/admin/instances/%s/trace", instanceID),
+		nil,
+	)
+}
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// FingerprintField identifies a field of a provisioning request that
+// contributes to the request's fingerprint
+type FingerprintField string
+
+const (
+	// FingerprintFieldService is the id of the requested service
+	FingerprintFieldService FingerprintField = "service"
+	// FingerprintFieldPlan is the id of the requested plan
+	FingerprintFieldPlan FingerprintField = "plan"
+	// FingerprintFieldParameters are the requested parameters, exactly as they
+	// were specified
+	FingerprintFieldParameters FingerprintField = "parameters"
+	// FingerprintFieldOrganization is the GUID of the organization requesting
+	// the instance
+	FingerprintFieldOrganization FingerprintField = "organization"
+	// FingerprintFieldSpace is the GUID of the space requesting the instance
+	FingerprintFieldSpace FingerprintField = "space"
+)
+
+// DefaultFingerprintFields are the fields a provisioning request's
+// fingerprint is derived from unless others are specified
+var DefaultFingerprintFields = []FingerprintField{
+	FingerprintFieldService,
+	FingerprintFieldPlan,
+	FingerprintFieldParameters,
+}
+
+// ProvisioningDeduplication describes how provisioning requests for the same
+// instance that arrive in quick succession, e.g. because a platform submitted
+// one twice, are collapsed into a single provisioning operation. The zero
+// value collapses none.
+type ProvisioningDeduplication struct {
+	window time.Duration
+	fields []FingerprintField
+}
+
+// NewProvisioningDeduplication returns a ProvisioningDeduplication that,
+// within the given window, collapses requests for the same instance whose
+// fingerprints, derived from the given fields, are equal. A window of zero
+// disables deduplication.
+func NewProvisioningDeduplication(
+	window time.Duration,
+	fields []FingerprintField,
+) (ProvisioningDeduplication, error) {
+	if window < 0 {
+		return ProvisioningDeduplication{}, errors.New(
+			"deduplication window may not be negative",
+		)
+	}
+	if window == 0 {
+		return ProvisioningDeduplication{}, nil
+	}
+	if len(fields) == 0 {
+		return ProvisioningDeduplication{}, errors.New(
+			"at least one fingerprint field must be specified",
+		)
+	}
+	used := map[FingerprintField]bool{}
+	for _, field := range fields {
+		switch field {
+		case FingerprintFieldService,
+			FingerprintFieldPlan,
+			FingerprintFieldParameters,
+			FingerprintFieldOrganization,
+			FingerprintFieldSpace:
+		default:
+			return ProvisioningDeduplication{}, fmt.Errorf(
+				`unrecognized fingerprint field "%s"`,
+				field,
+			)
+		}
+		if used[field] {
+			return ProvisioningDeduplication{}, fmt.Errorf(
+				`duplicate fingerprint field "%s"`,
+				field,
+			)
+		}
+		used[field] = true
+	}
+	return ProvisioningDeduplication{
+		window: window,
+		fields: fields,
+	}, nil
+}
+
+// isEnabled returns a bool indicating whether any provisioning requests are
+// to be collapsed
+func (p ProvisioningDeduplication) isEnabled() bool {
+	return p.window > 0
+}
+
+// getFingerprint returns a digest of those fields of the given provisioning
+// request that deduplication is configured to take into account
+func (p ProvisioningDeduplication) getFingerprint(
+	pr *ProvisioningRequest,
+) (string, error) {
+	values := map[FingerprintField]interface{}{}
+	for _, field := range p.fields {
+		switch field {
+		case FingerprintFieldService:
+			values[field] = pr.ServiceID
+		case FingerprintFieldPlan:
+			values[field] = pr.PlanID
+		case FingerprintFieldParameters:
+			values[field] = pr.Parameters
+		case FingerprintFieldOrganization:
+			values[field] = pr.GetOrganizationGUID()
+		case FingerprintFieldSpace:
+			values[field] = pr.GetSpaceGUID()
+		}
+	}
+	// Maps are marshaled with their keys sorted, so equal requests always
+	// produce equal JSON
+	valuesJSON, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf(
+			"error marshaling provisioning request fingerprint: %s",
+			err,
+		)
+	}
+	digest := sha256.Sum256(valuesJSON)
+	return hex.EncodeToString(digest[:]), nil
+}
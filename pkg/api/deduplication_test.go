@@ -0,0 +1,83 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProvisioningDeduplicationWithZeroWindow(t *testing.T) {
+	d, err := NewProvisioningDeduplication(0, DefaultFingerprintFields)
+	assert.Nil(t, err)
+	assert.False(t, d.isEnabled())
+}
+
+func TestNewProvisioningDeduplicationWithNegativeWindow(t *testing.T) {
+	_, err := NewProvisioningDeduplication(-time.Second, DefaultFingerprintFields)
+	assert.NotNil(t, err)
+}
+
+func TestNewProvisioningDeduplicationWithInvalidFields(t *testing.T) {
+	_, err := NewProvisioningDeduplication(time.Minute, []FingerprintField{})
+	assert.NotNil(t, err)
+	_, err = NewProvisioningDeduplication(
+		time.Minute,
+		[]FingerprintField{"bogus"},
+	)
+	assert.NotNil(t, err)
+	_, err = NewProvisioningDeduplication(
+		time.Minute,
+		[]FingerprintField{FingerprintFieldPlan, FingerprintFieldPlan},
+	)
+	assert.NotNil(t, err)
+}
+
+func TestGetFingerprint(t *testing.T) {
+	d, err := NewProvisioningDeduplication(
+		time.Minute,
+		[]FingerprintField{FingerprintFieldPlan, FingerprintFieldParameters},
+	)
+	assert.Nil(t, err)
+	fingerprint, err := d.getFingerprint(
+		&ProvisioningRequest{
+			ServiceID: "foo",
+			PlanID:    "bar",
+			Parameters: map[string]interface{}{
+				"location": "eastus",
+				"tags": map[string]interface{}{
+					"a": "1",
+					"b": "2",
+				},
+			},
+		},
+	)
+	assert.Nil(t, err)
+	// Fields that aren't part of the fingerprint don't affect it
+	sameFingerprint, err := d.getFingerprint(
+		&ProvisioningRequest{
+			ServiceID: "baz",
+			PlanID:    "bar",
+			Parameters: map[string]interface{}{
+				"tags": map[string]interface{}{
+					"b": "2",
+					"a": "1",
+				},
+				"location": "eastus",
+			},
+		},
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, fingerprint, sameFingerprint)
+	differentFingerprint, err := d.getFingerprint(
+		&ProvisioningRequest{
+			ServiceID: "foo",
+			PlanID:    "bar",
+			Parameters: map[string]interface{}{
+				"location": "westus",
+			},
+		},
+	)
+	assert.Nil(t, err)
+	assert.NotEqual(t, fingerprint, differentFingerprint)
+}
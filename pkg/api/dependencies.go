@@ -0,0 +1,102 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	log "github.com/Sirupsen/logrus"
+)
+
+// getDependsOn returns the ids of the instances, if any, that the "dependsOn"
+// parameter in the given provisioning parameter map asks for a new instance
+// to depend upon. The ids are sorted and duplicates are dropped, so that
+// requests naming the same instances in a different order are equivalent.
+func getDependsOn(parameters map[string]interface{}) ([]string, error) {
+	dependsOnIface, ok := parameters["dependsOn"]
+	if !ok {
+		return nil, nil
+	}
+	dependsOnIfaces, ok := dependsOnIface.([]interface{})
+	if !ok {
+		return nil, service.NewValidationError(
+			"dependsOn",
+			fmt.Sprintf(`"%v" is not an array`, dependsOnIface),
+		)
+	}
+	seen := map[string]bool{}
+	dependsOn := []string{}
+	for _, instanceIDIface := range dependsOnIfaces {
+		instanceID, ok := instanceIDIface.(string)
+		if !ok || instanceID == "" {
+			return nil, service.NewValidationError(
+				"dependsOn",
+				fmt.Sprintf(`"%v" is not an instance id`, instanceIDIface),
+			)
+		}
+		if !seen[instanceID] {
+			seen[instanceID] = true
+			dependsOn = append(dependsOn, instanceID)
+		}
+	}
+	if len(dependsOn) == 0 {
+		return nil, nil
+	}
+	sort.Strings(dependsOn)
+	return dependsOn, nil
+}
+
+// validateDependencies verifies that each of the instances that the instance
+// having the given instance id is to depend upon exists and is fully
+// provisioned, since their details are what the new instance's provisioning
+// steps are given about them
+func (s *server) validateDependencies(
+	instanceID string,
+	dependsOn []string,
+) error {
+	for _, dependencyID := range dependsOn {
+		if dependencyID == instanceID {
+			return service.NewValidationError(
+				"dependsOn",
+				"an instance cannot depend upon itself",
+			)
+		}
+		dependency, ok, err := s.store.GetInstance(dependencyID)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"instanceID":   instanceID,
+				"dependencyID": dependencyID,
+				"error":        err,
+			}).Error("pre-provisioning error: error retrieving dependency instance")
+			return err
+		}
+		if !ok {
+			return service.NewValidationError(
+				"dependsOn",
+				fmt.Sprintf(`instance "%s" does not exist`, dependencyID),
+			)
+		}
+		if dependency.Status != service.InstanceStateProvisioned {
+			return service.NewValidationError(
+				"dependsOn",
+				fmt.Sprintf(`instance "%s" is not fully provisioned`, dependencyID),
+			)
+		}
+	}
+	return nil
+}
+
+// areDependenciesEqual returns a bool indicating whether two sorted lists of
+// instance ids are the same. No list and an empty list are considered the
+// same.
+func areDependenciesEqual(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDependsOn(t *testing.T) {
+	dependsOn, err := getDependsOn(map[string]interface{}{})
+	assert.Nil(t, err)
+	assert.Nil(t, dependsOn)
+	dependsOn, err = getDependsOn(map[string]interface{}{
+		"dependsOn": []interface{}{"b", "a", "b"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b"}, dependsOn)
+	_, err = getDependsOn(map[string]interface{}{
+		"dependsOn": "a",
+	})
+	assert.NotNil(t, err)
+	_, err = getDependsOn(map[string]interface{}{
+		"dependsOn": []interface{}{"a", ""},
+	})
+	assert.NotNil(t, err)
+}
+
+func TestProvisioningWithDependency(t *testing.T) {
+	s, _, err := getTestServer("eastus", "")
+	assert.Nil(t, err)
+	dependencyID := getDisposableInstanceID()
+	err = s.store.WriteInstance(service.Instance{
+		InstanceID: dependencyID,
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateProvisioned,
+	})
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	req, err := getProvisionRequest(
+		instanceID,
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&ProvisioningRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+			Parameters: map[string]interface{}{
+				"dependsOn": []interface{}{dependencyID},
+			},
+		},
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	instance, ok, err := s.store.GetInstance(instanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []string{dependencyID}, instance.DependsOn)
+	dependentIDs, err := s.store.GetInstanceDependentIDs(dependencyID)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{instanceID}, dependentIDs)
+}
+
+func TestProvisioningWithUnprovisionedDependencyFails(t *testing.T) {
+	s, _, err := getTestServer("eastus", "")
+	assert.Nil(t, err)
+	dependencyID := getDisposableInstanceID()
+	err = s.store.WriteInstance(service.Instance{
+		InstanceID: dependencyID,
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateProvisioning,
+	})
+	assert.Nil(t, err)
+	for _, dependsOn := range []string{dependencyID, getDisposableInstanceID()} {
+		req, err := getProvisionRequest(
+			getDisposableInstanceID(),
+			map[string]string{
+				"accepts_incomplete": "true",
+			},
+			&ProvisioningRequest{
+				ServiceID: fake.ServiceID,
+				PlanID:    fake.StandardPlanID,
+				Parameters: map[string]interface{}{
+					"dependsOn": []interface{}{dependsOn},
+				},
+			},
+		)
+		assert.Nil(t, err)
+		rr := httptest.NewRecorder()
+		s.router.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	}
+}
+
+func TestDeprovisioningInstanceWithDependentsConflicts(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	dependencyID := getDisposableInstanceID()
+	err = s.store.WriteInstance(service.Instance{
+		InstanceID: dependencyID,
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateProvisioned,
+	})
+	assert.Nil(t, err)
+	dependentID := getDisposableInstanceID()
+	err = s.store.WriteInstance(service.Instance{
+		InstanceID: dependentID,
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateProvisioned,
+		DependsOn:  []string{dependencyID},
+	})
+	assert.Nil(t, err)
+	req, err := getDeprovisionRequest(
+		dependencyID,
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	response := errorResponse{}
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, "DependentsExist", response.Error)
+	assert.Contains(t, response.Description, dependentID)
+	instance, _, err := s.store.GetInstance(dependencyID)
+	assert.Nil(t, err)
+	assert.Equal(t, service.InstanceStateProvisioned, instance.Status)
+
+	// Once the dependent instance is gone, so is the conflict
+	_, err = s.store.DeleteInstance(dependentID)
+	assert.Nil(t, err)
+	rr = httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+}
@@ -77,6 +77,7 @@ func (s *server) deprovision(w http.ResponseWriter, r *http.Request) {
 		return
 	case service.InstanceStateProvisioned:
 	case service.InstanceStateProvisioningFailed:
+	case service.InstanceStateQuarantined:
 	default:
 		// This is going to handle the case where we cannot deprovision because
 		// the instance isn't in a terminal state-- i.e. it's still provisioning
@@ -88,6 +89,30 @@ func (s *server) deprovision(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Instances that other instances were provisioned to depend upon are kept
+	// until those instances have been deprovisioned
+	dependentIDs, err := s.store.GetInstanceDependentIDs(instanceID)
+	if err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error(
+			"pre-deprovisioning error: error retrieving dependent instances",
+		)
+		s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
+		return
+	}
+	if len(dependentIDs) > 0 {
+		logFields["dependentIDs"] = dependentIDs
+		log.WithFields(logFields).Debug(
+			"cannot deprovision instance that other instances depend upon",
+		)
+		s.writeResponse(
+			w,
+			http.StatusConflict,
+			generateDependentsExistResponse(dependentIDs),
+		)
+		return
+	}
+
 	// If we get to here, we're dealing with an instance that is fully provisioned
 	// or has failed provisioning. We need to kick off asynchronous
 	// deprovisioning.
@@ -161,6 +186,7 @@ func (s *server) deprovision(w http.ResponseWriter, r *http.Request) {
 			"no provisioned children, starting deprovision",
 		)
 	}
+	task.SetTenant(instance.OrganizationGUID)
 	if err = s.asyncEngine.SubmitTask(task); err != nil {
 		logFields["step"] = firstStepName
 		logFields["error"] = err
@@ -0,0 +1,153 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	log "github.com/Sirupsen/logrus"
+	"github.com/gorilla/mux"
+)
+
+// DriftResponse represents the response to a request to fetch whether an
+// instance's underlying resources have drifted from their desired state. Drift
+// is omitted if the instance has never been checked for drift.
+type DriftResponse struct {
+	InstanceID string              `json:"instance_id"`
+	ServiceID  string              `json:"service_id"`
+	Status     string              `json:"status"`
+	Drift      *service.DriftState `json:"drift,omitempty"`
+}
+
+// GetDriftResponseFromJSON returns a new DriftResponse unmarshalled from the
+// provided JSON []byte
+func GetDriftResponseFromJSON(
+	jsonBytes []byte,
+	driftResponse *DriftResponse,
+) error {
+	return json.Unmarshal(jsonBytes, driftResponse)
+}
+
+// ToJSON returns a []byte containing a JSON representation of the drift
+// response
+func (d *DriftResponse) ToJSON() ([]byte, error) {
+	return json.Marshal(d)
+}
+
+// DriftReportResponse represents the response to a request to fetch all the
+// instances that the most recent checks found to have drifted, in order of
+// instance ID
+type DriftReportResponse struct {
+	Instances []DriftResponse `json:"instances"`
+}
+
+// GetDriftReportResponseFromJSON returns a new DriftReportResponse
+// unmarshalled from the provided JSON []byte
+func GetDriftReportResponseFromJSON(
+	jsonBytes []byte,
+	driftReportResponse *DriftReportResponse,
+) error {
+	return json.Unmarshal(jsonBytes, driftReportResponse)
+}
+
+// ToJSON returns a []byte containing a JSON representation of the drift report
+// response
+func (d *DriftReportResponse) ToJSON() ([]byte, error) {
+	return json.Marshal(d)
+}
+
+func newDriftResponse(instance service.Instance) DriftResponse {
+	return DriftResponse{
+		InstanceID: instance.InstanceID,
+		ServiceID:  instance.ServiceID,
+		Status:     instance.Status,
+		Drift:      instance.Drift,
+	}
+}
+
+func (s *server) getDriftReport(w http.ResponseWriter, _ *http.Request) {
+	log.Debug("received request to fetch drift report")
+
+	instanceIDs, err := s.store.GetInstanceIDs()
+	if err != nil {
+		log.WithField("error", err).Error(
+			"drift report error: error listing instances",
+		)
+		s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
+		return
+	}
+	sort.Strings(instanceIDs)
+	driftReportResponse := &DriftReportResponse{
+		Instances: []DriftResponse{},
+	}
+	for _, instanceID := range instanceIDs {
+		instance, ok, err := s.store.GetInstance(instanceID)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"instanceID": instanceID,
+				"error":      err,
+			}).Error("drift report error: error retrieving instance by id")
+			s.writeResponse(
+				w,
+				http.StatusInternalServerError,
+				generateEmptyResponse(),
+			)
+			return
+		}
+		// The instance may have been deprovisioned since it was listed
+		if ok && instance.HasDrifted() {
+			driftReportResponse.Instances = append(
+				driftReportResponse.Instances,
+				newDriftResponse(instance),
+			)
+		}
+	}
+	driftReportJSON, err := driftReportResponse.ToJSON()
+	if err != nil {
+		log.WithField("error", err).Error(
+			"drift report error: error marshaling response",
+		)
+		s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
+		return
+	}
+	s.writeResponse(w, http.StatusOK, driftReportJSON)
+}
+
+func (s *server) getDrift(w http.ResponseWriter, r *http.Request) {
+	instanceID := mux.Vars(r)["instance_id"]
+
+	logFields := log.Fields{
+		"instanceID": instanceID,
+	}
+
+	log.WithFields(logFields).Debug("received request to fetch instance drift")
+
+	instance, ok, err := s.store.GetInstance(instanceID)
+	if err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error(
+			"drift error: error retrieving instance by id",
+		)
+		s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
+		return
+	}
+	if !ok {
+		log.WithFields(logFields).Debug(
+			"drift request for an instance that does not exist",
+		)
+		s.writeResponse(w, http.StatusNotFound, generateEmptyResponse())
+		return
+	}
+	driftResponse := newDriftResponse(instance)
+	driftJSON, err := driftResponse.ToJSON()
+	if err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error(
+			"drift error: error marshaling drift response",
+		)
+		s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
+		return
+	}
+	s.writeResponse(w, http.StatusOK, driftJSON)
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDriftReport(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	checkedAt := time.Now().UTC()
+	for instanceID, drift := range map[string]*service.DriftState{
+		"unchecked": nil,
+		"unchanged": {CheckedAt: checkedAt},
+		"drifted": {
+			CheckedAt:  checkedAt,
+			DetectedAt: &checkedAt,
+			Drifts: []service.Drift{
+				{
+					Resource: "server",
+					Property: "sslEnforcement",
+					Expected: "Enabled",
+					Actual:   "Disabled",
+				},
+			},
+		},
+	} {
+		err = s.store.WriteInstance(service.Instance{
+			InstanceID: instanceID,
+			ServiceID:  fake.ServiceID,
+			PlanID:     fake.StandardPlanID,
+			Status:     service.InstanceStateProvisioned,
+			Drift:      drift,
+		})
+		assert.Nil(t, err)
+	}
+	req, err := http.NewRequest(http.MethodGet, "/admin/drift", nil)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	driftReportResponse := &DriftReportResponse{}
+	err = GetDriftReportResponseFromJSON(rr.Body.Bytes(), driftReportResponse)
+	assert.Nil(t, err)
+	assert.Len(t, driftReportResponse.Instances, 1)
+	assert.Equal(t, "drifted", driftReportResponse.Instances[0].InstanceID)
+	assert.Equal(
+		t,
+		"sslEnforcement",
+		driftReportResponse.Instances[0].Drift.Drifts[0].Property,
+	)
+}
+
+func TestGetDriftForNonexistentInstance(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	req, err := http.NewRequest(
+		http.MethodGet,
+		"/admin/instances/nonexistent/drift",
+		nil,
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestGetDriftForUncheckedInstance(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	err = s.store.WriteInstance(service.Instance{
+		InstanceID: "instance",
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateProvisioned,
+	})
+	assert.Nil(t, err)
+	req, err := http.NewRequest(
+		http.MethodGet,
+		"/admin/instances/instance/drift",
+		nil,
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	driftResponse := &DriftResponse{}
+	err = GetDriftResponseFromJSON(rr.Body.Bytes(), driftResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, "instance", driftResponse.InstanceID)
+	assert.Equal(t, service.InstanceStateProvisioned, driftResponse.Status)
+	assert.Nil(t, driftResponse.Drift)
+}
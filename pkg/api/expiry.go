@@ -0,0 +1,164 @@
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// getSandbox returns a bool indicating whether the "sandbox" parameter in the
+// given provisioning parameter map asks for an instance to be provisioned as a
+// sandbox instance, which is deprovisioned once its lifespan is over
+func getSandbox(parameters map[string]interface{}) (bool, error) {
+	sandboxIface, ok := parameters["sandbox"]
+	if !ok {
+		return false, nil
+	}
+	sandbox, ok := sandboxIface.(bool)
+	if !ok {
+		return false, service.NewValidationError(
+			"sandbox",
+			fmt.Sprintf(`"%v" is not a boolean`, sandboxIface),
+		)
+	}
+	return sandbox, nil
+}
+
+// getExpiresAt returns the time, if any, until which the "expiresAt"
+// parameter in the given updating parameter map asks for a sandbox instance's
+// expiry to be put off
+func getExpiresAt(parameters map[string]interface{}) (*time.Time, error) {
+	expiresAtIface, ok := parameters["expiresAt"]
+	if !ok {
+		return nil, nil
+	}
+	expiresAtStr, ok := expiresAtIface.(string)
+	if !ok {
+		return nil, service.NewValidationError(
+			"expiresAt",
+			fmt.Sprintf(`"%v" is not a string`, expiresAtIface),
+		)
+	}
+	expiresAt, err := time.Parse(time.RFC3339, expiresAtStr)
+	if err != nil {
+		return nil, service.NewValidationError(
+			"expiresAt",
+			fmt.Sprintf(`"%s" is not an RFC 3339 timestamp`, expiresAtStr),
+		)
+	}
+	expiresAt = expiresAt.UTC()
+	return &expiresAt, nil
+}
+
+// getExpiryState returns the expiry state of a new sandbox instance of the
+// given plan of the given service, provisioned at the given time
+func (s *server) getExpiryState(
+	svc service.Service,
+	plan service.Plan,
+	created time.Time,
+) (*service.ExpiryState, error) {
+	lifespan, ok := s.expiryPolicy.GetLifespan(svc, plan)
+	if !ok {
+		return nil, service.NewValidationError(
+			"sandbox",
+			fmt.Sprintf(
+				`plan "%s" of service "%s" does not permit sandbox instances`,
+				plan.GetName(),
+				svc.GetName(),
+			),
+		)
+	}
+	created = created.UTC()
+	return &service.ExpiryState{
+		ExpiresAt:    created.Add(lifespan),
+		MaxExpiresAt: created.Add(s.expiryPolicy.GetMaxLifespan()),
+	}, nil
+}
+
+// validateExpiresAt verifies that a sandbox instance's expiry can be put off
+// until the given time
+func validateExpiresAt(instance service.Instance, expiresAt time.Time) error {
+	if !instance.IsSandbox() {
+		return service.NewValidationError(
+			"expiresAt",
+			"the instance is not a sandbox instance",
+		)
+	}
+	if expiresAt.Before(instance.Expiry.ExpiresAt) {
+		return service.NewValidationError(
+			"expiresAt",
+			fmt.Sprintf(
+				`expiresAt "%s" is earlier than the instance's current expiry, "%s"`,
+				expiresAt.Format(time.RFC3339),
+				instance.Expiry.ExpiresAt.Format(time.RFC3339),
+			),
+		)
+	}
+	if expiresAt.After(instance.Expiry.MaxExpiresAt) {
+		return service.NewValidationError(
+			"expiresAt",
+			fmt.Sprintf(
+				`expiresAt "%s" is later than the instance's maximum lifespan `+
+					`permits, "%s"`,
+				expiresAt.Format(time.RFC3339),
+				instance.Expiry.MaxExpiresAt.Format(time.RFC3339),
+			),
+		)
+	}
+	return nil
+}
+
+// extendExpiry puts off a sandbox instance's expiry until the given time and
+// submits tasks to warn of and carry out its expiry at the new time. The
+// tasks previously submitted do nothing when they find the instance's expiry
+// has been put off.
+func (s *server) extendExpiry(
+	instance *service.Instance,
+	expiresAt time.Time,
+) error {
+	instance.Expiry.ExpiresAt = expiresAt
+	instance.Expiry.WarnedAt = nil
+	if err := s.store.WriteInstance(*instance); err != nil {
+		return fmt.Errorf("error persisting instance: %s", err)
+	}
+	return s.scheduleExpiry(*instance)
+}
+
+// scheduleExpiry submits tasks to warn subscribers of a sandbox instance's
+// impending expiry, if the broker is configured to, and to deprovision the
+// instance once it has expired. An instance whose lifespan is shorter than
+// the warning period is warned of its expiry right away.
+func (s *server) scheduleExpiry(instance service.Instance) error {
+	args := map[string]string{
+		"instanceID": instance.InstanceID,
+		"expiresAt":  instance.Expiry.ExpiresAt.Format(time.RFC3339Nano),
+	}
+	tasks := []async.Task{}
+	if warningPeriod, ok := s.expiryPolicy.GetWarningPeriod(); ok {
+		tasks = append(
+			tasks,
+			async.NewScheduledTask(
+				"warnOfInstanceExpiry",
+				args,
+				instance.Expiry.ExpiresAt.Add(-warningPeriod),
+			),
+		)
+	}
+	tasks = append(
+		tasks,
+		async.NewScheduledTask("expireInstance", args, instance.Expiry.ExpiresAt),
+	)
+	for _, task := range tasks {
+		task.SetTenant(instance.OrganizationGUID)
+		if err := s.asyncEngine.SubmitTask(task); err != nil {
+			return fmt.Errorf(
+				`error submitting task "%s": %s`,
+				task.GetJobName(),
+				err,
+			)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/Azure/open-service-broker-azure/pkg/redaction"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	log "github.com/Sirupsen/logrus"
+	"github.com/gorilla/mux"
+)
+
+func (s *server) getInstance(w http.ResponseWriter, r *http.Request) {
+	instanceID := mux.Vars(r)["instance_id"]
+
+	logFields := log.Fields{
+		"instanceID": instanceID,
+	}
+
+	log.WithFields(logFields).Debug("received request to fetch instance")
+
+	instance, ok, err := s.store.GetInstance(instanceID)
+	if err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error(
+			"instance fetching error: error retrieving instance by id",
+		)
+		s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
+		return
+	}
+	// The spec says to respond with a 404 for an instance that is still being
+	// provisioned, just as for one that doesn't exist
+	if !ok || instance.Status == service.InstanceStateAwaitingApproval ||
+		instance.Status == service.InstanceStateQueued ||
+		instance.Status == service.InstanceStateProvisioning ||
+		instance.Status == service.InstanceStateProvisioningDegraded {
+		log.WithFields(logFields).Debug(
+			"instance does not exist or is still being provisioned",
+		)
+		s.writeResponse(w, http.StatusNotFound, generateEmptyResponse())
+		return
+	}
+
+	instanceResponse := &InstanceResponse{
+		ServiceID:          instance.ServiceID,
+		PlanID:             instance.PlanID,
+		CostEstimate:       instance.CostEstimate,
+		ProvisioningTiming: instance.ProvisioningTiming,
+	}
+	if instance.IsSandbox() {
+		instanceResponse.ExpiresAt = &instance.Expiry.ExpiresAt
+	}
+	if instance.ProvisioningAudit != nil {
+		// Secret parameters were redacted when they were audited, but connection
+		// strings and the like may also be found in the values of others
+		instanceResponse.Parameters = redaction.RedactParameters(
+			instance.ProvisioningAudit.EffectiveParameters,
+			s.responseRedactionMode,
+		)
+		instanceResponse.RequestedParameters = redaction.RedactParameters(
+			instance.ProvisioningAudit.RequestedParameters,
+			s.responseRedactionMode,
+		)
+	}
+	instanceJSON, err := instanceResponse.ToJSON()
+	if err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error(
+			"instance fetching error: error marshaling instance response",
+		)
+		s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
+		return
+	}
+	s.writeResponse(w, http.StatusOK, instanceJSON)
+}
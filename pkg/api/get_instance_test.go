@@ -0,0 +1,184 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetInstanceThatDoesNotExist(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	req, err := getGetInstanceRequest(getDisposableInstanceID())
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Equal(t, responseEmptyJSON, rr.Body.Bytes())
+}
+
+func TestGetInstanceThatIsProvisioning(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	err = s.store.WriteInstance(service.Instance{
+		InstanceID: instanceID,
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateProvisioning,
+	})
+	assert.Nil(t, err)
+	req, err := getGetInstanceRequest(instanceID)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestGetInstanceWithoutParametersAudit(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	err = s.store.WriteInstance(service.Instance{
+		InstanceID: instanceID,
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateProvisioned,
+	})
+	assert.Nil(t, err)
+	req, err := getGetInstanceRequest(instanceID)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	instanceResponse := &InstanceResponse{}
+	err = GetInstanceResponseFromJSON(rr.Body.Bytes(), instanceResponse)
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		&InstanceResponse{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+		},
+		instanceResponse,
+	)
+}
+
+func TestGetSandboxInstanceReportsExpiry(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	err = s.store.WriteInstance(service.Instance{
+		InstanceID: instanceID,
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateProvisioned,
+		Expiry: &service.ExpiryState{
+			ExpiresAt:    expiresAt,
+			MaxExpiresAt: expiresAt.Add(24 * time.Hour),
+		},
+	})
+	assert.Nil(t, err)
+	req, err := getGetInstanceRequest(instanceID)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	instanceResponse := &InstanceResponse{}
+	err = GetInstanceResponseFromJSON(rr.Body.Bytes(), instanceResponse)
+	assert.Nil(t, err)
+	if assert.NotNil(t, instanceResponse.ExpiresAt) {
+		assert.True(t, expiresAt.Equal(*instanceResponse.ExpiresAt))
+	}
+}
+
+func TestGetInstanceWithParametersAudit(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	requestedParams := map[string]interface{}{
+		"someSecretParameter": service.RedactedValue,
+	}
+	effectiveParams := map[string]interface{}{
+		"location":            "eastus",
+		"someParameter":       "default",
+		"someSecretParameter": service.RedactedValue,
+	}
+	err = s.store.WriteInstance(service.Instance{
+		InstanceID: instanceID,
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateProvisioned,
+		ProvisioningAudit: &service.ProvisioningAudit{
+			RequestedParameters: requestedParams,
+			EffectiveParameters: effectiveParams,
+		},
+	})
+	assert.Nil(t, err)
+	req, err := getGetInstanceRequest(instanceID)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	instanceResponse := &InstanceResponse{}
+	err = GetInstanceResponseFromJSON(rr.Body.Bytes(), instanceResponse)
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		&InstanceResponse{
+			ServiceID:           fake.ServiceID,
+			PlanID:              fake.StandardPlanID,
+			Parameters:          effectiveParams,
+			RequestedParameters: requestedParams,
+		},
+		instanceResponse,
+	)
+}
+
+func TestGetInstanceWithCostEstimate(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	costEstimate := &service.CostEstimate{
+		MonthlyCost: 12.5,
+		Currency:    "USD",
+		Items: []service.CostEstimateItem{
+			{
+				Quantity:    1,
+				MonthlyCost: 12.5,
+			},
+		},
+	}
+	err = s.store.WriteInstance(service.Instance{
+		InstanceID:   instanceID,
+		ServiceID:    fake.ServiceID,
+		PlanID:       fake.StandardPlanID,
+		Status:       service.InstanceStateProvisioned,
+		CostEstimate: costEstimate,
+	})
+	assert.Nil(t, err)
+	req, err := getGetInstanceRequest(instanceID)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	instanceResponse := &InstanceResponse{}
+	err = GetInstanceResponseFromJSON(rr.Body.Bytes(), instanceResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, costEstimate, instanceResponse.CostEstimate)
+}
+
+func getGetInstanceRequest(instanceID string) (*http.Request, error) {
+	return http.NewRequest(
+		http.MethodGet,
+		fmt.Sprintf("/v2/service_instances/%s", instanceID),
+		nil,
+	)
+}
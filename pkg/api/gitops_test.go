@@ -0,0 +1,150 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	"github.com/Azure/open-service-broker-azure/pkg/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeManifestWriter struct {
+	manifests map[string]service.GitOpsManifest
+	writeErr  error
+}
+
+func (f *fakeManifestWriter) WriteManifest(
+	instance service.Instance,
+	bindingID string,
+	_ service.Credentials,
+) (*service.GitOpsManifest, error) {
+	if f.writeErr != nil {
+		return nil, f.writeErr
+	}
+	manifest := service.GitOpsManifest{
+		Path:     instance.InstanceID + "/" + bindingID + ".yaml",
+		Revision: "abc",
+	}
+	f.manifests[manifest.Path] = manifest
+	return &manifest, nil
+}
+
+func (f *fakeManifestWriter) DeleteManifest(
+	manifest service.GitOpsManifest,
+) error {
+	delete(f.manifests, manifest.Path)
+	return nil
+}
+
+// boundBindingRefusingStore is a storage.Store that refuses to persist bound
+// bindings, used for testing
+type boundBindingRefusingStore struct {
+	storage.Store
+}
+
+func (b *boundBindingRefusingStore) WriteBinding(
+	binding service.Binding,
+) error {
+	if binding.Status == service.BindingStateBound {
+		return errors.New("the store is unavailable")
+	}
+	return b.Store.WriteBinding(binding)
+}
+
+func writeTestProvisionedInstance(t *testing.T, s *server) string {
+	instanceID := getDisposableInstanceID()
+	err := s.store.WriteInstance(service.Instance{
+		InstanceID: instanceID,
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateProvisioned,
+	})
+	assert.Nil(t, err)
+	return instanceID
+}
+
+func TestBindingWritesGitOpsManifest(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	manifestWriter := &fakeManifestWriter{
+		manifests: map[string]service.GitOpsManifest{},
+	}
+	s.manifestWriter = manifestWriter
+	instanceID := writeTestProvisionedInstance(t, s)
+	bindingID := getDisposableBindingID()
+	req, err := getBindingRequest(instanceID, bindingID, &BindingRequest{})
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	binding, ok, err := s.store.GetBinding(bindingID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(
+		t,
+		&service.GitOpsManifest{
+			Path:     instanceID + "/" + bindingID + ".yaml",
+			Revision: "abc",
+		},
+		binding.GitOpsManifest,
+	)
+
+	// Unbinding removes the manifest
+	req, err = getUnbindingRequest(instanceID, bindingID)
+	assert.Nil(t, err)
+	rr = httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, manifestWriter.manifests)
+}
+
+func TestBindingFailsWhenGitOpsManifestCannotBeWritten(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	s.manifestWriter = &fakeManifestWriter{
+		writeErr: errors.New("the Git repository refused the broker's token"),
+	}
+	instanceID := writeTestProvisionedInstance(t, s)
+	bindingID := getDisposableBindingID()
+	req, err := getBindingRequest(instanceID, bindingID, &BindingRequest{})
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	binding, ok, err := s.store.GetBinding(bindingID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, service.BindingStateBindingFailed, binding.Status)
+	assert.Nil(t, binding.GitOpsManifest)
+}
+
+func TestBindingDeletesGitOpsManifestWhenBindingCannotBePersisted(
+	t *testing.T,
+) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	manifestWriter := &fakeManifestWriter{
+		manifests: map[string]service.GitOpsManifest{},
+	}
+	s.manifestWriter = manifestWriter
+	s.store = &boundBindingRefusingStore{
+		Store: s.store,
+	}
+	instanceID := writeTestProvisionedInstance(t, s)
+	bindingID := getDisposableBindingID()
+	req, err := getBindingRequest(instanceID, bindingID, &BindingRequest{})
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Empty(t, manifestWriter.manifests)
+	binding, ok, err := s.store.GetBinding(bindingID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, service.BindingStateBindingFailed, binding.Status)
+	assert.Nil(t, binding.GitOpsManifest)
+}
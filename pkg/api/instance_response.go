@@ -0,0 +1,42 @@
+package api
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// InstanceResponse represents the response to a request to fetch an instance.
+// Parameters are those the instance was effectively provisioned with and
+// RequestedParameters are those that were originally requested. Both are
+// populated only if the broker was auditing provisioning parameters when the
+// instance was provisioned. Values of secret parameters are redacted.
+// CostEstimate is populated only if the broker was able to estimate the
+// instance's cost when it was provisioned. ProvisioningTiming is populated
+// only once the instance has finished provisioning. ExpiresAt is populated
+// only for sandbox instances.
+type InstanceResponse struct {
+	ServiceID           string                      `json:"service_id"`
+	PlanID              string                      `json:"plan_id"`
+	Parameters          map[string]interface{}      `json:"parameters,omitempty"`
+	RequestedParameters map[string]interface{}      `json:"requested_parameters,omitempty"` // nolint: lll
+	CostEstimate        *service.CostEstimate       `json:"cost_estimate,omitempty"`        // nolint: lll
+	ProvisioningTiming  *service.ProvisioningTiming `json:"provisioning_timing,omitempty"`  // nolint: lll
+	ExpiresAt           *time.Time                  `json:"expires_at,omitempty"`           // nolint: lll
+}
+
+// GetInstanceResponseFromJSON returns a new InstanceResponse unmarshalled from
+// the provided JSON []byte
+func GetInstanceResponseFromJSON(
+	jsonBytes []byte,
+	instanceResponse *InstanceResponse,
+) error {
+	return json.Unmarshal(jsonBytes, instanceResponse)
+}
+
+// ToJSON returns a []byte containing a JSON representation of the instance
+// response
+func (i *InstanceResponse) ToJSON() ([]byte, error) {
+	return json.Marshal(i)
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+const (
+	keyRotationStatusInProgress = "in progress"
+	keyRotationStatusCompleted  = "completed"
+)
+
+// KeyRotationResponse represents the response to a request to fetch the
+// progress of the rotation of the broker's encryption key. Remaining counts
+// the instances that have yet to be processed.
+type KeyRotationResponse struct {
+	KeyVersion     string     `json:"key_version"`
+	Status         string     `json:"status"`
+	Started        time.Time  `json:"started"`
+	Updated        time.Time  `json:"updated"`
+	Completed      *time.Time `json:"completed,omitempty"`
+	LastInstanceID string     `json:"last_instance_id,omitempty"`
+	Processed      int64      `json:"processed"`
+	Remaining      int64      `json:"remaining"`
+	Rotated        int64      `json:"rotated"`
+	Skipped        int64      `json:"skipped"`
+	Deferred       int64      `json:"deferred"`
+	Failed         int64      `json:"failed"`
+}
+
+// GetKeyRotationResponseFromJSON returns a new KeyRotationResponse
+// unmarshalled from the provided JSON []byte
+func GetKeyRotationResponseFromJSON(
+	jsonBytes []byte,
+	keyRotationResponse *KeyRotationResponse,
+) error {
+	return json.Unmarshal(jsonBytes, keyRotationResponse)
+}
+
+// ToJSON returns a []byte containing a JSON representation of the key rotation
+// response
+func (k *KeyRotationResponse) ToJSON() ([]byte, error) {
+	return json.Marshal(k)
+}
+
+func (s *server) getKeyRotation(w http.ResponseWriter, _ *http.Request) {
+	log.Debug("received request to fetch key rotation progress")
+
+	progress, ok, err := s.store.GetKeyRotationProgress()
+	if err != nil {
+		log.WithField("error", err).Error(
+			"key rotation progress error: error retrieving progress",
+		)
+		s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
+		return
+	}
+	if !ok {
+		log.Debug("key rotation progress request, but no rotation has started")
+		s.writeResponse(w, http.StatusNotFound, generateEmptyResponse())
+		return
+	}
+	keyRotationResponse := &KeyRotationResponse{
+		KeyVersion:     progress.KeyVersion,
+		Status:         keyRotationStatusInProgress,
+		Started:        progress.Started,
+		Updated:        progress.Updated,
+		Completed:      progress.Completed,
+		LastInstanceID: progress.LastInstanceID,
+		Processed:      progress.GetProcessed(),
+		Rotated:        progress.Rotated,
+		Skipped:        progress.Skipped,
+		Deferred:       progress.Deferred,
+		Failed:         progress.Failed,
+	}
+	if progress.Completed != nil {
+		keyRotationResponse.Status = keyRotationStatusCompleted
+	} else {
+		instanceIDs, err := s.store.GetInstanceIDs()
+		if err != nil {
+			log.WithField("error", err).Error(
+				"key rotation progress error: error listing instances",
+			)
+			s.writeResponse(
+				w,
+				http.StatusInternalServerError,
+				generateEmptyResponse(),
+			)
+			return
+		}
+		for _, instanceID := range instanceIDs {
+			if instanceID > progress.LastInstanceID {
+				keyRotationResponse.Remaining++
+			}
+		}
+	}
+	keyRotationJSON, err := keyRotationResponse.ToJSON()
+	if err != nil {
+		log.WithField("error", err).Error(
+			"key rotation progress error: error marshaling response",
+		)
+		s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
+		return
+	}
+	s.writeResponse(w, http.StatusOK, keyRotationJSON)
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetKeyRotationBeforeRotationHasStarted(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	req, err := http.NewRequest(http.MethodGet, "/admin/key_rotation", nil)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestGetKeyRotationInProgress(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	for _, instanceID := range []string{"a", "b", "c"} {
+		err = s.store.WriteInstance(service.Instance{
+			InstanceID: instanceID,
+			ServiceID:  fake.ServiceID,
+			PlanID:     fake.StandardPlanID,
+		})
+		assert.Nil(t, err)
+	}
+	err = s.store.WriteKeyRotationProgress(service.KeyRotationProgress{
+		KeyVersion:     "2",
+		Started:        time.Now().UTC(),
+		Updated:        time.Now().UTC(),
+		LastInstanceID: "a",
+		Rotated:        1,
+	})
+	assert.Nil(t, err)
+	req, err := http.NewRequest(http.MethodGet, "/admin/key_rotation", nil)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	keyRotationResponse := &KeyRotationResponse{}
+	err = GetKeyRotationResponseFromJSON(rr.Body.Bytes(), keyRotationResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, "2", keyRotationResponse.KeyVersion)
+	assert.Equal(t, keyRotationStatusInProgress, keyRotationResponse.Status)
+	assert.Equal(t, int64(1), keyRotationResponse.Processed)
+	assert.Equal(t, int64(2), keyRotationResponse.Remaining)
+	assert.Equal(t, int64(1), keyRotationResponse.Rotated)
+}
+
+func TestGetKeyRotationCompleted(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	completed := time.Now().UTC()
+	err = s.store.WriteKeyRotationProgress(service.KeyRotationProgress{
+		KeyVersion: "2",
+		Completed:  &completed,
+		Skipped:    3,
+	})
+	assert.Nil(t, err)
+	req, err := http.NewRequest(http.MethodGet, "/admin/key_rotation", nil)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	keyRotationResponse := &KeyRotationResponse{}
+	err = GetKeyRotationResponseFromJSON(rr.Body.Bytes(), keyRotationResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, keyRotationStatusCompleted, keyRotationResponse.Status)
+	assert.Equal(t, int64(3), keyRotationResponse.Processed)
+	assert.Equal(t, int64(0), keyRotationResponse.Remaining)
+}
@@ -0,0 +1,30 @@
+package api
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// validateMaintenanceWindow verifies that the maintenance window, if any,
+// requested by the given provisioning or updating parameters is well formed
+// and can be set for instances of the given plan in the given location
+func validateMaintenanceWindow(
+	serviceManager service.ServiceManager,
+	plan service.Plan,
+	location string,
+	parameters interface{},
+) error {
+	requester, ok := parameters.(service.MaintenanceWindowRequester)
+	if !ok {
+		return nil
+	}
+	window := requester.GetMaintenanceWindow()
+	if window == nil {
+		return nil
+	}
+	if err := window.Validate("maintenanceWindow"); err != nil {
+		return err
+	}
+	if validator, ok :=
+		serviceManager.(service.MaintenanceWindowValidator); ok {
+		return validator.ValidateMaintenanceWindow(plan, location, *window)
+	}
+	return nil
+}
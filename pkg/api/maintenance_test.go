@@ -0,0 +1,79 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+type maintenanceWindowParameters struct {
+	window *service.MaintenanceWindow
+}
+
+func (
+	m maintenanceWindowParameters,
+) GetMaintenanceWindow() *service.MaintenanceWindow {
+	return m.window
+}
+
+type maintenanceWindowServiceManager struct {
+	*fake.ServiceManager
+	validatedLocation string
+	err               error
+}
+
+func (m *maintenanceWindowServiceManager) ValidateMaintenanceWindow(
+	_ service.Plan,
+	location string,
+	_ service.MaintenanceWindow,
+) error {
+	m.validatedLocation = location
+	return m.err
+}
+
+func TestValidateMaintenanceWindow(t *testing.T) {
+	m := &maintenanceWindowServiceManager{
+		err: service.NewValidationError("maintenanceWindow", "unsupported"),
+	}
+	// Parameters that request no window aren't validated
+	assert.Nil(t, validateMaintenanceWindow(m, nil, "eastus", nil))
+	assert.Nil(
+		t,
+		validateMaintenanceWindow(
+			m,
+			nil,
+			"eastus",
+			maintenanceWindowParameters{},
+		),
+	)
+	assert.Empty(t, m.validatedLocation)
+	// A malformed window is rejected before the module is consulted
+	err := validateMaintenanceWindow(
+		m,
+		nil,
+		"eastus",
+		maintenanceWindowParameters{
+			window: &service.MaintenanceWindow{DayOfWeek: "Someday"},
+		},
+	)
+	assert.Equal(
+		t,
+		"maintenanceWindow.dayOfWeek",
+		err.(*service.ValidationError).Field,
+	)
+	assert.Empty(t, m.validatedLocation)
+	pp := maintenanceWindowParameters{
+		window: &service.MaintenanceWindow{DayOfWeek: "Sunday", StartHourUTC: 2},
+	}
+	err = validateMaintenanceWindow(m, nil, "eastus", pp)
+	assert.Equal(t, m.err, err)
+	assert.Equal(t, "eastus", m.validatedLocation)
+	// Modules that don't validate windows themselves accept any that's well
+	// formed
+	assert.Nil(
+		t,
+		validateMaintenanceWindow(&fake.ServiceManager{}, nil, "eastus", pp),
+	)
+}
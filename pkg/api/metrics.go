@@ -0,0 +1,383 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	"github.com/Azure/open-service-broker-azure/pkg/storage"
+	"github.com/Azure/open-service-broker-azure/pkg/telemetry"
+	log "github.com/Sirupsen/logrus"
+)
+
+// InFlightProvisionCounter is an interface to be implemented by types that
+// count the provisioning operations in flight against each Azure subscription
+// whose concurrent provisions are capped
+type InFlightProvisionCounter interface {
+	// GetInFlightProvisions returns the number of provisioning operations in
+	// flight, keyed by subscription ID
+	GetInFlightProvisions() (map[string]int64, error)
+}
+
+// metricLabelValueEscaper escapes those characters the Prometheus text
+// exposition format doesn't permit unescaped in label values
+var metricLabelValueEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"\n", `\n`,
+)
+
+// seriesLimiter is the signature for functions that split the sorted label
+// values of a metric's series into those whose series are reported
+// individually and those whose series are aggregated into one
+type seriesLimiter func(
+	labelValues []string,
+) (kept []string, aggregated []string)
+
+// limitSeries caps the series of metrics whose labels aren't bounded by the
+// catalog, in accordance with the telemetry sampling, if there is any
+func (s *server) limitSeries(labelValues []string) ([]string, []string) {
+	if s.telemetrySampler == nil {
+		return labelValues, nil
+	}
+	return s.telemetrySampler.LimitSeries(labelValues)
+}
+
+// getMetrics responds with the size of the async engine's worker pools, storage
+// replication lag, in-flight provisioning operations and provisioning SLA
+// attainment in the Prometheus text exposition format
+func (s *server) getMetrics(w http.ResponseWriter, _ *http.Request) {
+	report, err := s.buildProvisioningSLAReport()
+	if err != nil {
+		log.WithField("error", err).Error(
+			"metrics error: error building provisioning SLA report",
+		)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	metrics := &bytes.Buffer{}
+	writeWorkerMetrics(metrics, s.asyncEngine.GetWorkerCount())
+	writeTenantPoolMetrics(
+		metrics,
+		s.asyncEngine.GetTenantPoolUtilization(),
+		s.limitSeries,
+	)
+	if s.throttle != nil {
+		writeDispatchRateMetrics(
+			metrics,
+			s.throttle.GetDispatchRates(),
+			s.limitSeries,
+		)
+	}
+	if replicatedStore, ok := s.store.(storage.ReplicatedStore); ok {
+		lag, err := replicatedStore.GetReplicationLag()
+		if err != nil {
+			log.WithField("error", err).Error(
+				"metrics error: error retrieving storage replication lag",
+			)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		writeReplicationLagMetrics(metrics, lag)
+	}
+	if s.inFlightProvisions != nil {
+		counts, err := s.inFlightProvisions.GetInFlightProvisions()
+		if err != nil {
+			log.WithField("error", err).Error(
+				"metrics error: error counting in-flight provisioning operations",
+			)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		writeInFlightProvisionMetrics(metrics, counts, s.limitSeries)
+	}
+	writeProvisioningSLAMetrics(metrics, report)
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(metrics.Bytes()); err != nil {
+		log.WithField("error", err).Error(
+			"api server error: error writing response",
+		)
+	}
+}
+
+// writeWorkerMetrics writes the number of workers the async engine has
+// available to execute tasks
+func writeWorkerMetrics(metrics *bytes.Buffer, workerCount int) {
+	writeMetricHeader(
+		metrics,
+		"osba_async_workers",
+		"gauge",
+		"Workers the async engine currently has available to execute tasks.",
+	)
+	fmt.Fprintf(metrics, "osba_async_workers %d\n", workerCount)
+}
+
+// writeTenantPoolMetrics writes the size and utilization of the pools of
+// workers dedicated to organizations, if there are any. Pools beyond the
+// series limit are reported together.
+func writeTenantPoolMetrics(
+	metrics *bytes.Buffer,
+	utilization map[string]async.PoolUtilization,
+	limitSeries seriesLimiter,
+) {
+	if len(utilization) == 0 {
+		return
+	}
+	organizationGUIDs := make([]string, 0, len(utilization))
+	for organizationGUID := range utilization {
+		organizationGUIDs = append(organizationGUIDs, organizationGUID)
+	}
+	sort.Strings(organizationGUIDs)
+	organizationGUIDs, aggregated := limitSeries(organizationGUIDs)
+	other := async.PoolUtilization{}
+	for _, organizationGUID := range aggregated {
+		other.Workers += utilization[organizationGUID].Workers
+		other.BusyWorkers += utilization[organizationGUID].BusyWorkers
+	}
+	writeMetricHeader(
+		metrics,
+		"osba_async_tenant_pool_workers",
+		"gauge",
+		"Workers the async engine has dedicated to executing the organization's "+
+			"tasks.",
+	)
+	for _, organizationGUID := range organizationGUIDs {
+		fmt.Fprintf(
+			metrics,
+			"osba_async_tenant_pool_workers{organization_guid=\"%s\"} %d\n",
+			metricLabelValueEscaper.Replace(organizationGUID),
+			utilization[organizationGUID].Workers,
+		)
+	}
+	if len(aggregated) > 0 {
+		fmt.Fprintf(
+			metrics,
+			"osba_async_tenant_pool_workers{organization_guid=\"%s\"} %d\n",
+			telemetry.OtherLabelValue,
+			other.Workers,
+		)
+	}
+	writeMetricHeader(
+		metrics,
+		"osba_async_tenant_pool_busy_workers",
+		"gauge",
+		"Workers dedicated to the organization that are executing a task.",
+	)
+	for _, organizationGUID := range organizationGUIDs {
+		fmt.Fprintf(
+			metrics,
+			"osba_async_tenant_pool_busy_workers{organization_guid=\"%s\"} %d\n",
+			metricLabelValueEscaper.Replace(organizationGUID),
+			utilization[organizationGUID].BusyWorkers,
+		)
+	}
+	if len(aggregated) > 0 {
+		fmt.Fprintf(
+			metrics,
+			"osba_async_tenant_pool_busy_workers{organization_guid=\"%s\"} %d\n",
+			telemetry.OtherLabelValue,
+			other.BusyWorkers,
+		)
+	}
+}
+
+// writeDispatchRateMetrics writes the rate at which steps using each throttled
+// Azure resource provider are executed. Providers beyond the series limit are
+// reported together.
+func writeDispatchRateMetrics(
+	metrics *bytes.Buffer,
+	rates map[string]float64,
+	limitSeries seriesLimiter,
+) {
+	writeMetricHeader(
+		metrics,
+		"osba_azure_dispatch_rate_per_minute",
+		"gauge",
+		"Rate at which steps using the Azure resource provider are executed "+
+			"while it is throttling the broker.",
+	)
+	providers := make([]string, 0, len(rates))
+	for provider := range rates {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+	providers, aggregated := limitSeries(providers)
+	for _, provider := range providers {
+		fmt.Fprintf(
+			metrics,
+			"osba_azure_dispatch_rate_per_minute{provider=\"%s\"} %g\n",
+			metricLabelValueEscaper.Replace(provider),
+			rates[provider],
+		)
+	}
+	if len(aggregated) > 0 {
+		var other float64
+		for _, provider := range aggregated {
+			other += rates[provider]
+		}
+		fmt.Fprintf(
+			metrics,
+			"osba_azure_dispatch_rate_per_minute{provider=\"%s\"} %g\n",
+			telemetry.OtherLabelValue,
+			other,
+		)
+	}
+}
+
+// writeReplicationLagMetrics writes the age of the oldest write to the primary
+// store that has yet to be replicated to the secondary store
+func writeReplicationLagMetrics(metrics *bytes.Buffer, lag time.Duration) {
+	writeMetricHeader(
+		metrics,
+		"osba_storage_replication_lag_seconds",
+		"gauge",
+		"Age of the oldest write to the primary store that has yet to be "+
+			"replicated to the secondary store.",
+	)
+	fmt.Fprintf(
+		metrics,
+		"osba_storage_replication_lag_seconds %g\n",
+		lag.Seconds(),
+	)
+}
+
+// writeInFlightProvisionMetrics writes the number of provisioning operations
+// in flight against each capped Azure subscription. Subscriptions beyond the
+// series limit are reported together.
+func writeInFlightProvisionMetrics(
+	metrics *bytes.Buffer,
+	counts map[string]int64,
+	limitSeries seriesLimiter,
+) {
+	writeMetricHeader(
+		metrics,
+		"osba_provisioning_in_flight",
+		"gauge",
+		"Provisioning operations in flight against the Azure subscription.",
+	)
+	subscriptionIDs := make([]string, 0, len(counts))
+	for subscriptionID := range counts {
+		subscriptionIDs = append(subscriptionIDs, subscriptionID)
+	}
+	sort.Strings(subscriptionIDs)
+	subscriptionIDs, aggregated := limitSeries(subscriptionIDs)
+	for _, subscriptionID := range subscriptionIDs {
+		fmt.Fprintf(
+			metrics,
+			"osba_provisioning_in_flight{subscription=\"%s\"} %d\n",
+			metricLabelValueEscaper.Replace(subscriptionID),
+			counts[subscriptionID],
+		)
+	}
+	if len(aggregated) > 0 {
+		var other int64
+		for _, subscriptionID := range aggregated {
+			other += counts[subscriptionID]
+		}
+		fmt.Fprintf(
+			metrics,
+			"osba_provisioning_in_flight{subscription=\"%s\"} %d\n",
+			telemetry.OtherLabelValue,
+			other,
+		)
+	}
+}
+
+// writeProvisioningSLAMetrics writes the target and attainment of each plan in
+// the given provisioning SLA report
+func writeProvisioningSLAMetrics(
+	metrics *bytes.Buffer,
+	report *ProvisioningSLAReport,
+) {
+	writeMetricHeader(
+		metrics,
+		"osba_provisioning_sla_target_seconds",
+		"gauge",
+		"Provisioning SLA target of the plan.",
+	)
+	for _, entry := range report.Plans {
+		if entry.TargetSeconds > 0 {
+			writePlanMetric(
+				metrics,
+				"osba_provisioning_sla_target_seconds",
+				entry,
+				entry.TargetSeconds,
+			)
+		}
+	}
+	writeMetricHeader(
+		metrics,
+		"osba_provisioning_sla_within_target_total",
+		"counter",
+		"Instances of the plan that were provisioned within target.",
+	)
+	for _, entry := range report.Plans {
+		writePlanMetric(
+			metrics,
+			"osba_provisioning_sla_within_target_total",
+			entry,
+			float64(entry.WithinTarget),
+		)
+	}
+	writeMetricHeader(
+		metrics,
+		"osba_provisioning_sla_breaches_total",
+		"counter",
+		"Instances of the plan that took longer than target to provision.",
+	)
+	for _, entry := range report.Plans {
+		writePlanMetric(
+			metrics,
+			"osba_provisioning_sla_breaches_total",
+			entry,
+			float64(entry.Breached),
+		)
+	}
+	writeMetricHeader(
+		metrics,
+		"osba_provisioning_sla_attainment_ratio",
+		"gauge",
+		"Fraction of the plan's instances that were provisioned within target.",
+	)
+	for _, entry := range report.Plans {
+		if entry.Attainment != nil {
+			writePlanMetric(
+				metrics,
+				"osba_provisioning_sla_attainment_ratio",
+				entry,
+				*entry.Attainment,
+			)
+		}
+	}
+}
+
+func writeMetricHeader(
+	metrics *bytes.Buffer,
+	name string,
+	metricType string,
+	help string,
+) {
+	fmt.Fprintf(metrics, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(metrics, "# TYPE %s %s\n", name, metricType)
+}
+
+func writePlanMetric(
+	metrics *bytes.Buffer,
+	name string,
+	entry ProvisioningSLAReportEntry,
+	value float64,
+) {
+	fmt.Fprintf(
+		metrics,
+		"%s{service=\"%s\",plan=\"%s\"} %g\n",
+		name,
+		metricLabelValueEscaper.Replace(entry.ServiceName),
+		metricLabelValueEscaper.Replace(entry.PlanName),
+		value,
+	)
+}
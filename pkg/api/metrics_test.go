@@ -0,0 +1,180 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	fakeAsync "github.com/Azure/open-service-broker-azure/pkg/async/fake"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	"github.com/Azure/open-service-broker-azure/pkg/storage"
+	"github.com/Azure/open-service-broker-azure/pkg/telemetry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMetrics(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	s.provisioningSLA, err = service.NewProvisioningSLA(time.Minute, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, s.store.RecordProvisioningSLAOutcome(fake.StandardPlanID, true))
+	s.asyncEngine.(*fakeAsync.Engine).WorkerCount = 7
+	s.asyncEngine.(*fakeAsync.Engine).TenantPoolUtilization =
+		map[string]async.PoolUtilization{
+			"org": {
+				Workers:     2,
+				BusyWorkers: 1,
+			},
+		}
+	req, err := http.NewRequest(http.MethodGet, "/metrics", nil)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	lines := strings.Split(rr.Body.String(), "\n")
+	for _, expected := range []string{
+		`osba_async_workers 7`,
+		`osba_async_tenant_pool_workers{organization_guid="org"} 2`,
+		`osba_async_tenant_pool_busy_workers{organization_guid="org"} 1`,
+		`osba_provisioning_sla_target_seconds{service="fake",plan="standard"} 60`,
+		`osba_provisioning_sla_within_target_total{service="fake",plan="standard"} 0`, // nolint: lll
+		`osba_provisioning_sla_breaches_total{service="fake",plan="standard"} 1`,
+		`osba_provisioning_sla_attainment_ratio{service="fake",plan="standard"} 0`,
+	} {
+		assert.Contains(t, lines, expected)
+	}
+}
+
+type fakeResourceProviderThrottle struct {
+	rates map[string]float64
+}
+
+func (f *fakeResourceProviderThrottle) Admit([]string) time.Duration {
+	return 0
+}
+
+func (f *fakeResourceProviderThrottle) GetDispatchRates() map[string]float64 {
+	return f.rates
+}
+
+func TestGetMetricsWithThrottledResourceProviders(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	s.throttle = &fakeResourceProviderThrottle{
+		rates: map[string]float64{
+			"Microsoft.Sql":     7.5,
+			"Microsoft.Compute": 0,
+		},
+	}
+	req, err := http.NewRequest(http.MethodGet, "/metrics", nil)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(
+		t,
+		rr.Body.String(),
+		"osba_azure_dispatch_rate_per_minute{provider=\"Microsoft.Compute\"} 0\n"+
+			"osba_azure_dispatch_rate_per_minute{provider=\"Microsoft.Sql\"} 7.5\n",
+	)
+}
+
+type fakeReplicatedStore struct {
+	storage.Store
+	lag time.Duration
+}
+
+func (f *fakeReplicatedStore) ReplicateInstance(string) error {
+	return nil
+}
+
+func (f *fakeReplicatedStore) ReplicateBinding(string) error {
+	return nil
+}
+
+func (f *fakeReplicatedStore) GetReplicationLag() (time.Duration, error) {
+	return f.lag, nil
+}
+
+func TestGetMetricsWithReplicatedStore(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	s.store = &fakeReplicatedStore{
+		Store: s.store,
+		lag:   1500 * time.Millisecond,
+	}
+	req, err := http.NewRequest(http.MethodGet, "/metrics", nil)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	lines := strings.Split(rr.Body.String(), "\n")
+	assert.Contains(t, lines, "osba_storage_replication_lag_seconds 1.5")
+}
+
+type fakeInFlightProvisionCounter struct {
+	counts map[string]int64
+}
+
+func (f *fakeInFlightProvisionCounter) GetInFlightProvisions() (
+	map[string]int64,
+	error,
+) {
+	return f.counts, nil
+}
+
+func TestGetMetricsWithInFlightProvisions(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	s.inFlightProvisions = &fakeInFlightProvisionCounter{
+		counts: map[string]int64{
+			"sub-b": 0,
+			"sub-a": 3,
+		},
+	}
+	req, err := http.NewRequest(http.MethodGet, "/metrics", nil)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(
+		t,
+		rr.Body.String(),
+		"osba_provisioning_in_flight{subscription=\"sub-a\"} 3\n"+
+			"osba_provisioning_in_flight{subscription=\"sub-b\"} 0\n",
+	)
+}
+
+func TestGetMetricsWithSeriesLimit(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	s.telemetrySampler, err = telemetry.NewSampler(
+		telemetry.Sampling{TraceSampleRate: 1, MaxSeriesPerMetric: 2},
+		nil,
+		0,
+	)
+	assert.Nil(t, err)
+	s.inFlightProvisions = &fakeInFlightProvisionCounter{
+		counts: map[string]int64{
+			"sub-c": 2,
+			"sub-b": 1,
+			"sub-a": 3,
+		},
+	}
+	req, err := http.NewRequest(http.MethodGet, "/metrics", nil)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(
+		t,
+		rr.Body.String(),
+		"osba_provisioning_in_flight{subscription=\"sub-a\"} 3\n"+
+			"osba_provisioning_in_flight{subscription=\"other\"} 3\n",
+	)
+	assert.NotContains(t, rr.Body.String(), "sub-b")
+}
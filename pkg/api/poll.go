@@ -3,6 +3,7 @@ package api
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 	log "github.com/Sirupsen/logrus"
@@ -71,11 +72,53 @@ func (s *server) poll(
 
 	if operation == OperationProvisioning {
 		switch instance.Status {
+		case service.InstanceStateAwaitingApproval:
+			log.WithFields(logFields).Debug(
+				"provisioning is awaiting approval",
+			)
+			s.writeResponse(w, http.StatusOK, generateOperationInProgressResponse())
+		case service.InstanceStateQueued:
+			// The position is a convenience, so failing to find it doesn't fail
+			// the request
+			position, err := s.getProvisioningQueuePosition(instance)
+			if err != nil {
+				log.WithFields(logFields).WithField("error", err).Warn(
+					"polling error: error retrieving queue position; omitting position",
+				)
+			}
+			logFields["queuePosition"] = position
+			log.WithFields(logFields).Debug(
+				"provisioning is queued awaiting capacity",
+			)
+			s.writeResponse(
+				w,
+				http.StatusOK,
+				generateProvisioningQueuedResponse(position),
+			)
 		case service.InstanceStateProvisioning:
 			log.WithFields(logFields).Debug(
 				"provisioning is in progress",
 			)
-			s.writeResponse(w, http.StatusOK, generateOperationInProgressResponse())
+			s.writeResponse(
+				w,
+				http.StatusOK,
+				generateProvisioningInProgressResponse(
+					s.getProvisioningEstimate(instance, time.Now()),
+				),
+			)
+		case service.InstanceStateProvisioningDegraded:
+			// Provisioning hasn't failed until the failure grace window is
+			// exhausted
+			log.WithFields(logFields).Debug(
+				"provisioning is degraded, but still in progress",
+			)
+			s.writeResponse(
+				w,
+				http.StatusOK,
+				generateProvisioningInProgressResponse(
+					s.getProvisioningEstimate(instance, time.Now()),
+				),
+			)
 		case service.InstanceStateProvisioned:
 			log.WithFields(logFields).Debug(
 				"provisioning is complete",
@@ -52,6 +52,25 @@ func TestPollingWithInstanceProvisioning(t *testing.T) {
 	assert.Equal(t, responseInProgress, rr.Body.Bytes())
 }
 
+func TestPollingWithInstanceProvisioningDegraded(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	err = s.store.WriteInstance(service.Instance{
+		InstanceID: instanceID,
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateProvisioningDegraded,
+	})
+	assert.Nil(t, err)
+	req, err := getPollingRequest(instanceID, OperationProvisioning)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, responseInProgress, rr.Body.Bytes())
+}
+
 func TestPollingWithInstanceProvisioned(t *testing.T) {
 	s, _, err := getTestServer("", "")
 	assert.Nil(t, err)
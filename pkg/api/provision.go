@@ -1,6 +1,7 @@
 package api
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -126,6 +127,68 @@ func (s *server) provision(w http.ResponseWriter, r *http.Request) {
 
 	// Unpack the parameter map...
 
+	// Clone source...
+	cloneFrom := ""
+	cloneFromIface, ok := provisioningRequest.Parameters["cloneFrom"]
+	if ok {
+		cloneFrom, ok = cloneFromIface.(string)
+		if !ok {
+			s.handlePossibleValidationError(
+				service.NewValidationError(
+					"cloneFrom",
+					fmt.Sprintf(`"%v" is not a string`, cloneFromIface),
+				),
+				w,
+				logFields,
+			)
+			return
+		}
+	}
+	cloneData := false
+	cloneDataIface, ok := provisioningRequest.Parameters["cloneData"]
+	if ok {
+		cloneData, ok = cloneDataIface.(bool)
+		if !ok {
+			s.handlePossibleValidationError(
+				service.NewValidationError(
+					"cloneData",
+					fmt.Sprintf(`"%v" is not a bool`, cloneDataIface),
+				),
+				w,
+				logFields,
+			)
+			return
+		}
+	}
+	// If this instance is to be a clone of another, the source instance's
+	// location, resource group, tags, parent alias, dependencies, and
+	// service-specific parameters are used wherever the request doesn't
+	// explicitly override them
+	var cloneSource *service.Instance
+	if cloneFrom != "" {
+		logFields["cloneFrom"] = cloneFrom
+		source, err := s.getCloneSource(svc, cloneFrom, cloneData)
+		if err != nil {
+			s.handlePossibleValidationError(err, w, logFields)
+			return
+		}
+		cloneSource = &source
+	} else if cloneData {
+		s.handlePossibleValidationError(
+			service.NewValidationError(
+				"cloneData",
+				"cloneData may only be specified in conjunction with cloneFrom",
+			),
+			w,
+			logFields,
+		)
+		return
+	}
+
+	// Values from the request's context that templated parameters may
+	// reference
+	templateContext := provisioningRequest.GetTemplateContext()
+
 	// Location...
 	location := ""
 	locIface, ok := provisioningRequest.Parameters["location"]
@@ -142,6 +205,8 @@ func (s *server) provision(w http.ResponseWriter, r *http.Request) {
 			)
 			return
 		}
+	} else if cloneSource != nil {
+		location = cloneSource.Location
 	}
 	location = s.getLocation(location)
 
@@ -161,6 +226,18 @@ func (s *server) provision(w http.ResponseWriter, r *http.Request) {
 			)
 			return
 		}
+		// The resource group is always templatable, since resource group names
+		// can't otherwise contain braces
+		requestedResourceGroup, err = service.ResolveTemplate(
+			requestedResourceGroup,
+			templateContext,
+		)
+		if err != nil {
+			s.handlePossibleValidationError(err, w, logFields)
+			return
+		}
+	} else if cloneSource != nil {
+		requestedResourceGroup = cloneSource.ResourceGroup
 	}
 	resourceGroup := s.getResourceGroup(requestedResourceGroup)
 
@@ -202,6 +279,10 @@ func (s *server) provision(w http.ResponseWriter, r *http.Request) {
 			s.writeResponse(w, http.StatusBadRequest, generateMalformedTagsResponse())
 			return
 		}
+	} else if cloneSource != nil {
+		// The clone source's metadata tags describe the clone source, so they
+		// aren't carried over to the clone
+		tags = service.WithoutMetadataTags(cloneSource.Tags)
 	}
 
 	// Alias
@@ -238,10 +319,83 @@ func (s *server) provision(w http.ResponseWriter, r *http.Request) {
 			)
 			return
 		}
+	} else if cloneSource != nil {
+		parentAlias = cloneSource.ParentAlias
+	}
+
+	// Dependencies...
+	dependsOn, err := getDependsOn(provisioningRequest.Parameters)
+	if err != nil {
+		s.handlePossibleValidationError(err, w, logFields)
+		return
+	}
+	if _, ok = provisioningRequest.Parameters["dependsOn"]; !ok {
+		if cloneSource != nil {
+			dependsOn = cloneSource.DependsOn
+		}
+	}
+
+	// Subscription...
+	requestedSubscriptionID, err :=
+		getRequestedSubscriptionID(provisioningRequest.Parameters)
+	if err != nil {
+		s.handlePossibleValidationError(err, w, logFields)
+		return
+	}
+
+	// Deferred activation...
+	activateAt, err := getActivateAt(provisioningRequest.Parameters)
+	if err != nil {
+		s.handlePossibleValidationError(err, w, logFields)
+		return
+	}
+
+	// Sandbox...
+	sandbox, err := getSandbox(provisioningRequest.Parameters)
+	if err != nil {
+		s.handlePossibleValidationError(err, w, logFields)
+		return
+	}
+
+	// Debug tracing...
+	debug, err := s.getDebug(provisioningRequest.Parameters)
+	if err != nil {
+		s.handlePossibleValidationError(err, w, logFields)
+		return
 	}
 
 	// Now service-specific parameters...
 	provisioningParameters := serviceManager.GetEmptyProvisioningParameters()
+	if cloneSource != nil {
+		// Start from a copy of the source instance's parameters. Any parameters
+		// included in the request are decoded over the top of these.
+		if err = copyProvisioningParameters(
+			cloneSource.ProvisioningParameters,
+			provisioningParameters,
+		); err != nil {
+			logFields["error"] = err
+			log.WithFields(logFields).Error(
+				"pre-provisioning error: error copying clone source's parameters",
+			)
+			s.writeResponse(
+				w,
+				http.StatusInternalServerError,
+				generateEmptyResponse(),
+			)
+			return
+		}
+	}
+	// Templated service-specific parameters are resolved before they're
+	// decoded, so it's the resolved values that are validated and persisted
+	params, err := service.ResolveParameterTemplates(
+		provisioningRequest.Parameters,
+		provisioningParameters,
+		templateContext,
+	)
+	if err != nil {
+		s.handlePossibleValidationError(err, w, logFields)
+		return
+	}
 	decoderConfig := &mapstructure.DecoderConfig{
 		TagName: "json",
 		Result:  provisioningParameters,
@@ -255,7 +409,7 @@ func (s *server) provision(w http.ResponseWriter, r *http.Request) {
 		s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
 		return
 	}
-	err = decoder.Decode(provisioningRequest.Parameters)
+	err = decoder.Decode(params)
 	if err != nil {
 		log.WithFields(logFields).Debug(
 			"bad provisioning request: error decoding parameter map into " +
@@ -296,7 +450,15 @@ func (s *server) provision(w http.ResponseWriter, r *http.Request) {
 			// resourceGroup is the empty string...
 			(requestedResourceGroup == "" ||
 				instance.ResourceGroup == resourceGroup) &&
-			reflect.DeepEqual(instance.Tags, tags) &&
+			// Likewise, the subscription is only taken into account if one was
+			// explicitly requested
+			(requestedSubscriptionID == "" ||
+				instance.SubscriptionID == requestedSubscriptionID) &&
+			areTagsEqual(service.WithoutMetadataTags(instance.Tags), tags) &&
+			areDependenciesEqual(instance.DependsOn, dependsOn) &&
+			isActivationRequested(instance, activateAt) &&
+			instance.IsSandbox() == sandbox &&
+			instance.Debug == debug &&
 			reflect.DeepEqual(
 				instance.ProvisioningParameters,
 				provisioningParameters,
@@ -305,8 +467,15 @@ func (s *server) provision(w http.ResponseWriter, r *http.Request) {
 			// Filling in a gap in the spec-- if the status is anything else, we'll
 			// choose to respond with a 409
 			switch instance.Status {
-			case service.InstanceStateProvisioning:
-				s.writeResponse(w, http.StatusAccepted, generateProvisionAcceptedResponse())
+			case service.InstanceStateAwaitingApproval,
+				service.InstanceStateQueued,
+				service.InstanceStateProvisioning,
+				service.InstanceStateProvisioningDegraded:
+				s.writeResponse(
+					w,
+					http.StatusAccepted,
+					generateProvisionAcceptedResponse(instance.CostEstimate),
+				)
 				return
 			case service.InstanceStateProvisioned:
 				s.writeResponse(w, http.StatusOK, generateEmptyResponse())
@@ -334,6 +503,13 @@ func (s *server) provision(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Validate tags
+	err = s.metadataTagging.ValidateTags(tags)
+	if err != nil {
+		s.handlePossibleValidationError(err, w, logFields)
+		return
+	}
+
 	// Validate alias (only applies if this service type has children)
 	err = s.validateAlias(svc, alias)
 	if err != nil {
@@ -348,12 +524,61 @@ func (s *server) provision(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Validate dependencies (only applies if any were requested)
+	err = s.validateDependencies(instanceID, dependsOn)
+	if err != nil {
+		s.handlePossibleValidationError(err, w, logFields)
+		return
+	}
+
+	// Choose the subscription the instance is provisioned into
+	subscriptionID, err := s.getSubscriptionID(
+		requestedSubscriptionID,
+		parentAlias,
+		cloneSource,
+		provisioningRequest,
+	)
+	if err != nil {
+		logFields["error"] = err
+		s.handlePossibleValidationError(err, w, logFields)
+		return
+	}
+
+	// Validate deferred activation (only applies if it was requested)
+	if activateAt != nil {
+		if err = validateActivateAt(svc, *activateAt); err != nil {
+			s.handlePossibleValidationError(err, w, logFields)
+			return
+		}
+	}
+
 	// Then validate service-specific provisioning parameters
 	err = serviceManager.ValidateProvisioningParameters(provisioningParameters)
 	if err != nil {
 		s.handlePossibleValidationError(err, w, logFields)
 		return
 	}
+	if locationValidator, ok :=
+		serviceManager.(service.LocationValidator); ok {
+		err = locationValidator.ValidateProvisioningParametersForLocation(
+			provisioningParameters,
+			location,
+		)
+		if err != nil {
+			s.handlePossibleValidationError(err, w, logFields)
+			return
+		}
+	}
+	err = validateMaintenanceWindow(
+		serviceManager,
+		plan,
+		location,
+		provisioningParameters,
+	)
+	if err != nil {
+		s.handlePossibleValidationError(err, w, logFields)
+		return
+	}
 
 	provisioner, err := serviceManager.GetProvisioner(plan)
 	if err != nil {
@@ -380,6 +605,25 @@ func (s *server) provision(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	details := serviceManager.GetEmptyInstanceDetails()
+	if cloneSource != nil && cloneData {
+		// getCloneSource has already established that the service manager is a
+		// DataCopier
+		dataCopier := serviceManager.(service.DataCopier)
+		if details, err = dataCopier.GetDataCopyDetails(*cloneSource); err != nil {
+			logFields["error"] = err
+			log.WithFields(logFields).Error(
+				"pre-provisioning error: error preparing to copy clone source's data",
+			)
+			s.writeResponse(
+				w,
+				http.StatusInternalServerError,
+				generateEmptyResponse(),
+			)
+			return
+		}
+	}
+
 	instance = service.Instance{
 		InstanceID:             instanceID,
 		Alias:                  alias,
@@ -389,11 +633,101 @@ func (s *server) provision(w http.ResponseWriter, r *http.Request) {
 		Status:                 service.InstanceStateProvisioning,
 		Location:               location,
 		ResourceGroup:          resourceGroup,
+		SubscriptionID:         subscriptionID,
 		ParentAlias:            parentAlias,
+		DependsOn:              dependsOn,
+		Debug:                  debug,
 		Tags:                   tags,
-		Details:                serviceManager.GetEmptyInstanceDetails(),
+		OrganizationGUID:       provisioningRequest.GetOrganizationGUID(),
+		Details:                details,
 		Created:                time.Now(),
 	}
+	instance.Tags = s.metadataTagging.ApplyTags(
+		tags,
+		service.ProvisioningMetadata{
+			OrganizationGUID: instance.OrganizationGUID,
+			SpaceGUID:        provisioningRequest.GetSpaceGUID(),
+			PlanName:         plan.GetName(),
+			Provisioned:      instance.Created,
+		},
+	)
+	instance.CostCenter = provisioningRequest.GetCostCenter(
+		s.costCenterLabel,
+		tags,
+	)
+	if s.pricingTable != nil {
+		// An estimate is a convenience, so failing to produce one doesn't stop
+		// the instance from being provisioned, nor does it make the instance
+		// require approval because of its cost
+		instance.CostEstimate, err = getCostEstimate(
+			s.pricingTable,
+			svc,
+			plan,
+			location,
+			provisioningParameters,
+		)
+		if err != nil {
+			log.WithFields(logFields).WithField("error", err).Warn(
+				"pre-provisioning error: error estimating cost; omitting estimate",
+			)
+		}
+	}
+	// Provisioning of instances whose plan requires approval, or whose
+	// estimated cost exceeds their organization's threshold, is held until the
+	// approval service decides upon it
+	requiresApproval := s.approvalPolicy.IsApprovalRequired(svc, plan)
+	if threshold, ok := s.approvalPolicy.GetExceededCostThreshold(
+		instance.OrganizationGUID,
+		instance.CostEstimate,
+	); ok {
+		log.WithFields(logFields).WithFields(log.Fields{
+			"monthlyCost":   instance.CostEstimate.MonthlyCost,
+			"costThreshold": threshold,
+		}).Info(
+			"estimated monthly cost exceeds threshold; provisioning requires " +
+				"approval",
+		)
+		requiresApproval = true
+		instance.Approval = &service.ApprovalState{
+			CostThreshold: threshold,
+		}
+	}
+	if requiresApproval {
+		instance.Status = service.InstanceStateAwaitingApproval
+	}
+	if activateAt != nil {
+		instance.Activation = &service.ActivationState{
+			ActivateAt: *activateAt,
+		}
+	}
+	// A sandbox instance's lifespan begins when it's requested, however long
+	// provisioning takes
+	if sandbox {
+		instance.Expiry, err = s.getExpiryState(svc, plan, instance.Created)
+		if err != nil {
+			s.handlePossibleValidationError(err, w, logFields)
+			return
+		}
+	}
+	if s.auditProvisioningParameters {
+		instance.ProvisioningAudit, err = getProvisioningAudit(
+			serviceManager,
+			provisioningRequest.Parameters,
+			instance,
+		)
+		if err != nil {
+			logFields["error"] = err
+			log.WithFields(logFields).Error(
+				"pre-provisioning error: error auditing provisioning parameters",
+			)
+			s.writeResponse(
+				w,
+				http.StatusInternalServerError,
+				generateEmptyResponse(),
+			)
+			return
+		}
+	}
 
 	waitForParent, err := s.isParentProvisioning(instance)
 	if err != nil {
@@ -405,17 +739,106 @@ func (s *server) provision(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Guard against an identical request that is being handled concurrently,
+	// having also found no existing instance
+	if s.provisioningDeduplication.isEnabled() {
+		claimed, duplicate, err :=
+			s.claimProvisioningRequest(instanceID, provisioningRequest)
+		if err != nil {
+			logFields["error"] = err
+			log.WithFields(logFields).Error(
+				"pre-provisioning error: error deduplicating provisioning request",
+			)
+			s.writeResponse(
+				w,
+				http.StatusInternalServerError,
+				generateEmptyResponse(),
+			)
+			return
+		}
+		if !claimed {
+			if duplicate {
+				log.WithFields(logFields).Debug(
+					"duplicate provisioning request; provisioning already initiated",
+				)
+				s.writeResponse(
+					w,
+					http.StatusAccepted,
+					generateProvisionAcceptedResponse(instance.CostEstimate),
+				)
+				return
+			}
+			s.writeResponse(w, http.StatusConflict, generateConflictResponse())
+			return
+		}
+	}
+
+	// Provisioning that starts right away needs capacity in the module's pool
+	// for concurrent provisions, if it has one. Provisioning that awaits
+	// approval, or a parent, joins the queue for capacity once it's ready to
+	// start instead, so that it doesn't hold capacity in the meantime.
+	reservesCapacity := !requiresApproval && !waitForParent
+	if reservesCapacity {
+		reserved, position, err :=
+			s.reserveProvisioningCapacity(svc, instanceID)
+		if err != nil || !reserved {
+			s.releaseProvisioningRequest(instanceID, logFields)
+		}
+		if err != nil {
+			logFields["error"] = err
+			log.WithFields(logFields).Error(
+				"pre-provisioning error: error reserving provisioning capacity",
+			)
+			s.writeResponse(
+				w,
+				http.StatusInternalServerError,
+				generateEmptyResponse(),
+			)
+			return
+		}
+		if !reserved {
+			log.WithFields(logFields).Info(
+				"provisioning request refused; module is at its cap on concurrent " +
+					"provisions and its queue is full",
+			)
+			s.writeResponse(
+				w,
+				http.StatusServiceUnavailable,
+				generateProvisioningCapacityExhaustedResponse(),
+			)
+			return
+		}
+		if position > 0 {
+			logFields["queuePosition"] = position
+			instance.Status = service.InstanceStateQueued
+		}
+	}
+
 	if err = s.store.WriteInstance(instance); err != nil {
 		logFields["error"] = err
 		log.WithFields(logFields).Error(
 			"provisioning error: error persisting new instance",
 		)
+		if reservesCapacity {
+			s.releaseProvisioningCapacity(svc, instanceID)
+		}
+		s.releaseProvisioningRequest(instanceID, logFields)
 		s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
 		return
 	}
 
 	var task async.Task
-	if waitForParent {
+	if requiresApproval {
+		task = async.NewTask(
+			"awaitApproval",
+			map[string]string{
+				"instanceID": instanceID,
+			},
+		)
+		log.WithFields(logFields).Debug(
+			"provisioning requires approval, awaiting approval",
+		)
+	} else if waitForParent {
 		task = async.NewDelayedTask(
 			"checkParentStatus",
 			map[string]string{
@@ -436,22 +859,101 @@ func (s *server) provision(w http.ResponseWriter, r *http.Request) {
 			"no need to wait for parent, starting provision",
 		)
 	}
+	// Lets the async engine schedule this instance's tasks fairly with respect
+	// to those of other organizations
+	task.SetTenant(instance.OrganizationGUID)
 
+	// A sandbox instance's expiry is scheduled before its provisioning starts,
+	// so that failing to schedule it can still be reported to the platform
+	// without leaving behind an instance that would never expire
+	if instance.IsSandbox() {
+		if err = s.scheduleExpiry(instance); err != nil {
+			logFields["error"] = err
+			log.WithFields(logFields).Error(
+				"provisioning error: error scheduling sandbox instance's expiry",
+			)
+			// Any expiry tasks that were submitted find no instance and do nothing
+			if _, err = s.store.DeleteInstance(instanceID); err != nil {
+				log.WithFields(logFields).WithField("error", err).Error(
+					"provisioning error: error deleting new instance",
+				)
+			}
+			if reservesCapacity {
+				s.releaseProvisioningCapacity(svc, instanceID)
+			}
+			s.releaseProvisioningRequest(instanceID, logFields)
+			s.writeResponse(
+				w,
+				http.StatusInternalServerError,
+				generateEmptyResponse(),
+			)
+			return
+		}
+	}
 	if err = s.asyncEngine.SubmitTask(task); err != nil {
 		logFields["step"] = firstStepName
 		logFields["error"] = err
 		log.WithFields(logFields).Error(
 			"provisioning error: error submitting provisioning task",
 		)
+		if reservesCapacity {
+			s.releaseProvisioningCapacity(svc, instanceID)
+		}
+		s.releaseProvisioningRequest(instanceID, logFields)
 		s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
 		return
 	}
 	// If we get all the way to here, we've been successful!
-	s.writeResponse(w, http.StatusAccepted, generateProvisionAcceptedResponse())
+	s.writeResponse(
+		w,
+		http.StatusAccepted,
+		generateProvisionAcceptedResponse(instance.CostEstimate),
+	)
 
 	log.WithFields(logFields).Debug("asynchronous provisioning initiated")
 }
 
+// claimProvisioningRequest claims the right to provision the given instance
+// on behalf of the given request. If another request has already claimed it
+// within the deduplication window, a bool indicating whether that request had
+// the same fingerprint is also returned.
+func (s *server) claimProvisioningRequest(
+	instanceID string,
+	pr *ProvisioningRequest,
+) (bool, bool, error) {
+	fingerprint, err := s.provisioningDeduplication.getFingerprint(pr)
+	if err != nil {
+		return false, false, err
+	}
+	claimed, existingFingerprint, err := s.store.ClaimProvisioningRequest(
+		instanceID,
+		fingerprint,
+		s.provisioningDeduplication.window,
+	)
+	if err != nil {
+		return false, false, err
+	}
+	return claimed, existingFingerprint == fingerprint, nil
+}
+
+// releaseProvisioningRequest gives up the given instance's claim on
+// provisioning, if requests are deduplicated, so that a request that failed
+// can be retried. Failure to do so is logged, but isn't otherwise treated as
+// an error.
+func (s *server) releaseProvisioningRequest(
+	instanceID string,
+	logFields log.Fields,
+) {
+	if !s.provisioningDeduplication.isEnabled() {
+		return
+	}
+	if err := s.store.ReleaseProvisioningRequest(instanceID); err != nil {
+		log.WithFields(logFields).WithField("error", err).Error(
+			"provisioning error: error releasing provisioning request",
+		)
+	}
+}
+
 func (s *server) isParentProvisioning(instance service.Instance) (bool, error) {
 	//No parent, so no need to wait
 	if instance.ParentAlias == "" {
@@ -519,10 +1021,89 @@ func (s *server) validateLocation(svc service.Service, location string) error {
 				fmt.Sprintf(`invalid location: "%s"`, location),
 			)
 		}
+		if location != "" && !svc.IsAvailableInLocation(location) {
+			return service.NewValidationError(
+				"location",
+				fmt.Sprintf(
+					`service "%s" is not available in location "%s"`,
+					svc.GetName(),
+					location,
+				),
+			)
+		}
 	}
 	return nil
 }
 
+// getCloneSource retrieves the instance that a new instance of the given
+// service is to be cloned from and verifies that such a clone is possible
+func (s *server) getCloneSource(
+	svc service.Service,
+	cloneFrom string,
+	cloneData bool,
+) (service.Instance, error) {
+	if !svc.IsCloneable() {
+		return service.Instance{}, service.NewValidationError(
+			"cloneFrom",
+			fmt.Sprintf(`service "%s" does not support cloning`, svc.GetName()),
+		)
+	}
+	if _, ok := svc.GetServiceManager().(service.DataCopier); cloneData && !ok {
+		return service.Instance{}, service.NewValidationError(
+			"cloneData",
+			fmt.Sprintf(
+				`service "%s" does not support copying data into clones`,
+				svc.GetName(),
+			),
+		)
+	}
+	source, ok, err := s.store.GetInstance(cloneFrom)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"cloneFrom": cloneFrom,
+			"error":     err,
+		}).Error("pre-provisioning error: error retrieving clone source instance")
+		return source, err
+	}
+	if !ok {
+		return source, service.NewValidationError(
+			"cloneFrom",
+			fmt.Sprintf(`instance "%s" does not exist`, cloneFrom),
+		)
+	}
+	if source.ServiceID != svc.GetID() {
+		return source, service.NewValidationError(
+			"cloneFrom",
+			fmt.Sprintf(
+				`instance "%s" is not an instance of service "%s"`,
+				cloneFrom,
+				svc.GetName(),
+			),
+		)
+	}
+	if source.Status != service.InstanceStateProvisioned {
+		return source, service.NewValidationError(
+			"cloneFrom",
+			fmt.Sprintf(`instance "%s" is not fully provisioned`, cloneFrom),
+		)
+	}
+	return source, nil
+}
+
+// copyProvisioningParameters deep copies the src provisioning parameters into
+// dst by way of their JSON representation-- the same representation in which
+// they are persisted
+func copyProvisioningParameters(
+	src service.ProvisioningParameters,
+	dst service.ProvisioningParameters,
+) error {
+	jsonBytes, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(jsonBytes, dst)
+}
+
 func (s *server) validateAlias(svc service.Service, alias string) error {
 	if svc.GetChildServiceID() != "" && alias == "" {
 		return service.NewValidationError(
@@ -558,7 +1139,9 @@ func (s *server) handlePossibleValidationError(
 		log.WithFields(logFields).Debug(
 			"bad provisioning request: validation error",
 		)
-		response := generateValidationFailedResponse(validationErr)
+		response := generateValidationFailedResponse(
+			s.redactValidationError(validationErr),
+		)
 		s.writeResponse(w, http.StatusBadRequest, response)
 		return
 	}
@@ -581,3 +1164,12 @@ func (s *server) getResourceGroup(resourceGroup string) string {
 	}
 	return uuid.NewV4().String()
 }
+
+// areTagsEqual returns a bool indicating whether two sets of tags are the
+// same. No tags and an empty set of tags are considered the same.
+func areTagsEqual(a map[string]string, b map[string]string) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -2,13 +2,18 @@ package api
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"regexp"
 	"testing"
+	"time"
 
+	"github.com/Azure/open-service-broker-azure/pkg/async"
 	fakeAsync "github.com/Azure/open-service-broker-azure/pkg/async/fake"
+	"github.com/Azure/open-service-broker-azure/pkg/redaction"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
 	"github.com/stretchr/testify/assert"
@@ -294,6 +299,68 @@ func TestModuleSpecificValidationFails(t *testing.T) {
 	assert.Equal(t, responseError, rr.Body.Bytes())
 }
 
+func TestLocationSpecificValidationFails(t *testing.T) {
+	s, m, err := getTestServer("", "")
+	assert.Nil(t, err)
+	var validatedLocation string
+	fooError := service.NewValidationError("foo", "bar")
+	m.ServiceManager.LocationValidationBehavior =
+		func(_ service.ProvisioningParameters, location string) error {
+			validatedLocation = location
+			return fooError
+		}
+	req, err := getProvisionRequest(
+		getDisposableInstanceID(),
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&ProvisioningRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+			Parameters: map[string]interface{}{
+				"location": "eastus",
+			},
+		},
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "eastus", validatedLocation)
+	assert.Empty(t, s.asyncEngine.(*fakeAsync.Engine).SubmittedTasks)
+	responseError := generateValidationFailedResponse(fooError)
+	assert.Equal(t, responseError, rr.Body.Bytes())
+}
+
+func TestModuleSpecificValidationFailureIsRedacted(t *testing.T) {
+	s, m, err := getTestServer("eastus", "")
+	assert.Nil(t, err)
+	s.responseRedactionMode = redaction.ModePartial
+	m.ServiceManager.ProvisioningValidationBehavior =
+		func(service.ProvisioningParameters) error {
+			return service.NewValidationError(
+				"connectionString",
+				`invalid connectionString: "Server=db;Password=hunter2hunter2"`,
+			)
+		}
+	req, err := getProvisionRequest(
+		getDisposableInstanceID(),
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&ProvisioningRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+		},
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "hunter2")
+	assert.Contains(t, rr.Body.String(), "Password=****ter2")
+}
+
 func TestKickOffNewAsyncProvisioning(t *testing.T) {
 	s, m, err := getTestServer("", "")
 	assert.Nil(t, err)
@@ -329,6 +396,927 @@ func TestKickOffNewAsyncProvisioning(t *testing.T) {
 	assert.Equal(t, responseProvisioningAccepted, rr.Body.Bytes())
 }
 
+func TestProvisioningDuplicateRequestIsCollapsed(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	s.provisioningDeduplication, err = NewProvisioningDeduplication(
+		time.Minute,
+		DefaultFingerprintFields,
+	)
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	provisioningRequest := &ProvisioningRequest{
+		ServiceID: fake.ServiceID,
+		PlanID:    fake.StandardPlanID,
+		Parameters: map[string]interface{}{
+			"location": "eastus",
+		},
+	}
+	// Simulate an identical request that is being handled concurrently and
+	// hasn't yet recorded the new instance
+	claimed, _, err := s.claimProvisioningRequest(instanceID, provisioningRequest)
+	assert.Nil(t, err)
+	assert.True(t, claimed)
+	req, err := getProvisionRequest(
+		instanceID,
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		provisioningRequest,
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	assert.Empty(t, s.asyncEngine.(*fakeAsync.Engine).SubmittedTasks)
+	_, ok, err := s.store.GetInstance(instanceID)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
+
+func TestProvisioningRequestIsReleasedIfTaskCantBeSubmitted(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	s.provisioningDeduplication, err = NewProvisioningDeduplication(
+		time.Minute,
+		DefaultFingerprintFields,
+	)
+	assert.Nil(t, err)
+	s.asyncEngine = &failingAsyncEngine{
+		Engine:          s.asyncEngine.(*fakeAsync.Engine),
+		failingJobNames: map[string]bool{"executeProvisioningStep": true},
+	}
+	instanceID := getDisposableInstanceID()
+	provisioningRequest := &ProvisioningRequest{
+		ServiceID: fake.ServiceID,
+		PlanID:    fake.StandardPlanID,
+		Parameters: map[string]interface{}{
+			"location": "eastus",
+		},
+	}
+	req, err := getProvisionRequest(
+		instanceID,
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		provisioningRequest,
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	// The request may be retried without being mistaken for a duplicate of the
+	// one that failed
+	claimed, _, err := s.claimProvisioningRequest(instanceID, provisioningRequest)
+	assert.Nil(t, err)
+	assert.True(t, claimed)
+}
+
+func TestProvisioningChangedRequestWithinDeduplicationWindowConflicts(
+	t *testing.T,
+) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	s.provisioningDeduplication, err = NewProvisioningDeduplication(
+		time.Minute,
+		DefaultFingerprintFields,
+	)
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	claimed, _, err := s.claimProvisioningRequest(
+		instanceID,
+		&ProvisioningRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+			Parameters: map[string]interface{}{
+				"location": "eastus",
+			},
+		},
+	)
+	assert.Nil(t, err)
+	assert.True(t, claimed)
+	req, err := getProvisionRequest(
+		instanceID,
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&ProvisioningRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+			Parameters: map[string]interface{}{
+				"location": "westus",
+			},
+		},
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Empty(t, s.asyncEngine.(*fakeAsync.Engine).SubmittedTasks)
+}
+
+func TestProvisioningWithDeduplicationClaimsRequest(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	s.provisioningDeduplication, err = NewProvisioningDeduplication(
+		time.Minute,
+		DefaultFingerprintFields,
+	)
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	provisioningRequest := &ProvisioningRequest{
+		ServiceID: fake.ServiceID,
+		PlanID:    fake.StandardPlanID,
+		Parameters: map[string]interface{}{
+			"location": "eastus",
+		},
+	}
+	req, err := getProvisionRequest(
+		instanceID,
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		provisioningRequest,
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	assert.Len(t, s.asyncEngine.(*fakeAsync.Engine).SubmittedTasks, 1)
+	// The request has been recorded, so a concurrent duplicate would be
+	// collapsed
+	claimed, duplicate, err :=
+		s.claimProvisioningRequest(instanceID, provisioningRequest)
+	assert.Nil(t, err)
+	assert.False(t, claimed)
+	assert.True(t, duplicate)
+}
+
+func TestProvisioningRecordsOrganizationGUID(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	req, err := getProvisionRequest(
+		instanceID,
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&ProvisioningRequest{
+			ServiceID:        fake.ServiceID,
+			PlanID:           fake.StandardPlanID,
+			OrganizationGUID: "deprecated-org-guid",
+			Context: &ProvisioningContext{
+				Platform:         "cloudfoundry",
+				OrganizationGUID: "org-guid",
+			},
+			Parameters: map[string]interface{}{
+				"location": "eastus",
+			},
+		},
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	instance, ok, err := s.store.GetInstance(instanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "org-guid", instance.OrganizationGUID)
+	// The first provisioning task is attributed to the organization
+	e := s.asyncEngine.(*fakeAsync.Engine)
+	assert.Equal(t, 1, len(e.SubmittedTasks))
+	for _, task := range e.SubmittedTasks {
+		assert.Equal(t, "org-guid", task.GetTenant())
+	}
+}
+
+func TestProvisioningResolvesParameterTemplates(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	req, err := getProvisionRequest(
+		instanceID,
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&ProvisioningRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+			Context: &ProvisioningContext{
+				Platform:  "kubernetes",
+				Namespace: "team-a",
+			},
+			Parameters: map[string]interface{}{
+				"location":               "eastus",
+				"resourceGroup":          "{{.Context.Namespace}}-rg",
+				"someParameter":          "{{.Context.Namespace}}",
+				"someTemplatedParameter": "{{ .Context.Namespace }}-db",
+			},
+		},
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	instance, ok, err := s.store.GetInstance(instanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "team-a-rg", instance.ResourceGroup)
+	pp := instance.ProvisioningParameters.(*fake.ProvisioningParameters)
+	assert.Equal(t, "team-a-db", pp.SomeTemplatedParameter)
+	// Parameters that aren't templatable are left as they are
+	assert.Equal(t, "{{.Context.Namespace}}", pp.SomeParameter)
+}
+
+func TestProvisioningWithUnknownTemplateContextValueFails(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	req, err := getProvisionRequest(
+		getDisposableInstanceID(),
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&ProvisioningRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+			Context: &ProvisioningContext{
+				Platform: "cloudfoundry",
+			},
+			Parameters: map[string]interface{}{
+				"location":               "eastus",
+				"someTemplatedParameter": "{{.Context.Namespace}}-db",
+			},
+		},
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Namespace")
+}
+
+func TestProvisioningAppliesMetadataTags(t *testing.T) {
+	s, _, err := getTestServer("eastus", "")
+	assert.Nil(t, err)
+	s.metadataTagging, err = service.NewMetadataTagging(
+		[]service.TagMetadata{
+			service.TagMetadataOrganizationGUID,
+			service.TagMetadataSpaceGUID,
+			service.TagMetadataPlan,
+			service.TagMetadataBrokerID,
+		},
+		"broker",
+	)
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	provisioningRequest := &ProvisioningRequest{
+		ServiceID: fake.ServiceID,
+		PlanID:    fake.StandardPlanID,
+		Context: &ProvisioningContext{
+			Platform:         "cloudfoundry",
+			OrganizationGUID: "org-guid",
+			SpaceGUID:        "space-guid",
+		},
+		Parameters: map[string]interface{}{
+			"tags": map[string]interface{}{
+				"foo": "bar",
+			},
+		},
+	}
+	req, err := getProvisionRequest(
+		instanceID,
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		provisioningRequest,
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	instance, ok, err := s.store.GetInstance(instanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(
+		t,
+		map[string]string{
+			"foo":                    "bar",
+			"osba-organization-guid": "org-guid",
+			"osba-space-guid":        "space-guid",
+			"osba-plan":              "standard",
+			"osba-broker-id":         "broker",
+		},
+		instance.Tags,
+	)
+	// Metadata tags don't stop an identical request from being recognized as
+	// such
+	req, err = getProvisionRequest(
+		instanceID,
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		provisioningRequest,
+	)
+	assert.Nil(t, err)
+	rr = httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+}
+
+func TestProvisioningWithReservedTagFails(t *testing.T) {
+	s, _, err := getTestServer("eastus", "")
+	assert.Nil(t, err)
+	req, err := getProvisionRequest(
+		getDisposableInstanceID(),
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&ProvisioningRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+			Parameters: map[string]interface{}{
+				"tags": map[string]interface{}{
+					"osba-plan": "free",
+				},
+			},
+		},
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestProvisioningRecordsParametersAudit(t *testing.T) {
+	s, m, err := getTestServer("", "test-rg")
+	assert.Nil(t, err)
+	s.auditProvisioningParameters = true
+	m.ServiceManager.ProvisioningDefaultingBehavior =
+		func(pp service.ProvisioningParameters) error {
+			fpp := pp.(*fake.ProvisioningParameters)
+			if fpp.SomeParameter == "" {
+				fpp.SomeParameter = "default"
+			}
+			return nil
+		}
+	instanceID := getDisposableInstanceID()
+	req, err := getProvisionRequest(
+		instanceID,
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&ProvisioningRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+			Parameters: map[string]interface{}{
+				"location":            "eastus",
+				"someSecretParameter": "shh",
+			},
+		},
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	instance, ok, err := s.store.GetInstance(instanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.NotNil(t, instance.ProvisioningAudit)
+	assert.Equal(
+		t,
+		map[string]interface{}{
+			"location":            "eastus",
+			"someSecretParameter": service.RedactedValue,
+		},
+		instance.ProvisioningAudit.RequestedParameters,
+	)
+	assert.Equal(
+		t,
+		map[string]interface{}{
+			"location":            "eastus",
+			"resourceGroup":       "test-rg",
+			"tags":                map[string]interface{}{},
+			"someParameter":       "default",
+			"someSecretParameter": service.RedactedValue,
+		},
+		instance.ProvisioningAudit.EffectiveParameters,
+	)
+	// Defaults applied for the audit aren't persisted with the instance's
+	// parameters
+	assert.Equal(
+		t,
+		"",
+		instance.ProvisioningParameters.(*fake.ProvisioningParameters).
+			SomeParameter,
+	)
+	assert.Equal(
+		t,
+		"shh",
+		instance.ProvisioningParameters.(*fake.ProvisioningParameters).
+			SomeSecretParameter,
+	)
+}
+
+func TestProvisioningDoesNotRecordParametersAuditByDefault(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	req, err := getProvisionRequest(
+		instanceID,
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&ProvisioningRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+			Parameters: map[string]interface{}{
+				"location": "eastus",
+			},
+		},
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	instance, ok, err := s.store.GetInstance(instanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Nil(t, instance.ProvisioningAudit)
+}
+
+func TestProvisioningEstimatesCost(t *testing.T) {
+	s, m, err := getTestServer("eastus", "")
+	assert.Nil(t, err)
+	s.pricingTable, err = service.NewPricingTable(
+		"USD",
+		[]byte(`[
+			{"sku": "small", "monthlyCost": 10},
+			{"sku": "small", "region": "eastus", "monthlyCost": 12},
+			{"sku": "disk", "monthlyCost": 0.5}
+		]`),
+	)
+	assert.Nil(t, err)
+	m.ServiceManager.SKUMappingBehavior = func(
+		service.Plan,
+		service.ProvisioningParameters,
+	) ([]service.SKUUsage, error) {
+		return []service.SKUUsage{
+			{
+				SKU:      "small",
+				Quantity: 2,
+			},
+			{
+				SKU:      "disk",
+				Quantity: 64,
+			},
+		}, nil
+	}
+	instanceID := getDisposableInstanceID()
+	req, err := getProvisionRequest(
+		instanceID,
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&ProvisioningRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+		},
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	expectedEstimate := &service.CostEstimate{
+		MonthlyCost: 56,
+		Currency:    "USD",
+		Items: []service.CostEstimateItem{
+			{
+				SKU:         "small",
+				Quantity:    2,
+				MonthlyCost: 24,
+			},
+			{
+				SKU:         "disk",
+				Quantity:    64,
+				MonthlyCost: 32,
+			},
+		},
+	}
+	response := &provisioningResponse{}
+	err = json.Unmarshal(rr.Body.Bytes(), response)
+	assert.Nil(t, err)
+	assert.Equal(t, OperationProvisioning, response.Operation)
+	assert.Equal(t, expectedEstimate, response.CostEstimate)
+	instance, ok, err := s.store.GetInstance(instanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, expectedEstimate, instance.CostEstimate)
+}
+
+func TestProvisioningOmitsIncompleteCostEstimate(t *testing.T) {
+	s, _, err := getTestServer("eastus", "")
+	assert.Nil(t, err)
+	// The fake service is priced by plan, which is absent from this table
+	s.pricingTable, err = service.NewPricingTable(
+		"USD",
+		[]byte(`[{"sku": "small", "monthlyCost": 10}]`),
+	)
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	req, err := getProvisionRequest(
+		instanceID,
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&ProvisioningRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+		},
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	assert.Equal(t, responseProvisioningAccepted, rr.Body.Bytes())
+	instance, ok, err := s.store.GetInstance(instanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Nil(t, instance.CostEstimate)
+}
+
+func TestProvisioningWithDeferredActivation(t *testing.T) {
+	s, _, err := getTestServer("eastus", "")
+	assert.Nil(t, err)
+	activateAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	instanceID := getDisposableInstanceID()
+	req, err := getProvisionRequest(
+		instanceID,
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&ProvisioningRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+			Parameters: map[string]interface{}{
+				"activateAt": activateAt.Format(time.RFC3339),
+			},
+		},
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	instance, ok, err := s.store.GetInstance(instanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.True(t, instance.IsPendingActivation())
+	assert.True(t, activateAt.Equal(instance.Activation.ActivateAt))
+}
+
+func TestProvisioningWithPastActivateAtFails(t *testing.T) {
+	s, _, err := getTestServer("eastus", "")
+	assert.Nil(t, err)
+	req, err := getProvisionRequest(
+		getDisposableInstanceID(),
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&ProvisioningRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+			Parameters: map[string]interface{}{
+				"activateAt": "2018-01-02T03:04:05Z",
+			},
+		},
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestProvisioningSandboxInstanceSchedulesExpiry(t *testing.T) {
+	s, _, err := getTestServer("eastus", "")
+	assert.Nil(t, err)
+	s.expiryPolicy, err = service.NewExpiryPolicy(
+		map[string]time.Duration{"fake/standard": 8 * time.Hour},
+		24*time.Hour,
+		time.Hour,
+	)
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	req, err := getProvisionRequest(
+		instanceID,
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&ProvisioningRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+			Parameters: map[string]interface{}{
+				"sandbox": true,
+			},
+		},
+	)
+	assert.Nil(t, err)
+	e := s.asyncEngine.(*fakeAsync.Engine)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	instance, ok, err := s.store.GetInstance(instanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.True(t, instance.IsSandbox())
+	assert.Equal(
+		t,
+		8*time.Hour,
+		instance.Expiry.ExpiresAt.Sub(instance.Created),
+	)
+	assert.Equal(
+		t,
+		24*time.Hour,
+		instance.Expiry.MaxExpiresAt.Sub(instance.Created),
+	)
+	executeTimes := map[string]time.Time{}
+	for _, task := range e.SubmittedTasks {
+		if task.GetExecuteTime() != nil {
+			executeTimes[task.GetJobName()] = *task.GetExecuteTime()
+		}
+	}
+	assert.True(
+		t,
+		instance.Expiry.ExpiresAt.Equal(executeTimes["expireInstance"]),
+	)
+	assert.True(
+		t,
+		instance.Expiry.ExpiresAt.Add(-time.Hour).Equal(
+			executeTimes["warnOfInstanceExpiry"],
+		),
+	)
+}
+
+// failingAsyncEngine is an async.Engine that fails to submit tasks for the
+// named jobs
+type failingAsyncEngine struct {
+	*fakeAsync.Engine
+	failingJobNames map[string]bool
+}
+
+func (f *failingAsyncEngine) SubmitTask(task async.Task) error {
+	if f.failingJobNames[task.GetJobName()] {
+		return errors.New("error submitting task")
+	}
+	return f.Engine.SubmitTask(task)
+}
+
+func TestProvisioningSandboxInstanceFailsIfExpiryCantBeScheduled(
+	t *testing.T,
+) {
+	s, _, err := getTestServer("eastus", "")
+	assert.Nil(t, err)
+	s.expiryPolicy, err = service.NewExpiryPolicy(
+		map[string]time.Duration{"fake/standard": 8 * time.Hour},
+		24*time.Hour,
+		0,
+	)
+	assert.Nil(t, err)
+	e := &failingAsyncEngine{
+		Engine:          s.asyncEngine.(*fakeAsync.Engine),
+		failingJobNames: map[string]bool{"expireInstance": true},
+	}
+	s.asyncEngine = e
+	instanceID := getDisposableInstanceID()
+	req, err := getProvisionRequest(
+		instanceID,
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&ProvisioningRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+			Parameters: map[string]interface{}{
+				"sandbox": true,
+			},
+		},
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	// Provisioning never started, and the request may be retried
+	assert.Empty(t, e.SubmittedTasks)
+	_, ok, err := s.store.GetInstance(instanceID)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
+
+func TestProvisioningSandboxInstanceOfIneligiblePlanFails(t *testing.T) {
+	s, _, err := getTestServer("eastus", "")
+	assert.Nil(t, err)
+	req, err := getProvisionRequest(
+		getDisposableInstanceID(),
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&ProvisioningRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+			Parameters: map[string]interface{}{
+				"sandbox": true,
+			},
+		},
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestProvisioningCloneFromExistingInstance(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	sourceInstanceID := getDisposableInstanceID()
+	err = s.store.WriteInstance(service.Instance{
+		InstanceID: sourceInstanceID,
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateProvisioned,
+		Location:   "westus",
+		ProvisioningParameters: &fake.ProvisioningParameters{
+			SomeParameter: "foo",
+		},
+	})
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	req, err := getProvisionRequest(
+		instanceID,
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&ProvisioningRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+			Parameters: map[string]interface{}{
+				"cloneFrom": sourceInstanceID,
+			},
+		},
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	instance, ok, err := s.store.GetInstance(instanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "westus", instance.Location)
+	pp, ok := instance.ProvisioningParameters.(*fake.ProvisioningParameters)
+	assert.True(t, ok)
+	assert.Equal(t, "foo", pp.SomeParameter)
+}
+
+func TestProvisioningCloneWithOverrides(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	sourceInstanceID := getDisposableInstanceID()
+	err = s.store.WriteInstance(service.Instance{
+		InstanceID: sourceInstanceID,
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateProvisioned,
+		Location:   "westus",
+		ProvisioningParameters: &fake.ProvisioningParameters{
+			SomeParameter: "foo",
+		},
+	})
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	req, err := getProvisionRequest(
+		instanceID,
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&ProvisioningRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+			Parameters: map[string]interface{}{
+				"cloneFrom":     sourceInstanceID,
+				"location":      "eastus",
+				"someParameter": "bar",
+			},
+		},
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	instance, ok, err := s.store.GetInstance(instanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "eastus", instance.Location)
+	pp, ok := instance.ProvisioningParameters.(*fake.ProvisioningParameters)
+	assert.True(t, ok)
+	assert.Equal(t, "bar", pp.SomeParameter)
+	// The source instance must not have been altered
+	source, ok, err := s.store.GetInstance(sourceInstanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	sourcePP, ok := source.ProvisioningParameters.(*fake.ProvisioningParameters)
+	assert.True(t, ok)
+	assert.Equal(t, "foo", sourcePP.SomeParameter)
+}
+
+func TestProvisioningCloneFromNonexistentInstance(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	sourceInstanceID := getDisposableInstanceID()
+	req, err := getProvisionRequest(
+		getDisposableInstanceID(),
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&ProvisioningRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+			Parameters: map[string]interface{}{
+				"cloneFrom": sourceInstanceID,
+			},
+		},
+	)
+	assert.Nil(t, err)
+	e := s.asyncEngine.(*fakeAsync.Engine)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Empty(t, e.SubmittedTasks)
+	cloneError := service.NewValidationError(
+		"cloneFrom",
+		fmt.Sprintf(`instance "%s" does not exist`, sourceInstanceID),
+	)
+	responseError := generateValidationFailedResponse(cloneError)
+	assert.Equal(t, responseError, rr.Body.Bytes())
+}
+
+func TestProvisioningCloneFromNotFullyProvisionedInstance(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	sourceInstanceID := getDisposableInstanceID()
+	err = s.store.WriteInstance(service.Instance{
+		InstanceID: sourceInstanceID,
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateProvisioning,
+	})
+	assert.Nil(t, err)
+	req, err := getProvisionRequest(
+		getDisposableInstanceID(),
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&ProvisioningRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+			Parameters: map[string]interface{}{
+				"cloneFrom": sourceInstanceID,
+			},
+		},
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestProvisioningCloneDataUnsupported(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	sourceInstanceID := getDisposableInstanceID()
+	err = s.store.WriteInstance(service.Instance{
+		InstanceID: sourceInstanceID,
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateProvisioned,
+	})
+	assert.Nil(t, err)
+	req, err := getProvisionRequest(
+		getDisposableInstanceID(),
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&ProvisioningRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+			Parameters: map[string]interface{}{
+				"cloneFrom": sourceInstanceID,
+				"cloneData": true,
+			},
+		},
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
 func TestGetLocation(t *testing.T) {
 	const defaultLocation = "default-location"
 	const location = "test-location"
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"encoding/json"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// getProvisioningAudit returns a record of the parameters that were requested
+// for the given (new) instance alongside those it will effectively be
+// provisioned with. The effective parameters include the instance's location,
+// resource group, tags, and aliases as resolved by the broker, as well as any
+// defaults the service manager would substitute for unspecified
+// service-specific parameters.
+func getProvisioningAudit(
+	serviceManager service.ServiceManager,
+	requestedParams map[string]interface{},
+	instance service.Instance,
+) (*service.ProvisioningAudit, error) {
+	// Work on a copy so that the defaults applied here don't make their way into
+	// the instance's persisted parameters
+	pp := serviceManager.GetEmptyProvisioningParameters()
+	if err := copyProvisioningParameters(
+		instance.ProvisioningParameters,
+		pp,
+	); err != nil {
+		return nil, err
+	}
+	if defaulter, ok :=
+		serviceManager.(service.ProvisioningParametersDefaulter); ok {
+		if err := defaulter.ApplyProvisioningParametersDefaults(pp); err != nil {
+			return nil, err
+		}
+	}
+	jsonBytes, err := json.Marshal(pp)
+	if err != nil {
+		return nil, err
+	}
+	effectiveParams := map[string]interface{}{}
+	if err := json.Unmarshal(jsonBytes, &effectiveParams); err != nil {
+		return nil, err
+	}
+	effectiveParams["location"] = instance.Location
+	effectiveParams["resourceGroup"] = instance.ResourceGroup
+	tags := map[string]interface{}{}
+	for k, v := range instance.Tags {
+		tags[k] = v
+	}
+	effectiveParams["tags"] = tags
+	if instance.Alias != "" {
+		effectiveParams["alias"] = instance.Alias
+	}
+	if instance.ParentAlias != "" {
+		effectiveParams["parentAlias"] = instance.ParentAlias
+	}
+	if len(instance.DependsOn) > 0 {
+		effectiveParams["dependsOn"] = instance.DependsOn
+	}
+	if requestedParams == nil {
+		requestedParams = map[string]interface{}{}
+	}
+	return &service.ProvisioningAudit{
+		RequestedParameters: service.RedactProvisioningParameters(
+			requestedParams,
+			pp,
+		),
+		EffectiveParameters: service.RedactProvisioningParameters(
+			effectiveParams,
+			pp,
+		),
+	}, nil
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	log "github.com/Sirupsen/logrus"
+)
+
+// reserveProvisioningCapacity reserves, for a new instance of the given
+// service, either one of the slots for concurrent provisions shared by its
+// module's services or, if the module is at its cap and permits queueing, a
+// place in the queue for one. It returns a bool indicating whether a
+// reservation was made, along with the instance's position in the queue,
+// which is zero if it holds a slot. Services of modules without a cap are
+// always admitted.
+func (s *server) reserveProvisioningCapacity(
+	svc service.Service,
+	instanceID string,
+) (bool, int64, error) {
+	limits, ok := s.provisioningCapacity.GetLimits(svc)
+	if !ok {
+		return true, 0, nil
+	}
+	return s.store.ReserveProvisioningCapacity(
+		limits.Pool,
+		instanceID,
+		limits.MaxConcurrentProvisions,
+		limits.MaxQueueDepth,
+	)
+}
+
+// releaseProvisioningCapacity gives up any capacity reserved for an instance
+// whose provisioning was never started. Failure to do so is logged, but isn't
+// otherwise treated as an error.
+func (s *server) releaseProvisioningCapacity(
+	svc service.Service,
+	instanceID string,
+) {
+	limits, ok := s.provisioningCapacity.GetLimits(svc)
+	if !ok {
+		return
+	}
+	if err := s.store.ReleaseProvisioningCapacity(
+		limits.Pool,
+		instanceID,
+	); err != nil {
+		log.WithFields(log.Fields{
+			"instanceID": instanceID,
+			"pool":       limits.Pool,
+			"error":      err,
+		}).Error("provisioning error: error releasing provisioning capacity")
+	}
+}
+
+// getProvisioningQueuePosition returns a queued instance's position in the
+// queue for its module's provisioning capacity
+func (s *server) getProvisioningQueuePosition(
+	instance service.Instance,
+) (int64, error) {
+	limits, ok := s.provisioningCapacity.GetLimits(instance.Service)
+	if !ok {
+		return 0, nil
+	}
+	return s.store.GetProvisioningQueuePosition(limits.Pool, instance.InstanceID)
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProvisioningQueuesWhileModuleIsAtCapacity(t *testing.T) {
+	s, _, err := getTestServer("eastus", "")
+	assert.Nil(t, err)
+	s.provisioningCapacity = getTestProvisioningCapacityPolicy(1)
+	firstInstanceID := getDisposableInstanceID()
+	rr := provisionFakeInstance(t, s, firstInstanceID)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	instance, ok, err := s.store.GetInstance(firstInstanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, service.InstanceStateProvisioning, instance.Status)
+	secondInstanceID := getDisposableInstanceID()
+	rr = provisionFakeInstance(t, s, secondInstanceID)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	instance, ok, err = s.store.GetInstance(secondInstanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, service.InstanceStateQueued, instance.Status)
+	// The queue holds only one request
+	rr = provisionFakeInstance(t, s, getDisposableInstanceID())
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Equal(t, responseProvisioningCapacityExhausted, rr.Body.Bytes())
+}
+
+func TestProvisioningRefusedWhileModuleWithoutQueueIsAtCapacity(t *testing.T) {
+	s, _, err := getTestServer("eastus", "")
+	assert.Nil(t, err)
+	s.provisioningCapacity = getTestProvisioningCapacityPolicy(0)
+	rr := provisionFakeInstance(t, s, getDisposableInstanceID())
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	instanceID := getDisposableInstanceID()
+	rr = provisionFakeInstance(t, s, instanceID)
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	_, ok, err := s.store.GetInstance(instanceID)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
+
+func TestPollingWithInstanceQueued(t *testing.T) {
+	s, _, err := getTestServer("eastus", "")
+	assert.Nil(t, err)
+	s.provisioningCapacity = getTestProvisioningCapacityPolicy(2)
+	provisionFakeInstance(t, s, getDisposableInstanceID())
+	provisionFakeInstance(t, s, getDisposableInstanceID())
+	instanceID := getDisposableInstanceID()
+	provisionFakeInstance(t, s, instanceID)
+	req, err := getPollingRequest(instanceID, OperationProvisioning)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	response := provisioningQueuedResponse{}
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, OperationStateInProgress, response.State)
+	assert.Equal(t, int64(2), response.QueuePosition)
+}
+
+// getTestProvisioningCapacityPolicy returns a policy that permits one instance
+// of the fake service to be provisioning at once, and that queues up to the
+// given number of requests beyond that
+func getTestProvisioningCapacityPolicy(
+	maxQueueDepth int,
+) service.ProvisioningCapacityPolicy {
+	return service.NewProvisioningCapacityPolicy(
+		map[string]service.ProvisioningCapacityLimits{
+			fake.ServiceID: {
+				Pool:                    "fake",
+				MaxConcurrentProvisions: 1,
+				MaxQueueDepth:           maxQueueDepth,
+			},
+		},
+	)
+}
+
+func provisionFakeInstance(
+	t *testing.T,
+	s *server,
+	instanceID string,
+) *httptest.ResponseRecorder {
+	req, err := getProvisionRequest(
+		instanceID,
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&ProvisioningRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+		},
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	return rr
+}
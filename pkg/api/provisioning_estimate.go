@@ -0,0 +1,90 @@
+package api
+
+import (
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	log "github.com/Sirupsen/logrus"
+)
+
+// provisioningEstimate describes the timing of an in-progress provisioning
+// operation. EstimatedCompletion is zero if the duration of any of the
+// operation's steps can't be estimated.
+type provisioningEstimate struct {
+	Elapsed             time.Duration
+	EstimatedCompletion time.Time
+	// Overdue indicates whether the operation has run for longer than its
+	// estimated duration multiplied by the broker's overdue factor
+	Overdue bool
+}
+
+// getProvisioningEstimate estimates, as of the given time, when the given
+// instance's in-progress provisioning operation will complete. Each step's
+// duration is estimated from the average observed for the instance's plan or,
+// for steps that have yet to be observed, from the module's own estimate. It
+// returns nil if the operation's start time isn't known.
+func (s *server) getProvisioningEstimate(
+	instance service.Instance,
+	now time.Time,
+) *provisioningEstimate {
+	started := instance.GetProvisioningStarted()
+	if started.IsZero() {
+		return nil
+	}
+	estimate := &provisioningEstimate{
+		Elapsed: now.Sub(started),
+	}
+	logFields := log.Fields{
+		"instanceID": instance.InstanceID,
+	}
+	if instance.Service == nil || instance.Plan == nil {
+		return estimate
+	}
+	serviceManager := instance.Service.GetServiceManager()
+	progress := instance.ProvisioningProgress
+	if progress == nil {
+		// The first step has yet to complete, so the broker has recorded nothing
+		// of the operation's progress
+		provisioner, err := serviceManager.GetProvisioner(instance.Plan)
+		if err != nil {
+			logFields["error"] = err
+			log.WithFields(logFields).Error(
+				"polling error: error retrieving provisioner; omitting estimate",
+			)
+			return estimate
+		}
+		firstStepName, _ := provisioner.GetFirstStepName()
+		progress = &service.ProvisioningProgress{
+			Steps:              provisioner.GetStepNames(),
+			CurrentStep:        firstStepName,
+			CurrentStepStarted: started,
+		}
+	}
+	stepDurations, err := s.store.GetProvisioningStepDurations(instance.PlanID)
+	if err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error(
+			"polling error: error retrieving provisioning step durations; " +
+				"omitting estimate",
+		)
+		return estimate
+	}
+	if estimator, ok :=
+		serviceManager.(service.ProvisioningStepEstimator); ok {
+		for stepName, duration := range estimator.GetProvisioningStepEstimates(
+			instance.Plan,
+		) {
+			if _, observed := stepDurations[stepName]; !observed {
+				stepDurations[stepName] = duration
+			}
+		}
+	}
+	remaining, total, ok := progress.GetExpectedDurations(now, stepDurations)
+	if !ok {
+		return estimate
+	}
+	estimate.EstimatedCompletion = now.Add(remaining)
+	estimate.Overdue = s.provisioningOverdueFactor > 0 &&
+		float64(estimate.Elapsed) > float64(total)*s.provisioningOverdueFactor
+	return estimate
+}
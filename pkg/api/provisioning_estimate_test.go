@@ -0,0 +1,99 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPollingWithProvisioningEstimate(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	err = s.store.RecordProvisioningStepDuration(
+		fake.StandardPlanID,
+		"run",
+		10*time.Minute,
+	)
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	err = s.store.WriteInstance(service.Instance{
+		InstanceID: instanceID,
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateProvisioning,
+		Created:    time.Now().Add(-4 * time.Minute),
+	})
+	assert.Nil(t, err)
+	response := pollProvisioningEstimate(t, s, instanceID)
+	assert.Equal(t, OperationStateInProgress, response.State)
+	assert.Empty(t, response.Description)
+	assert.InDelta(t, 240, response.ElapsedSeconds, 5)
+	assert.NotNil(t, response.EstimatedCompletionTime)
+	assert.WithinDuration(
+		t,
+		time.Now().Add(6*time.Minute),
+		*response.EstimatedCompletionTime,
+		5*time.Second,
+	)
+}
+
+func TestPollingWithOverdueProvisioning(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	s.provisioningOverdueFactor = 1.5
+	err = s.store.RecordProvisioningStepDuration(
+		fake.StandardPlanID,
+		"run",
+		10*time.Minute,
+	)
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	err = s.store.WriteInstance(service.Instance{
+		InstanceID: instanceID,
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateProvisioning,
+		Created:    time.Now().Add(-20 * time.Minute),
+	})
+	assert.Nil(t, err)
+	response := pollProvisioningEstimate(t, s, instanceID)
+	assert.Contains(t, response.Description, "longer than expected")
+}
+
+func TestPollingWithUnestimatedProvisioningStep(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	err = s.store.WriteInstance(service.Instance{
+		InstanceID: instanceID,
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateProvisioning,
+		Created:    time.Now().Add(-time.Minute),
+	})
+	assert.Nil(t, err)
+	response := pollProvisioningEstimate(t, s, instanceID)
+	assert.InDelta(t, 60, response.ElapsedSeconds, 5)
+	assert.Nil(t, response.EstimatedCompletionTime)
+}
+
+func pollProvisioningEstimate(
+	t *testing.T,
+	s *server,
+	instanceID string,
+) provisioningInProgressResponse {
+	req, err := getPollingRequest(instanceID, OperationProvisioning)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	response := provisioningInProgressResponse{}
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	return response
+}
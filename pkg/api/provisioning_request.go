@@ -2,13 +2,36 @@ package api
 
 import (
 	"encoding/json"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
 // ProvisioningRequest represents a request to provision a service
 type ProvisioningRequest struct {
-	ServiceID  string                 `json:"service_id"`
-	PlanID     string                 `json:"plan_id"`
-	Parameters map[string]interface{} `json:"parameters"`
+	ServiceID        string                 `json:"service_id"`
+	PlanID           string                 `json:"plan_id"`
+	OrganizationGUID string                 `json:"organization_guid,omitempty"`
+	SpaceGUID        string                 `json:"space_guid,omitempty"`
+	Context          *ProvisioningContext   `json:"context,omitempty"`
+	Parameters       map[string]interface{} `json:"parameters"`
+}
+
+// ProvisioningContext represents the platform-specific contextual information
+// that accompanies a request to provision a service. Cloud Foundry supplies
+// the organization and space fields; Kubernetes supplies the namespace and
+// cluster ID. Labels are supplied by platforms that label the instances they
+// request.
+type ProvisioningContext struct {
+	Platform         string            `json:"platform,omitempty"`
+	OrganizationGUID string            `json:"organization_guid,omitempty"`
+	OrganizationName string            `json:"organization_name,omitempty"`
+	SpaceGUID        string            `json:"space_guid,omitempty"`
+	SpaceName        string            `json:"space_name,omitempty"`
+	Namespace        string            `json:"namespace,omitempty"`
+	ClusterID        string            `json:"clusterid,omitempty"`
+	InstanceName     string            `json:"instance_name,omitempty"`
+	Labels           map[string]string `json:"labels,omitempty"`
 }
 
 // NewProvisioningRequestFromJSON returns a new ProvisioningRequest unmarshaled
@@ -29,3 +52,71 @@ func NewProvisioningRequestFromJSON(
 func (p *ProvisioningRequest) ToJSON() ([]byte, error) {
 	return json.Marshal(p)
 }
+
+// GetOrganizationGUID returns the GUID of the organization on whose behalf the
+// service is being provisioned. The GUID in the request's context, if any, is
+// preferred since the top-level field is deprecated by the OSB API.
+func (p *ProvisioningRequest) GetOrganizationGUID() string {
+	if p.Context != nil && p.Context.OrganizationGUID != "" {
+		return p.Context.OrganizationGUID
+	}
+	return p.OrganizationGUID
+}
+
+// GetSpaceGUID returns the GUID of the space on whose behalf the service is
+// being provisioned. As with the organization GUID, the GUID in the request's
+// context, if any, is preferred.
+func (p *ProvisioningRequest) GetSpaceGUID() string {
+	if p.Context != nil && p.Context.SpaceGUID != "" {
+		return p.Context.SpaceGUID
+	}
+	return p.SpaceGUID
+}
+
+// GetTemplateContext returns the contextual values that templated provisioning
+// parameters may reference, keyed by the names they are referenced by, e.g.
+// {{.Context.Namespace}}. Values the request didn't include are omitted.
+func (p *ProvisioningRequest) GetTemplateContext() service.TemplateContext {
+	values := map[string]string{
+		"OrganizationGUID": p.GetOrganizationGUID(),
+		"SpaceGUID":        p.GetSpaceGUID(),
+	}
+	if p.Context != nil {
+		values["Platform"] = p.Context.Platform
+		values["OrganizationName"] = p.Context.OrganizationName
+		values["SpaceName"] = p.Context.SpaceName
+		values["Namespace"] = p.Context.Namespace
+		values["ClusterID"] = p.Context.ClusterID
+		values["InstanceName"] = p.Context.InstanceName
+	}
+	templateContext := service.TemplateContext{}
+	for name, value := range values {
+		if value != "" {
+			templateContext[name] = value
+		}
+	}
+	return templateContext
+}
+
+// GetCostCenter returns the cost center to which the costs of the instance
+// being provisioned are charged back. It is the value of the tag with the
+// given name, if one was specified, or else that of the request context's
+// label of that name. Like Azure, it disregards the case of tag names. An
+// empty name means instances aren't attributed to cost centers.
+func (p *ProvisioningRequest) GetCostCenter(
+	label string,
+	tags map[string]string,
+) string {
+	if label == "" {
+		return ""
+	}
+	for name, value := range tags {
+		if strings.EqualFold(name, label) && value != "" {
+			return value
+		}
+	}
+	if p.Context != nil {
+		return p.Context.Labels[label]
+	}
+	return ""
+}
@@ -5,6 +5,7 @@ import (
 	"regexp"
 	"testing"
 
+	"github.com/Azure/open-service-broker-azure/pkg/service"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -52,3 +53,55 @@ func TestProvisioningRequestToJSON(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, testProvisioningRequestJSON, json)
 }
+
+func TestProvisioningRequestGetTemplateContext(t *testing.T) {
+	provisioningRequest := &ProvisioningRequest{
+		OrganizationGUID: "deprecated-org-guid",
+		SpaceGUID:        "deprecated-space-guid",
+		Context: &ProvisioningContext{
+			Platform:         "cloudfoundry",
+			OrganizationGUID: "org-guid",
+			OrganizationName: "org",
+		},
+	}
+	assert.Equal(
+		t,
+		service.TemplateContext{
+			"Platform":         "cloudfoundry",
+			"OrganizationGUID": "org-guid",
+			"OrganizationName": "org",
+			"SpaceGUID":        "deprecated-space-guid",
+		},
+		provisioningRequest.GetTemplateContext(),
+	)
+}
+
+func TestProvisioningRequestGetCostCenter(t *testing.T) {
+	provisioningRequest := &ProvisioningRequest{
+		Context: &ProvisioningContext{
+			Platform: "kubernetes",
+			Labels: map[string]string{
+				"cost-center": "context-cost-center",
+			},
+		},
+	}
+	assert.Equal(
+		t,
+		"tag-cost-center",
+		provisioningRequest.GetCostCenter(
+			"cost-center",
+			map[string]string{"Cost-Center": "tag-cost-center"},
+		),
+	)
+	assert.Equal(
+		t,
+		"context-cost-center",
+		provisioningRequest.GetCostCenter("cost-center", nil),
+	)
+	assert.Equal(t, "", provisioningRequest.GetCostCenter("", nil))
+	assert.Equal(
+		t,
+		"",
+		(&ProvisioningRequest{}).GetCostCenter("cost-center", nil),
+	)
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// ProvisioningSLAReportEntry represents provisioning SLA attainment for a
+// single plan. Attainment is the fraction of instances counted that were
+// provisioned within target; it is omitted if none were counted.
+type ProvisioningSLAReportEntry struct {
+	ServiceID     string   `json:"service_id"`
+	ServiceName   string   `json:"service_name"`
+	PlanID        string   `json:"plan_id"`
+	PlanName      string   `json:"plan_name"`
+	TargetSeconds float64  `json:"target_seconds,omitempty"`
+	WithinTarget  int64    `json:"within_target"`
+	Breached      int64    `json:"breached"`
+	Attainment    *float64 `json:"attainment,omitempty"`
+}
+
+// ProvisioningSLAReport represents the response to a request to fetch the
+// broker's provisioning SLA report. It includes every plan that currently has
+// a target, along with any other plan whose instances were counted while it
+// had one.
+type ProvisioningSLAReport struct {
+	Plans []ProvisioningSLAReportEntry `json:"plans"`
+}
+
+// GetProvisioningSLAReportFromJSON returns a new ProvisioningSLAReport
+// unmarshalled from the provided JSON []byte
+func GetProvisioningSLAReportFromJSON(
+	jsonBytes []byte,
+	report *ProvisioningSLAReport,
+) error {
+	return json.Unmarshal(jsonBytes, report)
+}
+
+// ToJSON returns a []byte containing a JSON representation of the provisioning
+// SLA report
+func (p *ProvisioningSLAReport) ToJSON() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+func (s *server) getProvisioningSLAReport(
+	w http.ResponseWriter,
+	_ *http.Request,
+) {
+	log.Debug("received request to fetch provisioning SLA report")
+
+	report, err := s.buildProvisioningSLAReport()
+	if err != nil {
+		log.WithField("error", err).Error(
+			"provisioning SLA report error: error building report",
+		)
+		s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
+		return
+	}
+	reportJSON, err := report.ToJSON()
+	if err != nil {
+		log.WithField("error", err).Error(
+			"provisioning SLA report error: error marshaling report",
+		)
+		s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
+		return
+	}
+	s.writeResponse(w, http.StatusOK, reportJSON)
+}
+
+// buildProvisioningSLAReport assembles a report of provisioning SLA attainment
+// for each plan in the catalog, in catalog order
+func (s *server) buildProvisioningSLAReport() (*ProvisioningSLAReport, error) {
+	outcomes, err := s.store.GetProvisioningSLAOutcomes()
+	if err != nil {
+		return nil, err
+	}
+	report := &ProvisioningSLAReport{
+		Plans: []ProvisioningSLAReportEntry{},
+	}
+	for _, svc := range s.catalog.GetServices() {
+		for _, plan := range svc.GetPlans() {
+			target, hasTarget := s.provisioningSLA.GetTarget(svc, plan)
+			planOutcomes, counted := outcomes[plan.GetID()]
+			if !hasTarget && !counted {
+				continue
+			}
+			entry := ProvisioningSLAReportEntry{
+				ServiceID:    svc.GetID(),
+				ServiceName:  svc.GetName(),
+				PlanID:       plan.GetID(),
+				PlanName:     plan.GetName(),
+				WithinTarget: planOutcomes.WithinTarget,
+				Breached:     planOutcomes.Breached,
+			}
+			if hasTarget {
+				entry.TargetSeconds = target.Seconds()
+			}
+			if attainment, ok := planOutcomes.GetAttainment(); ok {
+				entry.Attainment = &attainment
+			}
+			report.Plans = append(report.Plans, entry)
+		}
+	}
+	return report, nil
+}
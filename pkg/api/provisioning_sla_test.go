@@ -0,0 +1,41 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetProvisioningSLAReport(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	s.provisioningSLA, err = service.NewProvisioningSLA(time.Minute, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, s.store.RecordProvisioningSLAOutcome(fake.StandardPlanID, false))
+	assert.Nil(t, s.store.RecordProvisioningSLAOutcome(fake.StandardPlanID, true))
+	req, err := http.NewRequest(
+		http.MethodGet,
+		"/admin/provisioning_sla",
+		nil,
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	report := &ProvisioningSLAReport{}
+	err = GetProvisioningSLAReportFromJSON(rr.Body.Bytes(), report)
+	assert.Nil(t, err)
+	assert.Len(t, report.Plans, 1)
+	entry := report.Plans[0]
+	assert.Equal(t, fake.StandardPlanID, entry.PlanID)
+	assert.Equal(t, float64(60), entry.TargetSeconds)
+	assert.Equal(t, int64(1), entry.WithinTarget)
+	assert.Equal(t, int64(1), entry.Breached)
+	assert.NotNil(t, entry.Attainment)
+	assert.Equal(t, 0.5, *entry.Attainment)
+}
@@ -0,0 +1,290 @@
+package api
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	log "github.com/Sirupsen/logrus"
+	"github.com/gorilla/mux"
+)
+
+// QuarantineRequest represents a request to quarantine an instance. Reason is
+// optional.
+type QuarantineRequest struct {
+	Reason string `json:"reason"`
+}
+
+// QuarantineResponse represents the response to a request to fetch an
+// instance's quarantine status. Quarantine is omitted if the instance has
+// never been quarantined.
+type QuarantineResponse struct {
+	InstanceID   string                   `json:"instance_id"`
+	Status       string                   `json:"status"`
+	StatusReason string                   `json:"status_reason,omitempty"`
+	Quarantine   *service.QuarantineState `json:"quarantine,omitempty"`
+}
+
+// GetQuarantineResponseFromJSON returns a new QuarantineResponse unmarshalled
+// from the provided JSON []byte
+func GetQuarantineResponseFromJSON(
+	jsonBytes []byte,
+	quarantineResponse *QuarantineResponse,
+) error {
+	return json.Unmarshal(jsonBytes, quarantineResponse)
+}
+
+// ToJSON returns a []byte containing a JSON representation of the quarantine
+// response
+func (q *QuarantineResponse) ToJSON() ([]byte, error) {
+	return json.Marshal(q)
+}
+
+func (s *server) getQuarantine(w http.ResponseWriter, r *http.Request) {
+	instanceID := mux.Vars(r)["instance_id"]
+
+	logFields := log.Fields{
+		"instanceID": instanceID,
+	}
+
+	log.WithFields(logFields).Debug(
+		"received request to fetch instance quarantine status",
+	)
+
+	instance, ok, err := s.store.GetInstance(instanceID)
+	if err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error(
+			"quarantine status error: error retrieving instance by id",
+		)
+		s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
+		return
+	}
+	if !ok {
+		log.WithFields(logFields).Debug(
+			"quarantine status request for an instance that does not exist",
+		)
+		s.writeResponse(w, http.StatusNotFound, generateEmptyResponse())
+		return
+	}
+	quarantineResponse := &QuarantineResponse{
+		InstanceID:   instance.InstanceID,
+		Status:       instance.Status,
+		StatusReason: instance.StatusReason,
+		Quarantine:   instance.Quarantine,
+	}
+	quarantineJSON, err := quarantineResponse.ToJSON()
+	if err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error(
+			"quarantine status error: error marshaling quarantine response",
+		)
+		s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
+		return
+	}
+	s.writeResponse(w, http.StatusOK, quarantineJSON)
+}
+
+// quarantine isolates an instance during a security incident. Network access
+// to the instance's underlying resources is denied and its bindings'
+// credentials are revoked, asynchronously. Until the instance is released, it
+// can't be updated or bound to, but it can be deprovisioned.
+func (s *server) quarantine(w http.ResponseWriter, r *http.Request) {
+	instanceID := mux.Vars(r)["instance_id"]
+
+	logFields := log.Fields{
+		"instanceID": instanceID,
+	}
+
+	log.WithFields(logFields).Debug("received quarantine request")
+
+	bodyBytes, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error(
+			"pre-quarantine error: error reading request body",
+		)
+		s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
+		return
+	}
+	defer r.Body.Close() // nolint: errcheck
+	quarantineRequest := QuarantineRequest{}
+	if len(bodyBytes) > 0 {
+		if err := json.Unmarshal(bodyBytes, &quarantineRequest); err != nil {
+			logFields["error"] = err
+			log.WithFields(logFields).Debug(
+				"bad quarantine request: error unmarshaling request body",
+			)
+			s.writeResponse(
+				w,
+				http.StatusBadRequest,
+				generateMalformedRequestResponse(),
+			)
+			return
+		}
+	}
+
+	instance, ok := s.getQuarantinableInstance(w, instanceID, logFields)
+	if !ok {
+		return
+	}
+	switch instance.Status {
+	case service.InstanceStateQuarantining:
+		log.WithFields(logFields).Debug("quarantine is already in progress")
+		s.writeResponse(w, http.StatusAccepted, generateEmptyResponse())
+		return
+	case service.InstanceStateQuarantined:
+		log.WithFields(logFields).Debug("instance is already quarantined")
+		s.writeResponse(w, http.StatusOK, generateEmptyResponse())
+		return
+	case service.InstanceStateProvisioned:
+	case service.InstanceStateUpdatingFailed:
+	case service.InstanceStateQuarantiningFailed:
+	case service.InstanceStateReleasingFailed:
+	default:
+		// An instance that is being provisioned, updated, deprovisioned or
+		// released can't be quarantined until that has finished
+		logFields["status"] = instance.Status
+		log.WithFields(logFields).Debug(
+			"cannot quarantine instance in its current state",
+		)
+		s.writeResponse(w, http.StatusConflict, generateEmptyResponse())
+		return
+	}
+
+	if instance.Quarantine == nil ||
+		instance.Status != service.InstanceStateQuarantiningFailed {
+		instance.Quarantine = &service.QuarantineState{}
+	}
+	instance.Quarantine.Reason = quarantineRequest.Reason
+	instance.Status = service.InstanceStateQuarantining
+	instance.StatusReason = ""
+	s.submitQuarantineTask(
+		w,
+		instance,
+		"quarantineInstance",
+		logFields,
+	)
+}
+
+// release restores the network access that was denied to a quarantined
+// instance's underlying resources, asynchronously. Bindings whose credentials
+// were revoked remain revoked.
+func (s *server) release(w http.ResponseWriter, r *http.Request) {
+	instanceID := mux.Vars(r)["instance_id"]
+
+	logFields := log.Fields{
+		"instanceID": instanceID,
+	}
+
+	log.WithFields(logFields).Debug("received quarantine release request")
+
+	instance, ok := s.getQuarantinableInstance(w, instanceID, logFields)
+	if !ok {
+		return
+	}
+	switch instance.Status {
+	case service.InstanceStateReleasing:
+		log.WithFields(logFields).Debug("release is already in progress")
+		s.writeResponse(w, http.StatusAccepted, generateEmptyResponse())
+		return
+	case service.InstanceStateQuarantined:
+	case service.InstanceStateReleasingFailed:
+	default:
+		logFields["status"] = instance.Status
+		log.WithFields(logFields).Debug(
+			"cannot release instance that is not quarantined",
+		)
+		s.writeResponse(w, http.StatusConflict, generateEmptyResponse())
+		return
+	}
+
+	instance.Status = service.InstanceStateReleasing
+	instance.StatusReason = ""
+	s.submitQuarantineTask(
+		w,
+		instance,
+		"releaseInstance",
+		logFields,
+	)
+}
+
+// getQuarantinableInstance retrieves the specified instance and verifies that
+// its service supports quarantine. If either fails, an appropriate response is
+// written and false is returned.
+func (s *server) getQuarantinableInstance(
+	w http.ResponseWriter,
+	instanceID string,
+	logFields log.Fields,
+) (service.Instance, bool) {
+	instance, ok, err := s.store.GetInstance(instanceID)
+	if err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error(
+			"pre-quarantine error: error retrieving instance by id",
+		)
+		s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
+		return instance, false
+	}
+	if !ok {
+		log.WithFields(logFields).Debug(
+			"bad quarantine request: the instance does not exist",
+		)
+		s.writeResponse(w, http.StatusNotFound, generateEmptyResponse())
+		return instance, false
+	}
+	_, ok = instance.Service.GetServiceManager().(service.Quarantiner)
+	if !ok {
+		logFields["serviceID"] = instance.ServiceID
+		log.WithFields(logFields).Debug(
+			"bad quarantine request: service does not support quarantine",
+		)
+		s.writeResponse(
+			w,
+			http.StatusUnprocessableEntity,
+			generateQuarantineUnsupportedResponse(),
+		)
+		return instance, false
+	}
+	return instance, true
+}
+
+// submitQuarantineTask persists the given instance, whose status has been
+// updated, and submits a task for the named job to carry out the quarantine or
+// release of it
+func (s *server) submitQuarantineTask(
+	w http.ResponseWriter,
+	instance service.Instance,
+	jobName string,
+	logFields log.Fields,
+) {
+	if err := s.store.WriteInstance(instance); err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error(
+			"quarantine error: error persisting updated instance",
+		)
+		s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
+		return
+	}
+	// The task is submitted on no tenant's behalf so that, where tasks are
+	// scheduled fairly, it doesn't wait behind the tenant's other tasks
+	task := async.NewTask(
+		jobName,
+		map[string]string{
+			"instanceID": instance.InstanceID,
+		},
+	)
+	logFields["job"] = jobName
+	if err := s.asyncEngine.SubmitTask(task); err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error(
+			"quarantine error: error submitting task",
+		)
+		s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
+		return
+	}
+	s.writeResponse(w, http.StatusAccepted, generateEmptyResponse())
+	log.WithFields(logFields).Info("asynchronous quarantine task submitted")
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	fakeAsync "github.com/Azure/open-service-broker-azure/pkg/async/fake"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuarantiningInstanceThatDoesNotExist(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	req, err := getQuarantineRequest(getDisposableInstanceID(), nil)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestQuarantiningInstanceThatIsBeingUpdated(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	err = s.store.WriteInstance(service.Instance{
+		InstanceID: instanceID,
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateUpdating,
+	})
+	assert.Nil(t, err)
+	req, err := getQuarantineRequest(instanceID, nil)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Empty(t, s.asyncEngine.(*fakeAsync.Engine).SubmittedTasks)
+}
+
+func TestQuarantiningProvisionedInstance(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	err = s.store.WriteInstance(service.Instance{
+		InstanceID: instanceID,
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateProvisioned,
+	})
+	assert.Nil(t, err)
+	req, err := getQuarantineRequest(
+		instanceID,
+		[]byte(`{"reason":"leaked credentials"}`),
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	e := s.asyncEngine.(*fakeAsync.Engine)
+	assert.Equal(t, 1, len(e.SubmittedTasks))
+	for _, task := range e.SubmittedTasks {
+		assert.Equal(t, "quarantineInstance", task.GetJobName())
+		assert.Equal(t, instanceID, task.GetArgs()["instanceID"])
+	}
+	instance, _, err := s.store.GetInstance(instanceID)
+	assert.Nil(t, err)
+	assert.Equal(t, service.InstanceStateQuarantining, instance.Status)
+	assert.Equal(t, "leaked credentials", instance.Quarantine.Reason)
+}
+
+func TestReleasingInstanceThatIsNotQuarantined(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	err = s.store.WriteInstance(service.Instance{
+		InstanceID: instanceID,
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateProvisioned,
+	})
+	assert.Nil(t, err)
+	req, err := getReleaseRequest(instanceID)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
+
+func TestReleasingQuarantinedInstance(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	err = s.store.WriteInstance(service.Instance{
+		InstanceID: instanceID,
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateQuarantined,
+	})
+	assert.Nil(t, err)
+	req, err := getReleaseRequest(instanceID)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	instance, _, err := s.store.GetInstance(instanceID)
+	assert.Nil(t, err)
+	assert.Equal(t, service.InstanceStateReleasing, instance.Status)
+}
+
+func getQuarantineRequest(
+	instanceID string,
+	body []byte,
+) (*http.Request, error) {
+	return http.NewRequest(
+		http.MethodPost,
+		fmt.Sprintf("/admin/instances/%s/quarantine", instanceID),
+		bytes.NewBuffer(body),
+	)
+}
+
+func getReleaseRequest(instanceID string) (*http.Request, error) {
+	return http.NewRequest(
+		http.MethodPost,
+		fmt.Sprintf("/admin/instances/%s/release", instanceID),
+		nil,
+	)
+}
@@ -0,0 +1,18 @@
+package api
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/redaction"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// redactValidationError returns a copy of the given validation error that is
+// safe to include in a response. Modules' descriptions of what's wrong with a
+// parameter often quote its value, which may be, or contain, a secret.
+func (s *server) redactValidationError(
+	validationErr *service.ValidationError,
+) *service.ValidationError {
+	return service.NewValidationError(
+		validationErr.Field,
+		redaction.RedactText(validationErr.Issue, s.responseRedactionMode),
+	)
+}
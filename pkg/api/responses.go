@@ -3,6 +3,8 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 	log "github.com/Sirupsen/logrus"
@@ -61,8 +63,32 @@ var responseProvisioningAccepted = []byte(
 	fmt.Sprintf(`{ "operation": "%s" }`, OperationProvisioning),
 )
 
-func generateProvisionAcceptedResponse() []byte {
-	return responseProvisioningAccepted
+// provisioningResponse represents the response to an accepted request to
+// provision an instance. CostEstimate is custom to this broker and is
+// populated only if the broker has been given a pricing table that covers the
+// new instance.
+type provisioningResponse struct {
+	Operation    string                `json:"operation"`
+	CostEstimate *service.CostEstimate `json:"cost_estimate,omitempty"`
+}
+
+func generateProvisionAcceptedResponse(
+	costEstimate *service.CostEstimate,
+) []byte {
+	if costEstimate == nil {
+		return responseProvisioningAccepted
+	}
+	responseBody, err := json.Marshal(provisioningResponse{
+		Operation:    OperationProvisioning,
+		CostEstimate: costEstimate,
+	})
+	if err != nil {
+		log.WithField("error", err).Error(
+			"Error generating provisioning response; omitting cost estimate",
+		)
+		return responseProvisioningAccepted
+	}
+	return responseBody
 }
 
 var responseUpdatingAccepted = []byte(
@@ -89,6 +115,45 @@ func generateOperationInProgressResponse() []byte {
 	return responseInProgress
 }
 
+// provisioningInProgressResponse represents the response to a request to poll
+// an in-progress provisioning operation. ElapsedSeconds and
+// EstimatedCompletionTime are custom to this broker.
+type provisioningInProgressResponse struct {
+	State                   string     `json:"state"`
+	Description             string     `json:"description,omitempty"`
+	ElapsedSeconds          int64      `json:"elapsed_seconds"`
+	EstimatedCompletionTime *time.Time `json:"estimated_completion_time,omitempty"` // nolint: lll
+}
+
+func generateProvisioningInProgressResponse(
+	estimate *provisioningEstimate,
+) []byte {
+	if estimate == nil {
+		return responseInProgress
+	}
+	response := provisioningInProgressResponse{
+		State:          OperationStateInProgress,
+		ElapsedSeconds: int64(estimate.Elapsed.Seconds()),
+	}
+	if !estimate.EstimatedCompletion.IsZero() {
+		estimatedCompletion := estimate.EstimatedCompletion.UTC().Truncate(
+			time.Second,
+		)
+		response.EstimatedCompletionTime = &estimatedCompletion
+	}
+	if estimate.Overdue {
+		response.Description = "Provisioning is taking longer than expected."
+	}
+	responseBody, err := json.Marshal(response)
+	if err != nil {
+		log.WithField("error", err).Error(
+			"Error generating polling response; omitting estimate",
+		)
+		return responseInProgress
+	}
+	return responseBody
+}
+
 var responseSucceeded = []byte(
 	fmt.Sprintf(`{ "state": "%s" }`, OperationStateSucceeded),
 )
@@ -208,3 +273,79 @@ var responseParentInvalid = []byte(
 func generateParentInvalidResponse() []byte {
 	return responseParentInvalid
 }
+
+var responseQuarantineUnsupported = []byte(
+	`{ "error": "QuarantineUnsupported", "description": "The service of the ` +
+		`specified service instance does not support quarantine" }`,
+)
+
+func generateQuarantineUnsupportedResponse() []byte {
+	return responseQuarantineUnsupported
+}
+
+var responseProvisioningCapacityExhausted = []byte(
+	`{ "error": "CapacityExhausted", "description": "The service is at its ` +
+		`cap on concurrent provisions and no more requests may be queued; try ` +
+		`again later" }`,
+)
+
+func generateProvisioningCapacityExhaustedResponse() []byte {
+	return responseProvisioningCapacityExhausted
+}
+
+// provisioningQueuedResponse represents the response to a request to poll a
+// provisioning operation that is queued awaiting capacity. QueuePosition is
+// custom to this broker.
+type provisioningQueuedResponse struct {
+	State         string `json:"state"`
+	Description   string `json:"description"`
+	QueuePosition int64  `json:"queue_position,omitempty"`
+}
+
+func generateProvisioningQueuedResponse(position int64) []byte {
+	response := provisioningQueuedResponse{
+		State:         OperationStateInProgress,
+		Description:   "Provisioning is queued awaiting capacity.",
+		QueuePosition: position,
+	}
+	if position > 0 {
+		response.Description = fmt.Sprintf(
+			"Provisioning is queued awaiting capacity, at position %d.",
+			position,
+		)
+	}
+	responseBody, err := json.Marshal(response)
+	if err != nil {
+		log.WithField("error", err).Error(
+			"Error generating polling response; omitting queue position",
+		)
+		return responseInProgress
+	}
+	return responseBody
+}
+
+var responseDependentsExist = []byte(
+	`{ "error": "DependentsExist", "description": "The service instance ` +
+		`cannot be deprovisioned while other service instances depend upon it" }`,
+)
+
+// generateDependentsExistResponse returns the response to a request to
+// deprovision an instance that the instances having the given ids depend upon
+func generateDependentsExistResponse(dependentIDs []string) []byte {
+	response := errorResponse{
+		Error: "DependentsExist",
+		Description: fmt.Sprintf(
+			"The service instance cannot be deprovisioned while other service "+
+				"instances depend upon it; deprovision them first: %s",
+			strings.Join(dependentIDs, ", "),
+		),
+	}
+	responseBody, err := json.Marshal(response)
+	if err != nil {
+		log.WithField("error", err).Error(
+			"Error generating dependents exist response; omitting dependents",
+		)
+		return responseDependentsExist
+	}
+	return responseBody
+}
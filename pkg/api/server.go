@@ -7,9 +7,12 @@ import (
 	"time"
 
 	"github.com/Azure/open-service-broker-azure/pkg/async"
+	"github.com/Azure/open-service-broker-azure/pkg/gitops"
 	"github.com/Azure/open-service-broker-azure/pkg/http/filter"
+	"github.com/Azure/open-service-broker-azure/pkg/redaction"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 	"github.com/Azure/open-service-broker-azure/pkg/storage"
+	"github.com/Azure/open-service-broker-azure/pkg/telemetry"
 	log "github.com/Sirupsen/logrus"
 	"github.com/gorilla/mux"
 )
@@ -42,68 +45,212 @@ type server struct {
 	catalog         service.Catalog
 	catalogResponse []byte
 	// This allows tests to inject an alternative implementation of this function
-	listenAndServe            func(context.Context) error
-	defaultAzureLocation      string
-	defaultAzureResourceGroup string
+	listenAndServe              func(context.Context) error
+	defaultAzureLocation        string
+	defaultAzureResourceGroup   string
+	auditProvisioningParameters bool
+	pricingTable                *service.PricingTable
+	responseRedactionMode       redaction.Mode
+	metadataTagging             service.MetadataTagging
+	provisioningDeduplication   ProvisioningDeduplication
+	provisioningSLA             service.ProvisioningSLA
+	approvalPolicy              service.ApprovalPolicy
+	// throttle may be nil, in which case no dispatch rates are reported
+	throttle           service.ResourceProviderThrottle
+	credentialRotation CredentialRotationPolicy
+	// provisioningOverdueFactor is the multiple of its estimated duration beyond
+	// which a provisioning operation is reported as taking longer than expected.
+	// Zero means it never is.
+	provisioningOverdueFactor float64
+	// costCenterLabel names the tag, or provisioning context label, whose value
+	// is the cost center an instance's costs are charged back to
+	costCenterLabel string
+	// subscriptionRouting determines which Azure subscription each new instance
+	// is provisioned into
+	subscriptionRouting SubscriptionRouting
+	// expiryPolicy determines which plans' instances may be provisioned as
+	// sandbox instances, and how long those instances live
+	expiryPolicy service.ExpiryPolicy
+	// provisioningCapacity determines how many instances of each module's
+	// services may be provisioning at once, and what becomes of requests that
+	// arrive while a module is at its cap
+	provisioningCapacity service.ProvisioningCapacityPolicy
+	// manifestWriter may be nil, in which case no GitOps manifests are written
+	// for bindings
+	manifestWriter gitops.ManifestWriter
+	// debugTracingMode determines whether new instances may ask for their steps
+	// to be traced
+	debugTracingMode service.DebugTracingMode
+	// inFlightProvisions may be nil, in which case no in-flight provisioning
+	// operations are reported
+	inFlightProvisions InFlightProvisionCounter
+	// telemetrySampler may be nil, in which case the series of metrics aren't
+	// capped
+	telemetrySampler telemetry.Sampler
+}
+
+// ServerConfig represents the components and options a Server is assembled
+// from. Options that are left unset assume their zero values, which in most
+// cases disable the feature they configure.
+type ServerConfig struct {
+	Port                        int
+	Store                       storage.Store
+	AsyncEngine                 async.Engine
+	FilterChain                 filter.Filter
+	Catalog                     service.Catalog
+	DefaultAzureLocation        string
+	DefaultAzureResourceGroup   string
+	AuditProvisioningParameters bool
+	PricingTable                *service.PricingTable
+	ResponseRedactionMode       redaction.Mode
+	MetadataTagging             service.MetadataTagging
+	ProvisioningDeduplication   ProvisioningDeduplication
+	ProvisioningSLA             service.ProvisioningSLA
+	ApprovalPolicy              service.ApprovalPolicy
+	// Throttle may be nil, in which case no dispatch rates are reported
+	Throttle           service.ResourceProviderThrottle
+	CredentialRotation CredentialRotationPolicy
+	// ProvisioningOverdueFactor is the multiple of its estimated duration
+	// beyond which a provisioning operation is reported as taking longer than
+	// expected. Zero means it never is.
+	ProvisioningOverdueFactor float64
+	// CostCenterLabel names the tag, or provisioning context label, whose value
+	// is the cost center an instance's costs are charged back to
+	CostCenterLabel      string
+	SubscriptionRouting  SubscriptionRouting
+	ExpiryPolicy         service.ExpiryPolicy
+	ProvisioningCapacity service.ProvisioningCapacityPolicy
+	// ManifestWriter may be nil, in which case no GitOps manifests are written
+	// for bindings
+	ManifestWriter   gitops.ManifestWriter
+	DebugTracingMode service.DebugTracingMode
+	// InFlightProvisions may be nil, in which case no in-flight provisioning
+	// operations are reported
+	InFlightProvisions InFlightProvisionCounter
+	// TelemetrySampler may be nil, in which case the series of metrics aren't
+	// capped
+	TelemetrySampler telemetry.Sampler
 }
 
 // NewServer returns an HTTP router
-func NewServer(
-	port int,
-	store storage.Store,
-	asyncEngine async.Engine,
-	filterChain filter.Filter,
-	catalog service.Catalog,
-	defaultAzureLocation string,
-	defaultAzureResourceGroup string,
-) (Server, error) {
+func NewServer(config ServerConfig) (Server, error) {
+	// Unless the broker traces steps, new instances may not ask it to
+	if config.DebugTracingMode == "" {
+		config.DebugTracingMode = service.DebugTracingModeOff
+	}
 	s := &server{
-		port:                      port,
-		store:                     store,
-		asyncEngine:               asyncEngine,
-		filterChain:               filterChain,
-		catalog:                   catalog,
-		defaultAzureLocation:      defaultAzureLocation,
-		defaultAzureResourceGroup: defaultAzureResourceGroup,
+		port:                        config.Port,
+		store:                       config.Store,
+		asyncEngine:                 config.AsyncEngine,
+		filterChain:                 config.FilterChain,
+		catalog:                     config.Catalog,
+		defaultAzureLocation:        config.DefaultAzureLocation,
+		defaultAzureResourceGroup:   config.DefaultAzureResourceGroup,
+		auditProvisioningParameters: config.AuditProvisioningParameters,
+		pricingTable:                config.PricingTable,
+		responseRedactionMode:       config.ResponseRedactionMode,
+		metadataTagging:             config.MetadataTagging,
+		provisioningDeduplication:   config.ProvisioningDeduplication,
+		provisioningSLA:             config.ProvisioningSLA,
+		approvalPolicy:              config.ApprovalPolicy,
+		throttle:                    config.Throttle,
+		credentialRotation:          config.CredentialRotation,
+		provisioningOverdueFactor:   config.ProvisioningOverdueFactor,
+		costCenterLabel:             config.CostCenterLabel,
+		subscriptionRouting:         config.SubscriptionRouting,
+		expiryPolicy:                config.ExpiryPolicy,
+		provisioningCapacity:        config.ProvisioningCapacity,
+		manifestWriter:              config.ManifestWriter,
+		debugTracingMode:            config.DebugTracingMode,
+		inFlightProvisions:          config.InFlightProvisions,
+		telemetrySampler:            config.TelemetrySampler,
 	}
 
 	router := mux.NewRouter()
 	router.StrictSlash(true)
 	router.HandleFunc(
 		"/v2/catalog",
-		filterChain.GetHandler(s.getCatalog),
+		config.FilterChain.GetHandler(s.getCatalog),
 	).Methods(http.MethodGet)
 	router.HandleFunc(
 		"/v2/service_instances/{instance_id}",
-		filterChain.GetHandler(s.provision),
+		config.FilterChain.GetHandler(s.provision),
 	).Methods(http.MethodPut)
 	router.HandleFunc(
 		"/v2/service_instances/{instance_id}",
-		filterChain.GetHandler(s.update),
+		config.FilterChain.GetHandler(s.getInstance),
+	).Methods(http.MethodGet)
+	router.HandleFunc(
+		"/v2/service_instances/{instance_id}",
+		config.FilterChain.GetHandler(s.update),
 	).Methods(http.MethodPatch)
 	router.HandleFunc(
 		"/v2/service_instances/{instance_id}/last_operation",
-		filterChain.GetHandler(s.poll),
+		config.FilterChain.GetHandler(s.poll),
 	).Methods(http.MethodGet)
 	router.HandleFunc(
 		"/v2/service_instances/{instance_id}/service_bindings/{binding_id}",
-		filterChain.GetHandler(s.bind),
+		config.FilterChain.GetHandler(s.bind),
 	).Methods(http.MethodPut)
 	router.HandleFunc(
 		"/v2/service_instances/{instance_id}/service_bindings/{binding_id}",
-		filterChain.GetHandler(s.unbind),
+		config.FilterChain.GetHandler(s.unbind),
 	).Methods(http.MethodDelete)
 	router.HandleFunc(
 		"/v2/service_instances/{instance_id}",
-		filterChain.GetHandler(s.deprovision),
+		config.FilterChain.GetHandler(s.deprovision),
 	).Methods(http.MethodDelete)
+	router.HandleFunc(
+		"/admin/provisioning_sla",
+		config.FilterChain.GetHandler(s.getProvisioningSLAReport),
+	).Methods(http.MethodGet)
+	router.HandleFunc(
+		"/admin/key_rotation",
+		config.FilterChain.GetHandler(s.getKeyRotation),
+	).Methods(http.MethodGet)
+	router.HandleFunc(
+		"/admin/chargeback",
+		config.FilterChain.GetHandler(s.getChargebackReport),
+	).Methods(http.MethodGet)
+	router.HandleFunc(
+		"/admin/drift",
+		config.FilterChain.GetHandler(s.getDriftReport),
+	).Methods(http.MethodGet)
+	router.HandleFunc(
+		"/admin/instances/{instance_id}/drift",
+		config.FilterChain.GetHandler(s.getDrift),
+	).Methods(http.MethodGet)
+	router.HandleFunc(
+		"/admin/instances/{instance_id}/quarantine",
+		config.FilterChain.GetHandler(s.getQuarantine),
+	).Methods(http.MethodGet)
+	router.HandleFunc(
+		"/admin/instances/{instance_id}/quarantine",
+		config.FilterChain.GetHandler(s.quarantine),
+	).Methods(http.MethodPost)
+	router.HandleFunc(
+		"/admin/instances/{instance_id}/release",
+		config.FilterChain.GetHandler(s.release),
+	).Methods(http.MethodPost)
+	router.HandleFunc(
+		"/admin/instances/{instance_id}/trace",
+		config.FilterChain.GetHandler(s.getStepTraces),
+	).Methods(http.MethodGet)
+	router.HandleFunc(
+		"/admin/instances/{instance_id}/approval",
+		config.FilterChain.GetHandler(s.decideApproval),
+	).Methods(http.MethodPost)
+	router.HandleFunc(
+		"/metrics",
+		s.getMetrics, // Filter chain not applied to this request
+	).Methods(http.MethodGet)
 	router.HandleFunc(
 		"/healthz",
 		s.healthCheck, // Filter chain not applied to this reqeust
 	).Methods(http.MethodGet)
 	s.router = router
 
-	catalogJSON, err := catalog.ToJSON()
+	catalogJSON, err := config.Catalog.ToJSON()
 	if err != nil {
 		return nil, err
 	}
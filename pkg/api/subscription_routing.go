@@ -0,0 +1,264 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// SubscriptionRoutingPolicy determines which of the subscriptions in the pool
+// an instance is provisioned into when neither the request nor the request's
+// context determines it
+type SubscriptionRoutingPolicy string
+
+const (
+	// SubscriptionRoutingPolicyDefault provisions instances into the broker's
+	// own subscription
+	SubscriptionRoutingPolicyDefault SubscriptionRoutingPolicy = "default"
+	// SubscriptionRoutingPolicyRoundRobin provisions instances into each of the
+	// subscriptions in the pool in turn
+	SubscriptionRoutingPolicyRoundRobin SubscriptionRoutingPolicy = "round-robin"
+)
+
+// SubscriptionRouting describes how the Azure subscription each new instance
+// is provisioned into is chosen. In order of precedence, it's the
+// subscription explicitly requested using the "subscriptionId" parameter, the
+// subscription of the instance's parent or clone source, the subscription
+// mapped to the requesting space or organization, and lastly the subscription
+// chosen by the routing policy. The zero value provisions every instance into
+// the broker's own subscription and permits no other to be requested.
+type SubscriptionRouting struct {
+	defaultSubscriptionID string
+	// pool holds the subscriptions, other than the broker's own, that instances
+	// may be provisioned into
+	pool           map[string]bool
+	policyPool     []string
+	policy         SubscriptionRoutingPolicy
+	byOrganization map[string]string
+	bySpace        map[string]string
+	checker        azure.SubscriptionChecker
+	// next counts the instances routed using the round-robin policy. It's
+	// shared by copies of the SubscriptionRouting.
+	next *uint64
+}
+
+// NewSubscriptionRouting returns a SubscriptionRouting that provisions
+// instances into the given default subscription (the broker's own) or any of
+// the subscriptions in the given pool. Organization and space GUIDs may be
+// mapped to the subscriptions their instances are provisioned into. The given
+// checker is used to verify that the broker can access each subscription that
+// isn't the default before provisioning into it.
+func NewSubscriptionRouting(
+	defaultSubscriptionID string,
+	pool []string,
+	policy SubscriptionRoutingPolicy,
+	byOrganization map[string]string,
+	bySpace map[string]string,
+	checker azure.SubscriptionChecker,
+) (SubscriptionRouting, error) {
+	if defaultSubscriptionID == "" {
+		return SubscriptionRouting{}, errors.New(
+			"a default subscription must be specified",
+		)
+	}
+	s := SubscriptionRouting{
+		defaultSubscriptionID: defaultSubscriptionID,
+		pool:                  map[string]bool{},
+		policy:                policy,
+		byOrganization:        byOrganization,
+		bySpace:               bySpace,
+		checker:               checker,
+		next:                  new(uint64),
+	}
+	for _, subscriptionID := range pool {
+		if s.pool[subscriptionID] {
+			return SubscriptionRouting{}, fmt.Errorf(
+				`subscription "%s" is included in the pool more than once`,
+				subscriptionID,
+			)
+		}
+		s.policyPool = append(s.policyPool, subscriptionID)
+		if subscriptionID != defaultSubscriptionID {
+			s.pool[subscriptionID] = true
+		}
+	}
+	if len(s.pool) > 0 && checker == nil {
+		return SubscriptionRouting{}, errors.New(
+			"a subscription checker must be specified to provision into " +
+				"subscriptions other than the default",
+		)
+	}
+	switch policy {
+	case SubscriptionRoutingPolicyDefault:
+	case SubscriptionRoutingPolicyRoundRobin:
+		if len(s.policyPool) == 0 {
+			return SubscriptionRouting{}, errors.New(
+				"the round-robin routing policy requires a subscription pool",
+			)
+		}
+	default:
+		return SubscriptionRouting{}, fmt.Errorf(
+			`invalid subscription routing policy "%s"`,
+			policy,
+		)
+	}
+	for _, mappings := range []map[string]string{byOrganization, bySpace} {
+		for guid, subscriptionID := range mappings {
+			if !s.isRoutable(subscriptionID) {
+				return SubscriptionRouting{}, fmt.Errorf(
+					`"%s" is mapped to subscription "%s", which is neither the `+
+						`default subscription nor in the pool`,
+					guid,
+					subscriptionID,
+				)
+			}
+		}
+	}
+	return s, nil
+}
+
+// GetSubscriptionIDs returns the IDs of the subscriptions, other than the
+// broker's own, that instances may be provisioned into
+func (s SubscriptionRouting) GetSubscriptionIDs() []string {
+	subscriptionIDs := []string{}
+	for _, subscriptionID := range s.policyPool {
+		if s.pool[subscriptionID] {
+			subscriptionIDs = append(subscriptionIDs, subscriptionID)
+		}
+	}
+	return subscriptionIDs
+}
+
+// isRoutable returns a bool indicating whether instances may be provisioned
+// into the specified subscription
+func (s SubscriptionRouting) isRoutable(subscriptionID string) bool {
+	return subscriptionID == s.defaultSubscriptionID || s.pool[subscriptionID]
+}
+
+// getRequestedSubscriptionID returns the subscription, if any, that the
+// "subscriptionId" parameter in the given provisioning parameter map asks for
+// an instance to be provisioned into
+func getRequestedSubscriptionID(
+	parameters map[string]interface{},
+) (string, error) {
+	subscriptionIDIface, ok := parameters["subscriptionId"]
+	if !ok {
+		return "", nil
+	}
+	subscriptionID, ok := subscriptionIDIface.(string)
+	if !ok {
+		return "", service.NewValidationError(
+			"subscriptionId",
+			fmt.Sprintf(`"%v" is not a string`, subscriptionIDIface),
+		)
+	}
+	return subscriptionID, nil
+}
+
+// getSubscriptionID returns the subscription a new instance is to be
+// provisioned into, given the subscription explicitly requested (if any), the
+// alias of the instance's parent (if any), the instance's clone source (if
+// any), and the provisioning request
+func (s *server) getSubscriptionID(
+	requestedSubscriptionID string,
+	parentAlias string,
+	cloneSource *service.Instance,
+	provisioningRequest *ProvisioningRequest,
+) (string, error) {
+	var parent *service.Instance
+	if parentAlias != "" {
+		p, ok, err := s.store.GetInstanceByAlias(parentAlias)
+		if err != nil {
+			return "", fmt.Errorf(
+				`error retrieving parent with alias "%s": %s`,
+				parentAlias,
+				err,
+			)
+		}
+		// A parent that doesn't exist yet has no subscription to inherit
+		if ok {
+			parent = &p
+		}
+	}
+	return s.subscriptionRouting.route(
+		requestedSubscriptionID,
+		parent,
+		cloneSource,
+		provisioningRequest,
+	)
+}
+
+// route returns the subscription a new instance is to be provisioned into,
+// given the subscription explicitly requested (if any), the instance's parent
+// and clone source (if any), and the provisioning request. The subscription
+// is verified to be accessible to the broker.
+func (s SubscriptionRouting) route(
+	requestedSubscriptionID string,
+	parent *service.Instance,
+	cloneSource *service.Instance,
+	provisioningRequest *ProvisioningRequest,
+) (string, error) {
+	// A child's resources are provisioned into those of its parent, so it can
+	// only be provisioned into its parent's subscription
+	if parent != nil {
+		if requestedSubscriptionID != "" &&
+			requestedSubscriptionID != parent.SubscriptionID {
+			return "", service.NewValidationError(
+				"subscriptionId",
+				fmt.Sprintf(
+					`subscription "%s" is not the subscription of the parent instance`,
+					requestedSubscriptionID,
+				),
+			)
+		}
+		return parent.SubscriptionID, nil
+	}
+	if requestedSubscriptionID != "" {
+		if !s.isRoutable(requestedSubscriptionID) {
+			return "", service.NewValidationError(
+				"subscriptionId",
+				fmt.Sprintf(
+					`instances can't be provisioned into subscription "%s"`,
+					requestedSubscriptionID,
+				),
+			)
+		}
+		return requestedSubscriptionID, s.checkSubscription(
+			requestedSubscriptionID,
+		)
+	}
+	if cloneSource != nil {
+		return cloneSource.SubscriptionID, nil
+	}
+	if s.defaultSubscriptionID == "" {
+		return "", nil
+	}
+	subscriptionID, ok := s.bySpace[provisioningRequest.GetSpaceGUID()]
+	if !ok {
+		subscriptionID, ok =
+			s.byOrganization[provisioningRequest.GetOrganizationGUID()]
+	}
+	if !ok {
+		subscriptionID = s.defaultSubscriptionID
+		if s.policy == SubscriptionRoutingPolicyRoundRobin {
+			n := atomic.AddUint64(s.next, 1) - 1
+			subscriptionID = s.policyPool[n%uint64(len(s.policyPool))]
+		}
+	}
+	return subscriptionID, s.checkSubscription(subscriptionID)
+}
+
+// checkSubscription verifies that the broker can provision into the specified
+// subscription. The broker's own subscription is assumed to be accessible.
+func (s SubscriptionRouting) checkSubscription(subscriptionID string) error {
+	if subscriptionID == s.defaultSubscriptionID {
+		return nil
+	}
+	if err := s.checker.CheckSubscription(subscriptionID); err != nil {
+		return service.NewValidationError("subscriptionId", err.Error())
+	}
+	return nil
+}
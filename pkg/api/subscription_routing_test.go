@@ -0,0 +1,233 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	fakeAsync "github.com/Azure/open-service-broker-azure/pkg/async/fake"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSubscriptionChecker struct {
+	inaccessible map[string]bool
+}
+
+func (f fakeSubscriptionChecker) CheckSubscription(
+	subscriptionID string,
+) error {
+	if f.inaccessible[subscriptionID] {
+		return errors.New("subscription is not accessible")
+	}
+	return nil
+}
+
+func TestNewSubscriptionRoutingWithInvalidConfig(t *testing.T) {
+	checker := fakeSubscriptionChecker{}
+	_, err := NewSubscriptionRouting(
+		"",
+		nil,
+		SubscriptionRoutingPolicyDefault,
+		nil,
+		nil,
+		checker,
+	)
+	assert.NotNil(t, err)
+	_, err = NewSubscriptionRouting(
+		"default-sub",
+		[]string{"sub-a"},
+		"bogus",
+		nil,
+		nil,
+		checker,
+	)
+	assert.NotNil(t, err)
+	_, err = NewSubscriptionRouting(
+		"default-sub",
+		[]string{"sub-a", "sub-a"},
+		SubscriptionRoutingPolicyDefault,
+		nil,
+		nil,
+		checker,
+	)
+	assert.NotNil(t, err)
+	_, err = NewSubscriptionRouting(
+		"default-sub",
+		nil,
+		SubscriptionRoutingPolicyRoundRobin,
+		nil,
+		nil,
+		checker,
+	)
+	assert.NotNil(t, err)
+	// Organizations and spaces may only be mapped to subscriptions that can be
+	// provisioned into
+	_, err = NewSubscriptionRouting(
+		"default-sub",
+		[]string{"sub-a"},
+		SubscriptionRoutingPolicyDefault,
+		map[string]string{"org": "sub-b"},
+		nil,
+		checker,
+	)
+	assert.NotNil(t, err)
+}
+
+func TestSubscriptionRoutingPrecedence(t *testing.T) {
+	s, err := NewSubscriptionRouting(
+		"default-sub",
+		[]string{"sub-a", "sub-b", "sub-c"},
+		SubscriptionRoutingPolicyDefault,
+		map[string]string{"org": "sub-a"},
+		map[string]string{"space": "sub-b"},
+		fakeSubscriptionChecker{},
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"sub-a", "sub-b", "sub-c"}, s.GetSubscriptionIDs())
+	pr := &ProvisioningRequest{
+		Context: &ProvisioningContext{
+			OrganizationGUID: "org",
+			SpaceGUID:        "space",
+		},
+	}
+	subscriptionID, err := s.route("sub-c", nil, nil, pr)
+	assert.Nil(t, err)
+	assert.Equal(t, "sub-c", subscriptionID)
+	subscriptionID, err = s.route("", nil, nil, pr)
+	assert.Nil(t, err)
+	assert.Equal(t, "sub-b", subscriptionID)
+	pr.Context.SpaceGUID = "other-space"
+	subscriptionID, err = s.route("", nil, nil, pr)
+	assert.Nil(t, err)
+	assert.Equal(t, "sub-a", subscriptionID)
+	pr.Context.OrganizationGUID = "other-org"
+	subscriptionID, err = s.route("", nil, nil, pr)
+	assert.Nil(t, err)
+	assert.Equal(t, "default-sub", subscriptionID)
+	// Clones are provisioned into their source's subscription
+	subscriptionID, err = s.route(
+		"",
+		nil,
+		&service.Instance{SubscriptionID: "sub-c"},
+		pr,
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, "sub-c", subscriptionID)
+}
+
+func TestSubscriptionRoutingOfChildInstances(t *testing.T) {
+	s, err := NewSubscriptionRouting(
+		"default-sub",
+		[]string{"sub-a", "sub-b"},
+		SubscriptionRoutingPolicyRoundRobin,
+		nil,
+		nil,
+		fakeSubscriptionChecker{},
+	)
+	assert.Nil(t, err)
+	parent := &service.Instance{SubscriptionID: "sub-b"}
+	subscriptionID, err := s.route("", parent, nil, &ProvisioningRequest{})
+	assert.Nil(t, err)
+	assert.Equal(t, "sub-b", subscriptionID)
+	_, err = s.route("sub-a", parent, nil, &ProvisioningRequest{})
+	validationErr, ok := err.(*service.ValidationError)
+	assert.True(t, ok)
+	assert.Equal(t, "subscriptionId", validationErr.Field)
+}
+
+func TestSubscriptionRoutingRoundRobin(t *testing.T) {
+	s, err := NewSubscriptionRouting(
+		"default-sub",
+		[]string{"default-sub", "sub-a", "sub-b"},
+		SubscriptionRoutingPolicyRoundRobin,
+		nil,
+		nil,
+		fakeSubscriptionChecker{},
+	)
+	assert.Nil(t, err)
+	// The default subscription may be part of the pool, but it's not one of
+	// the additional subscriptions
+	assert.Equal(t, []string{"sub-a", "sub-b"}, s.GetSubscriptionIDs())
+	// Copies share their position in the rotation
+	copies := []SubscriptionRouting{s, s}
+	subscriptionIDs := []string{}
+	for i := 0; i < 4; i++ {
+		subscriptionID, err :=
+			copies[i%2].route("", nil, nil, &ProvisioningRequest{})
+		assert.Nil(t, err)
+		subscriptionIDs = append(subscriptionIDs, subscriptionID)
+	}
+	assert.Equal(
+		t,
+		[]string{"default-sub", "sub-a", "sub-b", "default-sub"},
+		subscriptionIDs,
+	)
+}
+
+func TestSubscriptionRoutingToUnusableSubscriptions(t *testing.T) {
+	s, err := NewSubscriptionRouting(
+		"default-sub",
+		[]string{"sub-a"},
+		SubscriptionRoutingPolicyDefault,
+		nil,
+		nil,
+		fakeSubscriptionChecker{
+			inaccessible: map[string]bool{"sub-a": true},
+		},
+	)
+	assert.Nil(t, err)
+	_, err = s.route("sub-b", nil, nil, &ProvisioningRequest{})
+	_, ok := err.(*service.ValidationError)
+	assert.True(t, ok)
+	_, err = s.route("sub-a", nil, nil, &ProvisioningRequest{})
+	_, ok = err.(*service.ValidationError)
+	assert.True(t, ok)
+	// Without routing configured, only the broker's own subscription can be
+	// provisioned into
+	s = SubscriptionRouting{}
+	_, err = s.route("sub-a", nil, nil, &ProvisioningRequest{})
+	_, ok = err.(*service.ValidationError)
+	assert.True(t, ok)
+}
+
+func TestProvisioningRecordsSubscription(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	s.subscriptionRouting, err = NewSubscriptionRouting(
+		"default-sub",
+		[]string{"sub-a"},
+		SubscriptionRoutingPolicyDefault,
+		nil,
+		nil,
+		fakeSubscriptionChecker{},
+	)
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	req, err := getProvisionRequest(
+		instanceID,
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&ProvisioningRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+			Parameters: map[string]interface{}{
+				"location":       "eastus",
+				"subscriptionId": "sub-a",
+			},
+		},
+	)
+	assert.Nil(t, err)
+	e := s.asyncEngine.(*fakeAsync.Engine)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	assert.Len(t, e.SubmittedTasks, 1)
+	instance, ok, err := s.store.GetInstance(instanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "sub-a", instance.SubscriptionID)
+}
@@ -59,6 +59,30 @@ func (s *server) unbind(w http.ResponseWriter, r *http.Request) {
 		s.writeResponse(w, http.StatusInternalServerError, generateEmptyResponse())
 		return
 	}
+
+	// The manifest is removed first, so that platforms watching the GitOps
+	// repository stop handing out the binding's credentials before they're
+	// revoked. Deleting a manifest that's already gone succeeds, so a failed
+	// unbinding can safely be retried.
+	if binding.GitOpsManifest != nil {
+		if s.manifestWriter == nil {
+			logFields["manifestPath"] = binding.GitOpsManifest.Path
+			log.WithFields(logFields).Warn(
+				"GitOps manifests are no longer written; the binding's manifest " +
+					"must be removed by hand",
+			)
+		} else if err = s.manifestWriter.DeleteManifest(
+			*binding.GitOpsManifest,
+		); err != nil {
+			s.handleUnbindingError(
+				binding,
+				err,
+				"error deleting GitOps manifest",
+				w,
+			)
+			return
+		}
+	}
 	if !ok {
 		// The instance to unbind from does not exist!
 		// krancour: Not totally sure what to do here. It seems within the realm
@@ -76,6 +100,11 @@ func (s *server) unbind(w http.ResponseWriter, r *http.Request) {
 		log.WithFields(logFields).Debug(
 			"unbinding an orphaned binding",
 		)
+	} else if binding.Status == service.BindingStateRevoked {
+		// The binding was already unbound when its instance was quarantined
+		log.WithFields(logFields).Debug(
+			"unbinding a binding whose credentials were revoked",
+		)
 	} else {
 		serviceManager := instance.Service.GetServiceManager()
 
@@ -112,6 +112,42 @@ func TestUnbindingFromInstanceThatExists(t *testing.T) {
 	assert.False(t, ok)
 }
 
+func TestUnbindingRevokedBinding(t *testing.T) {
+	s, m, err := getTestServer("", "")
+	assert.Nil(t, err)
+	m.ServiceManager.UnbindBehavior = func(
+		service.Instance,
+		service.BindingDetails,
+	) error {
+		assert.Fail(t, "a revoked binding was unbound a second time")
+		return nil
+	}
+	instanceID := getDisposableInstanceID()
+	bindingID := getDisposableBindingID()
+	err = s.store.WriteInstance(service.Instance{
+		InstanceID: instanceID,
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateQuarantined,
+	})
+	assert.Nil(t, err)
+	err = s.store.WriteBinding(service.Binding{
+		InstanceID: instanceID,
+		BindingID:  bindingID,
+		ServiceID:  fake.ServiceID,
+		Status:     service.BindingStateRevoked,
+	})
+	assert.Nil(t, err)
+	req, err := getUnbindingRequest(instanceID, bindingID)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	_, ok, err := s.store.GetBinding(bindingID)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
+
 func getUnbindingRequest(
 	instanceID string,
 	bindingID string,
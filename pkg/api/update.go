@@ -180,12 +180,79 @@ func (s *server) update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if instance.ServiceID == updatingRequest.ServiceID &&
+	// Bringing forward or pushing back the activation of an instance that is
+	// pending activation is handled synchronously, and may be done even while
+	// the instance is still being provisioned
+	activateAt, err := getActivateAt(updatingRequest.Parameters)
+	if err == nil && activateAt != nil && !instance.IsPendingActivation() {
+		err = service.NewValidationError(
+			"activateAt",
+			"the instance is not pending activation",
+		)
+	}
+	if err != nil {
+		s.handlePossibleValidationError(err, w, logFields)
+		return
+	}
+	rescheduled := false
+	if activateAt != nil &&
+		!activateAt.Equal(instance.Activation.ActivateAt) {
+		if err = s.rescheduleActivation(&instance, *activateAt); err != nil {
+			logFields["error"] = err
+			log.WithFields(logFields).Error(
+				"updating error: error rescheduling activation",
+			)
+			s.writeResponse(
+				w,
+				http.StatusInternalServerError,
+				generateEmptyResponse(),
+			)
+			return
+		}
+		rescheduled = true
+	}
+
+	// Likewise, putting off the expiry of a sandbox instance is handled
+	// synchronously
+	expiresAt, err := getExpiresAt(updatingRequest.Parameters)
+	if err == nil && expiresAt != nil {
+		err = validateExpiresAt(instance, *expiresAt)
+	}
+	if err != nil {
+		s.handlePossibleValidationError(err, w, logFields)
+		return
+	}
+	if expiresAt != nil && !expiresAt.Equal(instance.Expiry.ExpiresAt) {
+		if err = s.extendExpiry(&instance, *expiresAt); err != nil {
+			logFields["error"] = err
+			log.WithFields(logFields).Error(
+				"updating error: error putting off expiry",
+			)
+			s.writeResponse(
+				w,
+				http.StatusInternalServerError,
+				generateEmptyResponse(),
+			)
+			return
+		}
+		rescheduled = true
+	}
+
+	// Updating a suspended instance resumes it, so such a request is never
+	// treated as a no-op
+	if !instance.IsSuspended() &&
+		instance.ServiceID == updatingRequest.ServiceID &&
 		instance.PlanID == updatingRequest.PlanID &&
 		reflect.DeepEqual(
 			instance.UpdatingParameters,
 			updatingParameters,
 		) {
+		// If nothing but the instance's activation or expiry was changed, the
+		// update is already complete
+		if rescheduled {
+			s.writeResponse(w, http.StatusOK, generateEmptyResponse())
+			return
+		}
 		// Per the spec, if fully provisioned, respond with a 200, else a 202.
 		// Filling in a gap in the spec-- if the status is anything else, we'll
 		// choose to respond with a 409
@@ -215,7 +282,7 @@ func (s *server) update(w http.ResponseWriter, r *http.Request) {
 
 	// If we get to here, we need to update the instance.
 	// Start by carrying out serviceManager-specific request validation
-	err = serviceManager.ValidateUpdatingParameters(updatingRequest.Parameters)
+	err = serviceManager.ValidateUpdatingParameters(updatingParameters)
 	if err != nil {
 		validationErr, ok := err.(*service.ValidationError)
 		if ok {
@@ -248,6 +315,35 @@ func (s *server) update(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
+	err = validateMaintenanceWindow(
+		serviceManager,
+		plan,
+		instance.Location,
+		updatingParameters,
+	)
+	if err != nil {
+		s.handlePossibleValidationError(err, w, logFields)
+		return
+	}
+
+	// Updates too risky to apply in place are applied by replacing the instance
+	blueGreenUpdater, ok := serviceManager.(service.BlueGreenUpdater)
+	if ok && blueGreenUpdater.IsBlueGreenUpdate(
+		instance,
+		plan,
+		updatingParameters,
+	) {
+		s.startBlueGreenUpdate(
+			w,
+			blueGreenUpdater,
+			instance,
+			plan,
+			updatingParameters,
+			logFields,
+		)
+		return
+	}
+
 	updater, err := serviceManager.GetUpdater(plan)
 	if err != nil {
 		logFields["serviceID"] = updatingRequest.ServiceID
@@ -282,13 +378,19 @@ func (s *server) update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	jobName := "executeUpdatingStep"
+	if instance.IsSuspended() {
+		// The instance is resumed before the updating steps are executed
+		jobName = "resumeInstance"
+	}
 	task := async.NewTask(
-		"executeUpdatingStep",
+		jobName,
 		map[string]string{
 			"stepName":   firstStepName,
 			"instanceID": instanceID,
 		},
 	)
+	task.SetTenant(instance.OrganizationGUID)
 	if err := s.asyncEngine.SubmitTask(task); err != nil {
 		logFields["step"] = firstStepName
 		logFields["error"] = err
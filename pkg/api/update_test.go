@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	fakeAsync "github.com/Azure/open-service-broker-azure/pkg/async/fake"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
@@ -203,6 +204,318 @@ func TestKickOffNewAsyncUpdating(t *testing.T) {
 	assert.Equal(t, responseUpdatingAccepted, rr.Body.Bytes())
 }
 
+func TestUpdatingSuspendedInstanceResumesIt(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	err = s.store.WriteInstance(service.Instance{
+		InstanceID: instanceID,
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateProvisioned,
+		Suspension: &service.SuspensionState{
+			Suspended: true,
+		},
+	})
+	assert.Nil(t, err)
+	// Even a request that changes nothing resumes the instance
+	req, err := getUpdateRequest(
+		instanceID,
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&UpdatingRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+		},
+	)
+	assert.Nil(t, err)
+	e := s.asyncEngine.(*fakeAsync.Engine)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	assert.Equal(t, responseUpdatingAccepted, rr.Body.Bytes())
+	assert.Equal(t, 1, len(e.SubmittedTasks))
+	for _, task := range e.SubmittedTasks {
+		assert.Equal(t, "resumeInstance", task.GetJobName())
+		assert.Equal(t, instanceID, task.GetArgs()["instanceID"])
+	}
+}
+
+func TestUpdatingActivateAtReschedulesActivation(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	err = s.store.WriteInstance(service.Instance{
+		InstanceID: instanceID,
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateProvisioned,
+		Activation: &service.ActivationState{
+			ActivateAt:  time.Now().Add(24 * time.Hour).UTC(),
+			Deactivated: true,
+		},
+	})
+	assert.Nil(t, err)
+	activateAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	req, err := getUpdateRequest(
+		instanceID,
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&UpdatingRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+			Parameters: map[string]interface{}{
+				"activateAt": activateAt.Format(time.RFC3339),
+			},
+		},
+	)
+	assert.Nil(t, err)
+	e := s.asyncEngine.(*fakeAsync.Engine)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, 1, len(e.SubmittedTasks))
+	for _, task := range e.SubmittedTasks {
+		assert.Equal(t, "activateInstance", task.GetJobName())
+		assert.True(t, activateAt.Equal(*task.GetExecuteTime()))
+	}
+	instance, _, err := s.store.GetInstance(instanceID)
+	assert.Nil(t, err)
+	assert.True(t, activateAt.Equal(instance.Activation.ActivateAt))
+}
+
+func TestUpdatingActivateAtOfActiveInstanceFails(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	err = s.store.WriteInstance(service.Instance{
+		InstanceID: instanceID,
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateProvisioned,
+	})
+	assert.Nil(t, err)
+	req, err := getUpdateRequest(
+		instanceID,
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&UpdatingRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+			Parameters: map[string]interface{}{
+				"activateAt": time.Now().Add(time.Hour).Format(time.RFC3339),
+			},
+		},
+	)
+	assert.Nil(t, err)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestUpdatingExpiresAtExtendsExpiry(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	now := time.Now().UTC().Truncate(time.Second)
+	err = s.store.WriteInstance(service.Instance{
+		InstanceID: instanceID,
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateProvisioned,
+		Expiry: &service.ExpiryState{
+			ExpiresAt:    now.Add(time.Hour),
+			MaxExpiresAt: now.Add(24 * time.Hour),
+			WarnedAt:     &now,
+		},
+	})
+	assert.Nil(t, err)
+	expiresAt := now.Add(8 * time.Hour)
+	req, err := getUpdateRequest(
+		instanceID,
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&UpdatingRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+			Parameters: map[string]interface{}{
+				"expiresAt": expiresAt.Format(time.RFC3339),
+			},
+		},
+	)
+	assert.Nil(t, err)
+	e := s.asyncEngine.(*fakeAsync.Engine)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, 1, len(e.SubmittedTasks))
+	for _, task := range e.SubmittedTasks {
+		assert.Equal(t, "expireInstance", task.GetJobName())
+		assert.True(t, expiresAt.Equal(*task.GetExecuteTime()))
+	}
+	instance, _, err := s.store.GetInstance(instanceID)
+	assert.Nil(t, err)
+	assert.True(t, expiresAt.Equal(instance.Expiry.ExpiresAt))
+	// Subscribers are warned again ahead of the new expiry
+	assert.Nil(t, instance.Expiry.WarnedAt)
+}
+
+func TestUpdatingExpiresAtWithInvalidExpiryFails(t *testing.T) {
+	s, _, err := getTestServer("", "")
+	assert.Nil(t, err)
+	now := time.Now().UTC().Truncate(time.Second)
+	sandboxInstanceID := getDisposableInstanceID()
+	err = s.store.WriteInstance(service.Instance{
+		InstanceID: sandboxInstanceID,
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateProvisioned,
+		Expiry: &service.ExpiryState{
+			ExpiresAt:    now.Add(time.Hour),
+			MaxExpiresAt: now.Add(24 * time.Hour),
+		},
+	})
+	assert.Nil(t, err)
+	instanceID := getDisposableInstanceID()
+	err = s.store.WriteInstance(service.Instance{
+		InstanceID: instanceID,
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateProvisioned,
+	})
+	assert.Nil(t, err)
+	testCases := []struct {
+		instanceID string
+		expiresAt  time.Time
+	}{
+		{instanceID: sandboxInstanceID, expiresAt: now.Add(48 * time.Hour)},
+		// Expiry can only be put off, not brought forward
+		{instanceID: sandboxInstanceID, expiresAt: now.Add(30 * time.Minute)},
+		// Only sandbox instances expire
+		{instanceID: instanceID, expiresAt: now.Add(8 * time.Hour)},
+	}
+	for _, testCase := range testCases {
+		req, err := getUpdateRequest(
+			testCase.instanceID,
+			map[string]string{
+				"accepts_incomplete": "true",
+			},
+			&UpdatingRequest{
+				ServiceID: fake.ServiceID,
+				PlanID:    fake.StandardPlanID,
+				Parameters: map[string]interface{}{
+					"expiresAt": testCase.expiresAt.Format(time.RFC3339),
+				},
+			},
+		)
+		assert.Nil(t, err)
+		rr := httptest.NewRecorder()
+		s.router.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	}
+}
+
+func TestUpdatingWithBlueGreenUpdateProvisionsGreenInstance(t *testing.T) {
+	s, m, err := getTestServer("", "")
+	assert.Nil(t, err)
+	m.ServiceManager.BlueGreenUpdateBehavior = func(
+		service.Instance,
+		service.Plan,
+		service.UpdatingParameters,
+	) bool {
+		return true
+	}
+	instanceID := getDisposableInstanceID()
+	err = s.store.WriteInstance(service.Instance{
+		InstanceID: instanceID,
+		ServiceID:  fake.ServiceID,
+		PlanID:     fake.StandardPlanID,
+		Status:     service.InstanceStateProvisioned,
+		ProvisioningParameters: &fake.ProvisioningParameters{
+			SomeParameter: "blue",
+		},
+	})
+	assert.Nil(t, err)
+	req, err := getUpdateRequest(
+		instanceID,
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&UpdatingRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+			Parameters: map[string]interface{}{
+				"someParameter": "fake",
+			},
+		},
+	)
+	assert.Nil(t, err)
+	e := s.asyncEngine.(*fakeAsync.Engine)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	assert.Equal(t, responseUpdatingAccepted, rr.Body.Bytes())
+	assert.Equal(t, 1, len(e.SubmittedTasks))
+	for _, task := range e.SubmittedTasks {
+		assert.Equal(t, "executeGreenProvisioningStep", task.GetJobName())
+		assert.Equal(t, instanceID, task.GetArgs()["instanceID"])
+	}
+	instance, _, err := s.store.GetInstance(instanceID)
+	assert.Nil(t, err)
+	assert.Equal(t, service.InstanceStateUpdating, instance.Status)
+	assert.Equal(
+		t,
+		service.BlueGreenPhaseProvisioningGreen,
+		instance.BlueGreen.Phase,
+	)
+	// The update isn't applied to the blue instance itself
+	assert.Equal(
+		t,
+		&fake.UpdatingParameters{},
+		instance.UpdatingParameters,
+	)
+	green, ok, err := s.store.GetInstance(service.GetGreenInstanceID(instanceID))
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, service.InstanceStateProvisioning, green.Status)
+	assert.Equal(
+		t,
+		"fake",
+		green.UpdatingParameters.(*fake.UpdatingParameters).SomeParameter,
+	)
+	assert.Equal(
+		t,
+		"blue",
+		green.ProvisioningParameters.(*fake.ProvisioningParameters).SomeParameter,
+	)
+
+	// Another blue-green update can't begin while the green instance exists
+	instance.Status = service.InstanceStateProvisioned
+	instance.BlueGreen = nil
+	assert.Nil(t, s.store.WriteInstance(instance))
+	rr = httptest.NewRecorder()
+	req, err = getUpdateRequest(
+		instanceID,
+		map[string]string{
+			"accepts_incomplete": "true",
+		},
+		&UpdatingRequest{
+			ServiceID: fake.ServiceID,
+			PlanID:    fake.StandardPlanID,
+			Parameters: map[string]interface{}{
+				"someParameter": "fake",
+			},
+		},
+	)
+	assert.Nil(t, err)
+	s.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
+
 func getUpdateRequest(
 	instanceID string,
 	queryParams map[string]string,
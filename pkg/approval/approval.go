@@ -0,0 +1,51 @@
+package approval
+
+// Decisions an external approval service may make about a provisioning
+// request
+const (
+	DecisionPending  = "pending"
+	DecisionApproved = "approved"
+	DecisionDenied   = "denied"
+)
+
+// Request describes a provisioning request that awaits approval
+type Request struct {
+	InstanceID       string            `json:"instanceId"`
+	ServiceID        string            `json:"serviceId"`
+	ServiceName      string            `json:"serviceName"`
+	PlanID           string            `json:"planId"`
+	PlanName         string            `json:"planName"`
+	Location         string            `json:"location"`
+	ResourceGroup    string            `json:"resourceGroup"`
+	OrganizationGUID string            `json:"organizationGuid"`
+	Tags             map[string]string `json:"tags"`
+	// EstimatedMonthlyCost and Currency are omitted if the broker doesn't
+	// estimate the cost of new instances, or couldn't estimate this one's
+	EstimatedMonthlyCost *float64 `json:"estimatedMonthlyCost,omitempty"`
+	Currency             string   `json:"currency,omitempty"`
+	// CostThreshold is the threshold the estimated monthly cost exceeds, if
+	// that's why approval is requested. It is in the same currency.
+	CostThreshold *float64 `json:"costThreshold,omitempty"`
+}
+
+// Response is an external approval service's decision about a provisioning
+// request. While the decision is pending, ID identifies the request to the
+// approval service so that its decision can be retrieved later.
+type Response struct {
+	ID       string `json:"id"`
+	Decision string `json:"decision"`
+	Reason   string `json:"reason"`
+}
+
+// Approver is an interface to be implemented by any component capable of
+// asking an external approval service to approve provisioning requests
+type Approver interface {
+	// RequestApproval submits the given request for approval. The same request
+	// may be submitted more than once, e.g. if the broker is restarted before
+	// it has recorded the response; approval services should identify requests
+	// by their instance IDs.
+	RequestApproval(Request) (Response, error)
+	// GetDecision retrieves the approval service's current decision about the
+	// request it identified by the given ID
+	GetDecision(id string) (Response, error)
+}
@@ -0,0 +1,133 @@
+package approval
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const webhookTimeout = 30 * time.Second
+
+type webhookApprover struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// NewWebhookApprover returns an Approver that submits provisioning requests
+// for approval by POSTing them, as JSON, to the given URL, and that retrieves
+// pending decisions by GETting the given URL followed by the ID the approval
+// service assigned to the request. If a token is given, it is sent as a bearer
+// token with every request.
+func NewWebhookApprover(url string, token string) Approver {
+	return &webhookApprover{
+		url:   strings.TrimSuffix(url, "/"),
+		token: token,
+		client: &http.Client{
+			Timeout: webhookTimeout,
+		},
+	}
+}
+
+func (w *webhookApprover) RequestApproval(req Request) (Response, error) {
+	reqJSON, err := json.Marshal(req)
+	if err != nil {
+		return Response{}, fmt.Errorf(
+			"error marshaling approval request: %s",
+			err,
+		)
+	}
+	httpReq, err := http.NewRequest(
+		http.MethodPost,
+		w.url,
+		bytes.NewBuffer(reqJSON),
+	)
+	if err != nil {
+		return Response{}, fmt.Errorf(
+			"error preparing approval request: %s",
+			err,
+		)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	return w.send(httpReq)
+}
+
+func (w *webhookApprover) GetDecision(id string) (Response, error) {
+	httpReq, err := http.NewRequest(
+		http.MethodGet,
+		fmt.Sprintf("%s/%s", w.url, url.PathEscape(id)),
+		nil,
+	)
+	if err != nil {
+		return Response{}, fmt.Errorf(
+			"error preparing approval decision request: %s",
+			err,
+		)
+	}
+	resp, err := w.send(httpReq)
+	if err != nil {
+		return resp, err
+	}
+	// The approval service needn't repeat the ID it assigned to the request
+	if resp.ID == "" {
+		resp.ID = id
+	}
+	return resp, nil
+}
+
+// send sends the given request to the approval service and returns the
+// response, which is validated
+func (w *webhookApprover) send(httpReq *http.Request) (Response, error) {
+	if w.token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+w.token)
+	}
+	httpResp, err := w.client.Do(httpReq)
+	if err != nil {
+		return Response{}, fmt.Errorf(
+			"error contacting approval service: %s",
+			err,
+		)
+	}
+	defer httpResp.Body.Close() // nolint: errcheck
+	switch httpResp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusAccepted:
+	default:
+		return Response{}, fmt.Errorf(
+			"error contacting approval service: unexpected status code %d",
+			httpResp.StatusCode,
+		)
+	}
+	resp := Response{}
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return resp, fmt.Errorf(
+			"error unmarshaling approval service response: %s",
+			err,
+		)
+	}
+	return resp, validateResponse(resp, httpReq.Method == http.MethodPost)
+}
+
+func validateResponse(resp Response, requireID bool) error {
+	switch resp.Decision {
+	case DecisionApproved, DecisionDenied:
+		return nil
+	case DecisionPending:
+		if requireID && resp.ID == "" {
+			return errors.New(
+				"invalid approval service response: a pending decision must " +
+					"identify the request",
+			)
+		}
+		return nil
+	default:
+		return fmt.Errorf(
+			`invalid approval service response: unknown decision "%s"`,
+			resp.Decision,
+		)
+	}
+}
@@ -0,0 +1,96 @@
+package approval
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookApproverRequestApproval(t *testing.T) {
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+			req := Request{}
+			assert.Nil(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, "instance", req.InstanceID)
+			w.WriteHeader(http.StatusAccepted)
+			_, err := w.Write([]byte(`{"id":"abc","decision":"pending"}`))
+			assert.Nil(t, err)
+		}),
+	)
+	defer server.Close()
+	resp, err := NewWebhookApprover(server.URL, "token").RequestApproval(
+		Request{InstanceID: "instance"},
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, "abc", resp.ID)
+	assert.Equal(t, DecisionPending, resp.Decision)
+}
+
+func TestWebhookApproverRequestApprovalWithoutID(t *testing.T) {
+	server := getTestApprovalServer(
+		t,
+		http.StatusOK,
+		`{"decision":"pending"}`,
+	)
+	defer server.Close()
+	_, err := NewWebhookApprover(server.URL, "").RequestApproval(Request{})
+	assert.NotNil(t, err)
+}
+
+func TestWebhookApproverGetDecision(t *testing.T) {
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodGet, r.Method)
+			assert.Equal(t, "/approvals/abc", r.URL.Path)
+			assert.Empty(t, r.Header.Get("Authorization"))
+			_, err := w.Write(
+				[]byte(`{"decision":"denied","reason":"change freeze"}`),
+			)
+			assert.Nil(t, err)
+		}),
+	)
+	defer server.Close()
+	resp, err := NewWebhookApprover(server.URL+"/approvals/", "").GetDecision(
+		"abc",
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, "abc", resp.ID)
+	assert.Equal(t, DecisionDenied, resp.Decision)
+	assert.Equal(t, "change freeze", resp.Reason)
+}
+
+func TestWebhookApproverWithInvalidResponse(t *testing.T) {
+	for _, testCase := range []struct {
+		statusCode int
+		body       string
+	}{
+		{http.StatusInternalServerError, `{"decision":"approved"}`},
+		{http.StatusOK, `{"decision":"maybe"}`},
+		{http.StatusOK, `not json`},
+	} {
+		server := getTestApprovalServer(t, testCase.statusCode, testCase.body)
+		_, err := NewWebhookApprover(server.URL, "").GetDecision("abc")
+		assert.NotNil(t, err, testCase.body)
+		server.Close()
+	}
+}
+
+func getTestApprovalServer(
+	t *testing.T,
+	statusCode int,
+	body string,
+) *httptest.Server {
+	return httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(statusCode)
+			_, err := w.Write([]byte(body))
+			assert.Nil(t, err)
+		}),
+	)
+}
@@ -16,4 +16,18 @@ type Engine interface {
 	// until a fatal error is encountered or the context passed to it has been
 	// canceled. Run always returns a non-nil error.
 	Run(context.Context) error
+	// GetWorkerCount returns the number of workers the async engine currently
+	// has available to execute tasks
+	GetWorkerCount() int
+	// GetTenantPoolUtilization returns the utilization of the worker pool
+	// dedicated to each tenant that has one, keyed by tenant
+	GetTenantPoolUtilization() map[string]PoolUtilization
+}
+
+// PoolUtilization describes how busy a pool of workers is
+type PoolUtilization struct {
+	Workers int
+	// BusyWorkers is the number of the pool's workers that are executing a
+	// task, as opposed to waiting for one
+	BusyWorkers int
 }
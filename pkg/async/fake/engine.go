@@ -14,6 +14,9 @@ type RunFn func(context.Context) error
 type Engine struct {
 	SubmittedTasks map[string]async.Task
 	RunBehavior    RunFn
+	WorkerCount    int
+	// TenantPoolUtilization is returned by GetTenantPoolUtilization
+	TenantPoolUtilization map[string]async.PoolUtilization
 }
 
 // NewEngine returns a new, fake implementation of async.Engine used for testing
@@ -43,6 +46,18 @@ func (e *Engine) Run(ctx context.Context) error {
 	return e.RunBehavior(ctx)
 }
 
+// GetWorkerCount returns the number of workers the fake async engine has been
+// told it has
+func (e *Engine) GetWorkerCount() int {
+	return e.WorkerCount
+}
+
+// GetTenantPoolUtilization returns the utilization of tenants' worker pools
+// that the fake async engine has been told of
+func (e *Engine) GetTenantPoolUtilization() map[string]async.PoolUtilization {
+	return e.TenantPoolUtilization
+}
+
 func defaultEngineRunBehavior(ctx context.Context) error {
 	<-ctx.Done()
 	return ctx.Err()
@@ -74,6 +74,16 @@ func (e *engine) defaultClean(
 				); err != nil {
 					return err
 				}
+				// Tasks a dead worker was sorting into tenants' queues are simply
+				// returned to the pending queue to be sorted again
+				if err := e.cleanSortingTaskQueue(
+					ctx,
+					workerID,
+					getSortingTaskQueueName(workerID),
+					pendingTaskQueueName,
+				); err != nil {
+					return err
+				}
 				err = e.redisClient.SRem(workerSetName, workerID).Err()
 				if err != nil && err != redis.Nil {
 					return fmt.Errorf(
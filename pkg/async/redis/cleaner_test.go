@@ -9,7 +9,13 @@ import (
 )
 
 func TestDefaultCleanCleansDeadWorkers(t *testing.T) {
-	e := NewEngine(redisClient).(*engine)
+	e := NewEngine(
+		redisClient,
+		FairSchedulingConfig{},
+		WorkerPoolConfig{},
+		TenantPoolsConfig{},
+		nil,
+	).(*engine)
 
 	// Add some workers to the worker set, but do not add any heartbeats for these
 	// workers. i.e. They should appear dead.
@@ -41,6 +47,19 @@ func TestDefaultCleanCleansDeadWorkers(t *testing.T) {
 		return nil
 	}
 
+	// Override the default cleanSortingTaskQueue function to just count how many
+	// times it is invoked
+	var cleanSortingTaskQueueCallCount int
+	e.cleanSortingTaskQueue = func(
+		context.Context,
+		string,
+		string,
+		string,
+	) error {
+		cleanSortingTaskQueueCallCount++
+		return nil
+	}
+
 	// Under nominal conditions, defaultClean could block for a very long time,
 	// unless the context it is passed is canceled. Use a context that will cancel
 	// itself after 2 seconds to make defaultClean STOP working so we can then
@@ -73,14 +92,21 @@ func TestDefaultCleanCleansDeadWorkers(t *testing.T) {
 		)
 	}
 
-	// Assert cleanActiveTaskQueue and cleanWatchedTaskQueue were each invoked
-	// once per dead worker
+	// Assert cleanActiveTaskQueue, cleanWatchedTaskQueue, and
+	// cleanSortingTaskQueue were each invoked once per dead worker
 	assert.Equal(t, workerCount, cleanActiveTaskQueueCallCount)
 	assert.Equal(t, workerCount, cleanWatchedTaskQueueCallCount)
+	assert.Equal(t, workerCount, cleanSortingTaskQueueCallCount)
 }
 
 func TestDefaultCleanDoesNotCleanLiveWorkers(t *testing.T) {
-	e := NewEngine(redisClient).(*engine)
+	e := NewEngine(
+		redisClient,
+		FairSchedulingConfig{},
+		WorkerPoolConfig{},
+		TenantPoolsConfig{},
+		nil,
+	).(*engine)
 
 	// Add a worker to the worker set. Also add a heartbeat so this worker appears
 	// to be alive.
@@ -112,6 +138,19 @@ func TestDefaultCleanDoesNotCleanLiveWorkers(t *testing.T) {
 		return nil
 	}
 
+	// Override the default cleanSortingTaskQueue function to just count how many
+	// times it is invoked
+	var cleanSortingTaskQueueCallCount int
+	e.cleanSortingTaskQueue = func(
+		context.Context,
+		string,
+		string,
+		string,
+	) error {
+		cleanSortingTaskQueueCallCount++
+		return nil
+	}
+
 	// Under nominal conditions, defaultClean could block for a very long time,
 	// unless the context it is passed is canceled. Use a context that will cancel
 	// itself after 2 seconds to make defaultClean STOP working so we can then
@@ -144,14 +183,21 @@ func TestDefaultCleanDoesNotCleanLiveWorkers(t *testing.T) {
 		)
 	}
 
-	// Assert neither cleanActiveTaskQueue and cleanWatchedTaskQueue were ever
-	// invoked
+	// Assert none of cleanActiveTaskQueue, cleanWatchedTaskQueue, and
+	// cleanSortingTaskQueue were ever invoked
 	assert.Equal(t, 0, cleanActiveTaskQueueCallCount)
 	assert.Equal(t, 0, cleanWatchedTaskQueueCallCount)
+	assert.Equal(t, 0, cleanSortingTaskQueueCallCount)
 }
 
 func TestDefaultCleanWorkerQueue(t *testing.T) {
-	e := NewEngine(redisClient).(*engine)
+	e := NewEngine(
+		redisClient,
+		FairSchedulingConfig{},
+		WorkerPoolConfig{},
+		TenantPoolsConfig{},
+		nil,
+	).(*engine)
 
 	sourceQueueName := getDisposableQueueName()
 	destinationQueueName := getDisposableQueueName()
@@ -196,7 +242,13 @@ func TestDefaultCleanWorkerQueue(t *testing.T) {
 }
 
 func TestDefaultCleanWorkerQueueRespondsToCanceledContext(t *testing.T) {
-	e := NewEngine(redisClient).(*engine)
+	e := NewEngine(
+		redisClient,
+		FairSchedulingConfig{},
+		WorkerPoolConfig{},
+		TenantPoolsConfig{},
+		nil,
+	).(*engine)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -12,6 +12,9 @@ const (
 	aliveIndicator        = "alive"
 	pendingTaskQueueName  = "pendingTasks"
 	deferredTaskQueueName = "deferredTasks"
+	// tenantSetName is the name of the set of tenants that, when fair scheduling
+	// is enabled, may have tasks pending dispatch
+	tenantSetName = "tenants"
 )
 
 func getActiveTaskQueueName(workerID string) string {
@@ -22,6 +25,21 @@ func getWatchedTaskQueueName(workerID string) string {
 	return fmt.Sprintf("watched-tasks:%s", workerID)
 }
 
+func getSortingTaskQueueName(workerID string) string {
+	return fmt.Sprintf("sorting-tasks:%s", workerID)
+}
+
+func getTenantTaskQueueName(tenant string) string {
+	return fmt.Sprintf("tenant-tasks:%s", tenant)
+}
+
+// getTenantOrderQueueName returns the name of the queue that, when fair
+// scheduling is disabled, records the tenant of each task sorted into the
+// queues of tenants in the given set, in the order the tasks were sorted
+func getTenantOrderQueueName(tenantSetName string) string {
+	return fmt.Sprintf("%s:order", tenantSetName)
+}
+
 func (e *engine) getTaskFromJSON(
 	taskJSON []byte,
 	queueName string,
@@ -26,3 +26,7 @@ func getDisposableWorkerID() string {
 func getDisposableWorkerSetName() string {
 	return uuid.NewV4().String()
 }
+
+func getDisposableTenant() string {
+	return uuid.NewV4().String()
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/Azure/open-service-broker-azure/pkg/async"
 	log "github.com/Sirupsen/logrus"
@@ -11,12 +12,40 @@ import (
 	uuid "github.com/satori/go.uuid"
 )
 
+// FairSchedulingConfig encapsulates options for scheduling tasks fairly across
+// the tenants on whose behalf they are executed. When enabled, tenants take
+// turns having their pending tasks dispatched to workers instead of all
+// pending tasks being dispatched in the order they were submitted.
+type FairSchedulingConfig struct {
+	Enabled bool
+	// Weights indicates how many tasks may be dispatched on each named tenant's
+	// turn. Tenants not named here have one task dispatched per turn.
+	Weights map[string]int
+}
+
+// ThrottleFn defines functions used to postpone the execution of tasks. They
+// return how long the execution of the given task should be postponed for, or
+// zero if it may be executed right away.
+type ThrottleFn func(async.Task) time.Duration
+
 // engine is a Redis-based implementation of the Engine interface.
 type engine struct {
-	workerID     string
-	jobsFns      map[string]async.JobFn
-	jobsFnsMutex sync.RWMutex
-	redisClient  *redis.Client
+	workerID       string
+	jobsFns        map[string]async.JobFn
+	jobsFnsMutex   sync.RWMutex
+	redisClient    *redis.Client
+	fairScheduling FairSchedulingConfig
+	// throttle, if non-nil, is consulted before each task is executed
+	throttle ThrottleFn
+	// workerPoolConfig bounds the size of pool, which is created by Run and
+	// executes the tasks of tenants without a pool of their own
+	workerPoolConfig WorkerPoolConfig
+	pool             *workerPool
+	workerPoolMutex  sync.RWMutex
+	tenantPools      TenantPoolsConfig
+	// pools are the pools dedicated to tenants, keyed by tenant
+	pools            map[string]*tenantPool
+	tenantPoolsMutex sync.RWMutex
 	// This allows tests to inject an alternative implementation of this function
 	clean cleanFn
 	// This allows tests to inject an alternative implementation of this function
@@ -24,6 +53,8 @@ type engine struct {
 	// This allows tests to inject an alternative implementation of this function
 	cleanWatchedTaskQueue cleanWorkerQueueFn
 	// This allows tests to inject an alternative implementation of this function
+	cleanSortingTaskQueue cleanWorkerQueueFn
+	// This allows tests to inject an alternative implementation of this function
 	runHeart runHeartFn
 	// This allows tests to inject an alternative implementation of this function
 	heartbeat heartbeatFn
@@ -34,26 +65,45 @@ type engine struct {
 	// This allows tests to inject an alternative implementation of this function
 	executeTasks executeTasksFn
 	// This allows tests to inject an alternative implementation of this function
+	sortTasks sortTasksFn
+	// This allows tests to inject an alternative implementation of this function
+	dispatchTasks dispatchTasksFn
+	// This allows tests to inject an alternative implementation of this function
 	watchDeferredTask watchDeferredTaskFn
 }
 
 // NewEngine returns a new Redis-based implementation of the aync.Engine
-// interface
-func NewEngine(redisClient *redis.Client) async.Engine {
+// interface. The throttle function may be nil, in which case tasks are
+// executed as soon as they are received.
+func NewEngine(
+	redisClient *redis.Client,
+	fairScheduling FairSchedulingConfig,
+	workerPoolConfig WorkerPoolConfig,
+	tenantPools TenantPoolsConfig,
+	throttle ThrottleFn,
+) async.Engine {
 	workerID := uuid.NewV4().String()
 	e := &engine{
-		workerID:    workerID,
-		jobsFns:     make(map[string]async.JobFn),
-		redisClient: redisClient,
+		workerID:         workerID,
+		jobsFns:          make(map[string]async.JobFn),
+		redisClient:      redisClient,
+		fairScheduling:   fairScheduling,
+		throttle:         throttle,
+		workerPoolConfig: workerPoolConfig.withDefaults(),
+		tenantPools:      tenantPools.withDefaults(),
+		pools:            map[string]*tenantPool{},
 	}
 	e.clean = e.defaultClean
 	e.cleanActiveTaskQueue = e.defaultCleanWorkerQueue
 	e.cleanWatchedTaskQueue = e.defaultCleanWorkerQueue
+	e.cleanSortingTaskQueue = e.defaultCleanWorkerQueue
 	e.runHeart = e.defaultRunHeart
 	e.heartbeat = e.defaultHeartbeat
 	e.receivePendingTasks = e.defaultReceiveTasks
 	e.receiveDeferredTasks = e.defaultReceiveTasks
 	e.executeTasks = e.defaultExecuteTasks
+	e.sortTasks = e.defaultSortTasks
+	e.dispatchTasks = e.defaultDispatchTasks
 	e.watchDeferredTask = e.defaultWatchDeferredTask
 	return e
 }
@@ -145,23 +195,93 @@ func (e *engine) Run(ctx context.Context) error {
 	go func() {
 		pendingReceiverRetCh := make(chan []byte)
 		pendingReceiverErrCh := make(chan error)
+		executorRetCh := pendingReceiverRetCh
 		executorErrCh := make(chan error)
-		go e.receivePendingTasks(
-			ctx,
-			pendingTaskQueueName,
-			getActiveTaskQueueName(e.workerID),
-			pendingReceiverRetCh,
-			pendingReceiverErrCh,
-		)
-		// Fan out to 5 executors
-		for range [5]struct{}{} {
-			go e.executeTasks(
+		sorterErrCh := make(chan error)
+		dispatcherErrCh := make(chan error)
+		if !e.sortsTasks() {
+			go e.receivePendingTasks(
+				ctx,
+				pendingTaskQueueName,
+				getActiveTaskQueueName(e.workerID),
+				pendingReceiverRetCh,
+				pendingReceiverErrCh,
+			)
+		} else {
+			// With fair scheduling or tenant pools enabled, pending tasks are first
+			// sorted into queues belonging to their tenants. Executors of the
+			// default pool are fed from those queues, one tenant at a time, and
+			// executors of tenants' own pools from their tenants' queues alone.
+			go e.receivePendingTasks(
 				ctx,
+				pendingTaskQueueName,
+				getSortingTaskQueueName(e.workerID),
 				pendingReceiverRetCh,
+				pendingReceiverErrCh,
+			)
+			go e.sortTasks(
+				ctx,
+				pendingReceiverRetCh,
+				getSortingTaskQueueName(e.workerID),
+				tenantSetName,
+				deferredTaskQueueName,
+				sorterErrCh,
+			)
+			if e.tenantPools.GetPools != nil {
+				// Tenants' pools must be in place before the default pool's
+				// dispatcher starts, lest it dispatch those tenants' tasks
+				pools, err := e.tenantPools.GetPools()
+				if err != nil {
+					select {
+					case errCh <- &errTenantPoolsNotLoaded{
+						workerID: e.workerID,
+						err:      err,
+					}:
+					case <-ctx.Done():
+					}
+					return
+				}
+				e.applyTenantPools(
+					ctx,
+					pools,
+					getActiveTaskQueueName(e.workerID),
+					executorErrCh,
+					dispatcherErrCh,
+				)
+				go e.runTenantPools(
+					ctx,
+					getActiveTaskQueueName(e.workerID),
+					executorErrCh,
+					dispatcherErrCh,
+				)
+			}
+			executorRetCh = make(chan []byte)
+			go e.dispatchTasks(
+				ctx,
+				tenantSetName,
+				getActiveTaskQueueName(e.workerID),
+				executorRetCh,
+				dispatcherErrCh,
+			)
+		}
+		// Fan out to as many executors as the worker pool calls for
+		pool := newWorkerPool(func(stopCh chan struct{}, busyWorkers *int32) {
+			e.executeTasks(
+				ctx,
+				executorRetCh,
+				stopCh,
+				busyWorkers,
 				pendingTaskQueueName,
 				deferredTaskQueueName,
 				executorErrCh,
 			)
+		})
+		pool.resize(e.workerPoolConfig.MinWorkers)
+		e.workerPoolMutex.Lock()
+		e.pool = pool
+		e.workerPoolMutex.Unlock()
+		if e.workerPoolConfig.MaxWorkers > e.workerPoolConfig.MinWorkers {
+			go e.scaleWorkerPool(ctx, pool)
 		}
 		select {
 		case err := <-pendingReceiverErrCh:
@@ -170,6 +290,10 @@ func (e *engine) Run(ctx context.Context) error {
 				queueName: pendingTaskQueueName,
 				err:       err,
 			}
+		case err := <-sorterErrCh:
+			errCh <- &errTaskSorterStopped{workerID: e.workerID, err: err}
+		case err := <-dispatcherErrCh:
+			errCh <- &errTaskDispatcherStopped{workerID: e.workerID, err: err}
 		case err := <-executorErrCh:
 			errCh <- &errTaskExecutorStopped{workerID: e.workerID, err: err}
 		case <-ctx.Done():
@@ -10,8 +10,20 @@ import (
 
 func TestNewEnginesHaveUniqueWorkerIDs(t *testing.T) {
 	// Create two engines
-	e1 := NewEngine(redisClient).(*engine)
-	e2 := NewEngine(redisClient).(*engine)
+	e1 := NewEngine(
+		redisClient,
+		FairSchedulingConfig{},
+		WorkerPoolConfig{},
+		TenantPoolsConfig{},
+		nil,
+	).(*engine)
+	e2 := NewEngine(
+		redisClient,
+		FairSchedulingConfig{},
+		WorkerPoolConfig{},
+		TenantPoolsConfig{},
+		nil,
+	).(*engine)
 
 	// Assert that their workerIDs are at least different from one another
 	assert.NotEqual(t, e1.workerID, e2.workerID)
@@ -207,6 +219,8 @@ func TestRunBlocksUntilExecuteTasksSendsError(t *testing.T) {
 	e.executeTasks = func(
 		ctx context.Context,
 		_ chan []byte,
+		_ chan struct{},
+		_ *int32,
 		_ string,
 		_ string,
 		errCh chan error,
@@ -253,6 +267,178 @@ func TestRunBlocksUntilExecuteTasksSendsError(t *testing.T) {
 	}
 }
 
+func TestRunBlocksUntilSortTasksSendsError(t *testing.T) {
+	e := getTestEngine()
+	e.fairScheduling.Enabled = true
+
+	// Override the engine's clean function so it just communicates when the
+	// context it was passed has been canceled
+	contextCanceledCh := make(chan struct{})
+	e.clean = func(
+		ctx context.Context,
+		_ string,
+		_ string,
+		_ string,
+		_ time.Duration,
+	) error {
+		<-ctx.Done()
+		close(contextCanceledCh)
+		return ctx.Err()
+	}
+
+	// Override the engine's sortTasks function so it just sends an error
+	e.sortTasks = func(
+		ctx context.Context,
+		_ chan []byte,
+		_ string,
+		_ string,
+		_ string,
+		errCh chan error,
+	) {
+		select {
+		case errCh <- errSome:
+		case <-ctx.Done():
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Call Run in a goroutine. If it never unblocks, as we hope it does, we don't
+	// want the test to stall.
+	errCh := make(chan error)
+	go func() {
+		errCh <- e.Run(ctx)
+	}()
+
+	// Assert that the error returned from the Run function wraps the error that
+	// the overridden sortTasks function sent
+	select {
+	case err := <-errCh:
+		assert.Equal(
+			t,
+			&errTaskSorterStopped{
+				workerID: e.workerID,
+				err:      errSome,
+			},
+			err,
+		)
+	case <-time.After(time.Second):
+		assert.Fail(t, "an error should have been received, but wasn't")
+	}
+
+	// Assert that the context got canceled. It's helpful to know that when the
+	// task sorter stops, the rest of the worker components are also signaled
+	// to shut down.
+	select {
+	case <-contextCanceledCh:
+	case <-time.After(time.Second):
+		assert.Fail(t, "context should have been canceled, but it was not")
+	}
+}
+
+func TestRunFailsIfTenantPoolsCannotBeLoaded(t *testing.T) {
+	e := getTestEngine()
+	e.tenantPools.GetPools = func() (map[string]TenantPool, error) {
+		return nil, errSome
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Call Run in a goroutine. If it never unblocks, as we hope it does, we don't
+	// want the test to stall.
+	errCh := make(chan error)
+	go func() {
+		errCh <- e.Run(ctx)
+	}()
+
+	// Assert that the error returned from the Run function wraps the error that
+	// the overridden GetPools function returned
+	select {
+	case err := <-errCh:
+		assert.Equal(
+			t,
+			&errTenantPoolsNotLoaded{
+				workerID: e.workerID,
+				err:      errSome,
+			},
+			err,
+		)
+	case <-time.After(time.Second):
+		assert.Fail(t, "an error should have been received, but wasn't")
+	}
+}
+
+func TestRunBlocksUntilDispatchTasksSendsError(t *testing.T) {
+	e := getTestEngine()
+	e.fairScheduling.Enabled = true
+
+	// Override the engine's clean function so it just communicates when the
+	// context it was passed has been canceled
+	contextCanceledCh := make(chan struct{})
+	e.clean = func(
+		ctx context.Context,
+		_ string,
+		_ string,
+		_ string,
+		_ time.Duration,
+	) error {
+		<-ctx.Done()
+		close(contextCanceledCh)
+		return ctx.Err()
+	}
+
+	// Override the engine's dispatchTasks function so it just sends an error
+	e.dispatchTasks = func(
+		ctx context.Context,
+		_ string,
+		_ string,
+		_ chan []byte,
+		errCh chan error,
+	) {
+		select {
+		case errCh <- errSome:
+		case <-ctx.Done():
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Call Run in a goroutine. If it never unblocks, as we hope it does, we don't
+	// want the test to stall.
+	errCh := make(chan error)
+	go func() {
+		errCh <- e.Run(ctx)
+	}()
+
+	// Assert that the error returned from the Run function wraps the error that
+	// the overridden dispatchTasks function sent
+	select {
+	case err := <-errCh:
+		assert.Equal(
+			t,
+			&errTaskDispatcherStopped{
+				workerID: e.workerID,
+				err:      errSome,
+			},
+			err,
+		)
+	case <-time.After(time.Second):
+		assert.Fail(t, "an error should have been received, but wasn't")
+	}
+
+	// Assert that the context got canceled. It's helpful to know that when the
+	// task dispatcher stops, the rest of the worker components are also signaled
+	// to shut down.
+	select {
+	case <-contextCanceledCh:
+	case <-time.After(time.Second):
+		assert.Fail(t, "context should have been canceled, but it was not")
+	}
+}
+
 func TestRunBlocksUntilReceiveDeferredTasksSendError(t *testing.T) {
 	e := getTestEngine()
 
@@ -440,7 +626,13 @@ func TestRunRespondsToCanceledContext(t *testing.T) {
 // are passed is canceled. Individual test cases can selectively revert or
 // amend these overrides to test specific scenarios.
 func getTestEngine() *engine {
-	e := NewEngine(redisClient).(*engine)
+	e := NewEngine(
+		redisClient,
+		FairSchedulingConfig{},
+		WorkerPoolConfig{},
+		TenantPoolsConfig{},
+		nil,
+	).(*engine)
 	// Cleaner loop
 	e.clean = func(
 		ctx context.Context,
@@ -481,8 +673,31 @@ func getTestEngine() *engine {
 	e.executeTasks = func(
 		ctx context.Context,
 		_ chan []byte,
+		_ chan struct{},
+		_ *int32,
+		_ string,
 		_ string,
+		_ chan error,
+	) {
+		<-ctx.Done()
+	}
+	// Tasks sorter
+	e.sortTasks = func(
+		ctx context.Context,
+		_ chan []byte,
 		_ string,
+		_ string,
+		_ string,
+		_ chan error,
+	) {
+		<-ctx.Done()
+	}
+	// Tasks dispatcher
+	e.dispatchTasks = func(
+		ctx context.Context,
+		_ string,
+		_ string,
+		_ chan []byte,
 		_ chan error,
 	) {
 		<-ctx.Done()
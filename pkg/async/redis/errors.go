@@ -58,6 +58,32 @@ func (e *errTaskExecutorStopped) Error() string {
 	return fmt.Sprintf("%s: %s", baseMsg, e.err)
 }
 
+type errTaskSorterStopped struct {
+	workerID string
+	err      error
+}
+
+func (e *errTaskSorterStopped) Error() string {
+	baseMsg := fmt.Sprintf(`worker "%s" task sorter stopped`, e.workerID)
+	if e.err == nil {
+		return baseMsg
+	}
+	return fmt.Sprintf("%s: %s", baseMsg, e.err)
+}
+
+type errTaskDispatcherStopped struct {
+	workerID string
+	err      error
+}
+
+func (e *errTaskDispatcherStopped) Error() string {
+	baseMsg := fmt.Sprintf(`worker "%s" task dispatcher stopped`, e.workerID)
+	if e.err == nil {
+		return baseMsg
+	}
+	return fmt.Sprintf("%s: %s", baseMsg, e.err)
+}
+
 type errDeferredTaskWatcherStopped struct {
 	workerID string
 	err      error
@@ -74,6 +100,19 @@ func (e *errDeferredTaskWatcherStopped) Error() string {
 	return fmt.Sprintf("%s: %s", baseMsg, e.err)
 }
 
+type errTenantPoolsNotLoaded struct {
+	workerID string
+	err      error
+}
+
+func (e *errTenantPoolsNotLoaded) Error() string {
+	baseMsg := fmt.Sprintf(`worker "%s" tenant pools not loaded`, e.workerID)
+	if e.err == nil {
+		return baseMsg
+	}
+	return fmt.Sprintf("%s: %s", baseMsg, e.err)
+}
+
 type errDuplicateJob struct {
 	name string
 }
@@ -9,7 +9,13 @@ import (
 )
 
 func TestDefaultRunHeartBlocksUntilBeatErrors(t *testing.T) {
-	e := NewEngine(redisClient).(*engine)
+	e := NewEngine(
+		redisClient,
+		FairSchedulingConfig{},
+		WorkerPoolConfig{},
+		TenantPoolsConfig{},
+		nil,
+	).(*engine)
 
 	// Override default heartbeat function so it just returns an error
 	e.heartbeat = func(time.Duration) error {
@@ -37,7 +43,13 @@ func TestDefaultRunHeartBlocksUntilBeatErrors(t *testing.T) {
 }
 
 func TestDefaultRunHeartRespondsToCanceledContext(t *testing.T) {
-	e := NewEngine(redisClient).(*engine)
+	e := NewEngine(
+		redisClient,
+		FairSchedulingConfig{},
+		WorkerPoolConfig{},
+		TenantPoolsConfig{},
+		nil,
+	).(*engine)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -65,7 +77,13 @@ func TestDefaultRunHeartRespondsToCanceledContext(t *testing.T) {
 }
 
 func TestDefaultHeartbeat(t *testing.T) {
-	e := NewEngine(redisClient).(*engine)
+	e := NewEngine(
+		redisClient,
+		FairSchedulingConfig{},
+		WorkerPoolConfig{},
+		TenantPoolsConfig{},
+		nil,
+	).(*engine)
 
 	err := e.defaultHeartbeat(time.Second)
 	assert.Nil(t, err)
@@ -0,0 +1,371 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/go-redis/redis"
+)
+
+// dispatchPollInterval is how long a dispatcher waits before looking for
+// new tasks after finding none
+const dispatchPollInterval = time.Second
+
+// dispatchTasksFn defines functions used to dispatch tasks from tenants' queues
+// to a destination queue and a return channel
+type dispatchTasksFn func(
+	ctx context.Context,
+	tenantSetName string,
+	destinationQueueName string,
+	retCh chan []byte,
+	errCh chan error,
+)
+
+// dispatchNextTaskScript gives up the place at the head of the tenant order
+// queue, provided it's still the given tenant's, and moves that tenant's oldest
+// task to the destination queue. The place of a tenant that has a pool of its
+// own is given up without a task being moved, since that pool's dispatcher
+// dispatches the tenant's tasks.
+var dispatchNextTaskScript = redis.NewScript(`
+if redis.call("LINDEX", KEYS[1], -1) ~= ARGV[1] then
+  return false
+end
+redis.call("RPOP", KEYS[1])
+if ARGV[2] == "1" then
+  return false
+end
+return redis.call("RPOPLPUSH", KEYS[2], KEYS[3])
+`)
+
+// defaultDispatchTasks dispatches tasks from the queues of tenants without a
+// pool of their own to both a destination queue and a return channel. With
+// fair scheduling enabled, tenants take turns; otherwise, tasks are dispatched
+// in the order they were sorted into their tenants' queues.
+func (e *engine) defaultDispatchTasks(
+	ctx context.Context,
+	tenantSetName string,
+	destinationQueueName string,
+	retCh chan []byte,
+	errCh chan error,
+) {
+	if e.fairScheduling.Enabled {
+		e.dispatchTasksInTurns(
+			ctx,
+			tenantSetName,
+			destinationQueueName,
+			retCh,
+			errCh,
+		)
+		return
+	}
+	e.dispatchTasksInOrder(
+		ctx,
+		tenantSetName,
+		destinationQueueName,
+		retCh,
+		errCh,
+	)
+}
+
+// dispatchTasksInTurns takes turns dispatching tasks from each tenant's queue
+// to both a destination queue and a return channel. On its turn, a tenant has
+// as many tasks dispatched as its weight allows. Tasks are taken from a
+// tenant's queue one at a time, and the next isn't taken until the return
+// channel has accepted the last, so a tenant with many pending tasks cannot
+// delay other tenants' tasks by more than one turn. A task that has been taken
+// but not yet accepted when the context is canceled is returned to its
+// tenant's queue. Tenants with a pool of their own are skipped; their tasks are
+// dispatched by their pools' own dispatchers.
+func (e *engine) dispatchTasksInTurns(
+	ctx context.Context,
+	tenantSetName string,
+	destinationQueueName string,
+	retCh chan []byte,
+	errCh chan error,
+) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	for {
+		dispatchedCount, err := e.dispatchTurns(
+			ctx,
+			tenantSetName,
+			destinationQueueName,
+			retCh,
+		)
+		if err != nil {
+			select {
+			case errCh <- err:
+			case <-ctx.Done():
+			}
+			return
+		}
+		if dispatchedCount == 0 {
+			select {
+			case <-time.After(dispatchPollInterval):
+			case <-ctx.Done():
+				return
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+	}
+}
+
+// dispatchTurns gives each tenant in the tenant set without a pool of its own
+// one turn and returns the number of tasks that were dispatched
+func (e *engine) dispatchTurns(
+	ctx context.Context,
+	tenantSetName string,
+	destinationQueueName string,
+	retCh chan []byte,
+) (int, error) {
+	tenants, err := e.redisClient.SMembers(tenantSetName).Result()
+	if err != nil && err != redis.Nil {
+		return 0, fmt.Errorf(
+			`error retrieving tenants from set "%s": %s`,
+			tenantSetName,
+			err,
+		)
+	}
+	// Take turns in a consistent order
+	sort.Strings(tenants)
+	var dispatchedCount int
+	for _, tenant := range tenants {
+		if e.hasTenantPool(tenant) {
+			continue
+		}
+		count, err := e.dispatchTenantTasks(
+			ctx,
+			tenantSetName,
+			tenant,
+			destinationQueueName,
+			retCh,
+		)
+		if err != nil {
+			return dispatchedCount, err
+		}
+		dispatchedCount += count
+	}
+	return dispatchedCount, nil
+}
+
+// dispatchTasksInOrder dispatches tasks from tenants' queues to both a
+// destination queue and a return channel in the order the tenant order queue
+// records they were sorted, regardless of whose they are. A task that has been
+// taken but not yet accepted when the context is canceled is returned to its
+// tenant's queue, and its place at the head of the order. Tasks left in the
+// queue of a tenant whose pool was taken away have no place in the order, so
+// they're dispatched in turns whenever the order is empty.
+func (e *engine) dispatchTasksInOrder(
+	ctx context.Context,
+	tenantSetName string,
+	destinationQueueName string,
+	retCh chan []byte,
+	errCh chan error,
+) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	orderQueueName := getTenantOrderQueueName(tenantSetName)
+	for {
+		tenant, err := e.redisClient.LIndex(orderQueueName, -1).Result()
+		if err == redis.Nil {
+			var dispatchedCount int
+			dispatchedCount, err = e.dispatchTurns(
+				ctx,
+				tenantSetName,
+				destinationQueueName,
+				retCh,
+			)
+			if err == nil && dispatchedCount == 0 {
+				select {
+				case <-time.After(dispatchPollInterval):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+		var taskJSON []byte
+		if err == nil && tenant != "" {
+			taskJSON, err = e.dispatchNextTask(
+				orderQueueName,
+				tenant,
+				destinationQueueName,
+			)
+		}
+		if err != nil {
+			select {
+			case errCh <- err:
+			case <-ctx.Done():
+			}
+			return
+		}
+		if taskJSON != nil {
+			select {
+			case retCh <- taskJSON:
+			case <-ctx.Done():
+				e.returnUndispatchedTask(
+					tenant,
+					destinationQueueName,
+					orderQueueName,
+					taskJSON,
+				)
+				return
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+	}
+}
+
+// dispatchNextTask moves the oldest task of the given tenant, which was found
+// at the head of the tenant order queue, to the destination queue and returns
+// it. Nil is returned if another dispatcher took the tenant's place first, if
+// the tenant has a pool of its own, or if its queue was emptied by some other
+// means, e.g. by a pool since taken away.
+func (e *engine) dispatchNextTask(
+	orderQueueName string,
+	tenant string,
+	destinationQueueName string,
+) ([]byte, error) {
+	hasTenantPool := "0"
+	if e.hasTenantPool(tenant) {
+		hasTenantPool = "1"
+	}
+	tenantTaskQueueName := getTenantTaskQueueName(tenant)
+	res, err := dispatchNextTaskScript.Run(
+		e.redisClient,
+		[]string{orderQueueName, tenantTaskQueueName, destinationQueueName},
+		tenant,
+		hasTenantPool,
+	).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error receiving task from queue "%s": %s`,
+			tenantTaskQueueName,
+			err,
+		)
+	}
+	taskJSON, _ := res.(string)
+	return []byte(taskJSON), nil
+}
+
+// dispatchTenantTasks dispatches up to as many of the given tenant's tasks as
+// its weight allows and returns the number that were dispatched. If the
+// tenant's queue is found to be empty, the tenant is removed from the tenant
+// set.
+func (e *engine) dispatchTenantTasks(
+	ctx context.Context,
+	tenantSetName string,
+	tenant string,
+	destinationQueueName string,
+	retCh chan []byte,
+) (int, error) {
+	tenantTaskQueueName := getTenantTaskQueueName(tenant)
+	weight := e.getTenantWeight(tenant)
+	for i := 0; i < weight; i++ {
+		taskJSON, err := e.redisClient.RPopLPush(
+			tenantTaskQueueName,
+			destinationQueueName,
+		).Bytes()
+		if err == redis.Nil {
+			return i, e.removeTenant(tenantSetName, tenant)
+		}
+		if err != nil {
+			return i, fmt.Errorf(
+				`error receiving task from queue "%s": %s`,
+				tenantTaskQueueName,
+				err,
+			)
+		}
+		select {
+		case retCh <- taskJSON:
+		case <-ctx.Done():
+			e.returnUndispatchedTask(tenant, destinationQueueName, "", taskJSON)
+			return i, nil
+		}
+	}
+	return weight, nil
+}
+
+// returnUndispatchedTask moves a task that was taken from a tenant's queue,
+// but never accepted by the return channel, from the destination queue back to
+// the tenant's queue. If an order queue is named, the tenant's place at the
+// head of it is restored too.
+func (e *engine) returnUndispatchedTask(
+	tenant string,
+	destinationQueueName string,
+	orderQueueName string,
+	taskJSON []byte,
+) {
+	pipeline := e.redisClient.TxPipeline()
+	pipeline.RPush(getTenantTaskQueueName(tenant), taskJSON)
+	if orderQueueName != "" {
+		pipeline.RPush(orderQueueName, tenant)
+	}
+	pipeline.LRem(destinationQueueName, -1, taskJSON)
+	if _, err := pipeline.Exec(); err != nil {
+		// The task remains in this worker's active task queue, from which a
+		// cleaner will return it to the pending task queue once this worker is
+		// gone
+		log.WithFields(log.Fields{
+			"workerID": e.workerID,
+			"tenant":   tenant,
+			"error":    err,
+		}).Error("error returning undispatched task to tenant's queue")
+	}
+}
+
+// removeTenant removes a tenant whose queue has been found empty from the
+// tenant set. A task may have been sorted into the tenant's queue after it was
+// found empty, though, so the tenant is added back if its queue is no longer
+// empty afterwards.
+func (e *engine) removeTenant(tenantSetName string, tenant string) error {
+	if err := e.redisClient.SRem(tenantSetName, tenant).Err(); err != nil &&
+		err != redis.Nil {
+		return fmt.Errorf(
+			`error removing tenant "%s" from set "%s": %s`,
+			tenant,
+			tenantSetName,
+			err,
+		)
+	}
+	tenantTaskQueueName := getTenantTaskQueueName(tenant)
+	queueDepth, err := e.redisClient.LLen(tenantTaskQueueName).Result()
+	if err != nil {
+		return fmt.Errorf(
+			`error checking depth of queue "%s": %s`,
+			tenantTaskQueueName,
+			err,
+		)
+	}
+	if queueDepth > 0 {
+		if err := e.redisClient.SAdd(tenantSetName, tenant).Err(); err != nil {
+			return fmt.Errorf(
+				`error adding tenant "%s" to set "%s": %s`,
+				tenant,
+				tenantSetName,
+				err,
+			)
+		}
+	}
+	return nil
+}
+
+func (e *engine) getTenantWeight(tenant string) int {
+	if weight, ok := e.fairScheduling.Weights[tenant]; ok && weight > 0 {
+		return weight
+	}
+	return 1
+}
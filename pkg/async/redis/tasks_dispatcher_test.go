@@ -0,0 +1,277 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultDispatchTasksTakesTurnsAcrossTenants(t *testing.T) {
+	e := getTestEngine()
+
+	destinationQueueName := getDisposableQueueName()
+	tenantSetName := getDisposableWorkerSetName()
+	// The busy tenant floods the queue; the quiet tenant submits only two tasks
+	busyTenant := getDisposableTenant()
+	quietTenant := getDisposableTenant()
+	e.fairScheduling.Enabled = true
+	e.fairScheduling.Weights = map[string]int{busyTenant: 2}
+
+	// Tasks are pushed onto the left of a queue and popped off the right, so
+	// push them in the order they'd have been submitted
+	pushTenantTasks(t, tenantSetName, busyTenant, 10)
+	pushTenantTasks(t, tenantSetName, quietTenant, 2)
+
+	// Under nominal conditions, defaultDispatchTasks blocks until the context it
+	// is passed is canceled. Use a context that will cancel itself after 2
+	// seconds to make defaultDispatchTasks STOP working so we can then examine
+	// what it accomplished.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	retCh := make(chan []byte)
+	errCh := make(chan error)
+	doneCh := make(chan struct{})
+	go func() {
+		e.defaultDispatchTasks(
+			ctx,
+			tenantSetName,
+			destinationQueueName,
+			retCh,
+			errCh,
+		)
+		close(doneCh)
+	}()
+
+	// Receive the first six tasks dispatched and record whose they were
+	dispatchedTenants := []string{}
+	for len(dispatchedTenants) < 6 {
+		select {
+		case taskJSON := <-retCh:
+			task, err := async.NewTaskFromJSON(taskJSON)
+			assert.Nil(t, err)
+			dispatchedTenants = append(dispatchedTenants, task.GetTenant())
+		case err := <-errCh:
+			assert.Fail(t, "should not have received any error, but did: %s", err)
+			return
+		case <-ctx.Done():
+			assert.Fail(t, "should have received six tasks, but didn't")
+			return
+		}
+	}
+
+	// Assert that the quiet tenant's tasks weren't left waiting behind all of
+	// the busy tenant's tasks. Tenants take turns in a consistent order, so which
+	// goes first depends only on their (random) names.
+	expected := []string{
+		busyTenant, busyTenant, quietTenant,
+		busyTenant, busyTenant, quietTenant,
+	}
+	if quietTenant < busyTenant {
+		expected = []string{
+			quietTenant, busyTenant, busyTenant,
+			quietTenant, busyTenant, busyTenant,
+		}
+	}
+	assert.Equal(t, expected, dispatchedTenants)
+
+	// Stop the dispatcher. It has already taken a seventh task, which nothing
+	// will receive, and must return that to its tenant's queue.
+	cancel()
+	<-doneCh
+
+	// Assert that every dispatched task, and only those, was also placed on the
+	// destination queue, and that the rest remain in their tenants' queues
+	destinationQueueDepth, err := redisClient.LLen(destinationQueueName).Result()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(len(dispatchedTenants)), destinationQueueDepth)
+	var pendingCount int64
+	for _, tenant := range []string{busyTenant, quietTenant} {
+		queueDepth, err :=
+			redisClient.LLen(getTenantTaskQueueName(tenant)).Result()
+		assert.Nil(t, err)
+		pendingCount += queueDepth
+	}
+	assert.Equal(t, int64(12-len(dispatchedTenants)), pendingCount)
+}
+
+func TestDefaultDispatchTasksDispatchesInOrderWithoutFairScheduling(
+	t *testing.T,
+) {
+	e := getTestEngine()
+
+	destinationQueueName := getDisposableQueueName()
+	tenantSetName := getDisposableWorkerSetName()
+	tenantA := getDisposableTenant()
+	tenantB := getDisposableTenant()
+	// The pooled tenant's tasks are dispatched by its own pool's dispatcher
+	pooledTenant := getDisposableTenant()
+	e.pools[pooledTenant] = &tenantPool{TenantPool: TenantPool{Workers: 1}}
+
+	// Push tasks in the order they'd have been submitted, recording their
+	// tenants' places in the order as the sorter would
+	submitted := []string{
+		tenantB, tenantA, tenantB, pooledTenant, tenantA, tenantB,
+	}
+	for _, tenant := range submitted {
+		pushTenantTasks(t, tenantSetName, tenant, 1)
+		if tenant != pooledTenant {
+			err := redisClient.LPush(
+				getTenantOrderQueueName(tenantSetName),
+				tenant,
+			).Err()
+			assert.Nil(t, err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	retCh := make(chan []byte)
+	errCh := make(chan error)
+	doneCh := make(chan struct{})
+	go func() {
+		e.defaultDispatchTasks(
+			ctx,
+			tenantSetName,
+			destinationQueueName,
+			retCh,
+			errCh,
+		)
+		close(doneCh)
+	}()
+
+	// Receive the first four tasks dispatched and record whose they were
+	dispatchedTenants := []string{}
+	for len(dispatchedTenants) < 4 {
+		select {
+		case taskJSON := <-retCh:
+			task, err := async.NewTaskFromJSON(taskJSON)
+			assert.Nil(t, err)
+			dispatchedTenants = append(dispatchedTenants, task.GetTenant())
+		case err := <-errCh:
+			assert.Fail(t, "should not have received any error, but did: %s", err)
+			return
+		case <-ctx.Done():
+			assert.Fail(t, "should have received four tasks, but didn't")
+			return
+		}
+	}
+
+	// Assert that tasks were dispatched in the order they were submitted, not
+	// in turns, and that the pooled tenant's task was skipped
+	assert.Equal(
+		t,
+		[]string{tenantB, tenantA, tenantB, tenantA},
+		dispatchedTenants,
+	)
+
+	// Stop the dispatcher. Any task it has taken since, which nothing will
+	// receive, must be returned to its tenant's queue along with its tenant's
+	// place in the order.
+	cancel()
+	<-doneCh
+
+	destinationQueueDepth, err := redisClient.LLen(destinationQueueName).Result()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(len(dispatchedTenants)), destinationQueueDepth)
+	order, err := redisClient.LRange(
+		getTenantOrderQueueName(tenantSetName),
+		0,
+		-1,
+	).Result()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{tenantB}, order)
+	for _, tenant := range []string{tenantB, pooledTenant} {
+		queueDepth, err :=
+			redisClient.LLen(getTenantTaskQueueName(tenant)).Result()
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), queueDepth)
+	}
+}
+
+func TestDefaultDispatchTasksRemovesTenantsWithNoTasks(t *testing.T) {
+	e := getTestEngine()
+
+	tenantSetName := getDisposableWorkerSetName()
+	tenant := getDisposableTenant()
+	err := redisClient.SAdd(tenantSetName, tenant).Err()
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	errCh := make(chan error)
+	go e.defaultDispatchTasks(
+		ctx,
+		tenantSetName,
+		getDisposableQueueName(),
+		make(chan []byte),
+		errCh,
+	)
+
+	select {
+	case <-errCh:
+		assert.Fail(t, "should not have received any error, but did")
+	case <-ctx.Done():
+	}
+
+	isMember, err := redisClient.SIsMember(tenantSetName, tenant).Result()
+	assert.Nil(t, err)
+	assert.False(t, isMember)
+}
+
+func TestDefaultDispatchTasksSkipsTenantsWithPools(t *testing.T) {
+	e := getTestEngine()
+
+	tenantSetName := getDisposableWorkerSetName()
+	tenant := getDisposableTenant()
+	pushTenantTasks(t, tenantSetName, tenant, 1)
+	e.pools[tenant] = &tenantPool{TenantPool: TenantPool{Workers: 1}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	retCh := make(chan []byte)
+	errCh := make(chan error)
+	go e.defaultDispatchTasks(
+		ctx,
+		tenantSetName,
+		getDisposableQueueName(),
+		retCh,
+		errCh,
+	)
+
+	select {
+	case <-retCh:
+		assert.Fail(t, "should not have received the tenant's task, but did")
+	case <-errCh:
+		assert.Fail(t, "should not have received any error, but did")
+	case <-ctx.Done():
+	}
+
+	queueDepth, err := redisClient.LLen(getTenantTaskQueueName(tenant)).Result()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), queueDepth)
+}
+
+func pushTenantTasks(
+	t *testing.T,
+	tenantSetName string,
+	tenant string,
+	count int,
+) {
+	for i := 0; i < count; i++ {
+		task := async.NewTask("foo", nil)
+		task.SetTenant(tenant)
+		taskJSON, err := task.ToJSON()
+		assert.Nil(t, err)
+		err = redisClient.LPush(getTenantTaskQueueName(tenant), taskJSON).Err()
+		assert.Nil(t, err)
+	}
+	err := redisClient.SAdd(tenantSetName, tenant).Err()
+	assert.Nil(t, err)
+}
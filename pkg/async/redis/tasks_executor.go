@@ -3,14 +3,21 @@ package redis
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
+	"time"
 
+	"github.com/Azure/open-service-broker-azure/pkg/async"
 	log "github.com/Sirupsen/logrus"
 )
 
-// executeTasksFn defines functions used to execute pending tasks
+// executeTasksFn defines functions used to execute pending tasks. They stop,
+// between tasks, when the stop channel is closed, and count themselves among
+// the given number of busy workers while executing a task.
 type executeTasksFn func(
 	ctx context.Context,
 	inputCh chan []byte,
+	stopCh chan struct{},
+	busyWorkers *int32,
 	pendingTaskQueueName string,
 	deferredTaskQueueName string,
 	errCh chan error,
@@ -19,6 +26,8 @@ type executeTasksFn func(
 func (e *engine) defaultExecuteTasks(
 	ctx context.Context,
 	inputCh chan []byte,
+	stopCh chan struct{},
+	busyWorkers *int32,
 	pendingTaskQueueName string,
 	deferredTaskQueueName string,
 	errCh chan error,
@@ -84,10 +93,33 @@ func (e *engine) defaultExecuteTasks(
 				}
 				continue
 			}
+			if e.throttle != nil {
+				if delay := e.throttle(task); delay > 0 {
+					// Rather than tie up this worker until the task may be executed,
+					// move the task to the deferred task queue. It will be re-queued in
+					// the pending task queue once the delay has lapsed.
+					if err := e.postponeTask(
+						task,
+						taskJSON,
+						delay,
+						getActiveTaskQueueName(e.workerID),
+						deferredTaskQueueName,
+					); err != nil {
+						select {
+						case errCh <- err:
+						case <-ctx.Done():
+						}
+						return
+					}
+					continue
+				}
+			}
 			taskSuccess := false
 			followUpTaskJSONs := [][]byte{}
 			hadMarshalingError := false
+			atomic.AddInt32(busyWorkers, 1)
 			followUpTasks, err := jobFn(ctx, task)
+			atomic.AddInt32(busyWorkers, -1)
 			if err != nil {
 				// If we get to here, we have a legitimate failure executing the task.
 				// This isn't the worker's fault. Simply log this.
@@ -109,6 +141,9 @@ func (e *engine) defaultExecuteTasks(
 				// a cleaner will eventually put it back on the pending task queue when
 				// this worker dies.
 				for _, followUpTask := range followUpTasks {
+					if followUpTask.GetTenant() == "" {
+						followUpTask.SetTenant(task.GetTenant())
+					}
 					// In reality, this is nearly guaranteed to never fail because there's
 					// no legitimate possibility of a task not being serializable. So it's
 					// possible that the following is unnecessarily defensive.
@@ -164,8 +199,49 @@ func (e *engine) defaultExecuteTasks(
 				}
 				return
 			}
+		case <-stopCh:
+			return
 		case <-ctx.Done():
 			return
 		}
 	}
 }
+
+// postponeTask executes a transaction that removes the given task from the
+// given queue and submits a copy of it, to be executed once the given delay
+// has lapsed, to the deferred task queue
+func (e *engine) postponeTask(
+	task async.Task,
+	taskJSON []byte,
+	delay time.Duration,
+	sourceQueueName string,
+	deferredTaskQueueName string,
+) error {
+	postponedTask := async.NewDelayedTask(
+		task.GetJobName(),
+		task.GetArgs(),
+		delay,
+	)
+	postponedTask.SetTenant(task.GetTenant())
+	postponedTaskJSON, err := postponedTask.ToJSON()
+	if err != nil {
+		return fmt.Errorf(
+			`error moving postponed task "%s" to queue "%s": %s`,
+			task.GetID(),
+			deferredTaskQueueName,
+			err,
+		)
+	}
+	pipeline := e.redisClient.TxPipeline()
+	pipeline.LPush(deferredTaskQueueName, postponedTaskJSON)
+	pipeline.LRem(sourceQueueName, -1, taskJSON)
+	if _, err = pipeline.Exec(); err != nil {
+		return fmt.Errorf(
+			`error moving postponed task "%s" to queue "%s": %s`,
+			task.GetID(),
+			deferredTaskQueueName,
+			err,
+		)
+	}
+	return nil
+}
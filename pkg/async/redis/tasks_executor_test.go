@@ -3,6 +3,7 @@ package redis
 import (
 	"context"
 	"errors"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -49,6 +50,7 @@ func TestDefaultExecuteTasks(t *testing.T) {
 	badTaskJSON, err := badTask.ToJSON()
 	assert.Nil(t, err)
 	goodTask := async.NewTask("goodJob", map[string]string{})
+	goodTask.SetTenant("test-tenant")
 	goodTaskJSON, err := goodTask.ToJSON()
 	assert.Nil(t, err)
 	assert.Nil(t, err)
@@ -102,6 +104,8 @@ func TestDefaultExecuteTasks(t *testing.T) {
 	go e.defaultExecuteTasks(
 		ctx,
 		inputCh,
+		make(chan struct{}),
+		new(int32),
 		pendingTaskQueueName,
 		deferredTaskQueueName,
 		errCh,
@@ -126,6 +130,14 @@ func TestDefaultExecuteTasks(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, int64(1), deferredTaskQueueDepth)
 
+	// Assert that the follow-up task inherited the tenant of the task that
+	// returned it
+	followUpTaskJSON, err := redisClient.LIndex(deferredTaskQueueName, 0).Bytes()
+	assert.Nil(t, err)
+	followUpTask, err := async.NewTaskFromJSON(followUpTaskJSON)
+	assert.Nil(t, err)
+	assert.Equal(t, goodTask.GetTenant(), followUpTask.GetTenant())
+
 	// Assert that the worker's active task queue is empty-- in all cases, the
 	// tasks should have been removed from this queue
 	activeTaskQueueDepth, err = redisClient.LLen(activeTaskQueueName).Result()
@@ -136,3 +148,96 @@ func TestDefaultExecuteTasks(t *testing.T) {
 	assert.Equal(t, 1, badJobCallCount)
 	assert.Equal(t, 1, goodJobCallCount)
 }
+
+func TestDefaultExecuteTasksPostponesThrottledTasks(t *testing.T) {
+	e := getTestEngine()
+	e.throttle = func(task async.Task) time.Duration {
+		if task.GetArgs()["throttled"] == "true" {
+			return time.Minute
+		}
+		return 0
+	}
+
+	pendingTaskQueueName := getDisposableQueueName()
+	deferredTaskQueueName := getDisposableQueueName()
+	activeTaskQueueName := getActiveTaskQueueName(e.workerID)
+
+	var jobCallCount int32
+	err := e.RegisterJob(
+		"job",
+		func(_ context.Context, _ async.Task) ([]async.Task, error) {
+			atomic.AddInt32(&jobCallCount, 1)
+			return nil, nil
+		},
+	)
+	assert.Nil(t, err)
+
+	throttledTask := async.NewTask(
+		"job",
+		map[string]string{"throttled": "true"},
+	)
+	throttledTask.SetTenant("test-tenant")
+	throttledTaskJSON, err := throttledTask.ToJSON()
+	assert.Nil(t, err)
+	unthrottledTask := async.NewTask("job", map[string]string{})
+	unthrottledTaskJSON, err := unthrottledTask.ToJSON()
+	assert.Nil(t, err)
+	tasks := [][]byte{throttledTaskJSON, unthrottledTaskJSON}
+
+	for _, task := range tasks {
+		err = redisClient.LPush(activeTaskQueueName, task).Err()
+		assert.Nil(t, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	inputCh := make(chan []byte)
+	go func() {
+		for _, task := range tasks {
+			select {
+			case inputCh <- task:
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	errCh := make(chan error)
+	go e.defaultExecuteTasks(
+		ctx,
+		inputCh,
+		make(chan struct{}),
+		new(int32),
+		pendingTaskQueueName,
+		deferredTaskQueueName,
+		errCh,
+	)
+
+	select {
+	case <-errCh:
+		assert.Fail(t, "should not have received any error, but did")
+	case <-ctx.Done():
+	}
+
+	// Assert that only the unthrottled task was executed
+	assert.Equal(t, int32(1), atomic.LoadInt32(&jobCallCount))
+
+	// Assert that the throttled task was moved to the deferred task queue with
+	// its arguments and tenant intact
+	deferredTaskQueueDepth, err := redisClient.LLen(deferredTaskQueueName).Result()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), deferredTaskQueueDepth)
+	postponedTaskJSON, err := redisClient.LIndex(deferredTaskQueueName, 0).Bytes()
+	assert.Nil(t, err)
+	postponedTask, err := async.NewTaskFromJSON(postponedTaskJSON)
+	assert.Nil(t, err)
+	assert.Equal(t, throttledTask.GetJobName(), postponedTask.GetJobName())
+	assert.Equal(t, throttledTask.GetArgs(), postponedTask.GetArgs())
+	assert.Equal(t, throttledTask.GetTenant(), postponedTask.GetTenant())
+	assert.NotNil(t, postponedTask.GetExecuteTime())
+
+	// Assert that the worker's active task queue is empty
+	activeTaskQueueDepth, err := redisClient.LLen(activeTaskQueueName).Result()
+	assert.Nil(t, err)
+	assert.Empty(t, activeTaskQueueDepth)
+}
@@ -0,0 +1,124 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/go-redis/redis"
+)
+
+// sortTasksFn defines functions used to sort pending tasks into queues
+// belonging to the tenants on whose behalf they are executed
+type sortTasksFn func(
+	ctx context.Context,
+	inputCh chan []byte,
+	sortingTaskQueueName string,
+	tenantSetName string,
+	deferredTaskQueueName string,
+	errCh chan error,
+)
+
+// defaultSortTasks moves each task it receives from this worker's sorting queue
+// to the queue belonging to the task's tenant and records that the tenant has
+// tasks pending dispatch. Both happen in a single transaction so that a
+// dispatcher never sees a tenant's queue gain a task without also seeing the
+// tenant in the tenant set. Unless fair scheduling is enabled, the tenant of a
+// task that the default pool will execute is also recorded in the tenant order
+// queue, so that the default pool's dispatcher can dispatch tasks in the order
+// they were sorted. A task whose tenant's queue is already at capacity
+// is moved to the deferred task queue instead, to be sorted again later. The
+// capacity is checked before, not within, that transaction, so a queue that
+// more than one worker is sorting tasks into may briefly exceed it.
+func (e *engine) defaultSortTasks(
+	ctx context.Context,
+	inputCh chan []byte,
+	sortingTaskQueueName string,
+	tenantSetName string,
+	deferredTaskQueueName string,
+	errCh chan error,
+) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	for {
+		select {
+		case taskJSON := <-inputCh:
+			task, err := e.getTaskFromJSON(taskJSON, sortingTaskQueueName)
+			if err != nil {
+				select {
+				case errCh <- err:
+				case <-ctx.Done():
+				}
+				return
+			}
+			if task == nil {
+				continue
+			}
+			tenant := task.GetTenant()
+			full, err := e.isTenantQueueFull(tenant)
+			if err == nil && full {
+				log.WithFields(log.Fields{
+					"taskID": task.GetID(),
+					"tenant": tenant,
+				}).Debug("tenant's task queue is full; postponing task")
+				err = e.postponeTask(
+					task,
+					taskJSON,
+					tenantQueueOverflowDelay,
+					sortingTaskQueueName,
+					deferredTaskQueueName,
+				)
+			}
+			if err != nil {
+				select {
+				case errCh <- err:
+				case <-ctx.Done():
+				}
+				return
+			}
+			if full {
+				continue
+			}
+			pipeline := e.redisClient.TxPipeline()
+			pipeline.LPush(getTenantTaskQueueName(tenant), taskJSON)
+			pipeline.SAdd(tenantSetName, tenant)
+			if !e.fairScheduling.Enabled && !e.hasTenantPool(tenant) {
+				pipeline.LPush(getTenantOrderQueueName(tenantSetName), tenant)
+			}
+			pipeline.LRem(sortingTaskQueueName, -1, taskJSON)
+			if _, err := pipeline.Exec(); err != nil {
+				select {
+				case errCh <- fmt.Errorf(
+					`error moving task "%s" to queue "%s": %s`,
+					task.GetID(),
+					getTenantTaskQueueName(tenant),
+					err,
+				):
+				case <-ctx.Done():
+				}
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// isTenantQueueFull returns whether the given tenant's queue holds as many
+// tasks as its capacity allows
+func (e *engine) isTenantQueueFull(tenant string) (bool, error) {
+	capacity := e.getTenantQueueCapacity(tenant)
+	if capacity <= 0 {
+		return false, nil
+	}
+	tenantTaskQueueName := getTenantTaskQueueName(tenant)
+	queueDepth, err := e.redisClient.LLen(tenantTaskQueueName).Result()
+	if err != nil && err != redis.Nil {
+		return false, fmt.Errorf(
+			`error checking depth of queue "%s": %s`,
+			tenantTaskQueueName,
+			err,
+		)
+	}
+	return queueDepth >= int64(capacity), nil
+}
@@ -0,0 +1,162 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultSortTasks(t *testing.T) {
+	e := getTestEngine()
+
+	sortingTaskQueueName := getDisposableQueueName()
+	tenantSetName := getDisposableWorkerSetName()
+	tenants := []string{getDisposableTenant(), getDisposableTenant()}
+
+	// Put some tasks for each tenant on the sorting task queue
+	const tasksPerTenant = 3
+	taskJSONs := [][]byte{}
+	for range [tasksPerTenant]struct{}{} {
+		for _, tenant := range tenants {
+			task := async.NewTask("foo", nil)
+			task.SetTenant(tenant)
+			taskJSON, err := task.ToJSON()
+			assert.Nil(t, err)
+			err = redisClient.LPush(sortingTaskQueueName, taskJSON).Err()
+			assert.Nil(t, err)
+			taskJSONs = append(taskJSONs, taskJSON)
+		}
+	}
+
+	// Under nominal conditions, defaultSortTasks blocks until the context it is
+	// passed is canceled. Use a context that will cancel itself after 1 second
+	// to make defaultSortTasks STOP working so we can then examine what it
+	// accomplished.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	inputCh := make(chan []byte)
+	errCh := make(chan error)
+	go e.defaultSortTasks(
+		ctx,
+		inputCh,
+		sortingTaskQueueName,
+		tenantSetName,
+		getDisposableQueueName(),
+		errCh,
+	)
+
+	for _, taskJSON := range taskJSONs {
+		select {
+		case inputCh <- taskJSON:
+		case err := <-errCh:
+			assert.Fail(t, "should not have received any error, but did: %s", err)
+		}
+	}
+
+	select {
+	case <-errCh:
+		assert.Fail(t, "should not have received any error, but did")
+	case <-ctx.Done():
+	}
+
+	// Assert that the sorting task queue has been drained
+	sortingQueueDepth, err := redisClient.LLen(sortingTaskQueueName).Result()
+	assert.Nil(t, err)
+	assert.Empty(t, sortingQueueDepth)
+
+	// Assert that each tenant's queue now has precisely tasksPerTenant tasks and
+	// that each tenant has been added to the tenant set
+	for _, tenant := range tenants {
+		queueDepth, err := redisClient.LLen(getTenantTaskQueueName(tenant)).Result()
+		assert.Nil(t, err)
+		assert.Equal(t, int64(tasksPerTenant), queueDepth)
+		isMember, err := redisClient.SIsMember(tenantSetName, tenant).Result()
+		assert.Nil(t, err)
+		assert.True(t, isMember)
+	}
+
+	// Assert that, with fair scheduling disabled, each task's tenant has been
+	// recorded in the tenant order queue in the order the tasks were sorted
+	order, err := redisClient.LRange(
+		getTenantOrderQueueName(tenantSetName),
+		0,
+		-1,
+	).Result()
+	assert.Nil(t, err)
+	expectedOrder := []string{}
+	for _, taskJSON := range taskJSONs {
+		task, err := async.NewTaskFromJSON(taskJSON)
+		assert.Nil(t, err)
+		// Tenants are pushed onto the left of the queue
+		expectedOrder = append([]string{task.GetTenant()}, expectedOrder...)
+	}
+	assert.Equal(t, expectedOrder, order)
+}
+
+func TestDefaultSortTasksPostponesTasksBeyondQueueCapacity(t *testing.T) {
+	e := getTestEngine()
+	e.tenantPools.DefaultQueueCapacity = 2
+
+	sortingTaskQueueName := getDisposableQueueName()
+	deferredTaskQueueName := getDisposableQueueName()
+	tenantSetName := getDisposableWorkerSetName()
+	tenant := getDisposableTenant()
+
+	taskJSONs := [][]byte{}
+	for range [3]struct{}{} {
+		task := async.NewTask("foo", nil)
+		task.SetTenant(tenant)
+		taskJSON, err := task.ToJSON()
+		assert.Nil(t, err)
+		err = redisClient.LPush(sortingTaskQueueName, taskJSON).Err()
+		assert.Nil(t, err)
+		taskJSONs = append(taskJSONs, taskJSON)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	inputCh := make(chan []byte)
+	errCh := make(chan error)
+	go e.defaultSortTasks(
+		ctx,
+		inputCh,
+		sortingTaskQueueName,
+		tenantSetName,
+		deferredTaskQueueName,
+		errCh,
+	)
+
+	for _, taskJSON := range taskJSONs {
+		select {
+		case inputCh <- taskJSON:
+		case err := <-errCh:
+			assert.Fail(t, "should not have received any error, but did: %s", err)
+		}
+	}
+
+	select {
+	case <-errCh:
+		assert.Fail(t, "should not have received any error, but did")
+	case <-ctx.Done():
+	}
+
+	// Assert that the sorting task queue has been drained, that the tenant's
+	// queue is at capacity and that the task beyond it was deferred
+	sortingQueueDepth, err := redisClient.LLen(sortingTaskQueueName).Result()
+	assert.Nil(t, err)
+	assert.Empty(t, sortingQueueDepth)
+	queueDepth, err := redisClient.LLen(getTenantTaskQueueName(tenant)).Result()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), queueDepth)
+	deferredTaskJSON, err := redisClient.RPop(deferredTaskQueueName).Bytes()
+	assert.Nil(t, err)
+	deferredTask, err := async.NewTaskFromJSON(deferredTaskJSON)
+	assert.Nil(t, err)
+	assert.Equal(t, tenant, deferredTask.GetTenant())
+	assert.NotNil(t, deferredTask.GetExecuteTime())
+}
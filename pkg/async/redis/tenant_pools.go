@@ -0,0 +1,274 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	log "github.com/Sirupsen/logrus"
+	"github.com/go-redis/redis"
+)
+
+const (
+	defaultTenantPoolsReloadInterval = 30 * time.Second
+	// tenantQueueOverflowDelay is how long a task is postponed for when its
+	// tenant already has as many tasks waiting as its queue capacity allows
+	tenantQueueOverflowDelay = 10 * time.Second
+)
+
+// TenantPool encapsulates the resources dedicated to executing the tasks of a
+// single tenant
+type TenantPool struct {
+	// Workers is the number of workers that execute the tenant's tasks and no
+	// other tenant's. Pools with fewer than one worker are ignored.
+	Workers int
+	// QueueCapacity bounds the number of the tenant's tasks that may wait for
+	// one of those workers. Tasks beyond that are postponed, rather than
+	// queued, until the queue has room. Zero means the queue is unbounded.
+	QueueCapacity int
+}
+
+// TenantPoolsFn defines functions used to retrieve the pools dedicated to
+// tenants, keyed by tenant
+type TenantPoolsFn func() (map[string]TenantPool, error)
+
+// TenantPoolsConfig encapsulates options for partitioning the engine's workers
+// between tenants. Tenants given a pool of their own have their tasks executed
+// by its workers alone, so that no matter how many tasks a tenant submits, it
+// can't occupy workers that other tenants' tasks depend upon. Tenants without
+// a pool of their own share the engine's default pool, sized according to its
+// WorkerPoolConfig.
+type TenantPoolsConfig struct {
+	// GetPools, if non-nil, is called when the engine starts and again every
+	// ReloadInterval, so that tenants can be given pools, or have their pools
+	// resized or taken away, without the broker being restarted
+	GetPools       TenantPoolsFn
+	ReloadInterval time.Duration
+	// DefaultQueueCapacity bounds the number of tasks each tenant without a
+	// pool of its own may have waiting for the default pool's workers. Zero
+	// means tenants' queues are unbounded.
+	DefaultQueueCapacity int
+}
+
+// withDefaults returns a copy of the config with empty fields set to their
+// default values
+func (t TenantPoolsConfig) withDefaults() TenantPoolsConfig {
+	if t.ReloadInterval <= 0 {
+		t.ReloadInterval = defaultTenantPoolsReloadInterval
+	}
+	return t
+}
+
+// tenantPool is a pool of workers dedicated to a single tenant, along with
+// the means to stop the dispatcher that feeds them
+type tenantPool struct {
+	TenantPool
+	workers         *workerPool
+	stopDispatching context.CancelFunc
+}
+
+// sortsTasks returns whether pending tasks are sorted into queues belonging
+// to their tenants before they're executed
+func (e *engine) sortsTasks() bool {
+	return e.fairScheduling.Enabled || e.tenantPools.GetPools != nil ||
+		e.tenantPools.DefaultQueueCapacity > 0
+}
+
+func (e *engine) hasTenantPool(tenant string) bool {
+	e.tenantPoolsMutex.RLock()
+	defer e.tenantPoolsMutex.RUnlock()
+	_, ok := e.pools[tenant]
+	return ok
+}
+
+// getTenantQueueCapacity returns how many of the given tenant's tasks may
+// wait to be executed, or zero if there is no limit
+func (e *engine) getTenantQueueCapacity(tenant string) int {
+	e.tenantPoolsMutex.RLock()
+	defer e.tenantPoolsMutex.RUnlock()
+	if pool, ok := e.pools[tenant]; ok {
+		return pool.QueueCapacity
+	}
+	return e.tenantPools.DefaultQueueCapacity
+}
+
+// GetTenantPoolUtilization returns the utilization of the pool dedicated to
+// each tenant that has one, keyed by tenant
+func (e *engine) GetTenantPoolUtilization() map[string]async.PoolUtilization {
+	e.tenantPoolsMutex.RLock()
+	defer e.tenantPoolsMutex.RUnlock()
+	utilization := make(map[string]async.PoolUtilization, len(e.pools))
+	for tenant, pool := range e.pools {
+		utilization[tenant] = async.PoolUtilization{
+			Workers:     pool.workers.getSize(),
+			BusyWorkers: int(atomic.LoadInt32(&pool.workers.busyWorkers)),
+		}
+	}
+	return utilization
+}
+
+// runTenantPools periodically reloads the pools dedicated to tenants. Failure
+// to retrieve them is logged and the pools are left as they are until the
+// next interval; it isn't fatal.
+func (e *engine) runTenantPools(
+	ctx context.Context,
+	destinationQueueName string,
+	executorErrCh chan error,
+	dispatcherErrCh chan error,
+) {
+	ticker := time.NewTicker(e.tenantPools.ReloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			log.Debug("context canceled; async tenant pool reloader stopping")
+			return
+		}
+		pools, err := e.tenantPools.GetPools()
+		if err != nil {
+			log.WithFields(log.Fields{
+				"workerID": e.workerID,
+				"error":    err,
+			}).Error("error reloading tenant pools; leaving tenant pools unchanged")
+			continue
+		}
+		e.applyTenantPools(
+			ctx,
+			pools,
+			destinationQueueName,
+			executorErrCh,
+			dispatcherErrCh,
+		)
+	}
+}
+
+// applyTenantPools starts, resizes and stops tenants' pools so that they
+// match the given ones. When a tenant's pool is taken away, its workers
+// finish the tasks they're executing and the tenant's remaining tasks are
+// left for the default pool.
+func (e *engine) applyTenantPools(
+	ctx context.Context,
+	pools map[string]TenantPool,
+	destinationQueueName string,
+	executorErrCh chan error,
+	dispatcherErrCh chan error,
+) {
+	e.tenantPoolsMutex.Lock()
+	defer e.tenantPoolsMutex.Unlock()
+	for tenant, pool := range e.pools {
+		if config, ok := pools[tenant]; ok && config.Workers > 0 {
+			continue
+		}
+		pool.stopDispatching()
+		pool.workers.resize(0)
+		delete(e.pools, tenant)
+		log.WithFields(log.Fields{
+			"workerID": e.workerID,
+			"tenant":   tenant,
+		}).Info("removed tenant pool")
+	}
+	tenants := make([]string, 0, len(pools))
+	for tenant := range pools {
+		tenants = append(tenants, tenant)
+	}
+	sort.Strings(tenants)
+	for _, tenant := range tenants {
+		config := pools[tenant]
+		if config.Workers < 1 {
+			continue
+		}
+		logFields := log.Fields{
+			"workerID":      e.workerID,
+			"tenant":        tenant,
+			"workers":       config.Workers,
+			"queueCapacity": config.QueueCapacity,
+		}
+		if pool, ok := e.pools[tenant]; ok {
+			if pool.TenantPool != config {
+				pool.TenantPool = config
+				pool.workers.resize(config.Workers)
+				log.WithFields(logFields).Info("updated tenant pool")
+			}
+			continue
+		}
+		retCh := make(chan []byte)
+		dispatcherCtx, stopDispatching := context.WithCancel(ctx)
+		pool := &tenantPool{
+			TenantPool: config,
+			workers: newWorkerPool(
+				func(stopCh chan struct{}, busyWorkers *int32) {
+					e.executeTasks(
+						ctx,
+						retCh,
+						stopCh,
+						busyWorkers,
+						pendingTaskQueueName,
+						deferredTaskQueueName,
+						executorErrCh,
+					)
+				},
+			),
+			stopDispatching: stopDispatching,
+		}
+		go e.dispatchTenantPoolTasks(
+			dispatcherCtx,
+			tenant,
+			destinationQueueName,
+			retCh,
+			dispatcherErrCh,
+		)
+		pool.workers.resize(config.Workers)
+		e.pools[tenant] = pool
+		log.WithFields(logFields).Info("added tenant pool")
+	}
+}
+
+// dispatchTenantPoolTasks dispatches the given tenant's tasks, in the order
+// they were sorted into the tenant's queue, to both a destination queue and a
+// return channel drained by the tenant's own workers. A task that has been
+// taken from the tenant's queue when the dispatcher is stopped is put back, so
+// it is left for whichever pool executes the tenant's tasks next.
+func (e *engine) dispatchTenantPoolTasks(
+	ctx context.Context,
+	tenant string,
+	destinationQueueName string,
+	retCh chan []byte,
+	errCh chan error,
+) {
+	tenantTaskQueueName := getTenantTaskQueueName(tenant)
+	for {
+		taskJSON, err := e.redisClient.RPopLPush(
+			tenantTaskQueueName,
+			destinationQueueName,
+		).Bytes()
+		if err == redis.Nil {
+			select {
+			case <-time.After(dispatchPollInterval):
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+		if err != nil {
+			select {
+			case errCh <- fmt.Errorf(
+				`error receiving task from queue "%s": %s`,
+				tenantTaskQueueName,
+				err,
+			):
+			case <-ctx.Done():
+			}
+			return
+		}
+		select {
+		case retCh <- taskJSON:
+		case <-ctx.Done():
+			e.returnUndispatchedTask(tenant, destinationQueueName, "", taskJSON)
+			return
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyTenantPools(t *testing.T) {
+	e := getTestEngine()
+	tenant := getDisposableTenant()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errCh := make(chan error)
+
+	e.applyTenantPools(
+		ctx,
+		map[string]TenantPool{
+			tenant:                {Workers: 2, QueueCapacity: 5},
+			getDisposableTenant(): {Workers: 0},
+		},
+		getDisposableQueueName(),
+		errCh,
+		errCh,
+	)
+	assert.Equal(
+		t,
+		map[string]async.PoolUtilization{tenant: {Workers: 2}},
+		e.GetTenantPoolUtilization(),
+	)
+	assert.True(t, e.hasTenantPool(tenant))
+	assert.Equal(t, 5, e.getTenantQueueCapacity(tenant))
+
+	e.applyTenantPools(
+		ctx,
+		map[string]TenantPool{tenant: {Workers: 3}},
+		getDisposableQueueName(),
+		errCh,
+		errCh,
+	)
+	assert.Equal(t, 3, e.GetTenantPoolUtilization()[tenant].Workers)
+	assert.Equal(t, 0, e.getTenantQueueCapacity(tenant))
+
+	e.tenantPools.DefaultQueueCapacity = 10
+	e.applyTenantPools(
+		ctx,
+		map[string]TenantPool{},
+		getDisposableQueueName(),
+		errCh,
+		errCh,
+	)
+	assert.Empty(t, e.GetTenantPoolUtilization())
+	assert.False(t, e.hasTenantPool(tenant))
+	assert.Equal(t, 10, e.getTenantQueueCapacity(tenant))
+}
+
+func TestDispatchTenantPoolTasks(t *testing.T) {
+	e := getTestEngine()
+	destinationQueueName := getDisposableQueueName()
+	tenant := getDisposableTenant()
+	pushTenantTasks(t, getDisposableWorkerSetName(), tenant, 3)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	dispatcherCtx, stopDispatching := context.WithCancel(ctx)
+	retCh := make(chan []byte)
+	errCh := make(chan error)
+	doneCh := make(chan struct{})
+	go func() {
+		e.dispatchTenantPoolTasks(
+			dispatcherCtx,
+			tenant,
+			destinationQueueName,
+			retCh,
+			errCh,
+		)
+		close(doneCh)
+	}()
+
+	// Receive one task, then stop the dispatcher while it waits to hand over
+	// the next
+	select {
+	case <-retCh:
+	case err := <-errCh:
+		assert.FailNow(t, "should not have received any error, but did: %s", err)
+	case <-ctx.Done():
+		assert.FailNow(t, "should have received a task, but didn't")
+	}
+	stopDispatching()
+	select {
+	case <-doneCh:
+	case <-ctx.Done():
+		assert.FailNow(t, "dispatcher should have stopped, but didn't")
+	}
+
+	// Assert that only the task that was received remains in the destination
+	// queue and that the others are in the tenant's queue still
+	destinationQueueDepth, err := redisClient.LLen(destinationQueueName).Result()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), destinationQueueDepth)
+	tenantQueueDepth, err :=
+		redisClient.LLen(getTenantTaskQueueName(tenant)).Result()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), tenantQueueDepth)
+}
@@ -0,0 +1,213 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/go-redis/redis"
+)
+
+const (
+	defaultWorkers         = 5
+	defaultScalingInterval = 10 * time.Second
+)
+
+// WorkerPoolConfig encapsulates options for sizing the pool of workers that
+// execute pending tasks. The pool starts with MinWorkers workers. If MaxWorkers
+// is greater than that, the pool grows, every ScalingInterval, to meet the
+// number of tasks that are waiting to be executed, and shrinks again, one
+// worker per interval, as that number falls. Fields left empty take their
+// default values; by default, the pool has a fixed size of five workers.
+type WorkerPoolConfig struct {
+	MinWorkers      int
+	MaxWorkers      int
+	ScalingInterval time.Duration
+}
+
+// withDefaults returns a copy of the config with empty fields set to their
+// default values
+func (w WorkerPoolConfig) withDefaults() WorkerPoolConfig {
+	if w.MinWorkers <= 0 {
+		w.MinWorkers = defaultWorkers
+	}
+	if w.MaxWorkers < w.MinWorkers {
+		w.MaxWorkers = w.MinWorkers
+	}
+	if w.ScalingInterval <= 0 {
+		w.ScalingInterval = defaultScalingInterval
+	}
+	return w
+}
+
+// workerPool tracks the executors that are running. Each is stopped by closing
+// its own stop channel, which it checks between tasks, so a worker removed
+// from the pool finishes the task it's executing, if any, first.
+type workerPool struct {
+	startWorker func(stopCh chan struct{}, busyWorkers *int32)
+	stopChs     []chan struct{}
+	mutex       sync.Mutex
+	// busyWorkers counts the pool's workers that are executing a task, as
+	// opposed to waiting for one
+	busyWorkers int32
+}
+
+func newWorkerPool(
+	startWorker func(stopCh chan struct{}, busyWorkers *int32),
+) *workerPool {
+	return &workerPool{
+		startWorker: startWorker,
+	}
+}
+
+// resize starts or stops workers until the pool has the given number of them
+func (w *workerPool) resize(size int) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	for len(w.stopChs) < size {
+		stopCh := make(chan struct{})
+		w.stopChs = append(w.stopChs, stopCh)
+		go w.startWorker(stopCh, &w.busyWorkers)
+	}
+	for len(w.stopChs) > size {
+		last := len(w.stopChs) - 1
+		close(w.stopChs[last])
+		w.stopChs = w.stopChs[:last]
+	}
+}
+
+func (w *workerPool) getSize() int {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return len(w.stopChs)
+}
+
+// GetWorkerCount returns the number of workers currently in the engine's
+// default pool and in the pools dedicated to tenants
+func (e *engine) GetWorkerCount() int {
+	var count int
+	for _, utilization := range e.GetTenantPoolUtilization() {
+		count += utilization.Workers
+	}
+	e.workerPoolMutex.RLock()
+	defer e.workerPoolMutex.RUnlock()
+	if e.pool == nil {
+		return count
+	}
+	return count + e.pool.getSize()
+}
+
+// scaleWorkerPool periodically resizes the worker pool to suit the number of
+// tasks waiting to be executed. Failure to count those tasks is logged and the
+// pool is left as it is until the next interval; it isn't fatal.
+func (e *engine) scaleWorkerPool(ctx context.Context, pool *workerPool) {
+	ticker := time.NewTicker(e.workerPoolConfig.ScalingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			log.Debug("context canceled; async worker pool scaler stopping")
+			return
+		}
+		backlog, err := e.getBacklog()
+		if err != nil {
+			log.WithFields(log.Fields{
+				"workerID": e.workerID,
+				"error":    err,
+			}).Error("error counting pending tasks; not scaling worker pool")
+			continue
+		}
+		current := pool.getSize()
+		desired := getDesiredWorkerCount(
+			current,
+			int(atomic.LoadInt32(&pool.busyWorkers)),
+			backlog,
+			e.workerPoolConfig,
+		)
+		if desired != current {
+			log.WithFields(log.Fields{
+				"workerID": e.workerID,
+				"backlog":  backlog,
+				"from":     current,
+				"to":       desired,
+			}).Debug("scaling async worker pool")
+			pool.resize(desired)
+		}
+	}
+}
+
+// getBacklog returns the number of tasks waiting to be executed by the default
+// pool. When tasks are sorted, that includes the tasks already sorted into the
+// queues of tenants without a pool of their own.
+func (e *engine) getBacklog() (int64, error) {
+	backlog, err := e.redisClient.LLen(pendingTaskQueueName).Result()
+	if err != nil && err != redis.Nil {
+		return 0, fmt.Errorf(
+			`error retrieving length of queue "%s": %s`,
+			pendingTaskQueueName,
+			err,
+		)
+	}
+	if !e.sortsTasks() {
+		return backlog, nil
+	}
+	tenants, err := e.redisClient.SMembers(tenantSetName).Result()
+	if err != nil && err != redis.Nil {
+		return 0, fmt.Errorf(
+			`error retrieving tenants from set "%s": %s`,
+			tenantSetName,
+			err,
+		)
+	}
+	for _, tenant := range tenants {
+		if e.hasTenantPool(tenant) {
+			continue
+		}
+		queueName := getTenantTaskQueueName(tenant)
+		length, err := e.redisClient.LLen(queueName).Result()
+		if err != nil && err != redis.Nil {
+			return 0, fmt.Errorf(
+				`error retrieving length of queue "%s": %s`,
+				queueName,
+				err,
+			)
+		}
+		backlog += length
+	}
+	return backlog, nil
+}
+
+// getDesiredWorkerCount returns how many workers the pool should have, given
+// how many it has, how many of those are busy executing a task and how many
+// tasks are waiting. The pool grows straight to the size that meets demand,
+// but shrinks by only one worker at a time, so that a brief lull doesn't
+// undo growth that will soon be needed again. The result is always within the
+// configured bounds.
+func getDesiredWorkerCount(
+	current int,
+	busy int,
+	backlog int64,
+	config WorkerPoolConfig,
+) int {
+	desired := current
+	demand := int64(busy) + backlog
+	if demand > int64(current) {
+		desired = config.MaxWorkers
+		if demand < int64(config.MaxWorkers) {
+			desired = int(demand)
+		}
+	} else if demand < int64(current) {
+		desired = current - 1
+	}
+	if desired < config.MinWorkers {
+		desired = config.MinWorkers
+	}
+	if desired > config.MaxWorkers {
+		desired = config.MaxWorkers
+	}
+	return desired
+}
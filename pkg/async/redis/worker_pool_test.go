@@ -0,0 +1,116 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDesiredWorkerCount(t *testing.T) {
+	config := WorkerPoolConfig{
+		MinWorkers: 2,
+		MaxWorkers: 10,
+	}
+	testCases := []struct {
+		name     string
+		current  int
+		busy     int
+		backlog  int64
+		expected int
+	}{
+		{
+			name:     "demand met",
+			current:  4,
+			busy:     2,
+			backlog:  2,
+			expected: 4,
+		},
+		{
+			name:     "grows to meet demand",
+			current:  4,
+			busy:     4,
+			backlog:  3,
+			expected: 7,
+		},
+		{
+			name:     "grows no larger than max",
+			current:  4,
+			busy:     4,
+			backlog:  50,
+			expected: 10,
+		},
+		{
+			name:     "shrinks by one",
+			current:  8,
+			busy:     1,
+			backlog:  0,
+			expected: 7,
+		},
+		{
+			name:     "shrinks no smaller than min",
+			current:  2,
+			busy:     0,
+			backlog:  0,
+			expected: 2,
+		},
+		{
+			name:     "grows to min",
+			current:  0,
+			busy:     0,
+			backlog:  0,
+			expected: 2,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(
+				t,
+				tc.expected,
+				getDesiredWorkerCount(tc.current, tc.busy, tc.backlog, config),
+			)
+		})
+	}
+}
+
+func TestWorkerPoolResize(t *testing.T) {
+	startedCh := make(chan struct{})
+	stoppedCh := make(chan struct{})
+	pool := newWorkerPool(func(stopCh chan struct{}, _ *int32) {
+		startedCh <- struct{}{}
+		<-stopCh
+		stoppedCh <- struct{}{}
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	pool.resize(3)
+	for range [3]struct{}{} {
+		select {
+		case <-startedCh:
+		case <-ctx.Done():
+			assert.FailNow(t, "timed out waiting for workers to start")
+		}
+	}
+	assert.Equal(t, 3, pool.getSize())
+
+	pool.resize(1)
+	for range [2]struct{}{} {
+		select {
+		case <-stoppedCh:
+		case <-ctx.Done():
+			assert.FailNow(t, "timed out waiting for workers to stop")
+		}
+	}
+	assert.Equal(t, 1, pool.getSize())
+}
+
+func TestWorkerPoolConfigWithDefaults(t *testing.T) {
+	config := WorkerPoolConfig{}.withDefaults()
+	assert.Equal(t, defaultWorkers, config.MinWorkers)
+	assert.Equal(t, defaultWorkers, config.MaxWorkers)
+	assert.Equal(t, defaultScalingInterval, config.ScalingInterval)
+	config = WorkerPoolConfig{MinWorkers: 3, MaxWorkers: 1}.withDefaults()
+	assert.Equal(t, 3, config.MaxWorkers)
+}
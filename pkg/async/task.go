@@ -17,6 +17,12 @@ type Task interface {
 	IncrementWorkerRejectionCount() int
 	ToJSON() ([]byte, error)
 	GetExecuteTime() *time.Time
+	// GetTenant returns the tenant, if any, on whose behalf the task is executed
+	GetTenant() string
+	// SetTenant sets the tenant on whose behalf the task is executed. Follow-up
+	// tasks for which no tenant has been set inherit the tenant of the task that
+	// returned them.
+	SetTenant(tenant string)
 }
 
 type task struct {
@@ -25,6 +31,7 @@ type task struct {
 	Args                 map[string]string `json:"args"`
 	WorkerRejectionCount int               `json:"workerRejectionCount"`
 	ExecuteTime          *time.Time        `json:"executeTime"`
+	Tenant               string            `json:"tenant"`
 }
 
 // NewTask returns a new task
@@ -100,3 +107,11 @@ func (t *task) ToJSON() ([]byte, error) {
 func (t *task) GetExecuteTime() *time.Time {
 	return t.ExecuteTime
 }
+
+func (t *task) GetTenant() string {
+	return t.Tenant
+}
+
+func (t *task) SetTenant(tenant string) {
+	t.Tenant = tenant
+}
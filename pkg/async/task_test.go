@@ -17,6 +17,7 @@ func init() {
 	jobName := "test-job"
 	argName := "foo"
 	argValue := "FOO"
+	tenant := "test-tenant"
 
 	testTask = NewTask(
 		jobName,
@@ -24,6 +25,7 @@ func init() {
 			argName: argValue,
 		},
 	)
+	testTask.SetTenant(tenant)
 
 	testTaskJSONStr := fmt.Sprintf(
 		`{
@@ -31,13 +33,15 @@ func init() {
 			"jobName":"%s",
 			"args":{"%s":"%s"},
 			"workerRejectionCount": %d,
-			"executeTime": null
+			"executeTime": null,
+			"tenant":"%s"
 		}`,
 		testTask.GetID(),
 		jobName,
 		argName,
 		argValue,
 		0,
+		tenant,
 	)
 	testTaskJSONStr = strings.Replace(testTaskJSONStr, " ", "", -1)
 	testTaskJSONStr = strings.Replace(testTaskJSONStr, "\n", "", -1)
@@ -0,0 +1,359 @@
+package arc
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/version"
+)
+
+const (
+	machinesProviderNamespace = "Microsoft.HybridCompute"
+	machinesResourceType      = "machines"
+	machinesAPIVersion        = "2024-07-10"
+	clustersProviderNamespace = "Microsoft.Kubernetes"
+	clustersResourceType      = "connectedClusters"
+	clustersAPIVersion        = "2024-01-01"
+	permissionsAPIVersion     = "2022-04-01"
+)
+
+// cloudNames maps the names of Azure environments to the names by which the
+// Azure Arc agents know the same clouds
+var cloudNames = map[string]string{
+	azure.PublicCloud.Name:       "AzureCloud",
+	azure.USGovernmentCloud.Name: "AzureUSGovernment",
+	azure.ChinaCloud.Name:        "AzureChinaCloud",
+}
+
+// Permission is a set of actions that the broker's identity is allowed to
+// perform, less those it is not
+type Permission struct {
+	Actions    []string `json:"actions"`
+	NotActions []string `json:"notActions"`
+}
+
+// ConnectedCluster is a Kubernetes cluster connected to Azure Arc
+type ConnectedCluster struct {
+	ID         string `json:"id"`
+	Properties struct {
+		ConnectivityStatus string `json:"connectivityStatus"`
+	} `json:"properties"`
+}
+
+// Manager is an interface to be implemented by any component capable of
+// managing the Azure resources that represent servers and Kubernetes clusters
+// connected to Azure Arc, and access to them
+type Manager interface {
+	GetTenantID() string
+	GetSubscriptionID() string
+	// GetCloudName returns the name by which the Azure Arc agents know the cloud
+	// the broker provisions in
+	GetCloudName() string
+	// GetPermissions returns the broker's own permissions in the named resource
+	// group or, if the resource group doesn't exist yet, in the subscription
+	GetPermissions(resourceGroupName string) ([]Permission, error)
+	// GetConnectedClusterID returns the resource ID that the named Kubernetes
+	// cluster has, or will have, once it is connected to Azure Arc
+	GetConnectedClusterID(clusterName string, resourceGroupName string) string
+	// GetConnectedCluster retrieves the named connected Kubernetes cluster. It
+	// returns a bool indicating whether the cluster was found.
+	GetConnectedCluster(
+		clusterName string,
+		resourceGroupName string,
+	) (ConnectedCluster, bool, error)
+	// CreateRoleAssignment assigns the role identified by the given (unqualified)
+	// role definition ID to the given principal at the given scope
+	CreateRoleAssignment(
+		scope string,
+		roleAssignmentName string,
+		roleDefinitionID string,
+		principalID string,
+	) error
+	// DeleteRoleAssignment deletes the named role assignment from the given
+	// scope. Deleting a role assignment that does not exist is not an error.
+	DeleteRoleAssignment(scope string, roleAssignmentName string) error
+	DeleteMachine(machineName string, resourceGroupName string) error
+	DeleteConnectedCluster(clusterName string, resourceGroupName string) error
+}
+
+type manager struct {
+	azureEnvironment azure.Environment
+	subscriptionID   string
+	tenantID         string
+	clientID         string
+	clientSecret     string
+	resourceClient   az.ResourceClient
+	az.RoleAssignmentClient
+}
+
+type permissionList struct {
+	Value    []Permission `json:"value"`
+	NextLink string       `json:"nextLink"`
+}
+
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	return &manager{
+		azureEnvironment: azureEnvironment,
+		subscriptionID:   azureConfig.SubscriptionID,
+		tenantID:         azureConfig.TenantID,
+		clientID:         azureConfig.ClientID,
+		clientSecret:     azureConfig.ClientSecret,
+		resourceClient: az.NewResourceClient(
+			azureEnvironment,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+		RoleAssignmentClient: az.NewRoleAssignmentClient(
+			azureEnvironment,
+			azureConfig.SubscriptionID,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+	}, nil
+}
+
+func (m *manager) GetTenantID() string {
+	return m.tenantID
+}
+
+func (m *manager) GetSubscriptionID() string {
+	return m.subscriptionID
+}
+
+func (m *manager) GetCloudName() string {
+	if cloudName, ok := cloudNames[m.azureEnvironment.Name]; ok {
+		return cloudName
+	}
+	return m.azureEnvironment.Name
+}
+
+// GetPermissions lists the broker's permissions directly, since the generic
+// resource client doesn't list resources
+func (m *manager) GetPermissions(
+	resourceGroupName string,
+) ([]Permission, error) {
+	permissions, found, err := m.listPermissions(
+		fmt.Sprintf(
+			"/subscriptions/%s/resourceGroups/%s",
+			m.subscriptionID,
+			resourceGroupName,
+		),
+	)
+	if err != nil || found {
+		return permissions, err
+	}
+	permissions, _, err = m.listPermissions(
+		fmt.Sprintf("/subscriptions/%s", m.subscriptionID),
+	)
+	return permissions, err
+}
+
+func (m *manager) GetConnectedClusterID(
+	clusterName string,
+	resourceGroupName string,
+) string {
+	return m.getConnectedClusterReference(clusterName, resourceGroupName).ID()
+}
+
+func (m *manager) GetConnectedCluster(
+	clusterName string,
+	resourceGroupName string,
+) (ConnectedCluster, bool, error) {
+	cluster := ConnectedCluster{}
+	found, err := m.resourceClient.GetResource(
+		m.getConnectedClusterReference(clusterName, resourceGroupName),
+		&cluster,
+	)
+	if err != nil {
+		return cluster, false, service.WrapError(
+			err,
+			"error retrieving connected Kubernetes cluster",
+		)
+	}
+	return cluster, found, nil
+}
+
+// DeleteMachine deletes the Azure resource that represents the named server,
+// which deregisters the server from Azure Arc. The Connected Machine agent
+// installed on the server itself is left in place, disconnected.
+func (m *manager) DeleteMachine(
+	machineName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		az.ResourceReference{
+			SubscriptionID:    m.subscriptionID,
+			ResourceGroupName: resourceGroupName,
+			ProviderNamespace: machinesProviderNamespace,
+			ResourceType:      machinesResourceType,
+			ResourceName:      machineName,
+			APIVersion:        machinesAPIVersion,
+		},
+	); err != nil {
+		return service.WrapError(err, "error deleting Azure Arc-enabled server")
+	}
+	return nil
+}
+
+// DeleteConnectedCluster deletes the Azure resource that represents the named
+// Kubernetes cluster, which deregisters the cluster from Azure Arc. The Azure
+// Arc agents deployed to the cluster itself are left in place.
+func (m *manager) DeleteConnectedCluster(
+	clusterName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		m.getConnectedClusterReference(clusterName, resourceGroupName),
+	); err != nil {
+		return service.WrapError(
+			err,
+			"error deleting connected Kubernetes cluster",
+		)
+	}
+	return nil
+}
+
+func (m *manager) getConnectedClusterReference(
+	clusterName string,
+	resourceGroupName string,
+) az.ResourceReference {
+	return az.ResourceReference{
+		SubscriptionID:    m.subscriptionID,
+		ResourceGroupName: resourceGroupName,
+		ProviderNamespace: clustersProviderNamespace,
+		ResourceType:      clustersResourceType,
+		ResourceName:      clusterName,
+		APIVersion:        clustersAPIVersion,
+	}
+}
+
+// listPermissions lists the broker's permissions at the given scope. It
+// returns a bool indicating whether the scope was found.
+func (m *manager) listPermissions(scope string) ([]Permission, bool, error) {
+	path := fmt.Sprintf("%s/providers/Microsoft.Authorization/permissions", scope)
+	permissions := []Permission{}
+	for path != "" {
+		list := permissionList{}
+		found, err := m.sendRequest(
+			autorest.AsGet(),
+			path,
+			permissionsAPIVersion,
+			nil,
+			&list,
+			http.StatusOK,
+		)
+		if err != nil {
+			return nil, false, service.WrapError(
+				err,
+				"error listing the broker's permissions",
+			)
+		}
+		if !found {
+			return nil, false, nil
+		}
+		permissions = append(permissions, list.Value...)
+		path = list.NextLink
+	}
+	return permissions, true, nil
+}
+
+// sendRequest sends a request to the Azure Resource Manager endpoint for the
+// given path, which may instead be an absolute URL (e.g. a nextLink), and
+// unmarshals the response (if any) into the provided result. It returns a bool
+// indicating whether the path was found; a path that isn't found is only an
+// error if the not found status isn't among those expected.
+func (m *manager) sendRequest(
+	method autorest.PrepareDecorator,
+	path string,
+	apiVersion string,
+	body interface{},
+	result interface{},
+	expectedStatusCodes ...int,
+) (bool, error) {
+	authorizer, err := az.GetBearerTokenAuthorizer(
+		m.azureEnvironment,
+		m.tenantID,
+		m.clientID,
+		m.clientSecret,
+	)
+	if err != nil {
+		return false, fmt.Errorf("error getting bearer token authorizer: %s", err)
+	}
+	client := autorest.NewClientWithUserAgent(
+		fmt.Sprintf("open-service-broker/%s", version.GetVersion()),
+	)
+	client.Authorizer = authorizer
+	decorators := []autorest.PrepareDecorator{method}
+	if strings.HasPrefix(path, "https://") {
+		decorators = append(decorators, autorest.WithBaseURL(path))
+	} else {
+		decorators = append(
+			decorators,
+			autorest.WithBaseURL(m.azureEnvironment.ResourceManagerEndpoint),
+			autorest.WithPath(path),
+			autorest.WithQueryParameters(map[string]interface{}{
+				"api-version": apiVersion,
+			}),
+		)
+	}
+	if body != nil {
+		decorators = append(
+			decorators,
+			autorest.AsJSON(),
+			autorest.WithJSON(body),
+		)
+	}
+	req, err := autorest.Prepare(&http.Request{}, decorators...)
+	if err != nil {
+		return false, fmt.Errorf("error preparing request: %s", err)
+	}
+	resp, err := autorest.SendWithSender(client, req)
+	if err != nil {
+		return false, fmt.Errorf("error sending request: %s", err)
+	}
+	if resp.StatusCode == http.StatusNotFound &&
+		!containsStatusCode(expectedStatusCodes, http.StatusNotFound) {
+		return false, autorest.Respond(resp, autorest.ByClosing())
+	}
+	responders := []autorest.RespondDecorator{
+		client.ByInspecting(),
+		azure.WithErrorUnlessStatusCode(expectedStatusCodes...),
+	}
+	if result != nil {
+		responders = append(responders, autorest.ByUnmarshallingJSON(result))
+	}
+	responders = append(responders, autorest.ByClosing())
+	if err := autorest.Respond(resp, responders...); err != nil {
+		return false, az.CategorizeError(err)
+	}
+	return true, nil
+}
+
+func containsStatusCode(statusCodes []int, statusCode int) bool {
+	for _, s := range statusCodes {
+		if s == statusCode {
+			return true
+		}
+	}
+	return false
+}
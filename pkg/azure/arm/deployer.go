@@ -11,10 +11,18 @@ import (
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/azure"
 	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
 	"github.com/Azure/open-service-broker-azure/pkg/template"
 	log "github.com/Sirupsen/logrus"
 )
 
+// HeritageTagName and HeritageTagValue make up the tag applied to every
+// resource the broker deploys
+const (
+	HeritageTagName  = "heritage"
+	HeritageTagValue = "open-service-broker-azure"
+)
+
 type deploymentStatus string
 
 const (
@@ -38,6 +46,22 @@ type Deployer interface {
 		tags map[string]string,
 	) (map[string]interface{}, error)
 	Delete(deploymentName string, resourceGroupName string) error
+	// DeployStack deploys an ARM template as the named Azure Deployment Stack,
+	// which manages the template's resources as a group, and returns the ID of
+	// the stack along with the template's outputs
+	DeployStack(
+		stackName string,
+		resourceGroupName string,
+		location string,
+		template []byte,
+		goParams interface{},
+		armParams map[string]interface{},
+		tags map[string]string,
+		settings StackSettings,
+	) (string, map[string]interface{}, error)
+	// DeleteStack deletes the named deployment stack along with the resources
+	// it manages
+	DeleteStack(stackName string, resourceGroupName string) error
 }
 
 // deployer is an ARM-based implementation of the Deployer interface
@@ -50,8 +74,9 @@ type deployer struct {
 }
 
 // NewDeployer returns a new ARM-based implementation of the Deployer interface
-func NewDeployer() (Deployer, error) {
-	azureConfig, err := az.GetConfig()
+// that deploys into the specified subscription
+func NewDeployer(subscriptionID string) (Deployer, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
 	if err != nil {
 		return nil, err
 	}
@@ -122,12 +147,14 @@ func (d *deployer) Deploy(
 		resourceGroupName,
 	)
 	if err != nil {
-		return nil, fmt.Errorf(
-			`error deploying "%s" in resource group "%s": error getting `+
-				`deployment: %s`,
-			deploymentName,
-			resourceGroupName,
+		return nil, service.WrapError(
 			err,
+			fmt.Sprintf(
+				`error deploying "%s" in resource group "%s": error getting `+
+					`deployment`,
+				deploymentName,
+				resourceGroupName,
+			),
 		)
 	}
 
@@ -150,11 +177,13 @@ func (d *deployer) Deploy(
 			armParams,
 			tags,
 		); err != nil {
-			return nil, fmt.Errorf(
-				`error deploying "%s" in resource group "%s": %s`,
-				deploymentName,
-				resourceGroupName,
+			return nil, service.WrapError(
 				err,
+				fmt.Sprintf(
+					`error deploying "%s" in resource group "%s"`,
+					deploymentName,
+					resourceGroupName,
+				),
 			)
 		}
 	case deploymentStatusRunning:
@@ -169,11 +198,13 @@ func (d *deployer) Deploy(
 			deploymentName,
 			resourceGroupName,
 		); err != nil {
-			return nil, fmt.Errorf(
-				`error deploying "%s" in resource group "%s": %s`,
-				deploymentName,
-				resourceGroupName,
+			return nil, service.WrapError(
 				err,
+				fmt.Sprintf(
+					`error deploying "%s" in resource group "%s"`,
+					deploymentName,
+					resourceGroupName,
+				),
 			)
 		}
 	case deploymentStatusSucceeded:
@@ -278,9 +309,8 @@ func getDeploymentAndStatus(
 ) (*resources.DeploymentExtended, deploymentStatus, error) {
 	deployment, err := deploymentsClient.Get(resourceGroupName, deploymentName)
 	if err != nil {
-		detailedErr, ok := err.(autorest.DetailedError)
-		if !ok || detailedErr.StatusCode != http.StatusNotFound {
-			return nil, "", err
+		if !az.IsNotFoundError(err) {
+			return nil, "", az.CategorizeError(err)
 		}
 		return nil, deploymentStatusNotFound, nil
 	}
@@ -307,6 +337,77 @@ func (d *deployer) doNewDeployment(
 	armParams map[string]interface{},
 	tags map[string]string,
 ) (*resources.DeploymentExtended, error) {
+	if err := d.ensureResourceGroup(
+		authorizer,
+		resourceGroupName,
+		location,
+	); err != nil {
+		return nil, err
+	}
+
+	armTemplateMap, armParamsMap, _, err := getTemplateAndParameters(
+		armTemplate,
+		goParams,
+		armParams,
+		location,
+		tags,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Deploy the template
+	cancelCh := make(chan struct{})
+	defer close(cancelCh)
+	_, errChan := deploymentsClient.CreateOrUpdate(
+		resourceGroupName,
+		deploymentName,
+		resources.Deployment{
+			Properties: &resources.DeploymentProperties{
+				Template:   &armTemplateMap,
+				Parameters: &armParamsMap,
+				Mode:       resources.Incremental,
+			},
+		},
+		cancelCh,
+	)
+	timer := time.NewTimer(time.Minute * 30)
+	defer timer.Stop()
+	select {
+	case err = <-errChan:
+		if err != nil {
+			return nil, service.WrapError(
+				az.CategorizeError(err),
+				"error submitting ARM template",
+			)
+		}
+	case <-timer.C:
+		return nil, service.NewCategorizedError(
+			service.ErrorCategoryTransient,
+			errors.New("timed out waiting for deployment to complete"),
+		)
+	}
+
+	// Deployment object found on the result channel doesn't include properties,
+	// so we need to make a separate call to retrieve the deployment
+	deployment, err := deploymentsClient.Get(resourceGroupName, deploymentName)
+	if err != nil {
+		return nil, service.WrapError(
+			az.CategorizeError(err),
+			"error retrieving completed deployment",
+		)
+	}
+
+	return &deployment, nil
+}
+
+// ensureResourceGroup creates the named resource group in the specified
+// location if it doesn't already exist
+func (d *deployer) ensureResourceGroup(
+	authorizer autorest.Authorizer,
+	resourceGroupName string,
+	location string,
+) error {
 	groupsClient := resources.NewGroupsClientWithBaseURI(
 		d.azureEnvironment.ResourceManagerEndpoint,
 		d.subscriptionID,
@@ -319,7 +420,7 @@ func (d *deployer) doNewDeployment(
 	groupsClient.Authorizer = authorizer
 	res, err := groupsClient.CheckExistence(resourceGroupName)
 	if err != nil {
-		return nil, fmt.Errorf(
+		return fmt.Errorf(
 			"error checking existence of resource group: %s",
 			err,
 		)
@@ -332,29 +433,50 @@ func (d *deployer) doNewDeployment(
 				Location: &location,
 			},
 		); err != nil {
-			return nil, fmt.Errorf(
+			return fmt.Errorf(
 				"error creating resource group: %s",
 				err,
 			)
 		}
 	}
+	return nil
+}
 
+// getTemplateAndParameters renders the given template, if it's a Go text
+// template, and returns it as a map along with the parameters to deploy it
+// with. The parameters are augmented with the location and the tags, which
+// are themselves augmented with heritage information and also returned.
+func getTemplateAndParameters(
+	armTemplate []byte,
+	goParams interface{},
+	armParams map[string]interface{},
+	location string,
+	tags map[string]string,
+) (map[string]interface{}, map[string]interface{}, map[string]string, error) {
 	finalArmTemplate := armTemplate
 
 	// The template could be a Go text template that renders down to an ARM
 	// template, so deal with that possibility first.
 	if goParams != nil {
+		var err error
 		finalArmTemplate, err = template.Render(armTemplate, goParams)
 		if err != nil {
-			return nil, err
+			return nil, nil, nil, err
 		}
 	}
 
 	// Unmarshal the template into a map
 	var armTemplateMap map[string]interface{}
-	err = json.Unmarshal(finalArmTemplate, &armTemplateMap)
-	if err != nil {
-		return nil, fmt.Errorf("error unmarshaling ARM template: %s", err)
+	if err := json.Unmarshal(finalArmTemplate, &armTemplateMap); err != nil {
+		return nil, nil, nil, fmt.Errorf(
+			"error unmarshaling ARM template: %s",
+			err,
+		)
+	}
+
+	// Deal with the possiiblity that params == nil
+	if armParams == nil {
+		armParams = make(map[string]interface{})
 	}
 
 	// Augment the params with location
@@ -366,12 +488,7 @@ func (d *deployer) doNewDeployment(
 	}
 
 	// Augment the provided tags with heritage information
-	tags["heritage"] = "open-service-broker-azure"
-
-	// Deal with the possiiblity that params == nil
-	if armParams == nil {
-		armParams = make(map[string]interface{})
-	}
+	tags[HeritageTagName] = HeritageTagValue
 
 	// Augment the params with tags
 	armParams["tags"] = tags
@@ -385,40 +502,7 @@ func (d *deployer) doNewDeployment(
 		}
 	}
 
-	// Deploy the template
-	cancelCh := make(chan struct{})
-	defer close(cancelCh)
-	_, errChan := deploymentsClient.CreateOrUpdate(
-		resourceGroupName,
-		deploymentName,
-		resources.Deployment{
-			Properties: &resources.DeploymentProperties{
-				Template:   &armTemplateMap,
-				Parameters: &armParamsMap,
-				Mode:       resources.Incremental,
-			},
-		},
-		cancelCh,
-	)
-	timer := time.NewTimer(time.Minute * 30)
-	defer timer.Stop()
-	select {
-	case err = <-errChan:
-		if err != nil {
-			return nil, fmt.Errorf("error submitting ARM template: %s", err)
-		}
-	case <-timer.C:
-		return nil, errors.New("timed out waiting for deployment to complete")
-	}
-
-	// Deployment object found on the result channel doesn't include properties,
-	// so we need to make a separate call to retrieve the deployment
-	deployment, err := deploymentsClient.Get(resourceGroupName, deploymentName)
-	if err != nil {
-		return nil, fmt.Errorf("error retrieving completed deployment: %s", err)
-	}
-
-	return &deployment, nil
+	return armTemplateMap, armParamsMap, tags, nil
 }
 
 // pollUntilComplete polls the status of a deployment periodically until the
@@ -468,8 +552,12 @@ func (d *deployer) pollUntilComplete(
 				return nil, errors.New("deployment is in an unrecognized state")
 			}
 		case <-timer.C:
-			// We've reached a timeout
-			return nil, errors.New("timed out waiting for deployment to complete")
+			// We've reached a timeout. The deployment may yet complete, so polling
+			// again later is worthwhile.
+			return nil, service.NewCategorizedError(
+				service.ErrorCategoryTransient,
+				errors.New("timed out waiting for deployment to complete"),
+			)
 		}
 	}
 }
@@ -0,0 +1,506 @@
+package arm
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const deploymentStacksAPIVersion = "2024-03-01"
+
+const (
+	// DenySettingsModeNone places no restrictions on the resources managed by
+	// a deployment stack
+	DenySettingsModeNone = "none"
+	// DenySettingsModeDenyDelete prevents the resources managed by a deployment
+	// stack from being deleted other than by deleting the stack
+	DenySettingsModeDenyDelete = "denyDelete"
+	// DenySettingsModeDenyWriteAndDelete prevents the resources managed by a
+	// deployment stack from being modified or deleted other than through the
+	// stack
+	DenySettingsModeDenyWriteAndDelete = "denyWriteAndDelete"
+)
+
+const (
+	// ActionOnUnmanageDelete deletes resources that a deployment stack ceases
+	// to manage
+	ActionOnUnmanageDelete = "delete"
+	// ActionOnUnmanageDetach leaves resources that a deployment stack ceases to
+	// manage in place
+	ActionOnUnmanageDetach = "detach"
+)
+
+// Azure limits the number of principals and actions that may be excluded from
+// a deployment stack's deny settings
+const (
+	maxExcludedPrincipals = 5
+	maxExcludedActions    = 200
+)
+
+// excludedActionRegex matches resource provider operations, e.g.
+// Microsoft.ConfidentialLedger/ledgers/write
+var excludedActionRegex = regexp.MustCompile(
+	`^[A-Za-z0-9]+(\.[A-Za-z0-9]+)+(/[A-Za-z0-9*]+)+$`,
+)
+
+// StackSettings encapsulates the options of an Azure Deployment Stack that a
+// module provisions its resources through. The stack manages the resources
+// as a group: its deny settings protect them from changes made other than
+// through the stack, and deleting the stack deletes them all.
+type StackSettings struct {
+	// DenySettingsMode determines which operations on the stack's resources
+	// are denied. It defaults to denyDelete.
+	DenySettingsMode string `json:"denySettingsMode"`
+	// ExcludedPrincipals are the object IDs of principals to which the deny
+	// settings don't apply
+	ExcludedPrincipals []string `json:"excludedPrincipals"`
+	// ExcludedActions are resource provider operations to which the deny
+	// settings don't apply
+	ExcludedActions []string `json:"excludedActions"`
+	// ApplyToChildScopes extends the deny settings to resources nested within
+	// the stack's resources
+	ApplyToChildScopes bool `json:"applyToChildScopes"`
+	// ActionOnUnmanage determines whether resources that the stack ceases to
+	// manage, because a later deployment of it no longer includes them, are
+	// deleted or detached. It defaults to delete.
+	ActionOnUnmanage string `json:"actionOnUnmanage"`
+}
+
+// ValidateStackSettings returns a validation error, reported against fields
+// nested within the specified field, if the given settings are invalid
+func ValidateStackSettings(field string, settings StackSettings) error {
+	switch settings.DenySettingsMode {
+	case "",
+		DenySettingsModeNone,
+		DenySettingsModeDenyDelete,
+		DenySettingsModeDenyWriteAndDelete:
+	default:
+		return service.NewValidationError(
+			field+".denySettingsMode",
+			fmt.Sprintf(
+				`invalid denySettingsMode: "%s"; allowed values are: %s, %s, %s`,
+				settings.DenySettingsMode,
+				DenySettingsModeNone,
+				DenySettingsModeDenyDelete,
+				DenySettingsModeDenyWriteAndDelete,
+			),
+		)
+	}
+	// Exclusions from deny settings that deny nothing would be meaningless
+	if settings.DenySettingsMode == DenySettingsModeNone {
+		if len(settings.ExcludedPrincipals) > 0 {
+			return service.NewValidationError(
+				field+".excludedPrincipals",
+				"excludedPrincipals may not be specified when denySettingsMode is "+
+					DenySettingsModeNone,
+			)
+		}
+		if len(settings.ExcludedActions) > 0 {
+			return service.NewValidationError(
+				field+".excludedActions",
+				"excludedActions may not be specified when denySettingsMode is "+
+					DenySettingsModeNone,
+			)
+		}
+		if settings.ApplyToChildScopes {
+			return service.NewValidationError(
+				field+".applyToChildScopes",
+				"applyToChildScopes may not be specified when denySettingsMode is "+
+					DenySettingsModeNone,
+			)
+		}
+	}
+	if len(settings.ExcludedPrincipals) > maxExcludedPrincipals {
+		return service.NewValidationError(
+			field+".excludedPrincipals",
+			fmt.Sprintf(
+				"no more than %d principals may be excluded",
+				maxExcludedPrincipals,
+			),
+		)
+	}
+	for i, principalID := range settings.ExcludedPrincipals {
+		if id, err := uuid.FromString(principalID); err != nil ||
+			!strings.EqualFold(id.String(), principalID) {
+			return service.NewValidationError(
+				fmt.Sprintf("%s.excludedPrincipals[%d]", field, i),
+				fmt.Sprintf(`invalid principal object ID: "%s"`, principalID),
+			)
+		}
+	}
+	if len(settings.ExcludedActions) > maxExcludedActions {
+		return service.NewValidationError(
+			field+".excludedActions",
+			fmt.Sprintf(
+				"no more than %d actions may be excluded",
+				maxExcludedActions,
+			),
+		)
+	}
+	for i, action := range settings.ExcludedActions {
+		if !excludedActionRegex.MatchString(action) {
+			return service.NewValidationError(
+				fmt.Sprintf("%s.excludedActions[%d]", field, i),
+				fmt.Sprintf(`invalid resource provider operation: "%s"`, action),
+			)
+		}
+	}
+	switch settings.ActionOnUnmanage {
+	case "", ActionOnUnmanageDelete, ActionOnUnmanageDetach:
+	default:
+		return service.NewValidationError(
+			field+".actionOnUnmanage",
+			fmt.Sprintf(
+				`invalid actionOnUnmanage: "%s"; allowed values are: %s, %s`,
+				settings.ActionOnUnmanage,
+				ActionOnUnmanageDelete,
+				ActionOnUnmanageDetach,
+			),
+		)
+	}
+	return nil
+}
+
+// withDefaults returns a copy of the settings with defaults applied
+func (s StackSettings) withDefaults() StackSettings {
+	if s.DenySettingsMode == "" {
+		s.DenySettingsMode = DenySettingsModeDenyDelete
+	}
+	if s.ActionOnUnmanage == "" {
+		s.ActionOnUnmanage = ActionOnUnmanageDelete
+	}
+	if s.ExcludedPrincipals == nil {
+		s.ExcludedPrincipals = []string{}
+	}
+	if s.ExcludedActions == nil {
+		s.ExcludedActions = []string{}
+	}
+	return s
+}
+
+type deploymentStack struct {
+	ID         string                    `json:"id,omitempty"`
+	Tags       map[string]string         `json:"tags,omitempty"`
+	Properties deploymentStackProperties `json:"properties"`
+}
+
+type deploymentStackProperties struct {
+	Template          map[string]interface{}   `json:"template,omitempty"`
+	Parameters        map[string]interface{}   `json:"parameters,omitempty"`
+	ActionOnUnmanage  *stackActionOnUnmanage   `json:"actionOnUnmanage,omitempty"`
+	DenySettings      *stackDenySettings       `json:"denySettings,omitempty"`
+	ProvisioningState string                   `json:"provisioningState,omitempty"`
+	Outputs           map[string]interface{}   `json:"outputs,omitempty"`
+	Error             *stackError              `json:"error,omitempty"`
+	Resources         []map[string]interface{} `json:"resources,omitempty"`
+}
+
+type stackActionOnUnmanage struct {
+	Resources string `json:"resources"`
+}
+
+type stackDenySettings struct {
+	Mode               string   `json:"mode"`
+	ExcludedPrincipals []string `json:"excludedPrincipals"`
+	ExcludedActions    []string `json:"excludedActions"`
+	ApplyToChildScopes bool     `json:"applyToChildScopes"`
+}
+
+type stackError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// DeployStack idempotently deploys an ARM template as the named deployment
+// stack, blocking until the stack's deployment completes. Like Deploy, it
+// waits upon, rather than repeats, a deployment that is already in progress.
+func (d *deployer) DeployStack(
+	stackName string,
+	resourceGroupName string,
+	location string,
+	template []byte,
+	goParams interface{},
+	armParams map[string]interface{},
+	tags map[string]string,
+	settings StackSettings,
+) (string, map[string]interface{}, error) {
+	resourceClient := d.getResourceClient()
+	ref := d.getStackReference(stackName, resourceGroupName)
+	stack := deploymentStack{}
+	ok, err := resourceClient.GetResource(ref, &stack)
+	if err != nil {
+		return "", nil, service.WrapError(
+			err,
+			fmt.Sprintf(
+				`error deploying stack "%s" in resource group "%s": error getting `+
+					`stack`,
+				stackName,
+				resourceGroupName,
+			),
+		)
+	}
+	// A stack whose deployment is in progress is waited upon rather than
+	// deployed again
+	if ok && !isStackStateTerminal(stack.Properties.ProvisioningState) {
+		if stack, err = pollStackUntilComplete(resourceClient, ref); err != nil {
+			return "", nil, service.WrapError(
+				err,
+				fmt.Sprintf(
+					`error deploying stack "%s" in resource group "%s"`,
+					stackName,
+					resourceGroupName,
+				),
+			)
+		}
+	}
+	if !ok {
+		authorizer, err := az.GetBearerTokenAuthorizer(
+			d.azureEnvironment,
+			d.tenantID,
+			d.clientID,
+			d.clientSecret,
+		)
+		if err != nil {
+			return "", nil, fmt.Errorf(
+				`error deploying stack "%s" in resource group "%s": error getting `+
+					`bearer token authorizer: %s`,
+				stackName,
+				resourceGroupName,
+				err,
+			)
+		}
+		if err = d.ensureResourceGroup(
+			authorizer,
+			resourceGroupName,
+			location,
+		); err != nil {
+			return "", nil, err
+		}
+		armTemplateMap, armParamsMap, tags, err := getTemplateAndParameters(
+			template,
+			goParams,
+			armParams,
+			location,
+			tags,
+		)
+		if err != nil {
+			return "", nil, err
+		}
+		settings = settings.withDefaults()
+		if err = resourceClient.PutResource(
+			ref,
+			deploymentStack{
+				Tags: tags,
+				Properties: deploymentStackProperties{
+					Template:   armTemplateMap,
+					Parameters: armParamsMap,
+					ActionOnUnmanage: &stackActionOnUnmanage{
+						Resources: settings.ActionOnUnmanage,
+					},
+					DenySettings: &stackDenySettings{
+						Mode:               settings.DenySettingsMode,
+						ExcludedPrincipals: settings.ExcludedPrincipals,
+						ExcludedActions:    settings.ExcludedActions,
+						ApplyToChildScopes: settings.ApplyToChildScopes,
+					},
+				},
+			},
+			nil,
+		); err != nil {
+			return "", nil, service.WrapError(
+				err,
+				fmt.Sprintf(
+					`error deploying stack "%s" in resource group "%s"`,
+					stackName,
+					resourceGroupName,
+				),
+			)
+		}
+		// The result of the PUT describes the operation, not the stack
+		stack = deploymentStack{}
+		if _, err = resourceClient.GetResource(ref, &stack); err != nil {
+			return "", nil, service.WrapError(
+				err,
+				fmt.Sprintf(
+					`error retrieving deployed stack "%s" in resource group "%s"`,
+					stackName,
+					resourceGroupName,
+				),
+			)
+		}
+	}
+	if !strings.EqualFold(stack.Properties.ProvisioningState, "succeeded") {
+		msg := fmt.Sprintf(
+			`error deploying stack "%s" in resource group "%s": stack is in `+
+				`state "%s"`,
+			stackName,
+			resourceGroupName,
+			stack.Properties.ProvisioningState,
+		)
+		if stack.Properties.Error != nil {
+			msg = fmt.Sprintf(
+				"%s: %s: %s",
+				msg,
+				stack.Properties.Error.Code,
+				stack.Properties.Error.Message,
+			)
+		}
+		return "", nil, errors.New(msg)
+	}
+	outputs := map[string]interface{}{}
+	for k, v := range stack.Properties.Outputs {
+		if output, ok := v.(map[string]interface{}); ok {
+			outputs[k] = output["value"]
+		}
+	}
+	return stack.ID, outputs, nil
+}
+
+// DeleteStack deletes the named deployment stack along with every resource
+// it manages, and blocks until deletion completes. Deleting a stack that
+// doesn't exist is not an error.
+func (d *deployer) DeleteStack(
+	stackName string,
+	resourceGroupName string,
+) error {
+	ref := d.getStackReference(stackName, resourceGroupName)
+	authorizer, err := az.GetBearerTokenAuthorizer(
+		d.azureEnvironment,
+		d.tenantID,
+		d.clientID,
+		d.clientSecret,
+	)
+	if err != nil {
+		return fmt.Errorf(
+			`error deleting stack "%s" from resource group "%s": error getting `+
+				`bearer token authorizer: %s`,
+			stackName,
+			resourceGroupName,
+			err,
+		)
+	}
+	client := autorest.NewClientWithUserAgent(userAgent())
+	client.Authorizer = authorizer
+	// The resources a stack manages are deleted along with it, whatever it
+	// would do with resources it ceases to manage
+	req, err := autorest.Prepare(
+		&http.Request{},
+		autorest.AsDelete(),
+		autorest.WithBaseURL(d.azureEnvironment.ResourceManagerEndpoint),
+		autorest.WithPath(ref.ID()),
+		autorest.WithQueryParameters(map[string]interface{}{
+			"api-version":              deploymentStacksAPIVersion,
+			"unmanageAction.Resources": ActionOnUnmanageDelete,
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf(
+			`error preparing request to delete stack "%s": %s`,
+			ref.ID(),
+			err,
+		)
+	}
+	resp, err := autorest.SendWithSender(
+		client,
+		req,
+		azure.DoPollForAsynchronous(client.PollingDelay),
+	)
+	if err != nil {
+		return fmt.Errorf(`error deleting stack "%s": %s`, ref.ID(), err)
+	}
+	if err := autorest.Respond(
+		resp,
+		client.ByInspecting(),
+		azure.WithErrorUnlessStatusCode(
+			http.StatusOK,
+			http.StatusAccepted,
+			http.StatusNoContent,
+			http.StatusNotFound,
+		),
+		autorest.ByClosing(),
+	); err != nil {
+		return service.WrapError(
+			az.CategorizeError(err),
+			fmt.Sprintf(`error deleting stack "%s"`, ref.ID()),
+		)
+	}
+	return nil
+}
+
+// isStackStateTerminal returns a bool indicating whether a deployment stack in
+// the given provisioning state has finished deploying
+func isStackStateTerminal(provisioningState string) bool {
+	for _, state := range []string{"succeeded", "failed", "canceled"} {
+		if strings.EqualFold(provisioningState, state) {
+			return true
+		}
+	}
+	return false
+}
+
+// pollStackUntilComplete polls the referenced deployment stack periodically
+// until its deployment completes, polling fails, or a timeout is reached
+func pollStackUntilComplete(
+	resourceClient az.ResourceClient,
+	ref az.ResourceReference,
+) (deploymentStack, error) {
+	ticker := time.NewTicker(time.Second * 10)
+	defer ticker.Stop()
+	timer := time.NewTimer(time.Minute * 30)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			stack := deploymentStack{}
+			ok, err := resourceClient.GetResource(ref, &stack)
+			if err != nil {
+				return stack, err
+			}
+			if !ok {
+				return stack, errors.New(
+					"error polling stack status; stack should exist, but does not",
+				)
+			}
+			if isStackStateTerminal(stack.Properties.ProvisioningState) {
+				return stack, nil
+			}
+		case <-timer.C:
+			return deploymentStack{}, service.NewCategorizedError(
+				service.ErrorCategoryTransient,
+				errors.New("timed out waiting for stack deployment to complete"),
+			)
+		}
+	}
+}
+
+func (d *deployer) getResourceClient() az.ResourceClient {
+	return az.NewResourceClient(
+		d.azureEnvironment,
+		d.tenantID,
+		d.clientID,
+		d.clientSecret,
+	)
+}
+
+func (d *deployer) getStackReference(
+	stackName string,
+	resourceGroupName string,
+) az.ResourceReference {
+	return az.ResourceReference{
+		SubscriptionID:    d.subscriptionID,
+		ResourceGroupName: resourceGroupName,
+		ProviderNamespace: "Microsoft.Resources",
+		ResourceType:      "deploymentStacks",
+		ResourceName:      stackName,
+		APIVersion:        deploymentStacksAPIVersion,
+	}
+}
@@ -15,6 +15,25 @@ func GetBearerTokenAuthorizer(
 	tenantID string,
 	clientID string,
 	clientSecret string,
+) (*autorest.BearerAuthorizer, error) {
+	return GetBearerTokenAuthorizerForResource(
+		azureEnvironment,
+		tenantID,
+		clientID,
+		clientSecret,
+		azureEnvironment.ResourceManagerEndpoint,
+	)
+}
+
+// GetBearerTokenAuthorizerForResource returns a *autorest.BearerAuthorizer
+// used for authenticating outbound requests to the given resource, e.g. a data
+// plane API such as Key Vault's, rather than to Azure Resource Manager
+func GetBearerTokenAuthorizerForResource(
+	azureEnvironment azure.Environment,
+	tenantID string,
+	clientID string,
+	clientSecret string,
+	resource string,
 ) (*autorest.BearerAuthorizer, error) {
 	// Get a token used for authorizing requests to Azure
 	oauthConfig, err := adal.NewOAuthConfig(
@@ -28,7 +47,7 @@ func GetBearerTokenAuthorizer(
 		*oauthConfig,
 		clientID,
 		clientSecret,
-		azureEnvironment.ResourceManagerEndpoint,
+		resource,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error getting service principal token: %s", err)
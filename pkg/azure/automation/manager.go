@@ -0,0 +1,175 @@
+package automation
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest/azure"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+const (
+	providerNamespace = "Microsoft.Automation"
+	resourceType      = "automationAccounts"
+	apiVersion        = "2023-11-01"
+	// webhookAPIVersion is the latest api-version that addresses webhooks,
+	// which later api-versions of the provider don't cover
+	webhookAPIVersion = "2015-10-31"
+)
+
+// Manager is an interface to be implemented by any component capable of
+// managing Azure Automation accounts
+type Manager interface {
+	// CreateWebhook creates a webhook, expiring at the given time, by which the
+	// named runbook of an Automation account can be started, and returns the
+	// webhook's URL. Azure reveals a webhook's URL only when it is created.
+	CreateWebhook(
+		accountName string,
+		resourceGroupName string,
+		webhookName string,
+		runbookName string,
+		expiry time.Time,
+	) (string, error)
+	// DeleteWebhook deletes the named webhook of an Automation account.
+	// Deleting a webhook that does not exist is not an error.
+	DeleteWebhook(
+		accountName string,
+		resourceGroupName string,
+		webhookName string,
+	) error
+	DeleteAccount(accountName string, resourceGroupName string) error
+}
+
+type manager struct {
+	subscriptionID string
+	resourceClient az.ResourceClient
+}
+
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	return &manager{
+		subscriptionID: azureConfig.SubscriptionID,
+		resourceClient: az.NewResourceClient(
+			azureEnvironment,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+	}, nil
+}
+
+func (m *manager) CreateWebhook(
+	accountName string,
+	resourceGroupName string,
+	webhookName string,
+	runbookName string,
+	expiry time.Time,
+) (string, error) {
+	accountRef := m.getReference(accountName, resourceGroupName)
+	accountRef.APIVersion = webhookAPIVersion
+	// A webhook's URL, including the token that authorizes it, is generated
+	// by Azure and must be supplied when the webhook is created
+	var url string
+	if err := m.resourceClient.InvokeAction(
+		accountRef,
+		"webhooks/generateUri",
+		nil,
+		&url,
+	); err != nil {
+		return "", service.WrapError(err, "error generating webhook url")
+	}
+	if err := m.resourceClient.PutResource(
+		m.getWebhookReference(accountName, resourceGroupName, webhookName),
+		map[string]interface{}{
+			"name": webhookName,
+			"properties": map[string]interface{}{
+				"isEnabled":  true,
+				"uri":        url,
+				"expiryTime": expiry.UTC().Format(time.RFC3339),
+				"runbook": map[string]string{
+					"name": runbookName,
+				},
+			},
+		},
+		nil,
+	); err != nil {
+		return "", service.WrapError(
+			err,
+			fmt.Sprintf(`error creating webhook for runbook "%s"`, runbookName),
+		)
+	}
+	return url, nil
+}
+
+func (m *manager) DeleteWebhook(
+	accountName string,
+	resourceGroupName string,
+	webhookName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		m.getWebhookReference(accountName, resourceGroupName, webhookName),
+	); err != nil {
+		return service.WrapError(
+			err,
+			fmt.Sprintf(`error deleting webhook "%s"`, webhookName),
+		)
+	}
+	return nil
+}
+
+func (m *manager) DeleteAccount(
+	accountName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		m.getReference(accountName, resourceGroupName),
+	); err != nil {
+		return service.WrapError(
+			err,
+			"error deleting Azure Automation account",
+		)
+	}
+	return nil
+}
+
+func (m *manager) getReference(
+	accountName string,
+	resourceGroupName string,
+) az.ResourceReference {
+	return az.ResourceReference{
+		SubscriptionID:    m.subscriptionID,
+		ResourceGroupName: resourceGroupName,
+		ProviderNamespace: providerNamespace,
+		ResourceType:      resourceType,
+		ResourceName:      accountName,
+		APIVersion:        apiVersion,
+	}
+}
+
+func (m *manager) getWebhookReference(
+	accountName string,
+	resourceGroupName string,
+	webhookName string,
+) az.ResourceReference {
+	return az.ResourceReference{
+		SubscriptionID:    m.subscriptionID,
+		ResourceGroupName: resourceGroupName,
+		ProviderNamespace: providerNamespace,
+		ResourceType:      fmt.Sprintf("%s/%s/webhooks", resourceType, accountName),
+		ResourceName:      webhookName,
+		APIVersion:        webhookAPIVersion,
+	}
+}
@@ -0,0 +1,43 @@
+package autoscale
+
+// ARMTemplateBytes is an ARM template for an autoscale setting that applies
+// the given profiles to the resource identified by targetResourceId
+// nolint: lll
+var ARMTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "autoscaleSettingName": {
+      "type": "string"
+    },
+    "targetResourceId": {
+      "type": "string"
+    },
+    "profiles": {
+      "type": "array"
+    },
+    "tags": {
+      "type": "object"
+    }
+  },
+  "resources": [
+    {
+      "apiVersion": "2015-04-01",
+      "name": "[parameters('autoscaleSettingName')]",
+      "type": "Microsoft.Insights/autoscalesettings",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "properties": {
+        "name": "[parameters('autoscaleSettingName')]",
+        "enabled": true,
+        "targetResourceUri": "[parameters('targetResourceId')]",
+        "profiles": "[parameters('profiles')]"
+      }
+    }
+  ]
+}
+`)
@@ -0,0 +1,70 @@
+package autoscale
+
+import (
+	"fmt"
+
+	"github.com/Azure/go-autorest/autorest/azure"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// Manager is an interface to be implemented by any component capable of
+// managing Azure autoscale settings
+type Manager interface {
+	DeleteAutoscaleSetting(
+		autoscaleSettingName string,
+		resourceGroupName string,
+	) error
+}
+
+type manager struct {
+	subscriptionID string
+	resourceClient az.ResourceClient
+}
+
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	return &manager{
+		subscriptionID: azureConfig.SubscriptionID,
+		resourceClient: az.NewResourceClient(
+			azureEnvironment,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+	}, nil
+}
+
+// DeleteAutoscaleSetting deletes the named autoscale setting. Autoscale
+// settings are resources in their own right and are not deleted along with
+// the resources they target.
+func (m *manager) DeleteAutoscaleSetting(
+	autoscaleSettingName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		az.ResourceReference{
+			SubscriptionID:    m.subscriptionID,
+			ResourceGroupName: resourceGroupName,
+			ProviderNamespace: "Microsoft.Insights",
+			ResourceType:      "autoscalesettings",
+			ResourceName:      autoscaleSettingName,
+			APIVersion:        "2015-04-01",
+		},
+	); err != nil {
+		return service.WrapError(err, "error deleting autoscale setting")
+	}
+	return nil
+}
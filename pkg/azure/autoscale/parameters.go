@@ -0,0 +1,172 @@
+package autoscale
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+const (
+	defaultTimeZone = "UTC"
+	// Azure permits no more than 20 profiles in a single autoscale setting
+	maxProfiles = 20
+)
+
+var (
+	startTimeRegex = regexp.MustCompile(`^([01][0-9]|2[0-3]):([0-5][0-9])$`)
+	days           = map[string]bool{
+		"Monday":    true,
+		"Tuesday":   true,
+		"Wednesday": true,
+		"Thursday":  true,
+		"Friday":    true,
+		"Saturday":  true,
+		"Sunday":    true,
+	}
+)
+
+// Parameters encapsulates the provisioning parameters that describe a
+// schedule on which a resource's instance count changes. Each profile takes
+// effect at its start time on each of its days and remains in effect until
+// the next profile's start time.
+type Parameters struct {
+	TimeZone string              `json:"timeZone"`
+	Profiles []ProfileParameters `json:"profiles"`
+}
+
+// ProfileParameters encapsulates the provisioning parameters that describe a
+// single recurring autoscale profile
+type ProfileParameters struct {
+	Name      string   `json:"name"`
+	Days      []string `json:"days"`
+	StartTime string   `json:"startTime"`
+	Minimum   int      `json:"minimum"`
+	Maximum   int      `json:"maximum"`
+	Default   int      `json:"default"`
+}
+
+// Validate validates the given autoscale parameters, including that every
+// profile's instance counts fall between the given bounds. Field names in any
+// validation error returned are qualified with the given field name.
+func Validate(
+	field string,
+	params *Parameters,
+	minCapacity int,
+	maxCapacity int,
+) error {
+	if len(params.Profiles) == 0 {
+		return service.NewValidationError(
+			field+".profiles",
+			"at least one profile must be specified",
+		)
+	}
+	if len(params.Profiles) > maxProfiles {
+		return service.NewValidationError(
+			field+".profiles",
+			fmt.Sprintf("no more than %d profiles may be specified", maxProfiles),
+		)
+	}
+	names := map[string]bool{}
+	for i, profile := range params.Profiles {
+		profileField := fmt.Sprintf("%s.profiles[%d]", field, i)
+		if strings.TrimSpace(profile.Name) == "" {
+			return service.NewValidationError(
+				profileField+".name",
+				"name must be specified",
+			)
+		}
+		if names[profile.Name] {
+			return service.NewValidationError(
+				profileField+".name",
+				fmt.Sprintf(`duplicate profile name: "%s"`, profile.Name),
+			)
+		}
+		names[profile.Name] = true
+		if len(profile.Days) == 0 {
+			return service.NewValidationError(
+				profileField+".days",
+				"at least one day must be specified",
+			)
+		}
+		for _, day := range profile.Days {
+			if !days[day] {
+				return service.NewValidationError(
+					profileField+".days",
+					fmt.Sprintf(`invalid day: "%s"`, day),
+				)
+			}
+		}
+		if !startTimeRegex.MatchString(profile.StartTime) {
+			return service.NewValidationError(
+				profileField+".startTime",
+				fmt.Sprintf(
+					`invalid startTime: "%s"; time must be formatted as HH:MM`,
+					profile.StartTime,
+				),
+			)
+		}
+		if profile.Minimum < minCapacity || profile.Maximum > maxCapacity {
+			return service.NewValidationError(
+				profileField,
+				fmt.Sprintf(
+					"instance counts must be between %d and %d",
+					minCapacity,
+					maxCapacity,
+				),
+			)
+		}
+		if profile.Default < profile.Minimum ||
+			profile.Default > profile.Maximum {
+			return service.NewValidationError(
+				profileField+".default",
+				fmt.Sprintf(
+					"invalid default: %d; default must be between minimum (%d) and "+
+						"maximum (%d)",
+					profile.Default,
+					profile.Minimum,
+					profile.Maximum,
+				),
+			)
+		}
+	}
+	return nil
+}
+
+// GetARMProfiles returns the autoscale profiles described by the given
+// parameters in the form expected by the profiles parameter of
+// ARMTemplateBytes. Parameters are assumed to have already been validated.
+func GetARMProfiles(params *Parameters) []map[string]interface{} {
+	timeZone := params.TimeZone
+	if timeZone == "" {
+		timeZone = defaultTimeZone
+	}
+	profiles := make([]map[string]interface{}, len(params.Profiles))
+	for i, profile := range params.Profiles {
+		matches := startTimeRegex.FindStringSubmatch(profile.StartTime)
+		hour, _ := strconv.Atoi(matches[1])
+		minute, _ := strconv.Atoi(matches[2])
+		profiles[i] = map[string]interface{}{
+			"name": profile.Name,
+			// Capacities are expressed as strings
+			"capacity": map[string]interface{}{
+				"minimum": strconv.Itoa(profile.Minimum),
+				"maximum": strconv.Itoa(profile.Maximum),
+				"default": strconv.Itoa(profile.Default),
+			},
+			"rules": []interface{}{},
+			"recurrence": map[string]interface{}{
+				"frequency": "Week",
+				"schedule": map[string]interface{}{
+					"timeZone": timeZone,
+					"days":     profile.Days,
+					"hours":    []int{hour},
+					"minutes":  []int{minute},
+				},
+			},
+		}
+	}
+	return profiles
+}
@@ -0,0 +1,338 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/version"
+)
+
+const (
+	providerNamespace         = "Microsoft.RecoveryServices"
+	vaultResourceType         = "vaults"
+	policyResourceType        = "backupPolicies"
+	protectedItemsListingType = "backupProtectedItems"
+	apiVersion                = "2023-04-01"
+
+	// fabricName is the only backup fabric Azure resources are protected in
+	fabricName = "Azure"
+
+	protectionPollingInterval = 10 * time.Second
+)
+
+// Vault is a Recovery Services vault
+type Vault struct {
+	ID   string            `json:"id"`
+	Tags map[string]string `json:"tags"`
+}
+
+// ProtectedItem is a resource enrolled in backup protection by a vault
+type ProtectedItem struct {
+	ID       string
+	PolicyID string
+}
+
+// Manager is an interface to be implemented by any component capable of
+// managing Recovery Services vaults, their backup policies, and the resources
+// they protect
+type Manager interface {
+	// GetVault retrieves the named vault. It returns a bool indicating whether
+	// the vault was found.
+	GetVault(vaultName string, resourceGroupName string) (Vault, bool, error)
+	// GetVaultProtectedItems returns the items the named vault protects,
+	// including those whose protection was stopped but whose backups have yet
+	// to be deleted
+	GetVaultProtectedItems(
+		vaultName string,
+		resourceGroupName string,
+	) ([]ProtectedItem, error)
+	// StopProtection stops protecting the given item and blocks until Azure has
+	// done so
+	StopProtection(
+		ctx context.Context,
+		vaultName string,
+		containerName string,
+		protectedItemName string,
+		resourceGroupName string,
+	) error
+	DeletePolicy(
+		vaultName string,
+		policyName string,
+		resourceGroupName string,
+	) error
+	// DeleteVault deletes the named vault. Azure refuses to delete a vault that
+	// still protects any items.
+	DeleteVault(vaultName string, resourceGroupName string) error
+}
+
+type manager struct {
+	azureEnvironment azure.Environment
+	subscriptionID   string
+	tenantID         string
+	clientID         string
+	clientSecret     string
+	resourceClient   az.ResourceClient
+}
+
+type protectedItem struct {
+	ID         string `json:"id"`
+	Properties struct {
+		PolicyID string `json:"policyId"`
+		// IsScheduledForDeferredDelete is set on items whose protection was
+		// stopped while the vault's soft delete feature is enabled
+		IsScheduledForDeferredDelete bool `json:"isScheduledForDeferredDelete"`
+	} `json:"properties"`
+}
+
+type protectedItemList struct {
+	Value    []protectedItem `json:"value"`
+	NextLink string          `json:"nextLink"`
+}
+
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	return &manager{
+		azureEnvironment: azureEnvironment,
+		subscriptionID:   azureConfig.SubscriptionID,
+		tenantID:         azureConfig.TenantID,
+		clientID:         azureConfig.ClientID,
+		clientSecret:     azureConfig.ClientSecret,
+		resourceClient: az.NewResourceClient(
+			azureEnvironment,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+	}, nil
+}
+
+func (m *manager) GetVault(
+	vaultName string,
+	resourceGroupName string,
+) (Vault, bool, error) {
+	vault := Vault{}
+	found, err := m.resourceClient.GetResource(
+		m.getVaultReference(vaultName, resourceGroupName),
+		&vault,
+	)
+	if err != nil {
+		return vault, false, service.WrapError(
+			err,
+			"error retrieving Recovery Services vault",
+		)
+	}
+	return vault, found, nil
+}
+
+// GetVaultProtectedItems lists the vault's protected items directly, since
+// the generic resource client doesn't list resources
+func (m *manager) GetVaultProtectedItems(
+	vaultName string,
+	resourceGroupName string,
+) ([]ProtectedItem, error) {
+	authorizer, err := az.GetBearerTokenAuthorizer(
+		m.azureEnvironment,
+		m.tenantID,
+		m.clientID,
+		m.clientSecret,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error getting bearer token authorizer: %s", err)
+	}
+	client := autorest.NewClientWithUserAgent(
+		fmt.Sprintf("open-service-broker/%s", version.GetVersion()),
+	)
+	client.Authorizer = authorizer
+	req, err := autorest.Prepare(
+		&http.Request{},
+		autorest.AsGet(),
+		autorest.WithBaseURL(m.azureEnvironment.ResourceManagerEndpoint),
+		autorest.WithPath(
+			fmt.Sprintf(
+				"%s/%s",
+				m.getVaultReference(vaultName, resourceGroupName).ID(),
+				protectedItemsListingType,
+			),
+		),
+		autorest.WithQueryParameters(map[string]interface{}{
+			"api-version": apiVersion,
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error preparing request: %s", err)
+	}
+	protectedItems := []ProtectedItem{}
+	for {
+		resp, err := autorest.SendWithSender(client, req)
+		if err != nil {
+			return nil, fmt.Errorf("error sending request: %s", err)
+		}
+		items := protectedItemList{}
+		if err := autorest.Respond(
+			resp,
+			client.ByInspecting(),
+			azure.WithErrorUnlessStatusCode(http.StatusOK),
+			autorest.ByUnmarshallingJSON(&items),
+			autorest.ByClosing(),
+		); err != nil {
+			return nil, service.WrapError(
+				az.CategorizeError(err),
+				"error listing backup protected items",
+			)
+		}
+		for _, item := range items.Value {
+			protectedItems = append(protectedItems, ProtectedItem{
+				ID:       item.ID,
+				PolicyID: item.Properties.PolicyID,
+			})
+		}
+		if items.NextLink == "" {
+			return protectedItems, nil
+		}
+		if req, err = autorest.Prepare(
+			&http.Request{},
+			autorest.AsGet(),
+			autorest.WithBaseURL(items.NextLink),
+		); err != nil {
+			return nil, fmt.Errorf("error preparing request: %s", err)
+		}
+	}
+}
+
+// StopProtection deletes the protected item, which Azure does asynchronously.
+// With soft delete enabled on the vault, the item isn't removed but is instead
+// scheduled for deferred deletion, along with its backups; protection has
+// stopped all the same.
+func (m *manager) StopProtection(
+	ctx context.Context,
+	vaultName string,
+	containerName string,
+	protectedItemName string,
+	resourceGroupName string,
+) error {
+	ref := m.getProtectedItemReference(
+		vaultName,
+		containerName,
+		protectedItemName,
+		resourceGroupName,
+	)
+	if err := m.resourceClient.DeleteResource(ref); err != nil {
+		return service.WrapError(err, "error stopping backup protection")
+	}
+	ticker := time.NewTicker(protectionPollingInterval)
+	defer ticker.Stop()
+	for {
+		item := protectedItem{}
+		found, err := m.resourceClient.GetResource(ref, &item)
+		if err != nil {
+			return service.WrapError(err, "error retrieving backup protected item")
+		}
+		if !found || item.Properties.IsScheduledForDeferredDelete {
+			return nil
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (m *manager) DeletePolicy(
+	vaultName string,
+	policyName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		m.getPolicyReference(vaultName, policyName, resourceGroupName),
+	); err != nil {
+		return service.WrapError(err, "error deleting backup policy")
+	}
+	return nil
+}
+
+func (m *manager) DeleteVault(
+	vaultName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		m.getVaultReference(vaultName, resourceGroupName),
+	); err != nil {
+		return service.WrapError(err, "error deleting Recovery Services vault")
+	}
+	return nil
+}
+
+func (m *manager) getVaultReference(
+	vaultName string,
+	resourceGroupName string,
+) az.ResourceReference {
+	return az.ResourceReference{
+		SubscriptionID:    m.subscriptionID,
+		ResourceGroupName: resourceGroupName,
+		ProviderNamespace: providerNamespace,
+		ResourceType:      vaultResourceType,
+		ResourceName:      vaultName,
+		APIVersion:        apiVersion,
+	}
+}
+
+func (m *manager) getPolicyReference(
+	vaultName string,
+	policyName string,
+	resourceGroupName string,
+) az.ResourceReference {
+	return az.ResourceReference{
+		SubscriptionID:    m.subscriptionID,
+		ResourceGroupName: resourceGroupName,
+		ProviderNamespace: providerNamespace,
+		ResourceType: fmt.Sprintf(
+			"%s/%s/%s",
+			vaultResourceType,
+			vaultName,
+			policyResourceType,
+		),
+		ResourceName: policyName,
+		APIVersion:   apiVersion,
+	}
+}
+
+func (m *manager) getProtectedItemReference(
+	vaultName string,
+	containerName string,
+	protectedItemName string,
+	resourceGroupName string,
+) az.ResourceReference {
+	return az.ResourceReference{
+		SubscriptionID:    m.subscriptionID,
+		ResourceGroupName: resourceGroupName,
+		ProviderNamespace: providerNamespace,
+		ResourceType: fmt.Sprintf(
+			"%s/%s/backupFabrics/%s/protectionContainers/%s/protectedItems",
+			vaultResourceType,
+			vaultName,
+			fabricName,
+			containerName,
+		),
+		ResourceName: protectedItemName,
+		APIVersion:   apiVersion,
+	}
+}
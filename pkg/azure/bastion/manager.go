@@ -0,0 +1,183 @@
+package bastion
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest/azure"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+)
+
+const (
+	networkProviderNamespace = "Microsoft.Network"
+	bastionHostType          = "bastionHosts"
+	publicIPAddressType      = "publicIPAddresses"
+	virtualNetworkType       = "virtualNetworks"
+	apiVersion               = "2021-05-01"
+)
+
+// Subnet encapsulates those parts of a virtual network subnet that determine
+// whether a Bastion host can be deployed into it
+type Subnet struct {
+	Name string
+	// AddressPrefixes are the subnet's address ranges in CIDR notation
+	AddressPrefixes []string
+	// Location is the location of the subnet's virtual network
+	Location string
+}
+
+// Manager is an interface to be implemented by any component capable of
+// managing Azure Bastion hosts
+type Manager interface {
+	// GetSubnet retrieves the subnet having the given resource ID. It returns a
+	// bool indicating whether the subnet was found.
+	GetSubnet(subnetID string) (*Subnet, bool, error)
+	DeleteBastionHost(
+		bastionHostName string,
+		resourceGroupName string,
+	) error
+	DeletePublicIPAddress(
+		publicIPAddressName string,
+		resourceGroupName string,
+	) error
+}
+
+type manager struct {
+	subscriptionID string
+	resourceClient az.ResourceClient
+}
+
+type virtualNetwork struct {
+	Location   string `json:"location"`
+	Properties struct {
+		Subnets []struct {
+			Name       string `json:"name"`
+			Properties struct {
+				AddressPrefix   string   `json:"addressPrefix"`
+				AddressPrefixes []string `json:"addressPrefixes"`
+			} `json:"properties"`
+		} `json:"subnets"`
+	} `json:"properties"`
+}
+
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	return &manager{
+		subscriptionID: azureConfig.SubscriptionID,
+		resourceClient: az.NewResourceClient(
+			azureEnvironment,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+	}, nil
+}
+
+// GetSubnetName returns the name of the subnet identified by the given,
+// well-formed, subnet resource ID
+func GetSubnetName(subnetID string) string {
+	id, _ := az.ParseSubnetID(subnetID)
+	return id.SubnetName
+}
+
+func (m *manager) GetSubnet(subnetID string) (*Subnet, bool, error) {
+	id, ok := az.ParseSubnetID(subnetID)
+	if !ok {
+		return nil, false, fmt.Errorf(`invalid subnet id "%s"`, subnetID)
+	}
+	vnet := &virtualNetwork{}
+	ok, err := m.resourceClient.GetResource(
+		az.ResourceReference{
+			SubscriptionID:    id.SubscriptionID,
+			ResourceGroupName: id.ResourceGroupName,
+			ProviderNamespace: networkProviderNamespace,
+			ResourceType:      virtualNetworkType,
+			ResourceName:      id.VirtualNetworkName,
+			APIVersion:        apiVersion,
+		},
+		vnet,
+	)
+	if err != nil {
+		return nil, false, fmt.Errorf("error retrieving virtual network: %s", err)
+	}
+	if !ok {
+		return nil, false, nil
+	}
+	for _, subnet := range vnet.Properties.Subnets {
+		if !strings.EqualFold(subnet.Name, id.SubnetName) {
+			continue
+		}
+		addressPrefixes := subnet.Properties.AddressPrefixes
+		if subnet.Properties.AddressPrefix != "" {
+			addressPrefixes = append(
+				addressPrefixes,
+				subnet.Properties.AddressPrefix,
+			)
+		}
+		return &Subnet{
+			Name:            subnet.Name,
+			AddressPrefixes: addressPrefixes,
+			Location:        vnet.Location,
+		}, true, nil
+	}
+	return nil, false, nil
+}
+
+func (m *manager) DeleteBastionHost(
+	bastionHostName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		m.getResourceReference(
+			bastionHostType,
+			bastionHostName,
+			resourceGroupName,
+		),
+	); err != nil {
+		return fmt.Errorf("error deleting Bastion host: %s", err)
+	}
+	return nil
+}
+
+func (m *manager) DeletePublicIPAddress(
+	publicIPAddressName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		m.getResourceReference(
+			publicIPAddressType,
+			publicIPAddressName,
+			resourceGroupName,
+		),
+	); err != nil {
+		return fmt.Errorf("error deleting public IP address: %s", err)
+	}
+	return nil
+}
+
+func (m *manager) getResourceReference(
+	resourceType string,
+	resourceName string,
+	resourceGroupName string,
+) az.ResourceReference {
+	return az.ResourceReference{
+		SubscriptionID:    m.subscriptionID,
+		ResourceGroupName: resourceGroupName,
+		ProviderNamespace: networkProviderNamespace,
+		ResourceType:      resourceType,
+		ResourceName:      resourceName,
+		APIVersion:        apiVersion,
+	}
+}
@@ -0,0 +1,173 @@
+package chaosstudio
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/version"
+)
+
+const (
+	providerNamespace = "Microsoft.Chaos"
+	resourceType      = "experiments"
+	apiVersion        = "2023-11-01"
+)
+
+// ExperimentURLs are the Azure Resource Manager URLs through which a chaos
+// experiment is operated
+type ExperimentURLs struct {
+	// Start is POSTed to in order to run the experiment
+	Start string
+	// Cancel is POSTed to in order to stop a running experiment
+	Cancel string
+	// Executions lists the experiment's runs, along with their statuses
+	Executions string
+}
+
+// Manager is an interface to be implemented by any component capable of
+// managing Azure Chaos Studio experiments
+type Manager interface {
+	// IsTargetEnabled returns a bool indicating whether the given resource
+	// exists and has been enabled as a Chaos Studio target of the given type
+	// (e.g. Microsoft-VirtualMachine)
+	IsTargetEnabled(resourceID string, targetType string) (bool, error)
+	GetExperimentURLs(experimentID string) ExperimentURLs
+	DeleteExperiment(experimentName string, resourceGroupName string) error
+}
+
+type manager struct {
+	azureEnvironment azure.Environment
+	subscriptionID   string
+	tenantID         string
+	clientID         string
+	clientSecret     string
+	resourceClient   az.ResourceClient
+}
+
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	return &manager{
+		azureEnvironment: azureEnvironment,
+		subscriptionID:   azureConfig.SubscriptionID,
+		tenantID:         azureConfig.TenantID,
+		clientID:         azureConfig.ClientID,
+		clientSecret:     azureConfig.ClientSecret,
+		resourceClient: az.NewResourceClient(
+			azureEnvironment,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+	}, nil
+}
+
+// IsTargetEnabled retrieves the Chaos Studio target beneath the given
+// resource. The generic resource client can't be used here because targets
+// are extension resources, whose IDs are nested beneath another resource's.
+// A target that is not found means either that the resource doesn't exist or
+// that it hasn't been enabled for Chaos Studio.
+func (m *manager) IsTargetEnabled(
+	resourceID string,
+	targetType string,
+) (bool, error) {
+	authorizer, err := az.GetBearerTokenAuthorizer(
+		m.azureEnvironment,
+		m.tenantID,
+		m.clientID,
+		m.clientSecret,
+	)
+	if err != nil {
+		return false, fmt.Errorf("error getting bearer token authorizer: %s", err)
+	}
+	client := autorest.NewClientWithUserAgent(
+		fmt.Sprintf("open-service-broker/%s", version.GetVersion()),
+	)
+	client.Authorizer = authorizer
+	req, err := autorest.Prepare(
+		&http.Request{},
+		autorest.AsGet(),
+		autorest.WithBaseURL(m.azureEnvironment.ResourceManagerEndpoint),
+		autorest.WithPath(
+			fmt.Sprintf(
+				"%s/providers/%s/targets/%s",
+				strings.TrimSuffix(resourceID, "/"),
+				providerNamespace,
+				targetType,
+			),
+		),
+		autorest.WithQueryParameters(map[string]interface{}{
+			"api-version": apiVersion,
+		}),
+	)
+	if err != nil {
+		return false, fmt.Errorf("error preparing request: %s", err)
+	}
+	resp, err := autorest.SendWithSender(client, req)
+	if err != nil {
+		return false, fmt.Errorf("error sending request: %s", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return false, autorest.Respond(resp, autorest.ByClosing())
+	}
+	if err := autorest.Respond(
+		resp,
+		client.ByInspecting(),
+		azure.WithErrorUnlessStatusCode(http.StatusOK),
+		autorest.ByClosing(),
+	); err != nil {
+		return false, fmt.Errorf(
+			`error retrieving Chaos Studio target of resource "%s": %s`,
+			resourceID,
+			az.CategorizeError(err),
+		)
+	}
+	return true, nil
+}
+
+func (m *manager) GetExperimentURLs(experimentID string) ExperimentURLs {
+	experimentURL := strings.TrimSuffix(
+		m.azureEnvironment.ResourceManagerEndpoint,
+		"/",
+	) + experimentID
+	query := "?api-version=" + apiVersion
+	return ExperimentURLs{
+		Start:      experimentURL + "/start" + query,
+		Cancel:     experimentURL + "/cancel" + query,
+		Executions: experimentURL + "/executions" + query,
+	}
+}
+
+func (m *manager) DeleteExperiment(
+	experimentName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		az.ResourceReference{
+			SubscriptionID:    m.subscriptionID,
+			ResourceGroupName: resourceGroupName,
+			ProviderNamespace: providerNamespace,
+			ResourceType:      resourceType,
+			ResourceName:      experimentName,
+			APIVersion:        apiVersion,
+		},
+	); err != nil {
+		return fmt.Errorf("error deleting chaos experiment: %s", err)
+	}
+	return nil
+}
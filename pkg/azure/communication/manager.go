@@ -0,0 +1,114 @@
+package communication
+
+import (
+	"fmt"
+
+	"github.com/Azure/go-autorest/autorest/azure"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+)
+
+const (
+	providerNamespace = "Microsoft.Communication"
+	resourceType      = "communicationServices"
+	apiVersion        = "2020-08-20"
+)
+
+// Keys encapsulates the access keys and connection strings of an Azure
+// Communication Services resource
+type Keys struct {
+	PrimaryKey                string `json:"primaryKey"`
+	SecondaryKey              string `json:"secondaryKey"`
+	PrimaryConnectionString   string `json:"primaryConnectionString"`
+	SecondaryConnectionString string `json:"secondaryConnectionString"`
+}
+
+// Manager is an interface to be implemented by any component capable of
+// managing an Azure Communication Services resource
+type Manager interface {
+	GetKeys(
+		serviceName string,
+		resourceGroupName string,
+	) (*Keys, error)
+	DeleteCommunicationService(
+		serviceName string,
+		resourceGroupName string,
+	) error
+}
+
+type manager struct {
+	subscriptionID string
+	resourceClient az.ResourceClient
+}
+
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	return &manager{
+		subscriptionID: azureConfig.SubscriptionID,
+		resourceClient: az.NewResourceClient(
+			azureEnvironment,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+	}, nil
+}
+
+func (m *manager) GetKeys(
+	serviceName string,
+	resourceGroupName string,
+) (*Keys, error) {
+	keys := &Keys{}
+	if err := m.resourceClient.InvokeAction(
+		m.getResourceReference(serviceName, resourceGroupName),
+		"listKeys",
+		nil,
+		keys,
+	); err != nil {
+		return nil, fmt.Errorf(
+			"error listing Azure Communication Services keys: %s",
+			err,
+		)
+	}
+	return keys, nil
+}
+
+func (m *manager) DeleteCommunicationService(
+	serviceName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		m.getResourceReference(serviceName, resourceGroupName),
+	); err != nil {
+		return fmt.Errorf(
+			"error deleting Azure Communication Services resource: %s",
+			err,
+		)
+	}
+	return nil
+}
+
+func (m *manager) getResourceReference(
+	serviceName string,
+	resourceGroupName string,
+) az.ResourceReference {
+	return az.ResourceReference{
+		SubscriptionID:    m.subscriptionID,
+		ResourceGroupName: resourceGroupName,
+		ProviderNamespace: providerNamespace,
+		ResourceType:      resourceType,
+		ResourceName:      serviceName,
+		APIVersion:        apiVersion,
+	}
+}
@@ -0,0 +1,214 @@
+package confidentialledger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Azure/go-autorest/autorest/azure"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+const (
+	providerNamespace = "Microsoft.ConfidentialLedger"
+	resourceType      = "ledgers"
+	apiVersion        = "2022-05-13"
+)
+
+// SecurityPrincipal is a Microsoft Entra ID principal and the role it is
+// granted in a ledger
+type SecurityPrincipal struct {
+	PrincipalID    string `json:"principalId"`
+	TenantID       string `json:"tenantId,omitempty"`
+	LedgerRoleName string `json:"ledgerRoleName"`
+}
+
+type ledgerProperties struct {
+	AADBasedSecurityPrincipals []SecurityPrincipal `json:"aadBasedSecurityPrincipals"` // nolint: lll
+}
+
+type ledger struct {
+	Properties ledgerProperties `json:"properties"`
+}
+
+// Manager is an interface to be implemented by any component capable of
+// managing Azure Confidential Ledgers and the principals granted roles in them
+type Manager interface {
+	GetTenantID() string
+	// AddSecurityPrincipal grants the given principal its role in the named
+	// ledger. It fails if the principal already has a role in the ledger, since
+	// a principal may have only one.
+	AddSecurityPrincipal(
+		ledgerName string,
+		resourceGroupName string,
+		principal SecurityPrincipal,
+	) error
+	// RemoveSecurityPrincipal revokes the given principal's role in the named
+	// ledger. Removing a principal that has no role is not an error.
+	RemoveSecurityPrincipal(
+		ledgerName string,
+		resourceGroupName string,
+		principalID string,
+	) error
+	DeleteLedger(ledgerName string, resourceGroupName string) error
+}
+
+type manager struct {
+	subscriptionID string
+	tenantID       string
+	resourceClient az.ResourceClient
+	// principalsMutex serializes changes to ledgers' principals, each of which
+	// rewrites the ledger's whole list of them
+	principalsMutex sync.Mutex
+}
+
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	return &manager{
+		subscriptionID: azureConfig.SubscriptionID,
+		tenantID:       azureConfig.TenantID,
+		resourceClient: az.NewResourceClient(
+			azureEnvironment,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+	}, nil
+}
+
+func (m *manager) GetTenantID() string {
+	return m.tenantID
+}
+
+func (m *manager) AddSecurityPrincipal(
+	ledgerName string,
+	resourceGroupName string,
+	principal SecurityPrincipal,
+) error {
+	m.principalsMutex.Lock()
+	defer m.principalsMutex.Unlock()
+	principals, err := m.getSecurityPrincipals(ledgerName, resourceGroupName)
+	if err != nil {
+		return err
+	}
+	for _, p := range principals {
+		if strings.EqualFold(p.PrincipalID, principal.PrincipalID) {
+			return fmt.Errorf(
+				`principal "%s" already has the %s role in the ledger`,
+				principal.PrincipalID,
+				p.LedgerRoleName,
+			)
+		}
+	}
+	return m.updateSecurityPrincipals(
+		ledgerName,
+		resourceGroupName,
+		append(principals, principal),
+	)
+}
+
+func (m *manager) RemoveSecurityPrincipal(
+	ledgerName string,
+	resourceGroupName string,
+	principalID string,
+) error {
+	m.principalsMutex.Lock()
+	defer m.principalsMutex.Unlock()
+	principals, err := m.getSecurityPrincipals(ledgerName, resourceGroupName)
+	if err != nil {
+		return err
+	}
+	remaining := []SecurityPrincipal{}
+	for _, p := range principals {
+		if !strings.EqualFold(p.PrincipalID, principalID) {
+			remaining = append(remaining, p)
+		}
+	}
+	if len(remaining) == len(principals) {
+		return nil
+	}
+	return m.updateSecurityPrincipals(
+		ledgerName,
+		resourceGroupName,
+		remaining,
+	)
+}
+
+func (m *manager) DeleteLedger(
+	ledgerName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		m.getReference(ledgerName, resourceGroupName),
+	); err != nil {
+		return service.WrapError(err, "error deleting confidential ledger")
+	}
+	return nil
+}
+
+func (m *manager) getSecurityPrincipals(
+	ledgerName string,
+	resourceGroupName string,
+) ([]SecurityPrincipal, error) {
+	l := &ledger{}
+	found, err := m.resourceClient.GetResource(
+		m.getReference(ledgerName, resourceGroupName),
+		l,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error retrieving confidential ledger")
+	}
+	if !found {
+		return nil, fmt.Errorf(`confidential ledger "%s" not found`, ledgerName)
+	}
+	return l.Properties.AADBasedSecurityPrincipals, nil
+}
+
+func (m *manager) updateSecurityPrincipals(
+	ledgerName string,
+	resourceGroupName string,
+	principals []SecurityPrincipal,
+) error {
+	if err := m.resourceClient.PatchResource(
+		m.getReference(ledgerName, resourceGroupName),
+		&ledger{
+			Properties: ledgerProperties{
+				AADBasedSecurityPrincipals: principals,
+			},
+		},
+		nil,
+	); err != nil {
+		return service.WrapError(
+			err,
+			"error updating confidential ledger's security principals",
+		)
+	}
+	return nil
+}
+
+func (m *manager) getReference(
+	ledgerName string,
+	resourceGroupName string,
+) az.ResourceReference {
+	return az.ResourceReference{
+		SubscriptionID:    m.subscriptionID,
+		ResourceGroupName: resourceGroupName,
+		ProviderNamespace: providerNamespace,
+		ResourceType:      resourceType,
+		ResourceName:      ledgerName,
+		APIVersion:        apiVersion,
+	}
+}
@@ -22,3 +22,15 @@ func GetConfig() (Config, error) {
 	err := envconfig.Process("", &ac)
 	return ac, err
 }
+
+// GetConfigForSubscription returns the same configuration details as
+// GetConfig, except for connecting to the specified subscription instead of
+// the one the broker is configured with. An empty subscription ID stands for
+// the subscription the broker is configured with.
+func GetConfigForSubscription(subscriptionID string) (Config, error) {
+	ac, err := GetConfig()
+	if err == nil && subscriptionID != "" {
+		ac.SubscriptionID = subscriptionID
+	}
+	return ac, err
+}
@@ -0,0 +1,68 @@
+package azure
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+)
+
+// consistencyRetryWindow and consistencyRetryInterval govern how long, and
+// how often, ReadWithConsistencyRetry retries a read of a resource that hasn't
+// been found. These are variables only so that tests may shorten them.
+var (
+	consistencyRetryWindow   = time.Minute
+	consistencyRetryInterval = 5 * time.Second
+)
+
+// ReadWithConsistencyRetry carries out a read of a resource that was only just
+// created. Because Azure is eventually consistent, such a read can briefly
+// report that the resource doesn't exist. The provided read function returns a
+// bool indicating whether the resource was found. While it wasn't, the read is
+// retried periodically for a short window, or until the given context is
+// canceled. Errors are never retried; they are returned as is. The returned
+// bool indicates whether the resource was eventually found.
+func ReadWithConsistencyRetry(
+	ctx context.Context,
+	read func() (bool, error),
+) (bool, error) {
+	timer := time.NewTimer(consistencyRetryWindow)
+	defer timer.Stop()
+	ticker := time.NewTicker(consistencyRetryInterval)
+	defer ticker.Stop()
+	for {
+		found, err := read()
+		if err != nil || found {
+			return found, err
+		}
+		select {
+		case <-ticker.C:
+		case <-timer.C:
+			return false, nil
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	}
+}
+
+// IsNotFoundError returns a bool indicating whether the given error, as
+// returned by an Azure client, resulted from an HTTP 404 response
+func IsNotFoundError(err error) bool {
+	var statusCode interface{}
+	switch e := err.(type) {
+	case autorest.DetailedError:
+		statusCode = e.StatusCode
+	case *autorest.DetailedError:
+		statusCode = e.StatusCode
+	case azure.RequestError:
+		statusCode = e.StatusCode
+	case *azure.RequestError:
+		statusCode = e.StatusCode
+	default:
+		return false
+	}
+	code, ok := statusCode.(int)
+	return ok && code == http.StatusNotFound
+}
@@ -0,0 +1,79 @@
+package azure
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	consistencyRetryWindow = 100 * time.Millisecond
+	consistencyRetryInterval = time.Millisecond
+}
+
+func TestReadWithConsistencyRetryEventuallyFinds(t *testing.T) {
+	reads := 0
+	found, err := ReadWithConsistencyRetry(
+		context.Background(),
+		func() (bool, error) {
+			reads++
+			return reads == 3, nil
+		},
+	)
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 3, reads)
+}
+
+func TestReadWithConsistencyRetryGivesUp(t *testing.T) {
+	found, err := ReadWithConsistencyRetry(
+		context.Background(),
+		func() (bool, error) {
+			return false, nil
+		},
+	)
+	assert.Nil(t, err)
+	assert.False(t, found)
+}
+
+func TestReadWithConsistencyRetryDoesNotRetryErrors(t *testing.T) {
+	reads := 0
+	_, err := ReadWithConsistencyRetry(
+		context.Background(),
+		func() (bool, error) {
+			reads++
+			return false, errors.New("error")
+		},
+	)
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, reads)
+}
+
+func TestReadWithConsistencyRetryRespectsContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := ReadWithConsistencyRetry(
+		ctx,
+		func() (bool, error) {
+			return false, nil
+		},
+	)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestIsNotFoundError(t *testing.T) {
+	assert.True(
+		t,
+		IsNotFoundError(autorest.DetailedError{StatusCode: http.StatusNotFound}),
+	)
+	assert.False(
+		t,
+		IsNotFoundError(autorest.DetailedError{StatusCode: http.StatusConflict}),
+	)
+	assert.False(t, IsNotFoundError(errors.New("not found")))
+}
@@ -0,0 +1,299 @@
+package containerapps
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/version"
+)
+
+const (
+	providerNamespace       = "Microsoft.App"
+	environmentResourceType = "managedEnvironments"
+	appResourceType         = "containerApps"
+	apiVersion              = "2023-05-01"
+
+	revisionPollingInterval = 10 * time.Second
+)
+
+// Environment is a Container Apps environment
+type Environment struct {
+	ID   string            `json:"id"`
+	Tags map[string]string `json:"tags"`
+}
+
+// Manager is an interface to be implemented by any component capable of
+// managing Azure Container Apps and the environments they run in
+type Manager interface {
+	// GetEnvironment retrieves the named environment. It returns a bool
+	// indicating whether the environment was found.
+	GetEnvironment(
+		environmentName string,
+		resourceGroupName string,
+	) (Environment, bool, error)
+	// GetEnvironmentAppIDs returns the resource IDs of the container apps,
+	// anywhere in the subscription, that run in the environment with the given
+	// ID
+	GetEnvironmentAppIDs(environmentID string) ([]string, error)
+	// WaitForReadyRevision blocks until the named app's latest revision is
+	// ready to serve requests, or fails if the revision can't become ready
+	WaitForReadyRevision(
+		ctx context.Context,
+		appName string,
+		resourceGroupName string,
+	) error
+	DeleteApp(appName string, resourceGroupName string) error
+	// DeleteEnvironment deletes the named environment. Azure refuses to delete
+	// an environment that any app still runs in.
+	DeleteEnvironment(environmentName string, resourceGroupName string) error
+}
+
+type manager struct {
+	azureEnvironment azure.Environment
+	subscriptionID   string
+	tenantID         string
+	clientID         string
+	clientSecret     string
+	resourceClient   az.ResourceClient
+}
+
+type app struct {
+	ID         string `json:"id"`
+	Properties struct {
+		ProvisioningState       string `json:"provisioningState"`
+		ManagedEnvironmentID    string `json:"managedEnvironmentId"`
+		LatestRevisionName      string `json:"latestRevisionName"`
+		LatestReadyRevisionName string `json:"latestReadyRevisionName"`
+	} `json:"properties"`
+}
+
+type appList struct {
+	Value    []app  `json:"value"`
+	NextLink string `json:"nextLink"`
+}
+
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	return &manager{
+		azureEnvironment: azureEnvironment,
+		subscriptionID:   azureConfig.SubscriptionID,
+		tenantID:         azureConfig.TenantID,
+		clientID:         azureConfig.ClientID,
+		clientSecret:     azureConfig.ClientSecret,
+		resourceClient: az.NewResourceClient(
+			azureEnvironment,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+	}, nil
+}
+
+func (m *manager) GetEnvironment(
+	environmentName string,
+	resourceGroupName string,
+) (Environment, bool, error) {
+	environment := Environment{}
+	found, err := m.resourceClient.GetResource(
+		m.getEnvironmentReference(environmentName, resourceGroupName),
+		&environment,
+	)
+	if err != nil {
+		return environment, false, service.WrapError(
+			err,
+			"error retrieving Container Apps environment",
+		)
+	}
+	return environment, found, nil
+}
+
+// GetEnvironmentAppIDs lists every container app in the subscription, since
+// an app can run in an environment belonging to another resource group. The
+// generic resource client can't be used here because it doesn't list
+// resources.
+func (m *manager) GetEnvironmentAppIDs(
+	environmentID string,
+) ([]string, error) {
+	authorizer, err := az.GetBearerTokenAuthorizer(
+		m.azureEnvironment,
+		m.tenantID,
+		m.clientID,
+		m.clientSecret,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error getting bearer token authorizer: %s", err)
+	}
+	client := autorest.NewClientWithUserAgent(
+		fmt.Sprintf("open-service-broker/%s", version.GetVersion()),
+	)
+	client.Authorizer = authorizer
+	req, err := autorest.Prepare(
+		&http.Request{},
+		autorest.AsGet(),
+		autorest.WithBaseURL(m.azureEnvironment.ResourceManagerEndpoint),
+		autorest.WithPath(
+			fmt.Sprintf(
+				"/subscriptions/%s/providers/%s/%s",
+				m.subscriptionID,
+				providerNamespace,
+				appResourceType,
+			),
+		),
+		autorest.WithQueryParameters(map[string]interface{}{
+			"api-version": apiVersion,
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error preparing request: %s", err)
+	}
+	appIDs := []string{}
+	for {
+		resp, err := autorest.SendWithSender(client, req)
+		if err != nil {
+			return nil, fmt.Errorf("error sending request: %s", err)
+		}
+		apps := appList{}
+		if err := autorest.Respond(
+			resp,
+			client.ByInspecting(),
+			azure.WithErrorUnlessStatusCode(http.StatusOK),
+			autorest.ByUnmarshallingJSON(&apps),
+			autorest.ByClosing(),
+		); err != nil {
+			return nil, service.WrapError(
+				az.CategorizeError(err),
+				"error listing container apps",
+			)
+		}
+		for _, a := range apps.Value {
+			if strings.EqualFold(
+				a.Properties.ManagedEnvironmentID,
+				environmentID,
+			) {
+				appIDs = append(appIDs, a.ID)
+			}
+		}
+		if apps.NextLink == "" {
+			return appIDs, nil
+		}
+		if req, err = autorest.Prepare(
+			&http.Request{},
+			autorest.AsGet(),
+			autorest.WithBaseURL(apps.NextLink),
+		); err != nil {
+			return nil, fmt.Errorf("error preparing request: %s", err)
+		}
+	}
+}
+
+func (m *manager) WaitForReadyRevision(
+	ctx context.Context,
+	appName string,
+	resourceGroupName string,
+) error {
+	ticker := time.NewTicker(revisionPollingInterval)
+	defer ticker.Stop()
+	for {
+		a := app{}
+		found, err := az.ReadWithConsistencyRetry(ctx, func() (bool, error) {
+			return m.resourceClient.GetResource(
+				m.getAppReference(appName, resourceGroupName),
+				&a,
+			)
+		})
+		if err != nil {
+			return service.WrapError(err, "error retrieving container app")
+		}
+		if !found {
+			return fmt.Errorf(`container app "%s" not found`, appName)
+		}
+		if a.Properties.ProvisioningState == "Failed" {
+			return fmt.Errorf(
+				`container app "%s" failed to provision revision "%s"`,
+				appName,
+				a.Properties.LatestRevisionName,
+			)
+		}
+		if a.Properties.LatestRevisionName != "" &&
+			a.Properties.LatestReadyRevisionName ==
+				a.Properties.LatestRevisionName {
+			return nil
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (m *manager) DeleteApp(appName string, resourceGroupName string) error {
+	if err := m.resourceClient.DeleteResource(
+		m.getAppReference(appName, resourceGroupName),
+	); err != nil {
+		return service.WrapError(err, "error deleting container app")
+	}
+	return nil
+}
+
+func (m *manager) DeleteEnvironment(
+	environmentName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		m.getEnvironmentReference(environmentName, resourceGroupName),
+	); err != nil {
+		return service.WrapError(
+			err,
+			"error deleting Container Apps environment",
+		)
+	}
+	return nil
+}
+
+func (m *manager) getEnvironmentReference(
+	environmentName string,
+	resourceGroupName string,
+) az.ResourceReference {
+	return az.ResourceReference{
+		SubscriptionID:    m.subscriptionID,
+		ResourceGroupName: resourceGroupName,
+		ProviderNamespace: providerNamespace,
+		ResourceType:      environmentResourceType,
+		ResourceName:      environmentName,
+		APIVersion:        apiVersion,
+	}
+}
+
+func (m *manager) getAppReference(
+	appName string,
+	resourceGroupName string,
+) az.ResourceReference {
+	return az.ResourceReference{
+		SubscriptionID:    m.subscriptionID,
+		ResourceGroupName: resourceGroupName,
+		ProviderNamespace: providerNamespace,
+		ResourceType:      appResourceType,
+		ResourceName:      appName,
+		APIVersion:        apiVersion,
+	}
+}
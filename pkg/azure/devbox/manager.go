@@ -0,0 +1,308 @@
+package devbox
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/adal"
+	"github.com/Azure/go-autorest/autorest/azure"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/version"
+)
+
+const (
+	providerNamespace = "Microsoft.DevCenter"
+	apiVersion        = "2024-02-01"
+
+	// dataPlaneResource is the resource that tokens used to authorize requests
+	// to a dev center's own endpoint (as opposed to Azure Resource Manager)
+	// must be issued for
+	dataPlaneResource   = "https://devcenter.azure.com"
+	dataPlaneAPIVersion = "2023-04-01"
+)
+
+// Manager is an interface to be implemented by any component capable of
+// managing Microsoft Dev Box dev centers and the dev boxes created in them
+type Manager interface {
+	// CreateDevBox requests that a dev box be created from the named pool of
+	// the named project on behalf of the user having the given object ID.
+	// Creating a dev box takes a long while; CreateDevBox returns once Azure
+	// has accepted the request.
+	CreateDevBox(
+		devCenterURI string,
+		projectName string,
+		userID string,
+		devBoxName string,
+		poolName string,
+	) error
+	// DeleteDevBox requests that the named dev box, belonging to the user
+	// having the given object ID, be deleted. Deleting a dev box that does not
+	// exist is not an error.
+	DeleteDevBox(
+		devCenterURI string,
+		projectName string,
+		userID string,
+		devBoxName string,
+	) error
+	// GetDevBoxURL returns the URL by which the named dev box is addressed
+	// on its dev center's endpoint
+	GetDevBoxURL(
+		devCenterURI string,
+		projectName string,
+		userID string,
+		devBoxName string,
+	) string
+	DeletePool(
+		poolName string,
+		projectName string,
+		resourceGroupName string,
+	) error
+	DeleteProject(projectName string, resourceGroupName string) error
+	DeleteDevCenter(devCenterName string, resourceGroupName string) error
+}
+
+type manager struct {
+	azureEnvironment azure.Environment
+	subscriptionID   string
+	tenantID         string
+	clientID         string
+	clientSecret     string
+	resourceClient   az.ResourceClient
+}
+
+type devBoxRequest struct {
+	PoolName string `json:"poolName"`
+}
+
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	return &manager{
+		azureEnvironment: azureEnvironment,
+		subscriptionID:   azureConfig.SubscriptionID,
+		tenantID:         azureConfig.TenantID,
+		clientID:         azureConfig.ClientID,
+		clientSecret:     azureConfig.ClientSecret,
+		resourceClient: az.NewResourceClient(
+			azureEnvironment,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+	}, nil
+}
+
+func (m *manager) CreateDevBox(
+	devCenterURI string,
+	projectName string,
+	userID string,
+	devBoxName string,
+	poolName string,
+) error {
+	if err := m.sendDataPlaneRequest(
+		devCenterURI,
+		autorest.AsPut(),
+		getDevBoxPath(projectName, userID, devBoxName),
+		&devBoxRequest{
+			PoolName: poolName,
+		},
+		http.StatusOK,
+		http.StatusCreated,
+	); err != nil {
+		return service.WrapError(err, "error creating dev box")
+	}
+	return nil
+}
+
+func (m *manager) DeleteDevBox(
+	devCenterURI string,
+	projectName string,
+	userID string,
+	devBoxName string,
+) error {
+	if err := m.sendDataPlaneRequest(
+		devCenterURI,
+		autorest.AsDelete(),
+		getDevBoxPath(projectName, userID, devBoxName),
+		nil,
+		http.StatusOK,
+		http.StatusAccepted,
+		http.StatusNoContent,
+		http.StatusNotFound,
+	); err != nil {
+		return service.WrapError(err, "error deleting dev box")
+	}
+	return nil
+}
+
+func (m *manager) GetDevBoxURL(
+	devCenterURI string,
+	projectName string,
+	userID string,
+	devBoxName string,
+) string {
+	return strings.TrimSuffix(devCenterURI, "/") +
+		getDevBoxPath(projectName, userID, devBoxName)
+}
+
+func (m *manager) DeletePool(
+	poolName string,
+	projectName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		az.ResourceReference{
+			SubscriptionID:    m.subscriptionID,
+			ResourceGroupName: resourceGroupName,
+			ProviderNamespace: providerNamespace,
+			ResourceType:      fmt.Sprintf("projects/%s/pools", projectName),
+			ResourceName:      poolName,
+			APIVersion:        apiVersion,
+		},
+	); err != nil {
+		return service.WrapError(err, "error deleting dev box pool")
+	}
+	return nil
+}
+
+func (m *manager) DeleteProject(
+	projectName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		az.ResourceReference{
+			SubscriptionID:    m.subscriptionID,
+			ResourceGroupName: resourceGroupName,
+			ProviderNamespace: providerNamespace,
+			ResourceType:      "projects",
+			ResourceName:      projectName,
+			APIVersion:        apiVersion,
+		},
+	); err != nil {
+		return service.WrapError(err, "error deleting project")
+	}
+	return nil
+}
+
+func (m *manager) DeleteDevCenter(
+	devCenterName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		az.ResourceReference{
+			SubscriptionID:    m.subscriptionID,
+			ResourceGroupName: resourceGroupName,
+			ProviderNamespace: providerNamespace,
+			ResourceType:      "devcenters",
+			ResourceName:      devCenterName,
+			APIVersion:        apiVersion,
+		},
+	); err != nil {
+		return service.WrapError(err, "error deleting dev center")
+	}
+	return nil
+}
+
+// sendDataPlaneRequest sends a request to the given path relative to the dev
+// center at the given URI
+func (m *manager) sendDataPlaneRequest(
+	devCenterURI string,
+	method autorest.PrepareDecorator,
+	path string,
+	body interface{},
+	expectedStatusCodes ...int,
+) error {
+	client, err := m.getDataPlaneClient()
+	if err != nil {
+		return err
+	}
+	decorators := []autorest.PrepareDecorator{
+		method,
+		autorest.WithBaseURL(strings.TrimSuffix(devCenterURI, "/")),
+		autorest.WithPath(path),
+		autorest.WithQueryParameters(map[string]interface{}{
+			"api-version": dataPlaneAPIVersion,
+		}),
+	}
+	if body != nil {
+		decorators = append(
+			decorators,
+			autorest.AsJSON(),
+			autorest.WithJSON(body),
+		)
+	}
+	req, err := autorest.Prepare(&http.Request{}, decorators...)
+	if err != nil {
+		return fmt.Errorf("error preparing request: %s", err)
+	}
+	resp, err := autorest.SendWithSender(client, req)
+	if err != nil {
+		return fmt.Errorf("error sending request: %s", err)
+	}
+	if err := autorest.Respond(
+		resp,
+		client.ByInspecting(),
+		azure.WithErrorUnlessStatusCode(expectedStatusCodes...),
+		autorest.ByClosing(),
+	); err != nil {
+		return az.CategorizeError(err)
+	}
+	return nil
+}
+
+func (m *manager) getDataPlaneClient() (autorest.Client, error) {
+	oauthConfig, err := adal.NewOAuthConfig(
+		m.azureEnvironment.ActiveDirectoryEndpoint,
+		m.tenantID,
+	)
+	if err != nil {
+		return autorest.Client{}, fmt.Errorf(
+			"error building oauth config: %s",
+			err,
+		)
+	}
+	spt, err := adal.NewServicePrincipalToken(
+		*oauthConfig,
+		m.clientID,
+		m.clientSecret,
+		dataPlaneResource,
+	)
+	if err != nil {
+		return autorest.Client{}, fmt.Errorf(
+			"error getting service principal token: %s",
+			err,
+		)
+	}
+	client := autorest.NewClientWithUserAgent(
+		fmt.Sprintf("open-service-broker/%s", version.GetVersion()),
+	)
+	client.Authorizer = autorest.NewBearerAuthorizer(spt)
+	return client, nil
+}
+
+func getDevBoxPath(
+	projectName string,
+	userID string,
+	devBoxName string,
+) string {
+	return fmt.Sprintf(
+		"/projects/%s/users/%s/devboxes/%s",
+		projectName,
+		userID,
+		devBoxName,
+	)
+}
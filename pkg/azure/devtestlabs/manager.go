@@ -0,0 +1,229 @@
+package devtestlabs
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/Azure/azure-sdk-for-go/arm/compute"
+	"github.com/Azure/azure-sdk-for-go/arm/devtestlabs"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+var formulaIDRegex = regexp.MustCompile(
+	`(?i)^/subscriptions/[^/]+/resourceGroups/([^/]+)/providers/` +
+		`Microsoft\.DevTestLab/labs/([^/]+)/formulas/([^/]+)$`,
+)
+
+// Formula encapsulates those parts of a DevTest Labs formula that describe
+// the virtual machine it creates
+type Formula struct {
+	Size           string
+	OSType         string
+	ImagePublisher string
+	ImageOffer     string
+	ImageSKU       string
+	ImageVersion   string
+}
+
+// Manager is an interface to be implemented by any component capable of
+// managing Azure DevTest Labs
+type Manager interface {
+	GetFormula(formulaID string) (*Formula, error)
+	IsVMSizeAvailable(location string, vmSize string) (bool, error)
+	DeleteVirtualMachine(
+		labName string,
+		vmName string,
+		resourceGroupName string,
+	) error
+	DeleteLab(
+		labName string,
+		resourceGroupName string,
+	) error
+}
+
+type manager struct {
+	azureEnvironment azure.Environment
+	subscriptionID   string
+	tenantID         string
+	clientID         string
+	clientSecret     string
+}
+
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	return &manager{
+		azureEnvironment: azureEnvironment,
+		subscriptionID:   azureConfig.SubscriptionID,
+		tenantID:         azureConfig.TenantID,
+		clientID:         azureConfig.ClientID,
+		clientSecret:     azureConfig.ClientSecret,
+	}, nil
+}
+
+// IsValidFormulaID returns a bool indicating whether the given string is a
+// well-formed DevTest Labs formula resource ID
+func IsValidFormulaID(formulaID string) bool {
+	return formulaIDRegex.MatchString(formulaID)
+}
+
+func (m *manager) GetFormula(formulaID string) (*Formula, error) {
+	matches := formulaIDRegex.FindStringSubmatch(formulaID)
+	if matches == nil {
+		return nil, fmt.Errorf(`invalid formula id "%s"`, formulaID)
+	}
+	resourceGroupName, labName, formulaName := matches[1], matches[2], matches[3]
+	authorizer, err := m.getAuthorizer()
+	if err != nil {
+		return nil, err
+	}
+	formulasClient := devtestlabs.NewFormulasClientWithBaseURI(
+		m.azureEnvironment.ResourceManagerEndpoint,
+		m.subscriptionID,
+	)
+	formulasClient.Authorizer = authorizer
+	result, err := formulasClient.Get(
+		resourceGroupName,
+		labName,
+		formulaName,
+		"",
+	)
+	if err != nil {
+		return nil, service.WrapError(
+			az.CategorizeError(err),
+			fmt.Sprintf(`error retrieving formula "%s"`, formulaID),
+		)
+	}
+	if result.FormulaProperties == nil ||
+		result.FormulaContent == nil ||
+		result.FormulaContent.LabVirtualMachineCreationParameterProperties == nil {
+		return nil, fmt.Errorf(`formula "%s" has no content`, formulaID)
+	}
+	content := result.FormulaContent.LabVirtualMachineCreationParameterProperties
+	image := content.GalleryImageReference
+	if image == nil {
+		return nil, fmt.Errorf(
+			`formula "%s" does not reference a gallery image`,
+			formulaID,
+		)
+	}
+	return &Formula{
+		Size:           stringValue(content.Size),
+		OSType:         stringValue(image.OsType),
+		ImagePublisher: stringValue(image.Publisher),
+		ImageOffer:     stringValue(image.Offer),
+		ImageSKU:       stringValue(image.Sku),
+		ImageVersion:   stringValue(image.Version),
+	}, nil
+}
+
+func (m *manager) IsVMSizeAvailable(
+	location string,
+	vmSize string,
+) (bool, error) {
+	authorizer, err := m.getAuthorizer()
+	if err != nil {
+		return false, err
+	}
+	sizesClient := compute.NewVirtualMachineSizesClientWithBaseURI(
+		m.azureEnvironment.ResourceManagerEndpoint,
+		m.subscriptionID,
+	)
+	sizesClient.Authorizer = authorizer
+	result, err := sizesClient.List(location)
+	if err != nil {
+		return false, service.WrapError(
+			az.CategorizeError(err),
+			fmt.Sprintf(`error listing vm sizes in location "%s"`, location),
+		)
+	}
+	if result.Value == nil {
+		return false, nil
+	}
+	for _, size := range *result.Value {
+		if stringValue(size.Name) == vmSize {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *manager) DeleteVirtualMachine(
+	labName string,
+	vmName string,
+	resourceGroupName string,
+) error {
+	authorizer, err := m.getAuthorizer()
+	if err != nil {
+		return err
+	}
+	vmsClient := devtestlabs.NewVirtualMachinesClientWithBaseURI(
+		m.azureEnvironment.ResourceManagerEndpoint,
+		m.subscriptionID,
+	)
+	vmsClient.Authorizer = authorizer
+	cancelCh := make(chan struct{})
+	_, errChan := vmsClient.Delete(resourceGroupName, labName, vmName, cancelCh)
+	if err := <-errChan; err != nil {
+		return service.WrapError(
+			az.CategorizeError(err),
+			"error deleting lab virtual machine",
+		)
+	}
+	return nil
+}
+
+func (m *manager) DeleteLab(
+	labName string,
+	resourceGroupName string,
+) error {
+	authorizer, err := m.getAuthorizer()
+	if err != nil {
+		return err
+	}
+	labsClient := devtestlabs.NewLabsClientWithBaseURI(
+		m.azureEnvironment.ResourceManagerEndpoint,
+		m.subscriptionID,
+	)
+	labsClient.Authorizer = authorizer
+	cancelCh := make(chan struct{})
+	_, errChan := labsClient.Delete(resourceGroupName, labName, cancelCh)
+	if err := <-errChan; err != nil {
+		return service.WrapError(az.CategorizeError(err), "error deleting lab")
+	}
+	return nil
+}
+
+func (m *manager) getAuthorizer() (autorest.Authorizer, error) {
+	authorizer, err := az.GetBearerTokenAuthorizer(
+		m.azureEnvironment,
+		m.tenantID,
+		m.clientID,
+		m.clientSecret,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error getting bearer token authorizer: %s", err)
+	}
+	return authorizer, nil
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,90 @@
+package digitaltwins
+
+import (
+	"fmt"
+
+	"github.com/Azure/go-autorest/autorest/azure"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+)
+
+const (
+	providerNamespace = "Microsoft.DigitalTwins"
+	resourceType      = "digitalTwinsInstances"
+	apiVersion        = "2023-01-31"
+)
+
+// Manager is an interface to be implemented by any component capable of
+// managing an Azure Digital Twins instance and access to it
+type Manager interface {
+	// CreateRoleAssignment assigns the role identified by the given (unqualified)
+	// role definition ID to the given principal at the scope of the given
+	// Digital Twins instance
+	CreateRoleAssignment(
+		digitalTwinsID string,
+		roleAssignmentName string,
+		roleDefinitionID string,
+		principalID string,
+	) error
+	// DeleteRoleAssignment deletes the named role assignment from the given
+	// Digital Twins instance. Deleting a role assignment that does not exist is
+	// not an error.
+	DeleteRoleAssignment(digitalTwinsID string, roleAssignmentName string) error
+	DeleteDigitalTwins(digitalTwinsName string, resourceGroupName string) error
+}
+
+type manager struct {
+	subscriptionID string
+	resourceClient az.ResourceClient
+	az.RoleAssignmentClient
+}
+
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	return &manager{
+		subscriptionID: azureConfig.SubscriptionID,
+		resourceClient: az.NewResourceClient(
+			azureEnvironment,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+		RoleAssignmentClient: az.NewRoleAssignmentClient(
+			azureEnvironment,
+			azureConfig.SubscriptionID,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+	}, nil
+}
+
+func (m *manager) DeleteDigitalTwins(
+	digitalTwinsName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		az.ResourceReference{
+			SubscriptionID:    m.subscriptionID,
+			ResourceGroupName: resourceGroupName,
+			ProviderNamespace: providerNamespace,
+			ResourceType:      resourceType,
+			ResourceName:      digitalTwinsName,
+			APIVersion:        apiVersion,
+		},
+	); err != nil {
+		return fmt.Errorf("error deleting Digital Twins instance: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package dms
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest/azure"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+)
+
+const (
+	providerNamespace = "Microsoft.DataMigration"
+	resourceType      = "services"
+	apiVersion        = "2018-04-19"
+
+	networkProviderNamespace = "Microsoft.Network"
+	virtualNetworkType       = "virtualNetworks"
+	networkAPIVersion        = "2018-08-01"
+)
+
+// ServiceStatus encapsulates the status of an Azure Database Migration
+// Service instance as reported by its checkStatus action
+type ServiceStatus struct {
+	Status       string `json:"status"`
+	VMSize       string `json:"vmSize"`
+	AgentVersion string `json:"agentVersion"`
+}
+
+// Manager is an interface to be implemented by any component capable of
+// managing Azure Database Migration Service instances
+type Manager interface {
+	GetServiceStatus(
+		serviceName string,
+		resourceGroupName string,
+	) (*ServiceStatus, error)
+	// GetManagementEndpoint returns the Azure Resource Manager URL at which the
+	// identified resource can be managed
+	GetManagementEndpoint(resourceID string) string
+	DeleteService(
+		serviceName string,
+		resourceGroupName string,
+	) error
+	DeleteVirtualNetwork(
+		virtualNetworkName string,
+		resourceGroupName string,
+	) error
+}
+
+type manager struct {
+	azureEnvironment azure.Environment
+	subscriptionID   string
+	resourceClient   az.ResourceClient
+}
+
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	return &manager{
+		azureEnvironment: azureEnvironment,
+		subscriptionID:   azureConfig.SubscriptionID,
+		resourceClient: az.NewResourceClient(
+			azureEnvironment,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+	}, nil
+}
+
+func (m *manager) GetServiceStatus(
+	serviceName string,
+	resourceGroupName string,
+) (*ServiceStatus, error) {
+	status := &ServiceStatus{}
+	if err := m.resourceClient.InvokeAction(
+		m.getServiceReference(serviceName, resourceGroupName),
+		"checkStatus",
+		nil,
+		status,
+	); err != nil {
+		return nil, fmt.Errorf(
+			"error checking Azure Database Migration Service status: %s",
+			err,
+		)
+	}
+	return status, nil
+}
+
+func (m *manager) GetManagementEndpoint(resourceID string) string {
+	return strings.TrimSuffix(
+		m.azureEnvironment.ResourceManagerEndpoint,
+		"/",
+	) + resourceID
+}
+
+func (m *manager) DeleteService(
+	serviceName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		m.getServiceReference(serviceName, resourceGroupName),
+	); err != nil {
+		return fmt.Errorf(
+			"error deleting Azure Database Migration Service: %s",
+			err,
+		)
+	}
+	return nil
+}
+
+func (m *manager) DeleteVirtualNetwork(
+	virtualNetworkName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		az.ResourceReference{
+			SubscriptionID:    m.subscriptionID,
+			ResourceGroupName: resourceGroupName,
+			ProviderNamespace: networkProviderNamespace,
+			ResourceType:      virtualNetworkType,
+			ResourceName:      virtualNetworkName,
+			APIVersion:        networkAPIVersion,
+		},
+	); err != nil {
+		return fmt.Errorf("error deleting virtual network: %s", err)
+	}
+	return nil
+}
+
+func (m *manager) getServiceReference(
+	serviceName string,
+	resourceGroupName string,
+) az.ResourceReference {
+	return az.ResourceReference{
+		SubscriptionID:    m.subscriptionID,
+		ResourceGroupName: resourceGroupName,
+		ProviderNamespace: providerNamespace,
+		ResourceType:      resourceType,
+		ResourceName:      serviceName,
+		APIVersion:        apiVersion,
+	}
+}
@@ -0,0 +1,130 @@
+package elasticsan
+
+import (
+	"fmt"
+
+	"github.com/Azure/go-autorest/autorest/azure"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+const (
+	providerNamespace = "Microsoft.ElasticSan"
+	resourceType      = "elasticSans"
+	apiVersion        = "2023-01-01"
+)
+
+// Manager is an interface to be implemented by any component capable of
+// managing an Azure Elastic SAN and its volume groups and volumes
+type Manager interface {
+	DeleteVolume(
+		sanName string,
+		volumeGroupName string,
+		volumeName string,
+		resourceGroupName string,
+	) error
+	DeleteVolumeGroup(
+		sanName string,
+		volumeGroupName string,
+		resourceGroupName string,
+	) error
+	// DeleteSAN deletes the named Elastic SAN. Azure refuses to delete a SAN
+	// that still has volume groups, so those must be deleted first.
+	DeleteSAN(sanName string, resourceGroupName string) error
+}
+
+type manager struct {
+	subscriptionID string
+	resourceClient az.ResourceClient
+}
+
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	return &manager{
+		subscriptionID: azureConfig.SubscriptionID,
+		resourceClient: az.NewResourceClient(
+			azureEnvironment,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+	}, nil
+}
+
+func (m *manager) DeleteVolume(
+	sanName string,
+	volumeGroupName string,
+	volumeName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		az.ResourceReference{
+			SubscriptionID:    m.subscriptionID,
+			ResourceGroupName: resourceGroupName,
+			ProviderNamespace: providerNamespace,
+			ResourceType: fmt.Sprintf(
+				"%s/%s/volumegroups/%s/volumes",
+				resourceType,
+				sanName,
+				volumeGroupName,
+			),
+			ResourceName: volumeName,
+			APIVersion:   apiVersion,
+		},
+	); err != nil {
+		return service.WrapError(err, "error deleting Elastic SAN volume")
+	}
+	return nil
+}
+
+func (m *manager) DeleteVolumeGroup(
+	sanName string,
+	volumeGroupName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		az.ResourceReference{
+			SubscriptionID:    m.subscriptionID,
+			ResourceGroupName: resourceGroupName,
+			ProviderNamespace: providerNamespace,
+			ResourceType: fmt.Sprintf(
+				"%s/%s/volumegroups",
+				resourceType,
+				sanName,
+			),
+			ResourceName: volumeGroupName,
+			APIVersion:   apiVersion,
+		},
+	); err != nil {
+		return service.WrapError(err, "error deleting Elastic SAN volume group")
+	}
+	return nil
+}
+
+func (m *manager) DeleteSAN(sanName string, resourceGroupName string) error {
+	if err := m.resourceClient.DeleteResource(
+		az.ResourceReference{
+			SubscriptionID:    m.subscriptionID,
+			ResourceGroupName: resourceGroupName,
+			ProviderNamespace: providerNamespace,
+			ResourceType:      resourceType,
+			ResourceName:      sanName,
+			APIVersion:        apiVersion,
+		},
+	); err != nil {
+		return service.WrapError(err, "error deleting Elastic SAN")
+	}
+	return nil
+}
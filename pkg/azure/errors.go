@@ -0,0 +1,55 @@
+package azure
+
+import (
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// inUseErrorCodes are the codes with which Azure refuses to delete a resource
+// because other resources still depend on it. Azure doesn't use any one status
+// code for this, so such errors can only be recognized by their codes.
+var inUseErrorCodes = map[string]bool{
+	"InUseSubnetCannotBeDeleted":               true,
+	"InUseNetworkSecurityGroupCannotBeDeleted": true,
+	"InUseRouteTableCannotBeDeleted":           true,
+	"PublicIPAddressInUse":                     true,
+	"NicInUse":                                 true,
+	"ResourceInUse":                            true,
+}
+
+// CategorizeError inspects an error returned by an Azure client and, if the
+// error resulted from an unsuccessful HTTP response, wraps it in a
+// service.CategorizedError whose category reflects the response's status code
+// or, if the response refused to delete a resource that is still in use,
+// service.ErrorCategoryInUse. Any other error is returned unmodified.
+func CategorizeError(err error) error {
+	var statusCode interface{}
+	var serviceError *azure.ServiceError
+	switch e := err.(type) {
+	case autorest.DetailedError:
+		statusCode = e.StatusCode
+	case *autorest.DetailedError:
+		statusCode = e.StatusCode
+	case azure.RequestError:
+		statusCode = e.StatusCode
+		serviceError = e.ServiceError
+	case *azure.RequestError:
+		statusCode = e.StatusCode
+		serviceError = e.ServiceError
+	default:
+		return err
+	}
+	if serviceError != nil && inUseErrorCodes[serviceError.Code] {
+		return service.NewCategorizedError(service.ErrorCategoryInUse, err)
+	}
+	code, ok := statusCode.(int)
+	if !ok {
+		return err
+	}
+	category := service.GetErrorCategoryForStatusCode(code)
+	if category == service.ErrorCategoryUnknown {
+		return err
+	}
+	return service.NewCategorizedError(category, err)
+}
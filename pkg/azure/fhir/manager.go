@@ -0,0 +1,313 @@
+package fhir
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/version"
+)
+
+const (
+	providerNamespace       = "Microsoft.HealthcareApis"
+	workspaceResourceType   = "workspaces"
+	fhirServiceResourceType = "fhirservices"
+	apiVersion              = "2023-11-01"
+
+	provisioningPollingInterval = 15 * time.Second
+)
+
+// Workspace is an Azure Health Data Services workspace
+type Workspace struct {
+	ID   string            `json:"id"`
+	Tags map[string]string `json:"tags"`
+}
+
+// Manager is an interface to be implemented by any component capable of
+// managing FHIR services and the Azure Health Data Services workspaces they
+// belong to
+type Manager interface {
+	// GetWorkspace retrieves the named workspace. It returns a bool indicating
+	// whether the workspace was found.
+	GetWorkspace(
+		workspaceName string,
+		resourceGroupName string,
+	) (Workspace, bool, error)
+	// GetWorkspaceFHIRServiceIDs returns the resource IDs of the FHIR services
+	// that belong to the named workspace
+	GetWorkspaceFHIRServiceIDs(
+		workspaceName string,
+		resourceGroupName string,
+	) ([]string, error)
+	// WaitForFHIRService blocks until the named FHIR service has finished
+	// provisioning, or fails if it can't
+	WaitForFHIRService(
+		ctx context.Context,
+		workspaceName string,
+		fhirServiceName string,
+		resourceGroupName string,
+	) error
+	DeleteFHIRService(
+		workspaceName string,
+		fhirServiceName string,
+		resourceGroupName string,
+	) error
+	// DeleteWorkspace deletes the named workspace. Azure refuses to delete a
+	// workspace that still has services.
+	DeleteWorkspace(workspaceName string, resourceGroupName string) error
+}
+
+type manager struct {
+	azureEnvironment azure.Environment
+	subscriptionID   string
+	tenantID         string
+	clientID         string
+	clientSecret     string
+	resourceClient   az.ResourceClient
+}
+
+type fhirService struct {
+	ID         string `json:"id"`
+	Properties struct {
+		ProvisioningState string `json:"provisioningState"`
+	} `json:"properties"`
+}
+
+type fhirServiceList struct {
+	Value    []fhirService `json:"value"`
+	NextLink string        `json:"nextLink"`
+}
+
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	return &manager{
+		azureEnvironment: azureEnvironment,
+		subscriptionID:   azureConfig.SubscriptionID,
+		tenantID:         azureConfig.TenantID,
+		clientID:         azureConfig.ClientID,
+		clientSecret:     azureConfig.ClientSecret,
+		resourceClient: az.NewResourceClient(
+			azureEnvironment,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+	}, nil
+}
+
+func (m *manager) GetWorkspace(
+	workspaceName string,
+	resourceGroupName string,
+) (Workspace, bool, error) {
+	workspace := Workspace{}
+	found, err := m.resourceClient.GetResource(
+		m.getWorkspaceReference(workspaceName, resourceGroupName),
+		&workspace,
+	)
+	if err != nil {
+		return workspace, false, service.WrapError(
+			err,
+			"error retrieving Health Data Services workspace",
+		)
+	}
+	return workspace, found, nil
+}
+
+// GetWorkspaceFHIRServiceIDs lists the workspace's FHIR services directly,
+// since the generic resource client doesn't list resources
+func (m *manager) GetWorkspaceFHIRServiceIDs(
+	workspaceName string,
+	resourceGroupName string,
+) ([]string, error) {
+	authorizer, err := az.GetBearerTokenAuthorizer(
+		m.azureEnvironment,
+		m.tenantID,
+		m.clientID,
+		m.clientSecret,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error getting bearer token authorizer: %s", err)
+	}
+	client := autorest.NewClientWithUserAgent(
+		fmt.Sprintf("open-service-broker/%s", version.GetVersion()),
+	)
+	client.Authorizer = authorizer
+	req, err := autorest.Prepare(
+		&http.Request{},
+		autorest.AsGet(),
+		autorest.WithBaseURL(m.azureEnvironment.ResourceManagerEndpoint),
+		autorest.WithPath(
+			fmt.Sprintf(
+				"%s/%s",
+				m.getWorkspaceReference(workspaceName, resourceGroupName).ID(),
+				fhirServiceResourceType,
+			),
+		),
+		autorest.WithQueryParameters(map[string]interface{}{
+			"api-version": apiVersion,
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error preparing request: %s", err)
+	}
+	fhirServiceIDs := []string{}
+	for {
+		resp, err := autorest.SendWithSender(client, req)
+		if err != nil {
+			return nil, fmt.Errorf("error sending request: %s", err)
+		}
+		fhirServices := fhirServiceList{}
+		if err := autorest.Respond(
+			resp,
+			client.ByInspecting(),
+			azure.WithErrorUnlessStatusCode(http.StatusOK),
+			autorest.ByUnmarshallingJSON(&fhirServices),
+			autorest.ByClosing(),
+		); err != nil {
+			return nil, service.WrapError(
+				az.CategorizeError(err),
+				"error listing FHIR services",
+			)
+		}
+		for _, f := range fhirServices.Value {
+			fhirServiceIDs = append(fhirServiceIDs, f.ID)
+		}
+		if fhirServices.NextLink == "" {
+			return fhirServiceIDs, nil
+		}
+		if req, err = autorest.Prepare(
+			&http.Request{},
+			autorest.AsGet(),
+			autorest.WithBaseURL(fhirServices.NextLink),
+		); err != nil {
+			return nil, fmt.Errorf("error preparing request: %s", err)
+		}
+	}
+}
+
+func (m *manager) WaitForFHIRService(
+	ctx context.Context,
+	workspaceName string,
+	fhirServiceName string,
+	resourceGroupName string,
+) error {
+	ticker := time.NewTicker(provisioningPollingInterval)
+	defer ticker.Stop()
+	for {
+		f := fhirService{}
+		found, err := az.ReadWithConsistencyRetry(ctx, func() (bool, error) {
+			return m.resourceClient.GetResource(
+				m.getFHIRServiceReference(
+					workspaceName,
+					fhirServiceName,
+					resourceGroupName,
+				),
+				&f,
+			)
+		})
+		if err != nil {
+			return service.WrapError(err, "error retrieving FHIR service")
+		}
+		if !found {
+			return fmt.Errorf(`FHIR service "%s" not found`, fhirServiceName)
+		}
+		switch f.Properties.ProvisioningState {
+		case "Succeeded":
+			return nil
+		case "Failed", "Canceled":
+			return fmt.Errorf(
+				`FHIR service "%s" failed to provision; provisioning state is "%s"`,
+				fhirServiceName,
+				f.Properties.ProvisioningState,
+			)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (m *manager) DeleteFHIRService(
+	workspaceName string,
+	fhirServiceName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		m.getFHIRServiceReference(
+			workspaceName,
+			fhirServiceName,
+			resourceGroupName,
+		),
+	); err != nil {
+		return service.WrapError(err, "error deleting FHIR service")
+	}
+	return nil
+}
+
+func (m *manager) DeleteWorkspace(
+	workspaceName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		m.getWorkspaceReference(workspaceName, resourceGroupName),
+	); err != nil {
+		return service.WrapError(
+			err,
+			"error deleting Health Data Services workspace",
+		)
+	}
+	return nil
+}
+
+func (m *manager) getWorkspaceReference(
+	workspaceName string,
+	resourceGroupName string,
+) az.ResourceReference {
+	return az.ResourceReference{
+		SubscriptionID:    m.subscriptionID,
+		ResourceGroupName: resourceGroupName,
+		ProviderNamespace: providerNamespace,
+		ResourceType:      workspaceResourceType,
+		ResourceName:      workspaceName,
+		APIVersion:        apiVersion,
+	}
+}
+
+func (m *manager) getFHIRServiceReference(
+	workspaceName string,
+	fhirServiceName string,
+	resourceGroupName string,
+) az.ResourceReference {
+	return az.ResourceReference{
+		SubscriptionID:    m.subscriptionID,
+		ResourceGroupName: resourceGroupName,
+		ProviderNamespace: providerNamespace,
+		ResourceType: fmt.Sprintf(
+			"%s/%s/%s",
+			workspaceResourceType,
+			workspaceName,
+			fhirServiceResourceType,
+		),
+		ResourceName: fhirServiceName,
+		APIVersion:   apiVersion,
+	}
+}
@@ -0,0 +1,117 @@
+package firewallpolicy
+
+import (
+	"fmt"
+
+	"github.com/Azure/go-autorest/autorest/azure"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+const (
+	providerNamespace  = "Microsoft.Network"
+	policyResourceType = "firewallPolicies"
+	apiVersion         = "2023-09-01"
+)
+
+// SubResource is a reference, by resource ID, to another resource
+type SubResource struct {
+	ID string `json:"id"`
+}
+
+// Policy is a Firewall Manager policy
+type Policy struct {
+	ID         string `json:"id"`
+	Properties struct {
+		// Firewalls are the Azure Firewalls the policy is associated with
+		Firewalls []SubResource `json:"firewalls"`
+		// ChildPolicies are the policies that inherit the policy's rules
+		ChildPolicies []SubResource `json:"childPolicies"`
+	} `json:"properties"`
+}
+
+// Manager is an interface to be implemented by any component capable of
+// managing Firewall Manager policies
+type Manager interface {
+	// GetPolicy retrieves the named policy. It returns a bool indicating
+	// whether the policy was found.
+	GetPolicy(policyName string, resourceGroupName string) (Policy, bool, error)
+	// DeletePolicy deletes the named policy, along with its rule collection
+	// groups. Azure refuses to delete a policy that firewalls or child
+	// policies still refer to.
+	DeletePolicy(policyName string, resourceGroupName string) error
+}
+
+type manager struct {
+	subscriptionID string
+	resourceClient az.ResourceClient
+}
+
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	return &manager{
+		subscriptionID: azureConfig.SubscriptionID,
+		resourceClient: az.NewResourceClient(
+			azureEnvironment,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+	}, nil
+}
+
+func (m *manager) GetPolicy(
+	policyName string,
+	resourceGroupName string,
+) (Policy, bool, error) {
+	policy := Policy{}
+	found, err := m.resourceClient.GetResource(
+		m.getPolicyReference(policyName, resourceGroupName),
+		&policy,
+	)
+	if err != nil {
+		return policy, false, service.WrapError(
+			err,
+			"error retrieving firewall policy",
+		)
+	}
+	return policy, found, nil
+}
+
+func (m *manager) DeletePolicy(
+	policyName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		m.getPolicyReference(policyName, resourceGroupName),
+	); err != nil {
+		return service.WrapError(err, "error deleting firewall policy")
+	}
+	return nil
+}
+
+func (m *manager) getPolicyReference(
+	policyName string,
+	resourceGroupName string,
+) az.ResourceReference {
+	return az.ResourceReference{
+		SubscriptionID:    m.subscriptionID,
+		ResourceGroupName: resourceGroupName,
+		ProviderNamespace: providerNamespace,
+		ResourceType:      policyResourceType,
+		ResourceName:      policyName,
+		APIVersion:        apiVersion,
+	}
+}
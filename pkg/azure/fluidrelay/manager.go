@@ -0,0 +1,99 @@
+package fluidrelay
+
+import (
+	"fmt"
+
+	"github.com/Azure/go-autorest/autorest/azure"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+)
+
+const (
+	providerNamespace = "Microsoft.FluidRelay"
+	resourceType      = "fluidRelayServers"
+	apiVersion        = "2022-06-01"
+)
+
+// Keys encapsulates the access keys of an Azure Fluid Relay server
+type Keys struct {
+	Key1 string `json:"key1"`
+	Key2 string `json:"key2"`
+}
+
+// Manager is an interface to be implemented by any component capable of
+// managing an Azure Fluid Relay server
+type Manager interface {
+	GetKeys(serverName string, resourceGroupName string) (*Keys, error)
+	DeleteServer(serverName string, resourceGroupName string) error
+}
+
+type manager struct {
+	subscriptionID string
+	resourceClient az.ResourceClient
+}
+
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	return &manager{
+		subscriptionID: azureConfig.SubscriptionID,
+		resourceClient: az.NewResourceClient(
+			azureEnvironment,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+	}, nil
+}
+
+func (m *manager) GetKeys(
+	serverName string,
+	resourceGroupName string,
+) (*Keys, error) {
+	keys := &Keys{}
+	if err := m.resourceClient.InvokeAction(
+		m.getResourceReference(serverName, resourceGroupName),
+		"listKeys",
+		nil,
+		keys,
+	); err != nil {
+		return nil, fmt.Errorf("error listing Azure Fluid Relay keys: %s", err)
+	}
+	return keys, nil
+}
+
+func (m *manager) DeleteServer(
+	serverName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		m.getResourceReference(serverName, resourceGroupName),
+	); err != nil {
+		return fmt.Errorf("error deleting Azure Fluid Relay server: %s", err)
+	}
+	return nil
+}
+
+func (m *manager) getResourceReference(
+	serverName string,
+	resourceGroupName string,
+) az.ResourceReference {
+	return az.ResourceReference{
+		SubscriptionID:    m.subscriptionID,
+		ResourceGroupName: resourceGroupName,
+		ProviderNamespace: providerNamespace,
+		ResourceType:      resourceType,
+		ResourceName:      serverName,
+		APIVersion:        apiVersion,
+	}
+}
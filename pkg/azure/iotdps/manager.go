@@ -0,0 +1,459 @@
+package iotdps
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/version"
+)
+
+const (
+	providerNamespace   = "Microsoft.Devices"
+	resourceType        = "provisioningServices"
+	apiVersion          = "2022-02-05"
+	iotHubResourceType  = "IotHubs"
+	iotHubAPIVersion    = "2021-07-02"
+	iotHubOwnerKeyName  = "iothubowner"
+	ownerKeyName        = "provisioningserviceowner"
+	dataPlaneAPIVersion = "2021-10-01"
+	// sasTokenTTL is how long each shared access signature used to authorize a
+	// request to a DPS's service API remains valid
+	sasTokenTTL = time.Hour
+)
+
+// IoTHub is the subset of an IoT hub's properties that linking it to a DPS
+// requires, along with the connection string of its owner policy, which the
+// DPS uses to register devices in the hub
+type IoTHub struct {
+	Location         string
+	HostName         string
+	ConnectionString string
+}
+
+// AccessKeys are the keys of one of a DPS's shared access policies
+type AccessKeys struct {
+	PrimaryKey   string `json:"primaryKey"`
+	SecondaryKey string `json:"secondaryKey"`
+}
+
+// EnrollmentGroup is a group of devices, attested using keys derived from the
+// group's own symmetric key, that are provisioned alike
+type EnrollmentGroup struct {
+	ID string
+	// AllocationPolicy is how the group's devices are assigned to hubs, using
+	// the data plane's names for the policies (e.g. "geoLatency"), or empty if
+	// the DPS's own policy applies
+	AllocationPolicy string
+	// IoTHubHostNames are the linked hubs to which the group's devices may be
+	// assigned, or empty if they may be assigned to any of them
+	IoTHubHostNames []string
+}
+
+// Manager is an interface to be implemented by any component capable of
+// managing Azure IoT Hub Device Provisioning Services
+type Manager interface {
+	// GetIoTHub retrieves the IoT hub having the given resource ID, which may
+	// belong to a subscription other than the broker's own. It returns a bool
+	// indicating whether the hub was found.
+	GetIoTHub(resourceID string) (IoTHub, bool, error)
+	// CreateAccessPolicy creates, or replaces, the named shared access policy of
+	// a DPS, granting the given rights, and returns the policy's keys
+	CreateAccessPolicy(
+		serviceName string,
+		resourceGroupName string,
+		keyName string,
+		rights []string,
+	) (AccessKeys, error)
+	// DeleteAccessPolicy deletes the named shared access policy of a DPS.
+	// Deleting a policy that does not exist is not an error.
+	DeleteAccessPolicy(
+		serviceName string,
+		resourceGroupName string,
+		keyName string,
+	) error
+	// CreateEnrollmentGroup creates, or replaces, an enrollment group of the
+	// DPS whose service API is served from the given host name
+	CreateEnrollmentGroup(
+		serviceName string,
+		resourceGroupName string,
+		serviceOperationsHostName string,
+		group EnrollmentGroup,
+	) error
+	DeleteService(serviceName string, resourceGroupName string) error
+}
+
+type manager struct {
+	subscriptionID string
+	resourceClient az.ResourceClient
+}
+
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	return &manager{
+		subscriptionID: azureConfig.SubscriptionID,
+		resourceClient: az.NewResourceClient(
+			azureEnvironment,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+	}, nil
+}
+
+func (m *manager) GetIoTHub(resourceID string) (IoTHub, bool, error) {
+	ref, err := getIoTHubReference(resourceID)
+	if err != nil {
+		return IoTHub{}, false, err
+	}
+	hub := struct {
+		Location   string `json:"location"`
+		Properties struct {
+			HostName string `json:"hostName"`
+		} `json:"properties"`
+	}{}
+	found, err := m.resourceClient.GetResource(ref, &hub)
+	if err != nil {
+		return IoTHub{}, false, service.WrapError(
+			err,
+			"error retrieving IoT hub",
+		)
+	}
+	if !found {
+		return IoTHub{}, false, nil
+	}
+	keys := struct {
+		Value []struct {
+			KeyName    string `json:"keyName"`
+			PrimaryKey string `json:"primaryKey"`
+		} `json:"value"`
+	}{}
+	if err := m.resourceClient.InvokeAction(
+		ref,
+		"listkeys",
+		nil,
+		&keys,
+	); err != nil {
+		return IoTHub{}, false, service.WrapError(
+			err,
+			"error listing IoT hub keys",
+		)
+	}
+	for _, key := range keys.Value {
+		if key.KeyName == iotHubOwnerKeyName {
+			return IoTHub{
+				Location: hub.Location,
+				HostName: hub.Properties.HostName,
+				ConnectionString: fmt.Sprintf(
+					"HostName=%s;SharedAccessKeyName=%s;SharedAccessKey=%s",
+					hub.Properties.HostName,
+					key.KeyName,
+					key.PrimaryKey,
+				),
+			}, true, nil
+		}
+	}
+	return IoTHub{}, false, fmt.Errorf(
+		`IoT hub "%s" has no "%s" shared access policy`,
+		resourceID,
+		iotHubOwnerKeyName,
+	)
+}
+
+func (m *manager) CreateAccessPolicy(
+	serviceName string,
+	resourceGroupName string,
+	keyName string,
+	rights []string,
+) (AccessKeys, error) {
+	ref := m.getReference(serviceName, resourceGroupName)
+	if err := m.updateAccessPolicies(
+		ref,
+		keyName,
+		map[string]interface{}{
+			"keyName": keyName,
+			"rights":  strings.Join(rights, ", "),
+		},
+	); err != nil {
+		return AccessKeys{}, fmt.Errorf(
+			`error creating shared access policy "%s": %s`,
+			keyName,
+			err,
+		)
+	}
+	keys, err := m.getAccessKeys(ref, keyName)
+	if err != nil {
+		return AccessKeys{}, fmt.Errorf(
+			`error listing keys of shared access policy "%s": %s`,
+			keyName,
+			err,
+		)
+	}
+	return keys, nil
+}
+
+func (m *manager) DeleteAccessPolicy(
+	serviceName string,
+	resourceGroupName string,
+	keyName string,
+) error {
+	if err := m.updateAccessPolicies(
+		m.getReference(serviceName, resourceGroupName),
+		keyName,
+		nil,
+	); err != nil {
+		return fmt.Errorf(
+			`error deleting shared access policy "%s": %s`,
+			keyName,
+			err,
+		)
+	}
+	return nil
+}
+
+func (m *manager) CreateEnrollmentGroup(
+	serviceName string,
+	resourceGroupName string,
+	serviceOperationsHostName string,
+	group EnrollmentGroup,
+) error {
+	ownerKeys, err := m.getAccessKeys(
+		m.getReference(serviceName, resourceGroupName),
+		ownerKeyName,
+	)
+	if err != nil {
+		return fmt.Errorf("error listing owner keys: %s", err)
+	}
+	sasToken, err := getSASToken(
+		serviceOperationsHostName,
+		ownerKeyName,
+		ownerKeys.PrimaryKey,
+		time.Now().Add(sasTokenTTL),
+	)
+	if err != nil {
+		return err
+	}
+	body := map[string]interface{}{
+		"enrollmentGroupId": group.ID,
+		// Omitting the symmetric key has the DPS generate one
+		"attestation": map[string]interface{}{
+			"type":         "symmetricKey",
+			"symmetricKey": map[string]interface{}{},
+		},
+		"provisioningStatus": "enabled",
+	}
+	if group.AllocationPolicy != "" {
+		body["allocationPolicy"] = group.AllocationPolicy
+	}
+	if len(group.IoTHubHostNames) > 0 {
+		body["iotHubs"] = group.IoTHubHostNames
+	}
+	client := autorest.NewClientWithUserAgent(
+		fmt.Sprintf("open-service-broker/%s", version.GetVersion()),
+	)
+	req, err := autorest.Prepare(
+		&http.Request{},
+		autorest.AsPut(),
+		autorest.AsJSON(),
+		autorest.WithBaseURL("https://"+serviceOperationsHostName),
+		autorest.WithPathParameters(
+			"/enrollmentGroups/{id}",
+			map[string]interface{}{
+				"id": autorest.Encode("path", group.ID),
+			},
+		),
+		autorest.WithQueryParameters(map[string]interface{}{
+			"api-version": dataPlaneAPIVersion,
+		}),
+		autorest.WithHeader("Authorization", sasToken),
+		autorest.WithJSON(body),
+	)
+	if err != nil {
+		return fmt.Errorf("error preparing request: %s", err)
+	}
+	resp, err := autorest.SendWithSender(client, req)
+	if err != nil {
+		return fmt.Errorf(
+			`error creating enrollment group "%s": %s`,
+			group.ID,
+			err,
+		)
+	}
+	if err := autorest.Respond(
+		resp,
+		client.ByInspecting(),
+		azure.WithErrorUnlessStatusCode(http.StatusOK),
+		autorest.ByClosing(),
+	); err != nil {
+		return service.WrapError(
+			az.CategorizeError(err),
+			fmt.Sprintf(`error creating enrollment group "%s"`, group.ID),
+		)
+	}
+	return nil
+}
+
+func (m *manager) DeleteService(
+	serviceName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		m.getReference(serviceName, resourceGroupName),
+	); err != nil {
+		return service.WrapError(
+			err,
+			"error deleting IoT Hub Device Provisioning Service",
+		)
+	}
+	return nil
+}
+
+func (m *manager) getReference(
+	serviceName string,
+	resourceGroupName string,
+) az.ResourceReference {
+	return az.ResourceReference{
+		SubscriptionID:    m.subscriptionID,
+		ResourceGroupName: resourceGroupName,
+		ProviderNamespace: providerNamespace,
+		ResourceType:      resourceType,
+		ResourceName:      serviceName,
+		APIVersion:        apiVersion,
+	}
+}
+
+// updateAccessPolicies replaces the named shared access policy of a DPS with
+// the given one or, if that is nil, removes it. A DPS's policies are
+// properties of the DPS itself rather than resources of their own, so the
+// whole DPS is read and written back.
+func (m *manager) updateAccessPolicies(
+	ref az.ResourceReference,
+	keyName string,
+	policy map[string]interface{},
+) error {
+	dps := map[string]interface{}{}
+	found, err := m.resourceClient.GetResource(ref, &dps)
+	if err != nil {
+		return err
+	}
+	if !found {
+		if policy == nil {
+			return nil
+		}
+		return errors.New("IoT Hub Device Provisioning Service not found")
+	}
+	properties, _ := dps["properties"].(map[string]interface{})
+	if properties == nil {
+		properties = map[string]interface{}{}
+		dps["properties"] = properties
+	}
+	existing, _ := properties["authorizationPolicies"].([]interface{})
+	policies := []interface{}{}
+	var changed bool
+	for _, p := range existing {
+		if existingPolicy, ok := p.(map[string]interface{}); ok &&
+			existingPolicy["keyName"] == keyName {
+			changed = true
+			continue
+		}
+		policies = append(policies, p)
+	}
+	if policy != nil {
+		policies = append(policies, policy)
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	properties["authorizationPolicies"] = policies
+	return m.resourceClient.PutResource(ref, dps, nil)
+}
+
+func (m *manager) getAccessKeys(
+	ref az.ResourceReference,
+	keyName string,
+) (AccessKeys, error) {
+	keys := AccessKeys{}
+	err := m.resourceClient.InvokeAction(
+		ref,
+		fmt.Sprintf("keys/%s/listkeys", url.PathEscape(keyName)),
+		nil,
+		&keys,
+	)
+	return keys, err
+}
+
+// getIoTHubReference returns a reference to the IoT hub having the given
+// resource ID
+func getIoTHubReference(resourceID string) (az.ResourceReference, error) {
+	parts := strings.Split(strings.Trim(resourceID, "/"), "/")
+	if len(parts) != 8 ||
+		!strings.EqualFold(parts[0], "subscriptions") ||
+		!strings.EqualFold(parts[2], "resourceGroups") ||
+		!strings.EqualFold(parts[4], "providers") ||
+		!strings.EqualFold(parts[5], providerNamespace) ||
+		!strings.EqualFold(parts[6], iotHubResourceType) {
+		return az.ResourceReference{}, fmt.Errorf(
+			`"%s" is not the resource ID of an IoT hub`,
+			resourceID,
+		)
+	}
+	return az.ResourceReference{
+		SubscriptionID:    parts[1],
+		ResourceGroupName: parts[3],
+		ProviderNamespace: providerNamespace,
+		ResourceType:      iotHubResourceType,
+		ResourceName:      parts[7],
+		APIVersion:        iotHubAPIVersion,
+	}, nil
+}
+
+// getSASToken returns a shared access signature, signed with the given key of
+// the named policy, that authorizes requests to the given host until the given
+// expiry
+func getSASToken(
+	hostName string,
+	keyName string,
+	key string,
+	expiry time.Time,
+) (string, error) {
+	decodedKey, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return "", fmt.Errorf("error decoding shared access key: %s", err)
+	}
+	resourceURI := url.QueryEscape(hostName)
+	expiryStr := fmt.Sprintf("%d", expiry.Unix())
+	mac := hmac.New(sha256.New, decodedKey)
+	// hash.Hash writes never fail
+	mac.Write([]byte(resourceURI + "\n" + expiryStr)) // nolint: errcheck
+	signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	return fmt.Sprintf(
+		"SharedAccessSignature sr=%s&sig=%s&se=%s&skn=%s",
+		resourceURI,
+		url.QueryEscape(signature),
+		expiryStr,
+		keyName,
+	), nil
+}
@@ -0,0 +1,186 @@
+package kusto
+
+import (
+	"fmt"
+
+	"github.com/Azure/go-autorest/autorest/azure"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+)
+
+const (
+	providerNamespace = "Microsoft.Kusto"
+	apiVersion        = "2020-09-18"
+)
+
+// PrincipalAssignment describes a principal that has been granted a role on
+// an Azure Data Explorer database
+type PrincipalAssignment struct {
+	PrincipalID string
+	Role        string
+	TenantID    string
+}
+
+// Manager is an interface to be implemented by any component capable of
+// managing Azure Data Explorer clusters and databases
+type Manager interface {
+	CreateDatabasePrincipalAssignment(
+		clusterName string,
+		databaseName string,
+		resourceGroupName string,
+		assignmentName string,
+		principalID string,
+		role string,
+	) (*PrincipalAssignment, error)
+	DeleteDatabasePrincipalAssignment(
+		clusterName string,
+		databaseName string,
+		resourceGroupName string,
+		assignmentName string,
+	) error
+	DeleteCluster(
+		clusterName string,
+		resourceGroupName string,
+	) error
+}
+
+type manager struct {
+	subscriptionID string
+	tenantID       string
+	resourceClient az.ResourceClient
+}
+
+type principalAssignmentProperties struct {
+	PrincipalID   string `json:"principalId"`
+	PrincipalType string `json:"principalType"`
+	Role          string `json:"role"`
+	TenantID      string `json:"tenantId"`
+}
+
+type principalAssignment struct {
+	Properties principalAssignmentProperties `json:"properties"`
+}
+
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	return &manager{
+		subscriptionID: azureConfig.SubscriptionID,
+		tenantID:       azureConfig.TenantID,
+		resourceClient: az.NewResourceClient(
+			azureEnvironment,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+	}, nil
+}
+
+func (m *manager) CreateDatabasePrincipalAssignment(
+	clusterName string,
+	databaseName string,
+	resourceGroupName string,
+	assignmentName string,
+	principalID string,
+	role string,
+) (*PrincipalAssignment, error) {
+	result := &principalAssignment{}
+	if err := m.resourceClient.PutResource(
+		m.getPrincipalAssignmentReference(
+			clusterName,
+			databaseName,
+			resourceGroupName,
+			assignmentName,
+		),
+		&principalAssignment{
+			Properties: principalAssignmentProperties{
+				PrincipalID:   principalID,
+				PrincipalType: "App",
+				Role:          role,
+				TenantID:      m.tenantID,
+			},
+		},
+		result,
+	); err != nil {
+		return nil, fmt.Errorf(
+			"error creating Azure Data Explorer database principal assignment: %s",
+			err,
+		)
+	}
+	return &PrincipalAssignment{
+		PrincipalID: result.Properties.PrincipalID,
+		Role:        result.Properties.Role,
+		TenantID:    result.Properties.TenantID,
+	}, nil
+}
+
+func (m *manager) DeleteDatabasePrincipalAssignment(
+	clusterName string,
+	databaseName string,
+	resourceGroupName string,
+	assignmentName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		m.getPrincipalAssignmentReference(
+			clusterName,
+			databaseName,
+			resourceGroupName,
+			assignmentName,
+		),
+	); err != nil {
+		return fmt.Errorf(
+			"error deleting Azure Data Explorer database principal assignment: %s",
+			err,
+		)
+	}
+	return nil
+}
+
+func (m *manager) DeleteCluster(
+	clusterName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		az.ResourceReference{
+			SubscriptionID:    m.subscriptionID,
+			ResourceGroupName: resourceGroupName,
+			ProviderNamespace: providerNamespace,
+			ResourceType:      "clusters",
+			ResourceName:      clusterName,
+			APIVersion:        apiVersion,
+		},
+	); err != nil {
+		return fmt.Errorf("error deleting Azure Data Explorer cluster: %s", err)
+	}
+	return nil
+}
+
+func (m *manager) getPrincipalAssignmentReference(
+	clusterName string,
+	databaseName string,
+	resourceGroupName string,
+	assignmentName string,
+) az.ResourceReference {
+	return az.ResourceReference{
+		SubscriptionID:    m.subscriptionID,
+		ResourceGroupName: resourceGroupName,
+		ProviderNamespace: providerNamespace,
+		ResourceType: fmt.Sprintf(
+			"clusters/%s/databases/%s/principalAssignments",
+			clusterName,
+			databaseName,
+		),
+		ResourceName: assignmentName,
+		APIVersion:   apiVersion,
+	}
+}
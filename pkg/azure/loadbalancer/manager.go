@@ -0,0 +1,364 @@
+package loadbalancer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest/azure"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+)
+
+const (
+	networkProviderNamespace = "Microsoft.Network"
+	loadBalancerType         = "loadBalancers"
+	backendPoolType          = "backendAddressPools"
+	apiVersion               = "2023-09-01"
+)
+
+// HealthProbe describes how a load balancer probes a backend to decide
+// whether it is healthy
+type HealthProbe struct {
+	// Protocol is one of Tcp, Http or Https
+	Protocol string
+	Port     int
+	// RequestPath is the path requested of Http and Https probes and must be
+	// empty for Tcp probes
+	RequestPath       string
+	IntervalInSeconds int
+	NumberOfProbes    int
+}
+
+// Backend describes an endpoint, identified by its IP address, that a load
+// balancer is to balance traffic to, along with how that traffic arrives and
+// how the endpoint is probed
+type Backend struct {
+	// Name names the backend pool, health probe and load-balancing rule that are
+	// created for the backend alike
+	Name             string
+	VirtualNetworkID string
+	IPAddress        string
+	// Protocol is one of Tcp or Udp
+	Protocol     string
+	Port         int
+	FrontendPort int
+	HealthProbe  HealthProbe
+}
+
+// Manager is an interface to be implemented by any component capable of
+// managing Azure load balancers and the backends registered with them
+type Manager interface {
+	// RegisterBackend adds the given backend to the named load balancer as the
+	// sole member of a backend pool of its own, probed by a health probe of its
+	// own and reached through a load-balancing rule of its own on the load
+	// balancer's frontend. It fails if another rule already uses the backend's
+	// frontend port and protocol.
+	RegisterBackend(
+		loadBalancerName string,
+		resourceGroupName string,
+		backend Backend,
+	) error
+	// DeregisterBackend removes the named backend's load-balancing rule, health
+	// probe and backend pool from the named load balancer. Deregistering a
+	// backend that isn't registered is not an error.
+	DeregisterBackend(
+		loadBalancerName string,
+		resourceGroupName string,
+		backendName string,
+	) error
+	DeleteLoadBalancer(
+		loadBalancerName string,
+		resourceGroupName string,
+	) error
+}
+
+type manager struct {
+	subscriptionID string
+	resourceClient az.ResourceClient
+}
+
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	return &manager{
+		subscriptionID: azureConfig.SubscriptionID,
+		resourceClient: az.NewResourceClient(
+			azureEnvironment,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+	}, nil
+}
+
+// GetVirtualNetworkID returns the resource ID of the virtual network that the
+// subnet having the given (well-formed) resource ID belongs to
+func GetVirtualNetworkID(subnetID string) string {
+	return subnetID[:strings.LastIndex(strings.ToLower(subnetID), "/subnets/")]
+}
+
+// RegisterBackend creates the backend's pool on its own, since Azure accepts
+// backend pools as resources in their own right, but must put the whole load
+// balancer to add the probe and rule, which it doesn't. Properties of the load
+// balancer that the broker doesn't know of are put back as they were found.
+func (m *manager) RegisterBackend(
+	loadBalancerName string,
+	resourceGroupName string,
+	backend Backend,
+) error {
+	lbRef := m.getLoadBalancerReference(loadBalancerName, resourceGroupName)
+	lb := map[string]interface{}{}
+	found, err := m.resourceClient.GetResource(lbRef, &lb)
+	if err != nil {
+		return fmt.Errorf("error getting load balancer: %s", err)
+	}
+	if !found {
+		return fmt.Errorf(`load balancer "%s" does not exist`, loadBalancerName)
+	}
+	properties := getMap(lb, "properties")
+	for _, rule := range getList(properties, "loadBalancingRules") {
+		ruleProperties := getMap(rule, "properties")
+		if rule["name"] == backend.Name {
+			continue
+		}
+		if toInt(ruleProperties["frontendPort"]) == backend.FrontendPort &&
+			strings.EqualFold(
+				fmt.Sprint(ruleProperties["protocol"]),
+				backend.Protocol,
+			) {
+			return fmt.Errorf(
+				`frontend port %d/%s of load balancer "%s" is already in use`,
+				backend.FrontendPort,
+				backend.Protocol,
+				loadBalancerName,
+			)
+		}
+	}
+	frontends := getList(properties, "frontendIPConfigurations")
+	if len(frontends) == 0 {
+		return fmt.Errorf(
+			`load balancer "%s" has no frontend IP configuration`,
+			loadBalancerName,
+		)
+	}
+
+	poolRef := m.getBackendPoolReference(
+		loadBalancerName,
+		resourceGroupName,
+		backend.Name,
+	)
+	if err := m.resourceClient.PutResource(
+		poolRef,
+		map[string]interface{}{
+			"properties": map[string]interface{}{
+				"loadBalancerBackendAddresses": []interface{}{
+					map[string]interface{}{
+						"name": backend.Name,
+						"properties": map[string]interface{}{
+							"ipAddress": backend.IPAddress,
+							"virtualNetwork": map[string]interface{}{
+								"id": backend.VirtualNetworkID,
+							},
+						},
+					},
+				},
+			},
+		},
+		nil,
+	); err != nil {
+		return fmt.Errorf("error creating backend pool: %s", err)
+	}
+
+	// The load balancer as it was read doesn't know of the new pool; putting it
+	// without the pool would delete it
+	lb = map[string]interface{}{}
+	if _, err = m.resourceClient.GetResource(lbRef, &lb); err != nil {
+		return fmt.Errorf("error getting load balancer: %s", err)
+	}
+	properties = getMap(lb, "properties")
+	probeProperties := map[string]interface{}{
+		"protocol":          backend.HealthProbe.Protocol,
+		"port":              backend.HealthProbe.Port,
+		"intervalInSeconds": backend.HealthProbe.IntervalInSeconds,
+		"numberOfProbes":    backend.HealthProbe.NumberOfProbes,
+	}
+	if backend.HealthProbe.RequestPath != "" {
+		probeProperties["requestPath"] = backend.HealthProbe.RequestPath
+	}
+	properties["probes"] = append(
+		withoutNamed(getList(properties, "probes"), backend.Name),
+		map[string]interface{}{
+			"name":       backend.Name,
+			"properties": probeProperties,
+		},
+	)
+	properties["loadBalancingRules"] = append(
+		withoutNamed(getList(properties, "loadBalancingRules"), backend.Name),
+		map[string]interface{}{
+			"name": backend.Name,
+			"properties": map[string]interface{}{
+				"frontendIPConfiguration": map[string]interface{}{
+					"id": frontends[0]["id"],
+				},
+				"backendAddressPool": map[string]interface{}{
+					"id": poolRef.ID(),
+				},
+				"probe": map[string]interface{}{
+					"id": fmt.Sprintf("%s/probes/%s", lbRef.ID(), backend.Name),
+				},
+				"protocol":     backend.Protocol,
+				"frontendPort": backend.FrontendPort,
+				"backendPort":  backend.Port,
+			},
+		},
+	)
+	if err := m.resourceClient.PutResource(lbRef, lb, nil); err != nil {
+		return fmt.Errorf("error adding load-balancing rule: %s", err)
+	}
+	return nil
+}
+
+func (m *manager) DeregisterBackend(
+	loadBalancerName string,
+	resourceGroupName string,
+	backendName string,
+) error {
+	lbRef := m.getLoadBalancerReference(loadBalancerName, resourceGroupName)
+	lb := map[string]interface{}{}
+	found, err := m.resourceClient.GetResource(lbRef, &lb)
+	if err != nil {
+		return fmt.Errorf("error getting load balancer: %s", err)
+	}
+	if !found {
+		return nil
+	}
+	properties := getMap(lb, "properties")
+	probes := getList(properties, "probes")
+	rules := getList(properties, "loadBalancingRules")
+	remainingProbes := withoutNamed(probes, backendName)
+	remainingRules := withoutNamed(rules, backendName)
+	// The pool can't be deleted while a rule still refers to it, nor the probe
+	// while the rule does
+	if len(remainingProbes) != len(probes) || len(remainingRules) != len(rules) {
+		properties["probes"] = remainingProbes
+		properties["loadBalancingRules"] = remainingRules
+		if err := m.resourceClient.PutResource(lbRef, lb, nil); err != nil {
+			return fmt.Errorf("error removing load-balancing rule: %s", err)
+		}
+	}
+	if err := m.resourceClient.DeleteResource(
+		m.getBackendPoolReference(
+			loadBalancerName,
+			resourceGroupName,
+			backendName,
+		),
+	); err != nil {
+		return fmt.Errorf("error deleting backend pool: %s", err)
+	}
+	return nil
+}
+
+func (m *manager) DeleteLoadBalancer(
+	loadBalancerName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		m.getLoadBalancerReference(loadBalancerName, resourceGroupName),
+	); err != nil {
+		return fmt.Errorf("error deleting load balancer: %s", err)
+	}
+	return nil
+}
+
+func (m *manager) getLoadBalancerReference(
+	loadBalancerName string,
+	resourceGroupName string,
+) az.ResourceReference {
+	return az.ResourceReference{
+		SubscriptionID:    m.subscriptionID,
+		ResourceGroupName: resourceGroupName,
+		ProviderNamespace: networkProviderNamespace,
+		ResourceType:      loadBalancerType,
+		ResourceName:      loadBalancerName,
+		APIVersion:        apiVersion,
+	}
+}
+
+func (m *manager) getBackendPoolReference(
+	loadBalancerName string,
+	resourceGroupName string,
+	poolName string,
+) az.ResourceReference {
+	return az.ResourceReference{
+		SubscriptionID:    m.subscriptionID,
+		ResourceGroupName: resourceGroupName,
+		ProviderNamespace: networkProviderNamespace,
+		ResourceType: fmt.Sprintf(
+			"%s/%s/%s",
+			loadBalancerType,
+			loadBalancerName,
+			backendPoolType,
+		),
+		ResourceName: poolName,
+		APIVersion:   apiVersion,
+	}
+}
+
+// getMap returns the object held by the named field of the given object,
+// adding an empty one if there is none
+func getMap(
+	object map[string]interface{},
+	field string,
+) map[string]interface{} {
+	value, ok := object[field].(map[string]interface{})
+	if !ok {
+		value = map[string]interface{}{}
+		object[field] = value
+	}
+	return value
+}
+
+// getList returns the objects in the list held by the named field of the
+// given object
+func getList(
+	object map[string]interface{},
+	field string,
+) []map[string]interface{} {
+	values, _ := object[field].([]interface{})
+	list := make([]map[string]interface{}, 0, len(values))
+	for _, value := range values {
+		if item, ok := value.(map[string]interface{}); ok {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// withoutNamed returns those of the given objects not having the given name,
+// as a list that can be marshaled back into a field
+func withoutNamed(list []map[string]interface{}, name string) []interface{} {
+	others := []interface{}{}
+	for _, item := range list {
+		if item["name"] != name {
+			others = append(others, item)
+		}
+	}
+	return others
+}
+
+func toInt(value interface{}) int {
+	if number, ok := value.(float64); ok {
+		return int(number)
+	}
+	return 0
+}
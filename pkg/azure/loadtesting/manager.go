@@ -0,0 +1,255 @@
+package loadtesting
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/version"
+)
+
+const (
+	providerNamespace          = "Microsoft.LoadTestService"
+	resourceType               = "loadTests"
+	apiVersion                 = "2022-12-01"
+	privateEndpointsAPIVersion = "2023-04-01"
+	// dataPlaneResource is the resource that tokens for every load testing
+	// resource's data plane are issued for
+	dataPlaneResource = "https://cnt-prod.loadtesting.azure.com"
+)
+
+// DataPlaneAPIVersion is the version of the Azure Load Testing data plane API
+// that tests are created with, and that test runs should be started with
+const DataPlaneAPIVersion = "2022-11-01"
+
+// Manager is an interface to be implemented by any component capable of
+// managing an Azure Load Testing resource and access to it
+type Manager interface {
+	// CreateRoleAssignment assigns the role identified by the given (unqualified)
+	// role definition ID to the given principal at the scope of the given load
+	// testing resource
+	CreateRoleAssignment(
+		loadTestID string,
+		roleAssignmentName string,
+		roleDefinitionID string,
+		principalID string,
+	) error
+	// DeleteRoleAssignment deletes the named role assignment from the given load
+	// testing resource. Deleting a role assignment that does not exist is not an
+	// error.
+	DeleteRoleAssignment(loadTestID string, roleAssignmentName string) error
+	DeleteLoadTest(loadTestName string, resourceGroupName string) error
+	// CreateOrUpdateTest creates the identified test using the given load
+	// testing resource's data plane, or, if it already exists, merges the given
+	// test definition into it
+	CreateOrUpdateTest(
+		dataPlaneURI string,
+		testID string,
+		test map[string]interface{},
+	) error
+	// UploadTestScript uploads the given JMeter script to the identified test,
+	// replacing any script already uploaded under the same file name. Azure
+	// validates the script asynchronously.
+	UploadTestScript(
+		dataPlaneURI string,
+		testID string,
+		fileName string,
+		script []byte,
+	) error
+	// DeletePrivateEndpoint deletes the named private endpoint. Deleting a
+	// private endpoint that does not exist is not an error.
+	DeletePrivateEndpoint(
+		privateEndpointName string,
+		resourceGroupName string,
+	) error
+}
+
+type manager struct {
+	azureEnvironment azure.Environment
+	subscriptionID   string
+	tenantID         string
+	clientID         string
+	clientSecret     string
+	resourceClient   az.ResourceClient
+	az.RoleAssignmentClient
+}
+
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	return &manager{
+		azureEnvironment: azureEnvironment,
+		subscriptionID:   azureConfig.SubscriptionID,
+		tenantID:         azureConfig.TenantID,
+		clientID:         azureConfig.ClientID,
+		clientSecret:     azureConfig.ClientSecret,
+		resourceClient: az.NewResourceClient(
+			azureEnvironment,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+		RoleAssignmentClient: az.NewRoleAssignmentClient(
+			azureEnvironment,
+			azureConfig.SubscriptionID,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+	}, nil
+}
+
+func (m *manager) DeleteLoadTest(
+	loadTestName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		az.ResourceReference{
+			SubscriptionID:    m.subscriptionID,
+			ResourceGroupName: resourceGroupName,
+			ProviderNamespace: providerNamespace,
+			ResourceType:      resourceType,
+			ResourceName:      loadTestName,
+			APIVersion:        apiVersion,
+		},
+	); err != nil {
+		return fmt.Errorf("error deleting Azure Load Testing resource: %s", err)
+	}
+	return nil
+}
+
+func (m *manager) CreateOrUpdateTest(
+	dataPlaneURI string,
+	testID string,
+	test map[string]interface{},
+) error {
+	if err := m.sendDataPlaneRequest(
+		dataPlaneURI,
+		fmt.Sprintf("/tests/%s", testID),
+		map[string]interface{}{},
+		[]autorest.PrepareDecorator{
+			autorest.AsPatch(),
+			autorest.AsContentType("application/merge-patch+json"),
+			autorest.WithJSON(test),
+		},
+		http.StatusOK,
+		http.StatusCreated,
+	); err != nil {
+		return fmt.Errorf(`error creating test "%s": %s`, testID, err)
+	}
+	return nil
+}
+
+func (m *manager) UploadTestScript(
+	dataPlaneURI string,
+	testID string,
+	fileName string,
+	script []byte,
+) error {
+	if err := m.sendDataPlaneRequest(
+		dataPlaneURI,
+		fmt.Sprintf("/tests/%s/files/%s", testID, fileName),
+		map[string]interface{}{
+			"fileType": "JMX_FILE",
+		},
+		[]autorest.PrepareDecorator{
+			autorest.AsPut(),
+			autorest.AsContentType("application/octet-stream"),
+			autorest.WithString(string(script)),
+		},
+		http.StatusOK,
+		http.StatusCreated,
+	); err != nil {
+		return fmt.Errorf(
+			`error uploading script for test "%s": %s`,
+			testID,
+			err,
+		)
+	}
+	return nil
+}
+
+func (m *manager) DeletePrivateEndpoint(
+	privateEndpointName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		az.ResourceReference{
+			SubscriptionID:    m.subscriptionID,
+			ResourceGroupName: resourceGroupName,
+			ProviderNamespace: "Microsoft.Network",
+			ResourceType:      "privateEndpoints",
+			ResourceName:      privateEndpointName,
+			APIVersion:        privateEndpointsAPIVersion,
+		},
+	); err != nil {
+		return fmt.Errorf("error deleting private endpoint: %s", err)
+	}
+	return nil
+}
+
+// sendDataPlaneRequest sends a request, prepared using the given decorators,
+// to the given path, with the given query parameters, on the data plane of a
+// load testing resource. Unlike Azure Resource Manager, the data plane only
+// accepts requests from principals holding a role on the resource itself.
+func (m *manager) sendDataPlaneRequest(
+	dataPlaneURI string,
+	path string,
+	queryParameters map[string]interface{},
+	decorators []autorest.PrepareDecorator,
+	expectedStatusCodes ...int,
+) error {
+	authorizer, err := az.GetBearerTokenAuthorizerForResource(
+		m.azureEnvironment,
+		m.tenantID,
+		m.clientID,
+		m.clientSecret,
+		dataPlaneResource,
+	)
+	if err != nil {
+		return fmt.Errorf("error getting bearer token authorizer: %s", err)
+	}
+	client := autorest.NewClientWithUserAgent(
+		fmt.Sprintf("open-service-broker/%s", version.GetVersion()),
+	)
+	client.Authorizer = authorizer
+	queryParameters["api-version"] = DataPlaneAPIVersion
+	decorators = append(
+		[]autorest.PrepareDecorator{
+			autorest.WithBaseURL(dataPlaneURI),
+			autorest.WithPath(path),
+			autorest.WithQueryParameters(queryParameters),
+		},
+		decorators...,
+	)
+	req, err := autorest.Prepare(&http.Request{}, decorators...)
+	if err != nil {
+		return fmt.Errorf("error preparing request: %s", err)
+	}
+	resp, err := autorest.SendWithSender(client, req)
+	if err != nil {
+		return fmt.Errorf("error sending request: %s", err)
+	}
+	if err := autorest.Respond(
+		resp,
+		client.ByInspecting(),
+		azure.WithErrorUnlessStatusCode(expectedStatusCodes...),
+		autorest.ByClosing(),
+	); err != nil {
+		return az.CategorizeError(err)
+	}
+	return nil
+}
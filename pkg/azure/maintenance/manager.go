@@ -0,0 +1,211 @@
+package maintenance
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/version"
+)
+
+const (
+	providerNamespace = "Microsoft.Maintenance"
+	resourceType      = "maintenanceConfigurations"
+	apiVersion        = "2023-04-01"
+)
+
+// Manager is an interface to be implemented by any component capable of
+// managing Azure Update Manager maintenance configurations and the resources
+// they apply to
+type Manager interface {
+	// AssignConfiguration applies the identified maintenance configuration to
+	// the identified resource by creating a configuration assignment, with the
+	// given name, beneath it. Assigning a configuration that is already
+	// assigned under the same name is not an error.
+	AssignConfiguration(
+		configurationID string,
+		resourceID string,
+		location string,
+		assignmentName string,
+	) error
+	// UnassignConfiguration deletes the named configuration assignment of the
+	// identified resource. Deleting an assignment that does not exist, or of a
+	// resource that no longer exists, is not an error.
+	UnassignConfiguration(resourceID string, assignmentName string) error
+	DeleteConfiguration(configurationName string, resourceGroupName string) error
+}
+
+type manager struct {
+	azureEnvironment azure.Environment
+	subscriptionID   string
+	tenantID         string
+	clientID         string
+	clientSecret     string
+	resourceClient   az.ResourceClient
+}
+
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	return &manager{
+		azureEnvironment: azureEnvironment,
+		subscriptionID:   azureConfig.SubscriptionID,
+		tenantID:         azureConfig.TenantID,
+		clientID:         azureConfig.ClientID,
+		clientSecret:     azureConfig.ClientSecret,
+		resourceClient: az.NewResourceClient(
+			azureEnvironment,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+	}, nil
+}
+
+func (m *manager) AssignConfiguration(
+	configurationID string,
+	resourceID string,
+	location string,
+	assignmentName string,
+) error {
+	if err := m.sendAssignmentRequest(
+		resourceID,
+		assignmentName,
+		[]autorest.PrepareDecorator{
+			autorest.AsPut(),
+			autorest.AsJSON(),
+			autorest.WithJSON(map[string]interface{}{
+				"location": location,
+				"properties": map[string]string{
+					"maintenanceConfigurationId": configurationID,
+					"resourceId":                 resourceID,
+				},
+			}),
+		},
+		http.StatusOK,
+		http.StatusCreated,
+	); err != nil {
+		return fmt.Errorf(
+			`error assigning maintenance configuration to resource "%s": %s`,
+			resourceID,
+			err,
+		)
+	}
+	return nil
+}
+
+func (m *manager) UnassignConfiguration(
+	resourceID string,
+	assignmentName string,
+) error {
+	if err := m.sendAssignmentRequest(
+		resourceID,
+		assignmentName,
+		[]autorest.PrepareDecorator{
+			autorest.AsDelete(),
+		},
+		http.StatusOK,
+		http.StatusNoContent,
+		http.StatusNotFound,
+	); err != nil {
+		return fmt.Errorf(
+			`error unassigning maintenance configuration from resource "%s": %s`,
+			resourceID,
+			err,
+		)
+	}
+	return nil
+}
+
+func (m *manager) DeleteConfiguration(
+	configurationName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		az.ResourceReference{
+			SubscriptionID:    m.subscriptionID,
+			ResourceGroupName: resourceGroupName,
+			ProviderNamespace: providerNamespace,
+			ResourceType:      resourceType,
+			ResourceName:      configurationName,
+			APIVersion:        apiVersion,
+		},
+	); err != nil {
+		return fmt.Errorf("error deleting maintenance configuration: %s", err)
+	}
+	return nil
+}
+
+// sendAssignmentRequest sends a request, prepared using the given decorators,
+// that addresses the named configuration assignment of the identified
+// resource. The generic resource client can't be used here because
+// configuration assignments are extension resources, whose IDs are nested
+// beneath that of the resource they're assigned to.
+func (m *manager) sendAssignmentRequest(
+	resourceID string,
+	assignmentName string,
+	decorators []autorest.PrepareDecorator,
+	expectedStatusCodes ...int,
+) error {
+	authorizer, err := az.GetBearerTokenAuthorizer(
+		m.azureEnvironment,
+		m.tenantID,
+		m.clientID,
+		m.clientSecret,
+	)
+	if err != nil {
+		return fmt.Errorf("error getting bearer token authorizer: %s", err)
+	}
+	client := autorest.NewClientWithUserAgent(
+		fmt.Sprintf("open-service-broker/%s", version.GetVersion()),
+	)
+	client.Authorizer = authorizer
+	decorators = append(
+		[]autorest.PrepareDecorator{
+			autorest.WithBaseURL(m.azureEnvironment.ResourceManagerEndpoint),
+			autorest.WithPath(
+				fmt.Sprintf(
+					"%s/providers/%s/configurationAssignments/%s",
+					strings.TrimSuffix(resourceID, "/"),
+					providerNamespace,
+					assignmentName,
+				),
+			),
+			autorest.WithQueryParameters(map[string]interface{}{
+				"api-version": apiVersion,
+			}),
+		},
+		decorators...,
+	)
+	req, err := autorest.Prepare(&http.Request{}, decorators...)
+	if err != nil {
+		return fmt.Errorf("error preparing request: %s", err)
+	}
+	resp, err := autorest.SendWithSender(client, req)
+	if err != nil {
+		return fmt.Errorf("error sending request: %s", err)
+	}
+	if err := autorest.Respond(
+		resp,
+		client.ByInspecting(),
+		azure.WithErrorUnlessStatusCode(expectedStatusCodes...),
+		autorest.ByClosing(),
+	); err != nil {
+		return az.CategorizeError(err)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package managedhsm
+
+import (
+	"crypto/rsa"
+	"crypto/sha1" // nolint: gas
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+)
+
+// NewSecurityDomainCertificate returns the SecurityDomainCertificate for the
+// given PEM-encoded X.509 certificate, which must have an RSA public key
+func NewSecurityDomainCertificate(
+	pemCertificate string,
+) (SecurityDomainCertificate, error) {
+	block, _ := pem.Decode([]byte(pemCertificate))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return SecurityDomainCertificate{},
+			errors.New("not a PEM-encoded certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return SecurityDomainCertificate{}, err
+	}
+	publicKey, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return SecurityDomainCertificate{},
+			errors.New("certificate does not have an RSA public key")
+	}
+	sha1Thumbprint := sha1.Sum(cert.Raw) // nolint: gas
+	sha256Thumbprint := sha256.Sum256(cert.Raw)
+	return SecurityDomainCertificate{
+		KeyType:       "RSA",
+		KeyOperations: []string{"verify", "encrypt", "wrapKey"},
+		Algorithm:     "RSA-OAEP-256",
+		Use:           "enc",
+		N:             base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+		E: base64.RawURLEncoding.EncodeToString(
+			big.NewInt(int64(publicKey.E)).Bytes(),
+		),
+		X5C:     []string{base64.StdEncoding.EncodeToString(cert.Raw)},
+		X5T:     base64.RawURLEncoding.EncodeToString(sha1Thumbprint[:]),
+		X5TS256: base64.RawURLEncoding.EncodeToString(sha256Thumbprint[:]),
+	}, nil
+}
@@ -0,0 +1,368 @@
+package managedhsm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/adal"
+	"github.com/Azure/go-autorest/autorest/azure"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/version"
+)
+
+const (
+	providerNamespace = "Microsoft.KeyVault"
+	resourceType      = "managedHSMs"
+	apiVersion        = "2021-10-01"
+
+	// dataPlaneResource is the resource that tokens used to authorize requests
+	// to a managed HSM's own endpoint (as opposed to Azure Resource Manager)
+	// must be issued for
+	dataPlaneResource   = "https://managedhsm.azure.net"
+	dataPlaneAPIVersion = "7.2"
+
+	activationPollingInterval = 15 * time.Second
+)
+
+// SecurityDomainCertificate is the JSON web key representation of one of the
+// certificates a managed HSM's security domain is encrypted to when the HSM
+// is activated
+type SecurityDomainCertificate struct {
+	KeyType       string   `json:"kty"`
+	KeyOperations []string `json:"key_ops"`
+	Algorithm     string   `json:"alg"`
+	Use           string   `json:"use"`
+	N             string   `json:"n"`
+	E             string   `json:"e"`
+	X5C           []string `json:"x5c"`
+	X5T           string   `json:"x5t"`
+	X5TS256       string   `json:"x5t#S256"`
+}
+
+// Manager is an interface to be implemented by any component capable of
+// managing Azure Managed HSM pools
+type Manager interface {
+	GetTenantID() string
+	// DownloadSecurityDomain activates the managed HSM at the given URI by
+	// requesting its security domain, encrypted to the given certificates, of
+	// which quorum are required to recover it. Activation continues after the
+	// encrypted security domain is returned; see WaitForActivation.
+	DownloadSecurityDomain(
+		hsmURI string,
+		certificates []SecurityDomainCertificate,
+		quorum int,
+	) (string, error)
+	// WaitForActivation blocks until the managed HSM at the given URI has been
+	// activated, activation has failed, or the context is canceled
+	WaitForActivation(ctx context.Context, hsmURI string) error
+	CreateRoleAssignment(
+		hsmURI string,
+		scope string,
+		roleAssignmentName string,
+		roleDefinitionID string,
+		principalID string,
+	) error
+	DeleteRoleAssignment(
+		hsmURI string,
+		scope string,
+		roleAssignmentName string,
+	) error
+	// DeleteHSM deletes the named managed HSM. If soft-delete is in effect, the
+	// HSM is retained, in a deleted state, until it is purged or its retention
+	// period elapses.
+	DeleteHSM(hsmName string, resourceGroupName string) error
+	// PurgeDeletedHSM permanently deletes the named, soft-deleted managed HSM.
+	// Purging an HSM that has purge protection enabled is refused by Azure.
+	PurgeDeletedHSM(hsmName string, location string) error
+}
+
+type manager struct {
+	azureEnvironment azure.Environment
+	subscriptionID   string
+	tenantID         string
+	clientID         string
+	clientSecret     string
+	resourceClient   az.ResourceClient
+}
+
+type securityDomainDownloadRequest struct {
+	Certificates []SecurityDomainCertificate `json:"certificates"`
+	Required     int                         `json:"required"`
+}
+
+type securityDomainDownloadResponse struct {
+	Value string `json:"value"`
+}
+
+type securityDomainOperationStatus struct {
+	Status        string `json:"status"`
+	StatusDetails string `json:"status_details"`
+}
+
+type roleAssignmentRequest struct {
+	Properties struct {
+		RoleDefinitionID string `json:"roleDefinitionId"`
+		PrincipalID      string `json:"principalId"`
+	} `json:"properties"`
+}
+
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	return &manager{
+		azureEnvironment: azureEnvironment,
+		subscriptionID:   azureConfig.SubscriptionID,
+		tenantID:         azureConfig.TenantID,
+		clientID:         azureConfig.ClientID,
+		clientSecret:     azureConfig.ClientSecret,
+		resourceClient: az.NewResourceClient(
+			azureEnvironment,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+	}, nil
+}
+
+func (m *manager) GetTenantID() string {
+	return m.tenantID
+}
+
+func (m *manager) DownloadSecurityDomain(
+	hsmURI string,
+	certificates []SecurityDomainCertificate,
+	quorum int,
+) (string, error) {
+	result := &securityDomainDownloadResponse{}
+	if err := m.sendDataPlaneRequest(
+		hsmURI,
+		autorest.AsPost(),
+		"/securitydomain/download",
+		&securityDomainDownloadRequest{
+			Certificates: certificates,
+			Required:     quorum,
+		},
+		result,
+		http.StatusOK,
+		http.StatusAccepted,
+	); err != nil {
+		return "", service.WrapError(err, "error downloading security domain")
+	}
+	return result.Value, nil
+}
+
+func (m *manager) WaitForActivation(ctx context.Context, hsmURI string) error {
+	ticker := time.NewTicker(activationPollingInterval)
+	defer ticker.Stop()
+	for {
+		status := &securityDomainOperationStatus{}
+		if err := m.sendDataPlaneRequest(
+			hsmURI,
+			autorest.AsGet(),
+			"/securitydomain/download/pending",
+			nil,
+			status,
+			http.StatusOK,
+		); err != nil {
+			return service.WrapError(err, "error checking activation status")
+		}
+		switch status.Status {
+		case "Success":
+			return nil
+		case "Failed":
+			return fmt.Errorf("activation failed: %s", status.StatusDetails)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (m *manager) CreateRoleAssignment(
+	hsmURI string,
+	scope string,
+	roleAssignmentName string,
+	roleDefinitionID string,
+	principalID string,
+) error {
+	body := &roleAssignmentRequest{}
+	body.Properties.RoleDefinitionID = roleDefinitionID
+	body.Properties.PrincipalID = principalID
+	if err := m.sendDataPlaneRequest(
+		hsmURI,
+		autorest.AsPut(),
+		getRoleAssignmentPath(scope, roleAssignmentName),
+		body,
+		nil,
+		http.StatusOK,
+		http.StatusCreated,
+	); err != nil {
+		return service.WrapError(err, "error creating role assignment")
+	}
+	return nil
+}
+
+func (m *manager) DeleteRoleAssignment(
+	hsmURI string,
+	scope string,
+	roleAssignmentName string,
+) error {
+	if err := m.sendDataPlaneRequest(
+		hsmURI,
+		autorest.AsDelete(),
+		getRoleAssignmentPath(scope, roleAssignmentName),
+		nil,
+		nil,
+		http.StatusOK,
+		http.StatusNoContent,
+		http.StatusNotFound,
+	); err != nil {
+		return service.WrapError(err, "error deleting role assignment")
+	}
+	return nil
+}
+
+func (m *manager) DeleteHSM(hsmName string, resourceGroupName string) error {
+	if err := m.resourceClient.DeleteResource(
+		az.ResourceReference{
+			SubscriptionID:    m.subscriptionID,
+			ResourceGroupName: resourceGroupName,
+			ProviderNamespace: providerNamespace,
+			ResourceType:      resourceType,
+			ResourceName:      hsmName,
+			APIVersion:        apiVersion,
+		},
+	); err != nil {
+		return service.WrapError(err, "error deleting managed HSM")
+	}
+	return nil
+}
+
+func (m *manager) PurgeDeletedHSM(hsmName string, location string) error {
+	if err := m.resourceClient.InvokeAction(
+		az.ResourceReference{
+			SubscriptionID:    m.subscriptionID,
+			ProviderNamespace: providerNamespace,
+			ResourceType: fmt.Sprintf(
+				"locations/%s/deletedManagedHSMs",
+				location,
+			),
+			ResourceName: hsmName,
+			APIVersion:   apiVersion,
+		},
+		"purge",
+		nil,
+		nil,
+	); err != nil {
+		return service.WrapError(err, "error purging deleted managed HSM")
+	}
+	return nil
+}
+
+// sendDataPlaneRequest sends a request to the given path relative to the
+// managed HSM at the given URI and unmarshals the response (if any) into the
+// provided result
+func (m *manager) sendDataPlaneRequest(
+	hsmURI string,
+	method autorest.PrepareDecorator,
+	path string,
+	body interface{},
+	result interface{},
+	expectedStatusCodes ...int,
+) error {
+	client, err := m.getDataPlaneClient()
+	if err != nil {
+		return err
+	}
+	decorators := []autorest.PrepareDecorator{
+		method,
+		autorest.WithBaseURL(strings.TrimSuffix(hsmURI, "/")),
+		autorest.WithPath(path),
+		autorest.WithQueryParameters(map[string]interface{}{
+			"api-version": dataPlaneAPIVersion,
+		}),
+	}
+	if body != nil {
+		decorators = append(
+			decorators,
+			autorest.AsJSON(),
+			autorest.WithJSON(body),
+		)
+	}
+	req, err := autorest.Prepare(&http.Request{}, decorators...)
+	if err != nil {
+		return fmt.Errorf("error preparing request: %s", err)
+	}
+	resp, err := autorest.SendWithSender(client, req)
+	if err != nil {
+		return fmt.Errorf("error sending request: %s", err)
+	}
+	responders := []autorest.RespondDecorator{
+		client.ByInspecting(),
+		azure.WithErrorUnlessStatusCode(expectedStatusCodes...),
+	}
+	if result != nil && resp.StatusCode != http.StatusNoContent {
+		responders = append(responders, autorest.ByUnmarshallingJSON(result))
+	}
+	responders = append(responders, autorest.ByClosing())
+	if err := autorest.Respond(resp, responders...); err != nil {
+		return az.CategorizeError(err)
+	}
+	return nil
+}
+
+func (m *manager) getDataPlaneClient() (autorest.Client, error) {
+	oauthConfig, err := adal.NewOAuthConfig(
+		m.azureEnvironment.ActiveDirectoryEndpoint,
+		m.tenantID,
+	)
+	if err != nil {
+		return autorest.Client{}, fmt.Errorf(
+			"error building oauth config: %s",
+			err,
+		)
+	}
+	spt, err := adal.NewServicePrincipalToken(
+		*oauthConfig,
+		m.clientID,
+		m.clientSecret,
+		dataPlaneResource,
+	)
+	if err != nil {
+		return autorest.Client{}, fmt.Errorf(
+			"error getting service principal token: %s",
+			err,
+		)
+	}
+	client := autorest.NewClientWithUserAgent(
+		fmt.Sprintf("open-service-broker/%s", version.GetVersion()),
+	)
+	client.Authorizer = autorest.NewBearerAuthorizer(spt)
+	return client, nil
+}
+
+func getRoleAssignmentPath(scope string, roleAssignmentName string) string {
+	return fmt.Sprintf(
+		"%s/providers/Microsoft.Authorization/roleAssignments/%s",
+		strings.TrimSuffix(scope, "/"),
+		roleAssignmentName,
+	)
+}
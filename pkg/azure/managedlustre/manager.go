@@ -0,0 +1,194 @@
+package managedlustre
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/version"
+)
+
+const (
+	storageCacheProviderNamespace = "Microsoft.StorageCache"
+	fileSystemType                = "amlFilesystems"
+	apiVersion                    = "2024-03-01"
+)
+
+// Manager is an interface to be implemented by any component capable of
+// managing Azure Managed Lustre file systems
+type Manager interface {
+	// CheckSubnet asks Azure whether a file system of the given SKU and storage
+	// capacity can be deployed, in the given location, into the subnet having
+	// the given resource ID. It returns Azure's reason why not, or an empty
+	// string if it can be.
+	CheckSubnet(
+		subnetID string,
+		location string,
+		sku string,
+		storageCapacityTiB int,
+	) (string, error)
+	DeleteFileSystem(
+		fileSystemName string,
+		resourceGroupName string,
+	) error
+}
+
+type manager struct {
+	azureEnvironment azure.Environment
+	subscriptionID   string
+	tenantID         string
+	clientID         string
+	clientSecret     string
+	resourceClient   az.ResourceClient
+}
+
+type checkSubnetRequest struct {
+	FilesystemSubnet   string `json:"filesystemSubnet"`
+	StorageCapacityTiB int    `json:"storageCapacityTiB"`
+	SKU                struct {
+		Name string `json:"name"`
+	} `json:"sku"`
+	Location string `json:"location"`
+}
+
+type checkSubnetError struct {
+	FilesystemSubnet struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+	} `json:"filesystemSubnet"`
+}
+
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	return &manager{
+		azureEnvironment: azureEnvironment,
+		subscriptionID:   azureConfig.SubscriptionID,
+		tenantID:         azureConfig.TenantID,
+		clientID:         azureConfig.ClientID,
+		clientSecret:     azureConfig.ClientSecret,
+		resourceClient: az.NewResourceClient(
+			azureEnvironment,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+	}, nil
+}
+
+// CheckSubnet invokes the subscription-level checkAmlFSSubnets action
+// directly, since the generic resource client only invokes actions of
+// individual resources. Azure answers a subnet that won't do with a 400 that
+// explains why; that isn't treated as an error.
+func (m *manager) CheckSubnet(
+	subnetID string,
+	location string,
+	sku string,
+	storageCapacityTiB int,
+) (string, error) {
+	authorizer, err := az.GetBearerTokenAuthorizer(
+		m.azureEnvironment,
+		m.tenantID,
+		m.clientID,
+		m.clientSecret,
+	)
+	if err != nil {
+		return "", fmt.Errorf("error getting bearer token authorizer: %s", err)
+	}
+	client := autorest.NewClientWithUserAgent(
+		fmt.Sprintf("open-service-broker/%s", version.GetVersion()),
+	)
+	client.Authorizer = authorizer
+	body := checkSubnetRequest{
+		FilesystemSubnet:   subnetID,
+		StorageCapacityTiB: storageCapacityTiB,
+		Location:           location,
+	}
+	body.SKU.Name = sku
+	req, err := autorest.Prepare(
+		&http.Request{},
+		autorest.AsPost(),
+		autorest.WithBaseURL(m.azureEnvironment.ResourceManagerEndpoint),
+		autorest.WithPathParameters(
+			"/subscriptions/{subscriptionId}/providers/{namespace}/"+
+				"checkAmlFSSubnets",
+			map[string]interface{}{
+				"subscriptionId": m.subscriptionID,
+				"namespace":      storageCacheProviderNamespace,
+			},
+		),
+		autorest.WithQueryParameters(map[string]interface{}{
+			"api-version": apiVersion,
+		}),
+		autorest.AsJSON(),
+		autorest.WithJSON(body),
+	)
+	if err != nil {
+		return "", fmt.Errorf(
+			"error preparing request to check Managed Lustre subnet: %s",
+			err,
+		)
+	}
+	resp, err := autorest.SendWithSender(client, req)
+	if err != nil {
+		return "", fmt.Errorf("error checking Managed Lustre subnet: %s", err)
+	}
+	if resp.StatusCode == http.StatusBadRequest {
+		result := &checkSubnetError{}
+		if err := autorest.Respond(
+			resp,
+			autorest.ByUnmarshallingJSON(result),
+			autorest.ByClosing(),
+		); err != nil {
+			return "", fmt.Errorf("error checking Managed Lustre subnet: %s", err)
+		}
+		if result.FilesystemSubnet.Message != "" {
+			return result.FilesystemSubnet.Message, nil
+		}
+		return fmt.Sprintf(
+			"the subnet's status is %s",
+			result.FilesystemSubnet.Status,
+		), nil
+	}
+	if err := autorest.Respond(
+		resp,
+		client.ByInspecting(),
+		azure.WithErrorUnlessStatusCode(http.StatusOK),
+		autorest.ByClosing(),
+	); err != nil {
+		return "", az.CategorizeError(err)
+	}
+	return "", nil
+}
+
+func (m *manager) DeleteFileSystem(
+	fileSystemName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		az.ResourceReference{
+			SubscriptionID:    m.subscriptionID,
+			ResourceGroupName: resourceGroupName,
+			ProviderNamespace: storageCacheProviderNamespace,
+			ResourceType:      fileSystemType,
+			ResourceName:      fileSystemName,
+			APIVersion:        apiVersion,
+		},
+	); err != nil {
+		return fmt.Errorf("error deleting Managed Lustre file system: %s", err)
+	}
+	return nil
+}
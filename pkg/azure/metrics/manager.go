@@ -0,0 +1,193 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/version"
+)
+
+const apiVersion = "2018-01-01"
+
+// Aggregation is how a metric's raw values are combined into a single value
+// for each interval
+type Aggregation string
+
+const (
+	// AggregationAverage combines a metric's values by averaging them
+	AggregationAverage Aggregation = "Average"
+	// AggregationMaximum combines a metric's values by taking the largest
+	AggregationMaximum Aggregation = "Maximum"
+	// AggregationTotal combines a metric's values by summing them
+	AggregationTotal Aggregation = "Total"
+)
+
+// Interval is the ISO 8601 duration of each of the intervals a metric's values
+// are aggregated over
+type Interval string
+
+const (
+	// IntervalHour aggregates a metric's values hour by hour
+	IntervalHour Interval = "PT1H"
+	// IntervalDay aggregates a metric's values day by day
+	IntervalDay Interval = "P1D"
+)
+
+// Manager is an interface to be implemented by any component capable of
+// retrieving Azure Monitor metrics
+type Manager interface {
+	// GetMetricValues returns the aggregated values of the named metric for the
+	// identified resource, one per interval, over the period ending now.
+	// Intervals for which no values were recorded are omitted, so an empty
+	// result means there is no data to go by.
+	GetMetricValues(
+		resourceID string,
+		metricName string,
+		aggregation Aggregation,
+		interval Interval,
+		period time.Duration,
+	) ([]float64, error)
+}
+
+type manager struct {
+	azureEnvironment azure.Environment
+	tenantID         string
+	clientID         string
+	clientSecret     string
+}
+
+type metricsResponse struct {
+	Value []struct {
+		Timeseries []struct {
+			Data []map[string]interface{} `json:"data"`
+		} `json:"timeseries"`
+	} `json:"value"`
+}
+
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	return &manager{
+		azureEnvironment: azureEnvironment,
+		tenantID:         azureConfig.TenantID,
+		clientID:         azureConfig.ClientID,
+		clientSecret:     azureConfig.ClientSecret,
+	}, nil
+}
+
+func (m *manager) GetMetricValues(
+	resourceID string,
+	metricName string,
+	aggregation Aggregation,
+	interval Interval,
+	period time.Duration,
+) ([]float64, error) {
+	client, err := m.getClient()
+	if err != nil {
+		return nil, err
+	}
+	end := time.Now().UTC()
+	start := end.Add(-period)
+	req, err := autorest.Prepare(
+		&http.Request{},
+		autorest.AsGet(),
+		autorest.WithBaseURL(m.azureEnvironment.ResourceManagerEndpoint),
+		autorest.WithPath(
+			fmt.Sprintf("%s/providers/Microsoft.Insights/metrics", resourceID),
+		),
+		autorest.WithQueryParameters(map[string]interface{}{
+			"api-version": apiVersion,
+			"metricnames": autorest.Encode("query", metricName),
+			"aggregation": string(aggregation),
+			"interval":    string(interval),
+			"timespan": fmt.Sprintf(
+				"%s/%s",
+				start.Format(time.RFC3339),
+				end.Format(time.RFC3339),
+			),
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error preparing request for metrics: %s", err)
+	}
+	resp, err := autorest.SendWithSender(client, req)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error getting metric "%s" for resource "%s": %s`,
+			metricName,
+			resourceID,
+			err,
+		)
+	}
+	result := &metricsResponse{}
+	if err := autorest.Respond(
+		resp,
+		client.ByInspecting(),
+		azure.WithErrorUnlessStatusCode(http.StatusOK),
+		autorest.ByUnmarshallingJSON(result),
+		autorest.ByClosing(),
+	); err != nil {
+		return nil, az.CategorizeError(err)
+	}
+	return getValues(result, aggregation), nil
+}
+
+func (m *manager) getClient() (autorest.Client, error) {
+	authorizer, err := az.GetBearerTokenAuthorizer(
+		m.azureEnvironment,
+		m.tenantID,
+		m.clientID,
+		m.clientSecret,
+	)
+	if err != nil {
+		return autorest.Client{}, fmt.Errorf(
+			"error getting bearer token authorizer: %s",
+			err,
+		)
+	}
+	client := autorest.NewClientWithUserAgent(
+		fmt.Sprintf("open-service-broker/%s", version.GetVersion()),
+	)
+	client.Authorizer = authorizer
+	return client, nil
+}
+
+// getValues extracts the requested aggregation from each of the data points in
+// the given response. Each data point is keyed by the lowercase name of each
+// aggregation that was requested and that had any values to aggregate.
+func getValues(
+	result *metricsResponse,
+	aggregation Aggregation,
+) []float64 {
+	key := map[Aggregation]string{
+		AggregationAverage: "average",
+		AggregationMaximum: "maximum",
+		AggregationTotal:   "total",
+	}[aggregation]
+	values := []float64{}
+	for _, metric := range result.Value {
+		for _, timeseries := range metric.Timeseries {
+			for _, data := range timeseries.Data {
+				if value, ok := data[key].(float64); ok {
+					values = append(values, value)
+				}
+			}
+		}
+	}
+	return values
+}
@@ -0,0 +1,310 @@
+package monitorworkspace
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/version"
+)
+
+const (
+	providerNamespace         = "Microsoft.Monitor"
+	resourceType              = "accounts"
+	apiVersion                = "2023-04-03"
+	insightsProviderNamespace = "Microsoft.Insights"
+	insightsAPIVersion        = "2022-06-01"
+	// configurationAccessEndpointAssociationName is the name Azure Monitor
+	// requires of the association through which a cluster's metrics agent finds
+	// the data collection endpoint it fetches its configuration from. A cluster
+	// can have only one such association.
+	configurationAccessEndpointAssociationName = "configurationAccessEndpoint"
+)
+
+// Manager is an interface to be implemented by any component capable of
+// managing an Azure Monitor workspace, the resources that feed metrics into
+// it, and access to it
+type Manager interface {
+	// CreateRoleAssignment assigns the role identified by the given (unqualified)
+	// role definition ID to the given principal at the given scope
+	CreateRoleAssignment(
+		scope string,
+		roleAssignmentName string,
+		roleDefinitionID string,
+		principalID string,
+	) error
+	// DeleteRoleAssignment deletes the named role assignment from the given
+	// scope. Deleting a role assignment that does not exist is not an error.
+	DeleteRoleAssignment(scope string, roleAssignmentName string) error
+	// LinkCluster associates the given AKS cluster with the given data
+	// collection rule and endpoint, so that the cluster's metrics agent scrapes
+	// Prometheus metrics into the workspace the rule sends them to
+	LinkCluster(
+		clusterID string,
+		associationName string,
+		dataCollectionRuleID string,
+		dataCollectionEndpointID string,
+	) error
+	// UnlinkCluster deletes the associations created by LinkCluster. Deleting
+	// associations that do not exist is not an error.
+	UnlinkCluster(clusterID string, associationName string) error
+	DeleteDataCollectionRule(
+		dataCollectionRuleName string,
+		resourceGroupName string,
+	) error
+	DeleteDataCollectionEndpoint(
+		dataCollectionEndpointName string,
+		resourceGroupName string,
+	) error
+	DeleteWorkspace(workspaceName string, resourceGroupName string) error
+}
+
+type manager struct {
+	azureEnvironment azure.Environment
+	subscriptionID   string
+	tenantID         string
+	clientID         string
+	clientSecret     string
+	resourceClient   az.ResourceClient
+	az.RoleAssignmentClient
+}
+
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	return &manager{
+		azureEnvironment: azureEnvironment,
+		subscriptionID:   azureConfig.SubscriptionID,
+		tenantID:         azureConfig.TenantID,
+		clientID:         azureConfig.ClientID,
+		clientSecret:     azureConfig.ClientSecret,
+		resourceClient: az.NewResourceClient(
+			azureEnvironment,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+		RoleAssignmentClient: az.NewRoleAssignmentClient(
+			azureEnvironment,
+			azureConfig.SubscriptionID,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+	}, nil
+}
+
+func (m *manager) LinkCluster(
+	clusterID string,
+	associationName string,
+	dataCollectionRuleID string,
+	dataCollectionEndpointID string,
+) error {
+	if err := m.sendExtensionResourceRequest(
+		autorest.AsPut(),
+		clusterID,
+		"Microsoft.Insights/dataCollectionRuleAssociations",
+		configurationAccessEndpointAssociationName,
+		insightsAPIVersion,
+		map[string]interface{}{
+			"properties": map[string]string{
+				"dataCollectionEndpointId": dataCollectionEndpointID,
+			},
+		},
+		http.StatusOK,
+		http.StatusCreated,
+	); err != nil {
+		return fmt.Errorf(
+			"error associating cluster with data collection endpoint: %s",
+			err,
+		)
+	}
+	if err := m.sendExtensionResourceRequest(
+		autorest.AsPut(),
+		clusterID,
+		"Microsoft.Insights/dataCollectionRuleAssociations",
+		associationName,
+		insightsAPIVersion,
+		map[string]interface{}{
+			"properties": map[string]string{
+				"dataCollectionRuleId": dataCollectionRuleID,
+			},
+		},
+		http.StatusOK,
+		http.StatusCreated,
+	); err != nil {
+		return fmt.Errorf(
+			"error associating cluster with data collection rule: %s",
+			err,
+		)
+	}
+	return nil
+}
+
+func (m *manager) UnlinkCluster(
+	clusterID string,
+	associationName string,
+) error {
+	for _, name := range []string{
+		associationName,
+		configurationAccessEndpointAssociationName,
+	} {
+		if err := m.sendExtensionResourceRequest(
+			autorest.AsDelete(),
+			clusterID,
+			"Microsoft.Insights/dataCollectionRuleAssociations",
+			name,
+			insightsAPIVersion,
+			nil,
+			http.StatusOK,
+			http.StatusNoContent,
+			http.StatusNotFound,
+		); err != nil {
+			return fmt.Errorf(
+				`error deleting data collection rule association "%s": %s`,
+				name,
+				err,
+			)
+		}
+	}
+	return nil
+}
+
+func (m *manager) DeleteDataCollectionRule(
+	dataCollectionRuleName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		az.ResourceReference{
+			SubscriptionID:    m.subscriptionID,
+			ResourceGroupName: resourceGroupName,
+			ProviderNamespace: insightsProviderNamespace,
+			ResourceType:      "dataCollectionRules",
+			ResourceName:      dataCollectionRuleName,
+			APIVersion:        insightsAPIVersion,
+		},
+	); err != nil {
+		return fmt.Errorf("error deleting data collection rule: %s", err)
+	}
+	return nil
+}
+
+func (m *manager) DeleteDataCollectionEndpoint(
+	dataCollectionEndpointName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		az.ResourceReference{
+			SubscriptionID:    m.subscriptionID,
+			ResourceGroupName: resourceGroupName,
+			ProviderNamespace: insightsProviderNamespace,
+			ResourceType:      "dataCollectionEndpoints",
+			ResourceName:      dataCollectionEndpointName,
+			APIVersion:        insightsAPIVersion,
+		},
+	); err != nil {
+		return fmt.Errorf("error deleting data collection endpoint: %s", err)
+	}
+	return nil
+}
+
+func (m *manager) DeleteWorkspace(
+	workspaceName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		az.ResourceReference{
+			SubscriptionID:    m.subscriptionID,
+			ResourceGroupName: resourceGroupName,
+			ProviderNamespace: providerNamespace,
+			ResourceType:      resourceType,
+			ResourceName:      workspaceName,
+			APIVersion:        apiVersion,
+		},
+	); err != nil {
+		return fmt.Errorf("error deleting Azure Monitor workspace: %s", err)
+	}
+	return nil
+}
+
+// sendExtensionResourceRequest sends a request to the Azure Resource Manager
+// endpoint for the named extension resource of the given type beneath the
+// given scope. Role assignments and data collection rule associations are
+// both extension resources, which the generic resource client can't address
+// because their IDs are nested beneath another resource's.
+func (m *manager) sendExtensionResourceRequest(
+	method autorest.PrepareDecorator,
+	scope string,
+	extensionType string,
+	extensionName string,
+	extensionAPIVersion string,
+	body interface{},
+	expectedStatusCodes ...int,
+) error {
+	authorizer, err := az.GetBearerTokenAuthorizer(
+		m.azureEnvironment,
+		m.tenantID,
+		m.clientID,
+		m.clientSecret,
+	)
+	if err != nil {
+		return fmt.Errorf("error getting bearer token authorizer: %s", err)
+	}
+	client := autorest.NewClientWithUserAgent(
+		fmt.Sprintf("open-service-broker/%s", version.GetVersion()),
+	)
+	client.Authorizer = authorizer
+	decorators := []autorest.PrepareDecorator{
+		method,
+		autorest.WithBaseURL(m.azureEnvironment.ResourceManagerEndpoint),
+		autorest.WithPath(
+			fmt.Sprintf(
+				"%s/providers/%s/%s",
+				strings.TrimSuffix(scope, "/"),
+				extensionType,
+				extensionName,
+			),
+		),
+		autorest.WithQueryParameters(map[string]interface{}{
+			"api-version": extensionAPIVersion,
+		}),
+	}
+	if body != nil {
+		decorators = append(
+			decorators,
+			autorest.AsJSON(),
+			autorest.WithJSON(body),
+		)
+	}
+	req, err := autorest.Prepare(&http.Request{}, decorators...)
+	if err != nil {
+		return fmt.Errorf("error preparing request: %s", err)
+	}
+	resp, err := autorest.SendWithSender(client, req)
+	if err != nil {
+		return fmt.Errorf("error sending request: %s", err)
+	}
+	if err := autorest.Respond(
+		resp,
+		client.ByInspecting(),
+		azure.WithErrorUnlessStatusCode(expectedStatusCodes...),
+		autorest.ByClosing(),
+	); err != nil {
+		return az.CategorizeError(err)
+	}
+	return nil
+}
@@ -25,9 +25,10 @@ type manager struct {
 	clientSecret     string
 }
 
-// NewManager returns a new implementation of the Manager interface
-func NewManager() (Manager, error) {
-	azureConfig, err := az.GetConfig()
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,373 @@
+package netappfiles
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest/azure"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+const (
+	providerNamespace    = "Microsoft.NetApp"
+	accountResourceType  = "netAppAccounts"
+	apiVersion           = "2023-07-01"
+	networkNamespace     = "Microsoft.Network"
+	networkAPIVersion    = "2023-09-01"
+	volumesDelegationKey = "Microsoft.NetApp/volumes"
+)
+
+// Account is a NetApp account
+type Account struct {
+	ID   string            `json:"id"`
+	Tags map[string]string `json:"tags"`
+}
+
+// CapacityPool is a capacity pool of a NetApp account
+type CapacityPool struct {
+	ID         string            `json:"id"`
+	Tags       map[string]string `json:"tags"`
+	Properties struct {
+		ServiceLevel string `json:"serviceLevel"`
+		// Size is in bytes
+		Size int64 `json:"size"`
+	} `json:"properties"`
+}
+
+// Manager is an interface to be implemented by any component capable of
+// managing Azure NetApp Files accounts, capacity pools and volumes
+type Manager interface {
+	// IsSubnetDelegated returns a bool indicating whether the subnet having the
+	// given resource ID is delegated to Azure NetApp Files, which volumes can
+	// only be placed in subnets that are. A subnet that doesn't exist isn't.
+	IsSubnetDelegated(subnetID string) (bool, error)
+	// GetAccount retrieves the named account. It returns a bool indicating
+	// whether the account was found.
+	GetAccount(
+		accountName string,
+		resourceGroupName string,
+	) (Account, bool, error)
+	// GetCapacityPool retrieves the named capacity pool. It returns a bool
+	// indicating whether the pool was found.
+	GetCapacityPool(
+		accountName string,
+		capacityPoolName string,
+		resourceGroupName string,
+	) (CapacityPool, bool, error)
+	// GetCapacityPoolNames returns the names of the named account's capacity
+	// pools
+	GetCapacityPoolNames(
+		accountName string,
+		resourceGroupName string,
+	) ([]string, error)
+	// GetVolumeNames returns the names of the named capacity pool's volumes
+	GetVolumeNames(
+		accountName string,
+		capacityPoolName string,
+		resourceGroupName string,
+	) ([]string, error)
+	DeleteVolume(
+		accountName string,
+		capacityPoolName string,
+		volumeName string,
+		resourceGroupName string,
+	) error
+	// DeleteCapacityPool deletes the named capacity pool. Azure refuses to
+	// delete a pool that still has volumes.
+	DeleteCapacityPool(
+		accountName string,
+		capacityPoolName string,
+		resourceGroupName string,
+	) error
+	// DeleteAccount deletes the named account. Azure refuses to delete an
+	// account that still has capacity pools.
+	DeleteAccount(accountName string, resourceGroupName string) error
+}
+
+type manager struct {
+	subscriptionID string
+	resourceClient az.ResourceClient
+}
+
+type subnet struct {
+	Properties struct {
+		Delegations []struct {
+			Properties struct {
+				ServiceName string `json:"serviceName"`
+			} `json:"properties"`
+		} `json:"delegations"`
+	} `json:"properties"`
+}
+
+type resourceList struct {
+	Value []struct {
+		Name string `json:"name"`
+	} `json:"value"`
+}
+
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	return &manager{
+		subscriptionID: azureConfig.SubscriptionID,
+		resourceClient: az.NewResourceClient(
+			azureEnvironment,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+	}, nil
+}
+
+func (m *manager) IsSubnetDelegated(subnetID string) (bool, error) {
+	id, ok := az.ParseSubnetID(subnetID)
+	if !ok {
+		return false, fmt.Errorf(`invalid subnet id: "%s"`, subnetID)
+	}
+	s := subnet{}
+	found, err := m.resourceClient.GetResource(
+		az.ResourceReference{
+			SubscriptionID:    id.SubscriptionID,
+			ResourceGroupName: id.ResourceGroupName,
+			ProviderNamespace: networkNamespace,
+			ResourceType: fmt.Sprintf(
+				"virtualNetworks/%s/subnets",
+				id.VirtualNetworkName,
+			),
+			ResourceName: id.SubnetName,
+			APIVersion:   networkAPIVersion,
+		},
+		&s,
+	)
+	if err != nil {
+		return false, service.WrapError(err, "error retrieving subnet")
+	}
+	if !found {
+		return false, nil
+	}
+	for _, delegation := range s.Properties.Delegations {
+		if strings.EqualFold(
+			delegation.Properties.ServiceName,
+			volumesDelegationKey,
+		) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *manager) GetAccount(
+	accountName string,
+	resourceGroupName string,
+) (Account, bool, error) {
+	account := Account{}
+	found, err := m.resourceClient.GetResource(
+		m.getAccountReference(accountName, resourceGroupName),
+		&account,
+	)
+	if err != nil {
+		return account, false, service.WrapError(
+			err,
+			"error retrieving NetApp account",
+		)
+	}
+	return account, found, nil
+}
+
+func (m *manager) GetCapacityPool(
+	accountName string,
+	capacityPoolName string,
+	resourceGroupName string,
+) (CapacityPool, bool, error) {
+	capacityPool := CapacityPool{}
+	found, err := m.resourceClient.GetResource(
+		m.getCapacityPoolReference(
+			accountName,
+			capacityPoolName,
+			resourceGroupName,
+		),
+		&capacityPool,
+	)
+	if err != nil {
+		return capacityPool, false, service.WrapError(
+			err,
+			"error retrieving NetApp capacity pool",
+		)
+	}
+	return capacityPool, found, nil
+}
+
+// GetCapacityPoolNames lists the account's capacity pools by getting the
+// collection of them as though it were a resource of its own, since the
+// generic resource client doesn't list resources
+func (m *manager) GetCapacityPoolNames(
+	accountName string,
+	resourceGroupName string,
+) ([]string, error) {
+	names, err := m.getChildNames(
+		m.getAccountReference(accountName, resourceGroupName),
+		"capacityPools",
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error listing NetApp capacity pools")
+	}
+	return names, nil
+}
+
+// GetVolumeNames lists the capacity pool's volumes the same way
+// GetCapacityPoolNames lists an account's capacity pools
+func (m *manager) GetVolumeNames(
+	accountName string,
+	capacityPoolName string,
+	resourceGroupName string,
+) ([]string, error) {
+	names, err := m.getChildNames(
+		m.getCapacityPoolReference(
+			accountName,
+			capacityPoolName,
+			resourceGroupName,
+		),
+		"volumes",
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error listing NetApp volumes")
+	}
+	return names, nil
+}
+
+func (m *manager) DeleteVolume(
+	accountName string,
+	capacityPoolName string,
+	volumeName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		az.ResourceReference{
+			SubscriptionID:    m.subscriptionID,
+			ResourceGroupName: resourceGroupName,
+			ProviderNamespace: providerNamespace,
+			ResourceType: fmt.Sprintf(
+				"%s/%s/capacityPools/%s/volumes",
+				accountResourceType,
+				accountName,
+				capacityPoolName,
+			),
+			ResourceName: volumeName,
+			APIVersion:   apiVersion,
+		},
+	); err != nil {
+		return service.WrapError(err, "error deleting NetApp volume")
+	}
+	return nil
+}
+
+func (m *manager) DeleteCapacityPool(
+	accountName string,
+	capacityPoolName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		m.getCapacityPoolReference(
+			accountName,
+			capacityPoolName,
+			resourceGroupName,
+		),
+	); err != nil {
+		return service.WrapError(err, "error deleting NetApp capacity pool")
+	}
+	return nil
+}
+
+func (m *manager) DeleteAccount(
+	accountName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		m.getAccountReference(accountName, resourceGroupName),
+	); err != nil {
+		return service.WrapError(err, "error deleting NetApp account")
+	}
+	return nil
+}
+
+// getChildNames returns the names of the referenced resource's child
+// resources of the given type. Azure names child resources after their
+// parents (e.g. "account/pool/volume"); only the last segment is returned.
+func (m *manager) getChildNames(
+	parent az.ResourceReference,
+	childType string,
+) ([]string, error) {
+	children := resourceList{}
+	found, err := m.resourceClient.GetResource(
+		az.ResourceReference{
+			SubscriptionID:    parent.SubscriptionID,
+			ResourceGroupName: parent.ResourceGroupName,
+			ProviderNamespace: parent.ProviderNamespace,
+			ResourceType: fmt.Sprintf(
+				"%s/%s",
+				parent.ResourceType,
+				parent.ResourceName,
+			),
+			ResourceName: childType,
+			APIVersion:   parent.APIVersion,
+		},
+		&children,
+	)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	if !found {
+		return names, nil
+	}
+	for _, child := range children.Value {
+		segments := strings.Split(child.Name, "/")
+		names = append(names, segments[len(segments)-1])
+	}
+	return names, nil
+}
+
+func (m *manager) getAccountReference(
+	accountName string,
+	resourceGroupName string,
+) az.ResourceReference {
+	return az.ResourceReference{
+		SubscriptionID:    m.subscriptionID,
+		ResourceGroupName: resourceGroupName,
+		ProviderNamespace: providerNamespace,
+		ResourceType:      accountResourceType,
+		ResourceName:      accountName,
+		APIVersion:        apiVersion,
+	}
+}
+
+func (m *manager) getCapacityPoolReference(
+	accountName string,
+	capacityPoolName string,
+	resourceGroupName string,
+) az.ResourceReference {
+	return az.ResourceReference{
+		SubscriptionID:    m.subscriptionID,
+		ResourceGroupName: resourceGroupName,
+		ProviderNamespace: providerNamespace,
+		ResourceType: fmt.Sprintf(
+			"%s/%s/capacityPools",
+			accountResourceType,
+			accountName,
+		),
+		ResourceName: capacityPoolName,
+		APIVersion:   apiVersion,
+	}
+}
@@ -0,0 +1,132 @@
+package orbital
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest/azure"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+)
+
+const (
+	providerNamespace = "Microsoft.Orbital"
+	apiVersion        = "2022-11-01"
+)
+
+// Manager is an interface to be implemented by any component capable of
+// managing Azure Orbital spacecraft registrations and contact profiles
+type Manager interface {
+	// GetAvailableGroundStationsURL returns the URL from which the ground
+	// stations that contacts may be scheduled with are listed
+	GetAvailableGroundStationsURL() string
+	// GetAvailableContactsURL returns the URL to which requests for the
+	// contacts that can be scheduled with the spacecraft having the given
+	// resource ID are posted
+	GetAvailableContactsURL(spacecraftID string) string
+	// GetContactsURL returns the URL of the collection of contacts scheduled
+	// with the spacecraft having the given resource ID
+	GetContactsURL(spacecraftID string) string
+	DeleteContactProfile(contactProfileName string, resourceGroupName string) error
+	DeleteSpacecraft(spacecraftName string, resourceGroupName string) error
+}
+
+type manager struct {
+	azureEnvironment azure.Environment
+	subscriptionID   string
+	resourceClient   az.ResourceClient
+}
+
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	return &manager{
+		azureEnvironment: azureEnvironment,
+		subscriptionID:   azureConfig.SubscriptionID,
+		resourceClient: az.NewResourceClient(
+			azureEnvironment,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+	}, nil
+}
+
+func (m *manager) GetAvailableGroundStationsURL() string {
+	return m.getURL(
+		fmt.Sprintf(
+			"/subscriptions/%s/providers/%s/availableGroundStations",
+			m.subscriptionID,
+			providerNamespace,
+		),
+		url.Values{"capability": []string{"EarthObservation"}},
+	)
+}
+
+func (m *manager) GetAvailableContactsURL(spacecraftID string) string {
+	return m.getURL(spacecraftID+"/listAvailableContacts", nil)
+}
+
+func (m *manager) GetContactsURL(spacecraftID string) string {
+	return m.getURL(spacecraftID+"/contacts", nil)
+}
+
+func (m *manager) DeleteContactProfile(
+	contactProfileName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		az.ResourceReference{
+			SubscriptionID:    m.subscriptionID,
+			ResourceGroupName: resourceGroupName,
+			ProviderNamespace: providerNamespace,
+			ResourceType:      "contactProfiles",
+			ResourceName:      contactProfileName,
+			APIVersion:        apiVersion,
+		},
+	); err != nil {
+		return fmt.Errorf("error deleting contact profile: %s", err)
+	}
+	return nil
+}
+
+func (m *manager) DeleteSpacecraft(
+	spacecraftName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		az.ResourceReference{
+			SubscriptionID:    m.subscriptionID,
+			ResourceGroupName: resourceGroupName,
+			ProviderNamespace: providerNamespace,
+			ResourceType:      "spacecrafts",
+			ResourceName:      spacecraftName,
+			APIVersion:        apiVersion,
+		},
+	); err != nil {
+		return fmt.Errorf("error deleting spacecraft: %s", err)
+	}
+	return nil
+}
+
+// getURL returns the Azure Resource Manager URL of the given path, qualified
+// by the given query and the API version
+func (m *manager) getURL(path string, query url.Values) string {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("api-version", apiVersion)
+	return strings.TrimSuffix(m.azureEnvironment.ResourceManagerEndpoint, "/") +
+		path + "?" + query.Encode()
+}
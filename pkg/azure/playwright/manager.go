@@ -0,0 +1,210 @@
+package playwright
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/version"
+)
+
+const (
+	providerNamespace = "Microsoft.AzurePlaywrightService"
+	resourceType      = "accounts"
+	apiVersion        = "2024-12-01"
+	// dataPlaneURIPattern is the pattern of the URI of the data plane of the
+	// region that a workspace is in
+	dataPlaneURIPattern = "https://%s.api.playwright.microsoft.com"
+)
+
+// AccessToken encapsulates an access token of a workspace, which Playwright
+// clients present to the service in place of an Azure AD identity
+type AccessToken struct {
+	ID       string `json:"id"`
+	JWTToken string `json:"jwtToken"`
+}
+
+// Manager is an interface to be implemented by any component capable of
+// managing an Azure Playwright Testing workspace and access to it
+type Manager interface {
+	// CreateAccessToken creates a named access token of the identified
+	// workspace, in the given region, that expires at the given time
+	CreateAccessToken(
+		location string,
+		workspaceID string,
+		name string,
+		expiresAt time.Time,
+	) (*AccessToken, error)
+	// DeleteAccessToken revokes the identified access token of the identified
+	// workspace. Deleting an access token that does not exist is not an error.
+	DeleteAccessToken(
+		location string,
+		workspaceID string,
+		accessTokenID string,
+	) error
+	DeleteWorkspace(workspaceName string, resourceGroupName string) error
+}
+
+type manager struct {
+	azureEnvironment azure.Environment
+	subscriptionID   string
+	tenantID         string
+	clientID         string
+	clientSecret     string
+	resourceClient   az.ResourceClient
+}
+
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	return &manager{
+		azureEnvironment: azureEnvironment,
+		subscriptionID:   azureConfig.SubscriptionID,
+		tenantID:         azureConfig.TenantID,
+		clientID:         azureConfig.ClientID,
+		clientSecret:     azureConfig.ClientSecret,
+		resourceClient: az.NewResourceClient(
+			azureEnvironment,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+	}, nil
+}
+
+func (m *manager) CreateAccessToken(
+	location string,
+	workspaceID string,
+	name string,
+	expiresAt time.Time,
+) (*AccessToken, error) {
+	accessToken := &AccessToken{}
+	if err := m.sendDataPlaneRequest(
+		location,
+		fmt.Sprintf("/accounts/%s/access-tokens", workspaceID),
+		[]autorest.PrepareDecorator{
+			autorest.AsPost(),
+			autorest.AsJSON(),
+			autorest.WithJSON(map[string]string{
+				"name":     name,
+				"expiryAt": expiresAt.UTC().Format(time.RFC3339),
+			}),
+		},
+		accessToken,
+		http.StatusOK,
+		http.StatusCreated,
+	); err != nil {
+		return nil, fmt.Errorf("error creating access token: %s", err)
+	}
+	return accessToken, nil
+}
+
+func (m *manager) DeleteAccessToken(
+	location string,
+	workspaceID string,
+	accessTokenID string,
+) error {
+	if err := m.sendDataPlaneRequest(
+		location,
+		fmt.Sprintf("/accounts/%s/access-tokens/%s", workspaceID, accessTokenID),
+		[]autorest.PrepareDecorator{
+			autorest.AsDelete(),
+		},
+		nil,
+		http.StatusOK,
+		http.StatusNoContent,
+		http.StatusNotFound,
+	); err != nil {
+		return fmt.Errorf("error deleting access token: %s", err)
+	}
+	return nil
+}
+
+func (m *manager) DeleteWorkspace(
+	workspaceName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		az.ResourceReference{
+			SubscriptionID:    m.subscriptionID,
+			ResourceGroupName: resourceGroupName,
+			ProviderNamespace: providerNamespace,
+			ResourceType:      resourceType,
+			ResourceName:      workspaceName,
+			APIVersion:        apiVersion,
+		},
+	); err != nil {
+		return fmt.Errorf("error deleting Playwright Testing workspace: %s", err)
+	}
+	return nil
+}
+
+// sendDataPlaneRequest sends a request, prepared using the given decorators,
+// to the given path on the data plane of the given region, and unmarshals the
+// response into result, if it's non-nil. The data plane accepts the same
+// tokens as Azure Resource Manager does.
+func (m *manager) sendDataPlaneRequest(
+	location string,
+	path string,
+	decorators []autorest.PrepareDecorator,
+	result interface{},
+	expectedStatusCodes ...int,
+) error {
+	authorizer, err := az.GetBearerTokenAuthorizer(
+		m.azureEnvironment,
+		m.tenantID,
+		m.clientID,
+		m.clientSecret,
+	)
+	if err != nil {
+		return fmt.Errorf("error getting bearer token authorizer: %s", err)
+	}
+	client := autorest.NewClientWithUserAgent(
+		fmt.Sprintf("open-service-broker/%s", version.GetVersion()),
+	)
+	client.Authorizer = authorizer
+	decorators = append(
+		[]autorest.PrepareDecorator{
+			autorest.WithBaseURL(fmt.Sprintf(dataPlaneURIPattern, location)),
+			autorest.WithPath(path),
+			autorest.WithQueryParameters(map[string]interface{}{
+				"api-version": apiVersion,
+			}),
+		},
+		decorators...,
+	)
+	req, err := autorest.Prepare(&http.Request{}, decorators...)
+	if err != nil {
+		return fmt.Errorf("error preparing request: %s", err)
+	}
+	resp, err := autorest.SendWithSender(client, req)
+	if err != nil {
+		return fmt.Errorf("error sending request: %s", err)
+	}
+	responders := []autorest.RespondDecorator{
+		client.ByInspecting(),
+		azure.WithErrorUnlessStatusCode(expectedStatusCodes...),
+	}
+	if result != nil {
+		responders = append(responders, autorest.ByUnmarshallingJSON(result))
+	}
+	responders = append(responders, autorest.ByClosing())
+	if err := autorest.Respond(resp, responders...); err != nil {
+		return az.CategorizeError(err)
+	}
+	return nil
+}
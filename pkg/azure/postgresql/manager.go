@@ -15,6 +15,28 @@ type Manager interface {
 		serverName string,
 		resourceGroupName string,
 	) error
+	// GetServerState returns the live state of the given server and of the
+	// firewall rule having the given name. The returned state's FirewallRule is
+	// nil if the server has no such rule.
+	GetServerState(
+		serverName string,
+		resourceGroupName string,
+		firewallRuleName string,
+	) (ServerState, error)
+}
+
+// ServerState encapsulates the settings of a server that are read back from
+// Azure to determine whether they've been changed since they were applied
+type ServerState struct {
+	SSLEnforcement string
+	FirewallRule   *FirewallRule
+}
+
+// FirewallRule encapsulates the range of client addresses a firewall rule
+// allows
+type FirewallRule struct {
+	StartIPAddress string
+	EndIPAddress   string
 }
 
 type manager struct {
@@ -25,9 +47,10 @@ type manager struct {
 	clientSecret     string
 }
 
-// NewManager returns a new implementation of the Manager interface
-func NewManager() (Manager, error) {
-	azureConfig, err := az.GetConfig()
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
 	if err != nil {
 		return nil, err
 	}
@@ -78,3 +101,63 @@ func (m *manager) DeleteServer(
 
 	return nil
 }
+
+func (m *manager) GetServerState(
+	serverName string,
+	resourceGroupName string,
+	firewallRuleName string,
+) (ServerState, error) {
+	state := ServerState{}
+	authorizer, err := az.GetBearerTokenAuthorizer(
+		m.azureEnvironment,
+		m.tenantID,
+		m.clientID,
+		m.clientSecret,
+	)
+	if err != nil {
+		return state, fmt.Errorf("error getting bearer token authorizer: %s", err)
+	}
+
+	serversClient := postgresql.NewServersClientWithBaseURI(
+		m.azureEnvironment.ResourceManagerEndpoint,
+		m.subscriptionID,
+	)
+	serversClient.Authorizer = authorizer
+	server, err := serversClient.Get(resourceGroupName, serverName)
+	if err != nil {
+		return state, fmt.Errorf("error getting postgresql server: %s", err)
+	}
+	if server.ServerProperties != nil {
+		state.SSLEnforcement = string(server.SslEnforcement)
+	}
+
+	firewallRulesClient := postgresql.NewFirewallRulesClientWithBaseURI(
+		m.azureEnvironment.ResourceManagerEndpoint,
+		m.subscriptionID,
+	)
+	firewallRulesClient.Authorizer = authorizer
+	rule, err := firewallRulesClient.Get(
+		resourceGroupName,
+		serverName,
+		firewallRuleName,
+	)
+	if az.IsNotFoundError(err) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf(
+			"error getting postgresql server firewall rule: %s",
+			err,
+		)
+	}
+	state.FirewallRule = &FirewallRule{}
+	if rule.FirewallRuleProperties != nil {
+		if rule.StartIPAddress != nil {
+			state.FirewallRule.StartIPAddress = *rule.StartIPAddress
+		}
+		if rule.EndIPAddress != nil {
+			state.FirewallRule.EndIPAddress = *rule.EndIPAddress
+		}
+	}
+	return state, nil
+}
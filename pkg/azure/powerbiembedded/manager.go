@@ -0,0 +1,166 @@
+package powerbiembedded
+
+import (
+	"fmt"
+
+	"github.com/Azure/go-autorest/autorest/azure"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+const (
+	providerNamespace = "Microsoft.PowerBIDedicated"
+	resourceType      = "capacities"
+	apiVersion        = "2021-01-01"
+)
+
+// Capacity describes the current state of a Power BI Embedded capacity
+type Capacity struct {
+	// State is, e.g., "Succeeded" (i.e. active), "Pausing", "Paused",
+	// "Resuming", "Scaling", or "Failed"
+	State             string
+	ProvisioningState string
+	SKU               string
+	Administrators    []string
+}
+
+// Manager is an interface to be implemented by any component capable of
+// managing Power BI Embedded capacities
+type Manager interface {
+	// GetCapacity retrieves the named capacity. It also returns a bool
+	// indicating whether the capacity was found.
+	GetCapacity(
+		capacityName string,
+		resourceGroupName string,
+	) (Capacity, bool, error)
+	// SuspendCapacity pauses the named capacity, which stops it being billed,
+	// and blocks until Azure reports that it has been paused
+	SuspendCapacity(capacityName string, resourceGroupName string) error
+	// ResumeCapacity resumes the named, paused capacity and blocks until Azure
+	// reports that it has been resumed
+	ResumeCapacity(capacityName string, resourceGroupName string) error
+	DeleteCapacity(capacityName string, resourceGroupName string) error
+}
+
+type manager struct {
+	subscriptionID string
+	resourceClient az.ResourceClient
+}
+
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	return &manager{
+		subscriptionID: azureConfig.SubscriptionID,
+		resourceClient: az.NewResourceClient(
+			azureEnvironment,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+	}, nil
+}
+
+func (m *manager) GetCapacity(
+	capacityName string,
+	resourceGroupName string,
+) (Capacity, bool, error) {
+	result := struct {
+		SKU struct {
+			Name string `json:"name"`
+		} `json:"sku"`
+		Properties struct {
+			State             string `json:"state"`
+			ProvisioningState string `json:"provisioningState"`
+			Administration    struct {
+				Members []string `json:"members"`
+			} `json:"administration"`
+		} `json:"properties"`
+	}{}
+	ok, err := m.resourceClient.GetResource(
+		m.getCapacityReference(capacityName, resourceGroupName),
+		&result,
+	)
+	if err != nil {
+		return Capacity{}, false, service.WrapError(
+			err,
+			"error retrieving Power BI Embedded capacity",
+		)
+	}
+	if !ok {
+		return Capacity{}, false, nil
+	}
+	return Capacity{
+		State:             result.Properties.State,
+		ProvisioningState: result.Properties.ProvisioningState,
+		SKU:               result.SKU.Name,
+		Administrators:    result.Properties.Administration.Members,
+	}, true, nil
+}
+
+func (m *manager) SuspendCapacity(
+	capacityName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.InvokeAction(
+		m.getCapacityReference(capacityName, resourceGroupName),
+		"suspend",
+		nil,
+		nil,
+	); err != nil {
+		return service.WrapError(err, "error pausing Power BI Embedded capacity")
+	}
+	return nil
+}
+
+func (m *manager) ResumeCapacity(
+	capacityName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.InvokeAction(
+		m.getCapacityReference(capacityName, resourceGroupName),
+		"resume",
+		nil,
+		nil,
+	); err != nil {
+		return service.WrapError(err, "error resuming Power BI Embedded capacity")
+	}
+	return nil
+}
+
+func (m *manager) DeleteCapacity(
+	capacityName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		m.getCapacityReference(capacityName, resourceGroupName),
+	); err != nil {
+		return fmt.Errorf("error deleting Power BI Embedded capacity: %s", err)
+	}
+	return nil
+}
+
+func (m *manager) getCapacityReference(
+	capacityName string,
+	resourceGroupName string,
+) az.ResourceReference {
+	return az.ResourceReference{
+		SubscriptionID:    m.subscriptionID,
+		ResourceGroupName: resourceGroupName,
+		ProviderNamespace: providerNamespace,
+		ResourceType:      resourceType,
+		ResourceName:      capacityName,
+		APIVersion:        apiVersion,
+	}
+}
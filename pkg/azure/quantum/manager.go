@@ -0,0 +1,142 @@
+package quantum
+
+import (
+	"fmt"
+
+	"github.com/Azure/go-autorest/autorest/azure"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+const (
+	providerNamespace        = "Microsoft.Quantum"
+	resourceType             = "workspaces"
+	apiVersion               = "2022-01-10-preview"
+	storageProviderNamespace = "Microsoft.Storage"
+	storageResourceType      = "storageAccounts"
+	storageAPIVersion        = "2021-09-01"
+)
+
+// StorageAccount is the subset of a storage account's properties that
+// determines whether a Quantum workspace can use it
+type StorageAccount struct {
+	Kind     string `json:"kind"`
+	Location string `json:"location"`
+}
+
+// Manager is an interface to be implemented by any component capable of
+// managing Azure Quantum workspaces and access to them
+type Manager interface {
+	GetTenantID() string
+	// GetStorageAccount retrieves the named storage account, which may belong to
+	// a subscription other than the broker's own. It returns a bool indicating
+	// whether the storage account was found.
+	GetStorageAccount(
+		subscriptionID string,
+		resourceGroupName string,
+		storageAccountName string,
+	) (StorageAccount, bool, error)
+	// CreateRoleAssignment assigns the role identified by the given (unqualified)
+	// role definition ID to the given principal at the scope of the resource
+	// having the given ID
+	CreateRoleAssignment(
+		scope string,
+		roleAssignmentName string,
+		roleDefinitionID string,
+		principalID string,
+	) error
+	// DeleteRoleAssignment deletes the named role assignment from the scope of
+	// the resource having the given ID. Deleting a role assignment that does
+	// not exist is not an error.
+	DeleteRoleAssignment(scope string, roleAssignmentName string) error
+	DeleteWorkspace(workspaceName string, resourceGroupName string) error
+}
+
+type manager struct {
+	subscriptionID string
+	tenantID       string
+	resourceClient az.ResourceClient
+	az.RoleAssignmentClient
+}
+
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	return &manager{
+		subscriptionID: azureConfig.SubscriptionID,
+		tenantID:       azureConfig.TenantID,
+		resourceClient: az.NewResourceClient(
+			azureEnvironment,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+		RoleAssignmentClient: az.NewRoleAssignmentClient(
+			azureEnvironment,
+			azureConfig.SubscriptionID,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+	}, nil
+}
+
+func (m *manager) GetTenantID() string {
+	return m.tenantID
+}
+
+func (m *manager) GetStorageAccount(
+	subscriptionID string,
+	resourceGroupName string,
+	storageAccountName string,
+) (StorageAccount, bool, error) {
+	storageAccount := StorageAccount{}
+	found, err := m.resourceClient.GetResource(
+		az.ResourceReference{
+			SubscriptionID:    subscriptionID,
+			ResourceGroupName: resourceGroupName,
+			ProviderNamespace: storageProviderNamespace,
+			ResourceType:      storageResourceType,
+			ResourceName:      storageAccountName,
+			APIVersion:        storageAPIVersion,
+		},
+		&storageAccount,
+	)
+	if err != nil {
+		return storageAccount, false, service.WrapError(
+			err,
+			"error retrieving storage account",
+		)
+	}
+	return storageAccount, found, nil
+}
+
+func (m *manager) DeleteWorkspace(
+	workspaceName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		az.ResourceReference{
+			SubscriptionID:    m.subscriptionID,
+			ResourceGroupName: resourceGroupName,
+			ProviderNamespace: providerNamespace,
+			ResourceType:      resourceType,
+			ResourceName:      workspaceName,
+			APIVersion:        apiVersion,
+		},
+	); err != nil {
+		return service.WrapError(err, "error deleting Quantum workspace")
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package azure
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/redaction"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// recording accumulates summaries of the requests that concern one resource
+// group
+type recording struct {
+	// subscriptionID and resourceGroup are lower case. An empty subscriptionID
+	// matches any subscription.
+	subscriptionID string
+	resourceGroup  string
+	requests       []service.AzureRequestSummary
+}
+
+// RequestRecorder summarizes the requests made to Azure that concern the
+// resource groups it has been asked to record. It fulfills the
+// service.AzureRequestRecorder interface. Requests are only observed by it if
+// they're sent using a transport returned from NewRecordingTransport.
+type RequestRecorder struct {
+	recordings map[*recording]struct{}
+	mutex      sync.Mutex
+}
+
+// NewRequestRecorder returns a new RequestRecorder
+func NewRequestRecorder() *RequestRecorder {
+	return &RequestRecorder{
+		recordings: map[*recording]struct{}{},
+	}
+}
+
+// StartRecording begins recording the requests made concerning the given
+// resource group of the given subscription. The function it returns stops
+// the recording and returns what was recorded.
+func (r *RequestRecorder) StartRecording(
+	subscriptionID string,
+	resourceGroup string,
+) func() []service.AzureRequestSummary {
+	rec := &recording{
+		subscriptionID: strings.ToLower(subscriptionID),
+		resourceGroup:  strings.ToLower(resourceGroup),
+		requests:       []service.AzureRequestSummary{},
+	}
+	r.mutex.Lock()
+	r.recordings[rec] = struct{}{}
+	r.mutex.Unlock()
+	return func() []service.AzureRequestSummary {
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+		delete(r.recordings, rec)
+		return rec.requests
+	}
+}
+
+// isRecording returns a bool indicating whether any recording is in progress
+func (r *RequestRecorder) isRecording() bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return len(r.recordings) > 0
+}
+
+// observe adds a summary of the given request, and of its response or the
+// error that was returned instead, to every recording of the resource group
+// the request concerns
+func (r *RequestRecorder) observe(
+	req *http.Request,
+	res *http.Response,
+	err error,
+	duration time.Duration,
+) {
+	subscriptionID, resourceGroup, ok := getResourceGroup(req.URL.Path)
+	if !ok {
+		return
+	}
+	summary := service.AzureRequestSummary{
+		Method:   req.Method,
+		URL:      getSummarizedURL(req.URL),
+		Duration: duration,
+	}
+	if err != nil {
+		// A transport's error typically includes the whole URL
+		summary.Error = redaction.RedactText(err.Error(), redaction.ModeFull)
+	} else if res != nil {
+		summary.StatusCode = res.StatusCode
+		summary.RequestID = res.Header.Get("x-ms-request-id")
+		summary.CorrelationID = res.Header.Get("x-ms-correlation-request-id")
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for rec := range r.recordings {
+		if rec.resourceGroup == resourceGroup &&
+			(rec.subscriptionID == "" || rec.subscriptionID == subscriptionID) {
+			rec.requests = append(rec.requests, summary)
+		}
+	}
+}
+
+// getResourceGroup returns the lower case subscription id and resource group
+// named by the given path of Azure Resource Manager's API, e.g.
+// /subscriptions/sub/resourceGroups/rg/providers/..., and a bool indicating
+// whether the path names a resource group at all
+func getResourceGroup(path string) (string, string, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 0; i+3 < len(segments); i++ {
+		if strings.EqualFold(segments[i], "subscriptions") &&
+			strings.EqualFold(segments[i+2], "resourceGroups") &&
+			segments[i+3] != "" {
+			return strings.ToLower(segments[i+1]),
+				strings.ToLower(segments[i+3]),
+				true
+		}
+	}
+	return "", "", false
+}
+
+// getSummarizedURL returns the given URL without its query string, which can
+// hold secrets such as SAS tokens, except for the api-version
+func getSummarizedURL(u *url.URL) string {
+	summarized := url.URL{
+		Scheme: u.Scheme,
+		Host:   u.Host,
+		Path:   u.Path,
+	}
+	if apiVersion := u.Query().Get("api-version"); apiVersion != "" {
+		summarized.RawQuery = url.Values{"api-version": {apiVersion}}.Encode()
+	}
+	return summarized.String()
+}
+
+type recordingTransport struct {
+	base     http.RoundTripper
+	recorder *RequestRecorder
+}
+
+// NewRecordingTransport returns an http.RoundTripper that sends requests
+// using the given base transport and lets the given recorder observe each
+// request and its outcome. While nothing is being recorded, requests are
+// passed straight through.
+func NewRecordingTransport(
+	base http.RoundTripper,
+	recorder *RequestRecorder,
+) http.RoundTripper {
+	return &recordingTransport{
+		base:     base,
+		recorder: recorder,
+	}
+}
+
+func (t *recordingTransport) RoundTrip(
+	req *http.Request,
+) (*http.Response, error) {
+	if !t.recorder.isRecording() {
+		return t.base.RoundTrip(req)
+	}
+	sent := time.Now()
+	res, err := t.base.RoundTrip(req)
+	t.recorder.observe(req, res, err, time.Since(sent))
+	return res, err
+}
@@ -0,0 +1,81 @@
+package azure
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type failingRoundTripper struct {
+	err error
+}
+
+func (f *failingRoundTripper) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, f.err
+}
+
+func TestRecordingTransportRecordsOnlyRecordedResourceGroups(t *testing.T) {
+	recorder := NewRequestRecorder()
+	transport := NewRecordingTransport(
+		&fakeRoundTripper{
+			res: &http.Response{
+				StatusCode: http.StatusOK,
+				Header: http.Header{
+					"X-Ms-Request-Id":             []string{"request"},
+					"X-Ms-Correlation-Request-Id": []string{"correlation"},
+				},
+			},
+		},
+		recorder,
+	)
+	stopRG := recorder.StartRecording("", "RG")
+	stopOther := recorder.StartRecording("sub", "other")
+	for _, rawURL := range []string{
+		"https://management.azure.com" + testComputePath +
+			"?api-version=2021-03-01&sig=secret",
+		"https://management.azure.com/subscriptions/sub/resourceGroups/foo",
+		"https://vault.azure.net/secrets/secret",
+	} {
+		req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+		assert.Nil(t, err)
+		_, err = transport.RoundTrip(req)
+		assert.Nil(t, err)
+	}
+	requests := stopRG()
+	assert.Len(t, requests, 1)
+	assert.Equal(t, http.MethodGet, requests[0].Method)
+	assert.Equal(
+		t,
+		"https://management.azure.com"+testComputePath+
+			"?api-version=2021-03-01",
+		requests[0].URL,
+	)
+	assert.Equal(t, http.StatusOK, requests[0].StatusCode)
+	assert.Equal(t, "request", requests[0].RequestID)
+	assert.Equal(t, "correlation", requests[0].CorrelationID)
+	assert.Empty(t, stopOther())
+	assert.False(t, recorder.isRecording())
+}
+
+func TestRecordingTransportRecordsFailedRequests(t *testing.T) {
+	recorder := NewRequestRecorder()
+	transport := NewRecordingTransport(
+		&failingRoundTripper{err: errors.New("connection reset")},
+		recorder,
+	)
+	stop := recorder.StartRecording("sub", "rg")
+	req, err := http.NewRequest(
+		http.MethodPut,
+		"https://management.azure.com"+testComputePath,
+		nil,
+	)
+	assert.Nil(t, err)
+	_, err = transport.RoundTrip(req)
+	assert.NotNil(t, err)
+	requests := stop()
+	assert.Len(t, requests, 1)
+	assert.Equal(t, "connection reset", requests[0].Error)
+	assert.Zero(t, requests[0].StatusCode)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/arm/redis"
 	"github.com/Azure/go-autorest/autorest/azure"
 	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
 
 // Manager is an interface to be implemented by any component capable of
@@ -15,8 +16,21 @@ type Manager interface {
 		serverName string,
 		resourceGroupName string,
 	) error
+	// SetPatchSchedule replaces the given server's patch schedule with one that
+	// lets Azure begin patching it at the given hour, UTC, on the given day of
+	// the week. Only Premium caches have patch schedules.
+	SetPatchSchedule(
+		serverName string,
+		resourceGroupName string,
+		dayOfWeek string,
+		startHourUTC int,
+	) error
 }
 
+// patchWindow is how long Azure may take to patch a server once it has
+// begun. It is the shortest window Azure permits.
+const patchWindow = "PT5H"
+
 type manager struct {
 	azureEnvironment azure.Environment
 	subscriptionID   string
@@ -25,9 +39,10 @@ type manager struct {
 	clientSecret     string
 }
 
-// NewManager returns a new implementation of the Manager interface
-func NewManager() (Manager, error) {
-	azureConfig, err := az.GetConfig()
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
 	if err != nil {
 		return nil, err
 	}
@@ -78,3 +93,51 @@ func (m *manager) DeleteServer(
 
 	return nil
 }
+
+func (m *manager) SetPatchSchedule(
+	serverName string,
+	resourceGroupName string,
+	dayOfWeek string,
+	startHourUTC int,
+) error {
+	authorizer, err := az.GetBearerTokenAuthorizer(
+		m.azureEnvironment,
+		m.tenantID,
+		m.clientID,
+		m.clientSecret,
+	)
+	if err != nil {
+		return fmt.Errorf("error getting bearer token authorizer: %s", err)
+	}
+
+	patchSchedulesClient := redis.NewPatchSchedulesClientWithBaseURI(
+		m.azureEnvironment.ResourceManagerEndpoint,
+		m.subscriptionID,
+	)
+	patchSchedulesClient.Authorizer = authorizer
+	start := int32(startHourUTC)
+	window := patchWindow
+	_, err = patchSchedulesClient.CreateOrUpdate(
+		resourceGroupName,
+		serverName,
+		redis.PatchSchedule{
+			ScheduleEntries: &redis.ScheduleEntries{
+				ScheduleEntries: &[]redis.ScheduleEntry{
+					{
+						DayOfWeek:         redis.DayOfWeek(dayOfWeek),
+						StartHourUtc:      &start,
+						MaintenanceWindow: &window,
+					},
+				},
+			},
+		},
+	)
+	if err != nil {
+		return service.WrapError(
+			az.CategorizeError(err),
+			"error setting redis server patch schedule",
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,400 @@
+package azure
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/version"
+)
+
+// ResourceReference identifies a single Azure resource along with the
+// api-version that should be used when addressing it. It is used by modules
+// whose resource types aren't (yet) supported by a purpose-built client in the
+// Azure SDK for Go.
+type ResourceReference struct {
+	SubscriptionID    string
+	ResourceGroupName string
+	ProviderNamespace string
+	ResourceType      string
+	ResourceName      string
+	APIVersion        string
+}
+
+// ID returns the fully qualified Azure resource ID of the referenced resource.
+// A reference that names no resource group is to a resource that belongs
+// directly to the subscription (e.g. a deleted resource awaiting purge).
+func (r ResourceReference) ID() string {
+	if r.ResourceGroupName == "" {
+		return fmt.Sprintf(
+			"/subscriptions/%s/providers/%s/%s/%s",
+			r.SubscriptionID,
+			r.ProviderNamespace,
+			r.ResourceType,
+			r.ResourceName,
+		)
+	}
+	return fmt.Sprintf(
+		"/subscriptions/%s/resourceGroups/%s/providers/%s/%s/%s",
+		r.SubscriptionID,
+		r.ResourceGroupName,
+		r.ProviderNamespace,
+		r.ResourceType,
+		r.ResourceName,
+	)
+}
+
+// ResourceClient is an interface to be implemented by any component capable
+// of carrying out generic operations against individual Azure resources
+type ResourceClient interface {
+	// GetResource retrieves the referenced resource and unmarshals it into the
+	// provided result. It returns a bool indicating whether the resource was
+	// found.
+	GetResource(ref ResourceReference, result interface{}) (bool, error)
+	// PutResource creates or updates the referenced resource using the provided
+	// body and blocks until Azure reports that the operation has completed. The
+	// resulting resource (if any) is unmarshaled into the provided result.
+	PutResource(ref ResourceReference, body interface{}, result interface{}) error
+	// PatchResource updates only those properties of the referenced resource
+	// that are included in the provided body and blocks until Azure reports
+	// that the operation has completed. The resulting resource (if any) is
+	// unmarshaled into the provided result.
+	PatchResource(
+		ref ResourceReference,
+		body interface{},
+		result interface{},
+	) error
+	// InvokeAction POSTs to the named action of the referenced resource (e.g.
+	// "listKeys" or "regenerateKey") and unmarshals the response (if any) into
+	// the provided result.
+	InvokeAction(
+		ref ResourceReference,
+		action string,
+		body interface{},
+		result interface{},
+	) error
+	// DeleteResource deletes the referenced resource and blocks until Azure
+	// reports that deletion has completed. Deleting a resource that does not
+	// exist is not an error.
+	DeleteResource(ref ResourceReference) error
+}
+
+type resourceClient struct {
+	azureEnvironment azure.Environment
+	tenantID         string
+	clientID         string
+	clientSecret     string
+}
+
+// NewResourceClient returns a new implementation of the ResourceClient
+// interface
+func NewResourceClient(
+	azureEnvironment azure.Environment,
+	tenantID string,
+	clientID string,
+	clientSecret string,
+) ResourceClient {
+	return &resourceClient{
+		azureEnvironment: azureEnvironment,
+		tenantID:         tenantID,
+		clientID:         clientID,
+		clientSecret:     clientSecret,
+	}
+}
+
+func (r *resourceClient) GetResource(
+	ref ResourceReference,
+	result interface{},
+) (bool, error) {
+	client, err := r.getClient()
+	if err != nil {
+		return false, err
+	}
+	req, err := autorest.Prepare(
+		&http.Request{},
+		autorest.AsGet(),
+		autorest.WithBaseURL(r.azureEnvironment.ResourceManagerEndpoint),
+		autorest.WithPath(ref.ID()),
+		autorest.WithQueryParameters(getAPIVersionQueryParameters(ref)),
+	)
+	if err != nil {
+		return false, fmt.Errorf(
+			`error preparing request to get resource "%s": %s`,
+			ref.ID(),
+			err,
+		)
+	}
+	resp, err := autorest.SendWithSender(client, req)
+	if err != nil {
+		return false, fmt.Errorf(`error getting resource "%s": %s`, ref.ID(), err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return false, autorest.Respond(resp, autorest.ByClosing())
+	}
+	if err := autorest.Respond(
+		resp,
+		client.ByInspecting(),
+		azure.WithErrorUnlessStatusCode(http.StatusOK),
+		autorest.ByUnmarshallingJSON(result),
+		autorest.ByClosing(),
+	); err != nil {
+		return false, service.WrapError(
+			CategorizeError(err),
+			fmt.Sprintf(`error getting resource "%s"`, ref.ID()),
+		)
+	}
+	return true, nil
+}
+
+func (r *resourceClient) PutResource(
+	ref ResourceReference,
+	body interface{},
+	result interface{},
+) error {
+	client, err := r.getClient()
+	if err != nil {
+		return err
+	}
+	req, err := autorest.Prepare(
+		&http.Request{},
+		autorest.AsPut(),
+		autorest.AsJSON(),
+		autorest.WithBaseURL(r.azureEnvironment.ResourceManagerEndpoint),
+		autorest.WithPath(ref.ID()),
+		autorest.WithQueryParameters(getAPIVersionQueryParameters(ref)),
+		autorest.WithJSON(body),
+	)
+	if err != nil {
+		return fmt.Errorf(
+			`error preparing request to put resource "%s": %s`,
+			ref.ID(),
+			err,
+		)
+	}
+	resp, err := autorest.SendWithSender(
+		client,
+		req,
+		azure.DoPollForAsynchronous(client.PollingDelay),
+	)
+	if err != nil {
+		return fmt.Errorf(`error putting resource "%s": %s`, ref.ID(), err)
+	}
+	responders := []autorest.RespondDecorator{
+		client.ByInspecting(),
+		azure.WithErrorUnlessStatusCode(
+			http.StatusOK,
+			http.StatusCreated,
+			http.StatusAccepted,
+		),
+	}
+	if result != nil {
+		responders = append(responders, autorest.ByUnmarshallingJSON(result))
+	}
+	responders = append(responders, autorest.ByClosing())
+	if err := autorest.Respond(resp, responders...); err != nil {
+		return service.WrapError(
+			CategorizeError(err),
+			fmt.Sprintf(`error putting resource "%s"`, ref.ID()),
+		)
+	}
+	return nil
+}
+
+func (r *resourceClient) PatchResource(
+	ref ResourceReference,
+	body interface{},
+	result interface{},
+) error {
+	client, err := r.getClient()
+	if err != nil {
+		return err
+	}
+	req, err := autorest.Prepare(
+		&http.Request{},
+		autorest.AsPatch(),
+		autorest.AsJSON(),
+		autorest.WithBaseURL(r.azureEnvironment.ResourceManagerEndpoint),
+		autorest.WithPath(ref.ID()),
+		autorest.WithQueryParameters(getAPIVersionQueryParameters(ref)),
+		autorest.WithJSON(body),
+	)
+	if err != nil {
+		return fmt.Errorf(
+			`error preparing request to patch resource "%s": %s`,
+			ref.ID(),
+			err,
+		)
+	}
+	resp, err := autorest.SendWithSender(
+		client,
+		req,
+		azure.DoPollForAsynchronous(client.PollingDelay),
+	)
+	if err != nil {
+		return fmt.Errorf(`error patching resource "%s": %s`, ref.ID(), err)
+	}
+	responders := []autorest.RespondDecorator{
+		client.ByInspecting(),
+		azure.WithErrorUnlessStatusCode(
+			http.StatusOK,
+			http.StatusAccepted,
+		),
+	}
+	if result != nil {
+		responders = append(responders, autorest.ByUnmarshallingJSON(result))
+	}
+	responders = append(responders, autorest.ByClosing())
+	if err := autorest.Respond(resp, responders...); err != nil {
+		return service.WrapError(
+			CategorizeError(err),
+			fmt.Sprintf(`error patching resource "%s"`, ref.ID()),
+		)
+	}
+	return nil
+}
+
+func (r *resourceClient) InvokeAction(
+	ref ResourceReference,
+	action string,
+	body interface{},
+	result interface{},
+) error {
+	client, err := r.getClient()
+	if err != nil {
+		return err
+	}
+	decorators := []autorest.PrepareDecorator{
+		autorest.AsPost(),
+		autorest.WithBaseURL(r.azureEnvironment.ResourceManagerEndpoint),
+		autorest.WithPath(fmt.Sprintf("%s/%s", ref.ID(), action)),
+		autorest.WithQueryParameters(getAPIVersionQueryParameters(ref)),
+	}
+	if body != nil {
+		decorators = append(
+			decorators,
+			autorest.AsJSON(),
+			autorest.WithJSON(body),
+		)
+	}
+	req, err := autorest.Prepare(&http.Request{}, decorators...)
+	if err != nil {
+		return fmt.Errorf(
+			`error preparing request to invoke action "%s" on resource "%s": %s`,
+			action,
+			ref.ID(),
+			err,
+		)
+	}
+	resp, err := autorest.SendWithSender(
+		client,
+		req,
+		azure.DoPollForAsynchronous(client.PollingDelay),
+	)
+	if err != nil {
+		return fmt.Errorf(
+			`error invoking action "%s" on resource "%s": %s`,
+			action,
+			ref.ID(),
+			err,
+		)
+	}
+	responders := []autorest.RespondDecorator{
+		client.ByInspecting(),
+		azure.WithErrorUnlessStatusCode(
+			http.StatusOK,
+			http.StatusAccepted,
+			http.StatusNoContent,
+		),
+	}
+	if result != nil {
+		responders = append(responders, autorest.ByUnmarshallingJSON(result))
+	}
+	responders = append(responders, autorest.ByClosing())
+	if err := autorest.Respond(resp, responders...); err != nil {
+		return service.WrapError(
+			CategorizeError(err),
+			fmt.Sprintf(
+				`error invoking action "%s" on resource "%s"`,
+				action,
+				ref.ID(),
+			),
+		)
+	}
+	return nil
+}
+
+func (r *resourceClient) DeleteResource(ref ResourceReference) error {
+	client, err := r.getClient()
+	if err != nil {
+		return err
+	}
+	req, err := autorest.Prepare(
+		&http.Request{},
+		autorest.AsDelete(),
+		autorest.WithBaseURL(r.azureEnvironment.ResourceManagerEndpoint),
+		autorest.WithPath(ref.ID()),
+		autorest.WithQueryParameters(getAPIVersionQueryParameters(ref)),
+	)
+	if err != nil {
+		return fmt.Errorf(
+			`error preparing request to delete resource "%s": %s`,
+			ref.ID(),
+			err,
+		)
+	}
+	resp, err := autorest.SendWithSender(
+		client,
+		req,
+		azure.DoPollForAsynchronous(client.PollingDelay),
+	)
+	if err != nil {
+		return fmt.Errorf(`error deleting resource "%s": %s`, ref.ID(), err)
+	}
+	if err := autorest.Respond(
+		resp,
+		client.ByInspecting(),
+		azure.WithErrorUnlessStatusCode(
+			http.StatusOK,
+			http.StatusAccepted,
+			http.StatusNoContent,
+			http.StatusNotFound,
+		),
+		autorest.ByClosing(),
+	); err != nil {
+		return service.WrapError(
+			CategorizeError(err),
+			fmt.Sprintf(`error deleting resource "%s"`, ref.ID()),
+		)
+	}
+	return nil
+}
+
+func (r *resourceClient) getClient() (autorest.Client, error) {
+	authorizer, err := GetBearerTokenAuthorizer(
+		r.azureEnvironment,
+		r.tenantID,
+		r.clientID,
+		r.clientSecret,
+	)
+	if err != nil {
+		return autorest.Client{}, fmt.Errorf(
+			"error getting bearer token authorizer: %s",
+			err,
+		)
+	}
+	client := autorest.NewClientWithUserAgent(
+		fmt.Sprintf("open-service-broker/%s", version.GetVersion()),
+	)
+	client.Authorizer = authorizer
+	return client, nil
+}
+
+func getAPIVersionQueryParameters(
+	ref ResourceReference,
+) map[string]interface{} {
+	return map[string]interface{}{
+		"api-version": ref.APIVersion,
+	}
+}
@@ -0,0 +1,175 @@
+package azure
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+)
+
+const (
+	roleAssignmentsAPIVersion   = "2022-04-01"
+	roleDefinitionIDPathPattern = "/subscriptions/%s/providers/Microsoft.Authorization/roleDefinitions/%s" // nolint: lll
+)
+
+// RoleAssignmentClient is an interface to be implemented by any component
+// capable of assigning Azure RBAC roles to principals at the scope of
+// individual Azure resources
+type RoleAssignmentClient interface {
+	// CreateRoleAssignment assigns the role identified by the given
+	// (unqualified) role definition ID to the given principal at the given
+	// scope, e.g. a resource ID
+	CreateRoleAssignment(
+		scope string,
+		roleAssignmentName string,
+		roleDefinitionID string,
+		principalID string,
+	) error
+	// DeleteRoleAssignment deletes the named role assignment from the given
+	// scope. Deleting a role assignment that does not exist is not an error.
+	DeleteRoleAssignment(scope string, roleAssignmentName string) error
+}
+
+type roleAssignmentClient struct {
+	resourceClient
+	subscriptionID string
+}
+
+// NewRoleAssignmentClient returns a new implementation of the
+// RoleAssignmentClient interface. Role definitions are qualified by the
+// subscription of the scope they're assigned at or, if the scope doesn't
+// identify one, by the specified subscription.
+func NewRoleAssignmentClient(
+	azureEnvironment azure.Environment,
+	subscriptionID string,
+	tenantID string,
+	clientID string,
+	clientSecret string,
+) RoleAssignmentClient {
+	return &roleAssignmentClient{
+		resourceClient: resourceClient{
+			azureEnvironment: azureEnvironment,
+			tenantID:         tenantID,
+			clientID:         clientID,
+			clientSecret:     clientSecret,
+		},
+		subscriptionID: subscriptionID,
+	}
+}
+
+func (r *roleAssignmentClient) CreateRoleAssignment(
+	scope string,
+	roleAssignmentName string,
+	roleDefinitionID string,
+	principalID string,
+) error {
+	subscriptionID := getSubscriptionIDFromScope(scope)
+	if subscriptionID == "" {
+		subscriptionID = r.subscriptionID
+	}
+	if err := r.sendRoleAssignmentRequest(
+		autorest.AsPut(),
+		scope,
+		roleAssignmentName,
+		map[string]interface{}{
+			"properties": map[string]string{
+				"roleDefinitionId": fmt.Sprintf(
+					roleDefinitionIDPathPattern,
+					subscriptionID,
+					roleDefinitionID,
+				),
+				"principalId": principalID,
+			},
+		},
+		http.StatusOK,
+		http.StatusCreated,
+	); err != nil {
+		return fmt.Errorf("error creating role assignment: %s", err)
+	}
+	return nil
+}
+
+func (r *roleAssignmentClient) DeleteRoleAssignment(
+	scope string,
+	roleAssignmentName string,
+) error {
+	if err := r.sendRoleAssignmentRequest(
+		autorest.AsDelete(),
+		scope,
+		roleAssignmentName,
+		nil,
+		http.StatusOK,
+		http.StatusNoContent,
+		http.StatusNotFound,
+	); err != nil {
+		return fmt.Errorf("error deleting role assignment: %s", err)
+	}
+	return nil
+}
+
+// sendRoleAssignmentRequest sends a request to the Azure Resource Manager
+// endpoint for the named role assignment at the given scope. Role assignments
+// are extension resources, whose IDs are nested beneath another resource's, so
+// they can't be addressed using a ResourceReference.
+func (r *roleAssignmentClient) sendRoleAssignmentRequest(
+	method autorest.PrepareDecorator,
+	scope string,
+	roleAssignmentName string,
+	body interface{},
+	expectedStatusCodes ...int,
+) error {
+	client, err := r.getClient()
+	if err != nil {
+		return err
+	}
+	decorators := []autorest.PrepareDecorator{
+		method,
+		autorest.WithBaseURL(r.azureEnvironment.ResourceManagerEndpoint),
+		autorest.WithPath(
+			fmt.Sprintf(
+				"%s/providers/Microsoft.Authorization/roleAssignments/%s",
+				strings.TrimSuffix(scope, "/"),
+				roleAssignmentName,
+			),
+		),
+		autorest.WithQueryParameters(map[string]interface{}{
+			"api-version": roleAssignmentsAPIVersion,
+		}),
+	}
+	if body != nil {
+		decorators = append(
+			decorators,
+			autorest.AsJSON(),
+			autorest.WithJSON(body),
+		)
+	}
+	req, err := autorest.Prepare(&http.Request{}, decorators...)
+	if err != nil {
+		return fmt.Errorf("error preparing request: %s", err)
+	}
+	resp, err := autorest.SendWithSender(client, req)
+	if err != nil {
+		return fmt.Errorf("error sending request: %s", err)
+	}
+	if err := autorest.Respond(
+		resp,
+		client.ByInspecting(),
+		azure.WithErrorUnlessStatusCode(expectedStatusCodes...),
+		autorest.ByClosing(),
+	); err != nil {
+		return CategorizeError(err)
+	}
+	return nil
+}
+
+// getSubscriptionIDFromScope returns the ID of the subscription that the
+// given scope belongs to, or an empty string if it doesn't identify one
+func getSubscriptionIDFromScope(scope string) string {
+	parts := strings.Split(scope, "/")
+	if len(parts) < 3 || !strings.EqualFold(parts[1], "subscriptions") {
+		return ""
+	}
+	return parts[2]
+}
@@ -0,0 +1,20 @@
+package azure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSubscriptionIDFromScope(t *testing.T) {
+	assert.Equal(
+		t,
+		"sub",
+		getSubscriptionIDFromScope(
+			"/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Storage/"+
+				"storageAccounts/account",
+		),
+	)
+	assert.Equal(t, "sub", getSubscriptionIDFromScope("/subscriptions/sub"))
+	assert.Equal(t, "", getSubscriptionIDFromScope("bogus"))
+}
@@ -6,11 +6,41 @@ import (
 	"github.com/Azure/azure-sdk-for-go/arm/storage"
 	"github.com/Azure/go-autorest/autorest/azure"
 	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+const (
+	providerNamespace      = "Microsoft.Storage"
+	accountResourceType    = "storageAccounts"
+	accountAPIVersion      = "2021-09-01"
+	primaryAccessKeyName   = "key1"
+	secondaryAccessKeyName = "key2"
 )
 
 // Manager is an interface to be implemented by any component capable of
 // managing Azure Storage Accounts
 type Manager interface {
+	// GetPublicNetworkAccess returns whether the named storage account accepts
+	// traffic from public networks ("Enabled" or "Disabled"). Accounts created
+	// before the setting existed may report an empty string, which Azure
+	// treats as "Enabled".
+	GetPublicNetworkAccess(
+		storageAccountName string,
+		resourceGroupName string,
+	) (string, error)
+	// SetPublicNetworkAccess sets whether the named storage account accepts
+	// traffic from public networks
+	SetPublicNetworkAccess(
+		storageAccountName string,
+		resourceGroupName string,
+		publicNetworkAccess string,
+	) error
+	// RegenerateAccessKeys replaces both of the named storage account's access
+	// keys and returns the new primary key
+	RegenerateAccessKeys(
+		storageAccountName string,
+		resourceGroupName string,
+	) (string, error)
 	DeleteStorageAccount(
 		storageAccountName string,
 		resourceGroupName string,
@@ -23,11 +53,28 @@ type manager struct {
 	tenantID         string
 	clientID         string
 	clientSecret     string
+	resourceClient   az.ResourceClient
 }
 
-// NewManager returns a new implementation of the Manager interface
-func NewManager() (Manager, error) {
-	azureConfig, err := az.GetConfig()
+type account struct {
+	Properties accountProperties `json:"properties"`
+}
+
+type accountProperties struct {
+	PublicNetworkAccess string `json:"publicNetworkAccess,omitempty"`
+}
+
+type accountKeyList struct {
+	Keys []struct {
+		KeyName string `json:"keyName"`
+		Value   string `json:"value"`
+	} `json:"keys"`
+}
+
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
 	if err != nil {
 		return nil, err
 	}
@@ -44,9 +91,93 @@ func NewManager() (Manager, error) {
 		tenantID:         azureConfig.TenantID,
 		clientID:         azureConfig.ClientID,
 		clientSecret:     azureConfig.ClientSecret,
+		resourceClient: az.NewResourceClient(
+			azureEnvironment,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
 	}, nil
 }
 
+func (m *manager) GetPublicNetworkAccess(
+	storageAccountName string,
+	resourceGroupName string,
+) (string, error) {
+	a := account{}
+	found, err := m.resourceClient.GetResource(
+		m.getAccountReference(storageAccountName, resourceGroupName),
+		&a,
+	)
+	if err != nil {
+		return "", service.WrapError(err, "error retrieving storage account")
+	}
+	if !found {
+		return "", fmt.Errorf(
+			`storage account "%s" not found`,
+			storageAccountName,
+		)
+	}
+	return a.Properties.PublicNetworkAccess, nil
+}
+
+func (m *manager) SetPublicNetworkAccess(
+	storageAccountName string,
+	resourceGroupName string,
+	publicNetworkAccess string,
+) error {
+	if err := m.resourceClient.PatchResource(
+		m.getAccountReference(storageAccountName, resourceGroupName),
+		account{
+			Properties: accountProperties{
+				PublicNetworkAccess: publicNetworkAccess,
+			},
+		},
+		nil,
+	); err != nil {
+		return service.WrapError(
+			err,
+			"error setting storage account public network access",
+		)
+	}
+	return nil
+}
+
+// RegenerateAccessKeys regenerates the secondary key as well as the primary
+// one because anyone who held the primary key may also have listed the other.
+func (m *manager) RegenerateAccessKeys(
+	storageAccountName string,
+	resourceGroupName string,
+) (string, error) {
+	keys := accountKeyList{}
+	for _, keyName := range []string{
+		secondaryAccessKeyName,
+		primaryAccessKeyName,
+	} {
+		if err := m.resourceClient.InvokeAction(
+			m.getAccountReference(storageAccountName, resourceGroupName),
+			"regenerateKey",
+			map[string]string{"keyName": keyName},
+			&keys,
+		); err != nil {
+			return "", service.WrapError(
+				err,
+				"error regenerating storage account access key",
+			)
+		}
+	}
+	for _, key := range keys.Keys {
+		if key.KeyName == primaryAccessKeyName {
+			return key.Value, nil
+		}
+	}
+	return "", fmt.Errorf(
+		`regenerated keys for storage account "%s" did not include "%s"`,
+		storageAccountName,
+		primaryAccessKeyName,
+	)
+}
+
 func (m *manager) DeleteStorageAccount(
 	storageAccountName string,
 	resourceGroupName string,
@@ -76,3 +207,17 @@ func (m *manager) DeleteStorageAccount(
 
 	return nil
 }
+
+func (m *manager) getAccountReference(
+	storageAccountName string,
+	resourceGroupName string,
+) az.ResourceReference {
+	return az.ResourceReference{
+		SubscriptionID:    m.subscriptionID,
+		ResourceGroupName: resourceGroupName,
+		ProviderNamespace: providerNamespace,
+		ResourceType:      accountResourceType,
+		ResourceName:      storageAccountName,
+		APIVersion:        accountAPIVersion,
+	}
+}
@@ -0,0 +1,154 @@
+package streamanalytics
+
+import (
+	"fmt"
+
+	"github.com/Azure/go-autorest/autorest/azure"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+const (
+	providerNamespace = "Microsoft.StreamAnalytics"
+	resourceType      = "streamingjobs"
+	apiVersion        = "2020-03-01"
+)
+
+// JobStatus describes the current state of a Stream Analytics job
+type JobStatus struct {
+	// JobState is, e.g., "Created", "Starting", "Running", "Stopping",
+	// "Stopped", "Degraded", or "Failed"
+	JobState          string
+	ProvisioningState string
+	// LastOutputEventTime is empty if the job has never produced output
+	LastOutputEventTime string
+}
+
+// Manager is an interface to be implemented by any component capable of
+// managing Azure Stream Analytics jobs
+type Manager interface {
+	// GetJobStatus retrieves the status of the named job. It also returns a
+	// bool indicating whether the job was found.
+	GetJobStatus(
+		jobName string,
+		resourceGroupName string,
+	) (JobStatus, bool, error)
+	// StartJob starts the named job, which begins producing output from the
+	// time at which it is started, and blocks until Azure reports that it has
+	// started
+	StartJob(jobName string, resourceGroupName string) error
+	// StopJob stops the named job and blocks until Azure reports that it has
+	// stopped
+	StopJob(jobName string, resourceGroupName string) error
+	DeleteJob(jobName string, resourceGroupName string) error
+}
+
+type manager struct {
+	subscriptionID string
+	resourceClient az.ResourceClient
+}
+
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	return &manager{
+		subscriptionID: azureConfig.SubscriptionID,
+		resourceClient: az.NewResourceClient(
+			azureEnvironment,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+	}, nil
+}
+
+func (m *manager) GetJobStatus(
+	jobName string,
+	resourceGroupName string,
+) (JobStatus, bool, error) {
+	result := struct {
+		Properties struct {
+			JobState            string `json:"jobState"`
+			ProvisioningState   string `json:"provisioningState"`
+			LastOutputEventTime string `json:"lastOutputEventTime"`
+		} `json:"properties"`
+	}{}
+	ok, err := m.resourceClient.GetResource(
+		m.getJobReference(jobName, resourceGroupName),
+		&result,
+	)
+	if err != nil {
+		return JobStatus{}, false, service.WrapError(
+			err,
+			"error retrieving Stream Analytics job",
+		)
+	}
+	if !ok {
+		return JobStatus{}, false, nil
+	}
+	return JobStatus{
+		JobState:            result.Properties.JobState,
+		ProvisioningState:   result.Properties.ProvisioningState,
+		LastOutputEventTime: result.Properties.LastOutputEventTime,
+	}, true, nil
+}
+
+func (m *manager) StartJob(jobName string, resourceGroupName string) error {
+	if err := m.resourceClient.InvokeAction(
+		m.getJobReference(jobName, resourceGroupName),
+		"start",
+		map[string]string{
+			"outputStartMode": "JobStartTime",
+		},
+		nil,
+	); err != nil {
+		return service.WrapError(err, "error starting Stream Analytics job")
+	}
+	return nil
+}
+
+func (m *manager) StopJob(jobName string, resourceGroupName string) error {
+	if err := m.resourceClient.InvokeAction(
+		m.getJobReference(jobName, resourceGroupName),
+		"stop",
+		nil,
+		nil,
+	); err != nil {
+		return service.WrapError(err, "error stopping Stream Analytics job")
+	}
+	return nil
+}
+
+func (m *manager) DeleteJob(jobName string, resourceGroupName string) error {
+	if err := m.resourceClient.DeleteResource(
+		m.getJobReference(jobName, resourceGroupName),
+	); err != nil {
+		return fmt.Errorf("error deleting Stream Analytics job: %s", err)
+	}
+	return nil
+}
+
+func (m *manager) getJobReference(
+	jobName string,
+	resourceGroupName string,
+) az.ResourceReference {
+	return az.ResourceReference{
+		SubscriptionID:    m.subscriptionID,
+		ResourceGroupName: resourceGroupName,
+		ProviderNamespace: providerNamespace,
+		ResourceType:      resourceType,
+		ResourceName:      jobName,
+		APIVersion:        apiVersion,
+	}
+}
@@ -0,0 +1,37 @@
+package azure
+
+import "regexp"
+
+var subnetIDRegex = regexp.MustCompile(
+	`(?i)^/subscriptions/([^/]+)/resourceGroups/([^/]+)/providers/` +
+		`Microsoft\.Network/virtualNetworks/([^/]+)/subnets/([^/]+)$`,
+)
+
+// SubnetID represents the parts of a virtual network subnet's resource ID
+type SubnetID struct {
+	SubscriptionID     string
+	ResourceGroupName  string
+	VirtualNetworkName string
+	SubnetName         string
+}
+
+// ParseSubnetID parses the given virtual network subnet resource ID. It
+// returns false if the ID isn't well-formed.
+func ParseSubnetID(subnetID string) (SubnetID, bool) {
+	matches := subnetIDRegex.FindStringSubmatch(subnetID)
+	if matches == nil {
+		return SubnetID{}, false
+	}
+	return SubnetID{
+		SubscriptionID:     matches[1],
+		ResourceGroupName:  matches[2],
+		VirtualNetworkName: matches[3],
+		SubnetName:         matches[4],
+	}, true
+}
+
+// IsValidSubnetID returns a bool indicating whether the given string is a
+// well-formed virtual network subnet resource ID
+func IsValidSubnetID(subnetID string) bool {
+	return subnetIDRegex.MatchString(subnetID)
+}
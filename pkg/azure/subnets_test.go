@@ -0,0 +1,41 @@
+package azure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSubnetID(t *testing.T) {
+	id, ok := ParseSubnetID(
+		"/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/" +
+			"virtualNetworks/vnet/subnets/subnet",
+	)
+	assert.True(t, ok)
+	assert.Equal(
+		t,
+		SubnetID{
+			SubscriptionID:     "sub",
+			ResourceGroupName:  "rg",
+			VirtualNetworkName: "vnet",
+			SubnetName:         "subnet",
+		},
+		id,
+	)
+	_, ok = ParseSubnetID(
+		"/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/" +
+			"virtualNetworks/vnet",
+	)
+	assert.False(t, ok)
+}
+
+func TestIsValidSubnetID(t *testing.T) {
+	assert.True(
+		t,
+		IsValidSubnetID(
+			"/SUBSCRIPTIONS/sub/resourcegroups/rg/providers/microsoft.network/"+
+				"virtualnetworks/vnet/subnets/subnet",
+		),
+	)
+	assert.False(t, IsValidSubnetID("bogus"))
+}
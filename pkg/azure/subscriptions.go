@@ -0,0 +1,100 @@
+package azure
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/arm/resources/subscriptions"
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/version"
+)
+
+// SubscriptionChecker is an interface to be implemented by any component
+// capable of checking whether the broker can provision resources into a
+// subscription
+type SubscriptionChecker interface {
+	// CheckSubscription returns an error if the broker's service principal
+	// can't access the specified subscription, or if the subscription can't have
+	// resources provisioned into it
+	CheckSubscription(subscriptionID string) error
+}
+
+type subscriptionChecker struct {
+	azureEnvironment azure.Environment
+	tenantID         string
+	clientID         string
+	clientSecret     string
+}
+
+// NewSubscriptionChecker returns a new implementation of the
+// SubscriptionChecker interface
+func NewSubscriptionChecker() (SubscriptionChecker, error) {
+	azureConfig, err := GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	return &subscriptionChecker{
+		azureEnvironment: azureEnvironment,
+		tenantID:         azureConfig.TenantID,
+		clientID:         azureConfig.ClientID,
+		clientSecret:     azureConfig.ClientSecret,
+	}, nil
+}
+
+func (s *subscriptionChecker) CheckSubscription(subscriptionID string) error {
+	authorizer, err := GetBearerTokenAuthorizer(
+		s.azureEnvironment,
+		s.tenantID,
+		s.clientID,
+		s.clientSecret,
+	)
+	if err != nil {
+		return fmt.Errorf("error getting bearer token authorizer: %s", err)
+	}
+	client := subscriptions.NewGroupClientWithBaseURI(
+		s.azureEnvironment.ResourceManagerEndpoint,
+	)
+	client.Authorizer = authorizer
+	client.UserAgent = fmt.Sprintf(
+		"%s; open-service-broker/%s",
+		client.UserAgent,
+		version.GetVersion(),
+	)
+	subscription, err := client.Get(subscriptionID)
+	if err != nil {
+		// Azure doesn't distinguish between subscriptions that don't exist and
+		// those the principal has no role in
+		if subscription.Response.Response != nil &&
+			(subscription.StatusCode == http.StatusNotFound ||
+				subscription.StatusCode == http.StatusForbidden) {
+			return fmt.Errorf(
+				`subscription "%s" does not exist or is not accessible to the broker`,
+				subscriptionID,
+			)
+		}
+		return fmt.Errorf(
+			`error retrieving subscription "%s": %s`,
+			subscriptionID,
+			CategorizeError(err),
+		)
+	}
+	// Resources can't be created in subscriptions that are disabled, deleted,
+	// or past due
+	if subscription.State != subscriptions.Enabled &&
+		subscription.State != subscriptions.Warned {
+		return fmt.Errorf(
+			`subscription "%s" is in state "%s"; resources can't be provisioned `+
+				`into it`,
+			subscriptionID,
+			subscription.State,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,366 @@
+package azure
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultThrottlingMaxRate                    = 60
+	defaultThrottlingMinRate                    = 1
+	defaultThrottlingRemainingRequestsThreshold = 100
+	defaultThrottlingRecoveryPeriod             = 10 * time.Minute
+	defaultThrottlingRetryAfter                 = 30 * time.Second
+	// resourcesProvider is the namespace of the resource provider that manages
+	// subscriptions, resource groups and deployments. Requests whose paths name
+	// no other provider are made to it.
+	resourcesProvider             = "Microsoft.Resources"
+	remainingRequestsHeaderPrefix = "X-Ms-Ratelimit-Remaining-"
+)
+
+// ThrottlingConfig encapsulates options for slowing the execution of tasks
+// that make requests to Azure resource providers which have recently throttled
+// the broker's requests, or which have reported that few requests remain
+// before they will
+type ThrottlingConfig struct {
+	// MaxRate is the rate, in tasks per minute, at which a provider that has
+	// been throttled is paced as it recovers. A provider that has fully
+	// recovered isn't paced at all.
+	MaxRate float64
+	// MinRate is the rate, in tasks per minute, below which no provider is ever
+	// paced
+	MinRate float64
+	// RemainingRequestsThreshold is the number of requests remaining, as
+	// reported by a provider, below which the provider is paced in proportion
+	// to how few remain
+	RemainingRequestsThreshold int
+	// RecoveryPeriod is how long a provider takes to recover from MinRate to
+	// MaxRate, provided it doesn't throttle the broker again in the meantime
+	RecoveryPeriod time.Duration
+	// DefaultRetryAfter is how long execution is postponed for after a provider
+	// throttles the broker without indicating when to retry
+	DefaultRetryAfter time.Duration
+}
+
+func (t ThrottlingConfig) withDefaults() ThrottlingConfig {
+	if t.MaxRate <= 0 {
+		t.MaxRate = defaultThrottlingMaxRate
+	}
+	if t.MinRate <= 0 {
+		t.MinRate = defaultThrottlingMinRate
+	}
+	if t.MinRate > t.MaxRate {
+		t.MinRate = t.MaxRate
+	}
+	if t.RemainingRequestsThreshold <= 0 {
+		t.RemainingRequestsThreshold = defaultThrottlingRemainingRequestsThreshold
+	}
+	if t.RecoveryPeriod <= 0 {
+		t.RecoveryPeriod = defaultThrottlingRecoveryPeriod
+	}
+	if t.DefaultRetryAfter <= 0 {
+		t.DefaultRetryAfter = defaultThrottlingRetryAfter
+	}
+	return t
+}
+
+// providerThrottling tracks how heavily a single resource provider is
+// throttling the broker
+type providerThrottling struct {
+	// name is the provider's namespace, as it was first seen
+	name string
+	// rate is the rate, in tasks per minute, that the provider is paced at as
+	// of rateChanged. It recovers linearly from then on.
+	rate        float64
+	rateChanged time.Time
+	// throttledUntil is when the provider last asked that requests not be
+	// retried before
+	throttledUntil time.Time
+	// nextDispatch is the earliest time at which another task may be executed
+	nextDispatch       time.Time
+	throttledResponses int64
+}
+
+// ThrottlingMonitor observes the responses of Azure Resource Manager for
+// signs of throttling and paces the execution of tasks that make requests to
+// those resource providers that are throttling, or about to throttle, the
+// broker. Pacing follows the familiar additive-increase, multiplicative-
+// decrease pattern: each throttled response halves a provider's rate, which
+// then recovers linearly. It fulfills the service.ResourceProviderThrottle
+// interface.
+type ThrottlingMonitor struct {
+	config    ThrottlingConfig
+	providers map[string]*providerThrottling
+	mutex     sync.Mutex
+	// This allows tests to inject an alternative implementation of this function
+	now func() time.Time
+}
+
+// NewThrottlingMonitor returns a new ThrottlingMonitor. Unset options in the
+// given config assume their defaults.
+func NewThrottlingMonitor(config ThrottlingConfig) *ThrottlingMonitor {
+	return &ThrottlingMonitor{
+		config:    config.withDefaults(),
+		providers: map[string]*providerThrottling{},
+		now:       time.Now,
+	}
+}
+
+// Observe inspects a response from Azure Resource Manager and, if it was
+// throttled or reports that few requests remain before it would be, slows the
+// pace of the resource provider that the request was made to
+func (t *ThrottlingMonitor) Observe(res *http.Response) {
+	if res == nil || res.Request == nil || res.Request.URL == nil {
+		return
+	}
+	throttled := res.StatusCode == http.StatusTooManyRequests
+	remaining, hasRemaining := getRemainingRequests(res.Header)
+	if !throttled &&
+		(!hasRemaining || remaining >= t.config.RemainingRequestsThreshold) {
+		return
+	}
+	provider := getResourceProvider(res.Request.URL.Path)
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	now := t.now()
+	p := t.getProviderThrottling(provider)
+	rate := t.getRate(p, now)
+	if throttled {
+		p.throttledResponses++
+		p.rate = math.Max(t.config.MinRate, rate/2)
+		throttledUntil := now.Add(
+			getRetryAfter(res.Header, now, t.config.DefaultRetryAfter),
+		)
+		if throttledUntil.After(p.throttledUntil) {
+			p.throttledUntil = throttledUntil
+		}
+		// The provider doesn't begin to recover until it may be retried
+		p.rateChanged = p.throttledUntil
+		return
+	}
+	target := math.Max(
+		t.config.MinRate,
+		t.config.MaxRate*float64(remaining)/
+			float64(t.config.RemainingRequestsThreshold),
+	)
+	if target < rate {
+		p.rate = target
+		p.rateChanged = now
+	}
+}
+
+// Admit returns zero if a task that makes requests to the given resource
+// providers may be executed now, in which case the task counts against each
+// provider's pace. Otherwise, it returns how long to wait, at least, before
+// asking again.
+func (t *ThrottlingMonitor) Admit(resourceProviders []string) time.Duration {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	now := t.now()
+	var delay time.Duration
+	paced := []*providerThrottling{}
+	for _, provider := range resourceProviders {
+		p, ok := t.providers[strings.ToLower(provider)]
+		if !ok {
+			continue
+		}
+		if p.throttledUntil.After(now) {
+			if wait := p.throttledUntil.Sub(now); wait > delay {
+				delay = wait
+			}
+			continue
+		}
+		if t.getRate(p, now) >= t.config.MaxRate {
+			continue
+		}
+		if p.nextDispatch.After(now) {
+			if wait := p.nextDispatch.Sub(now); wait > delay {
+				delay = wait
+			}
+			continue
+		}
+		paced = append(paced, p)
+	}
+	if delay > 0 {
+		return delay
+	}
+	for _, p := range paced {
+		p.nextDispatch = now.Add(
+			time.Duration(float64(time.Minute) / t.getRate(p, now)),
+		)
+	}
+	return 0
+}
+
+// GetDispatchRates returns the rate, in tasks per minute, at which tasks that
+// make requests to each resource provider that has shown signs of throttling
+// are currently paced. Providers that are no longer paced report the maximum
+// rate. Providers that are still to be retried later report zero.
+func (t *ThrottlingMonitor) GetDispatchRates() map[string]float64 {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	now := t.now()
+	rates := make(map[string]float64, len(t.providers))
+	for _, p := range t.providers {
+		if p.throttledUntil.After(now) {
+			rates[p.name] = 0
+		} else {
+			rates[p.name] = t.getRate(p, now)
+		}
+	}
+	return rates
+}
+
+// GetThrottledResponseCounts returns how many throttled responses each
+// resource provider has returned to the broker
+func (t *ThrottlingMonitor) GetThrottledResponseCounts() map[string]int64 {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	counts := make(map[string]int64, len(t.providers))
+	for _, p := range t.providers {
+		counts[p.name] = p.throttledResponses
+	}
+	return counts
+}
+
+func (t *ThrottlingMonitor) getProviderThrottling(
+	provider string,
+) *providerThrottling {
+	key := strings.ToLower(provider)
+	p, ok := t.providers[key]
+	if !ok {
+		p = &providerThrottling{
+			name: provider,
+			rate: t.config.MaxRate,
+		}
+		t.providers[key] = p
+	}
+	return p
+}
+
+// getRate returns the rate at which the given provider is paced at the given
+// time, having recovered since its rate last changed
+func (t *ThrottlingMonitor) getRate(
+	p *providerThrottling,
+	now time.Time,
+) float64 {
+	elapsed := now.Sub(p.rateChanged)
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	recovery := (t.config.MaxRate - t.config.MinRate) *
+		float64(elapsed) / float64(t.config.RecoveryPeriod)
+	return math.Min(t.config.MaxRate, p.rate+recovery)
+}
+
+// getResourceProvider returns the namespace of the resource provider that a
+// request for the given path of Azure Resource Manager's API is handled by.
+// Where one resource is nested in another of a different provider, e.g.
+// metrics of a virtual machine, the last provider named is the one that
+// handles the request.
+func getResourceProvider(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := len(segments) - 2; i >= 0; i-- {
+		if strings.EqualFold(segments[i], "providers") && segments[i+1] != "" {
+			return segments[i+1]
+		}
+	}
+	return resourcesProvider
+}
+
+// getRemainingRequests returns the fewest requests that any of Azure Resource
+// Manager's x-ms-ratelimit-remaining-* headers report remain before requests
+// will be throttled. Most of these headers hold a single count, but
+// x-ms-ratelimit-remaining-resource holds a list of policies and their
+// counts, e.g. "Microsoft.Compute/HighCostGet3Min;107,...". A bool indicating
+// whether any count was found is also returned.
+func getRemainingRequests(header http.Header) (int, bool) {
+	remaining := 0
+	found := false
+	for name, values := range header {
+		if !strings.HasPrefix(
+			http.CanonicalHeaderKey(name),
+			remainingRequestsHeaderPrefix,
+		) {
+			continue
+		}
+		for _, value := range values {
+			for _, policy := range strings.Split(value, ",") {
+				count := policy
+				if i := strings.LastIndex(policy, ";"); i >= 0 {
+					count = policy[i+1:]
+				}
+				n, err := strconv.Atoi(strings.TrimSpace(count))
+				if err != nil {
+					continue
+				}
+				if !found || n < remaining {
+					remaining = n
+					found = true
+				}
+			}
+		}
+	}
+	return remaining, found
+}
+
+// getRetryAfter returns how long a throttled response's Retry-After header,
+// which holds either a number of seconds or an HTTP date, asks that requests
+// not be retried for. If the header is absent or invalid, the given default
+// is returned.
+func getRetryAfter(
+	header http.Header,
+	now time.Time,
+	defaultRetryAfter time.Duration,
+) time.Duration {
+	value := strings.TrimSpace(header.Get("Retry-After"))
+	if value == "" {
+		return defaultRetryAfter
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if date.After(now) {
+			return date.Sub(now)
+		}
+		return 0
+	}
+	return defaultRetryAfter
+}
+
+type throttlingTransport struct {
+	base    http.RoundTripper
+	monitor *ThrottlingMonitor
+	host    string
+}
+
+// NewThrottlingTransport returns an http.RoundTripper that sends requests
+// using the given base transport and lets the given monitor observe responses
+// from the Azure Resource Manager endpoint at the given host. Responses from
+// any other host are left unobserved.
+func NewThrottlingTransport(
+	base http.RoundTripper,
+	monitor *ThrottlingMonitor,
+	resourceManagerHost string,
+) http.RoundTripper {
+	return &throttlingTransport{
+		base:    base,
+		monitor: monitor,
+		host:    strings.ToLower(resourceManagerHost),
+	}
+}
+
+func (t *throttlingTransport) RoundTrip(
+	req *http.Request,
+) (*http.Response, error) {
+	res, err := t.base.RoundTrip(req)
+	if err == nil && strings.ToLower(req.URL.Hostname()) == t.host {
+		t.monitor.Observe(res)
+	}
+	return res, err
+}
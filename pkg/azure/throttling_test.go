@@ -0,0 +1,238 @@
+package azure
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testComputePath = "/subscriptions/sub/resourceGroups/rg/providers/" +
+	"Microsoft.Compute/virtualMachines/vm"
+
+func TestThrottledResponseHaltsDispatch(t *testing.T) {
+	now := time.Now()
+	monitor := getTestThrottlingMonitor(&now)
+
+	// Providers that haven't throttled the broker aren't paced
+	assert.Equal(t, time.Duration(0), monitor.Admit([]string{"Microsoft.Compute"}))
+
+	monitor.Observe(
+		getTestResponse(
+			testComputePath,
+			http.StatusTooManyRequests,
+			http.Header{"Retry-After": []string{"20"}},
+		),
+	)
+	assert.Equal(t, 20*time.Second, monitor.Admit([]string{"microsoft.compute"}))
+	assert.Equal(
+		t,
+		20*time.Second,
+		monitor.Admit([]string{"Microsoft.Resources", "Microsoft.Compute"}),
+	)
+	assert.Equal(t, time.Duration(0), monitor.Admit([]string{"Microsoft.Sql"}))
+	assert.Equal(
+		t,
+		map[string]float64{"Microsoft.Compute": 0},
+		monitor.GetDispatchRates(),
+	)
+	assert.Equal(
+		t,
+		map[string]int64{"Microsoft.Compute": 1},
+		monitor.GetThrottledResponseCounts(),
+	)
+
+	// Once the provider may be retried, tasks are paced at half the maximum
+	// rate-- one every two seconds
+	now = now.Add(20 * time.Second)
+	assert.Equal(t, time.Duration(0), monitor.Admit([]string{"Microsoft.Compute"}))
+	delay := monitor.Admit([]string{"Microsoft.Compute"})
+	assert.True(t, delay > 0 && delay <= 2*time.Second, delay)
+}
+
+func TestThrottledProviderRecovers(t *testing.T) {
+	now := time.Now()
+	monitor := getTestThrottlingMonitor(&now)
+	for i := 0; i < 10; i++ {
+		monitor.Observe(
+			getTestResponse(testComputePath, http.StatusTooManyRequests, nil),
+		)
+	}
+	// The rate never falls below the minimum
+	now = now.Add(defaultThrottlingRetryAfter)
+	assert.Equal(
+		t,
+		map[string]float64{"Microsoft.Compute": 1},
+		monitor.GetDispatchRates(),
+	)
+	now = now.Add(defaultThrottlingRecoveryPeriod / 2)
+	assert.InDelta(
+		t,
+		30.5,
+		monitor.GetDispatchRates()["Microsoft.Compute"],
+		0.1,
+	)
+	now = now.Add(defaultThrottlingRecoveryPeriod)
+	assert.Equal(
+		t,
+		map[string]float64{"Microsoft.Compute": 60},
+		monitor.GetDispatchRates(),
+	)
+	// A provider that has recovered is no longer paced
+	for i := 0; i < 3; i++ {
+		assert.Equal(
+			t,
+			time.Duration(0),
+			monitor.Admit([]string{"Microsoft.Compute"}),
+		)
+	}
+}
+
+func TestFewRemainingRequestsSlowDispatch(t *testing.T) {
+	now := time.Now()
+	monitor := getTestThrottlingMonitor(&now)
+	monitor.Observe(
+		getTestResponse(
+			testComputePath,
+			http.StatusOK,
+			http.Header{
+				"X-Ms-Ratelimit-Remaining-Subscription-Reads": []string{"11999"},
+				"X-Ms-Ratelimit-Remaining-Resource": []string{
+					"Microsoft.Compute/HighCostGet3Min;25," +
+						"Microsoft.Compute/HighCostGet30Min;500",
+				},
+			},
+		),
+	)
+	assert.Equal(
+		t,
+		map[string]float64{"Microsoft.Compute": 15},
+		monitor.GetDispatchRates(),
+	)
+	assert.Equal(t, time.Duration(0), monitor.Admit([]string{"Microsoft.Compute"}))
+	assert.Equal(t, 4*time.Second, monitor.Admit([]string{"Microsoft.Compute"}))
+
+	// Plenty of remaining requests are ignored
+	monitor.Observe(
+		getTestResponse(
+			"/subscriptions/sub/resourceGroups/rg",
+			http.StatusOK,
+			http.Header{
+				"X-Ms-Ratelimit-Remaining-Subscription-Writes": []string{"1199"},
+			},
+		),
+	)
+	assert.NotContains(t, monitor.GetDispatchRates(), "Microsoft.Resources")
+}
+
+func TestGetResourceProvider(t *testing.T) {
+	testCases := map[string]string{
+		testComputePath: "Microsoft.Compute",
+		testComputePath + "/providers/Microsoft.Insights/metrics": "Microsoft." +
+			"Insights",
+		"/subscriptions/sub/resourcegroups/rg":               "Microsoft.Resources",
+		"/subscriptions/sub/PROVIDERS/Microsoft.Sql/servers": "Microsoft.Sql",
+	}
+	for path, provider := range testCases {
+		assert.Equal(t, provider, getResourceProvider(path), path)
+	}
+}
+
+func TestGetRetryAfter(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	assert.Equal(
+		t,
+		5*time.Second,
+		getRetryAfter(http.Header{"Retry-After": []string{"5"}}, now, time.Minute),
+	)
+	assert.Equal(
+		t,
+		90*time.Second,
+		getRetryAfter(
+			http.Header{
+				"Retry-After": []string{
+					now.Add(90 * time.Second).Format(http.TimeFormat),
+				},
+			},
+			now,
+			time.Minute,
+		),
+	)
+	assert.Equal(t, time.Minute, getRetryAfter(http.Header{}, now, time.Minute))
+	assert.Equal(
+		t,
+		time.Minute,
+		getRetryAfter(
+			http.Header{"Retry-After": []string{"soon"}},
+			now,
+			time.Minute,
+		),
+	)
+}
+
+type fakeRoundTripper struct {
+	res *http.Response
+}
+
+func (f *fakeRoundTripper) RoundTrip(
+	req *http.Request,
+) (*http.Response, error) {
+	f.res.Request = req
+	return f.res, nil
+}
+
+func TestThrottlingTransportObservesOnlyResourceManager(t *testing.T) {
+	now := time.Now()
+	monitor := getTestThrottlingMonitor(&now)
+	transport := NewThrottlingTransport(
+		&fakeRoundTripper{
+			res: &http.Response{StatusCode: http.StatusTooManyRequests},
+		},
+		monitor,
+		"management.azure.com",
+	)
+	for _, rawURL := range []string{
+		"https://vault.azure.net/secrets/secret",
+		"https://management.azure.com" + testComputePath,
+	} {
+		req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+		assert.Nil(t, err)
+		_, err = transport.RoundTrip(req)
+		assert.Nil(t, err)
+	}
+	assert.Equal(
+		t,
+		map[string]int64{"Microsoft.Compute": 1},
+		monitor.GetThrottledResponseCounts(),
+	)
+}
+
+// getTestThrottlingMonitor returns a ThrottlingMonitor with the default
+// configuration whose idea of the current time is whatever the given time is
+func getTestThrottlingMonitor(now *time.Time) *ThrottlingMonitor {
+	monitor := NewThrottlingMonitor(ThrottlingConfig{})
+	monitor.now = func() time.Time {
+		return *now
+	}
+	return monitor
+}
+
+func getTestResponse(
+	path string,
+	statusCode int,
+	header http.Header,
+) *http.Response {
+	return &http.Response{
+		StatusCode: statusCode,
+		Header:     header,
+		Request: &http.Request{
+			URL: &url.URL{
+				Scheme: "https",
+				Host:   "management.azure.com",
+				Path:   path,
+			},
+		},
+	}
+}
@@ -0,0 +1,441 @@
+package virtualmachine
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/arm/compute"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+const (
+	computeProviderNamespace = "Microsoft.Compute"
+	virtualMachineType       = "virtualMachines"
+	diskType                 = "disks"
+	computeAPIVersion        = "2021-07-01"
+
+	networkProviderNamespace = "Microsoft.Network"
+	networkInterfaceType     = "networkInterfaces"
+	publicIPAddressType      = "publicIPAddresses"
+	virtualNetworkType       = "virtualNetworks"
+	networkAPIVersion        = "2021-05-01"
+
+	// latestImageVersion stands in for whichever version of an image is most
+	// recent
+	latestImageVersion = "latest"
+
+	powerStateRunning       = "running"
+	powerStatePollingPeriod = 15 * time.Second
+)
+
+// ImageReference identifies a marketplace image that virtual machines may be
+// created from
+type ImageReference struct {
+	Publisher string
+	Offer     string
+	SKU       string
+	Version   string
+}
+
+// Manager is an interface to be implemented by any component capable of
+// managing Azure virtual machines
+type Manager interface {
+	IsVMSizeAvailable(location string, vmSize string) (bool, error)
+	// IsImageAvailable returns a bool indicating whether the referenced image
+	// exists in the given location. An image version of "latest" is available
+	// if any version of the image is.
+	IsImageAvailable(location string, image ImageReference) (bool, error)
+	// WaitForRunning blocks until the named virtual machine is running, it has
+	// failed or stopped, or the context is canceled
+	WaitForRunning(
+		ctx context.Context,
+		vmName string,
+		resourceGroupName string,
+	) error
+	// DeallocateVirtualMachine stops the named virtual machine and releases its
+	// compute resources so that they are no longer billed
+	DeallocateVirtualMachine(vmName string, resourceGroupName string) error
+	StartVirtualMachine(vmName string, resourceGroupName string) error
+	DeleteVirtualMachine(vmName string, resourceGroupName string) error
+	DeleteNetworkInterface(
+		networkInterfaceName string,
+		resourceGroupName string,
+	) error
+	DeleteDisk(diskName string, resourceGroupName string) error
+	DeletePublicIPAddress(
+		publicIPAddressName string,
+		resourceGroupName string,
+	) error
+	DeleteVirtualNetwork(
+		virtualNetworkName string,
+		resourceGroupName string,
+	) error
+}
+
+type manager struct {
+	azureEnvironment azure.Environment
+	subscriptionID   string
+	tenantID         string
+	clientID         string
+	clientSecret     string
+	resourceClient   az.ResourceClient
+}
+
+// NewManager returns a new implementation of the Manager interface that
+// manages resources in the specified subscription
+func NewManager(subscriptionID string) (Manager, error) {
+	azureConfig, err := az.GetConfigForSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	return &manager{
+		azureEnvironment: azureEnvironment,
+		subscriptionID:   azureConfig.SubscriptionID,
+		tenantID:         azureConfig.TenantID,
+		clientID:         azureConfig.ClientID,
+		clientSecret:     azureConfig.ClientSecret,
+		resourceClient: az.NewResourceClient(
+			azureEnvironment,
+			azureConfig.TenantID,
+			azureConfig.ClientID,
+			azureConfig.ClientSecret,
+		),
+	}, nil
+}
+
+func (m *manager) IsVMSizeAvailable(
+	location string,
+	vmSize string,
+) (bool, error) {
+	authorizer, err := m.getAuthorizer()
+	if err != nil {
+		return false, err
+	}
+	sizesClient := compute.NewVirtualMachineSizesClientWithBaseURI(
+		m.azureEnvironment.ResourceManagerEndpoint,
+		m.subscriptionID,
+	)
+	sizesClient.Authorizer = authorizer
+	result, err := sizesClient.List(location)
+	if err != nil {
+		return false, service.WrapError(
+			az.CategorizeError(err),
+			fmt.Sprintf(`error listing vm sizes in location "%s"`, location),
+		)
+	}
+	if result.Value == nil {
+		return false, nil
+	}
+	for _, size := range *result.Value {
+		if strings.EqualFold(stringValue(size.Name), vmSize) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *manager) IsImageAvailable(
+	location string,
+	image ImageReference,
+) (bool, error) {
+	authorizer, err := m.getAuthorizer()
+	if err != nil {
+		return false, err
+	}
+	imagesClient := compute.NewVirtualMachineImagesClientWithBaseURI(
+		m.azureEnvironment.ResourceManagerEndpoint,
+		m.subscriptionID,
+	)
+	imagesClient.Authorizer = authorizer
+	if !strings.EqualFold(image.Version, latestImageVersion) {
+		result, err := imagesClient.Get(
+			location,
+			image.Publisher,
+			image.Offer,
+			image.SKU,
+			image.Version,
+		)
+		if isNotFound(result.Response, err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, service.WrapError(
+				az.CategorizeError(err),
+				"error retrieving image",
+			)
+		}
+		return true, nil
+	}
+	top := int32(1)
+	result, err := imagesClient.List(
+		location,
+		image.Publisher,
+		image.Offer,
+		image.SKU,
+		"",
+		&top,
+		"",
+	)
+	if isNotFound(result.Response, err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, service.WrapError(
+			az.CategorizeError(err),
+			"error listing image versions",
+		)
+	}
+	return result.Value != nil && len(*result.Value) > 0, nil
+}
+
+func (m *manager) WaitForRunning(
+	ctx context.Context,
+	vmName string,
+	resourceGroupName string,
+) error {
+	authorizer, err := m.getAuthorizer()
+	if err != nil {
+		return err
+	}
+	vmsClient := compute.NewVirtualMachinesClientWithBaseURI(
+		m.azureEnvironment.ResourceManagerEndpoint,
+		m.subscriptionID,
+	)
+	vmsClient.Authorizer = authorizer
+	ticker := time.NewTicker(powerStatePollingPeriod)
+	defer ticker.Stop()
+	for {
+		var result compute.VirtualMachine
+		// The virtual machine was only just created, so it may not be found at
+		// first
+		found, err := az.ReadWithConsistencyRetry(ctx, func() (bool, error) {
+			var err error
+			result, err = vmsClient.Get(
+				resourceGroupName,
+				vmName,
+				compute.InstanceView,
+			)
+			if az.IsNotFoundError(err) {
+				return false, nil
+			}
+			return err == nil, err
+		})
+		if err != nil {
+			return service.WrapError(
+				az.CategorizeError(err),
+				"error retrieving virtual machine instance view",
+			)
+		}
+		if !found {
+			return fmt.Errorf(`virtual machine "%s" does not exist`, vmName)
+		}
+		provisioningState, powerState := getStates(result)
+		if strings.HasPrefix(provisioningState, "failed") {
+			return fmt.Errorf(
+				`virtual machine provisioning state is "%s"`,
+				provisioningState,
+			)
+		}
+		switch powerState {
+		case powerStateRunning:
+			return nil
+		case "stopped", "deallocated":
+			return fmt.Errorf(`virtual machine power state is "%s"`, powerState)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (m *manager) DeallocateVirtualMachine(
+	vmName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.InvokeAction(
+		m.getComputeReference(virtualMachineType, vmName, resourceGroupName),
+		"deallocate",
+		nil,
+		nil,
+	); err != nil {
+		return service.WrapError(err, "error deallocating virtual machine")
+	}
+	return nil
+}
+
+func (m *manager) StartVirtualMachine(
+	vmName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.InvokeAction(
+		m.getComputeReference(virtualMachineType, vmName, resourceGroupName),
+		"start",
+		nil,
+		nil,
+	); err != nil {
+		return service.WrapError(err, "error starting virtual machine")
+	}
+	return nil
+}
+
+func (m *manager) DeleteVirtualMachine(
+	vmName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		m.getComputeReference(virtualMachineType, vmName, resourceGroupName),
+	); err != nil {
+		return service.WrapError(err, "error deleting virtual machine")
+	}
+	return nil
+}
+
+func (m *manager) DeleteNetworkInterface(
+	networkInterfaceName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		m.getNetworkReference(
+			networkInterfaceType,
+			networkInterfaceName,
+			resourceGroupName,
+		),
+	); err != nil {
+		return service.WrapError(err, "error deleting network interface")
+	}
+	return nil
+}
+
+func (m *manager) DeleteDisk(diskName string, resourceGroupName string) error {
+	if err := m.resourceClient.DeleteResource(
+		m.getComputeReference(diskType, diskName, resourceGroupName),
+	); err != nil {
+		return service.WrapError(err, "error deleting disk")
+	}
+	return nil
+}
+
+func (m *manager) DeletePublicIPAddress(
+	publicIPAddressName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		m.getNetworkReference(
+			publicIPAddressType,
+			publicIPAddressName,
+			resourceGroupName,
+		),
+	); err != nil {
+		return service.WrapError(err, "error deleting public IP address")
+	}
+	return nil
+}
+
+func (m *manager) DeleteVirtualNetwork(
+	virtualNetworkName string,
+	resourceGroupName string,
+) error {
+	if err := m.resourceClient.DeleteResource(
+		m.getNetworkReference(
+			virtualNetworkType,
+			virtualNetworkName,
+			resourceGroupName,
+		),
+	); err != nil {
+		return service.WrapError(err, "error deleting virtual network")
+	}
+	return nil
+}
+
+func (m *manager) getComputeReference(
+	resourceType string,
+	resourceName string,
+	resourceGroupName string,
+) az.ResourceReference {
+	return az.ResourceReference{
+		SubscriptionID:    m.subscriptionID,
+		ResourceGroupName: resourceGroupName,
+		ProviderNamespace: computeProviderNamespace,
+		ResourceType:      resourceType,
+		ResourceName:      resourceName,
+		APIVersion:        computeAPIVersion,
+	}
+}
+
+func (m *manager) getNetworkReference(
+	resourceType string,
+	resourceName string,
+	resourceGroupName string,
+) az.ResourceReference {
+	return az.ResourceReference{
+		SubscriptionID:    m.subscriptionID,
+		ResourceGroupName: resourceGroupName,
+		ProviderNamespace: networkProviderNamespace,
+		ResourceType:      resourceType,
+		ResourceName:      resourceName,
+		APIVersion:        networkAPIVersion,
+	}
+}
+
+func (m *manager) getAuthorizer() (autorest.Authorizer, error) {
+	authorizer, err := az.GetBearerTokenAuthorizer(
+		m.azureEnvironment,
+		m.tenantID,
+		m.clientID,
+		m.clientSecret,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error getting bearer token authorizer: %s", err)
+	}
+	return authorizer, nil
+}
+
+// getStates returns the provisioning and power states reported by a virtual
+// machine's instance view. Each is reported as a status whose code is, for
+// example, "ProvisioningState/succeeded" or "PowerState/running". The prefixes
+// are removed.
+func getStates(vm compute.VirtualMachine) (string, string) {
+	var provisioningState, powerState string
+	if vm.VirtualMachineProperties == nil ||
+		vm.VirtualMachineProperties.InstanceView == nil ||
+		vm.VirtualMachineProperties.InstanceView.Statuses == nil {
+		return provisioningState, powerState
+	}
+	for _, status := range *vm.VirtualMachineProperties.InstanceView.Statuses {
+		code := stringValue(status.Code)
+		switch {
+		case strings.HasPrefix(code, "ProvisioningState/"):
+			provisioningState = strings.TrimPrefix(code, "ProvisioningState/")
+		case strings.HasPrefix(code, "PowerState/"):
+			powerState = strings.TrimPrefix(code, "PowerState/")
+		}
+	}
+	return provisioningState, powerState
+}
+
+func isNotFound(resp autorest.Response, err error) bool {
+	return err != nil && resp.Response != nil &&
+		resp.StatusCode == http.StatusNotFound
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
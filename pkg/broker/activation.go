@@ -0,0 +1,254 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	"github.com/Azure/open-service-broker-azure/pkg/notification"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	log "github.com/Sirupsen/logrus"
+)
+
+const deactivateInstanceStepName = "deactivateInstance"
+
+// activationRetryDelay is how long activation of an instance that is in the
+// midst of being updated is put off for
+const activationRetryDelay = time.Minute
+
+// newActivationTask returns a task that activates the specified instance at
+// the given time. Activation tasks cannot be withdrawn once submitted, so each
+// carries the time it was scheduled for and does nothing if, by the time it
+// fires, the instance's activation has been rescheduled.
+func newActivationTask(instanceID string, activateAt time.Time) async.Task {
+	return async.NewScheduledTask(
+		"activateInstance",
+		map[string]string{
+			"instanceID": instanceID,
+			"activateAt": activateAt.UTC().Format(time.RFC3339Nano),
+		},
+		activateAt,
+	)
+}
+
+// deactivateInstance is the final step of provisioning for any instance that
+// was provisioned with deferred activation. It puts the instance's underlying
+// resources into their inactive state, then schedules their activation.
+func (b *broker) deactivateInstance(
+	ctx context.Context,
+	task async.Task,
+) ([]async.Task, error) {
+	args := task.GetArgs()
+	instanceID, ok := args["instanceID"]
+	if !ok {
+		return nil, errors.New(`missing required argument "instanceID"`)
+	}
+	instance, ok, err := b.store.GetInstance(instanceID)
+	if err != nil {
+		return nil, b.handleProvisioningError(
+			instanceID,
+			deactivateInstanceStepName,
+			err,
+			"error loading persisted instance",
+		)
+	}
+	if !ok {
+		return nil, b.handleProvisioningError(
+			instanceID,
+			deactivateInstanceStepName,
+			nil,
+			"instance does not exist in the data store",
+		)
+	}
+	// See executeProvisioningStep for why a second copy is retrieved
+	instanceCopy, _, err := b.store.GetInstance(instanceID)
+	if err != nil {
+		return nil, b.handleProvisioningError(
+			instanceID,
+			deactivateInstanceStepName,
+			err,
+			"error loading persisted instance",
+		)
+	}
+	activator, ok := instance.Service.GetServiceManager().(service.Activator)
+	if !ok {
+		return nil, b.handleProvisioningError(
+			instance,
+			deactivateInstanceStepName,
+			nil,
+			fmt.Sprintf(
+				`service "%s" does not support deferred activation`,
+				instance.ServiceID,
+			),
+		)
+	}
+	logFields := log.Fields{
+		"step":       deactivateInstanceStepName,
+		"instanceID": instanceID,
+	}
+	log.WithFields(logFields).Debug("deactivating instance")
+	details, err := activator.Deactivate(ctx, instance)
+	if err != nil {
+		retryCount, _ := strconv.Atoi(args["retryCount"])
+		if delay, ok := b.retryPolicy.getRetryDelay(err, retryCount); ok {
+			logFields["errorCategory"] = service.GetErrorCategory(err)
+			logFields["retryCount"] = retryCount + 1
+			logFields["retryDelay"] = delay
+			logFields["error"] = err
+			log.WithFields(logFields).Warn("deactivation failed; retrying")
+			return []async.Task{
+				async.NewDelayedTask(
+					deactivateInstanceStepName,
+					map[string]string{
+						"instanceID": instanceID,
+						"retryCount": strconv.Itoa(retryCount + 1),
+					},
+					delay,
+				),
+			}, nil
+		}
+		return nil, b.handleProvisioningError(
+			instance,
+			deactivateInstanceStepName,
+			err,
+			"error deactivating instance",
+		)
+	}
+	instanceCopy.Details = details
+	instanceCopy.Activation.Deactivated = true
+	instanceCopy.Status = service.InstanceStateProvisioned
+	instanceCopy = b.withProvisioningTiming(instanceCopy)
+	if err := b.store.WriteInstance(instanceCopy); err != nil {
+		return nil, b.handleProvisioningError(
+			instanceCopy,
+			deactivateInstanceStepName,
+			err,
+			"error persisting instance",
+		)
+	}
+	b.releaseProvisioningSlot(instanceCopy)
+	b.recordProvisioningSLAOutcome(instanceCopy)
+	b.submitProvisioningNotifications(instanceCopy)
+	// The most recently persisted activation time is used, since it may have
+	// been changed by an update while the instance was being provisioned
+	return []async.Task{
+		newActivationTask(instanceID, instanceCopy.Activation.ActivateAt),
+	}, nil
+}
+
+// activateInstance activates an instance that was provisioned with deferred
+// activation, as scheduled
+func (b *broker) activateInstance(
+	ctx context.Context,
+	task async.Task,
+) ([]async.Task, error) {
+	args := task.GetArgs()
+	instanceID, ok := args["instanceID"]
+	if !ok {
+		return nil, errors.New(`missing required argument "instanceID"`)
+	}
+	activateAt, err := time.Parse(time.RFC3339Nano, args["activateAt"])
+	if err != nil {
+		return nil, fmt.Errorf(`error parsing argument "activateAt": %s`, err)
+	}
+	instance, ok, err := b.store.GetInstance(instanceID)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error loading persisted instance "%s": %s`,
+			instanceID,
+			err,
+		)
+	}
+	// The instance may have been deprovisioned or already activated since
+	// this task was submitted, or its activation may have been rescheduled. In
+	// the latter case, another task has been submitted for the new time.
+	if !ok ||
+		!instance.IsPendingActivation() ||
+		!instance.Activation.ActivateAt.Equal(activateAt) {
+		return nil, nil
+	}
+	logFields := log.Fields{
+		"instanceID": instanceID,
+		"activateAt": activateAt,
+	}
+	if instance.Status == service.InstanceStateUpdating {
+		log.WithFields(logFields).Debug(
+			"instance is being updated; putting off activation",
+		)
+		return []async.Task{
+			async.NewDelayedTask("activateInstance", args, activationRetryDelay),
+		}, nil
+	}
+	if instance.Status != service.InstanceStateProvisioned {
+		log.WithFields(logFields).Debug(
+			"instance is not in a provisioned state; not activating",
+		)
+		return nil, nil
+	}
+	activator, ok := instance.Service.GetServiceManager().(service.Activator)
+	if !ok {
+		return nil, fmt.Errorf(
+			`service "%s" does not support deferred activation`,
+			instance.ServiceID,
+		)
+	}
+	// As with provisioning steps, only the details returned by the module are
+	// written back to storage, on an untouched copy of the instance
+	instanceCopy, _, err := b.store.GetInstance(instanceID)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error loading persisted instance "%s": %s`,
+			instanceID,
+			err,
+		)
+	}
+	log.WithFields(logFields).Debug("activating instance")
+	details, err := activator.Activate(ctx, instance)
+	if err != nil {
+		retryCount, _ := strconv.Atoi(args["retryCount"])
+		if delay, ok := b.retryPolicy.getRetryDelay(err, retryCount); ok {
+			logFields["errorCategory"] = service.GetErrorCategory(err)
+			logFields["retryCount"] = retryCount + 1
+			logFields["retryDelay"] = delay
+			logFields["error"] = err
+			log.WithFields(logFields).Warn("activation failed; retrying")
+			retryArgs := map[string]string{
+				"instanceID": instanceID,
+				"activateAt": args["activateAt"],
+				"retryCount": strconv.Itoa(retryCount + 1),
+			}
+			return []async.Task{
+				async.NewDelayedTask("activateInstance", retryArgs, delay),
+			}, nil
+		}
+		return nil, fmt.Errorf(
+			`error activating instance "%s": %s`,
+			instanceID,
+			err,
+		)
+	}
+	now := time.Now().UTC()
+	instanceCopy.Details = details
+	instanceCopy.Activation.Activated = true
+	instanceCopy.Activation.ActivatedAt = &now
+	if err := b.store.WriteInstance(instanceCopy); err != nil {
+		return nil, fmt.Errorf(
+			`error persisting activated instance "%s": %s`,
+			instanceID,
+			err,
+		)
+	}
+	log.WithFields(logFields).Info("activated instance")
+	b.submitNotifications(
+		instanceCopy,
+		notification.StatusActivated,
+		fmt.Sprintf(
+			"activated as scheduled for %s",
+			activateAt.UTC().Format(time.RFC3339),
+		),
+	)
+	return nil, nil
+}
@@ -0,0 +1,161 @@
+package broker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	fakeAsync "github.com/Azure/open-service-broker-azure/pkg/async/fake"
+	"github.com/Azure/open-service-broker-azure/pkg/notification"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLastProvisioningStepSubmitsDeactivation(t *testing.T) {
+	b, _, instance := getActivationTestBroker(t)
+	followUpTasks, err := b.executeProvisioningStep(
+		context.Background(),
+		async.NewTask(
+			"executeProvisioningStep",
+			map[string]string{
+				"stepName":   "run",
+				"instanceID": instance.InstanceID,
+			},
+		),
+	)
+	assert.Nil(t, err)
+	assert.Len(t, followUpTasks, 1)
+	assert.Equal(t, "deactivateInstance", followUpTasks[0].GetJobName())
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Equal(t, service.InstanceStateProvisioning, instance.Status)
+}
+
+func TestDeactivateInstanceSchedulesActivation(t *testing.T) {
+	b, serviceManager, instance := getActivationTestBroker(t)
+	var deactivated bool
+	serviceManager.DeactivateBehavior = func(
+		_ context.Context,
+		instance service.Instance,
+	) (service.InstanceDetails, error) {
+		deactivated = true
+		return instance.Details, nil
+	}
+	followUpTasks, err := b.deactivateInstance(
+		context.Background(),
+		async.NewTask(
+			"deactivateInstance",
+			map[string]string{
+				"instanceID": instance.InstanceID,
+			},
+		),
+	)
+	assert.Nil(t, err)
+	assert.True(t, deactivated)
+	assert.Len(t, followUpTasks, 1)
+	assert.Equal(t, "activateInstance", followUpTasks[0].GetJobName())
+	assert.True(
+		t,
+		instance.Activation.ActivateAt.Equal(
+			*followUpTasks[0].GetExecuteTime(),
+		),
+	)
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Equal(t, service.InstanceStateProvisioned, instance.Status)
+	assert.True(t, instance.Activation.Deactivated)
+	assert.True(t, instance.IsPendingActivation())
+}
+
+func TestActivateInstance(t *testing.T) {
+	b, serviceManager, instance := getActivationTestBroker(t)
+	instance.Status = service.InstanceStateProvisioned
+	instance.Activation.Deactivated = true
+	assert.Nil(t, b.store.WriteInstance(instance))
+	var activated bool
+	serviceManager.ActivateBehavior = func(
+		_ context.Context,
+		instance service.Instance,
+	) (service.InstanceDetails, error) {
+		activated = true
+		return instance.Details, nil
+	}
+	_, err := b.activateInstance(
+		context.Background(),
+		newActivationTask(instance.InstanceID, instance.Activation.ActivateAt),
+	)
+	assert.Nil(t, err)
+	assert.True(t, activated)
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.False(t, instance.IsPendingActivation())
+	assert.NotNil(t, instance.Activation.ActivatedAt)
+	tasks := getSubmittedNotificationTasks(b.asyncEngine.(*fakeAsync.Engine))
+	assert.Equal(
+		t,
+		notification.StatusActivated,
+		tasks["everything"].GetArgs()["status"],
+	)
+}
+
+func TestActivateInstanceSkipsRescheduledActivation(t *testing.T) {
+	b, serviceManager, instance := getActivationTestBroker(t)
+	instance.Status = service.InstanceStateProvisioned
+	instance.Activation.Deactivated = true
+	assert.Nil(t, b.store.WriteInstance(instance))
+	serviceManager.ActivateBehavior = func(
+		context.Context,
+		service.Instance,
+	) (service.InstanceDetails, error) {
+		assert.Fail(t, "an instance was activated ahead of its rescheduled time")
+		return nil, nil
+	}
+	followUpTasks, err := b.activateInstance(
+		context.Background(),
+		newActivationTask(
+			instance.InstanceID,
+			instance.Activation.ActivateAt.Add(-time.Hour),
+		),
+	)
+	assert.Nil(t, err)
+	assert.Empty(t, followUpTasks)
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.True(t, instance.IsPendingActivation())
+}
+
+func TestActivateInstanceWaitsForUpdate(t *testing.T) {
+	b, _, instance := getActivationTestBroker(t)
+	instance.Status = service.InstanceStateUpdating
+	instance.Activation.Deactivated = true
+	assert.Nil(t, b.store.WriteInstance(instance))
+	followUpTasks, err := b.activateInstance(
+		context.Background(),
+		newActivationTask(instance.InstanceID, instance.Activation.ActivateAt),
+	)
+	assert.Nil(t, err)
+	assert.Len(t, followUpTasks, 1)
+	assert.Equal(t, "activateInstance", followUpTasks[0].GetJobName())
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.True(t, instance.IsPendingActivation())
+}
+
+// getActivationTestBroker returns a broker whose only instance is an instance
+// of the fake service that is being provisioned with deferred activation,
+// along with the fake service manager
+func getActivationTestBroker(
+	t *testing.T,
+) (*broker, *fake.ServiceManager, service.Instance) {
+	b, _, _, instance := getNotificationTestBroker(t)
+	serviceManager, ok :=
+		instance.Service.GetServiceManager().(*fake.ServiceManager)
+	assert.True(t, ok)
+	instance.Activation = &service.ActivationState{
+		ActivateAt: time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second),
+	}
+	assert.Nil(t, b.store.WriteInstance(instance))
+	return b, serviceManager, instance
+}
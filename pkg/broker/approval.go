@@ -0,0 +1,349 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/approval"
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	log "github.com/Sirupsen/logrus"
+)
+
+const (
+	awaitApprovalStepName = "awaitApproval"
+
+	defaultApprovalTimeout      = 24 * time.Hour
+	defaultApprovalPollInterval = time.Minute
+)
+
+// ApprovalConfig represents which instances must be approved by an external
+// approval service before they are provisioned, and how long the broker waits
+// for that. Until a decision is made, either because the broker polls the
+// approval service for it or because the approval service calls back with it,
+// the instance is held in the AWAITING_APPROVAL state. Instances that are
+// denied, or that aren't decided upon before the timeout, fail provisioning.
+type ApprovalConfig struct {
+	Approver approval.Approver
+	// Modules names the modules all of whose plans require approval
+	Modules []string
+	// Plans identifies additional plans that require approval, as
+	// serviceName/planName
+	Plans []string
+	// Timeout is how long an instance awaits approval before it is denied
+	Timeout time.Duration
+	// PollInterval is how often the approval service is asked for its decision
+	PollInterval time.Duration
+	// CostThreshold, if positive, is the estimated monthly cost above which
+	// instances of any plan require approval. Estimating costs requires a
+	// pricing table.
+	CostThreshold float64
+	// CostThresholdByOrg is keyed by organization GUID and overrides
+	// CostThreshold for instances in those organizations
+	CostThresholdByOrg map[string]float64
+}
+
+func (a ApprovalConfig) getTimeout() time.Duration {
+	if a.Timeout <= 0 {
+		return defaultApprovalTimeout
+	}
+	return a.Timeout
+}
+
+func (a ApprovalConfig) getPollInterval() time.Duration {
+	if a.PollInterval <= 0 {
+		return defaultApprovalPollInterval
+	}
+	return a.PollInterval
+}
+
+// getApprovalPolicy returns a policy that requires approval of all plans of
+// the given modules' services, as well as of the separately configured plans,
+// all of which must be known, and of instances whose estimated cost exceeds
+// the configured thresholds. Costs are estimated using the given pricing
+// table, without which there can be no thresholds.
+func getApprovalPolicy(
+	services []service.Service,
+	moduleNamesByServiceID map[string]string,
+	approvalConfig ApprovalConfig,
+	pricingTable *service.PricingTable,
+) (service.ApprovalPolicy, error) {
+	approvalModules := map[string]bool{}
+	for _, moduleName := range approvalConfig.Modules {
+		approvalModules[moduleName] = true
+	}
+	knownPlanKeys := map[string]bool{}
+	planKeys := []string{}
+	for _, svc := range services {
+		for _, plan := range svc.GetPlans() {
+			planKey := service.GetPlanKey(svc, plan)
+			knownPlanKeys[planKey] = true
+			if approvalModules[moduleNamesByServiceID[svc.GetID()]] {
+				planKeys = append(planKeys, planKey)
+			}
+		}
+	}
+	for _, planKey := range approvalConfig.Plans {
+		if !knownPlanKeys[planKey] {
+			return service.ApprovalPolicy{}, fmt.Errorf(
+				`approval configuration names unknown plan "%s"; plans are `+
+					"identified as serviceName/planName",
+				planKey,
+			)
+		}
+		planKeys = append(planKeys, planKey)
+	}
+	if approvalConfig.CostThreshold < 0 {
+		return service.ApprovalPolicy{}, errors.New(
+			"approval configuration has a negative cost threshold",
+		)
+	}
+	costThresholds := approvalConfig.CostThreshold > 0
+	for orgGUID, threshold := range approvalConfig.CostThresholdByOrg {
+		if threshold < 0 {
+			return service.ApprovalPolicy{}, fmt.Errorf(
+				"approval configuration has a negative cost threshold for "+
+					`organization "%s"`,
+				orgGUID,
+			)
+		}
+		costThresholds = costThresholds || threshold > 0
+	}
+	if (len(planKeys) > 0 || costThresholds) && approvalConfig.Approver == nil {
+		return service.ApprovalPolicy{}, errors.New(
+			"approval is required for some plans or costs, but no approver is " +
+				"configured",
+		)
+	}
+	if costThresholds && pricingTable == nil {
+		return service.ApprovalPolicy{}, errors.New(
+			"approval is required for some costs, but no pricing table is " +
+				"configured to estimate them",
+		)
+	}
+	return service.NewApprovalPolicy(
+		planKeys,
+		approvalConfig.CostThreshold,
+		approvalConfig.CostThresholdByOrg,
+	), nil
+}
+
+// awaitApproval holds an instance whose provisioning requires approval until
+// the approval service decides upon it. It submits the instance for approval,
+// if it hasn't been already, then polls for the decision. A decision the
+// approval service called back with is passed in the task's "decision" and
+// "reason" arguments instead. Once approved, provisioning starts.
+func (b *broker) awaitApproval(
+	_ context.Context,
+	task async.Task,
+) ([]async.Task, error) {
+	args := task.GetArgs()
+	instanceID, ok := args["instanceID"]
+	if !ok {
+		return nil, errors.New(`missing required argument "instanceID"`)
+	}
+	instance, ok, err := b.store.GetInstance(instanceID)
+	if err != nil {
+		return nil, b.handleProvisioningError(
+			instanceID,
+			awaitApprovalStepName,
+			err,
+			"error loading persisted instance",
+		)
+	}
+	// The instance may already have been decided upon, e.g. by a callback that
+	// arrived while this task was waiting to be executed
+	if !ok || instance.Status != service.InstanceStateAwaitingApproval {
+		return nil, nil
+	}
+	logFields := log.Fields{
+		"step":       awaitApprovalStepName,
+		"instanceID": instanceID,
+	}
+	now := time.Now().UTC()
+	// An instance that requires approval because of its cost already records
+	// which threshold its cost exceeded
+	if instance.Approval == nil {
+		instance.Approval = &service.ApprovalState{}
+	}
+	if instance.Approval.Deadline.IsZero() {
+		instance.Approval.RequestedAt = now
+		instance.Approval.Deadline = now.Add(b.approval.getTimeout())
+	}
+	if decision, ok := args["decision"]; ok {
+		instance.Approval.Decision = decision
+		instance.Approval.Reason = args["reason"]
+	} else if err := b.getApprovalDecision(instance); err != nil {
+		// The approval service may be briefly unavailable; it is asked again at
+		// the next poll, unless the deadline has passed by then
+		logFields["error"] = err
+		log.WithFields(logFields).Warn("error retrieving approval decision")
+	}
+	switch instance.Approval.Decision {
+	case approval.DecisionApproved:
+		instance.Approval.DecidedAt = &now
+		return b.startApprovedProvisioning(instance)
+	case approval.DecisionDenied:
+		instance.Approval.DecidedAt = &now
+		log.WithFields(logFields).Info("provisioning was denied approval")
+		msg := "provisioning was denied approval"
+		if instance.Approval.Reason != "" {
+			msg = fmt.Sprintf("%s: %s", msg, instance.Approval.Reason)
+		}
+		return nil, b.handleProvisioningError(
+			instance,
+			awaitApprovalStepName,
+			nil,
+			msg,
+		)
+	}
+	if !now.Before(instance.Approval.Deadline) {
+		instance.Approval.Decision = approval.DecisionDenied
+		instance.Approval.Reason = "approval timed out"
+		instance.Approval.DecidedAt = &now
+		log.WithFields(logFields).Info("approval timed out")
+		return nil, b.handleProvisioningError(
+			instance,
+			awaitApprovalStepName,
+			nil,
+			fmt.Sprintf(
+				"provisioning was not approved by %s",
+				instance.Approval.Deadline.Format(time.RFC3339),
+			),
+		)
+	}
+	if err := b.store.WriteInstance(instance); err != nil {
+		return nil, b.handleProvisioningError(
+			instance,
+			awaitApprovalStepName,
+			err,
+			"error persisting instance",
+		)
+	}
+	log.WithFields(logFields).Debug("instance is awaiting approval")
+	return []async.Task{
+		async.NewDelayedTask(
+			awaitApprovalStepName,
+			map[string]string{
+				"instanceID": instanceID,
+			},
+			b.approval.getPollInterval(),
+		),
+	}, nil
+}
+
+// getApprovalDecision submits the given instance for approval or, if it was
+// already submitted, retrieves the approval service's decision about it, and
+// records the outcome in the instance's approval state
+func (b *broker) getApprovalDecision(instance service.Instance) error {
+	var resp approval.Response
+	var err error
+	if instance.Approval.ID == "" {
+		resp, err = b.approval.Approver.RequestApproval(
+			getApprovalRequest(instance),
+		)
+	} else {
+		resp, err = b.approval.Approver.GetDecision(instance.Approval.ID)
+	}
+	if err != nil {
+		return err
+	}
+	if resp.ID != "" {
+		instance.Approval.ID = resp.ID
+	}
+	if resp.Decision != approval.DecisionPending {
+		instance.Approval.Decision = resp.Decision
+		instance.Approval.Reason = resp.Reason
+	}
+	return nil
+}
+
+// getApprovalRequest returns the request that submits the given instance for
+// approval
+func getApprovalRequest(instance service.Instance) approval.Request {
+	req := approval.Request{
+		InstanceID:       instance.InstanceID,
+		ServiceID:        instance.ServiceID,
+		ServiceName:      instance.Service.GetName(),
+		PlanID:           instance.PlanID,
+		PlanName:         instance.Plan.GetName(),
+		Location:         instance.Location,
+		ResourceGroup:    instance.ResourceGroup,
+		OrganizationGUID: instance.OrganizationGUID,
+		Tags:             instance.Tags,
+	}
+	if instance.CostEstimate != nil {
+		monthlyCost := instance.CostEstimate.MonthlyCost
+		req.EstimatedMonthlyCost = &monthlyCost
+		req.Currency = instance.CostEstimate.Currency
+	}
+	if instance.Approval.CostThreshold > 0 {
+		costThreshold := instance.Approval.CostThreshold
+		req.CostThreshold = &costThreshold
+	}
+	return req
+}
+
+// startApprovedProvisioning persists the given instance, which has just been
+// approved, as provisioning and returns the task that starts provisioning it.
+// An instance that has a parent first waits for the parent, as it would have
+// if it hadn't required approval.
+func (b *broker) startApprovedProvisioning(
+	instance service.Instance,
+) ([]async.Task, error) {
+	instance.Status = service.InstanceStateProvisioning
+	instance.StatusReason = ""
+	if err := b.store.WriteInstance(instance); err != nil {
+		return nil, b.handleProvisioningError(
+			instance,
+			awaitApprovalStepName,
+			err,
+			"error persisting approved instance",
+		)
+	}
+	log.WithFields(log.Fields{
+		"step":       awaitApprovalStepName,
+		"instanceID": instance.InstanceID,
+	}).Info("provisioning was approved; starting provisioning")
+	if instance.ParentAlias != "" {
+		return []async.Task{
+			async.NewTask(
+				"checkParentStatus",
+				map[string]string{
+					"instanceID": instance.InstanceID,
+				},
+			),
+		}, nil
+	}
+	provisioner, err := instance.Service.GetServiceManager().GetProvisioner(
+		instance.Plan,
+	)
+	if err != nil {
+		return nil, b.handleProvisioningError(
+			instance,
+			awaitApprovalStepName,
+			err,
+			"error retrieving provisioner for service and plan",
+		)
+	}
+	firstStepName, ok := provisioner.GetFirstStepName()
+	if !ok {
+		return nil, b.handleProvisioningError(
+			instance,
+			awaitApprovalStepName,
+			nil,
+			"no steps found for provisioning service and plan",
+		)
+	}
+	return []async.Task{
+		async.NewTask(
+			"executeProvisioningStep",
+			map[string]string{
+				"stepName":   firstStepName,
+				"instanceID": instance.InstanceID,
+			},
+		),
+	}, nil
+}
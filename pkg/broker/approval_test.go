@@ -0,0 +1,342 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/approval"
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeApprover struct {
+	requests  []approval.Request
+	decisions []string
+	response  approval.Response
+	err       error
+}
+
+func (f *fakeApprover) RequestApproval(
+	req approval.Request,
+) (approval.Response, error) {
+	f.requests = append(f.requests, req)
+	return f.response, f.err
+}
+
+func (f *fakeApprover) GetDecision(id string) (approval.Response, error) {
+	f.decisions = append(f.decisions, id)
+	return f.response, f.err
+}
+
+func TestAwaitApprovalRequestsApproval(t *testing.T) {
+	approver := &fakeApprover{
+		response: approval.Response{
+			ID:       "request",
+			Decision: approval.DecisionPending,
+		},
+	}
+	b, instance := getApprovalTestBroker(t, approver)
+	followUpTasks, err := b.awaitApproval(
+		context.Background(),
+		getAwaitApprovalTask(instance.InstanceID, nil),
+	)
+	assert.Nil(t, err)
+	assert.Len(t, approver.requests, 1)
+	assert.Equal(t, "standard", approver.requests[0].PlanName)
+	assert.Len(t, followUpTasks, 1)
+	assert.Equal(t, awaitApprovalStepName, followUpTasks[0].GetJobName())
+	assert.NotNil(t, followUpTasks[0].GetExecuteTime())
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Equal(t, service.InstanceStateAwaitingApproval, instance.Status)
+	assert.Equal(t, "request", instance.Approval.ID)
+	assert.True(t, instance.Approval.Deadline.After(time.Now()))
+}
+
+func TestAwaitApprovalRequestsApprovalOfCostlyInstance(t *testing.T) {
+	approver := &fakeApprover{
+		response: approval.Response{
+			ID:       "request",
+			Decision: approval.DecisionPending,
+		},
+	}
+	b, instance := getApprovalTestBroker(t, approver)
+	instance.CostEstimate = &service.CostEstimate{
+		MonthlyCost: 250,
+		Currency:    "USD",
+	}
+	instance.Approval = &service.ApprovalState{
+		CostThreshold: 100,
+	}
+	assert.Nil(t, b.store.WriteInstance(instance))
+	_, err := b.awaitApproval(
+		context.Background(),
+		getAwaitApprovalTask(instance.InstanceID, nil),
+	)
+	assert.Nil(t, err)
+	assert.Len(t, approver.requests, 1)
+	req := approver.requests[0]
+	assert.Equal(t, float64(250), *req.EstimatedMonthlyCost)
+	assert.Equal(t, "USD", req.Currency)
+	assert.Equal(t, float64(100), *req.CostThreshold)
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Equal(t, float64(100), instance.Approval.CostThreshold)
+	assert.True(t, instance.Approval.Deadline.After(time.Now()))
+}
+
+func TestAwaitApprovalPollsForDecision(t *testing.T) {
+	approver := &fakeApprover{
+		response: approval.Response{
+			Decision: approval.DecisionApproved,
+		},
+	}
+	b, instance := getApprovalTestBroker(t, approver)
+	instance.Approval = &service.ApprovalState{
+		ID:       "request",
+		Deadline: time.Now().Add(time.Hour),
+	}
+	assert.Nil(t, b.store.WriteInstance(instance))
+	followUpTasks, err := b.awaitApproval(
+		context.Background(),
+		getAwaitApprovalTask(instance.InstanceID, nil),
+	)
+	assert.Nil(t, err)
+	assert.Empty(t, approver.requests)
+	assert.Equal(t, []string{"request"}, approver.decisions)
+	assert.Len(t, followUpTasks, 1)
+	assert.Equal(t, "executeProvisioningStep", followUpTasks[0].GetJobName())
+	assert.Equal(t, "run", followUpTasks[0].GetArgs()["stepName"])
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Equal(t, service.InstanceStateProvisioning, instance.Status)
+	assert.Equal(t, approval.DecisionApproved, instance.Approval.Decision)
+	assert.NotNil(t, instance.Approval.DecidedAt)
+}
+
+func TestAwaitApprovalToleratesApproverErrors(t *testing.T) {
+	approver := &fakeApprover{
+		err: errors.New("unavailable"),
+	}
+	b, instance := getApprovalTestBroker(t, approver)
+	followUpTasks, err := b.awaitApproval(
+		context.Background(),
+		getAwaitApprovalTask(instance.InstanceID, nil),
+	)
+	assert.Nil(t, err)
+	assert.Len(t, followUpTasks, 1)
+	assert.Equal(t, awaitApprovalStepName, followUpTasks[0].GetJobName())
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Equal(t, service.InstanceStateAwaitingApproval, instance.Status)
+}
+
+func TestAwaitApprovalAppliesCallbackDenial(t *testing.T) {
+	approver := &fakeApprover{}
+	b, instance := getApprovalTestBroker(t, approver)
+	followUpTasks, err := b.awaitApproval(
+		context.Background(),
+		getAwaitApprovalTask(
+			instance.InstanceID,
+			map[string]string{
+				"decision": approval.DecisionDenied,
+				"reason":   "over budget",
+			},
+		),
+	)
+	assert.NotNil(t, err)
+	assert.Empty(t, followUpTasks)
+	assert.Empty(t, approver.requests)
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Equal(t, service.InstanceStateProvisioningFailed, instance.Status)
+	assert.Contains(t, instance.StatusReason, "over budget")
+	assert.Equal(t, approval.DecisionDenied, instance.Approval.Decision)
+}
+
+func TestAwaitApprovalTimesOut(t *testing.T) {
+	approver := &fakeApprover{
+		response: approval.Response{
+			Decision: approval.DecisionPending,
+		},
+	}
+	b, instance := getApprovalTestBroker(t, approver)
+	instance.Approval = &service.ApprovalState{
+		ID:       "request",
+		Deadline: time.Now().Add(-time.Minute),
+	}
+	assert.Nil(t, b.store.WriteInstance(instance))
+	followUpTasks, err := b.awaitApproval(
+		context.Background(),
+		getAwaitApprovalTask(instance.InstanceID, nil),
+	)
+	assert.NotNil(t, err)
+	assert.Empty(t, followUpTasks)
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Equal(t, service.InstanceStateProvisioningFailed, instance.Status)
+	assert.Equal(t, "approval timed out", instance.Approval.Reason)
+}
+
+func TestAwaitApprovalIgnoresDecidedInstance(t *testing.T) {
+	approver := &fakeApprover{}
+	b, instance := getApprovalTestBroker(t, approver)
+	instance.Status = service.InstanceStateProvisioning
+	assert.Nil(t, b.store.WriteInstance(instance))
+	followUpTasks, err := b.awaitApproval(
+		context.Background(),
+		getAwaitApprovalTask(instance.InstanceID, nil),
+	)
+	assert.Nil(t, err)
+	assert.Empty(t, followUpTasks)
+	assert.Empty(t, approver.requests)
+}
+
+func TestGetApprovalPolicy(t *testing.T) {
+	fakeModule, err := fake.New()
+	assert.Nil(t, err)
+	catalog, err := fakeModule.GetCatalog()
+	assert.Nil(t, err)
+	services := catalog.GetServices()
+	svc, ok := catalog.GetService(fake.ServiceID)
+	assert.True(t, ok)
+	plan, ok := svc.GetPlan(fake.StandardPlanID)
+	assert.True(t, ok)
+	moduleNamesByServiceID := map[string]string{
+		fake.ServiceID: fakeModule.GetName(),
+	}
+
+	policy, err := getApprovalPolicy(
+		services,
+		moduleNamesByServiceID,
+		ApprovalConfig{
+			Approver: &fakeApprover{},
+			Modules:  []string{fakeModule.GetName()},
+		},
+		nil,
+	)
+	assert.Nil(t, err)
+	assert.True(t, policy.IsApprovalRequired(svc, plan))
+
+	policy, err = getApprovalPolicy(
+		services,
+		moduleNamesByServiceID,
+		ApprovalConfig{
+			Approver: &fakeApprover{},
+			Plans:    []string{"fake/standard"},
+		},
+		nil,
+	)
+	assert.Nil(t, err)
+	assert.True(t, policy.IsApprovalRequired(svc, plan))
+
+	_, err = getApprovalPolicy(
+		services,
+		moduleNamesByServiceID,
+		ApprovalConfig{
+			Approver: &fakeApprover{},
+			Plans:    []string{"fake/nonexistent"},
+		},
+		nil,
+	)
+	assert.NotNil(t, err)
+
+	_, err = getApprovalPolicy(
+		services,
+		moduleNamesByServiceID,
+		ApprovalConfig{
+			Modules: []string{fakeModule.GetName()},
+		},
+		nil,
+	)
+	assert.NotNil(t, err)
+}
+
+func TestGetApprovalPolicyWithCostThresholds(t *testing.T) {
+	pricingTable, err := service.NewPricingTable("USD", []byte("[]"))
+	assert.Nil(t, err)
+	costEstimate := &service.CostEstimate{MonthlyCost: 75}
+
+	policy, err := getApprovalPolicy(
+		nil,
+		nil,
+		ApprovalConfig{
+			Approver:      &fakeApprover{},
+			CostThreshold: 100,
+			CostThresholdByOrg: map[string]float64{
+				"org": 50,
+			},
+		},
+		pricingTable,
+	)
+	assert.Nil(t, err)
+	_, ok := policy.GetExceededCostThreshold("other", costEstimate)
+	assert.False(t, ok)
+	threshold, ok := policy.GetExceededCostThreshold("org", costEstimate)
+	assert.True(t, ok)
+	assert.Equal(t, float64(50), threshold)
+
+	_, err = getApprovalPolicy(
+		nil,
+		nil,
+		ApprovalConfig{
+			Approver:      &fakeApprover{},
+			CostThreshold: 100,
+		},
+		nil,
+	)
+	assert.NotNil(t, err)
+
+	_, err = getApprovalPolicy(
+		nil,
+		nil,
+		ApprovalConfig{
+			CostThresholdByOrg: map[string]float64{
+				"org": 50,
+			},
+		},
+		pricingTable,
+	)
+	assert.NotNil(t, err)
+
+	_, err = getApprovalPolicy(
+		nil,
+		nil,
+		ApprovalConfig{
+			Approver:      &fakeApprover{},
+			CostThreshold: -1,
+		},
+		pricingTable,
+	)
+	assert.NotNil(t, err)
+}
+
+func getApprovalTestBroker(
+	t *testing.T,
+	approver approval.Approver,
+) (*broker, service.Instance) {
+	b, _, instance := getConnectivityValidationTestBroker(t)
+	b.approval = ApprovalConfig{
+		Approver: approver,
+	}
+	instance.Status = service.InstanceStateAwaitingApproval
+	assert.Nil(t, b.store.WriteInstance(instance))
+	return b, instance
+}
+
+func getAwaitApprovalTask(
+	instanceID string,
+	extraArgs map[string]string,
+) async.Task {
+	args := map[string]string{
+		"instanceID": instanceID,
+	}
+	for k, v := range extraArgs {
+		args[k] = v
+	}
+	return async.NewTask(awaitApprovalStepName, args)
+}
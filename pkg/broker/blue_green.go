@@ -0,0 +1,824 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	log "github.com/Sirupsen/logrus"
+)
+
+// executeGreenProvisioningStep executes a single provisioning step for the
+// green instance of a blue-green update. Once the last step succeeds, the
+// green instance is validated. If any step fails, the update is rolled back.
+func (b *broker) executeGreenProvisioningStep(
+	ctx context.Context,
+	task async.Task,
+) ([]async.Task, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stepName, ok := task.GetArgs()["stepName"]
+	if !ok {
+		return nil, errors.New(`missing required argument "stepName"`)
+	}
+	blue, green, ok, err := b.getBlueGreenInstances(
+		task,
+		service.BlueGreenPhaseProvisioningGreen,
+	)
+	if err != nil || !ok {
+		return nil, err
+	}
+	log.WithFields(log.Fields{
+		"step":            stepName,
+		"instanceID":      blue.InstanceID,
+		"greenInstanceID": green.InstanceID,
+	}).Debug("executing green provisioning step")
+
+	// As with any other step, only the details that the module returns are
+	// written back to storage, so they're added to an untouched copy of the
+	// green instance
+	greenCopy, _, err := b.store.GetInstance(green.InstanceID)
+	if err != nil {
+		return b.startBlueGreenRollback(
+			blue.InstanceID,
+			stepName,
+			err,
+			"error loading persisted green instance",
+		)
+	}
+
+	provisioner, err := green.Service.GetServiceManager().GetProvisioner(
+		green.Plan,
+	)
+	if err != nil {
+		return b.startBlueGreenRollback(
+			blue.InstanceID,
+			stepName,
+			err,
+			fmt.Sprintf(
+				`error retrieving provisioner for service "%s"`,
+				green.ServiceID,
+			),
+		)
+	}
+	// The green instance isn't swapped in until it meets its plan's success
+	// criteria, as any other instance must before it is considered provisioned
+	_, checkedCriteria := b.getSuccessCriteria(green)
+	provisioner, err = provisioner.WithSuccessCriteria(checkedCriteria)
+	if err != nil {
+		return b.startBlueGreenRollback(
+			blue.InstanceID,
+			stepName,
+			err,
+			"error adding success criteria to provisioner",
+		)
+	}
+	step, ok := provisioner.GetStep(stepName)
+	if !ok {
+		return b.startBlueGreenRollback(
+			blue.InstanceID,
+			stepName,
+			nil,
+			"provisioner does not know how to process step",
+		)
+	}
+	updatedDetails, err := step.Execute(ctx, green)
+	if err != nil {
+		return b.startBlueGreenRollback(
+			blue.InstanceID,
+			stepName,
+			err,
+			"error executing green provisioning step",
+		)
+	}
+	greenCopy.Details = updatedDetails
+	nextStepName, ok := provisioner.GetNextStepName(step.GetName())
+	if !ok {
+		greenCopy.Status = service.InstanceStateProvisioned
+	}
+	if err = b.store.WriteInstance(greenCopy); err != nil {
+		return b.startBlueGreenRollback(
+			blue.InstanceID,
+			stepName,
+			err,
+			"error persisting green instance",
+		)
+	}
+	if ok {
+		return []async.Task{
+			async.NewTask(
+				"executeGreenProvisioningStep",
+				map[string]string{
+					"stepName":   nextStepName,
+					"instanceID": blue.InstanceID,
+				},
+			),
+		}, nil
+	}
+	// No next step-- the green instance is provisioned
+	err = b.setBlueGreenPhase(
+		blue.InstanceID,
+		stepName,
+		service.BlueGreenPhaseValidatingGreen,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return []async.Task{
+		async.NewTask(
+			"validateGreenInstance",
+			map[string]string{
+				"instanceID": blue.InstanceID,
+			},
+		),
+	}, nil
+}
+
+// validateGreenInstance has the module verify that a newly provisioned green
+// instance is fit to replace the blue instance before anything is cut over to
+// it. If it isn't, the update is rolled back.
+func (b *broker) validateGreenInstance(
+	ctx context.Context,
+	task async.Task,
+) ([]async.Task, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	blue, green, ok, err := b.getBlueGreenInstances(
+		task,
+		service.BlueGreenPhaseValidatingGreen,
+	)
+	if err != nil || !ok {
+		return nil, err
+	}
+	updater, err := b.getBlueGreenUpdater(blue, task.GetJobName())
+	if err != nil {
+		return nil, err
+	}
+	if err := updater.ValidateGreenInstance(ctx, blue, green); err != nil {
+		return b.startBlueGreenRollback(
+			blue.InstanceID,
+			task.GetJobName(),
+			err,
+			"green instance failed validation",
+		)
+	}
+	err = b.setBlueGreenPhase(
+		blue.InstanceID,
+		task.GetJobName(),
+		service.BlueGreenPhaseCuttingOver,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return []async.Task{
+		async.NewTask(
+			"cutOverBindings",
+			map[string]string{
+				"instanceID": blue.InstanceID,
+			},
+		),
+	}, nil
+}
+
+// cutOverBindings cuts each of the blue instance's bindings over to the green
+// instance, then puts the green instance's resources in the blue instance's
+// place and moves the blue resources aside to be torn down. Bindings already
+// cut over by an earlier, interrupted attempt are skipped. If any binding
+// can't be cut over, the update is rolled back, which cuts the bindings that
+// were back over to the blue instance.
+func (b *broker) cutOverBindings(
+	ctx context.Context,
+	task async.Task,
+) ([]async.Task, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stepName := task.GetJobName()
+	blue, green, ok, err := b.getBlueGreenInstances(
+		task,
+		service.BlueGreenPhaseCuttingOver,
+	)
+	if err != nil || !ok {
+		return nil, err
+	}
+	updater, err := b.getBlueGreenUpdater(blue, stepName)
+	if err != nil {
+		return nil, err
+	}
+	// Only the instances' progress and resources are written back to storage,
+	// so those are applied to untouched copies of both instances
+	blueCopy, _, err := b.store.GetInstance(blue.InstanceID)
+	if err != nil {
+		return b.startBlueGreenRollback(
+			blue.InstanceID,
+			stepName,
+			err,
+			"error loading persisted instance",
+		)
+	}
+	greenCopy, _, err := b.store.GetInstance(green.InstanceID)
+	if err != nil {
+		return b.startBlueGreenRollback(
+			blue.InstanceID,
+			stepName,
+			err,
+			"error loading persisted green instance",
+		)
+	}
+	bindingIDs, err := b.store.GetBindingIDs(blue.InstanceID)
+	if err != nil {
+		return b.startBlueGreenRollback(
+			blue.InstanceID,
+			stepName,
+			err,
+			"error listing bindings",
+		)
+	}
+	for _, bindingID := range bindingIDs {
+		if blueCopy.BlueGreen.IsBindingCutOver(bindingID) {
+			continue
+		}
+		binding, ok, err := b.store.GetBinding(bindingID)
+		if err != nil {
+			return b.startBlueGreenRollback(
+				blue.InstanceID,
+				stepName,
+				err,
+				fmt.Sprintf(`error loading persisted binding "%s"`, bindingID),
+			)
+		}
+		// The binding may have been unbound since it was listed
+		if !ok || binding.Status != service.BindingStateBound {
+			continue
+		}
+		details, err := updater.CutOverBinding(ctx, blue, green, binding)
+		if err != nil {
+			return b.startBlueGreenRollback(
+				blue.InstanceID,
+				stepName,
+				err,
+				fmt.Sprintf(`error cutting over binding "%s"`, bindingID),
+			)
+		}
+		binding.Details = details
+		if err := b.store.WriteBinding(binding); err != nil {
+			return b.startBlueGreenRollback(
+				blue.InstanceID,
+				stepName,
+				err,
+				fmt.Sprintf(`error persisting binding "%s"`, bindingID),
+			)
+		}
+		blueCopy.BlueGreen.CutOverBindingIDs = append(
+			blueCopy.BlueGreen.CutOverBindingIDs,
+			bindingID,
+		)
+		if err := b.store.WriteInstance(blueCopy); err != nil {
+			return b.startBlueGreenRollback(
+				blue.InstanceID,
+				stepName,
+				err,
+				"error persisting instance",
+			)
+		}
+	}
+
+	// The blue resources are copied aside before the green resources replace
+	// them, so that the blue resources are never without an instance to
+	// represent them
+	retired := blueCopy
+	retired.InstanceID = service.GetRetiredBlueInstanceID(blue.InstanceID)
+	retired.Alias = ""
+	retired.Status = service.InstanceStateDeprovisioning
+	retired.StatusReason = ""
+	retired.BlueGreen = nil
+	if err := b.store.WriteInstance(retired); err != nil {
+		return b.startBlueGreenRollback(
+			blue.InstanceID,
+			stepName,
+			err,
+			"error persisting retired blue instance",
+		)
+	}
+	deprovisioner, err := retired.Service.GetServiceManager().GetDeprovisioner(
+		retired.Plan,
+	)
+	if err != nil {
+		return nil, b.handleUpdatingError(
+			blueCopy,
+			stepName,
+			err,
+			fmt.Sprintf(
+				`error retrieving deprovisioner for service "%s"`,
+				retired.ServiceID,
+			),
+		)
+	}
+	firstStepName, ok := deprovisioner.GetFirstStepName()
+	if !ok {
+		return nil, b.handleUpdatingError(
+			blueCopy,
+			stepName,
+			nil,
+			"no steps found for deprovisioning retired blue instance",
+		)
+	}
+	blueCopy.PlanID = greenCopy.PlanID
+	blueCopy.Plan = greenCopy.Plan
+	blueCopy.ProvisioningParameters = greenCopy.ProvisioningParameters
+	blueCopy.UpdatingParameters = greenCopy.UpdatingParameters
+	blueCopy.Details = greenCopy.Details
+	blueCopy.BlueGreen.Phase = service.BlueGreenPhaseTearingDownBlue
+	if err := b.store.WriteInstance(blueCopy); err != nil {
+		return b.startBlueGreenRollback(
+			blue.InstanceID,
+			stepName,
+			err,
+			"error persisting instance",
+		)
+	}
+	// From here on the green resources are in service, so there's no going back
+	if _, err := b.store.DeleteInstance(green.InstanceID); err != nil {
+		return nil, b.handleUpdatingError(
+			blueCopy,
+			stepName,
+			err,
+			"error deleting green instance",
+		)
+	}
+	log.WithFields(log.Fields{
+		"instanceID": blue.InstanceID,
+		"bindings":   len(blueCopy.BlueGreen.CutOverBindingIDs),
+	}).Info("cut over to green instance; tearing down blue instance")
+	return []async.Task{
+		async.NewTask(
+			"executeBlueGreenDeprovisioningStep",
+			map[string]string{
+				"stepName":   firstStepName,
+				"instanceID": blue.InstanceID,
+			},
+		),
+	}, nil
+}
+
+// rollBackBlueGreenUpdate begins rolling back a failed blue-green update. Any
+// bindings that were already cut over to the green instance are cut back over
+// to the blue instance before the green instance is deprovisioned.
+func (b *broker) rollBackBlueGreenUpdate(
+	ctx context.Context,
+	task async.Task,
+) ([]async.Task, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stepName := task.GetJobName()
+	blue, green, ok, err := b.getBlueGreenInstances(
+		task,
+		service.BlueGreenPhaseRollingBack,
+	)
+	if err != nil || !ok {
+		return nil, err
+	}
+	updater, err := b.getBlueGreenUpdater(blue, stepName)
+	if err != nil {
+		return nil, err
+	}
+	blueCopy, _, err := b.store.GetInstance(blue.InstanceID)
+	if err != nil {
+		return nil, b.handleUpdatingError(
+			blue,
+			stepName,
+			err,
+			"error loading persisted instance",
+		)
+	}
+	for len(blueCopy.BlueGreen.CutOverBindingIDs) > 0 {
+		bindingID := blueCopy.BlueGreen.CutOverBindingIDs[0]
+		binding, ok, err := b.store.GetBinding(bindingID)
+		if err != nil {
+			return nil, b.handleUpdatingError(
+				blueCopy,
+				stepName,
+				err,
+				fmt.Sprintf(`error loading persisted binding "%s"`, bindingID),
+			)
+		}
+		if ok {
+			details, err := updater.CutOverBinding(ctx, green, blue, binding)
+			if err != nil {
+				return nil, b.handleUpdatingError(
+					blueCopy,
+					stepName,
+					err,
+					fmt.Sprintf(
+						`error cutting binding "%s" back over to blue instance`,
+						bindingID,
+					),
+				)
+			}
+			binding.Details = details
+			if err := b.store.WriteBinding(binding); err != nil {
+				return nil, b.handleUpdatingError(
+					blueCopy,
+					stepName,
+					err,
+					fmt.Sprintf(`error persisting binding "%s"`, bindingID),
+				)
+			}
+		}
+		blueCopy.BlueGreen.CutOverBindingIDs =
+			blueCopy.BlueGreen.CutOverBindingIDs[1:]
+		if err := b.store.WriteInstance(blueCopy); err != nil {
+			return nil, b.handleUpdatingError(
+				blueCopy,
+				stepName,
+				err,
+				"error persisting instance",
+			)
+		}
+	}
+
+	deprovisioner, err := green.Service.GetServiceManager().GetDeprovisioner(
+		green.Plan,
+	)
+	if err != nil {
+		return nil, b.handleUpdatingError(
+			blueCopy,
+			stepName,
+			err,
+			fmt.Sprintf(
+				`error retrieving deprovisioner for service "%s"`,
+				green.ServiceID,
+			),
+		)
+	}
+	firstStepName, ok := deprovisioner.GetFirstStepName()
+	if !ok {
+		return nil, b.handleUpdatingError(
+			blueCopy,
+			stepName,
+			nil,
+			"no steps found for deprovisioning green instance",
+		)
+	}
+	greenCopy, _, err := b.store.GetInstance(green.InstanceID)
+	if err != nil {
+		return nil, b.handleUpdatingError(
+			blueCopy,
+			stepName,
+			err,
+			"error loading persisted green instance",
+		)
+	}
+	greenCopy.Status = service.InstanceStateDeprovisioning
+	if err := b.store.WriteInstance(greenCopy); err != nil {
+		return nil, b.handleUpdatingError(
+			blueCopy,
+			stepName,
+			err,
+			"error persisting green instance",
+		)
+	}
+	return []async.Task{
+		async.NewTask(
+			"executeBlueGreenDeprovisioningStep",
+			map[string]string{
+				"stepName":   firstStepName,
+				"instanceID": blue.InstanceID,
+			},
+		),
+	}, nil
+}
+
+// executeBlueGreenDeprovisioningStep executes a single deprovisioning step
+// for whichever of a blue-green update's instances is going out of service:
+// the retired blue instance once the update has cut over, or the green
+// instance if the update is being rolled back. Once that instance is
+// deprovisioned, the update is complete, or has failed, respectively.
+func (b *broker) executeBlueGreenDeprovisioningStep(
+	ctx context.Context,
+	task async.Task,
+) ([]async.Task, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stepName, ok := task.GetArgs()["stepName"]
+	if !ok {
+		return nil, errors.New(`missing required argument "stepName"`)
+	}
+	blue, outgoing, ok, err := b.getBlueGreenInstances(
+		task,
+		service.BlueGreenPhaseTearingDownBlue,
+		service.BlueGreenPhaseRollingBack,
+	)
+	if err != nil || !ok {
+		return nil, err
+	}
+	log.WithFields(log.Fields{
+		"step":               stepName,
+		"instanceID":         blue.InstanceID,
+		"outgoingInstanceID": outgoing.InstanceID,
+	}).Debug("executing blue-green deprovisioning step")
+
+	outgoingCopy, _, err := b.store.GetInstance(outgoing.InstanceID)
+	if err != nil {
+		return nil, b.handleBlueGreenDeprovisioningError(
+			blue,
+			outgoing,
+			stepName,
+			err,
+			"error loading persisted instance",
+		)
+	}
+	deprovisioner, err := outgoing.Service.GetServiceManager().GetDeprovisioner(
+		outgoing.Plan,
+	)
+	if err != nil {
+		return nil, b.handleBlueGreenDeprovisioningError(
+			blue,
+			outgoing,
+			stepName,
+			err,
+			fmt.Sprintf(
+				`error retrieving deprovisioner for service "%s"`,
+				outgoing.ServiceID,
+			),
+		)
+	}
+	step, ok := deprovisioner.GetStep(stepName)
+	if !ok {
+		return nil, b.handleBlueGreenDeprovisioningError(
+			blue,
+			outgoing,
+			stepName,
+			nil,
+			"deprovisioner does not know how to process step",
+		)
+	}
+	updatedDetails, err := step.Execute(ctx, outgoing)
+	if err != nil {
+		return nil, b.handleBlueGreenDeprovisioningError(
+			blue,
+			outgoing,
+			stepName,
+			err,
+			"error executing deprovisioning step",
+		)
+	}
+	outgoingCopy.Details = updatedDetails
+	if nextStepName, ok := deprovisioner.GetNextStepName(step.GetName()); ok {
+		if err = b.store.WriteInstance(outgoingCopy); err != nil {
+			return nil, b.handleBlueGreenDeprovisioningError(
+				blue,
+				outgoingCopy,
+				stepName,
+				err,
+				"error persisting instance",
+			)
+		}
+		return []async.Task{
+			async.NewTask(
+				"executeBlueGreenDeprovisioningStep",
+				map[string]string{
+					"stepName":   nextStepName,
+					"instanceID": blue.InstanceID,
+				},
+			),
+		}, nil
+	}
+	// No next step-- the outgoing instance is gone and the update is over
+	if _, err = b.store.DeleteInstance(outgoing.InstanceID); err != nil {
+		return nil, b.handleBlueGreenDeprovisioningError(
+			blue,
+			outgoingCopy,
+			stepName,
+			err,
+			"error deleting deprovisioned instance",
+		)
+	}
+	logFields := log.Fields{
+		"instanceID": blue.InstanceID,
+	}
+	if blue.BlueGreen.Phase == service.BlueGreenPhaseRollingBack {
+		blue.Status = service.InstanceStateUpdatingFailed
+		blue.StatusReason = fmt.Sprintf(
+			"blue-green update was rolled back: %s",
+			blue.BlueGreen.RollbackReason,
+		)
+		logFields["reason"] = blue.BlueGreen.RollbackReason
+		log.WithFields(logFields).Warn("rolled back blue-green update")
+	} else {
+		blue.Status = service.InstanceStateUpdated
+		blue.StatusReason = ""
+		log.WithFields(logFields).Info("completed blue-green update")
+	}
+	blue.BlueGreen = nil
+	if err = b.store.WriteInstance(blue); err != nil {
+		return nil, b.handleUpdatingError(
+			blue,
+			stepName,
+			err,
+			"error persisting instance",
+		)
+	}
+	return nil, nil
+}
+
+// getBlueGreenInstances loads the instance named by the given task, if it is
+// in one of the given phases of a blue-green update, along with the instance
+// that the phase concerns: the retired blue instance while its resources are
+// being torn down and the green instance otherwise. It returns false if the
+// instance has moved on since the task was submitted.
+func (b *broker) getBlueGreenInstances(
+	task async.Task,
+	phases ...string,
+) (service.Instance, service.Instance, bool, error) {
+	instanceID, ok := task.GetArgs()["instanceID"]
+	if !ok {
+		return service.Instance{}, service.Instance{}, false, errors.New(
+			`missing required argument "instanceID"`,
+		)
+	}
+	instance, ok, err := b.store.GetInstance(instanceID)
+	if err != nil {
+		return service.Instance{}, service.Instance{}, false, fmt.Errorf(
+			`error loading persisted instance "%s": %s`,
+			instanceID,
+			err,
+		)
+	}
+	if !ok || instance.BlueGreen == nil {
+		return service.Instance{}, service.Instance{}, false, nil
+	}
+	var inPhase bool
+	for _, phase := range phases {
+		if instance.BlueGreen.Phase == phase {
+			inPhase = true
+			break
+		}
+	}
+	if !inPhase {
+		return service.Instance{}, service.Instance{}, false, nil
+	}
+	counterpartID := service.GetGreenInstanceID(instanceID)
+	if instance.BlueGreen.Phase == service.BlueGreenPhaseTearingDownBlue {
+		counterpartID = service.GetRetiredBlueInstanceID(instanceID)
+	}
+	counterpart, ok, err := b.store.GetInstance(counterpartID)
+	if err != nil {
+		return service.Instance{}, service.Instance{}, false, fmt.Errorf(
+			`error loading persisted instance "%s": %s`,
+			counterpartID,
+			err,
+		)
+	}
+	if !ok {
+		return service.Instance{}, service.Instance{}, false, b.handleUpdatingError(
+			instance,
+			task.GetJobName(),
+			nil,
+			fmt.Sprintf(
+				`instance "%s" does not exist in the data store`,
+				counterpartID,
+			),
+		)
+	}
+	return instance, counterpart, true, nil
+}
+
+// getBlueGreenUpdater returns the BlueGreenUpdater for the given instance. An
+// instance whose module isn't one can't have started a blue-green update, so
+// its absence fails the update.
+func (b *broker) getBlueGreenUpdater(
+	instance service.Instance,
+	stepName string,
+) (service.BlueGreenUpdater, error) {
+	updater, ok :=
+		instance.Service.GetServiceManager().(service.BlueGreenUpdater)
+	if !ok {
+		return nil, b.handleUpdatingError(
+			instance,
+			stepName,
+			nil,
+			fmt.Sprintf(
+				`service "%s" does not support blue-green updates`,
+				instance.ServiceID,
+			),
+		)
+	}
+	return updater, nil
+}
+
+// setBlueGreenPhase moves the blue-green update of the specified instance on
+// to the given phase
+func (b *broker) setBlueGreenPhase(
+	instanceID string,
+	stepName string,
+	phase string,
+) error {
+	instance, ok, err := b.store.GetInstance(instanceID)
+	if err != nil {
+		return b.handleUpdatingError(
+			instanceID,
+			stepName,
+			err,
+			"error loading persisted instance",
+		)
+	}
+	if !ok {
+		return b.handleUpdatingError(
+			instanceID,
+			stepName,
+			nil,
+			"instance does not exist in the data store",
+		)
+	}
+	instance.BlueGreen.Phase = phase
+	if err := b.store.WriteInstance(instance); err != nil {
+		return b.handleUpdatingError(
+			instance,
+			stepName,
+			err,
+			"error persisting instance",
+		)
+	}
+	return nil
+}
+
+// startBlueGreenRollback abandons the blue-green update of the specified
+// instance, which must not yet have put the green resources in service, and
+// returns the task that rolls it back
+func (b *broker) startBlueGreenRollback(
+	instanceID string,
+	stepName string,
+	e error,
+	msg string,
+) ([]async.Task, error) {
+	instance, ok, err := b.store.GetInstance(instanceID)
+	if err != nil {
+		return nil, b.handleUpdatingError(
+			instanceID,
+			stepName,
+			err,
+			"error loading persisted instance",
+		)
+	}
+	if !ok {
+		return nil, b.handleUpdatingError(
+			instanceID,
+			stepName,
+			nil,
+			"instance does not exist in the data store",
+		)
+	}
+	reason := fmt.Sprintf(`error executing step "%s": %s`, stepName, msg)
+	if e != nil {
+		reason = fmt.Sprintf("%s: %s", reason, e)
+	}
+	instance.BlueGreen.Phase = service.BlueGreenPhaseRollingBack
+	instance.BlueGreen.RollbackReason = reason
+	if err := b.store.WriteInstance(instance); err != nil {
+		return nil, b.handleUpdatingError(
+			instance,
+			stepName,
+			err,
+			"error persisting instance",
+		)
+	}
+	log.WithFields(log.Fields{
+		"instanceID": instanceID,
+		"reason":     reason,
+	}).Warn("blue-green update failed; rolling back")
+	return []async.Task{
+		async.NewTask(
+			"rollBackBlueGreenUpdate",
+			map[string]string{
+				"instanceID": instanceID,
+			},
+		),
+	}, nil
+}
+
+// handleBlueGreenDeprovisioningError records the failure of a blue-green
+// update's outgoing instance to deprovision, on both the outgoing instance and
+// the instance being updated. Either way, the update is left failed: with the
+// green resources in service but the blue resources still in existence, or
+// with the blue resources still in service but the green resources not yet
+// gone.
+func (b *broker) handleBlueGreenDeprovisioningError(
+	instance service.Instance,
+	outgoing service.Instance,
+	stepName string,
+	e error,
+	msg string,
+) error {
+	ret := b.handleDeprovisioningError(outgoing, stepName, e, msg)
+	if instance.BlueGreen.Phase == service.BlueGreenPhaseRollingBack {
+		msg = fmt.Sprintf(
+			"error rolling back blue-green update (%s)",
+			instance.BlueGreen.RollbackReason,
+		)
+	} else {
+		msg = "error tearing down the resources replaced by blue-green update"
+	}
+	return b.handleUpdatingError(instance, stepName, ret, msg)
+}
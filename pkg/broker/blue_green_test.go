@@ -0,0 +1,247 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlueGreenUpdateCutsOverAndTearsDownBlue(t *testing.T) {
+	b, serviceManager, blue := getBlueGreenTestBroker(t, "binding")
+	serviceManager.BindingCutOverBehavior = func(
+		_ context.Context,
+		from service.Instance,
+		to service.Instance,
+		binding service.Binding,
+	) (service.BindingDetails, error) {
+		assert.Equal(t, blue.InstanceID, from.InstanceID)
+		assert.Equal(
+			t,
+			service.GetGreenInstanceID(blue.InstanceID),
+			to.InstanceID,
+		)
+		return &fake.BindingDetails{}, nil
+	}
+	jobNames := runBlueGreenTasks(t, b, getGreenProvisioningTask(blue))
+	assert.Equal(
+		t,
+		[]string{
+			"executeGreenProvisioningStep",
+			"validateGreenInstance",
+			"cutOverBindings",
+			"executeBlueGreenDeprovisioningStep",
+			"executeBlueGreenDeprovisioningStep",
+		},
+		jobNames,
+	)
+	instance, ok, err := b.store.GetInstance(blue.InstanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, service.InstanceStateUpdated, instance.Status)
+	assert.Nil(t, instance.BlueGreen)
+	// The instance now represents the green resources
+	assert.Equal(
+		t,
+		"green",
+		instance.ProvisioningParameters.(*fake.ProvisioningParameters).
+			SomeParameter,
+	)
+	for _, instanceID := range []string{
+		service.GetGreenInstanceID(blue.InstanceID),
+		service.GetRetiredBlueInstanceID(blue.InstanceID),
+	} {
+		_, ok, err = b.store.GetInstance(instanceID)
+		assert.Nil(t, err)
+		assert.False(t, ok, instanceID)
+	}
+}
+
+func TestBlueGreenUpdateRollsBackGreenThatFailsValidation(t *testing.T) {
+	b, serviceManager, blue := getBlueGreenTestBroker(t, "binding")
+	serviceManager.GreenValidationBehavior = func(
+		context.Context,
+		service.Instance,
+		service.Instance,
+	) error {
+		return errors.New("data copy incomplete")
+	}
+	var cutOver bool
+	serviceManager.BindingCutOverBehavior = func(
+		_ context.Context,
+		_ service.Instance,
+		_ service.Instance,
+		binding service.Binding,
+	) (service.BindingDetails, error) {
+		cutOver = true
+		return binding.Details, nil
+	}
+	var torndown []string
+	serviceManager.DeprovisionCleanupBehavior = func(
+		_ context.Context,
+		instance service.Instance,
+	) (service.InstanceDetails, error) {
+		torndown = append(torndown, instance.InstanceID)
+		return instance.Details, nil
+	}
+	jobNames := runBlueGreenTasks(t, b, getGreenProvisioningTask(blue))
+	assert.Contains(t, jobNames, "rollBackBlueGreenUpdate")
+	assert.False(t, cutOver)
+	assert.Equal(
+		t,
+		[]string{service.GetGreenInstanceID(blue.InstanceID)},
+		torndown,
+	)
+	instance, _, err := b.store.GetInstance(blue.InstanceID)
+	assert.Nil(t, err)
+	assert.Equal(t, service.InstanceStateUpdatingFailed, instance.Status)
+	assert.Contains(t, instance.StatusReason, "rolled back")
+	assert.Contains(t, instance.StatusReason, "data copy incomplete")
+	assert.Nil(t, instance.BlueGreen)
+	// The instance still represents the blue resources
+	assert.Equal(
+		t,
+		"blue",
+		instance.ProvisioningParameters.(*fake.ProvisioningParameters).
+			SomeParameter,
+	)
+	_, ok, err :=
+		b.store.GetInstance(service.GetGreenInstanceID(blue.InstanceID))
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
+
+func TestBlueGreenRollbackCutsBindingsBackOverToBlue(t *testing.T) {
+	b, serviceManager, blue :=
+		getBlueGreenTestBroker(t, "binding-1", "binding-2")
+	greenInstanceID := service.GetGreenInstanceID(blue.InstanceID)
+	type cutOver struct {
+		bindingID string
+		toGreen   bool
+	}
+	var cutOvers []cutOver
+	serviceManager.BindingCutOverBehavior = func(
+		_ context.Context,
+		_ service.Instance,
+		to service.Instance,
+		binding service.Binding,
+	) (service.BindingDetails, error) {
+		toGreen := to.InstanceID == greenInstanceID
+		// The first binding is cut over, but the second can't be
+		if toGreen && len(cutOvers) > 0 {
+			return nil, errors.New("login could not be created")
+		}
+		cutOvers = append(cutOvers, cutOver{binding.BindingID, toGreen})
+		return binding.Details, nil
+	}
+	jobNames := runBlueGreenTasks(t, b, getGreenProvisioningTask(blue))
+	assert.Contains(t, jobNames, "rollBackBlueGreenUpdate")
+	assert.Len(t, cutOvers, 2)
+	assert.True(t, cutOvers[0].toGreen)
+	assert.False(t, cutOvers[1].toGreen)
+	assert.Equal(t, cutOvers[0].bindingID, cutOvers[1].bindingID)
+	instance, _, err := b.store.GetInstance(blue.InstanceID)
+	assert.Nil(t, err)
+	assert.Equal(t, service.InstanceStateUpdatingFailed, instance.Status)
+	assert.Contains(t, instance.StatusReason, "login could not be created")
+}
+
+func TestBlueGreenTasksForInstancesThatMovedOnDoNothing(t *testing.T) {
+	b, _, blue := getBlueGreenTestBroker(t)
+	tasks, err := b.cutOverBindings(
+		context.Background(),
+		async.NewTask(
+			"cutOverBindings",
+			map[string]string{
+				"instanceID": blue.InstanceID,
+			},
+		),
+	)
+	assert.Nil(t, err)
+	assert.Empty(t, tasks)
+	instance, _, err := b.store.GetInstance(blue.InstanceID)
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		service.BlueGreenPhaseProvisioningGreen,
+		instance.BlueGreen.Phase,
+	)
+}
+
+// getBlueGreenTestBroker returns a broker and an instance, with the given
+// bindings, whose blue-green update has just been started
+func getBlueGreenTestBroker(
+	t *testing.T,
+	bindingIDs ...string,
+) (*broker, *fake.ServiceManager, service.Instance) {
+	b, fakeModule, blue := getConnectivityValidationTestBroker(t)
+	blue.ProvisioningParameters = &fake.ProvisioningParameters{
+		SomeParameter: "blue",
+	}
+	green := blue
+	green.InstanceID = service.GetGreenInstanceID(blue.InstanceID)
+	green.ProvisioningParameters = &fake.ProvisioningParameters{
+		SomeParameter: "green",
+	}
+	assert.Nil(t, b.store.WriteInstance(green))
+	blue.Status = service.InstanceStateUpdating
+	blue.BlueGreen = &service.BlueGreenState{
+		Phase:   service.BlueGreenPhaseProvisioningGreen,
+		Started: time.Now(),
+	}
+	assert.Nil(t, b.store.WriteInstance(blue))
+	for _, bindingID := range bindingIDs {
+		assert.Nil(t, b.store.WriteBinding(service.Binding{
+			BindingID:         bindingID,
+			InstanceID:        blue.InstanceID,
+			ServiceID:         blue.ServiceID,
+			BindingParameters: &fake.BindingParameters{},
+			Details:           &fake.BindingDetails{},
+			Status:            service.BindingStateBound,
+		}))
+	}
+	return b, fakeModule.ServiceManager, blue
+}
+
+func getGreenProvisioningTask(blue service.Instance) async.Task {
+	return async.NewTask(
+		"executeGreenProvisioningStep",
+		map[string]string{
+			"stepName":   "run",
+			"instanceID": blue.InstanceID,
+		},
+	)
+}
+
+// runBlueGreenTasks executes the given task, and every task that follows from
+// it, and returns the names of the jobs executed
+func runBlueGreenTasks(
+	t *testing.T,
+	b *broker,
+	task async.Task,
+) []string {
+	jobs := map[string]async.JobFn{
+		"executeGreenProvisioningStep":       b.executeGreenProvisioningStep,
+		"validateGreenInstance":              b.validateGreenInstance,
+		"cutOverBindings":                    b.cutOverBindings,
+		"rollBackBlueGreenUpdate":            b.rollBackBlueGreenUpdate,
+		"executeBlueGreenDeprovisioningStep": b.executeBlueGreenDeprovisioningStep, // nolint: lll
+	}
+	var jobNames []string
+	tasks := []async.Task{task}
+	for len(tasks) > 0 {
+		task, tasks = tasks[0], tasks[1:]
+		job, ok := jobs[task.GetJobName()]
+		assert.True(t, ok, task.GetJobName())
+		jobNames = append(jobNames, task.GetJobName())
+		nextTasks, err := job(context.Background(), task)
+		assert.Nil(t, err, task.GetJobName())
+		tasks = append(tasks, nextTasks...)
+	}
+	return jobNames
+}
@@ -4,14 +4,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/Azure/open-service-broker-azure/pkg/api"
 	"github.com/Azure/open-service-broker-azure/pkg/async"
 	redisAsync "github.com/Azure/open-service-broker-azure/pkg/async/redis"
 	"github.com/Azure/open-service-broker-azure/pkg/crypto"
+	"github.com/Azure/open-service-broker-azure/pkg/gitops"
 	"github.com/Azure/open-service-broker-azure/pkg/http/filter"
+	"github.com/Azure/open-service-broker-azure/pkg/notification"
+	"github.com/Azure/open-service-broker-azure/pkg/redaction"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 	"github.com/Azure/open-service-broker-azure/pkg/storage"
+	"github.com/Azure/open-service-broker-azure/pkg/telemetry"
 	log "github.com/Sirupsen/logrus"
 	"github.com/go-redis/redis"
 )
@@ -41,31 +46,161 @@ type Broker interface {
 }
 
 type broker struct {
-	store       storage.Store
-	apiServer   api.Server
-	asyncEngine async.Engine
-	catalog     service.Catalog
+	store                 storage.Store
+	apiServer             api.Server
+	asyncEngine           async.Engine
+	catalog               service.Catalog
+	provisioningLimits    ProvisioningLimits
+	provisioningSemaphore provisioningSemaphore
+	retryPolicy           RetryPolicy
+	// teardownRetryPolicy governs the retrying of failed deprovisioning steps
+	// that have dependencies. Other deprovisioning steps are never retried.
+	teardownRetryPolicy RetryPolicy
+	// connectivityValidated is keyed by service ID and indicates which
+	// services' instances must pass connectivity validation before they are
+	// considered provisioned
+	connectivityValidated map[string]bool
+	// stepOrders is keyed by service ID and overrides the order in which the
+	// provisioning steps of that service's instances are executed
+	stepOrders map[string][]string
+	// skippedSuccessCriteria is keyed by plans, identified as
+	// serviceName/planName, and indicates which of their optional success
+	// criteria aren't checked
+	skippedSuccessCriteria map[string]map[string]bool
+	// subscribers are notified of the outcome of provisioning operations and
+	// of idle instances
+	subscribers                []notification.Subscriber
+	idleDetection              IdleDetectionConfig
+	idleCheckSchedule          checkSchedule
+	driftDetection             DriftDetectionConfig
+	driftCheckSchedule         checkSchedule
+	credentialRotation         CredentialRotationConfig
+	credentialRotationSchedule checkSchedule
+	provisioningSLA            service.ProvisioningSLA
+	failureGrace               FailureGraceConfig
+	// failureGraceWindows is keyed by service ID and indicates how long failed
+	// provisioning steps of that service's instances are retried, once the
+	// retry policy has given up, before provisioning is considered failed
+	failureGraceWindows map[string]time.Duration
+	approval            ApprovalConfig
+	// throttle, if non-nil, paces the execution of steps of instances whose
+	// services make requests to throttled resource providers
+	throttle service.ResourceProviderThrottle
+	// resourceProviders is keyed by service ID and indicates which Azure
+	// resource providers that service's steps make requests to
+	resourceProviders map[string][]string
+	keyRotation       KeyRotationConfig
+	// replicatedStore, if non-nil, is the store (also b.store) that replicates
+	// instances and bindings to a secondary store
+	replicatedStore storage.ReplicatedStore
+	// subscriptionRouting determines which Azure subscription each new instance
+	// is provisioned into
+	subscriptionRouting api.SubscriptionRouting
+	// provisioningCapacity caps how many instances of each module's services
+	// may be provisioning at once
+	provisioningCapacity service.ProvisioningCapacityPolicy
+	// recordStepExecutions indicates whether instances record the execution of
+	// their provisioning steps, so that re-delivered steps aren't re-executed
+	recordStepExecutions bool
+	debugTracing         DebugTracingConfig
+	// telemetrySampler, if non-nil, determines which instances' successful
+	// steps are traced when every instance's steps may be
+	telemetrySampler telemetry.Sampler
+	// manifestWriter, if non-nil, rewrites the GitOps manifests of bindings
+	// whose credentials are rotated and deletes those of bindings that are
+	// revoked
+	manifestWriter gitops.ManifestWriter
+}
+
+// Config represents the components and options a Broker is assembled from.
+// Options that are left unset assume their zero values, which in most cases
+// disable the feature they configure.
+type Config struct {
+	StorageRedisClient *redis.Client
+	AsyncRedisClient   *redis.Client
+	// SecondaryStorageRedisClient, if non-nil, is the Redis that instances and
+	// bindings are replicated to for disaster recovery
+	SecondaryStorageRedisClient *redis.Client
+	Codec                       crypto.Codec
+	FilterChain                 filter.Filter
+	// Modules whose stability is less than MinStability are ignored
+	Modules                   []service.Module
+	MinStability              service.Stability
+	DefaultAzureLocation      string
+	DefaultAzureResourceGroup string
+	ProvisioningLimits        ProvisioningLimits
+	RetryPolicy               RetryPolicy
+	// TeardownRetryPolicy governs the retrying of failed deprovisioning steps
+	// that have dependencies
+	TeardownRetryPolicy RetryPolicy
+	// ConnectivityValidationModules names the modules whose instances must pass
+	// connectivity validation before they are considered provisioned
+	ConnectivityValidationModules []string
+	FairScheduling                redisAsync.FairSchedulingConfig
+	WorkerPool                    redisAsync.WorkerPoolConfig
+	TenantPools                   redisAsync.TenantPoolsConfig
+	NotificationSubscribers       []notification.Subscriber
+	// StepOrderOverrides is keyed by service name and overrides the order in
+	// which the provisioning steps of that service's instances are executed
+	StepOrderOverrides map[string][]string
+	// SkippedSuccessCriteria is keyed by plans, identified as
+	// serviceName/planName, and names those of their optional success criteria
+	// that aren't checked
+	SkippedSuccessCriteria      map[string][]string
+	AuditProvisioningParameters bool
+	// RecordStepExecutions indicates whether instances record the execution of
+	// their provisioning steps, so that re-delivered steps aren't re-executed
+	RecordStepExecutions      bool
+	IdleDetection             IdleDetectionConfig
+	DriftDetection            DriftDetectionConfig
+	CredentialRotation        CredentialRotationConfig
+	KeyRotation               KeyRotationConfig
+	PricingTable              *service.PricingTable
+	ResponseRedactionMode     redaction.Mode
+	MetadataTagging           service.MetadataTagging
+	ProvisioningDeduplication api.ProvisioningDeduplication
+	ProvisioningSLA           service.ProvisioningSLA
+	// ProvisioningOverdueFactor is the multiple of its estimated duration
+	// beyond which a provisioning operation is reported as taking longer than
+	// expected. Zero means it never is.
+	ProvisioningOverdueFactor float64
+	ProvisioningCapacity      ProvisioningCapacityConfig
+	FailureGrace              FailureGraceConfig
+	Approval                  ApprovalConfig
+	// ResourceProviderThrottle, if non-nil, paces the execution of steps that
+	// make requests to throttled Azure resource providers
+	ResourceProviderThrottle service.ResourceProviderThrottle
+	// CostCenterLabel names the tag, or provisioning context label, whose value
+	// is the cost center an instance's costs are charged back to
+	CostCenterLabel     string
+	SubscriptionRouting SubscriptionRoutingConfig
+	ExpiryPolicy        service.ExpiryPolicy
+	// ManifestWriter, if non-nil, writes a GitOps manifest for each binding
+	ManifestWriter gitops.ManifestWriter
+	DebugTracing   DebugTracingConfig
+	// TelemetrySampler, if non-nil, samples the instances whose successful
+	// steps are traced when every instance's steps may be, and caps the series
+	// of the broker's metrics
+	TelemetrySampler telemetry.Sampler
 }
 
 // NewBroker returns a new Broker
-func NewBroker(
-	storageRedisClient *redis.Client,
-	asyncRedisClient *redis.Client,
-	codec crypto.Codec,
-	filterChain filter.Filter,
-	modules []service.Module,
-	minStability service.Stability,
-	defaultAzureLocation string,
-	defaultAzureResourceGroup string,
-) (Broker, error) {
+func NewBroker(config Config) (Broker, error) {
 	// Consolidate the catalogs from all the individual modules into a single
 	// catalog. Check as we go along to make sure that no two modules provide
 	// services having the same ID.
 	services := []service.Service{}
 	usedServiceIDs := map[string]string{}
-	for _, module := range modules {
-		if module.GetStability() >= minStability {
+	connectivityValidationServiceIDs := map[string]bool{}
+	failureGraceWindows := map[string]time.Duration{}
+	resourceProviders := map[string][]string{}
+	for _, module := range config.Modules {
+		if module.GetStability() >= config.MinStability {
 			moduleName := module.GetName()
+			var moduleResourceProviders []string
+			if user, ok := module.(service.ResourceProviderUser); ok {
+				moduleResourceProviders = user.GetResourceProviders()
+			}
 			catalog, err := module.GetCatalog()
 			if err != nil {
 				return nil, fmt.Errorf(
@@ -86,17 +221,174 @@ func NewBroker(
 				}
 				services = append(services, svc)
 				usedServiceIDs[serviceID] = moduleName
+				for _, name := range config.ConnectivityValidationModules {
+					if name == moduleName {
+						connectivityValidationServiceIDs[serviceID] = true
+					}
+				}
+				if window, ok := config.FailureGrace.WindowByModule[moduleName]; ok {
+					failureGraceWindows[serviceID] = window
+				}
+				if len(moduleResourceProviders) > 0 {
+					resourceProviders[serviceID] = moduleResourceProviders
+				}
 			}
 		}
 	}
+	stepOrders, err := getStepOrders(services, config.StepOrderOverrides)
+	if err != nil {
+		return nil, err
+	}
+	skippedSuccessCriteriaByPlan, err := getSkippedSuccessCriteria(
+		services,
+		config.SkippedSuccessCriteria,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err = validateIdlePolicies(services, config.IdleDetection); err != nil {
+		return nil, err
+	}
+	if err = validateDriftPolicies(services, config.DriftDetection); err != nil {
+		return nil, err
+	}
+	if err = validatePricingTable(services, config.PricingTable); err != nil {
+		return nil, err
+	}
+	if err = validateProvisioningSLA(
+		services,
+		config.ProvisioningSLA,
+	); err != nil {
+		return nil, err
+	}
+	if err = validateExpiryPolicy(services, config.ExpiryPolicy); err != nil {
+		return nil, err
+	}
+	approvalPolicy, err := getApprovalPolicy(
+		services,
+		usedServiceIDs,
+		config.Approval,
+		config.PricingTable,
+	)
+	if err != nil {
+		return nil, err
+	}
+	provisioningCapacityPolicy, err := getProvisioningCapacityPolicy(
+		usedServiceIDs,
+		config.ProvisioningCapacity,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var credentialRotationPolicy api.CredentialRotationPolicy
+	if config.CredentialRotation.Enabled {
+		if config.CredentialRotation.SecretStore == nil {
+			return nil, errors.New(
+				"credential rotation requires a secret store to deliver rotated " +
+					"credentials to",
+			)
+		}
+		credentialRotationPolicy, err = api.NewCredentialRotationPolicy(
+			config.CredentialRotation.DefaultTTL,
+			config.CredentialRotation.MinTTL,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
 	catalog := service.NewCatalog(services)
-	b := &broker{
-		store:       storage.NewStore(storageRedisClient, catalog, codec),
-		asyncEngine: redisAsync.NewEngine(asyncRedisClient),
-		catalog:     catalog,
+	subscriptionCatalogs, err := getSubscriptionCatalogs(
+		config.SubscriptionRouting,
+		catalog,
+		config.MinStability,
+	)
+	if err != nil {
+		return nil, err
+	}
+	// The async engine consults the broker before executing each task, but the
+	// broker doesn't exist until the engine does
+	var b *broker
+	var throttle redisAsync.ThrottleFn
+	if config.ResourceProviderThrottle != nil {
+		throttle = func(task async.Task) time.Duration {
+			return b.getThrottlingDelay(task)
+		}
+	}
+	asyncEngine := redisAsync.NewEngine(
+		config.AsyncRedisClient,
+		config.FairScheduling,
+		config.WorkerPool,
+		config.TenantPools,
+		throttle,
+	)
+	store := storage.NewMultiSubscriptionStore(
+		config.StorageRedisClient,
+		catalog,
+		subscriptionCatalogs,
+		config.Codec,
+	)
+	var replicatedStore storage.ReplicatedStore
+	if config.SecondaryStorageRedisClient != nil {
+		replicatedStore = storage.NewReplicatedStore(
+			store,
+			storage.NewMultiSubscriptionStore(
+				config.SecondaryStorageRedisClient,
+				catalog,
+				subscriptionCatalogs,
+				config.Codec,
+			),
+			config.StorageRedisClient,
+			asyncEngine,
+		)
+		store = replicatedStore
+	}
+	b = &broker{
+		store:              store,
+		asyncEngine:        asyncEngine,
+		catalog:            catalog,
+		provisioningLimits: config.ProvisioningLimits,
+		provisioningSemaphore: newRedisProvisioningSemaphore(
+			config.StorageRedisClient,
+			config.ProvisioningLimits.SlotTTL,
+		),
+		retryPolicy:            config.RetryPolicy,
+		teardownRetryPolicy:    config.TeardownRetryPolicy,
+		connectivityValidated:  connectivityValidationServiceIDs,
+		subscribers:            config.NotificationSubscribers,
+		stepOrders:             stepOrders,
+		skippedSuccessCriteria: skippedSuccessCriteriaByPlan,
+		idleDetection:          config.IdleDetection,
+		idleCheckSchedule: newRedisCheckSchedule(
+			config.StorageRedisClient,
+			"idle-checks:next",
+		),
+		driftDetection: config.DriftDetection,
+		driftCheckSchedule: newRedisCheckSchedule(
+			config.StorageRedisClient,
+			"drift-checks:next",
+		),
+		credentialRotation: config.CredentialRotation,
+		credentialRotationSchedule: newRedisCheckSchedule(
+			config.StorageRedisClient,
+			"credential-rotation-checks:next",
+		),
+		provisioningSLA:      config.ProvisioningSLA,
+		failureGrace:         config.FailureGrace,
+		failureGraceWindows:  failureGraceWindows,
+		approval:             config.Approval,
+		throttle:             config.ResourceProviderThrottle,
+		resourceProviders:    resourceProviders,
+		keyRotation:          config.KeyRotation,
+		replicatedStore:      replicatedStore,
+		subscriptionRouting:  config.SubscriptionRouting.Routing,
+		provisioningCapacity: provisioningCapacityPolicy,
+		recordStepExecutions: config.RecordStepExecutions,
+		debugTracing:         withDebugTracingDefaults(config.DebugTracing),
+		telemetrySampler:     config.TelemetrySampler,
+		manifestWriter:       config.ManifestWriter,
 	}
 
-	err := b.asyncEngine.RegisterJob(
+	err = b.asyncEngine.RegisterJob(
 		"executeProvisioningStep",
 		b.executeProvisioningStep,
 	)
@@ -105,12 +397,77 @@ func NewBroker(
 			"error registering async job for executing provisioning steps",
 		)
 	}
+	err = b.asyncEngine.RegisterJob(awaitApprovalStepName, b.awaitApproval)
+	if err != nil {
+		return nil, errors.New(
+			"error registering async job for awaiting approval of provisioning",
+		)
+	}
+	err = b.asyncEngine.RegisterJob(
+		"validateConnectivity",
+		b.validateConnectivity,
+	)
+	if err != nil {
+		return nil, errors.New(
+			"error registering async job for validating connectivity",
+		)
+	}
+	err = b.asyncEngine.RegisterJob("sendNotification", b.sendNotification)
+	if err != nil {
+		return nil, errors.New(
+			"error registering async job for sending notifications",
+		)
+	}
 	err = b.asyncEngine.RegisterJob("executeUpdatingStep", b.executeUpdatingStep)
 	if err != nil {
 		return nil, errors.New(
 			"error registering async job for executing updating steps",
 		)
 	}
+	err = b.asyncEngine.RegisterJob(
+		"executeGreenProvisioningStep",
+		b.executeGreenProvisioningStep,
+	)
+	if err != nil {
+		return nil, errors.New(
+			"error registering async job for executing green provisioning steps",
+		)
+	}
+	err = b.asyncEngine.RegisterJob(
+		"validateGreenInstance",
+		b.validateGreenInstance,
+	)
+	if err != nil {
+		return nil, errors.New(
+			"error registering async job for validating green instances",
+		)
+	}
+	err = b.asyncEngine.RegisterJob("cutOverBindings", b.cutOverBindings)
+	if err != nil {
+		return nil, errors.New(
+			"error registering async job for cutting bindings over to green " +
+				"instances",
+		)
+	}
+	err = b.asyncEngine.RegisterJob(
+		"rollBackBlueGreenUpdate",
+		b.rollBackBlueGreenUpdate,
+	)
+	if err != nil {
+		return nil, errors.New(
+			"error registering async job for rolling back blue-green updates",
+		)
+	}
+	err = b.asyncEngine.RegisterJob(
+		"executeBlueGreenDeprovisioningStep",
+		b.executeBlueGreenDeprovisioningStep,
+	)
+	if err != nil {
+		return nil, errors.New(
+			"error registering async job for executing blue-green deprovisioning " +
+				"steps",
+		)
+	}
 	err = b.asyncEngine.RegisterJob(
 		"executeDeprovisioningStep",
 		b.executeDeprovisioningStep,
@@ -121,6 +478,119 @@ func NewBroker(
 		)
 	}
 
+	err = b.asyncEngine.RegisterJob(
+		deactivateInstanceStepName,
+		b.deactivateInstance,
+	)
+	if err != nil {
+		return nil, errors.New(
+			"error registering async job for deactivating instances whose " +
+				"activation is deferred",
+		)
+	}
+	err = b.asyncEngine.RegisterJob("activateInstance", b.activateInstance)
+	if err != nil {
+		return nil, errors.New(
+			"error registering async job for activating instances",
+		)
+	}
+	err = b.asyncEngine.RegisterJob(
+		"warnOfInstanceExpiry",
+		b.warnOfInstanceExpiry,
+	)
+	if err != nil {
+		return nil, errors.New(
+			"error registering async job for warning of sandbox instances' expiry",
+		)
+	}
+	err = b.asyncEngine.RegisterJob("expireInstance", b.expireInstance)
+	if err != nil {
+		return nil, errors.New(
+			"error registering async job for expiring sandbox instances",
+		)
+	}
+	err = b.asyncEngine.RegisterJob("resumeInstance", b.resumeInstance)
+	if err != nil {
+		return nil, errors.New(
+			"error registering async job for resuming suspended instances",
+		)
+	}
+	err = b.asyncEngine.RegisterJob(
+		quarantineInstanceJobName,
+		b.quarantineInstance,
+	)
+	if err != nil {
+		return nil, errors.New(
+			"error registering async job for quarantining instances",
+		)
+	}
+	err = b.asyncEngine.RegisterJob(releaseInstanceJobName, b.releaseInstance)
+	if err != nil {
+		return nil, errors.New(
+			"error registering async job for releasing quarantined instances",
+		)
+	}
+	err = b.asyncEngine.RegisterJob("checkIdleInstances", b.checkIdleInstances)
+	if err != nil {
+		return nil, errors.New(
+			"error registering async job for checking for idle instances",
+		)
+	}
+	err = b.asyncEngine.RegisterJob(
+		"checkInstanceIdleness",
+		b.checkInstanceIdleness,
+	)
+	if err != nil {
+		return nil, errors.New(
+			"error registering async job for checking instance idleness",
+		)
+	}
+
+	err = b.asyncEngine.RegisterJob(
+		"checkDriftedInstances",
+		b.checkDriftedInstances,
+	)
+	if err != nil {
+		return nil, errors.New(
+			"error registering async job for checking for drifted instances",
+		)
+	}
+	err = b.asyncEngine.RegisterJob("checkInstanceDrift", b.checkInstanceDrift)
+	if err != nil {
+		return nil, errors.New(
+			"error registering async job for checking instance drift",
+		)
+	}
+
+	err = b.asyncEngine.RegisterJob(
+		"checkCredentialRotations",
+		b.checkCredentialRotations,
+	)
+	if err != nil {
+		return nil, errors.New(
+			"error registering async job for checking for credentials due to be " +
+				"rotated",
+		)
+	}
+	err = b.asyncEngine.RegisterJob(
+		"rotateBindingCredentials",
+		b.rotateBindingCredentials,
+	)
+	if err != nil {
+		return nil, errors.New(
+			"error registering async job for rotating binding credentials",
+		)
+	}
+	err = b.asyncEngine.RegisterJob(
+		"deliverBindingCredentials",
+		b.deliverBindingCredentials,
+	)
+	if err != nil {
+		return nil, errors.New(
+			"error registering async job for delivering binding credentials",
+		)
+	}
+
 	err = b.asyncEngine.RegisterJob("checkParentStatus", b.doCheckParentStatus)
 	if err != nil {
 		return nil, errors.New(
@@ -139,15 +609,73 @@ func NewBroker(
 		)
 	}
 
-	b.apiServer, err = api.NewServer(
-		8080,
-		b.store,
-		b.asyncEngine,
-		filterChain,
-		b.catalog,
-		defaultAzureLocation,
-		defaultAzureResourceGroup,
+	err = b.asyncEngine.RegisterJob(
+		rotateEncryptionKeyJobName,
+		b.rotateEncryptionKey,
 	)
+	if err != nil {
+		return nil, errors.New(
+			"error registering async job for rotating the encryption key",
+		)
+	}
+
+	if replicatedStore != nil {
+		err = b.asyncEngine.RegisterJob(
+			storage.ReplicateInstanceJobName,
+			b.replicateInstance,
+		)
+		if err != nil {
+			return nil, errors.New(
+				"error registering async job for replicating instances",
+			)
+		}
+		err = b.asyncEngine.RegisterJob(
+			storage.ReplicateBindingJobName,
+			b.replicateBinding,
+		)
+		if err != nil {
+			return nil, errors.New(
+				"error registering async job for replicating bindings",
+			)
+		}
+	}
+
+	var inFlightProvisions api.InFlightProvisionCounter
+	if counter, ok := newProvisioningSlotCounter(
+		config.ProvisioningLimits,
+		config.SubscriptionRouting.Routing.GetSubscriptionIDs(),
+		b.provisioningSemaphore,
+	); ok {
+		inFlightProvisions = counter
+	}
+
+	b.apiServer, err = api.NewServer(api.ServerConfig{
+		Port:                        8080,
+		Store:                       b.store,
+		AsyncEngine:                 b.asyncEngine,
+		FilterChain:                 config.FilterChain,
+		Catalog:                     b.catalog,
+		DefaultAzureLocation:        config.DefaultAzureLocation,
+		DefaultAzureResourceGroup:   config.DefaultAzureResourceGroup,
+		AuditProvisioningParameters: config.AuditProvisioningParameters,
+		PricingTable:                config.PricingTable,
+		ResponseRedactionMode:       config.ResponseRedactionMode,
+		MetadataTagging:             config.MetadataTagging,
+		ProvisioningDeduplication:   config.ProvisioningDeduplication,
+		ProvisioningSLA:             config.ProvisioningSLA,
+		ApprovalPolicy:              approvalPolicy,
+		Throttle:                    config.ResourceProviderThrottle,
+		CredentialRotation:          credentialRotationPolicy,
+		ProvisioningOverdueFactor:   config.ProvisioningOverdueFactor,
+		CostCenterLabel:             config.CostCenterLabel,
+		SubscriptionRouting:         config.SubscriptionRouting.Routing,
+		ExpiryPolicy:                config.ExpiryPolicy,
+		ProvisioningCapacity:        provisioningCapacityPolicy,
+		ManifestWriter:              config.ManifestWriter,
+		DebugTracingMode:            b.debugTracing.Mode,
+		InFlightProvisions:          inFlightProvisions,
+		TelemetrySampler:            config.TelemetrySampler,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -158,6 +686,29 @@ func NewBroker(
 // Start starts all broker components (e.g. API server and async execution
 // engine) and blocks until one of those components returns or fails.
 func (b *broker) Start(ctx context.Context) error {
+	if b.idleDetection.Enabled {
+		if err := b.scheduleIdleChecks(); err != nil {
+			return fmt.Errorf("error scheduling idle instance checks: %s", err)
+		}
+	}
+	if b.driftDetection.Enabled {
+		if err := b.scheduleDriftChecks(); err != nil {
+			return fmt.Errorf("error scheduling instance drift checks: %s", err)
+		}
+	}
+	if b.credentialRotation.Enabled {
+		if err := b.scheduleCredentialRotationChecks(); err != nil {
+			return fmt.Errorf(
+				"error scheduling credential rotation checks: %s",
+				err,
+			)
+		}
+	}
+	if b.keyRotation.Enabled {
+		if err := b.scheduleKeyRotation(); err != nil {
+			return fmt.Errorf("error scheduling key rotation: %s", err)
+		}
+	}
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 	errChan := make(chan error)
@@ -168,6 +719,10 @@ func (b *broker) Start(ctx context.Context) error {
 		case <-ctx.Done():
 		}
 	}()
+	// Start reloading the telemetry sampling
+	if b.telemetrySampler != nil {
+		go b.telemetrySampler.Run(ctx)
+	}
 	// Start api server
 	go func() {
 		select {
@@ -10,6 +10,7 @@ import (
 
 	fakeAPI "github.com/Azure/open-service-broker-azure/pkg/api/fake"
 	fakeAsync "github.com/Azure/open-service-broker-azure/pkg/async/fake"
+	"github.com/Azure/open-service-broker-azure/pkg/redaction"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 	"github.com/stretchr/testify/assert"
 )
@@ -141,16 +142,13 @@ func TestBrokerStartBlocksUntilContextCanceled(t *testing.T) {
 }
 
 func getTestBroker() (*broker, error) {
-	b, err := NewBroker(
-		nil,
-		nil,
-		nil,
-		filter.NewChain(),
-		nil,
-		service.StabilityExperimental,
-		"",
-		"",
-	)
+	b, err := NewBroker(Config{
+		FilterChain:           filter.NewChain(),
+		MinStability:          service.StabilityExperimental,
+		RetryPolicy:           NewDefaultRetryPolicy(),
+		ResponseRedactionMode: redaction.ModeFull,
+		TeardownRetryPolicy:   NewTeardownRetryPolicy(10, 30*time.Second),
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,64 @@
+package broker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// checkSchedule ensures that only one chain of a recurring check (e.g. of
+// instances for idleness) is ever scheduled, however many broker processes
+// share the async engine and however often they are restarted
+type checkSchedule interface {
+	// claim records the given check as the next one scheduled if no check is
+	// recorded or if the given previous check is the one recorded. It returns a
+	// bool indicating whether the check was recorded. A record expires once the
+	// given ttl elapses so that a chain of checks that was lost is eventually
+	// replaced by the next broker process to start.
+	claim(checkID string, previousCheckID string, ttl time.Duration) (bool, error)
+}
+
+var claimCheckScript = redis.NewScript(`
+local current = redis.call("GET", KEYS[1])
+if current == false or current == ARGV[2] then
+  redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[3])
+  return 1
+end
+return 0
+`)
+
+type redisCheckSchedule struct {
+	redisClient *redis.Client
+	// key is the key under which the next scheduled check is recorded
+	key string
+}
+
+func newRedisCheckSchedule(
+	redisClient *redis.Client,
+	key string,
+) checkSchedule {
+	return &redisCheckSchedule{
+		redisClient: redisClient,
+		key:         key,
+	}
+}
+
+func (r *redisCheckSchedule) claim(
+	checkID string,
+	previousCheckID string,
+	ttl time.Duration,
+) (bool, error) {
+	res, err := claimCheckScript.Run(
+		r.redisClient,
+		[]string{r.key},
+		checkID,
+		previousCheckID,
+		int64(ttl/time.Millisecond),
+	).Result()
+	if err != nil {
+		return false, fmt.Errorf("error scheduling check: %s", err)
+	}
+	claimed, _ := res.(int64)
+	return claimed == 1, nil
+}
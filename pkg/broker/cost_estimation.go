@@ -0,0 +1,65 @@
+package broker
+
+import (
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// validatePricingTable checks that every service and plan named in the given
+// pricing table's entries is known. An entry naming a plan must also name the
+// service it belongs to, since plan names are only unique within a service.
+func validatePricingTable(
+	services []service.Service,
+	pricingTable *service.PricingTable,
+) error {
+	if pricingTable == nil {
+		return nil
+	}
+	for i, entry := range pricingTable.GetEntries() {
+		if entry.Service == "" {
+			if entry.Plan != "" {
+				return fmt.Errorf(
+					`pricing table entry at index %d names plan "%s" but no service`,
+					i,
+					entry.Plan,
+				)
+			}
+			continue
+		}
+		var svc service.Service
+		for _, s := range services {
+			if s.GetName() == entry.Service {
+				svc = s
+				break
+			}
+		}
+		if svc == nil {
+			return fmt.Errorf(
+				`pricing table entry at index %d names unknown service "%s"`,
+				i,
+				entry.Service,
+			)
+		}
+		if entry.Plan == "" {
+			continue
+		}
+		var found bool
+		for _, plan := range svc.GetPlans() {
+			if plan.GetName() == entry.Plan {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf(
+				`pricing table entry at index %d names unknown plan "%s" of `+
+					`service "%s"`,
+				i,
+				entry.Plan,
+				entry.Service,
+			)
+		}
+	}
+	return nil
+}
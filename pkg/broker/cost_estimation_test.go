@@ -0,0 +1,36 @@
+package broker
+
+import (
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePricingTable(t *testing.T) {
+	b, _, _ := getConnectivityValidationTestBroker(t)
+	services := b.catalog.GetServices()
+	for _, testCase := range []struct {
+		entries string
+		valid   bool
+	}{
+		{`[{"sku": "small", "monthlyCost": 1}]`, true},
+		{`[{"service": "fake", "plan": "standard", "monthlyCost": 1}]`, true},
+		{`[{"service": "bogus", "monthlyCost": 1}]`, false},
+		{`[{"service": "fake", "plan": "bogus", "monthlyCost": 1}]`, false},
+		// Plan names are only unique within a service
+		{`[{"plan": "standard", "monthlyCost": 1}]`, false},
+	} {
+		pricingTable, err := service.NewPricingTable(
+			"USD",
+			[]byte(testCase.entries),
+		)
+		assert.Nil(t, err)
+		err = validatePricingTable(services, pricingTable)
+		if testCase.valid {
+			assert.Nil(t, err, testCase.entries)
+		} else {
+			assert.NotNil(t, err, testCase.entries)
+		}
+	}
+}
@@ -0,0 +1,321 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	"github.com/Azure/open-service-broker-azure/pkg/secrets"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	log "github.com/Sirupsen/logrus"
+	uuid "github.com/satori/go.uuid"
+)
+
+// CredentialRotationConfig represents whether, and how often, the broker
+// replaces the credentials of bindings to services whose ServiceManagers
+// implement service.CredentialRotator. Each binding's credential is replaced
+// once its TTL elapses and the new one is delivered to SecretStore.
+type CredentialRotationConfig struct {
+	Enabled bool
+	// DefaultTTL applies to bindings that don't request a TTL of their own
+	DefaultTTL time.Duration
+	// MinTTL is the shortest TTL a binding may request
+	MinTTL time.Duration
+	// CheckInterval is how long the broker waits between checks for bindings
+	// whose credentials are due to be replaced
+	CheckInterval time.Duration
+	SecretStore   secrets.Store
+}
+
+// getCredentialRotationScheduleTTL returns how long a scheduled check for
+// credentials that are due to be rotated remains recorded
+func (b *broker) getCredentialRotationScheduleTTL() time.Duration {
+	return 3 * b.credentialRotation.CheckInterval
+}
+
+// scheduleCredentialRotationChecks starts the chain of recurring checks for
+// credentials that are due to be rotated unless one is already scheduled
+func (b *broker) scheduleCredentialRotationChecks() error {
+	checkID := uuid.NewV4().String()
+	claimed, err := b.credentialRotationSchedule.claim(
+		checkID,
+		"",
+		b.getCredentialRotationScheduleTTL(),
+	)
+	if err != nil || !claimed {
+		return err
+	}
+	log.WithField("checkInterval", b.credentialRotation.CheckInterval).Info(
+		"scheduling credential rotation checks",
+	)
+	return b.asyncEngine.SubmitTask(
+		async.NewTask(
+			"checkCredentialRotations",
+			map[string]string{
+				"checkID": checkID,
+			},
+		),
+	)
+}
+
+// checkCredentialRotations fans out a task for rotating the credentials of
+// each binding whose TTL has elapsed, and for delivering those of each binding
+// whose last delivery failed, and schedules the next check. A check that has
+// been superseded does nothing.
+func (b *broker) checkCredentialRotations(
+	_ context.Context,
+	task async.Task,
+) ([]async.Task, error) {
+	checkID, ok := task.GetArgs()["checkID"]
+	if !ok {
+		return nil, errors.New(`missing required argument "checkID"`)
+	}
+	nextCheckID := uuid.NewV4().String()
+	claimed, err := b.credentialRotationSchedule.claim(
+		nextCheckID,
+		checkID,
+		b.getCredentialRotationScheduleTTL(),
+	)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"checkID": checkID,
+			"error":   err,
+		}).Error("error scheduling next credential rotation check; will retry")
+		return []async.Task{
+			async.NewDelayedTask(
+				"checkCredentialRotations",
+				task.GetArgs(),
+				b.credentialRotation.CheckInterval,
+			),
+		}, nil
+	}
+	if !claimed {
+		log.WithField("checkID", checkID).Debug(
+			"credential rotation check has been superseded; skipping",
+		)
+		return nil, nil
+	}
+	tasks := []async.Task{
+		async.NewDelayedTask(
+			"checkCredentialRotations",
+			map[string]string{
+				"checkID": nextCheckID,
+			},
+			b.credentialRotation.CheckInterval,
+		),
+	}
+	instanceIDs, err := b.store.GetInstanceIDs()
+	if err != nil {
+		// The next check is scheduled regardless
+		log.WithField("error", err).Error(
+			"error listing instances to check for credential rotations",
+		)
+		return tasks, nil
+	}
+	now := time.Now().UTC()
+	for _, instanceID := range instanceIDs {
+		bindingIDs, err := b.store.GetBindingIDs(instanceID)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"instanceID": instanceID,
+				"error":      err,
+			}).Error("error listing bindings to check for credential rotations")
+			continue
+		}
+		for _, bindingID := range bindingIDs {
+			binding, ok, err := b.store.GetBinding(bindingID)
+			if err != nil {
+				log.WithFields(log.Fields{
+					"bindingID": bindingID,
+					"error":     err,
+				}).Error("error loading binding to check for credential rotation")
+				continue
+			}
+			if !ok || binding.CredentialRotation == nil {
+				continue
+			}
+			args := map[string]string{
+				"instanceID": instanceID,
+				"bindingID":  bindingID,
+			}
+			if binding.IsCredentialRotationDue(now) {
+				tasks = append(
+					tasks,
+					async.NewTask("rotateBindingCredentials", args),
+				)
+			} else if binding.CredentialRotation.DeliveryPending {
+				tasks = append(
+					tasks,
+					async.NewTask("deliverBindingCredentials", args),
+				)
+			}
+		}
+	}
+	return tasks, nil
+}
+
+// rotateBindingCredentials replaces a single binding's credential, if it is
+// still due to be replaced, records when the next one is due, and hands off
+// delivery of the new credential to the secret store
+func (b *broker) rotateBindingCredentials(
+	ctx context.Context,
+	task async.Task,
+) ([]async.Task, error) {
+	instance, binding, ok, err := b.getRotatableBinding(task)
+	if err != nil || !ok {
+		return nil, err
+	}
+	// The binding may have been rotated by an earlier task since the check
+	// was scheduled. Instances that are mid-operation or suspended are picked
+	// up again by a later check.
+	if !binding.IsCredentialRotationDue(time.Now().UTC()) ||
+		instance.Status != service.InstanceStateProvisioned ||
+		instance.IsSuspended() {
+		return nil, nil
+	}
+	rotator, ok :=
+		instance.Service.GetServiceManager().(service.CredentialRotator)
+	if !ok {
+		return nil, nil
+	}
+	logFields := log.Fields{
+		"instanceID": instance.InstanceID,
+		"bindingID":  binding.BindingID,
+	}
+	details, err := rotator.RotateCredentials(ctx, instance, binding)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error rotating credentials of binding "%s": %s`,
+			binding.BindingID,
+			err,
+		)
+	}
+	now := time.Now().UTC()
+	binding.Details = details
+	binding.CredentialRotation.LastRotated = &now
+	binding.CredentialRotation.NextRotation =
+		now.Add(binding.CredentialRotation.TTL)
+	binding.CredentialRotation.DeliveryPending = true
+	if err := b.store.WriteBinding(binding); err != nil {
+		// The old credential has already been revoked, so this is as bad as
+		// it gets: nobody, the broker included, knows the new one
+		logFields["error"] = err
+		log.WithFields(logFields).Error(
+			"credential rotation error: error persisting rotated binding",
+		)
+		return nil, fmt.Errorf(
+			`error persisting binding "%s": %s`,
+			binding.BindingID,
+			err,
+		)
+	}
+	logFields["nextRotation"] = binding.CredentialRotation.NextRotation
+	log.WithFields(logFields).Info("rotated binding credentials")
+	return []async.Task{
+		async.NewTask("deliverBindingCredentials", task.GetArgs()),
+	}, nil
+}
+
+// deliverBindingCredentials writes a single binding's current credentials to
+// the secret store, and to the binding's GitOps manifest if it has one, if
+// they haven't been already. A failed delivery is retried by the next check
+// for credential rotations.
+func (b *broker) deliverBindingCredentials(
+	_ context.Context,
+	task async.Task,
+) ([]async.Task, error) {
+	instance, binding, ok, err := b.getRotatableBinding(task)
+	if err != nil || !ok || !binding.CredentialRotation.DeliveryPending {
+		return nil, err
+	}
+	credentials, err :=
+		instance.Service.GetServiceManager().GetCredentials(instance, binding)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error extracting credentials from binding "%s": %s`,
+			binding.BindingID,
+			err,
+		)
+	}
+	if err := b.credentialRotation.SecretStore.WriteCredentials(
+		instance.InstanceID,
+		binding.BindingID,
+		credentials,
+	); err != nil {
+		return nil, fmt.Errorf(
+			`error delivering credentials of binding "%s": %s`,
+			binding.BindingID,
+			err,
+		)
+	}
+	// Platforms watching the GitOps repository would otherwise go on handing
+	// out the revoked credential
+	if binding.GitOpsManifest != nil && b.manifestWriter != nil {
+		manifest, err := b.manifestWriter.WriteManifest(
+			instance,
+			binding.BindingID,
+			credentials,
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				`error rewriting GitOps manifest of binding "%s": %s`,
+				binding.BindingID,
+				err,
+			)
+		}
+		binding.GitOpsManifest = manifest
+	}
+	binding.CredentialRotation.DeliveryPending = false
+	if err := b.store.WriteBinding(binding); err != nil {
+		return nil, fmt.Errorf(
+			`error persisting binding "%s": %s`,
+			binding.BindingID,
+			err,
+		)
+	}
+	return nil, nil
+}
+
+// getRotatableBinding loads the binding named by the given task, along with
+// the instance it belongs to. It returns false if either no longer exists or
+// if the binding isn't bound or isn't subject to credential rotation.
+func (b *broker) getRotatableBinding(
+	task async.Task,
+) (service.Instance, service.Binding, bool, error) {
+	instanceID, ok := task.GetArgs()["instanceID"]
+	if !ok {
+		return service.Instance{}, service.Binding{}, false, errors.New(
+			`missing required argument "instanceID"`,
+		)
+	}
+	bindingID, ok := task.GetArgs()["bindingID"]
+	if !ok {
+		return service.Instance{}, service.Binding{}, false, errors.New(
+			`missing required argument "bindingID"`,
+		)
+	}
+	binding, ok, err := b.store.GetBinding(bindingID)
+	if err != nil {
+		return service.Instance{}, service.Binding{}, false, fmt.Errorf(
+			`error loading persisted binding "%s": %s`,
+			bindingID,
+			err,
+		)
+	}
+	if !ok ||
+		binding.Status != service.BindingStateBound ||
+		binding.CredentialRotation == nil {
+		return service.Instance{}, service.Binding{}, false, nil
+	}
+	instance, ok, err := b.store.GetInstance(instanceID)
+	if err != nil {
+		return service.Instance{}, service.Binding{}, false, fmt.Errorf(
+			`error loading persisted instance "%s": %s`,
+			instanceID,
+			err,
+		)
+	}
+	return instance, binding, ok, nil
+}
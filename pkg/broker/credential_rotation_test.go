@@ -0,0 +1,256 @@
+package broker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	fakeAsync "github.com/Azure/open-service-broker-azure/pkg/async/fake"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+// memorySecretStore is an in-memory implementation of the secrets.Store
+// interface, used for testing
+type memorySecretStore struct {
+	credentials map[string]service.Credentials
+}
+
+func (m *memorySecretStore) WriteCredentials(
+	_ string,
+	bindingID string,
+	credentials service.Credentials,
+) error {
+	m.credentials[bindingID] = credentials
+	return nil
+}
+
+// memoryManifestWriter is an in-memory implementation of the
+// gitops.ManifestWriter interface, used for testing
+type memoryManifestWriter struct {
+	// credentials is keyed by manifest path
+	credentials map[string]service.Credentials
+}
+
+func (m *memoryManifestWriter) WriteManifest(
+	instance service.Instance,
+	bindingID string,
+	credentials service.Credentials,
+) (*service.GitOpsManifest, error) {
+	manifestPath := instance.InstanceID + "/" + bindingID + ".yaml"
+	m.credentials[manifestPath] = credentials
+	return &service.GitOpsManifest{
+		Path:     manifestPath,
+		Revision: "def",
+	}, nil
+}
+
+func (m *memoryManifestWriter) DeleteManifest(
+	manifest service.GitOpsManifest,
+) error {
+	delete(m.credentials, manifest.Path)
+	return nil
+}
+
+func TestScheduleCredentialRotationChecksOnlyOnce(t *testing.T) {
+	b, _, _, _ := getCredentialRotationTestBroker(t)
+	assert.Nil(t, b.scheduleCredentialRotationChecks())
+	assert.Nil(t, b.scheduleCredentialRotationChecks())
+	assert.Len(t, b.asyncEngine.(*fakeAsync.Engine).SubmittedTasks, 1)
+}
+
+func TestCheckCredentialRotationsFansOut(t *testing.T) {
+	b, _, instance, binding := getCredentialRotationTestBroker(t)
+	schedule := b.credentialRotationSchedule.(*memoryCheckSchedule)
+	schedule.checkID = "check"
+	checkTask := async.NewTask(
+		"checkCredentialRotations",
+		map[string]string{
+			"checkID": "check",
+		},
+	)
+	tasks, err := b.checkCredentialRotations(context.Background(), checkTask)
+	assert.Nil(t, err)
+	assert.Len(t, tasks, 2)
+	assert.Equal(t, "checkCredentialRotations", tasks[0].GetJobName())
+	assert.Equal(t, schedule.checkID, tasks[0].GetArgs()["checkID"])
+	assert.Equal(t, "rotateBindingCredentials", tasks[1].GetJobName())
+	assert.Equal(t, instance.InstanceID, tasks[1].GetArgs()["instanceID"])
+	assert.Equal(t, binding.BindingID, tasks[1].GetArgs()["bindingID"])
+	// A binding that isn't due is only picked up if its delivery is pending
+	binding.CredentialRotation.NextRotation = time.Now().Add(time.Hour)
+	binding.CredentialRotation.DeliveryPending = true
+	assert.Nil(t, b.store.WriteBinding(binding))
+	checkTask = tasks[0]
+	tasks, err = b.checkCredentialRotations(context.Background(), checkTask)
+	assert.Nil(t, err)
+	assert.Len(t, tasks, 2)
+	assert.Equal(t, "deliverBindingCredentials", tasks[1].GetJobName())
+	binding.CredentialRotation.DeliveryPending = false
+	assert.Nil(t, b.store.WriteBinding(binding))
+	tasks, err = b.checkCredentialRotations(context.Background(), tasks[0])
+	assert.Nil(t, err)
+	assert.Len(t, tasks, 1)
+}
+
+func TestRotateBindingCredentials(t *testing.T) {
+	b, serviceManager, instance, binding := getCredentialRotationTestBroker(t)
+	var rotated bool
+	serviceManager.CredentialRotationBehavior = func(
+		_ context.Context,
+		_ service.Instance,
+		binding service.Binding,
+	) (service.BindingDetails, error) {
+		rotated = true
+		return binding.Details, nil
+	}
+	tasks, err := b.rotateBindingCredentials(
+		context.Background(),
+		getCredentialRotationTask(
+			"rotateBindingCredentials",
+			instance,
+			binding,
+		),
+	)
+	assert.Nil(t, err)
+	assert.True(t, rotated)
+	assert.Len(t, tasks, 1)
+	assert.Equal(t, "deliverBindingCredentials", tasks[0].GetJobName())
+	binding, _, err = b.store.GetBinding(binding.BindingID)
+	assert.Nil(t, err)
+	rotation := binding.CredentialRotation
+	assert.NotNil(t, rotation.LastRotated)
+	assert.Equal(t, rotation.LastRotated.Add(rotation.TTL), rotation.NextRotation)
+	assert.True(t, rotation.DeliveryPending)
+	// The binding is no longer due, so rotating it again does nothing
+	rotated = false
+	tasks, err = b.rotateBindingCredentials(
+		context.Background(),
+		getCredentialRotationTask(
+			"rotateBindingCredentials",
+			instance,
+			binding,
+		),
+	)
+	assert.Nil(t, err)
+	assert.False(t, rotated)
+	assert.Empty(t, tasks)
+}
+
+func TestRotateBindingCredentialsIgnoresInstancesMidOperation(t *testing.T) {
+	b, _, instance, binding := getCredentialRotationTestBroker(t)
+	instance.Status = service.InstanceStateUpdating
+	assert.Nil(t, b.store.WriteInstance(instance))
+	tasks, err := b.rotateBindingCredentials(
+		context.Background(),
+		getCredentialRotationTask(
+			"rotateBindingCredentials",
+			instance,
+			binding,
+		),
+	)
+	assert.Nil(t, err)
+	assert.Empty(t, tasks)
+	binding, _, err = b.store.GetBinding(binding.BindingID)
+	assert.Nil(t, err)
+	assert.Nil(t, binding.CredentialRotation.LastRotated)
+}
+
+func TestDeliverBindingCredentials(t *testing.T) {
+	b, _, instance, binding := getCredentialRotationTestBroker(t)
+	binding.CredentialRotation.DeliveryPending = true
+	assert.Nil(t, b.store.WriteBinding(binding))
+	tasks, err := b.deliverBindingCredentials(
+		context.Background(),
+		getCredentialRotationTask(
+			"deliverBindingCredentials",
+			instance,
+			binding,
+		),
+	)
+	assert.Nil(t, err)
+	assert.Empty(t, tasks)
+	secretStore := b.credentialRotation.SecretStore.(*memorySecretStore)
+	assert.Contains(t, secretStore.credentials, binding.BindingID)
+	binding, _, err = b.store.GetBinding(binding.BindingID)
+	assert.Nil(t, err)
+	assert.False(t, binding.CredentialRotation.DeliveryPending)
+}
+
+func TestDeliverBindingCredentialsRewritesGitOpsManifest(t *testing.T) {
+	b, _, instance, binding := getCredentialRotationTestBroker(t)
+	manifestWriter := &memoryManifestWriter{
+		credentials: map[string]service.Credentials{},
+	}
+	b.manifestWriter = manifestWriter
+	binding.GitOpsManifest = &service.GitOpsManifest{
+		Path:     instance.InstanceID + "/" + binding.BindingID + ".yaml",
+		Revision: "abc",
+	}
+	binding.CredentialRotation.DeliveryPending = true
+	assert.Nil(t, b.store.WriteBinding(binding))
+	_, err := b.deliverBindingCredentials(
+		context.Background(),
+		getCredentialRotationTask(
+			"deliverBindingCredentials",
+			instance,
+			binding,
+		),
+	)
+	assert.Nil(t, err)
+	assert.Contains(t, manifestWriter.credentials, binding.GitOpsManifest.Path)
+	binding, _, err = b.store.GetBinding(binding.BindingID)
+	assert.Nil(t, err)
+	assert.Equal(t, "def", binding.GitOpsManifest.Revision)
+	assert.False(t, binding.CredentialRotation.DeliveryPending)
+}
+
+func getCredentialRotationTestBroker(
+	t *testing.T,
+) (*broker, *fake.ServiceManager, service.Instance, service.Binding) {
+	b, _, _, instance := getNotificationTestBroker(t)
+	serviceManager, ok :=
+		instance.Service.GetServiceManager().(*fake.ServiceManager)
+	assert.True(t, ok)
+	instance.Status = service.InstanceStateProvisioned
+	assert.Nil(t, b.store.WriteInstance(instance))
+	binding := service.Binding{
+		BindingID:         "binding",
+		InstanceID:        instance.InstanceID,
+		ServiceID:         instance.ServiceID,
+		BindingParameters: &fake.BindingParameters{},
+		Details:           &fake.BindingDetails{},
+		Status:            service.BindingStateBound,
+		CredentialRotation: &service.CredentialRotation{
+			TTL:          24 * time.Hour,
+			NextRotation: time.Now().Add(-time.Minute),
+		},
+	}
+	assert.Nil(t, b.store.WriteBinding(binding))
+	b.credentialRotation = CredentialRotationConfig{
+		Enabled:       true,
+		DefaultTTL:    24 * time.Hour,
+		CheckInterval: time.Hour,
+		SecretStore: &memorySecretStore{
+			credentials: map[string]service.Credentials{},
+		},
+	}
+	b.credentialRotationSchedule = &memoryCheckSchedule{}
+	return b, serviceManager, instance, binding
+}
+
+func getCredentialRotationTask(
+	jobName string,
+	instance service.Instance,
+	binding service.Binding,
+) async.Task {
+	return async.NewTask(
+		jobName,
+		map[string]string{
+			"instanceID": instance.InstanceID,
+			"bindingID":  binding.BindingID,
+		},
+	)
+}
@@ -0,0 +1,106 @@
+package broker
+
+import (
+	"context"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	log "github.com/Sirupsen/logrus"
+)
+
+const (
+	defaultDebugTracingTTL      = 7 * 24 * time.Hour
+	defaultDebugTracingMaxSteps = 200
+)
+
+// DebugTracingConfig represents which instances have the execution of each of
+// their provisioning and updating steps traced, and for how long those traces
+// are kept. Tracing is meant for diagnosing misbehaving steps and is
+// considerably heavier than logging, so it is off unless enabled.
+type DebugTracingConfig struct {
+	Mode service.DebugTracingMode
+	// TTL is how long an instance's traces are kept after its most recently
+	// traced step
+	TTL time.Duration
+	// MaxSteps is how many of its most recently traced steps are kept for
+	// each instance
+	MaxSteps int
+	// Recorder, if non-nil, summarizes the requests that traced steps make to
+	// Azure
+	Recorder service.AzureRequestRecorder
+}
+
+// tracedStep is what provisioning and updating steps have in common
+type tracedStep interface {
+	GetName() string
+	Execute(
+		ctx context.Context,
+		instance service.Instance,
+	) (service.InstanceDetails, error)
+}
+
+// executeTracedStep executes the given step of the named operation on behalf
+// of the given instance and, if the instance's steps are traced, persists a
+// trace of it. When every instance's steps are traced, those of instances that
+// didn't ask for it and aren't sampled are persisted only if they fail.
+// Failing to persist the trace is logged, but otherwise doesn't affect the
+// outcome of the step.
+func (b *broker) executeTracedStep(
+	ctx context.Context,
+	operation string,
+	step tracedStep,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	if !b.debugTracing.Mode.IsTraced(instance) {
+		return step.Execute(ctx, instance)
+	}
+	sampled := instance.Debug || b.telemetrySampler == nil ||
+		b.telemetrySampler.IsTraceSampled(instance.InstanceID)
+	// The inputs are captured before the step executes, since it may modify
+	// them in place
+	trace := service.NewStepTrace(operation, step.GetName(), instance)
+	var stopRecording func() []service.AzureRequestSummary
+	if b.debugTracing.Recorder != nil {
+		stopRecording = b.debugTracing.Recorder.StartRecording(
+			instance.SubscriptionID,
+			instance.ResourceGroup,
+		)
+	}
+	updatedDetails, err := step.Execute(ctx, instance)
+	var azureRequests []service.AzureRequestSummary
+	if stopRecording != nil {
+		azureRequests = stopRecording()
+	}
+	if err == nil && !sampled {
+		return updatedDetails, nil
+	}
+	trace.Complete(updatedDetails, err, azureRequests)
+	if traceErr := b.store.AppendStepTrace(
+		instance.InstanceID,
+		trace,
+		b.debugTracing.MaxSteps,
+		b.debugTracing.TTL,
+	); traceErr != nil {
+		log.WithFields(log.Fields{
+			"step":       trace.StepName,
+			"instanceID": instance.InstanceID,
+			"error":      traceErr,
+		}).Error("error persisting step trace")
+	}
+	return updatedDetails, err
+}
+
+// withDebugTracingDefaults returns the given config with any unset options
+// assuming their defaults
+func withDebugTracingDefaults(config DebugTracingConfig) DebugTracingConfig {
+	if config.Mode == "" {
+		config.Mode = service.DebugTracingModeOff
+	}
+	if config.TTL <= 0 {
+		config.TTL = defaultDebugTracingTTL
+	}
+	if config.MaxSteps <= 0 {
+		config.MaxSteps = defaultDebugTracingMaxSteps
+	}
+	return config
+}
@@ -0,0 +1,180 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	"github.com/Azure/open-service-broker-azure/pkg/telemetry"
+	"github.com/stretchr/testify/assert"
+)
+
+type testAzureRequestRecorder struct {
+	resourceGroups []string
+	requests       []service.AzureRequestSummary
+}
+
+func (t *testAzureRequestRecorder) StartRecording(
+	_ string,
+	resourceGroup string,
+) func() []service.AzureRequestSummary {
+	t.resourceGroups = append(t.resourceGroups, resourceGroup)
+	return func() []service.AzureRequestSummary {
+		return t.requests
+	}
+}
+
+func TestRequestedDebugTracingTracesProvisioningStep(t *testing.T) {
+	b, fakeModule, instance := getConnectivityValidationTestBroker(t)
+	recorder := &testAzureRequestRecorder{
+		requests: []service.AzureRequestSummary{
+			{Method: "PUT", URL: "https://management.azure.com/foo", StatusCode: 200},
+		},
+	}
+	b.debugTracing = withDebugTracingDefaults(DebugTracingConfig{
+		Mode:     service.DebugTracingModeRequested,
+		Recorder: recorder,
+	})
+	instance.Debug = true
+	instance.ResourceGroup = "rg"
+	instance.ProvisioningParameters = &fake.ProvisioningParameters{
+		SomeParameter:       "foo",
+		SomeSecretParameter: "bar",
+	}
+	assert.Nil(t, b.store.WriteInstance(instance))
+	fakeModule.ServiceManager.ProvisionBehavior = func(
+		_ context.Context,
+		instance service.Instance,
+	) (service.InstanceDetails, error) {
+		return &fake.InstanceDetails{ResourceGroupName: "rg"}, nil
+	}
+	_, err := b.executeProvisioningStep(
+		context.Background(),
+		async.NewTask(
+			"executeProvisioningStep",
+			map[string]string{
+				"stepName":   "run",
+				"instanceID": instance.InstanceID,
+			},
+		),
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"rg"}, recorder.resourceGroups)
+	traces, err := b.store.GetStepTraces(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Len(t, traces, 1)
+	trace := traces[0]
+	assert.Equal(t, "provisioning", trace.Operation)
+	assert.Equal(t, "run", trace.StepName)
+	assert.Equal(t, "foo", trace.ProvisioningParameters["someParameter"])
+	assert.Equal(
+		t,
+		service.RedactedValue,
+		trace.ProvisioningParameters["someSecretParameter"],
+	)
+	assert.Equal(t, "", trace.InputDetails["resourceGroup"])
+	assert.Equal(t, "rg", trace.OutputDetails["resourceGroup"])
+	assert.Empty(t, trace.Error)
+	assert.Equal(t, recorder.requests, trace.AzureRequests)
+}
+
+func TestDebugTracingRecordsFailedStep(t *testing.T) {
+	b, fakeModule, instance := getConnectivityValidationTestBroker(t)
+	b.debugTracing = withDebugTracingDefaults(DebugTracingConfig{
+		Mode: service.DebugTracingModeAll,
+	})
+	fakeModule.ServiceManager.ProvisionBehavior = func(
+		context.Context,
+		service.Instance,
+	) (service.InstanceDetails, error) {
+		return nil, errors.New(
+			"error connecting: Server=foo;Password=hunter2;Database=bar",
+		)
+	}
+	_, err := b.executeProvisioningStep(
+		context.Background(),
+		async.NewTask(
+			"executeProvisioningStep",
+			map[string]string{
+				"stepName":   "run",
+				"instanceID": instance.InstanceID,
+			},
+		),
+	)
+	assert.NotNil(t, err)
+	traces, err := b.store.GetStepTraces(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Len(t, traces, 1)
+	assert.Equal(
+		t,
+		"error connecting: Server=foo;Password=REDACTED;Database=bar",
+		traces[0].Error,
+	)
+	assert.Nil(t, traces[0].OutputDetails)
+	assert.Empty(t, traces[0].AzureRequests)
+}
+
+func TestUnrequestedDebugTracingDoesNotTraceStep(t *testing.T) {
+	b, _, instance := getConnectivityValidationTestBroker(t)
+	b.debugTracing = withDebugTracingDefaults(DebugTracingConfig{
+		Mode: service.DebugTracingModeRequested,
+	})
+	_, err := b.executeProvisioningStep(
+		context.Background(),
+		async.NewTask(
+			"executeProvisioningStep",
+			map[string]string{
+				"stepName":   "run",
+				"instanceID": instance.InstanceID,
+			},
+		),
+	)
+	assert.Nil(t, err)
+	traces, err := b.store.GetStepTraces(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Empty(t, traces)
+}
+
+func TestUnsampledDebugTracingTracesOnlyFailedSteps(t *testing.T) {
+	sampler, err := telemetry.NewSampler(
+		telemetry.Sampling{TraceSampleRate: 0},
+		nil,
+		0,
+	)
+	assert.Nil(t, err)
+	for _, stepErr := range []error{nil, errors.New("error provisioning")} {
+		b, fakeModule, instance := getConnectivityValidationTestBroker(t)
+		b.debugTracing = withDebugTracingDefaults(DebugTracingConfig{
+			Mode: service.DebugTracingModeAll,
+		})
+		b.telemetrySampler = sampler
+		fakeModule.ServiceManager.ProvisionBehavior = func(
+			context.Context,
+			service.Instance,
+		) (service.InstanceDetails, error) {
+			return &fake.InstanceDetails{}, stepErr
+		}
+		_, err = b.executeProvisioningStep(
+			context.Background(),
+			async.NewTask(
+				"executeProvisioningStep",
+				map[string]string{
+					"stepName":   "run",
+					"instanceID": instance.InstanceID,
+				},
+			),
+		)
+		assert.Equal(t, stepErr != nil, err != nil)
+		traces, err := b.store.GetStepTraces(instance.InstanceID)
+		assert.Nil(t, err)
+		if stepErr == nil {
+			assert.Empty(t, traces)
+		} else {
+			assert.Len(t, traces, 1)
+			assert.Equal(t, "error provisioning", traces[0].Error)
+		}
+	}
+}
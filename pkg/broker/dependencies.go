@@ -0,0 +1,47 @@
+package broker
+
+import (
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// withDependencies returns the given instance with a description of each of
+// the instances it was provisioned to depend upon, so that its module's steps
+// can make use of their non-secret details. The descriptions are built afresh
+// for every step, so they reflect any updates made to those instances since.
+func (b *broker) withDependencies(
+	instance service.Instance,
+) (service.Instance, error) {
+	if len(instance.DependsOn) == 0 {
+		return instance, nil
+	}
+	dependencies := make(map[string]service.Dependency, len(instance.DependsOn))
+	for _, dependencyID := range instance.DependsOn {
+		dependencyInstance, ok, err := b.store.GetInstance(dependencyID)
+		if err != nil {
+			return instance, fmt.Errorf(
+				`error loading dependency "%s": %s`,
+				dependencyID,
+				err,
+			)
+		}
+		if !ok {
+			return instance, fmt.Errorf(
+				`dependency "%s" does not exist in the data store`,
+				dependencyID,
+			)
+		}
+		dependency, err := service.NewDependency(dependencyInstance)
+		if err != nil {
+			return instance, fmt.Errorf(
+				`error describing dependency "%s": %s`,
+				dependencyID,
+				err,
+			)
+		}
+		dependencies[dependencyID] = dependency
+	}
+	instance.Dependencies = dependencies
+	return instance, nil
+}
@@ -0,0 +1,70 @@
+package broker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProvisioningStepIsGivenDependencies(t *testing.T) {
+	b, fakeModule, instance := getConnectivityValidationTestBroker(t)
+	assert.Nil(t, b.store.WriteInstance(service.Instance{
+		InstanceID:    "dependency",
+		ServiceID:     fake.ServiceID,
+		PlanID:        fake.StandardPlanID,
+		Status:        service.InstanceStateProvisioned,
+		ResourceGroup: "dependency-rg",
+		Details: &fake.InstanceDetails{
+			ResourceGroupName: "dependency-rg",
+		},
+	}))
+	instance.DependsOn = []string{"dependency"}
+	assert.Nil(t, b.store.WriteInstance(instance))
+	var dependencies map[string]service.Dependency
+	fakeModule.ServiceManager.ProvisionBehavior = func(
+		_ context.Context,
+		instance service.Instance,
+	) (service.InstanceDetails, error) {
+		dependencies = instance.Dependencies
+		return instance.Details, nil
+	}
+	_, err := b.executeProvisioningStep(
+		context.Background(),
+		async.NewTask(
+			"executeProvisioningStep",
+			map[string]string{
+				"stepName":   "run",
+				"instanceID": instance.InstanceID,
+			},
+		),
+	)
+	assert.Nil(t, err)
+	assert.Len(t, dependencies, 1)
+	dependency := dependencies["dependency"]
+	assert.Equal(t, "dependency-rg", dependency.ResourceGroup)
+	assert.Equal(t, "dependency-rg", dependency.Outputs["resourceGroup"])
+}
+
+func TestProvisioningStepFailsIfDependencyIsMissing(t *testing.T) {
+	b, _, instance := getConnectivityValidationTestBroker(t)
+	instance.DependsOn = []string{"missing"}
+	assert.Nil(t, b.store.WriteInstance(instance))
+	_, err := b.executeProvisioningStep(
+		context.Background(),
+		async.NewTask(
+			"executeProvisioningStep",
+			map[string]string{
+				"stepName":   "run",
+				"instanceID": instance.InstanceID,
+			},
+		),
+	)
+	assert.NotNil(t, err)
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Equal(t, service.InstanceStateProvisioningFailed, instance.Status)
+}
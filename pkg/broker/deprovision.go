@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 
 	"github.com/Azure/open-service-broker-azure/pkg/async"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
@@ -88,8 +89,45 @@ func (b *broker) executeDeprovisioningStep(
 			`deprovisioner does not know how to process step "%s"`,
 		)
 	}
+	// An instance deprovisioned before it finished provisioning may still hold
+	// a provisioning slot, which it will never release otherwise
+	firstStepName, _ := deprovisioner.GetFirstStepName()
+	if stepName == firstStepName {
+		b.releaseProvisioningSlot(instance)
+	}
 	updatedDetails, err := step.Execute(ctx, instance)
 	if err != nil {
+		// A step with dependencies can find that what it deletes is still in use
+		// for a while after the steps it depends on have deleted its dependents.
+		// If the teardown retry policy permits, try the step again later.
+		// Otherwise, fail.
+		retryCount, _ := strconv.Atoi(args["retryCount"])
+		if len(deprovisioner.GetStepDependencies(stepName)) > 0 {
+			if delay, ok := b.teardownRetryPolicy.getRetryDelay(
+				err,
+				retryCount,
+			); ok {
+				log.WithFields(log.Fields{
+					"step":          stepName,
+					"instanceID":    instanceID,
+					"errorCategory": service.GetErrorCategory(err),
+					"retryCount":    retryCount + 1,
+					"retryDelay":    delay,
+					"error":         err,
+				}).Warn("deprovisioning step failed; retrying")
+				return []async.Task{
+					async.NewDelayedTask(
+						"executeDeprovisioningStep",
+						map[string]string{
+							"stepName":   stepName,
+							"instanceID": instanceID,
+							"retryCount": strconv.Itoa(retryCount + 1),
+						},
+						delay,
+					),
+				}, nil
+			}
+		}
 		return nil, b.handleDeprovisioningError(
 			instance,
 			stepName,
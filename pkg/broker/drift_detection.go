@@ -0,0 +1,289 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	log "github.com/Sirupsen/logrus"
+	uuid "github.com/satori/go.uuid"
+)
+
+// DriftPolicy determines what the broker does about an instance whose
+// underlying resources have drifted from the state the broker put them in
+type DriftPolicy string
+
+const (
+	// DriftPolicyIgnore doesn't check instances for drift
+	DriftPolicyIgnore DriftPolicy = "ignore"
+	// DriftPolicyReport records drift so that it is reported by the broker's
+	// admin API
+	DriftPolicyReport DriftPolicy = "report"
+	// DriftPolicyCorrect records drift and then re-applies the instance's
+	// desired state by executing its service's updating steps
+	DriftPolicyCorrect DriftPolicy = "correct"
+)
+
+// DriftDetectionConfig represents whether, how often, and to what end the
+// broker checks instances for drift. Only services whose ServiceManagers
+// implement service.DriftDetector are checked.
+type DriftDetectionConfig struct {
+	Enabled bool
+	// CheckInterval is how long the broker waits between checks
+	CheckInterval time.Duration
+	// Policy is applied to any service that doesn't have a policy of its own in
+	// PolicyByService
+	Policy DriftPolicy
+	// PolicyByService maps service names to policies that override Policy
+	PolicyByService map[string]DriftPolicy
+}
+
+func (d DriftDetectionConfig) getPolicy(svc service.Service) DriftPolicy {
+	if policy, ok := d.PolicyByService[svc.GetName()]; ok {
+		return policy
+	}
+	return d.Policy
+}
+
+// validateDriftPolicies checks that every service named in the given config's
+// per-service policies is known
+func validateDriftPolicies(
+	services []service.Service,
+	config DriftDetectionConfig,
+) error {
+	for serviceName := range config.PolicyByService {
+		var found bool
+		for _, svc := range services {
+			if svc.GetName() == serviceName {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf(
+				`drift policy names unknown service "%s"`,
+				serviceName,
+			)
+		}
+	}
+	return nil
+}
+
+// getDriftCheckScheduleTTL returns how long a scheduled drift check remains
+// recorded
+func (b *broker) getDriftCheckScheduleTTL() time.Duration {
+	return 3 * b.driftDetection.CheckInterval
+}
+
+// scheduleDriftChecks starts the chain of recurring drift checks unless one is
+// already scheduled
+func (b *broker) scheduleDriftChecks() error {
+	checkID := uuid.NewV4().String()
+	claimed, err := b.driftCheckSchedule.claim(
+		checkID,
+		"",
+		b.getDriftCheckScheduleTTL(),
+	)
+	if err != nil || !claimed {
+		return err
+	}
+	log.WithField("checkInterval", b.driftDetection.CheckInterval).Info(
+		"scheduling instance drift checks",
+	)
+	return b.asyncEngine.SubmitTask(
+		async.NewTask(
+			"checkDriftedInstances",
+			map[string]string{
+				"checkID": checkID,
+			},
+		),
+	)
+}
+
+// checkDriftedInstances fans out a task for checking each instance for drift
+// and schedules the next check. A check that has been superseded does nothing.
+func (b *broker) checkDriftedInstances(
+	_ context.Context,
+	task async.Task,
+) ([]async.Task, error) {
+	checkID, ok := task.GetArgs()["checkID"]
+	if !ok {
+		return nil, errors.New(`missing required argument "checkID"`)
+	}
+	nextCheckID := uuid.NewV4().String()
+	claimed, err := b.driftCheckSchedule.claim(
+		nextCheckID,
+		checkID,
+		b.getDriftCheckScheduleTTL(),
+	)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"checkID": checkID,
+			"error":   err,
+		}).Error("error scheduling next drift check; will retry")
+		return []async.Task{
+			async.NewDelayedTask(
+				"checkDriftedInstances",
+				task.GetArgs(),
+				b.driftDetection.CheckInterval,
+			),
+		}, nil
+	}
+	if !claimed {
+		log.WithField("checkID", checkID).Debug(
+			"drift check has been superseded; skipping",
+		)
+		return nil, nil
+	}
+	tasks := []async.Task{
+		async.NewDelayedTask(
+			"checkDriftedInstances",
+			map[string]string{
+				"checkID": nextCheckID,
+			},
+			b.driftDetection.CheckInterval,
+		),
+	}
+	instanceIDs, err := b.store.GetInstanceIDs()
+	if err != nil {
+		// The next check is scheduled regardless
+		log.WithField("error", err).Error(
+			"error listing instances to check for drift",
+		)
+		return tasks, nil
+	}
+	for _, instanceID := range instanceIDs {
+		tasks = append(
+			tasks,
+			async.NewTask(
+				"checkInstanceDrift",
+				map[string]string{
+					"instanceID": instanceID,
+				},
+			),
+		)
+	}
+	return tasks, nil
+}
+
+// checkInstanceDrift compares a single instance's underlying resources with
+// its desired state, records the outcome, and, if the instance has drifted
+// and its service's policy calls for it, re-applies the desired state
+func (b *broker) checkInstanceDrift(
+	ctx context.Context,
+	task async.Task,
+) ([]async.Task, error) {
+	instanceID, ok := task.GetArgs()["instanceID"]
+	if !ok {
+		return nil, errors.New(`missing required argument "instanceID"`)
+	}
+	instance, ok, err := b.store.GetInstance(instanceID)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error loading persisted instance "%s": %s`,
+			instanceID,
+			err,
+		)
+	}
+	// The instance may have been deprovisioned since the check was scheduled.
+	// Instances that are mid-operation or suspended are expected to differ
+	// from their desired state, so they are left alone.
+	if !ok ||
+		instance.Status != service.InstanceStateProvisioned ||
+		instance.IsSuspended() {
+		return nil, nil
+	}
+	policy := b.driftDetection.getPolicy(instance.Service)
+	if policy == DriftPolicyIgnore {
+		return nil, nil
+	}
+	serviceManager := instance.Service.GetServiceManager()
+	driftDetector, ok := serviceManager.(service.DriftDetector)
+	if !ok {
+		return nil, nil
+	}
+	drifts, err := driftDetector.DetectDrift(ctx, instance)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error checking whether instance "%s" has drifted: %s`,
+			instanceID,
+			err,
+		)
+	}
+	now := time.Now().UTC()
+	if instance.Drift == nil {
+		instance.Drift = &service.DriftState{}
+	}
+	instance.Drift.CheckedAt = now
+	instance.Drift.Drifts = drifts
+	if len(drifts) == 0 {
+		instance.Drift.DetectedAt = nil
+	} else if instance.Drift.DetectedAt == nil {
+		instance.Drift.DetectedAt = &now
+		log.WithFields(log.Fields{
+			"instanceID": instanceID,
+			"drifts":     drifts,
+		}).Warn("instance has drifted from its desired state")
+	}
+	var tasks []async.Task
+	if len(drifts) > 0 && policy == DriftPolicyCorrect {
+		if tasks, err = b.correctDrift(&instance); err != nil {
+			return nil, err
+		}
+		if len(tasks) > 0 {
+			instance.Drift.CorrectedAt = &now
+		}
+	}
+	if err := b.store.WriteInstance(instance); err != nil {
+		return nil, fmt.Errorf(
+			`error persisting instance "%s": %s`,
+			instanceID,
+			err,
+		)
+	}
+	return tasks, nil
+}
+
+// correctDrift readies the given instance for updating with the parameters it
+// already has and returns the task that executes the first updating step. An
+// instance whose service's updater has no steps is left as it is.
+func (b *broker) correctDrift(
+	instance *service.Instance,
+) ([]async.Task, error) {
+	serviceManager := instance.Service.GetServiceManager()
+	updater, err := serviceManager.GetUpdater(instance.Plan)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error retrieving updater for service "%s": %s`,
+			instance.ServiceID,
+			err,
+		)
+	}
+	firstStepName, ok := updater.GetFirstStepName()
+	if !ok {
+		log.WithField("instanceID", instance.InstanceID).Warn(
+			"instance has drifted, but its service has no updating steps with " +
+				"which to correct it",
+		)
+		return nil, nil
+	}
+	if instance.UpdatingParameters == nil {
+		instance.UpdatingParameters = serviceManager.GetEmptyUpdatingParameters()
+	}
+	instance.Status = service.InstanceStateUpdating
+	log.WithField("instanceID", instance.InstanceID).Info(
+		"re-applying drifted instance's desired state",
+	)
+	task := async.NewTask(
+		"executeUpdatingStep",
+		map[string]string{
+			"stepName":   firstStepName,
+			"instanceID": instance.InstanceID,
+		},
+	)
+	task.SetTenant(instance.OrganizationGUID)
+	return []async.Task{task}, nil
+}
@@ -0,0 +1,174 @@
+package broker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	fakeAsync "github.com/Azure/open-service-broker-azure/pkg/async/fake"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleDriftChecksOnlyOnce(t *testing.T) {
+	b, _, _ := getDriftDetectionTestBroker(t, DriftPolicyReport)
+	assert.Nil(t, b.scheduleDriftChecks())
+	assert.Nil(t, b.scheduleDriftChecks())
+	assert.Len(t, b.asyncEngine.(*fakeAsync.Engine).SubmittedTasks, 1)
+}
+
+func TestCheckDriftedInstancesFansOut(t *testing.T) {
+	b, _, instance := getDriftDetectionTestBroker(t, DriftPolicyReport)
+	schedule := b.driftCheckSchedule.(*memoryCheckSchedule)
+	schedule.checkID = "check"
+	tasks, err := b.checkDriftedInstances(
+		context.Background(),
+		async.NewTask(
+			"checkDriftedInstances",
+			map[string]string{
+				"checkID": "check",
+			},
+		),
+	)
+	assert.Nil(t, err)
+	assert.Len(t, tasks, 2)
+	assert.Equal(t, "checkDriftedInstances", tasks[0].GetJobName())
+	assert.Equal(t, schedule.checkID, tasks[0].GetArgs()["checkID"])
+	assert.Equal(t, "checkInstanceDrift", tasks[1].GetJobName())
+	assert.Equal(t, instance.InstanceID, tasks[1].GetArgs()["instanceID"])
+}
+
+func TestCheckInstanceDriftReports(t *testing.T) {
+	b, _, instance := getDriftDetectionTestBroker(t, DriftPolicyReport)
+	tasks, err := b.checkInstanceDrift(
+		context.Background(),
+		getCheckInstanceDriftTask(instance),
+	)
+	assert.Nil(t, err)
+	assert.Empty(t, tasks)
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.True(t, instance.HasDrifted())
+	assert.Equal(t, getTestDrifts(), instance.Drift.Drifts)
+	assert.NotNil(t, instance.Drift.DetectedAt)
+	assert.Nil(t, instance.Drift.CorrectedAt)
+	assert.Equal(t, service.InstanceStateProvisioned, instance.Status)
+}
+
+func TestCheckInstanceDriftClearsDriftWhenResolved(t *testing.T) {
+	b, serviceManager, instance :=
+		getDriftDetectionTestBroker(t, DriftPolicyReport)
+	_, err := b.checkInstanceDrift(
+		context.Background(),
+		getCheckInstanceDriftTask(instance),
+	)
+	assert.Nil(t, err)
+	serviceManager.DriftDetectionBehavior = getDriftDetectionBehavior(nil)
+	_, err = b.checkInstanceDrift(
+		context.Background(),
+		getCheckInstanceDriftTask(instance),
+	)
+	assert.Nil(t, err)
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.False(t, instance.HasDrifted())
+	assert.Nil(t, instance.Drift.DetectedAt)
+}
+
+func TestCheckInstanceDriftCorrects(t *testing.T) {
+	b, _, instance := getDriftDetectionTestBroker(t, DriftPolicyCorrect)
+	tasks, err := b.checkInstanceDrift(
+		context.Background(),
+		getCheckInstanceDriftTask(instance),
+	)
+	assert.Nil(t, err)
+	assert.Len(t, tasks, 1)
+	assert.Equal(t, "executeUpdatingStep", tasks[0].GetJobName())
+	assert.Equal(t, "run", tasks[0].GetArgs()["stepName"])
+	assert.Equal(t, instance.OrganizationGUID, tasks[0].GetTenant())
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Equal(t, service.InstanceStateUpdating, instance.Status)
+	assert.NotNil(t, instance.Drift.CorrectedAt)
+}
+
+func TestCheckInstanceDriftIgnoresInstancesMidOperation(t *testing.T) {
+	b, _, instance := getDriftDetectionTestBroker(t, DriftPolicyCorrect)
+	instance.Status = service.InstanceStateUpdating
+	assert.Nil(t, b.store.WriteInstance(instance))
+	tasks, err := b.checkInstanceDrift(
+		context.Background(),
+		getCheckInstanceDriftTask(instance),
+	)
+	assert.Nil(t, err)
+	assert.Empty(t, tasks)
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Nil(t, instance.Drift)
+}
+
+func TestCheckInstanceDriftHonorsPerServicePolicy(t *testing.T) {
+	b, _, instance := getDriftDetectionTestBroker(t, DriftPolicyCorrect)
+	b.driftDetection.PolicyByService = map[string]DriftPolicy{
+		instance.Service.GetName(): DriftPolicyIgnore,
+	}
+	_, err := b.checkInstanceDrift(
+		context.Background(),
+		getCheckInstanceDriftTask(instance),
+	)
+	assert.Nil(t, err)
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Nil(t, instance.Drift)
+}
+
+func getDriftDetectionTestBroker(
+	t *testing.T,
+	policy DriftPolicy,
+) (*broker, *fake.ServiceManager, service.Instance) {
+	b, _, _, instance := getNotificationTestBroker(t)
+	serviceManager, ok :=
+		instance.Service.GetServiceManager().(*fake.ServiceManager)
+	assert.True(t, ok)
+	serviceManager.DriftDetectionBehavior =
+		getDriftDetectionBehavior(getTestDrifts())
+	instance.Status = service.InstanceStateProvisioned
+	assert.Nil(t, b.store.WriteInstance(instance))
+	b.driftDetection = DriftDetectionConfig{
+		Enabled:       true,
+		CheckInterval: time.Hour,
+		Policy:        policy,
+	}
+	b.driftCheckSchedule = &memoryCheckSchedule{}
+	return b, serviceManager, instance
+}
+
+func getTestDrifts() []service.Drift {
+	return []service.Drift{
+		{
+			Resource: "server",
+			Property: "sslEnforcement",
+			Expected: "Enabled",
+			Actual:   "Disabled",
+		},
+	}
+}
+
+func getDriftDetectionBehavior(
+	drifts []service.Drift,
+) fake.DriftDetectionFunction {
+	return func(context.Context, service.Instance) ([]service.Drift, error) {
+		return drifts, nil
+	}
+}
+
+func getCheckInstanceDriftTask(instance service.Instance) async.Task {
+	return async.NewTask(
+		"checkInstanceDrift",
+		map[string]string{
+			"instanceID": instance.InstanceID,
+		},
+	)
+}
@@ -0,0 +1,242 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	"github.com/Azure/open-service-broker-azure/pkg/notification"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	log "github.com/Sirupsen/logrus"
+)
+
+// expiryRetryDelay is how long the expiry of a sandbox instance that is in
+// the midst of another operation is put off for
+const expiryRetryDelay = time.Minute
+
+// validateExpiryPolicy checks that every plan that permits sandbox instances
+// is known
+func validateExpiryPolicy(
+	services []service.Service,
+	expiryPolicy service.ExpiryPolicy,
+) error {
+	knownPlanKeys := map[string]bool{}
+	for _, svc := range services {
+		for _, plan := range svc.GetPlans() {
+			knownPlanKeys[service.GetPlanKey(svc, plan)] = true
+		}
+	}
+	for _, planKey := range expiryPolicy.GetPlanKeys() {
+		if !knownPlanKeys[planKey] {
+			return fmt.Errorf(
+				`sandbox lifespan names unknown plan "%s"; plans are identified as `+
+					"serviceName/planName",
+				planKey,
+			)
+		}
+	}
+	return nil
+}
+
+// getExpiringInstance loads the sandbox instance that an expiry task
+// concerns. Expiry tasks cannot be withdrawn once submitted, so each carries
+// the expiry time it was scheduled for. A bool is returned indicating whether
+// the instance still exists and is due to expire at that time; if its expiry
+// has since been put off, another task has been submitted for the new time.
+func (b *broker) getExpiringInstance(
+	task async.Task,
+) (service.Instance, bool, error) {
+	args := task.GetArgs()
+	instanceID, ok := args["instanceID"]
+	if !ok {
+		return service.Instance{}, false, errors.New(
+			`missing required argument "instanceID"`,
+		)
+	}
+	expiresAt, err := time.Parse(time.RFC3339Nano, args["expiresAt"])
+	if err != nil {
+		return service.Instance{}, false, fmt.Errorf(
+			`error parsing argument "expiresAt": %s`,
+			err,
+		)
+	}
+	instance, ok, err := b.store.GetInstance(instanceID)
+	if err != nil {
+		return service.Instance{}, false, fmt.Errorf(
+			`error loading persisted instance "%s": %s`,
+			instanceID,
+			err,
+		)
+	}
+	if !ok ||
+		!instance.IsSandbox() ||
+		!instance.Expiry.ExpiresAt.Equal(expiresAt) ||
+		instance.Expiry.ExpiredAt != nil {
+		return service.Instance{}, false, nil
+	}
+	return instance, true, nil
+}
+
+// warnOfInstanceExpiry notifies subscribers that a sandbox instance will soon
+// expire, as scheduled. Subscribers are warned only once ahead of each expiry
+// time.
+func (b *broker) warnOfInstanceExpiry(
+	_ context.Context,
+	task async.Task,
+) ([]async.Task, error) {
+	instance, ok, err := b.getExpiringInstance(task)
+	if err != nil || !ok || instance.Expiry.WarnedAt != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	instance.Expiry.WarnedAt = &now
+	if err := b.store.WriteInstance(instance); err != nil {
+		return nil, fmt.Errorf(
+			`error persisting instance "%s": %s`,
+			instance.InstanceID,
+			err,
+		)
+	}
+	log.WithFields(log.Fields{
+		"instanceID": instance.InstanceID,
+		"expiresAt":  instance.Expiry.ExpiresAt,
+	}).Info("sandbox instance is expiring")
+	b.submitNotifications(
+		instance,
+		notification.StatusExpiring,
+		fmt.Sprintf(
+			"expires at %s; update the instance to put off its expiry",
+			instance.Expiry.ExpiresAt.Format(time.RFC3339),
+		),
+	)
+	return nil, nil
+}
+
+// expireInstance deprovisions a sandbox instance whose lifespan is over, as
+// scheduled. An instance that is in the midst of another operation is
+// deprovisioned once that operation completes.
+func (b *broker) expireInstance(
+	_ context.Context,
+	task async.Task,
+) ([]async.Task, error) {
+	instance, ok, err := b.getExpiringInstance(task)
+	if err != nil || !ok {
+		return nil, err
+	}
+	logFields := log.Fields{
+		"instanceID": instance.InstanceID,
+		"expiresAt":  instance.Expiry.ExpiresAt,
+		"status":     instance.Status,
+	}
+	switch instance.Status {
+	case service.InstanceStateProvisioned,
+		service.InstanceStateProvisioningFailed,
+		service.InstanceStateQuarantined:
+	case service.InstanceStateAwaitingApproval,
+		service.InstanceStateQueued,
+		service.InstanceStateProvisioning,
+		service.InstanceStateProvisioningDegraded,
+		service.InstanceStateUpdating,
+		service.InstanceStateQuarantining,
+		service.InstanceStateReleasing:
+		log.WithFields(logFields).Debug(
+			"sandbox instance is mid-operation; putting off expiry",
+		)
+		return []async.Task{
+			async.NewDelayedTask("expireInstance", task.GetArgs(), expiryRetryDelay),
+		}, nil
+	default:
+		// The instance is already being deprovisioned, or is in a failed state
+		// from which it can't be deprovisioned without intervention
+		log.WithFields(logFields).Warn(
+			"sandbox instance can't be deprovisioned in its current state; not " +
+				"expiring",
+		)
+		return nil, nil
+	}
+	// Unlike a request to deprovision it, a sandbox instance's expiry isn't
+	// refused because other instances depend upon it; its lifespan is over
+	// regardless. Those instances are left to fend for themselves.
+	dependentIDs, err := b.store.GetInstanceDependentIDs(instance.InstanceID)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error retrieving dependents of expired instance "%s": %s`,
+			instance.InstanceID,
+			err,
+		)
+	}
+	if len(dependentIDs) > 0 {
+		log.WithFields(logFields).WithField("dependentIDs", dependentIDs).Warn(
+			"expiring sandbox instance that other instances depend upon",
+		)
+	}
+	deprovisioner, err := instance.Service.GetServiceManager().GetDeprovisioner(
+		instance.Plan,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error retrieving deprovisioner for service "%s": %s`,
+			instance.ServiceID,
+			err,
+		)
+	}
+	firstStepName, ok := deprovisioner.GetFirstStepName()
+	if !ok {
+		return nil, fmt.Errorf(
+			`no steps found for deprovisioning service "%s"`,
+			instance.ServiceID,
+		)
+	}
+	now := time.Now().UTC()
+	instance.Status = service.InstanceStateDeprovisioning
+	instance.StatusReason = "sandbox instance expired"
+	instance.Expiry.ExpiredAt = &now
+	if err = b.store.WriteInstance(instance); err != nil {
+		return nil, fmt.Errorf(
+			`error persisting expired instance "%s": %s`,
+			instance.InstanceID,
+			err,
+		)
+	}
+	// As when deprovisioning is requested, an instance whose children have yet
+	// to be deprovisioned waits for them
+	childCount, err := b.store.GetInstanceChildCountByAlias(instance.Alias)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error determining child count of expired instance "%s": %s`,
+			instance.InstanceID,
+			err,
+		)
+	}
+	var nextTask async.Task
+	if childCount > 0 {
+		nextTask = async.NewDelayedTask(
+			"checkChildrenStatuses",
+			map[string]string{
+				"instanceID": instance.InstanceID,
+			},
+			time.Minute*1,
+		)
+	} else {
+		nextTask = async.NewTask(
+			"executeDeprovisioningStep",
+			map[string]string{
+				"stepName":   firstStepName,
+				"instanceID": instance.InstanceID,
+			},
+		)
+	}
+	nextTask.SetTenant(instance.OrganizationGUID)
+	log.WithFields(logFields).Info("sandbox instance expired; deprovisioning")
+	b.submitNotifications(
+		instance,
+		notification.StatusExpired,
+		fmt.Sprintf(
+			"expired at %s; deprovisioning",
+			instance.Expiry.ExpiresAt.Format(time.RFC3339),
+		),
+	)
+	return []async.Task{nextTask}, nil
+}
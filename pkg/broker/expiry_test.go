@@ -0,0 +1,135 @@
+package broker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	fakeAsync "github.com/Azure/open-service-broker-azure/pkg/async/fake"
+	"github.com/Azure/open-service-broker-azure/pkg/notification"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWarnOfInstanceExpiryNotifiesOnce(t *testing.T) {
+	b, instance := getExpiryTestBroker(t)
+	task := getExpiryTestTask("warnOfInstanceExpiry", instance, 0)
+	_, err := b.warnOfInstanceExpiry(context.Background(), task)
+	assert.Nil(t, err)
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.NotNil(t, instance.Expiry.WarnedAt)
+	engine := b.asyncEngine.(*fakeAsync.Engine)
+	tasks := getSubmittedNotificationTasks(engine)
+	assert.Equal(
+		t,
+		notification.StatusExpiring,
+		tasks["everything"].GetArgs()["status"],
+	)
+	submitted := len(engine.SubmittedTasks)
+	_, err = b.warnOfInstanceExpiry(context.Background(), task)
+	assert.Nil(t, err)
+	assert.Len(t, engine.SubmittedTasks, submitted)
+}
+
+func TestExpireInstanceDeprovisions(t *testing.T) {
+	b, instance := getExpiryTestBroker(t)
+	followUpTasks, err := b.expireInstance(
+		context.Background(),
+		getExpiryTestTask("expireInstance", instance, 0),
+	)
+	assert.Nil(t, err)
+	assert.Len(t, followUpTasks, 1)
+	assert.Equal(t, "executeDeprovisioningStep", followUpTasks[0].GetJobName())
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Equal(t, service.InstanceStateDeprovisioning, instance.Status)
+	assert.NotNil(t, instance.Expiry.ExpiredAt)
+	tasks := getSubmittedNotificationTasks(b.asyncEngine.(*fakeAsync.Engine))
+	assert.Equal(
+		t,
+		notification.StatusExpired,
+		tasks["everything"].GetArgs()["status"],
+	)
+}
+
+func TestExpireInstanceSkipsExtendedExpiry(t *testing.T) {
+	b, instance := getExpiryTestBroker(t)
+	followUpTasks, err := b.expireInstance(
+		context.Background(),
+		getExpiryTestTask("expireInstance", instance, -time.Hour),
+	)
+	assert.Nil(t, err)
+	assert.Empty(t, followUpTasks)
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Equal(t, service.InstanceStateProvisioned, instance.Status)
+	assert.Nil(t, instance.Expiry.ExpiredAt)
+}
+
+func TestExpireInstanceWaitsForUpdate(t *testing.T) {
+	b, instance := getExpiryTestBroker(t)
+	instance.Status = service.InstanceStateUpdating
+	assert.Nil(t, b.store.WriteInstance(instance))
+	followUpTasks, err := b.expireInstance(
+		context.Background(),
+		getExpiryTestTask("expireInstance", instance, 0),
+	)
+	assert.Nil(t, err)
+	assert.Len(t, followUpTasks, 1)
+	assert.Equal(t, "expireInstance", followUpTasks[0].GetJobName())
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Equal(t, service.InstanceStateUpdating, instance.Status)
+}
+
+func TestValidateExpiryPolicy(t *testing.T) {
+	b, _ := getExpiryTestBroker(t)
+	expiryPolicy, err := service.NewExpiryPolicy(
+		map[string]time.Duration{"fake/standard": time.Hour},
+		24*time.Hour,
+		0,
+	)
+	assert.Nil(t, err)
+	assert.Nil(t, validateExpiryPolicy(b.catalog.GetServices(), expiryPolicy))
+	expiryPolicy, err = service.NewExpiryPolicy(
+		map[string]time.Duration{"fake/bogus": time.Hour},
+		24*time.Hour,
+		0,
+	)
+	assert.Nil(t, err)
+	assert.NotNil(t, validateExpiryPolicy(b.catalog.GetServices(), expiryPolicy))
+}
+
+// getExpiryTestBroker returns a broker whose only instance is a provisioned
+// sandbox instance of the fake service
+func getExpiryTestBroker(t *testing.T) (*broker, service.Instance) {
+	b, _, _, instance := getNotificationTestBroker(t)
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	instance.Status = service.InstanceStateProvisioned
+	instance.Expiry = &service.ExpiryState{
+		ExpiresAt:    expiresAt,
+		MaxExpiresAt: expiresAt.Add(24 * time.Hour),
+	}
+	assert.Nil(t, b.store.WriteInstance(instance))
+	return b, instance
+}
+
+// getExpiryTestTask returns a task for the named expiry job that was
+// scheduled for the given offset from the instance's expiry time
+func getExpiryTestTask(
+	jobName string,
+	instance service.Instance,
+	offset time.Duration,
+) async.Task {
+	return async.NewTask(
+		jobName,
+		map[string]string{
+			"instanceID": instance.InstanceID,
+			"expiresAt": instance.Expiry.ExpiresAt.Add(offset).Format(
+				time.RFC3339Nano,
+			),
+		},
+	)
+}
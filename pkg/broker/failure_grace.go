@@ -0,0 +1,114 @@
+package broker
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	log "github.com/Sirupsen/logrus"
+)
+
+const defaultFailureGraceRetryInterval = time.Minute
+
+// FailureGraceConfig represents how long, for the instances of each module,
+// the broker keeps retrying a failed provisioning step, after the retry policy
+// has given up on it, before it considers provisioning to have failed. In the
+// meantime, the instance is held in the PROVISIONING_DEGRADED state, which
+// gives operators a chance to remedy the cause. Modules without a window fail
+// as soon as the retry policy gives up.
+type FailureGraceConfig struct {
+	// WindowByModule is keyed by module name
+	WindowByModule map[string]time.Duration
+	// RetryInterval is how long to wait between attempts within the window
+	RetryInterval time.Duration
+}
+
+// getFailureGraceRetryTask returns a task that retries the named provisioning
+// step of the given instance after the failure grace retry interval, if the
+// instance's service has a failure grace window and it hasn't yet been
+// exhausted. The instance is first persisted as degraded, with the error that
+// caused that as its status reason. A bool indicating whether a task was
+// returned is also returned. Once the window has been exhausted, or if the
+// instance can't be persisted, provisioning should fail as it otherwise would.
+func (b *broker) getFailureGraceRetryTask(
+	instance service.Instance,
+	stepName string,
+	err error,
+) (async.Task, bool) {
+	window := b.failureGraceWindows[instance.ServiceID]
+	if window <= 0 {
+		return nil, false
+	}
+	logFields := log.Fields{
+		"step":       stepName,
+		"instanceID": instance.InstanceID,
+		"error":      err,
+	}
+	now := time.Now().UTC()
+	if instance.ProvisioningDegradedSince == nil {
+		instance.ProvisioningDegradedSince = &now
+	} else if now.Sub(*instance.ProvisioningDegradedSince) >= window {
+		log.WithFields(logFields).Warn(
+			"provisioning failure grace window exhausted",
+		)
+		return nil, false
+	}
+	deadline := instance.ProvisioningDegradedSince.Add(window)
+	instance.Status = service.InstanceStateProvisioningDegraded
+	instance.StatusReason = fmt.Sprintf(
+		`provisioning step "%s" failed and will be retried until %s: %s`,
+		stepName,
+		deadline.Format(time.RFC3339),
+		err,
+	)
+	if err := b.store.WriteInstance(instance); err != nil {
+		logFields["persistenceError"] = err
+		log.WithFields(logFields).Error(
+			"error persisting degraded instance; not retrying provisioning step",
+		)
+		return nil, false
+	}
+	retryInterval := b.failureGrace.RetryInterval
+	if retryInterval <= 0 {
+		retryInterval = defaultFailureGraceRetryInterval
+	}
+	logFields["retryDelay"] = retryInterval
+	logFields["deadline"] = deadline
+	log.WithFields(logFields).Warn(
+		"provisioning step failed; instance is degraded and will be retried",
+	)
+	args := map[string]string{
+		"stepName":   stepName,
+		"instanceID": instance.InstanceID,
+	}
+	// A retry of a recorded step execution carries a retry count that tells it
+	// apart from a re-delivery of the task that failed
+	if execution, ok := instance.ProvisioningStepExecutions[stepName]; ok {
+		args[executionTokenArg] = execution.Token
+		args["retryCount"] = strconv.Itoa(execution.RetryCount + 1)
+	}
+	return async.NewDelayedTask(
+		"executeProvisioningStep",
+		args,
+		retryInterval,
+	), true
+}
+
+// withRecoveredProvisioning returns the given instance, which has just
+// completed a provisioning step, returned to the PROVISIONING state if it was
+// degraded
+func withRecoveredProvisioning(instance service.Instance) service.Instance {
+	if instance.Status != service.InstanceStateProvisioningDegraded {
+		return instance
+	}
+	log.WithFields(log.Fields{
+		"instanceID":    instance.InstanceID,
+		"degradedSince": instance.ProvisioningDegradedSince,
+	}).Info("degraded provisioning recovered")
+	instance.Status = service.InstanceStateProvisioning
+	instance.StatusReason = ""
+	instance.ProvisioningDegradedSince = nil
+	return instance
+}
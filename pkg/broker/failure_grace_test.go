@@ -0,0 +1,122 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFailedProvisioningStepWithoutGraceWindowFails(t *testing.T) {
+	b, _, instance := getFailureGraceTestBroker(t, 0)
+	_, err := b.executeProvisioningStep(
+		context.Background(),
+		getFailureGraceTestTask(instance),
+	)
+	assert.NotNil(t, err)
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Equal(t, service.InstanceStateProvisioningFailed, instance.Status)
+}
+
+func TestFailedProvisioningStepDegradesInstance(t *testing.T) {
+	b, _, instance := getFailureGraceTestBroker(t, time.Hour)
+	followUpTasks, err := b.executeProvisioningStep(
+		context.Background(),
+		getFailureGraceTestTask(instance),
+	)
+	assert.Nil(t, err)
+	assert.Len(t, followUpTasks, 1)
+	assert.Equal(t, "executeProvisioningStep", followUpTasks[0].GetJobName())
+	assert.NotNil(t, followUpTasks[0].GetExecuteTime())
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Equal(t, service.InstanceStateProvisioningDegraded, instance.Status)
+	assert.Contains(t, instance.StatusReason, "quota exceeded")
+	assert.NotNil(t, instance.ProvisioningDegradedSince)
+}
+
+func TestFailedProvisioningStepFailsOnceGraceWindowIsExhausted(
+	t *testing.T,
+) {
+	b, _, instance := getFailureGraceTestBroker(t, time.Hour)
+	degradedSince := time.Now().Add(-2 * time.Hour)
+	instance.Status = service.InstanceStateProvisioningDegraded
+	instance.ProvisioningDegradedSince = &degradedSince
+	assert.Nil(t, b.store.WriteInstance(instance))
+	followUpTasks, err := b.executeProvisioningStep(
+		context.Background(),
+		getFailureGraceTestTask(instance),
+	)
+	assert.NotNil(t, err)
+	assert.Empty(t, followUpTasks)
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Equal(t, service.InstanceStateProvisioningFailed, instance.Status)
+}
+
+func TestDegradedProvisioningRecovers(t *testing.T) {
+	b, fakeModule, instance := getFailureGraceTestBroker(t, time.Hour)
+	fakeModule.ServiceManager.ProvisionBehavior = func(
+		_ context.Context,
+		instance service.Instance,
+	) (service.InstanceDetails, error) {
+		return instance.Details, nil
+	}
+	degradedSince := time.Now().Add(-time.Minute)
+	instance.Status = service.InstanceStateProvisioningDegraded
+	instance.ProvisioningDegradedSince = &degradedSince
+	assert.Nil(t, b.store.WriteInstance(instance))
+	followUpTasks, err := b.executeProvisioningStep(
+		context.Background(),
+		getFailureGraceTestTask(instance),
+	)
+	assert.Nil(t, err)
+	// The broker validates connectivity to the fake service's instances next
+	assert.Len(t, followUpTasks, 1)
+	assert.Equal(t, "validateConnectivity", followUpTasks[0].GetJobName())
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Equal(t, service.InstanceStateProvisioning, instance.Status)
+	assert.Empty(t, instance.StatusReason)
+	assert.Nil(t, instance.ProvisioningDegradedSince)
+}
+
+// getFailureGraceTestBroker returns a broker whose only instance is an
+// instance of the fake service that is being provisioned, along with the fake
+// module, whose provisioning step fails. The fake service has the given
+// failure grace window.
+func getFailureGraceTestBroker(
+	t *testing.T,
+	window time.Duration,
+) (*broker, *fake.Module, service.Instance) {
+	b, fakeModule, instance := getConnectivityValidationTestBroker(t)
+	fakeModule.ServiceManager.ProvisionBehavior = func(
+		context.Context,
+		service.Instance,
+	) (service.InstanceDetails, error) {
+		return nil, errors.New("quota exceeded")
+	}
+	b.failureGrace = FailureGraceConfig{
+		RetryInterval: time.Minute,
+	}
+	b.failureGraceWindows = map[string]time.Duration{
+		fake.ServiceID: window,
+	}
+	return b, fakeModule, instance
+}
+
+func getFailureGraceTestTask(instance service.Instance) async.Task {
+	return async.NewTask(
+		"executeProvisioningStep",
+		map[string]string{
+			"stepName":   "run",
+			"instanceID": instance.InstanceID,
+		},
+	)
+}
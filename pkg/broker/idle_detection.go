@@ -0,0 +1,315 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	"github.com/Azure/open-service-broker-azure/pkg/notification"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	log "github.com/Sirupsen/logrus"
+	uuid "github.com/satori/go.uuid"
+)
+
+// IdlePolicy determines what the broker does about an instance whose
+// underlying resources have been idle for the configured period
+type IdlePolicy string
+
+const (
+	// IdlePolicyIgnore leaves idle instances alone
+	IdlePolicyIgnore IdlePolicy = "ignore"
+	// IdlePolicyNotify notifies subscribers that an instance is idle
+	IdlePolicyNotify IdlePolicy = "notify"
+	// IdlePolicySuspend suspends idle instances and notifies subscribers that
+	// it has done so. Idle instances of services that don't support suspension
+	// are treated as if the policy were IdlePolicyNotify.
+	IdlePolicySuspend IdlePolicy = "suspend"
+)
+
+// IdleDetectionConfig represents whether, how often, and to what end the
+// broker looks for instances whose underlying resources are idle. Only
+// services whose ServiceManagers implement service.IdleDetector are checked.
+type IdleDetectionConfig struct {
+	Enabled bool
+	// CheckInterval is how long the broker waits between checks
+	CheckInterval time.Duration
+	// IdlePeriod is how long an instance must have been idle before it is acted
+	// on
+	IdlePeriod time.Duration
+	// Policy is applied to any service that doesn't have a policy of its own in
+	// PolicyByService
+	Policy IdlePolicy
+	// PolicyByService maps service names to policies that override Policy
+	PolicyByService map[string]IdlePolicy
+}
+
+func (i IdleDetectionConfig) getPolicy(svc service.Service) IdlePolicy {
+	if policy, ok := i.PolicyByService[svc.GetName()]; ok {
+		return policy
+	}
+	return i.Policy
+}
+
+// validateIdlePolicies checks that every service named in the given config's
+// per-service policies is known
+func validateIdlePolicies(
+	services []service.Service,
+	config IdleDetectionConfig,
+) error {
+	for serviceName := range config.PolicyByService {
+		var found bool
+		for _, svc := range services {
+			if svc.GetName() == serviceName {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf(
+				`idle policy names unknown service "%s"`,
+				serviceName,
+			)
+		}
+	}
+	return nil
+}
+
+// getIdleCheckScheduleTTL returns how long a scheduled idle check remains
+// recorded. It leaves room for a check to start late when workers are busy.
+func (b *broker) getIdleCheckScheduleTTL() time.Duration {
+	return 3 * b.idleDetection.CheckInterval
+}
+
+// scheduleIdleChecks starts the chain of recurring idle checks unless one is
+// already scheduled
+func (b *broker) scheduleIdleChecks() error {
+	checkID := uuid.NewV4().String()
+	claimed, err := b.idleCheckSchedule.claim(
+		checkID,
+		"",
+		b.getIdleCheckScheduleTTL(),
+	)
+	if err != nil || !claimed {
+		return err
+	}
+	log.WithFields(log.Fields{
+		"checkInterval": b.idleDetection.CheckInterval,
+		"idlePeriod":    b.idleDetection.IdlePeriod,
+	}).Info("scheduling idle instance checks")
+	return b.asyncEngine.SubmitTask(
+		async.NewTask(
+			"checkIdleInstances",
+			map[string]string{
+				"checkID": checkID,
+			},
+		),
+	)
+}
+
+// checkIdleInstances fans out a task for checking each instance for idleness
+// and schedules the next check. A check that has been superseded (e.g. by one
+// scheduled after its record expired) does nothing.
+func (b *broker) checkIdleInstances(
+	_ context.Context,
+	task async.Task,
+) ([]async.Task, error) {
+	checkID, ok := task.GetArgs()["checkID"]
+	if !ok {
+		return nil, errors.New(`missing required argument "checkID"`)
+	}
+	nextCheckID := uuid.NewV4().String()
+	claimed, err := b.idleCheckSchedule.claim(
+		nextCheckID,
+		checkID,
+		b.getIdleCheckScheduleTTL(),
+	)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"checkID": checkID,
+			"error":   err,
+		}).Error("error scheduling next idle check; will retry")
+		return []async.Task{
+			async.NewDelayedTask(
+				"checkIdleInstances",
+				task.GetArgs(),
+				b.idleDetection.CheckInterval,
+			),
+		}, nil
+	}
+	if !claimed {
+		log.WithField("checkID", checkID).Debug(
+			"idle check has been superseded; skipping",
+		)
+		return nil, nil
+	}
+	tasks := []async.Task{
+		async.NewDelayedTask(
+			"checkIdleInstances",
+			map[string]string{
+				"checkID": nextCheckID,
+			},
+			b.idleDetection.CheckInterval,
+		),
+	}
+	instanceIDs, err := b.store.GetInstanceIDs()
+	if err != nil {
+		// The next check is scheduled regardless
+		log.WithField("error", err).Error(
+			"error listing instances to check for idleness",
+		)
+		return tasks, nil
+	}
+	for _, instanceID := range instanceIDs {
+		tasks = append(
+			tasks,
+			async.NewTask(
+				"checkInstanceIdleness",
+				map[string]string{
+					"instanceID": instanceID,
+				},
+			),
+		)
+	}
+	return tasks, nil
+}
+
+// checkInstanceIdleness determines whether a single instance is idle and, if
+// so, acts on it according to its service's idle policy. Subscribers are only
+// notified once that an instance is idle, unless it is found to be in use
+// again in the meantime.
+func (b *broker) checkInstanceIdleness(
+	ctx context.Context,
+	task async.Task,
+) ([]async.Task, error) {
+	instanceID, ok := task.GetArgs()["instanceID"]
+	if !ok {
+		return nil, errors.New(`missing required argument "instanceID"`)
+	}
+	instance, ok, err := b.store.GetInstance(instanceID)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error loading persisted instance "%s": %s`,
+			instanceID,
+			err,
+		)
+	}
+	// The instance may have been deprovisioned since the check was scheduled.
+	// Instances that are mid-operation, already suspended, or too new to have
+	// been idle for the whole period are left alone.
+	if !ok ||
+		instance.Status != service.InstanceStateProvisioned ||
+		instance.IsSuspended() ||
+		time.Since(instance.Created) < b.idleDetection.IdlePeriod {
+		return nil, nil
+	}
+	policy := b.idleDetection.getPolicy(instance.Service)
+	if policy == IdlePolicyIgnore {
+		return nil, nil
+	}
+	serviceManager := instance.Service.GetServiceManager()
+	idleDetector, ok := serviceManager.(service.IdleDetector)
+	if !ok {
+		return nil, nil
+	}
+	idle, err := idleDetector.IsIdle(ctx, instance, b.idleDetection.IdlePeriod)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error checking whether instance "%s" is idle: %s`,
+			instanceID,
+			err,
+		)
+	}
+	notified := instance.Suspension != nil &&
+		instance.Suspension.IdleNotifiedAt != nil
+	if !idle {
+		if notified {
+			instance.Suspension.IdleNotifiedAt = nil
+			if err := b.store.WriteInstance(instance); err != nil {
+				return nil, fmt.Errorf(
+					`error persisting instance "%s": %s`,
+					instanceID,
+					err,
+				)
+			}
+		}
+		return nil, nil
+	}
+	if suspender, ok := serviceManager.(service.Suspender); ok &&
+		policy == IdlePolicySuspend {
+		return nil, b.suspendInstance(ctx, instance, suspender)
+	}
+	if notified {
+		return nil, nil
+	}
+	now := time.Now().UTC()
+	if instance.Suspension == nil {
+		instance.Suspension = &service.SuspensionState{}
+	}
+	instance.Suspension.IdleNotifiedAt = &now
+	if err := b.store.WriteInstance(instance); err != nil {
+		return nil, fmt.Errorf(
+			`error persisting instance "%s": %s`,
+			instanceID,
+			err,
+		)
+	}
+	log.WithField("instanceID", instanceID).Info("instance is idle")
+	b.submitNotifications(
+		instance,
+		notification.StatusIdle,
+		fmt.Sprintf("idle for at least %s", b.idleDetection.IdlePeriod),
+	)
+	return nil, nil
+}
+
+func (b *broker) suspendInstance(
+	ctx context.Context,
+	instance service.Instance,
+	suspender service.Suspender,
+) error {
+	// As with provisioning steps, only the details returned by the module are
+	// written back to storage, on an untouched copy of the instance
+	instanceCopy, _, err := b.store.GetInstance(instance.InstanceID)
+	if err != nil {
+		return fmt.Errorf(
+			`error loading persisted instance "%s": %s`,
+			instance.InstanceID,
+			err,
+		)
+	}
+	details, err := suspender.Suspend(ctx, instance)
+	if err != nil {
+		return fmt.Errorf(
+			`error suspending idle instance "%s": %s`,
+			instance.InstanceID,
+			err,
+		)
+	}
+	now := time.Now().UTC()
+	instanceCopy.Details = details
+	instanceCopy.Suspension = &service.SuspensionState{
+		Suspended:   true,
+		SuspendedAt: &now,
+	}
+	if err := b.store.WriteInstance(instanceCopy); err != nil {
+		return fmt.Errorf(
+			`error persisting suspended instance "%s": %s`,
+			instance.InstanceID,
+			err,
+		)
+	}
+	log.WithField("instanceID", instance.InstanceID).Info(
+		"suspended idle instance",
+	)
+	b.submitNotifications(
+		instanceCopy,
+		notification.StatusSuspended,
+		fmt.Sprintf(
+			"idle for at least %s; update the instance to resume it",
+			b.idleDetection.IdlePeriod,
+		),
+	)
+	return nil
+}
@@ -0,0 +1,283 @@
+package broker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	fakeAsync "github.com/Azure/open-service-broker-azure/pkg/async/fake"
+	"github.com/Azure/open-service-broker-azure/pkg/notification"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryCheckSchedule is an in-memory implementation of the checkSchedule
+// interface, used for testing
+type memoryCheckSchedule struct {
+	checkID string
+}
+
+func (m *memoryCheckSchedule) claim(
+	checkID string,
+	previousCheckID string,
+	_ time.Duration,
+) (bool, error) {
+	if m.checkID != "" && m.checkID != previousCheckID {
+		return false, nil
+	}
+	m.checkID = checkID
+	return true, nil
+}
+
+func TestScheduleIdleChecksOnlyOnce(t *testing.T) {
+	b, engine, _, _ := getIdleDetectionTestBroker(t, IdlePolicyNotify)
+	assert.Nil(t, b.scheduleIdleChecks())
+	assert.Nil(t, b.scheduleIdleChecks())
+	assert.Len(t, engine.SubmittedTasks, 1)
+}
+
+func TestCheckIdleInstancesFansOut(t *testing.T) {
+	b, _, _, instance := getIdleDetectionTestBroker(t, IdlePolicyNotify)
+	schedule := b.idleCheckSchedule.(*memoryCheckSchedule)
+	schedule.checkID = "check"
+	tasks, err := b.checkIdleInstances(
+		context.Background(),
+		async.NewTask(
+			"checkIdleInstances",
+			map[string]string{
+				"checkID": "check",
+			},
+		),
+	)
+	assert.Nil(t, err)
+	assert.Len(t, tasks, 2)
+	assert.Equal(t, "checkIdleInstances", tasks[0].GetJobName())
+	assert.Equal(t, schedule.checkID, tasks[0].GetArgs()["checkID"])
+	assert.Equal(t, "checkInstanceIdleness", tasks[1].GetJobName())
+	assert.Equal(t, instance.InstanceID, tasks[1].GetArgs()["instanceID"])
+}
+
+func TestCheckIdleInstancesSkipsSupersededCheck(t *testing.T) {
+	b, _, _, _ := getIdleDetectionTestBroker(t, IdlePolicyNotify)
+	b.idleCheckSchedule.(*memoryCheckSchedule).checkID = "newer-check"
+	tasks, err := b.checkIdleInstances(
+		context.Background(),
+		async.NewTask(
+			"checkIdleInstances",
+			map[string]string{
+				"checkID": "check",
+			},
+		),
+	)
+	assert.Nil(t, err)
+	assert.Empty(t, tasks)
+}
+
+func TestCheckInstanceIdlenessNotifiesOnce(t *testing.T) {
+	b, engine, _, instance := getIdleDetectionTestBroker(t, IdlePolicyNotify)
+	for i := 0; i < 2; i++ {
+		_, err := b.checkInstanceIdleness(
+			context.Background(),
+			getCheckInstanceIdlenessTask(instance),
+		)
+		assert.Nil(t, err)
+	}
+	tasks := getSubmittedNotificationTasks(engine)
+	assert.Len(t, tasks, 1)
+	assert.Equal(
+		t,
+		notification.StatusIdle,
+		tasks["everything"].GetArgs()["status"],
+	)
+	instance, _, err := b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.NotNil(t, instance.Suspension.IdleNotifiedAt)
+	assert.False(t, instance.IsSuspended())
+}
+
+func TestCheckInstanceIdlenessClearsNotificationWhenInUse(t *testing.T) {
+	b, engine, serviceManager, instance :=
+		getIdleDetectionTestBroker(t, IdlePolicyNotify)
+	_, err := b.checkInstanceIdleness(
+		context.Background(),
+		getCheckInstanceIdlenessTask(instance),
+	)
+	assert.Nil(t, err)
+	serviceManager.IdleDetectionBehavior = getIdleDetectionBehavior(
+		false,
+	)
+	_, err = b.checkInstanceIdleness(
+		context.Background(),
+		getCheckInstanceIdlenessTask(instance),
+	)
+	assert.Nil(t, err)
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Nil(t, instance.Suspension.IdleNotifiedAt)
+	assert.Len(t, getSubmittedNotificationTasks(engine), 1)
+}
+
+func TestCheckInstanceIdlenessSuspends(t *testing.T) {
+	b, engine, serviceManager, instance :=
+		getIdleDetectionTestBroker(t, IdlePolicySuspend)
+	var suspended bool
+	serviceManager.SuspendBehavior = func(
+		_ context.Context,
+		instance service.Instance,
+	) (service.InstanceDetails, error) {
+		suspended = true
+		return instance.Details, nil
+	}
+	_, err := b.checkInstanceIdleness(
+		context.Background(),
+		getCheckInstanceIdlenessTask(instance),
+	)
+	assert.Nil(t, err)
+	assert.True(t, suspended)
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.True(t, instance.IsSuspended())
+	assert.NotNil(t, instance.Suspension.SuspendedAt)
+	tasks := getSubmittedNotificationTasks(engine)
+	assert.Len(t, tasks, 1)
+	assert.Equal(
+		t,
+		notification.StatusSuspended,
+		tasks["everything"].GetArgs()["status"],
+	)
+}
+
+func TestCheckInstanceIdlenessIgnoresPolicy(t *testing.T) {
+	b, engine, _, instance := getIdleDetectionTestBroker(t, IdlePolicyIgnore)
+	_, err := b.checkInstanceIdleness(
+		context.Background(),
+		getCheckInstanceIdlenessTask(instance),
+	)
+	assert.Nil(t, err)
+	assert.Empty(t, engine.SubmittedTasks)
+}
+
+func TestCheckInstanceIdlenessSkipsNewInstance(t *testing.T) {
+	b, engine, _, instance := getIdleDetectionTestBroker(t, IdlePolicyNotify)
+	instance.Created = time.Now()
+	assert.Nil(t, b.store.WriteInstance(instance))
+	_, err := b.checkInstanceIdleness(
+		context.Background(),
+		getCheckInstanceIdlenessTask(instance),
+	)
+	assert.Nil(t, err)
+	assert.Empty(t, engine.SubmittedTasks)
+}
+
+func TestCheckInstanceIdlenessSkipsSuspendedInstance(t *testing.T) {
+	b, engine, serviceManager, instance :=
+		getIdleDetectionTestBroker(t, IdlePolicySuspend)
+	instance.Suspension = &service.SuspensionState{
+		Suspended: true,
+	}
+	assert.Nil(t, b.store.WriteInstance(instance))
+	serviceManager.SuspendBehavior = func(
+		context.Context,
+		service.Instance,
+	) (service.InstanceDetails, error) {
+		assert.Fail(t, "an instance that is already suspended was suspended")
+		return nil, nil
+	}
+	_, err := b.checkInstanceIdleness(
+		context.Background(),
+		getCheckInstanceIdlenessTask(instance),
+	)
+	assert.Nil(t, err)
+	assert.Empty(t, engine.SubmittedTasks)
+}
+
+func TestResumeInstance(t *testing.T) {
+	b, _, serviceManager, instance :=
+		getIdleDetectionTestBroker(t, IdlePolicySuspend)
+	instance.Suspension = &service.SuspensionState{
+		Suspended: true,
+	}
+	assert.Nil(t, b.store.WriteInstance(instance))
+	var resumed bool
+	serviceManager.ResumeBehavior = func(
+		_ context.Context,
+		instance service.Instance,
+	) (service.InstanceDetails, error) {
+		resumed = true
+		return instance.Details, nil
+	}
+	tasks, err := b.resumeInstance(
+		context.Background(),
+		async.NewTask(
+			"resumeInstance",
+			map[string]string{
+				"stepName":   "run",
+				"instanceID": instance.InstanceID,
+			},
+		),
+	)
+	assert.Nil(t, err)
+	assert.True(t, resumed)
+	assert.Len(t, tasks, 1)
+	assert.Equal(t, "executeUpdatingStep", tasks[0].GetJobName())
+	assert.Equal(t, "run", tasks[0].GetArgs()["stepName"])
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.False(t, instance.IsSuspended())
+	assert.NotNil(t, instance.Suspension.ResumedAt)
+}
+
+func TestValidateIdlePoliciesUnknownService(t *testing.T) {
+	b, _, _, _ := getIdleDetectionTestBroker(t, IdlePolicyNotify)
+	err := validateIdlePolicies(
+		b.catalog.GetServices(),
+		IdleDetectionConfig{
+			PolicyByService: map[string]IdlePolicy{
+				"bogus": IdlePolicySuspend,
+			},
+		},
+	)
+	assert.NotNil(t, err)
+}
+
+// getIdleDetectionTestBroker returns a broker whose only instance is an old,
+// provisioned instance of the fake service, along with the fake service
+// manager, which reports every instance as idle
+func getIdleDetectionTestBroker(
+	t *testing.T,
+	policy IdlePolicy,
+) (*broker, *fakeAsync.Engine, *fake.ServiceManager, service.Instance) {
+	b, _, _, instance := getNotificationTestBroker(t)
+	serviceManager, ok :=
+		instance.Service.GetServiceManager().(*fake.ServiceManager)
+	assert.True(t, ok)
+	serviceManager.IdleDetectionBehavior = getIdleDetectionBehavior(true)
+	instance.Status = service.InstanceStateProvisioned
+	instance.Created = time.Now().Add(-96 * time.Hour)
+	assert.Nil(t, b.store.WriteInstance(instance))
+	b.idleDetection = IdleDetectionConfig{
+		Enabled:       true,
+		CheckInterval: time.Hour,
+		IdlePeriod:    72 * time.Hour,
+		Policy:        policy,
+	}
+	b.idleCheckSchedule = &memoryCheckSchedule{}
+	return b, b.asyncEngine.(*fakeAsync.Engine), serviceManager, instance
+}
+
+func getIdleDetectionBehavior(idle bool) fake.IdleDetectionFunction {
+	return func(context.Context, service.Instance, time.Duration) (bool, error) {
+		return idle, nil
+	}
+}
+
+func getCheckInstanceIdlenessTask(instance service.Instance) async.Task {
+	return async.NewTask(
+		"checkInstanceIdleness",
+		map[string]string{
+			"instanceID": instance.InstanceID,
+		},
+	)
+}
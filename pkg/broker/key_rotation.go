@@ -0,0 +1,317 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	log "github.com/Sirupsen/logrus"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	rotateEncryptionKeyJobName = "rotateEncryptionKey"
+	// minKeyRotationStaleAfter is the least time that must pass without a
+	// rotation's progress being updated before the rotation is presumed lost
+	minKeyRotationStaleAfter = 10 * time.Minute
+)
+
+// keyRotationOperationStates are the states of instances that have an
+// operation in progress. The operation's own steps write, and thereby
+// re-encrypt, such an instance, which mustn't be written concurrently lest
+// the operation's changes be lost.
+var keyRotationOperationStates = map[string]bool{
+	service.InstanceStateAwaitingApproval:     true,
+	service.InstanceStateQueued:               true,
+	service.InstanceStateProvisioning:         true,
+	service.InstanceStateProvisioningDegraded: true,
+	service.InstanceStateUpdating:             true,
+	service.InstanceStateDeprovisioning:       true,
+	service.InstanceStateQuarantining:         true,
+	service.InstanceStateReleasing:            true,
+}
+
+// KeyRotationConfig represents whether and how the broker re-encrypts stored
+// instances, and their bindings, that were encrypted with versions of the
+// encryption key other than the current one. Instances are processed in
+// batches, with a pause between each, so that the async engine's workers
+// remain available for other tasks.
+type KeyRotationConfig struct {
+	Enabled bool
+	// KeyVersion is the current version of the encryption key
+	KeyVersion    string
+	BatchSize     int
+	BatchInterval time.Duration
+}
+
+// getKeyRotationStaleAfter returns how long a rotation's progress may go
+// without being updated before the rotation is presumed lost, e.g. because the
+// broker process carrying it out was stopped part way through a batch
+func (b *broker) getKeyRotationStaleAfter() time.Duration {
+	staleAfter := 3 * b.keyRotation.BatchInterval
+	if staleAfter < minKeyRotationStaleAfter {
+		return minKeyRotationStaleAfter
+	}
+	return staleAfter
+}
+
+// scheduleKeyRotation starts rotating the encryption key, or resumes a
+// rotation that was interrupted, unless a rotation to the current key version
+// has already completed or is still under way
+func (b *broker) scheduleKeyRotation() error {
+	progress, ok, err := b.store.GetKeyRotationProgress()
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	if ok && progress.KeyVersion == b.keyRotation.KeyVersion {
+		if progress.Completed != nil {
+			log.WithField("keyVersion", progress.KeyVersion).Debug(
+				"key rotation has already completed",
+			)
+			return nil
+		}
+		if now.Sub(progress.Updated) < b.getKeyRotationStaleAfter() {
+			log.WithField("keyVersion", progress.KeyVersion).Debug(
+				"key rotation is already under way",
+			)
+			return nil
+		}
+	} else {
+		progress = service.KeyRotationProgress{
+			KeyVersion: b.keyRotation.KeyVersion,
+			Started:    now,
+		}
+	}
+	// Any task still belonging to an earlier run is superseded by this one
+	progress.RunID = uuid.NewV4().String()
+	progress.Updated = now
+	if err := b.store.WriteKeyRotationProgress(progress); err != nil {
+		return err
+	}
+	log.WithFields(log.Fields{
+		"keyVersion":     progress.KeyVersion,
+		"lastInstanceID": progress.LastInstanceID,
+	}).Info("scheduling key rotation")
+	return b.asyncEngine.SubmitTask(
+		async.NewTask(
+			rotateEncryptionKeyJobName,
+			map[string]string{
+				"runID": progress.RunID,
+			},
+		),
+	)
+}
+
+// rotateEncryptionKey re-encrypts the next batch of instances, in order of
+// instance ID, and schedules the following batch. A task belonging to a run
+// that has been superseded does nothing.
+func (b *broker) rotateEncryptionKey(
+	_ context.Context,
+	task async.Task,
+) ([]async.Task, error) {
+	runID, ok := task.GetArgs()["runID"]
+	if !ok {
+		return nil, errors.New(`missing required argument "runID"`)
+	}
+	progress, ok, err := b.store.GetKeyRotationProgress()
+	if err != nil {
+		return nil, fmt.Errorf("error loading key rotation progress: %s", err)
+	}
+	if !ok || progress.RunID != runID || progress.Completed != nil {
+		log.WithField("runID", runID).Debug(
+			"key rotation has been superseded; skipping",
+		)
+		return nil, nil
+	}
+	instanceIDs, err := b.store.GetInstanceIDs()
+	if err != nil {
+		return nil, fmt.Errorf("error listing instances to re-encrypt: %s", err)
+	}
+	batch := getKeyRotationBatch(
+		instanceIDs,
+		progress.LastInstanceID,
+		b.keyRotation.BatchSize,
+	)
+	for _, instanceID := range batch {
+		b.rotateInstanceKey(instanceID, &progress)
+		progress.LastInstanceID = instanceID
+	}
+	now := time.Now().UTC()
+	progress.Updated = now
+	if len(batch) < b.keyRotation.BatchSize {
+		progress.Completed = &now
+	}
+	if err := b.store.WriteKeyRotationProgress(progress); err != nil {
+		return nil, fmt.Errorf("error persisting key rotation progress: %s", err)
+	}
+	if progress.Completed != nil {
+		log.WithFields(log.Fields{
+			"keyVersion": progress.KeyVersion,
+			"rotated":    progress.Rotated,
+			"skipped":    progress.Skipped,
+			"deferred":   progress.Deferred,
+			"failed":     progress.Failed,
+		}).Info("key rotation completed")
+		return nil, nil
+	}
+	return []async.Task{
+		async.NewDelayedTask(
+			rotateEncryptionKeyJobName,
+			task.GetArgs(),
+			b.keyRotation.BatchInterval,
+		),
+	}, nil
+}
+
+// getKeyRotationBatch returns, in order, up to batchSize of the given instance
+// IDs that sort after lastInstanceID
+func getKeyRotationBatch(
+	instanceIDs []string,
+	lastInstanceID string,
+	batchSize int,
+) []string {
+	sorted := make([]string, len(instanceIDs))
+	copy(sorted, instanceIDs)
+	sort.Strings(sorted)
+	start := sort.SearchStrings(sorted, lastInstanceID)
+	if start < len(sorted) && sorted[start] == lastInstanceID {
+		start++
+	}
+	end := start + batchSize
+	if end > len(sorted) {
+		end = len(sorted)
+	}
+	return sorted[start:end]
+}
+
+// rotateInstanceKey re-encrypts a single instance and its bindings, if any of
+// them were encrypted with a key version other than the current one, and
+// counts the outcome in the given progress. Failures are logged rather than
+// returned so that one unreadable instance doesn't hold up the rest.
+func (b *broker) rotateInstanceKey(
+	instanceID string,
+	progress *service.KeyRotationProgress,
+) {
+	logFields := log.Fields{
+		"instanceID": instanceID,
+	}
+	instance, ok, err := b.store.GetInstance(instanceID)
+	if err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error(
+			"key rotation error: error loading persisted instance",
+		)
+		progress.Failed++
+		return
+	}
+	// The instance may have been deprovisioned since it was listed
+	if !ok {
+		progress.Skipped++
+		return
+	}
+	rotatedBindings, err := b.rotateBindingKeys(instanceID)
+	if err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error(
+			"key rotation error: error re-encrypting bindings",
+		)
+		progress.Failed++
+		return
+	}
+	if keyRotationOperationStates[instance.Status] {
+		progress.Deferred++
+		return
+	}
+	current, err := b.isEncryptedWithCurrentKey(instance.GetKeyVersions())
+	if err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error(
+			"key rotation error: error reading key versions of instance",
+		)
+		progress.Failed++
+		return
+	}
+	if current {
+		if rotatedBindings {
+			progress.Rotated++
+		} else {
+			progress.Skipped++
+		}
+		return
+	}
+	if err := b.store.WriteInstance(instance); err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error(
+			"key rotation error: error persisting instance",
+		)
+		progress.Failed++
+		return
+	}
+	progress.Rotated++
+}
+
+// rotateBindingKeys re-encrypts those bindings to the given instance that
+// were encrypted with a key version other than the current one. It returns a
+// bool indicating whether any were.
+func (b *broker) rotateBindingKeys(instanceID string) (bool, error) {
+	bindingIDs, err := b.store.GetBindingIDs(instanceID)
+	if err != nil {
+		return false, err
+	}
+	var rotated bool
+	for _, bindingID := range bindingIDs {
+		binding, ok, err := b.store.GetBinding(bindingID)
+		if err != nil {
+			return rotated, fmt.Errorf(
+				`error loading persisted binding "%s": %s`,
+				bindingID,
+				err,
+			)
+		}
+		if !ok {
+			continue
+		}
+		current, err := b.isEncryptedWithCurrentKey(binding.GetKeyVersions())
+		if err != nil {
+			return rotated, fmt.Errorf(
+				`error reading key versions of binding "%s": %s`,
+				bindingID,
+				err,
+			)
+		}
+		if current {
+			continue
+		}
+		if err := b.store.WriteBinding(binding); err != nil {
+			return rotated, fmt.Errorf(
+				`error persisting binding "%s": %s`,
+				bindingID,
+				err,
+			)
+		}
+		rotated = true
+	}
+	return rotated, nil
+}
+
+// isEncryptedWithCurrentKey returns true if the given key versions, as
+// returned by GetKeyVersions, include none but the current one
+func (b *broker) isEncryptedWithCurrentKey(
+	keyVersions []string,
+	err error,
+) (bool, error) {
+	if err != nil {
+		return false, err
+	}
+	for _, keyVersion := range keyVersions {
+		if keyVersion != b.keyRotation.KeyVersion {
+			return false, nil
+		}
+	}
+	return true, nil
+}
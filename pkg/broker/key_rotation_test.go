@@ -0,0 +1,349 @@
+package broker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	fakeAsync "github.com/Azure/open-service-broker-azure/pkg/async/fake"
+	"github.com/Azure/open-service-broker-azure/pkg/crypto"
+	"github.com/Azure/open-service-broker-azure/pkg/crypto/aes256"
+	"github.com/Azure/open-service-broker-azure/pkg/crypto/versioned"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	memoryStorage "github.com/Azure/open-service-broker-azure/pkg/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+// rotatableCodec is an implementation of crypto.FieldCodec whose current key
+// version can be changed, used for testing. It stands in for a broker being
+// restarted with a new key.
+type rotatableCodec struct {
+	crypto.FieldCodec
+}
+
+// unversionedCodec is an implementation of crypto.FieldCodec that encrypts
+// whole values the way the broker did before they recorded a key version,
+// used for testing
+type unversionedCodec struct {
+	crypto.FieldCodec
+	codec crypto.Codec
+}
+
+func (u *unversionedCodec) Encrypt(plaintext []byte) ([]byte, error) {
+	return u.codec.Encrypt(plaintext)
+}
+
+func TestScheduleKeyRotationOnlyOnce(t *testing.T) {
+	b, engine, _ := getKeyRotationTestBroker(t)
+	assert.Nil(t, b.scheduleKeyRotation())
+	assert.Nil(t, b.scheduleKeyRotation())
+	assert.Len(t, engine.SubmittedTasks, 1)
+	progress, ok, err := b.store.GetKeyRotationProgress()
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "2", progress.KeyVersion)
+	assert.NotNil(t, getSubmittedKeyRotationTask(engine, progress.RunID))
+}
+
+func TestScheduleKeyRotationResumesStaleRotation(t *testing.T) {
+	b, engine, _ := getKeyRotationTestBroker(t)
+	started := time.Now().Add(-time.Hour).UTC()
+	assert.Nil(t, b.store.WriteKeyRotationProgress(
+		service.KeyRotationProgress{
+			KeyVersion:     "2",
+			RunID:          "lost-run",
+			Started:        started,
+			Updated:        started,
+			LastInstanceID: "instance-1",
+			Rotated:        1,
+		},
+	))
+	assert.Nil(t, b.scheduleKeyRotation())
+	assert.Len(t, engine.SubmittedTasks, 1)
+	progress, _, err := b.store.GetKeyRotationProgress()
+	assert.Nil(t, err)
+	assert.NotEqual(t, "lost-run", progress.RunID)
+	assert.Equal(t, started, progress.Started)
+	assert.Equal(t, "instance-1", progress.LastInstanceID)
+	assert.Equal(t, int64(1), progress.Rotated)
+}
+
+func TestScheduleKeyRotationAfterCompletedRotation(t *testing.T) {
+	b, engine, _ := getKeyRotationTestBroker(t)
+	completed := time.Now().UTC()
+	assert.Nil(t, b.store.WriteKeyRotationProgress(
+		service.KeyRotationProgress{
+			KeyVersion: "2",
+			Completed:  &completed,
+		},
+	))
+	assert.Nil(t, b.scheduleKeyRotation())
+	assert.Empty(t, engine.SubmittedTasks)
+	// A new key version starts a new rotation
+	b.keyRotation.KeyVersion = "3"
+	assert.Nil(t, b.scheduleKeyRotation())
+	assert.Len(t, engine.SubmittedTasks, 1)
+	progress, _, err := b.store.GetKeyRotationProgress()
+	assert.Nil(t, err)
+	assert.Equal(t, "3", progress.KeyVersion)
+	assert.Nil(t, progress.Completed)
+}
+
+func TestRotateEncryptionKey(t *testing.T) {
+	b, engine, instances := getKeyRotationTestBroker(t)
+	assert.Nil(t, b.scheduleKeyRotation())
+	progress, _, err := b.store.GetKeyRotationProgress()
+	assert.Nil(t, err)
+	task := getSubmittedKeyRotationTask(engine, progress.RunID)
+	batches := 0
+	for task != nil {
+		tasks, err := b.rotateEncryptionKey(context.Background(), task)
+		assert.Nil(t, err)
+		batches++
+		task = nil
+		if len(tasks) > 0 {
+			assert.Len(t, tasks, 1)
+			assert.Equal(t, rotateEncryptionKeyJobName, tasks[0].GetJobName())
+			task = tasks[0]
+		}
+	}
+	assert.Equal(t, 2, batches)
+	progress, _, err = b.store.GetKeyRotationProgress()
+	assert.Nil(t, err)
+	assert.NotNil(t, progress.Completed)
+	assert.Equal(t, "instance-3", progress.LastInstanceID)
+	assert.Equal(t, int64(1), progress.Rotated)
+	assert.Equal(t, int64(1), progress.Skipped)
+	assert.Equal(t, int64(1), progress.Deferred)
+	assert.Equal(t, int64(0), progress.Failed)
+	for i, expected := range []string{"2", "1", "2"} {
+		instance, ok, err := b.store.GetInstance(instances[i].InstanceID)
+		assert.Nil(t, err)
+		assert.True(t, ok)
+		versions, err := instance.GetKeyVersions()
+		assert.Nil(t, err)
+		assert.Equal(t, []string{expected}, versions)
+	}
+}
+
+func TestRotateEncryptionKeyOfWholeEncryptedInstances(t *testing.T) {
+	fakeModule, err := fake.New()
+	assert.Nil(t, err)
+	catalog, err := fakeModule.GetCatalog()
+	assert.Nil(t, err)
+	oldCodec, err := aes256.NewCodec(
+		[]byte(keyRotationTestKeys["1"]),
+	)
+	assert.Nil(t, err)
+	codec := &rotatableCodec{
+		FieldCodec: &unversionedCodec{
+			FieldCodec: getKeyRotationTestCodec(t, "1", false),
+			codec:      oldCodec,
+		},
+	}
+	engine := fakeAsync.NewEngine()
+	b := &broker{
+		store:       memoryStorage.NewStore(catalog, codec),
+		asyncEngine: engine,
+		catalog:     catalog,
+		keyRotation: KeyRotationConfig{
+			Enabled:       true,
+			KeyVersion:    "2",
+			BatchSize:     10,
+			BatchInterval: time.Second,
+		},
+	}
+	// The first instance was encrypted before whole values recorded a key
+	// version, the second with version 1 of the key and the third with
+	// version 2
+	for i, instanceID := range []string{
+		"instance-1",
+		"instance-2",
+		"instance-3",
+	} {
+		switch i {
+		case 1:
+			codec.FieldCodec = getKeyRotationTestCodec(t, "1", false)
+		case 2:
+			codec.FieldCodec = getKeyRotationTestCodec(t, "2", false)
+		}
+		assert.Nil(t, b.store.WriteInstance(service.Instance{
+			InstanceID: instanceID,
+			ServiceID:  fake.ServiceID,
+			PlanID:     fake.StandardPlanID,
+			Status:     service.InstanceStateProvisioned,
+			ProvisioningParameters: &fake.ProvisioningParameters{
+				SomeSecretParameter: "s3cr3t",
+			},
+			UpdatingParameters: &fake.UpdatingParameters{},
+			Details:            &fake.InstanceDetails{},
+		}))
+	}
+	assert.Nil(t, b.scheduleKeyRotation())
+	progress, _, err := b.store.GetKeyRotationProgress()
+	assert.Nil(t, err)
+	tasks, err := b.rotateEncryptionKey(
+		context.Background(),
+		getSubmittedKeyRotationTask(engine, progress.RunID),
+	)
+	assert.Nil(t, err)
+	assert.Empty(t, tasks)
+	progress, _, err = b.store.GetKeyRotationProgress()
+	assert.Nil(t, err)
+	assert.NotNil(t, progress.Completed)
+	assert.Equal(t, int64(2), progress.Rotated)
+	assert.Equal(t, int64(1), progress.Skipped)
+	assert.Equal(t, int64(0), progress.Failed)
+	// Every instance remains readable once the retired key is dropped
+	newCodec, err := aes256.NewCodec(
+		[]byte(keyRotationTestKeys["2"]),
+	)
+	assert.Nil(t, err)
+	codec.FieldCodec, err = versioned.NewCodec(
+		"2",
+		map[string]crypto.Codec{"2": newCodec},
+		false,
+	)
+	assert.Nil(t, err)
+	for _, instanceID := range []string{
+		"instance-1",
+		"instance-2",
+		"instance-3",
+	} {
+		instance, ok, err := b.store.GetInstance(instanceID)
+		assert.Nil(t, err)
+		assert.True(t, ok)
+		versions, err := instance.GetKeyVersions()
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"2"}, versions)
+		pp := instance.ProvisioningParameters.(*fake.ProvisioningParameters)
+		assert.Equal(t, "s3cr3t", pp.SomeSecretParameter)
+	}
+}
+
+func TestRotateEncryptionKeySkipsSupersededRun(t *testing.T) {
+	b, engine, _ := getKeyRotationTestBroker(t)
+	assert.Nil(t, b.scheduleKeyRotation())
+	progress, _, err := b.store.GetKeyRotationProgress()
+	assert.Nil(t, err)
+	supersededRunID := progress.RunID
+	b.keyRotation.KeyVersion = "3"
+	assert.Nil(t, b.scheduleKeyRotation())
+	tasks, err := b.rotateEncryptionKey(
+		context.Background(),
+		getSubmittedKeyRotationTask(engine, supersededRunID),
+	)
+	assert.Nil(t, err)
+	assert.Empty(t, tasks)
+	progress, _, err = b.store.GetKeyRotationProgress()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), progress.GetProcessed())
+}
+
+func TestGetKeyRotationBatch(t *testing.T) {
+	instanceIDs := []string{"c", "a", "d", "b"}
+	assert.Equal(t, []string{"a", "b"}, getKeyRotationBatch(instanceIDs, "", 2))
+	assert.Equal(
+		t,
+		[]string{"c", "d"},
+		getKeyRotationBatch(instanceIDs, "b", 2),
+	)
+	// The last instance processed may since have been deleted
+	assert.Equal(
+		t,
+		[]string{"c"},
+		getKeyRotationBatch([]string{"a", "c"}, "b", 2),
+	)
+	assert.Empty(t, getKeyRotationBatch(instanceIDs, "d", 2))
+}
+
+// getKeyRotationTestBroker returns a broker, configured to rotate the
+// encryption key to version 2 in batches of two instances, whose store holds
+// three instances. The first, which is provisioned, and the second, which is
+// being updated, are encrypted with version 1 of the key. The third is already
+// encrypted with version 2.
+func getKeyRotationTestBroker(
+	t *testing.T,
+) (*broker, *fakeAsync.Engine, []service.Instance) {
+	fakeModule, err := fake.New()
+	assert.Nil(t, err)
+	catalog, err := fakeModule.GetCatalog()
+	assert.Nil(t, err)
+	codec := &rotatableCodec{
+		FieldCodec: getKeyRotationTestCodec(t, "1", true),
+	}
+	engine := fakeAsync.NewEngine()
+	b := &broker{
+		store:       memoryStorage.NewStore(catalog, codec),
+		asyncEngine: engine,
+		catalog:     catalog,
+		keyRotation: KeyRotationConfig{
+			Enabled:       true,
+			KeyVersion:    "2",
+			BatchSize:     2,
+			BatchInterval: time.Second,
+		},
+	}
+	instances := []service.Instance{}
+	for i, status := range []string{
+		service.InstanceStateProvisioned,
+		service.InstanceStateUpdating,
+		service.InstanceStateProvisioned,
+	} {
+		if i == 2 {
+			codec.FieldCodec = getKeyRotationTestCodec(t, "2", true)
+		}
+		instance := service.Instance{
+			InstanceID: []string{"instance-1", "instance-2", "instance-3"}[i],
+			ServiceID:  fake.ServiceID,
+			PlanID:     fake.StandardPlanID,
+			Status:     status,
+			ProvisioningParameters: &fake.ProvisioningParameters{
+				SomeSecretParameter: "s3cr3t",
+			},
+			UpdatingParameters: &fake.UpdatingParameters{},
+			Details:            &fake.InstanceDetails{},
+		}
+		assert.Nil(t, b.store.WriteInstance(instance))
+		instances = append(instances, instance)
+	}
+	return b, engine, instances
+}
+
+// getSubmittedKeyRotationTask returns the key rotation task belonging to the
+// given run that was submitted to the given engine, or nil if there is none
+func getSubmittedKeyRotationTask(
+	engine *fakeAsync.Engine,
+	runID string,
+) async.Task {
+	for _, task := range engine.SubmittedTasks {
+		if task.GetJobName() == rotateEncryptionKeyJobName &&
+			task.GetArgs()["runID"] == runID {
+			return task
+		}
+	}
+	return nil
+}
+
+var keyRotationTestKeys = map[string]string{
+	"1": "AES256Key-32Characters1234567890",
+	"2": "AES256Key-32Characters0987654321",
+}
+
+func getKeyRotationTestCodec(
+	t *testing.T,
+	keyVersion string,
+	encryptsFields bool,
+) crypto.FieldCodec {
+	codecs := map[string]crypto.Codec{}
+	for version, key := range keyRotationTestKeys {
+		c, err := aes256.NewCodec([]byte(key))
+		assert.Nil(t, err)
+		codecs[version] = c
+	}
+	codec, err := versioned.NewCodec(keyVersion, codecs, encryptsFields)
+	assert.Nil(t, err)
+	return codec
+}
@@ -0,0 +1,193 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	"github.com/Azure/open-service-broker-azure/pkg/notification"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	log "github.com/Sirupsen/logrus"
+)
+
+const (
+	// maxNotificationRetries is how many times delivery of a notification is
+	// retried before it is abandoned
+	maxNotificationRetries = 5
+	// notificationRetryDelay is how long the first retry of a failed delivery
+	// waits. Each subsequent retry waits twice as long as the one before.
+	notificationRetryDelay = 30 * time.Second
+)
+
+// submitProvisioningNotifications submits a task for delivering a
+// notification of the outcome of the given instance's provisioning to each
+// interested subscriber. The notification captures the instance as it is now,
+// so later changes to the instance don't affect what is delivered. Failure to
+// submit a task is logged, but is not treated as a failure of the
+// provisioning operation itself.
+func (b *broker) submitProvisioningNotifications(instance service.Instance) {
+	b.submitNotifications(instance, instance.Status, instance.StatusReason)
+}
+
+// submitNotifications submits a task for delivering a notification about the
+// given instance, with the given status and reason, to each interested
+// subscriber
+func (b *broker) submitNotifications(
+	instance service.Instance,
+	status string,
+	statusReason string,
+) {
+	if len(b.subscribers) == 0 {
+		return
+	}
+	n := notification.Notification{
+		InstanceID:    instance.InstanceID,
+		ServiceID:     instance.ServiceID,
+		PlanID:        instance.PlanID,
+		Location:      instance.Location,
+		ResourceGroup: instance.ResourceGroup,
+		Status:        status,
+		StatusReason:  statusReason,
+		Time:          time.Now().UTC(),
+	}
+	if instance.Service != nil {
+		n.ServiceName = instance.Service.GetName()
+	}
+	if instance.Plan != nil {
+		n.PlanName = instance.Plan.GetName()
+	}
+	for _, subscriber := range b.subscribers {
+		if !subscriber.Matches(n) {
+			continue
+		}
+		args := getNotificationTaskArgs(n)
+		args["subscriber"] = subscriber.Name
+		task := async.NewTask("sendNotification", args)
+		task.SetTenant(instance.OrganizationGUID)
+		if err := b.asyncEngine.SubmitTask(task); err != nil {
+			log.WithFields(log.Fields{
+				"instanceID": instance.InstanceID,
+				"subscriber": subscriber.Name,
+				"error":      err,
+			}).Error("error submitting notification task")
+		}
+	}
+}
+
+// sendNotification delivers a single notification to a single subscriber,
+// retrying with an increasing delay if delivery fails
+func (b *broker) sendNotification(
+	_ context.Context,
+	task async.Task,
+) ([]async.Task, error) {
+	args := task.GetArgs()
+	subscriberName, ok := args["subscriber"]
+	if !ok {
+		return nil, errors.New(`missing required argument "subscriber"`)
+	}
+	n, err := getNotificationFromTaskArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	logFields := log.Fields{
+		"instanceID": n.InstanceID,
+		"subscriber": subscriberName,
+	}
+	subscriber, ok := b.getNotificationSubscriber(subscriberName)
+	if !ok {
+		// This can happen if the broker's configuration changed after this task
+		// was submitted
+		log.WithFields(logFields).Warn(
+			"notification subscriber is no longer configured; skipping",
+		)
+		return nil, nil
+	}
+	msg, err := subscriber.Format(n)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error formatting notification for subscriber "%s": %s`,
+			subscriberName,
+			err,
+		)
+	}
+	if err := subscriber.Channel.Send(msg); err != nil {
+		retryCount, _ := strconv.Atoi(args["retryCount"])
+		if retryCount >= maxNotificationRetries {
+			return nil, fmt.Errorf(
+				`error sending notification to subscriber "%s"; giving up after `+
+					`%d retries: %s`,
+				subscriberName,
+				retryCount,
+				err,
+			)
+		}
+		delay := notificationRetryDelay << uint(retryCount)
+		logFields["retryCount"] = retryCount + 1
+		logFields["retryDelay"] = delay
+		logFields["error"] = err
+		log.WithFields(logFields).Warn("error sending notification; retrying")
+		retryArgs := map[string]string{}
+		for k, v := range args {
+			retryArgs[k] = v
+		}
+		retryArgs["retryCount"] = strconv.Itoa(retryCount + 1)
+		return []async.Task{
+			async.NewDelayedTask("sendNotification", retryArgs, delay),
+		}, nil
+	}
+	log.WithFields(logFields).Debug("sent notification")
+	return nil, nil
+}
+
+func (b *broker) getNotificationSubscriber(
+	name string,
+) (notification.Subscriber, bool) {
+	for _, subscriber := range b.subscribers {
+		if subscriber.Name == name {
+			return subscriber, true
+		}
+	}
+	return notification.Subscriber{}, false
+}
+
+func getNotificationTaskArgs(n notification.Notification) map[string]string {
+	return map[string]string{
+		"instanceID":    n.InstanceID,
+		"serviceID":     n.ServiceID,
+		"serviceName":   n.ServiceName,
+		"planID":        n.PlanID,
+		"planName":      n.PlanName,
+		"location":      n.Location,
+		"resourceGroup": n.ResourceGroup,
+		"status":        n.Status,
+		"statusReason":  n.StatusReason,
+		"time":          n.Time.Format(time.RFC3339),
+	}
+}
+
+func getNotificationFromTaskArgs(
+	args map[string]string,
+) (notification.Notification, error) {
+	t, err := time.Parse(time.RFC3339, args["time"])
+	if err != nil {
+		return notification.Notification{}, fmt.Errorf(
+			`error parsing argument "time": %s`,
+			err,
+		)
+	}
+	return notification.Notification{
+		InstanceID:    args["instanceID"],
+		ServiceID:     args["serviceID"],
+		ServiceName:   args["serviceName"],
+		PlanID:        args["planID"],
+		PlanName:      args["planName"],
+		Location:      args["location"],
+		ResourceGroup: args["resourceGroup"],
+		Status:        args["status"],
+		StatusReason:  args["statusReason"],
+		Time:          t,
+	}, nil
+}
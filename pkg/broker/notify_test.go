@@ -0,0 +1,186 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	fakeAsync "github.com/Azure/open-service-broker-azure/pkg/async/fake"
+	"github.com/Azure/open-service-broker-azure/pkg/notification"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeChannel struct {
+	messages []notification.Message
+	err      error
+}
+
+func (f *fakeChannel) Send(msg notification.Message) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.messages = append(f.messages, msg)
+	return nil
+}
+
+func TestSuccessfulProvisioningSubmitsNotifications(t *testing.T) {
+	b, engine, _, instance := getNotificationTestBroker(t)
+	_, err := b.executeProvisioningStep(
+		context.Background(),
+		async.NewTask(
+			"executeProvisioningStep",
+			map[string]string{
+				"stepName":   "run",
+				"instanceID": instance.InstanceID,
+			},
+		),
+	)
+	assert.Nil(t, err)
+	// Only the subscribers that aren't filtering out this service or status
+	// should be notified
+	tasks := getSubmittedNotificationTasks(engine)
+	assert.Len(t, tasks, 1)
+	args := tasks["everything"].GetArgs()
+	assert.Equal(t, instance.InstanceID, args["instanceID"])
+	assert.Equal(t, service.InstanceStateProvisioned, args["status"])
+	assert.Equal(t, "org", tasks["everything"].GetTenant())
+}
+
+func TestFailedProvisioningSubmitsNotifications(t *testing.T) {
+	b, engine, _, instance := getNotificationTestBroker(t)
+	err := b.handleProvisioningError(
+		instance,
+		"run",
+		errors.New("quota exceeded"),
+		"error executing provisioning step",
+	)
+	assert.NotNil(t, err)
+	tasks := getSubmittedNotificationTasks(engine)
+	assert.Len(t, tasks, 2)
+	args := tasks["failures"].GetArgs()
+	assert.Equal(t, service.InstanceStateProvisioningFailed, args["status"])
+	assert.Contains(t, args["statusReason"], "quota exceeded")
+	_, ok := tasks["everything"]
+	assert.True(t, ok)
+}
+
+func TestSendNotification(t *testing.T) {
+	b, _, channel, instance := getNotificationTestBroker(t)
+	followUpTasks, err := b.sendNotification(
+		context.Background(),
+		getTestNotificationTask(instance, nil),
+	)
+	assert.Nil(t, err)
+	assert.Empty(t, followUpTasks)
+	assert.Len(t, channel.messages, 1)
+	assert.Equal(
+		t,
+		"Instance instance failed to provision",
+		channel.messages[0].Subject,
+	)
+}
+
+func TestSendNotificationRetriesOnError(t *testing.T) {
+	b, _, channel, instance := getNotificationTestBroker(t)
+	channel.err = errors.New("connection refused")
+	followUpTasks, err := b.sendNotification(
+		context.Background(),
+		getTestNotificationTask(instance, nil),
+	)
+	assert.Nil(t, err)
+	assert.Len(t, followUpTasks, 1)
+	assert.NotNil(t, followUpTasks[0].GetExecuteTime())
+	assert.Equal(t, "1", followUpTasks[0].GetArgs()["retryCount"])
+	assert.Equal(t, "failures", followUpTasks[0].GetArgs()["subscriber"])
+	// Once retries are exhausted, delivery is abandoned
+	followUpTasks, err = b.sendNotification(
+		context.Background(),
+		getTestNotificationTask(
+			instance,
+			map[string]string{"retryCount": "5"},
+		),
+	)
+	assert.NotNil(t, err)
+	assert.Empty(t, followUpTasks)
+}
+
+func TestSendNotificationSkipsUnknownSubscriber(t *testing.T) {
+	b, _, channel, instance := getNotificationTestBroker(t)
+	followUpTasks, err := b.sendNotification(
+		context.Background(),
+		getTestNotificationTask(
+			instance,
+			map[string]string{"subscriber": "nobody"},
+		),
+	)
+	assert.Nil(t, err)
+	assert.Empty(t, followUpTasks)
+	assert.Empty(t, channel.messages)
+}
+
+func getNotificationTestBroker(
+	t *testing.T,
+) (*broker, *fakeAsync.Engine, *fakeChannel, service.Instance) {
+	b, _, instance := getConnectivityValidationTestBroker(t)
+	b.connectivityValidated = nil
+	engine := fakeAsync.NewEngine()
+	b.asyncEngine = engine
+	instance.OrganizationGUID = "org"
+	assert.Nil(t, b.store.WriteInstance(instance))
+	channel := &fakeChannel{}
+	for _, config := range []notification.SubscriberConfig{
+		{
+			Name: "everything",
+		},
+		{
+			Name:     "failures",
+			Statuses: []string{service.InstanceStateProvisioningFailed},
+		},
+		{
+			Name:       "other-service",
+			ServiceIDs: []string{"other-service"},
+		},
+	} {
+		config.Type = "slack"
+		config.WebhookURL = "https://example.com"
+		subscriber, err := notification.NewSubscriber(config)
+		assert.Nil(t, err)
+		subscriber.Channel = channel
+		b.subscribers = append(b.subscribers, subscriber)
+	}
+	return b, engine, channel, instance
+}
+
+// getSubmittedNotificationTasks returns the notification tasks submitted to
+// the given engine, indexed by subscriber
+func getSubmittedNotificationTasks(
+	engine *fakeAsync.Engine,
+) map[string]async.Task {
+	tasks := map[string]async.Task{}
+	for _, task := range engine.SubmittedTasks {
+		if task.GetJobName() == "sendNotification" {
+			tasks[task.GetArgs()["subscriber"]] = task
+		}
+	}
+	return tasks
+}
+
+func getTestNotificationTask(
+	instance service.Instance,
+	overrides map[string]string,
+) async.Task {
+	args := map[string]string{
+		"subscriber": "failures",
+		"instanceID": instance.InstanceID,
+		"serviceID":  fake.ServiceID,
+		"status":     service.InstanceStateProvisioningFailed,
+		"time":       "2018-01-02T03:04:05Z",
+	}
+	for k, v := range overrides {
+		args[k] = v
+	}
+	return async.NewTask("sendNotification", args)
+}
@@ -4,12 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/Azure/open-service-broker-azure/pkg/async"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 	log "github.com/Sirupsen/logrus"
 )
 
+// provisioningSlotRetryDelay is how long a provisioning operation waits before
+// it re-attempts to acquire a slot in a subscription that was at capacity
+const provisioningSlotRetryDelay = 15 * time.Second
+
 func (b *broker) executeProvisioningStep(
 	ctx context.Context,
 	task async.Task,
@@ -79,6 +85,37 @@ func (b *broker) executeProvisioningStep(
 			),
 		)
 	}
+	// Operators may override the order in which steps are executed, or skip
+	// some of them altogether. Success criteria are checked after all other
+	// steps, whatever their order, and only optional criteria may be skipped.
+	declaredCriteria, checkedCriteria := b.getSuccessCriteria(instance)
+	declaredProvisioner, err := provisioner.WithSuccessCriteria(declaredCriteria)
+	if err != nil {
+		return nil, b.handleProvisioningError(
+			instance,
+			stepName,
+			err,
+			"error adding success criteria to provisioner",
+		)
+	}
+	provisioner, err = b.applyStepOrder(instance.ServiceID, provisioner)
+	if err != nil {
+		return nil, b.handleProvisioningError(
+			instance,
+			stepName,
+			err,
+			"error applying provisioning step order override",
+		)
+	}
+	provisioner, err = provisioner.WithSuccessCriteria(checkedCriteria)
+	if err != nil {
+		return nil, b.handleProvisioningError(
+			instance,
+			stepName,
+			err,
+			"error adding success criteria to provisioner",
+		)
+	}
 	step, ok := provisioner.GetStep(stepName)
 	if !ok {
 		return nil, b.handleProvisioningError(
@@ -88,8 +125,163 @@ func (b *broker) executeProvisioningStep(
 			`provisioner does not know how to process step "%s"`,
 		)
 	}
-	updatedDetails, err := step.Execute(ctx, instance)
+	// A task can be delivered again if the broker stops while executing it. If
+	// the step it names is recorded as having completed since, don't execute it
+	// again.
+	if b.recordStepExecutions {
+		if _, ok := getCompletedStepExecution(instance, stepName, args); ok {
+			return b.getTasksFollowingCompletedStep(instance, provisioner, stepName),
+				nil
+		}
+	}
+	// Before the first step of any provisioning operation is executed, the
+	// operation must hold one of its module's provisioning slots, if its module
+	// is capped, and one of its subscription's. If either isn't available,
+	// return a delayed copy of this task to try again later. An operation
+	// waiting on its module is held in the queued state meanwhile.
+	firstStepName, _ := provisioner.GetFirstStepName()
+	if stepName == firstStepName {
+		claimed, err := b.claimProvisioningCapacity(instance)
+		if err != nil {
+			return nil, b.handleProvisioningError(
+				instance,
+				stepName,
+				err,
+				"error claiming provisioning capacity",
+			)
+		}
+		// A queued instance that has claimed capacity is no longer queued, and
+		// one that couldn't claim any is queued if it wasn't already
+		queued := instance.Status == service.InstanceStateQueued
+		if claimed == queued {
+			if claimed {
+				instance.Status = service.InstanceStateProvisioning
+			} else {
+				instance.Status = service.InstanceStateQueued
+			}
+			instanceCopy.Status = instance.Status
+			if err = b.store.WriteInstance(instanceCopy); err != nil {
+				return nil, b.handleProvisioningError(
+					instance,
+					stepName,
+					err,
+					"error persisting instance",
+				)
+			}
+		}
+		if !claimed {
+			return []async.Task{
+				async.NewDelayedTask(
+					"executeProvisioningStep",
+					map[string]string{
+						"stepName":   stepName,
+						"instanceID": instanceID,
+					},
+					provisioningSlotRetryDelay,
+				),
+			}, nil
+		}
+		acquired, err := b.acquireProvisioningSlot(instance)
+		if err != nil {
+			return nil, b.handleProvisioningError(
+				instance,
+				stepName,
+				err,
+				"error acquiring provisioning slot",
+			)
+		}
+		if !acquired {
+			return []async.Task{
+				async.NewDelayedTask(
+					"executeProvisioningStep",
+					map[string]string{
+						"stepName":   stepName,
+						"instanceID": instanceID,
+					},
+					provisioningSlotRetryDelay,
+				),
+			}, nil
+		}
+		// Record which of the declared steps won't be executed for this instance
+		instanceCopy.SkippedProvisioningSteps =
+			getSkippedStepNames(declaredProvisioner, provisioner)
+		if len(instanceCopy.SkippedProvisioningSteps) > 0 {
+			log.WithFields(log.Fields{
+				"instanceID":   instanceID,
+				"skippedSteps": instanceCopy.SkippedProvisioningSteps,
+			}).Info(
+				"skipping provisioning steps per step order override or success " +
+					"criteria configuration",
+			)
+		}
+	} else {
+		// Each subsequent step renews the slot, so that it isn't reaped while the
+		// operation is still making progress
+		b.renewProvisioningSlot(instance)
+	}
+	if b.recordStepExecutions {
+		if instanceCopy, err = b.startStepExecution(
+			instanceCopy,
+			stepName,
+			args,
+		); err != nil {
+			return nil, b.handleProvisioningError(
+				instance,
+				stepName,
+				err,
+				"error persisting instance",
+			)
+		}
+	}
+	if instance, err = b.withDependencies(instance); err != nil {
+		return nil, b.handleProvisioningError(
+			instance,
+			stepName,
+			err,
+			"error loading instance's dependencies",
+		)
+	}
+	stepStarted := time.Now()
+	updatedDetails, err :=
+		b.executeTracedStep(ctx, "provisioning", step, instance)
 	if err != nil {
+		// If the retry policy permits, try the step again later. Failing that, if
+		// the service has a failure grace window, hold the instance as degraded
+		// and keep trying until the window is exhausted. Otherwise, fail. A
+		// degraded instance has already exhausted the retry policy, so it isn't
+		// consulted again. Note that a retried step starts over from the instance
+		// details as they were persisted after the last successful step.
+		retryCount, _ := strconv.Atoi(args["retryCount"])
+		if delay, ok := b.retryPolicy.getRetryDelay(err, retryCount); ok &&
+			instance.Status != service.InstanceStateProvisioningDegraded {
+			log.WithFields(log.Fields{
+				"step":          stepName,
+				"instanceID":    instanceID,
+				"errorCategory": service.GetErrorCategory(err),
+				"retryCount":    retryCount + 1,
+				"retryDelay":    delay,
+				"error":         err,
+			}).Warn("provisioning step failed; retrying")
+			return []async.Task{
+				async.NewDelayedTask(
+					"executeProvisioningStep",
+					withExecutionToken(
+						map[string]string{
+							"stepName":   stepName,
+							"instanceID": instanceID,
+							"retryCount": strconv.Itoa(retryCount + 1),
+						},
+						instanceCopy,
+						stepName,
+					),
+					delay,
+				),
+			}, nil
+		}
+		if retryTask, ok :=
+			b.getFailureGraceRetryTask(instanceCopy, stepName, err); ok {
+			return []async.Task{retryTask}, nil
+		}
 		return nil, b.handleProvisioningError(
 			instance,
 			stepName,
@@ -97,8 +289,26 @@ func (b *broker) executeProvisioningStep(
 			"error executing provisioning step",
 		)
 	}
+	b.recordProvisioningStepDuration(
+		instanceCopy,
+		stepName,
+		time.Since(stepStarted),
+	)
+	instanceCopy = withRecoveredProvisioning(instanceCopy)
 	instanceCopy.Details = updatedDetails
-	if nextStepName, ok := provisioner.GetNextStepName(step.GetName()); ok {
+	nextStepName, hasNextStep := provisioner.GetNextStepName(step.GetName())
+	// The step is recorded as completed in the same write that persists the
+	// details it returned
+	if b.recordStepExecutions {
+		instanceCopy =
+			withCompletedStepExecution(instanceCopy, stepName, nextStepName)
+	}
+	if hasNextStep {
+		instanceCopy = withProvisioningProgress(
+			instanceCopy,
+			provisioner,
+			nextStepName,
+		)
 		if err = b.store.WriteInstance(instanceCopy); err != nil {
 			return nil, b.handleProvisioningError(
 				instanceCopy,
@@ -110,15 +320,61 @@ func (b *broker) executeProvisioningStep(
 		return []async.Task{
 			async.NewTask(
 				"executeProvisioningStep",
+				withExecutionToken(
+					map[string]string{
+						"stepName":   nextStepName,
+						"instanceID": instanceID,
+					},
+					instanceCopy,
+					nextStepName,
+				),
+			),
+		}, nil
+	}
+	instanceCopy = withProvisioningProgress(instanceCopy, provisioner, "")
+	// No next step. If the broker validates connectivity to instances of this
+	// service, do that before considering provisioning complete.
+	if _, ok := b.getConnectivityValidator(instance); ok {
+		if err = b.store.WriteInstance(instanceCopy); err != nil {
+			return nil, b.handleProvisioningError(
+				instanceCopy,
+				stepName,
+				err,
+				"error persisting instance",
+			)
+		}
+		return []async.Task{
+			async.NewTask(
+				"validateConnectivity",
 				map[string]string{
-					"stepName":   nextStepName,
 					"instanceID": instanceID,
 				},
 			),
 		}, nil
 	}
-	// No next step-- we're done provisioning!
+	// Instances provisioned with deferred activation are deactivated before
+	// provisioning is considered complete
+	if instanceCopy.IsPendingActivation() {
+		if err = b.store.WriteInstance(instanceCopy); err != nil {
+			return nil, b.handleProvisioningError(
+				instanceCopy,
+				stepName,
+				err,
+				"error persisting instance",
+			)
+		}
+		return []async.Task{
+			async.NewTask(
+				deactivateInstanceStepName,
+				map[string]string{
+					"instanceID": instanceID,
+				},
+			),
+		}, nil
+	}
+	// We're done provisioning!
 	instanceCopy.Status = service.InstanceStateProvisioned
+	instanceCopy = b.withProvisioningTiming(instanceCopy)
 	if err = b.store.WriteInstance(instanceCopy); err != nil {
 		return nil, b.handleProvisioningError(
 			instanceCopy,
@@ -127,9 +383,125 @@ func (b *broker) executeProvisioningStep(
 			"error persisting instance",
 		)
 	}
+	b.releaseProvisioningSlot(instanceCopy)
+	b.recordProvisioningSLAOutcome(instanceCopy)
+	b.submitProvisioningNotifications(instanceCopy)
 	return nil, nil
 }
 
+// acquireProvisioningSlot attempts to obtain, on behalf of the specified
+// instance, one of the limited number of provisioning slots for the Azure
+// subscription that the instance is provisioned into. If that subscription has
+// no cap on concurrent provisions, this always succeeds.
+func (b *broker) acquireProvisioningSlot(
+	instance service.Instance,
+) (bool, error) {
+	subscriptionID := b.getSubscriptionID(instance)
+	max := b.provisioningLimits.getMaxConcurrentProvisions(subscriptionID)
+	if max <= 0 {
+		return true, nil
+	}
+	acquired, count, err := b.provisioningSemaphore.acquire(
+		subscriptionID,
+		instance.InstanceID,
+		max,
+	)
+	if err != nil {
+		return false, err
+	}
+	logFields := log.Fields{
+		"subscriptionID":          subscriptionID,
+		"instanceID":              instance.InstanceID,
+		"inFlightProvisions":      count,
+		"maxConcurrentProvisions": max,
+	}
+	if acquired {
+		log.WithFields(logFields).Debug("acquired provisioning slot")
+	} else {
+		log.WithFields(logFields).Info(
+			"subscription is at its cap on concurrent provisions; provisioning " +
+				"will wait",
+		)
+	}
+	return acquired, nil
+}
+
+// renewProvisioningSlot extends the lease on the provisioning slot (if any)
+// held by the specified instance. Failure to do so is logged, but is not
+// treated as a failure of the provisioning operation itself.
+func (b *broker) renewProvisioningSlot(instance service.Instance) {
+	subscriptionID := b.getSubscriptionID(instance)
+	if b.provisioningLimits.getMaxConcurrentProvisions(subscriptionID) <= 0 {
+		return
+	}
+	if err := b.provisioningSemaphore.renew(
+		subscriptionID,
+		instance.InstanceID,
+	); err != nil {
+		log.WithFields(log.Fields{
+			"subscriptionID": subscriptionID,
+			"instanceID":     instance.InstanceID,
+			"error":          err,
+		}).Error("error renewing provisioning slot")
+	}
+}
+
+// releaseProvisioningSlot gives up the provisioning slot (if any) held by the
+// specified instance, along with any module capacity it holds. Failure to do
+// so is logged, but is not treated as a failure of the provisioning operation
+// itself.
+func (b *broker) releaseProvisioningSlot(instance service.Instance) {
+	b.releaseProvisioningSlotInSubscription(
+		b.getSubscriptionID(instance),
+		instance.InstanceID,
+	)
+	b.releaseProvisioningCapacity(instance)
+}
+
+// releaseProvisioningSlotInSubscription gives up the provisioning slot (if
+// any) held by the specified instance in the specified subscription
+func (b *broker) releaseProvisioningSlotInSubscription(
+	subscriptionID string,
+	instanceID string,
+) {
+	max := b.provisioningLimits.getMaxConcurrentProvisions(subscriptionID)
+	if max <= 0 {
+		return
+	}
+	logFields := log.Fields{
+		"subscriptionID": subscriptionID,
+		"instanceID":     instanceID,
+	}
+	count, err := b.provisioningSemaphore.release(subscriptionID, instanceID)
+	if err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error("error releasing provisioning slot")
+		return
+	}
+	logFields["inFlightProvisions"] = count
+	logFields["maxConcurrentProvisions"] = max
+	log.WithFields(logFields).Debug("released provisioning slot")
+}
+
+// getSubscriptionID returns the Azure subscription that the specified
+// instance is provisioned into. Instances provisioned before subscriptions
+// were recorded are in the broker's own subscription.
+func (b *broker) getSubscriptionID(instance service.Instance) string {
+	if instance.SubscriptionID != "" {
+		return instance.SubscriptionID
+	}
+	return b.provisioningLimits.AzureSubscriptionID
+}
+
+// getSubscriptionIDs returns every Azure subscription that instances may be
+// provisioned into
+func (b *broker) getSubscriptionIDs() []string {
+	return append(
+		[]string{b.provisioningLimits.AzureSubscriptionID},
+		b.subscriptionRouting.GetSubscriptionIDs()...,
+	)
+}
+
 // handleProvisioningError tries to handle async provisioning errors. If an
 // instance is passed in, its status is updated and an attempt is made to
 // persist the instance with updated status. If this fails, we have a very
@@ -146,6 +518,16 @@ func (b *broker) handleProvisioningError(
 	instance, ok := instanceOrInstanceID.(service.Instance)
 	if !ok {
 		instanceID := instanceOrInstanceID
+		if id, ok := instanceID.(string); ok {
+			// Without the instance, there's no knowing which subscription's slot
+			// it holds
+			for _, subscriptionID := range b.getSubscriptionIDs() {
+				b.releaseProvisioningSlotInSubscription(subscriptionID, id)
+			}
+			for _, pool := range b.provisioningCapacity.GetPools() {
+				b.releaseProvisioningCapacityInPool(pool, id)
+			}
+		}
 		if e == nil {
 			return fmt.Errorf(
 				`error executing provisioning step "%s" for instance "%s": %s`,
@@ -163,6 +545,7 @@ func (b *broker) handleProvisioningError(
 		)
 	}
 	// If we get to here, we have an instance (not just an instanceID)
+	b.releaseProvisioningSlot(instance)
 	instance.Status = service.InstanceStateProvisioningFailed
 	var ret error
 	if e == nil {
@@ -190,5 +573,6 @@ func (b *broker) handleProvisioningError(
 			"persistenceError": err,
 		}).Fatal("error persisting instance with updated status")
 	}
+	b.submitProvisioningNotifications(instance)
 	return ret
 }
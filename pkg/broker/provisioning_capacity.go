@@ -0,0 +1,142 @@
+package broker
+
+import (
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	log "github.com/Sirupsen/logrus"
+)
+
+// ProvisioningCapacityConfig represents caps on the number of instances of
+// each module's services that may be provisioning at once, independent of any
+// cap on the subscription they're provisioned into. Requests that arrive while
+// a module is at its cap are refused unless the module has a queue, in which
+// case they are held in the QUEUED state, in the order they arrived, until
+// capacity frees up. Requests that arrive while the queue is full are refused.
+type ProvisioningCapacityConfig struct {
+	// MaxConcurrencyByModule is keyed by module name
+	MaxConcurrencyByModule map[string]int
+	// QueueDepthByModule is keyed by module name. A module without a queue
+	// depth has no queue.
+	QueueDepthByModule map[string]int
+}
+
+// getProvisioningCapacityPolicy returns a policy that caps concurrent
+// provisions of the given services per the given config, in which only known
+// modules may be named. Each module's services share a single pool of
+// capacity, which is named after the module.
+func getProvisioningCapacityPolicy(
+	moduleNamesByServiceID map[string]string,
+	config ProvisioningCapacityConfig,
+) (service.ProvisioningCapacityPolicy, error) {
+	knownModules := map[string]bool{}
+	for _, moduleName := range moduleNamesByServiceID {
+		knownModules[moduleName] = true
+	}
+	for moduleName, max := range config.MaxConcurrencyByModule {
+		if !knownModules[moduleName] {
+			return service.ProvisioningCapacityPolicy{}, fmt.Errorf(
+				`provisioning concurrency configuration names unknown module "%s"`,
+				moduleName,
+			)
+		}
+		if max <= 0 {
+			return service.ProvisioningCapacityPolicy{}, fmt.Errorf(
+				`provisioning concurrency for module "%s" must be greater than zero`,
+				moduleName,
+			)
+		}
+	}
+	for moduleName, depth := range config.QueueDepthByModule {
+		if _, ok := config.MaxConcurrencyByModule[moduleName]; !ok {
+			return service.ProvisioningCapacityPolicy{}, fmt.Errorf(
+				`provisioning queue depth is configured for module "%s", which has `+
+					"no provisioning concurrency cap",
+				moduleName,
+			)
+		}
+		if depth < 0 {
+			return service.ProvisioningCapacityPolicy{}, fmt.Errorf(
+				`provisioning queue depth for module "%s" must not be negative`,
+				moduleName,
+			)
+		}
+	}
+	limitsByServiceID := map[string]service.ProvisioningCapacityLimits{}
+	for serviceID, moduleName := range moduleNamesByServiceID {
+		max, ok := config.MaxConcurrencyByModule[moduleName]
+		if !ok {
+			continue
+		}
+		limitsByServiceID[serviceID] = service.ProvisioningCapacityLimits{
+			Pool:                    moduleName,
+			MaxConcurrentProvisions: max,
+			MaxQueueDepth:           config.QueueDepthByModule[moduleName],
+		}
+	}
+	return service.NewProvisioningCapacityPolicy(limitsByServiceID), nil
+}
+
+// claimProvisioningCapacity attempts to obtain, on behalf of the specified
+// instance, one of the slots for concurrent provisions shared by its module's
+// services. Slots go to queued instances in the order they were queued; an
+// instance that can't have one yet keeps its place in the queue, or joins the
+// back of it if it had none. If the module has no cap, this always succeeds.
+func (b *broker) claimProvisioningCapacity(
+	instance service.Instance,
+) (bool, error) {
+	limits, ok := b.provisioningCapacity.GetLimits(instance.Service)
+	if !ok {
+		return true, nil
+	}
+	claimed, position, err := b.store.ClaimProvisioningCapacity(
+		limits.Pool,
+		instance.InstanceID,
+		limits.MaxConcurrentProvisions,
+	)
+	if err != nil {
+		return false, err
+	}
+	logFields := log.Fields{
+		"pool":                    limits.Pool,
+		"instanceID":              instance.InstanceID,
+		"maxConcurrentProvisions": limits.MaxConcurrentProvisions,
+	}
+	if claimed {
+		log.WithFields(logFields).Debug("claimed provisioning capacity")
+	} else {
+		logFields["queuePosition"] = position
+		log.WithFields(logFields).Info(
+			"module is at its cap on concurrent provisions; provisioning is queued",
+		)
+	}
+	return claimed, nil
+}
+
+// releaseProvisioningCapacity gives up the module capacity (if any) held by
+// the specified instance, as well as its place in the queue for it, if it has
+// one
+func (b *broker) releaseProvisioningCapacity(instance service.Instance) {
+	limits, ok := b.provisioningCapacity.GetLimits(instance.Service)
+	if !ok {
+		return
+	}
+	b.releaseProvisioningCapacityInPool(limits.Pool, instance.InstanceID)
+}
+
+// releaseProvisioningCapacityInPool gives up the capacity (if any) held by the
+// specified instance in the specified pool, along with its place in that
+// pool's queue. Failure to do so is logged, but is not treated as a failure of
+// the provisioning operation itself.
+func (b *broker) releaseProvisioningCapacityInPool(
+	pool string,
+	instanceID string,
+) {
+	if err := b.store.ReleaseProvisioningCapacity(pool, instanceID); err != nil {
+		log.WithFields(log.Fields{
+			"pool":       pool,
+			"instanceID": instanceID,
+			"error":      err,
+		}).Error("error releasing provisioning capacity")
+	}
+}
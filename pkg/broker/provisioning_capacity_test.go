@@ -0,0 +1,125 @@
+package broker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	fakeAsync "github.com/Azure/open-service-broker-azure/pkg/async/fake"
+	"github.com/Azure/open-service-broker-azure/pkg/crypto/noop"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	memoryStorage "github.com/Azure/open-service-broker-azure/pkg/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetProvisioningCapacityPolicy(t *testing.T) {
+	moduleNamesByServiceID := map[string]string{fake.ServiceID: "fake"}
+	policy, err := getProvisioningCapacityPolicy(
+		moduleNamesByServiceID,
+		ProvisioningCapacityConfig{
+			MaxConcurrencyByModule: map[string]int{"fake": 2},
+			QueueDepthByModule:     map[string]int{"fake": 5},
+		},
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"fake"}, policy.GetPools())
+	testCases := []ProvisioningCapacityConfig{
+		// Unknown module
+		{MaxConcurrencyByModule: map[string]int{"bogus": 2}},
+		// Non-positive cap
+		{MaxConcurrencyByModule: map[string]int{"fake": 0}},
+		// Queue for a module without a cap
+		{QueueDepthByModule: map[string]int{"fake": 5}},
+		// Negative queue depth
+		{
+			MaxConcurrencyByModule: map[string]int{"fake": 2},
+			QueueDepthByModule:     map[string]int{"fake": -1},
+		},
+	}
+	for _, testCase := range testCases {
+		_, err = getProvisioningCapacityPolicy(moduleNamesByServiceID, testCase)
+		assert.NotNil(t, err)
+	}
+}
+
+func TestProvisioningWaitsInQueueForModuleCapacity(t *testing.T) {
+	fakeModule, err := fake.New()
+	assert.Nil(t, err)
+	catalog, err := fakeModule.GetCatalog()
+	assert.Nil(t, err)
+	b := &broker{
+		store:                 memoryStorage.NewStore(catalog, noop.NewCodec()),
+		asyncEngine:           fakeAsync.NewEngine(),
+		catalog:               catalog,
+		provisioningSemaphore: newMemoryProvisioningSemaphore(),
+	}
+	b.provisioningCapacity, err = getProvisioningCapacityPolicy(
+		map[string]string{fake.ServiceID: "fake"},
+		ProvisioningCapacityConfig{
+			MaxConcurrencyByModule: map[string]int{"fake": 1},
+		},
+	)
+	assert.Nil(t, err)
+	svc, ok := catalog.GetService(fake.ServiceID)
+	assert.True(t, ok)
+	plan, ok := svc.GetPlan(fake.StandardPlanID)
+	assert.True(t, ok)
+	instance := service.Instance{
+		InstanceID:             "instance",
+		ServiceID:              fake.ServiceID,
+		Service:                svc,
+		PlanID:                 fake.StandardPlanID,
+		Plan:                   plan,
+		Status:                 service.InstanceStateProvisioning,
+		ProvisioningParameters: &fake.ProvisioningParameters{},
+		UpdatingParameters:     &fake.UpdatingParameters{},
+		Details:                &fake.InstanceDetails{},
+	}
+	assert.Nil(t, b.store.WriteInstance(instance))
+
+	// Another instance holds the module's only slot
+	claimed, _, err :=
+		b.store.ClaimProvisioningCapacity("fake", "other-instance", 1)
+	assert.Nil(t, err)
+	assert.True(t, claimed)
+
+	args := map[string]string{
+		"stepName":   "run",
+		"instanceID": instance.InstanceID,
+	}
+	followUpTasks, err := b.executeProvisioningStep(
+		context.Background(),
+		async.NewTask("executeProvisioningStep", args),
+	)
+	assert.Nil(t, err)
+	instance, ok, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, service.InstanceStateQueued, instance.Status)
+	assert.Len(t, followUpTasks, 1)
+	assert.NotNil(t, followUpTasks[0].GetExecuteTime())
+	assert.Equal(t, args, followUpTasks[0].GetArgs())
+	position, err :=
+		b.store.GetProvisioningQueuePosition("fake", instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), position)
+
+	// Once the slot is freed up, the queued instance proceeds and, upon
+	// completion, releases the slot again
+	assert.Nil(t, b.store.ReleaseProvisioningCapacity("fake", "other-instance"))
+	followUpTasks, err = b.executeProvisioningStep(
+		context.Background(),
+		async.NewTask("executeProvisioningStep", args),
+	)
+	assert.Nil(t, err)
+	instance, ok, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, service.InstanceStateProvisioned, instance.Status)
+	assert.Empty(t, followUpTasks)
+	claimed, _, err =
+		b.store.ClaimProvisioningCapacity("fake", "another-instance", 1)
+	assert.Nil(t, err)
+	assert.True(t, claimed)
+}
@@ -0,0 +1,274 @@
+package broker
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// defaultProvisioningSlotTTL is how long a provisioning slot is held without
+// being renewed, unless otherwise configured, before it is presumed abandoned
+const defaultProvisioningSlotTTL = time.Hour
+
+// ProvisioningLimits represents caps on the number of provisioning operations
+// that may be in-flight at once against a single Azure subscription. Caps
+// apply across all modules. A cap of zero means no cap.
+type ProvisioningLimits struct {
+	// AzureSubscriptionID is the subscription modules provision resources into
+	// unless an instance is routed to another
+	AzureSubscriptionID string
+	// MaxConcurrentProvisions is the cap applied to any subscription that
+	// doesn't have a cap of its own in PerSubscriptionMax
+	MaxConcurrentProvisions int
+	// PerSubscriptionMax maps subscription IDs to caps that override
+	// MaxConcurrentProvisions
+	PerSubscriptionMax map[string]int
+	// SlotTTL is how long a provisioning slot is held without being renewed,
+	// which each provisioning step does, before it is presumed abandoned by a
+	// broker that stopped mid-provision and is reaped. Zero means
+	// defaultProvisioningSlotTTL.
+	SlotTTL time.Duration
+}
+
+func (p ProvisioningLimits) getMaxConcurrentProvisions(
+	subscriptionID string,
+) int {
+	if max, ok := p.PerSubscriptionMax[subscriptionID]; ok {
+		return max
+	}
+	return p.MaxConcurrentProvisions
+}
+
+// provisioningSemaphore tracks which instances currently hold one of a
+// subscription's limited number of provisioning slots. Slots are leased, so
+// that those held by instances whose provisioning was abandoned are
+// eventually freed.
+type provisioningSemaphore interface {
+	// acquire attempts to obtain a slot for the given instance. Acquiring a slot
+	// that the instance already holds renews it without consuming another. The
+	// number of slots held after the attempt is returned whether it succeeds or
+	// not.
+	acquire(subscriptionID string, instanceID string, max int) (bool, int64, error)
+	// renew extends the lease on the slot (if any) held by the given instance
+	renew(subscriptionID string, instanceID string) error
+	// release gives up the slot (if any) held by the given instance and returns
+	// the number of slots still held
+	release(subscriptionID string, instanceID string) (int64, error)
+	// count returns the number of slots currently held
+	count(subscriptionID string) (int64, error)
+}
+
+// acquireScript atomically reaps the slots whose leases have expired and then
+// checks a subscription's in-flight count against its cap before recording a
+// new holder. Holders are scored by the time, in milliseconds, at which they
+// last acquired or renewed their slots. A cap <= 0 means no cap.
+var acquireScript = redis.NewScript(`
+local now = tonumber(ARGV[3])
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now - tonumber(ARGV[4]))
+if redis.call("ZSCORE", KEYS[1], ARGV[1]) then
+  redis.call("ZADD", KEYS[1], now, ARGV[1])
+  return {1, redis.call("ZCARD", KEYS[1])}
+end
+local count = redis.call("ZCARD", KEYS[1])
+local max = tonumber(ARGV[2])
+if max > 0 and count >= max then
+  return {0, count}
+end
+redis.call("ZADD", KEYS[1], now, ARGV[1])
+return {1, count + 1}
+`)
+
+type redisProvisioningSemaphore struct {
+	redisClient *redis.Client
+	ttl         time.Duration
+	// This allows tests to inject an alternative implementation of this function
+	now func() time.Time
+}
+
+func newRedisProvisioningSemaphore(
+	redisClient *redis.Client,
+	ttl time.Duration,
+) provisioningSemaphore {
+	if ttl <= 0 {
+		ttl = defaultProvisioningSlotTTL
+	}
+	return &redisProvisioningSemaphore{
+		redisClient: redisClient,
+		ttl:         ttl,
+		now:         time.Now,
+	}
+}
+
+func (r *redisProvisioningSemaphore) acquire(
+	subscriptionID string,
+	instanceID string,
+	max int,
+) (bool, int64, error) {
+	key := getProvisioningSemaphoreKey(subscriptionID)
+	res, err := acquireScript.Run(
+		r.redisClient,
+		[]string{key},
+		instanceID,
+		max,
+		getUnixMillis(r.now()),
+		int64(r.ttl/time.Millisecond),
+	).Result()
+	if err != nil {
+		return false, 0, fmt.Errorf(
+			`error acquiring provisioning slot in subscription "%s": %s`,
+			subscriptionID,
+			err,
+		)
+	}
+	vals, ok := res.([]interface{})
+	if !ok || len(vals) != 2 {
+		return false, 0, fmt.Errorf(
+			`unexpected result acquiring provisioning slot in subscription "%s"`,
+			subscriptionID,
+		)
+	}
+	acquired, _ := vals[0].(int64)
+	count, _ := vals[1].(int64)
+	return acquired == 1, count, nil
+}
+
+func (r *redisProvisioningSemaphore) renew(
+	subscriptionID string,
+	instanceID string,
+) error {
+	// XX updates only the score of an existing holder, so a slot that was
+	// already released or reaped isn't taken back regardless of the cap
+	if err := r.redisClient.ZAddXX(
+		getProvisioningSemaphoreKey(subscriptionID),
+		redis.Z{
+			Score:  float64(getUnixMillis(r.now())),
+			Member: instanceID,
+		},
+	).Err(); err != nil {
+		return fmt.Errorf(
+			`error renewing provisioning slot in subscription "%s": %s`,
+			subscriptionID,
+			err,
+		)
+	}
+	return nil
+}
+
+func (r *redisProvisioningSemaphore) release(
+	subscriptionID string,
+	instanceID string,
+) (int64, error) {
+	key := getProvisioningSemaphoreKey(subscriptionID)
+	pipeline := r.redisClient.TxPipeline()
+	pipeline.ZRem(key, instanceID)
+	countCmd := pipeline.ZCount(key, r.getMinLiveScore(), "+inf")
+	if _, err := pipeline.Exec(); err != nil {
+		return 0, fmt.Errorf(
+			`error releasing provisioning slot in subscription "%s": %s`,
+			subscriptionID,
+			err,
+		)
+	}
+	return countCmd.Val(), nil
+}
+
+// count doesn't reap expired slots, which acquire does, but doesn't count them
+// either
+func (r *redisProvisioningSemaphore) count(
+	subscriptionID string,
+) (int64, error) {
+	count, err := r.redisClient.ZCount(
+		getProvisioningSemaphoreKey(subscriptionID),
+		r.getMinLiveScore(),
+		"+inf",
+	).Result()
+	if err != nil && err != redis.Nil {
+		return 0, fmt.Errorf(
+			`error counting provisioning slots in subscription "%s": %s`,
+			subscriptionID,
+			err,
+		)
+	}
+	return count, nil
+}
+
+// getMinLiveScore returns, as an exclusive ZCOUNT bound, the score below which
+// a slot's lease has expired
+func (r *redisProvisioningSemaphore) getMinLiveScore() string {
+	minLive := getUnixMillis(r.now().Add(-r.ttl))
+	return "(" + strconv.FormatInt(minLive, 10)
+}
+
+// getProvisioningSemaphoreKey returns the key of the sorted set of a
+// subscription's slot holders. It differs from that of the plain set slots were
+// once held in, which Redis wouldn't let sorted set commands operate on.
+func getProvisioningSemaphoreKey(subscriptionID string) string {
+	return fmt.Sprintf("provisioning-slots:%s", subscriptionID)
+}
+
+func getUnixMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+// provisioningSlotCounter reports how many provisioning slots are held in each
+// subscription whose concurrent provisions are capped
+type provisioningSlotCounter struct {
+	subscriptionIDs []string
+	semaphore       provisioningSemaphore
+}
+
+// newProvisioningSlotCounter returns a provisioningSlotCounter for those of
+// the given subscriptions that are capped by the given limits. It returns
+// false if none are.
+func newProvisioningSlotCounter(
+	limits ProvisioningLimits,
+	subscriptionIDs []string,
+	semaphore provisioningSemaphore,
+) (*provisioningSlotCounter, bool) {
+	capped := map[string]bool{}
+	for _, subscriptionID := range append(
+		[]string{limits.AzureSubscriptionID},
+		subscriptionIDs...,
+	) {
+		if limits.getMaxConcurrentProvisions(subscriptionID) > 0 {
+			capped[subscriptionID] = true
+		}
+	}
+	// Subscriptions with caps of their own are counted even if no instance is
+	// currently routed to them, since instances may still request them
+	for subscriptionID, max := range limits.PerSubscriptionMax {
+		if max > 0 {
+			capped[subscriptionID] = true
+		}
+	}
+	if len(capped) == 0 {
+		return nil, false
+	}
+	counter := &provisioningSlotCounter{
+		subscriptionIDs: make([]string, 0, len(capped)),
+		semaphore:       semaphore,
+	}
+	for subscriptionID := range capped {
+		counter.subscriptionIDs = append(counter.subscriptionIDs, subscriptionID)
+	}
+	sort.Strings(counter.subscriptionIDs)
+	return counter, true
+}
+
+func (p *provisioningSlotCounter) GetInFlightProvisions() (
+	map[string]int64,
+	error,
+) {
+	counts := make(map[string]int64, len(p.subscriptionIDs))
+	for _, subscriptionID := range p.subscriptionIDs {
+		count, err := p.semaphore.count(subscriptionID)
+		if err != nil {
+			return nil, err
+		}
+		counts[subscriptionID] = count
+	}
+	return counts, nil
+}
@@ -0,0 +1,318 @@
+package broker
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	fakeAsync "github.com/Azure/open-service-broker-azure/pkg/async/fake"
+	"github.com/Azure/open-service-broker-azure/pkg/crypto/noop"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	memoryStorage "github.com/Azure/open-service-broker-azure/pkg/storage/memory"
+	"github.com/go-redis/redis"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryProvisioningSemaphore is an in-memory provisioningSemaphore. It is
+// only suitable for use by a single broker process, and its slots are held
+// until they're released.
+type memoryProvisioningSemaphore struct {
+	holders map[string]map[string]struct{}
+	mutex   sync.Mutex
+}
+
+func newMemoryProvisioningSemaphore() *memoryProvisioningSemaphore {
+	return &memoryProvisioningSemaphore{
+		holders: map[string]map[string]struct{}{},
+	}
+}
+
+func (m *memoryProvisioningSemaphore) acquire(
+	subscriptionID string,
+	instanceID string,
+	max int,
+) (bool, int64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	holders, ok := m.holders[subscriptionID]
+	if !ok {
+		holders = map[string]struct{}{}
+		m.holders[subscriptionID] = holders
+	}
+	if _, ok := holders[instanceID]; ok {
+		return true, int64(len(holders)), nil
+	}
+	if max > 0 && len(holders) >= max {
+		return false, int64(len(holders)), nil
+	}
+	holders[instanceID] = struct{}{}
+	return true, int64(len(holders)), nil
+}
+
+func (m *memoryProvisioningSemaphore) renew(string, string) error {
+	return nil
+}
+
+func (m *memoryProvisioningSemaphore) release(
+	subscriptionID string,
+	instanceID string,
+) (int64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	holders := m.holders[subscriptionID]
+	delete(holders, instanceID)
+	return int64(len(holders)), nil
+}
+
+func (m *memoryProvisioningSemaphore) count(
+	subscriptionID string,
+) (int64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return int64(len(m.holders[subscriptionID])), nil
+}
+
+func TestGetMaxConcurrentProvisions(t *testing.T) {
+	limits := ProvisioningLimits{
+		MaxConcurrentProvisions: 5,
+		PerSubscriptionMax: map[string]int{
+			"sub-a": 2,
+		},
+	}
+	assert.Equal(t, 2, limits.getMaxConcurrentProvisions("sub-a"))
+	assert.Equal(t, 5, limits.getMaxConcurrentProvisions("sub-b"))
+}
+
+func TestProvisioningSlotCounterWithoutCaps(t *testing.T) {
+	_, ok := newProvisioningSlotCounter(
+		ProvisioningLimits{
+			AzureSubscriptionID: "sub-a",
+		},
+		[]string{"sub-b"},
+		newMemoryProvisioningSemaphore(),
+	)
+	assert.False(t, ok)
+}
+
+func TestProvisioningSlotCounterCountsCappedSubscriptions(t *testing.T) {
+	semaphore := newMemoryProvisioningSemaphore()
+	counter, ok := newProvisioningSlotCounter(
+		ProvisioningLimits{
+			AzureSubscriptionID: "sub-a",
+			PerSubscriptionMax: map[string]int{
+				"sub-a": 2,
+				"sub-c": 1,
+			},
+		},
+		[]string{"sub-b"},
+		semaphore,
+	)
+	assert.True(t, ok)
+	_, _, err := semaphore.acquire("sub-a", "instance-1", 2)
+	assert.Nil(t, err)
+	_, _, err = semaphore.acquire("sub-a", "instance-2", 2)
+	assert.Nil(t, err)
+	counts, err := counter.GetInFlightProvisions()
+	assert.Nil(t, err)
+	// sub-b has no cap, so its provisions aren't counted
+	assert.Equal(t, map[string]int64{"sub-a": 2, "sub-c": 0}, counts)
+}
+
+func TestProvisioningWaitsForSlot(t *testing.T) {
+	fakeModule, err := fake.New()
+	assert.Nil(t, err)
+	catalog, err := fakeModule.GetCatalog()
+	assert.Nil(t, err)
+	semaphore := newMemoryProvisioningSemaphore()
+	b := &broker{
+		store:       memoryStorage.NewStore(catalog, noop.NewCodec()),
+		asyncEngine: fakeAsync.NewEngine(),
+		catalog:     catalog,
+		provisioningLimits: ProvisioningLimits{
+			AzureSubscriptionID:     "sub",
+			MaxConcurrentProvisions: 1,
+		},
+		provisioningSemaphore: semaphore,
+	}
+	svc, ok := catalog.GetService(fake.ServiceID)
+	assert.True(t, ok)
+	plan, ok := svc.GetPlan(fake.StandardPlanID)
+	assert.True(t, ok)
+	instance := service.Instance{
+		InstanceID:             "instance",
+		ServiceID:              fake.ServiceID,
+		Service:                svc,
+		PlanID:                 fake.StandardPlanID,
+		Plan:                   plan,
+		Status:                 service.InstanceStateProvisioning,
+		ProvisioningParameters: &fake.ProvisioningParameters{},
+		UpdatingParameters:     &fake.UpdatingParameters{},
+		Details:                &fake.InstanceDetails{},
+	}
+	assert.Nil(t, b.store.WriteInstance(instance))
+
+	// Another instance holds the only slot
+	acquired, _, err := semaphore.acquire("sub", "other-instance", 1)
+	assert.Nil(t, err)
+	assert.True(t, acquired)
+
+	args := map[string]string{
+		"stepName":   "run",
+		"instanceID": instance.InstanceID,
+	}
+	followUpTasks, err := b.executeProvisioningStep(
+		context.Background(),
+		async.NewTask("executeProvisioningStep", args),
+	)
+	assert.Nil(t, err)
+	instance, ok, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, service.InstanceStateProvisioning, instance.Status)
+	assert.Len(t, followUpTasks, 1)
+	assert.NotNil(t, followUpTasks[0].GetExecuteTime())
+	assert.Equal(t, args, followUpTasks[0].GetArgs())
+
+	// Once the slot is freed up, provisioning proceeds and, upon completion,
+	// releases the slot again
+	count, err := semaphore.release("sub", "other-instance")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+	followUpTasks, err = b.executeProvisioningStep(
+		context.Background(),
+		async.NewTask("executeProvisioningStep", args),
+	)
+	assert.Nil(t, err)
+	instance, ok, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, service.InstanceStateProvisioned, instance.Status)
+	assert.Empty(t, followUpTasks)
+	assert.Empty(t, semaphore.holders["sub"])
+}
+
+func TestProvisioningSlotsAreHeldInInstancesSubscriptions(t *testing.T) {
+	semaphore := newMemoryProvisioningSemaphore()
+	b := &broker{
+		provisioningLimits: ProvisioningLimits{
+			AzureSubscriptionID:     "sub",
+			MaxConcurrentProvisions: 1,
+		},
+		provisioningSemaphore: semaphore,
+	}
+	// The broker's own subscription is at its cap
+	acquired, err := b.acquireProvisioningSlot(
+		service.Instance{InstanceID: "instance-a"},
+	)
+	assert.Nil(t, err)
+	assert.True(t, acquired)
+	// But an instance routed to another subscription is held to that
+	// subscription's cap
+	routed := service.Instance{InstanceID: "instance-b", SubscriptionID: "sub-b"}
+	acquired, err = b.acquireProvisioningSlot(routed)
+	assert.Nil(t, err)
+	assert.True(t, acquired)
+	assert.Contains(t, semaphore.holders["sub-b"], "instance-b")
+	b.releaseProvisioningSlot(routed)
+	assert.Empty(t, semaphore.holders["sub-b"])
+	assert.Contains(t, semaphore.holders["sub"], "instance-a")
+}
+
+func TestRedisProvisioningSemaphoreReapsExpiredSlots(t *testing.T) {
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: "redis:6379",
+	})
+	semaphore := newRedisProvisioningSemaphore(
+		redisClient,
+		time.Minute,
+	).(*redisProvisioningSemaphore)
+	now := time.Now()
+	semaphore.now = func() time.Time {
+		return now
+	}
+	subscriptionID := uuid.NewV4().String()
+	acquired, count, err := semaphore.acquire(subscriptionID, "instance-a", 1)
+	assert.Nil(t, err)
+	assert.True(t, acquired)
+	assert.Equal(t, int64(1), count)
+
+	// A renewed slot is still held after its original lease would have expired
+	now = now.Add(45 * time.Second)
+	assert.Nil(t, semaphore.renew(subscriptionID, "instance-a"))
+	now = now.Add(45 * time.Second)
+	acquired, count, err = semaphore.acquire(subscriptionID, "instance-b", 1)
+	assert.Nil(t, err)
+	assert.False(t, acquired)
+	assert.Equal(t, int64(1), count)
+
+	// But once its lease expires, it is no longer counted and is reaped by the
+	// next attempt to acquire a slot
+	now = now.Add(time.Minute)
+	count, err = semaphore.count(subscriptionID)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+	acquired, count, err = semaphore.acquire(subscriptionID, "instance-b", 1)
+	assert.Nil(t, err)
+	assert.True(t, acquired)
+	assert.Equal(t, int64(1), count)
+
+	// Renewing a slot that was reaped doesn't take it back
+	assert.Nil(t, semaphore.renew(subscriptionID, "instance-a"))
+	count, err = semaphore.release(subscriptionID, "instance-b")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestDeprovisioningReleasesProvisioningSlot(t *testing.T) {
+	fakeModule, err := fake.New()
+	assert.Nil(t, err)
+	catalog, err := fakeModule.GetCatalog()
+	assert.Nil(t, err)
+	semaphore := newMemoryProvisioningSemaphore()
+	b := &broker{
+		store:       memoryStorage.NewStore(catalog, noop.NewCodec()),
+		asyncEngine: fakeAsync.NewEngine(),
+		catalog:     catalog,
+		provisioningLimits: ProvisioningLimits{
+			AzureSubscriptionID:     "sub",
+			MaxConcurrentProvisions: 1,
+		},
+		provisioningSemaphore: semaphore,
+	}
+	svc, ok := catalog.GetService(fake.ServiceID)
+	assert.True(t, ok)
+	plan, ok := svc.GetPlan(fake.StandardPlanID)
+	assert.True(t, ok)
+	instance := service.Instance{
+		InstanceID:             "instance",
+		ServiceID:              fake.ServiceID,
+		Service:                svc,
+		PlanID:                 fake.StandardPlanID,
+		Plan:                   plan,
+		Status:                 service.InstanceStateDeprovisioning,
+		ProvisioningParameters: &fake.ProvisioningParameters{},
+		UpdatingParameters:     &fake.UpdatingParameters{},
+		Details:                &fake.InstanceDetails{},
+	}
+	assert.Nil(t, b.store.WriteInstance(instance))
+	// The instance is deprovisioned before it finished provisioning
+	acquired, err := b.acquireProvisioningSlot(instance)
+	assert.Nil(t, err)
+	assert.True(t, acquired)
+	_, err = b.executeDeprovisioningStep(
+		context.Background(),
+		async.NewTask(
+			"executeDeprovisioningStep",
+			map[string]string{
+				"stepName":   "run",
+				"instanceID": instance.InstanceID,
+			},
+		),
+	)
+	assert.Nil(t, err)
+	assert.Empty(t, semaphore.holders["sub"])
+}
@@ -0,0 +1,49 @@
+package broker
+
+import (
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	log "github.com/Sirupsen/logrus"
+)
+
+// withProvisioningProgress returns a copy of the given instance that records
+// that the named step of the given provisioner has just begun executing. An
+// empty step name records that every step has been executed.
+func withProvisioningProgress(
+	instance service.Instance,
+	provisioner service.Provisioner,
+	stepName string,
+) service.Instance {
+	instance.ProvisioningProgress = &service.ProvisioningProgress{
+		Steps:              provisioner.GetStepNames(),
+		CurrentStep:        stepName,
+		CurrentStepStarted: time.Now(),
+	}
+	return instance
+}
+
+// recordProvisioningStepDuration counts how long the named step took to
+// provision the given instance towards the average for the instance's plan,
+// from which the time remaining for later provisioning operations is
+// estimated. Failure to do so is logged, but is not treated as a failure of
+// the provisioning operation itself.
+func (b *broker) recordProvisioningStepDuration(
+	instance service.Instance,
+	stepName string,
+	duration time.Duration,
+) {
+	if err := b.store.RecordProvisioningStepDuration(
+		instance.PlanID,
+		stepName,
+		duration,
+	); err != nil {
+		log.WithFields(log.Fields{
+			"instanceID": instance.InstanceID,
+			"planID":     instance.PlanID,
+			"step":       stepName,
+			"duration":   duration,
+			"error":      err,
+		}).Error("error recording provisioning step duration")
+	}
+}
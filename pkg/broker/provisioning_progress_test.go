@@ -0,0 +1,47 @@
+package broker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProvisioningStepRecordsProgressAndDuration(t *testing.T) {
+	b, fakeModule, instance := getConnectivityValidationTestBroker(t)
+	fakeModule.ServiceManager.ProvisionBehavior = func(
+		_ context.Context,
+		instance service.Instance,
+	) (service.InstanceDetails, error) {
+		return instance.Details, nil
+	}
+	_, err := b.executeProvisioningStep(
+		context.Background(),
+		getFailureGraceTestTask(instance),
+	)
+	assert.Nil(t, err)
+	durations, err := b.store.GetProvisioningStepDurations(fake.StandardPlanID)
+	assert.Nil(t, err)
+	_, ok := durations["run"]
+	assert.True(t, ok)
+	// Connectivity validation is all that remains
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.NotNil(t, instance.ProvisioningProgress)
+	assert.Equal(t, []string{"run"}, instance.ProvisioningProgress.Steps)
+	assert.Empty(t, instance.ProvisioningProgress.CurrentStep)
+}
+
+func TestFailedProvisioningStepRecordsNoDuration(t *testing.T) {
+	b, _, instance := getFailureGraceTestBroker(t, 0)
+	_, err := b.executeProvisioningStep(
+		context.Background(),
+		getFailureGraceTestTask(instance),
+	)
+	assert.NotNil(t, err)
+	durations, err := b.store.GetProvisioningStepDurations(fake.StandardPlanID)
+	assert.Nil(t, err)
+	assert.Empty(t, durations)
+}
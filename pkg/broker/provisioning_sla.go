@@ -0,0 +1,92 @@
+package broker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	log "github.com/Sirupsen/logrus"
+)
+
+// validateProvisioningSLA checks that every plan that has a provisioning SLA
+// target of its own is known
+func validateProvisioningSLA(
+	services []service.Service,
+	sla service.ProvisioningSLA,
+) error {
+	knownPlanKeys := map[string]bool{}
+	for _, svc := range services {
+		for _, plan := range svc.GetPlans() {
+			knownPlanKeys[service.GetPlanKey(svc, plan)] = true
+		}
+	}
+	for _, planKey := range sla.GetPlanKeys() {
+		if !knownPlanKeys[planKey] {
+			return fmt.Errorf(
+				`provisioning SLA target names unknown plan "%s"; plans are `+
+					"identified as serviceName/planName",
+				planKey,
+			)
+		}
+	}
+	return nil
+}
+
+// withProvisioningTiming returns a copy of the given instance, which has just
+// finished provisioning, that records how long provisioning took and whether
+// that breached the provisioning SLA target for the instance's plan. Progress
+// through the provisioning steps is no longer of interest and is forgotten.
+func (b *broker) withProvisioningTiming(
+	instance service.Instance,
+) service.Instance {
+	completed := time.Now()
+	timing := &service.ProvisioningTiming{
+		Started:   instance.Created,
+		Completed: completed,
+		Duration:  completed.Sub(instance.Created),
+	}
+	if instance.Service != nil && instance.Plan != nil {
+		if target, ok := b.provisioningSLA.GetTarget(
+			instance.Service,
+			instance.Plan,
+		); ok {
+			timing.Target = target
+			timing.BreachedSLA = timing.Duration > target
+		}
+	}
+	instance.ProvisioningTiming = timing
+	instance.ProvisioningProgress = nil
+	return instance
+}
+
+// recordProvisioningSLAOutcome counts the given instance, which has just
+// finished provisioning, towards its plan's provisioning SLA attainment.
+// Instances whose plans have no target aren't counted. Failure to count an
+// instance is logged, but is not treated as a failure of the provisioning
+// operation itself.
+func (b *broker) recordProvisioningSLAOutcome(instance service.Instance) {
+	timing := instance.ProvisioningTiming
+	if timing == nil || timing.Target == 0 {
+		return
+	}
+	logFields := log.Fields{
+		"instanceID": instance.InstanceID,
+		"planID":     instance.PlanID,
+		"duration":   timing.Duration,
+		"target":     timing.Target,
+	}
+	if timing.BreachedSLA {
+		log.WithFields(logFields).Warn(
+			"instance took longer to provision than its provisioning SLA target",
+		)
+	}
+	if err := b.store.RecordProvisioningSLAOutcome(
+		instance.PlanID,
+		timing.BreachedSLA,
+	); err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error(
+			"error recording provisioning SLA outcome",
+		)
+	}
+}
@@ -0,0 +1,118 @@
+package broker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	fakeAsync "github.com/Azure/open-service-broker-azure/pkg/async/fake"
+	"github.com/Azure/open-service-broker-azure/pkg/crypto/noop"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	memoryStorage "github.com/Azure/open-service-broker-azure/pkg/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateProvisioningSLA(t *testing.T) {
+	fakeModule, err := fake.New()
+	assert.Nil(t, err)
+	catalog, err := fakeModule.GetCatalog()
+	assert.Nil(t, err)
+	sla, err := service.NewProvisioningSLA(
+		0,
+		map[string]time.Duration{"fake/standard": time.Minute},
+	)
+	assert.Nil(t, err)
+	assert.Nil(t, validateProvisioningSLA(catalog.GetServices(), sla))
+	sla, err = service.NewProvisioningSLA(
+		0,
+		map[string]time.Duration{"fake/bogus": time.Minute},
+	)
+	assert.Nil(t, err)
+	assert.NotNil(t, validateProvisioningSLA(catalog.GetServices(), sla))
+}
+
+func TestProvisioningRecordsSLAOutcome(t *testing.T) {
+	testCases := []struct {
+		name             string
+		target           time.Duration
+		expectedBreached bool
+	}{
+		{
+			name:             "within target",
+			target:           time.Hour,
+			expectedBreached: false,
+		},
+		{
+			name:             "target breached",
+			target:           time.Minute,
+			expectedBreached: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			fakeModule, err := fake.New()
+			assert.Nil(t, err)
+			catalog, err := fakeModule.GetCatalog()
+			assert.Nil(t, err)
+			sla, err := service.NewProvisioningSLA(testCase.target, nil)
+			assert.Nil(t, err)
+			b := &broker{
+				store:           memoryStorage.NewStore(catalog, noop.NewCodec()),
+				asyncEngine:     fakeAsync.NewEngine(),
+				catalog:         catalog,
+				provisioningSLA: sla,
+			}
+			svc, ok := catalog.GetService(fake.ServiceID)
+			assert.True(t, ok)
+			plan, ok := svc.GetPlan(fake.StandardPlanID)
+			assert.True(t, ok)
+			instance := service.Instance{
+				InstanceID:             "instance",
+				ServiceID:              fake.ServiceID,
+				Service:                svc,
+				PlanID:                 fake.StandardPlanID,
+				Plan:                   plan,
+				Status:                 service.InstanceStateProvisioning,
+				ProvisioningParameters: &fake.ProvisioningParameters{},
+				UpdatingParameters:     &fake.UpdatingParameters{},
+				Details:                &fake.InstanceDetails{},
+				Created:                time.Now().Add(-10 * time.Minute),
+			}
+			assert.Nil(t, b.store.WriteInstance(instance))
+
+			_, err = b.executeProvisioningStep(
+				context.Background(),
+				async.NewTask(
+					"executeProvisioningStep",
+					map[string]string{
+						"stepName":   "run",
+						"instanceID": instance.InstanceID,
+					},
+				),
+			)
+			assert.Nil(t, err)
+			instance, ok, err = b.store.GetInstance(instance.InstanceID)
+			assert.Nil(t, err)
+			assert.True(t, ok)
+			assert.Equal(t, service.InstanceStateProvisioned, instance.Status)
+			timing := instance.ProvisioningTiming
+			assert.NotNil(t, timing)
+			assert.Equal(t, testCase.target, timing.Target)
+			assert.Equal(t, testCase.expectedBreached, timing.BreachedSLA)
+			assert.True(t, timing.Duration >= 10*time.Minute)
+
+			outcomes, err := b.store.GetProvisioningSLAOutcomes()
+			assert.Nil(t, err)
+			planOutcomes := outcomes[fake.StandardPlanID]
+			if testCase.expectedBreached {
+				assert.Equal(t, int64(1), planOutcomes.Breached)
+				assert.Equal(t, int64(0), planOutcomes.WithinTarget)
+			} else {
+				assert.Equal(t, int64(0), planOutcomes.Breached)
+				assert.Equal(t, int64(1), planOutcomes.WithinTarget)
+			}
+		})
+	}
+}
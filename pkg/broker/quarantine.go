@@ -0,0 +1,363 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	log "github.com/Sirupsen/logrus"
+)
+
+const (
+	quarantineInstanceJobName = "quarantineInstance"
+	releaseInstanceJobName    = "releaseInstance"
+)
+
+// quarantineInstance isolates an instance at an operator's request. Network
+// access to the instance's underlying resources is denied first, since that
+// contains an incident most quickly, then each of its bindings has its GitOps
+// manifest deleted and is unbound and marked as revoked. Bindings revoked by
+// an earlier, failed attempt are left as they are.
+func (b *broker) quarantineInstance(
+	ctx context.Context,
+	task async.Task,
+) ([]async.Task, error) {
+	args := task.GetArgs()
+	instanceID, ok := args["instanceID"]
+	if !ok {
+		return nil, errors.New(`missing required argument "instanceID"`)
+	}
+	instance, instanceCopy, quarantiner, err := b.getQuarantinableInstance(
+		instanceID,
+		"quarantine",
+		service.InstanceStateQuarantiningFailed,
+	)
+	if err != nil {
+		return nil, err
+	}
+	logFields := log.Fields{
+		"instanceID": instanceID,
+	}
+	log.WithFields(logFields).Debug("quarantining instance")
+	details, err := quarantiner.Quarantine(ctx, instance)
+	if err != nil {
+		if retryTask, ok := b.getQuarantineRetryTask(task, err); ok {
+			return []async.Task{retryTask}, nil
+		}
+		return nil, b.handleQuarantineError(
+			instance,
+			"quarantine",
+			service.InstanceStateQuarantiningFailed,
+			err,
+			"error denying network access",
+		)
+	}
+	// Bindings are revoked using the instance's updated details
+	instance.Details = details
+	instanceCopy.Details = details
+	if instanceCopy.Quarantine == nil {
+		instanceCopy.Quarantine = &service.QuarantineState{}
+	}
+	bindingIDs, err := b.store.GetBindingIDs(instanceID)
+	if err != nil {
+		return nil, b.handleQuarantineError(
+			instanceCopy,
+			"quarantine",
+			service.InstanceStateQuarantiningFailed,
+			err,
+			"error listing bindings",
+		)
+	}
+	serviceManager := instance.Service.GetServiceManager()
+	for _, bindingID := range bindingIDs {
+		binding, ok, err := b.store.GetBinding(bindingID)
+		if err != nil {
+			return nil, b.handleQuarantineError(
+				instanceCopy,
+				"quarantine",
+				service.InstanceStateQuarantiningFailed,
+				err,
+				fmt.Sprintf(`error loading persisted binding "%s"`, bindingID),
+			)
+		}
+		if !ok || binding.Status == service.BindingStateRevoked {
+			continue
+		}
+		// As when unbinding, the manifest is removed first so that platforms
+		// watching the GitOps repository stop handing out the binding's
+		// credentials before they're revoked
+		if binding.GitOpsManifest != nil {
+			if b.manifestWriter == nil {
+				log.WithFields(log.Fields{
+					"instanceID":   instanceID,
+					"bindingID":    bindingID,
+					"manifestPath": binding.GitOpsManifest.Path,
+				}).Warn(
+					"GitOps manifests are no longer written; the revoked binding's " +
+						"manifest must be removed by hand",
+				)
+			} else if err := b.manifestWriter.DeleteManifest(
+				*binding.GitOpsManifest,
+			); err != nil {
+				return nil, b.handleQuarantineError(
+					instanceCopy,
+					"quarantine",
+					service.InstanceStateQuarantiningFailed,
+					err,
+					fmt.Sprintf(
+						`error deleting GitOps manifest of binding "%s"`,
+						bindingID,
+					),
+				)
+			}
+		}
+		if err := serviceManager.Unbind(instance, binding.Details); err != nil {
+			return nil, b.handleQuarantineError(
+				instanceCopy,
+				"quarantine",
+				service.InstanceStateQuarantiningFailed,
+				err,
+				fmt.Sprintf(`error revoking binding "%s"`, bindingID),
+			)
+		}
+		binding.Status = service.BindingStateRevoked
+		binding.StatusReason = "credentials revoked when instance was quarantined"
+		binding.GitOpsManifest = nil
+		if err := b.store.WriteBinding(binding); err != nil {
+			return nil, b.handleQuarantineError(
+				instanceCopy,
+				"quarantine",
+				service.InstanceStateQuarantiningFailed,
+				err,
+				fmt.Sprintf(`error persisting revoked binding "%s"`, bindingID),
+			)
+		}
+		instanceCopy.Quarantine.RevokedBindingIDs = append(
+			instanceCopy.Quarantine.RevokedBindingIDs,
+			bindingID,
+		)
+	}
+	now := time.Now().UTC()
+	instanceCopy.Quarantine.QuarantinedAt = &now
+	instanceCopy.Quarantine.ReleasedAt = nil
+	instanceCopy.Status = service.InstanceStateQuarantined
+	instanceCopy.StatusReason = ""
+	if err := b.store.WriteInstance(instanceCopy); err != nil {
+		return nil, b.handleQuarantineError(
+			instanceCopy,
+			"quarantine",
+			service.InstanceStateQuarantiningFailed,
+			err,
+			"error persisting instance",
+		)
+	}
+	logFields["revokedBindings"] = len(instanceCopy.Quarantine.RevokedBindingIDs)
+	log.WithFields(logFields).Info("quarantined instance")
+	return nil, nil
+}
+
+// releaseInstance restores the network access that was denied to a
+// quarantined instance's underlying resources. The instance's revoked bindings
+// remain revoked; they must be unbound and bound again.
+func (b *broker) releaseInstance(
+	ctx context.Context,
+	task async.Task,
+) ([]async.Task, error) {
+	args := task.GetArgs()
+	instanceID, ok := args["instanceID"]
+	if !ok {
+		return nil, errors.New(`missing required argument "instanceID"`)
+	}
+	instance, instanceCopy, quarantiner, err := b.getQuarantinableInstance(
+		instanceID,
+		"release",
+		service.InstanceStateReleasingFailed,
+	)
+	if err != nil {
+		return nil, err
+	}
+	logFields := log.Fields{
+		"instanceID": instanceID,
+	}
+	log.WithFields(logFields).Debug("releasing quarantined instance")
+	details, err := quarantiner.Release(ctx, instance)
+	if err != nil {
+		if retryTask, ok := b.getQuarantineRetryTask(task, err); ok {
+			return []async.Task{retryTask}, nil
+		}
+		return nil, b.handleQuarantineError(
+			instance,
+			"release",
+			service.InstanceStateReleasingFailed,
+			err,
+			"error restoring network access",
+		)
+	}
+	now := time.Now().UTC()
+	instanceCopy.Details = details
+	if instanceCopy.Quarantine == nil {
+		instanceCopy.Quarantine = &service.QuarantineState{}
+	}
+	instanceCopy.Quarantine.ReleasedAt = &now
+	instanceCopy.Status = service.InstanceStateProvisioned
+	instanceCopy.StatusReason = ""
+	if err := b.store.WriteInstance(instanceCopy); err != nil {
+		return nil, b.handleQuarantineError(
+			instanceCopy,
+			"release",
+			service.InstanceStateReleasingFailed,
+			err,
+			"error persisting instance",
+		)
+	}
+	log.WithFields(logFields).Info("released quarantined instance")
+	return nil, nil
+}
+
+// getQuarantinableInstance loads the specified instance, a second, untouched
+// copy of it to write back to storage (see executeProvisioningStep for why),
+// and the Quarantiner implemented by its service. Any failure is recorded,
+// using the given failed status, before it is returned.
+func (b *broker) getQuarantinableInstance(
+	instanceID string,
+	operation string,
+	failedStatus string,
+) (service.Instance, service.Instance, service.Quarantiner, error) {
+	instance, ok, err := b.store.GetInstance(instanceID)
+	if err != nil {
+		return instance, instance, nil, b.handleQuarantineError(
+			instanceID,
+			operation,
+			failedStatus,
+			err,
+			"error loading persisted instance",
+		)
+	}
+	if !ok {
+		return instance, instance, nil, b.handleQuarantineError(
+			instanceID,
+			operation,
+			failedStatus,
+			nil,
+			"instance does not exist in the data store",
+		)
+	}
+	instanceCopy, _, err := b.store.GetInstance(instanceID)
+	if err != nil {
+		return instance, instance, nil, b.handleQuarantineError(
+			instanceID,
+			operation,
+			failedStatus,
+			err,
+			"error loading persisted instance",
+		)
+	}
+	quarantiner, ok := instance.Service.GetServiceManager().(service.Quarantiner)
+	if !ok {
+		return instance, instanceCopy, nil, b.handleQuarantineError(
+			instance,
+			operation,
+			failedStatus,
+			nil,
+			fmt.Sprintf(
+				`service "%s" does not support quarantine`,
+				instance.ServiceID,
+			),
+		)
+	}
+	return instance, instanceCopy, quarantiner, nil
+}
+
+// getQuarantineRetryTask returns a task that retries the given quarantine or
+// release task after a delay, if the retry policy permits it for the given
+// error
+func (b *broker) getQuarantineRetryTask(
+	task async.Task,
+	err error,
+) (async.Task, bool) {
+	args := task.GetArgs()
+	retryCount, _ := strconv.Atoi(args["retryCount"])
+	delay, ok := b.retryPolicy.getRetryDelay(err, retryCount)
+	if !ok {
+		return nil, false
+	}
+	log.WithFields(log.Fields{
+		"job":           task.GetJobName(),
+		"instanceID":    args["instanceID"],
+		"errorCategory": service.GetErrorCategory(err),
+		"retryCount":    retryCount + 1,
+		"retryDelay":    delay,
+		"error":         err,
+	}).Warn("quarantine task failed; retrying")
+	return async.NewDelayedTask(
+		task.GetJobName(),
+		map[string]string{
+			"instanceID": args["instanceID"],
+			"retryCount": strconv.Itoa(retryCount + 1),
+		},
+		delay,
+	), true
+}
+
+// handleQuarantineError records the failure of an instance's quarantine or
+// release, as the given failed status, and returns an error describing it.
+// Failures to persist the instance are fatal, as they are for other
+// operations.
+func (b *broker) handleQuarantineError(
+	instanceOrInstanceID interface{},
+	operation string,
+	failedStatus string,
+	e error,
+	msg string,
+) error {
+	instance, ok := instanceOrInstanceID.(service.Instance)
+	if !ok {
+		if e == nil {
+			return fmt.Errorf(
+				`error executing %s of instance "%s": %s`,
+				operation,
+				instanceOrInstanceID,
+				msg,
+			)
+		}
+		return fmt.Errorf(
+			`error executing %s of instance "%s": %s: %s`,
+			operation,
+			instanceOrInstanceID,
+			msg,
+			e,
+		)
+	}
+	var ret error
+	if e == nil {
+		ret = fmt.Errorf(
+			`error executing %s of instance "%s": %s`,
+			operation,
+			instance.InstanceID,
+			msg,
+		)
+	} else {
+		ret = fmt.Errorf(
+			`error executing %s of instance "%s": %s: %s`,
+			operation,
+			instance.InstanceID,
+			msg,
+			e,
+		)
+	}
+	instance.Status = failedStatus
+	instance.StatusReason = ret.Error()
+	if err := b.store.WriteInstance(instance); err != nil {
+		log.WithFields(log.Fields{
+			"instanceID":       instance.InstanceID,
+			"status":           instance.Status,
+			"originalError":    ret,
+			"persistenceError": err,
+		}).Fatal("error persisting instance with updated status")
+	}
+	return ret
+}
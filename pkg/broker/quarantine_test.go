@@ -0,0 +1,163 @@
+package broker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuarantineInstanceRevokesBindings(t *testing.T) {
+	b, serviceManager, instance := getQuarantineTestBroker(t)
+	var quarantined bool
+	serviceManager.QuarantineBehavior = func(
+		_ context.Context,
+		instance service.Instance,
+	) (service.InstanceDetails, error) {
+		quarantined = true
+		return instance.Details, nil
+	}
+	var unbound int
+	serviceManager.UnbindBehavior = func(
+		service.Instance,
+		service.BindingDetails,
+	) error {
+		unbound++
+		return nil
+	}
+	for _, binding := range []service.Binding{
+		{
+			BindingID: "active",
+			Status:    service.BindingStateBound,
+		},
+		{
+			BindingID: "revoked",
+			Status:    service.BindingStateRevoked,
+		},
+	} {
+		binding.InstanceID = instance.InstanceID
+		binding.ServiceID = instance.ServiceID
+		assert.Nil(t, b.store.WriteBinding(binding))
+	}
+	followUpTasks, err := b.quarantineInstance(
+		context.Background(),
+		getQuarantineTestTask("quarantineInstance", instance),
+	)
+	assert.Nil(t, err)
+	assert.Empty(t, followUpTasks)
+	assert.True(t, quarantined)
+	assert.Equal(t, 1, unbound)
+	binding, _, err := b.store.GetBinding("active")
+	assert.Nil(t, err)
+	assert.Equal(t, service.BindingStateRevoked, binding.Status)
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Equal(t, service.InstanceStateQuarantined, instance.Status)
+	assert.Equal(t, []string{"active"}, instance.Quarantine.RevokedBindingIDs)
+	assert.NotNil(t, instance.Quarantine.QuarantinedAt)
+}
+
+func TestQuarantineInstanceDeletesGitOpsManifests(t *testing.T) {
+	b, _, instance := getQuarantineTestBroker(t)
+	manifest := service.GitOpsManifest{
+		Path:     instance.InstanceID + "/active.yaml",
+		Revision: "abc",
+	}
+	manifestWriter := &memoryManifestWriter{
+		credentials: map[string]service.Credentials{
+			manifest.Path: fake.Credentials{},
+		},
+	}
+	b.manifestWriter = manifestWriter
+	assert.Nil(t, b.store.WriteBinding(service.Binding{
+		BindingID:      "active",
+		InstanceID:     instance.InstanceID,
+		ServiceID:      instance.ServiceID,
+		Status:         service.BindingStateBound,
+		GitOpsManifest: &manifest,
+	}))
+	_, err := b.quarantineInstance(
+		context.Background(),
+		getQuarantineTestTask("quarantineInstance", instance),
+	)
+	assert.Nil(t, err)
+	assert.Empty(t, manifestWriter.credentials)
+	binding, _, err := b.store.GetBinding("active")
+	assert.Nil(t, err)
+	assert.Equal(t, service.BindingStateRevoked, binding.Status)
+	assert.Nil(t, binding.GitOpsManifest)
+}
+
+func TestQuarantineInstanceRecordsFailure(t *testing.T) {
+	b, serviceManager, instance := getQuarantineTestBroker(t)
+	serviceManager.QuarantineBehavior = func(
+		context.Context,
+		service.Instance,
+	) (service.InstanceDetails, error) {
+		return nil, assert.AnError
+	}
+	_, err := b.quarantineInstance(
+		context.Background(),
+		getQuarantineTestTask("quarantineInstance", instance),
+	)
+	assert.NotNil(t, err)
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Equal(t, service.InstanceStateQuarantiningFailed, instance.Status)
+	assert.NotEmpty(t, instance.StatusReason)
+}
+
+func TestReleaseInstance(t *testing.T) {
+	b, serviceManager, instance := getQuarantineTestBroker(t)
+	instance.Status = service.InstanceStateReleasing
+	instance.Quarantine = &service.QuarantineState{}
+	assert.Nil(t, b.store.WriteInstance(instance))
+	var released bool
+	serviceManager.ReleaseBehavior = func(
+		_ context.Context,
+		instance service.Instance,
+	) (service.InstanceDetails, error) {
+		released = true
+		return instance.Details, nil
+	}
+	_, err := b.releaseInstance(
+		context.Background(),
+		getQuarantineTestTask("releaseInstance", instance),
+	)
+	assert.Nil(t, err)
+	assert.True(t, released)
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Equal(t, service.InstanceStateProvisioned, instance.Status)
+	assert.NotNil(t, instance.Quarantine.ReleasedAt)
+}
+
+// getQuarantineTestBroker returns a broker whose only instance is an instance
+// of the fake service that is being quarantined, along with the fake service
+// manager
+func getQuarantineTestBroker(
+	t *testing.T,
+) (*broker, *fake.ServiceManager, service.Instance) {
+	b, _, _, instance := getNotificationTestBroker(t)
+	serviceManager, ok :=
+		instance.Service.GetServiceManager().(*fake.ServiceManager)
+	assert.True(t, ok)
+	instance.Status = service.InstanceStateQuarantining
+	assert.Nil(t, b.store.WriteInstance(instance))
+	return b, serviceManager, instance
+}
+
+func getQuarantineTestTask(
+	jobName string,
+	instance service.Instance,
+) async.Task {
+	return async.NewTask(
+		jobName,
+		map[string]string{
+			"instanceID": instance.InstanceID,
+		},
+	)
+}
@@ -0,0 +1,66 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	log "github.com/Sirupsen/logrus"
+)
+
+// replicationRetryDelay is how long replication of a record waits before
+// being retried after failing, e.g. because the secondary store is unavailable
+const replicationRetryDelay = 30 * time.Second
+
+// replicateInstance copies an instance from the primary store to the
+// secondary store, retrying later if that fails
+func (b *broker) replicateInstance(
+	_ context.Context,
+	task async.Task,
+) ([]async.Task, error) {
+	instanceID, ok := task.GetArgs()["instanceID"]
+	if !ok {
+		return nil, errors.New(`missing required argument "instanceID"`)
+	}
+	if err := b.replicatedStore.ReplicateInstance(instanceID); err != nil {
+		log.WithFields(log.Fields{
+			"instanceID": instanceID,
+			"error":      err,
+		}).Error("error replicating instance; will retry")
+		return []async.Task{
+			async.NewDelayedTask(
+				task.GetJobName(),
+				task.GetArgs(),
+				replicationRetryDelay,
+			),
+		}, nil
+	}
+	return nil, nil
+}
+
+// replicateBinding copies a binding from the primary store to the secondary
+// store, retrying later if that fails
+func (b *broker) replicateBinding(
+	_ context.Context,
+	task async.Task,
+) ([]async.Task, error) {
+	bindingID, ok := task.GetArgs()["bindingID"]
+	if !ok {
+		return nil, errors.New(`missing required argument "bindingID"`)
+	}
+	if err := b.replicatedStore.ReplicateBinding(bindingID); err != nil {
+		log.WithFields(log.Fields{
+			"bindingID": bindingID,
+			"error":     err,
+		}).Error("error replicating binding; will retry")
+		return []async.Task{
+			async.NewDelayedTask(
+				task.GetJobName(),
+				task.GetArgs(),
+				replicationRetryDelay,
+			),
+		}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,129 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	"github.com/Azure/open-service-broker-azure/pkg/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeReplicatedStore records the records it is asked to replicate and fails
+// to replicate them if err is non-nil
+type fakeReplicatedStore struct {
+	storage.Store
+	replicatedInstanceIDs []string
+	replicatedBindingIDs  []string
+	err                   error
+}
+
+func (f *fakeReplicatedStore) ReplicateInstance(instanceID string) error {
+	f.replicatedInstanceIDs = append(f.replicatedInstanceIDs, instanceID)
+	return f.err
+}
+
+func (f *fakeReplicatedStore) ReplicateBinding(bindingID string) error {
+	f.replicatedBindingIDs = append(f.replicatedBindingIDs, bindingID)
+	return f.err
+}
+
+func (f *fakeReplicatedStore) GetReplicationLag() (time.Duration, error) {
+	return 0, nil
+}
+
+func TestReplicateInstance(t *testing.T) {
+	store := &fakeReplicatedStore{}
+	b := &broker{replicatedStore: store}
+	followUpTasks, err := b.replicateInstance(
+		context.Background(),
+		async.NewTask(
+			storage.ReplicateInstanceJobName,
+			map[string]string{"instanceID": "instance"},
+		),
+	)
+	assert.Nil(t, err)
+	assert.Empty(t, followUpTasks)
+	assert.Equal(t, []string{"instance"}, store.replicatedInstanceIDs)
+}
+
+func TestReplicateInstanceRetriesAfterFailure(t *testing.T) {
+	store := &fakeReplicatedStore{
+		err: errors.New("error replicating instance"),
+	}
+	b := &broker{replicatedStore: store}
+	args := map[string]string{"instanceID": "instance"}
+	followUpTasks, err := b.replicateInstance(
+		context.Background(),
+		async.NewTask(storage.ReplicateInstanceJobName, args),
+	)
+	assert.Nil(t, err)
+	assert.Len(t, followUpTasks, 1)
+	assert.Equal(
+		t,
+		storage.ReplicateInstanceJobName,
+		followUpTasks[0].GetJobName(),
+	)
+	assert.Equal(t, args, followUpTasks[0].GetArgs())
+	assert.NotNil(t, followUpTasks[0].GetExecuteTime())
+}
+
+func TestReplicateInstanceRequiresInstanceID(t *testing.T) {
+	store := &fakeReplicatedStore{}
+	b := &broker{replicatedStore: store}
+	_, err := b.replicateInstance(
+		context.Background(),
+		async.NewTask(storage.ReplicateInstanceJobName, map[string]string{}),
+	)
+	assert.NotNil(t, err)
+	assert.Empty(t, store.replicatedInstanceIDs)
+}
+
+func TestReplicateBinding(t *testing.T) {
+	store := &fakeReplicatedStore{}
+	b := &broker{replicatedStore: store}
+	followUpTasks, err := b.replicateBinding(
+		context.Background(),
+		async.NewTask(
+			storage.ReplicateBindingJobName,
+			map[string]string{"bindingID": "binding"},
+		),
+	)
+	assert.Nil(t, err)
+	assert.Empty(t, followUpTasks)
+	assert.Equal(t, []string{"binding"}, store.replicatedBindingIDs)
+}
+
+func TestReplicateBindingRetriesAfterFailure(t *testing.T) {
+	store := &fakeReplicatedStore{
+		err: errors.New("error replicating binding"),
+	}
+	b := &broker{replicatedStore: store}
+	args := map[string]string{"bindingID": "binding"}
+	followUpTasks, err := b.replicateBinding(
+		context.Background(),
+		async.NewTask(storage.ReplicateBindingJobName, args),
+	)
+	assert.Nil(t, err)
+	assert.Len(t, followUpTasks, 1)
+	assert.Equal(
+		t,
+		storage.ReplicateBindingJobName,
+		followUpTasks[0].GetJobName(),
+	)
+	assert.Equal(t, args, followUpTasks[0].GetArgs())
+	assert.NotNil(t, followUpTasks[0].GetExecuteTime())
+}
+
+func TestReplicateBindingRequiresBindingID(t *testing.T) {
+	store := &fakeReplicatedStore{}
+	b := &broker{replicatedStore: store}
+	_, err := b.replicateBinding(
+		context.Background(),
+		async.NewTask(storage.ReplicateBindingJobName, map[string]string{}),
+	)
+	assert.NotNil(t, err)
+	assert.Empty(t, store.replicatedBindingIDs)
+}
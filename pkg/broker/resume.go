@@ -0,0 +1,103 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	log "github.com/Sirupsen/logrus"
+)
+
+// resumeInstance resumes an instance that was suspended while idle. It is the
+// first task executed when a suspended instance is updated and is followed by
+// the first of the instance's updating steps.
+func (b *broker) resumeInstance(
+	ctx context.Context,
+	task async.Task,
+) ([]async.Task, error) {
+	args := task.GetArgs()
+	stepName, ok := args["stepName"]
+	if !ok {
+		return nil, errors.New(`missing required argument "stepName"`)
+	}
+	instanceID, ok := args["instanceID"]
+	if !ok {
+		return nil, errors.New(`missing required argument "instanceID"`)
+	}
+	instance, ok, err := b.store.GetInstance(instanceID)
+	if err != nil {
+		return nil, b.handleUpdatingError(
+			instanceID,
+			"resume",
+			err,
+			"error loading persisted instance",
+		)
+	}
+	if !ok {
+		return nil, b.handleUpdatingError(
+			instanceID,
+			"resume",
+			nil,
+			"instance does not exist in the data store",
+		)
+	}
+	// See executeUpdatingStep for why a second copy is retrieved
+	instanceCopy, _, err := b.store.GetInstance(instanceID)
+	if err != nil {
+		return nil, b.handleUpdatingError(
+			instanceID,
+			"resume",
+			err,
+			"error loading persisted instance",
+		)
+	}
+	if instance.IsSuspended() {
+		suspender, ok := instance.Service.GetServiceManager().(service.Suspender)
+		if !ok {
+			return nil, b.handleUpdatingError(
+				instance,
+				"resume",
+				nil,
+				fmt.Sprintf(
+					`service "%s" does not support resuming suspended instances`,
+					instance.ServiceID,
+				),
+			)
+		}
+		log.WithField("instanceID", instanceID).Debug("resuming instance")
+		details, err := suspender.Resume(ctx, instance)
+		if err != nil {
+			return nil, b.handleUpdatingError(
+				instance,
+				"resume",
+				err,
+				"error resuming suspended instance",
+			)
+		}
+		now := time.Now().UTC()
+		instanceCopy.Details = details
+		instanceCopy.Suspension.Suspended = false
+		instanceCopy.Suspension.ResumedAt = &now
+		instanceCopy.Suspension.IdleNotifiedAt = nil
+		if err := b.store.WriteInstance(instanceCopy); err != nil {
+			return nil, b.handleUpdatingError(
+				instanceCopy,
+				"resume",
+				err,
+				"error persisting instance",
+			)
+		}
+	}
+	return []async.Task{
+		async.NewTask(
+			"executeUpdatingStep",
+			map[string]string{
+				"stepName":   stepName,
+				"instanceID": instanceID,
+			},
+		),
+	}, nil
+}
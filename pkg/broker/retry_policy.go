@@ -0,0 +1,79 @@
+package broker
+
+import (
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// maxRetryDelay caps how long exponential backoff may delay any single retry
+const maxRetryDelay = 10 * time.Minute
+
+// RetryBehavior describes how a provisioning (or deprovisioning) step that
+// failed with an error of a given category is retried
+type RetryBehavior struct {
+	// MaxRetries is the number of times a failed step is retried before the
+	// provisioning operation is failed. Zero means the step is never retried.
+	MaxRetries int
+	// Delay is how long to wait before the first retry
+	Delay time.Duration
+	// Backoff indicates whether the delay doubles with each subsequent retry
+	Backoff bool
+}
+
+// RetryPolicy maps error categories to retry behaviors. Errors belonging to
+// categories that are absent from the policy are never retried.
+type RetryPolicy map[service.ErrorCategory]RetryBehavior
+
+// NewDefaultRetryPolicy returns a RetryPolicy that retries throttled requests
+// persistently with exponential backoff, transient errors a few times, and
+// never retries invalid requests or errors that haven't been categorized
+func NewDefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		service.ErrorCategoryThrottled: {
+			MaxRetries: 8,
+			Delay:      10 * time.Second,
+			Backoff:    true,
+		},
+		service.ErrorCategoryTransient: {
+			MaxRetries: 3,
+			Delay:      30 * time.Second,
+		},
+	}
+}
+
+// getRetryDelay determines, based on its category, whether a step that has
+// already been retried retryCount times should be retried again after failing
+// with the given error. If so, how long to wait before doing so is also
+// returned.
+func (r RetryPolicy) getRetryDelay(
+	err error,
+	retryCount int,
+) (time.Duration, bool) {
+	behavior, ok := r[service.GetErrorCategory(err)]
+	if !ok || retryCount >= behavior.MaxRetries {
+		return 0, false
+	}
+	delay := behavior.Delay
+	if behavior.Backoff {
+		for i := 0; i < retryCount && delay < maxRetryDelay; i++ {
+			delay *= 2
+		}
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay, true
+}
+
+// NewTeardownRetryPolicy returns a RetryPolicy, for deprovisioning steps with
+// dependencies, that retries a step finding what it deletes still in use up to
+// maxRetries times, waiting the given delay before each retry
+func NewTeardownRetryPolicy(maxRetries int, delay time.Duration) RetryPolicy {
+	return RetryPolicy{
+		service.ErrorCategoryInUse: {
+			MaxRetries: maxRetries,
+			Delay:      delay,
+		},
+	}
+}
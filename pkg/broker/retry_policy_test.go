@@ -0,0 +1,306 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	fakeAsync "github.com/Azure/open-service-broker-azure/pkg/async/fake"
+	"github.com/Azure/open-service-broker-azure/pkg/crypto/noop"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	memoryStorage "github.com/Azure/open-service-broker-azure/pkg/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRetryDelay(t *testing.T) {
+	policy := RetryPolicy{
+		service.ErrorCategoryThrottled: {
+			MaxRetries: 20,
+			Delay:      time.Second,
+			Backoff:    true,
+		},
+		service.ErrorCategoryTransient: {
+			MaxRetries: 2,
+			Delay:      time.Minute,
+		},
+	}
+	throttledErr := service.NewCategorizedError(
+		service.ErrorCategoryThrottled,
+		errSome,
+	)
+	transientErr := service.NewCategorizedError(
+		service.ErrorCategoryTransient,
+		errSome,
+	)
+	testCases := []struct {
+		name          string
+		err           error
+		retryCount    int
+		expectedDelay time.Duration
+		expectedRetry bool
+	}{
+		{
+			name:          "first retry of throttled error",
+			err:           throttledErr,
+			retryCount:    0,
+			expectedDelay: time.Second,
+			expectedRetry: true,
+		},
+		{
+			name:          "backoff of throttled error",
+			err:           throttledErr,
+			retryCount:    3,
+			expectedDelay: 8 * time.Second,
+			expectedRetry: true,
+		},
+		{
+			name:          "backoff of throttled error is capped",
+			err:           throttledErr,
+			retryCount:    19,
+			expectedDelay: maxRetryDelay,
+			expectedRetry: true,
+		},
+		{
+			name:          "throttled error exhausts retries",
+			err:           throttledErr,
+			retryCount:    20,
+			expectedRetry: false,
+		},
+		{
+			name:          "transient error without backoff",
+			err:           transientErr,
+			retryCount:    1,
+			expectedDelay: time.Minute,
+			expectedRetry: true,
+		},
+		{
+			name:          "transient error exhausts retries",
+			err:           transientErr,
+			retryCount:    2,
+			expectedRetry: false,
+		},
+		{
+			name:          "validation error",
+			err:           service.NewValidationError("foo", "bar"),
+			expectedRetry: false,
+		},
+		{
+			name:          "uncategorized error",
+			err:           errSome,
+			expectedRetry: false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			delay, ok := policy.getRetryDelay(tc.err, tc.retryCount)
+			assert.Equal(t, tc.expectedRetry, ok)
+			assert.Equal(t, tc.expectedDelay, delay)
+		})
+	}
+}
+
+func TestProvisioningStepRetriesByErrorCategory(t *testing.T) {
+	fakeModule, err := fake.New()
+	assert.Nil(t, err)
+	catalog, err := fakeModule.GetCatalog()
+	assert.Nil(t, err)
+	b := &broker{
+		store:                 memoryStorage.NewStore(catalog, noop.NewCodec()),
+		asyncEngine:           fakeAsync.NewEngine(),
+		catalog:               catalog,
+		provisioningSemaphore: newMemoryProvisioningSemaphore(),
+		retryPolicy: RetryPolicy{
+			service.ErrorCategoryThrottled: {
+				MaxRetries: 1,
+				Delay:      time.Second,
+			},
+		},
+	}
+	svc, ok := catalog.GetService(fake.ServiceID)
+	assert.True(t, ok)
+	plan, ok := svc.GetPlan(fake.StandardPlanID)
+	assert.True(t, ok)
+	instance := service.Instance{
+		InstanceID:             "instance",
+		ServiceID:              fake.ServiceID,
+		Service:                svc,
+		PlanID:                 fake.StandardPlanID,
+		Plan:                   plan,
+		Status:                 service.InstanceStateProvisioning,
+		ProvisioningParameters: &fake.ProvisioningParameters{},
+		UpdatingParameters:     &fake.UpdatingParameters{},
+		Details:                &fake.InstanceDetails{},
+	}
+	assert.Nil(t, b.store.WriteInstance(instance))
+	stepErr := service.NewCategorizedError(
+		service.ErrorCategoryThrottled,
+		errors.New("too many requests"),
+	)
+	fakeModule.ServiceManager.ProvisionBehavior = func(
+		context.Context,
+		service.Instance,
+	) (service.InstanceDetails, error) {
+		return nil, stepErr
+	}
+
+	// A throttled step is retried while retries remain...
+	followUpTasks, err := b.executeProvisioningStep(
+		context.Background(),
+		async.NewTask(
+			"executeProvisioningStep",
+			map[string]string{
+				"stepName":   "run",
+				"instanceID": instance.InstanceID,
+			},
+		),
+	)
+	assert.Nil(t, err)
+	assert.Len(t, followUpTasks, 1)
+	assert.NotNil(t, followUpTasks[0].GetExecuteTime())
+	assert.Equal(t, "1", followUpTasks[0].GetArgs()["retryCount"])
+	instance, ok, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, service.InstanceStateProvisioning, instance.Status)
+
+	// ...and fails provisioning once they're exhausted
+	followUpTasks, err = b.executeProvisioningStep(
+		context.Background(),
+		followUpTasks[0],
+	)
+	assert.NotNil(t, err)
+	assert.Empty(t, followUpTasks)
+	instance, ok, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, service.InstanceStateProvisioningFailed, instance.Status)
+}
+
+func TestProvisioningStepFailsImmediatelyForFatalErrors(t *testing.T) {
+	fakeModule, err := fake.New()
+	assert.Nil(t, err)
+	catalog, err := fakeModule.GetCatalog()
+	assert.Nil(t, err)
+	b := &broker{
+		store:                 memoryStorage.NewStore(catalog, noop.NewCodec()),
+		asyncEngine:           fakeAsync.NewEngine(),
+		catalog:               catalog,
+		provisioningSemaphore: newMemoryProvisioningSemaphore(),
+		retryPolicy:           NewDefaultRetryPolicy(),
+	}
+	svc, ok := catalog.GetService(fake.ServiceID)
+	assert.True(t, ok)
+	plan, ok := svc.GetPlan(fake.StandardPlanID)
+	assert.True(t, ok)
+	instance := service.Instance{
+		InstanceID:             "instance",
+		ServiceID:              fake.ServiceID,
+		Service:                svc,
+		PlanID:                 fake.StandardPlanID,
+		Plan:                   plan,
+		Status:                 service.InstanceStateProvisioning,
+		ProvisioningParameters: &fake.ProvisioningParameters{},
+		UpdatingParameters:     &fake.UpdatingParameters{},
+		Details:                &fake.InstanceDetails{},
+	}
+	assert.Nil(t, b.store.WriteInstance(instance))
+	fakeModule.ServiceManager.ProvisionBehavior = func(
+		context.Context,
+		service.Instance,
+	) (service.InstanceDetails, error) {
+		return nil, service.NewCategorizedError(
+			service.ErrorCategoryInvalid,
+			errors.New("bad request"),
+		)
+	}
+	followUpTasks, err := b.executeProvisioningStep(
+		context.Background(),
+		async.NewTask(
+			"executeProvisioningStep",
+			map[string]string{
+				"stepName":   "run",
+				"instanceID": instance.InstanceID,
+			},
+		),
+	)
+	assert.NotNil(t, err)
+	assert.Empty(t, followUpTasks)
+	instance, ok, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, service.InstanceStateProvisioningFailed, instance.Status)
+}
+
+func TestDeprovisioningStepWithDependenciesRetriesWhileInUse(t *testing.T) {
+	fakeModule, err := fake.New()
+	assert.Nil(t, err)
+	catalog, err := fakeModule.GetCatalog()
+	assert.Nil(t, err)
+	b := &broker{
+		store:               memoryStorage.NewStore(catalog, noop.NewCodec()),
+		asyncEngine:         fakeAsync.NewEngine(),
+		catalog:             catalog,
+		teardownRetryPolicy: NewTeardownRetryPolicy(1, time.Second),
+	}
+	svc, ok := catalog.GetService(fake.ServiceID)
+	assert.True(t, ok)
+	plan, ok := svc.GetPlan(fake.StandardPlanID)
+	assert.True(t, ok)
+	instance := service.Instance{
+		InstanceID:             "instance",
+		ServiceID:              fake.ServiceID,
+		Service:                svc,
+		PlanID:                 fake.StandardPlanID,
+		Plan:                   plan,
+		Status:                 service.InstanceStateDeprovisioning,
+		ProvisioningParameters: &fake.ProvisioningParameters{},
+		UpdatingParameters:     &fake.UpdatingParameters{},
+		Details:                &fake.InstanceDetails{},
+	}
+	assert.Nil(t, b.store.WriteInstance(instance))
+	fakeModule.ServiceManager.DeprovisionCleanupBehavior = func(
+		context.Context,
+		service.Instance,
+	) (service.InstanceDetails, error) {
+		return nil, service.NewCategorizedError(
+			service.ErrorCategoryInUse,
+			errors.New("subnet is in use"),
+		)
+	}
+
+	// A step whose resource is still in use is retried while retries remain...
+	followUpTasks, err := b.executeDeprovisioningStep(
+		context.Background(),
+		async.NewTask(
+			"executeDeprovisioningStep",
+			map[string]string{
+				"stepName":   "cleanup",
+				"instanceID": instance.InstanceID,
+			},
+		),
+	)
+	assert.Nil(t, err)
+	assert.Len(t, followUpTasks, 1)
+	assert.NotNil(t, followUpTasks[0].GetExecuteTime())
+	assert.Equal(t, "cleanup", followUpTasks[0].GetArgs()["stepName"])
+	assert.Equal(t, "1", followUpTasks[0].GetArgs()["retryCount"])
+	instance, ok, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, service.InstanceStateDeprovisioning, instance.Status)
+
+	// ...and fails deprovisioning once they're exhausted
+	followUpTasks, err = b.executeDeprovisioningStep(
+		context.Background(),
+		followUpTasks[0],
+	)
+	assert.NotNil(t, err)
+	assert.Empty(t, followUpTasks)
+	instance, ok, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, service.InstanceStateDeprovisioningFailed, instance.Status)
+}
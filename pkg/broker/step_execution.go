@@ -0,0 +1,203 @@
+package broker
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	log "github.com/Sirupsen/logrus"
+	uuid "github.com/satori/go.uuid"
+)
+
+// executionTokenArg is the argument of an executeProvisioningStep task that
+// carries the token of the step execution the task belongs to. A task without
+// one, such as the task that executes the first step, belongs to whichever
+// execution of its step is recorded, if any.
+const executionTokenArg = "executionToken"
+
+// getCompletedStepExecution returns the recorded execution of the named
+// provisioning step of the given instance that a task with the given arguments
+// belongs to, if that execution completed. A bool indicating whether it did is
+// also returned.
+func getCompletedStepExecution(
+	instance service.Instance,
+	stepName string,
+	args map[string]string,
+) (service.StepExecution, bool) {
+	execution, ok := instance.ProvisioningStepExecutions[stepName]
+	if !ok || execution.Completed == nil {
+		return execution, false
+	}
+	token := args[executionTokenArg]
+	return execution, token == "" || token == execution.Token
+}
+
+// startStepExecution records that the named provisioning step of the given
+// instance is about to be executed by a task with the given arguments and
+// persists the instance. An execution that was started by a task with the
+// same arguments, but never completed, was interrupted, e.g. by the broker
+// restarting, and is resumed rather than started over: the step is executed
+// again from the instance details that were persisted after the previous step.
+func (b *broker) startStepExecution(
+	instance service.Instance,
+	stepName string,
+	args map[string]string,
+) (service.Instance, error) {
+	retryCount, _ := strconv.Atoi(args["retryCount"])
+	token := args[executionTokenArg]
+	execution, ok := instance.ProvisioningStepExecutions[stepName]
+	if !ok || (token != "" && token != execution.Token) {
+		if token == "" {
+			token = uuid.NewV4().String()
+		}
+		execution = service.StepExecution{Token: token}
+	} else if execution.Started != nil && execution.Completed == nil &&
+		execution.RetryCount == retryCount {
+		execution.Resumptions++
+		log.WithFields(log.Fields{
+			"step":        stepName,
+			"instanceID":  instance.InstanceID,
+			"token":       execution.Token,
+			"retryCount":  retryCount,
+			"resumptions": execution.Resumptions,
+		}).Info("resuming interrupted provisioning step")
+	}
+	now := time.Now().UTC()
+	execution.RetryCount = retryCount
+	execution.Started = &now
+	execution.Completed = nil
+	instance = withStepExecution(instance, stepName, execution)
+	return instance, b.store.WriteInstance(instance)
+}
+
+// withCompletedStepExecution returns the given instance with the execution of
+// the named provisioning step recorded as completed. If there is a next step,
+// an execution of it is recorded too, so that its token can be handed to the
+// task that executes it, and handed again to a task that replaces it should the
+// completed step's task be re-delivered before that task was submitted.
+func withCompletedStepExecution(
+	instance service.Instance,
+	stepName string,
+	nextStepName string,
+) service.Instance {
+	now := time.Now().UTC()
+	execution := instance.ProvisioningStepExecutions[stepName]
+	execution.Completed = &now
+	instance = withStepExecution(instance, stepName, execution)
+	if nextStepName != "" {
+		instance = withStepExecution(
+			instance,
+			nextStepName,
+			service.StepExecution{Token: uuid.NewV4().String()},
+		)
+	}
+	return instance
+}
+
+func withStepExecution(
+	instance service.Instance,
+	stepName string,
+	execution service.StepExecution,
+) service.Instance {
+	executions := service.StepExecutions{}
+	for name, e := range instance.ProvisioningStepExecutions {
+		executions[name] = e
+	}
+	executions[stepName] = execution
+	instance.ProvisioningStepExecutions = executions
+	return instance
+}
+
+// withExecutionToken returns the given arguments of a task that executes the
+// named provisioning step of the given instance, with the token of the step's
+// recorded execution added, if it has one
+func withExecutionToken(
+	args map[string]string,
+	instance service.Instance,
+	stepName string,
+) map[string]string {
+	if execution, ok := instance.ProvisioningStepExecutions[stepName]; ok {
+		args[executionTokenArg] = execution.Token
+	}
+	return args
+}
+
+// getTasksFollowingCompletedStep returns the tasks to submit in place of
+// re-executing the named provisioning step of the given instance, whose
+// execution already completed, when a task that executes it is re-delivered.
+// The tasks that followed the step may have been lost if the broker stopped
+// between recording the step as completed and submitting them, so they are
+// submitted again, unless the instance shows that they were already executed.
+func (b *broker) getTasksFollowingCompletedStep(
+	instance service.Instance,
+	provisioner service.Provisioner,
+	stepName string,
+) []async.Task {
+	logFields := log.Fields{
+		"step":       stepName,
+		"instanceID": instance.InstanceID,
+	}
+	if instance.Status != service.InstanceStateProvisioning &&
+		instance.Status != service.InstanceStateProvisioningDegraded {
+		log.WithFields(logFields).Info(
+			"provisioning step already completed and provisioning is no longer " +
+				"in progress; skipping re-delivered step",
+		)
+		return nil
+	}
+	nextStepName, ok := provisioner.GetNextStepName(stepName)
+	if !ok {
+		log.WithFields(logFields).Info(
+			"provisioning step already completed; skipping re-delivered step and " +
+				"finishing provisioning",
+		)
+		if _, ok := b.getConnectivityValidator(instance); ok {
+			return []async.Task{
+				async.NewTask(
+					"validateConnectivity",
+					map[string]string{
+						"instanceID": instance.InstanceID,
+					},
+				),
+			}
+		}
+		if instance.IsPendingActivation() {
+			return []async.Task{
+				async.NewTask(
+					deactivateInstanceStepName,
+					map[string]string{
+						"instanceID": instance.InstanceID,
+					},
+				),
+			}
+		}
+		return nil
+	}
+	logFields["nextStep"] = nextStepName
+	next := instance.ProvisioningStepExecutions[nextStepName]
+	if next.Started != nil {
+		log.WithFields(logFields).Info(
+			"provisioning step already completed and the next step has started; " +
+				"skipping re-delivered step",
+		)
+		return nil
+	}
+	log.WithFields(logFields).Info(
+		"provisioning step already completed; skipping re-delivered step and " +
+			"submitting the next step",
+	)
+	return []async.Task{
+		async.NewTask(
+			"executeProvisioningStep",
+			withExecutionToken(
+				map[string]string{
+					"stepName":   nextStepName,
+					"instanceID": instance.InstanceID,
+				},
+				instance,
+				nextStepName,
+			),
+		),
+	}
+}
@@ -0,0 +1,195 @@
+package broker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStepExecutionsNotRecordedUnlessEnabled(t *testing.T) {
+	b, _, instance, _ := getStepExecutionTestBroker(t)
+	b.recordStepExecutions = false
+	_, err := b.executeProvisioningStep(
+		context.Background(),
+		getFailureGraceTestTask(instance),
+	)
+	assert.Nil(t, err)
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Empty(t, instance.ProvisioningStepExecutions)
+}
+
+func TestExecutedStepIsRecorded(t *testing.T) {
+	b, _, instance, executions := getStepExecutionTestBroker(t)
+	_, err := b.executeProvisioningStep(
+		context.Background(),
+		getFailureGraceTestTask(instance),
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, *executions)
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	execution, ok := instance.ProvisioningStepExecutions["run"]
+	assert.True(t, ok)
+	assert.NotEmpty(t, execution.Token)
+	assert.NotNil(t, execution.Started)
+	assert.NotNil(t, execution.Completed)
+	assert.Equal(t, 0, execution.Resumptions)
+}
+
+func TestRedeliveredCompletedStepIsNotExecutedAgain(t *testing.T) {
+	b, _, instance, executions := getStepExecutionTestBroker(t)
+	task := getFailureGraceTestTask(instance)
+	_, err := b.executeProvisioningStep(context.Background(), task)
+	assert.Nil(t, err)
+	followUpTasks, err := b.executeProvisioningStep(context.Background(), task)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, *executions)
+	// The task that followed the last step may have been lost, so it's
+	// submitted again
+	assert.Len(t, followUpTasks, 1)
+	assert.Equal(t, "validateConnectivity", followUpTasks[0].GetJobName())
+}
+
+func TestRedeliveredCompletedStepOfProvisionedInstanceIsDropped(
+	t *testing.T,
+) {
+	b, _, instance, executions := getStepExecutionTestBroker(t)
+	task := getFailureGraceTestTask(instance)
+	_, err := b.executeProvisioningStep(context.Background(), task)
+	assert.Nil(t, err)
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	instance.Status = service.InstanceStateProvisioned
+	assert.Nil(t, b.store.WriteInstance(instance))
+	followUpTasks, err := b.executeProvisioningStep(context.Background(), task)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, *executions)
+	assert.Empty(t, followUpTasks)
+}
+
+func TestStepOfAnotherExecutionIsExecuted(t *testing.T) {
+	b, _, instance, executions := getStepExecutionTestBroker(t)
+	completed := time.Now()
+	instance.ProvisioningStepExecutions = service.StepExecutions{
+		"run": {
+			Token:     "earlier",
+			Started:   &completed,
+			Completed: &completed,
+		},
+	}
+	assert.Nil(t, b.store.WriteInstance(instance))
+	_, err := b.executeProvisioningStep(
+		context.Background(),
+		async.NewTask(
+			"executeProvisioningStep",
+			map[string]string{
+				"stepName":        "run",
+				"instanceID":      instance.InstanceID,
+				executionTokenArg: "later",
+			},
+		),
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, *executions)
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Equal(t, "later", instance.ProvisioningStepExecutions["run"].Token)
+}
+
+func TestInterruptedStepIsResumed(t *testing.T) {
+	b, _, instance, executions := getStepExecutionTestBroker(t)
+	started := time.Now().Add(-time.Minute)
+	instance.ProvisioningStepExecutions = service.StepExecutions{
+		"run": {
+			Token:   "token",
+			Started: &started,
+		},
+	}
+	assert.Nil(t, b.store.WriteInstance(instance))
+	_, err := b.executeProvisioningStep(
+		context.Background(),
+		getFailureGraceTestTask(instance),
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, *executions)
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	execution := instance.ProvisioningStepExecutions["run"]
+	assert.Equal(t, "token", execution.Token)
+	assert.Equal(t, 1, execution.Resumptions)
+	assert.NotNil(t, execution.Completed)
+}
+
+func TestRetriedStepIsNotResumed(t *testing.T) {
+	b, _, instance, _ := getStepExecutionTestBroker(t)
+	started := time.Now().Add(-time.Minute)
+	instance.ProvisioningStepExecutions = service.StepExecutions{
+		"run": {
+			Token:   "token",
+			Started: &started,
+		},
+	}
+	assert.Nil(t, b.store.WriteInstance(instance))
+	_, err := b.executeProvisioningStep(
+		context.Background(),
+		async.NewTask(
+			"executeProvisioningStep",
+			map[string]string{
+				"stepName":        "run",
+				"instanceID":      instance.InstanceID,
+				"retryCount":      "1",
+				executionTokenArg: "token",
+			},
+		),
+	)
+	assert.Nil(t, err)
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	execution := instance.ProvisioningStepExecutions["run"]
+	assert.Equal(t, 1, execution.RetryCount)
+	assert.Equal(t, 0, execution.Resumptions)
+}
+
+func TestFailureGraceRetryCarriesExecutionToken(t *testing.T) {
+	b, _, instance := getFailureGraceTestBroker(t, time.Hour)
+	b.recordStepExecutions = true
+	followUpTasks, err := b.executeProvisioningStep(
+		context.Background(),
+		getFailureGraceTestTask(instance),
+	)
+	assert.Nil(t, err)
+	assert.Len(t, followUpTasks, 1)
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	execution := instance.ProvisioningStepExecutions["run"]
+	assert.Nil(t, execution.Completed)
+	args := followUpTasks[0].GetArgs()
+	assert.Equal(t, execution.Token, args[executionTokenArg])
+	assert.Equal(t, "1", args["retryCount"])
+}
+
+// getStepExecutionTestBroker returns a broker that records step executions and
+// whose only instance is an instance of the fake service that is being
+// provisioned, along with the fake module and a count of the times the fake
+// service's provisioning step has been executed
+func getStepExecutionTestBroker(
+	t *testing.T,
+) (*broker, *fake.Module, service.Instance, *int) {
+	b, fakeModule, instance := getConnectivityValidationTestBroker(t)
+	b.recordStepExecutions = true
+	executions := 0
+	fakeModule.ServiceManager.ProvisionBehavior = func(
+		_ context.Context,
+		instance service.Instance,
+	) (service.InstanceDetails, error) {
+		executions++
+		return instance.Details, nil
+	}
+	return b, fakeModule, instance, &executions
+}
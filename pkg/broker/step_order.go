@@ -0,0 +1,123 @@
+package broker
+
+import (
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// getStepOrders converts the given provisioning step order overrides, which
+// are keyed by service name, to overrides keyed by service ID. Each override
+// is validated against the provisioner for every one of its service's plans
+// so that an override that would violate any step's dependencies is rejected
+// when the broker starts instead of when an instance is provisioned.
+func getStepOrders(
+	services []service.Service,
+	overrides map[string][]string,
+) (map[string][]string, error) {
+	stepOrders := map[string][]string{}
+	for serviceName, stepNames := range overrides {
+		var svc service.Service
+		for _, s := range services {
+			if s.GetName() == serviceName {
+				svc = s
+				break
+			}
+		}
+		if svc == nil {
+			return nil, fmt.Errorf(
+				`provisioning step order override names unknown service "%s"`,
+				serviceName,
+			)
+		}
+		knownStepNames := map[string]bool{}
+		for _, plan := range svc.GetPlans() {
+			provisioner, err := svc.GetServiceManager().GetProvisioner(plan)
+			if err != nil {
+				return nil, fmt.Errorf(
+					`error retrieving provisioner for service "%s" and plan "%s": `+
+						`%s`,
+					serviceName,
+					plan.GetName(),
+					err,
+				)
+			}
+			for _, stepName := range provisioner.GetStepNames() {
+				knownStepNames[stepName] = true
+			}
+			if _, err := getOrderedProvisioner(provisioner, stepNames); err != nil {
+				return nil, fmt.Errorf(
+					`invalid provisioning step order override for service "%s" `+
+						`and plan "%s": %s`,
+					serviceName,
+					plan.GetName(),
+					err,
+				)
+			}
+		}
+		for _, stepName := range stepNames {
+			if !knownStepNames[stepName] {
+				return nil, fmt.Errorf(
+					`provisioning step order override for service "%s" names `+
+						`unknown step "%s"`,
+					serviceName,
+					stepName,
+				)
+			}
+		}
+		stepOrders[svc.GetID()] = stepNames
+	}
+	return stepOrders, nil
+}
+
+// applyStepOrder applies any step order override the broker was configured
+// with for the given service to the given provisioner
+func (b *broker) applyStepOrder(
+	serviceID string,
+	provisioner service.Provisioner,
+) (service.Provisioner, error) {
+	stepNames, ok := b.stepOrders[serviceID]
+	if !ok {
+		return provisioner, nil
+	}
+	return getOrderedProvisioner(provisioner, stepNames)
+}
+
+// getOrderedProvisioner applies the given step order to the given provisioner.
+// Plans of a single service don't necessarily share the same steps, so steps
+// that aren't in the provisioner's chain are disregarded.
+func getOrderedProvisioner(
+	provisioner service.Provisioner,
+	stepNames []string,
+) (service.Provisioner, error) {
+	chainStepNames := map[string]bool{}
+	for _, stepName := range provisioner.GetStepNames() {
+		chainStepNames[stepName] = true
+	}
+	applicableStepNames := []string{}
+	for _, stepName := range stepNames {
+		if chainStepNames[stepName] {
+			applicableStepNames = append(applicableStepNames, stepName)
+		}
+	}
+	return provisioner.WithStepOrder(applicableStepNames)
+}
+
+// getSkippedStepNames returns the names of steps in a declared provisioner's
+// chain that are absent from an ordered provisioner's chain
+func getSkippedStepNames(
+	declared service.Provisioner,
+	ordered service.Provisioner,
+) []string {
+	orderedStepNames := map[string]bool{}
+	for _, stepName := range ordered.GetStepNames() {
+		orderedStepNames[stepName] = true
+	}
+	var skippedStepNames []string
+	for _, stepName := range declared.GetStepNames() {
+		if !orderedStepNames[stepName] {
+			skippedStepNames = append(skippedStepNames, stepName)
+		}
+	}
+	return skippedStepNames
+}
@@ -0,0 +1,93 @@
+package broker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStepOrders(t *testing.T) {
+	services := getStepOrderTestServices(t)
+	stepOrders, err := getStepOrders(
+		services,
+		map[string][]string{"fake": {"run"}},
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string][]string{fake.ServiceID: {"run"}}, stepOrders)
+}
+
+func TestGetStepOrdersWithUnknownService(t *testing.T) {
+	_, err := getStepOrders(
+		getStepOrderTestServices(t),
+		map[string][]string{"bogus": {"run"}},
+	)
+	assert.NotNil(t, err)
+}
+
+func TestGetStepOrdersWithUnknownStep(t *testing.T) {
+	_, err := getStepOrders(
+		getStepOrderTestServices(t),
+		map[string][]string{"fake": {"run", "bogus"}},
+	)
+	assert.NotNil(t, err)
+}
+
+func TestGetStepOrdersWithInvalidOrder(t *testing.T) {
+	// The first step can never be skipped
+	_, err := getStepOrders(
+		getStepOrderTestServices(t),
+		map[string][]string{"fake": {}},
+	)
+	assert.NotNil(t, err)
+}
+
+func TestGetOrderedProvisionerDisregardsStepsNotInChain(t *testing.T) {
+	declared := getStepOrderTestProvisioner(t)
+	ordered, err := getOrderedProvisioner(
+		declared,
+		[]string{"first", "monitoring", "third"},
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"first", "third"}, ordered.GetStepNames())
+	assert.Equal(
+		t,
+		[]string{"second"},
+		getSkippedStepNames(declared, ordered),
+	)
+}
+
+func TestApplyStepOrderWithoutOverride(t *testing.T) {
+	b := &broker{}
+	declared := getStepOrderTestProvisioner(t)
+	provisioner, err := b.applyStepOrder(fake.ServiceID, declared)
+	assert.Nil(t, err)
+	assert.Equal(t, declared, provisioner)
+	assert.Empty(t, getSkippedStepNames(declared, provisioner))
+}
+
+func getStepOrderTestServices(t *testing.T) []service.Service {
+	fakeModule, err := fake.New()
+	assert.Nil(t, err)
+	catalog, err := fakeModule.GetCatalog()
+	assert.Nil(t, err)
+	return catalog.GetServices()
+}
+
+func getStepOrderTestProvisioner(t *testing.T) service.Provisioner {
+	noop := func(
+		context.Context,
+		service.Instance,
+	) (service.InstanceDetails, error) {
+		return nil, nil
+	}
+	provisioner, err := service.NewProvisioner(
+		service.NewProvisioningStep("first", noop),
+		service.NewProvisioningStepWithDependencies("second", noop, "first"),
+		service.NewProvisioningStepWithDependencies("third", noop, "first"),
+	)
+	assert.Nil(t, err)
+	return provisioner
+}
@@ -0,0 +1,78 @@
+package broker
+
+import (
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/api"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// SubscriptionRoutingConfig represents configuration for provisioning
+// instances into Azure subscriptions other than the broker's own. Modules'
+// managers are bound to a single subscription, so each additional subscription
+// has a set of modules of its own.
+type SubscriptionRoutingConfig struct {
+	// Routing determines which subscription each new instance is provisioned
+	// into
+	Routing api.SubscriptionRouting
+	// ModulesBySubscription maps the IDs of the subscriptions in Routing's pool,
+	// other than the broker's own, to modules that manage resources in them
+	ModulesBySubscription map[string][]service.Module
+}
+
+// getSubscriptionCatalogs returns, for each subscription that instances may
+// be routed to, the catalog of services that manage resources in that
+// subscription. The services in each catalog are those of the broker's own
+// catalog, as provided by the subscription's modules.
+func getSubscriptionCatalogs(
+	config SubscriptionRoutingConfig,
+	catalog service.Catalog,
+	minStability service.Stability,
+) (map[string]service.Catalog, error) {
+	catalogs := map[string]service.Catalog{}
+	for _, subscriptionID := range config.Routing.GetSubscriptionIDs() {
+		modules, ok := config.ModulesBySubscription[subscriptionID]
+		if !ok {
+			return nil, fmt.Errorf(
+				`no modules manage resources in subscription "%s"`,
+				subscriptionID,
+			)
+		}
+		services := []service.Service{}
+		for _, module := range modules {
+			if module.GetStability() < minStability {
+				continue
+			}
+			moduleCatalog, err := module.GetCatalog()
+			if err != nil {
+				return nil, fmt.Errorf(
+					`error retrieving catalog from module "%s" for subscription "%s": %s`,
+					module.GetName(),
+					subscriptionID,
+					err,
+				)
+			}
+			services = append(services, moduleCatalog.GetServices()...)
+		}
+		// Every service must be available in every subscription, or instances
+		// routed there couldn't be managed
+		for _, svc := range catalog.GetServices() {
+			found := false
+			for _, subscriptionSvc := range services {
+				if subscriptionSvc.GetID() == svc.GetID() {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf(
+					`service "%s" is not provided for subscription "%s"`,
+					svc.GetName(),
+					subscriptionID,
+				)
+			}
+		}
+		catalogs[subscriptionID] = service.NewCatalog(services)
+	}
+	return catalogs, nil
+}
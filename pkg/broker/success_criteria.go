@@ -0,0 +1,92 @@
+package broker
+
+import (
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// getSkippedSuccessCriteria converts the given lists of success criteria to
+// skip, which are keyed by plans identified as serviceName/planName, to sets.
+// Each criterion named is checked against those its plan declares so that a
+// typo, or an attempt to skip a criterion that isn't optional, is rejected
+// when the broker starts instead of going unnoticed.
+func getSkippedSuccessCriteria(
+	catalogServices []service.Service,
+	skippedCriteria map[string][]string,
+) (map[string]map[string]bool, error) {
+	services := map[string]service.Service{}
+	plans := map[string]service.Plan{}
+	for _, svc := range catalogServices {
+		for _, plan := range svc.GetPlans() {
+			planKey := service.GetPlanKey(svc, plan)
+			services[planKey] = svc
+			plans[planKey] = plan
+		}
+	}
+	skipped := map[string]map[string]bool{}
+	for planKey, criterionNames := range skippedCriteria {
+		plan, ok := plans[planKey]
+		if !ok {
+			return nil, fmt.Errorf(
+				`skipped success criteria name unknown plan "%s"; plans are `+
+					"identified as serviceName/planName",
+				planKey,
+			)
+		}
+		declared := map[string]service.SuccessCriterion{}
+		serviceManager := services[planKey].GetServiceManager()
+		if provider, ok :=
+			serviceManager.(service.SuccessCriteriaProvider); ok {
+			for _, criterion := range provider.GetSuccessCriteria(plan) {
+				declared[criterion.Name] = criterion
+			}
+		}
+		skipped[planKey] = map[string]bool{}
+		for _, criterionName := range criterionNames {
+			criterion, ok := declared[criterionName]
+			if !ok {
+				return nil, fmt.Errorf(
+					`plan "%s" has no success criterion "%s"`,
+					planKey,
+					criterionName,
+				)
+			}
+			if !criterion.Optional {
+				return nil, fmt.Errorf(
+					`success criterion "%s" of plan "%s" is not optional and cannot `+
+						"be skipped",
+					criterionName,
+					planKey,
+				)
+			}
+			skipped[planKey][criterionName] = true
+		}
+	}
+	return skipped, nil
+}
+
+// getSuccessCriteria returns the success criteria that the given instance's
+// plan declares, along with those of them that are to be checked, which are
+// all of them but any optional criteria the broker was configured to skip
+func (b *broker) getSuccessCriteria(
+	instance service.Instance,
+) ([]service.SuccessCriterion, []service.SuccessCriterion) {
+	provider, ok :=
+		instance.Service.GetServiceManager().(service.SuccessCriteriaProvider)
+	if !ok {
+		return nil, nil
+	}
+	declared := provider.GetSuccessCriteria(instance.Plan)
+	skipped := b.skippedSuccessCriteria[service.GetPlanKey(
+		instance.Service,
+		instance.Plan,
+	)]
+	checked := []service.SuccessCriterion{}
+	for _, criterion := range declared {
+		if !criterion.Optional || !skipped[criterion.Name] {
+			checked = append(checked, criterion)
+		}
+	}
+	return declared, checked
+}
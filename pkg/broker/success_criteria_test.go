@@ -0,0 +1,171 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSkippedSuccessCriteria(t *testing.T) {
+	services := getSuccessCriteriaTestServices(t)
+	skipped, err := getSkippedSuccessCriteria(
+		services,
+		map[string][]string{"fake/standard": {"warmUp"}},
+	)
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		map[string]map[string]bool{"fake/standard": {"warmUp": true}},
+		skipped,
+	)
+}
+
+func TestGetSkippedSuccessCriteriaWithUnknownPlan(t *testing.T) {
+	_, err := getSkippedSuccessCriteria(
+		getSuccessCriteriaTestServices(t),
+		map[string][]string{"fake/bogus": {"warmUp"}},
+	)
+	assert.NotNil(t, err)
+}
+
+func TestGetSkippedSuccessCriteriaWithUnknownCriterion(t *testing.T) {
+	_, err := getSkippedSuccessCriteria(
+		getSuccessCriteriaTestServices(t),
+		map[string][]string{"fake/standard": {"bogus"}},
+	)
+	assert.NotNil(t, err)
+}
+
+func TestGetSkippedSuccessCriteriaWithRequiredCriterion(t *testing.T) {
+	_, err := getSkippedSuccessCriteria(
+		getSuccessCriteriaTestServices(t),
+		map[string][]string{"fake/standard": {"seedSchema"}},
+	)
+	assert.NotNil(t, err)
+}
+
+func TestSuccessCriteriaAreCheckedBeforeInstanceIsProvisioned(t *testing.T) {
+	b, fakeModule, instance := getConnectivityValidationTestBroker(t)
+	b.connectivityValidated = nil
+	b.skippedSuccessCriteria = map[string]map[string]bool{
+		"fake/standard": {"warmUp": true},
+	}
+	var seedErr error
+	fakeModule.ServiceManager.SuccessCriteriaBehavior =
+		getTestSuccessCriteria(func() error { return seedErr })
+
+	followUpTasks, err := b.executeProvisioningStep(
+		context.Background(),
+		async.NewTask(
+			"executeProvisioningStep",
+			map[string]string{
+				"stepName":   "run",
+				"instanceID": instance.InstanceID,
+			},
+		),
+	)
+	assert.Nil(t, err)
+	assert.Len(t, followUpTasks, 1)
+	assert.Equal(t, "seedSchema", followUpTasks[0].GetArgs()["stepName"])
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Equal(t, service.InstanceStateProvisioning, instance.Status)
+	assert.Equal(t, []string{"warmUp"}, instance.SkippedProvisioningSteps)
+	assert.Equal(
+		t,
+		[]string{"run", "seedSchema"},
+		instance.ProvisioningProgress.Steps,
+	)
+
+	// A criterion that fails is retried like any other step
+	seedErr = service.NewCategorizedError(
+		service.ErrorCategoryTransient,
+		errors.New("database is still starting"),
+	)
+	followUpTasks, err = b.executeProvisioningStep(
+		context.Background(),
+		followUpTasks[0],
+	)
+	assert.Nil(t, err)
+	assert.Len(t, followUpTasks, 1)
+	assert.Equal(t, "1", followUpTasks[0].GetArgs()["retryCount"])
+
+	seedErr = nil
+	followUpTasks, err = b.executeProvisioningStep(
+		context.Background(),
+		followUpTasks[0],
+	)
+	assert.Nil(t, err)
+	assert.Empty(t, followUpTasks)
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Equal(t, service.InstanceStateProvisioned, instance.Status)
+}
+
+func TestFailedSuccessCriterionFailsProvisioning(t *testing.T) {
+	b, fakeModule, instance := getConnectivityValidationTestBroker(t)
+	b.connectivityValidated = nil
+	fakeModule.ServiceManager.SuccessCriteriaBehavior =
+		getTestSuccessCriteria(func() error {
+			return errors.New("schema script is invalid")
+		})
+	_, err := b.executeProvisioningStep(
+		context.Background(),
+		async.NewTask(
+			"executeProvisioningStep",
+			map[string]string{
+				"stepName":   "seedSchema",
+				"instanceID": instance.InstanceID,
+			},
+		),
+	)
+	assert.NotNil(t, err)
+	instance, _, err = b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Equal(t, service.InstanceStateProvisioningFailed, instance.Status)
+}
+
+func getSuccessCriteriaTestServices(t *testing.T) []service.Service {
+	fakeModule, err := fake.New()
+	assert.Nil(t, err)
+	fakeModule.ServiceManager.SuccessCriteriaBehavior =
+		getTestSuccessCriteria(func() error { return nil })
+	catalog, err := fakeModule.GetCatalog()
+	assert.Nil(t, err)
+	return catalog.GetServices()
+}
+
+// getTestSuccessCriteria returns criteria of which the first, which is
+// required, invokes the given function and the second is optional
+func getTestSuccessCriteria(
+	seedSchema func() error,
+) fake.SuccessCriteriaFunction {
+	return func(service.Plan) []service.SuccessCriterion {
+		return []service.SuccessCriterion{
+			{
+				Name: "seedSchema",
+				Check: func(
+					_ context.Context,
+					instance service.Instance,
+				) (service.InstanceDetails, error) {
+					return instance.Details, seedSchema()
+				},
+			},
+			{
+				Name: "warmUp",
+				Check: func(
+					_ context.Context,
+					instance service.Instance,
+				) (service.InstanceDetails, error) {
+					return instance.Details, nil
+				},
+				Optional: true,
+			},
+		}
+	}
+}
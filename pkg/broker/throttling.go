@@ -0,0 +1,52 @@
+package broker
+
+import (
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	log "github.com/Sirupsen/logrus"
+)
+
+// resourcesProvider is the namespace of the resource provider that handles
+// the resource groups and ARM template deployments that steps of every service
+// make requests to
+const resourcesProvider = "Microsoft.Resources"
+
+// throttledJobNames are the names of the jobs whose tasks are paced according
+// to how heavily the resource providers they make requests to are throttling
+// the broker
+var throttledJobNames = map[string]bool{
+	"executeProvisioningStep":   true,
+	"executeUpdatingStep":       true,
+	"executeDeprovisioningStep": true,
+}
+
+// getThrottlingDelay returns how long the execution of the given task should
+// be postponed for because one or more of the resource providers used by the
+// service of the instance it operates on is throttling the broker. Tasks that
+// don't execute an instance's steps, and steps of instances that can't be
+// loaded, are never postponed; the latter will fail as they otherwise would.
+func (b *broker) getThrottlingDelay(task async.Task) time.Duration {
+	if !throttledJobNames[task.GetJobName()] {
+		return 0
+	}
+	instanceID := task.GetArgs()["instanceID"]
+	instance, ok, err := b.store.GetInstance(instanceID)
+	if err != nil || !ok {
+		return 0
+	}
+	resourceProviders := append(
+		[]string{resourcesProvider},
+		b.resourceProviders[instance.ServiceID]...,
+	)
+	delay := b.throttle.Admit(resourceProviders)
+	if delay > 0 {
+		log.WithFields(log.Fields{
+			"job":               task.GetJobName(),
+			"instanceID":        instanceID,
+			"resourceProviders": resourceProviders,
+			"delay":             delay,
+		}).Debug("postponing step of instance using throttled resource providers")
+	}
+	return delay
+}
@@ -0,0 +1,124 @@
+package broker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	"github.com/Azure/open-service-broker-azure/pkg/crypto/noop"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	memoryStorage "github.com/Azure/open-service-broker-azure/pkg/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeResourceProviderThrottle struct {
+	delay             time.Duration
+	resourceProviders [][]string
+}
+
+func (f *fakeResourceProviderThrottle) Admit(
+	resourceProviders []string,
+) time.Duration {
+	f.resourceProviders = append(f.resourceProviders, resourceProviders)
+	return f.delay
+}
+
+func (f *fakeResourceProviderThrottle) GetDispatchRates() map[string]float64 {
+	return nil
+}
+
+func TestGetThrottlingDelayForInstanceStep(t *testing.T) {
+	throttle := &fakeResourceProviderThrottle{delay: time.Minute}
+	b := getThrottlingTestBroker(t, throttle)
+	for _, jobName := range []string{
+		"executeProvisioningStep",
+		"executeUpdatingStep",
+		"executeDeprovisioningStep",
+	} {
+		delay := b.getThrottlingDelay(
+			async.NewTask(
+				jobName,
+				map[string]string{
+					"stepName":   "run",
+					"instanceID": "instance",
+				},
+			),
+		)
+		assert.Equal(t, time.Minute, delay, jobName)
+	}
+	assert.Len(t, throttle.resourceProviders, 3)
+	assert.Equal(
+		t,
+		[]string{resourcesProvider, "Microsoft.Fake"},
+		throttle.resourceProviders[0],
+	)
+}
+
+func TestGetThrottlingDelayForOtherJob(t *testing.T) {
+	throttle := &fakeResourceProviderThrottle{delay: time.Minute}
+	b := getThrottlingTestBroker(t, throttle)
+	delay := b.getThrottlingDelay(
+		async.NewTask(
+			"sendNotification",
+			map[string]string{"instanceID": "instance"},
+		),
+	)
+	assert.Equal(t, time.Duration(0), delay)
+	assert.Empty(t, throttle.resourceProviders)
+}
+
+func TestGetThrottlingDelayForMissingInstance(t *testing.T) {
+	throttle := &fakeResourceProviderThrottle{delay: time.Minute}
+	b := getThrottlingTestBroker(t, throttle)
+	delay := b.getThrottlingDelay(
+		async.NewTask(
+			"executeProvisioningStep",
+			map[string]string{
+				"stepName":   "run",
+				"instanceID": "missing-instance",
+			},
+		),
+	)
+	assert.Equal(t, time.Duration(0), delay)
+	assert.Empty(t, throttle.resourceProviders)
+}
+
+func getThrottlingTestBroker(
+	t *testing.T,
+	throttle service.ResourceProviderThrottle,
+) *broker {
+	fakeModule, err := fake.New()
+	assert.Nil(t, err)
+	catalog, err := fakeModule.GetCatalog()
+	assert.Nil(t, err)
+	b := &broker{
+		store:    memoryStorage.NewStore(catalog, noop.NewCodec()),
+		catalog:  catalog,
+		throttle: throttle,
+		resourceProviders: map[string][]string{
+			fake.ServiceID: {"Microsoft.Fake"},
+		},
+	}
+	svc, ok := catalog.GetService(fake.ServiceID)
+	assert.True(t, ok)
+	plan, ok := svc.GetPlan(fake.StandardPlanID)
+	assert.True(t, ok)
+	assert.Nil(
+		t,
+		b.store.WriteInstance(
+			service.Instance{
+				InstanceID:             "instance",
+				ServiceID:              fake.ServiceID,
+				Service:                svc,
+				PlanID:                 fake.StandardPlanID,
+				Plan:                   plan,
+				Status:                 service.InstanceStateProvisioning,
+				ProvisioningParameters: &fake.ProvisioningParameters{},
+				UpdatingParameters:     &fake.UpdatingParameters{},
+				Details:                &fake.InstanceDetails{},
+			},
+		),
+	)
+	return b
+}
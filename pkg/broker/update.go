@@ -88,7 +88,15 @@ func (b *broker) executeUpdatingStep(
 			`updater does not know how to process step "%s"`,
 		)
 	}
-	updatedDetails, err := step.Execute(ctx, instance)
+	if instance, err = b.withDependencies(instance); err != nil {
+		return nil, b.handleUpdatingError(
+			instance,
+			stepName,
+			err,
+			"error loading instance's dependencies",
+		)
+	}
+	updatedDetails, err := b.executeTracedStep(ctx, "updating", step, instance)
 	if err != nil {
 		return nil, b.handleUpdatingError(
 			instance,
@@ -0,0 +1,135 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	log "github.com/Sirupsen/logrus"
+)
+
+const validateConnectivityStepName = "validateConnectivity"
+
+// getConnectivityValidator returns the ConnectivityValidator for the given
+// instance if, and only if, the broker has been configured to validate
+// connectivity to instances of the instance's service and that service's
+// module supports doing so
+func (b *broker) getConnectivityValidator(
+	instance service.Instance,
+) (service.ConnectivityValidator, bool) {
+	if !b.connectivityValidated[instance.ServiceID] {
+		return nil, false
+	}
+	validator, ok :=
+		instance.Service.GetServiceManager().(service.ConnectivityValidator)
+	return validator, ok
+}
+
+// validateConnectivity is the final step of provisioning for any instance
+// whose module validates connectivity, unless the instance was provisioned
+// with deferred activation. Only once it has succeeded (or been skipped) is the
+// instance considered provisioned.
+func (b *broker) validateConnectivity(
+	ctx context.Context,
+	task async.Task,
+) ([]async.Task, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	args := task.GetArgs()
+	instanceID, ok := args["instanceID"]
+	if !ok {
+		return nil, errors.New(`missing required argument "instanceID"`)
+	}
+	instance, ok, err := b.store.GetInstance(instanceID)
+	if err != nil {
+		return nil, b.handleProvisioningError(
+			instanceID,
+			validateConnectivityStepName,
+			err,
+			"error loading persisted instance",
+		)
+	}
+	if !ok {
+		return nil, b.handleProvisioningError(
+			instanceID,
+			validateConnectivityStepName,
+			nil,
+			"instance does not exist in the data store",
+		)
+	}
+	logFields := log.Fields{
+		"step":       validateConnectivityStepName,
+		"instanceID": instanceID,
+	}
+	validator, ok := b.getConnectivityValidator(instance)
+	if !ok {
+		// This can happen if the broker's configuration changed after this task
+		// was submitted
+		log.WithFields(logFields).Debug(
+			"connectivity validation is not enabled for this instance; skipping",
+		)
+	} else {
+		log.WithFields(logFields).Debug("validating connectivity")
+		validated, err := validator.ValidateConnectivity(ctx, instance)
+		if err != nil {
+			retryCount, _ := strconv.Atoi(args["retryCount"])
+			if delay, ok := b.retryPolicy.getRetryDelay(err, retryCount); ok {
+				logFields["errorCategory"] = service.GetErrorCategory(err)
+				logFields["retryCount"] = retryCount + 1
+				logFields["retryDelay"] = delay
+				logFields["error"] = err
+				log.WithFields(logFields).Warn(
+					"connectivity validation failed; retrying",
+				)
+				return []async.Task{
+					async.NewDelayedTask(
+						"validateConnectivity",
+						map[string]string{
+							"instanceID": instanceID,
+							"retryCount": strconv.Itoa(retryCount + 1),
+						},
+						delay,
+					),
+				}, nil
+			}
+			return nil, b.handleProvisioningError(
+				instance,
+				validateConnectivityStepName,
+				err,
+				"error validating connectivity",
+			)
+		}
+		if !validated {
+			log.WithFields(logFields).Info(
+				"instance is not reachable from the broker due to network " +
+					"restrictions; skipped connectivity validation",
+			)
+		}
+	}
+	if instance.IsPendingActivation() {
+		return []async.Task{
+			async.NewTask(
+				deactivateInstanceStepName,
+				map[string]string{
+					"instanceID": instanceID,
+				},
+			),
+		}, nil
+	}
+	instance.Status = service.InstanceStateProvisioned
+	instance = b.withProvisioningTiming(instance)
+	if err := b.store.WriteInstance(instance); err != nil {
+		return nil, b.handleProvisioningError(
+			instance,
+			validateConnectivityStepName,
+			err,
+			"error persisting instance",
+		)
+	}
+	b.releaseProvisioningSlot(instance)
+	b.recordProvisioningSLAOutcome(instance)
+	b.submitProvisioningNotifications(instance)
+	return nil, nil
+}
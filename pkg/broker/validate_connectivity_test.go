@@ -0,0 +1,166 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	fakeAsync "github.com/Azure/open-service-broker-azure/pkg/async/fake"
+	"github.com/Azure/open-service-broker-azure/pkg/crypto/noop"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	memoryStorage "github.com/Azure/open-service-broker-azure/pkg/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLastProvisioningStepSubmitsConnectivityValidation(t *testing.T) {
+	b, _, instance := getConnectivityValidationTestBroker(t)
+	followUpTasks, err := b.executeProvisioningStep(
+		context.Background(),
+		async.NewTask(
+			"executeProvisioningStep",
+			map[string]string{
+				"stepName":   "run",
+				"instanceID": instance.InstanceID,
+			},
+		),
+	)
+	assert.Nil(t, err)
+	assert.Len(t, followUpTasks, 1)
+	assert.Equal(t, "validateConnectivity", followUpTasks[0].GetJobName())
+	instance, ok, err := b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, service.InstanceStateProvisioning, instance.Status)
+}
+
+func TestLastProvisioningStepSkipsConnectivityValidationIfNotEnabled(
+	t *testing.T,
+) {
+	b, _, instance := getConnectivityValidationTestBroker(t)
+	b.connectivityValidated = nil
+	followUpTasks, err := b.executeProvisioningStep(
+		context.Background(),
+		async.NewTask(
+			"executeProvisioningStep",
+			map[string]string{
+				"stepName":   "run",
+				"instanceID": instance.InstanceID,
+			},
+		),
+	)
+	assert.Nil(t, err)
+	assert.Empty(t, followUpTasks)
+	instance, ok, err := b.store.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, service.InstanceStateProvisioned, instance.Status)
+}
+
+func TestValidateConnectivity(t *testing.T) {
+	testCases := []struct {
+		name           string
+		validated      bool
+		err            error
+		expectedStatus string
+		expectRetry    bool
+	}{
+		{
+			name:           "validation succeeds",
+			validated:      true,
+			expectedStatus: service.InstanceStateProvisioned,
+		},
+		{
+			name:           "validation is skipped",
+			validated:      false,
+			expectedStatus: service.InstanceStateProvisioned,
+		},
+		{
+			name: "validation fails with a transient error",
+			err: service.NewCategorizedError(
+				service.ErrorCategoryTransient,
+				errors.New("connection refused"),
+			),
+			expectedStatus: service.InstanceStateProvisioning,
+			expectRetry:    true,
+		},
+		{
+			name:           "validation fails with a fatal error",
+			err:            errors.New("authentication failed"),
+			expectedStatus: service.InstanceStateProvisioningFailed,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b, fakeModule, instance := getConnectivityValidationTestBroker(t)
+			fakeModule.ServiceManager.ConnectivityValidationBehavior = func(
+				context.Context,
+				service.Instance,
+			) (bool, error) {
+				return tc.validated, tc.err
+			}
+			followUpTasks, err := b.validateConnectivity(
+				context.Background(),
+				async.NewTask(
+					"validateConnectivity",
+					map[string]string{
+						"instanceID": instance.InstanceID,
+					},
+				),
+			)
+			if tc.expectedStatus == service.InstanceStateProvisioningFailed {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+			if tc.expectRetry {
+				assert.Len(t, followUpTasks, 1)
+				assert.NotNil(t, followUpTasks[0].GetExecuteTime())
+				assert.Equal(t, "1", followUpTasks[0].GetArgs()["retryCount"])
+			} else {
+				assert.Empty(t, followUpTasks)
+			}
+			instance, ok, err := b.store.GetInstance(instance.InstanceID)
+			assert.Nil(t, err)
+			assert.True(t, ok)
+			assert.Equal(t, tc.expectedStatus, instance.Status)
+		})
+	}
+}
+
+func getConnectivityValidationTestBroker(
+	t *testing.T,
+) (*broker, *fake.Module, service.Instance) {
+	fakeModule, err := fake.New()
+	assert.Nil(t, err)
+	catalog, err := fakeModule.GetCatalog()
+	assert.Nil(t, err)
+	b := &broker{
+		store:                 memoryStorage.NewStore(catalog, noop.NewCodec()),
+		asyncEngine:           fakeAsync.NewEngine(),
+		catalog:               catalog,
+		provisioningSemaphore: newMemoryProvisioningSemaphore(),
+		retryPolicy:           NewDefaultRetryPolicy(),
+		connectivityValidated: map[string]bool{
+			fake.ServiceID: true,
+		},
+	}
+	svc, ok := catalog.GetService(fake.ServiceID)
+	assert.True(t, ok)
+	plan, ok := svc.GetPlan(fake.StandardPlanID)
+	assert.True(t, ok)
+	instance := service.Instance{
+		InstanceID:             "instance",
+		ServiceID:              fake.ServiceID,
+		Service:                svc,
+		PlanID:                 fake.StandardPlanID,
+		Plan:                   plan,
+		Status:                 service.InstanceStateProvisioning,
+		ProvisioningParameters: &fake.ProvisioningParameters{},
+		UpdatingParameters:     &fake.UpdatingParameters{},
+		Details:                &fake.InstanceDetails{},
+	}
+	assert.Nil(t, b.store.WriteInstance(instance))
+	return b, fakeModule, instance
+}
@@ -6,3 +6,24 @@ type Codec interface {
 	Encrypt([]byte) ([]byte, error)
 	Decrypt([]byte) ([]byte, error)
 }
+
+// FieldCodec is an interface to be implemented by any Codec that can also
+// encrypt and decrypt individual fields of a value. Each encrypted field
+// records the version of the key it was encrypted with, so that keys can be
+// rotated without fields that were encrypted earlier becoming unreadable.
+type FieldCodec interface {
+	Codec
+	// EncryptsFields indicates whether values should be encrypted one field at
+	// a time, rather than whole, when they are written. Fields that were
+	// encrypted earlier can be decrypted either way.
+	EncryptsFields() bool
+	EncryptField([]byte) (EncryptedField, error)
+	DecryptField(EncryptedField) ([]byte, error)
+}
+
+// EncryptedField is the encrypted value of a single field, along with the
+// version of the key it was encrypted with
+type EncryptedField struct {
+	KeyVersion string `json:"keyVersion"`
+	Ciphertext []byte `json:"ciphertext"`
+}
@@ -0,0 +1,147 @@
+package versioned
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/crypto"
+)
+
+// keyVersionPrefix begins the envelope in which whole values are encrypted,
+// which is followed by the version of the key the value was encrypted with, a
+// colon and then the ciphertext itself. Values encrypted before the envelope
+// was introduced have none.
+const keyVersionPrefix = "osba-key-version:"
+
+type codec struct {
+	keyVersion     string
+	codecs         map[string]crypto.Codec
+	encryptsFields bool
+}
+
+// NewCodec returns a new implementation of crypto.FieldCodec that encrypts
+// using the codec for the given key version and decrypts using the codec for
+// whichever version of the key each field or whole value was encrypted with.
+// Codecs for retired key versions are used only for decrypting. Whole values
+// that were encrypted before they recorded a key version are decrypted using
+// whichever codec, current or retired, can decrypt them.
+func NewCodec(
+	keyVersion string,
+	codecs map[string]crypto.Codec,
+	encryptsFields bool,
+) (crypto.FieldCodec, error) {
+	if keyVersion == "" {
+		return nil, errors.New("key version may not be empty")
+	}
+	if _, ok := codecs[keyVersion]; !ok {
+		return nil, fmt.Errorf(`no codec for key version "%s"`, keyVersion)
+	}
+	for version := range codecs {
+		if strings.Contains(version, ":") {
+			return nil, fmt.Errorf(
+				`key version "%s" may not contain ":"`,
+				version,
+			)
+		}
+	}
+	return &codec{
+		keyVersion:     keyVersion,
+		codecs:         codecs,
+		encryptsFields: encryptsFields,
+	}, nil
+}
+
+func (c *codec) Encrypt(plaintext []byte) ([]byte, error) {
+	ciphertext, err := c.codecs[c.keyVersion].Encrypt(plaintext)
+	if err != nil {
+		return nil, err
+	}
+	envelope := []byte(keyVersionPrefix + c.keyVersion + ":")
+	return append(envelope, ciphertext...), nil
+}
+
+func (c *codec) Decrypt(ciphertext []byte) ([]byte, error) {
+	keyVersion, unwrapped, ok := unwrap(ciphertext)
+	if ok {
+		versionCodec, ok := c.codecs[keyVersion]
+		if !ok {
+			return nil, fmt.Errorf(
+				`value was encrypted with unknown key version "%s"`,
+				keyVersion,
+			)
+		}
+		return versionCodec.Decrypt(unwrapped)
+	}
+	// The value doesn't record the version of the key it was encrypted with,
+	// so try the current version first, then each of the retired ones
+	plaintext, err := c.codecs[c.keyVersion].Decrypt(ciphertext)
+	if err == nil {
+		return plaintext, nil
+	}
+	retiredVersions := make([]string, 0, len(c.codecs))
+	for version := range c.codecs {
+		if version != c.keyVersion {
+			retiredVersions = append(retiredVersions, version)
+		}
+	}
+	sort.Strings(retiredVersions)
+	for _, version := range retiredVersions {
+		if plaintext, retiredErr := c.codecs[version].Decrypt(
+			ciphertext,
+		); retiredErr == nil {
+			return plaintext, nil
+		}
+	}
+	return nil, err
+}
+
+func (c *codec) EncryptsFields() bool {
+	return c.encryptsFields
+}
+
+func (c *codec) EncryptField(plaintext []byte) (crypto.EncryptedField, error) {
+	ciphertext, err := c.codecs[c.keyVersion].Encrypt(plaintext)
+	if err != nil {
+		return crypto.EncryptedField{}, err
+	}
+	return crypto.EncryptedField{
+		KeyVersion: c.keyVersion,
+		Ciphertext: ciphertext,
+	}, nil
+}
+
+func (c *codec) DecryptField(field crypto.EncryptedField) ([]byte, error) {
+	fieldCodec, ok := c.codecs[field.KeyVersion]
+	if !ok {
+		return nil, fmt.Errorf(
+			`field was encrypted with unknown key version "%s"`,
+			field.KeyVersion,
+		)
+	}
+	return fieldCodec.Decrypt(field.Ciphertext)
+}
+
+// GetKeyVersion returns the version of the key that the given whole value was
+// encrypted with. It returns false if the value was encrypted before whole
+// values recorded a key version.
+func GetKeyVersion(ciphertext []byte) (string, bool) {
+	keyVersion, _, ok := unwrap(ciphertext)
+	return keyVersion, ok
+}
+
+// unwrap splits a whole value's key version envelope from its ciphertext. It
+// returns false if the value has no envelope.
+func unwrap(ciphertext []byte) (string, []byte, bool) {
+	if !bytes.HasPrefix(ciphertext, []byte(keyVersionPrefix)) {
+		return "", nil, false
+	}
+	rest := ciphertext[len(keyVersionPrefix):]
+	i := bytes.IndexByte(rest, ':')
+	if i < 0 {
+		return "", nil, false
+	}
+	return string(rest[:i]), rest[i+1:], true
+}
@@ -0,0 +1,139 @@
+package versioned
+
+import (
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/crypto"
+	"github.com/Azure/open-service-broker-azure/pkg/crypto/aes256"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCodecRequiresCodecForKeyVersion(t *testing.T) {
+	_, err := NewCodec("2", map[string]crypto.Codec{"1": getTestCodec(t)}, true)
+	assert.NotNil(t, err)
+}
+
+func TestCodecEncryptAndDecryptField(t *testing.T) {
+	c, err := NewCodec("1", map[string]crypto.Codec{"1": getTestCodec(t)}, true)
+	assert.Nil(t, err)
+	field, err := c.EncryptField([]byte("foo"))
+	assert.Nil(t, err)
+	assert.Equal(t, "1", field.KeyVersion)
+	assert.NotEqual(t, []byte("foo"), field.Ciphertext)
+	plaintext, err := c.DecryptField(field)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("foo"), plaintext)
+}
+
+func TestCodecDecryptFieldAfterKeyRotation(t *testing.T) {
+	oldCodec := getTestCodec(t)
+	c, err := NewCodec("1", map[string]crypto.Codec{"1": oldCodec}, true)
+	assert.Nil(t, err)
+	field, err := c.EncryptField([]byte("foo"))
+	assert.Nil(t, err)
+	newCodec, err := aes256.NewCodec([]byte("AES256Key-32Characters0987654321"))
+	assert.Nil(t, err)
+	// The new key is used for encrypting; the old one is kept for decrypting
+	c, err = NewCodec(
+		"2",
+		map[string]crypto.Codec{"1": oldCodec, "2": newCodec},
+		true,
+	)
+	assert.Nil(t, err)
+	plaintext, err := c.DecryptField(field)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("foo"), plaintext)
+	field, err = c.EncryptField([]byte("foo"))
+	assert.Nil(t, err)
+	assert.Equal(t, "2", field.KeyVersion)
+	// Once the old key is retired altogether, fields it encrypted can't be
+	// decrypted
+	c, err = NewCodec("2", map[string]crypto.Codec{"2": newCodec}, true)
+	assert.Nil(t, err)
+	_, err = c.DecryptField(crypto.EncryptedField{KeyVersion: "1"})
+	assert.NotNil(t, err)
+}
+
+func TestNewCodecRejectsKeyVersionContainingColon(t *testing.T) {
+	_, err := NewCodec(
+		"1:2",
+		map[string]crypto.Codec{"1:2": getTestCodec(t)},
+		false,
+	)
+	assert.NotNil(t, err)
+}
+
+func TestCodecDecryptAfterKeyRotation(t *testing.T) {
+	oldCodec := getTestCodec(t)
+	c, err := NewCodec("1", map[string]crypto.Codec{"1": oldCodec}, false)
+	assert.Nil(t, err)
+	oldCiphertext, err := c.Encrypt([]byte("foo"))
+	assert.Nil(t, err)
+	keyVersion, ok := GetKeyVersion(oldCiphertext)
+	assert.True(t, ok)
+	assert.Equal(t, "1", keyVersion)
+	newCodec, err := aes256.NewCodec([]byte("AES256Key-32Characters0987654321"))
+	assert.Nil(t, err)
+	// The new key is used for encrypting; the old one is kept for decrypting
+	c, err = NewCodec(
+		"2",
+		map[string]crypto.Codec{"1": oldCodec, "2": newCodec},
+		false,
+	)
+	assert.Nil(t, err)
+	plaintext, err := c.Decrypt(oldCiphertext)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("foo"), plaintext)
+	newCiphertext, err := c.Encrypt([]byte("foo"))
+	assert.Nil(t, err)
+	keyVersion, ok = GetKeyVersion(newCiphertext)
+	assert.True(t, ok)
+	assert.Equal(t, "2", keyVersion)
+	// Once the old key is retired altogether, values it encrypted can't be
+	// decrypted
+	c, err = NewCodec("2", map[string]crypto.Codec{"2": newCodec}, false)
+	assert.Nil(t, err)
+	_, err = c.Decrypt(oldCiphertext)
+	assert.NotNil(t, err)
+	plaintext, err = c.Decrypt(newCiphertext)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("foo"), plaintext)
+}
+
+func TestCodecDecryptValueWithoutKeyVersion(t *testing.T) {
+	oldCodec := getTestCodec(t)
+	// Values encrypted before whole values recorded a key version were
+	// encrypted using the underlying codec directly
+	ciphertext, err := oldCodec.Encrypt([]byte("foo"))
+	assert.Nil(t, err)
+	_, ok := GetKeyVersion(ciphertext)
+	assert.False(t, ok)
+	newCodec, err := aes256.NewCodec([]byte("AES256Key-32Characters0987654321"))
+	assert.Nil(t, err)
+	// The value can be decrypted whether its key is current or retired
+	c, err := NewCodec("1", map[string]crypto.Codec{"1": oldCodec}, false)
+	assert.Nil(t, err)
+	plaintext, err := c.Decrypt(ciphertext)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("foo"), plaintext)
+	c, err = NewCodec(
+		"2",
+		map[string]crypto.Codec{"1": oldCodec, "2": newCodec},
+		false,
+	)
+	assert.Nil(t, err)
+	plaintext, err = c.Decrypt(ciphertext)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("foo"), plaintext)
+	// But not once its key is dropped
+	c, err = NewCodec("2", map[string]crypto.Codec{"2": newCodec}, false)
+	assert.Nil(t, err)
+	_, err = c.Decrypt(ciphertext)
+	assert.NotNil(t, err)
+}
+
+func getTestCodec(t *testing.T) crypto.Codec {
+	c, err := aes256.NewCodec([]byte("AES256Key-32Characters1234567890"))
+	assert.Nil(t, err)
+	return c
+}
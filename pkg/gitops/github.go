@@ -0,0 +1,228 @@
+package gitops
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	requestTimeout = 30 * time.Second
+	// maxCommitAttempts is how many times a commit is attempted when the branch
+	// keeps moving under it
+	maxCommitAttempts = 3
+)
+
+type gitHubRepository struct {
+	apiURL     string
+	repository string
+	branch     string
+	token      string
+	client     *http.Client
+}
+
+type gitHubContentsRequest struct {
+	Message string `json:"message"`
+	Content string `json:"content,omitempty"`
+	SHA     string `json:"sha,omitempty"`
+	Branch  string `json:"branch,omitempty"`
+}
+
+type gitHubContentsResponse struct {
+	SHA    string `json:"sha"`
+	Commit struct {
+		SHA string `json:"sha"`
+	} `json:"commit"`
+}
+
+// NewGitHubRepository returns a Repository that commits files to the given
+// branch of a repository, named as owner/name, by way of the GitHub contents
+// API at the given base URL. GitHub Enterprise Server and Gitea serve the same
+// API. The token authenticates every request and must be permitted to write
+// the repository's contents. If no branch is given, the repository's default
+// branch is used.
+func NewGitHubRepository(
+	apiURL string,
+	repository string,
+	branch string,
+	token string,
+) Repository {
+	return &gitHubRepository{
+		apiURL:     strings.TrimSuffix(apiURL, "/"),
+		repository: repository,
+		branch:     branch,
+		token:      token,
+		client: &http.Client{
+			Timeout: requestTimeout,
+		},
+	}
+}
+
+func (g *gitHubRepository) WriteFile(
+	path string,
+	content []byte,
+	message string,
+) (string, error) {
+	var commitSHA string
+	err := g.commit(path, func(sha string) (int, error) {
+		resp := gitHubContentsResponse{}
+		statusCode, err := g.send(
+			http.MethodPut,
+			path,
+			gitHubContentsRequest{
+				Message: message,
+				Content: base64.StdEncoding.EncodeToString(content),
+				SHA:     sha,
+				Branch:  g.branch,
+			},
+			&resp,
+		)
+		commitSHA = resp.Commit.SHA
+		return statusCode, err
+	})
+	if err != nil {
+		return "", fmt.Errorf("error writing %s: %s", path, err)
+	}
+	return commitSHA, nil
+}
+
+func (g *gitHubRepository) DeleteFile(path string, message string) error {
+	err := g.commit(path, func(sha string) (int, error) {
+		if sha == "" {
+			return http.StatusOK, nil
+		}
+		return g.send(
+			http.MethodDelete,
+			path,
+			gitHubContentsRequest{
+				Message: message,
+				SHA:     sha,
+				Branch:  g.branch,
+			},
+			nil,
+		)
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting %s: %s", path, err)
+	}
+	return nil
+}
+
+// commit looks up the blob SHA of the file at the given path, which is empty if
+// the file doesn't exist, and passes it to the given function to commit a
+// change to the file. The contents API refuses a change made against any blob
+// but the file's current one, so a conflict means the file was changed by
+// someone else in the meantime; the SHA is looked up afresh and the change is
+// attempted again.
+func (g *gitHubRepository) commit(
+	path string,
+	fn func(sha string) (int, error),
+) error {
+	for attempt := 1; ; attempt++ {
+		sha, err := g.getFileSHA(path)
+		if err != nil {
+			return err
+		}
+		statusCode, err := fn(sha)
+		if err == nil {
+			return nil
+		}
+		// The contents API reports a stale SHA with a 409 and a missing one, for a
+		// file created since it was looked up, with a 422
+		if (statusCode != http.StatusConflict &&
+			statusCode != http.StatusUnprocessableEntity) ||
+			attempt == maxCommitAttempts {
+			return err
+		}
+	}
+}
+
+func (g *gitHubRepository) getFileSHA(path string) (string, error) {
+	resp := gitHubContentsResponse{}
+	statusCode, err := g.send(http.MethodGet, path, nil, &resp)
+	if statusCode == http.StatusNotFound {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return resp.SHA, nil
+}
+
+// send sends a request concerning the file at the given path to the contents
+// API and unmarshals the response into the given object, if any. The response's
+// status code is returned even when it indicates an error.
+func (g *gitHubRepository) send(
+	method string,
+	path string,
+	body interface{},
+	resp interface{},
+) (int, error) {
+	var bodyBuffer bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&bodyBuffer).Encode(body); err != nil {
+			return 0, fmt.Errorf("error marshaling request: %s", err)
+		}
+	}
+	reqURL := fmt.Sprintf(
+		"%s/repos/%s/contents/%s",
+		g.apiURL,
+		g.repository,
+		escapePath(path),
+	)
+	if method == http.MethodGet && g.branch != "" {
+		reqURL = fmt.Sprintf("%s?ref=%s", reqURL, url.QueryEscape(g.branch))
+	}
+	httpReq, err := http.NewRequest(method, reqURL, &bodyBuffer)
+	if err != nil {
+		return 0, fmt.Errorf("error preparing request: %s", err)
+	}
+	httpReq.Header.Set("Accept", "application/vnd.github+json")
+	httpReq.Header.Set("Authorization", "Bearer "+g.token)
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	httpResp, err := g.client.Do(httpReq)
+	if err != nil {
+		return 0, fmt.Errorf("error contacting Git repository: %s", err)
+	}
+	defer httpResp.Body.Close() // nolint: errcheck
+	switch httpResp.StatusCode {
+	case http.StatusOK, http.StatusCreated:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return httpResp.StatusCode, fmt.Errorf(
+			"the Git repository refused the broker's token (status code %d); the "+
+				"token may have expired or may not be permitted to write the "+
+				"repository's contents",
+			httpResp.StatusCode,
+		)
+	default:
+		return httpResp.StatusCode, fmt.Errorf(
+			"unexpected status code %d from Git repository",
+			httpResp.StatusCode,
+		)
+	}
+	if resp != nil {
+		if err := json.NewDecoder(httpResp.Body).Decode(resp); err != nil {
+			return httpResp.StatusCode, fmt.Errorf(
+				"error unmarshaling response: %s",
+				err,
+			)
+		}
+	}
+	return httpResp.StatusCode, nil
+}
+
+// escapePath escapes each segment of the given slash-delimited path
+func escapePath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
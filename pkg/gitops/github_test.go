@@ -0,0 +1,161 @@
+package gitops
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testContentsPath = "/repos/owner/repo/contents/osba/instance/binding.yaml"
+
+func TestGitHubRepositoryWriteNewFile(t *testing.T) {
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, testContentsPath, r.URL.Path)
+			assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+			switch r.Method {
+			case http.MethodGet:
+				assert.Equal(t, "main", r.URL.Query().Get("ref"))
+				w.WriteHeader(http.StatusNotFound)
+			case http.MethodPut:
+				req := gitHubContentsRequest{}
+				assert.Nil(t, json.NewDecoder(r.Body).Decode(&req))
+				assert.Empty(t, req.SHA)
+				assert.Equal(t, "main", req.Branch)
+				assert.Equal(
+					t,
+					base64.StdEncoding.EncodeToString([]byte("manifest")),
+					req.Content,
+				)
+				w.WriteHeader(http.StatusCreated)
+				_, err := w.Write([]byte(`{"commit":{"sha":"abc"}}`))
+				assert.Nil(t, err)
+			}
+		}),
+	)
+	defer server.Close()
+	revision, err := NewGitHubRepository(
+		server.URL,
+		"owner/repo",
+		"main",
+		"token",
+	).WriteFile("osba/instance/binding.yaml", []byte("manifest"), "Add")
+	assert.Nil(t, err)
+	assert.Equal(t, "abc", revision)
+}
+
+func TestGitHubRepositoryWriteFileRetriesConflicts(t *testing.T) {
+	gets := 0
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet:
+				gets++
+				if gets == 1 {
+					_, err := w.Write([]byte(`{"sha":"stale"}`))
+					assert.Nil(t, err)
+					return
+				}
+				_, err := w.Write([]byte(`{"sha":"current"}`))
+				assert.Nil(t, err)
+			case http.MethodPut:
+				req := gitHubContentsRequest{}
+				assert.Nil(t, json.NewDecoder(r.Body).Decode(&req))
+				if req.SHA != "current" {
+					w.WriteHeader(http.StatusConflict)
+					return
+				}
+				_, err := w.Write([]byte(`{"commit":{"sha":"def"}}`))
+				assert.Nil(t, err)
+			}
+		}),
+	)
+	defer server.Close()
+	revision, err := NewGitHubRepository(server.URL, "owner/repo", "", "token").
+		WriteFile("osba/instance/binding.yaml", []byte("manifest"), "Add")
+	assert.Nil(t, err)
+	assert.Equal(t, "def", revision)
+	assert.Equal(t, 2, gets)
+}
+
+func TestGitHubRepositoryWriteFileGivesUpOnPersistentConflicts(
+	t *testing.T,
+) {
+	puts := 0
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet:
+				_, err := w.Write([]byte(`{"sha":"stale"}`))
+				assert.Nil(t, err)
+			case http.MethodPut:
+				puts++
+				w.WriteHeader(http.StatusConflict)
+			}
+		}),
+	)
+	defer server.Close()
+	_, err := NewGitHubRepository(server.URL, "owner/repo", "", "token").
+		WriteFile("osba/instance/binding.yaml", []byte("manifest"), "Add")
+	assert.NotNil(t, err)
+	assert.Equal(t, maxCommitAttempts, puts)
+}
+
+func TestGitHubRepositoryWriteFileWithRefusedToken(t *testing.T) {
+	gets := 0
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gets++
+			w.WriteHeader(http.StatusUnauthorized)
+		}),
+	)
+	defer server.Close()
+	_, err := NewGitHubRepository(server.URL, "owner/repo", "", "expired").
+		WriteFile("osba/instance/binding.yaml", []byte("manifest"), "Add")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "refused the broker's token")
+	// Authentication failures aren't retried
+	assert.Equal(t, 1, gets)
+}
+
+func TestGitHubRepositoryDeleteFile(t *testing.T) {
+	deleted := false
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet:
+				_, err := w.Write([]byte(`{"sha":"current"}`))
+				assert.Nil(t, err)
+			case http.MethodDelete:
+				req := gitHubContentsRequest{}
+				assert.Nil(t, json.NewDecoder(r.Body).Decode(&req))
+				assert.Equal(t, "current", req.SHA)
+				deleted = true
+				_, err := w.Write([]byte(`{"commit":{"sha":"ghi"}}`))
+				assert.Nil(t, err)
+			}
+		}),
+	)
+	defer server.Close()
+	err := NewGitHubRepository(server.URL, "owner/repo", "", "token").
+		DeleteFile("osba/instance/binding.yaml", "Remove")
+	assert.Nil(t, err)
+	assert.True(t, deleted)
+}
+
+func TestGitHubRepositoryDeleteFileThatDoesNotExist(t *testing.T) {
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodGet, r.Method)
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer server.Close()
+	err := NewGitHubRepository(server.URL, "owner/repo", "", "token").
+		DeleteFile("osba/instance/binding.yaml", "Remove")
+	assert.Nil(t, err)
+}
@@ -0,0 +1,252 @@
+package gitops
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/Azure/open-service-broker-azure/pkg/template"
+)
+
+// Formats in which a binding's manifest may be written
+const (
+	// FormatSecret describes a binding as a Kubernetes Secret holding its
+	// credentials
+	FormatSecret = "secret"
+	// FormatConfigMap describes a binding as a Kubernetes ConfigMap that
+	// identifies the instance and binding, but holds no credentials
+	FormatConfigMap = "configmap"
+)
+
+var secretTemplateBytes = []byte(`apiVersion: v1
+kind: Secret
+metadata:
+  name: {{ .Name }}
+  namespace: {{ .Namespace }}
+  labels:
+    app.kubernetes.io/managed-by: open-service-broker-azure
+  annotations:
+    osba.azure.com/instance-id: {{ quote .InstanceID }}
+    osba.azure.com/binding-id: {{ quote .BindingID }}
+    osba.azure.com/service: {{ quote .ServiceName }}
+    osba.azure.com/plan: {{ quote .PlanName }}
+type: Opaque
+stringData:
+{{- range $key, $value := .Credentials }}
+  {{ quote $key }}: {{ quote $value }}
+{{- end }}
+`)
+
+var configMapTemplateBytes = []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Name }}
+  namespace: {{ .Namespace }}
+  labels:
+    app.kubernetes.io/managed-by: open-service-broker-azure
+data:
+  instanceId: {{ quote .InstanceID }}
+  bindingId: {{ quote .BindingID }}
+  service: {{ quote .ServiceName }}
+  plan: {{ quote .PlanName }}
+  resourceGroup: {{ quote .ResourceGroup }}
+  location: {{ quote .Location }}
+`)
+
+// credentialsProbe is given as the value of a credential when a template is
+// checked for whether it writes credentials into manifests
+const credentialsProbe = "osba-credentials-probe"
+
+// ManifestConfig represents how, and where in the repository, bindings'
+// manifests are written. A template, if given, is used in place of the
+// format's own. It is a Go template, which may use the functions of the sprig
+// library, rendered with a ManifestData.
+type ManifestConfig struct {
+	Format    string
+	Template  []byte
+	Directory string
+	Namespace string
+	// AllowPlaintextCredentials must be set for manifests that hold bindings'
+	// credentials, i.e. those of FormatSecret and of any template that uses
+	// them, to be written. Those credentials are committed in the clear and
+	// remain in the repository's history after the manifest is deleted.
+	AllowPlaintextCredentials bool
+}
+
+// ManifestData is what a binding's manifest is rendered from. Credentials are
+// flattened to strings; any that aren't strings already are given as JSON.
+type ManifestData struct {
+	Name          string
+	Namespace     string
+	InstanceID    string
+	BindingID     string
+	ServiceName   string
+	PlanName      string
+	ResourceGroup string
+	Location      string
+	Credentials   map[string]string
+}
+
+// ManifestWriter is an interface to be implemented by components that write
+// manifests describing bindings to a GitOps repository, so that the platforms
+// watching the repository pick up new bindings without being told of them
+type ManifestWriter interface {
+	// WriteManifest writes the manifest describing the given binding and
+	// returns where it was written
+	WriteManifest(
+		instance service.Instance,
+		bindingID string,
+		credentials service.Credentials,
+	) (*service.GitOpsManifest, error)
+	// DeleteManifest deletes the given manifest
+	DeleteManifest(manifest service.GitOpsManifest) error
+}
+
+type manifestWriter struct {
+	repository    Repository
+	templateBytes []byte
+	directory     string
+	namespace     string
+}
+
+// NewManifestWriter returns a ManifestWriter that commits each binding's
+// manifest to the given repository at
+// <directory>/<instanceID>/<bindingID>.yaml
+func NewManifestWriter(
+	repository Repository,
+	config ManifestConfig,
+) (ManifestWriter, error) {
+	templateBytes := config.Template
+	if len(templateBytes) == 0 {
+		switch config.Format {
+		case FormatSecret:
+			templateBytes = secretTemplateBytes
+		case FormatConfigMap:
+			templateBytes = configMapTemplateBytes
+		default:
+			return nil, fmt.Errorf(`unrecognized manifest format "%s"`, config.Format)
+		}
+	}
+	// The template is rendered once up front so that a broken one is caught
+	// before any binding depends on it
+	if _, err := template.Render(templateBytes, ManifestData{}); err != nil {
+		return nil, fmt.Errorf("invalid manifest template: %s", err)
+	}
+	if !config.AllowPlaintextCredentials && writesCredentials(templateBytes) {
+		return nil, errors.New(
+			"manifests would hold bindings' credentials in the clear; this must " +
+				"be allowed explicitly",
+		)
+	}
+	return &manifestWriter{
+		repository:    repository,
+		templateBytes: templateBytes,
+		directory:     strings.Trim(config.Directory, "/"),
+		namespace:     config.Namespace,
+	}, nil
+}
+
+func (m *manifestWriter) WriteManifest(
+	instance service.Instance,
+	bindingID string,
+	credentials service.Credentials,
+) (*service.GitOpsManifest, error) {
+	flattenedCredentials, err := flattenCredentials(credentials)
+	if err != nil {
+		return nil, err
+	}
+	data := ManifestData{
+		// Kubernetes object names must be lower case
+		Name:          "osba-" + strings.ToLower(bindingID),
+		Namespace:     m.namespace,
+		InstanceID:    instance.InstanceID,
+		BindingID:     bindingID,
+		ResourceGroup: instance.ResourceGroup,
+		Location:      instance.Location,
+		Credentials:   flattenedCredentials,
+	}
+	if instance.Service != nil {
+		data.ServiceName = instance.Service.GetName()
+	}
+	if instance.Plan != nil {
+		data.PlanName = instance.Plan.GetName()
+	}
+	manifest, err := template.Render(m.templateBytes, data)
+	if err != nil {
+		return nil, fmt.Errorf("error rendering manifest: %s", err)
+	}
+	manifestPath := path.Join(
+		m.directory,
+		instance.InstanceID,
+		bindingID+".yaml",
+	)
+	revision, err := m.repository.WriteFile(
+		manifestPath,
+		manifest,
+		fmt.Sprintf("Add binding %s to instance %s", bindingID, instance.InstanceID),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &service.GitOpsManifest{
+		Path:     manifestPath,
+		Revision: revision,
+	}, nil
+}
+
+func (m *manifestWriter) DeleteManifest(manifest service.GitOpsManifest) error {
+	return m.repository.DeleteFile(
+		manifest.Path,
+		fmt.Sprintf("Remove %s", manifest.Path),
+	)
+}
+
+// writesCredentials returns a bool indicating whether manifests rendered from
+// the given template hold bindings' credentials. Any template that refers to
+// them by name is assumed to, as is any that renders a credential given to it
+// by some other means, e.g. by rendering all of its data as JSON.
+func writesCredentials(templateBytes []byte) bool {
+	if bytes.Contains(templateBytes, []byte("Credentials")) {
+		return true
+	}
+	manifest, err := template.Render(
+		templateBytes,
+		ManifestData{
+			Credentials: map[string]string{credentialsProbe: credentialsProbe},
+		},
+	)
+	return err != nil || bytes.Contains(manifest, []byte(credentialsProbe))
+}
+
+func flattenCredentials(
+	credentials service.Credentials,
+) (map[string]string, error) {
+	flattened := map[string]string{}
+	if credentials == nil {
+		return flattened, nil
+	}
+	credentialsJSON, err := json.Marshal(credentials)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling credentials: %s", err)
+	}
+	credentialsMap := map[string]interface{}{}
+	if err := json.Unmarshal(credentialsJSON, &credentialsMap); err != nil {
+		return nil, fmt.Errorf("error unmarshaling credentials: %s", err)
+	}
+	for key, value := range credentialsMap {
+		if str, ok := value.(string); ok {
+			flattened[key] = str
+			continue
+		}
+		valueJSON, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling credential %s: %s", key, err)
+		}
+		flattened[key] = string(valueJSON)
+	}
+	return flattened, nil
+}
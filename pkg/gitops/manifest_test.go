@@ -0,0 +1,157 @@
+package gitops
+
+import (
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeRepository struct {
+	files map[string]string
+}
+
+func (f *fakeRepository) WriteFile(
+	path string,
+	content []byte,
+	_ string,
+) (string, error) {
+	f.files[path] = string(content)
+	return "abc", nil
+}
+
+func (f *fakeRepository) DeleteFile(path string, _ string) error {
+	delete(f.files, path)
+	return nil
+}
+
+type testCredentials struct {
+	Host  string `json:"host"`
+	Port  int    `json:"port"`
+	Empty string `json:"empty,omitempty"`
+}
+
+func TestNewManifestWriterWithUnrecognizedFormat(t *testing.T) {
+	_, err := NewManifestWriter(
+		&fakeRepository{},
+		ManifestConfig{Format: "helmrelease"},
+	)
+	assert.NotNil(t, err)
+}
+
+func TestNewManifestWriterWithInvalidTemplate(t *testing.T) {
+	_, err := NewManifestWriter(
+		&fakeRepository{},
+		ManifestConfig{Template: []byte("{{ .Name ")},
+	)
+	assert.NotNil(t, err)
+}
+
+func TestNewManifestWriterRequiresPlaintextOptIn(t *testing.T) {
+	for _, config := range []ManifestConfig{
+		{Format: FormatSecret},
+		{Template: []byte(`{{ index .Credentials "password" }}`)},
+		{Template: []byte(`{{ toJson . }}`)},
+	} {
+		_, err := NewManifestWriter(&fakeRepository{}, config)
+		assert.NotNil(t, err)
+		config.AllowPlaintextCredentials = true
+		_, err = NewManifestWriter(&fakeRepository{}, config)
+		assert.Nil(t, err)
+	}
+	_, err := NewManifestWriter(
+		&fakeRepository{},
+		ManifestConfig{Format: FormatConfigMap},
+	)
+	assert.Nil(t, err)
+}
+
+func TestWriteSecretManifest(t *testing.T) {
+	repository := &fakeRepository{files: map[string]string{}}
+	writer, err := NewManifestWriter(
+		repository,
+		ManifestConfig{
+			Format:                    FormatSecret,
+			Directory:                 "/osba/",
+			Namespace:                 "apps",
+			AllowPlaintextCredentials: true,
+		},
+	)
+	assert.Nil(t, err)
+	manifest, err := writer.WriteManifest(
+		service.Instance{InstanceID: "instance"},
+		"BINDING",
+		&testCredentials{Host: "example.com", Port: 5432},
+	)
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		&service.GitOpsManifest{
+			Path:     "osba/instance/BINDING.yaml",
+			Revision: "abc",
+		},
+		manifest,
+	)
+	assert.Equal(
+		t,
+		`apiVersion: v1
+kind: Secret
+metadata:
+  name: osba-binding
+  namespace: apps
+  labels:
+    app.kubernetes.io/managed-by: open-service-broker-azure
+  annotations:
+    osba.azure.com/instance-id: "instance"
+    osba.azure.com/binding-id: "BINDING"
+    osba.azure.com/service: ""
+    osba.azure.com/plan: ""
+type: Opaque
+stringData:
+  "host": "example.com"
+  "port": "5432"
+`,
+		repository.files[manifest.Path],
+	)
+	assert.Nil(t, writer.DeleteManifest(*manifest))
+	assert.Empty(t, repository.files)
+}
+
+func TestWriteConfigMapManifestHoldsNoCredentials(t *testing.T) {
+	repository := &fakeRepository{files: map[string]string{}}
+	writer, err := NewManifestWriter(
+		repository,
+		ManifestConfig{Format: FormatConfigMap},
+	)
+	assert.Nil(t, err)
+	manifest, err := writer.WriteManifest(
+		service.Instance{InstanceID: "instance", Location: "eastus"},
+		"binding",
+		&testCredentials{Host: "example.com"},
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, "instance/binding.yaml", manifest.Path)
+	assert.Contains(t, repository.files[manifest.Path], "kind: ConfigMap")
+	assert.Contains(t, repository.files[manifest.Path], `location: "eastus"`)
+	assert.NotContains(t, repository.files[manifest.Path], "example.com")
+}
+
+func TestWriteManifestFromTemplate(t *testing.T) {
+	repository := &fakeRepository{files: map[string]string{}}
+	writer, err := NewManifestWriter(
+		repository,
+		ManifestConfig{
+			Format:                    "ignored",
+			Template:                  []byte(`{{ .Name }}: {{ index .Credentials "host" }}`), // nolint: lll
+			AllowPlaintextCredentials: true,
+		},
+	)
+	assert.Nil(t, err)
+	manifest, err := writer.WriteManifest(
+		service.Instance{InstanceID: "instance"},
+		"binding",
+		&testCredentials{Host: "example.com"},
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, "osba-binding: example.com", repository.files[manifest.Path])
+}
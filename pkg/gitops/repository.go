@@ -0,0 +1,14 @@
+package gitops
+
+// Repository is an interface to be implemented by components that keep files
+// in a Git repository watched by a GitOps tool, such as Flux or Argo CD, that
+// applies the manifests it finds there to a cluster
+type Repository interface {
+	// WriteFile commits the given content to the file at the given path,
+	// creating the file or replacing whatever it held before, and returns the
+	// ID of the commit
+	WriteFile(path string, content []byte, message string) (string, error)
+	// DeleteFile commits the deletion of the file at the given path. A file that
+	// doesn't exist is already deleted, so deleting it is not an error.
+	DeleteFile(path string, message string) error
+}
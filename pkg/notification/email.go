@@ -0,0 +1,69 @@
+package notification
+
+import (
+	"bytes"
+	"fmt"
+	"net/smtp"
+	"strings"
+)
+
+// EmailConfig represents details for sending email through an SMTP server
+type EmailConfig struct {
+	Host     string   `json:"smtpHost"`
+	Port     int      `json:"smtpPort"`
+	Username string   `json:"smtpUsername"`
+	Password string   `json:"smtpPassword"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+}
+
+type emailChannel struct {
+	config EmailConfig
+}
+
+// NewEmailChannel returns a Channel that sends messages by email
+func NewEmailChannel(config EmailConfig) Channel {
+	return &emailChannel{
+		config: config,
+	}
+}
+
+func (e *emailChannel) Send(msg Message) error {
+	var auth smtp.Auth
+	if e.config.Username != "" {
+		auth = smtp.PlainAuth(
+			"",
+			e.config.Username,
+			e.config.Password,
+			e.config.Host,
+		)
+	}
+	if err := smtp.SendMail(
+		fmt.Sprintf("%s:%d", e.config.Host, e.config.Port),
+		auth,
+		e.config.From,
+		e.config.To,
+		e.buildEmail(msg),
+	); err != nil {
+		return fmt.Errorf("error sending email: %s", err)
+	}
+	return nil
+}
+
+func (e *emailChannel) buildEmail(msg Message) []byte {
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "From: %s\r\n", e.config.From)
+	fmt.Fprintf(buf, "To: %s\r\n", strings.Join(e.config.To, ", "))
+	// Subjects are rendered from templates and a stray newline would otherwise
+	// end the message's headers early
+	fmt.Fprintf(
+		buf,
+		"Subject: %s\r\n",
+		strings.Join(strings.Fields(msg.Subject), " "),
+	)
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.Replace(msg.Body, "\n", "\r\n", -1))
+	return buf.Bytes()
+}
@@ -0,0 +1,24 @@
+package notification
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildEmail(t *testing.T) {
+	e := &emailChannel{
+		config: EmailConfig{
+			From: "osba@example.com",
+			To:   []string{"ops@example.com", "dev@example.com"},
+		},
+	}
+	email := string(e.buildEmail(Message{
+		Subject: "multi-line\nsubject",
+		Body:    "line one\nline two",
+	}))
+	assert.Contains(t, email, "From: osba@example.com\r\n")
+	assert.Contains(t, email, "To: ops@example.com, dev@example.com\r\n")
+	assert.Contains(t, email, "Subject: multi-line subject\r\n")
+	assert.Contains(t, email, "\r\n\r\nline one\r\nline two")
+}
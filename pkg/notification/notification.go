@@ -0,0 +1,52 @@
+package notification
+
+import "time"
+
+const (
+	// StatusIdle is the status of notifications that an instance's underlying
+	// resources have been idle for as long as the broker is configured to
+	// tolerate
+	StatusIdle = "IDLE"
+	// StatusSuspended is the status of notifications that an idle instance's
+	// underlying resources were suspended
+	StatusSuspended = "SUSPENDED"
+	// StatusActivated is the status of notifications that an instance
+	// provisioned with deferred activation was activated as scheduled
+	StatusActivated = "ACTIVATED"
+	// StatusExpiring is the status of notifications that a sandbox instance
+	// will soon be deprovisioned because its lifespan is nearly over
+	StatusExpiring = "EXPIRING"
+	// StatusExpired is the status of notifications that a sandbox instance is
+	// being deprovisioned because its lifespan is over
+	StatusExpired = "EXPIRED"
+)
+
+// Notification describes the outcome of a provisioning operation, that an
+// instance was found to be idle, that an instance was activated, or that a
+// sandbox instance is expiring or has expired. Its status is that of the
+// instance or, if it concerns idleness, activation, or expiry, StatusIdle,
+// StatusSuspended, StatusActivated, StatusExpiring, or StatusExpired.
+type Notification struct {
+	InstanceID    string
+	ServiceID     string
+	ServiceName   string
+	PlanID        string
+	PlanName      string
+	Location      string
+	ResourceGroup string
+	Status        string
+	StatusReason  string
+	Time          time.Time
+}
+
+// Message is a notification formatted for delivery through a Channel
+type Message struct {
+	Subject string
+	Body    string
+}
+
+// Channel is an interface to be implemented by any component capable of
+// delivering messages to people (e.g. by email or chat)
+type Channel interface {
+	Send(Message) error
+}
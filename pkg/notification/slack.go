@@ -0,0 +1,25 @@
+package notification
+
+import "fmt"
+
+type slackChannel struct {
+	webhookURL string
+}
+
+// NewSlackChannel returns a Channel that posts messages to a Slack incoming
+// webhook
+func NewSlackChannel(webhookURL string) Channel {
+	return &slackChannel{
+		webhookURL: webhookURL,
+	}
+}
+
+func (s *slackChannel) Send(msg Message) error {
+	return postJSON(
+		s.webhookURL,
+		map[string]string{
+			// Slack renders text between asterisks in bold
+			"text": fmt.Sprintf("*%s*\n%s", msg.Subject, msg.Body),
+		},
+	)
+}
@@ -0,0 +1,225 @@
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"text/template"
+)
+
+const (
+	channelTypeEmail = "email"
+	channelTypeSlack = "slack"
+	channelTypeTeams = "teams"
+
+	defaultSubjectTemplate = `Instance {{ .InstanceID }} ` +
+		`{{ if eq .Status "PROVISIONED" }}provisioned` +
+		`{{ else if eq .Status "PROVISIONING_FAILED" }}failed to provision` +
+		`{{ else if eq .Status "IDLE" }}is idle` +
+		`{{ else if eq .Status "SUSPENDED" }}was suspended while idle` +
+		`{{ else if eq .Status "ACTIVATED" }}was activated` +
+		`{{ else if eq .Status "EXPIRING" }}is expiring` +
+		`{{ else if eq .Status "EXPIRED" }}expired and is being deprovisioned` +
+		`{{ else }}status changed to {{ .Status }}{{ end }}`
+	defaultBodyTemplate = `Service: {{ .ServiceName }} ({{ .ServiceID }})
+Plan: {{ .PlanName }} ({{ .PlanID }})
+Location: {{ .Location }}
+Resource group: {{ .ResourceGroup }}
+Status: {{ .Status }}
+{{- if .StatusReason }}
+Reason: {{ .StatusReason }}
+{{- end }}
+Time: {{ .Time.Format "2006-01-02T15:04:05Z07:00" }}`
+)
+
+// SubscriberConfig represents the configuration of a single subscriber. Which
+// fields apply depends on the type of the subscriber's channel-- email, slack,
+// or teams.
+type SubscriberConfig struct {
+	Name            string       `json:"name"`
+	Type            string       `json:"type"`
+	WebhookURL      string       `json:"webhookUrl"`
+	Email           *EmailConfig `json:"email"`
+	ServiceIDs      []string     `json:"serviceIds"`
+	Statuses        []string     `json:"statuses"`
+	SubjectTemplate string       `json:"subjectTemplate"`
+	BodyTemplate    string       `json:"bodyTemplate"`
+}
+
+// Subscriber is a channel along with the notifications that ought to be
+// delivered through it and the templates used to format them
+type Subscriber struct {
+	Name            string
+	Channel         Channel
+	serviceIDs      map[string]bool
+	statuses        map[string]bool
+	subjectTemplate *template.Template
+	bodyTemplate    *template.Template
+}
+
+// NewSubscribers returns Subscribers configured according to the given JSON
+// array of subscriber configurations
+func NewSubscribers(configJSON []byte) ([]Subscriber, error) {
+	configs := []SubscriberConfig{}
+	if err := json.Unmarshal(configJSON, &configs); err != nil {
+		return nil, fmt.Errorf(
+			"error unmarshaling notification subscribers: %s",
+			err,
+		)
+	}
+	subscribers := make([]Subscriber, len(configs))
+	usedNames := map[string]bool{}
+	for i, config := range configs {
+		if config.Name == "" {
+			return nil, errors.New("notification subscribers must be named")
+		}
+		if usedNames[config.Name] {
+			return nil, fmt.Errorf(
+				`more than one notification subscriber is named "%s"`,
+				config.Name,
+			)
+		}
+		usedNames[config.Name] = true
+		subscriber, err := NewSubscriber(config)
+		if err != nil {
+			return nil, err
+		}
+		subscribers[i] = subscriber
+	}
+	return subscribers, nil
+}
+
+// NewSubscriber returns a Subscriber configured according to the given
+// SubscriberConfig
+func NewSubscriber(config SubscriberConfig) (Subscriber, error) {
+	subscriber := Subscriber{
+		Name:       config.Name,
+		serviceIDs: toSet(config.ServiceIDs),
+		statuses:   toSet(config.Statuses),
+	}
+	switch config.Type {
+	case channelTypeEmail:
+		if config.Email == nil ||
+			config.Email.Host == "" ||
+			config.Email.From == "" ||
+			len(config.Email.To) == 0 {
+			return subscriber, fmt.Errorf(
+				`notification subscriber "%s" must specify an smtpHost, a from `+
+					`address, and at least one to address`,
+				config.Name,
+			)
+		}
+		email := *config.Email
+		if email.Port == 0 {
+			email.Port = 25
+		}
+		subscriber.Channel = NewEmailChannel(email)
+	case channelTypeSlack, channelTypeTeams:
+		if config.WebhookURL == "" {
+			return subscriber, fmt.Errorf(
+				`notification subscriber "%s" must specify a webhookUrl`,
+				config.Name,
+			)
+		}
+		if config.Type == channelTypeSlack {
+			subscriber.Channel = NewSlackChannel(config.WebhookURL)
+		} else {
+			subscriber.Channel = NewTeamsChannel(config.WebhookURL)
+		}
+	default:
+		return subscriber, fmt.Errorf(
+			`notification subscriber "%s" has unrecognized type "%s"`,
+			config.Name,
+			config.Type,
+		)
+	}
+	var err error
+	subscriber.subjectTemplate, err = parseTemplate(
+		config.Name,
+		"subject",
+		config.SubjectTemplate,
+		defaultSubjectTemplate,
+	)
+	if err != nil {
+		return subscriber, err
+	}
+	subscriber.bodyTemplate, err = parseTemplate(
+		config.Name,
+		"body",
+		config.BodyTemplate,
+		defaultBodyTemplate,
+	)
+	return subscriber, err
+}
+
+// Matches returns a bool indicating whether the given notification ought to be
+// delivered to the subscriber. A subscriber that names no service IDs or no
+// statuses is not filtered on that basis.
+func (s Subscriber) Matches(n Notification) bool {
+	if len(s.serviceIDs) > 0 && !s.serviceIDs[n.ServiceID] {
+		return false
+	}
+	if len(s.statuses) > 0 && !s.statuses[n.Status] {
+		return false
+	}
+	return true
+}
+
+// Format renders the given notification as a Message using the subscriber's
+// templates
+func (s Subscriber) Format(n Notification) (Message, error) {
+	subject, err := execute(s.subjectTemplate, n)
+	if err != nil {
+		return Message{}, err
+	}
+	body, err := execute(s.bodyTemplate, n)
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{
+		Subject: subject,
+		Body:    body,
+	}, nil
+}
+
+func parseTemplate(
+	subscriberName string,
+	templateName string,
+	text string,
+	defaultText string,
+) (*template.Template, error) {
+	if text == "" {
+		text = defaultText
+	}
+	tmpl, err := template.New(templateName).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing %s template for notification subscriber "%s": %s`,
+			templateName,
+			subscriberName,
+			err,
+		)
+	}
+	return tmpl, nil
+}
+
+func execute(tmpl *template.Template, n Notification) (string, error) {
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, n); err != nil {
+		return "", fmt.Errorf(
+			"error executing %s template: %s",
+			tmpl.Name(),
+			err,
+		)
+	}
+	return buf.String(), nil
+}
+
+func toSet(values []string) map[string]bool {
+	set := map[string]bool{}
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
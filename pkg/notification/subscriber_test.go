@@ -0,0 +1,170 @@
+package notification
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSubscribersWithInvalidConfig(t *testing.T) {
+	testCases := map[string]string{
+		"malformed JSON":   `[{`,
+		"unnamed":          `[{"type":"slack","webhookUrl":"https://example.com"}]`,
+		"unknown type":     `[{"name":"foo","type":"pager"}]`,
+		"missing webhook":  `[{"name":"foo","type":"teams"}]`,
+		"incomplete email": `[{"name":"foo","type":"email","email":{"smtpHost":"smtp"}}]`, // nolint: lll
+		"duplicate names": `[
+			{"name":"foo","type":"slack","webhookUrl":"https://example.com"},
+			{"name":"foo","type":"teams","webhookUrl":"https://example.com"}
+		]`,
+		"bad template": `[{
+			"name":"foo",
+			"type":"slack",
+			"webhookUrl":"https://example.com",
+			"subjectTemplate":"{{ .InstanceID"
+		}]`,
+	}
+	for name, configJSON := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewSubscribers([]byte(configJSON))
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+func TestNewSubscribers(t *testing.T) {
+	subscribers, err := NewSubscribers([]byte(`[
+		{"name":"ops-slack","type":"slack","webhookUrl":"https://example.com"},
+		{"name":"ops-teams","type":"teams","webhookUrl":"https://example.com"},
+		{
+			"name":"ops-email",
+			"type":"email",
+			"email":{
+				"smtpHost":"smtp",
+				"from":"osba@example.com",
+				"to":["ops@example.com"]
+			}
+		}
+	]`))
+	assert.Nil(t, err)
+	assert.Len(t, subscribers, 3)
+	assert.IsType(t, &slackChannel{}, subscribers[0].Channel)
+	assert.IsType(t, &teamsChannel{}, subscribers[1].Channel)
+	assert.IsType(t, &emailChannel{}, subscribers[2].Channel)
+	assert.Equal(t, 25, subscribers[2].Channel.(*emailChannel).config.Port)
+}
+
+func TestSubscriberMatches(t *testing.T) {
+	subscriber, err := NewSubscriber(SubscriberConfig{
+		Name:       "foo",
+		Type:       channelTypeSlack,
+		WebhookURL: "https://example.com",
+	})
+	assert.Nil(t, err)
+	n := Notification{
+		ServiceID: "service",
+		Status:    "PROVISIONED",
+	}
+	// With no filters, everything matches
+	assert.True(t, subscriber.Matches(n))
+	subscriber.serviceIDs = toSet([]string{"other-service"})
+	assert.False(t, subscriber.Matches(n))
+	subscriber.serviceIDs = toSet([]string{"other-service", "service"})
+	assert.True(t, subscriber.Matches(n))
+	subscriber.statuses = toSet([]string{"PROVISIONING_FAILED"})
+	assert.False(t, subscriber.Matches(n))
+	n.Status = "PROVISIONING_FAILED"
+	assert.True(t, subscriber.Matches(n))
+}
+
+func TestSubscriberFormatWithDefaultTemplates(t *testing.T) {
+	subscriber, err := NewSubscriber(SubscriberConfig{
+		Name:       "foo",
+		Type:       channelTypeSlack,
+		WebhookURL: "https://example.com",
+	})
+	assert.Nil(t, err)
+	n := Notification{
+		InstanceID:   "instance",
+		ServiceName:  "azure-foo",
+		Status:       "PROVISIONING_FAILED",
+		StatusReason: "quota exceeded",
+		Time:         time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	msg, err := subscriber.Format(n)
+	assert.Nil(t, err)
+	assert.Equal(t, "Instance instance failed to provision", msg.Subject)
+	assert.Contains(t, msg.Body, "Service: azure-foo")
+	assert.Contains(t, msg.Body, "Reason: quota exceeded")
+	assert.Contains(t, msg.Body, "Time: 2018-01-02T03:04:05Z")
+	n.Status = "PROVISIONED"
+	n.StatusReason = ""
+	msg, err = subscriber.Format(n)
+	assert.Nil(t, err)
+	assert.Equal(t, "Instance instance provisioned", msg.Subject)
+	assert.NotContains(t, msg.Body, "Reason:")
+	n.Status = StatusIdle
+	msg, err = subscriber.Format(n)
+	assert.Nil(t, err)
+	assert.Equal(t, "Instance instance is idle", msg.Subject)
+	n.Status = StatusSuspended
+	msg, err = subscriber.Format(n)
+	assert.Nil(t, err)
+	assert.Equal(t, "Instance instance was suspended while idle", msg.Subject)
+	n.Status = StatusActivated
+	msg, err = subscriber.Format(n)
+	assert.Nil(t, err)
+	assert.Equal(t, "Instance instance was activated", msg.Subject)
+	n.Status = "DEPROVISIONING"
+	msg, err = subscriber.Format(n)
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		"Instance instance status changed to DEPROVISIONING",
+		msg.Subject,
+	)
+}
+
+func TestSubscriberFormatsDefaultSubjectOfEachStatus(t *testing.T) {
+	subscriber, err := NewSubscriber(SubscriberConfig{
+		Name:       "foo",
+		Type:       channelTypeSlack,
+		WebhookURL: "https://example.com",
+	})
+	assert.Nil(t, err)
+	for status, expectedSubject := range map[string]string{
+		StatusIdle:      "Instance instance is idle",
+		StatusSuspended: "Instance instance was suspended while idle",
+		StatusActivated: "Instance instance was activated",
+		StatusExpiring:  "Instance instance is expiring",
+		StatusExpired:   "Instance instance expired and is being deprovisioned",
+	} {
+		msg, err := subscriber.Format(Notification{
+			InstanceID: "instance",
+			Status:     status,
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, expectedSubject, msg.Subject, status)
+	}
+}
+
+func TestSubscriberFormatWithCustomTemplates(t *testing.T) {
+	subscriber, err := NewSubscriber(SubscriberConfig{
+		Name:            "foo",
+		Type:            channelTypeTeams,
+		WebhookURL:      "https://example.com",
+		SubjectTemplate: "{{ .ServiceName }}: {{ .Status }}",
+		BodyTemplate:    "{{ .InstanceID }} in {{ .Location }}",
+	})
+	assert.Nil(t, err)
+	msg, err := subscriber.Format(Notification{
+		InstanceID:  "instance",
+		ServiceName: "azure-foo",
+		Location:    "eastus",
+		Status:      "PROVISIONED",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "azure-foo: PROVISIONED", msg.Subject)
+	assert.Equal(t, "instance in eastus", msg.Body)
+}
@@ -0,0 +1,26 @@
+package notification
+
+type teamsChannel struct {
+	webhookURL string
+}
+
+// NewTeamsChannel returns a Channel that posts messages, as message cards, to
+// a Microsoft Teams incoming webhook
+func NewTeamsChannel(webhookURL string) Channel {
+	return &teamsChannel{
+		webhookURL: webhookURL,
+	}
+}
+
+func (t *teamsChannel) Send(msg Message) error {
+	return postJSON(
+		t.webhookURL,
+		map[string]string{
+			"@type":    "MessageCard",
+			"@context": "https://schema.org/extensions",
+			"summary":  msg.Subject,
+			"title":    msg.Subject,
+			"text":     msg.Body,
+		},
+	)
+}
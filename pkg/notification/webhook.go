@@ -0,0 +1,36 @@
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const webhookTimeout = 30 * time.Second
+
+// postJSON POSTs the given body, marshaled as JSON, to the given incoming
+// webhook URL. Slack and Teams both acknowledge messages posted to their
+// incoming webhooks with a 200.
+func postJSON(url string, body interface{}) error {
+	bodyJSON, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("error marshaling message: %s", err)
+	}
+	client := &http.Client{
+		Timeout: webhookTimeout,
+	}
+	resp, err := client.Post(url, "application/json", bytes.NewBuffer(bodyJSON))
+	if err != nil {
+		return fmt.Errorf("error posting message: %s", err)
+	}
+	defer resp.Body.Close() // nolint: errcheck
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf(
+			"error posting message: unexpected status code %d",
+			resp.StatusCode,
+		)
+	}
+	return nil
+}
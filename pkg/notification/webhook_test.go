@@ -0,0 +1,58 @@
+package notification
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlackChannelSend(t *testing.T) {
+	var body map[string]string
+	server := getTestWebhookServer(t, http.StatusOK, &body)
+	defer server.Close()
+	err := NewSlackChannel(server.URL).Send(Message{
+		Subject: "subject",
+		Body:    "body",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "*subject*\nbody", body["text"])
+}
+
+func TestTeamsChannelSend(t *testing.T) {
+	var body map[string]string
+	server := getTestWebhookServer(t, http.StatusOK, &body)
+	defer server.Close()
+	err := NewTeamsChannel(server.URL).Send(Message{
+		Subject: "subject",
+		Body:    "body",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "MessageCard", body["@type"])
+	assert.Equal(t, "subject", body["title"])
+	assert.Equal(t, "body", body["text"])
+}
+
+func TestWebhookChannelSendWithErrorStatus(t *testing.T) {
+	var body map[string]string
+	server := getTestWebhookServer(t, http.StatusBadRequest, &body)
+	defer server.Close()
+	err := NewSlackChannel(server.URL).Send(Message{})
+	assert.NotNil(t, err)
+}
+
+func getTestWebhookServer(
+	t *testing.T,
+	statusCode int,
+	body *map[string]string,
+) *httptest.Server {
+	return httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			assert.Nil(t, json.NewDecoder(r.Body).Decode(body))
+			w.WriteHeader(statusCode)
+		}),
+	)
+}
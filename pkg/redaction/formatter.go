@@ -0,0 +1,39 @@
+package redaction
+
+import (
+	log "github.com/Sirupsen/logrus"
+)
+
+type formatter struct {
+	formatter log.Formatter
+	mode      Mode
+}
+
+// NewFormatter returns a log.Formatter that redacts, according to the given
+// mode, any secrets in the message and string (or error) fields of each entry
+// before formatting it with the given formatter
+func NewFormatter(f log.Formatter, mode Mode) log.Formatter {
+	return &formatter{
+		formatter: f,
+		mode:      mode,
+	}
+}
+
+func (f *formatter) Format(entry *log.Entry) ([]byte, error) {
+	// The entry's fields often belong to a map the caller goes on using, so
+	// they're redacted in a copy
+	redacted := *entry
+	redacted.Message = RedactText(entry.Message, f.mode)
+	redacted.Data = make(log.Fields, len(entry.Data))
+	for k, v := range entry.Data {
+		switch value := v.(type) {
+		case string:
+			redacted.Data[k] = RedactText(value, f.mode)
+		case error:
+			redacted.Data[k] = RedactText(value.Error(), f.mode)
+		default:
+			redacted.Data[k] = v
+		}
+	}
+	return f.formatter.Format(&redacted)
+}
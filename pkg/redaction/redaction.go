@@ -0,0 +1,139 @@
+package redaction
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Mode determines how secrets, such as passwords and keys embedded in
+// connection strings, appear wherever they are redacted
+type Mode string
+
+const (
+	// ModeFull replaces secrets entirely with RedactedValue
+	ModeFull Mode = "full"
+	// ModePartial masks all but the last few characters of secrets, which is
+	// often enough to tell which of several secrets was used
+	ModePartial Mode = "partial"
+	// ModeNone leaves secrets as they are. It is intended only for use in
+	// development environments.
+	ModeNone Mode = "none"
+)
+
+// RedactedValue is what a fully redacted secret is replaced with
+const RedactedValue = "REDACTED"
+
+const (
+	// partialVisibleChars is the number of trailing characters of a secret
+	// that ModePartial leaves visible
+	partialVisibleChars = 4
+	// partialMask replaces the rest of a partially masked secret. It is of a
+	// fixed length so as not to give away the secret's length.
+	partialMask = "****"
+)
+
+var (
+	// secretKeyPattern matches the names of the keys whose values are secret in
+	// the connection strings and parameters of the services the broker manages
+	secretKeyPattern = `(?:password|pwd|accountkey|sharedaccesskey|` +
+		`primarykey|secondarykey|accesskey|clientsecret|sig)`
+	// keyValueRegex matches secrets in key=value pairs, as found in
+	// semicolon-delimited connection strings and URL query strings
+	keyValueRegex = regexp.MustCompile(
+		`(?i)(\b` + secretKeyPattern + `\s*=\s*)([^;&\s"']+)`,
+	)
+	// jsonRegex matches secrets in (possibly quoted) JSON
+	jsonRegex = regexp.MustCompile(
+		`(?i)(\\?"` + secretKeyPattern + `\\?"\s*:\s*\\?")([^"\\]+)`,
+	)
+	// userInfoRegex matches passwords in the user info of URLs, as found in
+	// many PostgreSQL, MySQL, MongoDB, and Redis connection strings
+	userInfoRegex = regexp.MustCompile(
+		`(?i)(\b[a-z][a-z0-9+.-]*://[^:/@\s]*:)([^@\s/]+)(@)`,
+	)
+)
+
+// ParseMode returns the Mode with the given name
+func ParseMode(modeStr string) (Mode, error) {
+	mode := Mode(strings.ToLower(modeStr))
+	switch mode {
+	case ModeFull, ModePartial, ModeNone:
+		return mode, nil
+	}
+	return "", fmt.Errorf(
+		`unrecognized redaction mode "%s"; valid modes are "%s", "%s", and "%s"`,
+		modeStr,
+		ModeFull,
+		ModePartial,
+		ModeNone,
+	)
+}
+
+// Redact returns the given secret as it should appear under the given mode.
+// Secrets too short to partially mask without giving most of them away are
+// fully redacted instead.
+func Redact(secret string, mode Mode) string {
+	switch mode {
+	case ModeNone:
+		return secret
+	case ModePartial:
+		if len(secret) > 2*partialVisibleChars {
+			return partialMask + secret[len(secret)-partialVisibleChars:]
+		}
+	}
+	return RedactedValue
+}
+
+// RedactText returns a copy of the given free-form text, such as a log message
+// or an error returned by Azure, in which the secrets found in any connection
+// strings, URLs, or JSON it contains are redacted according to the given
+// mode
+func RedactText(text string, mode Mode) string {
+	if mode == ModeNone {
+		return text
+	}
+	replace := func(re *regexp.Regexp, text string) string {
+		return re.ReplaceAllStringFunc(text, func(match string) string {
+			groups := re.FindStringSubmatch(match)
+			groups[2] = Redact(groups[2], mode)
+			return strings.Join(groups[1:], "")
+		})
+	}
+	text = replace(keyValueRegex, text)
+	text = replace(jsonRegex, text)
+	return replace(userInfoRegex, text)
+}
+
+// RedactParameters returns a copy of the given parameter map in which every
+// string value, including those of nested maps and lists, is redacted as by
+// RedactText
+func RedactParameters(
+	params map[string]interface{},
+	mode Mode,
+) map[string]interface{} {
+	if params == nil || mode == ModeNone {
+		return params
+	}
+	redacted := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		redacted[k] = redactValue(v, mode)
+	}
+	return redacted
+}
+
+func redactValue(value interface{}, mode Mode) interface{} {
+	switch v := value.(type) {
+	case string:
+		return RedactText(v, mode)
+	case map[string]interface{}:
+		return RedactParameters(v, mode)
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, elem := range v {
+			redacted[i] = redactValue(elem, mode)
+		}
+		return redacted
+	}
+	return value
+}
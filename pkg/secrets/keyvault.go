@@ -0,0 +1,92 @@
+package secrets
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/dataplane/keyvault"
+	"github.com/Azure/go-autorest/autorest/azure"
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+const (
+	credentialsContentType = "application/json"
+	// secretNamePrefix distinguishes the secrets the broker writes from any
+	// others kept in the same vault
+	secretNamePrefix = "osba-binding-"
+)
+
+type keyVaultStore struct {
+	vaultBaseURL string
+	client       keyvault.ManagementClient
+}
+
+// NewKeyVaultStore returns a Store that keeps each binding's credentials, as a
+// JSON document, in a secret of the named Azure Key Vault. The secret is named
+// for the binding and tagged with its instance and binding IDs. The broker's
+// service principal must be permitted to set secrets in the vault.
+func NewKeyVaultStore(vaultName string) (Store, error) {
+	azureConfig, err := az.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	azureEnvironment, err := azure.EnvironmentFromName(azureConfig.Environment)
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error parsing Azure environment name "%s"`,
+			azureConfig.Environment,
+		)
+	}
+	authorizer, err := az.GetBearerTokenAuthorizerForResource(
+		azureEnvironment,
+		azureConfig.TenantID,
+		azureConfig.ClientID,
+		azureConfig.ClientSecret,
+		strings.TrimSuffix(azureEnvironment.KeyVaultEndpoint, "/"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error getting bearer token authorizer: %s", err)
+	}
+	client := keyvault.New()
+	client.Authorizer = authorizer
+	return &keyVaultStore{
+		vaultBaseURL: fmt.Sprintf(
+			"https://%s.%s",
+			vaultName,
+			azureEnvironment.KeyVaultDNSSuffix,
+		),
+		client: client,
+	}, nil
+}
+
+func (k *keyVaultStore) WriteCredentials(
+	instanceID string,
+	bindingID string,
+	credentials service.Credentials,
+) error {
+	credentialsJSON, err := json.Marshal(credentials)
+	if err != nil {
+		return fmt.Errorf("error marshaling credentials: %s", err)
+	}
+	value := string(credentialsJSON)
+	contentType := credentialsContentType
+	tags := map[string]*string{
+		"instanceId": &instanceID,
+		"bindingId":  &bindingID,
+	}
+	secretName := secretNamePrefix + bindingID
+	if _, err := k.client.SetSecret(
+		k.vaultBaseURL,
+		secretName,
+		keyvault.SecretSetParameters{
+			Value:       &value,
+			ContentType: &contentType,
+			Tags:        &tags,
+		},
+	); err != nil {
+		return fmt.Errorf(`error setting secret "%s": %s`, secretName, err)
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package secrets
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// Store is an interface to be implemented by components that hold bindings'
+// credentials on behalf of the applications that consume them. The broker
+// delivers each credential it regenerates outside of a binding request to the
+// store, since there is no platform request through which it could be
+// returned.
+type Store interface {
+	// WriteCredentials stores the given binding's current credentials,
+	// replacing any that were stored for it before
+	WriteCredentials(
+		instanceID string,
+		bindingID string,
+		credentials service.Credentials,
+	) error
+}
@@ -0,0 +1,23 @@
+package service
+
+import "time"
+
+// ActivationState records the progress of an instance that was provisioned
+// with deferred activation
+type ActivationState struct {
+	// ActivateAt is when the instance's underlying resources are scheduled to
+	// be activated. It may be brought forward or pushed back by updating the
+	// instance until activation has happened.
+	ActivateAt time.Time `json:"activateAt"`
+	// Deactivated indicates whether the instance's underlying resources were
+	// put into their inactive state once provisioning completed
+	Deactivated bool       `json:"deactivated"`
+	Activated   bool       `json:"activated"`
+	ActivatedAt *time.Time `json:"activatedAt,omitempty"`
+}
+
+// IsPendingActivation returns a bool indicating whether the instance was
+// provisioned with deferred activation and has yet to be activated
+func (i Instance) IsPendingActivation() bool {
+	return i.Activation != nil && !i.Activation.Activated
+}
@@ -0,0 +1,83 @@
+package service
+
+import "time"
+
+// ApprovalPolicy identifies the plans whose instances must be approved by an
+// external approval service before they are provisioned, and the estimated
+// monthly costs above which instances of any plan must be. The zero value
+// requires approval of no instances.
+type ApprovalPolicy struct {
+	planKeys map[string]bool
+	// costThreshold, if positive, is the estimated monthly cost above which new
+	// instances of any plan require approval
+	costThreshold float64
+	// costThresholdsByOrg are keyed by organization GUID and override
+	// costThreshold for instances in those organizations
+	costThresholdsByOrg map[string]float64
+}
+
+// NewApprovalPolicy returns an ApprovalPolicy that requires approval of
+// instances of the given plans, which are identified as serviceName/planName,
+// and of instances whose estimated monthly cost exceeds the cost threshold of
+// the organization they're provisioned in. Organizations without a threshold
+// of their own are subject to the given default threshold. A threshold of zero
+// means there is none.
+func NewApprovalPolicy(
+	planKeys []string,
+	costThreshold float64,
+	costThresholdsByOrg map[string]float64,
+) ApprovalPolicy {
+	approvalPolicy := ApprovalPolicy{
+		planKeys:            map[string]bool{},
+		costThreshold:       costThreshold,
+		costThresholdsByOrg: costThresholdsByOrg,
+	}
+	for _, planKey := range planKeys {
+		approvalPolicy.planKeys[planKey] = true
+	}
+	return approvalPolicy
+}
+
+// IsApprovalRequired returns a bool indicating whether new instances of the
+// given plan of the given service must be approved before they are
+// provisioned
+func (a ApprovalPolicy) IsApprovalRequired(svc Service, plan Plan) bool {
+	return a.planKeys[GetPlanKey(svc, plan)]
+}
+
+// GetExceededCostThreshold returns the cost threshold of the given organization
+// if the given estimated cost of a new instance in that organization exceeds
+// it, along with a bool indicating whether it does. An instance whose cost
+// couldn't be estimated exceeds no threshold.
+func (a ApprovalPolicy) GetExceededCostThreshold(
+	organizationGUID string,
+	costEstimate *CostEstimate,
+) (float64, bool) {
+	if costEstimate == nil {
+		return 0, false
+	}
+	threshold, ok := a.costThresholdsByOrg[organizationGUID]
+	if !ok {
+		threshold = a.costThreshold
+	}
+	if threshold <= 0 || costEstimate.MonthlyCost <= threshold {
+		return 0, false
+	}
+	return threshold, true
+}
+
+// ApprovalState records the progress of an external approval service's
+// decision about provisioning an instance. ID is assigned by the approval
+// service to a request whose decision is pending. An instance that hasn't been
+// decided upon by the deadline is denied.
+type ApprovalState struct {
+	RequestedAt time.Time  `json:"requestedAt"`
+	Deadline    time.Time  `json:"deadline"`
+	ID          string     `json:"id,omitempty"`
+	Decision    string     `json:"decision,omitempty"`
+	Reason      string     `json:"reason,omitempty"`
+	DecidedAt   *time.Time `json:"decidedAt,omitempty"`
+	// CostThreshold is the cost threshold that the instance's estimated monthly
+	// cost exceeded, if that's why it required approval
+	CostThreshold float64 `json:"costThreshold,omitempty"`
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApprovalPolicyIsApprovalRequired(t *testing.T) {
+	svc := NewService(&ServiceProperties{Name: "svc"}, nil)
+	plan := NewPlan(&PlanProperties{Name: "plan"})
+	otherPlan := NewPlan(&PlanProperties{Name: "other"})
+
+	assert.False(t, ApprovalPolicy{}.IsApprovalRequired(svc, plan))
+
+	approvalPolicy := NewApprovalPolicy([]string{"svc/plan"}, 0, nil)
+	assert.True(t, approvalPolicy.IsApprovalRequired(svc, plan))
+	assert.False(t, approvalPolicy.IsApprovalRequired(svc, otherPlan))
+}
+
+func TestApprovalPolicyGetExceededCostThreshold(t *testing.T) {
+	approvalPolicy := NewApprovalPolicy(
+		nil,
+		100,
+		map[string]float64{
+			"frugal":    10,
+			"unlimited": 0,
+		},
+	)
+	costEstimate := &CostEstimate{MonthlyCost: 50}
+
+	_, ok := approvalPolicy.GetExceededCostThreshold("org", costEstimate)
+	assert.False(t, ok)
+	threshold, ok := approvalPolicy.GetExceededCostThreshold(
+		"frugal",
+		costEstimate,
+	)
+	assert.True(t, ok)
+	assert.Equal(t, float64(10), threshold)
+	_, ok = approvalPolicy.GetExceededCostThreshold("frugal", nil)
+	assert.False(t, ok)
+
+	costEstimate.MonthlyCost = 500
+	threshold, ok = approvalPolicy.GetExceededCostThreshold("org", costEstimate)
+	assert.True(t, ok)
+	assert.Equal(t, float64(100), threshold)
+	_, ok = approvalPolicy.GetExceededCostThreshold("unlimited", costEstimate)
+	assert.False(t, ok)
+}
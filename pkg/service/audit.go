@@ -0,0 +1,70 @@
+package service
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/Azure/open-service-broker-azure/pkg/redaction"
+)
+
+// RedactedValue replaces the values of secret parameters wherever parameters
+// are recorded for auditing purposes. Since audit records are persisted,
+// secrets are always fully redacted from them, whatever redaction mode the
+// broker is configured with.
+const RedactedValue = redaction.RedactedValue
+
+// ProvisioningAudit records the provisioning parameters that were requested
+// for an instance alongside those it was effectively provisioned with, after
+// defaults were applied and parameters were normalized. Values of secret
+// parameters are redacted from both.
+type ProvisioningAudit struct {
+	RequestedParameters map[string]interface{} `json:"requestedParameters"`
+	EffectiveParameters map[string]interface{} `json:"effectiveParameters"`
+}
+
+// RedactProvisioningParameters returns a copy of the given parameter map in
+// which the value of every parameter that is tagged `secret:"true"` in the
+// given (module-specific) provisioning parameters type is replaced with
+// RedactedValue. Parameters of nested types, and lists thereof, are redacted
+// likewise.
+func RedactProvisioningParameters(
+	params map[string]interface{},
+	pp ProvisioningParameters,
+) map[string]interface{} {
+	// Redaction can't fail
+	redacted, _ := mapSecrets(
+		params,
+		reflect.TypeOf(pp),
+		func(interface{}) (interface{}, error) {
+			return RedactedValue, nil
+		},
+	)
+	return redacted
+}
+
+// RedactSecrets returns the given parameters or details, which must marshal
+// to a JSON object, as a map in which the value of every field that is tagged
+// `secret:"true"` in their type is replaced with RedactedValue. Fields of
+// nested types, and lists thereof, are redacted likewise. Nil is returned for
+// nil.
+func RedactSecrets(v interface{}) (map[string]interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	jsonBytes, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+	if err = unmarshalPreservingNumbers(jsonBytes, &fields); err != nil {
+		return nil, err
+	}
+	// Redaction can't fail
+	return mapSecrets(
+		fields,
+		reflect.TypeOf(v),
+		func(interface{}) (interface{}, error) {
+			return RedactedValue, nil
+		},
+	)
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type auditTestParameters struct {
+	Name     string                      `json:"name"`
+	Password string                      `json:"password" secret:"true"`
+	Nested   *auditTestNestedParameters  `json:"nested"`
+	List     []auditTestNestedParameters `json:"list"`
+	Ignored  string                      `json:"-"`
+}
+
+type auditTestNestedParameters struct {
+	Key   string `json:"key,omitempty" secret:"true"`
+	Value string `json:"value"`
+}
+
+func TestRedactProvisioningParameters(t *testing.T) {
+	params := map[string]interface{}{
+		"name":     "foo",
+		"password": "bar",
+		"nested": map[string]interface{}{
+			"key":   "baz",
+			"value": "bat",
+		},
+		"location": "eastus",
+	}
+	redacted := RedactProvisioningParameters(params, &auditTestParameters{})
+	assert.Equal(
+		t,
+		map[string]interface{}{
+			"name":     "foo",
+			"password": RedactedValue,
+			"nested": map[string]interface{}{
+				"key":   RedactedValue,
+				"value": "bat",
+			},
+			"location": "eastus",
+		},
+		redacted,
+	)
+	// The original parameters are left as they were
+	assert.Equal(t, "bar", params["password"])
+	assert.Equal(
+		t,
+		"baz",
+		params["nested"].(map[string]interface{})["key"],
+	)
+}
+
+func TestRedactProvisioningParametersInLists(t *testing.T) {
+	params := map[string]interface{}{
+		"list": []interface{}{
+			map[string]interface{}{
+				"key":   "foo",
+				"value": "bar",
+			},
+			map[string]interface{}{
+				"key": "baz",
+			},
+		},
+	}
+	redacted := RedactProvisioningParameters(params, &auditTestParameters{})
+	assert.Equal(
+		t,
+		map[string]interface{}{
+			"list": []interface{}{
+				map[string]interface{}{
+					"key":   RedactedValue,
+					"value": "bar",
+				},
+				map[string]interface{}{
+					"key": RedactedValue,
+				},
+			},
+		},
+		redacted,
+	)
+	// The original parameters are left as they were
+	assert.Equal(
+		t,
+		"foo",
+		params["list"].([]interface{})[0].(map[string]interface{})["key"],
+	)
+}
+
+func TestRedactProvisioningParametersWithNilParameters(t *testing.T) {
+	assert.Nil(
+		t,
+		RedactProvisioningParameters(nil, &auditTestParameters{}),
+	)
+}
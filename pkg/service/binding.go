@@ -7,18 +7,25 @@ import (
 	"github.com/Azure/open-service-broker-azure/pkg/crypto"
 )
 
-// Binding represents a binding to a service
+// Binding represents a binding to a service. Its parameters and details are
+// each stored either encrypted whole, in the corresponding Encrypted* field, or
+// with only their secret fields encrypted, in the corresponding FieldEncrypted*
+// field.
 type Binding struct {
-	BindingID                  string            `json:"bindingId"`
-	InstanceID                 string            `json:"instanceId"`
-	ServiceID                  string            `json:"serviceId"`
-	EncryptedBindingParameters []byte            `json:"bindingParameters"`
-	BindingParameters          BindingParameters `json:"-"`
-	Status                     string            `json:"status"`
-	StatusReason               string            `json:"statusReason"`
-	EncryptedDetails           []byte            `json:"details"`
-	Details                    BindingDetails    `json:"-"`
-	Created                    time.Time         `json:"created"`
+	BindingID                       string              `json:"bindingId"`
+	InstanceID                      string              `json:"instanceId"`
+	ServiceID                       string              `json:"serviceId"`
+	EncryptedBindingParameters      []byte              `json:"bindingParameters"`
+	FieldEncryptedBindingParameters json.RawMessage     `json:"fieldEncryptedBindingParameters,omitempty"` // nolint: lll
+	BindingParameters               BindingParameters   `json:"-"`
+	Status                          string              `json:"status"`
+	StatusReason                    string              `json:"statusReason"`
+	EncryptedDetails                []byte              `json:"details"`
+	FieldEncryptedDetails           json.RawMessage     `json:"fieldEncryptedDetails,omitempty"` // nolint: lll
+	Details                         BindingDetails      `json:"-"`
+	Created                         time.Time           `json:"created"`
+	CredentialRotation              *CredentialRotation `json:"credentialRotation,omitempty"` // nolint: lll
+	GitOpsManifest                  *GitOpsManifest     `json:"gitOpsManifest,omitempty"`     // nolint: lll
 }
 
 // NewBindingFromJSON returns a new Binding unmarshalled from the provided JSON
@@ -57,20 +64,16 @@ func (b Binding) encrypt(codec crypto.Codec) (Binding, error) {
 }
 
 func (b Binding) encryptBindingParameters(codec crypto.Codec) (Binding, error) {
-	jsonBytes, err := json.Marshal(b.BindingParameters)
-	if err != nil {
-		return b, err
-	}
-	b.EncryptedBindingParameters, err = codec.Encrypt(jsonBytes)
+	var err error
+	b.EncryptedBindingParameters, b.FieldEncryptedBindingParameters, err =
+		encryptValue(b.BindingParameters, codec)
 	return b, err
 }
 
 func (b Binding) encryptDetails(codec crypto.Codec) (Binding, error) {
-	jsonBytes, err := json.Marshal(b.Details)
-	if err != nil {
-		return b, err
-	}
-	b.EncryptedDetails, err = codec.Encrypt(jsonBytes)
+	var err error
+	b.EncryptedDetails, b.FieldEncryptedDetails, err =
+		encryptValue(b.Details, codec)
 	return b, err
 }
 
@@ -83,25 +86,25 @@ func (b Binding) decrypt(codec crypto.Codec) (Binding, error) {
 }
 
 func (b Binding) decryptBindingParameters(codec crypto.Codec) (Binding, error) {
-	if len(b.EncryptedBindingParameters) == 0 ||
-		b.BindingParameters == nil {
+	if b.BindingParameters == nil {
 		return b, nil
 	}
-	plaintext, err := codec.Decrypt(b.EncryptedBindingParameters)
-	if err != nil {
-		return b, err
-	}
-	return b, json.Unmarshal(plaintext, b.BindingParameters)
+	return b, decryptValue(
+		b.EncryptedBindingParameters,
+		b.FieldEncryptedBindingParameters,
+		b.BindingParameters,
+		codec,
+	)
 }
 
 func (b Binding) decryptDetails(codec crypto.Codec) (Binding, error) {
-	if len(b.EncryptedDetails) == 0 ||
-		b.Details == nil {
+	if b.Details == nil {
 		return b, nil
 	}
-	plaintext, err := codec.Decrypt(b.EncryptedDetails)
-	if err != nil {
-		return b, err
-	}
-	return b, json.Unmarshal(plaintext, b.Details)
+	return b, decryptValue(
+		b.EncryptedDetails,
+		b.FieldEncryptedDetails,
+		b.Details,
+		codec,
+	)
 }
@@ -0,0 +1,64 @@
+package service
+
+import "time"
+
+const (
+	// BlueGreenPhaseProvisioningGreen is the phase of a blue-green update in
+	// which the replacement ("green") instance is being provisioned
+	BlueGreenPhaseProvisioningGreen = "PROVISIONING_GREEN"
+	// BlueGreenPhaseValidatingGreen is the phase of a blue-green update in which
+	// the green instance is being validated before anything is cut over to it
+	BlueGreenPhaseValidatingGreen = "VALIDATING_GREEN"
+	// BlueGreenPhaseCuttingOver is the phase of a blue-green update in which the
+	// instance's bindings are being cut over to the green instance
+	BlueGreenPhaseCuttingOver = "CUTTING_OVER"
+	// BlueGreenPhaseTearingDownBlue is the phase of a blue-green update in which
+	// the original ("blue") instance's underlying resources are being
+	// deprovisioned
+	BlueGreenPhaseTearingDownBlue = "TEARING_DOWN_BLUE"
+	// BlueGreenPhaseRollingBack is the phase of a failed blue-green update in
+	// which the green instance is being deprovisioned, leaving the blue instance
+	// as it was
+	BlueGreenPhaseRollingBack = "ROLLING_BACK"
+)
+
+// BlueGreenState records the progress of a blue-green update, i.e. one that
+// replaces an instance's underlying resources instead of modifying them in
+// place. It is carried by the instance being updated. The resources not in
+// service are represented by instances that only the broker knows about: the
+// green instance, until its resources are cut over to, and the retired blue
+// instance, from then until its resources are torn down.
+type BlueGreenState struct {
+	Phase   string    `json:"phase"`
+	Started time.Time `json:"started"`
+	// CutOverBindingIDs lists the bindings that have already been cut over to
+	// the green instance, so that an interrupted cut over can be resumed or
+	// reversed
+	CutOverBindingIDs []string `json:"cutOverBindingIds,omitempty"`
+	// RollbackReason is why the green instance is being rolled back
+	RollbackReason string `json:"rollbackReason,omitempty"`
+}
+
+// IsBindingCutOver returns a bool indicating whether the specified binding
+// has already been cut over to the green instance
+func (b *BlueGreenState) IsBindingCutOver(bindingID string) bool {
+	for _, id := range b.CutOverBindingIDs {
+		if id == bindingID {
+			return true
+		}
+	}
+	return false
+}
+
+// GetGreenInstanceID returns the ID of the instance that the green resources
+// of a blue-green update of the specified instance are provisioned into
+func GetGreenInstanceID(instanceID string) string {
+	return instanceID + "-green"
+}
+
+// GetRetiredBlueInstanceID returns the ID of the instance that the blue
+// resources of a blue-green update of the specified instance are moved to once
+// they are replaced, until they are torn down
+func GetRetiredBlueInstanceID(instanceID string) string {
+	return instanceID + "-blue"
+}
@@ -33,6 +33,12 @@ type ServiceProperties struct { // nolint: golint
 	// to match the spec
 	ParentServiceID string `json:"-"`
 	ChildServiceID  string `json:"-"`
+	// Locations optionally restricts the Azure regions in which a service may
+	// be provisioned. If empty, any valid Azure region is permitted.
+	Locations []string `json:"-"`
+	// Cloneable indicates whether new instances of a service may be provisioned
+	// using an existing instance's configuration as a starting point
+	Cloneable bool `json:"-"`
 }
 
 // Service is an interface to be implemented by types that represent a single
@@ -47,6 +53,8 @@ type Service interface {
 	GetPlan(planID string) (Plan, bool)
 	GetParentServiceID() string
 	GetChildServiceID() string
+	IsAvailableInLocation(location string) bool
+	IsCloneable() bool
 }
 
 type service struct {
@@ -240,6 +248,22 @@ func (s *service) GetChildServiceID() string {
 	return s.ChildServiceID
 }
 
+func (s *service) IsCloneable() bool {
+	return s.Cloneable
+}
+
+func (s *service) IsAvailableInLocation(location string) bool {
+	if len(s.Locations) == 0 {
+		return true
+	}
+	for _, l := range s.Locations {
+		if l == location {
+			return true
+		}
+	}
+	return false
+}
+
 // NewPlan initializes and returns a new Plan
 func NewPlan(planProperties *PlanProperties) Plan {
 	return &plan{
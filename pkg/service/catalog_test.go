@@ -107,3 +107,16 @@ func TestGetNonExistingPlanByID(t *testing.T) {
 func TestGetExistingPlanByID(t *testing.T) {
 
 }
+
+func TestServiceIsAvailableInLocation(t *testing.T) {
+	svc := NewService(&ServiceProperties{}, nil)
+	assert.True(t, svc.IsAvailableInLocation("eastus"))
+	svc = NewService(
+		&ServiceProperties{
+			Locations: []string{"eastus", "westus"},
+		},
+		nil,
+	)
+	assert.True(t, svc.IsAvailableInLocation("westus"))
+	assert.False(t, svc.IsAvailableInLocation("southcentralus"))
+}
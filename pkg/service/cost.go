@@ -0,0 +1,121 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// PricingTableEntry is the estimated monthly cost of one unit of a SKU. Every
+// field but SKU (and MonthlyCost) may be left empty, in which case the entry
+// applies to any service, plan, or region, as applicable. An entry with an
+// empty SKU is the cost of a single instance of any plan it applies to whose
+// ServiceManager doesn't implement the SKUMapper interface.
+type PricingTableEntry struct {
+	Service     string  `json:"service"`
+	Plan        string  `json:"plan"`
+	SKU         string  `json:"sku"`
+	Region      string  `json:"region"`
+	MonthlyCost float64 `json:"monthlyCost"`
+}
+
+// PricingTable is an operator-maintained table of estimated monthly costs,
+// used to estimate what newly provisioned instances will cost. Prices are not
+// retrieved from Azure.
+type PricingTable struct {
+	currency string
+	entries  []PricingTableEntry
+}
+
+// NewPricingTable returns a new PricingTable whose prices are in the given
+// currency and whose entries are unmarshalled from the provided JSON array
+func NewPricingTable(currency string, jsonBytes []byte) (*PricingTable, error) {
+	entries := []PricingTableEntry{}
+	if err := json.Unmarshal(jsonBytes, &entries); err != nil {
+		return nil, fmt.Errorf("error unmarshaling pricing table: %s", err)
+	}
+	if currency == "" {
+		return nil, errors.New("pricing table currency must be specified")
+	}
+	for i, entry := range entries {
+		if entry.MonthlyCost < 0 {
+			return nil, fmt.Errorf(
+				"pricing table entry at index %d has a negative monthlyCost",
+				i,
+			)
+		}
+	}
+	return &PricingTable{
+		currency: currency,
+		entries:  entries,
+	}, nil
+}
+
+// GetCurrency returns the currency the pricing table's prices are in
+func (p *PricingTable) GetCurrency() string {
+	return p.currency
+}
+
+// GetEntries returns all of the pricing table's entries
+func (p *PricingTable) GetEntries() []PricingTableEntry {
+	return p.entries
+}
+
+// GetMonthlyCost returns the estimated monthly cost of one unit of the given
+// SKU when used by the given service and plan in the given region, along with
+// a bool indicating whether the pricing table has an entry that applies. When
+// more than one entry applies, the most specific one is used.
+func (p *PricingTable) GetMonthlyCost(
+	serviceName string,
+	planName string,
+	sku string,
+	region string,
+) (float64, bool) {
+	var match *PricingTableEntry
+	matchSpecificity := -1
+	for i, entry := range p.entries {
+		if entry.SKU != sku ||
+			(entry.Service != "" && entry.Service != serviceName) ||
+			(entry.Plan != "" && entry.Plan != planName) ||
+			(entry.Region != "" && entry.Region != region) {
+			continue
+		}
+		specificity := 0
+		for _, field := range []string{entry.Service, entry.Plan, entry.Region} {
+			if field != "" {
+				specificity++
+			}
+		}
+		if specificity > matchSpecificity {
+			match = &p.entries[i]
+			matchSpecificity = specificity
+		}
+	}
+	if match == nil {
+		return 0, false
+	}
+	return match.MonthlyCost, true
+}
+
+// SKUUsage represents the quantity of a single SKU an instance uses, in
+// whatever units the SKU is priced per (e.g. virtual machines, or GB of disk)
+type SKUUsage struct {
+	SKU      string
+	Quantity float64
+}
+
+// CostEstimateItem is the estimated monthly cost of a single SKU an instance
+// uses
+type CostEstimateItem struct {
+	SKU         string  `json:"sku,omitempty"`
+	Quantity    float64 `json:"quantity"`
+	MonthlyCost float64 `json:"monthlyCost"`
+}
+
+// CostEstimate is the estimated monthly cost of an instance as it was
+// provisioned
+type CostEstimate struct {
+	MonthlyCost float64            `json:"monthlyCost"`
+	Currency    string             `json:"currency"`
+	Items       []CostEstimateItem `json:"items"`
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPricingTableWithInvalidJSON(t *testing.T) {
+	_, err := NewPricingTable("USD", []byte(`{"sku": "small"}`))
+	assert.NotNil(t, err)
+}
+
+func TestNewPricingTableWithNegativeCost(t *testing.T) {
+	_, err := NewPricingTable(
+		"USD",
+		[]byte(`[{"sku": "small", "monthlyCost": -1}]`),
+	)
+	assert.NotNil(t, err)
+}
+
+func TestPricingTableGetMonthlyCostPrefersMostSpecificEntry(t *testing.T) {
+	pt, err := NewPricingTable(
+		"USD",
+		[]byte(`[
+			{"sku": "small", "monthlyCost": 10},
+			{"sku": "small", "region": "eastus", "monthlyCost": 12},
+			{
+				"service": "azure-thing",
+				"plan": "basic",
+				"sku": "small",
+				"region": "eastus",
+				"monthlyCost": 15
+			},
+			{"service": "azure-thing", "plan": "basic", "monthlyCost": 99}
+		]`),
+	)
+	assert.Nil(t, err)
+	cost, ok := pt.GetMonthlyCost("azure-other", "basic", "small", "westus")
+	assert.True(t, ok)
+	assert.Equal(t, float64(10), cost)
+	cost, ok = pt.GetMonthlyCost("azure-other", "basic", "small", "eastus")
+	assert.True(t, ok)
+	assert.Equal(t, float64(12), cost)
+	cost, ok = pt.GetMonthlyCost("azure-thing", "basic", "small", "eastus")
+	assert.True(t, ok)
+	assert.Equal(t, float64(15), cost)
+	// Entries for specific SKUs never stand in for an entry for the plan alone
+	cost, ok = pt.GetMonthlyCost("azure-thing", "basic", "", "eastus")
+	assert.True(t, ok)
+	assert.Equal(t, float64(99), cost)
+	_, ok = pt.GetMonthlyCost("azure-other", "basic", "large", "eastus")
+	assert.False(t, ok)
+}
@@ -0,0 +1,28 @@
+package service
+
+import "time"
+
+// CredentialRotation records the schedule on which a binding's credential is
+// regenerated. Bindings that opted out of rotation, or whose services can't
+// rotate credentials, have none.
+type CredentialRotation struct {
+	// TTL is how long each credential issued to the binding remains in use
+	// before it is replaced
+	TTL time.Duration `json:"ttl"`
+	// NextRotation is when the binding's current credential is next due to be
+	// replaced
+	NextRotation time.Time `json:"nextRotation"`
+	// LastRotated is when the binding's credential was last replaced, if it
+	// ever has been
+	LastRotated *time.Time `json:"lastRotated,omitempty"`
+	// DeliveryPending indicates that the binding's current credential has yet
+	// to be delivered to the broker's secret store
+	DeliveryPending bool `json:"deliveryPending,omitempty"`
+}
+
+// IsCredentialRotationDue returns a bool indicating whether the binding's
+// credential is due to be replaced as of the given time
+func (b Binding) IsCredentialRotationDue(now time.Time) bool {
+	return b.CredentialRotation != nil &&
+		!now.Before(b.CredentialRotation.NextRotation)
+}
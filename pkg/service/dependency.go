@@ -0,0 +1,45 @@
+package service
+
+// Dependency describes an instance that another instance was provisioned to
+// depend upon, as it's made available to the dependent instance's
+// provisioning steps. A module can use it, for instance, to configure an app
+// with the endpoint of a database that was provisioned separately.
+type Dependency struct {
+	InstanceID     string `json:"instanceId"`
+	ServiceID      string `json:"serviceId"`
+	PlanID         string `json:"planId"`
+	Location       string `json:"location"`
+	ResourceGroup  string `json:"resourceGroup"`
+	SubscriptionID string `json:"subscriptionId,omitempty"`
+	// Outputs are the depended upon instance's details, keyed as they are
+	// persisted. The values of secret details are replaced with RedactedValue;
+	// a dependent instance that needs credentials should obtain them by binding
+	// to the instance it depends upon.
+	Outputs map[string]interface{} `json:"outputs"`
+}
+
+// NewDependency returns a description of the given instance, as a dependency
+// of another instance
+func NewDependency(instance Instance) (Dependency, error) {
+	dependency := Dependency{
+		InstanceID:     instance.InstanceID,
+		ServiceID:      instance.ServiceID,
+		PlanID:         instance.PlanID,
+		Location:       instance.Location,
+		ResourceGroup:  instance.ResourceGroup,
+		SubscriptionID: instance.SubscriptionID,
+		Outputs:        map[string]interface{}{},
+	}
+	if instance.Details == nil {
+		return dependency, nil
+	}
+	outputs, err := RedactSecrets(instance.Details)
+	if err != nil {
+		return dependency, err
+	}
+	dependency.Outputs = outputs
+	if dependency.Outputs == nil {
+		dependency.Outputs = map[string]interface{}{}
+	}
+	return dependency, nil
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type dependencyTestDetails struct {
+	Endpoint string `json:"endpoint"`
+	Password string `json:"password" secret:"true"`
+}
+
+func TestNewDependencyRedactsSecretDetails(t *testing.T) {
+	dependency, err := NewDependency(Instance{
+		InstanceID:    "foo",
+		ServiceID:     "bar",
+		ResourceGroup: "baz",
+		Details: &dependencyTestDetails{
+			Endpoint: "https://foo.example.com",
+			Password: "secret",
+		},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "foo", dependency.InstanceID)
+	assert.Equal(t, "baz", dependency.ResourceGroup)
+	assert.Equal(
+		t,
+		map[string]interface{}{
+			"endpoint": "https://foo.example.com",
+			"password": RedactedValue,
+		},
+		dependency.Outputs,
+	)
+}
+
+func TestNewDependencyWithoutDetails(t *testing.T) {
+	dependency, err := NewDependency(Instance{InstanceID: "foo"})
+	assert.Nil(t, err)
+	assert.Empty(t, dependency.Outputs)
+}
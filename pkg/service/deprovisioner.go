@@ -23,8 +23,9 @@ type DeprovisioningStep interface {
 }
 
 type deprovisioningStep struct {
-	name string
-	fn   DeprovisioningStepFunction
+	name         string
+	fn           DeprovisioningStepFunction
+	dependencies []string
 }
 
 // Deprovisioner is an interface to be implemented by types that model a
@@ -33,12 +34,19 @@ type Deprovisioner interface {
 	GetFirstStepName() (string, bool)
 	GetStep(name string) (DeprovisioningStep, bool)
 	GetNextStepName(name string) (string, bool)
+	// GetStepDependencies returns the names of the steps that delete resources
+	// depending on whatever the named step deletes. All of them are executed
+	// before the named step.
+	GetStepDependencies(name string) []string
 }
 
 type deprovisioner struct {
 	firstStepName string
 	steps         map[string]DeprovisioningStep
 	nextSteps     map[string]string
+	// dependencies is keyed by step name and indicates which other steps must
+	// be executed before a given step
+	dependencies map[string][]string
 }
 
 // NewDeprovisioningStep returns a new DeprovisioningStep
@@ -52,6 +60,25 @@ func NewDeprovisioningStep(
 	}
 }
 
+// NewDeprovisioningStepWithDependencies returns a new DeprovisioningStep that
+// deletes something other resources depend on (e.g. a subnet that a private
+// endpoint is attached to). The named steps, which delete those dependents,
+// are executed before it, wherever they are declared in the chain. Azure can
+// report a dependent deleted before it has let go of what it depended on, so
+// the broker retries a step with dependencies that fails because what it
+// deletes is still in use.
+func NewDeprovisioningStepWithDependencies(
+	name string,
+	fn DeprovisioningStepFunction,
+	dependencies ...string,
+) DeprovisioningStep {
+	return &deprovisioningStep{
+		name:         name,
+		fn:           fn,
+		dependencies: dependencies,
+	}
+}
+
 // GetName returns a deprovisioning step's name
 func (d *deprovisioningStep) GetName() string {
 	return d.name
@@ -70,35 +97,88 @@ func (d *deprovisioningStep) Execute(
 	)
 }
 
-// NewDeprovisioner returns a new deprovisioner
+// NewDeprovisioner returns a new deprovisioner. Steps are executed in the
+// order given, except that a step with dependencies is deferred until all the
+// steps it depends on have been executed.
 func NewDeprovisioner(steps ...DeprovisioningStep) (Deprovisioner, error) {
 	d := &deprovisioner{
-		steps:     make(map[string]DeprovisioningStep),
-		nextSteps: make(map[string]string),
+		steps:        make(map[string]DeprovisioningStep),
+		nextSteps:    make(map[string]string),
+		dependencies: make(map[string][]string),
+	}
+	for _, step := range steps {
+		_, ok := d.steps[step.GetName()]
+		if ok {
+			// This means a duplicate step name has been detected. This is a serious
+			// problem.
+			return nil, fmt.Errorf(
+				`duplicate step name "%s" detected`,
+				step.GetName(),
+			)
+		}
+		d.steps[step.GetName()] = step
+		if s, ok := step.(*deprovisioningStep); ok {
+			d.dependencies[step.GetName()] = s.dependencies
+		}
 	}
-	if len(steps) > 0 {
-		d.firstStepName = steps[0].GetName()
-		var lastStep DeprovisioningStep
-		for _, step := range steps {
-			_, ok := d.steps[step.GetName()]
-			if ok {
-				// This means a duplicate step name has been detected. This is a serious
-				// problem.
+	for stepName, dependencies := range d.dependencies {
+		for _, dependency := range dependencies {
+			if _, ok := d.steps[dependency]; !ok {
 				return nil, fmt.Errorf(
-					`duplicate step name "%s" detected`,
-					step.GetName(),
+					`step "%s" depends on unknown step "%s"`,
+					stepName,
+					dependency,
 				)
 			}
-			d.steps[step.GetName()] = step
-			if lastStep != nil {
-				d.nextSteps[lastStep.GetName()] = step.GetName()
+		}
+	}
+	// Repeatedly take the first remaining step whose dependencies have all been
+	// taken already. If no remaining step qualifies, the dependencies form a
+	// cycle.
+	executed := map[string]bool{}
+	remaining := steps
+	var lastStep DeprovisioningStep
+	for len(remaining) > 0 {
+		i := 0
+		for ; i < len(remaining); i++ {
+			if d.dependenciesExecuted(remaining[i].GetName(), executed) {
+				break
 			}
-			lastStep = step
 		}
+		if i == len(remaining) {
+			return nil, fmt.Errorf(
+				`step "%s" depends, directly or indirectly, on itself`,
+				remaining[0].GetName(),
+			)
+		}
+		step := remaining[i]
+		remaining = append(
+			append([]DeprovisioningStep{}, remaining[:i]...),
+			remaining[i+1:]...,
+		)
+		executed[step.GetName()] = true
+		if lastStep == nil {
+			d.firstStepName = step.GetName()
+		} else {
+			d.nextSteps[lastStep.GetName()] = step.GetName()
+		}
+		lastStep = step
 	}
 	return d, nil
 }
 
+func (d *deprovisioner) dependenciesExecuted(
+	stepName string,
+	executed map[string]bool,
+) bool {
+	for _, dependency := range d.dependencies[stepName] {
+		if !executed[dependency] {
+			return false
+		}
+	}
+	return true
+}
+
 // GetFirstStepName retrieves the name of the first step in the chain
 func (d *deprovisioner) GetFirstStepName() (string, bool) {
 	return d.firstStepName, (d.firstStepName != "")
@@ -116,3 +196,9 @@ func (d *deprovisioner) GetNextStepName(name string) (string, bool) {
 	nextStepName, ok := d.nextSteps[name]
 	return nextStepName, ok
 }
+
+// GetStepDependencies returns the names of the steps that must be executed
+// before the named step
+func (d *deprovisioner) GetStepDependencies(name string) []string {
+	return d.dependencies[name]
+}
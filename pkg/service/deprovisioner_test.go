@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDeprovisionerDefersStepsUntilDependenciesExecuted(t *testing.T) {
+	// "subnet" can't be deleted until "privateEndpoint" has been, and "vnet"
+	// can't be deleted until "subnet" has been
+	d, err := NewDeprovisioner(
+		NewDeprovisioningStep("deleteARMDeployment", noopDeprovisioningStep),
+		NewDeprovisioningStepWithDependencies(
+			"vnet",
+			noopDeprovisioningStep,
+			"subnet",
+		),
+		NewDeprovisioningStepWithDependencies(
+			"subnet",
+			noopDeprovisioningStep,
+			"privateEndpoint",
+		),
+		NewDeprovisioningStep("privateEndpoint", noopDeprovisioningStep),
+	)
+	assert.Nil(t, err)
+	stepNames := []string{}
+	stepName, ok := d.GetFirstStepName()
+	for ok {
+		stepNames = append(stepNames, stepName)
+		stepName, ok = d.GetNextStepName(stepName)
+	}
+	assert.Equal(
+		t,
+		[]string{"deleteARMDeployment", "privateEndpoint", "subnet", "vnet"},
+		stepNames,
+	)
+	assert.Equal(t, []string{"privateEndpoint"}, d.GetStepDependencies("subnet"))
+	assert.Empty(t, d.GetStepDependencies("privateEndpoint"))
+}
+
+func TestNewDeprovisionerRejectsDependencyOnUnknownStep(t *testing.T) {
+	_, err := NewDeprovisioner(
+		NewDeprovisioningStep("first", noopDeprovisioningStep),
+		NewDeprovisioningStepWithDependencies(
+			"second",
+			noopDeprovisioningStep,
+			"bogus",
+		),
+	)
+	assert.NotNil(t, err)
+}
+
+func TestNewDeprovisionerRejectsDependencyCycle(t *testing.T) {
+	_, err := NewDeprovisioner(
+		NewDeprovisioningStepWithDependencies(
+			"first",
+			noopDeprovisioningStep,
+			"second",
+		),
+		NewDeprovisioningStepWithDependencies(
+			"second",
+			noopDeprovisioningStep,
+			"first",
+		),
+	)
+	assert.NotNil(t, err)
+	_, err = NewDeprovisioner(
+		NewDeprovisioningStepWithDependencies(
+			"first",
+			noopDeprovisioningStep,
+			"first",
+		),
+	)
+	assert.NotNil(t, err)
+}
+
+func noopDeprovisioningStep(
+	context.Context,
+	Instance,
+) (InstanceDetails, error) {
+	return nil, nil
+}
@@ -0,0 +1,37 @@
+package service
+
+import "time"
+
+// Drift describes one way in which an instance's underlying resources, as they
+// currently are in Azure, differ from the state the broker put them in, e.g. a
+// firewall rule that was changed through the portal
+type Drift struct {
+	// Resource names the resource that has drifted, e.g. "server"
+	Resource string `json:"resource"`
+	// Property names the setting that has drifted, e.g. "sslEnforcement"
+	Property string `json:"property"`
+	Expected string `json:"expected"`
+	// Actual is empty if the setting, or the whole resource, no longer exists
+	Actual string `json:"actual,omitempty"`
+}
+
+// DriftState records the outcome of the most recent check of whether an
+// instance's underlying resources have drifted from the state the broker put
+// them in
+type DriftState struct {
+	CheckedAt time.Time `json:"checkedAt"`
+	// Drifts are the differences the most recent check found, if any
+	Drifts []Drift `json:"drifts,omitempty"`
+	// DetectedAt is when the drift was first found, if checks have found drift
+	// ever since
+	DetectedAt *time.Time `json:"detectedAt,omitempty"`
+	// CorrectedAt is when the broker last began re-applying the instance's
+	// desired state, if it has ever done so
+	CorrectedAt *time.Time `json:"correctedAt,omitempty"`
+}
+
+// HasDrifted returns a bool indicating whether the most recent check of the
+// instance found drift
+func (i Instance) HasDrifted() bool {
+	return i.Drift != nil && len(i.Drift.Drifts) > 0
+}
@@ -1,6 +1,9 @@
 package service
 
-import "fmt"
+import (
+	"fmt"
+	"net/http"
+)
 
 // ValidationError represents an error validating requestParameters. This
 // specific error type should be used to allow the broker's framework to
@@ -22,3 +25,83 @@ func NewValidationError(field, issue string) *ValidationError {
 func (e *ValidationError) Error() string {
 	return fmt.Sprintf("Error validating field '%s': %s", e.Field, e.Issue)
 }
+
+// ErrorCategory represents a broad class of errors that can be handled alike.
+// The broker consults the category of an error returned from an asynchronous
+// step to decide whether that step is worth retrying.
+type ErrorCategory string
+
+const (
+	// ErrorCategoryUnknown represents errors that have not been categorized
+	ErrorCategoryUnknown ErrorCategory = "unknown"
+	// ErrorCategoryThrottled represents errors resulting from a request being
+	// refused (e.g. HTTP 429) because too many requests were made
+	ErrorCategoryThrottled ErrorCategory = "throttled"
+	// ErrorCategoryTransient represents errors that are likely to resolve on
+	// their own (e.g. HTTP 5xx)
+	ErrorCategoryTransient ErrorCategory = "transient"
+	// ErrorCategoryInvalid represents errors resulting from a request that can
+	// never succeed as made (e.g. HTTP 4xx)
+	ErrorCategoryInvalid ErrorCategory = "invalid"
+	// ErrorCategoryInUse represents errors resulting from an attempt to delete
+	// a resource that other resources still depend on
+	ErrorCategoryInUse ErrorCategory = "inuse"
+)
+
+// CategorizedError wraps an error with its ErrorCategory
+type CategorizedError struct {
+	Category ErrorCategory
+	Err      error
+}
+
+// NewCategorizedError returns a new CategorizedError wrapping the given error
+func NewCategorizedError(category ErrorCategory, err error) *CategorizedError {
+	return &CategorizedError{
+		Category: category,
+		Err:      err,
+	}
+}
+
+func (e *CategorizedError) Error() string {
+	return e.Err.Error()
+}
+
+// GetErrorCategory returns the ErrorCategory of the given error.
+// ValidationErrors are always ErrorCategoryInvalid and other errors that were
+// never categorized are ErrorCategoryUnknown.
+func GetErrorCategory(err error) ErrorCategory {
+	switch e := err.(type) {
+	case *CategorizedError:
+		return e.Category
+	case *ValidationError:
+		return ErrorCategoryInvalid
+	default:
+		return ErrorCategoryUnknown
+	}
+}
+
+// GetErrorCategoryForStatusCode returns the ErrorCategory for an error
+// resulting from an HTTP response with the given status code
+func GetErrorCategoryForStatusCode(statusCode int) ErrorCategory {
+	switch {
+	case statusCode == http.StatusTooManyRequests:
+		return ErrorCategoryThrottled
+	case statusCode >= 500:
+		return ErrorCategoryTransient
+	case statusCode >= 400:
+		return ErrorCategoryInvalid
+	default:
+		return ErrorCategoryUnknown
+	}
+}
+
+// WrapError returns an error whose message prefixes the given error's message
+// with msg. The new error shares the given error's category.
+func WrapError(err error, msg string) error {
+	wrapped := fmt.Errorf("%s: %s", msg, err)
+	category := GetErrorCategory(err)
+	if category == ErrorCategoryUnknown {
+		return wrapped
+	}
+	return NewCategorizedError(category, wrapped)
+}
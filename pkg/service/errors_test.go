@@ -0,0 +1,43 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetErrorCategoryForStatusCode(t *testing.T) {
+	assert.Equal(
+		t,
+		ErrorCategoryThrottled,
+		GetErrorCategoryForStatusCode(http.StatusTooManyRequests),
+	)
+	assert.Equal(
+		t,
+		ErrorCategoryTransient,
+		GetErrorCategoryForStatusCode(http.StatusServiceUnavailable),
+	)
+	assert.Equal(
+		t,
+		ErrorCategoryInvalid,
+		GetErrorCategoryForStatusCode(http.StatusBadRequest),
+	)
+	assert.Equal(
+		t,
+		ErrorCategoryUnknown,
+		GetErrorCategoryForStatusCode(http.StatusOK),
+	)
+}
+
+func TestWrapErrorPreservesCategory(t *testing.T) {
+	err := WrapError(
+		NewCategorizedError(ErrorCategoryThrottled, errors.New("slow down")),
+		"error deploying ARM template",
+	)
+	assert.Equal(t, ErrorCategoryThrottled, GetErrorCategory(err))
+	assert.Equal(t, "error deploying ARM template: slow down", err.Error())
+	err = WrapError(errors.New("oops"), "error deploying ARM template")
+	assert.Equal(t, ErrorCategoryUnknown, GetErrorCategory(err))
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"fmt"
+	"time"
+)
+
+// ExpiryPolicy identifies the plans whose instances may be provisioned as
+// sandbox instances, which are deprovisioned automatically once their
+// lifespan has elapsed, and bounds how long they may live. The zero value
+// permits sandbox instances of no plan.
+type ExpiryPolicy struct {
+	lifespansByPlan map[string]time.Duration
+	maxLifespan     time.Duration
+	warningPeriod   time.Duration
+}
+
+// NewExpiryPolicy returns an ExpiryPolicy that permits sandbox instances of
+// the given plans, which are identified as serviceName/planName, each with the
+// lifespan it is mapped to. A sandbox instance's expiry may be put off, but it
+// may never live longer than the given maximum lifespan. Subscribers are
+// warned of an instance's impending expiry the given warning period before it
+// expires. A warning period of zero means no warning is given.
+func NewExpiryPolicy(
+	lifespansByPlan map[string]time.Duration,
+	maxLifespan time.Duration,
+	warningPeriod time.Duration,
+) (ExpiryPolicy, error) {
+	if warningPeriod < 0 {
+		return ExpiryPolicy{}, fmt.Errorf(
+			"warning period may not be negative: %s",
+			warningPeriod,
+		)
+	}
+	for plan, lifespan := range lifespansByPlan {
+		if lifespan <= 0 {
+			return ExpiryPolicy{}, fmt.Errorf(
+				`lifespan for plan "%s" must be positive: %s`,
+				plan,
+				lifespan,
+			)
+		}
+		if lifespan > maxLifespan {
+			return ExpiryPolicy{}, fmt.Errorf(
+				`lifespan for plan "%s" exceeds the maximum lifespan of %s: %s`,
+				plan,
+				maxLifespan,
+				lifespan,
+			)
+		}
+	}
+	return ExpiryPolicy{
+		lifespansByPlan: lifespansByPlan,
+		maxLifespan:     maxLifespan,
+		warningPeriod:   warningPeriod,
+	}, nil
+}
+
+// GetPlanKeys returns the serviceName/planName keys of all plans whose
+// instances may be provisioned as sandbox instances
+func (e ExpiryPolicy) GetPlanKeys() []string {
+	keys := make([]string, 0, len(e.lifespansByPlan))
+	for key := range e.lifespansByPlan {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// GetLifespan returns how long a sandbox instance of the given plan of the
+// given service lives, along with a bool indicating whether instances of that
+// plan may be provisioned as sandbox instances at all
+func (e ExpiryPolicy) GetLifespan(
+	svc Service,
+	plan Plan,
+) (time.Duration, bool) {
+	lifespan, ok := e.lifespansByPlan[GetPlanKey(svc, plan)]
+	return lifespan, ok
+}
+
+// GetMaxLifespan returns the longest any sandbox instance may live, however
+// often its expiry is put off
+func (e ExpiryPolicy) GetMaxLifespan() time.Duration {
+	return e.maxLifespan
+}
+
+// GetWarningPeriod returns how long before a sandbox instance expires
+// subscribers are warned that it will, along with a bool indicating whether
+// they are warned at all
+func (e ExpiryPolicy) GetWarningPeriod() (time.Duration, bool) {
+	return e.warningPeriod, e.warningPeriod > 0
+}
+
+// ExpiryState records when a sandbox instance is due to be deprovisioned
+type ExpiryState struct {
+	// ExpiresAt may be put off by updating the instance, up to the maximum
+	// lifespan permitted when the instance was provisioned
+	ExpiresAt time.Time `json:"expiresAt"`
+	// MaxExpiresAt is the latest ExpiresAt may be put off until
+	MaxExpiresAt time.Time `json:"maxExpiresAt"`
+	// WarnedAt is when subscribers were warned of the instance's impending
+	// expiry. It is cleared whenever expiry is put off, so that subscribers are
+	// warned again ahead of the new time.
+	WarnedAt  *time.Time `json:"warnedAt,omitempty"`
+	ExpiredAt *time.Time `json:"expiredAt,omitempty"`
+}
+
+// IsSandbox returns a bool indicating whether the instance was provisioned as
+// a sandbox instance that expires
+func (i Instance) IsSandbox() bool {
+	return i.Expiry != nil
+}
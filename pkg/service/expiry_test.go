@@ -0,0 +1,53 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewExpiryPolicyWithInvalidConfig(t *testing.T) {
+	_, err := NewExpiryPolicy(nil, 24*time.Hour, -time.Hour)
+	assert.NotNil(t, err)
+	_, err = NewExpiryPolicy(
+		map[string]time.Duration{"svc/plan": 0},
+		24*time.Hour,
+		time.Hour,
+	)
+	assert.NotNil(t, err)
+	// No plan's lifespan may exceed the maximum, or its instances could never
+	// be provisioned
+	_, err = NewExpiryPolicy(
+		map[string]time.Duration{"svc/plan": 48 * time.Hour},
+		24*time.Hour,
+		time.Hour,
+	)
+	assert.NotNil(t, err)
+}
+
+func TestExpiryPolicyGetLifespan(t *testing.T) {
+	svc := NewService(&ServiceProperties{Name: "svc"}, nil)
+	plan := NewPlan(&PlanProperties{Name: "plan"})
+	otherPlan := NewPlan(&PlanProperties{Name: "other"})
+
+	_, ok := ExpiryPolicy{}.GetLifespan(svc, plan)
+	assert.False(t, ok)
+	_, ok = ExpiryPolicy{}.GetWarningPeriod()
+	assert.False(t, ok)
+
+	policy, err := NewExpiryPolicy(
+		map[string]time.Duration{"svc/plan": 8 * time.Hour},
+		24*time.Hour,
+		time.Hour,
+	)
+	assert.Nil(t, err)
+	lifespan, ok := policy.GetLifespan(svc, plan)
+	assert.True(t, ok)
+	assert.Equal(t, 8*time.Hour, lifespan)
+	_, ok = policy.GetLifespan(svc, otherPlan)
+	assert.False(t, ok)
+	warningPeriod, ok := policy.GetWarningPeriod()
+	assert.True(t, ok)
+	assert.Equal(t, time.Hour, warningPeriod)
+}
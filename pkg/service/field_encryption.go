@@ -0,0 +1,264 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/crypto"
+)
+
+// encryptValue encrypts the given value whole or, if the codec calls for it,
+// returns a JSON representation of the value in which only the fields tagged
+// `secret:"true"` in its (module-specific) type are encrypted. Exactly one of
+// the two return values is non-nil.
+func encryptValue(
+	v interface{},
+	codec crypto.Codec,
+) ([]byte, json.RawMessage, error) {
+	if fieldCodec, ok := codec.(crypto.FieldCodec); ok &&
+		fieldCodec.EncryptsFields() {
+		fields, err := encryptFields(v, fieldCodec)
+		return nil, fields, err
+	}
+	jsonBytes, err := json.Marshal(v)
+	if err != nil {
+		return nil, nil, err
+	}
+	ciphertext, err := codec.Encrypt(jsonBytes)
+	return ciphertext, nil, err
+}
+
+// decryptValue decrypts into v a value that was encrypted by encryptValue in
+// either of the ways it can encrypt one. Values that were written before
+// field-level encryption was enabled, or after it was disabled, therefore
+// remain readable.
+func decryptValue(
+	ciphertext []byte,
+	fields json.RawMessage,
+	v interface{},
+	codec crypto.Codec,
+) error {
+	if v == nil {
+		return nil
+	}
+	if len(fields) > 0 {
+		fieldCodec, ok := codec.(crypto.FieldCodec)
+		if !ok {
+			return errors.New(
+				"value was encrypted field by field, but the codec cannot decrypt " +
+					"fields",
+			)
+		}
+		return decryptFields(fields, v, fieldCodec)
+	}
+	if len(ciphertext) == 0 {
+		return nil
+	}
+	plaintext, err := codec.Decrypt(ciphertext)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(plaintext, v)
+}
+
+func encryptFields(
+	v interface{},
+	codec crypto.FieldCodec,
+) (json.RawMessage, error) {
+	jsonBytes, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+	if err = unmarshalPreservingNumbers(jsonBytes, &fields); err != nil {
+		return nil, err
+	}
+	encrypted, err := mapSecrets(
+		fields,
+		reflect.TypeOf(v),
+		func(value interface{}) (interface{}, error) {
+			plaintext, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			return codec.EncryptField(plaintext)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(encrypted)
+}
+
+func decryptFields(
+	fieldsJSON json.RawMessage,
+	v interface{},
+	codec crypto.FieldCodec,
+) error {
+	fields := map[string]interface{}{}
+	if err := unmarshalPreservingNumbers(fieldsJSON, &fields); err != nil {
+		return err
+	}
+	decrypted, err := mapSecrets(
+		fields,
+		reflect.TypeOf(v),
+		func(value interface{}) (interface{}, error) {
+			// An encrypted field is always an object. Anything else was persisted
+			// before its field was tagged as secret, and is left as it is until
+			// it's next written.
+			if _, ok := value.(map[string]interface{}); !ok {
+				return value, nil
+			}
+			fieldJSON, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			field := crypto.EncryptedField{}
+			if err = json.Unmarshal(fieldJSON, &field); err != nil {
+				return nil, err
+			}
+			plaintext, err := codec.DecryptField(field)
+			if err != nil {
+				return nil, err
+			}
+			var decryptedValue interface{}
+			err = unmarshalPreservingNumbers(plaintext, &decryptedValue)
+			return decryptedValue, err
+		},
+	)
+	if err != nil {
+		return err
+	}
+	jsonBytes, err := json.Marshal(decrypted)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(jsonBytes, v)
+}
+
+// unmarshalPreservingNumbers unmarshals JSON into generic maps and slices
+// without first converting numbers to float64, which could lose precision
+func unmarshalPreservingNumbers(jsonBytes []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(jsonBytes))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+// mapSecrets returns a copy of the given field map in which the value of every
+// field that is tagged `secret:"true"` in the given type is replaced with the
+// result of applying fn to it. Fields of nested types, and lists thereof, are
+// treated likewise, as are the fields of embedded types.
+func mapSecrets(
+	fields map[string]interface{},
+	t reflect.Type,
+	fn func(interface{}) (interface{}, error),
+) (map[string]interface{}, error) {
+	return mapTagged(fields, t, "secret", fn)
+}
+
+// mapTagged is the generalization of mapSecrets to fields tagged
+// `<tag>:"true"` for any tag
+func mapTagged(
+	fields map[string]interface{},
+	t reflect.Type,
+	tag string,
+	fn func(interface{}) (interface{}, error),
+) (map[string]interface{}, error) {
+	if fields == nil {
+		return nil, nil
+	}
+	mapped := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		mapped[k] = v
+	}
+	return mapped, mapTaggedFields(mapped, t, tag, fn)
+}
+
+// mapTaggedFields is the in-place counterpart to mapTagged
+func mapTaggedFields(
+	fields map[string]interface{},
+	t reflect.Type,
+	tag string,
+	fn func(interface{}) (interface{}, error),
+) error {
+	if t == nil {
+		return nil
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			// The fields of embedded types are marshaled as though they belonged
+			// to the embedding type
+			if field.Anonymous {
+				if err := mapTaggedFields(fields, field.Type, tag, fn); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		value, ok := fields[name]
+		if !ok {
+			continue
+		}
+		var err error
+		if field.Tag.Get(tag) == "true" {
+			fields[name], err = fn(value)
+		} else {
+			fields[name], err = mapTaggedInValue(value, field.Type, tag, fn)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mapTaggedInValue applies fn to the tagged fields in the value of a single,
+// untagged field of the given type, descending into nested field maps and
+// into lists thereof
+func mapTaggedInValue(
+	value interface{},
+	t reflect.Type,
+	tag string,
+	fn func(interface{}) (interface{}, error),
+) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return mapTagged(v, t, tag, fn)
+	case []interface{}:
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return value, nil
+		}
+		mapped := make([]interface{}, len(v))
+		for i, elem := range v {
+			var err error
+			if mapped[i], err = mapTaggedInValue(
+				elem,
+				t.Elem(),
+				tag,
+				fn,
+			); err != nil {
+				return nil, err
+			}
+		}
+		return mapped, nil
+	default:
+		return value, nil
+	}
+}
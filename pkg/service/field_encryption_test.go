@@ -0,0 +1,175 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/crypto"
+	"github.com/Azure/open-service-broker-azure/pkg/crypto/aes256"
+	"github.com/Azure/open-service-broker-azure/pkg/crypto/versioned"
+	"github.com/stretchr/testify/assert"
+)
+
+type fieldEncryptionTestEmbedded struct {
+	Token string `json:"token" secret:"true"`
+}
+
+type fieldEncryptionTestNested struct {
+	Name     string `json:"name"`
+	Password string `json:"password" secret:"true"`
+}
+
+type fieldEncryptionTestType struct {
+	fieldEncryptionTestEmbedded
+	Name    string                      `json:"name"`
+	Count   int64                       `json:"count"`
+	Secret  map[string]interface{}      `json:"secret" secret:"true"`
+	Servers []fieldEncryptionTestNested `json:"servers"`
+}
+
+var testKeys = map[string]string{"1": "AES256Key-32Characters1234567890"}
+
+func TestFieldEncryptionRoundTrip(t *testing.T) {
+	codec := getTestFieldCodec(t, "1", testKeys, true)
+	v := &fieldEncryptionTestType{
+		fieldEncryptionTestEmbedded: fieldEncryptionTestEmbedded{Token: "t0k3n"},
+		Name:                        "foo",
+		Count:                       9007199254740993,
+		Secret:                      map[string]interface{}{"key": "s3cr3t"},
+		Servers: []fieldEncryptionTestNested{
+			{Name: "bar", Password: "p4ssw0rd"},
+		},
+	}
+	ciphertext, fields, err := encryptValue(v, codec)
+	assert.Nil(t, err)
+	assert.Nil(t, ciphertext)
+	// Only secret fields are encrypted
+	assert.Contains(t, string(fields), `"name":"foo"`)
+	assert.Contains(t, string(fields), `"name":"bar"`)
+	assert.Contains(t, string(fields), `"keyVersion":"1"`)
+	for _, secret := range []string{"t0k3n", "s3cr3t", "p4ssw0rd"} {
+		assert.NotContains(t, string(fields), secret)
+	}
+	decrypted := &fieldEncryptionTestType{}
+	err = decryptValue(nil, fields, decrypted, codec)
+	assert.Nil(t, err)
+	assert.Equal(t, v, decrypted)
+}
+
+func TestFieldEncryptionAfterKeyRotation(t *testing.T) {
+	oldCodec := getTestFieldCodec(t, "1", testKeys, true)
+	v := &fieldEncryptionTestType{
+		Name:   "foo",
+		Secret: map[string]interface{}{"key": "s3cr3t"},
+	}
+	_, fields, err := encryptValue(v, oldCodec)
+	assert.Nil(t, err)
+	newCodec := getTestFieldCodec(
+		t,
+		"2",
+		map[string]string{
+			"1": testKeys["1"],
+			"2": "AES256Key-32Characters0987654321",
+		},
+		true,
+	)
+	decrypted := &fieldEncryptionTestType{}
+	err = decryptValue(nil, fields, decrypted, newCodec)
+	assert.Nil(t, err)
+	assert.Equal(t, v, decrypted)
+}
+
+func TestFieldEncryptionCanBeEnabledAndDisabled(t *testing.T) {
+	v := &fieldEncryptionTestType{
+		Name:   "foo",
+		Secret: map[string]interface{}{"key": "s3cr3t"},
+	}
+	recordCodec := getTestFieldCodec(t, "1", testKeys, false)
+	fieldCodec := getTestFieldCodec(t, "1", testKeys, true)
+	// Values encrypted whole remain readable once field-level encryption is
+	// enabled...
+	ciphertext, fields, err := encryptValue(v, recordCodec)
+	assert.Nil(t, err)
+	assert.Nil(t, fields)
+	decrypted := &fieldEncryptionTestType{}
+	err = decryptValue(ciphertext, fields, decrypted, fieldCodec)
+	assert.Nil(t, err)
+	assert.Equal(t, v, decrypted)
+	// ...and values encrypted field by field remain readable once it's disabled
+	ciphertext, fields, err = encryptValue(v, fieldCodec)
+	assert.Nil(t, err)
+	decrypted = &fieldEncryptionTestType{}
+	err = decryptValue(ciphertext, fields, decrypted, recordCodec)
+	assert.Nil(t, err)
+	assert.Equal(t, v, decrypted)
+}
+
+func TestFieldDecryptionOfFieldsPersistedBeforeBeingTaggedSecret(t *testing.T) {
+	codec := getTestFieldCodec(t, "1", testKeys, true)
+	decrypted := &fieldEncryptionTestNested{}
+	err := decryptValue(
+		nil,
+		[]byte(`{"name":"foo","password":"p4ssw0rd"}`),
+		decrypted,
+		codec,
+	)
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		&fieldEncryptionTestNested{Name: "foo", Password: "p4ssw0rd"},
+		decrypted,
+	)
+}
+
+func TestInstanceToJSONWithFieldEncryption(t *testing.T) {
+	codec := getTestFieldCodec(t, "1", testKeys, true)
+	instance := Instance{
+		InstanceID: "test-instance-id",
+		ProvisioningParameters: &fieldEncryptionTestType{
+			Name:   "foo",
+			Secret: map[string]interface{}{"key": "s3cr3t"},
+		},
+		Details: &ArbitraryType{Foo: "bar"},
+	}
+	instanceJSON, err := instance.ToJSON(codec)
+	assert.Nil(t, err)
+	stored := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(instanceJSON, &stored))
+	assert.Nil(t, stored["provisioningParameters"])
+	assert.Equal(
+		t,
+		"foo",
+		stored["fieldEncryptedProvisioningParameters"].(map[string]interface{})["name"], // nolint: lll
+	)
+	retrieved, err := NewInstanceFromJSON(
+		instanceJSON,
+		&fieldEncryptionTestType{},
+		nil,
+		&ArbitraryType{},
+		codec,
+	)
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		instance.ProvisioningParameters,
+		retrieved.ProvisioningParameters,
+	)
+	assert.Equal(t, instance.Details, retrieved.Details)
+}
+
+func getTestFieldCodec(
+	t *testing.T,
+	keyVersion string,
+	keys map[string]string,
+	encryptsFields bool,
+) crypto.FieldCodec {
+	codecs := map[string]crypto.Codec{}
+	for version, key := range keys {
+		c, err := aes256.NewCodec([]byte(key))
+		assert.Nil(t, err)
+		codecs[version] = c
+	}
+	codec, err := versioned.NewCodec(keyVersion, codecs, encryptsFields)
+	assert.Nil(t, err)
+	return codec
+}
@@ -0,0 +1,11 @@
+package service
+
+// GitOpsManifest records where the manifest describing a binding was written
+// in the broker's GitOps repository, so that it can be removed when the
+// binding is unbound
+type GitOpsManifest struct {
+	// Path is the path of the manifest within the repository
+	Path string `json:"path"`
+	// Revision is the ID of the commit that wrote the manifest
+	Revision string `json:"revision"`
+}
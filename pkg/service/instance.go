@@ -7,28 +7,54 @@ import (
 	"github.com/Azure/open-service-broker-azure/pkg/crypto"
 )
 
-// Instance represents an instance of a service
+// Instance represents an instance of a service. Its provisioning parameters,
+// updating parameters and details are each stored either encrypted whole, in
+// the corresponding Encrypted* field, or with only their secret fields
+// encrypted, in the corresponding FieldEncrypted* field.
 type Instance struct {
-	InstanceID                      string                 `json:"instanceId"`
-	Alias                           string                 `json:"alias"`
-	ServiceID                       string                 `json:"serviceId"`
-	Service                         Service                `json:"-"`
-	PlanID                          string                 `json:"planId"`
-	Plan                            Plan                   `json:"-"`
-	EncryptedProvisioningParameters []byte                 `json:"provisioningParameters"` // nolint: lll
-	ProvisioningParameters          ProvisioningParameters `json:"-"`
-	EncryptedUpdatingParameters     []byte                 `json:"updatingParameters"` // nolint: lll
-	UpdatingParameters              UpdatingParameters     `json:"-"`
-	Status                          string                 `json:"status"`
-	StatusReason                    string                 `json:"statusReason"`
-	Location                        string                 `json:"location"`
-	ResourceGroup                   string                 `json:"resourceGroup"`
-	Parent                          *Instance              `json:"-"`
-	ParentAlias                     string                 `json:"parentAlias"`
-	Tags                            map[string]string      `json:"tags"`
-	EncryptedDetails                []byte                 `json:"details"`
-	Details                         InstanceDetails        `json:"-"`
-	Created                         time.Time              `json:"created"`
+	InstanceID                           string                 `json:"instanceId"`
+	Alias                                string                 `json:"alias"`
+	ServiceID                            string                 `json:"serviceId"`
+	Service                              Service                `json:"-"`
+	PlanID                               string                 `json:"planId"`
+	Plan                                 Plan                   `json:"-"`
+	EncryptedProvisioningParameters      []byte                 `json:"provisioningParameters"`                         // nolint: lll
+	FieldEncryptedProvisioningParameters json.RawMessage        `json:"fieldEncryptedProvisioningParameters,omitempty"` // nolint: lll
+	ProvisioningParameters               ProvisioningParameters `json:"-"`
+	EncryptedUpdatingParameters          []byte                 `json:"updatingParameters"`                         // nolint: lll
+	FieldEncryptedUpdatingParameters     json.RawMessage        `json:"fieldEncryptedUpdatingParameters,omitempty"` // nolint: lll
+	UpdatingParameters                   UpdatingParameters     `json:"-"`
+	Status                               string                 `json:"status"`
+	StatusReason                         string                 `json:"statusReason"`
+	Location                             string                 `json:"location"`
+	ResourceGroup                        string                 `json:"resourceGroup"`
+	SubscriptionID                       string                 `json:"subscriptionId,omitempty"` // nolint: lll
+	Parent                               *Instance              `json:"-"`
+	ParentAlias                          string                 `json:"parentAlias"`
+	DependsOn                            []string               `json:"dependsOn,omitempty"` // nolint: lll
+	Dependencies                         map[string]Dependency  `json:"-"`
+	Debug                                bool                   `json:"debug,omitempty"` // nolint: lll
+	Tags                                 map[string]string      `json:"tags"`
+	OrganizationGUID                     string                 `json:"organizationGuid"`                     // nolint: lll
+	CostCenter                           string                 `json:"costCenter,omitempty"`                 // nolint: lll
+	SkippedProvisioningSteps             []string               `json:"skippedProvisioningSteps"`             // nolint: lll
+	ProvisioningAudit                    *ProvisioningAudit     `json:"provisioningAudit,omitempty"`          // nolint: lll
+	Suspension                           *SuspensionState       `json:"suspension,omitempty"`                 // nolint: lll
+	Quarantine                           *QuarantineState       `json:"quarantine,omitempty"`                 // nolint: lll
+	Drift                                *DriftState            `json:"drift,omitempty"`                      // nolint: lll
+	CostEstimate                         *CostEstimate          `json:"costEstimate,omitempty"`               // nolint: lll
+	Activation                           *ActivationState       `json:"activation,omitempty"`                 // nolint: lll
+	ProvisioningTiming                   *ProvisioningTiming    `json:"provisioningTiming,omitempty"`         // nolint: lll
+	ProvisioningDegradedSince            *time.Time             `json:"provisioningDegradedSince,omitempty"`  // nolint: lll
+	ProvisioningProgress                 *ProvisioningProgress  `json:"provisioningProgress,omitempty"`       // nolint: lll
+	ProvisioningStepExecutions           StepExecutions         `json:"provisioningStepExecutions,omitempty"` // nolint: lll
+	Approval                             *ApprovalState         `json:"approval,omitempty"`                   // nolint: lll
+	BlueGreen                            *BlueGreenState        `json:"blueGreen,omitempty"`                  // nolint: lll
+	Expiry                               *ExpiryState           `json:"expiry,omitempty"`                     // nolint: lll
+	EncryptedDetails                     []byte                 `json:"details"`
+	FieldEncryptedDetails                json.RawMessage        `json:"fieldEncryptedDetails,omitempty"` // nolint: lll
+	Details                              InstanceDetails        `json:"-"`
+	Created                              time.Time              `json:"created"`
 }
 
 // NewInstanceFromJSON returns a new Instance unmarshalled from the provided
@@ -75,33 +101,27 @@ func (i Instance) encrypt(codec crypto.Codec) (Instance, error) {
 func (i Instance) encryptProvisioningParameters(
 	codec crypto.Codec,
 ) (Instance, error) {
-	jsonBytes, err := json.Marshal(i.ProvisioningParameters)
-	if err != nil {
-		return i, err
-	}
-	i.EncryptedProvisioningParameters, err = codec.Encrypt(jsonBytes)
+	var err error
+	i.EncryptedProvisioningParameters, i.FieldEncryptedProvisioningParameters,
+		err = encryptValue(i.ProvisioningParameters, codec)
 	return i, err
 }
 
 func (i Instance) encryptUpdatingParameters(
 	codec crypto.Codec,
 ) (Instance, error) {
-	jsonBytes, err := json.Marshal(i.UpdatingParameters)
-	if err != nil {
-		return i, err
-	}
-	i.EncryptedUpdatingParameters, err = codec.Encrypt(jsonBytes)
+	var err error
+	i.EncryptedUpdatingParameters, i.FieldEncryptedUpdatingParameters, err =
+		encryptValue(i.UpdatingParameters, codec)
 	return i, err
 }
 
 func (i Instance) encryptDetails(
 	codec crypto.Codec,
 ) (Instance, error) {
-	jsonBytes, err := json.Marshal(i.Details)
-	if err != nil {
-		return i, err
-	}
-	i.EncryptedDetails, err = codec.Encrypt(jsonBytes)
+	var err error
+	i.EncryptedDetails, i.FieldEncryptedDetails, err =
+		encryptValue(i.Details, codec)
 	return i, err
 }
 
@@ -119,41 +139,41 @@ func (i Instance) decrypt(codec crypto.Codec) (Instance, error) {
 func (i Instance) decryptProvisioningParameters(
 	codec crypto.Codec,
 ) (Instance, error) {
-	if len(i.EncryptedProvisioningParameters) == 0 ||
-		i.ProvisioningParameters == nil {
+	if i.ProvisioningParameters == nil {
 		return i, nil
 	}
-	plaintext, err := codec.Decrypt(i.EncryptedProvisioningParameters)
-	if err != nil {
-		return i, err
-	}
-	return i, json.Unmarshal(plaintext, i.ProvisioningParameters)
+	return i, decryptValue(
+		i.EncryptedProvisioningParameters,
+		i.FieldEncryptedProvisioningParameters,
+		i.ProvisioningParameters,
+		codec,
+	)
 }
 
 func (i Instance) decryptUpdatingParameters(
 	codec crypto.Codec,
 ) (Instance, error) {
-	if len(i.EncryptedUpdatingParameters) == 0 ||
-		i.UpdatingParameters == nil {
+	if i.UpdatingParameters == nil {
 		return i, nil
 	}
-	plaintext, err := codec.Decrypt(i.EncryptedUpdatingParameters)
-	if err != nil {
-		return i, err
-	}
-	return i, json.Unmarshal(plaintext, i.UpdatingParameters)
+	return i, decryptValue(
+		i.EncryptedUpdatingParameters,
+		i.FieldEncryptedUpdatingParameters,
+		i.UpdatingParameters,
+		codec,
+	)
 }
 
 func (i Instance) decryptDetails(
 	codec crypto.Codec,
 ) (Instance, error) {
-	if len(i.EncryptedDetails) == 0 ||
-		i.Details == nil {
+	if i.Details == nil {
 		return i, nil
 	}
-	plaintext, err := codec.Decrypt(i.EncryptedDetails)
-	if err != nil {
-		return i, err
-	}
-	return i, json.Unmarshal(plaintext, i.Details)
+	return i, decryptValue(
+		i.EncryptedDetails,
+		i.FieldEncryptedDetails,
+		i.Details,
+		codec,
+	)
 }
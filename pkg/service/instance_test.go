@@ -25,6 +25,10 @@ func init() {
 	parentAlias := "test-parent-alias"
 	tagKey := "foo"
 	tagVal := "bar"
+	organizationGUID := "test-organization-guid"
+	skippedStep := "test-step"
+	requestedFoo := "bar"
+	effectiveFoo := "BAR"
 	provisioningParameters := &ArbitraryType{
 		Foo: "bar",
 	}
@@ -42,12 +46,16 @@ func init() {
 	if err != nil {
 		panic(err)
 	}
+	suspendedAt, err := time.Parse(time.RFC3339, "2016-07-29T10:11:55-04:00")
+	if err != nil {
+		panic(err)
+	}
 
 	testInstance = Instance{
-		InstanceID: instanceID,
-		Alias:      alias,
-		ServiceID:  serviceID,
-		PlanID:     planID,
+		InstanceID:                      instanceID,
+		Alias:                           alias,
+		ServiceID:                       serviceID,
+		PlanID:                          planID,
 		EncryptedProvisioningParameters: encryptedProvisiongingParameters,
 		ProvisioningParameters:          provisioningParameters,
 		EncryptedUpdatingParameters:     encryptedUpdatingParameters,
@@ -58,9 +66,19 @@ func init() {
 		ResourceGroup:                   resourceGroup,
 		ParentAlias:                     parentAlias,
 		Tags:                            map[string]string{tagKey: tagVal},
-		EncryptedDetails:                encryptedDetails,
-		Details:                         details,
-		Created:                         created,
+		OrganizationGUID:                organizationGUID,
+		SkippedProvisioningSteps:        []string{skippedStep},
+		ProvisioningAudit: &ProvisioningAudit{
+			RequestedParameters: map[string]interface{}{"foo": requestedFoo},
+			EffectiveParameters: map[string]interface{}{"foo": effectiveFoo},
+		},
+		Suspension: &SuspensionState{
+			Suspended:   true,
+			SuspendedAt: &suspendedAt,
+		},
+		EncryptedDetails: encryptedDetails,
+		Details:          details,
+		Created:          created,
 	}
 
 	b64EncryptedProvisioningParameters := base64.StdEncoding.EncodeToString(
@@ -87,6 +105,16 @@ func init() {
 			"resourceGroup":"%s",
 			"parentAlias":"%s",
 			"tags":{"%s":"%s"},
+			"organizationGuid":"%s",
+			"skippedProvisioningSteps":["%s"],
+			"provisioningAudit":{
+				"requestedParameters":{"foo":"%s"},
+				"effectiveParameters":{"foo":"%s"}
+			},
+			"suspension":{
+				"suspended":true,
+				"suspendedAt":"%s"
+			},
 			"details":"%s",
 			"created":"%s"
 		}`,
@@ -103,6 +131,11 @@ func init() {
 		parentAlias,
 		tagKey,
 		tagVal,
+		organizationGUID,
+		skippedStep,
+		requestedFoo,
+		effectiveFoo,
+		suspendedAt.Format(time.RFC3339),
 		b64EncryptedDetails,
 		created.Format(time.RFC3339),
 	)
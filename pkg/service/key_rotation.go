@@ -0,0 +1,139 @@
+package service
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/crypto"
+	"github.com/Azure/open-service-broker-azure/pkg/crypto/versioned"
+)
+
+// KeyRotationProgress records how far the broker has got in re-encrypting its
+// stored records with the current version of the encryption key. Instances
+// are processed in order of instance ID, so a rotation that was interrupted
+// resumes with the first instance after LastInstanceID.
+type KeyRotationProgress struct {
+	KeyVersion string `json:"keyVersion"`
+	// RunID identifies the chain of tasks carrying out the rotation. A task
+	// belonging to any other chain does nothing.
+	RunID          string     `json:"runId"`
+	Started        time.Time  `json:"started"`
+	Updated        time.Time  `json:"updated"`
+	Completed      *time.Time `json:"completed,omitempty"`
+	LastInstanceID string     `json:"lastInstanceId,omitempty"`
+	// Rotated counts instances that were re-encrypted, along with their
+	// bindings
+	Rotated int64 `json:"rotated"`
+	// Skipped counts instances that, along with their bindings, were already
+	// encrypted with the current key version
+	Skipped int64 `json:"skipped"`
+	// Deferred counts instances that had an operation in progress. They are
+	// re-encrypted as that operation writes them, though their bindings are
+	// re-encrypted regardless.
+	Deferred int64 `json:"deferred"`
+	// Failed counts instances that couldn't be re-encrypted
+	Failed int64 `json:"failed"`
+}
+
+// GetProcessed returns the number of instances processed so far
+func (k KeyRotationProgress) GetProcessed() int64 {
+	return k.Rotated + k.Skipped + k.Deferred + k.Failed
+}
+
+// GetKeyVersions returns, in order, the distinct versions of the key that the
+// instance's secret fields and whole-encrypted values were encrypted with. A
+// value that was encrypted whole before whole values recorded a key version
+// is reflected by an empty version, which is never current.
+func (i Instance) GetKeyVersions() ([]string, error) {
+	return getKeyVersions(
+		encryptedValue{
+			ciphertext: i.EncryptedProvisioningParameters,
+			fields:     i.FieldEncryptedProvisioningParameters,
+			v:          i.ProvisioningParameters,
+		},
+		encryptedValue{
+			ciphertext: i.EncryptedUpdatingParameters,
+			fields:     i.FieldEncryptedUpdatingParameters,
+			v:          i.UpdatingParameters,
+		},
+		encryptedValue{
+			ciphertext: i.EncryptedDetails,
+			fields:     i.FieldEncryptedDetails,
+			v:          i.Details,
+		},
+	)
+}
+
+// GetKeyVersions returns, in order, the distinct versions of the key that the
+// binding's secret fields and whole-encrypted values were encrypted with. A
+// value that was encrypted whole before whole values recorded a key version
+// is reflected by an empty version, which is never current.
+func (b Binding) GetKeyVersions() ([]string, error) {
+	return getKeyVersions(
+		encryptedValue{
+			ciphertext: b.EncryptedBindingParameters,
+			fields:     b.FieldEncryptedBindingParameters,
+			v:          b.BindingParameters,
+		},
+		encryptedValue{
+			ciphertext: b.EncryptedDetails,
+			fields:     b.FieldEncryptedDetails,
+			v:          b.Details,
+		},
+	)
+}
+
+// encryptedValue pairs a value, encrypted either whole or field by field, with
+// a value of the (module-specific) type it decrypts to
+type encryptedValue struct {
+	ciphertext []byte
+	fields     json.RawMessage
+	v          interface{}
+}
+
+func getKeyVersions(values ...encryptedValue) ([]string, error) {
+	versions := map[string]bool{}
+	for _, value := range values {
+		if value.v == nil {
+			continue
+		}
+		if len(value.fields) == 0 {
+			if len(value.ciphertext) > 0 {
+				keyVersion, _ := versioned.GetKeyVersion(value.ciphertext)
+				versions[keyVersion] = true
+			}
+			continue
+		}
+		fields := map[string]interface{}{}
+		if err := unmarshalPreservingNumbers(value.fields, &fields); err != nil {
+			return nil, err
+		}
+		_, err := mapSecrets(
+			fields,
+			reflect.TypeOf(value.v),
+			func(fieldValue interface{}) (interface{}, error) {
+				fieldJSON, err := json.Marshal(fieldValue)
+				if err != nil {
+					return nil, err
+				}
+				field := crypto.EncryptedField{}
+				if err = json.Unmarshal(fieldJSON, &field); err != nil {
+					return nil, err
+				}
+				versions[field.KeyVersion] = true
+				return fieldValue, nil
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+	sortedVersions := make([]string, 0, len(versions))
+	for version := range versions {
+		sortedVersions = append(sortedVersions, version)
+	}
+	sort.Strings(sortedVersions)
+	return sortedVersions, nil
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/crypto/aes256"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstanceGetKeyVersions(t *testing.T) {
+	keys := map[string]string{
+		"1": testKeys["1"],
+		"2": "AES256Key-32Characters0987654321",
+	}
+	oldCodec := getTestFieldCodec(t, "1", testKeys, true)
+	newCodec := getTestFieldCodec(t, "2", keys, true)
+	instance := Instance{
+		InstanceID: "test-instance-id",
+		ProvisioningParameters: &fieldEncryptionTestType{
+			Name:   "foo",
+			Secret: map[string]interface{}{"key": "s3cr3t"},
+		},
+		Details: &ArbitraryType{Foo: "bar"},
+	}
+	instanceJSON, err := instance.ToJSON(oldCodec)
+	assert.Nil(t, err)
+	retrieved, err := NewInstanceFromJSON(
+		instanceJSON,
+		&fieldEncryptionTestType{},
+		nil,
+		&ArbitraryType{},
+		newCodec,
+	)
+	assert.Nil(t, err)
+	versions, err := retrieved.GetKeyVersions()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"1"}, versions)
+	// Writing the instance again re-encrypts it with the current key version
+	instanceJSON, err = retrieved.ToJSON(newCodec)
+	assert.Nil(t, err)
+	retrieved, err = NewInstanceFromJSON(
+		instanceJSON,
+		&fieldEncryptionTestType{},
+		nil,
+		&ArbitraryType{},
+		newCodec,
+	)
+	assert.Nil(t, err)
+	versions, err = retrieved.GetKeyVersions()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"2"}, versions)
+}
+
+func TestInstanceGetKeyVersionsWithoutFieldEncryption(t *testing.T) {
+	codec := getTestFieldCodec(t, "1", testKeys, false)
+	instance := Instance{
+		InstanceID: "test-instance-id",
+		ProvisioningParameters: &fieldEncryptionTestType{
+			Secret: map[string]interface{}{"key": "s3cr3t"},
+		},
+	}
+	instanceJSON, err := instance.ToJSON(codec)
+	assert.Nil(t, err)
+	retrieved, err := NewInstanceFromJSON(
+		instanceJSON,
+		&fieldEncryptionTestType{},
+		nil,
+		nil,
+		codec,
+	)
+	assert.Nil(t, err)
+	versions, err := retrieved.GetKeyVersions()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"1"}, versions)
+}
+
+func TestInstanceGetKeyVersionsOfValueWithoutKeyVersion(t *testing.T) {
+	// Values encrypted whole before they recorded a key version were encrypted
+	// using the underlying codec directly
+	legacyCodec, err := aes256.NewCodec([]byte(testKeys["1"]))
+	assert.Nil(t, err)
+	instance := Instance{
+		InstanceID: "test-instance-id",
+		ProvisioningParameters: &fieldEncryptionTestType{
+			Secret: map[string]interface{}{"key": "s3cr3t"},
+		},
+	}
+	instanceJSON, err := instance.ToJSON(legacyCodec)
+	assert.Nil(t, err)
+	retrieved, err := NewInstanceFromJSON(
+		instanceJSON,
+		&fieldEncryptionTestType{},
+		nil,
+		nil,
+		getTestFieldCodec(t, "1", testKeys, false),
+	)
+	assert.Nil(t, err)
+	versions, err := retrieved.GetKeyVersions()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{""}, versions)
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+)
+
+var daysOfWeek = []string{
+	"Monday",
+	"Tuesday",
+	"Wednesday",
+	"Thursday",
+	"Friday",
+	"Saturday",
+	"Sunday",
+}
+
+// MaintenanceWindow represents a preferred time, recurring weekly, at which
+// Azure may begin disruptive maintenance of an instance's underlying
+// resources. How long maintenance may then take is up to each service.
+type MaintenanceWindow struct {
+	DayOfWeek    string `json:"dayOfWeek"`
+	StartHourUTC int    `json:"startHourUtc"`
+}
+
+// Validate returns a ValidationError if the maintenance window's day of the
+// week or starting hour is invalid. The given field, i.e. the name of the
+// parameter the window was specified as, qualifies that of the error.
+func (m MaintenanceWindow) Validate(field string) error {
+	validDay := false
+	for _, day := range daysOfWeek {
+		if m.DayOfWeek == day {
+			validDay = true
+			break
+		}
+	}
+	if !validDay {
+		return NewValidationError(
+			field+".dayOfWeek",
+			fmt.Sprintf(
+				`invalid dayOfWeek: "%s"; allowed values are %s`,
+				m.DayOfWeek,
+				strings.Join(daysOfWeek, ", "),
+			),
+		)
+	}
+	if m.StartHourUTC < 0 || m.StartHourUTC > 23 {
+		return NewValidationError(
+			field+".startHourUtc",
+			fmt.Sprintf(
+				"invalid startHourUtc: %d; it must be from 0 to 23",
+				m.StartHourUTC,
+			),
+		)
+	}
+	return nil
+}
+
+// MaintenanceWindowRequester is an interface to be optionally implemented by
+// the provisioning and updating parameters of modules whose instances accept
+// a preferred maintenance window
+type MaintenanceWindowRequester interface {
+	// GetMaintenanceWindow returns the maintenance window requested, or nil if
+	// none was
+	GetMaintenanceWindow() *MaintenanceWindow
+}
+
+// MaintenanceWindowValidator is an interface to be optionally implemented by
+// the ServiceManagers of modules whose instances accept a preferred
+// maintenance window for some plans, or in some regions, but not others
+type MaintenanceWindowValidator interface {
+	// ValidateMaintenanceWindow returns an error if a maintenance window can't
+	// be set for instances of the given plan in the given location. The window
+	// given has already been validated by MaintenanceWindow.Validate.
+	ValidateMaintenanceWindow(
+		plan Plan,
+		location string,
+		window MaintenanceWindow,
+	) error
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateMaintenanceWindow(t *testing.T) {
+	for _, window := range []MaintenanceWindow{
+		{DayOfWeek: "Monday", StartHourUTC: 0},
+		{DayOfWeek: "Sunday", StartHourUTC: 23},
+	} {
+		assert.Nil(t, window.Validate("maintenanceWindow"), window)
+	}
+	err := MaintenanceWindow{DayOfWeek: "Everyday"}.Validate("maintenanceWindow")
+	assert.Equal(t, "maintenanceWindow.dayOfWeek", err.(*ValidationError).Field)
+	err = MaintenanceWindow{
+		DayOfWeek:    "Friday",
+		StartHourUTC: 24,
+	}.Validate("maintenanceWindow")
+	assert.Equal(t, "maintenanceWindow.startHourUtc", err.(*ValidationError).Field)
+	err = MaintenanceWindow{
+		DayOfWeek:    "Friday",
+		StartHourUTC: -1,
+	}.Validate("maintenanceWindow")
+	assert.NotNil(t, err)
+}
@@ -9,3 +9,14 @@ type Module interface {
 	// GetCatalog returns a Catalog of service/plans offered by a module
 	GetCatalog() (Catalog, error)
 }
+
+// ResourceProviderUser is an interface to be implemented by modules that can
+// name the Azure resource providers that their service managers make requests
+// to. This permits the broker to slow the provisioning, updating and
+// deprovisioning of their instances while any of those providers are
+// throttling the broker.
+type ResourceProviderUser interface {
+	// GetResourceProviders returns the namespaces of the resource providers,
+	// e.g. Microsoft.Storage
+	GetResourceProviders() []string
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template/parse"
+)
+
+// templateContextField is the only field of a parameter template's data; the
+// values it holds are referenced as {{.Context.<Name>}}
+const templateContextField = "Context"
+
+// TemplateContext holds the contextual values, keyed by name (e.g.
+// "Namespace"), that accompanied a provisioning request and that templated
+// provisioning parameters may reference. Values that weren't supplied are
+// omitted.
+type TemplateContext map[string]string
+
+// ResolveParameterTemplates returns a copy of the given parameter map in which
+// the value of every string parameter that is tagged `template:"true"` in the
+// given (module-specific) provisioning parameters type is resolved as a
+// template against the given context. Parameters of nested types, and lists
+// thereof, are resolved likewise. A template may contain nothing but text and
+// references to context values, e.g. "{{.Context.Namespace}}-db". Referencing
+// a value that the context doesn't hold is a validation error.
+func ResolveParameterTemplates(
+	params map[string]interface{},
+	pp ProvisioningParameters,
+	context TemplateContext,
+) (map[string]interface{}, error) {
+	return mapTagged(
+		params,
+		reflect.TypeOf(pp),
+		"template",
+		func(value interface{}) (interface{}, error) {
+			text, ok := value.(string)
+			if !ok {
+				return value, nil
+			}
+			return ResolveTemplate(text, context)
+		},
+	)
+}
+
+// ResolveTemplate resolves the given template text against the given context.
+// Text containing no template actions is returned as it is.
+func ResolveTemplate(text string, context TemplateContext) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	// No functions are made available to templates, so a template invoking
+	// one fails to parse
+	trees, err := parse.Parse("parameter", text, "", "")
+	if err != nil {
+		return "", NewValidationError(
+			"parameters",
+			fmt.Sprintf(`invalid template "%s": %s`, text, err),
+		)
+	}
+	tree, ok := trees["parameter"]
+	if !ok || len(trees) > 1 {
+		return "", NewValidationError(
+			"parameters",
+			fmt.Sprintf(
+				`invalid template "%s": templates may not define templates`,
+				text,
+			),
+		)
+	}
+	if tree.Root == nil {
+		return "", nil
+	}
+	resolved := &bytes.Buffer{}
+	for _, node := range tree.Root.Nodes {
+		switch n := node.(type) {
+		case *parse.TextNode:
+			resolved.Write(n.Text)
+		case *parse.ActionNode:
+			name, ok := getTemplateContextReference(n)
+			if !ok {
+				return "", NewValidationError(
+					"parameters",
+					fmt.Sprintf(
+						`invalid template "%s": "%s" is not a reference to a context `+
+							`value, e.g. {{.Context.Namespace}}`,
+						text,
+						n,
+					),
+				)
+			}
+			value, ok := context[name]
+			if !ok {
+				return "", NewValidationError(
+					"parameters",
+					fmt.Sprintf(
+						`invalid template "%s": the request's context has no value `+
+							`"%s"`,
+						text,
+						name,
+					),
+				)
+			}
+			resolved.WriteString(value)
+		default:
+			return "", NewValidationError(
+				"parameters",
+				fmt.Sprintf(
+					`invalid template "%s": "%s" is not allowed; templates may `+
+						`only reference context values`,
+					text,
+					n,
+				),
+			)
+		}
+	}
+	return resolved.String(), nil
+}
+
+// getTemplateContextReference returns the name of the context value that the
+// given action references, if the action is nothing more than a reference to
+// a context value
+func getTemplateContextReference(action *parse.ActionNode) (string, bool) {
+	pipe := action.Pipe
+	if pipe == nil || len(pipe.Decl) > 0 || len(pipe.Cmds) != 1 ||
+		len(pipe.Cmds[0].Args) != 1 {
+		return "", false
+	}
+	field, ok := pipe.Cmds[0].Args[0].(*parse.FieldNode)
+	if !ok || len(field.Ident) != 2 || field.Ident[0] != templateContextField {
+		return "", false
+	}
+	return field.Ident[1], true
+}
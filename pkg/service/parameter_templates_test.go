@@ -0,0 +1,133 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type parameterTemplatesTestNested struct {
+	Name  string `json:"name" template:"true"`
+	Label string `json:"label"`
+}
+
+type parameterTemplatesTestType struct {
+	Name    string                         `json:"name" template:"true"`
+	Count   int64                          `json:"count" template:"true"`
+	Label   string                         `json:"label"`
+	Server  *parameterTemplatesTestNested  `json:"server"`
+	Servers []parameterTemplatesTestNested `json:"servers"`
+}
+
+var testTemplateContext = TemplateContext{
+	"Namespace": "team-a",
+	"ClusterID": "cluster-1",
+}
+
+func TestResolveTemplate(t *testing.T) {
+	resolved, err := ResolveTemplate(
+		"{{.Context.Namespace}}-db-{{ .Context.ClusterID }}",
+		testTemplateContext,
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, "team-a-db-cluster-1", resolved)
+}
+
+func TestResolveTemplateWithoutActions(t *testing.T) {
+	resolved, err := ResolveTemplate("plain-{name}", testTemplateContext)
+	assert.Nil(t, err)
+	assert.Equal(t, "plain-{name}", resolved)
+}
+
+func TestResolveTemplateWithMissingContextValue(t *testing.T) {
+	_, err := ResolveTemplate(
+		"{{.Context.SpaceName}}-db",
+		testTemplateContext,
+	)
+	assert.NotNil(t, err)
+	validationErr, ok := err.(*ValidationError)
+	assert.True(t, ok)
+	assert.Equal(t, "parameters", validationErr.Field)
+	assert.Contains(t, err.Error(), "SpaceName")
+}
+
+func TestResolveTemplateRefusesAnythingButContextReferences(t *testing.T) {
+	for _, text := range []string{
+		`{{printf "%s" .Context.Namespace}}`,
+		`{{len .Context.Namespace}}`,
+		`{{.Context.Namespace | printf "%s"}}`,
+		`{{if .Context.Namespace}}x{{end}}`,
+		`{{range .Context}}x{{end}}`,
+		`{{$x := .Context.Namespace}}`,
+		`{{.Context}}`,
+		`{{.Namespace}}`,
+		`{{.Context.Namespace.Length}}`,
+		`{{define "x"}}y{{end}}`,
+		`{{template "x"}}`,
+		`{{.Context.Namespace`,
+	} {
+		_, err := ResolveTemplate(text, testTemplateContext)
+		assert.NotNil(t, err, text)
+		_, ok := err.(*ValidationError)
+		assert.True(t, ok, text)
+	}
+}
+
+func TestResolveParameterTemplates(t *testing.T) {
+	params := map[string]interface{}{
+		"name":  "{{.Context.Namespace}}-db",
+		"count": 3,
+		"label": "{{.Context.Namespace}}",
+		"server": map[string]interface{}{
+			"name":  "{{.Context.ClusterID}}",
+			"label": "{{.Context.ClusterID}}",
+		},
+		"servers": []interface{}{
+			map[string]interface{}{
+				"name": "{{.Context.Namespace}}-0",
+			},
+		},
+	}
+	resolved, err := ResolveParameterTemplates(
+		params,
+		&parameterTemplatesTestType{},
+		testTemplateContext,
+	)
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		map[string]interface{}{
+			"name":  "team-a-db",
+			"count": 3,
+			"label": "{{.Context.Namespace}}",
+			"server": map[string]interface{}{
+				"name":  "cluster-1",
+				"label": "{{.Context.ClusterID}}",
+			},
+			"servers": []interface{}{
+				map[string]interface{}{
+					"name": "team-a-0",
+				},
+			},
+		},
+		resolved,
+	)
+	// The given parameters are left as they were
+	assert.Equal(t, "{{.Context.Namespace}}-db", params["name"])
+	assert.Equal(
+		t,
+		"{{.Context.ClusterID}}",
+		params["server"].(map[string]interface{})["name"],
+	)
+}
+
+func TestResolveParameterTemplatesWithMissingContextValue(t *testing.T) {
+	_, err := ResolveParameterTemplates(
+		map[string]interface{}{
+			"name": "{{.Context.OrganizationName}}",
+		},
+		&parameterTemplatesTestType{},
+		testTemplateContext,
+	)
+	assert.NotNil(t, err)
+}
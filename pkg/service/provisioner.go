@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 )
 
@@ -23,8 +24,10 @@ type ProvisioningStep interface {
 }
 
 type provisioningStep struct {
-	name string
-	fn   ProvisioningStepFunction
+	name                 string
+	fn                   ProvisioningStepFunction
+	dependencies         []string
+	dependenciesDeclared bool
 }
 
 // Provisioner is an interface to be implemented by types that model a declared
@@ -33,15 +36,32 @@ type Provisioner interface {
 	GetFirstStepName() (string, bool)
 	GetStep(name string) (ProvisioningStep, bool)
 	GetNextStepName(name string) (string, bool)
+	// GetStepNames returns the names of all steps in the chain, in order
+	GetStepNames() []string
+	// WithStepOrder returns a Provisioner that executes only the named steps,
+	// in the order given. An error is returned if the new order would execute
+	// a step without first executing all the steps it depends on. The first
+	// step of a chain is where every provisioning operation enters that chain,
+	// so it can be neither skipped nor moved.
+	WithStepOrder(stepNames []string) (Provisioner, error)
+	// WithSuccessCriteria returns a Provisioner that, after executing all of
+	// this Provisioner's steps, checks each of the given criteria in turn. An
+	// error is returned if any criterion's name is already that of a step.
+	WithSuccessCriteria(criteria []SuccessCriterion) (Provisioner, error)
 }
 
 type provisioner struct {
 	firstStepName string
+	stepNames     []string
 	steps         map[string]ProvisioningStep
 	nextSteps     map[string]string
+	// dependencies is keyed by step name and indicates which other steps must
+	// be executed before a given step
+	dependencies map[string][]string
 }
 
-// NewProvisioningStep returns a new ProvisioningStep
+// NewProvisioningStep returns a new ProvisioningStep. The step depends on
+// every step that precedes it in its chain.
 func NewProvisioningStep(
 	name string,
 	fn ProvisioningStepFunction,
@@ -52,6 +72,25 @@ func NewProvisioningStep(
 	}
 }
 
+// NewProvisioningStepWithDependencies returns a new ProvisioningStep that
+// depends only on the named steps, each of which must precede it in its
+// chain. Unlike steps returned from NewProvisioningStep, a step with no
+// dependencies (or whose dependencies are all retained) can be reordered, and
+// a step that nothing depends on can be skipped, by overriding the chain's
+// step order.
+func NewProvisioningStepWithDependencies(
+	name string,
+	fn ProvisioningStepFunction,
+	dependencies ...string,
+) ProvisioningStep {
+	return &provisioningStep{
+		name:                 name,
+		fn:                   fn,
+		dependencies:         dependencies,
+		dependenciesDeclared: true,
+	}
+}
+
 // GetName returns a provisioning step's name
 func (p *provisioningStep) GetName() string {
 	return p.name
@@ -73,8 +112,9 @@ func (p *provisioningStep) Execute(
 // NewProvisioner returns a new provisioner
 func NewProvisioner(steps ...ProvisioningStep) (Provisioner, error) {
 	p := &provisioner{
-		steps:     make(map[string]ProvisioningStep),
-		nextSteps: make(map[string]string),
+		steps:        make(map[string]ProvisioningStep),
+		nextSteps:    make(map[string]string),
+		dependencies: make(map[string][]string),
 	}
 	if len(steps) > 0 {
 		p.firstStepName = steps[0].GetName()
@@ -89,7 +129,22 @@ func NewProvisioner(steps ...ProvisioningStep) (Provisioner, error) {
 					step.GetName(),
 				)
 			}
+			if s, ok := step.(*provisioningStep); ok && s.dependenciesDeclared {
+				for _, dependency := range s.dependencies {
+					if _, ok := p.steps[dependency]; !ok {
+						return nil, fmt.Errorf(
+							`step "%s" depends on step "%s", which does not precede it`,
+							step.GetName(),
+							dependency,
+						)
+					}
+				}
+				p.dependencies[step.GetName()] = s.dependencies
+			} else {
+				p.dependencies[step.GetName()] = append([]string{}, p.stepNames...)
+			}
 			p.steps[step.GetName()] = step
+			p.stepNames = append(p.stepNames, step.GetName())
 			if lastStep != nil {
 				p.nextSteps[lastStep.GetName()] = step.GetName()
 			}
@@ -116,3 +171,97 @@ func (p *provisioner) GetNextStepName(name string) (string, bool) {
 	nextStepName, ok := p.nextSteps[name]
 	return nextStepName, ok
 }
+
+// GetStepNames returns the names of all steps in the chain, in order
+func (p *provisioner) GetStepNames() []string {
+	return append([]string{}, p.stepNames...)
+}
+
+// WithStepOrder returns a Provisioner that executes only the named steps, in
+// the order given, provided that doesn't violate any step's dependencies
+func (p *provisioner) WithStepOrder(stepNames []string) (Provisioner, error) {
+	if len(stepNames) == 0 || stepNames[0] != p.firstStepName {
+		return nil, fmt.Errorf(
+			`step "%s" must remain the first step`,
+			p.firstStepName,
+		)
+	}
+	ordered := &provisioner{
+		firstStepName: p.firstStepName,
+		steps:         make(map[string]ProvisioningStep),
+		nextSteps:     make(map[string]string),
+		dependencies:  p.dependencies,
+	}
+	for i, stepName := range stepNames {
+		step, ok := p.steps[stepName]
+		if !ok {
+			return nil, fmt.Errorf(`unknown step "%s"`, stepName)
+		}
+		if _, ok := ordered.steps[stepName]; ok {
+			return nil, fmt.Errorf(`duplicate step name "%s" detected`, stepName)
+		}
+		for _, dependency := range p.dependencies[stepName] {
+			if _, ok := ordered.steps[dependency]; !ok {
+				return nil, fmt.Errorf(
+					`step "%s" depends on step "%s", which would not be executed `+
+						`before it`,
+					stepName,
+					dependency,
+				)
+			}
+		}
+		ordered.steps[stepName] = step
+		ordered.stepNames = append(ordered.stepNames, stepName)
+		if i > 0 {
+			ordered.nextSteps[stepNames[i-1]] = stepName
+		}
+	}
+	return ordered, nil
+}
+
+// WithSuccessCriteria returns a Provisioner whose chain continues, past the
+// last of this Provisioner's steps, with a step for each of the given criteria
+func (p *provisioner) WithSuccessCriteria(
+	criteria []SuccessCriterion,
+) (Provisioner, error) {
+	if len(criteria) == 0 {
+		return p, nil
+	}
+	if p.firstStepName == "" {
+		return nil, errors.New(
+			"success criteria cannot be added to a provisioner with no steps",
+		)
+	}
+	extended := &provisioner{
+		firstStepName: p.firstStepName,
+		stepNames:     append([]string{}, p.stepNames...),
+		steps:         make(map[string]ProvisioningStep),
+		nextSteps:     make(map[string]string),
+		dependencies:  make(map[string][]string),
+	}
+	for stepName, step := range p.steps {
+		extended.steps[stepName] = step
+	}
+	for stepName, nextStepName := range p.nextSteps {
+		extended.nextSteps[stepName] = nextStepName
+	}
+	for stepName, dependencies := range p.dependencies {
+		extended.dependencies[stepName] = dependencies
+	}
+	for _, criterion := range criteria {
+		if _, ok := extended.steps[criterion.Name]; ok {
+			return nil, fmt.Errorf(
+				`success criterion "%s" has the same name as another step`,
+				criterion.Name,
+			)
+		}
+		// A criterion is checked only once every step before it has succeeded
+		extended.dependencies[criterion.Name] =
+			append([]string{}, extended.stepNames...)
+		extended.steps[criterion.Name] = criterion.getProvisioningStep()
+		lastStepName := extended.stepNames[len(extended.stepNames)-1]
+		extended.nextSteps[lastStepName] = criterion.Name
+		extended.stepNames = append(extended.stepNames, criterion.Name)
+	}
+	return extended, nil
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProvisionerRejectsDependencyOnLaterStep(t *testing.T) {
+	_, err := NewProvisioner(
+		NewProvisioningStep("first", noopProvisioningStep),
+		NewProvisioningStepWithDependencies(
+			"second",
+			noopProvisioningStep,
+			"third",
+		),
+		NewProvisioningStep("third", noopProvisioningStep),
+	)
+	assert.NotNil(t, err)
+}
+
+func TestWithStepOrderRetainingAllStepsInOrder(t *testing.T) {
+	p := getTestProvisioner(t)
+	ordered, err := p.WithStepOrder(p.GetStepNames())
+	assert.Nil(t, err)
+	assert.Equal(t, p.GetStepNames(), ordered.GetStepNames())
+}
+
+func TestWithStepOrderSkippingStepNothingDependsOn(t *testing.T) {
+	p := getTestProvisioner(t)
+	ordered, err := p.WithStepOrder([]string{"first", "second", "fourth"})
+	assert.Nil(t, err)
+	nextStepName, ok := ordered.GetNextStepName("second")
+	assert.True(t, ok)
+	assert.Equal(t, "fourth", nextStepName)
+	_, ok = ordered.GetStep("third")
+	assert.False(t, ok)
+}
+
+func TestWithStepOrderReorderingIndependentSteps(t *testing.T) {
+	p := getTestProvisioner(t)
+	ordered, err := p.WithStepOrder(
+		[]string{"first", "second", "fourth", "third"},
+	)
+	assert.Nil(t, err)
+	_, ok := ordered.GetNextStepName("third")
+	assert.False(t, ok)
+}
+
+func TestWithStepOrderViolatingDeclaredDependencies(t *testing.T) {
+	p := getTestProvisioner(t)
+	// "third" and "fourth" both depend on "second"
+	_, err := p.WithStepOrder([]string{"first", "third", "second"})
+	assert.NotNil(t, err)
+	_, err = p.WithStepOrder([]string{"first", "fourth"})
+	assert.NotNil(t, err)
+}
+
+func TestWithStepOrderViolatingDefaultDependencies(t *testing.T) {
+	p, err := NewProvisioner(
+		NewProvisioningStep("first", noopProvisioningStep),
+		NewProvisioningStep("second", noopProvisioningStep),
+		NewProvisioningStep("third", noopProvisioningStep),
+	)
+	assert.Nil(t, err)
+	// By default, every step depends on all the steps before it, so none can be
+	// skipped except the last, and none can be reordered
+	_, err = p.WithStepOrder([]string{"first", "second"})
+	assert.Nil(t, err)
+	_, err = p.WithStepOrder([]string{"first", "third"})
+	assert.NotNil(t, err)
+	_, err = p.WithStepOrder([]string{"first", "third", "second"})
+	assert.NotNil(t, err)
+}
+
+func TestWithStepOrderMovingFirstStep(t *testing.T) {
+	p := getTestProvisioner(t)
+	_, err := p.WithStepOrder([]string{"second", "first"})
+	assert.NotNil(t, err)
+	_, err = p.WithStepOrder([]string{})
+	assert.NotNil(t, err)
+}
+
+func TestWithStepOrderUnknownOrDuplicateStep(t *testing.T) {
+	p := getTestProvisioner(t)
+	_, err := p.WithStepOrder([]string{"first", "bogus"})
+	assert.NotNil(t, err)
+	_, err = p.WithStepOrder([]string{"first", "second", "second"})
+	assert.NotNil(t, err)
+}
+
+func getTestProvisioner(t *testing.T) Provisioner {
+	p, err := NewProvisioner(
+		NewProvisioningStep("first", noopProvisioningStep),
+		NewProvisioningStepWithDependencies(
+			"second",
+			noopProvisioningStep,
+			"first",
+		),
+		NewProvisioningStepWithDependencies(
+			"third",
+			noopProvisioningStep,
+			"second",
+		),
+		NewProvisioningStepWithDependencies(
+			"fourth",
+			noopProvisioningStep,
+			"second",
+		),
+	)
+	assert.Nil(t, err)
+	return p
+}
+
+func noopProvisioningStep(
+	context.Context,
+	Instance,
+) (InstanceDetails, error) {
+	return nil, nil
+}
+
+func TestWithSuccessCriteria(t *testing.T) {
+	p := getTestProvisioner(t)
+	extended, err := p.WithSuccessCriteria([]SuccessCriterion{
+		{Name: "seedSchema", Check: noopProvisioningStep},
+		{Name: "warmUp", Check: noopProvisioningStep},
+	})
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		[]string{"first", "second", "third", "fourth", "seedSchema", "warmUp"},
+		extended.GetStepNames(),
+	)
+	nextStepName, ok := extended.GetNextStepName("fourth")
+	assert.True(t, ok)
+	assert.Equal(t, "seedSchema", nextStepName)
+	nextStepName, ok = extended.GetNextStepName("seedSchema")
+	assert.True(t, ok)
+	assert.Equal(t, "warmUp", nextStepName)
+	_, ok = extended.GetNextStepName("warmUp")
+	assert.False(t, ok)
+	// The original provisioner is left as it was
+	_, ok = p.GetNextStepName("fourth")
+	assert.False(t, ok)
+}
+
+func TestWithSuccessCriteriaAfterStepOrder(t *testing.T) {
+	p := getTestProvisioner(t)
+	ordered, err := p.WithStepOrder([]string{"first", "second", "fourth"})
+	assert.Nil(t, err)
+	extended, err := ordered.WithSuccessCriteria([]SuccessCriterion{
+		{Name: "seedSchema", Check: noopProvisioningStep},
+	})
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		[]string{"first", "second", "fourth", "seedSchema"},
+		extended.GetStepNames(),
+	)
+}
+
+func TestWithSuccessCriteriaDuplicatingStepName(t *testing.T) {
+	p := getTestProvisioner(t)
+	_, err := p.WithSuccessCriteria([]SuccessCriterion{
+		{Name: "third", Check: noopProvisioningStep},
+	})
+	assert.NotNil(t, err)
+}
+
+func TestSuccessCriterionTimeout(t *testing.T) {
+	p := getTestProvisioner(t)
+	extended, err := p.WithSuccessCriteria([]SuccessCriterion{
+		{
+			Name: "warmUp",
+			Check: func(
+				ctx context.Context,
+				_ Instance,
+			) (InstanceDetails, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			},
+			Timeout: time.Millisecond,
+		},
+	})
+	assert.Nil(t, err)
+	step, ok := extended.GetStep("warmUp")
+	assert.True(t, ok)
+	_, err = step.Execute(context.Background(), Instance{})
+	assert.NotNil(t, err)
+	assert.Equal(t, ErrorCategoryTransient, GetErrorCategory(err))
+}
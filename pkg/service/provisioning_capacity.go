@@ -0,0 +1,56 @@
+package service
+
+// ProvisioningCapacityPolicy caps how many instances of each module's
+// services may be provisioning at once, and decides whether a request that
+// arrives while a module is at its cap is queued until capacity frees up or
+// refused. The zero value caps no module.
+type ProvisioningCapacityPolicy struct {
+	limitsByServiceID map[string]ProvisioningCapacityLimits
+}
+
+// ProvisioningCapacityLimits represents the cap on concurrent provisions
+// shared by all the services of a single module
+type ProvisioningCapacityLimits struct {
+	// Pool names the capacity that the module's services share
+	Pool                    string
+	MaxConcurrentProvisions int
+	// MaxQueueDepth is how many requests may be queued awaiting capacity at
+	// once. Zero means requests are refused while the module is at its cap.
+	MaxQueueDepth int
+}
+
+// NewProvisioningCapacityPolicy returns a ProvisioningCapacityPolicy that
+// applies the given limits to instances of the services they are mapped to,
+// which are identified by service ID
+func NewProvisioningCapacityPolicy(
+	limitsByServiceID map[string]ProvisioningCapacityLimits,
+) ProvisioningCapacityPolicy {
+	return ProvisioningCapacityPolicy{
+		limitsByServiceID: limitsByServiceID,
+	}
+}
+
+// GetLimits returns the limits that apply to provisioning instances of the
+// given service, along with a bool indicating whether any apply at all
+func (p ProvisioningCapacityPolicy) GetLimits(
+	svc Service,
+) (ProvisioningCapacityLimits, bool) {
+	if svc == nil {
+		return ProvisioningCapacityLimits{}, false
+	}
+	limits, ok := p.limitsByServiceID[svc.GetID()]
+	return limits, ok
+}
+
+// GetPools returns the names of all the pools of capacity the policy caps
+func (p ProvisioningCapacityPolicy) GetPools() []string {
+	pools := []string{}
+	seen := map[string]bool{}
+	for _, limits := range p.limitsByServiceID {
+		if !seen[limits.Pool] {
+			seen[limits.Pool] = true
+			pools = append(pools, limits.Pool)
+		}
+	}
+	return pools
+}
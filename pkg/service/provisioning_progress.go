@@ -0,0 +1,56 @@
+package service
+
+import "time"
+
+// ProvisioningProgress records how far an instance's provisioning operation
+// has got, so that the time it has left can be estimated while it is in
+// progress
+type ProvisioningProgress struct {
+	// Steps are the names of the steps the operation executes, in order, once
+	// any step order override is applied
+	Steps []string `json:"steps"`
+	// CurrentStep is the name of the step being executed. It is empty once
+	// every step has been executed, while the broker finishes the operation off
+	// (e.g. by validating connectivity).
+	CurrentStep        string    `json:"currentStep"`
+	CurrentStepStarted time.Time `json:"currentStepStarted"`
+}
+
+// GetExpectedDurations returns, given the expected duration of each step, how
+// long the remainder of the operation is expected to take as of the given
+// time and how long the operation was expected to take in all. The returned
+// bool is false if any step has no expected duration.
+func (p ProvisioningProgress) GetExpectedDurations(
+	now time.Time,
+	stepDurations map[string]time.Duration,
+) (time.Duration, time.Duration, bool) {
+	var remaining, total time.Duration
+	var reachedCurrentStep bool
+	for _, stepName := range p.Steps {
+		duration, ok := stepDurations[stepName]
+		if !ok {
+			return 0, 0, false
+		}
+		total += duration
+		switch {
+		case stepName == p.CurrentStep:
+			reachedCurrentStep = true
+			// A step that overruns is expected to finish imminently
+			if left := duration - now.Sub(p.CurrentStepStarted); left > 0 {
+				remaining += left
+			}
+		case reachedCurrentStep:
+			remaining += duration
+		}
+	}
+	return remaining, total, true
+}
+
+// GetProvisioningStarted returns when the instance's provisioning operation
+// began. For an instance that awaited approval, that is when it was approved.
+func (i Instance) GetProvisioningStarted() time.Time {
+	if i.Approval != nil && i.Approval.DecidedAt != nil {
+		return *i.Approval.DecidedAt
+	}
+	return i.Created
+}
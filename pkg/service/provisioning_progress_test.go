@@ -0,0 +1,70 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testSteps = []string{"preProvision", "deployARMTemplate", "createUser"}
+
+var testStepDurations = map[string]time.Duration{
+	"preProvision":      time.Second,
+	"deployARMTemplate": 10 * time.Minute,
+	"createUser":        time.Minute,
+}
+
+func TestGetExpectedDurations(t *testing.T) {
+	now := time.Now()
+	progress := ProvisioningProgress{
+		Steps:              testSteps,
+		CurrentStep:        "deployARMTemplate",
+		CurrentStepStarted: now.Add(-4 * time.Minute),
+	}
+	remaining, total, ok :=
+		progress.GetExpectedDurations(now, testStepDurations)
+	assert.True(t, ok)
+	assert.Equal(t, 7*time.Minute, remaining)
+	assert.Equal(t, 11*time.Minute+time.Second, total)
+}
+
+func TestGetExpectedDurationsWithOverrunningStep(t *testing.T) {
+	now := time.Now()
+	progress := ProvisioningProgress{
+		Steps:              testSteps,
+		CurrentStep:        "deployARMTemplate",
+		CurrentStepStarted: now.Add(-time.Hour),
+	}
+	remaining, _, ok := progress.GetExpectedDurations(now, testStepDurations)
+	assert.True(t, ok)
+	assert.Equal(t, time.Minute, remaining)
+}
+
+func TestGetExpectedDurationsOnceStepsAreExecuted(t *testing.T) {
+	progress := ProvisioningProgress{
+		Steps: testSteps,
+	}
+	remaining, _, ok :=
+		progress.GetExpectedDurations(time.Now(), testStepDurations)
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), remaining)
+}
+
+func TestGetExpectedDurationsWithUnestimatedStep(t *testing.T) {
+	progress := ProvisioningProgress{
+		Steps:       []string{"preProvision", "configureFirewall"},
+		CurrentStep: "preProvision",
+	}
+	_, _, ok := progress.GetExpectedDurations(time.Now(), testStepDurations)
+	assert.False(t, ok)
+}
+
+func TestGetProvisioningStarted(t *testing.T) {
+	created := time.Now().Add(-time.Hour)
+	instance := Instance{Created: created}
+	assert.Equal(t, created, instance.GetProvisioningStarted())
+	decidedAt := created.Add(30 * time.Minute)
+	instance.Approval = &ApprovalState{DecidedAt: &decidedAt}
+	assert.Equal(t, decidedAt, instance.GetProvisioningStarted())
+}
@@ -0,0 +1,16 @@
+package service
+
+import "time"
+
+// QuarantineState records an operator's isolation of an instance during a
+// security incident
+type QuarantineState struct {
+	// Reason is the operator's explanation for quarantining the instance, if
+	// one was given
+	Reason        string     `json:"reason,omitempty"`
+	QuarantinedAt *time.Time `json:"quarantinedAt,omitempty"`
+	ReleasedAt    *time.Time `json:"releasedAt,omitempty"`
+	// RevokedBindingIDs are the ids of the bindings whose credentials were
+	// revoked. They remain revoked once the instance is released.
+	RevokedBindingIDs []string `json:"revokedBindingIds,omitempty"`
+}
@@ -1,5 +1,10 @@
 package service
 
+import (
+	"context"
+	"time"
+)
+
 // ServiceManager is an interface to be implemented by module components
 // responsible for managing the lifecycle of services and plans thereof
 type ServiceManager interface { // nolint: golint
@@ -30,13 +35,19 @@ type ServiceManager interface { // nolint: golint
 	// ValidateBindingParameters validates the provided bindingParameters and
 	// returns an error if there is any problem
 	ValidateBindingParameters(BindingParameters) error
-	// Bind synchronously binds to a service
+	// Bind synchronously binds to a service. The instance passed in is fully
+	// hydrated-- its provisioning parameters, updating parameters, and details
+	// (decrypted) are all available-- so binding can honor choices made when
+	// the instance was provisioned.
 	Bind(Instance, BindingParameters) (BindingDetails, error)
 	// GetEmptyBindingDetails returns an empty instance of service-specific
 	// bindingDetails
 	GetEmptyBindingDetails() BindingDetails
 	// GetCredentials returns service-specific credentials populated from instance
-	// and binding details
+	// and binding details. Credentials are returned verbatim to the platform, so
+	// implementations must take care to copy only those instance-level values
+	// that are meant to be shared with bound applications (e.g. never an
+	// instance's administrative password).
 	GetCredentials(Instance, Binding) (Credentials, error)
 	// Unbind synchronously unbinds from a service
 	Unbind(Instance, BindingDetails) error
@@ -44,3 +55,205 @@ type ServiceManager interface { // nolint: golint
 	// must execute asynchronously to deprovision a service
 	GetDeprovisioner(Plan) (Deprovisioner, error)
 }
+
+// DataCopier is an interface to be optionally implemented by the
+// ServiceManagers of cloneable services that, in addition to a source
+// instance's configuration, are able to copy its data into a clone
+type DataCopier interface {
+	// GetDataCopyDetails returns the initial instance details for a clone of the
+	// given source instance. The module's provisioner is expected to use these
+	// to copy (e.g. restore) the source instance's data into the clone.
+	GetDataCopyDetails(source Instance) (InstanceDetails, error)
+}
+
+// ConnectivityValidator is an interface to be optionally implemented by the
+// ServiceManagers of modules whose instances (e.g. databases and caches) the
+// broker can connect to once they are provisioned. Where the broker is
+// configured to do so, it uses this to verify that a new instance is actually
+// reachable with the credentials generated for it before the instance is
+// considered provisioned.
+type ConnectivityValidator interface {
+	// ValidateConnectivity opens a connection to the given instance using the
+	// credentials generated for it. If the instance's network restrictions leave
+	// the broker unable to reach it, validation is skipped and false is
+	// returned.
+	ValidateConnectivity(context.Context, Instance) (bool, error)
+}
+
+// ProvisioningParametersDefaulter is an interface to be optionally implemented
+// by the ServiceManagers of modules that substitute default values for
+// provisioning parameters that weren't specified, or that normalize those that
+// were. It lets the broker record the parameters an instance is effectively
+// provisioned with, alongside those that were requested.
+type ProvisioningParametersDefaulter interface {
+	// ApplyProvisioningParametersDefaults sets, in place, each unspecified
+	// parameter to the value that will be used in its stead and normalizes any
+	// others exactly as provisioning will. The parameters given have already
+	// been validated.
+	ApplyProvisioningParametersDefaults(ProvisioningParameters) error
+}
+
+// ProvisioningStepEstimator is an interface to be optionally implemented by
+// the ServiceManagers of modules that can say, in advance, roughly how long
+// each of their provisioning steps takes. The broker prefers the durations it
+// has observed for a plan's steps and falls back on these estimates for steps
+// it has yet to observe, when telling platforms polling an operation how long
+// it is expected to take.
+type ProvisioningStepEstimator interface {
+	// GetProvisioningStepEstimates returns, keyed by step name, how long each of
+	// the provisioning steps for the given plan is expected to take
+	GetProvisioningStepEstimates(Plan) map[string]time.Duration
+}
+
+// LocationValidator is an interface to be optionally implemented by the
+// ServiceManagers of modules whose provisioning parameters are subject to
+// limits that vary from region to region (e.g. the capacity or redundancy
+// options Azure offers in each)
+type LocationValidator interface {
+	// ValidateProvisioningParametersForLocation validates the given provisioning
+	// parameters against the limits that apply in the given location. The
+	// parameters given have already been validated by
+	// ValidateProvisioningParameters.
+	ValidateProvisioningParametersForLocation(
+		pp ProvisioningParameters,
+		location string,
+	) error
+}
+
+// IdleDetector is an interface to be optionally implemented by the
+// ServiceManagers of modules that can tell, from Azure Monitor metrics,
+// whether an instance's underlying resources are being used. Where the broker
+// is configured to do so, it periodically looks for idle instances and acts
+// on them according to policy.
+type IdleDetector interface {
+	// IsIdle returns a bool indicating whether the given instance's underlying
+	// resources have been idle for the whole of the given period
+	IsIdle(ctx context.Context, instance Instance, period time.Duration) (
+		bool,
+		error,
+	)
+}
+
+// Suspender is an interface to be optionally implemented by the
+// ServiceManagers of modules whose instances' underlying resources can be
+// suspended (e.g. a virtual machine deallocated) to save costs while they are
+// idle, and later resumed
+type Suspender interface {
+	IdleDetector
+	// Suspend suspends the given instance's underlying resources and returns
+	// the instance's updated details
+	Suspend(ctx context.Context, instance Instance) (InstanceDetails, error)
+	// Resume reverses Suspend and returns the instance's updated details
+	Resume(ctx context.Context, instance Instance) (InstanceDetails, error)
+}
+
+// DriftDetector is an interface to be optionally implemented by the
+// ServiceManagers of modules that can read the live state of an instance's
+// underlying resources back from Azure and compare it to the state that
+// provisioning (or the most recent update) put them in. Where the broker is
+// configured to do so, it periodically checks instances for drift and reports
+// it and, according to policy, re-applies the desired state by executing the
+// service's updating steps with the instance's current parameters, so a
+// module's updater should restore any setting it reports as drifted.
+type DriftDetector interface {
+	// DetectDrift returns the ways in which the given instance's underlying
+	// resources currently differ from its desired state, or none if they
+	// don't
+	DetectDrift(ctx context.Context, instance Instance) ([]Drift, error)
+}
+
+// Quarantiner is an interface to be optionally implemented by the
+// ServiceManagers of modules whose instances' underlying resources can be
+// isolated during a security incident without being deprovisioned. The broker
+// revokes the instance's bindings itself, by unbinding them, so a Quarantiner
+// need only deny network access to the resources and revoke any credentials
+// that bindings share with the instance (e.g. account keys).
+type Quarantiner interface {
+	// Quarantine denies all network access to the given instance's underlying
+	// resources and returns the instance's updated details. It may be invoked
+	// again for an instance whose quarantine failed part way.
+	Quarantine(ctx context.Context, instance Instance) (InstanceDetails, error)
+	// Release restores the network access that Quarantine denied and returns
+	// the instance's updated details. Revoked credentials are not restored.
+	Release(ctx context.Context, instance Instance) (InstanceDetails, error)
+}
+
+// CredentialRotator is an interface to be optionally implemented by the
+// ServiceManagers of modules whose bindings each have a credential of their
+// own (e.g. a database login) that can be regenerated without unbinding.
+// Where the broker is configured to do so, it regenerates such credentials
+// once their TTL elapses and delivers the new ones to its secret store.
+type CredentialRotator interface {
+	// RotateCredentials replaces the given binding's credential with a new one,
+	// revoking the old one, and returns the binding's updated details
+	RotateCredentials(ctx context.Context, instance Instance, binding Binding) (
+		BindingDetails,
+		error,
+	)
+}
+
+// Activator is an interface to be optionally implemented by the
+// ServiceManagers of modules whose instances can be provisioned now but
+// activated (i.e. made to start accepting traffic and incurring most of their
+// cost) at a later, scheduled time
+type Activator interface {
+	// Deactivate puts a newly provisioned instance's underlying resources into
+	// their stopped or disabled state and returns the instance's updated details
+	Deactivate(ctx context.Context, instance Instance) (InstanceDetails, error)
+	// Activate reverses Deactivate and returns the instance's updated details
+	Activate(ctx context.Context, instance Instance) (InstanceDetails, error)
+}
+
+// SKUMapper is an interface to be optionally implemented by the
+// ServiceManagers of modules whose instances' cost depends on the SKUs (e.g.
+// virtual machine sizes) chosen at provisioning time. Where the broker has
+// been given a pricing table, it uses this to estimate what a new instance
+// will cost.
+type SKUMapper interface {
+	// GetSKUUsages returns the SKUs, by the names used in the pricing table, that
+	// an instance provisioned using the given plan and provisioning parameters
+	// would use. The parameters given have already been validated.
+	GetSKUUsages(Plan, ProvisioningParameters) ([]SKUUsage, error)
+}
+
+// BlueGreenUpdater is an interface to be optionally implemented by the
+// ServiceManagers of modules for which some updates (e.g. major version
+// upgrades) are too risky to apply in place. The broker applies such an update
+// by provisioning a replacement ("green") instance alongside the existing
+// ("blue") one, validating it, cutting the instance's bindings over to it and
+// only then deprovisioning the blue instance's underlying resources. If the
+// green instance fails to provision or to validate, it is deprovisioned
+// instead and the blue instance is left untouched.
+type BlueGreenUpdater interface {
+	// IsBlueGreenUpdate returns whether the given updating parameters and plan
+	// should be applied to the given instance by replacing it. The parameters
+	// given have already been validated.
+	IsBlueGreenUpdate(Instance, Plan, UpdatingParameters) bool
+	// GetGreenInstance returns the provisioning parameters and initial details
+	// of a green instance that applies the given updating parameters and plan
+	// to the given blue instance. It is here that a module directs its
+	// provisioner to copy (e.g. restore) the blue instance's data, as with
+	// DataCopier. The green instance shares the blue instance's location and
+	// resource group, so its resources must be named apart from the blue
+	// instance's.
+	GetGreenInstance(blue Instance, plan Plan, up UpdatingParameters) (
+		ProvisioningParameters,
+		InstanceDetails,
+		error,
+	)
+	// ValidateGreenInstance verifies that a newly provisioned green instance,
+	// and any data copied into it, are fit to replace the blue instance. An
+	// error causes the green instance to be rolled back.
+	ValidateGreenInstance(ctx context.Context, blue Instance, green Instance) error
+	// CutOverBinding issues the given binding credentials for the instance it is
+	// being cut over to, and returns the binding's updated details. Bindings are
+	// cut over from blue to green and, if a rollback follows a partial cut over,
+	// back again. Credentials that a binding held for the instance it is cut
+	// over from are revoked when that instance is deprovisioned.
+	CutOverBinding(
+		ctx context.Context,
+		from Instance,
+		to Instance,
+		binding Binding,
+	) (BindingDetails, error)
+}
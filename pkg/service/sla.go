@@ -0,0 +1,101 @@
+package service
+
+import (
+	"fmt"
+	"time"
+)
+
+// ProvisioningSLA describes how long provisioning an instance of each plan is
+// expected to take. The zero value sets no target for any plan.
+type ProvisioningSLA struct {
+	defaultTarget time.Duration
+	targetsByPlan map[string]time.Duration
+}
+
+// NewProvisioningSLA returns a ProvisioningSLA whose target is the given
+// default target, except for those plans that have a target of their own.
+// Plans are identified as serviceName/planName. A target of zero means there
+// is no target.
+func NewProvisioningSLA(
+	defaultTarget time.Duration,
+	targetsByPlan map[string]time.Duration,
+) (ProvisioningSLA, error) {
+	if defaultTarget < 0 {
+		return ProvisioningSLA{}, fmt.Errorf(
+			"default target may not be negative: %s",
+			defaultTarget,
+		)
+	}
+	for plan, target := range targetsByPlan {
+		if target < 0 {
+			return ProvisioningSLA{}, fmt.Errorf(
+				`target for plan "%s" may not be negative: %s`,
+				plan,
+				target,
+			)
+		}
+	}
+	return ProvisioningSLA{
+		defaultTarget: defaultTarget,
+		targetsByPlan: targetsByPlan,
+	}, nil
+}
+
+// GetPlanKeys returns the serviceName/planName keys of all plans that have a
+// target of their own
+func (p ProvisioningSLA) GetPlanKeys() []string {
+	keys := make([]string, 0, len(p.targetsByPlan))
+	for key := range p.targetsByPlan {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// GetTarget returns the target duration for provisioning an instance of the
+// given plan of the given service, along with a bool indicating whether there
+// is a target at all
+func (p ProvisioningSLA) GetTarget(
+	svc Service,
+	plan Plan,
+) (time.Duration, bool) {
+	target := p.defaultTarget
+	if planTarget, ok := p.targetsByPlan[GetPlanKey(svc, plan)]; ok {
+		target = planTarget
+	}
+	return target, target > 0
+}
+
+// GetPlanKey returns the serviceName/planName key by which the given plan of
+// the given service is identified in configuration
+func GetPlanKey(svc Service, plan Plan) string {
+	return fmt.Sprintf("%s/%s", svc.GetName(), plan.GetName())
+}
+
+// ProvisioningTiming records how long an instance took to provision and how
+// that compares to the provisioning SLA that applied to it. Target and
+// BreachedSLA are left empty if no target applied.
+type ProvisioningTiming struct {
+	Started     time.Time     `json:"started"`
+	Completed   time.Time     `json:"completed"`
+	Duration    time.Duration `json:"duration"`
+	Target      time.Duration `json:"target,omitempty"`
+	BreachedSLA bool          `json:"breachedSLA,omitempty"`
+}
+
+// ProvisioningSLAOutcomes counts the instances of a single plan that were
+// provisioned within, and not within, the plan's provisioning SLA target
+type ProvisioningSLAOutcomes struct {
+	WithinTarget int64
+	Breached     int64
+}
+
+// GetAttainment returns the fraction, between 0 and 1, of instances that were
+// provisioned within target, along with a bool indicating whether any were
+// counted at all
+func (p ProvisioningSLAOutcomes) GetAttainment() (float64, bool) {
+	total := p.WithinTarget + p.Breached
+	if total == 0 {
+		return 0, false
+	}
+	return float64(p.WithinTarget) / float64(total), true
+}
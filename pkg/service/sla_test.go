@@ -0,0 +1,53 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProvisioningSLAWithNegativeTarget(t *testing.T) {
+	_, err := NewProvisioningSLA(-time.Minute, nil)
+	assert.NotNil(t, err)
+	_, err = NewProvisioningSLA(
+		time.Minute,
+		map[string]time.Duration{"svc/plan": -time.Minute},
+	)
+	assert.NotNil(t, err)
+}
+
+func TestProvisioningSLAGetTarget(t *testing.T) {
+	svc := NewService(&ServiceProperties{Name: "svc"}, nil)
+	plan := NewPlan(&PlanProperties{Name: "plan"})
+	otherPlan := NewPlan(&PlanProperties{Name: "other"})
+
+	_, ok := ProvisioningSLA{}.GetTarget(svc, plan)
+	assert.False(t, ok)
+
+	sla, err := NewProvisioningSLA(
+		10*time.Minute,
+		map[string]time.Duration{
+			"svc/plan":  30 * time.Minute,
+			"svc/other": 0,
+		},
+	)
+	assert.Nil(t, err)
+	target, ok := sla.GetTarget(svc, plan)
+	assert.True(t, ok)
+	assert.Equal(t, 30*time.Minute, target)
+	// A per-plan target of zero exempts the plan from the default target
+	_, ok = sla.GetTarget(svc, otherPlan)
+	assert.False(t, ok)
+}
+
+func TestProvisioningSLAOutcomesGetAttainment(t *testing.T) {
+	_, ok := ProvisioningSLAOutcomes{}.GetAttainment()
+	assert.False(t, ok)
+	attainment, ok := ProvisioningSLAOutcomes{
+		WithinTarget: 3,
+		Breached:     1,
+	}.GetAttainment()
+	assert.True(t, ok)
+	assert.Equal(t, 0.75, attainment)
+}
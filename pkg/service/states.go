@@ -1,6 +1,14 @@
 package service
 
 const (
+	// InstanceStateAwaitingApproval represents the state where a service
+	// instance's provisioning is on hold until an external approval service
+	// approves it
+	InstanceStateAwaitingApproval = "AWAITING_APPROVAL"
+	// InstanceStateQueued represents the state where a service instance's
+	// provisioning was accepted while its module was at its cap on concurrent
+	// provisions, and is waiting for capacity to free up
+	InstanceStateQueued = "QUEUED"
 	// InstanceStateProvisioning represents the state where service instance
 	// provisioning is in progress
 	InstanceStateProvisioning = "PROVISIONING"
@@ -10,6 +18,10 @@ const (
 	// InstanceStateProvisioningFailed represents the state where service instance
 	// provisioning has failed
 	InstanceStateProvisioningFailed = "PROVISIONING_FAILED"
+	// InstanceStateProvisioningDegraded represents the state where a service
+	// instance provisioning step has failed, but provisioning is still being
+	// retried within the service's failure grace window
+	InstanceStateProvisioningDegraded = "PROVISIONING_DEGRADED"
 	// InstanceStateUpdating represents the state where service instance
 	// updating is in progress
 	InstanceStateUpdating = "UPDATING"
@@ -27,6 +39,22 @@ const (
 	// InstanceStateDeprovisioningFailed represents the state where service
 	// instance deprovisioning has failed
 	InstanceStateDeprovisioningFailed = "DEPROVISIONING_FAILED"
+	// InstanceStateQuarantining represents the state where service instance
+	// quarantine is in progress
+	InstanceStateQuarantining = "QUARANTINING"
+	// InstanceStateQuarantined represents the state where service instance
+	// has been isolated, with its network access denied and its bindings'
+	// credentials revoked
+	InstanceStateQuarantined = "QUARANTINED"
+	// InstanceStateQuarantiningFailed represents the state where service
+	// instance quarantine has failed. The instance may be partially isolated.
+	InstanceStateQuarantiningFailed = "QUARANTINING_FAILED"
+	// InstanceStateReleasing represents the state where release of a
+	// quarantined service instance is in progress
+	InstanceStateReleasing = "RELEASING"
+	// InstanceStateReleasingFailed represents the state where release of a
+	// quarantined service instance has failed
+	InstanceStateReleasingFailed = "RELEASING_FAILED"
 	// BindingStateBound represents the state where service binding has completed
 	// successfully
 	BindingStateBound = "BOUND"
@@ -36,4 +64,7 @@ const (
 	// BindingStateUnbindingFailed represents the state where service unbinding
 	// has failed
 	BindingStateUnbindingFailed = "UNBINDING_FAILED"
+	// BindingStateRevoked represents the state where service binding's
+	// credentials were revoked when its instance was quarantined
+	BindingStateRevoked = "REVOKED"
 )
@@ -0,0 +1,26 @@
+package service
+
+import "time"
+
+// StepExecution is the durable record of the execution of a single
+// provisioning step. When so configured, the broker persists it before the
+// step is executed and again once it completes, so that a task re-delivered
+// after a restart of the broker can tell whether the step it names has
+// already completed.
+type StepExecution struct {
+	// Token identifies the execution. It is carried by every task that
+	// executes the step on the instance's behalf, including those that retry
+	// it, and is assigned before the first of those tasks is submitted.
+	Token string `json:"token"`
+	// RetryCount is the retry count of the task that last started the step
+	RetryCount int `json:"retryCount"`
+	// Started is nil while the step is yet to be executed
+	Started   *time.Time `json:"started,omitempty"`
+	Completed *time.Time `json:"completed,omitempty"`
+	// Resumptions is how many times the step was started again after its
+	// execution was interrupted
+	Resumptions int `json:"resumptions,omitempty"`
+}
+
+// StepExecutions are keyed by step name
+type StepExecutions map[string]StepExecution
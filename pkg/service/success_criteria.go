@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// SuccessCriterion is a check, beyond its resources having been created, that
+// an instance must pass before it is considered provisioned-- e.g. that a
+// database's schema has been seeded or a cache has been preloaded with data.
+// Criteria are checked after all of a provisioner's steps, as further steps of
+// their own, so a criterion that fails is retried (or, for a green instance,
+// rolls back a blue-green update) exactly as a failing step would be.
+type SuccessCriterion struct {
+	// Name identifies the criterion, among its plan's provisioning steps, in
+	// configuration and in an instance's provisioning progress
+	Name string
+	// Check returns an error if the instance doesn't meet the criterion.
+	// Details it returns are persisted like those of any provisioning step.
+	Check ProvisioningStepFunction
+	// Optional indicates that operators may configure the broker to skip the
+	// criterion
+	Optional bool
+	// Timeout, if non-zero, bounds how long a single check may take. A check
+	// that times out fails with an error in the transient category.
+	Timeout time.Duration
+}
+
+// SuccessCriteriaProvider is an interface to be optionally implemented by the
+// ServiceManagers of modules whose instances aren't ready for use merely
+// because their resources exist
+type SuccessCriteriaProvider interface {
+	// GetSuccessCriteria returns, in the order they are to be checked, the
+	// criteria that instances of the given plan must meet
+	GetSuccessCriteria(Plan) []SuccessCriterion
+}
+
+// getProvisioningStep returns a ProvisioningStep that checks the criterion
+func (s SuccessCriterion) getProvisioningStep() ProvisioningStep {
+	return NewProvisioningStep(
+		s.Name,
+		func(ctx context.Context, instance Instance) (InstanceDetails, error) {
+			if s.Timeout <= 0 {
+				return s.Check(ctx, instance)
+			}
+			ctx, cancel := context.WithTimeout(ctx, s.Timeout)
+			defer cancel()
+			details, err := s.Check(ctx, instance)
+			if err != nil && ctx.Err() == context.DeadlineExceeded {
+				return nil, NewCategorizedError(
+					ErrorCategoryTransient,
+					fmt.Errorf(
+						`success criterion "%s" timed out after %s: %s`,
+						s.Name,
+						s.Timeout,
+						err,
+					),
+				)
+			}
+			return details, err
+		},
+	)
+}
@@ -0,0 +1,23 @@
+package service
+
+import "time"
+
+// SuspensionState records what the broker has done about an instance whose
+// underlying resources were found to be idle
+type SuspensionState struct {
+	// Suspended indicates whether the instance's underlying resources are
+	// currently suspended. A suspended instance is resumed by updating it.
+	Suspended   bool       `json:"suspended"`
+	SuspendedAt *time.Time `json:"suspendedAt,omitempty"`
+	ResumedAt   *time.Time `json:"resumedAt,omitempty"`
+	// IdleNotifiedAt is when subscribers were notified that the instance is
+	// idle. It is cleared once the instance is found to be in use again so
+	// that subscribers are notified only once per idle spell.
+	IdleNotifiedAt *time.Time `json:"idleNotifiedAt,omitempty"`
+}
+
+// IsSuspended returns a bool indicating whether the instance's underlying
+// resources are currently suspended
+func (i Instance) IsSuspended() bool {
+	return i.Suspension != nil && i.Suspension.Suspended
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MetadataTagPrefix prefixes the name of every tag the broker derives from its
+// own metadata. Tags specified when provisioning may not use it.
+const MetadataTagPrefix = "osba-"
+
+const (
+	// maxTags is the number of tags Azure permits on a single resource. One of
+	// them is always the broker's heritage tag.
+	maxTags = 50
+	// maxTagNameLength and maxTagValueLength are the lengths Azure permits of
+	// tag names and values. (Some resource types, e.g. storage accounts, permit
+	// shorter names.)
+	maxTagNameLength  = 512
+	maxTagValueLength = 256
+)
+
+// TagMetadata represents an item of broker metadata that can be applied, as a
+// tag, to provisioned resources
+type TagMetadata string
+
+const (
+	// TagMetadataOrganizationGUID is the GUID of the organization on whose
+	// behalf an instance was provisioned
+	TagMetadataOrganizationGUID TagMetadata = "organizationGUID"
+	// TagMetadataSpaceGUID is the GUID of the space on whose behalf an
+	// instance was provisioned
+	TagMetadataSpaceGUID TagMetadata = "spaceGUID"
+	// TagMetadataPlan is the name of the plan an instance was provisioned with
+	TagMetadataPlan TagMetadata = "plan"
+	// TagMetadataProvisioned is the time at which an instance was provisioned
+	TagMetadataProvisioned TagMetadata = "provisioned"
+	// TagMetadataBrokerID identifies the broker that provisioned an instance
+	TagMetadataBrokerID TagMetadata = "brokerID"
+)
+
+// metadataTagNames maps each item of tag metadata to the name of the tag it
+// is applied as
+var metadataTagNames = map[TagMetadata]string{
+	TagMetadataOrganizationGUID: MetadataTagPrefix + "organization-guid",
+	TagMetadataSpaceGUID:        MetadataTagPrefix + "space-guid",
+	TagMetadataPlan:             MetadataTagPrefix + "plan",
+	TagMetadataProvisioned:      MetadataTagPrefix + "provisioned",
+	TagMetadataBrokerID:         MetadataTagPrefix + "broker-id",
+}
+
+// ProvisioningMetadata is the broker metadata describing a single instance
+// from which metadata tags are derived. Fields left empty are not applied as
+// tags.
+type ProvisioningMetadata struct {
+	OrganizationGUID string
+	SpaceGUID        string
+	PlanName         string
+	Provisioned      time.Time
+}
+
+// MetadataTagging describes which broker metadata is applied, as tags, to
+// provisioned resources, in addition to any tags specified when provisioning.
+// The zero value applies no metadata tags.
+type MetadataTagging struct {
+	metadata []TagMetadata
+	brokerID string
+}
+
+// NewMetadataTagging returns a MetadataTagging that applies the given items
+// of metadata. A broker ID must be specified if, and only if, it is one of
+// them.
+func NewMetadataTagging(
+	metadata []TagMetadata,
+	brokerID string,
+) (MetadataTagging, error) {
+	used := map[TagMetadata]bool{}
+	for _, m := range metadata {
+		if _, ok := metadataTagNames[m]; !ok {
+			return MetadataTagging{}, fmt.Errorf(`unrecognized metadata "%s"`, m)
+		}
+		if used[m] {
+			return MetadataTagging{}, fmt.Errorf(`duplicate metadata "%s"`, m)
+		}
+		used[m] = true
+	}
+	if used[TagMetadataBrokerID] {
+		if brokerID == "" {
+			return MetadataTagging{}, fmt.Errorf(
+				`a broker ID must be specified to apply metadata "%s"`,
+				TagMetadataBrokerID,
+			)
+		}
+		if len(brokerID) > maxTagValueLength {
+			return MetadataTagging{}, fmt.Errorf(
+				"broker ID may not exceed %d characters",
+				maxTagValueLength,
+			)
+		}
+	}
+	return MetadataTagging{
+		metadata: metadata,
+		brokerID: brokerID,
+	}, nil
+}
+
+// ValidateTags validates tags specified when provisioning. Tags may not use
+// names reserved for metadata tags and, together with metadata tags, must be
+// within Azure's limits.
+func (m MetadataTagging) ValidateTags(tags map[string]string) error {
+	// Leave room for the metadata tags and for the heritage tag
+	if max := maxTags - len(m.metadata) - 1; len(tags) > max {
+		return NewValidationError(
+			"tags",
+			fmt.Sprintf("no more than %d tags may be specified", max),
+		)
+	}
+	for name, value := range tags {
+		if IsMetadataTag(name) {
+			return NewValidationError(
+				"tags",
+				fmt.Sprintf(
+					`invalid tag "%s"; names beginning with "%s" are reserved`,
+					name,
+					MetadataTagPrefix,
+				),
+			)
+		}
+		if len(name) > maxTagNameLength {
+			return NewValidationError(
+				"tags",
+				fmt.Sprintf(
+					`invalid tag "%s"; names may not exceed %d characters`,
+					name,
+					maxTagNameLength,
+				),
+			)
+		}
+		if len(value) > maxTagValueLength {
+			return NewValidationError(
+				"tags",
+				fmt.Sprintf(
+					`invalid tag "%s"; values may not exceed %d characters`,
+					name,
+					maxTagValueLength,
+				),
+			)
+		}
+	}
+	return nil
+}
+
+// ApplyTags returns a copy of the given tags supplemented with metadata tags
+// derived from the given metadata. If no metadata tags apply, the given tags
+// are returned as they are.
+func (m MetadataTagging) ApplyTags(
+	tags map[string]string,
+	pm ProvisioningMetadata,
+) map[string]string {
+	metadataTags := map[string]string{}
+	for _, md := range m.metadata {
+		var value string
+		switch md {
+		case TagMetadataOrganizationGUID:
+			value = pm.OrganizationGUID
+		case TagMetadataSpaceGUID:
+			value = pm.SpaceGUID
+		case TagMetadataPlan:
+			value = pm.PlanName
+		case TagMetadataProvisioned:
+			if !pm.Provisioned.IsZero() {
+				value = pm.Provisioned.UTC().Format(time.RFC3339)
+			}
+		case TagMetadataBrokerID:
+			value = m.brokerID
+		}
+		if value == "" {
+			continue
+		}
+		if len(value) > maxTagValueLength {
+			value = value[:maxTagValueLength]
+		}
+		metadataTags[metadataTagNames[md]] = value
+	}
+	if len(metadataTags) == 0 {
+		return tags
+	}
+	for name, value := range tags {
+		metadataTags[name] = value
+	}
+	return metadataTags
+}
+
+// IsMetadataTag returns a bool indicating whether the named tag is one the
+// broker derives from its own metadata. Like Azure, it disregards case.
+func IsMetadataTag(name string) bool {
+	return strings.HasPrefix(strings.ToLower(name), MetadataTagPrefix)
+}
+
+// WithoutMetadataTags returns a copy of the given tags from which all
+// metadata tags have been removed, leaving only those that were specified
+// when provisioning
+func WithoutMetadataTags(tags map[string]string) map[string]string {
+	if tags == nil {
+		return nil
+	}
+	userTags := make(map[string]string, len(tags))
+	for name, value := range tags {
+		if !IsMetadataTag(name) {
+			userTags[name] = value
+		}
+	}
+	return userTags
+}
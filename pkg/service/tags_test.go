@@ -0,0 +1,118 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMetadataTagging(t *testing.T) {
+	_, err := NewMetadataTagging(
+		[]TagMetadata{TagMetadataPlan, TagMetadataProvisioned},
+		"",
+	)
+	assert.Nil(t, err)
+	_, err = NewMetadataTagging([]TagMetadata{"bogus"}, "")
+	assert.NotNil(t, err)
+	_, err = NewMetadataTagging(
+		[]TagMetadata{TagMetadataPlan, TagMetadataPlan},
+		"",
+	)
+	assert.NotNil(t, err)
+	// The broker ID is required to apply it
+	_, err = NewMetadataTagging([]TagMetadata{TagMetadataBrokerID}, "")
+	assert.NotNil(t, err)
+	_, err = NewMetadataTagging([]TagMetadata{TagMetadataBrokerID}, "broker")
+	assert.Nil(t, err)
+}
+
+func TestApplyTags(t *testing.T) {
+	mt, err := NewMetadataTagging(
+		[]TagMetadata{
+			TagMetadataOrganizationGUID,
+			TagMetadataSpaceGUID,
+			TagMetadataProvisioned,
+		},
+		"",
+	)
+	assert.Nil(t, err)
+	tags := map[string]string{
+		"foo": "bar",
+	}
+	// Metadata that isn't known (here, the space GUID) isn't applied
+	applied := mt.ApplyTags(
+		tags,
+		ProvisioningMetadata{
+			OrganizationGUID: "org-guid",
+			Provisioned: time.Date(
+				2018,
+				time.January,
+				2,
+				3,
+				4,
+				5,
+				0,
+				time.UTC,
+			),
+		},
+	)
+	assert.Equal(
+		t,
+		map[string]string{
+			"foo":                    "bar",
+			"osba-organization-guid": "org-guid",
+			"osba-provisioned":       "2018-01-02T03:04:05Z",
+		},
+		applied,
+	)
+	// The original tags are left as they were
+	assert.Len(t, tags, 1)
+	assert.Equal(
+		t,
+		map[string]string{
+			"foo": "bar",
+		},
+		WithoutMetadataTags(applied),
+	)
+}
+
+func TestApplyTagsWithoutMetadata(t *testing.T) {
+	mt := MetadataTagging{}
+	assert.Nil(t, mt.ApplyTags(nil, ProvisioningMetadata{PlanName: "plan"}))
+}
+
+func TestValidateTags(t *testing.T) {
+	mt, err := NewMetadataTagging([]TagMetadata{TagMetadataPlan}, "")
+	assert.Nil(t, err)
+	assert.Nil(t, mt.ValidateTags(nil))
+	assert.Nil(
+		t,
+		mt.ValidateTags(map[string]string{
+			"foo": "bar",
+		}),
+	)
+	// Metadata tag names are reserved, whatever their case
+	assert.NotNil(
+		t,
+		mt.ValidateTags(map[string]string{
+			"OSBA-plan": "free",
+		}),
+	)
+	assert.NotNil(
+		t,
+		mt.ValidateTags(map[string]string{
+			"foo": strings.Repeat("a", maxTagValueLength+1),
+		}),
+	)
+	// Room is left for the metadata tag and for the heritage tag
+	tags := map[string]string{}
+	for i := 0; i < maxTags-2; i++ {
+		tags[fmt.Sprintf("tag%d", i)] = "value"
+	}
+	assert.Nil(t, mt.ValidateTags(tags))
+	tags["one-too-many"] = "value"
+	assert.NotNil(t, mt.ValidateTags(tags))
+}
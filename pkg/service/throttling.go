@@ -0,0 +1,17 @@
+package service
+
+import "time"
+
+// ResourceProviderThrottle is an interface to be implemented by components
+// that track how heavily Azure is throttling the broker's requests to each
+// resource provider and pace the execution of work accordingly
+type ResourceProviderThrottle interface {
+	// Admit returns zero if work that makes requests to the given resource
+	// providers may proceed now, or otherwise how long to wait before asking
+	// again. Work that is admitted counts against each provider's pace.
+	Admit(resourceProviders []string) time.Duration
+	// GetDispatchRates returns the rate, in tasks per minute, at which work is
+	// currently paced for each resource provider that has shown signs of
+	// throttling
+	GetDispatchRates() map[string]float64
+}
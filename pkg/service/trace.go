@@ -0,0 +1,165 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/redaction"
+)
+
+// DebugTracingMode determines which instances have the execution of their
+// provisioning and updating steps traced
+type DebugTracingMode string
+
+const (
+	// DebugTracingModeOff traces no instance's steps
+	DebugTracingModeOff DebugTracingMode = "off"
+	// DebugTracingModeRequested traces the steps of those instances whose
+	// provisioning requested it with the "debug" parameter
+	DebugTracingModeRequested DebugTracingMode = "requested"
+	// DebugTracingModeAll traces the steps of every instance
+	DebugTracingModeAll DebugTracingMode = "all"
+)
+
+// ParseDebugTracingMode returns the DebugTracingMode with the given name
+func ParseDebugTracingMode(modeStr string) (DebugTracingMode, error) {
+	mode := DebugTracingMode(strings.ToLower(modeStr))
+	switch mode {
+	case DebugTracingModeOff, DebugTracingModeRequested, DebugTracingModeAll:
+		return mode, nil
+	}
+	return "", fmt.Errorf(
+		`unrecognized debug tracing mode "%s"; valid modes are "%s", "%s", and `+
+			`"%s"`,
+		modeStr,
+		DebugTracingModeOff,
+		DebugTracingModeRequested,
+		DebugTracingModeAll,
+	)
+}
+
+// IsTraced returns a bool indicating whether the given instance's steps are
+// traced under the mode
+func (d DebugTracingMode) IsTraced(instance Instance) bool {
+	switch d {
+	case DebugTracingModeAll:
+		return true
+	case DebugTracingModeRequested:
+		return instance.Debug
+	}
+	return false
+}
+
+// StepTrace records what a single execution of a provisioning or updating
+// step was given, what it returned, and the requests it made to Azure. It is
+// meant for diagnosing misbehaving steps, so it captures far more than the
+// broker otherwise persists. Secrets are redacted from all of it: the values
+// of fields tagged `secret:"true"` entirely, and those embedded in other
+// strings, such as connection strings, as they are from the broker's logs.
+type StepTrace struct {
+	// Operation is either "provisioning" or "updating"
+	Operation string    `json:"operation"`
+	StepName  string    `json:"stepName"`
+	Started   time.Time `json:"started"`
+	// Completed is when the step returned, whether or not it succeeded
+	Completed              time.Time              `json:"completed"`
+	ProvisioningParameters map[string]interface{} `json:"provisioningParameters"`
+	UpdatingParameters     map[string]interface{} `json:"updatingParameters,omitempty"` // nolint: lll
+	// InputDetails are the instance's details as they were given to the step
+	InputDetails map[string]interface{} `json:"inputDetails"`
+	// OutputDetails are the details the step returned. They're omitted if the
+	// step failed.
+	OutputDetails map[string]interface{} `json:"outputDetails,omitempty"`
+	Error         string                 `json:"error,omitempty"`
+	// AzureRequests summarize the requests made while the step was executing.
+	// See AzureRequestRecorder for how they're attributed to the step.
+	AzureRequests []AzureRequestSummary `json:"azureRequests"`
+}
+
+// AzureRequestSummary summarizes a single request made to Azure and its
+// response. Neither bodies nor headers are included, except for the ids Azure
+// assigns to requests, which are what Azure support asks for when a request
+// needs investigating.
+type AzureRequestSummary struct {
+	Method string `json:"method"`
+	// URL omits the query string, besides the api-version
+	URL        string        `json:"url"`
+	StatusCode int           `json:"statusCode,omitempty"`
+	Duration   time.Duration `json:"duration"`
+	// RequestID and CorrelationID are the x-ms-request-id and
+	// x-ms-correlation-request-id response headers
+	RequestID     string `json:"requestId,omitempty"`
+	CorrelationID string `json:"correlationId,omitempty"`
+	// Error is set if no response was received
+	Error string `json:"error,omitempty"`
+}
+
+// AzureRequestRecorder is an interface to be implemented by components that
+// observe the requests the broker makes to Azure. Since the Azure clients the
+// modules use aren't told which step they're acting on behalf of, requests
+// are attributed to a recording by the resource group they concern. Steps of
+// different instances that share a resource group and execute at the same
+// time, therefore, have each other's requests recorded too, and requests
+// that concern no resource group, such as those for subscription-level
+// resources, are never recorded.
+type AzureRequestRecorder interface {
+	// StartRecording begins recording the requests made concerning the given
+	// resource group of the given subscription. The function it returns stops
+	// the recording and returns what was recorded. An empty subscription id
+	// matches any subscription.
+	StartRecording(
+		subscriptionID string,
+		resourceGroup string,
+	) func() []AzureRequestSummary
+}
+
+// NewStepTrace returns a trace of the named step of the given operation,
+// started now, whose inputs are taken from the given instance
+func NewStepTrace(
+	operation string,
+	stepName string,
+	instance Instance,
+) StepTrace {
+	return StepTrace{
+		Operation:              operation,
+		StepName:               stepName,
+		Started:                time.Now(),
+		ProvisioningParameters: redactForTrace(instance.ProvisioningParameters),
+		UpdatingParameters:     redactForTrace(instance.UpdatingParameters),
+		InputDetails:           redactForTrace(instance.Details),
+		AzureRequests:          []AzureRequestSummary{},
+	}
+}
+
+// Complete records the outcome of the step: the details it returned, if it
+// succeeded, or the error it failed with
+func (s *StepTrace) Complete(
+	outputDetails InstanceDetails,
+	err error,
+	azureRequests []AzureRequestSummary,
+) {
+	s.Completed = time.Now()
+	if err != nil {
+		s.Error = redaction.RedactText(err.Error(), redaction.ModeFull)
+	} else {
+		s.OutputDetails = redactForTrace(outputDetails)
+	}
+	if azureRequests != nil {
+		s.AzureRequests = azureRequests
+	}
+}
+
+// redactForTrace returns the given parameters or details as a map, with the
+// values of their secret fields, and any secrets embedded in their other
+// strings, redacted. Anything that can't be represented as a map is recorded
+// only by its type.
+func redactForTrace(v interface{}) map[string]interface{} {
+	redacted, err := RedactSecrets(v)
+	if err != nil {
+		return map[string]interface{}{
+			"unrepresentable": fmt.Sprintf("%T", v),
+		}
+	}
+	return redaction.RedactParameters(redacted, redaction.ModeFull)
+}
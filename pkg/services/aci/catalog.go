@@ -10,6 +10,7 @@ func (m *module) GetCatalog() (service.Catalog, error) {
 				Name:        "azure-aci",
 				Description: "Azure Container Instance (Experimental)",
 				Bindable:    true,
+				Cloneable:   true,
 				Tags:        []string{"Azure", "Container", "Instance"},
 			},
 			m.serviceManager,
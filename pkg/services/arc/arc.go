@@ -0,0 +1,47 @@
+package arc
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arc"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer arm.Deployer
+	arcManager  arc.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of registering servers and Kubernetes clusters
+// hosted outside of Azure with Azure Arc
+func New(
+	armDeployer arm.Deployer,
+	arcManager arc.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer: armDeployer,
+			arcManager:  arcManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "arc"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{
+		"Microsoft.HybridCompute",
+		"Microsoft.Kubernetes",
+		"Microsoft.Authorization",
+	}
+}
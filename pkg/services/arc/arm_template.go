@@ -0,0 +1,80 @@
+package arc
+
+// nolint: lll
+var armTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    {{- if .createMachine }}
+    "machineName": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of the Azure Arc-enabled server"
+      }
+    },
+    {{- end }}
+    {{- if .assignOnboardingRole }}
+    "onboardingRoleAssignmentName": {
+      "type": "string"
+    },
+    "onboardingRoleDefinitionId": {
+      "type": "string"
+    },
+    "onboardingPrincipalId": {
+      "type": "string",
+      "metadata": {
+        "description": "Object ID of the service principal allowed to connect the server or cluster to Azure Arc"
+      }
+    },
+    {{- end }}
+    "tags": {
+      "type": "object"
+    }
+  },
+  "resources": [
+    {{- if .createMachine }}
+    {
+      "apiVersion": "2024-07-10",
+      "name": "[parameters('machineName')]",
+      "type": "Microsoft.HybridCompute/machines",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "identity": {
+        "type": "SystemAssigned"
+      },
+      "properties": {}
+    }{{ if .assignOnboardingRole }},{{ end }}
+    {{- end }}
+    {{- if .assignOnboardingRole }}
+    {
+      "apiVersion": "2022-04-01",
+      "name": "[parameters('onboardingRoleAssignmentName')]",
+      "type": "Microsoft.Authorization/roleAssignments",
+      {{- if .createMachine }}
+      "scope": "[resourceId('Microsoft.HybridCompute/machines', parameters('machineName'))]",
+      "dependsOn": [
+        "[resourceId('Microsoft.HybridCompute/machines', parameters('machineName'))]"
+      ],
+      {{- end }}
+      "properties": {
+        "roleDefinitionId": "[subscriptionResourceId('Microsoft.Authorization/roleDefinitions', parameters('onboardingRoleDefinitionId'))]",
+        "principalId": "[parameters('onboardingPrincipalId')]",
+        "principalType": "ServicePrincipal"
+      }
+    }
+    {{- end }}
+  ],
+  "outputs": {
+    {{- if .createMachine }}
+    "machineId": {
+      "type": "string",
+      "value": "[resourceId('Microsoft.HybridCompute/machines', parameters('machineName'))]"
+    }
+    {{- end }}
+  }
+}
+`)
@@ -0,0 +1,175 @@
+package arc
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+// defaultRoles maps each plan to the role that a binding assigns unless the
+// binding parameters name another; each is the plan's least privileged role
+var defaultRoles = map[string]string{
+	planServer:     "Reader",
+	planKubernetes: "Azure Arc Kubernetes Viewer",
+}
+
+// roleDefinitionIDs maps each plan to the names of the built-in roles that a
+// binding may assign and their role definition IDs
+var roleDefinitionIDs = map[string]map[string]string{
+	planServer: {
+		"Azure Connected Machine Resource Administrator": "cd570a14-e51a-42ad-bac8-bafd67325302", // nolint: lll
+		"Reader": "acdd72a7-3385-48ef-bd42-f606fba81ae7", // nolint: lll
+	},
+	planKubernetes: {
+		"Azure Arc Kubernetes Viewer":        "63f0a09d-1495-4db4-a681-037d84835eb4", // nolint: lll
+		"Azure Arc Kubernetes Writer":        "5b999177-9696-4545-85c7-50de3797e5a1", // nolint: lll
+		"Azure Arc Kubernetes Admin":         "dffb1e0c-446f-4dde-a09f-99eb5cc68b96", // nolint: lll
+		"Azure Arc Kubernetes Cluster Admin": "8393591c-06b9-48a2-a542-1bd6b377f6a2", // nolint: lll
+	},
+}
+
+func (s *serviceManager) ValidateBindingParameters(
+	bindingParameters service.BindingParameters,
+) error {
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return errors.New(
+			"error casting bindingParameters as *arc.BindingParameters",
+		)
+	}
+	if !objectIDRegex.MatchString(bp.PrincipalID) {
+		return service.NewValidationError(
+			"principalId",
+			fmt.Sprintf(`invalid principalId: "%s"`, bp.PrincipalID),
+		)
+	}
+	// Which roles are allowed depends upon the plan, which isn't known here, so
+	// the role is only checked against the plan when binding
+	if bp.Role != "" && !isKnownRole(bp.Role) {
+		return service.NewValidationError(
+			"role",
+			fmt.Sprintf(`invalid role: "%s"`, bp.Role),
+		)
+	}
+	return nil
+}
+
+// Bind assigns the requested role to the principal named in the binding
+// parameters, scoped to the server or cluster alone. A cluster can only be
+// bound to once it has been connected, since its resource doesn't exist until
+// then.
+func (s *serviceManager) Bind(
+	instance service.Instance,
+	bindingParameters service.BindingParameters,
+) (service.BindingDetails, error) {
+	dt, ok := instance.Details.(*arcInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *arcInstanceDetails",
+		)
+	}
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting bindingParameters as *arc.BindingParameters",
+		)
+	}
+	planName := instance.Plan.GetName()
+	bd := &arcBindingDetails{
+		PrincipalID:        bp.PrincipalID,
+		Role:               bp.Role,
+		RoleAssignmentName: uuid.NewV4().String(),
+	}
+	if bd.Role == "" {
+		bd.Role = defaultRoles[planName]
+	}
+	roleDefinitionID, ok := roleDefinitionIDs[planName][bd.Role]
+	if !ok {
+		return nil, fmt.Errorf(
+			`role "%s" cannot be assigned for plan "%s"; allowed values are: %s`,
+			bd.Role,
+			planName,
+			strings.Join(getRoleNames(planName), ", "),
+		)
+	}
+	if planName == planKubernetes {
+		_, found, err := s.arcManager.GetConnectedCluster(
+			dt.ResourceName,
+			instance.ResourceGroup,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return nil, fmt.Errorf(
+				`Kubernetes cluster "%s" has not been connected to Azure Arc yet`,
+				dt.ResourceName,
+			)
+		}
+	}
+	if err := s.arcManager.CreateRoleAssignment(
+		dt.ResourceID,
+		bd.RoleAssignmentName,
+		roleDefinitionID,
+		bd.PrincipalID,
+	); err != nil {
+		return nil, err
+	}
+	return bd, nil
+}
+
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	binding service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*arcInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *arcInstanceDetails",
+		)
+	}
+	bd, ok := binding.Details.(*arcBindingDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting binding.Details as *arcBindingDetails",
+		)
+	}
+	return &Credentials{
+		ResourceName:   dt.ResourceName,
+		SubscriptionID: s.arcManager.GetSubscriptionID(),
+		ResourceGroup:  instance.ResourceGroup,
+		TenantID:       s.arcManager.GetTenantID(),
+		Location:       instance.Location,
+		Scope:          dt.ResourceID,
+		PrincipalID:    bd.PrincipalID,
+		Role:           bd.Role,
+		RoleAssignmentID: fmt.Sprintf(
+			"%s/providers/Microsoft.Authorization/roleAssignments/%s",
+			dt.ResourceID,
+			bd.RoleAssignmentName,
+		),
+		OnboardingScript: dt.OnboardingScript,
+	}, nil
+}
+
+func isKnownRole(role string) bool {
+	for _, roles := range roleDefinitionIDs {
+		if _, ok := roles[role]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func getRoleNames(planName string) []string {
+	roles := make([]string, 0, len(roleDefinitionIDs[planName]))
+	for role := range roleDefinitionIDs[planName] {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles
+}
@@ -0,0 +1,37 @@
+package arc
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+const (
+	planServer     = "server"
+	planKubernetes = "kubernetes"
+)
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:          "57f88747-5e4f-48e9-92a5-fa299336c52f",
+				Name:        "azure-arc",
+				Description: "Azure Arc (Experimental)",
+				Bindable:    true,
+				Tags:        []string{"Azure", "Arc", "Hybrid", "Kubernetes"},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:   "bfb51b44-b864-4050-a8bb-dff7ea1000ec",
+				Name: planServer,
+				Description: "Registers a server hosted outside of Azure; Azure " +
+					"services used on the server are billed separately",
+				Free: true,
+			}),
+			service.NewPlan(&service.PlanProperties{
+				ID:   "223be4c9-d1ee-4049-81a7-f672e862cdc3",
+				Name: planKubernetes,
+				Description: "Registers a Kubernetes cluster hosted outside of " +
+					"Azure; Azure services used on the cluster are billed separately",
+				Free: true,
+			}),
+		),
+	}), nil
+}
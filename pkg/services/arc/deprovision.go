@@ -0,0 +1,94 @@
+package arc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) GetDeprovisioner(
+	service.Plan,
+) (service.Deprovisioner, error) {
+	return service.NewDeprovisioner(
+		service.NewDeprovisioningStep("deleteARMDeployment", s.deleteARMDeployment),
+		service.NewDeprovisioningStep(
+			"deleteOnboardingRoleAssignment",
+			s.deleteOnboardingRoleAssignment,
+		),
+		service.NewDeprovisioningStep("deregister", s.deregister),
+	)
+}
+
+func (s *serviceManager) deleteARMDeployment(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*arcInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *arcInstanceDetails",
+		)
+	}
+	if err := s.armDeployer.Delete(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, fmt.Errorf("error deleting ARM deployment: %s", err)
+	}
+	return dt, nil
+}
+
+// deleteOnboardingRoleAssignment deletes the onboarding principal's role
+// assignment, if there is one. Azure doesn't delete role assignments along
+// with the resources they are scoped to, and a Kubernetes cluster's is scoped
+// to the whole resource group.
+func (s *serviceManager) deleteOnboardingRoleAssignment(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*arcInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *arcInstanceDetails",
+		)
+	}
+	if dt.OnboardingRoleAssignmentScope == "" {
+		return dt, nil
+	}
+	if err := s.arcManager.DeleteRoleAssignment(
+		dt.OnboardingRoleAssignmentScope,
+		dt.OnboardingRoleAssignmentName,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+// deregister deletes the server's or cluster's Azure resource. A Kubernetes
+// cluster that was never connected has no resource, which isn't an error.
+func (s *serviceManager) deregister(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*arcInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *arcInstanceDetails",
+		)
+	}
+	var err error
+	if instance.Plan.GetName() == planServer {
+		err = s.arcManager.DeleteMachine(dt.ResourceName, instance.ResourceGroup)
+	} else {
+		err = s.arcManager.DeleteConnectedCluster(
+			dt.ResourceName,
+			instance.ResourceGroup,
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
@@ -0,0 +1,359 @@
+package arc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arc"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+// onboardingRoleDefinitionIDs maps each plan to the built-in role that allows
+// a principal to connect servers or clusters, respectively, to Azure Arc:
+// "Azure Connected Machine Onboarding" and "Kubernetes Cluster - Azure Arc
+// Onboarding"
+var onboardingRoleDefinitionIDs = map[string]string{
+	planServer:     "b64e21ea-ac4e-4cdf-9dc9-5b892992bee7",
+	planKubernetes: "34e09817-6cbe-4d01-b1a2-e0eac5743d41",
+}
+
+// requiredActions maps each plan to the actions that the broker must be
+// permitted to perform to provision, bind to and deprovision its instances
+var requiredActions = map[string][]string{
+	planServer: {
+		"Microsoft.Resources/deployments/write",
+		"Microsoft.HybridCompute/machines/write",
+		"Microsoft.HybridCompute/machines/delete",
+		"Microsoft.Authorization/roleAssignments/write",
+		"Microsoft.Authorization/roleAssignments/delete",
+	},
+	planKubernetes: {
+		"Microsoft.Resources/deployments/write",
+		"Microsoft.Kubernetes/connectedClusters/read",
+		"Microsoft.Kubernetes/connectedClusters/delete",
+		"Microsoft.Authorization/roleAssignments/write",
+		"Microsoft.Authorization/roleAssignments/delete",
+	},
+}
+
+// nameRegex matches names that are valid for both Azure Arc-enabled servers
+// and connected Kubernetes clusters
+var nameRegex = regexp.MustCompile(
+	`^[a-zA-Z0-9]([a-zA-Z0-9_.-]{0,52}[a-zA-Z0-9_-])?$`,
+)
+
+var objectIDRegex = regexp.MustCompile(
+	`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-` +
+		`[0-9a-fA-F]{12}$`,
+)
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as *arc.ProvisioningParameters",
+		)
+	}
+	if !nameRegex.MatchString(pp.Name) {
+		return service.NewValidationError(
+			"name",
+			fmt.Sprintf(
+				`invalid name: "%s"; names must be 1-54 letters, numbers, `+
+					"underscores, hyphens and periods, must begin with a letter or "+
+					"number and must not end with a period",
+				pp.Name,
+			),
+		)
+	}
+	if pp.OnboardingPrincipalID != "" &&
+		!objectIDRegex.MatchString(pp.OnboardingPrincipalID) {
+		return service.NewValidationError(
+			"onboardingPrincipalId",
+			fmt.Sprintf(
+				`invalid onboardingPrincipalId: "%s"`,
+				pp.OnboardingPrincipalID,
+			),
+		)
+	}
+	return nil
+}
+
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep(
+			"validatePermissions",
+			s.validatePermissions,
+		),
+		service.NewProvisioningStep("deployARMTemplate", s.deployARMTemplate),
+	)
+}
+
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*arcInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *arcInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*arc.ProvisioningParameters",
+		)
+	}
+	dt.ARMDeploymentName = uuid.NewV4().String()
+	dt.ResourceName = pp.Name
+	if pp.OnboardingPrincipalID != "" {
+		dt.OnboardingRoleAssignmentName = uuid.NewV4().String()
+	}
+	dt.OnboardingScript = getOnboardingScript(
+		instance.Plan.GetName(),
+		onboardingTarget{
+			name:             pp.Name,
+			subscriptionID:   s.arcManager.GetSubscriptionID(),
+			resourceGroup:    instance.ResourceGroup,
+			tenantID:         s.arcManager.GetTenantID(),
+			location:         instance.Location,
+			cloudName:        s.arcManager.GetCloudName(),
+			servicePrincipal: pp.OnboardingPrincipalID != "",
+		},
+	)
+	return dt, nil
+}
+
+// validatePermissions verifies, before anything is deployed, that the broker
+// itself is permitted to do everything that provisioning, binding and
+// deprovisioning instances of the plan entail, so that a broker identity
+// lacking permissions is reported up front rather than halfway through
+func (s *serviceManager) validatePermissions(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	permissions, err := s.arcManager.GetPermissions(instance.ResourceGroup)
+	if err != nil {
+		return nil, err
+	}
+	missingActions := getMissingActions(
+		permissions,
+		requiredActions[instance.Plan.GetName()],
+	)
+	if len(missingActions) > 0 {
+		return nil, fmt.Errorf(
+			`the broker is not permitted to perform these actions in resource `+
+				`group "%s": %s`,
+			instance.ResourceGroup,
+			strings.Join(missingActions, ", "),
+		)
+	}
+	return instance.Details, nil
+}
+
+func (s *serviceManager) deployARMTemplate(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*arcInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *arcInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*arc.ProvisioningParameters",
+		)
+	}
+	planName := instance.Plan.GetName()
+	goParams, armParams := buildARMTemplateParameters(planName, dt, pp)
+	outputs, err := s.armDeployer.Deploy(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateBytes,
+		goParams,
+		armParams,
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+	if planName == planServer {
+		machineID, ok := outputs["machineId"].(string)
+		if !ok {
+			return nil, errors.New(
+				"error retrieving Azure Arc-enabled server id from deployment",
+			)
+		}
+		dt.ResourceID = machineID
+	} else {
+		dt.ResourceID = s.arcManager.GetConnectedClusterID(
+			dt.ResourceName,
+			instance.ResourceGroup,
+		)
+	}
+	if dt.OnboardingRoleAssignmentName != "" {
+		dt.OnboardingRoleAssignmentScope = getOnboardingRoleAssignmentScope(
+			planName,
+			dt.ResourceID,
+		)
+	}
+	return dt, nil
+}
+
+// buildARMTemplateParameters returns the Go template parameters and the ARM
+// template parameters used to register a server or cluster with Azure Arc.
+// Only a server's resource is created up front; a Kubernetes cluster's
+// resource is created as the cluster is connected, which requires the
+// cluster's own agent key.
+func buildARMTemplateParameters(
+	planName string,
+	dt *arcInstanceDetails,
+	pp *ProvisioningParameters,
+) (map[string]interface{}, map[string]interface{}) {
+	createMachine := planName == planServer
+	assignOnboardingRole := dt.OnboardingRoleAssignmentName != ""
+	goParams := map[string]interface{}{
+		"createMachine":        createMachine,
+		"assignOnboardingRole": assignOnboardingRole,
+	}
+	armParams := map[string]interface{}{}
+	if createMachine {
+		armParams["machineName"] = dt.ResourceName
+	}
+	if assignOnboardingRole {
+		armParams["onboardingRoleAssignmentName"] =
+			dt.OnboardingRoleAssignmentName
+		armParams["onboardingRoleDefinitionId"] =
+			onboardingRoleDefinitionIDs[planName]
+		armParams["onboardingPrincipalId"] = pp.OnboardingPrincipalID
+	}
+	return goParams, armParams
+}
+
+// getOnboardingRoleAssignmentScope returns the scope at which the onboarding
+// principal is assigned its role. For a server, that is the server's own
+// resource. A Kubernetes cluster's resource doesn't exist until the cluster
+// is connected, so the role is instead assigned for the resource group.
+func getOnboardingRoleAssignmentScope(
+	planName string,
+	resourceID string,
+) string {
+	if planName == planServer {
+		return resourceID
+	}
+	return resourceID[:strings.Index(resourceID, "/providers/")]
+}
+
+type onboardingTarget struct {
+	name             string
+	subscriptionID   string
+	resourceGroup    string
+	tenantID         string
+	location         string
+	cloudName        string
+	servicePrincipal bool
+}
+
+// getOnboardingScript returns the commands that connect a server or cluster to
+// Azure Arc. They are run on the server itself or, for a cluster, wherever the
+// cluster's kubeconfig is. A service principal's secret is never known to the
+// broker, so it appears as a placeholder.
+func getOnboardingScript(planName string, target onboardingTarget) string {
+	if planName == planServer {
+		script := fmt.Sprintf(
+			"azcmagent connect --subscription-id %s --resource-group %s "+
+				"--tenant-id %s --location %s --resource-name %s --cloud %s",
+			target.subscriptionID,
+			target.resourceGroup,
+			target.tenantID,
+			target.location,
+			target.name,
+			target.cloudName,
+		)
+		if target.servicePrincipal {
+			script += " --service-principal-id <application id> " +
+				"--service-principal-secret <secret>"
+		}
+		return script
+	}
+	login := fmt.Sprintf("az login --tenant %s", target.tenantID)
+	if target.servicePrincipal {
+		login = fmt.Sprintf(
+			"az login --service-principal --username <application id> "+
+				"--password <secret> --tenant %s",
+			target.tenantID,
+		)
+	}
+	return strings.Join(
+		[]string{
+			fmt.Sprintf("az cloud set --name %s", target.cloudName),
+			login,
+			fmt.Sprintf(
+				"az connectedk8s connect --name %s --resource-group %s "+
+					"--location %s --subscription %s",
+				target.name,
+				target.resourceGroup,
+				target.location,
+				target.subscriptionID,
+			),
+		},
+		"\n",
+	)
+}
+
+// getMissingActions returns those of the given actions that the given
+// permissions don't permit
+func getMissingActions(
+	permissions []arc.Permission,
+	actions []string,
+) []string {
+	missingActions := []string{}
+	for _, action := range actions {
+		if !isActionPermitted(permissions, action) {
+			missingActions = append(missingActions, action)
+		}
+	}
+	return missingActions
+}
+
+// isActionPermitted returns true if any of the given permissions includes the
+// given action and doesn't also exclude it. Actions are matched as Azure
+// matches them: case-insensitively, with "*" matching any characters.
+func isActionPermitted(permissions []arc.Permission, action string) bool {
+	for _, permission := range permissions {
+		if matchesAny(permission.Actions, action) &&
+			!matchesAny(permission.NotActions, action) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAny(patterns []string, action string) bool {
+	for _, pattern := range patterns {
+		regex, err := regexp.Compile(
+			"(?i)^" +
+				strings.Replace(regexp.QuoteMeta(pattern), `\*`, ".*", -1) +
+				"$",
+		)
+		if err == nil && regex.MatchString(action) {
+			return true
+		}
+	}
+	return false
+}
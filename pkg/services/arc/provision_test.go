@@ -0,0 +1,154 @@
+package arc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arc"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPrincipalID = "6f5b9d4c-2a1e-4c3b-8d7f-0e9a8b7c6d5e"
+
+func TestValidateProvisioningParameters(t *testing.T) {
+	m := &module{}
+	for _, name := range []string{"a", "web-01", "k8s_edge.cluster-1"} {
+		err := m.serviceManager.ValidateProvisioningParameters(
+			&ProvisioningParameters{
+				Name:                  name,
+				OnboardingPrincipalID: testPrincipalID,
+			},
+		)
+		assert.Nil(t, err, name)
+	}
+}
+
+func TestValidateProvisioningParametersWithInvalidName(t *testing.T) {
+	m := &module{}
+	for _, name := range []string{
+		"",
+		"-web",
+		"web.",
+		"web 01",
+		strings.Repeat("a", 55),
+	} {
+		err := m.serviceManager.ValidateProvisioningParameters(
+			&ProvisioningParameters{Name: name},
+		)
+		assert.NotNil(t, err, name)
+	}
+}
+
+func TestValidateProvisioningParametersWithInvalidPrincipal(t *testing.T) {
+	m := &module{}
+	err := m.serviceManager.ValidateProvisioningParameters(
+		&ProvisioningParameters{
+			Name:                  "web-01",
+			OnboardingPrincipalID: "not-an-object-id",
+		},
+	)
+	assert.NotNil(t, err)
+}
+
+func TestGetMissingActions(t *testing.T) {
+	permissions := []arc.Permission{
+		{
+			Actions:    []string{"*"},
+			NotActions: []string{"Microsoft.Authorization/*/Delete"},
+		},
+		{
+			Actions: []string{"microsoft.hybridcompute/*"},
+		},
+	}
+	assert.Equal(
+		t,
+		[]string{"Microsoft.Authorization/roleAssignments/delete"},
+		getMissingActions(permissions, requiredActions[planServer]),
+	)
+	assert.Equal(
+		t,
+		requiredActions[planKubernetes][1:3],
+		getMissingActions(
+			[]arc.Permission{permissions[1]},
+			requiredActions[planKubernetes][1:3],
+		),
+	)
+	assert.Empty(
+		t,
+		getMissingActions(
+			[]arc.Permission{{Actions: []string{"*"}}},
+			requiredActions[planKubernetes],
+		),
+	)
+}
+
+func TestBuildARMTemplateParameters(t *testing.T) {
+	dt := &arcInstanceDetails{
+		ResourceName:                 "web-01",
+		OnboardingRoleAssignmentName: "assignment",
+	}
+	pp := &ProvisioningParameters{OnboardingPrincipalID: testPrincipalID}
+	goParams, armParams := buildARMTemplateParameters(planServer, dt, pp)
+	assert.Equal(t, true, goParams["createMachine"])
+	assert.Equal(t, true, goParams["assignOnboardingRole"])
+	assert.Equal(t, "web-01", armParams["machineName"])
+	assert.Equal(
+		t,
+		onboardingRoleDefinitionIDs[planServer],
+		armParams["onboardingRoleDefinitionId"],
+	)
+	assert.Equal(t, testPrincipalID, armParams["onboardingPrincipalId"])
+
+	dt.OnboardingRoleAssignmentName = ""
+	goParams, armParams = buildARMTemplateParameters(planKubernetes, dt, pp)
+	assert.Equal(t, false, goParams["createMachine"])
+	assert.Equal(t, false, goParams["assignOnboardingRole"])
+	assert.Empty(t, armParams)
+}
+
+func TestGetOnboardingRoleAssignmentScope(t *testing.T) {
+	const resourceGroupID = "/subscriptions/sub/resourceGroups/rg"
+	machineID := resourceGroupID +
+		"/providers/Microsoft.HybridCompute/machines/web-01"
+	assert.Equal(
+		t,
+		machineID,
+		getOnboardingRoleAssignmentScope(planServer, machineID),
+	)
+	assert.Equal(
+		t,
+		resourceGroupID,
+		getOnboardingRoleAssignmentScope(
+			planKubernetes,
+			resourceGroupID+
+				"/providers/Microsoft.Kubernetes/connectedClusters/edge",
+		),
+	)
+}
+
+func TestGetOnboardingScript(t *testing.T) {
+	target := onboardingTarget{
+		name:           "web-01",
+		subscriptionID: "sub",
+		resourceGroup:  "rg",
+		tenantID:       "tenant",
+		location:       "eastus",
+		cloudName:      "AzureCloud",
+	}
+	assert.Equal(
+		t,
+		"azcmagent connect --subscription-id sub --resource-group rg "+
+			"--tenant-id tenant --location eastus --resource-name web-01 "+
+			"--cloud AzureCloud",
+		getOnboardingScript(planServer, target),
+	)
+	target.servicePrincipal = true
+	script := getOnboardingScript(planKubernetes, target)
+	assert.Contains(t, script, "az login --service-principal")
+	assert.Contains(
+		t,
+		script,
+		"az connectedk8s connect --name web-01 --resource-group rg "+
+			"--location eastus --subscription sub",
+	)
+}
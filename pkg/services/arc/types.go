@@ -0,0 +1,88 @@
+package arc
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// ProvisioningParameters encapsulates Azure Arc-specific provisioning options
+type ProvisioningParameters struct {
+	// Name is the name of the server or Kubernetes cluster to register, which
+	// its Azure resource will also be known by
+	Name string `json:"name"`
+	// OnboardingPrincipalID is the object ID of a service principal that is to
+	// be allowed to connect the server or cluster to Azure Arc. If it is
+	// omitted, whoever connects it must already be allowed to.
+	OnboardingPrincipalID string `json:"onboardingPrincipalId"`
+}
+
+type arcInstanceDetails struct {
+	ARMDeploymentName string `json:"armDeployment"`
+	ResourceName      string `json:"resourceName"`
+	// ResourceID is the ID of the Azure resource that represents the server or
+	// cluster. A Kubernetes cluster's resource only exists once the cluster has
+	// been connected.
+	ResourceID string `json:"resourceId"`
+	// OnboardingRoleAssignmentScope and OnboardingRoleAssignmentName are empty
+	// unless an onboarding principal was assigned a role
+	OnboardingRoleAssignmentScope string `json:"onboardingRoleAssignmentScope"`
+	OnboardingRoleAssignmentName  string `json:"onboardingRoleAssignmentName"`
+	OnboardingScript              string `json:"onboardingScript"`
+}
+
+// UpdatingParameters encapsulates Azure Arc-specific updating options
+type UpdatingParameters struct {
+}
+
+// BindingParameters encapsulates Azure Arc-specific binding options
+type BindingParameters struct {
+	PrincipalID string `json:"principalId"`
+	Role        string `json:"role"`
+}
+
+type arcBindingDetails struct {
+	PrincipalID        string `json:"principalId"`
+	Role               string `json:"role"`
+	RoleAssignmentName string `json:"roleAssignmentName"`
+}
+
+// Credentials encapsulates Azure Arc-specific connection details
+type Credentials struct {
+	ResourceName   string `json:"resourceName"`
+	SubscriptionID string `json:"subscriptionId"`
+	ResourceGroup  string `json:"resourceGroup"`
+	TenantID       string `json:"tenantId"`
+	Location       string `json:"location"`
+	// Scope is the resource ID of the server or cluster, which is also the scope
+	// at which the principal's role was assigned
+	Scope            string `json:"scope"`
+	PrincipalID      string `json:"principalId"`
+	Role             string `json:"role"`
+	RoleAssignmentID string `json:"roleAssignmentId"`
+	// OnboardingScript connects the server or cluster to Azure Arc, or
+	// reconnects it after it was disconnected
+	OnboardingScript string `json:"onboardingScript"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &arcInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &arcBindingDetails{}
+}
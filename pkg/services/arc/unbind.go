@@ -0,0 +1,29 @@
+package arc
+
+import (
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) Unbind(
+	instance service.Instance,
+	bindingDetails service.BindingDetails,
+) error {
+	dt, ok := instance.Details.(*arcInstanceDetails)
+	if !ok {
+		return errors.New(
+			"error casting instance.Details as *arcInstanceDetails",
+		)
+	}
+	bd, ok := bindingDetails.(*arcBindingDetails)
+	if !ok {
+		return errors.New(
+			"error casting bindingDetails as *arcBindingDetails",
+		)
+	}
+	return s.arcManager.DeleteRoleAssignment(
+		dt.ResourceID,
+		bd.RoleAssignmentName,
+	)
+}
@@ -0,0 +1,77 @@
+package automation
+
+// nolint: lll
+var armTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "accountName": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of the Automation account"
+      }
+    },
+    "accountSku": {
+      "type": "string",
+      "allowedValues": [
+        "Free",
+        "Basic"
+      ]
+    },
+    "runbooks": {
+      "type": "array"
+    },
+    "tags": {
+      "type": "object"
+    }
+  },
+  "variables": {
+    "apiVersion": "2023-11-01"
+  },
+  "resources": [
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[parameters('accountName')]",
+      "type": "Microsoft.Automation/automationAccounts",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "properties": {
+        "sku": {
+          "name": "[parameters('accountSku')]"
+        }
+      }
+    },
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[concat(parameters('accountName'), '/', parameters('runbooks')[copyIndex()].name)]",
+      "type": "Microsoft.Automation/automationAccounts/runbooks",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "dependsOn": [
+        "[resourceId('Microsoft.Automation/automationAccounts', parameters('accountName'))]"
+      ],
+      "copy": {
+        "name": "runbooks",
+        "count": "[length(parameters('runbooks'))]"
+      },
+      "properties": {
+        "runbookType": "[parameters('runbooks')[copyIndex()].runbookType]",
+        "description": "[parameters('runbooks')[copyIndex()].description]",
+        "logProgress": false,
+        "logVerbose": false,
+        "publishContentLink": "[parameters('runbooks')[copyIndex()].publishContentLink]"
+      }
+    }
+  ],
+  "outputs": {
+    "accountId": {
+      "type": "string",
+      "value": "[resourceId('Microsoft.Automation/automationAccounts', parameters('accountName'))]"
+    }
+  }
+}
+`)
@@ -0,0 +1,42 @@
+package automation
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/automation"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer       arm.Deployer
+	automationManager automation.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of provisioning Azure Automation accounts
+func New(
+	armDeployer arm.Deployer,
+	automationManager automation.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer:       armDeployer,
+			automationManager: automationManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "automation"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{"Microsoft.Automation"}
+}
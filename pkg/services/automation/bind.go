@@ -0,0 +1,205 @@
+package automation
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	log "github.com/Sirupsen/logrus"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	defaultWebhookExpiryDays = 365
+	maxWebhookExpiryDays     = 3650
+)
+
+func (s *serviceManager) ValidateBindingParameters(
+	bindingParameters service.BindingParameters,
+) error {
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return errors.New(
+			"error casting bindingParameters as *automation.BindingParameters",
+		)
+	}
+	for _, runbookName := range bp.Runbooks {
+		if !runbookNameRegex.MatchString(runbookName) {
+			return service.NewValidationError(
+				"runbooks",
+				fmt.Sprintf(`invalid runbook name: "%s"`, runbookName),
+			)
+		}
+	}
+	if bp.WebhookExpiryDays < 0 || bp.WebhookExpiryDays > maxWebhookExpiryDays {
+		return service.NewValidationError(
+			"webhookExpiryDays",
+			fmt.Sprintf(
+				"invalid webhookExpiryDays: %d; webhooks may remain valid for "+
+					"between 1 and %d days",
+				bp.WebhookExpiryDays,
+				maxWebhookExpiryDays,
+			),
+		)
+	}
+	return nil
+}
+
+// Bind creates a webhook for each of the runbooks the binding may start. The
+// webhooks belong to the binding alone, so that unbinding revokes its ability
+// to start runbooks without affecting any other binding's.
+func (s *serviceManager) Bind(
+	instance service.Instance,
+	bindingParameters service.BindingParameters,
+) (service.BindingDetails, error) {
+	dt, ok := instance.Details.(*automationInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *automationInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*automation.ProvisioningParameters",
+		)
+	}
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting bindingParameters as *automation.BindingParameters",
+		)
+	}
+	runbookNames, err := getBindingRunbookNames(pp, bp)
+	if err != nil {
+		return nil, err
+	}
+	expiryDays := bp.WebhookExpiryDays
+	if expiryDays == 0 {
+		expiryDays = defaultWebhookExpiryDays
+	}
+	expiry := time.Now().AddDate(0, 0, int(expiryDays)).UTC().Truncate(
+		time.Second,
+	)
+	bd := &automationBindingDetails{
+		Webhooks: []bindingWebhook{},
+	}
+	for _, runbookName := range runbookNames {
+		webhook := bindingWebhook{
+			Name:    fmt.Sprintf("%s-%s", runbookName, uuid.NewV4().String()),
+			Runbook: runbookName,
+			Expires: expiry.Format(time.RFC3339),
+		}
+		webhook.URL, err = s.automationManager.CreateWebhook(
+			dt.AccountName,
+			instance.ResourceGroup,
+			webhook.Name,
+			runbookName,
+			expiry,
+		)
+		if err != nil {
+			s.deleteWebhooks(instance, bd.Webhooks)
+			return nil, err
+		}
+		bd.Webhooks = append(bd.Webhooks, webhook)
+	}
+	return bd, nil
+}
+
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	binding service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*automationInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *automationInstanceDetails",
+		)
+	}
+	bd, ok := binding.Details.(*automationBindingDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting binding.Details as *automationBindingDetails",
+		)
+	}
+	webhooks := make([]Webhook, len(bd.Webhooks))
+	for i, webhook := range bd.Webhooks {
+		webhooks[i] = Webhook{
+			Runbook: webhook.Runbook,
+			URL:     webhook.URL,
+			Expires: webhook.Expires,
+		}
+	}
+	return &Credentials{
+		AccountID:     dt.AccountID,
+		AccountName:   dt.AccountName,
+		ResourceGroup: instance.ResourceGroup,
+		Webhooks:      webhooks,
+	}, nil
+}
+
+// getBindingRunbookNames returns the names of the runbooks the binding may
+// start, as they were named when the instance was provisioned
+func getBindingRunbookNames(
+	pp *ProvisioningParameters,
+	bp *BindingParameters,
+) ([]string, error) {
+	if len(bp.Runbooks) == 0 {
+		runbookNames := make([]string, len(pp.Runbooks))
+		for i, runbook := range pp.Runbooks {
+			runbookNames[i] = runbook.Name
+		}
+		return runbookNames, nil
+	}
+	runbookNames := []string{}
+	selected := map[string]bool{}
+	for _, requested := range bp.Runbooks {
+		var found bool
+		for _, runbook := range pp.Runbooks {
+			if strings.EqualFold(runbook.Name, requested) {
+				found = true
+				if !selected[runbook.Name] {
+					runbookNames = append(runbookNames, runbook.Name)
+					selected[runbook.Name] = true
+				}
+				break
+			}
+		}
+		if !found {
+			return nil, service.NewValidationError(
+				"runbooks",
+				fmt.Sprintf(
+					`runbook "%s" was not imported into the account`,
+					requested,
+				),
+			)
+		}
+	}
+	return runbookNames, nil
+}
+
+// deleteWebhooks deletes the given webhooks, which were created for a binding
+// that failed part way. Failure to delete any of them is logged, but doesn't
+// mask the error that failed the binding.
+func (s *serviceManager) deleteWebhooks(
+	instance service.Instance,
+	webhooks []bindingWebhook,
+) {
+	dt := instance.Details.(*automationInstanceDetails)
+	for _, webhook := range webhooks {
+		if err := s.automationManager.DeleteWebhook(
+			dt.AccountName,
+			instance.ResourceGroup,
+			webhook.Name,
+		); err != nil {
+			log.WithFields(log.Fields{
+				"instanceID": instance.InstanceID,
+				"webhook":    webhook.Name,
+				"error":      err,
+			}).Error("error deleting webhook of failed binding")
+		}
+	}
+}
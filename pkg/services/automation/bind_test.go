@@ -0,0 +1,92 @@
+package automation
+
+import (
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateBindingParameters(t *testing.T) {
+	m := &module{}
+	bp := &BindingParameters{}
+	err := m.serviceManager.ValidateBindingParameters(bp)
+	assert.Nil(t, err)
+	bp.Runbooks = []string{"Restart-Service"}
+	bp.WebhookExpiryDays = 30
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.Nil(t, err)
+	bp.WebhookExpiryDays = maxWebhookExpiryDays + 1
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.NotNil(t, err)
+	bp.WebhookExpiryDays = 30
+	bp.Runbooks = []string{"Restart Service"}
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.NotNil(t, err)
+}
+
+func TestGetBindingRunbookNames(t *testing.T) {
+	pp := getTestProvisioningParameters()
+	runbookNames, err := getBindingRunbookNames(pp, &BindingParameters{})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Restart-Service", "rotate_logs"}, runbookNames)
+	runbookNames, err = getBindingRunbookNames(
+		pp,
+		&BindingParameters{
+			Runbooks: []string{"Rotate_Logs", "rotate_logs"},
+		},
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"rotate_logs"}, runbookNames)
+	_, err = getBindingRunbookNames(
+		pp,
+		&BindingParameters{
+			Runbooks: []string{"Stop-Service"},
+		},
+	)
+	assert.NotNil(t, err)
+}
+
+func TestGetCredentials(t *testing.T) {
+	m := &module{}
+	creds, err := m.serviceManager.GetCredentials(
+		service.Instance{
+			ResourceGroup: "rg",
+			Details: &automationInstanceDetails{
+				AccountName: "aa-1",
+				AccountID: "/subscriptions/sub/resourceGroups/rg/providers/" +
+					"Microsoft.Automation/automationAccounts/aa-1",
+			},
+		},
+		service.Binding{
+			Details: &automationBindingDetails{
+				Webhooks: []bindingWebhook{
+					{
+						Name:    "rotate_logs-1",
+						Runbook: "rotate_logs",
+						URL:     "https://example.webhook.azure-automation.net/token",
+						Expires: "2027-10-14T00:00:00Z",
+					},
+				},
+			},
+		},
+	)
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		&Credentials{
+			AccountID: "/subscriptions/sub/resourceGroups/rg/providers/" +
+				"Microsoft.Automation/automationAccounts/aa-1",
+			AccountName:   "aa-1",
+			ResourceGroup: "rg",
+			Webhooks: []Webhook{
+				{
+					Runbook: "rotate_logs",
+					URL:     "https://example.webhook.azure-automation.net/token",
+					Expires: "2027-10-14T00:00:00Z",
+				},
+			},
+		},
+		creds,
+	)
+}
@@ -0,0 +1,70 @@
+package automation
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:          "3429d285-86b4-44c0-a494-f7736c226921",
+				Name:        "azure-automation",
+				Description: "Azure Automation (Experimental)",
+				Bindable:    true,
+				Tags:        []string{"Azure", "Automation", "Runbooks"},
+				// Azure Automation is only offered in some regions
+				Locations: []string{
+					"australiacentral",
+					"australiaeast",
+					"australiasoutheast",
+					"brazilsouth",
+					"canadacentral",
+					"centralindia",
+					"centralus",
+					"eastasia",
+					"eastus",
+					"eastus2",
+					"francecentral",
+					"germanywestcentral",
+					"japaneast",
+					"japanwest",
+					"koreacentral",
+					"northcentralus",
+					"northeurope",
+					"norwayeast",
+					"southafricanorth",
+					"southcentralus",
+					"southeastasia",
+					"switzerlandnorth",
+					"uaenorth",
+					"uksouth",
+					"ukwest",
+					"westcentralus",
+					"westeurope",
+					"westus",
+					"westus2",
+					"westus3",
+				},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:   "05c0c4f6-f6e7-4f3d-b883-2d1ce4a59622",
+				Name: "free",
+				Description: "Free tier, limited to 500 minutes of job run time " +
+					"per month",
+				Free: true,
+				Extended: map[string]interface{}{
+					"accountSku": "Free",
+				},
+			}),
+			service.NewPlan(&service.PlanProperties{
+				ID:          "cd39cd83-fc24-4b55-9597-8441c8449222",
+				Name:        "basic",
+				Description: "Basic tier, billed per minute of job run time",
+				Free:        false,
+				Extended: map[string]interface{}{
+					"accountSku": "Basic",
+				},
+			}),
+		),
+	}), nil
+}
@@ -0,0 +1,58 @@
+package automation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) GetDeprovisioner(
+	service.Plan,
+) (service.Deprovisioner, error) {
+	return service.NewDeprovisioner(
+		service.NewDeprovisioningStep("deleteARMDeployment", s.deleteARMDeployment),
+		service.NewDeprovisioningStep("deleteAccount", s.deleteAccount),
+	)
+}
+
+func (s *serviceManager) deleteARMDeployment(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*automationInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *automationInstanceDetails",
+		)
+	}
+	if err := s.armDeployer.Delete(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, fmt.Errorf("error deleting ARM deployment: %s", err)
+	}
+	return dt, nil
+}
+
+// deleteAccount deletes the Automation account, along with its runbooks, their
+// webhooks and its job history
+func (s *serviceManager) deleteAccount(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*automationInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *automationInstanceDetails",
+		)
+	}
+	if err := s.automationManager.DeleteAccount(
+		dt.AccountName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
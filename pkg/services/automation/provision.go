@@ -0,0 +1,213 @@
+package automation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const maxRunbookDescriptionLength = 512
+
+// runbookTypes are the types of runbook that may be imported
+var runbookTypes = []string{
+	"PowerShell",
+	"PowerShell72",
+	"PowerShellWorkflow",
+	"GraphPowerShell",
+	"GraphPowerShellWorkflow",
+	"Python2",
+	"Python3",
+}
+
+// runbookNameRegex matches the names Azure Automation allows runbooks to have
+var runbookNameRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]{0,62}$`)
+
+var sha256HexRegex = regexp.MustCompile(`^[a-fA-F0-9]{64}$`)
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*automation.ProvisioningParameters",
+		)
+	}
+	runbookNames := map[string]bool{}
+	for i, runbook := range pp.Runbooks {
+		field := fmt.Sprintf("runbooks[%d]", i)
+		if err := validateRunbook(field, runbook); err != nil {
+			return err
+		}
+		// Runbook names are case-insensitive
+		if runbookNames[strings.ToLower(runbook.Name)] {
+			return service.NewValidationError(
+				field+".name",
+				fmt.Sprintf(`duplicate runbook name: "%s"`, runbook.Name),
+			)
+		}
+		runbookNames[strings.ToLower(runbook.Name)] = true
+	}
+	return nil
+}
+
+func validateRunbook(field string, runbook Runbook) error {
+	if !runbookNameRegex.MatchString(runbook.Name) {
+		return service.NewValidationError(
+			field+".name",
+			fmt.Sprintf(
+				`invalid runbook name: "%s"; names must be 1-63 letters, numbers, `+
+					"hyphens and underscores and must begin with a letter",
+				runbook.Name,
+			),
+		)
+	}
+	if !containsString(runbookTypes, runbook.Type) {
+		return service.NewValidationError(
+			field+".type",
+			fmt.Sprintf(
+				`invalid runbook type: "%s"; allowed values are: %s`,
+				runbook.Type,
+				strings.Join(runbookTypes, ", "),
+			),
+		)
+	}
+	if len(runbook.Description) > maxRunbookDescriptionLength {
+		return service.NewValidationError(
+			field+".description",
+			fmt.Sprintf(
+				"runbook descriptions may be no longer than %d characters",
+				maxRunbookDescriptionLength,
+			),
+		)
+	}
+	// Azure fetches the content itself, so it must be published where Azure can
+	// reach it
+	contentURL, err := url.Parse(runbook.ContentURI)
+	if err != nil || contentURL.Scheme != "https" || contentURL.Host == "" {
+		return service.NewValidationError(
+			field+".contentUri",
+			fmt.Sprintf(
+				`invalid contentUri: "%s"; runbook content must be published at `+
+					"an https URL",
+				runbook.ContentURI,
+			),
+		)
+	}
+	if runbook.ContentHash != "" &&
+		!sha256HexRegex.MatchString(runbook.ContentHash) {
+		return service.NewValidationError(
+			field+".contentHash",
+			fmt.Sprintf(
+				`invalid contentHash: "%s"; hashes must be SHA-256 hashes in hex`,
+				runbook.ContentHash,
+			),
+		)
+	}
+	return nil
+}
+
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep("deployARMTemplate", s.deployARMTemplate),
+	)
+}
+
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*automationInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *automationInstanceDetails",
+		)
+	}
+	dt.ARMDeploymentName = uuid.NewV4().String()
+	// Automation account names may be no longer than 50 characters
+	dt.AccountName = "aa-" + uuid.NewV4().String()
+	return dt, nil
+}
+
+func (s *serviceManager) deployARMTemplate(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*automationInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *automationInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*automation.ProvisioningParameters",
+		)
+	}
+	outputs, err := s.armDeployer.Deploy(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateBytes,
+		nil, // Go template params
+		map[string]interface{}{
+			"accountName": dt.AccountName,
+			"accountSku":  instance.Plan.GetProperties().Extended["accountSku"],
+			"runbooks":    getARMRunbooks(pp.Runbooks),
+		},
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+	accountID, ok := outputs["accountId"].(string)
+	if !ok {
+		return nil, errors.New("error retrieving account ID from deployment")
+	}
+	dt.AccountID = accountID
+	return dt, nil
+}
+
+// getARMRunbooks returns the given runbooks as the ARM template expects them
+func getARMRunbooks(runbooks []Runbook) []map[string]interface{} {
+	armRunbooks := make([]map[string]interface{}, len(runbooks))
+	for i, runbook := range runbooks {
+		contentLink := map[string]interface{}{
+			"uri": runbook.ContentURI,
+		}
+		if runbook.ContentHash != "" {
+			contentLink["contentHash"] = map[string]interface{}{
+				"algorithm": "SHA256",
+				"value":     strings.ToUpper(runbook.ContentHash),
+			}
+		}
+		armRunbooks[i] = map[string]interface{}{
+			"name":               runbook.Name,
+			"runbookType":        runbook.Type,
+			"description":        runbook.Description,
+			"publishContentLink": contentLink,
+		}
+	}
+	return armRunbooks
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
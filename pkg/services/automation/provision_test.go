@@ -0,0 +1,99 @@
+package automation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testContentHash = "e3b0c44298fc1c149afbf4c8996fb924" +
+	"27ae41e4649b934ca495991b7852b855"
+
+func getTestProvisioningParameters() *ProvisioningParameters {
+	return &ProvisioningParameters{
+		Runbooks: []Runbook{
+			{
+				Name:        "Restart-Service",
+				Type:        "PowerShell",
+				Description: "Restarts the application service",
+				ContentURI:  "https://example.com/runbooks/restart-service.ps1",
+				ContentHash: testContentHash,
+			},
+			{
+				Name:       "rotate_logs",
+				Type:       "Python3",
+				ContentURI: "https://example.com/runbooks/rotate_logs.py",
+			},
+		},
+	}
+}
+
+func TestValidateProvisioningParameters(t *testing.T) {
+	m := &module{}
+	err := m.serviceManager.ValidateProvisioningParameters(
+		getTestProvisioningParameters(),
+	)
+	assert.Nil(t, err)
+	err = m.serviceManager.ValidateProvisioningParameters(
+		&ProvisioningParameters{},
+	)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidRunbook(t *testing.T) {
+	m := &module{}
+	for name, mutate := range map[string]func(*Runbook){
+		"bad name": func(r *Runbook) {
+			r.Name = "1-restart"
+		},
+		"bad type": func(r *Runbook) {
+			r.Type = "Bash"
+		},
+		"plain http": func(r *Runbook) {
+			r.ContentURI = "http://example.com/runbooks/restart-service.ps1"
+		},
+		"relative uri": func(r *Runbook) {
+			r.ContentURI = "runbooks/restart-service.ps1"
+		},
+		"bad hash": func(r *Runbook) {
+			r.ContentHash = "d41d8cd98f00b204e9800998ecf8427e"
+		},
+	} {
+		pp := getTestProvisioningParameters()
+		mutate(&pp.Runbooks[0])
+		err := m.serviceManager.ValidateProvisioningParameters(pp)
+		assert.NotNil(t, err, name)
+	}
+}
+
+func TestValidateProvisioningParametersWithDuplicateRunbookNames(t *testing.T) {
+	m := &module{}
+	pp := getTestProvisioningParameters()
+	pp.Runbooks[1].Name = "restart-service"
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+}
+
+func TestGetARMRunbooks(t *testing.T) {
+	runbooks := getARMRunbooks(getTestProvisioningParameters().Runbooks)
+	assert.Equal(t, 2, len(runbooks))
+	assert.Equal(
+		t,
+		map[string]interface{}{
+			"uri": "https://example.com/runbooks/restart-service.ps1",
+			"contentHash": map[string]interface{}{
+				"algorithm": "SHA256",
+				"value": "E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA4" +
+					"95991B7852B855",
+			},
+		},
+		runbooks[0]["publishContentLink"],
+	)
+	assert.Equal(
+		t,
+		map[string]interface{}{
+			"uri": "https://example.com/runbooks/rotate_logs.py",
+		},
+		runbooks[1]["publishContentLink"],
+	)
+}
@@ -0,0 +1,97 @@
+package automation
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// ProvisioningParameters encapsulates Azure Automation-specific provisioning
+// options
+type ProvisioningParameters struct {
+	// Runbooks are imported into the account, and published, when it is
+	// provisioned
+	Runbooks []Runbook `json:"runbooks"`
+}
+
+// Runbook encapsulates the definition of a runbook whose content is published
+// at a URL that Azure can reach
+type Runbook struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	ContentURI  string `json:"contentUri"`
+	// ContentHash, if given, is the SHA-256 hash, in hex, that the content must
+	// have for the runbook to be imported
+	ContentHash string `json:"contentHash"`
+}
+
+type automationInstanceDetails struct {
+	ARMDeploymentName string `json:"armDeployment"`
+	AccountName       string `json:"accountName"`
+	AccountID         string `json:"accountId"`
+}
+
+// UpdatingParameters encapsulates Azure Automation-specific updating options
+type UpdatingParameters struct {
+}
+
+// BindingParameters encapsulates Azure Automation-specific binding options
+type BindingParameters struct {
+	// Runbooks are the names of those of the account's runbooks that the
+	// binding may start. All of them may be started if none are given.
+	Runbooks []string `json:"runbooks"`
+	// WebhookExpiryDays is how long the binding's webhooks remain valid
+	WebhookExpiryDays int64 `json:"webhookExpiryDays"`
+}
+
+type automationBindingDetails struct {
+	Webhooks []bindingWebhook `json:"webhooks"`
+}
+
+// bindingWebhook records one of the webhooks created for a binding. A
+// webhook's URL can't be retrieved from Azure once it has been created.
+type bindingWebhook struct {
+	Name    string `json:"name"`
+	Runbook string `json:"runbook"`
+	URL     string `json:"url" secret:"true"`
+	Expires string `json:"expires"`
+}
+
+// Credentials encapsulates the Automation account and the webhooks by which
+// the binding may start its runbooks
+type Credentials struct {
+	AccountID     string    `json:"accountId"`
+	AccountName   string    `json:"accountName"`
+	ResourceGroup string    `json:"resourceGroup"`
+	Webhooks      []Webhook `json:"webhooks"`
+}
+
+// Webhook encapsulates a URL to which a POST starts a runbook
+type Webhook struct {
+	Runbook string `json:"runbook"`
+	URL     string `json:"url"`
+	Expires string `json:"expires"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &automationInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &automationBindingDetails{}
+}
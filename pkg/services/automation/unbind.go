@@ -0,0 +1,35 @@
+package automation
+
+import (
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) Unbind(
+	instance service.Instance,
+	bindingDetails service.BindingDetails,
+) error {
+	dt, ok := instance.Details.(*automationInstanceDetails)
+	if !ok {
+		return errors.New(
+			"error casting instance.Details as *automationInstanceDetails",
+		)
+	}
+	bd, ok := bindingDetails.(*automationBindingDetails)
+	if !ok {
+		return errors.New(
+			"error casting bindingDetails as *automationBindingDetails",
+		)
+	}
+	for _, webhook := range bd.Webhooks {
+		if err := s.automationManager.DeleteWebhook(
+			dt.AccountName,
+			instance.ResourceGroup,
+			webhook.Name,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package backup
+
+// nolint: lll
+var armTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "vaultName": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of the Recovery Services vault"
+      }
+    },
+    "policyName": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of the backup policy"
+      }
+    },
+    "schedulePolicy": {
+      "type": "object"
+    },
+    "retentionPolicy": {
+      "type": "object"
+    },
+    "containerName": {
+      "type": "string"
+    },
+    "protectedItemName": {
+      "type": "string"
+    },
+    "resourceId": {
+      "type": "string",
+      "metadata": {
+        "description": "ID of the virtual machine to protect"
+      }
+    },
+    "tags": {
+      "type": "object"
+    }
+  },
+  "variables": {
+    "apiVersion": "2023-04-01",
+    "vaultId": "[resourceId('Microsoft.RecoveryServices/vaults', parameters('vaultName'))]",
+    "policyId": "[resourceId('Microsoft.RecoveryServices/vaults/backupPolicies', parameters('vaultName'), parameters('policyName'))]",
+    "protectedItemId": "[resourceId('Microsoft.RecoveryServices/vaults/backupFabrics/protectionContainers/protectedItems', parameters('vaultName'), 'Azure', parameters('containerName'), parameters('protectedItemName'))]"
+  },
+  "resources": [
+    {{- if .createVault }}
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[parameters('vaultName')]",
+      "type": "Microsoft.RecoveryServices/vaults",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "sku": {
+        "name": "RS0",
+        "tier": "Standard"
+      },
+      "properties": {}
+    },
+    {{- end }}
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[concat(parameters('vaultName'), '/', parameters('policyName'))]",
+      "type": "Microsoft.RecoveryServices/vaults/backupPolicies",
+      "location": "[parameters('location')]",
+      {{- if .createVault }}
+      "dependsOn": [
+        "[variables('vaultId')]"
+      ],
+      {{- end }}
+      "properties": {
+        "backupManagementType": "AzureIaasVM",
+        "schedulePolicy": "[parameters('schedulePolicy')]",
+        "retentionPolicy": "[parameters('retentionPolicy')]",
+        "timeZone": "UTC"
+      }
+    },
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[concat(parameters('vaultName'), '/Azure/', parameters('containerName'), '/', parameters('protectedItemName'))]",
+      "type": "Microsoft.RecoveryServices/vaults/backupFabrics/protectionContainers/protectedItems",
+      "location": "[parameters('location')]",
+      "dependsOn": [
+        "[variables('policyId')]"
+      ],
+      "properties": {
+        "protectedItemType": "Microsoft.Compute/virtualMachines",
+        "policyId": "[variables('policyId')]",
+        "sourceResourceId": "[parameters('resourceId')]"
+      }
+    }
+  ],
+  "outputs": {
+    "vaultId": {
+      "type": "string",
+      "value": "[variables('vaultId')]"
+    },
+    "policyId": {
+      "type": "string",
+      "value": "[variables('policyId')]"
+    },
+    "protectedItemId": {
+      "type": "string",
+      "value": "[variables('protectedItemId')]"
+    }
+  }
+}
+`)
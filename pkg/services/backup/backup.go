@@ -0,0 +1,43 @@
+package backup
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/backup"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer   arm.Deployer
+	backupManager backup.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of enrolling Azure resources in backup protection
+// by Recovery Services vaults
+func New(
+	armDeployer arm.Deployer,
+	backupManager backup.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer:   armDeployer,
+			backupManager: backupManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "backup"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{"Microsoft.RecoveryServices"}
+}
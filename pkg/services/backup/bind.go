@@ -0,0 +1,40 @@
+package backup
+
+import (
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) ValidateBindingParameters(
+	service.BindingParameters,
+) error {
+	// There are no parameters for binding to a backup instance, so there is
+	// nothing to validate
+	return nil
+}
+
+func (s *serviceManager) Bind(
+	service.Instance,
+	service.BindingParameters,
+) (service.BindingDetails, error) {
+	return &backupBindingDetails{}, nil
+}
+
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	_ service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*backupInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *backupInstanceDetails",
+		)
+	}
+	return &Credentials{
+		VaultName:       dt.VaultName,
+		VaultID:         dt.VaultID,
+		PolicyID:        dt.PolicyID,
+		ProtectedItemID: dt.ProtectedItemID,
+	}, nil
+}
@@ -0,0 +1,28 @@
+package backup
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:          "19c9fbf1-f31a-42ba-b15e-defa4c323fdf",
+				Name:        "azure-backup",
+				Description: "Azure Backup (Experimental)",
+				Bindable:    true,
+				Tags: []string{
+					"Azure",
+					"Backup",
+					"Recovery Services",
+				},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:          "91ff692f-b6fe-4d30-8eef-43bdb1a07728",
+				Name:        "standard",
+				Description: "Billed per protected instance and per unit of backup storage",
+				Free:        false,
+			}),
+		),
+	}), nil
+}
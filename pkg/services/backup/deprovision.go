@@ -0,0 +1,178 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) GetDeprovisioner(
+	service.Plan,
+) (service.Deprovisioner, error) {
+	return service.NewDeprovisioner(
+		service.NewDeprovisioningStep("deleteARMDeployment", s.deleteARMDeployment),
+		service.NewDeprovisioningStep("stopProtection", s.stopProtection),
+		service.NewDeprovisioningStepWithDependencies(
+			"deletePolicy",
+			s.deletePolicy,
+			"stopProtection",
+		),
+		service.NewDeprovisioningStepWithDependencies(
+			"deleteVault",
+			s.deleteVault,
+			"deletePolicy",
+		),
+	)
+}
+
+func (s *serviceManager) deleteARMDeployment(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*backupInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *backupInstanceDetails",
+		)
+	}
+	if err := s.armDeployer.Delete(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, fmt.Errorf("error deleting ARM deployment: %s", err)
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) stopProtection(
+	ctx context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*backupInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *backupInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*backup.ProvisioningParameters",
+		)
+	}
+	if pp.KeepProtection || dt.ProtectedItemName == "" {
+		return dt, nil
+	}
+	if err := s.backupManager.StopProtection(
+		ctx,
+		dt.VaultName,
+		dt.ContainerName,
+		dt.ProtectedItemName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+// deletePolicy deletes the instance's backup policy unless some item is still
+// protected by it, as is the case if protection was kept, or if the vault
+// retains the instance's backups after protection was stopped
+func (s *serviceManager) deletePolicy(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*backupInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *backupInstanceDetails",
+		)
+	}
+	if dt.PolicyName == "" {
+		return dt, nil
+	}
+	_, ok, err := s.backupManager.GetVault(dt.VaultName, instance.ResourceGroup)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return dt, nil
+	}
+	protectedItems, err := s.backupManager.GetVaultProtectedItems(
+		dt.VaultName,
+		instance.ResourceGroup,
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, protectedItem := range protectedItems {
+		if strings.EqualFold(protectedItem.PolicyID, dt.PolicyID) {
+			return dt, nil
+		}
+	}
+	if err := s.backupManager.DeletePolicy(
+		dt.VaultName,
+		dt.PolicyName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+// deleteVault deletes the vault if the broker created it, the instance didn't
+// ask for it to be kept, and it protects no items anymore. A vault shared by
+// several instances therefore goes with the last of them to be deprovisioned.
+func (s *serviceManager) deleteVault(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*backupInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *backupInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*backup.ProvisioningParameters",
+		)
+	}
+	if pp.KeepVault || dt.VaultName == "" {
+		return dt, nil
+	}
+	vault, ok, err := s.backupManager.GetVault(
+		dt.VaultName,
+		instance.ResourceGroup,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !ok || vault.Tags[arm.HeritageTagName] != arm.HeritageTagValue {
+		return dt, nil
+	}
+	protectedItems, err := s.backupManager.GetVaultProtectedItems(
+		dt.VaultName,
+		instance.ResourceGroup,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(protectedItems) > 0 {
+		return dt, nil
+	}
+	if err := s.backupManager.DeleteVault(
+		dt.VaultName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
@@ -0,0 +1,401 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	frequencyDaily  = "Daily"
+	frequencyWeekly = "Weekly"
+
+	defaultTime           = "02:00"
+	defaultRetentionDays  = 30
+	defaultRetentionWeeks = 12
+
+	// These are the bounds Azure places on the retention of backups of virtual
+	// machines
+	minRetentionDays  = 7
+	maxRetentionDays  = 9999
+	minRetentionWeeks = 1
+	maxRetentionWeeks = 5163
+)
+
+var weekdays = []string{
+	"Sunday",
+	"Monday",
+	"Tuesday",
+	"Wednesday",
+	"Thursday",
+	"Friday",
+	"Saturday",
+}
+
+// resourceIDRegex matches the IDs of virtual machines, capturing the resource
+// group and name by which Azure Backup identifies them
+var resourceIDRegex = regexp.MustCompile(
+	`(?i)^/subscriptions/[^/]+/resourceGroups/([^/]+)/providers/` +
+		`Microsoft\.Compute/virtualMachines/([^/]+)$`,
+)
+
+var vaultNameRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9-]{1,49}$`)
+
+// Backups may only be scheduled on the hour or the half hour
+var timeRegex = regexp.MustCompile(`^([01][0-9]|2[0-3]):(00|30)$`)
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*backup.ProvisioningParameters",
+		)
+	}
+	if !resourceIDRegex.MatchString(pp.ResourceID) {
+		return service.NewValidationError(
+			"resourceId",
+			fmt.Sprintf(
+				`invalid resourceId: "%s"; it must be the ID of a virtual machine`,
+				pp.ResourceID,
+			),
+		)
+	}
+	if pp.VaultName != "" && !vaultNameRegex.MatchString(pp.VaultName) {
+		return service.NewValidationError(
+			"vault",
+			fmt.Sprintf(
+				`invalid vault: "%s"; names must be 2 to 50 letters, digits and `+
+					`hyphens, beginning with a letter`,
+				pp.VaultName,
+			),
+		)
+	}
+	if pp.Policy == nil {
+		return nil
+	}
+	frequency := frequencyDaily
+	if pp.Policy.Schedule != nil {
+		if err := validateSchedule(pp.Policy.Schedule); err != nil {
+			return err
+		}
+		if pp.Policy.Schedule.Frequency != "" {
+			frequency = pp.Policy.Schedule.Frequency
+		}
+	}
+	if pp.Policy.Retention == nil {
+		return nil
+	}
+	return validateRetention(pp.Policy.Retention, frequency)
+}
+
+func validateSchedule(schedule *Schedule) error {
+	switch schedule.Frequency {
+	case "", frequencyDaily:
+		if len(schedule.Days) > 0 {
+			return service.NewValidationError(
+				"policy.schedule.days",
+				"days may only be specified for weekly backups",
+			)
+		}
+	case frequencyWeekly:
+		if len(schedule.Days) == 0 {
+			return service.NewValidationError(
+				"policy.schedule.days",
+				"at least one day must be specified for weekly backups",
+			)
+		}
+	default:
+		return service.NewValidationError(
+			"policy.schedule.frequency",
+			fmt.Sprintf(
+				`invalid frequency: "%s"; backups must be taken "%s" or "%s"`,
+				schedule.Frequency,
+				frequencyDaily,
+				frequencyWeekly,
+			),
+		)
+	}
+	if schedule.Time != "" && !timeRegex.MatchString(schedule.Time) {
+		return service.NewValidationError(
+			"policy.schedule.time",
+			fmt.Sprintf(
+				`invalid time: "%s"; time must be formatted as HH:MM and fall on `+
+					`the hour or the half hour`,
+				schedule.Time,
+			),
+		)
+	}
+	seen := map[string]bool{}
+	for _, day := range schedule.Days {
+		if !isWeekday(day) {
+			return service.NewValidationError(
+				"policy.schedule.days",
+				fmt.Sprintf(`invalid day: "%s"`, day),
+			)
+		}
+		if seen[day] {
+			return service.NewValidationError(
+				"policy.schedule.days",
+				fmt.Sprintf(`day "%s" is specified more than once`, day),
+			)
+		}
+		seen[day] = true
+	}
+	return nil
+}
+
+func isWeekday(day string) bool {
+	for _, weekday := range weekdays {
+		if day == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// validateRetention validates the retention of backups taken at the given
+// frequency. Daily backups are retained for a number of days, and weekly ones
+// for a number of weeks.
+func validateRetention(retention *Retention, frequency string) error {
+	if frequency == frequencyWeekly {
+		if retention.Days != 0 {
+			return service.NewValidationError(
+				"policy.retention.days",
+				"retention of weekly backups must be specified in weeks",
+			)
+		}
+		if retention.Weeks != 0 &&
+			(retention.Weeks < minRetentionWeeks ||
+				retention.Weeks > maxRetentionWeeks) {
+			return service.NewValidationError(
+				"policy.retention.weeks",
+				fmt.Sprintf(
+					"invalid weeks: %d; backups must be retained for %d to %d weeks",
+					retention.Weeks,
+					minRetentionWeeks,
+					maxRetentionWeeks,
+				),
+			)
+		}
+		return nil
+	}
+	if retention.Weeks != 0 {
+		return service.NewValidationError(
+			"policy.retention.weeks",
+			"retention of daily backups must be specified in days",
+		)
+	}
+	if retention.Days != 0 &&
+		(retention.Days < minRetentionDays || retention.Days > maxRetentionDays) {
+		return service.NewValidationError(
+			"policy.retention.days",
+			fmt.Sprintf(
+				"invalid days: %d; backups must be retained for %d to %d days",
+				retention.Days,
+				minRetentionDays,
+				maxRetentionDays,
+			),
+		)
+	}
+	return nil
+}
+
+func (s *serviceManager) ApplyProvisioningParametersDefaults(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*backup.ProvisioningParameters",
+		)
+	}
+	if pp.Policy == nil {
+		pp.Policy = &Policy{}
+	}
+	if pp.Policy.Schedule == nil {
+		pp.Policy.Schedule = &Schedule{}
+	}
+	if pp.Policy.Schedule.Frequency == "" {
+		pp.Policy.Schedule.Frequency = frequencyDaily
+	}
+	if pp.Policy.Schedule.Time == "" {
+		pp.Policy.Schedule.Time = defaultTime
+	}
+	if pp.Policy.Retention == nil {
+		pp.Policy.Retention = &Retention{}
+	}
+	if pp.Policy.Schedule.Frequency == frequencyWeekly {
+		if pp.Policy.Retention.Weeks == 0 {
+			pp.Policy.Retention.Weeks = defaultRetentionWeeks
+		}
+	} else if pp.Policy.Retention.Days == 0 {
+		pp.Policy.Retention.Days = defaultRetentionDays
+	}
+	return nil
+}
+
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep("deployARMTemplate", s.deployARMTemplate),
+	)
+}
+
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*backupInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *backupInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*backup.ProvisioningParameters",
+		)
+	}
+	dt.ARMDeploymentName = uuid.NewV4().String()
+	dt.PolicyName = "policy-" + uuid.NewV4().String()
+	dt.VaultName = pp.VaultName
+	if dt.VaultName == "" {
+		dt.VaultName = "vault-" + uuid.NewV4().String()
+	}
+	// Azure Backup identifies a virtual machine, and the container it is
+	// protected in, by the machine's resource group and name
+	matches := resourceIDRegex.FindStringSubmatch(pp.ResourceID)
+	if matches == nil {
+		return nil, fmt.Errorf(`invalid resourceId: "%s"`, pp.ResourceID)
+	}
+	dt.ContainerName = fmt.Sprintf(
+		"iaasvmcontainer;iaasvmcontainerv2;%s;%s",
+		matches[1],
+		matches[2],
+	)
+	dt.ProtectedItemName = fmt.Sprintf(
+		"vm;iaasvmcontainerv2;%s;%s",
+		matches[1],
+		matches[2],
+	)
+	return dt, nil
+}
+
+func (s *serviceManager) deployARMTemplate(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*backupInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *backupInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*backup.ProvisioningParameters",
+		)
+	}
+	// A vault that already exists is left as it is, along with anything else
+	// it protects
+	_, vaultExists, err := s.backupManager.GetVault(
+		dt.VaultName,
+		instance.ResourceGroup,
+	)
+	if err != nil {
+		return nil, err
+	}
+	outputs, err := s.armDeployer.Deploy(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateBytes,
+		map[string]interface{}{ // Go template params
+			"createVault": !vaultExists,
+		},
+		map[string]interface{}{ // ARM template params
+			"vaultName":         dt.VaultName,
+			"policyName":        dt.PolicyName,
+			"schedulePolicy":    getSchedulePolicy(pp.Policy.Schedule),
+			"retentionPolicy":   getRetentionPolicy(pp.Policy),
+			"containerName":     dt.ContainerName,
+			"protectedItemName": dt.ProtectedItemName,
+			"resourceId":        pp.ResourceID,
+		},
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+	if dt.VaultID, ok = outputs["vaultId"].(string); !ok {
+		return nil, errors.New("error retrieving vault id from deployment")
+	}
+	if dt.PolicyID, ok = outputs["policyId"].(string); !ok {
+		return nil, errors.New("error retrieving policy id from deployment")
+	}
+	if dt.ProtectedItemID, ok = outputs["protectedItemId"].(string); !ok {
+		return nil, errors.New(
+			"error retrieving protected item id from deployment",
+		)
+	}
+	return dt, nil
+}
+
+// getRunTimes returns the time of day at which backups are scheduled in the
+// form Azure expects, i.e. as a timestamp whose date is ignored
+func getRunTimes(schedule *Schedule) []string {
+	return []string{fmt.Sprintf("2000-01-01T%s:00Z", schedule.Time)}
+}
+
+func getSchedulePolicy(schedule *Schedule) map[string]interface{} {
+	schedulePolicy := map[string]interface{}{
+		"schedulePolicyType":   "SimpleSchedulePolicy",
+		"scheduleRunFrequency": schedule.Frequency,
+		"scheduleRunTimes":     getRunTimes(schedule),
+	}
+	if schedule.Frequency == frequencyWeekly {
+		schedulePolicy["scheduleRunDays"] = schedule.Days
+	}
+	return schedulePolicy
+}
+
+// getRetentionPolicy returns a retention policy that retains each of the
+// backups the policy's schedule calls for
+func getRetentionPolicy(policy *Policy) map[string]interface{} {
+	retentionPolicy := map[string]interface{}{
+		"retentionPolicyType": "LongTermRetentionPolicy",
+	}
+	if policy.Schedule.Frequency == frequencyWeekly {
+		retentionPolicy["weeklySchedule"] = map[string]interface{}{
+			"daysOfTheWeek":  policy.Schedule.Days,
+			"retentionTimes": getRunTimes(policy.Schedule),
+			"retentionDuration": map[string]interface{}{
+				"count":        policy.Retention.Weeks,
+				"durationType": "Weeks",
+			},
+		}
+		return retentionPolicy
+	}
+	retentionPolicy["dailySchedule"] = map[string]interface{}{
+		"retentionTimes": getRunTimes(policy.Schedule),
+		"retentionDuration": map[string]interface{}{
+			"count":        policy.Retention.Days,
+			"durationType": "Days",
+		},
+	}
+	return retentionPolicy
+}
@@ -0,0 +1,153 @@
+package backup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testResourceID = "/subscriptions/sub/resourceGroups/rg/providers/" +
+	"Microsoft.Compute/virtualMachines/vm"
+
+func TestValidateProvisioningParameters(t *testing.T) {
+	m := &module{}
+	err := m.serviceManager.ValidateProvisioningParameters(
+		&ProvisioningParameters{ResourceID: testResourceID},
+	)
+	assert.Nil(t, err)
+	err = m.serviceManager.ValidateProvisioningParameters(
+		&ProvisioningParameters{
+			ResourceID: testResourceID,
+			VaultName:  "shared-vault",
+			Policy: &Policy{
+				Schedule: &Schedule{
+					Frequency: frequencyWeekly,
+					Time:      "23:30",
+					Days:      []string{"Saturday", "Sunday"},
+				},
+				Retention: &Retention{Weeks: 52},
+			},
+		},
+	)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidResourceID(t *testing.T) {
+	m := &module{}
+	for _, resourceID := range []string{
+		"",
+		"/subscriptions/sub/resourceGroups/rg/providers/" +
+			"Microsoft.Storage/storageAccounts/sa",
+		testResourceID + "/extensions/ext",
+	} {
+		err := m.serviceManager.ValidateProvisioningParameters(
+			&ProvisioningParameters{ResourceID: resourceID},
+		)
+		assert.NotNil(t, err, resourceID)
+	}
+}
+
+func TestValidateProvisioningParametersWithInvalidVault(t *testing.T) {
+	m := &module{}
+	for _, vaultName := range []string{"v", "1vault", "shared_vault"} {
+		err := m.serviceManager.ValidateProvisioningParameters(
+			&ProvisioningParameters{
+				ResourceID: testResourceID,
+				VaultName:  vaultName,
+			},
+		)
+		assert.NotNil(t, err, vaultName)
+	}
+}
+
+func TestValidateProvisioningParametersWithInvalidSchedule(t *testing.T) {
+	m := &module{}
+	for _, schedule := range []*Schedule{
+		{Frequency: "Hourly"},
+		{Time: "2:00"},
+		{Time: "02:15"},
+		{Days: []string{"Monday"}},
+		{Frequency: frequencyWeekly},
+		{Frequency: frequencyWeekly, Days: []string{"Funday"}},
+		{Frequency: frequencyWeekly, Days: []string{"Monday", "Monday"}},
+	} {
+		err := m.serviceManager.ValidateProvisioningParameters(
+			&ProvisioningParameters{
+				ResourceID: testResourceID,
+				Policy:     &Policy{Schedule: schedule},
+			},
+		)
+		assert.NotNil(t, err, "%+v", schedule)
+	}
+}
+
+func TestValidateProvisioningParametersWithInvalidRetention(t *testing.T) {
+	m := &module{}
+	weekly := &Schedule{Frequency: frequencyWeekly, Days: []string{"Sunday"}}
+	testCases := []struct {
+		schedule  *Schedule
+		retention *Retention
+	}{
+		{retention: &Retention{Days: 6}},
+		{retention: &Retention{Days: 10000}},
+		{retention: &Retention{Weeks: 4}},
+		{schedule: weekly, retention: &Retention{Weeks: 5164}},
+		{schedule: weekly, retention: &Retention{Days: 30}},
+	}
+	for _, testCase := range testCases {
+		err := m.serviceManager.ValidateProvisioningParameters(
+			&ProvisioningParameters{
+				ResourceID: testResourceID,
+				Policy: &Policy{
+					Schedule:  testCase.schedule,
+					Retention: testCase.retention,
+				},
+			},
+		)
+		assert.NotNil(t, err, "%+v", testCase.retention)
+	}
+}
+
+func TestApplyProvisioningParametersDefaults(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{}
+	err := m.serviceManager.ApplyProvisioningParametersDefaults(pp)
+	assert.Nil(t, err)
+	assert.Equal(t, frequencyDaily, pp.Policy.Schedule.Frequency)
+	assert.Equal(t, defaultTime, pp.Policy.Schedule.Time)
+	assert.Equal(t, defaultRetentionDays, pp.Policy.Retention.Days)
+	assert.Equal(t, 0, pp.Policy.Retention.Weeks)
+	pp = &ProvisioningParameters{
+		Policy: &Policy{
+			Schedule: &Schedule{
+				Frequency: frequencyWeekly,
+				Days:      []string{"Sunday"},
+			},
+		},
+	}
+	err = m.serviceManager.ApplyProvisioningParametersDefaults(pp)
+	assert.Nil(t, err)
+	assert.Equal(t, defaultRetentionWeeks, pp.Policy.Retention.Weeks)
+	assert.Equal(t, 0, pp.Policy.Retention.Days)
+}
+
+func TestGetRetentionPolicy(t *testing.T) {
+	retentionPolicy := getRetentionPolicy(&Policy{
+		Schedule: &Schedule{
+			Frequency: frequencyWeekly,
+			Time:      "04:30",
+			Days:      []string{"Sunday"},
+		},
+		Retention: &Retention{Weeks: 8},
+	})
+	assert.Nil(t, retentionPolicy["dailySchedule"])
+	weeklySchedule, ok :=
+		retentionPolicy["weeklySchedule"].(map[string]interface{})
+	assert.True(t, ok)
+	assert.Equal(t, []string{"Sunday"}, weeklySchedule["daysOfTheWeek"])
+	assert.Equal(
+		t,
+		[]string{"2000-01-01T04:30:00Z"},
+		weeklySchedule["retentionTimes"],
+	)
+}
@@ -0,0 +1,101 @@
+package backup
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// ProvisioningParameters encapsulates backup-specific provisioning options
+type ProvisioningParameters struct {
+	// ResourceID is the ID of the virtual machine to enroll in backup
+	// protection
+	ResourceID string `json:"resourceId"`
+	// VaultName, if specified, names a Recovery Services vault in the
+	// instance's resource group to protect the resource, which is created if it
+	// doesn't already exist. Otherwise, a new vault is created for the
+	// instance alone.
+	VaultName string `json:"vault" template:"true"`
+	// KeepVault indicates whether the vault is retained once it protects no
+	// items anymore. Vaults the broker didn't create are always retained.
+	KeepVault bool `json:"keepVault"`
+	// KeepProtection indicates whether the resource remains protected, and its
+	// backups are retained, after the instance is deprovisioned
+	KeepProtection bool    `json:"keepProtection"`
+	Policy         *Policy `json:"policy"`
+}
+
+// Policy encapsulates when the resource is backed up and how long its
+// backups are retained
+type Policy struct {
+	Schedule  *Schedule  `json:"schedule"`
+	Retention *Retention `json:"retention"`
+}
+
+// Schedule encapsulates when the resource is backed up. Backups are taken
+// daily, or on the given days of the week, at the given time, in UTC.
+type Schedule struct {
+	Frequency string   `json:"frequency"`
+	Time      string   `json:"time"`
+	Days      []string `json:"days"`
+}
+
+// Retention encapsulates how long backups are retained: a number of days for
+// daily backups, or a number of weeks for weekly ones
+type Retention struct {
+	Days  int `json:"days"`
+	Weeks int `json:"weeks"`
+}
+
+type backupInstanceDetails struct {
+	ARMDeploymentName string `json:"armDeployment"`
+	VaultName         string `json:"vaultName"`
+	VaultID           string `json:"vaultId"`
+	PolicyName        string `json:"policyName"`
+	PolicyID          string `json:"policyId"`
+	ContainerName     string `json:"containerName"`
+	ProtectedItemName string `json:"protectedItemName"`
+	ProtectedItemID   string `json:"protectedItemId"`
+}
+
+// UpdatingParameters encapsulates backup-specific updating options
+type UpdatingParameters struct {
+}
+
+// BindingParameters encapsulates backup-specific binding options
+type BindingParameters struct {
+}
+
+type backupBindingDetails struct {
+}
+
+// Credentials encapsulates the details needed to manage the protected
+// resource's backups, e.g. to trigger an on-demand backup or a restore
+type Credentials struct {
+	VaultName       string `json:"vaultName"`
+	VaultID         string `json:"vaultId"`
+	PolicyID        string `json:"policyId"`
+	ProtectedItemID string `json:"protectedItemId"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &backupInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &backupBindingDetails{}
+}
@@ -0,0 +1,42 @@
+package bastion
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/bastion"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer    arm.Deployer
+	bastionManager bastion.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of provisioning Azure Bastion hosts
+func New(
+	armDeployer arm.Deployer,
+	bastionManager bastion.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer:    armDeployer,
+			bastionManager: bastionManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "bastion"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{"Microsoft.Network"}
+}
@@ -0,0 +1,47 @@
+package bastion
+
+import (
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) ValidateBindingParameters(
+	service.BindingParameters,
+) error {
+	// There are no parameters for binding to a Bastion host, so there is
+	// nothing to validate
+	return nil
+}
+
+func (s *serviceManager) Bind(
+	service.Instance,
+	service.BindingParameters,
+) (service.BindingDetails, error) {
+	return &bastionBindingDetails{}, nil
+}
+
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	_ service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*bastionInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *bastionInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*bastion.ProvisioningParameters",
+		)
+	}
+	return &bastionCredentials{
+		BastionHostName: dt.BastionHostName,
+		BastionHostID:   dt.BastionHostID,
+		DNSName:         dt.DNSName,
+		SKU:             getSKU(pp),
+	}, nil
+}
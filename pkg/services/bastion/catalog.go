@@ -0,0 +1,25 @@
+package bastion
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:          "b3e81f2a-6c4d-4a97-9e25-1d7f0c8b5a36",
+				Name:        "azure-bastion",
+				Description: "Azure Bastion (Experimental)",
+				Bindable:    true,
+				Cloneable:   true,
+				Tags:        []string{"Azure", "Bastion", "Network", "RDP", "SSH"},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:          "6f2a9c04-8e1b-4d53-b7a6-3c5e9d1f0b82",
+				Name:        "standard",
+				Description: "A Bastion host in an existing virtual network",
+				Free:        false,
+			}),
+		),
+	}), nil
+}
@@ -0,0 +1,80 @@
+package bastion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) GetDeprovisioner(
+	service.Plan,
+) (service.Deprovisioner, error) {
+	return service.NewDeprovisioner(
+		service.NewDeprovisioningStep("deleteARMDeployment", s.deleteARMDeployment),
+		service.NewDeprovisioningStep("deleteBastionHost", s.deleteBastionHost),
+		// The public IP address can't be deleted while the host still uses it
+		service.NewDeprovisioningStep(
+			"deletePublicIPAddress",
+			s.deletePublicIPAddress,
+		),
+	)
+}
+
+func (s *serviceManager) deleteARMDeployment(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*bastionInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *bastionInstanceDetails",
+		)
+	}
+	if err := s.armDeployer.Delete(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, fmt.Errorf("error deleting ARM deployment: %s", err)
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deleteBastionHost(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*bastionInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *bastionInstanceDetails",
+		)
+	}
+	if err := s.bastionManager.DeleteBastionHost(
+		dt.BastionHostName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deletePublicIPAddress(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*bastionInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *bastionInstanceDetails",
+		)
+	}
+	if err := s.bastionManager.DeletePublicIPAddress(
+		dt.PublicIPAddressName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
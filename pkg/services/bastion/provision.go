@@ -0,0 +1,263 @@
+package bastion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/bastion"
+	"github.com/Azure/open-service-broker-azure/pkg/generate"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	skuBasic    = "Basic"
+	skuStandard = "Standard"
+	// Bastion hosts may only be deployed into a subnet having this name
+	bastionSubnetName = "AzureBastionSubnet"
+	// maxSubnetPrefixLength is the longest prefix (i.e. the smallest subnet)
+	// that a Bastion host may be deployed into
+	maxSubnetPrefixLength = 26
+	// Basic hosts always have two scale units. Standard hosts may be scaled.
+	minScaleUnits = 2
+	maxScaleUnits = 50
+)
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*bastion.ProvisioningParameters",
+		)
+	}
+	if pp.SubnetID == "" {
+		return service.NewValidationError("subnetId", "subnetId is required")
+	}
+	if !az.IsValidSubnetID(pp.SubnetID) {
+		return service.NewValidationError(
+			"subnetId",
+			fmt.Sprintf(`invalid subnetId: "%s"`, pp.SubnetID),
+		)
+	}
+	if bastion.GetSubnetName(pp.SubnetID) != bastionSubnetName {
+		return service.NewValidationError(
+			"subnetId",
+			fmt.Sprintf(
+				`invalid subnetId: "%s"; Bastion hosts may only be deployed into `+
+					`a subnet named %s`,
+				pp.SubnetID,
+				bastionSubnetName,
+			),
+		)
+	}
+	if pp.SKU != "" && pp.SKU != skuBasic && pp.SKU != skuStandard {
+		return service.NewValidationError(
+			"sku",
+			fmt.Sprintf(
+				`invalid sku: "%s"; allowed values are %s and %s`,
+				pp.SKU,
+				skuBasic,
+				skuStandard,
+			),
+		)
+	}
+	if pp.ScaleUnits == 0 {
+		return nil
+	}
+	if getSKU(pp) == skuBasic && pp.ScaleUnits != minScaleUnits {
+		return service.NewValidationError(
+			"scaleUnits",
+			fmt.Sprintf(
+				"invalid scaleUnits: %d; Basic hosts always have %d scale units",
+				pp.ScaleUnits,
+				minScaleUnits,
+			),
+		)
+	}
+	if pp.ScaleUnits < minScaleUnits || pp.ScaleUnits > maxScaleUnits {
+		return service.NewValidationError(
+			"scaleUnits",
+			fmt.Sprintf(
+				"invalid scaleUnits: %d; scaleUnits must be between %d and %d",
+				pp.ScaleUnits,
+				minScaleUnits,
+				maxScaleUnits,
+			),
+		)
+	}
+	return nil
+}
+
+func (s *serviceManager) ApplyProvisioningParametersDefaults(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*bastion.ProvisioningParameters",
+		)
+	}
+	pp.SKU = getSKU(pp)
+	if pp.ScaleUnits == 0 {
+		pp.ScaleUnits = minScaleUnits
+	}
+	return nil
+}
+
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep("deployARMTemplate", s.deployARMTemplate),
+	)
+}
+
+// preProvision checks that the subnet the host is to be deployed into exists,
+// is large enough, and is in the instance's location. None of that can be
+// known without looking the subnet up, so it is checked here rather than when
+// parameters are first validated.
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*bastionInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *bastionInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*bastion.ProvisioningParameters",
+		)
+	}
+	subnet, ok, err := s.bastionManager.GetSubnet(pp.SubnetID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, service.NewValidationError(
+			"subnetId",
+			fmt.Sprintf(`subnet "%s" does not exist`, pp.SubnetID),
+		)
+	}
+	if !isSubnetLargeEnough(subnet) {
+		return nil, service.NewValidationError(
+			"subnetId",
+			fmt.Sprintf(
+				`subnet "%s" is too small; Bastion hosts require a /%d or larger`,
+				pp.SubnetID,
+				maxSubnetPrefixLength,
+			),
+		)
+	}
+	if !strings.EqualFold(
+		normalizeLocation(subnet.Location),
+		normalizeLocation(instance.Location),
+	) {
+		return nil, service.NewValidationError(
+			"subnetId",
+			fmt.Sprintf(
+				`subnet "%s" is in location "%s"; it must be in the same location `+
+					`as the Bastion host`,
+				pp.SubnetID,
+				subnet.Location,
+			),
+		)
+	}
+	dt.ARMDeploymentName = uuid.NewV4().String()
+	dt.BastionHostName = generate.NewIdentifier()
+	dt.PublicIPAddressName = generate.NewIdentifier()
+	return dt, nil
+}
+
+func (s *serviceManager) deployARMTemplate(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*bastionInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *bastionInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*bastion.ProvisioningParameters",
+		)
+	}
+	scaleUnits := pp.ScaleUnits
+	if scaleUnits == 0 {
+		scaleUnits = minScaleUnits
+	}
+	outputs, err := s.armDeployer.Deploy(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateBytes,
+		nil, // Go template params
+		map[string]interface{}{ // ARM template params
+			"bastionHostName":     dt.BastionHostName,
+			"publicIPAddressName": dt.PublicIPAddressName,
+			"subnetId":            pp.SubnetID,
+			"sku":                 getSKU(pp),
+			"scaleUnits":          scaleUnits,
+		},
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+	bastionHostID, ok := outputs["bastionHostId"].(string)
+	if !ok {
+		return nil, errors.New("error retrieving bastion host id from deployment")
+	}
+	dt.BastionHostID = bastionHostID
+	dnsName, ok := outputs["dnsName"].(string)
+	if !ok {
+		return nil, errors.New("error retrieving dns name from deployment")
+	}
+	dt.DNSName = dnsName
+	return dt, nil
+}
+
+func getSKU(pp *ProvisioningParameters) string {
+	if pp.SKU == "" {
+		return skuBasic
+	}
+	return pp.SKU
+}
+
+// isSubnetLargeEnough returns a bool indicating whether any of the subnet's
+// address ranges is large enough to accommodate a Bastion host
+func isSubnetLargeEnough(subnet *bastion.Subnet) bool {
+	for _, addressPrefix := range subnet.AddressPrefixes {
+		_, ipNet, err := net.ParseCIDR(addressPrefix)
+		if err != nil {
+			continue
+		}
+		if ones, _ := ipNet.Mask.Size(); ones <= maxSubnetPrefixLength {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeLocation accounts for locations being reported by display name
+// (e.g. "East US") in some places and by name (e.g. "eastus") in others
+func normalizeLocation(location string) string {
+	return strings.Replace(location, " ", "", -1)
+}
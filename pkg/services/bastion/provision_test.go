@@ -0,0 +1,94 @@
+package bastion
+
+import (
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/bastion"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSubnetID = "/subscriptions/foo/resourceGroups/bar/providers/" +
+	"Microsoft.Network/virtualNetworks/baz/subnets/AzureBastionSubnet"
+
+func TestValidateProvisioningParametersWithInvalidSubnetID(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.SubnetID = "/subscriptions/foo/resourceGroups/bar"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	// The subnet must be named AzureBastionSubnet
+	pp.SubnetID = "/subscriptions/foo/resourceGroups/bar/providers/" +
+		"Microsoft.Network/virtualNetworks/baz/subnets/default"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.SubnetID = testSubnetID
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidSKU(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		SubnetID: testSubnetID,
+		SKU:      "Premium",
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.SKU = "Standard"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidScaleUnits(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		SubnetID:   testSubnetID,
+		ScaleUnits: 4,
+	}
+	// Basic hosts can't be scaled
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.SKU = "Standard"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+	pp.ScaleUnits = 51
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.ScaleUnits = 1
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+}
+
+func TestApplyProvisioningParametersDefaults(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		SubnetID: testSubnetID,
+	}
+	err := m.serviceManager.ApplyProvisioningParametersDefaults(pp)
+	assert.Nil(t, err)
+	assert.Equal(t, skuBasic, pp.SKU)
+	assert.Equal(t, minScaleUnits, pp.ScaleUnits)
+	// Parameters that were specified are left as they were
+	pp = &ProvisioningParameters{
+		SubnetID:   testSubnetID,
+		SKU:        skuStandard,
+		ScaleUnits: 10,
+	}
+	err = m.serviceManager.ApplyProvisioningParametersDefaults(pp)
+	assert.Nil(t, err)
+	assert.Equal(t, skuStandard, pp.SKU)
+	assert.Equal(t, 10, pp.ScaleUnits)
+}
+
+func TestIsSubnetLargeEnough(t *testing.T) {
+	subnet := &bastion.Subnet{
+		AddressPrefixes: []string{"10.0.1.0/27"},
+	}
+	assert.False(t, isSubnetLargeEnough(subnet))
+	subnet.AddressPrefixes = append(subnet.AddressPrefixes, "10.0.2.0/26")
+	assert.True(t, isSubnetLargeEnough(subnet))
+	subnet.AddressPrefixes = []string{"10.0.0.0/24"}
+	assert.True(t, isSubnetLargeEnough(subnet))
+}
@@ -0,0 +1,63 @@
+package bastion
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// ProvisioningParameters encapsulates Azure Bastion-specific provisioning
+// options
+type ProvisioningParameters struct {
+	SubnetID   string `json:"subnetId"`
+	SKU        string `json:"sku"`
+	ScaleUnits int    `json:"scaleUnits"`
+}
+
+type bastionInstanceDetails struct {
+	ARMDeploymentName   string `json:"armDeployment"`
+	BastionHostName     string `json:"bastionHostName"`
+	BastionHostID       string `json:"bastionHostId"`
+	PublicIPAddressName string `json:"publicIPAddressName"`
+	DNSName             string `json:"dnsName"`
+}
+
+// UpdatingParameters encapsulates Azure Bastion-specific updating options
+type UpdatingParameters struct {
+}
+
+// BindingParameters encapsulates Azure Bastion-specific binding options
+type BindingParameters struct {
+}
+
+type bastionBindingDetails struct {
+}
+
+type bastionCredentials struct {
+	BastionHostName string `json:"bastionHostName"`
+	BastionHostID   string `json:"bastionHostId"`
+	DNSName         string `json:"dnsName"`
+	SKU             string `json:"sku"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &bastionInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &bastionBindingDetails{}
+}
@@ -0,0 +1,12 @@
+package bastion
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) Unbind(
+	service.Instance,
+	service.BindingDetails,
+) error {
+	return nil
+}
@@ -0,0 +1,58 @@
+package chaosstudio
+
+// nolint: lll
+var armTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "experimentName": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of the chaos experiment"
+      }
+    },
+    "selectors": {
+      "type": "array"
+    },
+    "steps": {
+      "type": "array"
+    },
+    "tags": {
+      "type": "object"
+    }
+  },
+  "variables": {
+    "apiVersion": "2023-11-01"
+  },
+  "resources": [
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[parameters('experimentName')]",
+      "type": "Microsoft.Chaos/experiments",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "identity": {
+        "type": "SystemAssigned"
+      },
+      "properties": {
+        "selectors": "[parameters('selectors')]",
+        "steps": "[parameters('steps')]"
+      }
+    }
+  ],
+  "outputs": {
+    "experimentId": {
+      "type": "string",
+      "value": "[resourceId('Microsoft.Chaos/experiments', parameters('experimentName'))]"
+    },
+    "principalId": {
+      "type": "string",
+      "value": "[reference(parameters('experimentName'), variables('apiVersion'), 'Full').identity.principalId]"
+    }
+  }
+}
+`)
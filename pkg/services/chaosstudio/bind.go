@@ -0,0 +1,44 @@
+package chaosstudio
+
+import (
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) ValidateBindingParameters(
+	service.BindingParameters,
+) error {
+	// There are no parameters for binding to Chaos Studio, so there is nothing
+	// to validate
+	return nil
+}
+
+// Bind does nothing. Running an experiment requires Azure credentials of the
+// binder's own, permitted to start it.
+func (s *serviceManager) Bind(
+	service.Instance,
+	service.BindingParameters,
+) (service.BindingDetails, error) {
+	return &chaosStudioBindingDetails{}, nil
+}
+
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	_ service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*chaosStudioInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *chaosStudioInstanceDetails",
+		)
+	}
+	urls := s.chaosStudioManager.GetExperimentURLs(dt.ExperimentID)
+	return &Credentials{
+		ExperimentID: dt.ExperimentID,
+		PrincipalID:  dt.PrincipalID,
+		StartURL:     urls.Start,
+		CancelURL:    urls.Cancel,
+		StatusURL:    urls.Executions,
+	}, nil
+}
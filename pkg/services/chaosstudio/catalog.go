@@ -0,0 +1,49 @@
+package chaosstudio
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:          "23f1fc1b-4ade-4853-a5c2-40284a2389d0",
+				Name:        "azure-chaos-experiment",
+				Description: "Azure Chaos Studio Experiment (Experimental)",
+				Bindable:    true,
+				Tags:        []string{"Azure", "Chaos Studio", "Resilience"},
+				// Chaos experiments can only be created in some regions, though they
+				// may target resources in any region
+				Locations: []string{
+					"australiaeast",
+					"brazilsouth",
+					"canadacentral",
+					"centralindia",
+					"centralus",
+					"eastasia",
+					"eastus",
+					"eastus2",
+					"japaneast",
+					"koreacentral",
+					"northcentralus",
+					"northeurope",
+					"southcentralus",
+					"southeastasia",
+					"swedencentral",
+					"uksouth",
+					"westcentralus",
+					"westeurope",
+					"westus",
+					"westus2",
+					"westus3",
+				},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:          "6d9c88da-72b8-4d61-9822-30d15c29870b",
+				Name:        "standard",
+				Description: "Billed per target action-minute while the experiment runs",
+				Free:        false,
+			}),
+		),
+	}), nil
+}
@@ -0,0 +1,42 @@
+package chaosstudio
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/chaosstudio"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer        arm.Deployer
+	chaosStudioManager chaosstudio.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of provisioning Azure Chaos Studio experiments
+func New(
+	armDeployer arm.Deployer,
+	chaosStudioManager chaosstudio.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer:        armDeployer,
+			chaosStudioManager: chaosStudioManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "chaosstudio"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{"Microsoft.Chaos"}
+}
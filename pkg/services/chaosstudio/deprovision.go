@@ -0,0 +1,56 @@
+package chaosstudio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) GetDeprovisioner(
+	service.Plan,
+) (service.Deprovisioner, error) {
+	return service.NewDeprovisioner(
+		service.NewDeprovisioningStep("deleteARMDeployment", s.deleteARMDeployment),
+		service.NewDeprovisioningStep("deleteExperiment", s.deleteExperiment),
+	)
+}
+
+func (s *serviceManager) deleteARMDeployment(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*chaosStudioInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *chaosStudioInstanceDetails",
+		)
+	}
+	if err := s.armDeployer.Delete(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, fmt.Errorf("error deleting ARM deployment: %s", err)
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deleteExperiment(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*chaosStudioInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *chaosStudioInstanceDetails",
+		)
+	}
+	if err := s.chaosStudioManager.DeleteExperiment(
+		dt.ExperimentName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
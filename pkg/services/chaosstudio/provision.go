@@ -0,0 +1,463 @@
+package chaosstudio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	selectorID = "Selector1"
+	// maxTargets is the number of targets Chaos Studio permits a single
+	// selector to name
+	maxTargets = 50
+	// maxFaultDuration is the longest Chaos Studio permits a single continuous
+	// fault to last
+	maxFaultDuration = 12 * time.Hour
+	// maxRepetitions caps how many steps an experiment repeating its fault is
+	// made up of
+	maxRepetitions = 10
+)
+
+var targetTypeRegex = regexp.MustCompile(`^Microsoft-[A-Za-z]+$`)
+
+var targetResourceIDRegex = regexp.MustCompile(
+	`(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+/providers/[^/]+` +
+		`(/[^/]+/[^/]+)+$`,
+)
+
+var zones = []string{"1", "2", "3"}
+
+var faultURNRegex = regexp.MustCompile(
+	`^urn:csci:microsoft:[A-Za-z]+:[A-Za-z0-9]+/\d+\.\d+$`,
+)
+
+// durationRegex matches the subset of ISO 8601 durations Chaos Studio
+// accepts, e.g. PT10M or PT1H30M
+var durationRegex = regexp.MustCompile(
+	`^PT(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?$`,
+)
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*chaosstudio.ProvisioningParameters",
+		)
+	}
+	if !targetTypeRegex.MatchString(pp.TargetType) {
+		return service.NewValidationError(
+			"targetType",
+			fmt.Sprintf(
+				`invalid targetType: "%s"; it must be a Chaos Studio target type, `+
+					`e.g. Microsoft-VirtualMachine`,
+				pp.TargetType,
+			),
+		)
+	}
+	if len(pp.TargetResourceIDs) == 0 {
+		return service.NewValidationError(
+			"targetResourceIds",
+			"at least one target resource must be specified",
+		)
+	}
+	if len(pp.TargetResourceIDs) > maxTargets {
+		return service.NewValidationError(
+			"targetResourceIds",
+			fmt.Sprintf(
+				"no more than %d target resources may be specified",
+				maxTargets,
+			),
+		)
+	}
+	targetResourceIDs := map[string]bool{}
+	for _, id := range pp.TargetResourceIDs {
+		if !targetResourceIDRegex.MatchString(id) {
+			return service.NewValidationError(
+				"targetResourceIds",
+				fmt.Sprintf(`invalid target resource id: "%s"`, id),
+			)
+		}
+		if targetResourceIDs[strings.ToLower(id)] {
+			return service.NewValidationError(
+				"targetResourceIds",
+				fmt.Sprintf(`duplicate target resource id: "%s"`, id),
+			)
+		}
+		targetResourceIDs[strings.ToLower(id)] = true
+	}
+	for _, zone := range pp.Zones {
+		if !isValidZone(zone) {
+			return service.NewValidationError(
+				"zones",
+				fmt.Sprintf(
+					`invalid zone: "%s"; allowed values are: %s`,
+					zone,
+					strings.Join(zones, ", "),
+				),
+			)
+		}
+	}
+	if err := validateFault(pp.Fault); err != nil {
+		return err
+	}
+	return validateSchedule(pp.Schedule)
+}
+
+func validateFault(fault *Fault) error {
+	if fault == nil {
+		return service.NewValidationError("fault", "a fault must be specified")
+	}
+	if !faultURNRegex.MatchString(fault.URN) {
+		return service.NewValidationError(
+			"fault.urn",
+			fmt.Sprintf(
+				`invalid urn: "%s"; it must identify a Chaos Studio fault, e.g. `+
+					`urn:csci:microsoft:virtualMachine:shutdown/1.0`,
+				fault.URN,
+			),
+		)
+	}
+	if fault.Duration != "" {
+		duration, err := parseDuration(fault.Duration)
+		if err != nil {
+			return service.NewValidationError("fault.duration", err.Error())
+		}
+		if duration > maxFaultDuration {
+			return service.NewValidationError(
+				"fault.duration",
+				fmt.Sprintf(
+					`invalid duration: "%s"; it may not exceed %s`,
+					fault.Duration,
+					maxFaultDuration,
+				),
+			)
+		}
+	}
+	for key := range fault.Parameters {
+		if key == "" {
+			return service.NewValidationError(
+				"fault.parameters",
+				"parameter names may not be empty",
+			)
+		}
+	}
+	return nil
+}
+
+func validateSchedule(schedule *Schedule) error {
+	if schedule == nil {
+		return nil
+	}
+	if schedule.StartDelay != "" {
+		if _, err := parseDuration(schedule.StartDelay); err != nil {
+			return service.NewValidationError("schedule.startDelay", err.Error())
+		}
+	}
+	if schedule.Repetitions < 0 || schedule.Repetitions > maxRepetitions {
+		return service.NewValidationError(
+			"schedule.repetitions",
+			fmt.Sprintf(
+				"invalid repetitions: %d; it must be between 1 and %d",
+				schedule.Repetitions,
+				maxRepetitions,
+			),
+		)
+	}
+	if schedule.Interval != "" {
+		if schedule.Repetitions <= 1 {
+			return service.NewValidationError(
+				"schedule.interval",
+				"an interval can only be specified if the fault is repeated",
+			)
+		}
+		if _, err := parseDuration(schedule.Interval); err != nil {
+			return service.NewValidationError("schedule.interval", err.Error())
+		}
+	}
+	return nil
+}
+
+func (s *serviceManager) ApplyProvisioningParametersDefaults(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*chaosstudio.ProvisioningParameters",
+		)
+	}
+	if pp.Schedule == nil {
+		pp.Schedule = &Schedule{}
+	}
+	pp.Schedule.Repetitions = getRepetitions(pp)
+	return nil
+}
+
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep("validateTargets", s.validateTargets),
+		service.NewProvisioningStep("deployARMTemplate", s.deployARMTemplate),
+	)
+}
+
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*chaosStudioInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *chaosStudioInstanceDetails",
+		)
+	}
+	dt.ARMDeploymentName = uuid.NewV4().String()
+	dt.ExperimentName = "chaos-" + uuid.NewV4().String()
+	return dt, nil
+}
+
+// validateTargets verifies that every target resource exists and has been
+// enabled for Chaos Studio, which can't be determined until provisioning
+func (s *serviceManager) validateTargets(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*chaosstudio.ProvisioningParameters",
+		)
+	}
+	for _, id := range pp.TargetResourceIDs {
+		enabled, err := s.chaosStudioManager.IsTargetEnabled(id, pp.TargetType)
+		if err != nil {
+			return nil, err
+		}
+		if !enabled {
+			return nil, fmt.Errorf(
+				`target resource "%s" either does not exist or has not been `+
+					`enabled as a Chaos Studio target of type "%s"`,
+				id,
+				pp.TargetType,
+			)
+		}
+	}
+	return instance.Details, nil
+}
+
+func (s *serviceManager) deployARMTemplate(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*chaosStudioInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *chaosStudioInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*chaosstudio.ProvisioningParameters",
+		)
+	}
+	outputs, err := s.armDeployer.Deploy(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateBytes,
+		nil, // Go template params
+		map[string]interface{}{ // ARM template params
+			"experimentName": dt.ExperimentName,
+			"selectors":      buildSelectors(pp),
+			"steps":          buildSteps(pp),
+		},
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+	experimentID, ok := outputs["experimentId"].(string)
+	if !ok {
+		return nil, errors.New(
+			"error retrieving experiment resource id from deployment",
+		)
+	}
+	dt.ExperimentID = experimentID
+	principalID, ok := outputs["principalId"].(string)
+	if !ok {
+		return nil, errors.New(
+			"error retrieving experiment identity from deployment",
+		)
+	}
+	dt.PrincipalID = principalID
+	return dt, nil
+}
+
+// buildSelectors returns the experiment's sole selector, which selects every
+// target resource (in the specified zones, if any)
+func buildSelectors(pp *ProvisioningParameters) []map[string]interface{} {
+	targets := []map[string]interface{}{}
+	for _, id := range pp.TargetResourceIDs {
+		targets = append(
+			targets,
+			map[string]interface{}{
+				"type": "ChaosTarget",
+				"id": fmt.Sprintf(
+					"%s/providers/Microsoft.Chaos/targets/%s",
+					strings.TrimSuffix(id, "/"),
+					pp.TargetType,
+				),
+			},
+		)
+	}
+	selector := map[string]interface{}{
+		"type":    "List",
+		"id":      selectorID,
+		"targets": targets,
+	}
+	if len(pp.Zones) > 0 {
+		selector["filter"] = map[string]interface{}{
+			"type": "Simple",
+			"parameters": map[string]interface{}{
+				"zones": pp.Zones,
+			},
+		}
+	}
+	return []map[string]interface{}{selector}
+}
+
+// buildSteps returns the experiment's steps, which Chaos Studio executes in
+// order. Each repetition of the fault is a step of its own, preceded by a
+// delay if the schedule calls for one.
+func buildSteps(pp *ProvisioningParameters) []map[string]interface{} {
+	fault := map[string]interface{}{
+		"name":       pp.Fault.URN,
+		"selectorId": selectorID,
+		"parameters": buildFaultParameters(pp.Fault),
+	}
+	if pp.Fault.Duration != "" {
+		fault["type"] = "continuous"
+		fault["duration"] = pp.Fault.Duration
+	} else {
+		fault["type"] = "discrete"
+	}
+	schedule := pp.Schedule
+	if schedule == nil {
+		schedule = &Schedule{}
+	}
+	steps := []map[string]interface{}{}
+	for i := 0; i < getRepetitions(pp); i++ {
+		delay := schedule.Interval
+		if i == 0 {
+			delay = schedule.StartDelay
+		}
+		actions := []map[string]interface{}{}
+		if delay != "" {
+			actions = append(
+				actions,
+				map[string]interface{}{
+					"type":     "delay",
+					"name":     "urn:csci:microsoft:chaosStudio:timedDelay/1.0",
+					"duration": delay,
+				},
+			)
+		}
+		actions = append(actions, fault)
+		steps = append(
+			steps,
+			map[string]interface{}{
+				"name": fmt.Sprintf("Step %d", i+1),
+				"branches": []map[string]interface{}{
+					{
+						"name":    "Branch 1",
+						"actions": actions,
+					},
+				},
+			},
+		)
+	}
+	return steps
+}
+
+// buildFaultParameters returns the fault's parameters as the key/value pairs
+// Chaos Studio expects, ordered by key so that redeploying an unchanged
+// experiment changes nothing
+func buildFaultParameters(fault *Fault) []map[string]interface{} {
+	keys := make([]string, 0, len(fault.Parameters))
+	for key := range fault.Parameters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parameters := make([]map[string]interface{}, len(keys))
+	for i, key := range keys {
+		parameters[i] = map[string]interface{}{
+			"key":   key,
+			"value": fault.Parameters[key],
+		}
+	}
+	return parameters
+}
+
+func getRepetitions(pp *ProvisioningParameters) int {
+	if pp.Schedule == nil || pp.Schedule.Repetitions == 0 {
+		return 1
+	}
+	return pp.Schedule.Repetitions
+}
+
+func isValidZone(zone string) bool {
+	for _, z := range zones {
+		if zone == z {
+			return true
+		}
+	}
+	return false
+}
+
+// parseDuration parses an ISO 8601 duration of the form Chaos Studio accepts.
+// Durations of zero are not accepted.
+func parseDuration(durationStr string) (time.Duration, error) {
+	matches := durationRegex.FindStringSubmatch(durationStr)
+	if matches == nil || durationStr == "PT" {
+		return 0, fmt.Errorf(
+			`invalid duration: "%s"; it must be an ISO 8601 duration, e.g. PT10M`,
+			durationStr,
+		)
+	}
+	var duration time.Duration
+	for i, unit := range []time.Duration{time.Hour, time.Minute, time.Second} {
+		if matches[i+1] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(matches[i+1])
+		if err != nil {
+			return 0, fmt.Errorf(`invalid duration: "%s"`, durationStr)
+		}
+		duration += time.Duration(n) * unit
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf(
+			`invalid duration: "%s"; it must be greater than zero`,
+			durationStr,
+		)
+	}
+	return duration, nil
+}
@@ -0,0 +1,165 @@
+package chaosstudio
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testTargetResourceID = "/subscriptions/00000000-0000-0000-0000-" +
+	"000000000000/resourceGroups/test/providers/Microsoft.Compute/" +
+	"virtualMachines/test"
+
+func getTestProvisioningParameters() *ProvisioningParameters {
+	return &ProvisioningParameters{
+		TargetType:        "Microsoft-VirtualMachine",
+		TargetResourceIDs: []string{testTargetResourceID},
+		Fault: &Fault{
+			URN:      "urn:csci:microsoft:virtualMachine:shutdown/1.0",
+			Duration: "PT10M",
+			Parameters: map[string]string{
+				"abruptShutdown": "true",
+			},
+		},
+	}
+}
+
+func TestValidateProvisioningParameters(t *testing.T) {
+	m := &module{}
+	err := m.serviceManager.ValidateProvisioningParameters(
+		getTestProvisioningParameters(),
+	)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidTargets(t *testing.T) {
+	m := &module{}
+	pp := getTestProvisioningParameters()
+	pp.TargetType = "VirtualMachine"
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp = getTestProvisioningParameters()
+	pp.TargetResourceIDs = nil
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.TargetResourceIDs = []string{"/subscriptions/foo/resourceGroups/bar"}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.TargetResourceIDs = []string{testTargetResourceID, testTargetResourceID}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp = getTestProvisioningParameters()
+	pp.Zones = []string{"4"}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidFault(t *testing.T) {
+	m := &module{}
+	pp := getTestProvisioningParameters()
+	pp.Fault = nil
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp = getTestProvisioningParameters()
+	pp.Fault.URN = "shutdown"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp = getTestProvisioningParameters()
+	pp.Fault.Duration = "10m"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.Fault.Duration = "PT13H"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp = getTestProvisioningParameters()
+	pp.Fault.Parameters[""] = "foo"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	// Discrete faults have no duration
+	pp = getTestProvisioningParameters()
+	pp.Fault.Duration = ""
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidSchedule(t *testing.T) {
+	m := &module{}
+	pp := getTestProvisioningParameters()
+	pp.Schedule = &Schedule{StartDelay: "PT0S"}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.Schedule = &Schedule{Repetitions: maxRepetitions + 1}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.Schedule = &Schedule{Interval: "PT5M"}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.Schedule = &Schedule{
+		StartDelay:  "PT1H30M",
+		Repetitions: 3,
+		Interval:    "PT5M",
+	}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestApplyProvisioningParametersDefaults(t *testing.T) {
+	m := &module{}
+	pp := getTestProvisioningParameters()
+	err := m.serviceManager.ApplyProvisioningParametersDefaults(pp)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, pp.Schedule.Repetitions)
+}
+
+func TestParseDuration(t *testing.T) {
+	duration, err := parseDuration("PT1H30M15S")
+	assert.Nil(t, err)
+	assert.Equal(t, "1h30m15s", duration.String())
+	for _, durationStr := range []string{"", "PT", "P1D", "PT0M", "PT1.5H"} {
+		_, err = parseDuration(durationStr)
+		assert.NotNil(t, err, durationStr)
+	}
+}
+
+func TestBuildSteps(t *testing.T) {
+	pp := getTestProvisioningParameters()
+	pp.Schedule = &Schedule{
+		StartDelay:  "PT1M",
+		Repetitions: 2,
+		Interval:    "PT5M",
+	}
+	steps := buildSteps(pp)
+	assert.Len(t, steps, 2)
+	for i, delay := range []string{"PT1M", "PT5M"} {
+		branches := steps[i]["branches"].([]map[string]interface{})
+		actions := branches[0]["actions"].([]map[string]interface{})
+		assert.Len(t, actions, 2)
+		assert.Equal(t, "delay", actions[0]["type"])
+		assert.Equal(t, delay, actions[0]["duration"])
+		assert.Equal(t, "continuous", actions[1]["type"])
+		assert.Equal(t, selectorID, actions[1]["selectorId"])
+	}
+	pp.Schedule = nil
+	pp.Fault.Duration = ""
+	steps = buildSteps(pp)
+	assert.Len(t, steps, 1)
+	branches := steps[0]["branches"].([]map[string]interface{})
+	actions := branches[0]["actions"].([]map[string]interface{})
+	assert.Len(t, actions, 1)
+	assert.Equal(t, "discrete", actions[0]["type"])
+}
+
+func TestBuildSelectors(t *testing.T) {
+	pp := getTestProvisioningParameters()
+	pp.Zones = []string{"1"}
+	selectors := buildSelectors(pp)
+	assert.Len(t, selectors, 1)
+	targets := selectors[0]["targets"].([]map[string]interface{})
+	assert.Equal(
+		t,
+		testTargetResourceID+
+			"/providers/Microsoft.Chaos/targets/Microsoft-VirtualMachine",
+		targets[0]["id"],
+	)
+	assert.Contains(t, selectors[0], "filter")
+}
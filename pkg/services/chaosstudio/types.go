@@ -0,0 +1,98 @@
+package chaosstudio
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// ProvisioningParameters encapsulates Azure Chaos Studio-specific
+// provisioning options
+type ProvisioningParameters struct {
+	// TargetType is the kind of Chaos Studio target (e.g.
+	// Microsoft-VirtualMachine) the fault is injected into. Every target
+	// resource must already have been enabled as a target of this type.
+	TargetType        string   `json:"targetType"`
+	TargetResourceIDs []string `json:"targetResourceIds"`
+	// Zones, if specified, narrow the targets to those in the given
+	// availability zones
+	Zones    []string  `json:"zones"`
+	Fault    *Fault    `json:"fault"`
+	Schedule *Schedule `json:"schedule"`
+}
+
+// Fault encapsulates the fault an experiment injects into its targets
+type Fault struct {
+	// URN identifies the fault, e.g.
+	// urn:csci:microsoft:virtualMachine:shutdown/1.0
+	URN string `json:"urn"`
+	// Duration, an ISO 8601 duration, is how long a continuous fault lasts.
+	// Discrete faults, which happen once, have none.
+	Duration   string            `json:"duration"`
+	Parameters map[string]string `json:"parameters"`
+}
+
+// Schedule encapsulates when, and how often, an experiment injects its fault
+// once it has been started
+type Schedule struct {
+	// StartDelay, an ISO 8601 duration, is how long the experiment waits after
+	// being started before injecting the fault for the first time
+	StartDelay string `json:"startDelay"`
+	// Repetitions is the number of times the fault is injected
+	Repetitions int `json:"repetitions"`
+	// Interval, an ISO 8601 duration, is how long the experiment waits between
+	// repetitions
+	Interval string `json:"interval"`
+}
+
+type chaosStudioInstanceDetails struct {
+	ARMDeploymentName string `json:"armDeployment"`
+	ExperimentName    string `json:"experimentName"`
+	ExperimentID      string `json:"experimentId"`
+	// PrincipalID is the experiment's system-assigned identity, which must be
+	// granted whatever permissions the fault requires on the targets
+	PrincipalID string `json:"principalId"`
+}
+
+// UpdatingParameters encapsulates Azure Chaos Studio-specific updating options
+type UpdatingParameters struct {
+}
+
+// BindingParameters encapsulates Azure Chaos Studio-specific binding options
+type BindingParameters struct {
+}
+
+type chaosStudioBindingDetails struct {
+}
+
+// Credentials encapsulates the details needed to run a chaos experiment and
+// follow its progress
+type Credentials struct {
+	ExperimentID string `json:"experimentId"`
+	PrincipalID  string `json:"principalId"`
+	StartURL     string `json:"startUrl"`
+	CancelURL    string `json:"cancelUrl"`
+	StatusURL    string `json:"statusUrl"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &chaosStudioInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &chaosStudioBindingDetails{}
+}
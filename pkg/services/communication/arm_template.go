@@ -0,0 +1,57 @@
+package communication
+
+// nolint: lll
+var armTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string",
+      "metadata": {
+        "description": "Not used. Communication Services resources are always deployed to the global location; see dataLocation."
+      }
+    },
+    "communicationServiceName": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of the Communication Services resource"
+      }
+    },
+    "dataLocation": {
+      "type": "string",
+      "metadata": {
+        "description": "The geography in which the resource's data is stored at rest"
+      }
+    },
+    "tags": {
+      "type": "object"
+    }
+  },
+  "variables": {
+    "apiVersion": "2020-08-20"
+  },
+  "resources": [
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[parameters('communicationServiceName')]",
+      "type": "Microsoft.Communication/communicationServices",
+      "location": "global",
+      "tags": "[parameters('tags')]",
+      "properties": {
+        "dataLocation": "[parameters('dataLocation')]"
+      }
+    }
+  ],
+  "outputs": {
+    "hostName": {
+      "type": "string",
+      "value": "[reference(parameters('communicationServiceName')).hostName]"
+    },
+    "connectionString": {
+      "type": "string",
+      "value": "[listKeys(resourceId('Microsoft.Communication/communicationServices', parameters('communicationServiceName')), variables('apiVersion')).primaryConnectionString]"
+    }
+  }
+}
+`)
@@ -0,0 +1,64 @@
+package communication
+
+import (
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) ValidateBindingParameters(
+	bindingParameters service.BindingParameters,
+) error {
+	// There are no parameters for binding to Communication Services, so there
+	// is nothing to validate
+	return nil
+}
+
+// Bind hands out the resource's secondary key, which every binding shares. The
+// primary key backs the connection string stored in the instance details and
+// is reserved for the broker's own use.
+func (s *serviceManager) Bind(
+	instance service.Instance,
+	_ service.BindingParameters,
+) (service.BindingDetails, error) {
+	dt, ok := instance.Details.(*communicationInstanceDetails)
+	if !ok {
+		return nil, fmt.Errorf(
+			"error casting instance.Details as *communicationInstanceDetails",
+		)
+	}
+	keys, err := s.communicationManager.GetKeys(
+		dt.ServiceName,
+		instance.ResourceGroup,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &communicationBindingDetails{
+		AccessKey:        keys.SecondaryKey,
+		ConnectionString: keys.SecondaryConnectionString,
+	}, nil
+}
+
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	binding service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*communicationInstanceDetails)
+	if !ok {
+		return nil, fmt.Errorf(
+			"error casting instance.Details as *communicationInstanceDetails",
+		)
+	}
+	bd, ok := binding.Details.(*communicationBindingDetails)
+	if !ok {
+		return nil, fmt.Errorf(
+			"error casting binding.Details as *communicationBindingDetails",
+		)
+	}
+	return &communicationCredentials{
+		Endpoint:         dt.Endpoint,
+		AccessKey:        bd.AccessKey,
+		ConnectionString: bd.ConnectionString,
+	}, nil
+}
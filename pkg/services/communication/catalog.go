@@ -0,0 +1,25 @@
+package communication
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:          "e7c2a5b1-8d3f-4c62-9a7e-3f1b6d4c8e21",
+				Name:        "azure-communication",
+				Description: "Azure Communication Services (Experimental)",
+				Bindable:    true,
+				Cloneable:   true,
+				Tags:        []string{"Azure", "Communication", "SMS", "Chat"},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:          "5a9f3c17-2b4e-4d8a-b6c1-7e0d2f9a4b53",
+				Name:        "standard",
+				Description: "Pay-as-you-go",
+				Free:        false,
+			}),
+		),
+	}), nil
+}
@@ -0,0 +1,42 @@
+package communication
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/communication"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer          arm.Deployer
+	communicationManager communication.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of provisioning Azure Communication Services
+func New(
+	armDeployer arm.Deployer,
+	communicationManager communication.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer:          armDeployer,
+			communicationManager: communicationManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "communication"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{"Microsoft.Communication"}
+}
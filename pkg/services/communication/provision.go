@@ -0,0 +1,135 @@
+package communication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+// dataLocations are the geographies Azure Communication Services can store
+// data in at rest
+var dataLocations = map[string]bool{
+	"Africa":        true,
+	"Asia Pacific":  true,
+	"Australia":     true,
+	"Brazil":        true,
+	"Canada":        true,
+	"Europe":        true,
+	"France":        true,
+	"Germany":       true,
+	"India":         true,
+	"Japan":         true,
+	"Korea":         true,
+	"Norway":        true,
+	"Switzerland":   true,
+	"UAE":           true,
+	"UK":            true,
+	"United States": true,
+}
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*communication.ProvisioningParameters",
+		)
+	}
+	if pp.DataLocation == "" {
+		return service.NewValidationError(
+			"dataLocation",
+			"dataLocation is required",
+		)
+	}
+	if !dataLocations[pp.DataLocation] {
+		return service.NewValidationError(
+			"dataLocation",
+			fmt.Sprintf(`invalid dataLocation: "%s"`, pp.DataLocation),
+		)
+	}
+	return nil
+}
+
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep("deployARMTemplate", s.deployARMTemplate),
+	)
+}
+
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*communicationInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *communicationInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*communication.ProvisioningParameters",
+		)
+	}
+	dt.ARMDeploymentName = uuid.NewV4().String()
+	dt.ServiceName = "acs-" + uuid.NewV4().String()
+	dt.DataLocation = pp.DataLocation
+	return dt, nil
+}
+
+func (s *serviceManager) deployARMTemplate(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*communicationInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *communicationInstanceDetails",
+		)
+	}
+	outputs, err := s.armDeployer.Deploy(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateBytes,
+		nil, // Go template params
+		map[string]interface{}{ // ARM template params
+			"communicationServiceName": dt.ServiceName,
+			"dataLocation":             dt.DataLocation,
+		},
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+
+	hostName, ok := outputs["hostName"].(string)
+	if !ok {
+		return nil, fmt.Errorf(
+			"error retrieving host name from deployment: %s",
+			err,
+		)
+	}
+	dt.Endpoint = fmt.Sprintf("https://%s/", hostName)
+
+	connectionString, ok := outputs["connectionString"].(string)
+	if !ok {
+		return nil, fmt.Errorf(
+			"error retrieving connection string from deployment: %s",
+			err,
+		)
+	}
+	dt.ConnectionString = connectionString
+
+	return dt, nil
+}
@@ -0,0 +1,26 @@
+package communication
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateProvisioningParametersWithNoDataLocation(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidDataLocation(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		DataLocation: "eastus",
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.DataLocation = "United States"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,64 @@
+package communication
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// ProvisioningParameters encapsulates Azure Communication Services-specific
+// provisioning options
+type ProvisioningParameters struct {
+	DataLocation string `json:"dataLocation"`
+}
+
+type communicationInstanceDetails struct {
+	ARMDeploymentName string `json:"armDeployment"`
+	ServiceName       string `json:"serviceName"`
+	DataLocation      string `json:"dataLocation"`
+	Endpoint          string `json:"endpoint"`
+	ConnectionString  string `json:"connectionString" secret:"true"`
+}
+
+// UpdatingParameters encapsulates Azure Communication Services-specific
+// updating options
+type UpdatingParameters struct {
+}
+
+// BindingParameters encapsulates Azure Communication Services-specific
+// binding options
+type BindingParameters struct {
+}
+
+type communicationBindingDetails struct {
+	AccessKey        string `json:"accessKey" secret:"true"`
+	ConnectionString string `json:"connectionString" secret:"true"`
+}
+
+type communicationCredentials struct {
+	Endpoint         string `json:"endpoint"`
+	AccessKey        string `json:"accessKey"`
+	ConnectionString string `json:"connectionString"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &communicationInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &communicationBindingDetails{}
+}
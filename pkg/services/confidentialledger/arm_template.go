@@ -0,0 +1,63 @@
+package confidentialledger
+
+// nolint: lll
+var armTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "ledgerName": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of the confidential ledger"
+      }
+    },
+    "ledgerType": {
+      "type": "string",
+      "allowedValues": [
+        "Public",
+        "Private"
+      ]
+    },
+    "aadBasedSecurityPrincipals": {
+      "type": "array"
+    },
+    "certBasedSecurityPrincipals": {
+      "type": "array"
+    },
+    "tags": {
+      "type": "object"
+    }
+  },
+  "variables": {
+    "apiVersion": "2022-05-13"
+  },
+  "resources": [
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[parameters('ledgerName')]",
+      "type": "Microsoft.ConfidentialLedger/ledgers",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "properties": {
+        "ledgerType": "[parameters('ledgerType')]",
+        "aadBasedSecurityPrincipals": "[parameters('aadBasedSecurityPrincipals')]",
+        "certBasedSecurityPrincipals": "[parameters('certBasedSecurityPrincipals')]"
+      }
+    }
+  ],
+  "outputs": {
+    "ledgerUri": {
+      "type": "string",
+      "value": "[reference(parameters('ledgerName')).ledgerUri]"
+    },
+    "identityServiceUri": {
+      "type": "string",
+      "value": "[reference(parameters('ledgerName')).identityServiceUri]"
+    }
+  }
+}
+`)
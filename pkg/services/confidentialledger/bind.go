@@ -0,0 +1,138 @@
+package confidentialledger
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/confidentialledger"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// The roles a principal may be granted in a ledger. Readers may only read
+// entries; contributors may also write them; administrators may also manage
+// the ledger's users.
+const (
+	roleReader        = "Reader"
+	roleContributor   = "Contributor"
+	roleAdministrator = "Administrator"
+)
+
+var roles = []string{roleReader, roleContributor, roleAdministrator}
+
+func (s *serviceManager) ValidateBindingParameters(
+	bindingParameters service.BindingParameters,
+) error {
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return errors.New(
+			"error casting bindingParameters as " +
+				"*confidentialledger.BindingParameters",
+		)
+	}
+	if !isValidObjectID(bp.PrincipalID) {
+		return service.NewValidationError(
+			"principalId",
+			fmt.Sprintf(`invalid principalId: "%s"`, bp.PrincipalID),
+		)
+	}
+	if bp.TenantID != "" && !isValidObjectID(bp.TenantID) {
+		return service.NewValidationError(
+			"tenantId",
+			fmt.Sprintf(`invalid tenantId: "%s"`, bp.TenantID),
+		)
+	}
+	if bp.Role != "" && !isValidRole(bp.Role) {
+		return service.NewValidationError(
+			"role",
+			fmt.Sprintf(
+				`invalid role: "%s"; allowed values are: %s`,
+				bp.Role,
+				strings.Join(roles, ", "),
+			),
+		)
+	}
+	return nil
+}
+
+// Bind grants the requested role in the ledger to the principal named in the
+// binding parameters. The principal authenticates with its own credentials, so
+// the broker issues none. Since a principal may have only one role in a
+// ledger, binding fails if the principal is already an administrator or is
+// bound to the ledger already.
+func (s *serviceManager) Bind(
+	instance service.Instance,
+	bindingParameters service.BindingParameters,
+) (service.BindingDetails, error) {
+	dt, ok := instance.Details.(*ledgerInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *ledgerInstanceDetails",
+		)
+	}
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting bindingParameters as " +
+				"*confidentialledger.BindingParameters",
+		)
+	}
+	bd := &ledgerBindingDetails{
+		PrincipalID: bp.PrincipalID,
+		TenantID:    bp.TenantID,
+		Role:        bp.Role,
+	}
+	if bd.TenantID == "" {
+		bd.TenantID = s.ledgerManager.GetTenantID()
+	}
+	if bd.Role == "" {
+		bd.Role = roleContributor
+	}
+	if err := s.ledgerManager.AddSecurityPrincipal(
+		dt.LedgerName,
+		instance.ResourceGroup,
+		confidentialledger.SecurityPrincipal{
+			PrincipalID:    bd.PrincipalID,
+			TenantID:       bd.TenantID,
+			LedgerRoleName: bd.Role,
+		},
+	); err != nil {
+		return nil, err
+	}
+	return bd, nil
+}
+
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	binding service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*ledgerInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *ledgerInstanceDetails",
+		)
+	}
+	bd, ok := binding.Details.(*ledgerBindingDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting binding.Details as *ledgerBindingDetails",
+		)
+	}
+	return &Credentials{
+		LedgerName:         dt.LedgerName,
+		LedgerURI:          dt.LedgerURI,
+		IdentityServiceURI: dt.IdentityServiceURI,
+		TenantID:           bd.TenantID,
+		PrincipalID:        bd.PrincipalID,
+		Role:               bd.Role,
+	}, nil
+}
+
+func isValidRole(role string) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package confidentialledger
+
+import (
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/stretchr/testify/assert"
+)
+
+const testIdentityServiceURI = "https://identity.confidential-ledger.core." +
+	"azure.com/ledgerIdentity/cl1"
+
+func TestValidateBindingParameters(t *testing.T) {
+	m := &module{}
+	bp := &BindingParameters{}
+	err := m.serviceManager.ValidateBindingParameters(bp)
+	assert.NotNil(t, err)
+	bp.PrincipalID = testPrincipalID
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.Nil(t, err)
+	bp.TenantID = "contoso.onmicrosoft.com"
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.NotNil(t, err)
+	bp.TenantID = testTenantID
+	bp.Role = "Owner"
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.NotNil(t, err)
+	bp.Role = roleReader
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.Nil(t, err)
+}
+
+func TestGetCredentials(t *testing.T) {
+	m := &module{}
+	creds, err := m.serviceManager.GetCredentials(
+		service.Instance{
+			Details: &ledgerInstanceDetails{
+				LedgerName:         "cl1",
+				LedgerURI:          "https://cl1.confidential-ledger.azure.com",
+				IdentityServiceURI: testIdentityServiceURI,
+			},
+		},
+		service.Binding{
+			Details: &ledgerBindingDetails{
+				PrincipalID: testPrincipalID,
+				TenantID:    testTenantID,
+				Role:        roleContributor,
+			},
+		},
+	)
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		&Credentials{
+			LedgerName:         "cl1",
+			LedgerURI:          "https://cl1.confidential-ledger.azure.com",
+			IdentityServiceURI: testIdentityServiceURI,
+			TenantID:           testTenantID,
+			PrincipalID:        testPrincipalID,
+			Role:               roleContributor,
+		},
+		creds,
+	)
+}
@@ -0,0 +1,44 @@
+package confidentialledger
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:          "0b8f6c1e-5d27-4a93-9e1f-3c6a8d2b7e45",
+				Name:        "azure-confidential-ledger",
+				Description: "Azure Confidential Ledger (Experimental)",
+				Bindable:    true,
+				Tags:        []string{"Azure", "Confidential Ledger", "Audit"},
+				// Confidential ledgers run in hardware-backed secure enclaves,
+				// which only some regions offer
+				Locations: []string{
+					"australiaeast",
+					"canadacentral",
+					"canadaeast",
+					"eastus",
+					"eastus2",
+					"japaneast",
+					"northeurope",
+					"southcentralus",
+					"southeastasia",
+					"switzerlandnorth",
+					"uksouth",
+					"westcentralus",
+					"westeurope",
+					"westus",
+					"westus3",
+				},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:   "d4a9e3f2-7b16-4c58-a0e2-9f3d5b8c1a67",
+				Name: "standard",
+				Description: "A blockchain-backed, append-only ledger running in " +
+					"secure enclaves",
+				Free: false,
+			}),
+		),
+	}), nil
+}
@@ -0,0 +1,42 @@
+package confidentialledger
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/confidentialledger"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer   arm.Deployer
+	ledgerManager confidentialledger.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of provisioning Azure Confidential Ledgers
+func New(
+	armDeployer arm.Deployer,
+	ledgerManager confidentialledger.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer:   armDeployer,
+			ledgerManager: ledgerManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "confidentialledger"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{"Microsoft.ConfidentialLedger"}
+}
@@ -0,0 +1,102 @@
+package confidentialledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) GetDeprovisioner(
+	service.Plan,
+) (service.Deprovisioner, error) {
+	return service.NewDeprovisioner(
+		service.NewDeprovisioningStep(
+			"deleteDeploymentStack",
+			s.deleteDeploymentStack,
+		),
+		service.NewDeprovisioningStep("deleteARMDeployment", s.deleteARMDeployment),
+		service.NewDeprovisioningStep("deleteLedger", s.deleteLedger),
+	)
+}
+
+// deleteDeploymentStack deletes the deployment stack, if any, through which
+// the ledger was provisioned. Deleting the stack deletes the ledger with it,
+// which its deny settings would otherwise prevent.
+func (s *serviceManager) deleteDeploymentStack(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*ledgerInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *ledgerInstanceDetails",
+		)
+	}
+	if !usesDeploymentStack(instance) {
+		return dt, nil
+	}
+	if err := s.armDeployer.DeleteStack(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, fmt.Errorf("error deleting deployment stack: %s", err)
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deleteARMDeployment(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*ledgerInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *ledgerInstanceDetails",
+		)
+	}
+	// A ledger provisioned through a stack has no deployment of its own
+	if usesDeploymentStack(instance) {
+		return dt, nil
+	}
+	if err := s.armDeployer.Delete(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, fmt.Errorf("error deleting ARM deployment: %s", err)
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deleteLedger(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*ledgerInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *ledgerInstanceDetails",
+		)
+	}
+	// A ledger provisioned through a stack was deleted along with it
+	if usesDeploymentStack(instance) {
+		return dt, nil
+	}
+	if err := s.ledgerManager.DeleteLedger(
+		dt.LedgerName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+// usesDeploymentStack returns a bool indicating whether the given instance was
+// provisioned through a deployment stack. This is determined from the
+// instance's provisioning parameters rather than its recorded stack ID, which
+// is absent if provisioning failed partway through deploying the stack.
+func usesDeploymentStack(instance service.Instance) bool {
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	return ok && pp.DeploymentStack != nil
+}
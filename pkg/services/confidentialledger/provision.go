@@ -0,0 +1,279 @@
+package confidentialledger
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	ledgerTypePublic  = "Public"
+	ledgerTypePrivate = "Private"
+)
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*confidentialledger.ProvisioningParameters",
+		)
+	}
+	if pp.LedgerType != "" &&
+		pp.LedgerType != ledgerTypePublic &&
+		pp.LedgerType != ledgerTypePrivate {
+		return service.NewValidationError(
+			"ledgerType",
+			fmt.Sprintf(
+				`invalid ledgerType: "%s"; allowed values are: %s, %s`,
+				pp.LedgerType,
+				ledgerTypePublic,
+				ledgerTypePrivate,
+			),
+		)
+	}
+	// A ledger without administrators can never have its users changed
+	if len(pp.Administrators) == 0 {
+		return service.NewValidationError(
+			"administrators",
+			"at least one administrator must be specified",
+		)
+	}
+	principalIDs := map[string]bool{}
+	for i, administrator := range pp.Administrators {
+		field := fmt.Sprintf("administrators[%d]", i)
+		if err := validateAdministrator(field, administrator); err != nil {
+			return err
+		}
+		if administrator.PrincipalID == "" {
+			continue
+		}
+		principalID := strings.ToLower(administrator.PrincipalID)
+		if principalIDs[principalID] {
+			return service.NewValidationError(
+				field+".principalId",
+				fmt.Sprintf(
+					`duplicate administrator principalId: "%s"`,
+					administrator.PrincipalID,
+				),
+			)
+		}
+		principalIDs[principalID] = true
+	}
+	if pp.DeploymentStack != nil {
+		return arm.ValidateStackSettings("deploymentStack", *pp.DeploymentStack)
+	}
+	return nil
+}
+
+func validateAdministrator(field string, administrator Administrator) error {
+	if (administrator.PrincipalID == "") == (administrator.Certificate == "") {
+		return service.NewValidationError(
+			field,
+			"exactly one of principalId and certificate must be specified",
+		)
+	}
+	if administrator.Certificate != "" {
+		if administrator.TenantID != "" {
+			return service.NewValidationError(
+				field+".tenantId",
+				"a tenantId may only be specified along with a principalId",
+			)
+		}
+		if err := validateCertificate(administrator.Certificate); err != nil {
+			return service.NewValidationError(
+				field+".certificate",
+				fmt.Sprintf("invalid certificate: %s", err),
+			)
+		}
+		return nil
+	}
+	if !isValidObjectID(administrator.PrincipalID) {
+		return service.NewValidationError(
+			field+".principalId",
+			fmt.Sprintf(`invalid principalId: "%s"`, administrator.PrincipalID),
+		)
+	}
+	if administrator.TenantID != "" && !isValidObjectID(administrator.TenantID) {
+		return service.NewValidationError(
+			field+".tenantId",
+			fmt.Sprintf(`invalid tenantId: "%s"`, administrator.TenantID),
+		)
+	}
+	return nil
+}
+
+// validateCertificate returns an error unless the given string is a single
+// PEM-encoded X.509 certificate
+func validateCertificate(certificate string) error {
+	block, rest := pem.Decode([]byte(certificate))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return errors.New("not a PEM-encoded certificate")
+	}
+	if len(strings.TrimSpace(string(rest))) > 0 {
+		return errors.New("only one certificate may be specified")
+	}
+	if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *serviceManager) ApplyProvisioningParametersDefaults(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*confidentialledger.ProvisioningParameters",
+		)
+	}
+	if pp.LedgerType == "" {
+		pp.LedgerType = ledgerTypePublic
+	}
+	return nil
+}
+
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep("deployARMTemplate", s.deployARMTemplate),
+	)
+}
+
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*ledgerInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *ledgerInstanceDetails",
+		)
+	}
+	dt.ARMDeploymentName = uuid.NewV4().String()
+	// Ledger names become part of their endpoints' host names, and may be no
+	// longer than 24 characters
+	dt.LedgerName = "cl" +
+		strings.Replace(uuid.NewV4().String(), "-", "", -1)[:22]
+	return dt, nil
+}
+
+func (s *serviceManager) deployARMTemplate(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*ledgerInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *ledgerInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*confidentialledger.ProvisioningParameters",
+		)
+	}
+	aadPrincipals, certPrincipals := getARMSecurityPrincipals(
+		pp.Administrators,
+		s.ledgerManager.GetTenantID(),
+	)
+	armParams := map[string]interface{}{
+		"ledgerName":                  dt.LedgerName,
+		"ledgerType":                  pp.LedgerType,
+		"aadBasedSecurityPrincipals":  aadPrincipals,
+		"certBasedSecurityPrincipals": certPrincipals,
+	}
+	var outputs map[string]interface{}
+	var err error
+	if pp.DeploymentStack != nil {
+		// The stack is named for the deployment it stands in for
+		dt.DeploymentStackID, outputs, err = s.armDeployer.DeployStack(
+			dt.ARMDeploymentName,
+			instance.ResourceGroup,
+			instance.Location,
+			armTemplateBytes,
+			nil, // Go template params
+			armParams,
+			instance.Tags,
+			*pp.DeploymentStack,
+		)
+	} else {
+		outputs, err = s.armDeployer.Deploy(
+			dt.ARMDeploymentName,
+			instance.ResourceGroup,
+			instance.Location,
+			armTemplateBytes,
+			nil, // Go template params
+			armParams,
+			instance.Tags,
+		)
+	}
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+	ledgerURI, ok := outputs["ledgerUri"].(string)
+	if !ok {
+		return nil, errors.New("error retrieving ledger URI from deployment")
+	}
+	dt.LedgerURI = ledgerURI
+	identityServiceURI, ok := outputs["identityServiceUri"].(string)
+	if !ok {
+		return nil, errors.New(
+			"error retrieving identity service URI from deployment",
+		)
+	}
+	dt.IdentityServiceURI = identityServiceURI
+	return dt, nil
+}
+
+// getARMSecurityPrincipals returns the given administrators as the ARM
+// template expects them: those identified by principal ID, and those by
+// certificate. Principals whose tenant isn't given belong to the broker's.
+func getARMSecurityPrincipals(
+	administrators []Administrator,
+	defaultTenantID string,
+) ([]map[string]interface{}, []map[string]interface{}) {
+	aadPrincipals := []map[string]interface{}{}
+	certPrincipals := []map[string]interface{}{}
+	for _, administrator := range administrators {
+		if administrator.Certificate != "" {
+			certPrincipals = append(certPrincipals, map[string]interface{}{
+				"cert":           administrator.Certificate,
+				"ledgerRoleName": roleAdministrator,
+			})
+			continue
+		}
+		tenantID := administrator.TenantID
+		if tenantID == "" {
+			tenantID = defaultTenantID
+		}
+		aadPrincipals = append(aadPrincipals, map[string]interface{}{
+			"principalId":    administrator.PrincipalID,
+			"tenantId":       tenantID,
+			"ledgerRoleName": roleAdministrator,
+		})
+	}
+	return aadPrincipals, certPrincipals
+}
+
+// isValidObjectID returns a bool indicating whether the given string is an
+// object ID, i.e. a UUID in its canonical form
+func isValidObjectID(objectID string) bool {
+	id, err := uuid.FromString(objectID)
+	return err == nil && strings.EqualFold(id.String(), objectID)
+}
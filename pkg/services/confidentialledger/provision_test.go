@@ -0,0 +1,182 @@
+package confidentialledger
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testPrincipalID = "6a3b3c7e-0b54-4d8e-9d6c-3c2f0f6f0d11"
+	testTenantID    = "72f988bf-86f1-41af-91ab-2d7cd011db47"
+)
+
+func getTestCertificate(t *testing.T) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "ledger-administrator"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(
+		rand.Reader,
+		template,
+		template,
+		&key.PublicKey,
+		key,
+	)
+	assert.Nil(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestValidateProvisioningParameters(t *testing.T) {
+	m := &module{}
+	err := m.serviceManager.ValidateProvisioningParameters(
+		&ProvisioningParameters{
+			LedgerType: ledgerTypePrivate,
+			Administrators: []Administrator{
+				{PrincipalID: testPrincipalID},
+				{Certificate: getTestCertificate(t)},
+			},
+		},
+	)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidLedgerType(t *testing.T) {
+	m := &module{}
+	err := m.serviceManager.ValidateProvisioningParameters(
+		&ProvisioningParameters{
+			LedgerType: "Permissioned",
+			Administrators: []Administrator{
+				{PrincipalID: testPrincipalID},
+			},
+		},
+	)
+	assert.NotNil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidAdministrators(t *testing.T) {
+	m := &module{}
+	certificate := getTestCertificate(t)
+	for name, administrators := range map[string][]Administrator{
+		"none":    {},
+		"neither": {{TenantID: testTenantID}},
+		"both": {
+			{PrincipalID: testPrincipalID, Certificate: certificate},
+		},
+		"bad principal": {{PrincipalID: "alice@example.com"}},
+		"bad tenant":    {{PrincipalID: testPrincipalID, TenantID: "contoso"}},
+		"bad cert":      {{Certificate: "-----BEGIN CERTIFICATE-----"}},
+		"two certs":     {{Certificate: certificate + certificate}},
+		"cert tenant":   {{Certificate: certificate, TenantID: testTenantID}},
+		"duplicate": {
+			{PrincipalID: testPrincipalID},
+			{PrincipalID: testPrincipalID},
+		},
+	} {
+		err := m.serviceManager.ValidateProvisioningParameters(
+			&ProvisioningParameters{
+				Administrators: administrators,
+			},
+		)
+		assert.NotNil(t, err, name)
+	}
+}
+
+func TestValidateProvisioningParametersWithDeploymentStack(t *testing.T) {
+	m := &module{}
+	err := m.serviceManager.ValidateProvisioningParameters(
+		&ProvisioningParameters{
+			Administrators: []Administrator{
+				{PrincipalID: testPrincipalID},
+			},
+			DeploymentStack: &arm.StackSettings{
+				DenySettingsMode:   arm.DenySettingsModeDenyWriteAndDelete,
+				ExcludedPrincipals: []string{testPrincipalID},
+				ExcludedActions: []string{
+					"Microsoft.ConfidentialLedger/ledgers/write",
+				},
+				ActionOnUnmanage: arm.ActionOnUnmanageDetach,
+			},
+		},
+	)
+	assert.Nil(t, err)
+	for name, settings := range map[string]arm.StackSettings{
+		"bad mode":      {DenySettingsMode: "denyAll"},
+		"bad principal": {ExcludedPrincipals: []string{"alice@example.com"}},
+		"bad action":    {ExcludedActions: []string{"write"}},
+		"bad unmanage":  {ActionOnUnmanage: "ignore"},
+		"too many principals": {
+			ExcludedPrincipals: []string{
+				testPrincipalID,
+				testPrincipalID,
+				testPrincipalID,
+				testPrincipalID,
+				testPrincipalID,
+				testPrincipalID,
+			},
+		},
+		"exclusions without denial": {
+			DenySettingsMode:   arm.DenySettingsModeNone,
+			ExcludedPrincipals: []string{testPrincipalID},
+		},
+	} {
+		err := m.serviceManager.ValidateProvisioningParameters(
+			&ProvisioningParameters{
+				Administrators: []Administrator{
+					{PrincipalID: testPrincipalID},
+				},
+				DeploymentStack: &settings,
+			},
+		)
+		validationErr, ok := err.(*service.ValidationError)
+		if assert.True(t, ok, name) {
+			assert.Contains(t, validationErr.Field, "deploymentStack.", name)
+		}
+	}
+}
+
+func TestGetARMSecurityPrincipals(t *testing.T) {
+	certificate := getTestCertificate(t)
+	aadPrincipals, certPrincipals := getARMSecurityPrincipals(
+		[]Administrator{
+			{PrincipalID: testPrincipalID},
+			{Certificate: certificate},
+		},
+		testTenantID,
+	)
+	assert.Equal(
+		t,
+		[]map[string]interface{}{
+			{
+				"principalId":    testPrincipalID,
+				"tenantId":       testTenantID,
+				"ledgerRoleName": roleAdministrator,
+			},
+		},
+		aadPrincipals,
+	)
+	assert.Equal(
+		t,
+		[]map[string]interface{}{
+			{
+				"cert":           certificate,
+				"ledgerRoleName": roleAdministrator,
+			},
+		},
+		certPrincipals,
+	)
+}
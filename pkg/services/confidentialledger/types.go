@@ -0,0 +1,103 @@
+package confidentialledger
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// ProvisioningParameters encapsulates Azure Confidential Ledger-specific
+// provisioning options
+type ProvisioningParameters struct {
+	// LedgerType determines whether the ledger's entries may be read by anyone
+	// with a role in the ledger (Public) or are encrypted (Private)
+	LedgerType string `json:"ledgerType"`
+	// Administrators are granted the Administrator role in the ledger, which
+	// entitles them to manage its users
+	Administrators []Administrator `json:"administrators"`
+	// DeploymentStack, if specified, causes the ledger to be provisioned through
+	// an Azure Deployment Stack, which protects it from deletion other than by
+	// deprovisioning
+	DeploymentStack *arm.StackSettings `json:"deploymentStack"`
+}
+
+// Administrator identifies an administrator of a ledger, either by the object
+// ID of a Microsoft Entra ID principal or by the certificate with which the
+// administrator authenticates
+type Administrator struct {
+	PrincipalID string `json:"principalId"`
+	// TenantID is the tenant the principal belongs to. It defaults to the
+	// broker's own.
+	TenantID string `json:"tenantId"`
+	// Certificate is a PEM-encoded X.509 certificate
+	Certificate string `json:"certificate"`
+}
+
+type ledgerInstanceDetails struct {
+	ARMDeploymentName string `json:"armDeployment"`
+	LedgerName        string `json:"ledgerName"`
+	// LedgerURI is the endpoint to which ledger entries are written and from
+	// which they are read
+	LedgerURI string `json:"ledgerUri"`
+	// IdentityServiceURI is the endpoint from which the ledger's network
+	// certificate, used to verify its endpoint's TLS certificate, is retrieved
+	IdentityServiceURI string `json:"identityServiceUri"`
+	// DeploymentStackID is the ID of the deployment stack, if any, that manages
+	// the ledger
+	DeploymentStackID string `json:"deploymentStackId,omitempty"`
+}
+
+// UpdatingParameters encapsulates Azure Confidential Ledger-specific updating
+// options
+type UpdatingParameters struct {
+}
+
+// BindingParameters encapsulates Azure Confidential Ledger-specific binding
+// options
+type BindingParameters struct {
+	PrincipalID string `json:"principalId"`
+	TenantID    string `json:"tenantId"`
+	Role        string `json:"role"`
+}
+
+type ledgerBindingDetails struct {
+	PrincipalID string `json:"principalId"`
+	TenantID    string `json:"tenantId"`
+	Role        string `json:"role"`
+}
+
+// Credentials encapsulates Azure Confidential Ledger-specific connection
+// details
+type Credentials struct {
+	LedgerName         string `json:"ledgerName"`
+	LedgerURI          string `json:"ledgerUri"`
+	IdentityServiceURI string `json:"identityServiceUri"`
+	TenantID           string `json:"tenantId"`
+	PrincipalID        string `json:"principalId"`
+	Role               string `json:"role"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &ledgerInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &ledgerBindingDetails{}
+}
@@ -0,0 +1,30 @@
+package confidentialledger
+
+import (
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) Unbind(
+	instance service.Instance,
+	bindingDetails service.BindingDetails,
+) error {
+	dt, ok := instance.Details.(*ledgerInstanceDetails)
+	if !ok {
+		return errors.New(
+			"error casting instance.Details as *ledgerInstanceDetails",
+		)
+	}
+	bd, ok := bindingDetails.(*ledgerBindingDetails)
+	if !ok {
+		return errors.New(
+			"error casting bindingDetails as *ledgerBindingDetails",
+		)
+	}
+	return s.ledgerManager.RemoveSecurityPrincipal(
+		dt.LedgerName,
+		instance.ResourceGroup,
+		bd.PrincipalID,
+	)
+}
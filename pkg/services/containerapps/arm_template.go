@@ -0,0 +1,124 @@
+package containerapps
+
+// nolint: lll
+var armTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "environmentName": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of the Container Apps environment"
+      }
+    },
+    "appName": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of the container app"
+      }
+    },
+    "image": {
+      "type": "string"
+    },
+    "cpuCores": {
+      "type": "string"
+    },
+    "memory": {
+      "type": "string"
+    },
+    "minReplicas": {
+      "type": "int"
+    },
+    "maxReplicas": {
+      "type": "int"
+    },
+    "scaleRules": {
+      "type": "array"
+    },
+    "ingress": {
+      "type": "object"
+    },
+    "env": {
+      "type": "array"
+    },
+    "secrets": {
+      "type": "secureObject"
+    },
+    "tags": {
+      "type": "object"
+    }
+  },
+  "variables": {
+    "apiVersion": "2023-05-01",
+    "environmentId": "[resourceId('Microsoft.App/managedEnvironments', parameters('environmentName'))]"
+  },
+  "resources": [
+    {{- if .createEnvironment }}
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[parameters('environmentName')]",
+      "type": "Microsoft.App/managedEnvironments",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "properties": {}
+    },
+    {{- end }}
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[parameters('appName')]",
+      "type": "Microsoft.App/containerApps",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      {{- if .createEnvironment }}
+      "dependsOn": [
+        "[variables('environmentId')]"
+      ],
+      {{- end }}
+      "properties": {
+        "managedEnvironmentId": "[variables('environmentId')]",
+        "configuration": {
+          "activeRevisionsMode": "Single",
+          {{- if .ingress }}
+          "ingress": "[parameters('ingress')]",
+          {{- end }}
+          "secrets": "[parameters('secrets').items]"
+        },
+        "template": {
+          "containers": [
+            {
+              "name": "[parameters('appName')]",
+              "image": "[parameters('image')]",
+              "resources": {
+                "cpu": "[json(parameters('cpuCores'))]",
+                "memory": "[parameters('memory')]"
+              },
+              "env": "[parameters('env')]"
+            }
+          ],
+          "scale": {
+            "minReplicas": "[parameters('minReplicas')]",
+            "maxReplicas": "[parameters('maxReplicas')]",
+            "rules": "[parameters('scaleRules')]"
+          }
+        }
+      }
+    }
+  ],
+  "outputs": {
+    "environmentId": {
+      "type": "string",
+      "value": "[variables('environmentId')]"
+    }
+    {{- if .ingress }},
+    "fqdn": {
+      "type": "string",
+      "value": "[reference(parameters('appName')).configuration.ingress.fqdn]"
+    }
+    {{- end }}
+  }
+}
+`)
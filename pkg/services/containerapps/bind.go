@@ -0,0 +1,50 @@
+package containerapps
+
+import (
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) ValidateBindingParameters(
+	service.BindingParameters,
+) error {
+	// There are no parameters for binding to Container Apps, so there is
+	// nothing to validate
+	return nil
+}
+
+func (s *serviceManager) Bind(
+	service.Instance,
+	service.BindingParameters,
+) (service.BindingDetails, error) {
+	return &containerAppsBindingDetails{}, nil
+}
+
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	_ service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*containerAppsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *containerAppsInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*containerapps.ProvisioningParameters",
+		)
+	}
+	creds := &Credentials{
+		Secrets: pp.Secrets,
+	}
+	// An app without ingress can't be reached, so it has no endpoint
+	if dt.FQDN != "" {
+		creds.FQDN = dt.FQDN
+		creds.URL = "https://" + dt.FQDN
+	}
+	return creds, nil
+}
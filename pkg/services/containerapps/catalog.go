@@ -0,0 +1,54 @@
+package containerapps
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:          "c70c9e9d-cf59-40d3-b93e-a24d9a909fd8",
+				Name:        "azure-container-app",
+				Description: "Azure Container Apps (Experimental)",
+				Bindable:    true,
+				Tags:        []string{"Azure", "Container Apps", "Containers"},
+				// Azure Container Apps is only offered in some regions
+				Locations: []string{
+					"australiaeast",
+					"brazilsouth",
+					"canadacentral",
+					"centralindia",
+					"centralus",
+					"eastasia",
+					"eastus",
+					"eastus2",
+					"francecentral",
+					"germanywestcentral",
+					"japaneast",
+					"koreacentral",
+					"northcentralus",
+					"northeurope",
+					"norwayeast",
+					"southafricanorth",
+					"southcentralus",
+					"southeastasia",
+					"swedencentral",
+					"switzerlandnorth",
+					"uaenorth",
+					"uksouth",
+					"westcentralus",
+					"westeurope",
+					"westus",
+					"westus2",
+					"westus3",
+				},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:          "43e8ba7d-8a8e-4813-8c43-bc408058733b",
+				Name:        "consumption",
+				Description: "Serverless, billed per second of vCPU and memory used",
+				Free:        false,
+			}),
+		),
+	}), nil
+}
@@ -0,0 +1,42 @@
+package containerapps
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/containerapps"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer          arm.Deployer
+	containerAppsManager containerapps.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of provisioning Azure Container Apps
+func New(
+	armDeployer arm.Deployer,
+	containerAppsManager containerapps.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer:          armDeployer,
+			containerAppsManager: containerAppsManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "containerapps"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{"Microsoft.App"}
+}
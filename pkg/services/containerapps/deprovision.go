@@ -0,0 +1,117 @@
+package containerapps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) GetDeprovisioner(
+	service.Plan,
+) (service.Deprovisioner, error) {
+	return service.NewDeprovisioner(
+		service.NewDeprovisioningStep("deleteARMDeployment", s.deleteARMDeployment),
+		service.NewDeprovisioningStep("deleteApp", s.deleteApp),
+		service.NewDeprovisioningStepWithDependencies(
+			"deleteEnvironment",
+			s.deleteEnvironment,
+			"deleteApp",
+		),
+	)
+}
+
+func (s *serviceManager) deleteARMDeployment(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*containerAppsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *containerAppsInstanceDetails",
+		)
+	}
+	if err := s.armDeployer.Delete(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, fmt.Errorf("error deleting ARM deployment: %s", err)
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deleteApp(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*containerAppsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *containerAppsInstanceDetails",
+		)
+	}
+	if err := s.containerAppsManager.DeleteApp(
+		dt.AppName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+// deleteEnvironment deletes the app's environment once no apps run in it
+// anymore, unless the instance asked for it to be kept. Only environments the
+// broker created are deleted. Apps are counted afresh each time, so an
+// environment shared by several instances is deleted along with whichever
+// instance's app was the last to run in it.
+func (s *serviceManager) deleteEnvironment(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*containerAppsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *containerAppsInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*containerapps.ProvisioningParameters",
+		)
+	}
+	if pp.KeepEnvironment || dt.EnvironmentName == "" {
+		return dt, nil
+	}
+	environment, ok, err := s.containerAppsManager.GetEnvironment(
+		dt.EnvironmentName,
+		instance.ResourceGroup,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !ok || environment.Tags[arm.HeritageTagName] != arm.HeritageTagValue {
+		return dt, nil
+	}
+	appIDs, err := s.containerAppsManager.GetEnvironmentAppIDs(environment.ID)
+	if err != nil {
+		return nil, err
+	}
+	// The app this instance just deleted may briefly still be listed
+	for _, appID := range appIDs {
+		if !strings.HasSuffix(strings.ToLower(appID), "/"+dt.AppName) {
+			return dt, nil
+		}
+	}
+	if err := s.containerAppsManager.DeleteEnvironment(
+		dt.EnvironmentName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
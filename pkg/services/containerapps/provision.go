@@ -0,0 +1,595 @@
+package containerapps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	scaleRuleTypeHTTP   = "http"
+	scaleRuleTypeCustom = "custom"
+
+	transportAuto  = "auto"
+	transportHTTP  = "http"
+	transportHTTP2 = "http2"
+
+	defaultCPUCores    = 0.5
+	minCPUCores        = 0.25
+	maxCPUCores        = 2.0
+	defaultMaxReplicas = 10
+	// maxReplicas is the most replicas Azure permits a single app on the
+	// consumption plan to scale out to
+	maxReplicas = 300
+)
+
+// environmentNameRegex matches the names Azure permits of environments and
+// container apps alike
+var environmentNameRegex = regexp.MustCompile(`^[a-z][a-z0-9-]{0,30}[a-z0-9]$`)
+
+var secretNameRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9.-]*[a-z0-9])?$`)
+
+var scaleRuleNameRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9.-]*[a-z0-9])?$`)
+
+var environmentVariableNameRegex = regexp.MustCompile(
+	`^[A-Za-z_][A-Za-z0-9_]*$`,
+)
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*containerapps.ProvisioningParameters",
+		)
+	}
+	if pp.EnvironmentName != "" &&
+		!environmentNameRegex.MatchString(pp.EnvironmentName) {
+		return service.NewValidationError(
+			"environment",
+			fmt.Sprintf(
+				`invalid environment: "%s"; names must be 2 to 32 lowercase `+
+					`letters, digits, and hyphens, beginning with a letter and not `+
+					`ending with a hyphen`,
+				pp.EnvironmentName,
+			),
+		)
+	}
+	if pp.Image == "" {
+		return service.NewValidationError(
+			"image",
+			fmt.Sprintf(`invalid image: "%s"`, pp.Image),
+		)
+	}
+	if pp.CPUCores != 0 {
+		if pp.CPUCores < minCPUCores || pp.CPUCores > maxCPUCores ||
+			math.Mod(pp.CPUCores, minCPUCores) != 0 {
+			return service.NewValidationError(
+				"cpuCores",
+				fmt.Sprintf(
+					"invalid cpuCores: %g; it must be a multiple of %g between %g "+
+						"and %g",
+					pp.CPUCores,
+					minCPUCores,
+					minCPUCores,
+					maxCPUCores,
+				),
+			)
+		}
+	}
+	for name, value := range pp.Secrets {
+		if !secretNameRegex.MatchString(name) {
+			return service.NewValidationError(
+				"secrets",
+				fmt.Sprintf(
+					`invalid secret name: "%s"; names must be lowercase letters, `+
+						`digits, hyphens, and periods, beginning and ending with a `+
+						`letter or digit`,
+					name,
+				),
+			)
+		}
+		if value == "" {
+			return service.NewValidationError(
+				"secrets",
+				fmt.Sprintf(`secret "%s" has no value`, name),
+			)
+		}
+	}
+	if err := validateEnvironmentVariables(pp); err != nil {
+		return err
+	}
+	if err := validateIngress(pp.Ingress); err != nil {
+		return err
+	}
+	return validateScale(pp)
+}
+
+func validateEnvironmentVariables(pp *ProvisioningParameters) error {
+	names := map[string]bool{}
+	for _, ev := range pp.EnvironmentVariables {
+		if !environmentVariableNameRegex.MatchString(ev.Name) {
+			return service.NewValidationError(
+				"environmentVariables",
+				fmt.Sprintf(`invalid environment variable name: "%s"`, ev.Name),
+			)
+		}
+		if names[ev.Name] {
+			return service.NewValidationError(
+				"environmentVariables",
+				fmt.Sprintf(`duplicate environment variable: "%s"`, ev.Name),
+			)
+		}
+		names[ev.Name] = true
+		if ev.SecretRef == "" {
+			continue
+		}
+		if ev.Value != "" {
+			return service.NewValidationError(
+				"environmentVariables",
+				fmt.Sprintf(
+					`environment variable "%s" may specify a value or a secretRef, `+
+						`but not both`,
+					ev.Name,
+				),
+			)
+		}
+		if _, ok := pp.Secrets[ev.SecretRef]; !ok {
+			return service.NewValidationError(
+				"environmentVariables",
+				fmt.Sprintf(
+					`environment variable "%s" refers to unknown secret "%s"`,
+					ev.Name,
+					ev.SecretRef,
+				),
+			)
+		}
+	}
+	return nil
+}
+
+func validateIngress(ingress *Ingress) error {
+	if ingress == nil {
+		return nil
+	}
+	if ingress.TargetPort < 1 || ingress.TargetPort > 65535 {
+		return service.NewValidationError(
+			"ingress.targetPort",
+			fmt.Sprintf(
+				"invalid targetPort: %d; it must be between 1 and 65535",
+				ingress.TargetPort,
+			),
+		)
+	}
+	switch ingress.Transport {
+	case "", transportAuto, transportHTTP, transportHTTP2:
+	default:
+		return service.NewValidationError(
+			"ingress.transport",
+			fmt.Sprintf(
+				`invalid transport: "%s"; allowed values are: %s, %s, and %s`,
+				ingress.Transport,
+				transportAuto,
+				transportHTTP,
+				transportHTTP2,
+			),
+		)
+	}
+	return nil
+}
+
+func validateScale(pp *ProvisioningParameters) error {
+	if pp.Scale == nil {
+		return nil
+	}
+	max := getMaxReplicas(pp.Scale)
+	if max < 1 || max > maxReplicas {
+		return service.NewValidationError(
+			"scale.maxReplicas",
+			fmt.Sprintf(
+				"invalid maxReplicas: %d; it must be between 1 and %d",
+				max,
+				maxReplicas,
+			),
+		)
+	}
+	if min := pp.Scale.MinReplicas; min != nil && (*min < 0 || *min > max) {
+		return service.NewValidationError(
+			"scale.minReplicas",
+			fmt.Sprintf(
+				"invalid minReplicas: %d; it must be between 0 and maxReplicas (%d)",
+				*min,
+				max,
+			),
+		)
+	}
+	names := map[string]bool{}
+	for _, rule := range pp.Scale.Rules {
+		if !scaleRuleNameRegex.MatchString(rule.Name) {
+			return service.NewValidationError(
+				"scale.rules",
+				fmt.Sprintf(`invalid scale rule name: "%s"`, rule.Name),
+			)
+		}
+		if names[rule.Name] {
+			return service.NewValidationError(
+				"scale.rules",
+				fmt.Sprintf(`duplicate scale rule: "%s"`, rule.Name),
+			)
+		}
+		names[rule.Name] = true
+		if err := validateScaleRule(pp, rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateScaleRule(pp *ProvisioningParameters, rule ScaleRule) error {
+	switch rule.Type {
+	case scaleRuleTypeHTTP:
+		if pp.Ingress == nil {
+			return service.NewValidationError(
+				"scale.rules",
+				fmt.Sprintf(
+					`scale rule "%s" scales by HTTP requests, which requires ingress`,
+					rule.Name,
+				),
+			)
+		}
+		if rule.CustomType != "" {
+			return service.NewValidationError(
+				"scale.rules",
+				fmt.Sprintf(
+					`scale rule "%s" may only specify a customType if its type is %s`,
+					rule.Name,
+					scaleRuleTypeCustom,
+				),
+			)
+		}
+		if concurrentRequests, ok :=
+			rule.Metadata["concurrentRequests"]; ok {
+			if n, err := strconv.Atoi(concurrentRequests); err != nil || n < 1 {
+				return service.NewValidationError(
+					"scale.rules",
+					fmt.Sprintf(
+						`scale rule "%s" has invalid concurrentRequests: "%s"`,
+						rule.Name,
+						concurrentRequests,
+					),
+				)
+			}
+		}
+	case scaleRuleTypeCustom:
+		if rule.CustomType == "" {
+			return service.NewValidationError(
+				"scale.rules",
+				fmt.Sprintf(
+					`scale rule "%s" must specify the customType of its scaler, e.g. `+
+						`azure-servicebus`,
+					rule.Name,
+				),
+			)
+		}
+	default:
+		return service.NewValidationError(
+			"scale.rules",
+			fmt.Sprintf(
+				`scale rule "%s" has invalid type: "%s"; allowed values are: %s `+
+					`and %s`,
+				rule.Name,
+				rule.Type,
+				scaleRuleTypeHTTP,
+				scaleRuleTypeCustom,
+			),
+		)
+	}
+	for key := range rule.Metadata {
+		if key == "" {
+			return service.NewValidationError(
+				"scale.rules",
+				fmt.Sprintf(
+					`scale rule "%s" has metadata with an empty name`,
+					rule.Name,
+				),
+			)
+		}
+	}
+	for _, auth := range rule.Auth {
+		if _, ok := pp.Secrets[auth.SecretRef]; !ok {
+			return service.NewValidationError(
+				"scale.rules",
+				fmt.Sprintf(
+					`scale rule "%s" refers to unknown secret "%s"`,
+					rule.Name,
+					auth.SecretRef,
+				),
+			)
+		}
+		if auth.TriggerParameter == "" {
+			return service.NewValidationError(
+				"scale.rules",
+				fmt.Sprintf(
+					`scale rule "%s" must specify the triggerParameter each secret `+
+						`is passed as`,
+					rule.Name,
+				),
+			)
+		}
+	}
+	return nil
+}
+
+func (s *serviceManager) ApplyProvisioningParametersDefaults(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*containerapps.ProvisioningParameters",
+		)
+	}
+	if pp.CPUCores == 0 {
+		pp.CPUCores = defaultCPUCores
+	}
+	if pp.Scale == nil {
+		pp.Scale = &Scale{}
+	}
+	pp.Scale.MaxReplicas = getMaxReplicas(pp.Scale)
+	if pp.Scale.MinReplicas == nil {
+		minReplicas := 0
+		pp.Scale.MinReplicas = &minReplicas
+	}
+	if pp.Ingress != nil && pp.Ingress.Transport == "" {
+		pp.Ingress.Transport = transportAuto
+	}
+	return nil
+}
+
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep("deployARMTemplate", s.deployARMTemplate),
+		service.NewProvisioningStep(
+			"waitForReadyRevision",
+			s.waitForReadyRevision,
+		),
+	)
+}
+
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*containerAppsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *containerAppsInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*containerapps.ProvisioningParameters",
+		)
+	}
+	dt.ARMDeploymentName = uuid.NewV4().String()
+	// Environment and app names are limited to 32 characters
+	dt.AppName = "ca" + strings.Replace(uuid.NewV4().String(), "-", "", -1)[:20]
+	dt.EnvironmentName = pp.EnvironmentName
+	if dt.EnvironmentName == "" {
+		dt.EnvironmentName = "cae" + strings.Replace(
+			uuid.NewV4().String(),
+			"-",
+			"",
+			-1,
+		)[:20]
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deployARMTemplate(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*containerAppsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *containerAppsInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*containerapps.ProvisioningParameters",
+		)
+	}
+	// An environment that already exists is left as it is; redeploying it
+	// could alter it underneath the apps already running in it
+	_, environmentExists, err := s.containerAppsManager.GetEnvironment(
+		dt.EnvironmentName,
+		instance.ResourceGroup,
+	)
+	if err != nil {
+		return nil, err
+	}
+	ingress := map[string]interface{}{}
+	if pp.Ingress != nil {
+		ingress = map[string]interface{}{
+			"external":      pp.Ingress.External,
+			"targetPort":    pp.Ingress.TargetPort,
+			"transport":     pp.Ingress.Transport,
+			"allowInsecure": pp.Ingress.AllowInsecure,
+		}
+	}
+	// Apps on the consumption plan are allotted 2 GiB of memory per core
+	memory := strconv.FormatFloat(2*pp.CPUCores, 'f', -1, 64) + "Gi"
+	outputs, err := s.armDeployer.Deploy(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateBytes,
+		map[string]interface{}{ // Go template params
+			"createEnvironment": !environmentExists,
+			"ingress":           pp.Ingress != nil,
+		},
+		map[string]interface{}{ // ARM template params
+			"environmentName": dt.EnvironmentName,
+			"appName":         dt.AppName,
+			"image":           pp.Image,
+			"cpuCores":        strconv.FormatFloat(pp.CPUCores, 'f', -1, 64),
+			"memory":          memory,
+			"minReplicas":     *pp.Scale.MinReplicas,
+			"maxReplicas":     pp.Scale.MaxReplicas,
+			"scaleRules":      buildScaleRules(pp.Scale),
+			"ingress":         ingress,
+			"env":             buildEnvironmentVariables(pp),
+			"secrets": map[string]interface{}{
+				"items": buildSecrets(pp),
+			},
+		},
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+	environmentID, ok := outputs["environmentId"].(string)
+	if !ok {
+		return nil, errors.New(
+			"error retrieving environment resource id from deployment",
+		)
+	}
+	dt.EnvironmentID = environmentID
+	if pp.Ingress != nil {
+		fqdn, ok := outputs["fqdn"].(string)
+		if !ok {
+			return nil, errors.New("error retrieving fqdn from deployment")
+		}
+		dt.FQDN = fqdn
+	}
+	return dt, nil
+}
+
+// waitForReadyRevision waits for the app's first revision to become ready.
+// Deployment completes once the revision has been created, which is before
+// its replicas have started.
+func (s *serviceManager) waitForReadyRevision(
+	ctx context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*containerAppsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *containerAppsInstanceDetails",
+		)
+	}
+	if err := s.containerAppsManager.WaitForReadyRevision(
+		ctx,
+		dt.AppName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+func buildScaleRules(scale *Scale) []map[string]interface{} {
+	rules := []map[string]interface{}{}
+	for _, rule := range scale.Rules {
+		auth := []map[string]interface{}{}
+		for _, a := range rule.Auth {
+			auth = append(
+				auth,
+				map[string]interface{}{
+					"secretRef":        a.SecretRef,
+					"triggerParameter": a.TriggerParameter,
+				},
+			)
+		}
+		metadata := rule.Metadata
+		if metadata == nil {
+			metadata = map[string]string{}
+		}
+		scaler := map[string]interface{}{
+			"metadata": metadata,
+			"auth":     auth,
+		}
+		if rule.Type == scaleRuleTypeCustom {
+			scaler["type"] = rule.CustomType
+		}
+		rules = append(
+			rules,
+			map[string]interface{}{
+				"name":    rule.Name,
+				rule.Type: scaler,
+			},
+		)
+	}
+	return rules
+}
+
+func buildEnvironmentVariables(
+	pp *ProvisioningParameters,
+) []map[string]interface{} {
+	env := []map[string]interface{}{}
+	for _, ev := range pp.EnvironmentVariables {
+		if ev.SecretRef != "" {
+			env = append(
+				env,
+				map[string]interface{}{
+					"name":      ev.Name,
+					"secretRef": ev.SecretRef,
+				},
+			)
+			continue
+		}
+		env = append(
+			env,
+			map[string]interface{}{
+				"name":  ev.Name,
+				"value": ev.Value,
+			},
+		)
+	}
+	return env
+}
+
+// buildSecrets returns the app's secrets as the name/value pairs Azure
+// expects, sorted by name
+func buildSecrets(pp *ProvisioningParameters) []map[string]interface{} {
+	names := make([]string, 0, len(pp.Secrets))
+	for name := range pp.Secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	secrets := make([]map[string]interface{}, len(names))
+	for i, name := range names {
+		secrets[i] = map[string]interface{}{
+			"name":  name,
+			"value": pp.Secrets[name],
+		}
+	}
+	return secrets
+}
+
+func getMaxReplicas(scale *Scale) int {
+	if scale == nil || scale.MaxReplicas == 0 {
+		return defaultMaxReplicas
+	}
+	return scale.MaxReplicas
+}
@@ -0,0 +1,189 @@
+package containerapps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getTestProvisioningParameters() *ProvisioningParameters {
+	minReplicas := 1
+	return &ProvisioningParameters{
+		Image: "mcr.microsoft.com/azuredocs/containerapps-helloworld:latest",
+		Scale: &Scale{
+			MinReplicas: &minReplicas,
+			MaxReplicas: 5,
+			Rules: []ScaleRule{
+				{
+					Name: "http-rule",
+					Type: scaleRuleTypeHTTP,
+					Metadata: map[string]string{
+						"concurrentRequests": "50",
+					},
+				},
+				{
+					Name:       "queue-rule",
+					Type:       scaleRuleTypeCustom,
+					CustomType: "azure-servicebus",
+					Metadata: map[string]string{
+						"queueName": "orders",
+					},
+					Auth: []ScaleRuleAuth{
+						{
+							SecretRef:        "servicebus-connection",
+							TriggerParameter: "connection",
+						},
+					},
+				},
+			},
+		},
+		Ingress: &Ingress{
+			External:   true,
+			TargetPort: 80,
+		},
+		EnvironmentVariables: []EnvironmentVariable{
+			{
+				Name:  "LOG_LEVEL",
+				Value: "debug",
+			},
+			{
+				Name:      "SERVICEBUS_CONNECTION",
+				SecretRef: "servicebus-connection",
+			},
+		},
+		Secrets: map[string]string{
+			"servicebus-connection": "Endpoint=sb://example",
+		},
+	}
+}
+
+func TestValidateProvisioningParameters(t *testing.T) {
+	m := &module{}
+	err := m.serviceManager.ValidateProvisioningParameters(
+		getTestProvisioningParameters(),
+	)
+	assert.Nil(t, err)
+	err = m.serviceManager.ValidateProvisioningParameters(
+		&ProvisioningParameters{Image: "nginx"},
+	)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidApp(t *testing.T) {
+	m := &module{}
+	pp := getTestProvisioningParameters()
+	pp.Image = ""
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp = getTestProvisioningParameters()
+	pp.EnvironmentName = "Shared_Environment"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp = getTestProvisioningParameters()
+	pp.CPUCores = 0.3
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.CPUCores = 2.25
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.CPUCores = 1.75
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidSecrets(t *testing.T) {
+	m := &module{}
+	pp := getTestProvisioningParameters()
+	pp.Secrets["Invalid_Name"] = "value"
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp = getTestProvisioningParameters()
+	pp.Secrets["empty"] = ""
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp = getTestProvisioningParameters()
+	pp.EnvironmentVariables[1].SecretRef = "unknown"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp = getTestProvisioningParameters()
+	pp.EnvironmentVariables[1].Value = "both"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp = getTestProvisioningParameters()
+	pp.EnvironmentVariables[1].Name = "LOG_LEVEL"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidIngress(t *testing.T) {
+	m := &module{}
+	pp := getTestProvisioningParameters()
+	pp.Ingress.TargetPort = 0
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp = getTestProvisioningParameters()
+	pp.Ingress.Transport = "tcp"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	// HTTP scale rules require ingress
+	pp = getTestProvisioningParameters()
+	pp.Ingress = nil
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidScale(t *testing.T) {
+	m := &module{}
+	pp := getTestProvisioningParameters()
+	pp.Scale.MaxReplicas = maxReplicas + 1
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp = getTestProvisioningParameters()
+	minReplicas := 6
+	pp.Scale.MinReplicas = &minReplicas
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp = getTestProvisioningParameters()
+	pp.Scale.Rules[1].Name = pp.Scale.Rules[0].Name
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp = getTestProvisioningParameters()
+	pp.Scale.Rules[0].Type = "cpu"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp = getTestProvisioningParameters()
+	pp.Scale.Rules[0].Metadata["concurrentRequests"] = "0"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp = getTestProvisioningParameters()
+	pp.Scale.Rules[1].CustomType = ""
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp = getTestProvisioningParameters()
+	pp.Scale.Rules[1].Auth[0].SecretRef = "unknown"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+}
+
+func TestApplyProvisioningParametersDefaults(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		Image:   "nginx",
+		Ingress: &Ingress{TargetPort: 80},
+	}
+	err := m.serviceManager.ApplyProvisioningParametersDefaults(pp)
+	assert.Nil(t, err)
+	assert.Equal(t, defaultCPUCores, pp.CPUCores)
+	assert.Equal(t, 0, *pp.Scale.MinReplicas)
+	assert.Equal(t, defaultMaxReplicas, pp.Scale.MaxReplicas)
+	assert.Equal(t, transportAuto, pp.Ingress.Transport)
+}
+
+func TestBuildScaleRules(t *testing.T) {
+	rules := buildScaleRules(getTestProvisioningParameters().Scale)
+	assert.Len(t, rules, 2)
+	assert.Contains(t, rules[0], scaleRuleTypeHTTP)
+	custom, ok := rules[1][scaleRuleTypeCustom].(map[string]interface{})
+	assert.True(t, ok)
+	assert.Equal(t, "azure-servicebus", custom["type"])
+}
@@ -0,0 +1,122 @@
+package containerapps
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// ProvisioningParameters encapsulates Azure Container Apps-specific
+// provisioning options
+type ProvisioningParameters struct {
+	// EnvironmentName, if specified, names an environment in the instance's
+	// resource group for the app to run in, which is created if it doesn't
+	// already exist. Otherwise, a new environment is created for the app alone.
+	EnvironmentName string `json:"environment" template:"true"`
+	// KeepEnvironment indicates whether the environment is retained once no
+	// apps run in it anymore. Environments the broker didn't create are always
+	// retained.
+	KeepEnvironment      bool                  `json:"keepEnvironment"`
+	Image                string                `json:"image"`
+	CPUCores             float64               `json:"cpuCores"`
+	Scale                *Scale                `json:"scale"`
+	Ingress              *Ingress              `json:"ingress"`
+	EnvironmentVariables []EnvironmentVariable `json:"environmentVariables"`
+	// Secrets are keyed by name and can be referred to by environment
+	// variables and scale rules
+	Secrets map[string]string `json:"secrets" secret:"true"`
+}
+
+// Scale encapsulates how many replicas of the app run, and which rules
+// determine that
+type Scale struct {
+	MinReplicas *int        `json:"minReplicas"`
+	MaxReplicas int         `json:"maxReplicas"`
+	Rules       []ScaleRule `json:"rules"`
+}
+
+// ScaleRule encapsulates a single rule for scaling the app. Rules of type
+// "http" scale by concurrent HTTP requests; rules of type "custom" scale using
+// the named KEDA scaler.
+type ScaleRule struct {
+	Name       string            `json:"name"`
+	Type       string            `json:"type"`
+	CustomType string            `json:"customType"`
+	Metadata   map[string]string `json:"metadata"`
+	Auth       []ScaleRuleAuth   `json:"auth"`
+}
+
+// ScaleRuleAuth passes the named secret to a scale rule as the value of one
+// of its scaler's trigger parameters
+type ScaleRuleAuth struct {
+	SecretRef        string `json:"secretRef"`
+	TriggerParameter string `json:"triggerParameter"`
+}
+
+// Ingress encapsulates how the app is reached
+type Ingress struct {
+	// External indicates whether the app is reachable from outside its
+	// environment
+	External      bool   `json:"external"`
+	TargetPort    int    `json:"targetPort"`
+	Transport     string `json:"transport"`
+	AllowInsecure bool   `json:"allowInsecure"`
+}
+
+// EnvironmentVariable encapsulates a single environment variable set in the
+// app's container. Its value is either given or taken from the named secret.
+type EnvironmentVariable struct {
+	Name      string `json:"name"`
+	Value     string `json:"value"`
+	SecretRef string `json:"secretRef"`
+}
+
+type containerAppsInstanceDetails struct {
+	ARMDeploymentName string `json:"armDeployment"`
+	EnvironmentName   string `json:"environmentName"`
+	EnvironmentID     string `json:"environmentId"`
+	AppName           string `json:"appName"`
+	FQDN              string `json:"fqdn"`
+}
+
+// UpdatingParameters encapsulates Azure Container Apps-specific updating
+// options
+type UpdatingParameters struct {
+}
+
+// BindingParameters encapsulates Azure Container Apps-specific binding
+// options
+type BindingParameters struct {
+}
+
+type containerAppsBindingDetails struct {
+}
+
+// Credentials encapsulates the details needed to reach a container app
+type Credentials struct {
+	FQDN    string            `json:"fqdn,omitempty"`
+	URL     string            `json:"url,omitempty"`
+	Secrets map[string]string `json:"secrets,omitempty"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &containerAppsInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &containerAppsBindingDetails{}
+}
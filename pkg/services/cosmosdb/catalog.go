@@ -13,7 +13,8 @@ func (m *module) GetCatalog() (service.Catalog, error) {
 					Description: "Azure DocumentDB (Experimental) provided by CosmosDB " +
 						"and accessible via SQL (DocumentDB), Gremlin (Graph), and Table " +
 						"(Key-Value) APIs",
-					Bindable: true,
+					Bindable:  true,
+					Cloneable: true,
 					Tags: []string{"Azure",
 						"CosmosDB",
 						"Database",
@@ -43,6 +44,7 @@ func (m *module) GetCatalog() (service.Catalog, error) {
 					Name:        "azure-cosmos-mongo-db",
 					Description: "MongoDB on Azure (Experimental) provided by CosmosDB",
 					Bindable:    true,
+					Cloneable:   true,
 					Tags: []string{"Azure",
 						"CosmosDB",
 						"Database",
@@ -17,8 +17,8 @@ type cosmosdbInstanceDetails struct {
 	DatabaseAccountName      string       `json:"name"`
 	DatabaseKind             databaseKind `json:"kind"`
 	FullyQualifiedDomainName string       `json:"fullyQualifiedDomainName"`
-	ConnectionString         string       `json:"connectionString"`
-	PrimaryKey               string       `json:"primaryKey"`
+	ConnectionString         string       `json:"connectionString" secret:"true"`
+	PrimaryKey               string       `json:"primaryKey" secret:"true"`
 }
 
 // UpdatingParameters encapsulates CosmosDB-specific updating options
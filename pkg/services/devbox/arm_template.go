@@ -0,0 +1,147 @@
+package devbox
+
+// nolint: lll
+var armTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "devCenterName": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of the dev center"
+      }
+    },
+    "devBoxDefinitionName": {
+      "type": "string"
+    },
+    "projectName": {
+      "type": "string"
+    },
+    "poolName": {
+      "type": "string"
+    },
+    "image": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of an image in the dev center's built-in gallery"
+      }
+    },
+    "sku": {
+      "type": "string"
+    },
+    "osStorageType": {
+      "type": "string"
+    },
+    "localAdministrator": {
+      "type": "string"
+    },
+    "maxDevBoxesPerUser": {
+      "type": "int"
+    },
+    "stopOnDisconnectGracePeriodMinutes": {
+      "type": "int"
+    },
+    "tags": {
+      "type": "object"
+    }
+  },
+  "variables": {
+    "apiVersion": "2024-02-01"
+  },
+  "resources": [
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[parameters('devCenterName')]",
+      "type": "Microsoft.DevCenter/devcenters",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "properties": {}
+    },
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[concat(parameters('devCenterName'), '/', parameters('devBoxDefinitionName'))]",
+      "type": "Microsoft.DevCenter/devcenters/devboxdefinitions",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "dependsOn": [
+        "[resourceId('Microsoft.DevCenter/devcenters', parameters('devCenterName'))]"
+      ],
+      "properties": {
+        "imageReference": {
+          "id": "[concat(resourceId('Microsoft.DevCenter/devcenters/galleries', parameters('devCenterName'), 'Default'), '/images/', parameters('image'))]"
+        },
+        "sku": {
+          "name": "[parameters('sku')]"
+        },
+        "osStorageType": "[parameters('osStorageType')]",
+        "hibernateSupport": "Disabled"
+      }
+    },
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[parameters('projectName')]",
+      "type": "Microsoft.DevCenter/projects",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "dependsOn": [
+        "[resourceId('Microsoft.DevCenter/devcenters', parameters('devCenterName'))]"
+      ],
+      "properties": {
+        {{- if .maxDevBoxesPerUser }}
+        "maxDevBoxesPerUser": "[parameters('maxDevBoxesPerUser')]",
+        {{- end }}
+        "devCenterId": "[resourceId('Microsoft.DevCenter/devcenters', parameters('devCenterName'))]"
+      }
+    },
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[concat(parameters('projectName'), '/', parameters('poolName'))]",
+      "type": "Microsoft.DevCenter/projects/pools",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "dependsOn": [
+        "[resourceId('Microsoft.DevCenter/projects', parameters('projectName'))]",
+        "[resourceId('Microsoft.DevCenter/devcenters/devboxdefinitions', parameters('devCenterName'), parameters('devBoxDefinitionName'))]"
+      ],
+      "properties": {
+        "devBoxDefinitionName": "[parameters('devBoxDefinitionName')]",
+        "licenseType": "Windows_Client",
+        "localAdministrator": "[parameters('localAdministrator')]",
+        {{- if .stopOnDisconnect }}
+        "stopOnDisconnect": {
+          "status": "Enabled",
+          "gracePeriodMinutes": "[parameters('stopOnDisconnectGracePeriodMinutes')]"
+        },
+        {{- end }}
+        "networkConnectionName": "managedNetwork",
+        "virtualNetworkType": "Managed",
+        "managedVirtualNetworkRegions": [
+          "[parameters('location')]"
+        ]
+      }
+    }
+  ],
+  "outputs": {
+    "devCenterId": {
+      "type": "string",
+      "value": "[resourceId('Microsoft.DevCenter/devcenters', parameters('devCenterName'))]"
+    },
+    "devCenterUri": {
+      "type": "string",
+      "value": "[reference(parameters('devCenterName')).devCenterUri]"
+    },
+    "projectId": {
+      "type": "string",
+      "value": "[resourceId('Microsoft.DevCenter/projects', parameters('projectName'))]"
+    },
+    "poolId": {
+      "type": "string",
+      "value": "[resourceId('Microsoft.DevCenter/projects/pools', parameters('projectName'), parameters('poolName'))]"
+    }
+  }
+}
+`)
@@ -0,0 +1,120 @@
+package devbox
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+// devPortalURL is where users manage and connect to their dev boxes
+const devPortalURL = "https://devportal.microsoft.com"
+
+var devBoxNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]{2,62}$`)
+
+func (s *serviceManager) ValidateBindingParameters(
+	bindingParameters service.BindingParameters,
+) error {
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return errors.New(
+			"error casting bindingParameters as *devbox.BindingParameters",
+		)
+	}
+	if !isValidObjectID(bp.UserID) {
+		return service.NewValidationError(
+			"userId",
+			fmt.Sprintf(`invalid userId: "%s"`, bp.UserID),
+		)
+	}
+	if bp.DevBoxName != "" && !devBoxNameRegex.MatchString(bp.DevBoxName) {
+		return service.NewValidationError(
+			"devBoxName",
+			fmt.Sprintf(
+				`invalid devBoxName: "%s"; it must be 3-63 letters, numbers, `+
+					`hyphens and underscores and must begin with a letter or number`,
+				bp.DevBoxName,
+			),
+		)
+	}
+	return nil
+}
+
+// Bind requests that a dev box be created from the instance's pool for the
+// user named in the binding parameters. Azure carries on creating the dev box
+// after binding completes; it can be used once it appears in the user's dev
+// portal.
+func (s *serviceManager) Bind(
+	instance service.Instance,
+	bindingParameters service.BindingParameters,
+) (service.BindingDetails, error) {
+	dt, ok := instance.Details.(*devBoxInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *devBoxInstanceDetails",
+		)
+	}
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting bindingParameters as *devbox.BindingParameters",
+		)
+	}
+	bd := &devBoxBindingDetails{
+		UserID:     bp.UserID,
+		DevBoxName: bp.DevBoxName,
+	}
+	if bd.DevBoxName == "" {
+		bd.DevBoxName = "devbox-" + getShortID()
+	}
+	if err := s.devBoxManager.CreateDevBox(
+		dt.DevCenterURI,
+		dt.ProjectName,
+		bd.UserID,
+		bd.DevBoxName,
+		dt.PoolName,
+	); err != nil {
+		return nil, err
+	}
+	return bd, nil
+}
+
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	binding service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*devBoxInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *devBoxInstanceDetails",
+		)
+	}
+	bd, ok := binding.Details.(*devBoxBindingDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting binding.Details as *devBoxBindingDetails",
+		)
+	}
+	return &Credentials{
+		DevCenterURI: dt.DevCenterURI,
+		ProjectName:  dt.ProjectName,
+		PoolName:     dt.PoolName,
+		UserID:       bd.UserID,
+		DevBoxName:   bd.DevBoxName,
+		DevBoxURL: s.devBoxManager.GetDevBoxURL(
+			dt.DevCenterURI,
+			dt.ProjectName,
+			bd.UserID,
+			bd.DevBoxName,
+		),
+		DevPortalURL: devPortalURL,
+	}, nil
+}
+
+func isValidObjectID(objectID string) bool {
+	id, err := uuid.FromString(objectID)
+	return err == nil && strings.EqualFold(id.String(), objectID)
+}
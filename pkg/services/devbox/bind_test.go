@@ -0,0 +1,23 @@
+package devbox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateBindingParameters(t *testing.T) {
+	m := &module{}
+	bp := &BindingParameters{}
+	err := m.serviceManager.ValidateBindingParameters(bp)
+	assert.NotNil(t, err)
+	bp.UserID = "3f9a6c1e-52b7-4d08-9e3a-b14c7d2f6085"
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.Nil(t, err)
+	bp.DevBoxName = "my dev box"
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.NotNil(t, err)
+	bp.DevBoxName = "my-dev-box"
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,48 @@
+package devbox
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:          "8b1f4e62-93d7-4c0a-b5e8-2f6a91d3c74e",
+				Name:        "azure-dev-box",
+				Description: "Microsoft Dev Box (Experimental)",
+				Bindable:    true,
+				Tags:        []string{"Azure", "Dev Box", "Developer Workstations"},
+				// Microsoft Dev Box is only offered in some regions
+				Locations: []string{
+					"australiaeast",
+					"brazilsouth",
+					"canadacentral",
+					"centralindia",
+					"eastasia",
+					"eastus",
+					"eastus2",
+					"francecentral",
+					"germanywestcentral",
+					"japaneast",
+					"koreacentral",
+					"northeurope",
+					"southafricanorth",
+					"southcentralus",
+					"southeastasia",
+					"swedencentral",
+					"switzerlandnorth",
+					"uksouth",
+					"westeurope",
+					"westus2",
+					"westus3",
+				},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:          "d5c7a0e3-1b84-4f29-8e6d-7a3c52f9b018",
+				Name:        "dev-box-pool",
+				Description: "Billed per dev box, by compute hours and storage",
+				Free:        false,
+			}),
+		),
+	}), nil
+}
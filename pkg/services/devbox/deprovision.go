@@ -0,0 +1,100 @@
+package devbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// GetDeprovisioner returns a deprovisioner that deletes the pool, the project
+// and then the dev center, in that order, so that none is deleted while
+// resources beneath it remain
+func (s *serviceManager) GetDeprovisioner(
+	service.Plan,
+) (service.Deprovisioner, error) {
+	return service.NewDeprovisioner(
+		service.NewDeprovisioningStep("deleteARMDeployment", s.deleteARMDeployment),
+		service.NewDeprovisioningStep("deletePool", s.deletePool),
+		service.NewDeprovisioningStep("deleteProject", s.deleteProject),
+		service.NewDeprovisioningStep("deleteDevCenter", s.deleteDevCenter),
+	)
+}
+
+func (s *serviceManager) deleteARMDeployment(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*devBoxInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *devBoxInstanceDetails",
+		)
+	}
+	if err := s.armDeployer.Delete(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, fmt.Errorf("error deleting ARM deployment: %s", err)
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deletePool(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*devBoxInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *devBoxInstanceDetails",
+		)
+	}
+	if err := s.devBoxManager.DeletePool(
+		dt.PoolName,
+		dt.ProjectName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deleteProject(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*devBoxInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *devBoxInstanceDetails",
+		)
+	}
+	if err := s.devBoxManager.DeleteProject(
+		dt.ProjectName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deleteDevCenter(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*devBoxInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *devBoxInstanceDetails",
+		)
+	}
+	if err := s.devBoxManager.DeleteDevCenter(
+		dt.DevCenterName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
@@ -0,0 +1,44 @@
+package devbox
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/devbox"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer   arm.Deployer
+	devBoxManager devbox.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of provisioning Microsoft Dev Box dev centers,
+// along with a project and a pool of dev boxes, and of creating dev boxes for
+// individual users
+func New(
+	armDeployer arm.Deployer,
+	devBoxManager devbox.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer:   armDeployer,
+			devBoxManager: devBoxManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "devbox"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{"Microsoft.DevCenter"}
+}
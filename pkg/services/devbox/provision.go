@@ -0,0 +1,241 @@
+package devbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	defaultSKU   = "general_i_8c32gb256ssd_v2"
+	defaultImage = "microsoftwindowsdesktop_windows-ent-cpc_win11-22h2-ent-cpc-m365" // nolint: lll
+
+	localAdministratorEnabled  = "Enabled"
+	localAdministratorDisabled = "Disabled"
+
+	minStopOnDisconnectGracePeriodMinutes = 60
+	maxStopOnDisconnectGracePeriodMinutes = 480
+)
+
+// osStorageTypes maps the compute SKUs a pool's dev boxes may use to the OS
+// storage type each one comes with. A dev box definition must name both.
+var osStorageTypes = map[string]string{
+	"general_i_8c32gb256ssd_v2":    "ssd_256gb",
+	"general_i_8c32gb512ssd_v2":    "ssd_512gb",
+	"general_i_8c32gb1024ssd_v2":   "ssd_1024gb",
+	"general_i_16c64gb256ssd_v2":   "ssd_256gb",
+	"general_i_16c64gb512ssd_v2":   "ssd_512gb",
+	"general_i_16c64gb1024ssd_v2":  "ssd_1024gb",
+	"general_i_32c128gb512ssd_v2":  "ssd_512gb",
+	"general_i_32c128gb1024ssd_v2": "ssd_1024gb",
+	"general_i_32c128gb2048ssd_v2": "ssd_2048gb",
+}
+
+var imageRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]{0,127}$`)
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*devbox.ProvisioningParameters",
+		)
+	}
+	if pp.MaxDevBoxesPerUser < 0 {
+		return service.NewValidationError(
+			"maxDevBoxesPerUser",
+			fmt.Sprintf(
+				`invalid maxDevBoxesPerUser: %d; it may not be negative`,
+				pp.MaxDevBoxesPerUser,
+			),
+		)
+	}
+	if pp.Pool == nil {
+		return nil
+	}
+	if pp.Pool.SKU != "" {
+		if _, ok := osStorageTypes[pp.Pool.SKU]; !ok {
+			return service.NewValidationError(
+				"pool.sku",
+				fmt.Sprintf(
+					`invalid sku: "%s"; allowed values are: %s`,
+					pp.Pool.SKU,
+					strings.Join(getSKUs(), ", "),
+				),
+			)
+		}
+	}
+	if pp.Pool.Image != "" && !imageRegex.MatchString(pp.Pool.Image) {
+		return service.NewValidationError(
+			"pool.image",
+			fmt.Sprintf(`invalid image: "%s"`, pp.Pool.Image),
+		)
+	}
+	if pp.Pool.LocalAdministrator != "" &&
+		pp.Pool.LocalAdministrator != localAdministratorEnabled &&
+		pp.Pool.LocalAdministrator != localAdministratorDisabled {
+		return service.NewValidationError(
+			"pool.localAdministrator",
+			fmt.Sprintf(
+				`invalid localAdministrator: "%s"; allowed values are: %s, %s`,
+				pp.Pool.LocalAdministrator,
+				localAdministratorEnabled,
+				localAdministratorDisabled,
+			),
+		)
+	}
+	gracePeriod := pp.Pool.StopOnDisconnectGracePeriodMinutes
+	if gracePeriod != 0 &&
+		(gracePeriod < minStopOnDisconnectGracePeriodMinutes ||
+			gracePeriod > maxStopOnDisconnectGracePeriodMinutes) {
+		return service.NewValidationError(
+			"pool.stopOnDisconnectGracePeriodMinutes",
+			fmt.Sprintf(
+				`invalid stopOnDisconnectGracePeriodMinutes: %d; it must be `+
+					`between %d and %d`,
+				gracePeriod,
+				minStopOnDisconnectGracePeriodMinutes,
+				maxStopOnDisconnectGracePeriodMinutes,
+			),
+		)
+	}
+	return nil
+}
+
+func (s *serviceManager) ApplyProvisioningParametersDefaults(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*devbox.ProvisioningParameters",
+		)
+	}
+	if pp.Pool == nil {
+		pp.Pool = &PoolParameters{}
+	}
+	if pp.Pool.SKU == "" {
+		pp.Pool.SKU = defaultSKU
+	}
+	if pp.Pool.Image == "" {
+		pp.Pool.Image = defaultImage
+	}
+	if pp.Pool.LocalAdministrator == "" {
+		pp.Pool.LocalAdministrator = localAdministratorEnabled
+	}
+	return nil
+}
+
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep("deployARMTemplate", s.deployARMTemplate),
+	)
+}
+
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*devBoxInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *devBoxInstanceDetails",
+		)
+	}
+	dt.ARMDeploymentName = uuid.NewV4().String()
+	// Dev center names may be no longer than 26 characters
+	dt.DevCenterName = "dc-" + getShortID()
+	dt.DevBoxDefinitionName = "def-" + getShortID()
+	dt.ProjectName = "proj-" + getShortID()
+	dt.PoolName = "pool-" + getShortID()
+	return dt, nil
+}
+
+func (s *serviceManager) deployARMTemplate(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*devBoxInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *devBoxInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*devbox.ProvisioningParameters",
+		)
+	}
+	gracePeriod := pp.Pool.StopOnDisconnectGracePeriodMinutes
+	outputs, err := s.armDeployer.Deploy(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateBytes,
+		map[string]interface{}{ // Go template params
+			"maxDevBoxesPerUser": pp.MaxDevBoxesPerUser > 0,
+			"stopOnDisconnect":   gracePeriod > 0,
+		},
+		map[string]interface{}{ // ARM template params
+			"devCenterName":                      dt.DevCenterName,
+			"devBoxDefinitionName":               dt.DevBoxDefinitionName,
+			"projectName":                        dt.ProjectName,
+			"poolName":                           dt.PoolName,
+			"image":                              pp.Pool.Image,
+			"sku":                                pp.Pool.SKU,
+			"osStorageType":                      osStorageTypes[pp.Pool.SKU],
+			"localAdministrator":                 pp.Pool.LocalAdministrator,
+			"maxDevBoxesPerUser":                 pp.MaxDevBoxesPerUser,
+			"stopOnDisconnectGracePeriodMinutes": gracePeriod,
+		},
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+	for output, field := range map[string]*string{
+		"devCenterId":  &dt.DevCenterID,
+		"devCenterUri": &dt.DevCenterURI,
+		"projectId":    &dt.ProjectID,
+		"poolId":       &dt.PoolID,
+	} {
+		value, ok := outputs[output].(string)
+		if !ok {
+			return nil, fmt.Errorf(
+				`error retrieving "%s" from deployment`,
+				output,
+			)
+		}
+		*field = value
+	}
+	return dt, nil
+}
+
+// getShortID returns the first segment of a new UUID, which is short enough
+// to fit within the names of any of the resources provisioned
+func getShortID() string {
+	return strings.Split(uuid.NewV4().String(), "-")[0]
+}
+
+func getSKUs() []string {
+	skus := make([]string, 0, len(osStorageTypes))
+	for sku := range osStorageTypes {
+		skus = append(skus, sku)
+	}
+	sort.Strings(skus)
+	return skus
+}
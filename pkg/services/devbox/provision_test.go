@@ -0,0 +1,62 @@
+package devbox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateProvisioningParametersWithDefaults(t *testing.T) {
+	m := &module{}
+	err := m.serviceManager.ValidateProvisioningParameters(
+		&ProvisioningParameters{},
+	)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidPool(t *testing.T) {
+	m := &module{}
+	for _, pool := range []*PoolParameters{
+		{SKU: "general_i_4c16gb128ssd_v2"},
+		{Image: "-windows"},
+		{LocalAdministrator: "Yes"},
+		{StopOnDisconnectGracePeriodMinutes: 30},
+		{StopOnDisconnectGracePeriodMinutes: 600},
+	} {
+		err := m.serviceManager.ValidateProvisioningParameters(
+			&ProvisioningParameters{Pool: pool},
+		)
+		assert.NotNil(t, err, "%+v", *pool)
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(
+		&ProvisioningParameters{
+			Pool: &PoolParameters{
+				SKU:                                "general_i_16c64gb512ssd_v2",
+				Image:                              defaultImage,
+				LocalAdministrator:                 localAdministratorDisabled,
+				StopOnDisconnectGracePeriodMinutes: 60,
+			},
+		},
+	)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithNegativeMaxDevBoxes(t *testing.T) {
+	m := &module{}
+	err := m.serviceManager.ValidateProvisioningParameters(
+		&ProvisioningParameters{MaxDevBoxesPerUser: -1},
+	)
+	assert.NotNil(t, err)
+}
+
+func TestApplyProvisioningParametersDefaults(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{}
+	err := m.serviceManager.ApplyProvisioningParametersDefaults(pp)
+	assert.Nil(t, err)
+	assert.NotNil(t, pp.Pool)
+	assert.Equal(t, defaultSKU, pp.Pool.SKU)
+	assert.Equal(t, defaultImage, pp.Pool.Image)
+	assert.Equal(t, localAdministratorEnabled, pp.Pool.LocalAdministrator)
+	assert.Equal(t, int64(0), pp.Pool.StopOnDisconnectGracePeriodMinutes)
+}
@@ -0,0 +1,100 @@
+package devbox
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// ProvisioningParameters encapsulates Microsoft Dev Box-specific provisioning
+// options
+type ProvisioningParameters struct {
+	// MaxDevBoxesPerUser limits how many dev boxes each user may have in the
+	// project. Zero means no limit.
+	MaxDevBoxesPerUser int64           `json:"maxDevBoxesPerUser"`
+	Pool               *PoolParameters `json:"pool"`
+}
+
+// PoolParameters encapsulates options for the pool that dev boxes are created
+// from. They make up the pool's dev box definition as well as the pool's own
+// settings.
+type PoolParameters struct {
+	// SKU is the compute SKU of the pool's dev boxes, which also determines the
+	// size of their OS disks
+	SKU string `json:"sku"`
+	// Image is the name of an image in the dev center's built-in gallery
+	Image string `json:"image"`
+	// LocalAdministrator is either "Enabled" or "Disabled" and says whether
+	// users are administrators of their own dev boxes
+	LocalAdministrator string `json:"localAdministrator"`
+	// StopOnDisconnectGracePeriodMinutes, if non-zero, is how long a dev box
+	// is left running after its user disconnects before it is stopped
+	StopOnDisconnectGracePeriodMinutes int64 `json:"stopOnDisconnectGracePeriodMinutes"` // nolint: lll
+}
+
+type devBoxInstanceDetails struct {
+	ARMDeploymentName    string `json:"armDeployment"`
+	DevCenterName        string `json:"devCenterName"`
+	DevCenterID          string `json:"devCenterId"`
+	DevCenterURI         string `json:"devCenterUri"`
+	DevBoxDefinitionName string `json:"devBoxDefinitionName"`
+	ProjectName          string `json:"projectName"`
+	ProjectID            string `json:"projectId"`
+	PoolName             string `json:"poolName"`
+	PoolID               string `json:"poolId"`
+}
+
+// UpdatingParameters encapsulates Microsoft Dev Box-specific updating options
+type UpdatingParameters struct {
+}
+
+// BindingParameters encapsulates Microsoft Dev Box-specific binding options
+type BindingParameters struct {
+	// UserID is the Azure Active Directory object ID of the user the dev box
+	// is created for
+	UserID     string `json:"userId"`
+	DevBoxName string `json:"devBoxName"`
+}
+
+type devBoxBindingDetails struct {
+	UserID     string `json:"userId"`
+	DevBoxName string `json:"devBoxName"`
+}
+
+// Credentials encapsulates Microsoft Dev Box-specific connection details
+type Credentials struct {
+	DevCenterURI string `json:"devCenterUri"`
+	ProjectName  string `json:"projectName"`
+	PoolName     string `json:"poolName"`
+	UserID       string `json:"userId"`
+	DevBoxName   string `json:"devBoxName"`
+	// DevBoxURL addresses the dev box on its dev center's endpoint. Appending
+	// "/remoteConnection" to it retrieves the URLs for connecting to the dev
+	// box once Azure has finished creating it.
+	DevBoxURL string `json:"devBoxUrl"`
+	// DevPortalURL is where users manage and connect to their dev boxes from a
+	// browser
+	DevPortalURL string `json:"devPortalUrl"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &devBoxInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &devBoxBindingDetails{}
+}
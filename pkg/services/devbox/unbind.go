@@ -0,0 +1,33 @@
+package devbox
+
+import (
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// Unbind requests that the dev box created when binding be deleted. Whatever
+// the user kept on it is lost.
+func (s *serviceManager) Unbind(
+	instance service.Instance,
+	bindingDetails service.BindingDetails,
+) error {
+	dt, ok := instance.Details.(*devBoxInstanceDetails)
+	if !ok {
+		return errors.New(
+			"error casting instance.Details as *devBoxInstanceDetails",
+		)
+	}
+	bd, ok := bindingDetails.(*devBoxBindingDetails)
+	if !ok {
+		return errors.New(
+			"error casting bindingDetails as *devBoxBindingDetails",
+		)
+	}
+	return s.devBoxManager.DeleteDevBox(
+		dt.DevCenterURI,
+		dt.ProjectName,
+		bd.UserID,
+		bd.DevBoxName,
+	)
+}
@@ -0,0 +1,99 @@
+package devtestlabs
+
+// nolint: lll
+var armTemplateLabBytes = []byte(`
+{
+	"$schema": "https://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+	"contentVersion": "1.0.0.0",
+	"parameters": {
+		"location": {
+			"type": "string"
+		},
+		"labName": {
+			"type": "string"
+		},
+		"allowedVmSizes": {
+			"type": "string",
+			"metadata": {
+				"description": "JSON array of the vm sizes permitted by the lab's policy"
+			}
+		},
+		"shutdownTime": {
+			"type": "string",
+			"metadata": {
+				"description": "Daily time, formatted as HHmm, at which the lab's vms are shut down"
+			}
+		},
+		"timeZoneId": {
+			"type": "string"
+		},
+		"tags": {
+			"type": "object"
+		}
+	},
+	"variables": {
+		"labVirtualNetworkName": "[concat('Dtl', parameters('labName'))]",
+		"labId": "[resourceId('Microsoft.DevTestLab/labs', parameters('labName'))]",
+		"labVirtualNetworkId": "[resourceId('Microsoft.DevTestLab/labs/virtualnetworks', parameters('labName'), variables('labVirtualNetworkName'))]"
+	},
+	"resources": [
+		{
+			"type": "Microsoft.DevTestLab/labs",
+			"name": "[parameters('labName')]",
+			"apiVersion": "2016-05-15",
+			"location": "[parameters('location')]",
+			"tags": "[parameters('tags')]",
+			"resources": [
+				{
+					"type": "virtualnetworks",
+					"name": "[variables('labVirtualNetworkName')]",
+					"apiVersion": "2016-05-15",
+					"dependsOn": [
+						"[variables('labId')]"
+					]
+				},
+				{
+					"type": "schedules",
+					"name": "LabVmsShutdown",
+					"apiVersion": "2016-05-15",
+					"dependsOn": [
+						"[variables('labId')]"
+					],
+					"properties": {
+						"status": "Enabled",
+						"taskType": "LabVmsShutdownTask",
+						"timeZoneId": "[parameters('timeZoneId')]",
+						"dailyRecurrence": {
+							"time": "[parameters('shutdownTime')]"
+						}
+					}
+				},
+				{
+					"type": "policysets/policies",
+					"name": "default/AllowedVmSizesInLab",
+					"apiVersion": "2016-05-15",
+					"dependsOn": [
+						"[variables('labId')]"
+					],
+					"properties": {
+						"factName": "LabVmSize",
+						"threshold": "[parameters('allowedVmSizes')]",
+						"evaluatorType": "AllowedValuesPolicy",
+						"status": "Enabled"
+					}
+				}
+			]
+		}
+	],
+	"outputs": {
+		"labId": {
+			"type": "string",
+			"value": "[variables('labId')]"
+		},
+		"labVirtualNetworkId": {
+			"type": "string",
+			"value": "[variables('labVirtualNetworkId')]"
+		}
+	}
+}
+`)
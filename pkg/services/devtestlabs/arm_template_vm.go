@@ -0,0 +1,90 @@
+package devtestlabs
+
+// nolint: lll
+var armTemplateVMBytes = []byte(`
+{
+	"$schema": "https://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+	"contentVersion": "1.0.0.0",
+	"parameters": {
+		"location": {
+			"type": "string"
+		},
+		"labName": {
+			"type": "string"
+		},
+		"labVirtualNetworkId": {
+			"type": "string"
+		},
+		"vmName": {
+			"type": "string"
+		},
+		"size": {
+			"type": "string"
+		},
+		"userName": {
+			"type": "string"
+		},
+		"password": {
+			"type": "securestring"
+		},
+		"imagePublisher": {
+			"type": "string"
+		},
+		"imageOffer": {
+			"type": "string"
+		},
+		"imageSku": {
+			"type": "string"
+		},
+		"imageOsType": {
+			"type": "string"
+		},
+		"imageVersion": {
+			"type": "string"
+		},
+		"tags": {
+			"type": "object"
+		}
+	},
+	"variables": {
+		"labSubnetName": "[concat('Dtl', parameters('labName'), 'Subnet')]",
+		"vmId": "[resourceId('Microsoft.DevTestLab/labs/virtualmachines', parameters('labName'), parameters('vmName'))]"
+	},
+	"resources": [
+		{
+			"type": "Microsoft.DevTestLab/labs/virtualmachines",
+			"name": "[concat(parameters('labName'), '/', parameters('vmName'))]",
+			"apiVersion": "2016-05-15",
+			"location": "[parameters('location')]",
+			"tags": "[parameters('tags')]",
+			"properties": {
+				"labVirtualNetworkId": "[parameters('labVirtualNetworkId')]",
+				"labSubnetName": "[variables('labSubnetName')]",
+				"size": "[parameters('size')]",
+				"userName": "[parameters('userName')]",
+				"password": "[parameters('password')]",
+				"isAuthenticationWithSshKey": false,
+				"disallowPublicIpAddress": false,
+				"allowClaim": false,
+				"galleryImageReference": {
+					"publisher": "[parameters('imagePublisher')]",
+					"offer": "[parameters('imageOffer')]",
+					"sku": "[parameters('imageSku')]",
+					"osType": "[parameters('imageOsType')]",
+					"version": "[parameters('imageVersion')]"
+				}
+			}
+		}
+	],
+	"outputs": {
+		"vmId": {
+			"type": "string",
+			"value": "[variables('vmId')]"
+		},
+		"fqdn": {
+			"type": "string",
+			"value": "[reference(variables('vmId'), '2016-05-15').fqdn]"
+		}
+	}
+}
+`)
@@ -0,0 +1,56 @@
+package devtestlabs
+
+import (
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) ValidateBindingParameters(
+	service.BindingParameters,
+) error {
+	// There are no parameters for binding to a lab, so there is nothing to
+	// validate
+	return nil
+}
+
+func (s *serviceManager) Bind(
+	service.Instance,
+	service.BindingParameters,
+) (service.BindingDetails, error) {
+	return &devTestLabsBindingDetails{}, nil
+}
+
+// GetCredentials returns the lab's identifiers and, if the lab has a virtual
+// machine, what's needed to connect to it-- ssh for Linux and rdp for Windows
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	_ service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*devTestLabsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *devTestLabsInstanceDetails",
+		)
+	}
+	credentials := &devTestLabsCredentials{
+		LabName: dt.LabName,
+		LabID:   dt.LabID,
+	}
+	if dt.VMName == "" {
+		return credentials, nil
+	}
+	credentials.VMName = dt.VMName
+	credentials.VMID = dt.VMID
+	credentials.FQDN = dt.FQDN
+	credentials.Username = dt.UserName
+	credentials.Password = dt.Password
+	if dt.VMImage != nil && dt.VMImage.OSType == osTypeWindows {
+		credentials.Protocol = "rdp"
+		credentials.Port = 3389
+	} else {
+		credentials.Protocol = "ssh"
+		credentials.Port = 22
+	}
+	return credentials, nil
+}
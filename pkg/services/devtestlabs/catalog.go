@@ -0,0 +1,25 @@
+package devtestlabs
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:          "4c7a2e9d-3b58-4f16-a0d2-8e5b1c6f7a34",
+				Name:        "azure-devtest-lab",
+				Description: "Azure DevTest Labs (Experimental)",
+				Bindable:    true,
+				Cloneable:   true,
+				Tags:        []string{"Azure", "DevTest Labs", "Virtual Machine"},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:          "9e1d6b3a-7f24-4c85-b2e9-5a0c8d4f1e67",
+				Name:        "standard",
+				Description: "A lab with an optional virtual machine",
+				Free:        false,
+			}),
+		),
+	}), nil
+}
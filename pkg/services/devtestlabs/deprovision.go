@@ -0,0 +1,97 @@
+package devtestlabs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) GetDeprovisioner(
+	service.Plan,
+) (service.Deprovisioner, error) {
+	return service.NewDeprovisioner(
+		service.NewDeprovisioningStep(
+			"deleteARMDeployments",
+			s.deleteARMDeployments,
+		),
+		service.NewDeprovisioningStep(
+			"deleteVirtualMachine",
+			s.deleteVirtualMachine,
+		),
+		service.NewDeprovisioningStep("deleteLab", s.deleteLab),
+	)
+}
+
+func (s *serviceManager) deleteARMDeployments(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*devTestLabsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *devTestLabsInstanceDetails",
+		)
+	}
+	if dt.VMARMDeploymentName != "" {
+		if err := s.armDeployer.Delete(
+			dt.VMARMDeploymentName,
+			instance.ResourceGroup,
+		); err != nil {
+			return nil, fmt.Errorf("error deleting ARM deployment: %s", err)
+		}
+	}
+	if err := s.armDeployer.Delete(
+		dt.LabARMDeploymentName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, fmt.Errorf("error deleting ARM deployment: %s", err)
+	}
+	return dt, nil
+}
+
+// deleteVirtualMachine deletes the lab's virtual machine (if any) explicitly.
+// Deleting the lab would delete it too, but leaves no trace of which of the
+// two failed if something goes wrong.
+func (s *serviceManager) deleteVirtualMachine(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*devTestLabsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *devTestLabsInstanceDetails",
+		)
+	}
+	if dt.VMName == "" {
+		return dt, nil
+	}
+	if err := s.devTestLabsManager.DeleteVirtualMachine(
+		dt.LabName,
+		dt.VMName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deleteLab(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*devTestLabsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *devTestLabsInstanceDetails",
+		)
+	}
+	if err := s.devTestLabsManager.DeleteLab(
+		dt.LabName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
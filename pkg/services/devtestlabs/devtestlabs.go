@@ -0,0 +1,43 @@
+package devtestlabs
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/devtestlabs"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer        arm.Deployer
+	devTestLabsManager devtestlabs.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of provisioning Azure DevTest Labs and, optionally,
+// a virtual machine within each lab
+func New(
+	armDeployer arm.Deployer,
+	devTestLabsManager devtestlabs.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer:        armDeployer,
+			devTestLabsManager: devTestLabsManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "devtestlabs"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{"Microsoft.DevTestLab"}
+}
@@ -0,0 +1,381 @@
+package devtestlabs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/devtestlabs"
+	"github.com/Azure/open-service-broker-azure/pkg/generate"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	defaultAutoShutdownTime     = "19:00"
+	defaultAutoShutdownTimeZone = "UTC"
+	defaultImageVersion         = "latest"
+	defaultUserName             = "osbaadmin"
+	osTypeLinux                 = "Linux"
+	osTypeWindows               = "Windows"
+)
+
+// defaultAllowedVMSizes are the vm sizes a lab's policy permits if the
+// provisioning request doesn't say otherwise. The first is used for a lab's
+// virtual machine if none is specified.
+var defaultAllowedVMSizes = []string{
+	"Standard_DS1_v2",
+	"Standard_DS2_v2",
+	"Standard_B1s",
+	"Standard_B2s",
+	"Standard_B2ms",
+	"Standard_D2s_v3",
+}
+
+var (
+	autoShutdownTimeRegex = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+	// User names may contain letters, numbers, hyphens, and underscores, but
+	// may not begin with a hyphen or exceed 20 characters
+	userNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9_-]{0,19}$`)
+)
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*devtestlabs.ProvisioningParameters",
+		)
+	}
+	for _, size := range pp.AllowedVMSizes {
+		if strings.TrimSpace(size) == "" {
+			return service.NewValidationError(
+				"allowedVmSizes",
+				"vm sizes must not be blank",
+			)
+		}
+	}
+	if pp.AutoShutdownTime != "" &&
+		!autoShutdownTimeRegex.MatchString(pp.AutoShutdownTime) {
+		return service.NewValidationError(
+			"autoShutdownTime",
+			fmt.Sprintf(
+				`invalid autoShutdownTime: "%s"; time must be formatted as HH:MM`,
+				pp.AutoShutdownTime,
+			),
+		)
+	}
+	if pp.VM == nil {
+		return nil
+	}
+	return validateVMParameters(pp.VM, getAllowedVMSizes(pp))
+}
+
+func validateVMParameters(vm *VMParameters, allowedVMSizes []string) error {
+	if vm.Size != "" && !isVMSizeAllowed(vm.Size, allowedVMSizes) {
+		return service.NewValidationError(
+			"vm.size",
+			fmt.Sprintf(
+				`vm size "%s" is not permitted by the lab's policy; allowed sizes `+
+					`are: %s`,
+				vm.Size,
+				strings.Join(allowedVMSizes, ", "),
+			),
+		)
+	}
+	if vm.UserName != "" && !userNameRegex.MatchString(vm.UserName) {
+		return service.NewValidationError(
+			"vm.userName",
+			fmt.Sprintf(`invalid userName: "%s"`, vm.UserName),
+		)
+	}
+	if vm.FormulaID != "" && vm.Image != nil {
+		return service.NewValidationError(
+			"vm",
+			"only one of formulaId or image may be specified",
+		)
+	}
+	if vm.FormulaID != "" {
+		if !devtestlabs.IsValidFormulaID(vm.FormulaID) {
+			return service.NewValidationError(
+				"vm.formulaId",
+				fmt.Sprintf(`invalid formulaId: "%s"`, vm.FormulaID),
+			)
+		}
+		return nil
+	}
+	if vm.Image == nil {
+		return service.NewValidationError(
+			"vm",
+			"one of formulaId or image must be specified",
+		)
+	}
+	if vm.Image.Publisher == "" {
+		return service.NewValidationError(
+			"vm.image.publisher",
+			"publisher must be specified",
+		)
+	}
+	if vm.Image.Offer == "" {
+		return service.NewValidationError(
+			"vm.image.offer",
+			"offer must be specified",
+		)
+	}
+	if vm.Image.SKU == "" {
+		return service.NewValidationError(
+			"vm.image.sku",
+			"sku must be specified",
+		)
+	}
+	if vm.Image.OSType != osTypeLinux && vm.Image.OSType != osTypeWindows {
+		return service.NewValidationError(
+			"vm.image.osType",
+			fmt.Sprintf(
+				`invalid osType: "%s"; allowed values are %s and %s`,
+				vm.Image.OSType,
+				osTypeLinux,
+				osTypeWindows,
+			),
+		)
+	}
+	return nil
+}
+
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep("deployLab", s.deployLab),
+		service.NewProvisioningStep("deployVM", s.deployVM),
+	)
+}
+
+// preProvision generates names for new resources and, if a virtual machine
+// was requested, settles on the size and image it will be created with. The
+// size must be both permitted by the lab's policy and available in the lab's
+// region. Since that can only be determined once the size and location are
+// both known (a formula may supply the size), it is checked here rather than
+// when parameters are first validated.
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*devTestLabsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *devTestLabsInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*devtestlabs.ProvisioningParameters",
+		)
+	}
+	dt.LabARMDeploymentName = uuid.NewV4().String()
+	dt.LabName = generate.NewIdentifier()
+	if pp.VM == nil {
+		return dt, nil
+	}
+	allowedVMSizes := getAllowedVMSizes(pp)
+	dt.VMSize = pp.VM.Size
+	if pp.VM.FormulaID != "" {
+		formula, err := s.devTestLabsManager.GetFormula(pp.VM.FormulaID)
+		if err != nil {
+			return nil, err
+		}
+		if dt.VMSize == "" {
+			dt.VMSize = formula.Size
+		}
+		dt.VMImage = &ImageParameters{
+			Publisher: formula.ImagePublisher,
+			Offer:     formula.ImageOffer,
+			SKU:       formula.ImageSKU,
+			OSType:    formula.OSType,
+			Version:   formula.ImageVersion,
+		}
+	} else {
+		image := *pp.VM.Image
+		dt.VMImage = &image
+	}
+	if dt.VMImage.Version == "" {
+		dt.VMImage.Version = defaultImageVersion
+	}
+	if dt.VMSize == "" {
+		dt.VMSize = allowedVMSizes[0]
+	}
+	if !isVMSizeAllowed(dt.VMSize, allowedVMSizes) {
+		return nil, service.NewValidationError(
+			"vm.size",
+			fmt.Sprintf(
+				`vm size "%s" is not permitted by the lab's policy`,
+				dt.VMSize,
+			),
+		)
+	}
+	available, err := s.devTestLabsManager.IsVMSizeAvailable(
+		instance.Location,
+		dt.VMSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !available {
+		return nil, service.NewValidationError(
+			"vm.size",
+			fmt.Sprintf(
+				`vm size "%s" is not available in location "%s"`,
+				dt.VMSize,
+				instance.Location,
+			),
+		)
+	}
+	dt.VMARMDeploymentName = uuid.NewV4().String()
+	dt.VMName = generate.NewIdentifier()
+	dt.UserName = pp.VM.UserName
+	if dt.UserName == "" {
+		dt.UserName = defaultUserName
+	}
+	dt.Password = generate.NewPassword()
+	return dt, nil
+}
+
+func (s *serviceManager) deployLab(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*devTestLabsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *devTestLabsInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*devtestlabs.ProvisioningParameters",
+		)
+	}
+	// The allowed vm sizes policy expects its threshold to be a JSON array
+	allowedVMSizesJSON, err := json.Marshal(getAllowedVMSizes(pp))
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling allowed vm sizes: %s", err)
+	}
+	autoShutdownTime := pp.AutoShutdownTime
+	if autoShutdownTime == "" {
+		autoShutdownTime = defaultAutoShutdownTime
+	}
+	autoShutdownTimeZone := pp.AutoShutdownTimeZone
+	if autoShutdownTimeZone == "" {
+		autoShutdownTimeZone = defaultAutoShutdownTimeZone
+	}
+	outputs, err := s.armDeployer.Deploy(
+		dt.LabARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateLabBytes,
+		nil, // Go template params
+		map[string]interface{}{ // ARM template params
+			"labName":        dt.LabName,
+			"allowedVmSizes": string(allowedVMSizesJSON),
+			// The schedule expects HHmm
+			"shutdownTime": strings.Replace(autoShutdownTime, ":", "", 1),
+			"timeZoneId":   autoShutdownTimeZone,
+		},
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+	labID, ok := outputs["labId"].(string)
+	if !ok {
+		return nil, errors.New("error retrieving lab id from deployment")
+	}
+	dt.LabID = labID
+	labVirtualNetworkID, ok := outputs["labVirtualNetworkId"].(string)
+	if !ok {
+		return nil, errors.New(
+			"error retrieving lab virtual network id from deployment",
+		)
+	}
+	dt.LabVirtualNetworkID = labVirtualNetworkID
+	return dt, nil
+}
+
+func (s *serviceManager) deployVM(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*devTestLabsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *devTestLabsInstanceDetails",
+		)
+	}
+	// No virtual machine was requested
+	if dt.VMName == "" {
+		return dt, nil
+	}
+	outputs, err := s.armDeployer.Deploy(
+		dt.VMARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateVMBytes,
+		nil, // Go template params
+		map[string]interface{}{ // ARM template params
+			"labName":             dt.LabName,
+			"labVirtualNetworkId": dt.LabVirtualNetworkID,
+			"vmName":              dt.VMName,
+			"size":                dt.VMSize,
+			"userName":            dt.UserName,
+			"password":            dt.Password,
+			"imagePublisher":      dt.VMImage.Publisher,
+			"imageOffer":          dt.VMImage.Offer,
+			"imageSku":            dt.VMImage.SKU,
+			"imageOsType":         dt.VMImage.OSType,
+			"imageVersion":        dt.VMImage.Version,
+		},
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+	vmID, ok := outputs["vmId"].(string)
+	if !ok {
+		return nil, errors.New("error retrieving vm id from deployment")
+	}
+	dt.VMID = vmID
+	fqdn, ok := outputs["fqdn"].(string)
+	if !ok {
+		return nil, errors.New(
+			"error retrieving fully qualified domain name from deployment",
+		)
+	}
+	dt.FQDN = fqdn
+	return dt, nil
+}
+
+func getAllowedVMSizes(pp *ProvisioningParameters) []string {
+	if len(pp.AllowedVMSizes) == 0 {
+		return defaultAllowedVMSizes
+	}
+	return pp.AllowedVMSizes
+}
+
+func isVMSizeAllowed(size string, allowedVMSizes []string) bool {
+	for _, allowedSize := range allowedVMSizes {
+		if strings.EqualFold(size, allowedSize) {
+			return true
+		}
+	}
+	return false
+}
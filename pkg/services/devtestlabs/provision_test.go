@@ -0,0 +1,89 @@
+package devtestlabs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getTestImage() *ImageParameters {
+	return &ImageParameters{
+		Publisher: "Canonical",
+		Offer:     "UbuntuServer",
+		SKU:       "16.04-LTS",
+		OSType:    "Linux",
+	}
+}
+
+func TestValidateProvisioningParametersWithDefaults(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidAutoShutdownTime(
+	t *testing.T,
+) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		AutoShutdownTime: "7pm",
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.AutoShutdownTime = "24:00"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.AutoShutdownTime = "23:30"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithVMSizeNotAllowed(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		VM: &VMParameters{
+			Size:  "Standard_G5",
+			Image: getTestImage(),
+		},
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.AllowedVMSizes = []string{"Standard_G5"}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+	pp.VM.Size = "Standard_DS1_v2"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+}
+
+func TestValidateProvisioningParametersWithVMSource(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		VM: &VMParameters{},
+	}
+	// Neither a formula nor an image
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	// Both a formula and an image
+	pp.VM.FormulaID = "/subscriptions/sub/resourceGroups/rg/providers/" +
+		"Microsoft.DevTestLab/labs/lab/formulas/formula"
+	pp.VM.Image = getTestImage()
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	// Just a formula
+	pp.VM.Image = nil
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+	pp.VM.FormulaID = "formula"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	// Just an image
+	pp.VM.FormulaID = ""
+	pp.VM.Image = getTestImage()
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+	pp.VM.Image.OSType = "Plan9"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+}
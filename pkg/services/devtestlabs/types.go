@@ -0,0 +1,95 @@
+package devtestlabs
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// ProvisioningParameters encapsulates DevTest Labs-specific provisioning
+// options
+type ProvisioningParameters struct {
+	AllowedVMSizes       []string      `json:"allowedVmSizes"`
+	AutoShutdownTime     string        `json:"autoShutdownTime"`
+	AutoShutdownTimeZone string        `json:"autoShutdownTimeZone"`
+	VM                   *VMParameters `json:"vm"`
+}
+
+// VMParameters encapsulates options for the virtual machine that is,
+// optionally, created within a new lab. The virtual machine is created either
+// from an existing formula or from a gallery image.
+type VMParameters struct {
+	Size      string           `json:"size"`
+	FormulaID string           `json:"formulaId"`
+	Image     *ImageParameters `json:"image"`
+	UserName  string           `json:"userName"`
+}
+
+// ImageParameters identifies a gallery image to create a virtual machine from
+type ImageParameters struct {
+	Publisher string `json:"publisher"`
+	Offer     string `json:"offer"`
+	SKU       string `json:"sku"`
+	OSType    string `json:"osType"`
+	Version   string `json:"version"`
+}
+
+type devTestLabsInstanceDetails struct {
+	LabARMDeploymentName string           `json:"labArmDeployment"`
+	VMARMDeploymentName  string           `json:"vmArmDeployment"`
+	LabName              string           `json:"labName"`
+	LabID                string           `json:"labId"`
+	LabVirtualNetworkID  string           `json:"labVirtualNetworkId"`
+	VMName               string           `json:"vmName"`
+	VMID                 string           `json:"vmId"`
+	VMSize               string           `json:"vmSize"`
+	VMImage              *ImageParameters `json:"vmImage"`
+	FQDN                 string           `json:"fqdn"`
+	UserName             string           `json:"userName"`
+	Password             string           `json:"password" secret:"true"`
+}
+
+// UpdatingParameters encapsulates DevTest Labs-specific updating options
+type UpdatingParameters struct {
+}
+
+// BindingParameters encapsulates DevTest Labs-specific binding options
+type BindingParameters struct {
+}
+
+type devTestLabsBindingDetails struct {
+}
+
+type devTestLabsCredentials struct {
+	LabName  string `json:"labName"`
+	LabID    string `json:"labId"`
+	VMName   string `json:"vmName,omitempty"`
+	VMID     string `json:"vmId,omitempty"`
+	FQDN     string `json:"fqdn,omitempty"`
+	Protocol string `json:"protocol,omitempty"`
+	Port     int    `json:"port,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &devTestLabsInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &devTestLabsBindingDetails{}
+}
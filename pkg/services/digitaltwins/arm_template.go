@@ -0,0 +1,66 @@
+package digitaltwins
+
+// nolint: lll
+var armTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "digitalTwinsName": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of the Digital Twins instance"
+      }
+    },
+    "identityType": {
+      "type": "string"
+    },
+    {{- if .userAssignedIdentities }}
+    "userAssignedIdentities": {
+      "type": "object"
+    },
+    {{- end }}
+    "tags": {
+      "type": "object"
+    }
+  },
+  "variables": {
+    "apiVersion": "2023-01-31"
+  },
+  "resources": [
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[parameters('digitalTwinsName')]",
+      "type": "Microsoft.DigitalTwins/digitalTwinsInstances",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "identity": {
+        {{- if .userAssignedIdentities }}
+        "userAssignedIdentities": "[parameters('userAssignedIdentities')]",
+        {{- end }}
+        "type": "[parameters('identityType')]"
+      },
+      "properties": {}
+    }
+  ],
+  "outputs": {
+    "digitalTwinsId": {
+      "type": "string",
+      "value": "[resourceId('Microsoft.DigitalTwins/digitalTwinsInstances', parameters('digitalTwinsName'))]"
+    },
+    {{- if .systemAssignedIdentity }}
+    "principalId": {
+      "type": "string",
+      "value": "[reference(parameters('digitalTwinsName'), variables('apiVersion'), 'Full').identity.principalId]"
+    },
+    {{- end }}
+    "hostName": {
+      "type": "string",
+      "value": "[reference(parameters('digitalTwinsName')).hostName]"
+    }
+  }
+}
+`)
@@ -0,0 +1,130 @@
+package digitaltwins
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const defaultRole = "Azure Digital Twins Data Owner"
+
+// roleDefinitionIDs maps the names of the built-in Azure Digital Twins data
+// plane roles that a binding may assign to their role definition IDs
+var roleDefinitionIDs = map[string]string{
+	"Azure Digital Twins Data Owner":  "bcd981a7-7f74-457b-83e1-cceb9e632ffe",
+	"Azure Digital Twins Data Reader": "d57506d4-4c8d-48b1-8587-93c323f6a5a3",
+}
+
+var objectIDRegex = regexp.MustCompile(
+	`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-` +
+		`[0-9a-fA-F]{12}$`,
+)
+
+func (s *serviceManager) ValidateBindingParameters(
+	bindingParameters service.BindingParameters,
+) error {
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return errors.New(
+			"error casting bindingParameters as *digitaltwins.BindingParameters",
+		)
+	}
+	if !objectIDRegex.MatchString(bp.PrincipalID) {
+		return service.NewValidationError(
+			"principalId",
+			fmt.Sprintf(`invalid principalId: "%s"`, bp.PrincipalID),
+		)
+	}
+	if _, ok := roleDefinitionIDs[bp.Role]; bp.Role != "" && !ok {
+		return service.NewValidationError(
+			"role",
+			fmt.Sprintf(
+				`invalid role: "%s"; allowed values are: %s`,
+				bp.Role,
+				strings.Join(getRoleNames(), ", "),
+			),
+		)
+	}
+	return nil
+}
+
+// Bind assigns the requested data plane role to the principal named in the
+// binding parameters, scoped to the Digital Twins instance alone
+func (s *serviceManager) Bind(
+	instance service.Instance,
+	bindingParameters service.BindingParameters,
+) (service.BindingDetails, error) {
+	dt, ok := instance.Details.(*digitalTwinsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *digitalTwinsInstanceDetails",
+		)
+	}
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting bindingParameters as *digitaltwins.BindingParameters",
+		)
+	}
+	bd := &digitalTwinsBindingDetails{
+		PrincipalID:        bp.PrincipalID,
+		Role:               bp.Role,
+		RoleAssignmentName: uuid.NewV4().String(),
+	}
+	if bd.Role == "" {
+		bd.Role = defaultRole
+	}
+	if err := s.digitalTwinsManager.CreateRoleAssignment(
+		dt.DigitalTwinsID,
+		bd.RoleAssignmentName,
+		roleDefinitionIDs[bd.Role],
+		bd.PrincipalID,
+	); err != nil {
+		return nil, err
+	}
+	return bd, nil
+}
+
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	binding service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*digitalTwinsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *digitalTwinsInstanceDetails",
+		)
+	}
+	bd, ok := binding.Details.(*digitalTwinsBindingDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting binding.Details as *digitalTwinsBindingDetails",
+		)
+	}
+	return &Credentials{
+		HostName:    dt.HostName,
+		Endpoint:    "https://" + dt.HostName,
+		Scope:       dt.DigitalTwinsID,
+		PrincipalID: bd.PrincipalID,
+		Role:        bd.Role,
+		RoleAssignmentID: fmt.Sprintf(
+			"%s/providers/Microsoft.Authorization/roleAssignments/%s",
+			dt.DigitalTwinsID,
+			bd.RoleAssignmentName,
+		),
+	}, nil
+}
+
+func getRoleNames() []string {
+	roles := make([]string, 0, len(roleDefinitionIDs))
+	for role := range roleDefinitionIDs {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles
+}
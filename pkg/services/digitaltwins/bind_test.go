@@ -0,0 +1,23 @@
+package digitaltwins
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateBindingParameters(t *testing.T) {
+	m := &module{}
+	bp := &BindingParameters{}
+	err := m.serviceManager.ValidateBindingParameters(bp)
+	assert.NotNil(t, err)
+	bp.PrincipalID = "8c1f5e2a-3d7b-4a96-b0e4-6f2d9a1c7e53"
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.Nil(t, err)
+	bp.Role = "Azure Digital Twins Data Janitor"
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.NotNil(t, err)
+	bp.Role = "Azure Digital Twins Data Reader"
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,49 @@
+package digitaltwins
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:          "6b0e8f3a-92d4-4c17-a5e6-3f1d7c98b2a4",
+				Name:        "azure-digital-twins",
+				Description: "Azure Digital Twins (Experimental)",
+				Bindable:    true,
+				Tags:        []string{"Azure", "Digital Twins", "IoT"},
+				// Azure Digital Twins is only offered in some regions
+				Locations: []string{
+					"australiaeast",
+					"centralindia",
+					"centralus",
+					"eastasia",
+					"eastus",
+					"eastus2",
+					"francecentral",
+					"japaneast",
+					"koreacentral",
+					"northeurope",
+					"qatarcentral",
+					"southafricanorth",
+					"southcentralus",
+					"southeastasia",
+					"swedencentral",
+					"switzerlandnorth",
+					"uaenorth",
+					"uksouth",
+					"westcentralus",
+					"westeurope",
+					"westus2",
+					"westus3",
+				},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:          "e2c47a19-5d83-4f6b-9b0e-7a4d1c3f8e65",
+				Name:        "standard",
+				Description: "Pay-as-you-go, billed per operation, message and query",
+				Free:        false,
+			}),
+		),
+	}), nil
+}
@@ -0,0 +1,56 @@
+package digitaltwins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) GetDeprovisioner(
+	service.Plan,
+) (service.Deprovisioner, error) {
+	return service.NewDeprovisioner(
+		service.NewDeprovisioningStep("deleteARMDeployment", s.deleteARMDeployment),
+		service.NewDeprovisioningStep("deleteDigitalTwins", s.deleteDigitalTwins),
+	)
+}
+
+func (s *serviceManager) deleteARMDeployment(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*digitalTwinsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *digitalTwinsInstanceDetails",
+		)
+	}
+	if err := s.armDeployer.Delete(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, fmt.Errorf("error deleting ARM deployment: %s", err)
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deleteDigitalTwins(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*digitalTwinsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *digitalTwinsInstanceDetails",
+		)
+	}
+	if err := s.digitalTwinsManager.DeleteDigitalTwins(
+		dt.DigitalTwinsName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
@@ -0,0 +1,46 @@
+package digitaltwins
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/digitaltwins"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer         arm.Deployer
+	digitalTwinsManager digitaltwins.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of provisioning Azure Digital Twins instances
+func New(
+	armDeployer arm.Deployer,
+	digitalTwinsManager digitaltwins.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer:         armDeployer,
+			digitalTwinsManager: digitalTwinsManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "digitaltwins"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{
+		"Microsoft.DigitalTwins",
+		"Microsoft.ManagedIdentity",
+		"Microsoft.Authorization",
+	}
+}
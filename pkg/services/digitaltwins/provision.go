@@ -0,0 +1,229 @@
+package digitaltwins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	identityTypeNone                       = "None"
+	identityTypeSystemAssigned             = "SystemAssigned"
+	identityTypeUserAssigned               = "UserAssigned"
+	identityTypeSystemAssignedUserAssigned = "SystemAssigned,UserAssigned"
+)
+
+var identityTypes = []string{
+	identityTypeNone,
+	identityTypeSystemAssigned,
+	identityTypeUserAssigned,
+	identityTypeSystemAssignedUserAssigned,
+}
+
+var userAssignedIdentityIDRegex = regexp.MustCompile(
+	`(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+/providers/` +
+		`Microsoft\.ManagedIdentity/userAssignedIdentities/[^/]+$`,
+)
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*digitaltwins.ProvisioningParameters",
+		)
+	}
+	identityType := getIdentityType(pp)
+	if !isValidIdentityType(identityType) {
+		return service.NewValidationError(
+			"identityType",
+			fmt.Sprintf(
+				`invalid identityType: "%s"; allowed values are: %s`,
+				pp.IdentityType,
+				strings.Join(identityTypes, ", "),
+			),
+		)
+	}
+	if hasUserAssignedIdentities(identityType) {
+		if len(pp.UserAssignedIdentities) == 0 {
+			return service.NewValidationError(
+				"userAssignedIdentities",
+				fmt.Sprintf(
+					`at least one user-assigned identity must be specified when `+
+						`identityType is "%s"`,
+					identityType,
+				),
+			)
+		}
+	} else if len(pp.UserAssignedIdentities) > 0 {
+		return service.NewValidationError(
+			"userAssignedIdentities",
+			fmt.Sprintf(
+				`user-assigned identities cannot be specified when identityType `+
+					`is "%s"`,
+				identityType,
+			),
+		)
+	}
+	for _, id := range pp.UserAssignedIdentities {
+		if !userAssignedIdentityIDRegex.MatchString(id) {
+			return service.NewValidationError(
+				"userAssignedIdentities",
+				fmt.Sprintf(`invalid user-assigned identity resource id: "%s"`, id),
+			)
+		}
+	}
+	return nil
+}
+
+func (s *serviceManager) ApplyProvisioningParametersDefaults(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*digitaltwins.ProvisioningParameters",
+		)
+	}
+	pp.IdentityType = getIdentityType(pp)
+	return nil
+}
+
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep("deployARMTemplate", s.deployARMTemplate),
+	)
+}
+
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*digitalTwinsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *digitalTwinsInstanceDetails",
+		)
+	}
+	dt.ARMDeploymentName = uuid.NewV4().String()
+	dt.DigitalTwinsName = "adt-" + uuid.NewV4().String()
+	return dt, nil
+}
+
+func (s *serviceManager) deployARMTemplate(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*digitalTwinsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *digitalTwinsInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*digitaltwins.ProvisioningParameters",
+		)
+	}
+	goParams, armParams := buildARMTemplateParameters(pp)
+	armParams["digitalTwinsName"] = dt.DigitalTwinsName
+	outputs, err := s.armDeployer.Deploy(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateBytes,
+		goParams,
+		armParams,
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+
+	digitalTwinsID, ok := outputs["digitalTwinsId"].(string)
+	if !ok {
+		return nil, errors.New(
+			"error retrieving Digital Twins instance id from deployment",
+		)
+	}
+	dt.DigitalTwinsID = digitalTwinsID
+
+	hostName, ok := outputs["hostName"].(string)
+	if !ok {
+		return nil, errors.New("error retrieving host name from deployment")
+	}
+	dt.HostName = hostName
+
+	if goParams["systemAssignedIdentity"].(bool) {
+		principalID, ok := outputs["principalId"].(string)
+		if !ok {
+			return nil, errors.New(
+				"error retrieving system-assigned identity from deployment",
+			)
+		}
+		dt.PrincipalID = principalID
+	}
+
+	return dt, nil
+}
+
+// buildARMTemplateParameters returns the Go template parameters and the ARM
+// template parameters used to deploy a Digital Twins instance with the
+// identities the given provisioning parameters describe
+func buildARMTemplateParameters(
+	pp *ProvisioningParameters,
+) (map[string]interface{}, map[string]interface{}) {
+	identityType := getIdentityType(pp)
+	goParams := map[string]interface{}{
+		"systemAssignedIdentity": strings.HasPrefix(
+			identityType,
+			identityTypeSystemAssigned,
+		),
+		"userAssignedIdentities": len(pp.UserAssignedIdentities) > 0,
+	}
+	armParams := map[string]interface{}{
+		"identityType": identityType,
+	}
+	if len(pp.UserAssignedIdentities) > 0 {
+		identities := map[string]interface{}{}
+		for _, id := range pp.UserAssignedIdentities {
+			identities[id] = map[string]interface{}{}
+		}
+		armParams["userAssignedIdentities"] = identities
+	}
+	return goParams, armParams
+}
+
+func getIdentityType(pp *ProvisioningParameters) string {
+	if pp.IdentityType == "" {
+		return identityTypeNone
+	}
+	return pp.IdentityType
+}
+
+func isValidIdentityType(identityType string) bool {
+	for _, t := range identityTypes {
+		if identityType == t {
+			return true
+		}
+	}
+	return false
+}
+
+func hasUserAssignedIdentities(identityType string) bool {
+	return identityType == identityTypeUserAssigned ||
+		identityType == identityTypeSystemAssignedUserAssigned
+}
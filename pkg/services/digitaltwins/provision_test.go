@@ -0,0 +1,65 @@
+package digitaltwins
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testUserAssignedIdentityID = "/subscriptions/sub/resourceGroups/rg/" +
+	"providers/Microsoft.ManagedIdentity/userAssignedIdentities/id"
+
+func TestValidateProvisioningParametersWithDefaults(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidIdentityType(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		IdentityType: "Everything",
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+}
+
+func TestValidateProvisioningParametersWithUserAssignedIdentities(
+	t *testing.T,
+) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		IdentityType: identityTypeSystemAssignedUserAssigned,
+	}
+	// At least one identity is required
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.UserAssignedIdentities = []string{"bogus"}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.UserAssignedIdentities = []string{testUserAssignedIdentityID}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+	// Identities are only allowed if the identity type calls for them
+	pp.IdentityType = identityTypeNone
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+}
+
+func TestBuildARMTemplateParameters(t *testing.T) {
+	goParams, armParams := buildARMTemplateParameters(
+		&ProvisioningParameters{
+			IdentityType:           identityTypeUserAssigned,
+			UserAssignedIdentities: []string{testUserAssignedIdentityID},
+		},
+	)
+	assert.Equal(t, false, goParams["systemAssignedIdentity"])
+	assert.Equal(t, true, goParams["userAssignedIdentities"])
+	assert.Equal(t, identityTypeUserAssigned, armParams["identityType"])
+	assert.Contains(
+		t,
+		armParams["userAssignedIdentities"],
+		testUserAssignedIdentityID,
+	)
+}
@@ -0,0 +1,78 @@
+package digitaltwins
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// ProvisioningParameters encapsulates Azure Digital Twins-specific
+// provisioning options
+type ProvisioningParameters struct {
+	// IdentityType is one of "None", "SystemAssigned", "UserAssigned" or
+	// "SystemAssigned,UserAssigned"
+	IdentityType string `json:"identityType"`
+	// UserAssignedIdentities are the resource IDs of the user-assigned managed
+	// identities to associate with the instance
+	UserAssignedIdentities []string `json:"userAssignedIdentities"`
+}
+
+type digitalTwinsInstanceDetails struct {
+	ARMDeploymentName string `json:"armDeployment"`
+	DigitalTwinsName  string `json:"digitalTwinsName"`
+	DigitalTwinsID    string `json:"digitalTwinsId"`
+	HostName          string `json:"hostName"`
+	// PrincipalID is empty unless the instance has a system-assigned identity
+	PrincipalID string `json:"principalId"`
+}
+
+// UpdatingParameters encapsulates Azure Digital Twins-specific updating
+// options
+type UpdatingParameters struct {
+}
+
+// BindingParameters encapsulates Azure Digital Twins-specific binding options
+type BindingParameters struct {
+	PrincipalID string `json:"principalId"`
+	Role        string `json:"role"`
+}
+
+type digitalTwinsBindingDetails struct {
+	PrincipalID        string `json:"principalId"`
+	Role               string `json:"role"`
+	RoleAssignmentName string `json:"roleAssignmentName"`
+}
+
+// Credentials encapsulates Azure Digital Twins-specific connection details
+type Credentials struct {
+	HostName string `json:"hostName"`
+	Endpoint string `json:"endpoint"`
+	// Scope is the resource ID of the Digital Twins instance, which is also the
+	// scope at which the principal's role was assigned
+	Scope            string `json:"scope"`
+	PrincipalID      string `json:"principalId"`
+	Role             string `json:"role"`
+	RoleAssignmentID string `json:"roleAssignmentId"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &digitalTwinsInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &digitalTwinsBindingDetails{}
+}
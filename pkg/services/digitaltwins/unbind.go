@@ -0,0 +1,29 @@
+package digitaltwins
+
+import (
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) Unbind(
+	instance service.Instance,
+	bindingDetails service.BindingDetails,
+) error {
+	dt, ok := instance.Details.(*digitalTwinsInstanceDetails)
+	if !ok {
+		return errors.New(
+			"error casting instance.Details as *digitalTwinsInstanceDetails",
+		)
+	}
+	bd, ok := bindingDetails.(*digitalTwinsBindingDetails)
+	if !ok {
+		return errors.New(
+			"error casting bindingDetails as *digitalTwinsBindingDetails",
+		)
+	}
+	return s.digitalTwinsManager.DeleteRoleAssignment(
+		dt.DigitalTwinsID,
+		bd.RoleAssignmentName,
+	)
+}
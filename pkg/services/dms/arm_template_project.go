@@ -0,0 +1,51 @@
+package dms
+
+// nolint: lll
+var armTemplateProjectBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "serviceName": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of the migration service the project belongs to"
+      }
+    },
+    "projectName": {
+      "type": "string"
+    },
+    "sourcePlatform": {
+      "type": "string"
+    },
+    "targetPlatform": {
+      "type": "string"
+    },
+    "tags": {
+      "type": "object"
+    }
+  },
+  "resources": [
+    {
+      "apiVersion": "2018-04-19",
+      "name": "[concat(parameters('serviceName'), '/', parameters('projectName'))]",
+      "type": "Microsoft.DataMigration/services/projects",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "properties": {
+        "sourcePlatform": "[parameters('sourcePlatform')]",
+        "targetPlatform": "[parameters('targetPlatform')]"
+      }
+    }
+  ],
+  "outputs": {
+    "projectId": {
+      "type": "string",
+      "value": "[resourceId('Microsoft.DataMigration/services/projects', parameters('serviceName'), parameters('projectName'))]"
+    }
+  }
+}
+`)
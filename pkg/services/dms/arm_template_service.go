@@ -0,0 +1,103 @@
+package dms
+
+// nolint: lll
+var armTemplateServiceBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "serviceName": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of the migration service"
+      }
+    },
+    "skuName": {
+      "type": "string"
+    },
+    "skuTier": {
+      "type": "string"
+    },
+    {{- if .createVirtualNetwork }}
+    "virtualNetworkName": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of the virtual network created for the migration service"
+      }
+    },
+    {{- else }}
+    "virtualSubnetId": {
+      "type": "string",
+      "metadata": {
+        "description": "Resource ID of the subnet the migration service is attached to"
+      }
+    },
+    {{- end }}
+    "tags": {
+      "type": "object"
+    }
+  },
+  "variables": {
+    {{- if .createVirtualNetwork }}
+    "virtualSubnetId": "[resourceId('Microsoft.Network/virtualNetworks/subnets', parameters('virtualNetworkName'), 'default')]",
+    {{- else }}
+    "virtualSubnetId": "[parameters('virtualSubnetId')]",
+    {{- end }}
+    "apiVersion": "2018-04-19"
+  },
+  "resources": [
+    {{- if .createVirtualNetwork }}
+    {
+      "apiVersion": "2018-08-01",
+      "name": "[parameters('virtualNetworkName')]",
+      "type": "Microsoft.Network/virtualNetworks",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "properties": {
+        "addressSpace": {
+          "addressPrefixes": [
+            "10.0.0.0/16"
+          ]
+        },
+        "subnets": [
+          {
+            "name": "default",
+            "properties": {
+              "addressPrefix": "10.0.0.0/24"
+            }
+          }
+        ]
+      }
+    },
+    {{- end }}
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[parameters('serviceName')]",
+      "type": "Microsoft.DataMigration/services",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      {{- if .createVirtualNetwork }}
+      "dependsOn": [
+        "[resourceId('Microsoft.Network/virtualNetworks', parameters('virtualNetworkName'))]"
+      ],
+      {{- end }}
+      "sku": {
+        "name": "[parameters('skuName')]",
+        "tier": "[parameters('skuTier')]"
+      },
+      "properties": {
+        "virtualSubnetId": "[variables('virtualSubnetId')]"
+      }
+    }
+  ],
+  "outputs": {
+    "serviceId": {
+      "type": "string",
+      "value": "[resourceId('Microsoft.DataMigration/services', parameters('serviceName'))]"
+    }
+  }
+}
+`)
@@ -0,0 +1,67 @@
+package dms
+
+import (
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) ValidateBindingParameters(
+	service.BindingParameters,
+) error {
+	// There are no parameters for binding to a migration service, so there is
+	// nothing to validate
+	return nil
+}
+
+// Bind records the migration service's status (e.g. "Online") as reported
+// at the time of binding
+func (s *serviceManager) Bind(
+	instance service.Instance,
+	_ service.BindingParameters,
+) (service.BindingDetails, error) {
+	dt, ok := instance.Details.(*dmsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *dmsInstanceDetails",
+		)
+	}
+	status, err := s.dmsManager.GetServiceStatus(
+		dt.ServiceName,
+		instance.ResourceGroup,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &dmsBindingDetails{
+		Status: status.Status,
+	}, nil
+}
+
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	binding service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*dmsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *dmsInstanceDetails",
+		)
+	}
+	bd, ok := binding.Details.(*dmsBindingDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting binding.Details as *dmsBindingDetails",
+		)
+	}
+	return &dmsCredentials{
+		ServiceName:    dt.ServiceName,
+		ServiceID:      dt.ServiceID,
+		Status:         bd.Status,
+		Endpoint:       s.dmsManager.GetManagementEndpoint(dt.ServiceID),
+		ProjectName:    dt.ProjectName,
+		ProjectID:      dt.ProjectID,
+		SourcePlatform: dt.SourcePlatform,
+		TargetPlatform: dt.TargetPlatform,
+	}, nil
+}
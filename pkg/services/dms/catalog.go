@@ -0,0 +1,25 @@
+package dms
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:          "0c7f2d64-3b1a-4e59-8d2c-6a9e1f5b7c43",
+				Name:        "azure-database-migration",
+				Description: "Azure Database Migration Service (Experimental)",
+				Bindable:    true,
+				Cloneable:   true,
+				Tags:        []string{"Azure", "Database", "Migration"},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:          "8e4b1a95-7d2c-4f36-b0e8-2c5d9a3f6b17",
+				Name:        "standard",
+				Description: "A migration service with an optional migration project",
+				Free:        false,
+			}),
+		),
+	}), nil
+}
@@ -0,0 +1,96 @@
+package dms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) GetDeprovisioner(
+	service.Plan,
+) (service.Deprovisioner, error) {
+	return service.NewDeprovisioner(
+		service.NewDeprovisioningStep(
+			"deleteARMDeployments",
+			s.deleteARMDeployments,
+		),
+		// Deleting the service also deletes its project, if any
+		service.NewDeprovisioningStep("deleteService", s.deleteService),
+		service.NewDeprovisioningStep(
+			"deleteVirtualNetwork",
+			s.deleteVirtualNetwork,
+		),
+	)
+}
+
+func (s *serviceManager) deleteARMDeployments(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*dmsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *dmsInstanceDetails",
+		)
+	}
+	for _, deploymentName := range []string{
+		dt.ProjectARMDeploymentName,
+		dt.ServiceARMDeploymentName,
+	} {
+		if deploymentName == "" {
+			continue
+		}
+		if err := s.armDeployer.Delete(
+			deploymentName,
+			instance.ResourceGroup,
+		); err != nil {
+			return nil, fmt.Errorf("error deleting ARM deployment: %s", err)
+		}
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deleteService(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*dmsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *dmsInstanceDetails",
+		)
+	}
+	if err := s.dmsManager.DeleteService(
+		dt.ServiceName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+// deleteVirtualNetwork deletes the virtual network that was created for the
+// migration service if, and only if, one wasn't supplied at provisioning
+func (s *serviceManager) deleteVirtualNetwork(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*dmsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *dmsInstanceDetails",
+		)
+	}
+	if dt.VirtualNetworkName == "" {
+		return dt, nil
+	}
+	if err := s.dmsManager.DeleteVirtualNetwork(
+		dt.VirtualNetworkName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
@@ -0,0 +1,45 @@
+package dms
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/dms"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer arm.Deployer
+	dmsManager  dms.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of provisioning Azure Database Migration Service
+func New(
+	armDeployer arm.Deployer,
+	dmsManager dms.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer: armDeployer,
+			dmsManager:  dmsManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "dms"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{
+		"Microsoft.DataMigration",
+		"Microsoft.Network",
+	}
+}
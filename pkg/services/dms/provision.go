@@ -0,0 +1,263 @@
+package dms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/generate"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const defaultSKU = "Standard_1vCores"
+
+// skus are the SKUs a migration service may be provisioned with. Each is
+// prefixed with the name of the tier it belongs to.
+var skus = []string{
+	"Standard_1vCores",
+	"Standard_2vCores",
+	"Standard_4vCores",
+	"Premium_4vCores",
+}
+
+// targetPlatforms maps each source platform a migration project may have to
+// the target platforms that Azure Database Migration Service can migrate it
+// to
+var targetPlatforms = map[string][]string{
+	"SQL":        {"SQLDB", "SQLMI"},
+	"MySQL":      {"AzureDbForMySql"},
+	"PostgreSql": {"AzureDbForPostgreSql"},
+	"MongoDb":    {"MongoDb"},
+}
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as *dms.ProvisioningParameters",
+		)
+	}
+	if pp.SKU != "" && !isValidSKU(pp.SKU) {
+		return service.NewValidationError(
+			"sku",
+			fmt.Sprintf(
+				`invalid sku: "%s"; allowed values are: %s`,
+				pp.SKU,
+				strings.Join(skus, ", "),
+			),
+		)
+	}
+	if pp.VirtualSubnetID != "" && !az.IsValidSubnetID(pp.VirtualSubnetID) {
+		return service.NewValidationError(
+			"virtualSubnetId",
+			fmt.Sprintf(`invalid virtualSubnetId: "%s"`, pp.VirtualSubnetID),
+		)
+	}
+	if pp.Project == nil {
+		return nil
+	}
+	return validateProjectParameters(pp.Project)
+}
+
+func validateProjectParameters(project *ProjectParameters) error {
+	allowedTargets, ok := targetPlatforms[project.SourcePlatform]
+	if !ok {
+		return service.NewValidationError(
+			"project.sourcePlatform",
+			fmt.Sprintf(
+				`invalid sourcePlatform: "%s"; allowed values are: %s`,
+				project.SourcePlatform,
+				strings.Join(getSourcePlatforms(), ", "),
+			),
+		)
+	}
+	for _, target := range allowedTargets {
+		if project.TargetPlatform == target {
+			return nil
+		}
+	}
+	return service.NewValidationError(
+		"project.targetPlatform",
+		fmt.Sprintf(
+			`invalid targetPlatform: "%s"; sourcePlatform "%s" can only be `+
+				`migrated to: %s`,
+			project.TargetPlatform,
+			project.SourcePlatform,
+			strings.Join(allowedTargets, ", "),
+		),
+	)
+}
+
+func (s *serviceManager) ApplyProvisioningParametersDefaults(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as *dms.ProvisioningParameters",
+		)
+	}
+	if pp.SKU == "" {
+		pp.SKU = defaultSKU
+	}
+	return nil
+}
+
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep("deployService", s.deployService),
+		service.NewProvisioningStep("deployProject", s.deployProject),
+	)
+}
+
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*dmsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *dmsInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*dms.ProvisioningParameters",
+		)
+	}
+	dt.ServiceARMDeploymentName = uuid.NewV4().String()
+	dt.ServiceName = generate.NewIdentifier()
+	// Migration services must be attached to a subnet. If none was specified,
+	// a virtual network is created for the service's exclusive use.
+	if pp.VirtualSubnetID == "" {
+		dt.VirtualNetworkName = generate.NewIdentifier()
+	}
+	if pp.Project != nil {
+		dt.ProjectARMDeploymentName = uuid.NewV4().String()
+		dt.ProjectName = generate.NewIdentifier()
+		dt.SourcePlatform = pp.Project.SourcePlatform
+		dt.TargetPlatform = pp.Project.TargetPlatform
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deployService(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*dmsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *dmsInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*dms.ProvisioningParameters",
+		)
+	}
+	sku := pp.SKU
+	if sku == "" {
+		sku = defaultSKU
+	}
+	armParams := map[string]interface{}{
+		"serviceName": dt.ServiceName,
+		"skuName":     sku,
+		"skuTier":     strings.SplitN(sku, "_", 2)[0],
+	}
+	if dt.VirtualNetworkName != "" {
+		armParams["virtualNetworkName"] = dt.VirtualNetworkName
+	} else {
+		armParams["virtualSubnetId"] = pp.VirtualSubnetID
+	}
+	outputs, err := s.armDeployer.Deploy(
+		dt.ServiceARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateServiceBytes,
+		map[string]interface{}{ // Go template params
+			"createVirtualNetwork": dt.VirtualNetworkName != "",
+		},
+		armParams,
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+	serviceID, ok := outputs["serviceId"].(string)
+	if !ok {
+		return nil, errors.New("error retrieving service id from deployment")
+	}
+	dt.ServiceID = serviceID
+	return dt, nil
+}
+
+func (s *serviceManager) deployProject(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*dmsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *dmsInstanceDetails",
+		)
+	}
+	// No migration project was requested
+	if dt.ProjectName == "" {
+		return dt, nil
+	}
+	outputs, err := s.armDeployer.Deploy(
+		dt.ProjectARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateProjectBytes,
+		nil, // Go template params
+		map[string]interface{}{ // ARM template params
+			"serviceName":    dt.ServiceName,
+			"projectName":    dt.ProjectName,
+			"sourcePlatform": dt.SourcePlatform,
+			"targetPlatform": dt.TargetPlatform,
+		},
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+	projectID, ok := outputs["projectId"].(string)
+	if !ok {
+		return nil, errors.New("error retrieving project id from deployment")
+	}
+	dt.ProjectID = projectID
+	return dt, nil
+}
+
+func isValidSKU(sku string) bool {
+	for _, validSKU := range skus {
+		if sku == validSKU {
+			return true
+		}
+	}
+	return false
+}
+
+func getSourcePlatforms() []string {
+	sourcePlatforms := make([]string, 0, len(targetPlatforms))
+	for sourcePlatform := range targetPlatforms {
+		sourcePlatforms = append(sourcePlatforms, sourcePlatform)
+	}
+	sort.Strings(sourcePlatforms)
+	return sourcePlatforms
+}
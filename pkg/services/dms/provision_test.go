@@ -0,0 +1,61 @@
+package dms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateProvisioningParametersWithInvalidSKU(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		SKU: "Basic_1vCores",
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.SKU = "Premium_4vCores"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+	pp.SKU = ""
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidVirtualSubnetID(
+	t *testing.T,
+) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		VirtualSubnetID: "/subscriptions/foo/resourceGroups/bar/providers/" +
+			"Microsoft.Network/virtualNetworks/baz",
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.VirtualSubnetID += "/subnets/default"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidProject(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		Project: &ProjectParameters{},
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.Project.SourcePlatform = "Oracle"
+	pp.Project.TargetPlatform = "SQLDB"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	// MySQL can't be migrated to Azure SQL Database
+	pp.Project.SourcePlatform = "MySQL"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.Project.TargetPlatform = "AzureDbForMySql"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+	pp.Project.SourcePlatform = "SQL"
+	pp.Project.TargetPlatform = "SQLMI"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
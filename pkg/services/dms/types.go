@@ -0,0 +1,81 @@
+package dms
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// ProvisioningParameters encapsulates Azure Database Migration
+// Service-specific provisioning options
+type ProvisioningParameters struct {
+	SKU             string             `json:"sku"`
+	VirtualSubnetID string             `json:"virtualSubnetId"`
+	Project         *ProjectParameters `json:"project"`
+}
+
+// ProjectParameters encapsulates options for the migration project that is,
+// optionally, created within a new migration service
+type ProjectParameters struct {
+	SourcePlatform string `json:"sourcePlatform"`
+	TargetPlatform string `json:"targetPlatform"`
+}
+
+type dmsInstanceDetails struct {
+	ServiceARMDeploymentName string `json:"serviceArmDeployment"`
+	ProjectARMDeploymentName string `json:"projectArmDeployment"`
+	ServiceName              string `json:"serviceName"`
+	ServiceID                string `json:"serviceId"`
+	VirtualNetworkName       string `json:"virtualNetworkName"`
+	ProjectName              string `json:"projectName"`
+	ProjectID                string `json:"projectId"`
+	SourcePlatform           string `json:"sourcePlatform"`
+	TargetPlatform           string `json:"targetPlatform"`
+}
+
+// UpdatingParameters encapsulates Azure Database Migration Service-specific
+// updating options
+type UpdatingParameters struct {
+}
+
+// BindingParameters encapsulates Azure Database Migration Service-specific
+// binding options
+type BindingParameters struct {
+}
+
+type dmsBindingDetails struct {
+	Status string `json:"status"`
+}
+
+type dmsCredentials struct {
+	ServiceName    string `json:"serviceName"`
+	ServiceID      string `json:"serviceId"`
+	Status         string `json:"status"`
+	Endpoint       string `json:"endpoint"`
+	ProjectName    string `json:"projectName,omitempty"`
+	ProjectID      string `json:"projectId,omitempty"`
+	SourcePlatform string `json:"sourcePlatform,omitempty"`
+	TargetPlatform string `json:"targetPlatform,omitempty"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &dmsInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &dmsBindingDetails{}
+}
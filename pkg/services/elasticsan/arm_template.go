@@ -0,0 +1,100 @@
+package elasticsan
+
+// nolint: lll
+var armTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "sanName": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of the Elastic SAN"
+      }
+    },
+    "sku": {
+      "type": "string"
+    },
+    "baseSizeTiB": {
+      "type": "int"
+    },
+    "extendedCapacitySizeTiB": {
+      "type": "int"
+    },
+    "volumeGroups": {
+      "type": "array"
+    },
+    "volumes": {
+      "type": "array"
+    },
+    "tags": {
+      "type": "object"
+    }
+  },
+  "variables": {
+    "apiVersion": "2023-01-01"
+  },
+  "resources": [
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[parameters('sanName')]",
+      "type": "Microsoft.ElasticSan/elasticSans",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "properties": {
+        "sku": {
+          "name": "[parameters('sku')]",
+          "tier": "Premium"
+        },
+        "baseSizeTiB": "[parameters('baseSizeTiB')]",
+        "extendedCapacitySizeTiB": "[parameters('extendedCapacitySizeTiB')]"
+      }
+    }
+    {{- range $index, $volumeGroup := .volumeGroups }},
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[concat(parameters('sanName'), '/', parameters('volumeGroups')[{{ $index }}].name)]",
+      "type": "Microsoft.ElasticSan/elasticSans/volumegroups",
+      "dependsOn": [
+        "[resourceId('Microsoft.ElasticSan/elasticSans', parameters('sanName'))]"
+      ],
+      "properties": {
+        "protocolType": "Iscsi",
+        "encryption": "EncryptionAtRestWithPlatformKey",
+        "networkAcls": {
+          "virtualNetworkRules": "[parameters('volumeGroups')[{{ $index }}].virtualNetworkRules]"
+        }
+      }
+    }
+    {{- end }}
+    {{- range $index, $volume := .volumes }},
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[concat(parameters('sanName'), '/', parameters('volumes')[{{ $index }}].volumeGroupName, '/', parameters('volumes')[{{ $index }}].name)]",
+      "type": "Microsoft.ElasticSan/elasticSans/volumegroups/volumes",
+      "dependsOn": [
+        "[resourceId('Microsoft.ElasticSan/elasticSans/volumegroups', parameters('sanName'), parameters('volumes')[{{ $index }}].volumeGroupName)]"
+      ],
+      "properties": {
+        "sizeGiB": "[parameters('volumes')[{{ $index }}].sizeGiB]"
+      }
+    }
+    {{- end }}
+  ],
+  "outputs": {
+    "sanId": {
+      "type": "string",
+      "value": "[resourceId('Microsoft.ElasticSan/elasticSans', parameters('sanName'))]"
+    }
+    {{- range $index, $volume := .volumes }},
+    "volume{{ $index }}StorageTarget": {
+      "type": "object",
+      "value": "[reference(resourceId('Microsoft.ElasticSan/elasticSans/volumegroups/volumes', parameters('sanName'), parameters('volumes')[{{ $index }}].volumeGroupName, parameters('volumes')[{{ $index }}].name), variables('apiVersion')).storageTarget]"
+    }
+    {{- end }}
+  }
+}
+`)
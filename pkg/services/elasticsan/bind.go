@@ -0,0 +1,40 @@
+package elasticsan
+
+import (
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) ValidateBindingParameters(
+	service.BindingParameters,
+) error {
+	// There are no parameters for binding to Elastic SAN, so there is nothing
+	// to validate
+	return nil
+}
+
+// Bind does nothing. Access to the SAN's volumes is governed by the network
+// rules of their volume groups rather than by credentials.
+func (s *serviceManager) Bind(
+	service.Instance,
+	service.BindingParameters,
+) (service.BindingDetails, error) {
+	return &elasticSANBindingDetails{}, nil
+}
+
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	_ service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*elasticSANInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *elasticSANInstanceDetails",
+		)
+	}
+	return &Credentials{
+		SANID:   dt.SANID,
+		Volumes: dt.Volumes,
+	}, nil
+}
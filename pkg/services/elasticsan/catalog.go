@@ -0,0 +1,47 @@
+package elasticsan
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:          "bef43128-d580-4141-8615-ce6c853a6345",
+				Name:        "azure-elastic-san",
+				Description: "Azure Elastic SAN (Experimental)",
+				Bindable:    true,
+				Tags:        []string{"Azure", "Elastic SAN", "Storage", "iSCSI"},
+				// Azure Elastic SAN is only offered in some regions
+				Locations: []string{
+					"australiaeast",
+					"brazilsouth",
+					"canadacentral",
+					"centralus",
+					"eastasia",
+					"eastus",
+					"eastus2",
+					"francecentral",
+					"germanywestcentral",
+					"japaneast",
+					"koreacentral",
+					"northeurope",
+					"southcentralus",
+					"southeastasia",
+					"swedencentral",
+					"switzerlandnorth",
+					"uksouth",
+					"westeurope",
+					"westus2",
+					"westus3",
+				},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:          "e7187f14-f99a-45a8-ab1e-2e33d403e74c",
+				Name:        "premium",
+				Description: "Premium block storage, billed per TiB provisioned",
+				Free:        false,
+			}),
+		),
+	}), nil
+}
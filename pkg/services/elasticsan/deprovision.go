@@ -0,0 +1,133 @@
+package elasticsan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) GetDeprovisioner(
+	service.Plan,
+) (service.Deprovisioner, error) {
+	return service.NewDeprovisioner(
+		service.NewDeprovisioningStep("deleteARMDeployment", s.deleteARMDeployment),
+		service.NewDeprovisioningStep("deleteVolumes", s.deleteVolumes),
+		service.NewDeprovisioningStepWithDependencies(
+			"deleteVolumeGroups",
+			s.deleteVolumeGroups,
+			"deleteVolumes",
+		),
+		service.NewDeprovisioningStepWithDependencies(
+			"deleteSAN",
+			s.deleteSAN,
+			"deleteVolumeGroups",
+		),
+	)
+}
+
+func (s *serviceManager) deleteARMDeployment(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*elasticSANInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *elasticSANInstanceDetails",
+		)
+	}
+	if err := s.armDeployer.Delete(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, fmt.Errorf("error deleting ARM deployment: %s", err)
+	}
+	return dt, nil
+}
+
+// deleteVolumes deletes the SAN's volumes, which must be gone before their
+// volume groups can be deleted. The volumes are found from the provisioning
+// parameters rather than the instance details so that any created by a
+// deployment that didn't complete are deleted too.
+func (s *serviceManager) deleteVolumes(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*elasticSANInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *elasticSANInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*elasticsan.ProvisioningParameters",
+		)
+	}
+	for _, volumeGroup := range pp.VolumeGroups {
+		for _, volume := range volumeGroup.Volumes {
+			if err := s.elasticSANManager.DeleteVolume(
+				dt.SANName,
+				volumeGroup.Name,
+				volume.Name,
+				instance.ResourceGroup,
+			); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return dt, nil
+}
+
+// deleteVolumeGroups deletes the SAN's volume groups, which must be gone
+// before the SAN itself can be deleted
+func (s *serviceManager) deleteVolumeGroups(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*elasticSANInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *elasticSANInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*elasticsan.ProvisioningParameters",
+		)
+	}
+	for _, volumeGroup := range pp.VolumeGroups {
+		if err := s.elasticSANManager.DeleteVolumeGroup(
+			dt.SANName,
+			volumeGroup.Name,
+			instance.ResourceGroup,
+		); err != nil {
+			return nil, err
+		}
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deleteSAN(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*elasticSANInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *elasticSANInstanceDetails",
+		)
+	}
+	if err := s.elasticSANManager.DeleteSAN(
+		dt.SANName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
@@ -0,0 +1,42 @@
+package elasticsan
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/elasticsan"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer       arm.Deployer
+	elasticSANManager elasticsan.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of provisioning Azure Elastic SANs
+func New(
+	armDeployer arm.Deployer,
+	elasticSANManager elasticsan.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer:       armDeployer,
+			elasticSANManager: elasticSANManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "elasticsan"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{"Microsoft.ElasticSan"}
+}
@@ -0,0 +1,451 @@
+package elasticsan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	skuPremiumLRS = "Premium_LRS"
+	skuPremiumZRS = "Premium_ZRS"
+
+	minBaseSizeTiB     = 1
+	defaultBaseSizeTiB = 1
+	maxVolumeGroups    = 200
+	maxVolumesPerGroup = 1000
+	maxVolumeSizeGiB   = 65536
+)
+
+// regionLimits are the maximum capacities of a single Elastic SAN in a region
+type regionLimits struct {
+	maxBaseSizeTiB  int
+	maxTotalSizeTiB int
+}
+
+// defaultRegionLimits apply in every region not found in
+// reducedCapacityRegionLimits
+var defaultRegionLimits = regionLimits{
+	maxBaseSizeTiB:  400,
+	maxTotalSizeTiB: 600,
+}
+
+var reducedCapacityRegionLimits = map[string]regionLimits{
+	"francecentral": {maxBaseSizeTiB: 100, maxTotalSizeTiB: 100},
+	"southeastasia": {maxBaseSizeTiB: 100, maxTotalSizeTiB: 100},
+}
+
+// zrsRegions are the regions in which zone-redundant storage is offered
+var zrsRegions = map[string]bool{
+	"francecentral": true,
+	"northeurope":   true,
+	"westeurope":    true,
+	"westus2":       true,
+}
+
+// nameRegex matches valid names of volume groups and volumes
+var nameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,61}[a-z0-9]$`)
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*elasticsan.ProvisioningParameters",
+		)
+	}
+	if pp.SKU != "" && pp.SKU != skuPremiumLRS && pp.SKU != skuPremiumZRS {
+		return service.NewValidationError(
+			"sku",
+			fmt.Sprintf(
+				`invalid sku: "%s"; allowed values are: %s, %s`,
+				pp.SKU,
+				skuPremiumLRS,
+				skuPremiumZRS,
+			),
+		)
+	}
+	if pp.BaseSizeTiB != 0 && pp.BaseSizeTiB < minBaseSizeTiB {
+		return service.NewValidationError(
+			"baseSizeTiB",
+			fmt.Sprintf(
+				"invalid baseSizeTiB: %d; it must be at least %d",
+				pp.BaseSizeTiB,
+				minBaseSizeTiB,
+			),
+		)
+	}
+	if pp.ExtendedCapacitySizeTiB < 0 {
+		return service.NewValidationError(
+			"extendedCapacitySizeTiB",
+			fmt.Sprintf(
+				"invalid extendedCapacitySizeTiB: %d; it may not be negative",
+				pp.ExtendedCapacitySizeTiB,
+			),
+		)
+	}
+	if len(pp.VolumeGroups) > maxVolumeGroups {
+		return service.NewValidationError(
+			"volumeGroups",
+			fmt.Sprintf(
+				"no more than %d volume groups may be specified",
+				maxVolumeGroups,
+			),
+		)
+	}
+	volumeGroupNames := map[string]bool{}
+	totalVolumeSizeGiB := 0
+	for i, volumeGroup := range pp.VolumeGroups {
+		field := fmt.Sprintf("volumeGroups[%d]", i)
+		if !nameRegex.MatchString(volumeGroup.Name) {
+			return service.NewValidationError(
+				field+".name",
+				fmt.Sprintf(
+					`invalid volume group name: "%s"; names must be 3 to 63 `+
+						`lowercase letters, numbers, and hyphens, beginning and ending `+
+						`with a letter or number`,
+					volumeGroup.Name,
+				),
+			)
+		}
+		if volumeGroupNames[volumeGroup.Name] {
+			return service.NewValidationError(
+				field+".name",
+				fmt.Sprintf(`duplicate volume group name: "%s"`, volumeGroup.Name),
+			)
+		}
+		volumeGroupNames[volumeGroup.Name] = true
+		for _, subnetID := range volumeGroup.SubnetIDs {
+			if !az.IsValidSubnetID(subnetID) {
+				return service.NewValidationError(
+					field+".subnetIds",
+					fmt.Sprintf(`invalid subnet resource id: "%s"`, subnetID),
+				)
+			}
+		}
+		if len(volumeGroup.Volumes) > maxVolumesPerGroup {
+			return service.NewValidationError(
+				field+".volumes",
+				fmt.Sprintf(
+					"no more than %d volumes may be specified per volume group",
+					maxVolumesPerGroup,
+				),
+			)
+		}
+		volumeNames := map[string]bool{}
+		for j, volume := range volumeGroup.Volumes {
+			volumeField := fmt.Sprintf("%s.volumes[%d]", field, j)
+			if !nameRegex.MatchString(volume.Name) {
+				return service.NewValidationError(
+					volumeField+".name",
+					fmt.Sprintf(
+						`invalid volume name: "%s"; names must be 3 to 63 lowercase `+
+							`letters, numbers, and hyphens, beginning and ending with a `+
+							`letter or number`,
+						volume.Name,
+					),
+				)
+			}
+			if volumeNames[volume.Name] {
+				return service.NewValidationError(
+					volumeField+".name",
+					fmt.Sprintf(`duplicate volume name: "%s"`, volume.Name),
+				)
+			}
+			volumeNames[volume.Name] = true
+			if volume.SizeGiB < 1 || volume.SizeGiB > maxVolumeSizeGiB {
+				return service.NewValidationError(
+					volumeField+".sizeGiB",
+					fmt.Sprintf(
+						"invalid sizeGiB: %d; it must be between 1 and %d",
+						volume.SizeGiB,
+						maxVolumeSizeGiB,
+					),
+				)
+			}
+			totalVolumeSizeGiB += volume.SizeGiB
+		}
+	}
+	if capacityGiB := getTotalSizeTiB(pp) * 1024; totalVolumeSizeGiB >
+		capacityGiB {
+		return service.NewValidationError(
+			"volumeGroups",
+			fmt.Sprintf(
+				"the volumes specified total %d GiB, which exceeds the SAN's "+
+					"capacity of %d GiB",
+				totalVolumeSizeGiB,
+				capacityGiB,
+			),
+		)
+	}
+	return nil
+}
+
+// ValidateProvisioningParametersForLocation validates the SAN's SKU and
+// capacity against what is offered in the region it is to be provisioned in
+func (s *serviceManager) ValidateProvisioningParametersForLocation(
+	provisioningParameters service.ProvisioningParameters,
+	location string,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*elasticsan.ProvisioningParameters",
+		)
+	}
+	location = strings.ToLower(location)
+	if getSKU(pp) == skuPremiumZRS && !zrsRegions[location] {
+		return service.NewValidationError(
+			"sku",
+			fmt.Sprintf(
+				`sku "%s" is not available in location "%s"`,
+				skuPremiumZRS,
+				location,
+			),
+		)
+	}
+	limits := getRegionLimits(location)
+	if baseSizeTiB := getBaseSizeTiB(pp); baseSizeTiB > limits.maxBaseSizeTiB {
+		return service.NewValidationError(
+			"baseSizeTiB",
+			fmt.Sprintf(
+				`invalid baseSizeTiB: %d; it may not exceed %d in location "%s"`,
+				baseSizeTiB,
+				limits.maxBaseSizeTiB,
+				location,
+			),
+		)
+	}
+	if totalSizeTiB := getTotalSizeTiB(pp); totalSizeTiB >
+		limits.maxTotalSizeTiB {
+		return service.NewValidationError(
+			"extendedCapacitySizeTiB",
+			fmt.Sprintf(
+				`invalid extendedCapacitySizeTiB: %d; base and extended capacity `+
+					`together may not exceed %d TiB in location "%s"`,
+				pp.ExtendedCapacitySizeTiB,
+				limits.maxTotalSizeTiB,
+				location,
+			),
+		)
+	}
+	return nil
+}
+
+func (s *serviceManager) ApplyProvisioningParametersDefaults(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*elasticsan.ProvisioningParameters",
+		)
+	}
+	pp.SKU = getSKU(pp)
+	pp.BaseSizeTiB = getBaseSizeTiB(pp)
+	return nil
+}
+
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep("deployARMTemplate", s.deployARMTemplate),
+	)
+}
+
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*elasticSANInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *elasticSANInstanceDetails",
+		)
+	}
+	dt.ARMDeploymentName = uuid.NewV4().String()
+	// Elastic SAN names are limited to 24 characters
+	dt.SANName = "esan" + strings.Replace(
+		uuid.NewV4().String(),
+		"-",
+		"",
+		-1,
+	)[:20]
+	return dt, nil
+}
+
+func (s *serviceManager) deployARMTemplate(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*elasticSANInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *elasticSANInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*elasticsan.ProvisioningParameters",
+		)
+	}
+	volumeGroups, volumes := buildVolumeParameters(pp)
+	outputs, err := s.armDeployer.Deploy(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateBytes,
+		map[string]interface{}{ // Go template params
+			"volumeGroups": volumeGroups,
+			"volumes":      volumes,
+		},
+		map[string]interface{}{ // ARM template params
+			"sanName":                 dt.SANName,
+			"sku":                     getSKU(pp),
+			"baseSizeTiB":             getBaseSizeTiB(pp),
+			"extendedCapacitySizeTiB": pp.ExtendedCapacitySizeTiB,
+			"volumeGroups":            volumeGroups,
+			"volumes":                 volumes,
+		},
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+	sanID, ok := outputs["sanId"].(string)
+	if !ok {
+		return nil, errors.New("error retrieving SAN resource id from deployment")
+	}
+	dt.SANID = sanID
+	// The ARM template outputs the storage target of each volume, in the order
+	// the volumes are specified
+	dt.Volumes = []VolumeDetails{}
+	for _, volumeGroup := range pp.VolumeGroups {
+		for _, volume := range volumeGroup.Volumes {
+			storageTarget, ok := outputs[fmt.Sprintf(
+				"volume%dStorageTarget",
+				len(dt.Volumes),
+			)].(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf(
+					`error retrieving storage target of volume "%s" from deployment`,
+					volume.Name,
+				)
+			}
+			targetIQN, ok := storageTarget["targetIqn"].(string)
+			if !ok {
+				return nil, fmt.Errorf(
+					`error retrieving target IQN of volume "%s" from deployment`,
+					volume.Name,
+				)
+			}
+			targetPortalHostname, ok :=
+				storageTarget["targetPortalHostname"].(string)
+			if !ok {
+				return nil, fmt.Errorf(
+					`error retrieving target portal hostname of volume "%s" from `+
+						`deployment`,
+					volume.Name,
+				)
+			}
+			targetPortalPort, ok := storageTarget["targetPortalPort"].(float64)
+			if !ok {
+				return nil, fmt.Errorf(
+					`error retrieving target portal port of volume "%s" from `+
+						`deployment`,
+					volume.Name,
+				)
+			}
+			dt.Volumes = append(
+				dt.Volumes,
+				VolumeDetails{
+					VolumeGroupName:      volumeGroup.Name,
+					Name:                 volume.Name,
+					TargetIQN:            targetIQN,
+					TargetPortalHostname: targetPortalHostname,
+					TargetPortalPort:     int(targetPortalPort),
+				},
+			)
+		}
+	}
+	return dt, nil
+}
+
+// buildVolumeParameters flattens the volume groups, and the volumes within
+// them, that the given provisioning parameters describe into the form the ARM
+// template expects
+func buildVolumeParameters(
+	pp *ProvisioningParameters,
+) ([]map[string]interface{}, []map[string]interface{}) {
+	volumeGroups := []map[string]interface{}{}
+	volumes := []map[string]interface{}{}
+	for _, volumeGroup := range pp.VolumeGroups {
+		virtualNetworkRules := []map[string]interface{}{}
+		for _, subnetID := range volumeGroup.SubnetIDs {
+			virtualNetworkRules = append(
+				virtualNetworkRules,
+				map[string]interface{}{
+					"id":     subnetID,
+					"action": "Allow",
+				},
+			)
+		}
+		volumeGroups = append(
+			volumeGroups,
+			map[string]interface{}{
+				"name":                volumeGroup.Name,
+				"virtualNetworkRules": virtualNetworkRules,
+			},
+		)
+		for _, volume := range volumeGroup.Volumes {
+			volumes = append(
+				volumes,
+				map[string]interface{}{
+					"volumeGroupName": volumeGroup.Name,
+					"name":            volume.Name,
+					"sizeGiB":         volume.SizeGiB,
+				},
+			)
+		}
+	}
+	return volumeGroups, volumes
+}
+
+func getSKU(pp *ProvisioningParameters) string {
+	if pp.SKU == "" {
+		return skuPremiumLRS
+	}
+	return pp.SKU
+}
+
+func getBaseSizeTiB(pp *ProvisioningParameters) int {
+	if pp.BaseSizeTiB == 0 {
+		return defaultBaseSizeTiB
+	}
+	return pp.BaseSizeTiB
+}
+
+func getTotalSizeTiB(pp *ProvisioningParameters) int {
+	return getBaseSizeTiB(pp) + pp.ExtendedCapacitySizeTiB
+}
+
+func getRegionLimits(location string) regionLimits {
+	if limits, ok := reducedCapacityRegionLimits[location]; ok {
+		return limits
+	}
+	return defaultRegionLimits
+}
@@ -0,0 +1,175 @@
+package elasticsan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateProvisioningParametersWithDefaults(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidSKU(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		SKU: "Standard_LRS",
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.SKU = skuPremiumZRS
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidCapacity(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		BaseSizeTiB: -1,
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp = &ProvisioningParameters{
+		ExtendedCapacitySizeTiB: -1,
+	}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidVolumeGroupName(
+	t *testing.T,
+) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		VolumeGroups: []VolumeGroup{
+			{
+				Name: "Invalid_Name",
+			},
+		},
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+}
+
+func TestValidateProvisioningParametersWithDuplicateVolumeNames(
+	t *testing.T,
+) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		VolumeGroups: []VolumeGroup{
+			{
+				Name: "group",
+				Volumes: []Volume{
+					{
+						Name:    "volume",
+						SizeGiB: 1,
+					},
+					{
+						Name:    "volume",
+						SizeGiB: 1,
+					},
+				},
+			},
+		},
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidSubnetID(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		VolumeGroups: []VolumeGroup{
+			{
+				Name:      "group",
+				SubnetIDs: []string{"foo"},
+			},
+		},
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.VolumeGroups[0].SubnetIDs = []string{
+		"/subscriptions/foo/resourceGroups/bar/providers/" +
+			"Microsoft.Network/virtualNetworks/baz/subnets/default",
+	}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithVolumesExceedingCapacity(
+	t *testing.T,
+) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		VolumeGroups: []VolumeGroup{
+			{
+				Name: "group",
+				Volumes: []Volume{
+					{
+						Name:    "volume",
+						SizeGiB: 1025,
+					},
+				},
+			},
+		},
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.ExtendedCapacitySizeTiB = 1
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersForLocationWithZRS(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		SKU: skuPremiumZRS,
+	}
+	err := m.serviceManager.ValidateProvisioningParametersForLocation(
+		pp,
+		"eastus",
+	)
+	assert.NotNil(t, err)
+	err = m.serviceManager.ValidateProvisioningParametersForLocation(
+		pp,
+		"westeurope",
+	)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersForLocationWithCapacity(
+	t *testing.T,
+) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		BaseSizeTiB: 200,
+	}
+	err := m.serviceManager.ValidateProvisioningParametersForLocation(
+		pp,
+		"eastus",
+	)
+	assert.Nil(t, err)
+	err = m.serviceManager.ValidateProvisioningParametersForLocation(
+		pp,
+		"francecentral",
+	)
+	assert.NotNil(t, err)
+	pp.ExtendedCapacitySizeTiB = 401
+	err = m.serviceManager.ValidateProvisioningParametersForLocation(
+		pp,
+		"eastus",
+	)
+	assert.NotNil(t, err)
+}
+
+func TestApplyProvisioningParametersDefaults(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{}
+	err := m.serviceManager.ApplyProvisioningParametersDefaults(pp)
+	assert.Nil(t, err)
+	assert.Equal(t, skuPremiumLRS, pp.SKU)
+	assert.Equal(t, defaultBaseSizeTiB, pp.BaseSizeTiB)
+}
@@ -0,0 +1,95 @@
+package elasticsan
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// ProvisioningParameters encapsulates Azure Elastic SAN-specific provisioning
+// options
+type ProvisioningParameters struct {
+	// SKU is either "Premium_LRS" or "Premium_ZRS". Zone-redundant storage is
+	// only offered in some regions.
+	SKU string `json:"sku"`
+	// BaseSizeTiB is the SAN's base capacity, which determines its performance
+	// as well as contributing to its total capacity
+	BaseSizeTiB int `json:"baseSizeTiB"`
+	// ExtendedCapacitySizeTiB is capacity in addition to the base capacity,
+	// which adds no performance
+	ExtendedCapacitySizeTiB int           `json:"extendedCapacitySizeTiB"`
+	VolumeGroups            []VolumeGroup `json:"volumeGroups"`
+}
+
+// VolumeGroup encapsulates the options for a single volume group of an Elastic
+// SAN
+type VolumeGroup struct {
+	Name string `json:"name"`
+	// SubnetIDs are the resource IDs of the virtual network subnets from which
+	// the group's volumes may be reached over iSCSI. Each subnet must have the
+	// Microsoft.Storage.Global service endpoint enabled.
+	SubnetIDs []string `json:"subnetIds"`
+	Volumes   []Volume `json:"volumes"`
+}
+
+// Volume encapsulates the options for a single volume of an Elastic SAN
+type Volume struct {
+	Name    string `json:"name"`
+	SizeGiB int    `json:"sizeGiB"`
+}
+
+type elasticSANInstanceDetails struct {
+	ARMDeploymentName string          `json:"armDeployment"`
+	SANName           string          `json:"sanName"`
+	SANID             string          `json:"sanId"`
+	Volumes           []VolumeDetails `json:"volumes"`
+}
+
+// VolumeDetails encapsulates the details needed to connect to a single volume
+// using an iSCSI initiator
+type VolumeDetails struct {
+	VolumeGroupName      string `json:"volumeGroupName"`
+	Name                 string `json:"name"`
+	TargetIQN            string `json:"targetIqn"`
+	TargetPortalHostname string `json:"targetPortalHostname"`
+	TargetPortalPort     int    `json:"targetPortalPort"`
+}
+
+// UpdatingParameters encapsulates Azure Elastic SAN-specific updating options
+type UpdatingParameters struct {
+}
+
+// BindingParameters encapsulates Azure Elastic SAN-specific binding options
+type BindingParameters struct {
+}
+
+type elasticSANBindingDetails struct {
+}
+
+// Credentials encapsulates Azure Elastic SAN-specific connection details
+type Credentials struct {
+	SANID   string          `json:"sanId"`
+	Volumes []VolumeDetails `json:"volumes"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &elasticSANInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &elasticSANBindingDetails{}
+}
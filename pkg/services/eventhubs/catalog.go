@@ -10,6 +10,7 @@ func (m *module) GetCatalog() (service.Catalog, error) {
 				Name:        "azure-eventhubs",
 				Description: "Azure Event Hubs (Experimental)",
 				Bindable:    true,
+				Cloneable:   true,
 				Tags:        []string{"Azure", "Event", "Hubs"},
 			},
 			m.serviceManager,
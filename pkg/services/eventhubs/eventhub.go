@@ -36,3 +36,7 @@ func (m *module) GetName() string {
 func (m *module) GetStability() service.Stability {
 	return service.StabilityExperimental
 }
+
+func (m *module) GetResourceProviders() []string {
+	return []string{"Microsoft.EventHub"}
+}
@@ -66,7 +66,7 @@ func (s *serviceManager) deployARMTemplate(
 		instance.Tags,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("error deploying ARM template: %s", err)
+		return nil, service.WrapError(err, "error deploying ARM template")
 	}
 
 	connectionString, ok := outputs["connectionString"].(string)
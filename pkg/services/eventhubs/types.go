@@ -9,8 +9,8 @@ type eventHubInstanceDetails struct {
 	ARMDeploymentName string `json:"armDeployment"`
 	EventHubName      string `json:"eventHubName"`
 	EventHubNamespace string `json:"eventHubNamespace"`
-	PrimaryKey        string `json:"primaryKey"`
-	ConnectionString  string `json:"connectionString"`
+	PrimaryKey        string `json:"primaryKey" secret:"true"`
+	ConnectionString  string `json:"connectionString" secret:"true"`
 }
 
 // UpdatingParameters encapsulates search-specific updating options
@@ -20,6 +20,7 @@ func (m *Module) GetCatalog() (service.Catalog, error) {
 				Description: "Fake Service",
 				Bindable:    true,
 				Tags:        []string{"Fake"},
+				Cloneable:   true,
 			},
 			m.ServiceManager,
 			service.NewPlan(&service.PlanProperties{
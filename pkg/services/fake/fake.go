@@ -2,6 +2,7 @@ package fake
 
 import (
 	"context"
+	"time"
 
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 )
@@ -11,11 +12,128 @@ import (
 // service.Module interface
 type ProvisioningValidationFunction func(service.ProvisioningParameters) error
 
+// ProvisioningDefaultingFunction describes a function used to provide
+// pluggable provisioning parameter defaulting behavior to the fake
+// implementation of the service.Module interface
+type ProvisioningDefaultingFunction func(service.ProvisioningParameters) error
+
+// LocationValidationFunction describes a function used to provide pluggable
+// location-specific provisioning validation behavior to the fake
+// implementation of the service.Module interface
+type LocationValidationFunction func(
+	service.ProvisioningParameters,
+	string,
+) error
+
+// SKUMappingFunction describes a function used to provide pluggable SKU
+// mapping behavior to the fake implementation of the service.Module interface
+type SKUMappingFunction func(
+	service.Plan,
+	service.ProvisioningParameters,
+) ([]service.SKUUsage, error)
+
+// ProvisionFunction describes a function used to provide pluggable
+// provisioning behavior to the fake implementation of the service.Module
+// interface
+type ProvisionFunction func(
+	context.Context,
+	service.Instance,
+) (service.InstanceDetails, error)
+
+// ConnectivityValidationFunction describes a function used to provide
+// pluggable connectivity validation behavior to the fake implementation of the
+// service.Module interface
+type ConnectivityValidationFunction func(
+	context.Context,
+	service.Instance,
+) (bool, error)
+
+// SuccessCriteriaFunction describes a function used to provide pluggable
+// success criteria to the fake implementation of the service.Module interface
+type SuccessCriteriaFunction func(service.Plan) []service.SuccessCriterion
+
+// IdleDetectionFunction describes a function used to provide pluggable idle
+// detection behavior to the fake implementation of the service.Module
+// interface
+type IdleDetectionFunction func(
+	context.Context,
+	service.Instance,
+	time.Duration,
+) (bool, error)
+
+// DriftDetectionFunction describes a function used to provide pluggable drift
+// detection behavior to the fake implementation of the service.Module
+// interface
+type DriftDetectionFunction func(
+	context.Context,
+	service.Instance,
+) ([]service.Drift, error)
+
+// SuspensionFunction describes a function used to provide pluggable
+// suspending or resuming behavior to the fake implementation of the
+// service.Module interface
+type SuspensionFunction func(
+	context.Context,
+	service.Instance,
+) (service.InstanceDetails, error)
+
+// ActivationFunction describes a function used to provide pluggable
+// deactivating or activating behavior to the fake implementation of the
+// service.Module interface
+type ActivationFunction func(
+	context.Context,
+	service.Instance,
+) (service.InstanceDetails, error)
+
+// QuarantineFunction describes a function used to provide pluggable
+// quarantining or releasing behavior to the fake implementation of the
+// service.Module interface
+type QuarantineFunction func(
+	context.Context,
+	service.Instance,
+) (service.InstanceDetails, error)
+
+// DeprovisionFunction describes a function used to provide pluggable
+// deprovisioning behavior to the fake implementation of the service.Module
+// interface
+type DeprovisionFunction func(
+	context.Context,
+	service.Instance,
+) (service.InstanceDetails, error)
+
 // UpdatingValidationFunction describes a function used to provide pluggable
 // updating validation behavior to the fake implementation of the
 // service.Module interface
 type UpdatingValidationFunction func(service.UpdatingParameters) error
 
+// BlueGreenUpdateFunction describes a function used to provide pluggable
+// behavior for deciding whether updates are applied by replacing instances to
+// the fake implementation of the service.Module interface
+type BlueGreenUpdateFunction func(
+	service.Instance,
+	service.Plan,
+	service.UpdatingParameters,
+) bool
+
+// GreenValidationFunction describes a function used to provide pluggable
+// green instance validation behavior to the fake implementation of the
+// service.Module interface
+type GreenValidationFunction func(
+	ctx context.Context,
+	blue service.Instance,
+	green service.Instance,
+) error
+
+// BindingCutOverFunction describes a function used to provide pluggable
+// binding cut over behavior to the fake implementation of the service.Module
+// interface
+type BindingCutOverFunction func(
+	ctx context.Context,
+	from service.Instance,
+	to service.Instance,
+	binding service.Binding,
+) (service.BindingDetails, error)
+
 // BindingValidationFunction describes a function used to provide pluggable
 // binding validation behavior to the fake implementation of the service.Module
 // interface
@@ -28,6 +146,15 @@ type BindFunction func(
 	service.BindingParameters,
 ) (service.BindingDetails, error)
 
+// CredentialRotationFunction describes a function used to provide pluggable
+// credential rotation behavior to the fake implementation of the
+// service.Module interface
+type CredentialRotationFunction func(
+	context.Context,
+	service.Instance,
+	service.Binding,
+) (service.BindingDetails, error)
+
 // UnbindFunction describes a function used to provide pluggable unbinding
 // behavior to the fake implementation of the service.Module interface
 type UnbindFunction func(
@@ -45,10 +172,31 @@ type Module struct {
 // interface used to facilitate testing.
 type ServiceManager struct {
 	ProvisioningValidationBehavior ProvisioningValidationFunction
+	ProvisioningDefaultingBehavior ProvisioningDefaultingFunction
+	LocationValidationBehavior     LocationValidationFunction
+	SKUMappingBehavior             SKUMappingFunction
+	ProvisionBehavior              ProvisionFunction
+	ConnectivityValidationBehavior ConnectivityValidationFunction
+	SuccessCriteriaBehavior        SuccessCriteriaFunction
+	IdleDetectionBehavior          IdleDetectionFunction
+	DriftDetectionBehavior         DriftDetectionFunction
+	SuspendBehavior                SuspensionFunction
+	ResumeBehavior                 SuspensionFunction
+	DeactivateBehavior             ActivationFunction
+	ActivateBehavior               ActivationFunction
+	QuarantineBehavior             QuarantineFunction
+	ReleaseBehavior                QuarantineFunction
 	UpdatingValidationBehavior     UpdatingValidationFunction
+	BlueGreenUpdateBehavior        BlueGreenUpdateFunction
+	GreenValidationBehavior        GreenValidationFunction
+	BindingCutOverBehavior         BindingCutOverFunction
 	BindingValidationBehavior      BindingValidationFunction
 	BindBehavior                   BindFunction
+	CredentialRotationBehavior     CredentialRotationFunction
 	UnbindBehavior                 UnbindFunction
+	// DeprovisionCleanupBehavior is the behavior of the last deprovisioning
+	// step, which depends on the step before it
+	DeprovisionCleanupBehavior DeprovisionFunction
 }
 
 // New returns a new instance of a type that fulfills the service.Module
@@ -57,9 +205,28 @@ func New() (*Module, error) {
 	return &Module{
 		ServiceManager: &ServiceManager{
 			ProvisioningValidationBehavior: defaultProvisioningValidationBehavior,
+			ProvisioningDefaultingBehavior: defaultProvisioningDefaultingBehavior,
+			LocationValidationBehavior:     defaultLocationValidationBehavior,
+			SKUMappingBehavior:             defaultSKUMappingBehavior,
+			ProvisionBehavior:              defaultProvisionBehavior,
+			ConnectivityValidationBehavior: defaultConnectivityValidationBehavior,
+			SuccessCriteriaBehavior:        defaultSuccessCriteriaBehavior,
+			IdleDetectionBehavior:          defaultIdleDetectionBehavior,
+			DriftDetectionBehavior:         defaultDriftDetectionBehavior,
+			SuspendBehavior:                defaultSuspensionBehavior,
+			ResumeBehavior:                 defaultSuspensionBehavior,
+			DeactivateBehavior:             defaultActivationBehavior,
+			ActivateBehavior:               defaultActivationBehavior,
+			QuarantineBehavior:             defaultQuarantineBehavior,
+			ReleaseBehavior:                defaultQuarantineBehavior,
 			UpdatingValidationBehavior:     defaultUpdatingValidationBehavior,
+			BlueGreenUpdateBehavior:        defaultBlueGreenUpdateBehavior,
+			GreenValidationBehavior:        defaultGreenValidationBehavior,
+			BindingCutOverBehavior:         defaultBindingCutOverBehavior,
+			DeprovisionCleanupBehavior:     defaultDeprovisionBehavior,
 			BindingValidationBehavior:      defaultBindingValidationBehavior,
 			BindBehavior:                   defaultBindBehavior,
+			CredentialRotationBehavior:     defaultCredentialRotationBehavior,
 			UnbindBehavior:                 defaultUnbindBehavior,
 		},
 	}, nil
@@ -83,6 +250,33 @@ func (s *ServiceManager) ValidateProvisioningParameters(
 	return s.ProvisioningValidationBehavior(provisioningParameters)
 }
 
+// ApplyProvisioningParametersDefaults applies defaults to the provided
+// provisioningParameters
+func (s *ServiceManager) ApplyProvisioningParametersDefaults(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	return s.ProvisioningDefaultingBehavior(provisioningParameters)
+}
+
+// ValidateProvisioningParametersForLocation validates the provided
+// provisioningParameters against the limits that apply in the provided
+// location
+func (s *ServiceManager) ValidateProvisioningParametersForLocation(
+	provisioningParameters service.ProvisioningParameters,
+	location string,
+) error {
+	return s.LocationValidationBehavior(provisioningParameters, location)
+}
+
+// GetSKUUsages returns the SKUs an instance provisioned using the provided
+// plan and provisioningParameters would use
+func (s *ServiceManager) GetSKUUsages(
+	plan service.Plan,
+	provisioningParameters service.ProvisioningParameters,
+) ([]service.SKUUsage, error) {
+	return s.SKUMappingBehavior(plan, provisioningParameters)
+}
+
 // GetProvisioner returns a provisioner that defines the steps a module must
 // execute asynchronously to provision a service
 func (s *ServiceManager) GetProvisioner(
@@ -94,10 +288,95 @@ func (s *ServiceManager) GetProvisioner(
 }
 
 func (s *ServiceManager) provision(
-	_ context.Context,
+	ctx context.Context,
 	instance service.Instance,
 ) (service.InstanceDetails, error) {
-	return instance.Details, nil
+	return s.ProvisionBehavior(ctx, instance)
+}
+
+// ValidateConnectivity validates that a newly provisioned instance is
+// reachable
+func (s *ServiceManager) ValidateConnectivity(
+	ctx context.Context,
+	instance service.Instance,
+) (bool, error) {
+	return s.ConnectivityValidationBehavior(ctx, instance)
+}
+
+// GetSuccessCriteria returns the success criteria that instances of the given
+// plan must meet
+func (s *ServiceManager) GetSuccessCriteria(
+	plan service.Plan,
+) []service.SuccessCriterion {
+	return s.SuccessCriteriaBehavior(plan)
+}
+
+// IsIdle returns whether the instance has been idle for the given period
+func (s *ServiceManager) IsIdle(
+	ctx context.Context,
+	instance service.Instance,
+	period time.Duration,
+) (bool, error) {
+	return s.IdleDetectionBehavior(ctx, instance, period)
+}
+
+// DetectDrift returns the ways in which the instance's underlying resources
+// differ from its desired state
+func (s *ServiceManager) DetectDrift(
+	ctx context.Context,
+	instance service.Instance,
+) ([]service.Drift, error) {
+	return s.DriftDetectionBehavior(ctx, instance)
+}
+
+// Suspend suspends an idle instance
+func (s *ServiceManager) Suspend(
+	ctx context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	return s.SuspendBehavior(ctx, instance)
+}
+
+// Resume resumes a suspended instance
+func (s *ServiceManager) Resume(
+	ctx context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	return s.ResumeBehavior(ctx, instance)
+}
+
+// Deactivate deactivates a newly provisioned instance whose activation is
+// deferred
+func (s *ServiceManager) Deactivate(
+	ctx context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	return s.DeactivateBehavior(ctx, instance)
+}
+
+// Activate activates an instance whose activation was deferred
+func (s *ServiceManager) Activate(
+	ctx context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	return s.ActivateBehavior(ctx, instance)
+}
+
+// Quarantine denies network access to an instance's underlying resources
+func (s *ServiceManager) Quarantine(
+	ctx context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	return s.QuarantineBehavior(ctx, instance)
+}
+
+// Release restores network access to a quarantined instance's underlying
+// resources
+func (s *ServiceManager) Release(
+	ctx context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	return s.ReleaseBehavior(ctx, instance)
 }
 
 // ValidateUpdatingParameters validates the provided updatingParameters
@@ -123,6 +402,46 @@ func (s *ServiceManager) update(
 	return instance.Details, nil
 }
 
+// IsBlueGreenUpdate returns whether an update is applied by replacing the
+// instance
+func (s *ServiceManager) IsBlueGreenUpdate(
+	instance service.Instance,
+	plan service.Plan,
+	updatingParameters service.UpdatingParameters,
+) bool {
+	return s.BlueGreenUpdateBehavior(instance, plan, updatingParameters)
+}
+
+// GetGreenInstance returns the provisioning parameters and initial details of
+// an instance that replaces the given one. The fake module's instances have
+// nothing to copy.
+func (s *ServiceManager) GetGreenInstance(
+	blue service.Instance,
+	_ service.Plan,
+	_ service.UpdatingParameters,
+) (service.ProvisioningParameters, service.InstanceDetails, error) {
+	return blue.ProvisioningParameters, &InstanceDetails{}, nil
+}
+
+// ValidateGreenInstance validates an instance that is to replace another
+func (s *ServiceManager) ValidateGreenInstance(
+	ctx context.Context,
+	blue service.Instance,
+	green service.Instance,
+) error {
+	return s.GreenValidationBehavior(ctx, blue, green)
+}
+
+// CutOverBinding cuts a binding over from one instance to its replacement
+func (s *ServiceManager) CutOverBinding(
+	ctx context.Context,
+	from service.Instance,
+	to service.Instance,
+	binding service.Binding,
+) (service.BindingDetails, error) {
+	return s.BindingCutOverBehavior(ctx, from, to, binding)
+}
+
 // ValidateBindingParameters validates the provided bindingParameters and
 // returns an error if there is any problem
 func (s *ServiceManager) ValidateBindingParameters(
@@ -148,6 +467,15 @@ func (s *ServiceManager) GetCredentials(
 	return &Credentials{}, nil
 }
 
+// RotateCredentials replaces a binding's credential
+func (s *ServiceManager) RotateCredentials(
+	ctx context.Context,
+	instance service.Instance,
+	binding service.Binding,
+) (service.BindingDetails, error) {
+	return s.CredentialRotationBehavior(ctx, instance, binding)
+}
+
 // Unbind synchronously unbinds from a service
 func (s *ServiceManager) Unbind(
 	instance service.Instance,
@@ -163,6 +491,11 @@ func (s *ServiceManager) GetDeprovisioner(
 ) (service.Deprovisioner, error) {
 	return service.NewDeprovisioner(
 		service.NewDeprovisioningStep("run", s.deprovision),
+		service.NewDeprovisioningStepWithDependencies(
+			"cleanup",
+			s.cleanup,
+			"run",
+		),
 	)
 }
 
@@ -173,18 +506,136 @@ func (s *ServiceManager) deprovision(
 	return instance.Details, nil
 }
 
+func (s *ServiceManager) cleanup(
+	ctx context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	return s.DeprovisionCleanupBehavior(ctx, instance)
+}
+
 func defaultProvisioningValidationBehavior(
 	service.ProvisioningParameters,
 ) error {
 	return nil
 }
 
+func defaultProvisioningDefaultingBehavior(
+	service.ProvisioningParameters,
+) error {
+	return nil
+}
+
+func defaultLocationValidationBehavior(
+	service.ProvisioningParameters,
+	string,
+) error {
+	return nil
+}
+
+// defaultSKUMappingBehavior maps every instance to the cost of its plan alone
+func defaultSKUMappingBehavior(
+	service.Plan,
+	service.ProvisioningParameters,
+) ([]service.SKUUsage, error) {
+	return []service.SKUUsage{
+		{
+			Quantity: 1,
+		},
+	}, nil
+}
+
+func defaultProvisionBehavior(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	return instance.Details, nil
+}
+
+func defaultDeprovisionBehavior(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	return instance.Details, nil
+}
+
+func defaultConnectivityValidationBehavior(
+	context.Context,
+	service.Instance,
+) (bool, error) {
+	return true, nil
+}
+
+func defaultSuccessCriteriaBehavior(service.Plan) []service.SuccessCriterion {
+	return nil
+}
+
+func defaultIdleDetectionBehavior(
+	context.Context,
+	service.Instance,
+	time.Duration,
+) (bool, error) {
+	return false, nil
+}
+
+func defaultDriftDetectionBehavior(
+	context.Context,
+	service.Instance,
+) ([]service.Drift, error) {
+	return nil, nil
+}
+
+func defaultSuspensionBehavior(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	return instance.Details, nil
+}
+
+func defaultActivationBehavior(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	return instance.Details, nil
+}
+
+func defaultQuarantineBehavior(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	return instance.Details, nil
+}
+
 func defaultUpdatingValidationBehavior(
 	service.UpdatingParameters,
 ) error {
 	return nil
 }
 
+func defaultBlueGreenUpdateBehavior(
+	service.Instance,
+	service.Plan,
+	service.UpdatingParameters,
+) bool {
+	return false
+}
+
+func defaultGreenValidationBehavior(
+	context.Context,
+	service.Instance,
+	service.Instance,
+) error {
+	return nil
+}
+
+func defaultBindingCutOverBehavior(
+	_ context.Context,
+	_ service.Instance,
+	_ service.Instance,
+	binding service.Binding,
+) (service.BindingDetails, error) {
+	return binding.Details, nil
+}
+
 func defaultBindingValidationBehavior(service.BindingParameters) error {
 	return nil
 }
@@ -196,6 +647,14 @@ func defaultBindBehavior(
 	return &BindingDetails{}, nil
 }
 
+func defaultCredentialRotationBehavior(
+	_ context.Context,
+	_ service.Instance,
+	binding service.Binding,
+) (service.BindingDetails, error) {
+	return binding.Details, nil
+}
+
 func defaultUnbindBehavior(service.Instance, service.BindingDetails) error {
 	return nil
 }
@@ -8,7 +8,10 @@ import "github.com/Azure/open-service-broker-azure/pkg/service"
 // here because the fake service module is used to facilitate testing of the
 // broker framework itself.
 type ProvisioningParameters struct {
-	SomeParameter string `json:"someParameter"`
+	SomeParameter       string `json:"someParameter"`
+	SomeSecretParameter string `json:"someSecretParameter" secret:"true"`
+	// SomeTemplatedParameter may reference values from the request's context
+	SomeTemplatedParameter string `json:"someTemplatedParameter,omitempty" template:"true"` // nolint: lll
 }
 
 // InstanceDetails represents details collected and modified over the course
@@ -0,0 +1,98 @@
+package fhir
+
+// nolint: lll
+var armTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "workspaceName": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of the Health Data Services workspace"
+      }
+    },
+    "fhirServiceName": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of the FHIR service"
+      }
+    },
+    "kind": {
+      "type": "string",
+      "allowedValues": [
+        "fhir-R4",
+        "fhir-Stu3"
+      ]
+    },
+    "authority": {
+      "type": "string"
+    },
+    "audience": {
+      "type": "string"
+    },
+    "smartProxyEnabled": {
+      "type": "bool"
+    },
+    "tags": {
+      "type": "object"
+    }
+  },
+  "variables": {
+    "apiVersion": "2023-11-01",
+    "workspaceId": "[resourceId('Microsoft.HealthcareApis/workspaces', parameters('workspaceName'))]",
+    "endpoint": "[concat('https://', parameters('workspaceName'), '-', parameters('fhirServiceName'), '.fhir.azurehealthcareapis.com')]",
+    "authority": "[if(empty(parameters('authority')), concat(environment().authentication.loginEndpoint, subscription().tenantId), parameters('authority'))]",
+    "audience": "[if(empty(parameters('audience')), variables('endpoint'), parameters('audience'))]"
+  },
+  "resources": [
+    {{- if .createWorkspace }}
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[parameters('workspaceName')]",
+      "type": "Microsoft.HealthcareApis/workspaces",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "properties": {}
+    },
+    {{- end }}
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[concat(parameters('workspaceName'), '/', parameters('fhirServiceName'))]",
+      "type": "Microsoft.HealthcareApis/workspaces/fhirservices",
+      "kind": "[parameters('kind')]",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      {{- if .createWorkspace }}
+      "dependsOn": [
+        "[variables('workspaceId')]"
+      ],
+      {{- end }}
+      "properties": {
+        "authenticationConfiguration": {
+          "authority": "[variables('authority')]",
+          "audience": "[variables('audience')]",
+          "smartProxyEnabled": "[parameters('smartProxyEnabled')]"
+        }
+      }
+    }
+  ],
+  "outputs": {
+    "endpoint": {
+      "type": "string",
+      "value": "[variables('endpoint')]"
+    },
+    "authority": {
+      "type": "string",
+      "value": "[variables('authority')]"
+    },
+    "audience": {
+      "type": "string",
+      "value": "[variables('audience')]"
+    }
+  }
+}
+`)
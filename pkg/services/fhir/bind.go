@@ -0,0 +1,43 @@
+package fhir
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) ValidateBindingParameters(
+	service.BindingParameters,
+) error {
+	// There are no parameters for binding to a FHIR service, so there is
+	// nothing to validate
+	return nil
+}
+
+func (s *serviceManager) Bind(
+	service.Instance,
+	service.BindingParameters,
+) (service.BindingDetails, error) {
+	return &fhirBindingDetails{}, nil
+}
+
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	_ service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*fhirInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *fhirInstanceDetails",
+		)
+	}
+	return &Credentials{
+		Endpoint:  dt.Endpoint,
+		Authority: dt.Authority,
+		Audience:  dt.Audience,
+		// Access is granted to the service as a whole, i.e. to the audience's
+		// default scope
+		Scope: strings.TrimSuffix(dt.Audience, "/") + "/.default",
+	}, nil
+}
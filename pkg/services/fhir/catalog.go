@@ -0,0 +1,55 @@
+package fhir
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:          "3d419172-f23d-4c1b-8dc6-066aaae3ae63",
+				Name:        "azure-fhir-service",
+				Description: "Azure Health Data Services FHIR service (Experimental)",
+				Bindable:    true,
+				Tags: []string{
+					"Azure",
+					"Health Data Services",
+					"FHIR",
+					"Healthcare",
+				},
+				// Azure Health Data Services is only offered in some regions
+				Locations: []string{
+					"australiaeast",
+					"brazilsouth",
+					"canadacentral",
+					"centralindia",
+					"eastus",
+					"eastus2",
+					"francecentral",
+					"germanywestcentral",
+					"japaneast",
+					"koreacentral",
+					"northcentralus",
+					"northeurope",
+					"qatarcentral",
+					"southcentralus",
+					"southeastasia",
+					"swedencentral",
+					"switzerlandnorth",
+					"uksouth",
+					"ukwest",
+					"westcentralus",
+					"westeurope",
+					"westus2",
+					"westus3",
+				},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:          "9ef5a33f-e6fc-47cb-b33a-4e0fd60ae54d",
+				Name:        "standard",
+				Description: "Billed per unit of storage and per API request",
+				Free:        false,
+			}),
+		),
+	}), nil
+}
@@ -0,0 +1,123 @@
+package fhir
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) GetDeprovisioner(
+	service.Plan,
+) (service.Deprovisioner, error) {
+	return service.NewDeprovisioner(
+		service.NewDeprovisioningStep("deleteARMDeployment", s.deleteARMDeployment),
+		service.NewDeprovisioningStep("deleteFHIRService", s.deleteFHIRService),
+		service.NewDeprovisioningStepWithDependencies(
+			"deleteWorkspace",
+			s.deleteWorkspace,
+			"deleteFHIRService",
+		),
+	)
+}
+
+func (s *serviceManager) deleteARMDeployment(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*fhirInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *fhirInstanceDetails",
+		)
+	}
+	if err := s.armDeployer.Delete(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, fmt.Errorf("error deleting ARM deployment: %s", err)
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deleteFHIRService(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*fhirInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *fhirInstanceDetails",
+		)
+	}
+	if err := s.fhirManager.DeleteFHIRService(
+		dt.WorkspaceName,
+		dt.FHIRServiceName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+// deleteWorkspace deletes the FHIR service's workspace if the broker created
+// it, the instance didn't ask for it to be kept, and no FHIR services are left
+// in it. A workspace shared by several instances therefore goes with the last
+// of them to be deprovisioned.
+func (s *serviceManager) deleteWorkspace(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*fhirInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *fhirInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*fhir.ProvisioningParameters",
+		)
+	}
+	if pp.KeepWorkspace || dt.WorkspaceName == "" {
+		return dt, nil
+	}
+	workspace, ok, err := s.fhirManager.GetWorkspace(
+		dt.WorkspaceName,
+		instance.ResourceGroup,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !ok || workspace.Tags[arm.HeritageTagName] != arm.HeritageTagValue {
+		return dt, nil
+	}
+	fhirServiceIDs, err := s.fhirManager.GetWorkspaceFHIRServiceIDs(
+		dt.WorkspaceName,
+		instance.ResourceGroup,
+	)
+	if err != nil {
+		return nil, err
+	}
+	// The FHIR service this instance just deleted may briefly still be listed
+	for _, fhirServiceID := range fhirServiceIDs {
+		if !strings.HasSuffix(
+			strings.ToLower(fhirServiceID),
+			"/"+dt.FHIRServiceName,
+		) {
+			return dt, nil
+		}
+	}
+	if err := s.fhirManager.DeleteWorkspace(
+		dt.WorkspaceName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
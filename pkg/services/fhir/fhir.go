@@ -0,0 +1,43 @@
+package fhir
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/fhir"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer arm.Deployer
+	fhirManager fhir.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of provisioning FHIR services in Azure Health Data
+// Services workspaces
+func New(
+	armDeployer arm.Deployer,
+	fhirManager fhir.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer: armDeployer,
+			fhirManager: fhirManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "fhir"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{"Microsoft.HealthcareApis"}
+}
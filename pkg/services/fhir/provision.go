@@ -0,0 +1,238 @@
+package fhir
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	kindR4   = "fhir-R4"
+	kindSTU3 = "fhir-Stu3"
+)
+
+// nameRegex matches the names Azure permits of workspaces and FHIR services
+// alike
+var nameRegex = regexp.MustCompile(`^[a-z0-9]{3,24}$`)
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as *fhir.ProvisioningParameters",
+		)
+	}
+	if pp.WorkspaceName != "" && !nameRegex.MatchString(pp.WorkspaceName) {
+		return service.NewValidationError(
+			"workspace",
+			fmt.Sprintf(
+				`invalid workspace: "%s"; names must be 3 to 24 lowercase letters `+
+					`and digits`,
+				pp.WorkspaceName,
+			),
+		)
+	}
+	switch pp.Kind {
+	case "", kindR4, kindSTU3:
+	default:
+		return service.NewValidationError(
+			"kind",
+			fmt.Sprintf(
+				`invalid kind: "%s"; the FHIR version must be "%s" or "%s"`,
+				pp.Kind,
+				kindR4,
+				kindSTU3,
+			),
+		)
+	}
+	return validateAuthentication(pp.Authentication)
+}
+
+func validateAuthentication(authentication *Authentication) error {
+	if authentication == nil {
+		return nil
+	}
+	if authentication.Authority != "" {
+		authority, err := url.Parse(authentication.Authority)
+		if err != nil || authority.Scheme != "https" || authority.Host == "" {
+			return service.NewValidationError(
+				"authentication.authority",
+				fmt.Sprintf(
+					`invalid authority: "%s"; it must be an https URL`,
+					authentication.Authority,
+				),
+			)
+		}
+	}
+	if authentication.Audience != "" {
+		// Audiences are often application ID URIs (e.g. api://...) rather than
+		// https URLs
+		audience, err := url.Parse(authentication.Audience)
+		if err != nil || audience.Scheme == "" || audience.Host == "" {
+			return service.NewValidationError(
+				"authentication.audience",
+				fmt.Sprintf(
+					`invalid audience: "%s"; it must be an absolute URI`,
+					authentication.Audience,
+				),
+			)
+		}
+	}
+	return nil
+}
+
+func (s *serviceManager) ApplyProvisioningParametersDefaults(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as *fhir.ProvisioningParameters",
+		)
+	}
+	if pp.Kind == "" {
+		pp.Kind = kindR4
+	}
+	if pp.Authentication == nil {
+		pp.Authentication = &Authentication{}
+	}
+	return nil
+}
+
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep("deployARMTemplate", s.deployARMTemplate),
+		service.NewProvisioningStep("waitForFHIRService", s.waitForFHIRService),
+	)
+}
+
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*fhirInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *fhirInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*fhir.ProvisioningParameters",
+		)
+	}
+	dt.ARMDeploymentName = uuid.NewV4().String()
+	// Workspace and FHIR service names are limited to 24 letters and digits
+	dt.FHIRServiceName = "fhir" + strings.Replace(
+		uuid.NewV4().String(),
+		"-",
+		"",
+		-1,
+	)[:20]
+	dt.WorkspaceName = pp.WorkspaceName
+	if dt.WorkspaceName == "" {
+		dt.WorkspaceName = "hdw" + strings.Replace(
+			uuid.NewV4().String(),
+			"-",
+			"",
+			-1,
+		)[:20]
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deployARMTemplate(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*fhirInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *fhirInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*fhir.ProvisioningParameters",
+		)
+	}
+	// A workspace that already exists is left as it is, along with any other
+	// services in it
+	_, workspaceExists, err := s.fhirManager.GetWorkspace(
+		dt.WorkspaceName,
+		instance.ResourceGroup,
+	)
+	if err != nil {
+		return nil, err
+	}
+	outputs, err := s.armDeployer.Deploy(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateBytes,
+		map[string]interface{}{ // Go template params
+			"createWorkspace": !workspaceExists,
+		},
+		map[string]interface{}{ // ARM template params
+			"workspaceName":     dt.WorkspaceName,
+			"fhirServiceName":   dt.FHIRServiceName,
+			"kind":              pp.Kind,
+			"authority":         pp.Authentication.Authority,
+			"audience":          pp.Authentication.Audience,
+			"smartProxyEnabled": pp.Authentication.SMARTProxyEnabled,
+		},
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+	if dt.Endpoint, ok = outputs["endpoint"].(string); !ok {
+		return nil, errors.New("error retrieving endpoint from deployment")
+	}
+	if dt.Authority, ok = outputs["authority"].(string); !ok {
+		return nil, errors.New("error retrieving authority from deployment")
+	}
+	if dt.Audience, ok = outputs["audience"].(string); !ok {
+		return nil, errors.New("error retrieving audience from deployment")
+	}
+	return dt, nil
+}
+
+// waitForFHIRService waits for the FHIR service to finish provisioning. Its
+// deployment can complete while the service's data store is still being set
+// up.
+func (s *serviceManager) waitForFHIRService(
+	ctx context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*fhirInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *fhirInstanceDetails",
+		)
+	}
+	if err := s.fhirManager.WaitForFHIRService(
+		ctx,
+		dt.WorkspaceName,
+		dt.FHIRServiceName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
@@ -0,0 +1,76 @@
+package fhir
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateProvisioningParameters(t *testing.T) {
+	m := &module{}
+	err := m.serviceManager.ValidateProvisioningParameters(
+		&ProvisioningParameters{},
+	)
+	assert.Nil(t, err)
+	err = m.serviceManager.ValidateProvisioningParameters(
+		&ProvisioningParameters{
+			WorkspaceName: "clinicaldata",
+			Kind:          kindSTU3,
+			Authentication: &Authentication{
+				Authority:         "https://login.microsoftonline.com/tenant",
+				Audience:          "api://fhir-clients",
+				SMARTProxyEnabled: true,
+			},
+		},
+	)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidWorkspace(t *testing.T) {
+	m := &module{}
+	for _, workspaceName := range []string{"ws", "clinical-data", "Clinical"} {
+		err := m.serviceManager.ValidateProvisioningParameters(
+			&ProvisioningParameters{WorkspaceName: workspaceName},
+		)
+		assert.NotNil(t, err, workspaceName)
+	}
+}
+
+func TestValidateProvisioningParametersWithInvalidKind(t *testing.T) {
+	m := &module{}
+	for _, kind := range []string{"fhir-R5", "R4", "fhir-r4"} {
+		err := m.serviceManager.ValidateProvisioningParameters(
+			&ProvisioningParameters{Kind: kind},
+		)
+		assert.NotNil(t, err, kind)
+	}
+}
+
+func TestValidateProvisioningParametersWithInvalidAuthentication(t *testing.T) {
+	m := &module{}
+	err := m.serviceManager.ValidateProvisioningParameters(
+		&ProvisioningParameters{
+			Authentication: &Authentication{
+				Authority: "http://login.microsoftonline.com/tenant",
+			},
+		},
+	)
+	assert.NotNil(t, err)
+	err = m.serviceManager.ValidateProvisioningParameters(
+		&ProvisioningParameters{
+			Authentication: &Authentication{
+				Audience: "fhir-clients",
+			},
+		},
+	)
+	assert.NotNil(t, err)
+}
+
+func TestApplyProvisioningParametersDefaults(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{}
+	err := m.serviceManager.ApplyProvisioningParametersDefaults(pp)
+	assert.Nil(t, err)
+	assert.Equal(t, kindR4, pp.Kind)
+	assert.NotNil(t, pp.Authentication)
+}
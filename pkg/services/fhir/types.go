@@ -0,0 +1,84 @@
+package fhir
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// ProvisioningParameters encapsulates FHIR service-specific provisioning
+// options
+type ProvisioningParameters struct {
+	// WorkspaceName, if specified, names a Health Data Services workspace in
+	// the instance's resource group for the FHIR service to belong to, which is
+	// created if it doesn't already exist. Otherwise, a new workspace is
+	// created for the FHIR service alone.
+	WorkspaceName string `json:"workspace" template:"true"`
+	// KeepWorkspace indicates whether the workspace is retained once it has no
+	// FHIR services anymore. Workspaces the broker didn't create are always
+	// retained.
+	KeepWorkspace  bool            `json:"keepWorkspace"`
+	Kind           string          `json:"kind"`
+	Authentication *Authentication `json:"authentication"`
+}
+
+// Authentication encapsulates how clients of the FHIR service authenticate.
+// Tokens must be issued by the authority for the audience. By default, the
+// authority is the broker's own Azure AD tenant and the audience is the FHIR
+// service's endpoint.
+type Authentication struct {
+	Authority         string `json:"authority"`
+	Audience          string `json:"audience"`
+	SMARTProxyEnabled bool   `json:"smartProxyEnabled"`
+}
+
+type fhirInstanceDetails struct {
+	ARMDeploymentName string `json:"armDeployment"`
+	WorkspaceName     string `json:"workspaceName"`
+	FHIRServiceName   string `json:"fhirServiceName"`
+	Endpoint          string `json:"endpoint"`
+	Authority         string `json:"authority"`
+	Audience          string `json:"audience"`
+}
+
+// UpdatingParameters encapsulates FHIR service-specific updating options
+type UpdatingParameters struct {
+}
+
+// BindingParameters encapsulates FHIR service-specific binding options
+type BindingParameters struct {
+}
+
+type fhirBindingDetails struct {
+}
+
+// Credentials encapsulates the details needed to reach a FHIR service. Scope
+// is what a client requests a token for in order to access the service.
+type Credentials struct {
+	Endpoint  string `json:"endpoint"`
+	Authority string `json:"authority"`
+	Audience  string `json:"audience"`
+	Scope     string `json:"scope"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &fhirInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &fhirBindingDetails{}
+}
@@ -0,0 +1,81 @@
+package firewallpolicy
+
+// nolint: lll
+var armTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "policyName": {
+      "type": "string"
+    },
+    "tier": {
+      "type": "string",
+      "allowedValues": [
+        "Basic",
+        "Standard",
+        "Premium"
+      ]
+    },
+    "threatIntelMode": {
+      "type": "string",
+      "allowedValues": [
+        "Off",
+        "Alert",
+        "Deny"
+      ]
+    },
+    "ruleCollectionGroupPriority": {
+      "type": "int"
+    },
+    "ruleCollections": {
+      "type": "array",
+      "metadata": {
+        "description": "The rule collections of the group that the broker manages"
+      }
+    },
+    "tags": {
+      "type": "object"
+    }
+  },
+  "variables": {
+    "apiVersion": "2023-09-01"
+  },
+  "resources": [
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[parameters('policyName')]",
+      "type": "Microsoft.Network/firewallPolicies",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "properties": {
+        "sku": {
+          "tier": "[parameters('tier')]"
+        },
+        "threatIntelMode": "[parameters('threatIntelMode')]"
+      }
+    },
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[concat(parameters('policyName'), '/osba-rule-collections')]",
+      "type": "Microsoft.Network/firewallPolicies/ruleCollectionGroups",
+      "dependsOn": [
+        "[resourceId('Microsoft.Network/firewallPolicies', parameters('policyName'))]"
+      ],
+      "properties": {
+        "priority": "[parameters('ruleCollectionGroupPriority')]",
+        "ruleCollections": "[parameters('ruleCollections')]"
+      }
+    }
+  ],
+  "outputs": {
+    "policyId": {
+      "type": "string",
+      "value": "[resourceId('Microsoft.Network/firewallPolicies', parameters('policyName'))]"
+    }
+  }
+}
+`)
@@ -0,0 +1,42 @@
+package firewallpolicy
+
+import (
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) ValidateBindingParameters(
+	service.BindingParameters,
+) error {
+	// There are no parameters for binding to a Firewall Manager policy, so there
+	// is nothing to validate
+	return nil
+}
+
+func (s *serviceManager) Bind(
+	service.Instance,
+	service.BindingParameters,
+) (service.BindingDetails, error) {
+	// Firewalls are associated with the policy by reference, so binding only
+	// needs to hand out the policy's resource ID
+	return &firewallPolicyBindingDetails{}, nil
+}
+
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	_ service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*firewallPolicyInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *firewallPolicyInstanceDetails",
+		)
+	}
+	tier, _ := instance.Plan.GetProperties().Extended["policyTier"].(string)
+	return &Credentials{
+		PolicyID:   dt.PolicyID,
+		PolicyName: dt.PolicyName,
+		Tier:       tier,
+	}, nil
+}
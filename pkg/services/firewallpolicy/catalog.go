@@ -0,0 +1,53 @@
+package firewallpolicy
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:          "b49d9ed4-0104-49fb-98d5-4952958799de",
+				Name:        "azure-firewall-policy",
+				Description: "Azure Firewall Manager Policy (Experimental)",
+				Bindable:    true,
+				Tags: []string{
+					"Azure",
+					"Firewall",
+					"Firewall Manager",
+					"Network Security",
+				},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:   "2eba7010-fe7e-4222-80ec-f11fb82349c2",
+				Name: "basic",
+				Description: "A policy for Basic tier firewalls, with network, " +
+					"application and NAT rules",
+				Free: false,
+				Extended: map[string]interface{}{
+					"policyTier": "Basic",
+				},
+			}),
+			service.NewPlan(&service.PlanProperties{
+				ID:   "32662a0b-1da6-4663-837f-21fa82b27fab",
+				Name: "standard",
+				Description: "A policy for Standard tier firewalls, adding " +
+					"threat intelligence-based filtering",
+				Free: false,
+				Extended: map[string]interface{}{
+					"policyTier": "Standard",
+				},
+			}),
+			service.NewPlan(&service.PlanProperties{
+				ID:   "31470269-67e7-4c1c-bd45-b89aa38bd970",
+				Name: "premium",
+				Description: "A policy for Premium tier firewalls, which can " +
+					"also inspect TLS and detect intrusions",
+				Free: false,
+				Extended: map[string]interface{}{
+					"policyTier": "Premium",
+				},
+			}),
+		),
+	}), nil
+}
@@ -0,0 +1,97 @@
+package firewallpolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/firewallpolicy"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) GetDeprovisioner(
+	service.Plan,
+) (service.Deprovisioner, error) {
+	return service.NewDeprovisioner(
+		service.NewDeprovisioningStep("deleteARMDeployment", s.deleteARMDeployment),
+		service.NewDeprovisioningStepWithDependencies(
+			"deletePolicy",
+			s.deletePolicy,
+			"deleteARMDeployment",
+		),
+	)
+}
+
+func (s *serviceManager) deleteARMDeployment(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*firewallPolicyInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *firewallPolicyInstanceDetails",
+		)
+	}
+	if err := s.armDeployer.Delete(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, fmt.Errorf("error deleting ARM deployment: %s", err)
+	}
+	return dt, nil
+}
+
+// deletePolicy deletes the policy once no firewalls or child policies refer to
+// it anymore. Until then, the step fails with an error in the "in use"
+// category, which the teardown retry policy retries, so that the policy
+// outlives the last of the firewalls sharing it.
+func (s *serviceManager) deletePolicy(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*firewallPolicyInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *firewallPolicyInstanceDetails",
+		)
+	}
+	policy, ok, err := s.firewallPolicyManager.GetPolicy(
+		dt.PolicyName,
+		instance.ResourceGroup,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return dt, nil
+	}
+	references := append(
+		getIDs(policy.Properties.Firewalls),
+		getIDs(policy.Properties.ChildPolicies)...,
+	)
+	if len(references) > 0 {
+		return nil, service.NewCategorizedError(
+			service.ErrorCategoryInUse,
+			fmt.Errorf(
+				"firewall policy is still referred to by: %s",
+				strings.Join(references, ", "),
+			),
+		)
+	}
+	if err := s.firewallPolicyManager.DeletePolicy(
+		dt.PolicyName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+func getIDs(subResources []firewallpolicy.SubResource) []string {
+	ids := make([]string, len(subResources))
+	for i, subResource := range subResources {
+		ids[i] = subResource.ID
+	}
+	return ids
+}
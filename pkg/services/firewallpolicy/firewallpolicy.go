@@ -0,0 +1,42 @@
+package firewallpolicy
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/firewallpolicy"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer           arm.Deployer
+	firewallPolicyManager firewallpolicy.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of provisioning Firewall Manager policies
+func New(
+	armDeployer arm.Deployer,
+	firewallPolicyManager firewallpolicy.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer:           armDeployer,
+			firewallPolicyManager: firewallPolicyManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "firewallpolicy"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{"Microsoft.Network"}
+}
@@ -0,0 +1,642 @@
+package firewallpolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	threatIntelModeOff   = "Off"
+	threatIntelModeAlert = "Alert"
+	threatIntelModeDeny  = "Deny"
+
+	ruleCollectionTypeNetwork     = "network"
+	ruleCollectionTypeApplication = "application"
+	ruleCollectionTypeNAT         = "nat"
+
+	actionAllow = "Allow"
+	actionDeny  = "Deny"
+
+	defaultThreatIntelMode             = threatIntelModeAlert
+	defaultRuleCollectionGroupPriority = 200
+
+	// Azure orders rule collection groups, and the rule collections within
+	// them, by priorities within this range
+	minPriority = 100
+	maxPriority = 65000
+)
+
+var threatIntelModes = []string{
+	threatIntelModeOff,
+	threatIntelModeAlert,
+	threatIntelModeDeny,
+}
+
+var ruleCollectionTypes = []string{
+	ruleCollectionTypeNetwork,
+	ruleCollectionTypeApplication,
+	ruleCollectionTypeNAT,
+}
+
+var networkProtocols = []string{"TCP", "UDP", "ICMP", "Any"}
+
+var natProtocols = []string{"TCP", "UDP"}
+
+var applicationProtocols = []string{"Http", "Https", "Mssql"}
+
+// nameRegex matches the names Azure permits of rule collections and rules
+var nameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]{0,79}$`)
+
+// serviceTagRegex matches the names of service tags, e.g. "AzureCloud" or
+// "Storage.EastUS", which network rules may use as destinations
+var serviceTagRegex = regexp.MustCompile(
+	`^[a-zA-Z][a-zA-Z0-9]*(\.[a-zA-Z0-9]+)*$`,
+)
+
+// fqdnRegex matches fully qualified domain names, optionally with a leading
+// wildcard label
+var fqdnRegex = regexp.MustCompile(
+	`^(\*\.)?[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?` +
+		`(\.[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?)*$`,
+)
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*firewallpolicy.ProvisioningParameters",
+		)
+	}
+	if pp.ThreatIntelMode != "" &&
+		!contains(threatIntelModes, pp.ThreatIntelMode) {
+		return service.NewValidationError(
+			"threatIntelMode",
+			fmt.Sprintf(
+				`invalid threatIntelMode: "%s"; allowed values are: %s`,
+				pp.ThreatIntelMode,
+				strings.Join(threatIntelModes, ", "),
+			),
+		)
+	}
+	if pp.RuleCollectionGroupPriority != 0 &&
+		!isValidPriority(pp.RuleCollectionGroupPriority) {
+		return service.NewValidationError(
+			"ruleCollectionGroupPriority",
+			fmt.Sprintf(
+				"invalid ruleCollectionGroupPriority: %d; it must be from %d to %d",
+				pp.RuleCollectionGroupPriority,
+				minPriority,
+				maxPriority,
+			),
+		)
+	}
+	return validateRuleCollections("ruleCollections", pp.RuleCollections)
+}
+
+// validateRuleCollections validates the given rule collections, which are
+// identified in validation errors by the given field
+func validateRuleCollections(
+	field string,
+	ruleCollections []RuleCollection,
+) error {
+	names := map[string]bool{}
+	for i, ruleCollection := range ruleCollections {
+		collectionField := fmt.Sprintf("%s[%d]", field, i)
+		if err := validateName(
+			collectionField,
+			ruleCollection.Name,
+			names,
+		); err != nil {
+			return err
+		}
+		if err := validateRuleCollection(
+			collectionField,
+			ruleCollection,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateRuleCollection(
+	field string,
+	ruleCollection RuleCollection,
+) error {
+	if !isValidPriority(ruleCollection.Priority) {
+		return service.NewValidationError(
+			field+".priority",
+			fmt.Sprintf(
+				"invalid priority: %d; it must be from %d to %d",
+				ruleCollection.Priority,
+				minPriority,
+				maxPriority,
+			),
+		)
+	}
+	if !contains(ruleCollectionTypes, ruleCollection.Type) {
+		return service.NewValidationError(
+			field+".type",
+			fmt.Sprintf(
+				`invalid rule collection type: "%s"; allowed values are: %s`,
+				ruleCollection.Type,
+				strings.Join(ruleCollectionTypes, ", "),
+			),
+		)
+	}
+	if ruleCollection.Type == ruleCollectionTypeNAT {
+		if ruleCollection.Action != "" {
+			return service.NewValidationError(
+				field+".action",
+				"NAT rule collections don't take an action",
+			)
+		}
+	} else if ruleCollection.Action != actionAllow &&
+		ruleCollection.Action != actionDeny {
+		return service.NewValidationError(
+			field+".action",
+			fmt.Sprintf(
+				`invalid action: "%s"; allowed values are: %s, %s`,
+				ruleCollection.Action,
+				actionAllow,
+				actionDeny,
+			),
+		)
+	}
+	if len(ruleCollection.Rules) == 0 {
+		return service.NewValidationError(
+			field+".rules",
+			"at least one rule must be specified",
+		)
+	}
+	names := map[string]bool{}
+	for i, rule := range ruleCollection.Rules {
+		ruleField := fmt.Sprintf("%s.rules[%d]", field, i)
+		if err := validateName(ruleField, rule.Name, names); err != nil {
+			return err
+		}
+		if err := validateAddresses(
+			ruleField+".sourceAddresses",
+			rule.SourceAddresses,
+			false,
+		); err != nil {
+			return err
+		}
+		var err error
+		switch ruleCollection.Type {
+		case ruleCollectionTypeNetwork:
+			err = validateNetworkRule(ruleField, rule)
+		case ruleCollectionTypeApplication:
+			err = validateApplicationRule(ruleField, rule)
+		case ruleCollectionTypeNAT:
+			err = validateNATRule(ruleField, rule)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateNetworkRule(field string, rule Rule) error {
+	if err := validateProtocols(
+		field,
+		rule.Protocols,
+		networkProtocols,
+	); err != nil {
+		return err
+	}
+	if err := validateAddresses(
+		field+".destinationAddresses",
+		rule.DestinationAddresses,
+		true,
+	); err != nil {
+		return err
+	}
+	if len(rule.DestinationPorts) == 0 {
+		return service.NewValidationError(
+			field+".destinationPorts",
+			"at least one destination port must be specified",
+		)
+	}
+	for _, port := range rule.DestinationPorts {
+		if !isValidPortOrRange(port) {
+			return service.NewValidationError(
+				field+".destinationPorts",
+				fmt.Sprintf(`invalid destination port: "%s"`, port),
+			)
+		}
+	}
+	return nil
+}
+
+func validateApplicationRule(field string, rule Rule) error {
+	if len(rule.Protocols) == 0 {
+		return service.NewValidationError(
+			field+".protocols",
+			"at least one protocol must be specified",
+		)
+	}
+	for _, protocol := range rule.Protocols {
+		if _, _, ok := parseApplicationProtocol(protocol); !ok {
+			return service.NewValidationError(
+				field+".protocols",
+				fmt.Sprintf(
+					`invalid protocol: "%s"; it must be given as protocol:port, `+
+						"where the protocol is one of: %s",
+					protocol,
+					strings.Join(applicationProtocols, ", "),
+				),
+			)
+		}
+	}
+	if len(rule.TargetFQDNs) == 0 {
+		return service.NewValidationError(
+			field+".targetFqdns",
+			"at least one target FQDN must be specified",
+		)
+	}
+	for _, fqdn := range rule.TargetFQDNs {
+		if fqdn != "*" && !fqdnRegex.MatchString(fqdn) {
+			return service.NewValidationError(
+				field+".targetFqdns",
+				fmt.Sprintf(`invalid target FQDN: "%s"`, fqdn),
+			)
+		}
+	}
+	return nil
+}
+
+func validateNATRule(field string, rule Rule) error {
+	if err := validateProtocols(field, rule.Protocols, natProtocols); err != nil {
+		return err
+	}
+	if len(rule.DestinationAddresses) != 1 ||
+		net.ParseIP(rule.DestinationAddresses[0]) == nil {
+		return service.NewValidationError(
+			field+".destinationAddresses",
+			"a NAT rule's destination must be a single IP address, the "+
+				"firewall's public IP address",
+		)
+	}
+	if len(rule.DestinationPorts) != 1 ||
+		!isValidPort(rule.DestinationPorts[0]) {
+		return service.NewValidationError(
+			field+".destinationPorts",
+			"a NAT rule's destination must be a single port",
+		)
+	}
+	if net.ParseIP(rule.TranslatedAddress) == nil {
+		return service.NewValidationError(
+			field+".translatedAddress",
+			fmt.Sprintf(
+				`invalid translated address: "%s"; it must be an IP address`,
+				rule.TranslatedAddress,
+			),
+		)
+	}
+	if !isValidPort(rule.TranslatedPort) {
+		return service.NewValidationError(
+			field+".translatedPort",
+			fmt.Sprintf(`invalid translated port: "%s"`, rule.TranslatedPort),
+		)
+	}
+	return nil
+}
+
+func validateName(field string, name string, names map[string]bool) error {
+	if !nameRegex.MatchString(name) {
+		return service.NewValidationError(
+			field+".name",
+			fmt.Sprintf(`invalid name: "%s"`, name),
+		)
+	}
+	if names[name] {
+		return service.NewValidationError(
+			field+".name",
+			fmt.Sprintf(`name "%s" is used more than once`, name),
+		)
+	}
+	names[name] = true
+	return nil
+}
+
+func validateProtocols(
+	field string,
+	protocols []string,
+	allowedProtocols []string,
+) error {
+	if len(protocols) == 0 {
+		return service.NewValidationError(
+			field+".protocols",
+			"at least one protocol must be specified",
+		)
+	}
+	for _, protocol := range protocols {
+		if !contains(allowedProtocols, protocol) {
+			return service.NewValidationError(
+				field+".protocols",
+				fmt.Sprintf(
+					`invalid protocol: "%s"; allowed values are: %s`,
+					protocol,
+					strings.Join(allowedProtocols, ", "),
+				),
+			)
+		}
+	}
+	return nil
+}
+
+// validateAddresses validates that at least one address is given and that each
+// is an IP address, a CIDR range or "*". Service tags are permitted as well if
+// so indicated.
+func validateAddresses(
+	field string,
+	addresses []string,
+	allowServiceTags bool,
+) error {
+	if len(addresses) == 0 {
+		return service.NewValidationError(
+			field,
+			"at least one address must be specified",
+		)
+	}
+	for _, address := range addresses {
+		if address == "*" || net.ParseIP(address) != nil {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(address); err == nil {
+			continue
+		}
+		if allowServiceTags && serviceTagRegex.MatchString(address) {
+			continue
+		}
+		return service.NewValidationError(
+			field,
+			fmt.Sprintf(`invalid address: "%s"`, address),
+		)
+	}
+	return nil
+}
+
+func (s *serviceManager) ApplyProvisioningParametersDefaults(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*firewallpolicy.ProvisioningParameters",
+		)
+	}
+	pp.ThreatIntelMode = getThreatIntelMode(pp)
+	pp.RuleCollectionGroupPriority = getRuleCollectionGroupPriority(pp)
+	return nil
+}
+
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep("deployARMTemplate", s.deployARMTemplate),
+	)
+}
+
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*firewallPolicyInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *firewallPolicyInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*firewallpolicy.ProvisioningParameters",
+		)
+	}
+	dt.ARMDeploymentName = uuid.NewV4().String()
+	dt.PolicyName = "fwp-" + uuid.NewV4().String()
+	dt.RuleCollections = pp.RuleCollections
+	return dt, nil
+}
+
+func (s *serviceManager) deployARMTemplate(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*firewallPolicyInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *firewallPolicyInstanceDetails",
+		)
+	}
+	if err := s.deployPolicy(instance, dt); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+// deployPolicy deploys the policy, along with the rule collection group
+// holding the instance's current rule collections, and records the policy's
+// resource ID. Azure replaces the group's rule collections wholesale, so
+// deploying again is also how rule collections are added and removed.
+func (s *serviceManager) deployPolicy(
+	instance service.Instance,
+	dt *firewallPolicyInstanceDetails,
+) error {
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*firewallpolicy.ProvisioningParameters",
+		)
+	}
+	armParams := buildARMTemplateParameters(pp, dt.RuleCollections)
+	armParams["policyName"] = dt.PolicyName
+	armParams["tier"] = instance.Plan.GetProperties().Extended["policyTier"]
+	outputs, err := s.armDeployer.Deploy(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateBytes,
+		nil,
+		armParams,
+		instance.Tags,
+	)
+	if err != nil {
+		return service.WrapError(err, "error deploying ARM template")
+	}
+	policyID, ok := outputs["policyId"].(string)
+	if !ok {
+		return errors.New(
+			"error retrieving firewall policy resource id from deployment",
+		)
+	}
+	dt.PolicyID = policyID
+	return nil
+}
+
+// buildARMTemplateParameters returns the ARM template parameters used to
+// deploy a policy having the given rule collections
+func buildARMTemplateParameters(
+	pp *ProvisioningParameters,
+	ruleCollections []RuleCollection,
+) map[string]interface{} {
+	armRuleCollections := make([]interface{}, len(ruleCollections))
+	for i, ruleCollection := range ruleCollections {
+		armRuleCollections[i] = buildARMRuleCollection(ruleCollection)
+	}
+	return map[string]interface{}{
+		"threatIntelMode":             getThreatIntelMode(pp),
+		"ruleCollectionGroupPriority": getRuleCollectionGroupPriority(pp),
+		"ruleCollections":             armRuleCollections,
+	}
+}
+
+func buildARMRuleCollection(
+	ruleCollection RuleCollection,
+) map[string]interface{} {
+	rules := make([]interface{}, len(ruleCollection.Rules))
+	for i, rule := range ruleCollection.Rules {
+		switch ruleCollection.Type {
+		case ruleCollectionTypeNetwork:
+			rules[i] = map[string]interface{}{
+				"ruleType":             "NetworkRule",
+				"name":                 rule.Name,
+				"ipProtocols":          rule.Protocols,
+				"sourceAddresses":      rule.SourceAddresses,
+				"destinationAddresses": rule.DestinationAddresses,
+				"destinationPorts":     rule.DestinationPorts,
+			}
+		case ruleCollectionTypeApplication:
+			protocols := make([]interface{}, len(rule.Protocols))
+			for j, protocol := range rule.Protocols {
+				protocolType, port, _ := parseApplicationProtocol(protocol)
+				protocols[j] = map[string]interface{}{
+					"protocolType": protocolType,
+					"port":         port,
+				}
+			}
+			rules[i] = map[string]interface{}{
+				"ruleType":        "ApplicationRule",
+				"name":            rule.Name,
+				"protocols":       protocols,
+				"sourceAddresses": rule.SourceAddresses,
+				"targetFqdns":     rule.TargetFQDNs,
+			}
+		case ruleCollectionTypeNAT:
+			rules[i] = map[string]interface{}{
+				"ruleType":             "NatRule",
+				"name":                 rule.Name,
+				"ipProtocols":          rule.Protocols,
+				"sourceAddresses":      rule.SourceAddresses,
+				"destinationAddresses": rule.DestinationAddresses,
+				"destinationPorts":     rule.DestinationPorts,
+				"translatedAddress":    rule.TranslatedAddress,
+				"translatedPort":       rule.TranslatedPort,
+			}
+		}
+	}
+	if ruleCollection.Type == ruleCollectionTypeNAT {
+		return map[string]interface{}{
+			"ruleCollectionType": "FirewallPolicyNatRuleCollection",
+			"name":               ruleCollection.Name,
+			"priority":           ruleCollection.Priority,
+			"action": map[string]interface{}{
+				"type": "DNAT",
+			},
+			"rules": rules,
+		}
+	}
+	return map[string]interface{}{
+		"ruleCollectionType": "FirewallPolicyFilterRuleCollection",
+		"name":               ruleCollection.Name,
+		"priority":           ruleCollection.Priority,
+		"action": map[string]interface{}{
+			"type": ruleCollection.Action,
+		},
+		"rules": rules,
+	}
+}
+
+func getThreatIntelMode(pp *ProvisioningParameters) string {
+	if pp.ThreatIntelMode == "" {
+		return defaultThreatIntelMode
+	}
+	return pp.ThreatIntelMode
+}
+
+func getRuleCollectionGroupPriority(pp *ProvisioningParameters) int {
+	if pp.RuleCollectionGroupPriority == 0 {
+		return defaultRuleCollectionGroupPriority
+	}
+	return pp.RuleCollectionGroupPriority
+}
+
+// parseApplicationProtocol parses an application rule's protocol, given as
+// protocol:port (e.g. "Https:443"). It returns a bool indicating whether the
+// protocol was well-formed.
+func parseApplicationProtocol(protocol string) (string, int, bool) {
+	tokens := strings.Split(protocol, ":")
+	if len(tokens) != 2 || !contains(applicationProtocols, tokens[0]) {
+		return "", 0, false
+	}
+	port, err := strconv.Atoi(tokens[1])
+	if err != nil || port < 1 || port > 65535 {
+		return "", 0, false
+	}
+	return tokens[0], port, true
+}
+
+func isValidPriority(priority int) bool {
+	return priority >= minPriority && priority <= maxPriority
+}
+
+func isValidPort(port string) bool {
+	p, err := strconv.Atoi(port)
+	return err == nil && p >= 1 && p <= 65535
+}
+
+// isValidPortOrRange returns a bool indicating whether the given string is a
+// port, a range of ports (e.g. "8000-8999") or "*"
+func isValidPortOrRange(port string) bool {
+	if port == "*" {
+		return true
+	}
+	tokens := strings.Split(port, "-")
+	if len(tokens) != 2 {
+		return isValidPort(port)
+	}
+	if !isValidPort(tokens[0]) || !isValidPort(tokens[1]) {
+		return false
+	}
+	low, _ := strconv.Atoi(tokens[0])
+	high, _ := strconv.Atoi(tokens[1])
+	return low <= high
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
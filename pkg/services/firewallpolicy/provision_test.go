@@ -0,0 +1,254 @@
+package firewallpolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getTestNetworkRuleCollection() RuleCollection {
+	return RuleCollection{
+		Name:     "allow-dns",
+		Priority: 100,
+		Type:     "network",
+		Action:   "Allow",
+		Rules: []Rule{
+			{
+				Name:                 "dns",
+				SourceAddresses:      []string{"10.0.0.0/16"},
+				DestinationAddresses: []string{"AzureCloud", "168.63.129.16"},
+				DestinationPorts:     []string{"53"},
+				Protocols:            []string{"UDP", "TCP"},
+			},
+		},
+	}
+}
+
+func TestValidateProvisioningParametersWithInvalidThreatIntelMode(
+	t *testing.T,
+) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		ThreatIntelMode: "Block",
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.ThreatIntelMode = "Deny"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidPriority(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		RuleCollectionGroupPriority: 99,
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.RuleCollectionGroupPriority = 300
+	ruleCollection := getTestNetworkRuleCollection()
+	ruleCollection.Priority = 65001
+	pp.RuleCollections = []RuleCollection{ruleCollection}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.RuleCollections[0].Priority = 65000
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithDuplicateNames(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		RuleCollections: []RuleCollection{
+			getTestNetworkRuleCollection(),
+			getTestNetworkRuleCollection(),
+		},
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.RuleCollections[1].Name = "allow-dns-too"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+	pp.RuleCollections[1].Rules = append(
+		pp.RuleCollections[1].Rules,
+		pp.RuleCollections[1].Rules[0],
+	)
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidNetworkRule(t *testing.T) {
+	m := &module{}
+	ruleCollection := getTestNetworkRuleCollection()
+	pp := &ProvisioningParameters{
+		RuleCollections: []RuleCollection{ruleCollection},
+	}
+	rule := &pp.RuleCollections[0].Rules[0]
+	rule.Protocols = []string{"HTTP"}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	rule.Protocols = []string{"Any"}
+	rule.SourceAddresses = []string{"AzureCloud"}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	rule.SourceAddresses = []string{"*"}
+	rule.DestinationPorts = []string{"9000-8000"}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	rule.DestinationPorts = []string{"8000-9000", "*", "443"}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+	pp.RuleCollections[0].Action = "Alert"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidApplicationRule(
+	t *testing.T,
+) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		RuleCollections: []RuleCollection{
+			{
+				Name:     "allow-web",
+				Priority: 200,
+				Type:     "application",
+				Action:   "Allow",
+				Rules: []Rule{
+					{
+						Name:            "microsoft",
+						SourceAddresses: []string{"10.0.0.0/16"},
+						Protocols:       []string{"Https"},
+						TargetFQDNs:     []string{"*.microsoft.com"},
+					},
+				},
+			},
+		},
+	}
+	rule := &pp.RuleCollections[0].Rules[0]
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	rule.Protocols = []string{"Https:443", "Http:80"}
+	rule.TargetFQDNs = []string{"microsoft..com"}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	rule.TargetFQDNs = []string{"*.microsoft.com", "bing.com"}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidNATRule(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		RuleCollections: []RuleCollection{
+			{
+				Name:     "inbound-ssh",
+				Priority: 300,
+				Type:     "nat",
+				Action:   "Allow",
+				Rules: []Rule{
+					{
+						Name:                 "ssh",
+						SourceAddresses:      []string{"*"},
+						DestinationAddresses: []string{"20.1.2.3"},
+						DestinationPorts:     []string{"2222"},
+						Protocols:            []string{"TCP"},
+						TranslatedAddress:    "10.0.1.4",
+						TranslatedPort:       "22",
+					},
+				},
+			},
+		},
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.RuleCollections[0].Action = ""
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+	rule := &pp.RuleCollections[0].Rules[0]
+	rule.DestinationPorts = []string{"2222-2223"}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	rule.DestinationPorts = []string{"2222"}
+	rule.TranslatedAddress = "vm.internal"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+}
+
+func TestBuildARMTemplateParameters(t *testing.T) {
+	pp := &ProvisioningParameters{}
+	ruleCollections := []RuleCollection{
+		getTestNetworkRuleCollection(),
+		{
+			Name:     "allow-web",
+			Priority: 200,
+			Type:     "application",
+			Action:   "Deny",
+			Rules: []Rule{
+				{
+					Name:            "bing",
+					SourceAddresses: []string{"*"},
+					Protocols:       []string{"Https:443"},
+					TargetFQDNs:     []string{"bing.com"},
+				},
+			},
+		},
+		{
+			Name:     "inbound-ssh",
+			Priority: 300,
+			Type:     "nat",
+			Rules: []Rule{
+				{
+					Name:                 "ssh",
+					SourceAddresses:      []string{"*"},
+					DestinationAddresses: []string{"20.1.2.3"},
+					DestinationPorts:     []string{"2222"},
+					Protocols:            []string{"TCP"},
+					TranslatedAddress:    "10.0.1.4",
+					TranslatedPort:       "22",
+				},
+			},
+		},
+	}
+	params := buildARMTemplateParameters(pp, ruleCollections)
+	assert.Equal(t, "Alert", params["threatIntelMode"])
+	assert.Equal(t, 200, params["ruleCollectionGroupPriority"])
+	armRuleCollections, ok := params["ruleCollections"].([]interface{})
+	assert.True(t, ok)
+	assert.Len(t, armRuleCollections, 3)
+
+	network := armRuleCollections[0].(map[string]interface{})
+	assert.Equal(
+		t,
+		"FirewallPolicyFilterRuleCollection",
+		network["ruleCollectionType"],
+	)
+	assert.Equal(
+		t,
+		map[string]interface{}{"type": "Allow"},
+		network["action"],
+	)
+	networkRule := network["rules"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "NetworkRule", networkRule["ruleType"])
+	assert.Equal(t, []string{"UDP", "TCP"}, networkRule["ipProtocols"])
+
+	application := armRuleCollections[1].(map[string]interface{})
+	applicationRule :=
+		application["rules"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "ApplicationRule", applicationRule["ruleType"])
+	assert.Equal(
+		t,
+		[]interface{}{
+			map[string]interface{}{"protocolType": "Https", "port": 443},
+		},
+		applicationRule["protocols"],
+	)
+
+	nat := armRuleCollections[2].(map[string]interface{})
+	assert.Equal(t, "FirewallPolicyNatRuleCollection", nat["ruleCollectionType"])
+	assert.Equal(t, map[string]interface{}{"type": "DNAT"}, nat["action"])
+	natRule := nat["rules"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "NatRule", natRule["ruleType"])
+	assert.Equal(t, "10.0.1.4", natRule["translatedAddress"])
+	assert.Equal(t, "22", natRule["translatedPort"])
+}
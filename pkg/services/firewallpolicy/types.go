@@ -0,0 +1,110 @@
+package firewallpolicy
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// ProvisioningParameters encapsulates Firewall Manager policy-specific
+// provisioning options
+type ProvisioningParameters struct {
+	// ThreatIntelMode is "Off", "Alert" or "Deny"
+	ThreatIntelMode string `json:"threatIntelMode"`
+	// RuleCollectionGroupPriority orders the group holding the broker's rule
+	// collections among any other groups added to the policy
+	RuleCollectionGroupPriority int              `json:"ruleCollectionGroupPriority"` // nolint: lll
+	RuleCollections             []RuleCollection `json:"ruleCollections"`
+}
+
+// RuleCollection is a named, prioritized set of rules of a single type that
+// all share the same action
+type RuleCollection struct {
+	Name     string `json:"name"`
+	Priority int    `json:"priority"`
+	// Type is "network", "application" or "nat"
+	Type string `json:"type"`
+	// Action is "Allow" or "Deny". NAT rules always translate the traffic they
+	// match, so NAT rule collections have no action.
+	Action string `json:"action"`
+	Rules  []Rule `json:"rules"`
+}
+
+// Rule matches traffic by its source and destination. Which fields apply
+// depends on the type of the rule's collection.
+type Rule struct {
+	Name            string   `json:"name"`
+	SourceAddresses []string `json:"sourceAddresses"`
+	// DestinationAddresses apply to network and NAT rules. A NAT rule has one,
+	// the firewall's public IP address.
+	DestinationAddresses []string `json:"destinationAddresses"`
+	// DestinationPorts apply to network and NAT rules. A NAT rule has one.
+	DestinationPorts []string `json:"destinationPorts"`
+	// Protocols are "TCP", "UDP", "ICMP" or "Any" for network rules and "TCP"
+	// or "UDP" for NAT rules. Application rules give each protocol with its
+	// port, e.g. "Https:443".
+	Protocols []string `json:"protocols"`
+	// TargetFQDNs apply to application rules alone
+	TargetFQDNs []string `json:"targetFqdns"`
+	// TranslatedAddress and TranslatedPort apply to NAT rules alone
+	TranslatedAddress string `json:"translatedAddress"`
+	TranslatedPort    string `json:"translatedPort"`
+}
+
+type firewallPolicyInstanceDetails struct {
+	ARMDeploymentName string `json:"armDeployment"`
+	PolicyName        string `json:"policyName"`
+	PolicyID          string `json:"policyId"`
+	// RuleCollections are the policy's current rule collections, which
+	// updating may add to or remove from
+	RuleCollections []RuleCollection `json:"ruleCollections"`
+}
+
+// UpdatingParameters encapsulates Firewall Manager policy-specific updating
+// options
+type UpdatingParameters struct {
+	// AddRuleCollections are added to the policy, each replacing any of the
+	// policy's rule collections having the same name
+	AddRuleCollections []RuleCollection `json:"addRuleCollections"`
+	// RemoveRuleCollections names rule collections to remove from the policy
+	RemoveRuleCollections []string `json:"removeRuleCollections"`
+}
+
+// BindingParameters encapsulates Firewall Manager policy-specific binding
+// options
+type BindingParameters struct {
+}
+
+type firewallPolicyBindingDetails struct {
+}
+
+// Credentials encapsulates the details firewalls need in order to be
+// associated with the policy
+type Credentials struct {
+	PolicyID   string `json:"policyId"`
+	PolicyName string `json:"policyName"`
+	// Tier is the tier of the firewalls the policy can be associated with
+	Tier string `json:"tier"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &firewallPolicyInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &firewallPolicyBindingDetails{}
+}
@@ -0,0 +1,105 @@
+package firewallpolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) ValidateUpdatingParameters(
+	updatingParameters service.UpdatingParameters,
+) error {
+	up, ok := updatingParameters.(*UpdatingParameters)
+	if !ok {
+		return errors.New(
+			"error casting updatingParameters as " +
+				"*firewallpolicy.UpdatingParameters",
+		)
+	}
+	if err := validateRuleCollections(
+		"addRuleCollections",
+		up.AddRuleCollections,
+	); err != nil {
+		return err
+	}
+	added := map[string]bool{}
+	for _, ruleCollection := range up.AddRuleCollections {
+		added[ruleCollection.Name] = true
+	}
+	for i, name := range up.RemoveRuleCollections {
+		field := fmt.Sprintf("removeRuleCollections[%d]", i)
+		if name == "" {
+			return service.NewValidationError(
+				field,
+				"the name of the rule collection to remove must be specified",
+			)
+		}
+		if added[name] {
+			return service.NewValidationError(
+				field,
+				fmt.Sprintf(
+					`rule collection "%s" cannot be both added and removed`,
+					name,
+				),
+			)
+		}
+	}
+	return nil
+}
+
+func (s *serviceManager) GetUpdater(service.Plan) (service.Updater, error) {
+	return service.NewUpdater(
+		service.NewUpdatingStep("updateRuleCollections", s.updateRuleCollections),
+	)
+}
+
+// updateRuleCollections redeploys the policy with the requested rule
+// collections added and removed. Removing a rule collection the policy doesn't
+// have is a no-op.
+func (s *serviceManager) updateRuleCollections(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*firewallPolicyInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *firewallPolicyInstanceDetails",
+		)
+	}
+	up, ok := instance.UpdatingParameters.(*UpdatingParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.UpdatingParameters as " +
+				"*firewallpolicy.UpdatingParameters",
+		)
+	}
+	dt.RuleCollections = mergeRuleCollections(dt.RuleCollections, up)
+	if err := s.deployPolicy(instance, dt); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+// mergeRuleCollections returns the given rule collections less those the
+// updating parameters remove or replace, followed by those they add
+func mergeRuleCollections(
+	ruleCollections []RuleCollection,
+	up *UpdatingParameters,
+) []RuleCollection {
+	dropped := map[string]bool{}
+	for _, name := range up.RemoveRuleCollections {
+		dropped[name] = true
+	}
+	for _, ruleCollection := range up.AddRuleCollections {
+		dropped[ruleCollection.Name] = true
+	}
+	merged := []RuleCollection{}
+	for _, ruleCollection := range ruleCollections {
+		if !dropped[ruleCollection.Name] {
+			merged = append(merged, ruleCollection)
+		}
+	}
+	return append(merged, up.AddRuleCollections...)
+}
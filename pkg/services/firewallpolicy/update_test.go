@@ -0,0 +1,50 @@
+package firewallpolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateUpdatingParameters(t *testing.T) {
+	m := &module{}
+	up := &UpdatingParameters{
+		AddRuleCollections:    []RuleCollection{getTestNetworkRuleCollection()},
+		RemoveRuleCollections: []string{"allow-dns"},
+	}
+	err := m.serviceManager.ValidateUpdatingParameters(up)
+	assert.NotNil(t, err)
+	up.RemoveRuleCollections = []string{""}
+	err = m.serviceManager.ValidateUpdatingParameters(up)
+	assert.NotNil(t, err)
+	up.RemoveRuleCollections = []string{"allow-web"}
+	err = m.serviceManager.ValidateUpdatingParameters(up)
+	assert.Nil(t, err)
+	up.AddRuleCollections[0].Rules = nil
+	err = m.serviceManager.ValidateUpdatingParameters(up)
+	assert.NotNil(t, err)
+}
+
+func TestMergeRuleCollections(t *testing.T) {
+	ruleCollections := []RuleCollection{
+		{Name: "allow-dns", Priority: 100},
+		{Name: "allow-web", Priority: 200},
+		{Name: "inbound-ssh", Priority: 300},
+	}
+	up := &UpdatingParameters{
+		AddRuleCollections: []RuleCollection{
+			{Name: "allow-dns", Priority: 150},
+			{Name: "allow-ntp", Priority: 400},
+		},
+		RemoveRuleCollections: []string{"allow-web", "allow-ftp"},
+	}
+	assert.Equal(
+		t,
+		[]RuleCollection{
+			{Name: "inbound-ssh", Priority: 300},
+			{Name: "allow-dns", Priority: 150},
+			{Name: "allow-ntp", Priority: 400},
+		},
+		mergeRuleCollections(ruleCollections, up),
+	)
+}
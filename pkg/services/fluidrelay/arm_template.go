@@ -0,0 +1,59 @@
+package fluidrelay
+
+// nolint: lll
+var armTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "serverName": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of the Fluid Relay server"
+      }
+    },
+    "storageSku": {
+      "type": "string"
+    },
+    "tags": {
+      "type": "object"
+    }
+  },
+  "variables": {
+    "apiVersion": "2022-06-01"
+  },
+  "resources": [
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[parameters('serverName')]",
+      "type": "Microsoft.FluidRelay/fluidRelayServers",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "properties": {
+        "storagesku": "[parameters('storageSku')]"
+      }
+    }
+  ],
+  "outputs": {
+    "tenantId": {
+      "type": "string",
+      "value": "[reference(parameters('serverName')).frsTenantId]"
+    },
+    "ordererEndpoint": {
+      "type": "string",
+      "value": "[first(reference(parameters('serverName')).fluidRelayEndpoints.ordererEndpoints)]"
+    },
+    "storageEndpoint": {
+      "type": "string",
+      "value": "[first(reference(parameters('serverName')).fluidRelayEndpoints.storageEndpoints)]"
+    },
+    "primaryKey": {
+      "type": "string",
+      "value": "[listKeys(resourceId('Microsoft.FluidRelay/fluidRelayServers', parameters('serverName')), variables('apiVersion')).key1]"
+    }
+  }
+}
+`)
@@ -0,0 +1,63 @@
+package fluidrelay
+
+import (
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) ValidateBindingParameters(
+	service.BindingParameters,
+) error {
+	// There are no parameters for binding to Fluid Relay, so there is nothing
+	// to validate
+	return nil
+}
+
+// Bind hands out the server's key2, which every binding shares. The server's
+// key1 is reserved for the broker's own use.
+func (s *serviceManager) Bind(
+	instance service.Instance,
+	_ service.BindingParameters,
+) (service.BindingDetails, error) {
+	dt, ok := instance.Details.(*fluidRelayInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *fluidRelayInstanceDetails",
+		)
+	}
+	keys, err := s.fluidRelayManager.GetKeys(
+		dt.ServerName,
+		instance.ResourceGroup,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &fluidRelayBindingDetails{
+		Key: keys.Key2,
+	}, nil
+}
+
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	binding service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*fluidRelayInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *fluidRelayInstanceDetails",
+		)
+	}
+	bd, ok := binding.Details.(*fluidRelayBindingDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting binding.Details as *fluidRelayBindingDetails",
+		)
+	}
+	return &Credentials{
+		TenantID:        dt.TenantID,
+		OrdererEndpoint: dt.OrdererEndpoint,
+		StorageEndpoint: dt.StorageEndpoint,
+		Key:             bd.Key,
+	}, nil
+}
@@ -0,0 +1,41 @@
+package fluidrelay
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:          "45ced992-d43b-4be8-bb50-56e0910c44c7",
+				Name:        "azure-fluid-relay",
+				Description: "Azure Fluid Relay (Experimental)",
+				Bindable:    true,
+				Tags:        []string{"Azure", "Fluid Relay", "Collaboration"},
+				// Azure Fluid Relay is only offered in some regions
+				Locations: []string{
+					"australiaeast",
+					"brazilsouth",
+					"centralindia",
+					"eastus",
+					"eastus2",
+					"japaneast",
+					"koreacentral",
+					"northeurope",
+					"southcentralus",
+					"southeastasia",
+					"uksouth",
+					"westeurope",
+					"westus2",
+					"westus3",
+				},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:          "e137b7e8-4d8a-4d99-a615-b569a8e0d351",
+				Name:        "standard",
+				Description: "Pay-as-you-go, billed per operation and by storage",
+				Free:        false,
+			}),
+		),
+	}), nil
+}
@@ -0,0 +1,42 @@
+package fluidrelay
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/fluidrelay"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer       arm.Deployer
+	fluidRelayManager fluidrelay.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of provisioning Azure Fluid Relay servers
+func New(
+	armDeployer arm.Deployer,
+	fluidRelayManager fluidrelay.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer:       armDeployer,
+			fluidRelayManager: fluidRelayManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "fluidrelay"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{"Microsoft.FluidRelay"}
+}
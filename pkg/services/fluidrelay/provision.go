@@ -0,0 +1,145 @@
+package fluidrelay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	storageSKUStandard = "standard"
+	storageSKUBasic    = "basic"
+)
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*fluidrelay.ProvisioningParameters",
+		)
+	}
+	if pp.StorageSKU != "" &&
+		pp.StorageSKU != storageSKUStandard &&
+		pp.StorageSKU != storageSKUBasic {
+		return service.NewValidationError(
+			"storageSku",
+			fmt.Sprintf(
+				`invalid storageSku: "%s"; allowed values are: %s, %s`,
+				pp.StorageSKU,
+				storageSKUStandard,
+				storageSKUBasic,
+			),
+		)
+	}
+	return nil
+}
+
+func (s *serviceManager) ApplyProvisioningParametersDefaults(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*fluidrelay.ProvisioningParameters",
+		)
+	}
+	pp.StorageSKU = getStorageSKU(pp)
+	return nil
+}
+
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep("deployARMTemplate", s.deployARMTemplate),
+	)
+}
+
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*fluidRelayInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *fluidRelayInstanceDetails",
+		)
+	}
+	dt.ARMDeploymentName = uuid.NewV4().String()
+	dt.ServerName = "frs-" + uuid.NewV4().String()
+	return dt, nil
+}
+
+func (s *serviceManager) deployARMTemplate(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*fluidRelayInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *fluidRelayInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*fluidrelay.ProvisioningParameters",
+		)
+	}
+	outputs, err := s.armDeployer.Deploy(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateBytes,
+		nil, // Go template params
+		map[string]interface{}{ // ARM template params
+			"serverName": dt.ServerName,
+			"storageSku": getStorageSKU(pp),
+		},
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+	tenantID, ok := outputs["tenantId"].(string)
+	if !ok {
+		return nil, errors.New("error retrieving tenant id from deployment")
+	}
+	dt.TenantID = tenantID
+	ordererEndpoint, ok := outputs["ordererEndpoint"].(string)
+	if !ok {
+		return nil, errors.New(
+			"error retrieving orderer endpoint from deployment",
+		)
+	}
+	dt.OrdererEndpoint = ordererEndpoint
+	storageEndpoint, ok := outputs["storageEndpoint"].(string)
+	if !ok {
+		return nil, errors.New(
+			"error retrieving storage endpoint from deployment",
+		)
+	}
+	dt.StorageEndpoint = storageEndpoint
+	primaryKey, ok := outputs["primaryKey"].(string)
+	if !ok {
+		return nil, errors.New("error retrieving primary key from deployment")
+	}
+	dt.PrimaryKey = primaryKey
+	return dt, nil
+}
+
+func getStorageSKU(pp *ProvisioningParameters) string {
+	if pp.StorageSKU == "" {
+		return storageSKUStandard
+	}
+	return pp.StorageSKU
+}
@@ -0,0 +1,71 @@
+package fluidrelay
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// ProvisioningParameters encapsulates Azure Fluid Relay-specific provisioning
+// options
+type ProvisioningParameters struct {
+	// StorageSKU is either "standard" or "basic". It cannot be changed once
+	// the server has been provisioned.
+	StorageSKU string `json:"storageSku"`
+}
+
+type fluidRelayInstanceDetails struct {
+	ARMDeploymentName string `json:"armDeployment"`
+	ServerName        string `json:"serverName"`
+	// TenantID is the server's Fluid Relay tenant ID, which clients present
+	// along with a token signed using one of the server's keys. It is
+	// unrelated to any Azure Active Directory tenant.
+	TenantID        string `json:"tenantId"`
+	OrdererEndpoint string `json:"ordererEndpoint"`
+	StorageEndpoint string `json:"storageEndpoint"`
+	// PrimaryKey is the server's key1, which is never handed out to bindings
+	PrimaryKey string `json:"primaryKey" secret:"true"`
+}
+
+// UpdatingParameters encapsulates Azure Fluid Relay-specific updating options
+type UpdatingParameters struct {
+}
+
+// BindingParameters encapsulates Azure Fluid Relay-specific binding options
+type BindingParameters struct {
+}
+
+type fluidRelayBindingDetails struct {
+	Key string `json:"key" secret:"true"`
+}
+
+// Credentials encapsulates Azure Fluid Relay-specific connection details
+type Credentials struct {
+	TenantID        string `json:"tenantId"`
+	OrdererEndpoint string `json:"ordererEndpoint"`
+	StorageEndpoint string `json:"storageEndpoint"`
+	// Key is used to sign the tokens clients present to the server
+	Key string `json:"key"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &fluidRelayInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &fluidRelayBindingDetails{}
+}
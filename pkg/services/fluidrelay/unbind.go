@@ -0,0 +1,14 @@
+package fluidrelay
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// Unbind does nothing. Every binding shares the server's key2, which is left
+// as it is so that the credentials of other bindings remain valid.
+func (s *serviceManager) Unbind(
+	service.Instance,
+	service.BindingDetails,
+) error {
+	return nil
+}
@@ -0,0 +1,66 @@
+package iotdps
+
+// nolint: lll
+var armTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "serviceName": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of the Device Provisioning Service"
+      }
+    },
+    "allocationPolicy": {
+      "type": "string"
+    },
+    "iotHubs": {
+      "type": "secureObject",
+      "metadata": {
+        "description": "The linked IoT hubs, whose connection strings mustn't be recorded in the deployment"
+      }
+    },
+    "tags": {
+      "type": "object"
+    }
+  },
+  "variables": {
+    "apiVersion": "2022-02-05"
+  },
+  "resources": [
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[parameters('serviceName')]",
+      "type": "Microsoft.Devices/provisioningServices",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "sku": {
+        "name": "S1",
+        "capacity": 1
+      },
+      "properties": {
+        "allocationPolicy": "[parameters('allocationPolicy')]",
+        "iotHubs": "[parameters('iotHubs').hubs]"
+      }
+    }
+  ],
+  "outputs": {
+    "idScope": {
+      "type": "string",
+      "value": "[reference(parameters('serviceName'), variables('apiVersion')).idScope]"
+    },
+    "serviceOperationsHostName": {
+      "type": "string",
+      "value": "[reference(parameters('serviceName'), variables('apiVersion')).serviceOperationsHostName]"
+    },
+    "deviceProvisioningHostName": {
+      "type": "string",
+      "value": "[reference(parameters('serviceName'), variables('apiVersion')).deviceProvisioningHostName]"
+    }
+  }
+}
+`)
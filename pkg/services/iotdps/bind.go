@@ -0,0 +1,128 @@
+package iotdps
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const globalDeviceEndpoint = "global.azure-devices-provisioning.net"
+
+// rights are those that a DPS's shared access policies may grant
+var rights = []string{
+	"ServiceConfig",
+	"EnrollmentRead",
+	"EnrollmentWrite",
+	"DeviceConnect",
+	"RegistrationStatusRead",
+	"RegistrationStatusWrite",
+}
+
+// defaultRights permit a binding to read, but not change, the DPS's
+// enrollments and device registrations
+var defaultRights = []string{"EnrollmentRead", "RegistrationStatusRead"}
+
+func (s *serviceManager) ValidateBindingParameters(
+	bindingParameters service.BindingParameters,
+) error {
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return errors.New(
+			"error casting bindingParameters as *iotdps.BindingParameters",
+		)
+	}
+	for _, right := range bp.Rights {
+		if !isRight(right) {
+			return service.NewValidationError(
+				"rights",
+				fmt.Sprintf(
+					`invalid right: "%s"; allowed values are: %s`,
+					right,
+					strings.Join(rights, ", "),
+				),
+			)
+		}
+	}
+	return nil
+}
+
+// Bind creates a shared access policy of the DPS for the binding alone, so
+// that unbinding revokes the binding's access without affecting any other's
+func (s *serviceManager) Bind(
+	instance service.Instance,
+	bindingParameters service.BindingParameters,
+) (service.BindingDetails, error) {
+	dt, ok := instance.Details.(*dpsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *dpsInstanceDetails",
+		)
+	}
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting bindingParameters as *iotdps.BindingParameters",
+		)
+	}
+	policyRights := bp.Rights
+	if len(policyRights) == 0 {
+		policyRights = defaultRights
+	}
+	keyName := "binding-" + uuid.NewV4().String()
+	keys, err := s.dpsManager.CreateAccessPolicy(
+		dt.ServiceName,
+		instance.ResourceGroup,
+		keyName,
+		policyRights,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &dpsBindingDetails{
+		KeyName:    keyName,
+		PrimaryKey: keys.PrimaryKey,
+	}, nil
+}
+
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	binding service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*dpsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *dpsInstanceDetails",
+		)
+	}
+	bd, ok := binding.Details.(*dpsBindingDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting binding.Details as *dpsBindingDetails",
+		)
+	}
+	return &Credentials{
+		IDScope:                   dt.IDScope,
+		GlobalDeviceEndpoint:      globalDeviceEndpoint,
+		ServiceOperationsHostName: dt.ServiceOperationsHostName,
+		KeyName:                   bd.KeyName,
+		Key:                       bd.PrimaryKey,
+		ConnectionString: fmt.Sprintf(
+			"HostName=%s;SharedAccessKeyName=%s;SharedAccessKey=%s",
+			dt.ServiceOperationsHostName,
+			bd.KeyName,
+			bd.PrimaryKey,
+		),
+	}, nil
+}
+
+func isRight(right string) bool {
+	for _, r := range rights {
+		if r == right {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package iotdps
+
+import (
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateBindingParameters(t *testing.T) {
+	m := &module{}
+	bp := &BindingParameters{}
+	err := m.serviceManager.ValidateBindingParameters(bp)
+	assert.Nil(t, err)
+	bp.Rights = []string{"EnrollmentWrite", "RegistrationStatusWrite"}
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.Nil(t, err)
+	bp.Rights = []string{"RegistryWrite"}
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.NotNil(t, err)
+}
+
+func TestGetCredentials(t *testing.T) {
+	m := &module{}
+	creds, err := m.serviceManager.GetCredentials(
+		service.Instance{
+			Details: &dpsInstanceDetails{
+				IDScope:                   "0ne00000001",
+				ServiceOperationsHostName: "dps.azure-devices-provisioning.net",
+			},
+		},
+		service.Binding{
+			Details: &dpsBindingDetails{
+				KeyName:    "binding-1",
+				PrimaryKey: "key",
+			},
+		},
+	)
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		&Credentials{
+			IDScope:                   "0ne00000001",
+			GlobalDeviceEndpoint:      globalDeviceEndpoint,
+			ServiceOperationsHostName: "dps.azure-devices-provisioning.net",
+			KeyName:                   "binding-1",
+			Key:                       "key",
+			ConnectionString: "HostName=dps.azure-devices-provisioning.net;" +
+				"SharedAccessKeyName=binding-1;SharedAccessKey=key",
+		},
+		creds,
+	)
+}
@@ -0,0 +1,28 @@
+package iotdps
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:          "ee64c01a-f7f9-4587-aea7-827445139817",
+				Name:        "azure-iot-dps",
+				Description: "Azure IoT Hub Device Provisioning Service (Experimental)",
+				Bindable:    true,
+				Tags: []string{
+					"Azure",
+					"IoT",
+					"Device Provisioning Service",
+				},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:          "6eba6087-fbd9-49bd-97b1-73aaa689497c",
+				Name:        "standard",
+				Description: "S1 tier, billed per device registration operation",
+				Free:        false,
+			}),
+		),
+	}), nil
+}
@@ -0,0 +1,59 @@
+package iotdps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) GetDeprovisioner(
+	service.Plan,
+) (service.Deprovisioner, error) {
+	return service.NewDeprovisioner(
+		service.NewDeprovisioningStep("deleteARMDeployment", s.deleteARMDeployment),
+		service.NewDeprovisioningStep("deleteService", s.deleteService),
+	)
+}
+
+func (s *serviceManager) deleteARMDeployment(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*dpsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *dpsInstanceDetails",
+		)
+	}
+	if err := s.armDeployer.Delete(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, fmt.Errorf("error deleting ARM deployment: %s", err)
+	}
+	return dt, nil
+}
+
+// deleteService deletes the DPS, along with its enrollment groups and the
+// registrations of its devices. Devices already assigned to the linked hubs
+// remain registered in them; the hubs themselves are never deleted.
+func (s *serviceManager) deleteService(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*dpsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *dpsInstanceDetails",
+		)
+	}
+	if err := s.dpsManager.DeleteService(
+		dt.ServiceName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
@@ -0,0 +1,43 @@
+package iotdps
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/iotdps"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer arm.Deployer
+	dpsManager  iotdps.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of provisioning Azure IoT Hub Device Provisioning
+// Services
+func New(
+	armDeployer arm.Deployer,
+	dpsManager iotdps.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer: armDeployer,
+			dpsManager:  dpsManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "iotdps"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{"Microsoft.Devices"}
+}
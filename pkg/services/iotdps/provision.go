@@ -0,0 +1,445 @@
+package iotdps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/iotdps"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	allocationPolicyHashed     = "Hashed"
+	allocationPolicyGeoLatency = "GeoLatency"
+	allocationPolicyStatic     = "Static"
+	maxAllocationWeight        = 1000
+)
+
+// allocationPolicies are the allocation policies a DPS, or one of its
+// enrollment groups, may have, mapped to the names the DPS's service API uses
+// for them
+var allocationPolicies = map[string]string{
+	allocationPolicyHashed:     "hashed",
+	allocationPolicyGeoLatency: "geoLatency",
+	allocationPolicyStatic:     "static",
+}
+
+// iotHubIDRegex matches the resource IDs of IoT hubs
+var iotHubIDRegex = regexp.MustCompile(
+	`(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+/providers/` +
+		`Microsoft\.Devices/IotHubs/[^/]+$`,
+)
+
+// enrollmentGroupIDRegex matches the IDs the DPS allows enrollment groups to
+// have
+var enrollmentGroupIDRegex = regexp.MustCompile(
+	`^[a-zA-Z0-9\-._:]{0,127}[a-zA-Z0-9\-]$`,
+)
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*iotdps.ProvisioningParameters",
+		)
+	}
+	if len(pp.IoTHubs) == 0 {
+		return service.NewValidationError(
+			"iotHubs",
+			"at least one IoT hub must be linked",
+		)
+	}
+	allocationPolicy := getAllocationPolicy(pp)
+	if err := validateAllocationPolicy(
+		"allocationPolicy",
+		allocationPolicy,
+	); err != nil {
+		return err
+	}
+	if allocationPolicy == allocationPolicyStatic && len(pp.IoTHubs) != 1 {
+		return service.NewValidationError(
+			"allocationPolicy",
+			fmt.Sprintf(
+				"the %s allocation policy requires exactly one linked IoT hub",
+				allocationPolicyStatic,
+			),
+		)
+	}
+	linkedHubs := map[string]bool{}
+	for i, hub := range pp.IoTHubs {
+		field := fmt.Sprintf("iotHubs[%d]", i)
+		if !iotHubIDRegex.MatchString(hub.ResourceID) {
+			return service.NewValidationError(
+				field+".resourceId",
+				fmt.Sprintf(`invalid IoT hub resource id: "%s"`, hub.ResourceID),
+			)
+		}
+		if linkedHubs[strings.ToLower(hub.ResourceID)] {
+			return service.NewValidationError(
+				field+".resourceId",
+				fmt.Sprintf(`IoT hub "%s" is linked more than once`, hub.ResourceID),
+			)
+		}
+		linkedHubs[strings.ToLower(hub.ResourceID)] = true
+		if hub.AllocationWeight < 0 || hub.AllocationWeight > maxAllocationWeight {
+			return service.NewValidationError(
+				field+".allocationWeight",
+				fmt.Sprintf(
+					"invalid allocationWeight: %d; weights must be between 0 and %d",
+					hub.AllocationWeight,
+					maxAllocationWeight,
+				),
+			)
+		}
+		if hub.AllocationWeight != 0 && allocationPolicy != allocationPolicyHashed {
+			return service.NewValidationError(
+				field+".allocationWeight",
+				fmt.Sprintf(
+					"allocation weights apply only to the %s allocation policy",
+					allocationPolicyHashed,
+				),
+			)
+		}
+	}
+	groupIDs := map[string]bool{}
+	for i, group := range pp.EnrollmentGroups {
+		if err := validateEnrollmentGroup(
+			fmt.Sprintf("enrollmentGroups[%d]", i),
+			group,
+			allocationPolicy,
+			linkedHubs,
+		); err != nil {
+			return err
+		}
+		// Enrollment group IDs are case-insensitive
+		if groupIDs[strings.ToLower(group.ID)] {
+			return service.NewValidationError(
+				fmt.Sprintf("enrollmentGroups[%d].id", i),
+				fmt.Sprintf(`duplicate enrollment group id: "%s"`, group.ID),
+			)
+		}
+		groupIDs[strings.ToLower(group.ID)] = true
+	}
+	return nil
+}
+
+func validateAllocationPolicy(field string, allocationPolicy string) error {
+	if _, ok := allocationPolicies[allocationPolicy]; !ok {
+		return service.NewValidationError(
+			field,
+			fmt.Sprintf(
+				`invalid allocationPolicy: "%s"; allowed values are: %s, %s, %s`,
+				allocationPolicy,
+				allocationPolicyHashed,
+				allocationPolicyGeoLatency,
+				allocationPolicyStatic,
+			),
+		)
+	}
+	return nil
+}
+
+func validateEnrollmentGroup(
+	field string,
+	group EnrollmentGroup,
+	defaultAllocationPolicy string,
+	linkedHubs map[string]bool,
+) error {
+	if !enrollmentGroupIDRegex.MatchString(group.ID) {
+		return service.NewValidationError(
+			field+".id",
+			fmt.Sprintf(
+				`invalid enrollment group id: "%s"; ids must be 1-128 letters, `+
+					"numbers, hyphens, periods, underscores and colons and must end "+
+					"with a letter, number or hyphen",
+				group.ID,
+			),
+		)
+	}
+	allocationPolicy := defaultAllocationPolicy
+	if group.AllocationPolicy != "" {
+		if err := validateAllocationPolicy(
+			field+".allocationPolicy",
+			group.AllocationPolicy,
+		); err != nil {
+			return err
+		}
+		allocationPolicy = group.AllocationPolicy
+	}
+	groupHubs := map[string]bool{}
+	for j, hubID := range group.IoTHubs {
+		if !linkedHubs[strings.ToLower(hubID)] {
+			return service.NewValidationError(
+				fmt.Sprintf("%s.iotHubs[%d]", field, j),
+				fmt.Sprintf(`IoT hub "%s" is not linked to the DPS`, hubID),
+			)
+		}
+		groupHubs[strings.ToLower(hubID)] = true
+	}
+	hubCount := len(groupHubs)
+	if hubCount == 0 {
+		hubCount = len(linkedHubs)
+	}
+	if allocationPolicy == allocationPolicyStatic && hubCount != 1 {
+		return service.NewValidationError(
+			field+".iotHubs",
+			fmt.Sprintf(
+				"the %s allocation policy requires exactly one IoT hub",
+				allocationPolicyStatic,
+			),
+		)
+	}
+	return nil
+}
+
+func (s *serviceManager) ApplyProvisioningParametersDefaults(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*iotdps.ProvisioningParameters",
+		)
+	}
+	pp.AllocationPolicy = getAllocationPolicy(pp)
+	if pp.AllocationPolicy == allocationPolicyHashed {
+		for i := range pp.IoTHubs {
+			pp.IoTHubs[i].AllocationWeight = getAllocationWeight(pp.IoTHubs[i])
+		}
+	}
+	return nil
+}
+
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep("validateIoTHubs", s.validateIoTHubs),
+		service.NewProvisioningStep("deployARMTemplate", s.deployARMTemplate),
+		service.NewProvisioningStep(
+			"createEnrollmentGroups",
+			s.createEnrollmentGroups,
+		),
+	)
+}
+
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*dpsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *dpsInstanceDetails",
+		)
+	}
+	dt.ARMDeploymentName = uuid.NewV4().String()
+	// DPS names may be no longer than 64 characters
+	dt.ServiceName = "dps-" + uuid.NewV4().String()
+	return dt, nil
+}
+
+// validateIoTHubs checks that each of the IoT hubs to be linked exists and
+// records its host name, by which the DPS and its enrollment groups refer to
+// it
+func (s *serviceManager) validateIoTHubs(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*dpsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *dpsInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*iotdps.ProvisioningParameters",
+		)
+	}
+	dt.IoTHubHostNames = map[string]string{}
+	for _, link := range pp.IoTHubs {
+		hub, err := s.getIoTHub(link.ResourceID)
+		if err != nil {
+			return nil, err
+		}
+		dt.IoTHubHostNames[strings.ToLower(link.ResourceID)] = hub.HostName
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deployARMTemplate(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*dpsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *dpsInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*iotdps.ProvisioningParameters",
+		)
+	}
+	// Hubs' connection strings are retrieved afresh rather than recorded in the
+	// instance details, since the broker has no use for them once the hubs are
+	// linked
+	hubs := make([]iotdps.IoTHub, len(pp.IoTHubs))
+	for i, link := range pp.IoTHubs {
+		hub, err := s.getIoTHub(link.ResourceID)
+		if err != nil {
+			return nil, err
+		}
+		hubs[i] = hub
+	}
+	outputs, err := s.armDeployer.Deploy(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateBytes,
+		nil, // Go template params
+		buildARMTemplateParameters(dt, pp, hubs),
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+	idScope, ok := outputs["idScope"].(string)
+	if !ok {
+		return nil, errors.New("error retrieving ID scope from deployment")
+	}
+	dt.IDScope = idScope
+	serviceOperationsHostName, ok :=
+		outputs["serviceOperationsHostName"].(string)
+	if !ok {
+		return nil, errors.New(
+			"error retrieving service operations host name from deployment",
+		)
+	}
+	dt.ServiceOperationsHostName = serviceOperationsHostName
+	deviceProvisioningHostName, ok :=
+		outputs["deviceProvisioningHostName"].(string)
+	if !ok {
+		return nil, errors.New(
+			"error retrieving device provisioning host name from deployment",
+		)
+	}
+	dt.DeviceProvisioningHostName = deviceProvisioningHostName
+	return dt, nil
+}
+
+func (s *serviceManager) createEnrollmentGroups(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*dpsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *dpsInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*iotdps.ProvisioningParameters",
+		)
+	}
+	for _, group := range pp.EnrollmentGroups {
+		if err := s.dpsManager.CreateEnrollmentGroup(
+			dt.ServiceName,
+			instance.ResourceGroup,
+			dt.ServiceOperationsHostName,
+			getEnrollmentGroup(dt, group),
+		); err != nil {
+			return nil, err
+		}
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) getIoTHub(resourceID string) (iotdps.IoTHub, error) {
+	hub, found, err := s.dpsManager.GetIoTHub(resourceID)
+	if err != nil {
+		return hub, err
+	}
+	if !found {
+		return hub, fmt.Errorf(`IoT hub "%s" not found`, resourceID)
+	}
+	return hub, nil
+}
+
+func buildARMTemplateParameters(
+	dt *dpsInstanceDetails,
+	pp *ProvisioningParameters,
+	hubs []iotdps.IoTHub,
+) map[string]interface{} {
+	allocationPolicy := getAllocationPolicy(pp)
+	linkedHubs := make([]map[string]interface{}, len(hubs))
+	for i, hub := range hubs {
+		linkedHub := map[string]interface{}{
+			"connectionString":      hub.ConnectionString,
+			"location":              hub.Location,
+			"applyAllocationPolicy": true,
+		}
+		if allocationPolicy == allocationPolicyHashed {
+			linkedHub["allocationWeight"] = getAllocationWeight(pp.IoTHubs[i])
+		}
+		linkedHubs[i] = linkedHub
+	}
+	return map[string]interface{}{
+		"serviceName":      dt.ServiceName,
+		"allocationPolicy": allocationPolicy,
+		"iotHubs": map[string]interface{}{
+			"hubs": linkedHubs,
+		},
+	}
+}
+
+// getEnrollmentGroup returns the given enrollment group as the DPS's service
+// API describes it
+func getEnrollmentGroup(
+	dt *dpsInstanceDetails,
+	group EnrollmentGroup,
+) iotdps.EnrollmentGroup {
+	hostNames := make([]string, len(group.IoTHubs))
+	for i, hubID := range group.IoTHubs {
+		hostNames[i] = dt.IoTHubHostNames[strings.ToLower(hubID)]
+	}
+	return iotdps.EnrollmentGroup{
+		ID:               group.ID,
+		AllocationPolicy: allocationPolicies[group.AllocationPolicy],
+		IoTHubHostNames:  hostNames,
+	}
+}
+
+func getAllocationPolicy(pp *ProvisioningParameters) string {
+	if pp.AllocationPolicy == "" {
+		return allocationPolicyHashed
+	}
+	return pp.AllocationPolicy
+}
+
+// getAllocationWeight returns the given hub's weight under the Hashed
+// allocation policy. Hubs are weighted equally unless weights are given.
+func getAllocationWeight(link IoTHubLink) int64 {
+	if link.AllocationWeight == 0 {
+		return 1
+	}
+	return link.AllocationWeight
+}
@@ -0,0 +1,159 @@
+package iotdps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testIoTHubID1 = "/subscriptions/sub/resourceGroups/rg/providers/" +
+		"Microsoft.Devices/IotHubs/hub1"
+	testIoTHubID2 = "/subscriptions/sub/resourceGroups/rg/providers/" +
+		"Microsoft.Devices/IotHubs/hub2"
+)
+
+func getTestProvisioningParameters() *ProvisioningParameters {
+	return &ProvisioningParameters{
+		IoTHubs: []IoTHubLink{
+			{ResourceID: testIoTHubID1},
+			{ResourceID: testIoTHubID2, AllocationWeight: 3},
+		},
+		EnrollmentGroups: []EnrollmentGroup{
+			{ID: "sensors"},
+			{
+				ID:               "gateways",
+				AllocationPolicy: allocationPolicyStatic,
+				IoTHubs:          []string{testIoTHubID2},
+			},
+		},
+	}
+}
+
+func TestValidateProvisioningParameters(t *testing.T) {
+	m := &module{}
+	err := m.serviceManager.ValidateProvisioningParameters(
+		getTestProvisioningParameters(),
+	)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidIoTHubs(t *testing.T) {
+	m := &module{}
+	for name, mutate := range map[string]func(*ProvisioningParameters){
+		"none": func(pp *ProvisioningParameters) {
+			pp.IoTHubs = nil
+			pp.EnrollmentGroups = nil
+		},
+		"not a hub": func(pp *ProvisioningParameters) {
+			pp.IoTHubs[0].ResourceID = "/subscriptions/sub/resourceGroups/rg/" +
+				"providers/Microsoft.Storage/storageAccounts/hub1"
+		},
+		"duplicate": func(pp *ProvisioningParameters) {
+			pp.IoTHubs[1].ResourceID = "/subscriptions/sub/resourceGroups/RG/" +
+				"providers/Microsoft.Devices/IotHubs/HUB1"
+		},
+		"weight too high": func(pp *ProvisioningParameters) {
+			pp.IoTHubs[1].AllocationWeight = 1001
+		},
+		"weight not hashed": func(pp *ProvisioningParameters) {
+			pp.AllocationPolicy = allocationPolicyGeoLatency
+		},
+	} {
+		pp := getTestProvisioningParameters()
+		mutate(pp)
+		err := m.serviceManager.ValidateProvisioningParameters(pp)
+		assert.NotNil(t, err, name)
+	}
+}
+
+func TestValidateProvisioningParametersAllocationPolicy(t *testing.T) {
+	m := &module{}
+	for _, tc := range []struct {
+		allocationPolicy string
+		hubCount         int
+		valid            bool
+	}{
+		{allocationPolicyHashed, 2, true},
+		{allocationPolicyGeoLatency, 2, true},
+		{allocationPolicyStatic, 1, true},
+		{allocationPolicyStatic, 2, false},
+		{"hashed", 2, false},
+		{"Random", 2, false},
+	} {
+		pp := getTestProvisioningParameters()
+		pp.AllocationPolicy = tc.allocationPolicy
+		pp.IoTHubs = pp.IoTHubs[:tc.hubCount]
+		pp.IoTHubs[0].AllocationWeight = 0
+		if tc.hubCount > 1 {
+			pp.IoTHubs[1].AllocationWeight = 0
+		}
+		pp.EnrollmentGroups = nil
+		err := m.serviceManager.ValidateProvisioningParameters(pp)
+		if tc.valid {
+			assert.Nil(t, err, "%v", tc)
+		} else {
+			assert.NotNil(t, err, "%v", tc)
+		}
+	}
+}
+
+func TestValidateProvisioningParametersWithInvalidEnrollmentGroups(
+	t *testing.T,
+) {
+	m := &module{}
+	for name, mutate := range map[string]func(*ProvisioningParameters){
+		"bad id": func(pp *ProvisioningParameters) {
+			pp.EnrollmentGroups[0].ID = "sensors!"
+		},
+		"duplicate id": func(pp *ProvisioningParameters) {
+			pp.EnrollmentGroups[1].ID = "Sensors"
+		},
+		"bad allocation policy": func(pp *ProvisioningParameters) {
+			pp.EnrollmentGroups[0].AllocationPolicy = "Random"
+		},
+		"unlinked hub": func(pp *ProvisioningParameters) {
+			pp.EnrollmentGroups[1].IoTHubs = []string{
+				"/subscriptions/sub/resourceGroups/rg/providers/" +
+					"Microsoft.Devices/IotHubs/hub3",
+			}
+		},
+		"static with every hub": func(pp *ProvisioningParameters) {
+			pp.EnrollmentGroups[1].IoTHubs = nil
+		},
+	} {
+		pp := getTestProvisioningParameters()
+		mutate(pp)
+		err := m.serviceManager.ValidateProvisioningParameters(pp)
+		assert.NotNil(t, err, name)
+	}
+}
+
+func TestApplyProvisioningParametersDefaults(t *testing.T) {
+	m := &module{}
+	pp := getTestProvisioningParameters()
+	err := m.serviceManager.ApplyProvisioningParametersDefaults(pp)
+	assert.Nil(t, err)
+	assert.Equal(t, allocationPolicyHashed, pp.AllocationPolicy)
+	assert.Equal(t, int64(1), pp.IoTHubs[0].AllocationWeight)
+	assert.Equal(t, int64(3), pp.IoTHubs[1].AllocationWeight)
+}
+
+func TestGetEnrollmentGroup(t *testing.T) {
+	dt := &dpsInstanceDetails{
+		IoTHubHostNames: map[string]string{
+			"/subscriptions/sub/resourcegroups/rg/providers/" +
+				"microsoft.devices/iothubs/hub2": "hub2.azure-devices.net",
+		},
+	}
+	pp := getTestProvisioningParameters()
+	assert.Equal(
+		t,
+		"",
+		getEnrollmentGroup(dt, pp.EnrollmentGroups[0]).AllocationPolicy,
+	)
+	group := getEnrollmentGroup(dt, pp.EnrollmentGroups[1])
+	assert.Equal(t, "gateways", group.ID)
+	assert.Equal(t, "static", group.AllocationPolicy)
+	assert.Equal(t, []string{"hub2.azure-devices.net"}, group.IoTHubHostNames)
+}
@@ -0,0 +1,102 @@
+package iotdps
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// ProvisioningParameters encapsulates Azure IoT Hub Device Provisioning
+// Service-specific provisioning options
+type ProvisioningParameters struct {
+	// IoTHubs are the existing hubs that the DPS assigns devices to
+	IoTHubs []IoTHubLink `json:"iotHubs"`
+	// AllocationPolicy is how the DPS chooses which of its hubs to assign each
+	// device to, unless the device's enrollment group says otherwise
+	AllocationPolicy string            `json:"allocationPolicy"`
+	EnrollmentGroups []EnrollmentGroup `json:"enrollmentGroups"`
+}
+
+// IoTHubLink encapsulates the linking of an existing IoT hub to the DPS
+type IoTHubLink struct {
+	ResourceID string `json:"resourceId"`
+	// AllocationWeight is the hub's share, relative to other hubs, of the
+	// devices assigned under the Hashed allocation policy
+	AllocationWeight int64 `json:"allocationWeight"`
+}
+
+// EnrollmentGroup encapsulates a group of devices, attested using keys derived
+// from the group's key, that are provisioned alike
+type EnrollmentGroup struct {
+	ID string `json:"id"`
+	// AllocationPolicy overrides the DPS's own allocation policy for the group's
+	// devices
+	AllocationPolicy string `json:"allocationPolicy"`
+	// IoTHubs are the resource IDs of those of the linked hubs that the group's
+	// devices may be assigned to. Devices may be assigned to any linked hub if
+	// none are given.
+	IoTHubs []string `json:"iotHubs"`
+}
+
+type dpsInstanceDetails struct {
+	ARMDeploymentName string `json:"armDeployment"`
+	ServiceName       string `json:"serviceName"`
+	// IDScope identifies the DPS to the devices it provisions
+	IDScope                    string `json:"idScope"`
+	ServiceOperationsHostName  string `json:"serviceOperationsHostName"`
+	DeviceProvisioningHostName string `json:"deviceProvisioningHostName"`
+	// IoTHubHostNames maps the resource IDs of the linked hubs to their host
+	// names
+	IoTHubHostNames map[string]string `json:"iotHubHostNames"`
+}
+
+// UpdatingParameters encapsulates Azure IoT Hub Device Provisioning
+// Service-specific updating options
+type UpdatingParameters struct {
+}
+
+// BindingParameters encapsulates Azure IoT Hub Device Provisioning
+// Service-specific binding options
+type BindingParameters struct {
+	// Rights are those the binding's shared access policy grants
+	Rights []string `json:"rights"`
+}
+
+type dpsBindingDetails struct {
+	KeyName    string `json:"keyName"`
+	PrimaryKey string `json:"primaryKey" secret:"true"`
+}
+
+// Credentials encapsulates the ID scope by which devices find the DPS, and
+// the shared access policy by which the DPS's enrollments and registrations
+// are managed
+type Credentials struct {
+	IDScope                   string `json:"idScope"`
+	GlobalDeviceEndpoint      string `json:"globalDeviceEndpoint"`
+	ServiceOperationsHostName string `json:"serviceOperationsHostName"`
+	KeyName                   string `json:"keyName"`
+	Key                       string `json:"key"`
+	ConnectionString          string `json:"connectionString"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &dpsInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &dpsBindingDetails{}
+}
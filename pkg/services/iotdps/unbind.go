@@ -0,0 +1,30 @@
+package iotdps
+
+import (
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) Unbind(
+	instance service.Instance,
+	bindingDetails service.BindingDetails,
+) error {
+	dt, ok := instance.Details.(*dpsInstanceDetails)
+	if !ok {
+		return errors.New(
+			"error casting instance.Details as *dpsInstanceDetails",
+		)
+	}
+	bd, ok := bindingDetails.(*dpsBindingDetails)
+	if !ok {
+		return errors.New(
+			"error casting bindingDetails as *dpsBindingDetails",
+		)
+	}
+	return s.dpsManager.DeleteAccessPolicy(
+		dt.ServiceName,
+		instance.ResourceGroup,
+		bd.KeyName,
+	)
+}
@@ -10,6 +10,7 @@ func (m *module) GetCatalog() (service.Catalog, error) {
 				Name:        "azure-keyvault",
 				Description: "Azure Key Vault (Experimental)",
 				Bindable:    true,
+				Cloneable:   true,
 				Tags:        []string{"Azure", "Key", "Vault"},
 			},
 			m.serviceManager,
@@ -6,7 +6,7 @@ import "github.com/Azure/open-service-broker-azure/pkg/service"
 type ProvisioningParameters struct {
 	ObjectID     string `json:"objectId"`
 	ClientID     string `json:"clientId"`
-	ClientSecret string `json:"clientSecret"`
+	ClientSecret string `json:"clientSecret" secret:"true"`
 }
 
 type keyvaultInstanceDetails struct {
@@ -14,7 +14,7 @@ type keyvaultInstanceDetails struct {
 	KeyVaultName      string `json:"keyVaultName"`
 	VaultURI          string `json:"vaultUri"`
 	ClientID          string `json:"clientId"`
-	ClientSecret      string `json:"clientSecret"`
+	ClientSecret      string `json:"clientSecret" secret:"true"`
 }
 
 // UpdatingParameters encapsulates keyvault-specific updating options
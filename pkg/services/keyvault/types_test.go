@@ -0,0 +1,58 @@
+package keyvault
+
+import (
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/crypto"
+	"github.com/Azure/open-service-broker-azure/pkg/crypto/aes256"
+	"github.com/Azure/open-service-broker-azure/pkg/crypto/versioned"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFieldLevelEncryptionEncryptsClientSecrets(t *testing.T) {
+	aesCodec, err := aes256.NewCodec([]byte("AES256Key-32Characters1234567890"))
+	assert.Nil(t, err)
+	codec, err := versioned.NewCodec(
+		"1",
+		map[string]crypto.Codec{"1": aesCodec},
+		true,
+	)
+	assert.Nil(t, err)
+	instance := service.Instance{
+		InstanceID: "instance",
+		ProvisioningParameters: &ProvisioningParameters{
+			ClientID:     "client",
+			ClientSecret: "pp-s3cr3t",
+		},
+		Details: &keyvaultInstanceDetails{
+			KeyVaultName: "vault",
+			ClientID:     "client",
+			ClientSecret: "details-s3cr3t",
+		},
+	}
+	instanceJSON, err := instance.ToJSON(codec)
+	assert.Nil(t, err)
+	assert.Contains(t, string(instanceJSON), `"keyVaultName":"vault"`)
+	assert.NotContains(t, string(instanceJSON), "pp-s3cr3t")
+	assert.NotContains(t, string(instanceJSON), "details-s3cr3t")
+	retrieved, err := service.NewInstanceFromJSON(
+		instanceJSON,
+		&ProvisioningParameters{},
+		&UpdatingParameters{},
+		&keyvaultInstanceDetails{},
+		codec,
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, instance.Details, retrieved.Details)
+}
+
+func TestClientSecretsAreRedacted(t *testing.T) {
+	redacted, err := service.RedactSecrets(&keyvaultInstanceDetails{
+		ClientID:     "client",
+		ClientSecret: "s3cr3t",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "client", redacted["clientId"])
+	assert.Equal(t, service.RedactedValue, redacted["clientSecret"])
+}
@@ -0,0 +1,61 @@
+package kusto
+
+// nolint: lll
+var armTemplateClusterBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "clusterName": {
+      "type": "string"
+    },
+    "skuName": {
+      "type": "string"
+    },
+    "skuTier": {
+      "type": "string"
+    },
+    "capacity": {
+      "type": "int"
+    },
+    "tags": {
+      "type": "object"
+    }
+  },
+  "variables": {
+    "apiVersion": "2020-09-18"
+  },
+  "resources": [
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[parameters('clusterName')]",
+      "type": "Microsoft.Kusto/clusters",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "sku": {
+        "name": "[parameters('skuName')]",
+        "tier": "[parameters('skuTier')]",
+        "capacity": "[parameters('capacity')]"
+      },
+      "properties": {}
+    }
+  ],
+  "outputs": {
+    "clusterId": {
+      "type": "string",
+      "value": "[resourceId('Microsoft.Kusto/clusters', parameters('clusterName'))]"
+    },
+    "clusterUri": {
+      "type": "string",
+      "value": "[reference(parameters('clusterName')).uri]"
+    },
+    "dataIngestionUri": {
+      "type": "string",
+      "value": "[reference(parameters('clusterName')).dataIngestionUri]"
+    }
+  }
+}
+`)
@@ -0,0 +1,42 @@
+package kusto
+
+// nolint: lll
+var armTemplateDatabaseBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "clusterName": {
+      "type": "string"
+    },
+    "databaseName": {
+      "type": "string"
+    },
+    "softDeletePeriod": {
+      "type": "string"
+    },
+    "hotCachePeriod": {
+      "type": "string"
+    },
+    "tags": {
+      "type": "object"
+    }
+  },
+  "resources": [
+    {
+      "apiVersion": "2020-09-18",
+      "name": "[concat(parameters('clusterName'), '/', parameters('databaseName'))]",
+      "type": "Microsoft.Kusto/clusters/databases",
+      "location": "[parameters('location')]",
+      "kind": "ReadWrite",
+      "properties": {
+        "softDeletePeriod": "[parameters('softDeletePeriod')]",
+        "hotCachePeriod": "[parameters('hotCachePeriod')]"
+      }
+    }
+  ]
+}
+`)
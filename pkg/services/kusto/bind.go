@@ -0,0 +1,109 @@
+package kusto
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const defaultRole = "Viewer"
+
+// roles are the database-level roles a binding may be granted. Admin is
+// deliberately omitted so that bindings remain scoped to the data.
+var roles = map[string]bool{
+	"Viewer":   true,
+	"User":     true,
+	"Ingestor": true,
+}
+
+func (s *serviceManager) ValidateBindingParameters(
+	bindingParameters service.BindingParameters,
+) error {
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return errors.New(
+			"error casting bindingParameters as *kusto.BindingParameters",
+		)
+	}
+	if _, err := uuid.FromString(bp.PrincipalID); err != nil {
+		return service.NewValidationError(
+			"principalId",
+			fmt.Sprintf(`invalid principalId: "%s"`, bp.PrincipalID),
+		)
+	}
+	if bp.Role != "" && !roles[bp.Role] {
+		return service.NewValidationError(
+			"role",
+			fmt.Sprintf(`invalid role: "%s"`, bp.Role),
+		)
+	}
+	return nil
+}
+
+func (s *serviceManager) Bind(
+	instance service.Instance,
+	bindingParameters service.BindingParameters,
+) (service.BindingDetails, error) {
+	dt, ok := instance.Details.(*kustoInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *kustoInstanceDetails",
+		)
+	}
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting bindingParameters as *kusto.BindingParameters",
+		)
+	}
+	role := bp.Role
+	if role == "" {
+		role = defaultRole
+	}
+	assignmentName := uuid.NewV4().String()
+	assignment, err := s.kustoManager.CreateDatabasePrincipalAssignment(
+		dt.ClusterName,
+		dt.DatabaseName,
+		instance.ResourceGroup,
+		assignmentName,
+		bp.PrincipalID,
+		role,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &kustoBindingDetails{
+		PrincipalAssignmentName: assignmentName,
+		PrincipalID:             assignment.PrincipalID,
+		Role:                    assignment.Role,
+		TenantID:                assignment.TenantID,
+	}, nil
+}
+
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	binding service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*kustoInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *kustoInstanceDetails",
+		)
+	}
+	bd, ok := binding.Details.(*kustoBindingDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting binding.Details as *kustoBindingDetails",
+		)
+	}
+	return &kustoCredentials{
+		QueryEndpoint:     dt.ClusterURI,
+		IngestionEndpoint: dt.DataIngestionURI,
+		Database:          dt.DatabaseName,
+		PrincipalID:       bd.PrincipalID,
+		Role:              bd.Role,
+		TenantID:          bd.TenantID,
+	}, nil
+}
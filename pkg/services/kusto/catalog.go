@@ -0,0 +1,55 @@
+package kusto
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:          "b3f0c6d2-7a41-4e8b-9c25-1d6e8f4a2b97",
+				Name:        "azure-data-explorer",
+				Description: "Azure Data Explorer (Experimental)",
+				Bindable:    true,
+				Cloneable:   true,
+				Tags:        []string{"Azure", "Data Explorer", "Kusto"},
+				// Not every region supports Azure Data Explorer
+				Locations: []string{
+					"australiaeast",
+					"australiasoutheast",
+					"brazilsouth",
+					"canadacentral",
+					"canadaeast",
+					"centralindia",
+					"centralus",
+					"eastasia",
+					"eastus",
+					"eastus2",
+					"japaneast",
+					"japanwest",
+					"koreacentral",
+					"northcentralus",
+					"northeurope",
+					"southcentralus",
+					"southeastasia",
+					"southindia",
+					"uksouth",
+					"ukwest",
+					"westcentralus",
+					"westeurope",
+					"westus",
+					"westus2",
+				},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:          "0c8d2e7f-5b16-4a93-8e4d-6f2a9b1c3d58",
+				Name:        "standard",
+				Description: "Standard Tier. Production workloads with an SLA",
+				Free:        false,
+				Extended: map[string]interface{}{
+					"skuTier": "Standard",
+				},
+			}),
+		),
+	}), nil
+}
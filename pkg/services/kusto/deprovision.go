@@ -0,0 +1,96 @@
+package kusto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) GetDeprovisioner(
+	service.Plan,
+) (service.Deprovisioner, error) {
+	return service.NewDeprovisioner(
+		service.NewDeprovisioningStep(
+			"deleteARMDeployments",
+			s.deleteARMDeployments,
+		),
+		service.NewDeprovisioningStep(
+			"deleteAutoscaleSetting",
+			s.deleteAutoscaleSetting,
+		),
+		service.NewDeprovisioningStep("deleteCluster", s.deleteCluster),
+	)
+}
+
+func (s *serviceManager) deleteARMDeployments(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*kustoInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *kustoInstanceDetails",
+		)
+	}
+	for _, deploymentName := range []string{
+		dt.AutoscaleARMDeploymentName,
+		dt.DatabaseARMDeploymentName,
+		dt.ClusterARMDeploymentName,
+	} {
+		// Instances without an autoscale setting have no deployment for one
+		if deploymentName == "" {
+			continue
+		}
+		if err := s.armDeployer.Delete(
+			deploymentName,
+			instance.ResourceGroup,
+		); err != nil {
+			return nil, fmt.Errorf("error deleting ARM deployment: %s", err)
+		}
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deleteAutoscaleSetting(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*kustoInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *kustoInstanceDetails",
+		)
+	}
+	if dt.AutoscaleSettingName == "" {
+		return dt, nil
+	}
+	if err := s.autoscaleManager.DeleteAutoscaleSetting(
+		dt.AutoscaleSettingName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deleteCluster(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*kustoInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *kustoInstanceDetails",
+		)
+	}
+	// Deleting the cluster also deletes its databases
+	if err := s.kustoManager.DeleteCluster(
+		dt.ClusterName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
@@ -0,0 +1,50 @@
+package kusto
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/autoscale"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/kusto"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer      arm.Deployer
+	kustoManager     kusto.Manager
+	autoscaleManager autoscale.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of provisioning Azure Data Explorer clusters and
+// databases
+func New(
+	armDeployer arm.Deployer,
+	kustoManager kusto.Manager,
+	autoscaleManager autoscale.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer:      armDeployer,
+			kustoManager:     kustoManager,
+			autoscaleManager: autoscaleManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "kusto"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{
+		"Microsoft.Kusto",
+		"Microsoft.Insights",
+	}
+}
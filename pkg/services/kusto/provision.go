@@ -0,0 +1,344 @@
+package kusto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/autoscale"
+	"github.com/Azure/open-service-broker-azure/pkg/generate"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	defaultSKU                    = "Standard_D11_v2"
+	defaultCapacity               = 2
+	minCapacity                   = 2
+	maxCapacity                   = 1000
+	defaultSoftDeletePeriodInDays = 365
+	maxSoftDeletePeriodInDays     = 36500
+	defaultHotCachePeriodInDays   = 31
+)
+
+var skus = map[string]bool{
+	"Standard_D11_v2": true,
+	"Standard_D12_v2": true,
+	"Standard_D13_v2": true,
+	"Standard_D14_v2": true,
+	"Standard_L4s":    true,
+	"Standard_L8s":    true,
+	"Standard_L16s":   true,
+}
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as *kusto.ProvisioningParameters",
+		)
+	}
+	if pp.SKU != "" && !skus[pp.SKU] {
+		return service.NewValidationError(
+			"sku",
+			fmt.Sprintf(`invalid sku: "%s"`, pp.SKU),
+		)
+	}
+	if pp.Capacity != 0 &&
+		(pp.Capacity < minCapacity || pp.Capacity > maxCapacity) {
+		return service.NewValidationError(
+			"capacity",
+			fmt.Sprintf(
+				"invalid capacity: %d; capacity must be between %d and %d",
+				pp.Capacity,
+				minCapacity,
+				maxCapacity,
+			),
+		)
+	}
+	if pp.SoftDeletePeriodInDays < 0 ||
+		pp.SoftDeletePeriodInDays > maxSoftDeletePeriodInDays {
+		return service.NewValidationError(
+			"softDeletePeriodInDays",
+			fmt.Sprintf(
+				"invalid softDeletePeriodInDays: %d; value must be between 1 and %d",
+				pp.SoftDeletePeriodInDays,
+				maxSoftDeletePeriodInDays,
+			),
+		)
+	}
+	if pp.HotCachePeriodInDays < 0 ||
+		pp.HotCachePeriodInDays > getSoftDeletePeriodInDays(pp) {
+		return service.NewValidationError(
+			"hotCachePeriodInDays",
+			fmt.Sprintf(
+				"invalid hotCachePeriodInDays: %d; value must be between 1 and "+
+					"softDeletePeriodInDays",
+				pp.HotCachePeriodInDays,
+			),
+		)
+	}
+	if pp.Autoscale != nil {
+		return autoscale.Validate(
+			"autoscale",
+			pp.Autoscale,
+			minCapacity,
+			maxCapacity,
+		)
+	}
+	return nil
+}
+
+// GetSKUUsages maps a cluster to its SKU, once for each of its instances. If
+// the cluster autoscales, its initial capacity is used.
+func (s *serviceManager) GetSKUUsages(
+	_ service.Plan,
+	provisioningParameters service.ProvisioningParameters,
+) ([]service.SKUUsage, error) {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting provisioningParameters as " +
+				"*kusto.ProvisioningParameters",
+		)
+	}
+	sku := pp.SKU
+	if sku == "" {
+		sku = defaultSKU
+	}
+	capacity := pp.Capacity
+	if capacity == 0 {
+		capacity = defaultCapacity
+	}
+	return []service.SKUUsage{
+		{
+			SKU:      sku,
+			Quantity: float64(capacity),
+		},
+	}, nil
+}
+
+// GetProvisioner returns a provisioner that deploys the cluster and the
+// database as separate steps. Cluster creation routinely takes upwards of ten
+// minutes, so keeping it in a step of its own means that, should the broker
+// be restarted mid-deployment, the async engine resumes polling the existing
+// deployment instead of starting over. Any autoscale setting is deployed last,
+// once the cluster it targets is known to exist. Neither the database nor the
+// autoscale setting depends on the other, so operators may reorder them, or
+// skip the autoscale setting altogether.
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep("deployCluster", s.deployCluster),
+		service.NewProvisioningStepWithDependencies(
+			"deployDatabase",
+			s.deployDatabase,
+			"preProvision",
+			"deployCluster",
+		),
+		service.NewProvisioningStepWithDependencies(
+			"deployAutoscaleSetting",
+			s.deployAutoscaleSetting,
+			"preProvision",
+			"deployCluster",
+		),
+	)
+}
+
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*kustoInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *kustoInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*kusto.ProvisioningParameters",
+		)
+	}
+	dt.ClusterARMDeploymentName = uuid.NewV4().String()
+	dt.DatabaseARMDeploymentName = uuid.NewV4().String()
+	if pp.Autoscale != nil {
+		dt.AutoscaleARMDeploymentName = uuid.NewV4().String()
+		dt.AutoscaleSettingName = generate.NewIdentifier()
+	}
+	// Cluster names must be globally unique, begin with a letter, and contain
+	// only lowercase letters and numbers
+	dt.ClusterName = generate.NewIdentifierOfLength(20)
+	dt.DatabaseName = generate.NewIdentifier()
+	return dt, nil
+}
+
+func (s *serviceManager) deployCluster(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*kustoInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *kustoInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*kusto.ProvisioningParameters",
+		)
+	}
+	sku := pp.SKU
+	if sku == "" {
+		sku = defaultSKU
+	}
+	capacity := pp.Capacity
+	if capacity == 0 {
+		capacity = defaultCapacity
+	}
+	outputs, err := s.armDeployer.Deploy(
+		dt.ClusterARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateClusterBytes,
+		nil, // Go template params
+		map[string]interface{}{ // ARM template params
+			"clusterName": dt.ClusterName,
+			"skuName":     sku,
+			"skuTier":     instance.Plan.GetProperties().Extended["skuTier"],
+			"capacity":    capacity,
+		},
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+
+	clusterID, ok := outputs["clusterId"].(string)
+	if !ok {
+		return nil, errors.New("error retrieving cluster id from deployment")
+	}
+	dt.ClusterID = clusterID
+
+	clusterURI, ok := outputs["clusterUri"].(string)
+	if !ok {
+		return nil, fmt.Errorf(
+			"error retrieving cluster uri from deployment: %s",
+			err,
+		)
+	}
+	dt.ClusterURI = clusterURI
+
+	dataIngestionURI, ok := outputs["dataIngestionUri"].(string)
+	if !ok {
+		return nil, fmt.Errorf(
+			"error retrieving data ingestion uri from deployment: %s",
+			err,
+		)
+	}
+	dt.DataIngestionURI = dataIngestionURI
+
+	return dt, nil
+}
+
+func (s *serviceManager) deployDatabase(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*kustoInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *kustoInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*kusto.ProvisioningParameters",
+		)
+	}
+	softDeletePeriodInDays := getSoftDeletePeriodInDays(pp)
+	hotCachePeriodInDays := pp.HotCachePeriodInDays
+	if hotCachePeriodInDays == 0 {
+		hotCachePeriodInDays = defaultHotCachePeriodInDays
+	}
+	// The default hot cache period may exceed a short, explicitly requested
+	// retention period
+	if hotCachePeriodInDays > softDeletePeriodInDays {
+		hotCachePeriodInDays = softDeletePeriodInDays
+	}
+	_, err := s.armDeployer.Deploy(
+		dt.DatabaseARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateDatabaseBytes,
+		nil, // Go template params
+		map[string]interface{}{ // ARM template params
+			"clusterName":      dt.ClusterName,
+			"databaseName":     dt.DatabaseName,
+			"softDeletePeriod": fmt.Sprintf("P%dD", softDeletePeriodInDays),
+			"hotCachePeriod":   fmt.Sprintf("P%dD", hotCachePeriodInDays),
+		},
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deployAutoscaleSetting(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*kustoInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *kustoInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*kusto.ProvisioningParameters",
+		)
+	}
+	// No autoscale setting was requested
+	if dt.AutoscaleSettingName == "" {
+		return dt, nil
+	}
+	_, err := s.armDeployer.Deploy(
+		dt.AutoscaleARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		autoscale.ARMTemplateBytes,
+		nil, // Go template params
+		map[string]interface{}{ // ARM template params
+			"autoscaleSettingName": dt.AutoscaleSettingName,
+			"targetResourceId":     dt.ClusterID,
+			"profiles":             autoscale.GetARMProfiles(pp.Autoscale),
+		},
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+	return dt, nil
+}
+
+func getSoftDeletePeriodInDays(pp *ProvisioningParameters) int {
+	if pp.SoftDeletePeriodInDays == 0 {
+		return defaultSoftDeletePeriodInDays
+	}
+	return pp.SoftDeletePeriodInDays
+}
@@ -0,0 +1,141 @@
+package kusto
+
+import (
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/autoscale"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateProvisioningParametersWithDefaults(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidSKU(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		SKU: "Standard_D99_v2",
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.SKU = "Standard_D13_v2"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidCapacity(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		Capacity: 1,
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.Capacity = 1001
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.Capacity = 4
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithHotCacheExceedingRetention(
+	t *testing.T,
+) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		SoftDeletePeriodInDays: 30,
+		HotCachePeriodInDays:   60,
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.HotCachePeriodInDays = 7
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidAutoscaleSchedule(
+	t *testing.T,
+) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		Autoscale: &autoscale.Parameters{},
+	}
+	// At least one profile is required
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.Autoscale.Profiles = []autoscale.ProfileParameters{
+		{
+			Name:      "business-hours",
+			Days:      []string{"Monday", "Funday"},
+			StartTime: "08:00",
+			Minimum:   2,
+			Maximum:   4,
+			Default:   4,
+		},
+	}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.Autoscale.Profiles[0].Days = []string{"Monday", "Friday"}
+	pp.Autoscale.Profiles[0].StartTime = "8am"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.Autoscale.Profiles[0].StartTime = "08:00"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+	// A second profile may not reuse the first's name
+	pp.Autoscale.Profiles = append(
+		pp.Autoscale.Profiles,
+		pp.Autoscale.Profiles[0],
+	)
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.Autoscale.Profiles[1].Name = "off-hours"
+	pp.Autoscale.Profiles[1].StartTime = "18:00"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidAutoscaleCapacity(
+	t *testing.T,
+) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		Autoscale: &autoscale.Parameters{
+			Profiles: []autoscale.ProfileParameters{
+				{
+					Name:      "off-hours",
+					Days:      []string{"Saturday", "Sunday"},
+					StartTime: "00:00",
+					// Clusters can't have fewer than two instances
+					Minimum: 1,
+					Maximum: 2,
+					Default: 2,
+				},
+			},
+		},
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.Autoscale.Profiles[0].Minimum = 2
+	pp.Autoscale.Profiles[0].Default = 3
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.Autoscale.Profiles[0].Default = 2
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestGetSKUUsagesCountsEachInstance(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		Capacity: 3,
+	}
+	skuUsages, err := m.serviceManager.GetSKUUsages(nil, pp)
+	assert.Nil(t, err)
+	assert.Len(t, skuUsages, 1)
+	assert.Equal(t, defaultSKU, skuUsages[0].SKU)
+	assert.Equal(t, float64(3), skuUsages[0].Quantity)
+}
@@ -0,0 +1,82 @@
+package kusto
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/autoscale"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// ProvisioningParameters encapsulates Azure Data Explorer-specific
+// provisioning options
+type ProvisioningParameters struct {
+	SKU                    string `json:"sku"`
+	Capacity               int    `json:"capacity"`
+	SoftDeletePeriodInDays int    `json:"softDeletePeriodInDays"`
+	HotCachePeriodInDays   int    `json:"hotCachePeriodInDays"`
+	// Autoscale, if specified, schedules changes to the cluster's capacity
+	Autoscale *autoscale.Parameters `json:"autoscale"`
+}
+
+type kustoInstanceDetails struct {
+	ClusterARMDeploymentName   string `json:"clusterARMDeployment"`
+	DatabaseARMDeploymentName  string `json:"databaseARMDeployment"`
+	AutoscaleARMDeploymentName string `json:"autoscaleARMDeployment"`
+	ClusterName                string `json:"clusterName"`
+	ClusterID                  string `json:"clusterId"`
+	AutoscaleSettingName       string `json:"autoscaleSettingName"`
+	DatabaseName               string `json:"databaseName"`
+	ClusterURI                 string `json:"clusterUri"`
+	DataIngestionURI           string `json:"dataIngestionUri"`
+}
+
+// UpdatingParameters encapsulates Azure Data Explorer-specific updating
+// options
+type UpdatingParameters struct {
+}
+
+// BindingParameters encapsulates Azure Data Explorer-specific binding options
+type BindingParameters struct {
+	PrincipalID string `json:"principalId"`
+	Role        string `json:"role"`
+}
+
+type kustoBindingDetails struct {
+	PrincipalAssignmentName string `json:"principalAssignmentName"`
+	PrincipalID             string `json:"principalId"`
+	Role                    string `json:"role"`
+	TenantID                string `json:"tenantId"`
+}
+
+type kustoCredentials struct {
+	QueryEndpoint     string `json:"queryEndpoint"`
+	IngestionEndpoint string `json:"ingestionEndpoint"`
+	Database          string `json:"database"`
+	PrincipalID       string `json:"principalId"`
+	Role              string `json:"role"`
+	TenantID          string `json:"tenantId"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &kustoInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &kustoBindingDetails{}
+}
@@ -0,0 +1,31 @@
+package kusto
+
+import (
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) Unbind(
+	instance service.Instance,
+	bindingDetails service.BindingDetails,
+) error {
+	dt, ok := instance.Details.(*kustoInstanceDetails)
+	if !ok {
+		return errors.New(
+			"error casting instance.Details as *kustoInstanceDetails",
+		)
+	}
+	bd, ok := bindingDetails.(*kustoBindingDetails)
+	if !ok {
+		return errors.New(
+			"error casting bindingDetails as *kustoBindingDetails",
+		)
+	}
+	return s.kustoManager.DeleteDatabasePrincipalAssignment(
+		dt.ClusterName,
+		dt.DatabaseName,
+		instance.ResourceGroup,
+		bd.PrincipalAssignmentName,
+	)
+}
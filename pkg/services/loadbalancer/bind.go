@@ -0,0 +1,257 @@
+package loadbalancer
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/loadbalancer"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	protocolTCP   = "Tcp"
+	protocolUDP   = "Udp"
+	protocolHTTP  = "Http"
+	protocolHTTPS = "Https"
+	// Azure probes no more often than every five seconds
+	minProbeInterval      = 5
+	defaultProbeInterval  = 15
+	defaultNumberOfProbes = 2
+)
+
+func (s *serviceManager) ValidateBindingParameters(
+	bindingParameters service.BindingParameters,
+) error {
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return errors.New(
+			"error casting bindingParameters as *loadbalancer.BindingParameters",
+		)
+	}
+	if bp.IPAddress == "" {
+		return service.NewValidationError("ipAddress", "ipAddress is required")
+	}
+	if ip := net.ParseIP(bp.IPAddress); ip == nil || ip.To4() == nil ||
+		ip.IsUnspecified() || ip.IsLoopback() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() {
+		return service.NewValidationError(
+			"ipAddress",
+			fmt.Sprintf(
+				`invalid ipAddress: "%s"; it must be the IPv4 address of an `+
+					"endpoint in the load balancer's virtual network",
+				bp.IPAddress,
+			),
+		)
+	}
+	if err := validatePort("port", bp.Port, true); err != nil {
+		return err
+	}
+	if err := validatePort("frontendPort", bp.FrontendPort, false); err != nil {
+		return err
+	}
+	if bp.Protocol != "" && bp.Protocol != protocolTCP &&
+		bp.Protocol != protocolUDP {
+		return service.NewValidationError(
+			"protocol",
+			fmt.Sprintf(
+				`invalid protocol: "%s"; allowed values are %s and %s`,
+				bp.Protocol,
+				protocolTCP,
+				protocolUDP,
+			),
+		)
+	}
+	if bp.HealthProbe != nil {
+		return validateHealthProbe(bp.HealthProbe)
+	}
+	return nil
+}
+
+func validateHealthProbe(probe *HealthProbeParameters) error {
+	switch probe.Protocol {
+	case "", protocolTCP:
+		if probe.RequestPath != "" {
+			return service.NewValidationError(
+				"healthProbe.requestPath",
+				"a requestPath may only be given for Http and Https probes",
+			)
+		}
+	case protocolHTTP, protocolHTTPS:
+		if !strings.HasPrefix(probe.RequestPath, "/") {
+			return service.NewValidationError(
+				"healthProbe.requestPath",
+				fmt.Sprintf(
+					`invalid requestPath: "%s"; %s probes require a path beginning `+
+						"with /",
+					probe.RequestPath,
+					probe.Protocol,
+				),
+			)
+		}
+	default:
+		return service.NewValidationError(
+			"healthProbe.protocol",
+			fmt.Sprintf(
+				`invalid protocol: "%s"; allowed values are %s, %s and %s`,
+				probe.Protocol,
+				protocolTCP,
+				protocolHTTP,
+				protocolHTTPS,
+			),
+		)
+	}
+	if err := validatePort("healthProbe.port", probe.Port, false); err != nil {
+		return err
+	}
+	if probe.IntervalInSeconds != 0 &&
+		probe.IntervalInSeconds < minProbeInterval {
+		return service.NewValidationError(
+			"healthProbe.intervalInSeconds",
+			fmt.Sprintf(
+				"invalid intervalInSeconds: %d; it must be at least %d",
+				probe.IntervalInSeconds,
+				minProbeInterval,
+			),
+		)
+	}
+	if probe.NumberOfProbes < 0 {
+		return service.NewValidationError(
+			"healthProbe.numberOfProbes",
+			fmt.Sprintf(
+				"invalid numberOfProbes: %d; it must be at least 1",
+				probe.NumberOfProbes,
+			),
+		)
+	}
+	return nil
+}
+
+// validatePort returns a ValidationError if the given port isn't a valid TCP
+// or UDP port. An unspecified (zero) port is valid unless it is required.
+func validatePort(field string, port int, required bool) error {
+	if port == 0 && !required {
+		return nil
+	}
+	if port < 1 || port > 65535 {
+		return service.NewValidationError(
+			field,
+			fmt.Sprintf("invalid %s: %d; it must be from 1 to 65535", field, port),
+		)
+	}
+	return nil
+}
+
+// Bind registers the bound consumer's endpoint with the load balancer as a
+// backend of its own, which only this binding's traffic is balanced to
+func (s *serviceManager) Bind(
+	instance service.Instance,
+	bindingParameters service.BindingParameters,
+) (service.BindingDetails, error) {
+	dt, ok := instance.Details.(*loadBalancerInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *loadBalancerInstanceDetails",
+		)
+	}
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting bindingParameters as *loadbalancer.BindingParameters",
+		)
+	}
+	backend := getBackend(dt, bp)
+	backend.Name = uuid.NewV4().String()
+	if err := s.loadBalancerManager.RegisterBackend(
+		dt.LoadBalancerName,
+		instance.ResourceGroup,
+		backend,
+	); err != nil {
+		return nil, err
+	}
+	return &loadBalancerBindingDetails{
+		BackendName:  backend.Name,
+		IPAddress:    backend.IPAddress,
+		Port:         backend.Port,
+		FrontendPort: backend.FrontendPort,
+		Protocol:     backend.Protocol,
+	}, nil
+}
+
+// getBackend returns the backend that the given binding parameters describe,
+// with defaults substituted for any that weren't specified. Unless told
+// otherwise, traffic arrives on the same port it is balanced to, and that port
+// is probed over TCP.
+func getBackend(
+	dt *loadBalancerInstanceDetails,
+	bp *BindingParameters,
+) loadbalancer.Backend {
+	backend := loadbalancer.Backend{
+		VirtualNetworkID: dt.VirtualNetworkID,
+		IPAddress:        bp.IPAddress,
+		Protocol:         bp.Protocol,
+		Port:             bp.Port,
+		FrontendPort:     bp.FrontendPort,
+		HealthProbe: loadbalancer.HealthProbe{
+			Protocol:          protocolTCP,
+			Port:              bp.Port,
+			IntervalInSeconds: defaultProbeInterval,
+			NumberOfProbes:    defaultNumberOfProbes,
+		},
+	}
+	if backend.Protocol == "" {
+		backend.Protocol = protocolTCP
+	}
+	if backend.FrontendPort == 0 {
+		backend.FrontendPort = backend.Port
+	}
+	if probe := bp.HealthProbe; probe != nil {
+		if probe.Protocol != "" {
+			backend.HealthProbe.Protocol = probe.Protocol
+		}
+		if probe.Port != 0 {
+			backend.HealthProbe.Port = probe.Port
+		}
+		backend.HealthProbe.RequestPath = probe.RequestPath
+		if probe.IntervalInSeconds != 0 {
+			backend.HealthProbe.IntervalInSeconds = probe.IntervalInSeconds
+		}
+		if probe.NumberOfProbes != 0 {
+			backend.HealthProbe.NumberOfProbes = probe.NumberOfProbes
+		}
+	}
+	return backend
+}
+
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	binding service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*loadBalancerInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *loadBalancerInstanceDetails",
+		)
+	}
+	bd, ok := binding.Details.(*loadBalancerBindingDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting binding.Details as *loadBalancerBindingDetails",
+		)
+	}
+	return &loadBalancerCredentials{
+		LoadBalancerID:    dt.LoadBalancerID,
+		FrontendIPAddress: dt.FrontendIPAddress,
+		FrontendPort:      bd.FrontendPort,
+		Protocol:          bd.Protocol,
+		BackendPoolID: fmt.Sprintf(
+			"%s/backendAddressPools/%s",
+			dt.LoadBalancerID,
+			bd.BackendName,
+		),
+		BackendIPAddress: bd.IPAddress,
+		BackendPort:      bd.Port,
+	}, nil
+}
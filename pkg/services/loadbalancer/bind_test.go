@@ -0,0 +1,111 @@
+package loadbalancer
+
+import (
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/loadbalancer"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateBindingParametersWithInvalidEndpoint(t *testing.T) {
+	m := &module{}
+	for _, bp := range []*BindingParameters{
+		{Port: 80},
+		{IPAddress: "10.0.1", Port: 80},
+		{IPAddress: "fd00::4", Port: 80},
+		{IPAddress: "127.0.0.1", Port: 80},
+		{IPAddress: "10.0.1.4"},
+		{IPAddress: "10.0.1.4", Port: 65536},
+		{IPAddress: "10.0.1.4", Port: 80, FrontendPort: -1},
+		{IPAddress: "10.0.1.4", Port: 80, Protocol: "Icmp"},
+	} {
+		err := m.serviceManager.ValidateBindingParameters(bp)
+		assert.IsType(t, &service.ValidationError{}, err, *bp)
+	}
+	err := m.serviceManager.ValidateBindingParameters(
+		&BindingParameters{IPAddress: "10.0.1.4", Port: 8080, Protocol: "Udp"},
+	)
+	assert.Nil(t, err)
+}
+
+func TestValidateBindingParametersWithInvalidHealthProbe(t *testing.T) {
+	m := &module{}
+	for _, probe := range []*HealthProbeParameters{
+		{Protocol: "Udp"},
+		{RequestPath: "/healthz"},
+		{Protocol: "Http"},
+		{Protocol: "Https", RequestPath: "healthz"},
+		{Port: 70000},
+		{IntervalInSeconds: 2},
+		{NumberOfProbes: -1},
+	} {
+		err := m.serviceManager.ValidateBindingParameters(
+			&BindingParameters{
+				IPAddress:   "10.0.1.4",
+				Port:        80,
+				HealthProbe: probe,
+			},
+		)
+		assert.IsType(t, &service.ValidationError{}, err, *probe)
+	}
+	err := m.serviceManager.ValidateBindingParameters(
+		&BindingParameters{
+			IPAddress: "10.0.1.4",
+			Port:      80,
+			HealthProbe: &HealthProbeParameters{
+				Protocol:    "Http",
+				RequestPath: "/healthz",
+			},
+		},
+	)
+	assert.Nil(t, err)
+}
+
+func TestGetBackend(t *testing.T) {
+	dt := &loadBalancerInstanceDetails{VirtualNetworkID: "vnet"}
+	assert.Equal(
+		t,
+		loadbalancer.Backend{
+			VirtualNetworkID: "vnet",
+			IPAddress:        "10.0.1.4",
+			Protocol:         "Tcp",
+			Port:             80,
+			FrontendPort:     80,
+			HealthProbe: loadbalancer.HealthProbe{
+				Protocol:          "Tcp",
+				Port:              80,
+				IntervalInSeconds: defaultProbeInterval,
+				NumberOfProbes:    defaultNumberOfProbes,
+			},
+		},
+		getBackend(dt, &BindingParameters{IPAddress: "10.0.1.4", Port: 80}),
+	)
+	backend := getBackend(
+		dt,
+		&BindingParameters{
+			IPAddress:    "10.0.1.4",
+			Port:         8080,
+			FrontendPort: 80,
+			HealthProbe: &HealthProbeParameters{
+				Protocol:       "Http",
+				Port:           8081,
+				RequestPath:    "/healthz",
+				NumberOfProbes: 3,
+			},
+		},
+	)
+	assert.Equal(t, 80, backend.FrontendPort)
+	assert.Equal(t, 8080, backend.Port)
+	assert.Equal(
+		t,
+		loadbalancer.HealthProbe{
+			Protocol:          "Http",
+			Port:              8081,
+			RequestPath:       "/healthz",
+			IntervalInSeconds: defaultProbeInterval,
+			NumberOfProbes:    3,
+		},
+		backend.HealthProbe,
+	)
+}
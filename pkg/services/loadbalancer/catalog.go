@@ -0,0 +1,29 @@
+package loadbalancer
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:          "d3a6f1c8-27b4-4e95-9c0d-5b8e7a2f4c61",
+				Name:        "azure-load-balancer",
+				Description: "Azure Load Balancer (Experimental)",
+				Bindable:    true,
+				Tags: []string{
+					"Azure",
+					"Load Balancer",
+					"Networking",
+				},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:   "8c4e2b7a-91d3-4f6e-a058-3e7d9b1c2a46",
+				Name: "standard-internal",
+				Description: "A Standard internal load balancer in an existing " +
+					"virtual network; each binding registers a backend",
+				Free: false,
+			}),
+		),
+	}), nil
+}
@@ -0,0 +1,43 @@
+package loadbalancer
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/loadbalancer"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer         arm.Deployer
+	loadBalancerManager loadbalancer.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of provisioning Azure load balancers whose
+// backends are registered by binding to them
+func New(
+	armDeployer arm.Deployer,
+	loadBalancerManager loadbalancer.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer:         armDeployer,
+			loadBalancerManager: loadBalancerManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "loadbalancer"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{"Microsoft.Network"}
+}
@@ -0,0 +1,116 @@
+package loadbalancer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/loadbalancer"
+	"github.com/Azure/open-service-broker-azure/pkg/generate"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*loadbalancer.ProvisioningParameters",
+		)
+	}
+	if pp.SubnetID == "" {
+		return service.NewValidationError("subnetId", "subnetId is required")
+	}
+	if !az.IsValidSubnetID(pp.SubnetID) {
+		return service.NewValidationError(
+			"subnetId",
+			fmt.Sprintf(`invalid subnetId: "%s"`, pp.SubnetID),
+		)
+	}
+	return nil
+}
+
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep("deployARMTemplate", s.deployARMTemplate),
+	)
+}
+
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*loadBalancerInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *loadBalancerInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*loadbalancer.ProvisioningParameters",
+		)
+	}
+	dt.ARMDeploymentName = uuid.NewV4().String()
+	dt.LoadBalancerName = generate.NewIdentifier()
+	dt.VirtualNetworkID = loadbalancer.GetVirtualNetworkID(pp.SubnetID)
+	return dt, nil
+}
+
+func (s *serviceManager) deployARMTemplate(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*loadBalancerInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *loadBalancerInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*loadbalancer.ProvisioningParameters",
+		)
+	}
+	outputs, err := s.armDeployer.Deploy(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateBytes,
+		nil, // Go template params
+		map[string]interface{}{ // ARM template params
+			"loadBalancerName": dt.LoadBalancerName,
+			"subnetId":         pp.SubnetID,
+		},
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+	loadBalancerID, ok := outputs["loadBalancerId"].(string)
+	if !ok {
+		return nil, errors.New(
+			"error retrieving load balancer id from deployment",
+		)
+	}
+	dt.LoadBalancerID = loadBalancerID
+	frontendIPAddress, ok := outputs["frontendIPAddress"].(string)
+	if !ok {
+		return nil, errors.New(
+			"error retrieving frontend IP address from deployment",
+		)
+	}
+	dt.FrontendIPAddress = frontendIPAddress
+	return dt, nil
+}
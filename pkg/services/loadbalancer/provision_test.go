@@ -0,0 +1,23 @@
+package loadbalancer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testSubnetID = "/subscriptions/foo/resourceGroups/bar/providers/" +
+	"Microsoft.Network/virtualNetworks/baz/subnets/default"
+
+func TestValidateProvisioningParametersWithInvalidSubnetID(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.SubnetID = "/subscriptions/foo/resourceGroups/bar"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.SubnetID = testSubnetID
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,90 @@
+package loadbalancer
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// ProvisioningParameters encapsulates Azure Load Balancer-specific
+// provisioning options
+type ProvisioningParameters struct {
+	SubnetID string `json:"subnetId"`
+}
+
+type loadBalancerInstanceDetails struct {
+	ARMDeploymentName string `json:"armDeployment"`
+	LoadBalancerName  string `json:"loadBalancerName"`
+	LoadBalancerID    string `json:"loadBalancerId"`
+	FrontendIPAddress string `json:"frontendIPAddress"`
+	// VirtualNetworkID is the ID of the virtual network the frontend is in,
+	// which is also the one that backends' IP addresses are taken to be in
+	VirtualNetworkID string `json:"virtualNetworkId"`
+}
+
+// UpdatingParameters encapsulates Azure Load Balancer-specific updating
+// options
+type UpdatingParameters struct {
+}
+
+// BindingParameters encapsulates Azure Load Balancer-specific binding options
+type BindingParameters struct {
+	// IPAddress is the private IP address of the bound consumer's endpoint
+	IPAddress    string                 `json:"ipAddress"`
+	Port         int                    `json:"port"`
+	FrontendPort int                    `json:"frontendPort"`
+	Protocol     string                 `json:"protocol"`
+	HealthProbe  *HealthProbeParameters `json:"healthProbe"`
+}
+
+// HealthProbeParameters encapsulates options for probing the health of a
+// bound consumer's endpoint
+type HealthProbeParameters struct {
+	Protocol          string `json:"protocol"`
+	Port              int    `json:"port"`
+	RequestPath       string `json:"requestPath"`
+	IntervalInSeconds int    `json:"intervalInSeconds"`
+	NumberOfProbes    int    `json:"numberOfProbes"`
+}
+
+// loadBalancerBindingDetails records the backend a binding registered. Its
+// backend pool, health probe and load-balancing rule are all named BackendName.
+type loadBalancerBindingDetails struct {
+	BackendName  string `json:"backendName"`
+	IPAddress    string `json:"ipAddress"`
+	Port         int    `json:"port"`
+	FrontendPort int    `json:"frontendPort"`
+	Protocol     string `json:"protocol"`
+}
+
+type loadBalancerCredentials struct {
+	LoadBalancerID    string `json:"loadBalancerId"`
+	FrontendIPAddress string `json:"frontendIPAddress"`
+	FrontendPort      int    `json:"frontendPort"`
+	Protocol          string `json:"protocol"`
+	BackendPoolID     string `json:"backendPoolId"`
+	BackendIPAddress  string `json:"backendIPAddress"`
+	BackendPort       int    `json:"backendPort"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &loadBalancerInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &loadBalancerBindingDetails{}
+}
@@ -0,0 +1,30 @@
+package loadbalancer
+
+import (
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) Unbind(
+	instance service.Instance,
+	bindingDetails service.BindingDetails,
+) error {
+	dt, ok := instance.Details.(*loadBalancerInstanceDetails)
+	if !ok {
+		return errors.New(
+			"error casting instance.Details as *loadBalancerInstanceDetails",
+		)
+	}
+	bd, ok := bindingDetails.(*loadBalancerBindingDetails)
+	if !ok {
+		return errors.New(
+			"error casting bindingDetails as *loadBalancerBindingDetails",
+		)
+	}
+	return s.loadBalancerManager.DeregisterBackend(
+		dt.LoadBalancerName,
+		instance.ResourceGroup,
+		bd.BackendName,
+	)
+}
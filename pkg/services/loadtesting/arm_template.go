@@ -0,0 +1,143 @@
+package loadtesting
+
+// nolint: lll
+var armTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "loadTestName": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of the load testing resource"
+      }
+    },
+    "description": {
+      "type": "string"
+    },
+    "identityType": {
+      "type": "string"
+    },
+    {{- if .userAssignedIdentities }}
+    "userAssignedIdentities": {
+      "type": "object"
+    },
+    {{- end }}
+    {{- if .encryption }}
+    "keyUrl": {
+      "type": "string"
+    },
+    "encryptionIdentityType": {
+      "type": "string"
+    },
+    "encryptionIdentityResourceId": {
+      "type": "string"
+    },
+    {{- end }}
+    {{- if .privateEndpoint }}
+    "privateEndpointName": {
+      "type": "string"
+    },
+    "subnetId": {
+      "type": "string"
+    },
+    "targetResourceId": {
+      "type": "string"
+    },
+    "groupId": {
+      "type": "string"
+    },
+    {{- end }}
+    "tags": {
+      "type": "object"
+    }
+  },
+  "variables": {
+    "apiVersion": "2022-12-01",
+    "networkApiVersion": "2023-04-01"
+  },
+  "resources": [
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[parameters('loadTestName')]",
+      "type": "Microsoft.LoadTestService/loadTests",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "identity": {
+        {{- if .userAssignedIdentities }}
+        "userAssignedIdentities": "[parameters('userAssignedIdentities')]",
+        {{- end }}
+        "type": "[parameters('identityType')]"
+      },
+      "properties": {
+        {{- if .encryption }}
+        "encryption": {
+          "keyUrl": "[parameters('keyUrl')]",
+          "identity": {
+            {{- if .encryptionIdentityResourceId }}
+            "resourceId": "[parameters('encryptionIdentityResourceId')]",
+            {{- end }}
+            "type": "[parameters('encryptionIdentityType')]"
+          }
+        },
+        {{- end }}
+        "description": "[parameters('description')]"
+      }
+    }
+    {{- if .privateEndpoint }},
+    {
+      "apiVersion": "[variables('networkApiVersion')]",
+      "name": "[parameters('privateEndpointName')]",
+      "type": "Microsoft.Network/privateEndpoints",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "properties": {
+        "subnet": {
+          "id": "[parameters('subnetId')]"
+        },
+        "privateLinkServiceConnections": [
+          {
+            "name": "[parameters('privateEndpointName')]",
+            "properties": {
+              "privateLinkServiceId": "[parameters('targetResourceId')]",
+              "groupIds": [
+                "[parameters('groupId')]"
+              ]
+            }
+          }
+        ]
+      }
+    }
+    {{- end }}
+  ],
+  "outputs": {
+    "loadTestId": {
+      "type": "string",
+      "value": "[resourceId('Microsoft.LoadTestService/loadTests', parameters('loadTestName'))]"
+    },
+    {{- if .systemAssignedIdentity }}
+    "principalId": {
+      "type": "string",
+      "value": "[reference(parameters('loadTestName'), variables('apiVersion'), 'Full').identity.principalId]"
+    },
+    {{- end }}
+    {{- if .privateEndpoint }}
+    "privateEndpointId": {
+      "type": "string",
+      "value": "[resourceId('Microsoft.Network/privateEndpoints', parameters('privateEndpointName'))]"
+    },
+    "dnsConfigs": {
+      "type": "array",
+      "value": "[reference(parameters('privateEndpointName'), variables('networkApiVersion')).customDnsConfigs]"
+    },
+    {{- end }}
+    "dataPlaneUri": {
+      "type": "string",
+      "value": "[reference(parameters('loadTestName')).dataPlaneURI]"
+    }
+  }
+}
+`)
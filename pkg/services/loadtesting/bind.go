@@ -0,0 +1,142 @@
+package loadtesting
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/loadtesting"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const defaultRole = "Load Test Contributor"
+
+// roleDefinitionIDs maps the names of the built-in Azure Load Testing roles
+// that a binding may assign to their role definition IDs
+var roleDefinitionIDs = map[string]string{
+	"Load Test Owner":       "45bb0b16-2f0c-4e78-afaa-a07599b003f6",
+	"Load Test Contributor": "749a398d-560b-491b-bb21-08924219302e",
+	"Load Test Reader":      "3ae3fb29-0000-4ccd-bf80-542e7b26e081",
+}
+
+var objectIDRegex = regexp.MustCompile(
+	`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-` +
+		`[0-9a-fA-F]{12}$`,
+)
+
+func (s *serviceManager) ValidateBindingParameters(
+	bindingParameters service.BindingParameters,
+) error {
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return errors.New(
+			"error casting bindingParameters as *loadtesting.BindingParameters",
+		)
+	}
+	if !objectIDRegex.MatchString(bp.PrincipalID) {
+		return service.NewValidationError(
+			"principalId",
+			fmt.Sprintf(`invalid principalId: "%s"`, bp.PrincipalID),
+		)
+	}
+	if _, ok := roleDefinitionIDs[bp.Role]; bp.Role != "" && !ok {
+		return service.NewValidationError(
+			"role",
+			fmt.Sprintf(
+				`invalid role: "%s"; allowed values are: %s`,
+				bp.Role,
+				strings.Join(getRoleNames(), ", "),
+			),
+		)
+	}
+	return nil
+}
+
+// Bind assigns the requested role to the principal named in the binding
+// parameters, scoped to the load testing resource alone
+func (s *serviceManager) Bind(
+	instance service.Instance,
+	bindingParameters service.BindingParameters,
+) (service.BindingDetails, error) {
+	dt, ok := instance.Details.(*loadTestingInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *loadTestingInstanceDetails",
+		)
+	}
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting bindingParameters as *loadtesting.BindingParameters",
+		)
+	}
+	bd := &loadTestingBindingDetails{
+		PrincipalID:        bp.PrincipalID,
+		Role:               bp.Role,
+		RoleAssignmentName: uuid.NewV4().String(),
+	}
+	if bd.Role == "" {
+		bd.Role = defaultRole
+	}
+	if err := s.loadTestingManager.CreateRoleAssignment(
+		dt.LoadTestID,
+		bd.RoleAssignmentName,
+		roleDefinitionIDs[bd.Role],
+		bd.PrincipalID,
+	); err != nil {
+		return nil, err
+	}
+	return bd, nil
+}
+
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	binding service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*loadTestingInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *loadTestingInstanceDetails",
+		)
+	}
+	bd, ok := binding.Details.(*loadTestingBindingDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting binding.Details as *loadTestingBindingDetails",
+		)
+	}
+	var testIDs []string
+	for _, test := range dt.TestPlans {
+		testIDs = append(testIDs, test.TestID)
+	}
+	return &Credentials{
+		DataPlaneURI: dt.DataPlaneURI,
+		Scope:        dt.LoadTestID,
+		PrincipalID:  bd.PrincipalID,
+		Role:         bd.Role,
+		RoleAssignmentID: fmt.Sprintf(
+			"%s/providers/Microsoft.Authorization/roleAssignments/%s",
+			dt.LoadTestID,
+			bd.RoleAssignmentName,
+		),
+		TriggerURI: fmt.Sprintf(
+			"%s/test-runs/{testRunId}?api-version=%s",
+			strings.TrimSuffix(dt.DataPlaneURI, "/"),
+			loadtesting.DataPlaneAPIVersion,
+		),
+		TestIDs:    testIDs,
+		DNSConfigs: dt.DNSConfigs,
+	}, nil
+}
+
+func getRoleNames() []string {
+	roles := make([]string, 0, len(roleDefinitionIDs))
+	for role := range roleDefinitionIDs {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles
+}
@@ -0,0 +1,49 @@
+package loadtesting
+
+import (
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateBindingParameters(t *testing.T) {
+	m := &module{}
+	bp := &BindingParameters{}
+	err := m.serviceManager.ValidateBindingParameters(bp)
+	assert.NotNil(t, err)
+	bp.PrincipalID = "2f8c3a4e-6b1d-4e0a-9c7f-5d3b2a1e0f96"
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.Nil(t, err)
+	bp.Role = "Load Test Janitor"
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.NotNil(t, err)
+	bp.Role = "Load Test Reader"
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.Nil(t, err)
+}
+
+func TestGetCredentialsIncludesTriggerURI(t *testing.T) {
+	m := &module{}
+	credentials, err := m.serviceManager.GetCredentials(
+		service.Instance{
+			Details: &loadTestingInstanceDetails{
+				DataPlaneURI: "https://alt.cnt-prod.loadtesting.azure.com",
+				TestPlans: []loadTestingTestDetails{
+					{TestID: "checkout", ScriptFileName: "checkout.jmx"},
+				},
+			},
+		},
+		service.Binding{
+			Details: &loadTestingBindingDetails{},
+		},
+	)
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		"https://alt.cnt-prod.loadtesting.azure.com/test-runs/{testRunId}"+
+			"?api-version=2022-11-01",
+		credentials.(*Credentials).TriggerURI,
+	)
+	assert.Equal(t, []string{"checkout"}, credentials.(*Credentials).TestIDs)
+}
@@ -0,0 +1,42 @@
+package loadtesting
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:          "d1a7e4c9-3b62-4f58-8e0a-9c5b2f7d6e14",
+				Name:        "azure-load-testing",
+				Description: "Azure Load Testing (Experimental)",
+				Bindable:    true,
+				Tags:        []string{"Azure", "Load Testing", "Performance"},
+				// Azure Load Testing is only offered in some regions
+				Locations: []string{
+					"australiaeast",
+					"brazilsouth",
+					"canadacentral",
+					"centralindia",
+					"eastasia",
+					"eastus",
+					"eastus2",
+					"japaneast",
+					"koreacentral",
+					"northeurope",
+					"southcentralus",
+					"southeastasia",
+					"uksouth",
+					"westeurope",
+					"westus2",
+				},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:          "7f2c9b48-e51d-4a36-b0c7-2d8e6a1f4b93",
+				Name:        "standard",
+				Description: "Pay-as-you-go, billed per virtual user hour",
+				Free:        false,
+			}),
+		),
+	}), nil
+}
@@ -0,0 +1,82 @@
+package loadtesting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) GetDeprovisioner(
+	service.Plan,
+) (service.Deprovisioner, error) {
+	return service.NewDeprovisioner(
+		service.NewDeprovisioningStep("deleteARMDeployment", s.deleteARMDeployment),
+		service.NewDeprovisioningStep("deleteLoadTest", s.deleteLoadTest),
+		service.NewDeprovisioningStep(
+			"deletePrivateEndpoint",
+			s.deletePrivateEndpoint,
+		),
+	)
+}
+
+func (s *serviceManager) deleteARMDeployment(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*loadTestingInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *loadTestingInstanceDetails",
+		)
+	}
+	if err := s.armDeployer.Delete(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, fmt.Errorf("error deleting ARM deployment: %s", err)
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deleteLoadTest(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*loadTestingInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *loadTestingInstanceDetails",
+		)
+	}
+	if err := s.loadTestingManager.DeleteLoadTest(
+		dt.LoadTestName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deletePrivateEndpoint(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*loadTestingInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *loadTestingInstanceDetails",
+		)
+	}
+	if dt.PrivateEndpointName == "" {
+		return dt, nil
+	}
+	if err := s.loadTestingManager.DeletePrivateEndpoint(
+		dt.PrivateEndpointName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
@@ -0,0 +1,47 @@
+package loadtesting
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/loadtesting"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer        arm.Deployer
+	loadTestingManager loadtesting.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of provisioning Azure Load Testing resources
+func New(
+	armDeployer arm.Deployer,
+	loadTestingManager loadtesting.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer:        armDeployer,
+			loadTestingManager: loadTestingManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "loadtesting"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{
+		"Microsoft.LoadTestService",
+		"Microsoft.ManagedIdentity",
+		"Microsoft.Authorization",
+		"Microsoft.Network",
+	}
+}
@@ -0,0 +1,455 @@
+package loadtesting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	identityTypeNone                       = "None"
+	identityTypeSystemAssigned             = "SystemAssigned"
+	identityTypeUserAssigned               = "UserAssigned"
+	identityTypeSystemAssignedUserAssigned = "SystemAssigned,UserAssigned"
+)
+
+var identityTypes = []string{
+	identityTypeNone,
+	identityTypeSystemAssigned,
+	identityTypeUserAssigned,
+	identityTypeSystemAssignedUserAssigned,
+}
+
+var userAssignedIdentityIDRegex = regexp.MustCompile(
+	`(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+/providers/` +
+		`Microsoft\.ManagedIdentity/userAssignedIdentities/[^/]+$`,
+)
+
+// keyURLRegex matches the URLs of Key Vault keys, with or without a version
+var keyURLRegex = regexp.MustCompile(`^https://[^/]+/keys/[^/]+(/[^/]+)?$`)
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*loadtesting.ProvisioningParameters",
+		)
+	}
+	identityType := getIdentityType(pp)
+	if !isValidIdentityType(identityType) {
+		return service.NewValidationError(
+			"identityType",
+			fmt.Sprintf(
+				`invalid identityType: "%s"; allowed values are: %s`,
+				pp.IdentityType,
+				strings.Join(identityTypes, ", "),
+			),
+		)
+	}
+	if hasUserAssignedIdentities(identityType) {
+		if len(pp.UserAssignedIdentities) == 0 {
+			return service.NewValidationError(
+				"userAssignedIdentities",
+				fmt.Sprintf(
+					`at least one user-assigned identity must be specified when `+
+						`identityType is "%s"`,
+					identityType,
+				),
+			)
+		}
+	} else if len(pp.UserAssignedIdentities) > 0 {
+		return service.NewValidationError(
+			"userAssignedIdentities",
+			fmt.Sprintf(
+				`user-assigned identities cannot be specified when identityType `+
+					`is "%s"`,
+				identityType,
+			),
+		)
+	}
+	for _, id := range pp.UserAssignedIdentities {
+		if !userAssignedIdentityIDRegex.MatchString(id) {
+			return service.NewValidationError(
+				"userAssignedIdentities",
+				fmt.Sprintf(`invalid user-assigned identity resource id: "%s"`, id),
+			)
+		}
+	}
+	if err := validateTestPlans(pp.TestPlans); err != nil {
+		return err
+	}
+	if pp.PrivateEndpoint != nil {
+		if err := validatePrivateEndpoint(pp.PrivateEndpoint); err != nil {
+			return err
+		}
+	}
+	if pp.Encryption == nil {
+		return nil
+	}
+	if !keyURLRegex.MatchString(pp.Encryption.KeyURL) {
+		return service.NewValidationError(
+			"encryption.keyUrl",
+			fmt.Sprintf(`invalid keyUrl: "%s"`, pp.Encryption.KeyURL),
+		)
+	}
+	if identityType == identityTypeNone {
+		return service.NewValidationError(
+			"encryption",
+			"encryption with a customer-managed key requires a managed identity",
+		)
+	}
+	if _, err := getEncryptionIdentity(pp); err != nil {
+		return service.NewValidationError("encryption.identity", err.Error())
+	}
+	return nil
+}
+
+func (s *serviceManager) ApplyProvisioningParametersDefaults(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*loadtesting.ProvisioningParameters",
+		)
+	}
+	pp.IdentityType = getIdentityType(pp)
+	if pp.Encryption != nil {
+		identity, err := getEncryptionIdentity(pp)
+		if err != nil {
+			return err
+		}
+		pp.Encryption.Identity = identity
+	}
+	for i := range pp.TestPlans {
+		applyTestPlanDefaults(&pp.TestPlans[i])
+	}
+	return nil
+}
+
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep("deployARMTemplate", s.deployARMTemplate),
+		service.NewProvisioningStep("createTestPlans", s.createTestPlans),
+	)
+}
+
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*loadTestingInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *loadTestingInstanceDetails",
+		)
+	}
+	dt.ARMDeploymentName = uuid.NewV4().String()
+	dt.LoadTestName = "alt-" + uuid.NewV4().String()
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*loadtesting.ProvisioningParameters",
+		)
+	}
+	if pp.PrivateEndpoint != nil {
+		dt.PrivateEndpointName = "pe-" + uuid.NewV4().String()
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deployARMTemplate(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*loadTestingInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *loadTestingInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*loadtesting.ProvisioningParameters",
+		)
+	}
+	goParams, armParams, err := buildARMTemplateParameters(pp)
+	if err != nil {
+		return nil, err
+	}
+	armParams["loadTestName"] = dt.LoadTestName
+	if pp.PrivateEndpoint != nil {
+		armParams["privateEndpointName"] = dt.PrivateEndpointName
+	}
+	outputs, err := s.armDeployer.Deploy(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateBytes,
+		goParams,
+		armParams,
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+
+	loadTestID, ok := outputs["loadTestId"].(string)
+	if !ok {
+		return nil, errors.New(
+			"error retrieving load testing resource id from deployment",
+		)
+	}
+	dt.LoadTestID = loadTestID
+
+	dataPlaneURI, ok := outputs["dataPlaneUri"].(string)
+	if !ok {
+		return nil, errors.New(
+			"error retrieving data plane URI from deployment",
+		)
+	}
+	// Azure reports the data plane's host name without a scheme
+	if !strings.HasPrefix(dataPlaneURI, "https://") {
+		dataPlaneURI = "https://" + dataPlaneURI
+	}
+	dt.DataPlaneURI = dataPlaneURI
+
+	if goParams["systemAssignedIdentity"].(bool) {
+		principalID, ok := outputs["principalId"].(string)
+		if !ok {
+			return nil, errors.New(
+				"error retrieving system-assigned identity from deployment",
+			)
+		}
+		dt.PrincipalID = principalID
+	}
+
+	if pp.PrivateEndpoint != nil {
+		privateEndpointID, ok := outputs["privateEndpointId"].(string)
+		if !ok {
+			return nil, errors.New(
+				"error retrieving private endpoint id from deployment",
+			)
+		}
+		dt.PrivateEndpointID = privateEndpointID
+		dnsConfigs, err := getDNSConfigs(outputs["dnsConfigs"])
+		if err != nil {
+			return nil, err
+		}
+		dt.DNSConfigs = dnsConfigs
+	}
+
+	return dt, nil
+}
+
+// createTestPlans creates each requested test using the load testing
+// resource's data plane. Creating a test and uploading its script both
+// replace what is already there, so a test created by an earlier, failed
+// attempt at this step is simply created again.
+func (s *serviceManager) createTestPlans(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*loadTestingInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *loadTestingInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*loadtesting.ProvisioningParameters",
+		)
+	}
+	dt.TestPlans = make([]loadTestingTestDetails, len(pp.TestPlans))
+	for i, tp := range pp.TestPlans {
+		script, err := getTestScript(tp)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.loadTestingManager.CreateOrUpdateTest(
+			dt.DataPlaneURI,
+			tp.TestID,
+			buildTestDefinition(tp, pp.PrivateEndpoint),
+		); err != nil {
+			return nil, err
+		}
+		fileName := getTestScriptFileName(tp)
+		if err := s.loadTestingManager.UploadTestScript(
+			dt.DataPlaneURI,
+			tp.TestID,
+			fileName,
+			script,
+		); err != nil {
+			return nil, err
+		}
+		dt.TestPlans[i] = loadTestingTestDetails{
+			TestID:         tp.TestID,
+			ScriptFileName: fileName,
+		}
+	}
+	return dt, nil
+}
+
+// buildARMTemplateParameters returns the Go template parameters and the ARM
+// template parameters used to deploy a load testing resource configured as
+// the given provisioning parameters describe
+func buildARMTemplateParameters(
+	pp *ProvisioningParameters,
+) (map[string]interface{}, map[string]interface{}, error) {
+	identityType := getIdentityType(pp)
+	goParams := map[string]interface{}{
+		"systemAssignedIdentity": strings.HasPrefix(
+			identityType,
+			identityTypeSystemAssigned,
+		),
+		"userAssignedIdentities": len(pp.UserAssignedIdentities) > 0,
+		"encryption":             pp.Encryption != nil,
+		"privateEndpoint":        pp.PrivateEndpoint != nil,
+	}
+	armParams := map[string]interface{}{
+		"description":  pp.Description,
+		"identityType": identityType,
+	}
+	if len(pp.UserAssignedIdentities) > 0 {
+		identities := map[string]interface{}{}
+		for _, id := range pp.UserAssignedIdentities {
+			identities[id] = map[string]interface{}{}
+		}
+		armParams["userAssignedIdentities"] = identities
+	}
+	if pp.Encryption != nil {
+		identity, err := getEncryptionIdentity(pp)
+		if err != nil {
+			return nil, nil, err
+		}
+		armParams["keyUrl"] = pp.Encryption.KeyURL
+		if identity == identityTypeSystemAssigned {
+			armParams["encryptionIdentityType"] = identityTypeSystemAssigned
+			armParams["encryptionIdentityResourceId"] = ""
+		} else {
+			armParams["encryptionIdentityType"] = identityTypeUserAssigned
+			armParams["encryptionIdentityResourceId"] = identity
+		}
+		goParams["encryptionIdentityResourceId"] =
+			identity != identityTypeSystemAssigned
+	}
+	if pp.PrivateEndpoint != nil {
+		armParams["subnetId"] = pp.PrivateEndpoint.SubnetID
+		armParams["targetResourceId"] = pp.PrivateEndpoint.TargetResourceID
+		armParams["groupId"] = pp.PrivateEndpoint.GroupID
+	}
+	return goParams, armParams, nil
+}
+
+// getDNSConfigs converts a private endpoint's DNS configurations, as output
+// by a deployment, to PrivateEndpointDNSConfigs. Azure doesn't report any for
+// a private endpoint whose connection awaits the target owner's approval.
+func getDNSConfigs(output interface{}) ([]PrivateEndpointDNSConfig, error) {
+	if output == nil {
+		return nil, nil
+	}
+	configs, ok := output.([]interface{})
+	if !ok {
+		return nil, errors.New(
+			"error retrieving private endpoint DNS configurations from deployment",
+		)
+	}
+	dnsConfigs := make([]PrivateEndpointDNSConfig, len(configs))
+	for i, c := range configs {
+		config, ok := c.(map[string]interface{})
+		if !ok {
+			return nil, errors.New(
+				"error retrieving private endpoint DNS configuration from deployment",
+			)
+		}
+		dnsConfigs[i].FQDN, _ = config["fqdn"].(string)
+		ipAddresses, _ := config["ipAddresses"].([]interface{})
+		for _, ipAddress := range ipAddresses {
+			if ip, ok := ipAddress.(string); ok {
+				dnsConfigs[i].IPAddresses = append(dnsConfigs[i].IPAddresses, ip)
+			}
+		}
+	}
+	return dnsConfigs, nil
+}
+
+func getIdentityType(pp *ProvisioningParameters) string {
+	if pp.IdentityType == "" {
+		return identityTypeNone
+	}
+	return pp.IdentityType
+}
+
+func isValidIdentityType(identityType string) bool {
+	for _, t := range identityTypes {
+		if identityType == t {
+			return true
+		}
+	}
+	return false
+}
+
+func hasUserAssignedIdentities(identityType string) bool {
+	return identityType == identityTypeUserAssigned ||
+		identityType == identityTypeSystemAssignedUserAssigned
+}
+
+// getEncryptionIdentity returns the managed identity that the load testing
+// resource uses to access its customer-managed key. If none was specified,
+// the system-assigned identity is used if there is one, failing which the sole
+// user-assigned identity is used. An identity that was specified must be one
+// the resource is configured with.
+func getEncryptionIdentity(pp *ProvisioningParameters) (string, error) {
+	identityType := getIdentityType(pp)
+	identity := pp.Encryption.Identity
+	if identity == "" {
+		if strings.HasPrefix(identityType, identityTypeSystemAssigned) {
+			return identityTypeSystemAssigned, nil
+		}
+		if len(pp.UserAssignedIdentities) == 1 {
+			return pp.UserAssignedIdentities[0], nil
+		}
+		return "", errors.New(
+			"the identity used to access the key must be specified when the " +
+				"resource has more than one user-assigned identity",
+		)
+	}
+	if identity == identityTypeSystemAssigned {
+		if !strings.HasPrefix(identityType, identityTypeSystemAssigned) {
+			return "", fmt.Errorf(
+				`the system-assigned identity cannot be used when identityType is `+
+					`"%s"`,
+				identityType,
+			)
+		}
+		return identity, nil
+	}
+	for _, id := range pp.UserAssignedIdentities {
+		if strings.EqualFold(id, identity) {
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf(
+		`identity "%s" is neither "SystemAssigned" nor one of the resource's `+
+			`user-assigned identities`,
+		identity,
+	)
+}
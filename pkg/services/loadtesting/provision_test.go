@@ -0,0 +1,288 @@
+package loadtesting
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testUserAssignedIdentityID = "/subscriptions/sub/resourceGroups/rg/" +
+		"providers/Microsoft.ManagedIdentity/userAssignedIdentities/id"
+	testKeyURL   = "https://vault.vault.azure.net/keys/key"
+	testSubnetID = "/subscriptions/sub/resourceGroups/rg/providers/" +
+		"Microsoft.Network/virtualNetworks/vnet/subnets/tests"
+	testTargetResourceID = "/subscriptions/sub/resourceGroups/rg/" +
+		"providers/Microsoft.Web/sites/app"
+)
+
+func TestValidateProvisioningParametersWithDefaults(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidIdentityType(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		IdentityType: "Everything",
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+}
+
+func TestValidateProvisioningParametersWithUserAssignedIdentities(
+	t *testing.T,
+) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		IdentityType: identityTypeUserAssigned,
+	}
+	// At least one identity is required
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.UserAssignedIdentities = []string{"bogus"}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.UserAssignedIdentities = []string{testUserAssignedIdentityID}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+	// Identities are only allowed if the identity type calls for them
+	pp.IdentityType = identityTypeSystemAssigned
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+}
+
+func TestValidateProvisioningParametersWithEncryption(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		Encryption: &EncryptionParameters{
+			KeyURL: testKeyURL,
+		},
+	}
+	// Encryption requires an identity
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.IdentityType = identityTypeSystemAssigned
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+	pp.Encryption.KeyURL = "https://vault.vault.azure.net/secrets/key"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidEncryptionIdentity(
+	t *testing.T,
+) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		IdentityType:           identityTypeUserAssigned,
+		UserAssignedIdentities: []string{testUserAssignedIdentityID},
+		Encryption: &EncryptionParameters{
+			KeyURL:   testKeyURL,
+			Identity: identityTypeSystemAssigned,
+		},
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.Encryption.Identity = testUserAssignedIdentityID + "-other"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.Encryption.Identity = testUserAssignedIdentityID
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestApplyProvisioningParametersDefaults(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		IdentityType:           identityTypeUserAssigned,
+		UserAssignedIdentities: []string{testUserAssignedIdentityID},
+		Encryption: &EncryptionParameters{
+			KeyURL: testKeyURL,
+		},
+	}
+	err := m.serviceManager.ApplyProvisioningParametersDefaults(pp)
+	assert.Nil(t, err)
+	assert.Equal(t, testUserAssignedIdentityID, pp.Encryption.Identity)
+	pp = &ProvisioningParameters{}
+	err = m.serviceManager.ApplyProvisioningParametersDefaults(pp)
+	assert.Nil(t, err)
+	assert.Equal(t, identityTypeNone, pp.IdentityType)
+}
+
+func TestBuildARMTemplateParameters(t *testing.T) {
+	goParams, armParams, err := buildARMTemplateParameters(
+		&ProvisioningParameters{
+			IdentityType:           identityTypeSystemAssignedUserAssigned,
+			UserAssignedIdentities: []string{testUserAssignedIdentityID},
+			Encryption: &EncryptionParameters{
+				KeyURL:   testKeyURL,
+				Identity: testUserAssignedIdentityID,
+			},
+		},
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, true, goParams["systemAssignedIdentity"])
+	assert.Equal(t, true, goParams["userAssignedIdentities"])
+	assert.Equal(t, true, goParams["encryptionIdentityResourceId"])
+	assert.Equal(
+		t,
+		identityTypeUserAssigned,
+		armParams["encryptionIdentityType"],
+	)
+	assert.Equal(
+		t,
+		testUserAssignedIdentityID,
+		armParams["encryptionIdentityResourceId"],
+	)
+}
+
+func TestValidateProvisioningParametersWithTestPlans(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		TestPlans: []TestPlanParameters{
+			{
+				TestID:       "checkout",
+				JMeterScript: `<jmeterTestPlan version="1.2"><hashTree/></jmeterTestPlan>`,
+			},
+			{
+				TestID: "home-page",
+				Requests: []TestRequestParameters{
+					{URL: "https://app.internal.example.com/"},
+				},
+				RampUpSeconds: 30,
+			},
+		},
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+	// Test IDs must be unique
+	pp.TestPlans[1].TestID = "checkout"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.TestPlans[1].TestID = "Home Page"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.TestPlans[1].TestID = "home-page"
+	// Ramp up can't outlast the test
+	pp.TestPlans[1].DurationSeconds = 20
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.TestPlans[1].DurationSeconds = 0
+	pp.TestPlans[1].Requests[0].URL = "app.internal.example.com"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.TestPlans[1].Requests[0].URL = "https://app.internal.example.com/"
+	pp.TestPlans[1].Requests[0].Method = "TRACE"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.TestPlans[1].Requests[0].Method = "post"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidJMeterScript(
+	t *testing.T,
+) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		TestPlans: []TestPlanParameters{
+			{
+				TestID:       "checkout",
+				JMeterScript: "<jmeterTestPlan>",
+			},
+		},
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.TestPlans[0].JMeterScript = "<testPlan/>"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	// A script configures its own load
+	pp.TestPlans[0].JMeterScript = "<jmeterTestPlan/>"
+	pp.TestPlans[0].VirtualUsers = 50
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.TestPlans[0].VirtualUsers = 0
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithPrivateEndpoint(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		PrivateEndpoint: &PrivateEndpointParameters{
+			SubnetID:         "bogus",
+			TargetResourceID: testTargetResourceID,
+			GroupID:          "sites",
+		},
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.PrivateEndpoint.SubnetID = testSubnetID
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+	pp.PrivateEndpoint.GroupID = ""
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+}
+
+func TestGetTestScriptFromRequests(t *testing.T) {
+	script, err := getTestScript(TestPlanParameters{
+		TestID: "search",
+		Requests: []TestRequestParameters{
+			{
+				URL:     "http://app.internal.example.com:8080/search?q=a&b",
+				Method:  "post",
+				Headers: map[string]string{"Content-Type": "application/json"},
+				Body:    `{"query": "<a>"}`,
+			},
+		},
+	})
+	assert.Nil(t, err)
+	jmx := struct {
+		Samplers []struct {
+			Props     []string `xml:"stringProp"`
+			Arguments []string `xml:"elementProp>collectionProp>elementProp>stringProp"` // nolint: lll
+		} `xml:"hashTree>hashTree>hashTree>HTTPSamplerProxy"`
+	}{}
+	assert.Nil(t, xml.Unmarshal(script, &jmx))
+	assert.Len(t, jmx.Samplers, 1)
+	assert.Equal(
+		t,
+		[]string{
+			"app.internal.example.com",
+			"8080",
+			"http",
+			"/search?q=a&b",
+			"POST",
+		},
+		jmx.Samplers[0].Props,
+	)
+	// The body is an argument of the request
+	assert.Equal(
+		t,
+		[]string{`{"query": "<a>"}`, "="},
+		jmx.Samplers[0].Arguments,
+	)
+}
+
+func TestBuildARMTemplateParametersWithPrivateEndpoint(t *testing.T) {
+	goParams, armParams, err := buildARMTemplateParameters(
+		&ProvisioningParameters{
+			PrivateEndpoint: &PrivateEndpointParameters{
+				SubnetID:         testSubnetID,
+				TargetResourceID: testTargetResourceID,
+				GroupID:          "sites",
+			},
+		},
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, true, goParams["privateEndpoint"])
+	assert.Equal(t, testSubnetID, armParams["subnetId"])
+	assert.Equal(t, testTargetResourceID, armParams["targetResourceId"])
+	assert.Equal(t, "sites", armParams["groupId"])
+}
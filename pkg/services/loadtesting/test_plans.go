@@ -0,0 +1,400 @@
+package loadtesting
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"text/template"
+
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+const (
+	defaultEngineInstances = 1
+	defaultVirtualUsers    = 10
+	defaultDurationSeconds = 120
+	defaultRequestMethod   = "GET"
+)
+
+var requestMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD"}
+
+// testIDRegex matches the IDs Azure Load Testing allows tests to have
+var testIDRegex = regexp.MustCompile(`^[a-z0-9_-]{2,50}$`)
+
+var resourceIDRegex = regexp.MustCompile(
+	`(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+/providers/[^/]+/[^/]+/` +
+		`[^/]+(/[^/]+/[^/]+)*$`,
+)
+
+func validateTestPlans(testPlans []TestPlanParameters) error {
+	testIDs := map[string]bool{}
+	for i, tp := range testPlans {
+		field := fmt.Sprintf("testPlans[%d]", i)
+		if !testIDRegex.MatchString(tp.TestID) {
+			return service.NewValidationError(
+				field+".testId",
+				fmt.Sprintf(
+					`invalid testId: "%s"; test IDs must be 2 to 50 lowercase `+
+						`letters, digits, hyphens or underscores`,
+					tp.TestID,
+				),
+			)
+		}
+		if testIDs[tp.TestID] {
+			return service.NewValidationError(
+				field+".testId",
+				fmt.Sprintf(`testId "%s" is used by more than one test`, tp.TestID),
+			)
+		}
+		testIDs[tp.TestID] = true
+		if tp.EngineInstances < 0 {
+			return service.NewValidationError(
+				field+".engineInstances",
+				"engineInstances must be positive",
+			)
+		}
+		if tp.JMeterScript != "" {
+			if err := validateJMeterTestPlan(field, tp); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := validateRequestTestPlan(field, tp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateJMeterTestPlan(field string, tp TestPlanParameters) error {
+	if len(tp.Requests) > 0 {
+		return service.NewValidationError(
+			field,
+			"a test is defined by either a jmeterScript or requests, not both",
+		)
+	}
+	if tp.VirtualUsers != 0 || tp.DurationSeconds != 0 || tp.RampUpSeconds != 0 {
+		return service.NewValidationError(
+			field,
+			"virtualUsers, durationSeconds and rampUpSeconds cannot be specified "+
+				"for a test defined by a jmeterScript, which configures its own "+
+				"load",
+		)
+	}
+	// Azure only validates a script after it has been uploaded, so catch what
+	// plainly isn't a JMeter test plan now
+	root := struct {
+		XMLName xml.Name
+	}{}
+	if err := xml.Unmarshal([]byte(tp.JMeterScript), &root); err != nil {
+		return service.NewValidationError(
+			field+".jmeterScript",
+			fmt.Sprintf("jmeterScript is not well-formed XML: %s", err),
+		)
+	}
+	if root.XMLName.Local != "jmeterTestPlan" {
+		return service.NewValidationError(
+			field+".jmeterScript",
+			fmt.Sprintf(
+				`jmeterScript's root element is "%s"; it must be "jmeterTestPlan"`,
+				root.XMLName.Local,
+			),
+		)
+	}
+	return nil
+}
+
+func validateRequestTestPlan(field string, tp TestPlanParameters) error {
+	if len(tp.Requests) == 0 {
+		return service.NewValidationError(
+			field,
+			"a test must be defined by either a jmeterScript or requests",
+		)
+	}
+	for j, req := range tp.Requests {
+		reqField := fmt.Sprintf("%s.requests[%d]", field, j)
+		u, err := url.Parse(req.URL)
+		if err != nil ||
+			(u.Scheme != "http" && u.Scheme != "https") ||
+			u.Hostname() == "" {
+			return service.NewValidationError(
+				reqField+".url",
+				fmt.Sprintf(`invalid url: "%s"; urls must be absolute http or `+
+					`https urls`, req.URL),
+			)
+		}
+		if req.Method != "" && !isValidRequestMethod(req.Method) {
+			return service.NewValidationError(
+				reqField+".method",
+				fmt.Sprintf(
+					`invalid method: "%s"; allowed values are: %s`,
+					req.Method,
+					strings.Join(requestMethods, ", "),
+				),
+			)
+		}
+	}
+	if tp.VirtualUsers < 0 {
+		return service.NewValidationError(
+			field+".virtualUsers",
+			"virtualUsers must be positive",
+		)
+	}
+	if tp.DurationSeconds < 0 {
+		return service.NewValidationError(
+			field+".durationSeconds",
+			"durationSeconds must be positive",
+		)
+	}
+	if tp.RampUpSeconds < 0 || tp.RampUpSeconds > getDurationSeconds(tp) {
+		return service.NewValidationError(
+			field+".rampUpSeconds",
+			"rampUpSeconds must be positive and no greater than durationSeconds",
+		)
+	}
+	return nil
+}
+
+func validatePrivateEndpoint(pe *PrivateEndpointParameters) error {
+	if !az.IsValidSubnetID(pe.SubnetID) {
+		return service.NewValidationError(
+			"privateEndpoint.subnetId",
+			fmt.Sprintf(`invalid subnet resource id: "%s"`, pe.SubnetID),
+		)
+	}
+	if !resourceIDRegex.MatchString(pe.TargetResourceID) {
+		return service.NewValidationError(
+			"privateEndpoint.targetResourceId",
+			fmt.Sprintf(`invalid resource id: "%s"`, pe.TargetResourceID),
+		)
+	}
+	if pe.GroupID == "" {
+		return service.NewValidationError(
+			"privateEndpoint.groupId",
+			"groupId is required",
+		)
+	}
+	return nil
+}
+
+func applyTestPlanDefaults(tp *TestPlanParameters) {
+	if tp.DisplayName == "" {
+		tp.DisplayName = tp.TestID
+	}
+	tp.EngineInstances = getEngineInstances(*tp)
+	if tp.JMeterScript != "" {
+		return
+	}
+	tp.VirtualUsers = getVirtualUsers(*tp)
+	tp.DurationSeconds = getDurationSeconds(*tp)
+	for i := range tp.Requests {
+		tp.Requests[i].Method = getRequestMethod(tp.Requests[i])
+	}
+}
+
+// buildTestDefinition returns the definition of the given test, sans script,
+// in the form the data plane accepts. Tests whose engines are injected into a
+// subnet can reach whatever that subnet can, including private endpoints.
+func buildTestDefinition(
+	tp TestPlanParameters,
+	pe *PrivateEndpointParameters,
+) map[string]interface{} {
+	displayName := tp.DisplayName
+	if displayName == "" {
+		displayName = tp.TestID
+	}
+	test := map[string]interface{}{
+		"displayName": displayName,
+		"description": tp.Description,
+		"loadTestConfiguration": map[string]interface{}{
+			"engineInstances": getEngineInstances(tp),
+		},
+	}
+	if pe != nil {
+		test["subnetId"] = pe.SubnetID
+	}
+	return test
+}
+
+// getTestScript returns the JMeter script that runs the given test: either the
+// test's own script, or one generated from its requests
+func getTestScript(tp TestPlanParameters) ([]byte, error) {
+	if tp.JMeterScript != "" {
+		return []byte(tp.JMeterScript), nil
+	}
+	samplers := make([]jmeterSampler, len(tp.Requests))
+	for i, req := range tp.Requests {
+		u, err := url.Parse(req.URL)
+		if err != nil {
+			return nil, fmt.Errorf(`error parsing url "%s": %s`, req.URL, err)
+		}
+		port := u.Port()
+		if port == "" {
+			port = "443"
+			if u.Scheme == "http" {
+				port = "80"
+			}
+		}
+		samplers[i] = jmeterSampler{
+			Name:     fmt.Sprintf("%s %s", getRequestMethod(req), req.URL),
+			Protocol: u.Scheme,
+			Domain:   u.Hostname(),
+			Port:     port,
+			Path:     u.RequestURI(),
+			Method:   getRequestMethod(req),
+			Headers:  req.Headers,
+			Body:     req.Body,
+		}
+	}
+	buf := &bytes.Buffer{}
+	if err := jmeterScriptTemplate.Execute(buf, map[string]interface{}{
+		"name":            tp.TestID,
+		"virtualUsers":    getVirtualUsers(tp),
+		"rampUpSeconds":   tp.RampUpSeconds,
+		"durationSeconds": getDurationSeconds(tp),
+		"samplers":        samplers,
+	}); err != nil {
+		return nil, fmt.Errorf("error generating JMeter script: %s", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// getTestScriptFileName returns the name that the given test's script is
+// uploaded as
+func getTestScriptFileName(tp TestPlanParameters) string {
+	return tp.TestID + ".jmx"
+}
+
+func getEngineInstances(tp TestPlanParameters) int {
+	if tp.EngineInstances == 0 {
+		return defaultEngineInstances
+	}
+	return tp.EngineInstances
+}
+
+func getVirtualUsers(tp TestPlanParameters) int {
+	if tp.VirtualUsers == 0 {
+		return defaultVirtualUsers
+	}
+	return tp.VirtualUsers
+}
+
+func getDurationSeconds(tp TestPlanParameters) int {
+	if tp.DurationSeconds == 0 {
+		return defaultDurationSeconds
+	}
+	return tp.DurationSeconds
+}
+
+func getRequestMethod(req TestRequestParameters) string {
+	if req.Method == "" {
+		return defaultRequestMethod
+	}
+	return strings.ToUpper(req.Method)
+}
+
+func isValidRequestMethod(method string) bool {
+	for _, m := range requestMethods {
+		if strings.EqualFold(method, m) {
+			return true
+		}
+	}
+	return false
+}
+
+type jmeterSampler struct {
+	Name     string
+	Protocol string
+	Domain   string
+	Port     string
+	Path     string
+	Method   string
+	Headers  map[string]string
+	Body     string
+}
+
+func escapeXML(s interface{}) (string, error) {
+	buf := &bytes.Buffer{}
+	if err := xml.EscapeText(buf, []byte(fmt.Sprint(s))); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// jmeterScriptTemplate generates a JMeter test plan in which a fixed number of
+// virtual users make the given requests in turn, over and over, for a fixed
+// duration
+var jmeterScriptTemplate = template.Must(
+	template.New("jmx").
+		Funcs(template.FuncMap{"xml": escapeXML}).
+		Parse(jmeterScriptTemplateText),
+)
+
+// nolint: lll
+var jmeterScriptTemplateText = `<?xml version="1.0" encoding="UTF-8"?>
+<jmeterTestPlan version="1.2" properties="5.0" jmeter="5.5">
+  <hashTree>
+    <TestPlan guiclass="TestPlanGui" testclass="TestPlan" testname="{{ xml .name }}" enabled="true">
+      <elementProp name="TestPlan.user_defined_variables" elementType="Arguments" guiclass="ArgumentsPanel" testclass="Arguments" enabled="true">
+        <collectionProp name="Arguments.arguments"/>
+      </elementProp>
+    </TestPlan>
+    <hashTree>
+      <ThreadGroup guiclass="ThreadGroupGui" testclass="ThreadGroup" testname="Virtual users" enabled="true">
+        <stringProp name="ThreadGroup.on_sample_error">continue</stringProp>
+        <elementProp name="ThreadGroup.main_controller" elementType="LoopController" guiclass="LoopControlPanel" testclass="LoopController" enabled="true">
+          <boolProp name="LoopController.continue_forever">false</boolProp>
+          <intProp name="LoopController.loops">-1</intProp>
+        </elementProp>
+        <stringProp name="ThreadGroup.num_threads">{{ .virtualUsers }}</stringProp>
+        <stringProp name="ThreadGroup.ramp_time">{{ .rampUpSeconds }}</stringProp>
+        <boolProp name="ThreadGroup.scheduler">true</boolProp>
+        <stringProp name="ThreadGroup.duration">{{ .durationSeconds }}</stringProp>
+        <stringProp name="ThreadGroup.delay">0</stringProp>
+      </ThreadGroup>
+      <hashTree>
+        {{- range .samplers }}
+        <HTTPSamplerProxy guiclass="HttpTestSampleGui" testclass="HTTPSamplerProxy" testname="{{ xml .Name }}" enabled="true">
+          <boolProp name="HTTPSampler.postBodyRaw">true</boolProp>
+          <elementProp name="HTTPsampler.Arguments" elementType="Arguments">
+            <collectionProp name="Arguments.arguments">
+              <elementProp name="" elementType="HTTPArgument">
+                <boolProp name="HTTPArgument.always_encode">false</boolProp>
+                <stringProp name="Argument.value">{{ xml .Body }}</stringProp>
+                <stringProp name="Argument.metadata">=</stringProp>
+              </elementProp>
+            </collectionProp>
+          </elementProp>
+          <stringProp name="HTTPSampler.domain">{{ xml .Domain }}</stringProp>
+          <stringProp name="HTTPSampler.port">{{ xml .Port }}</stringProp>
+          <stringProp name="HTTPSampler.protocol">{{ xml .Protocol }}</stringProp>
+          <stringProp name="HTTPSampler.path">{{ xml .Path }}</stringProp>
+          <stringProp name="HTTPSampler.method">{{ xml .Method }}</stringProp>
+          <boolProp name="HTTPSampler.follow_redirects">true</boolProp>
+          <boolProp name="HTTPSampler.use_keepalive">true</boolProp>
+        </HTTPSamplerProxy>
+        <hashTree>
+          {{- if .Headers }}
+          <HeaderManager guiclass="HeaderPanel" testclass="HeaderManager" testname="Headers" enabled="true">
+            <collectionProp name="HeaderManager.headers">
+              {{- range $name, $value := .Headers }}
+              <elementProp name="{{ xml $name }}" elementType="Header">
+                <stringProp name="Header.name">{{ xml $name }}</stringProp>
+                <stringProp name="Header.value">{{ xml $value }}</stringProp>
+              </elementProp>
+              {{- end }}
+            </collectionProp>
+          </HeaderManager>
+          <hashTree/>
+          {{- end }}
+        </hashTree>
+        {{- end }}
+      </hashTree>
+    </hashTree>
+  </hashTree>
+</jmeterTestPlan>
+`
@@ -0,0 +1,161 @@
+package loadtesting
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// ProvisioningParameters encapsulates Azure Load Testing-specific
+// provisioning options
+type ProvisioningParameters struct {
+	Description string `json:"description"`
+	// IdentityType is one of "None", "SystemAssigned", "UserAssigned" or
+	// "SystemAssigned,UserAssigned"
+	IdentityType string `json:"identityType"`
+	// UserAssignedIdentities are the resource IDs of the user-assigned managed
+	// identities to associate with the resource
+	UserAssignedIdentities []string              `json:"userAssignedIdentities"`
+	Encryption             *EncryptionParameters `json:"encryption"`
+	// TestPlans are the tests to create on the resource, ready to be run
+	TestPlans       []TestPlanParameters       `json:"testPlans"`
+	PrivateEndpoint *PrivateEndpointParameters `json:"privateEndpoint"`
+}
+
+// TestPlanParameters encapsulates the definition of a single test. A test is
+// either defined by a JMeter script, or by a list of requests that are made
+// repeatedly by a number of virtual users for a fixed duration.
+type TestPlanParameters struct {
+	TestID      string `json:"testId"`
+	DisplayName string `json:"displayName"`
+	Description string `json:"description"`
+	// EngineInstances is the number of test engines the test is run on in
+	// parallel
+	EngineInstances int `json:"engineInstances"`
+	// JMeterScript is the content of a JMeter (.jmx) test plan
+	JMeterScript string                  `json:"jmeterScript"`
+	Requests     []TestRequestParameters `json:"requests"`
+	// VirtualUsers, DurationSeconds and RampUpSeconds only apply to tests
+	// defined by requests, and are per engine instance
+	VirtualUsers    int `json:"virtualUsers"`
+	DurationSeconds int `json:"durationSeconds"`
+	RampUpSeconds   int `json:"rampUpSeconds"`
+}
+
+// TestRequestParameters encapsulates a single request made by a test that is
+// defined by requests rather than by a JMeter script
+type TestRequestParameters struct {
+	URL     string            `json:"url"`
+	Method  string            `json:"method"`
+	Headers map[string]string `json:"headers"`
+	Body    string            `json:"body"`
+}
+
+// PrivateEndpointParameters encapsulates options for reaching an internal
+// resource from tests over a private endpoint. The private endpoint is created
+// in the given subnet, and tests' engines are injected into the same subnet.
+type PrivateEndpointParameters struct {
+	SubnetID string `json:"subnetId"`
+	// TargetResourceID is the resource ID of the resource the private endpoint
+	// connects to, e.g. a web app
+	TargetResourceID string `json:"targetResourceId"`
+	// GroupID is the target resource's sub-resource to connect to, e.g. "sites"
+	// for a web app or "blob" for a storage account
+	GroupID string `json:"groupId"`
+}
+
+// PrivateEndpointDNSConfig is a fully qualified domain name of a private
+// endpoint's target and the private IP addresses it must resolve to for
+// traffic to flow over the private endpoint
+type PrivateEndpointDNSConfig struct {
+	FQDN        string   `json:"fqdn"`
+	IPAddresses []string `json:"ipAddresses"`
+}
+
+// EncryptionParameters encapsulates options for encrypting a load testing
+// resource's data using a customer-managed key
+type EncryptionParameters struct {
+	KeyURL string `json:"keyUrl"`
+	// Identity is the managed identity used to access the key: either
+	// "SystemAssigned" or the resource ID of one of the resource's user-assigned
+	// identities
+	Identity string `json:"identity"`
+}
+
+type loadTestingInstanceDetails struct {
+	ARMDeploymentName string `json:"armDeployment"`
+	LoadTestName      string `json:"loadTestName"`
+	LoadTestID        string `json:"loadTestId"`
+	DataPlaneURI      string `json:"dataPlaneUri"`
+	// PrincipalID is empty unless the resource has a system-assigned identity
+	PrincipalID string                   `json:"principalId"`
+	TestPlans   []loadTestingTestDetails `json:"testPlans"`
+	// The private endpoint fields are empty unless one was requested
+	PrivateEndpointName string                     `json:"privateEndpointName"`
+	PrivateEndpointID   string                     `json:"privateEndpointId"`
+	DNSConfigs          []PrivateEndpointDNSConfig `json:"dnsConfigs"`
+}
+
+type loadTestingTestDetails struct {
+	TestID string `json:"testId"`
+	// ScriptFileName is the name the test's JMeter script was uploaded as
+	ScriptFileName string `json:"scriptFileName"`
+}
+
+// UpdatingParameters encapsulates Azure Load Testing-specific updating
+// options
+type UpdatingParameters struct {
+}
+
+// BindingParameters encapsulates Azure Load Testing-specific binding options
+type BindingParameters struct {
+	PrincipalID string `json:"principalId"`
+	Role        string `json:"role"`
+}
+
+type loadTestingBindingDetails struct {
+	PrincipalID        string `json:"principalId"`
+	Role               string `json:"role"`
+	RoleAssignmentName string `json:"roleAssignmentName"`
+}
+
+// Credentials encapsulates Azure Load Testing-specific connection details
+type Credentials struct {
+	DataPlaneURI string `json:"dataPlaneUri"`
+	// Scope is the resource ID of the load testing resource, which is also the
+	// scope at which the principal's role was assigned
+	Scope            string `json:"scope"`
+	PrincipalID      string `json:"principalId"`
+	Role             string `json:"role"`
+	RoleAssignmentID string `json:"roleAssignmentId"`
+	// TriggerURI is a URI template for starting a test run. A run is started by
+	// choosing a new run ID to expand {testRunId} with and sending a PATCH
+	// request, whose body names the test to run, to the resulting URI.
+	TriggerURI string   `json:"triggerUri"`
+	TestIDs    []string `json:"testIds,omitempty"`
+	// DNSConfigs are the names that must resolve to a private endpoint for tests
+	// to reach its target over it
+	DNSConfigs []PrivateEndpointDNSConfig `json:"dnsConfigs,omitempty"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &loadTestingInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &loadTestingBindingDetails{}
+}
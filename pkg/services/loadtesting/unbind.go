@@ -0,0 +1,29 @@
+package loadtesting
+
+import (
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) Unbind(
+	instance service.Instance,
+	bindingDetails service.BindingDetails,
+) error {
+	dt, ok := instance.Details.(*loadTestingInstanceDetails)
+	if !ok {
+		return errors.New(
+			"error casting instance.Details as *loadTestingInstanceDetails",
+		)
+	}
+	bd, ok := bindingDetails.(*loadTestingBindingDetails)
+	if !ok {
+		return errors.New(
+			"error casting bindingDetails as *loadTestingBindingDetails",
+		)
+	}
+	return s.loadTestingManager.DeleteRoleAssignment(
+		dt.LoadTestID,
+		bd.RoleAssignmentName,
+	)
+}
@@ -0,0 +1,98 @@
+package maintenance
+
+// nolint: lll
+var armTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "configurationName": {
+      "type": "string"
+    },
+    "maintenanceScope": {
+      "type": "string"
+    },
+    "startDateTime": {
+      "type": "string"
+    },
+    "expirationDateTime": {
+      "type": "string",
+      "defaultValue": ""
+    },
+    "duration": {
+      "type": "string"
+    },
+    "timeZone": {
+      "type": "string"
+    },
+    "recurEvery": {
+      "type": "string"
+    },
+    {{- if .inGuestPatch }}
+    "rebootSetting": {
+      "type": "string",
+      "allowedValues": [
+        "IfRequired",
+        "Never",
+        "Always"
+      ]
+    },
+    "windowsClassifications": {
+      "type": "array"
+    },
+    "linuxClassifications": {
+      "type": "array"
+    },
+    {{- end }}
+    "tags": {
+      "type": "object"
+    }
+  },
+  "variables": {
+    "apiVersion": "2023-04-01"
+  },
+  "resources": [
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[parameters('configurationName')]",
+      "type": "Microsoft.Maintenance/maintenanceConfigurations",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "properties": {
+        "maintenanceScope": "[parameters('maintenanceScope')]",
+        "visibility": "Custom",
+        "maintenanceWindow": {
+          "startDateTime": "[parameters('startDateTime')]",
+          "expirationDateTime": "[if(empty(parameters('expirationDateTime')), json('null'), parameters('expirationDateTime'))]",
+          "duration": "[parameters('duration')]",
+          "timeZone": "[parameters('timeZone')]",
+          "recurEvery": "[parameters('recurEvery')]"
+        }
+        {{- if .inGuestPatch }},
+        "extensionProperties": {
+          "InGuestPatchMode": "User"
+        },
+        "installPatches": {
+          "rebootSetting": "[parameters('rebootSetting')]",
+          "windowsParameters": {
+            "classificationsToInclude": "[parameters('windowsClassifications')]"
+          },
+          "linuxParameters": {
+            "classificationsToInclude": "[parameters('linuxClassifications')]"
+          }
+        }
+        {{- end }}
+      }
+    }
+  ],
+  "outputs": {
+    "configurationId": {
+      "type": "string",
+      "value": "[resourceId('Microsoft.Maintenance/maintenanceConfigurations', parameters('configurationName'))]"
+    }
+  }
+}
+`)
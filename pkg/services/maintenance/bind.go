@@ -0,0 +1,146 @@
+package maintenance
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	log "github.com/Sirupsen/logrus"
+	uuid "github.com/satori/go.uuid"
+)
+
+func (s *serviceManager) ValidateBindingParameters(
+	bindingParameters service.BindingParameters,
+) error {
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return errors.New(
+			"error casting bindingParameters as *maintenance.BindingParameters",
+		)
+	}
+	// Which types of resources the configuration can be assigned to depends on
+	// its scope, which isn't known until the binding is carried out
+	return validateResourceIDs("resourceIds", "", bp.ResourceIDs)
+}
+
+// Bind applies the configuration to the binding's resources, if any, through
+// assignments of the binding's own. Resources the configuration was applied
+// to when it was provisioned can't be assigned again.
+func (s *serviceManager) Bind(
+	instance service.Instance,
+	bindingParameters service.BindingParameters,
+) (service.BindingDetails, error) {
+	dt, ok := instance.Details.(*maintenanceInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *maintenanceInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*maintenance.ProvisioningParameters",
+		)
+	}
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting bindingParameters as *maintenance.BindingParameters",
+		)
+	}
+	if err := validateResourceIDs(
+		"resourceIds",
+		getMaintenanceScope(pp),
+		bp.ResourceIDs,
+	); err != nil {
+		return nil, err
+	}
+	for _, resourceID := range bp.ResourceIDs {
+		if containsFold(pp.ResourceIDs, resourceID) {
+			return nil, service.NewValidationError(
+				"resourceIds",
+				fmt.Sprintf(
+					`resource "%s" was assigned to the configuration when it was `+
+						`provisioned`,
+					resourceID,
+				),
+			)
+		}
+	}
+	bd := &maintenanceBindingDetails{
+		AssignmentName: "binding-" + uuid.NewV4().String(),
+		ResourceIDs:    bp.ResourceIDs,
+	}
+	for i, resourceID := range bp.ResourceIDs {
+		if err := s.maintenanceManager.AssignConfiguration(
+			dt.ConfigurationID,
+			resourceID,
+			instance.Location,
+			bd.AssignmentName,
+		); err != nil {
+			// The binding won't be recorded, so nothing would otherwise remove the
+			// assignments that were already made
+			s.unassignResources(bp.ResourceIDs[:i], bd.AssignmentName)
+			return nil, err
+		}
+	}
+	return bd, nil
+}
+
+// unassignResources makes a best effort to remove the named assignments of
+// the given resources. Failures are logged, but not returned.
+func (s *serviceManager) unassignResources(
+	resourceIDs []string,
+	assignmentName string,
+) {
+	for _, resourceID := range resourceIDs {
+		if err := s.maintenanceManager.UnassignConfiguration(
+			resourceID,
+			assignmentName,
+		); err != nil {
+			log.WithFields(log.Fields{
+				"resourceID":     resourceID,
+				"assignmentName": assignmentName,
+				"error":          err,
+			}).Error(
+				"error removing maintenance configuration assignment of a binding " +
+					"that failed",
+			)
+		}
+	}
+}
+
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	binding service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*maintenanceInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *maintenanceInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*maintenance.ProvisioningParameters",
+		)
+	}
+	bd, ok := binding.Details.(*maintenanceBindingDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting binding.Details as *maintenanceBindingDetails",
+		)
+	}
+	resourceIDs := bd.ResourceIDs
+	if resourceIDs == nil {
+		resourceIDs = []string{}
+	}
+	return &Credentials{
+		ConfigurationID:  dt.ConfigurationID,
+		MaintenanceScope: getMaintenanceScope(pp),
+		ResourceIDs:      resourceIDs,
+	}, nil
+}
@@ -0,0 +1,65 @@
+package maintenance
+
+import (
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateBindingParameters(t *testing.T) {
+	m := &module{}
+	bp := &BindingParameters{}
+	err := m.serviceManager.ValidateBindingParameters(bp)
+	assert.Nil(t, err)
+	bp.ResourceIDs = []string{"vm"}
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.NotNil(t, err)
+	bp.ResourceIDs = []string{testVMID}
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.Nil(t, err)
+}
+
+func TestBindRejectsResourcesAssignedAtProvisioning(t *testing.T) {
+	m := &module{serviceManager: &serviceManager{}}
+	_, err := m.serviceManager.Bind(
+		service.Instance{
+			ProvisioningParameters: &ProvisioningParameters{
+				ResourceIDs: []string{testVMID},
+			},
+			Details: &maintenanceInstanceDetails{},
+		},
+		&BindingParameters{
+			ResourceIDs: []string{testVMID},
+		},
+	)
+	_, ok := err.(*service.ValidationError)
+	assert.True(t, ok)
+}
+
+func TestGetCredentials(t *testing.T) {
+	m := &module{}
+	credentials, err := m.serviceManager.GetCredentials(
+		service.Instance{
+			ProvisioningParameters: &ProvisioningParameters{
+				MaintenanceScope: scopeHost,
+			},
+			Details: &maintenanceInstanceDetails{
+				ConfigurationID: "configuration",
+			},
+		},
+		service.Binding{
+			Details: &maintenanceBindingDetails{},
+		},
+	)
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		&Credentials{
+			ConfigurationID:  "configuration",
+			MaintenanceScope: scopeHost,
+			ResourceIDs:      []string{},
+		},
+		credentials,
+	)
+}
@@ -0,0 +1,31 @@
+package maintenance
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:          "7c4e2a90-3b6f-4d18-a5e1-9f0b2c8d6e43",
+				Name:        "azure-maintenance-configuration",
+				Description: "Azure Maintenance Configuration (Experimental)",
+				Bindable:    true,
+				Tags: []string{
+					"Azure",
+					"Update Manager",
+					"Maintenance",
+					"Patching",
+				},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:   "e1b85f37-6a2c-4e09-8d4b-3c7f9a0e5b12",
+				Name: "maintenance-configuration",
+				Description: "Schedules maintenance of the resources assigned to " +
+					"the configuration; there is no charge for the configuration " +
+					"itself",
+				Free: true,
+			}),
+		),
+	}), nil
+}
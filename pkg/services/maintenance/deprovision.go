@@ -0,0 +1,94 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) GetDeprovisioner(
+	service.Plan,
+) (service.Deprovisioner, error) {
+	return service.NewDeprovisioner(
+		service.NewDeprovisioningStep(
+			"unassignResources",
+			s.unassignProvisionedResources,
+		),
+		service.NewDeprovisioningStep("deleteARMDeployment", s.deleteARMDeployment),
+		service.NewDeprovisioningStep(
+			"deleteConfiguration",
+			s.deleteConfiguration,
+		),
+	)
+}
+
+// unassignProvisionedResources removes the assignments made when the
+// configuration was provisioned. Bindings' assignments were already removed
+// when they were unbound.
+func (s *serviceManager) unassignProvisionedResources(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*maintenanceInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *maintenanceInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*maintenance.ProvisioningParameters",
+		)
+	}
+	for _, resourceID := range pp.ResourceIDs {
+		if err := s.maintenanceManager.UnassignConfiguration(
+			resourceID,
+			dt.ConfigurationName,
+		); err != nil {
+			return nil, err
+		}
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deleteARMDeployment(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*maintenanceInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *maintenanceInstanceDetails",
+		)
+	}
+	if err := s.armDeployer.Delete(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, fmt.Errorf("error deleting ARM deployment: %s", err)
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deleteConfiguration(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*maintenanceInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *maintenanceInstanceDetails",
+		)
+	}
+	if err := s.maintenanceManager.DeleteConfiguration(
+		dt.ConfigurationName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
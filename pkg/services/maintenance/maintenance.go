@@ -0,0 +1,43 @@
+package maintenance
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/maintenance"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer        arm.Deployer
+	maintenanceManager maintenance.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of provisioning Azure Update Manager maintenance
+// configurations
+func New(
+	armDeployer arm.Deployer,
+	maintenanceManager maintenance.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer:        armDeployer,
+			maintenanceManager: maintenanceManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "maintenance"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{"Microsoft.Maintenance"}
+}
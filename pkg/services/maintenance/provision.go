@@ -0,0 +1,673 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	scopeInGuestPatch       = "InGuestPatch"
+	scopeHost               = "Host"
+	scopeOSImage            = "OSImage"
+	scopeExtension          = "Extension"
+	scopeSQLDB              = "SQLDB"
+	scopeSQLManagedInstance = "SQLManagedInstance"
+
+	rebootIfRequired = "IfRequired"
+	rebootNever      = "Never"
+	rebootAlways     = "Always"
+
+	// dateTimeLayout is the layout of the times at which schedules start and
+	// expire
+	dateTimeLayout  = "2006-01-02 15:04"
+	defaultDuration = "02:00"
+	defaultTimeZone = "UTC"
+	// minInGuestPatchDuration and maxInGuestPatchDuration bound how long the
+	// maintenance windows of InGuestPatch configurations may stay open
+	minInGuestPatchDuration = 90 * time.Minute
+	maxInGuestPatchDuration = 235 * time.Minute
+	// minHostDuration is the shortest Azure permits the maintenance windows of
+	// Host configurations to stay open
+	minHostDuration = 2 * time.Hour
+)
+
+var scopes = []string{
+	scopeInGuestPatch,
+	scopeHost,
+	scopeOSImage,
+	scopeExtension,
+	scopeSQLDB,
+	scopeSQLManagedInstance,
+}
+
+// resourceTypesByScope lists the types of resources the configurations of
+// each scope can be assigned to
+var resourceTypesByScope = map[string][]string{
+	scopeInGuestPatch: {
+		"Microsoft.Compute/virtualMachines",
+		"Microsoft.HybridCompute/machines",
+	},
+	scopeHost: {
+		"Microsoft.Compute/virtualMachines",
+		"Microsoft.Compute/dedicatedHosts",
+	},
+	scopeOSImage: {
+		"Microsoft.Compute/virtualMachineScaleSets",
+	},
+	scopeExtension: {
+		"Microsoft.Compute/virtualMachines",
+		"Microsoft.Compute/virtualMachineScaleSets",
+	},
+	scopeSQLDB: {
+		"Microsoft.Sql/servers/databases",
+	},
+	scopeSQLManagedInstance: {
+		"Microsoft.Sql/managedInstances",
+	},
+}
+
+var rebootSettings = []string{rebootIfRequired, rebootNever, rebootAlways}
+
+var windowsClassifications = []string{
+	"Critical",
+	"Security",
+	"UpdateRollup",
+	"FeaturePack",
+	"ServicePack",
+	"Definition",
+	"Tools",
+	"Updates",
+}
+
+var linuxClassifications = []string{"Critical", "Security", "Other"}
+
+var defaultClassifications = []string{"Critical", "Security"}
+
+var weekdays = []string{
+	"Monday",
+	"Tuesday",
+	"Wednesday",
+	"Thursday",
+	"Friday",
+	"Saturday",
+	"Sunday",
+}
+
+var resourceIDRegex = regexp.MustCompile(
+	`(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+/providers/([^/]+)` +
+		`((?:/[^/]+/[^/]+)+)$`,
+)
+
+var durationRegex = regexp.MustCompile(`^([0-9]{2}):([0-5][0-9])$`)
+
+// recurrenceRegex matches the recurrences Azure accepts, e.g. Day, 2Weeks
+// Saturday,Sunday or Month Second Tuesday, capturing their interval, unit and
+// whatever further narrows the days on which maintenance windows open
+var recurrenceRegex = regexp.MustCompile(
+	`^([0-9]*)(Day|Week|Month)s?(?: (.+))?$`,
+)
+
+var monthDayRegex = regexp.MustCompile(`^day(-1|[1-9]|[12][0-9]|3[01])$`)
+
+var monthWeekdayRegex = regexp.MustCompile(
+	`^(First|Second|Third|Fourth|Last) ([A-Za-z]+)(?: Offset(-?[0-6]))?$`,
+)
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*maintenance.ProvisioningParameters",
+		)
+	}
+	scope := getMaintenanceScope(pp)
+	if !contains(scopes, scope) {
+		return service.NewValidationError(
+			"maintenanceScope",
+			fmt.Sprintf(
+				`invalid maintenanceScope: "%s"; allowed values are: %s`,
+				scope,
+				strings.Join(scopes, ", "),
+			),
+		)
+	}
+	if err := validateSchedule(scope, pp.Schedule); err != nil {
+		return err
+	}
+	if err := validateResourceIDs(
+		"resourceIds",
+		scope,
+		pp.ResourceIDs,
+	); err != nil {
+		return err
+	}
+	return validatePatches(scope, pp.Patches)
+}
+
+func validateSchedule(scope string, schedule *Schedule) error {
+	if schedule == nil {
+		return service.NewValidationError(
+			"schedule",
+			"a schedule must be specified",
+		)
+	}
+	start, err := time.Parse(dateTimeLayout, schedule.StartDateTime)
+	if err != nil {
+		return service.NewValidationError(
+			"schedule.startDateTime",
+			fmt.Sprintf(
+				`invalid startDateTime: "%s"; it must be formatted as `+
+					`yyyy-MM-dd hh:mm`,
+				schedule.StartDateTime,
+			),
+		)
+	}
+	if schedule.ExpirationDateTime != "" {
+		var expiration time.Time
+		expiration, err = time.Parse(dateTimeLayout, schedule.ExpirationDateTime)
+		if err != nil {
+			return service.NewValidationError(
+				"schedule.expirationDateTime",
+				fmt.Sprintf(
+					`invalid expirationDateTime: "%s"; it must be formatted as `+
+						`yyyy-MM-dd hh:mm`,
+					schedule.ExpirationDateTime,
+				),
+			)
+		}
+		if !expiration.After(start) {
+			return service.NewValidationError(
+				"schedule.expirationDateTime",
+				"the schedule must expire after it starts",
+			)
+		}
+	}
+	if schedule.Duration != "" {
+		if err = validateDuration(scope, schedule.Duration); err != nil {
+			return service.NewValidationError("schedule.duration", err.Error())
+		}
+	}
+	if err = validateRecurrence(schedule.RecurEvery); err != nil {
+		return service.NewValidationError("schedule.recurEvery", err.Error())
+	}
+	return nil
+}
+
+// validateDuration validates how long the maintenance windows of a
+// configuration of the given scope stay open, which Azure constrains more
+// tightly for some scopes than for others
+func validateDuration(scope string, durationStr string) error {
+	duration, err := parseDuration(durationStr)
+	if err != nil {
+		return err
+	}
+	switch scope {
+	case scopeInGuestPatch:
+		if duration < minInGuestPatchDuration ||
+			duration > maxInGuestPatchDuration {
+			return fmt.Errorf(
+				`invalid duration: "%s"; maintenance windows of %s `+
+					`configurations must stay open for between 01:30 and 03:55`,
+				durationStr,
+				scope,
+			)
+		}
+	case scopeHost:
+		if duration < minHostDuration {
+			return fmt.Errorf(
+				`invalid duration: "%s"; maintenance windows of %s `+
+					`configurations must stay open for at least 02:00`,
+				durationStr,
+				scope,
+			)
+		}
+	default:
+		if duration <= 0 {
+			return fmt.Errorf(
+				`invalid duration: "%s"; it must be greater than zero`,
+				durationStr,
+			)
+		}
+	}
+	return nil
+}
+
+// parseDuration parses a duration formatted as hh:mm
+func parseDuration(durationStr string) (time.Duration, error) {
+	matches := durationRegex.FindStringSubmatch(durationStr)
+	if matches == nil {
+		return 0, fmt.Errorf(
+			`invalid duration: "%s"; it must be formatted as hh:mm`,
+			durationStr,
+		)
+	}
+	hours, _ := strconv.Atoi(matches[1])
+	minutes, _ := strconv.Atoi(matches[2])
+	return time.Duration(hours)*time.Hour +
+		time.Duration(minutes)*time.Minute, nil
+}
+
+// validateRecurrence validates how often maintenance windows recur. Windows
+// may recur every so many days; every so many weeks, optionally on given
+// days of the week; or every so many months, either on given days of the
+// month or on a given weekday of the month, optionally offset by some days.
+func validateRecurrence(recurEvery string) error {
+	matches := recurrenceRegex.FindStringSubmatch(recurEvery)
+	if matches == nil {
+		return fmt.Errorf(
+			`invalid recurEvery: "%s"; it must be a recurrence such as Day, `+
+				`2Weeks Saturday,Sunday, Month Second Tuesday or Month day1,day15`,
+			recurEvery,
+		)
+	}
+	if matches[1] != "" {
+		if interval, _ := strconv.Atoi(matches[1]); interval < 1 {
+			return fmt.Errorf(
+				`invalid recurEvery: "%s"; the interval must be at least 1`,
+				recurEvery,
+			)
+		}
+	}
+	unit, days := matches[2], matches[3]
+	switch unit {
+	case "Day":
+		if days != "" {
+			return fmt.Errorf(
+				`invalid recurEvery: "%s"; daily recurrences can't be narrowed `+
+					`to particular days`,
+				recurEvery,
+			)
+		}
+	case "Week":
+		if days == "" {
+			return nil
+		}
+		if err := validateDays(days, func(day string) bool {
+			return contains(weekdays, day)
+		}); err != nil {
+			return fmt.Errorf(`invalid recurEvery: "%s"; %s`, recurEvery, err)
+		}
+	case "Month":
+		if days == "" {
+			return fmt.Errorf(
+				`invalid recurEvery: "%s"; monthly recurrences must name either `+
+					`days of the month, e.g. day1,day15, or a weekday of the `+
+					`month, e.g. Second Tuesday`,
+				recurEvery,
+			)
+		}
+		if weekdayMatches := monthWeekdayRegex.FindStringSubmatch(
+			days,
+		); weekdayMatches != nil {
+			if !contains(weekdays, weekdayMatches[2]) {
+				return fmt.Errorf(
+					`invalid recurEvery: "%s"; invalid day "%s"`,
+					recurEvery,
+					weekdayMatches[2],
+				)
+			}
+			return nil
+		}
+		if err := validateDays(days, monthDayRegex.MatchString); err != nil {
+			return fmt.Errorf(`invalid recurEvery: "%s"; %s`, recurEvery, err)
+		}
+	}
+	return nil
+}
+
+// validateDays validates a comma-delimited list of days, each of which must
+// satisfy the given function and none of which may be repeated
+func validateDays(days string, isValid func(string) bool) error {
+	seen := map[string]bool{}
+	for _, day := range strings.Split(days, ",") {
+		if !isValid(day) {
+			return fmt.Errorf(`invalid day "%s"`, day)
+		}
+		if seen[day] {
+			return fmt.Errorf(`day "%s" is specified more than once`, day)
+		}
+		seen[day] = true
+	}
+	return nil
+}
+
+// validateResourceIDs validates the IDs of resources that a configuration of
+// the given scope is to be assigned to. Each must be of a type that the
+// configuration can be assigned to, and none may be repeated. If no scope is
+// given, resources of any type are permitted.
+func validateResourceIDs(
+	field string,
+	scope string,
+	resourceIDs []string,
+) error {
+	seen := map[string]bool{}
+	for _, id := range resourceIDs {
+		resourceType, ok := getResourceType(id)
+		if !ok {
+			return service.NewValidationError(
+				field,
+				fmt.Sprintf(`invalid resource id: "%s"`, id),
+			)
+		}
+		if scope != "" &&
+			!containsFold(resourceTypesByScope[scope], resourceType) {
+			return service.NewValidationError(
+				field,
+				fmt.Sprintf(
+					`resource "%s" is of type %s; %s configurations can only be `+
+						`assigned to resources of types: %s`,
+					id,
+					resourceType,
+					scope,
+					strings.Join(resourceTypesByScope[scope], ", "),
+				),
+			)
+		}
+		if seen[strings.ToLower(id)] {
+			return service.NewValidationError(
+				field,
+				fmt.Sprintf(`duplicate resource id: "%s"`, id),
+			)
+		}
+		seen[strings.ToLower(id)] = true
+	}
+	return nil
+}
+
+func validatePatches(scope string, patches *Patches) error {
+	if patches == nil {
+		return nil
+	}
+	if scope != scopeInGuestPatch {
+		return service.NewValidationError(
+			"patches",
+			fmt.Sprintf(
+				"patches can only be specified for %s configurations",
+				scopeInGuestPatch,
+			),
+		)
+	}
+	if patches.RebootSetting != "" &&
+		!contains(rebootSettings, patches.RebootSetting) {
+		return service.NewValidationError(
+			"patches.rebootSetting",
+			fmt.Sprintf(
+				`invalid rebootSetting: "%s"; allowed values are: %s`,
+				patches.RebootSetting,
+				strings.Join(rebootSettings, ", "),
+			),
+		)
+	}
+	if err := validateClassifications(
+		"patches.windowsClassifications",
+		patches.WindowsClassifications,
+		windowsClassifications,
+	); err != nil {
+		return err
+	}
+	return validateClassifications(
+		"patches.linuxClassifications",
+		patches.LinuxClassifications,
+		linuxClassifications,
+	)
+}
+
+func validateClassifications(
+	field string,
+	classifications []string,
+	allowed []string,
+) error {
+	seen := map[string]bool{}
+	for _, classification := range classifications {
+		if !contains(allowed, classification) {
+			return service.NewValidationError(
+				field,
+				fmt.Sprintf(
+					`invalid classification: "%s"; allowed values are: %s`,
+					classification,
+					strings.Join(allowed, ", "),
+				),
+			)
+		}
+		if seen[classification] {
+			return service.NewValidationError(
+				field,
+				fmt.Sprintf(
+					`classification "%s" is specified more than once`,
+					classification,
+				),
+			)
+		}
+		seen[classification] = true
+	}
+	return nil
+}
+
+func (s *serviceManager) ApplyProvisioningParametersDefaults(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*maintenance.ProvisioningParameters",
+		)
+	}
+	pp.MaintenanceScope = getMaintenanceScope(pp)
+	pp.Schedule.Duration = getDuration(pp.Schedule)
+	pp.Schedule.TimeZone = getTimeZone(pp.Schedule)
+	if pp.MaintenanceScope == scopeInGuestPatch {
+		pp.Patches = getPatches(pp)
+	}
+	return nil
+}
+
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep("deployARMTemplate", s.deployARMTemplate),
+		service.NewProvisioningStep("assignResources", s.assignResources),
+	)
+}
+
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*maintenanceInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *maintenanceInstanceDetails",
+		)
+	}
+	dt.ARMDeploymentName = uuid.NewV4().String()
+	dt.ConfigurationName = "mc-" + uuid.NewV4().String()
+	return dt, nil
+}
+
+func (s *serviceManager) deployARMTemplate(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*maintenanceInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *maintenanceInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*maintenance.ProvisioningParameters",
+		)
+	}
+	scope := getMaintenanceScope(pp)
+	outputs, err := s.armDeployer.Deploy(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateBytes,
+		map[string]interface{}{ // Go template params
+			"inGuestPatch": scope == scopeInGuestPatch,
+		},
+		buildARMTemplateParameters(pp, dt),
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+	configurationID, ok := outputs["configurationId"].(string)
+	if !ok {
+		return nil, errors.New(
+			"error retrieving maintenance configuration id from deployment",
+		)
+	}
+	dt.ConfigurationID = configurationID
+	return dt, nil
+}
+
+// assignResources applies the configuration to each of the resources named
+// at provisioning. Each assignment is named after the configuration, so that
+// assigning a resource again, should this step be retried, changes nothing.
+func (s *serviceManager) assignResources(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*maintenanceInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *maintenanceInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*maintenance.ProvisioningParameters",
+		)
+	}
+	for _, resourceID := range pp.ResourceIDs {
+		if err := s.maintenanceManager.AssignConfiguration(
+			dt.ConfigurationID,
+			resourceID,
+			instance.Location,
+			dt.ConfigurationName,
+		); err != nil {
+			return nil, err
+		}
+	}
+	return dt, nil
+}
+
+func buildARMTemplateParameters(
+	pp *ProvisioningParameters,
+	dt *maintenanceInstanceDetails,
+) map[string]interface{} {
+	scope := getMaintenanceScope(pp)
+	params := map[string]interface{}{
+		"configurationName":  dt.ConfigurationName,
+		"maintenanceScope":   scope,
+		"startDateTime":      pp.Schedule.StartDateTime,
+		"expirationDateTime": pp.Schedule.ExpirationDateTime,
+		"duration":           getDuration(pp.Schedule),
+		"timeZone":           getTimeZone(pp.Schedule),
+		"recurEvery":         pp.Schedule.RecurEvery,
+	}
+	if scope == scopeInGuestPatch {
+		patches := getPatches(pp)
+		params["rebootSetting"] = patches.RebootSetting
+		params["windowsClassifications"] = patches.WindowsClassifications
+		params["linuxClassifications"] = patches.LinuxClassifications
+	}
+	return params
+}
+
+func getMaintenanceScope(pp *ProvisioningParameters) string {
+	if pp.MaintenanceScope == "" {
+		return scopeInGuestPatch
+	}
+	return pp.MaintenanceScope
+}
+
+func getDuration(schedule *Schedule) string {
+	if schedule.Duration == "" {
+		return defaultDuration
+	}
+	return schedule.Duration
+}
+
+func getTimeZone(schedule *Schedule) string {
+	if schedule.TimeZone == "" {
+		return defaultTimeZone
+	}
+	return schedule.TimeZone
+}
+
+// getPatches returns the patches that an InGuestPatch configuration installs,
+// with defaults in place of any settings that weren't specified. By default,
+// only critical and security updates are installed, and machines are only
+// rebooted if an update requires it.
+func getPatches(pp *ProvisioningParameters) *Patches {
+	patches := Patches{}
+	if pp.Patches != nil {
+		patches = *pp.Patches
+	}
+	if patches.RebootSetting == "" {
+		patches.RebootSetting = rebootIfRequired
+	}
+	if len(patches.WindowsClassifications) == 0 {
+		patches.WindowsClassifications = defaultClassifications
+	}
+	if len(patches.LinuxClassifications) == 0 {
+		patches.LinuxClassifications = defaultClassifications
+	}
+	return &patches
+}
+
+// getResourceType returns the type, e.g. Microsoft.Compute/virtualMachines,
+// of the identified resource. A bool indicating whether the ID was valid is
+// also returned.
+func getResourceType(resourceID string) (string, bool) {
+	matches := resourceIDRegex.FindStringSubmatch(resourceID)
+	if matches == nil {
+		return "", false
+	}
+	segments := strings.Split(strings.TrimPrefix(matches[2], "/"), "/")
+	resourceType := matches[1]
+	for i := 0; i < len(segments); i += 2 {
+		resourceType += "/" + segments[i]
+	}
+	return resourceType, true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package maintenance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testVMID = "/subscriptions/00000000-0000-0000-0000-000000000000/" +
+	"resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/vm"
+
+func TestValidateProvisioningParameters(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.Schedule = &Schedule{
+		StartDateTime: "2026-11-01 02:00",
+		RecurEvery:    "Month Second Tuesday",
+	}
+	pp.ResourceIDs = []string{testVMID}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+	pp.MaintenanceScope = "Guest"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.MaintenanceScope = scopeSQLDB
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.MaintenanceScope = scopeHost
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+	pp.Patches = &Patches{}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.MaintenanceScope = scopeInGuestPatch
+	pp.Patches.LinuxClassifications = []string{"Critical", "Critical"}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.Patches.LinuxClassifications = []string{"Critical", "Other"}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+	pp.ResourceIDs = append(pp.ResourceIDs, testVMID)
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+}
+
+func TestValidateSchedule(t *testing.T) {
+	schedule := &Schedule{
+		StartDateTime:      "2026-11-01 02:00",
+		ExpirationDateTime: "2026-10-01 02:00",
+		RecurEvery:         "Day",
+	}
+	err := validateSchedule(scopeInGuestPatch, schedule)
+	assert.NotNil(t, err)
+	schedule.ExpirationDateTime = "2027-11-01 02:00"
+	err = validateSchedule(scopeInGuestPatch, schedule)
+	assert.Nil(t, err)
+	schedule.StartDateTime = "2026-11-01T02:00:00Z"
+	err = validateSchedule(scopeInGuestPatch, schedule)
+	assert.NotNil(t, err)
+	schedule.StartDateTime = "2026-11-01 02:00"
+	schedule.Duration = "01:00"
+	err = validateSchedule(scopeInGuestPatch, schedule)
+	assert.NotNil(t, err)
+	err = validateSchedule(scopeExtension, schedule)
+	assert.Nil(t, err)
+	err = validateSchedule(scopeHost, schedule)
+	assert.NotNil(t, err)
+	schedule.Duration = "3:00"
+	err = validateSchedule(scopeHost, schedule)
+	assert.NotNil(t, err)
+}
+
+func TestValidateRecurrence(t *testing.T) {
+	for _, recurEvery := range []string{
+		"Day",
+		"3Days",
+		"Week",
+		"2Weeks Saturday,Sunday",
+		"Month day1,day15,day-1",
+		"Month Second Tuesday",
+		"Month Last Sunday Offset-3",
+	} {
+		assert.Nil(t, validateRecurrence(recurEvery), recurEvery)
+	}
+	for _, recurEvery := range []string{
+		"",
+		"Fortnight",
+		"0Days",
+		"Day Monday",
+		"Week Funday",
+		"Week Monday,Monday",
+		"Month",
+		"Month day32",
+		"Month Fifth Monday",
+		"Month Last Funday",
+		"Month Last Sunday Offset7",
+	} {
+		assert.NotNil(t, validateRecurrence(recurEvery), recurEvery)
+	}
+}
+
+func TestApplyProvisioningParametersDefaults(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		Schedule: &Schedule{
+			StartDateTime: "2026-11-01 02:00",
+			RecurEvery:    "Day",
+		},
+		Patches: &Patches{
+			RebootSetting: rebootNever,
+		},
+	}
+	err := m.serviceManager.ApplyProvisioningParametersDefaults(pp)
+	assert.Nil(t, err)
+	assert.Equal(t, scopeInGuestPatch, pp.MaintenanceScope)
+	assert.Equal(t, defaultDuration, pp.Schedule.Duration)
+	assert.Equal(t, defaultTimeZone, pp.Schedule.TimeZone)
+	assert.Equal(t, rebootNever, pp.Patches.RebootSetting)
+	assert.Equal(t, defaultClassifications, pp.Patches.WindowsClassifications)
+	assert.Equal(t, defaultClassifications, pp.Patches.LinuxClassifications)
+}
+
+func TestGetResourceType(t *testing.T) {
+	resourceType, ok := getResourceType(testVMID)
+	assert.True(t, ok)
+	assert.Equal(t, "Microsoft.Compute/virtualMachines", resourceType)
+	resourceType, ok = getResourceType(
+		"/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/" +
+			"providers/Microsoft.Sql/servers/server/databases/db",
+	)
+	assert.True(t, ok)
+	assert.Equal(t, "Microsoft.Sql/servers/databases", resourceType)
+	_, ok = getResourceType("vm")
+	assert.False(t, ok)
+}
@@ -0,0 +1,108 @@
+package maintenance
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// ProvisioningParameters encapsulates Azure Update Manager-specific
+// provisioning options
+type ProvisioningParameters struct {
+	// MaintenanceScope is the kind of maintenance the configuration schedules,
+	// e.g. InGuestPatch, for patching the operating systems of machines
+	MaintenanceScope string    `json:"maintenanceScope"`
+	Schedule         *Schedule `json:"schedule"`
+	// ResourceIDs are the resources the configuration is applied to when it's
+	// provisioned. Bindings may apply it to further resources.
+	ResourceIDs []string `json:"resourceIds"`
+	// Patches, which only apply to the InGuestPatch scope, select the updates
+	// that are installed during each maintenance window
+	Patches *Patches `json:"patches"`
+}
+
+// Schedule encapsulates when maintenance windows open and how long each one
+// stays open
+type Schedule struct {
+	// StartDateTime, formatted as yyyy-MM-dd hh:mm in the schedule's time zone,
+	// is when the first maintenance window opens
+	StartDateTime string `json:"startDateTime"`
+	// ExpirationDateTime, formatted as StartDateTime is, is when the schedule
+	// ends. Schedules without one never end.
+	ExpirationDateTime string `json:"expirationDateTime"`
+	// Duration, formatted as hh:mm, is how long each maintenance window stays
+	// open
+	Duration string `json:"duration"`
+	// TimeZone is the Windows name of the time zone, e.g. Pacific Standard
+	// Time, or UTC
+	TimeZone string `json:"timeZone"`
+	// RecurEvery is how often maintenance windows recur, e.g. Day,
+	// 2Weeks Saturday,Sunday, Month Second Tuesday or Month day1,day15
+	RecurEvery string `json:"recurEvery"`
+}
+
+// Patches encapsulates the updates that are installed on machines during
+// each maintenance window, and whether they're rebooted afterward
+type Patches struct {
+	RebootSetting          string   `json:"rebootSetting"`
+	WindowsClassifications []string `json:"windowsClassifications"`
+	LinuxClassifications   []string `json:"linuxClassifications"`
+}
+
+type maintenanceInstanceDetails struct {
+	ARMDeploymentName string `json:"armDeployment"`
+	ConfigurationName string `json:"configurationName"`
+	ConfigurationID   string `json:"configurationId"`
+}
+
+// UpdatingParameters encapsulates Azure Update Manager-specific updating
+// options
+type UpdatingParameters struct {
+}
+
+// BindingParameters encapsulates Azure Update Manager-specific binding
+// options
+type BindingParameters struct {
+	// ResourceIDs are further resources the configuration is applied to for as
+	// long as the binding exists
+	ResourceIDs []string `json:"resourceIds"`
+}
+
+type maintenanceBindingDetails struct {
+	// AssignmentName names the configuration assignments through which the
+	// configuration is applied to the binding's resources, so that unbinding
+	// removes those assignments and no others
+	AssignmentName string   `json:"assignmentName"`
+	ResourceIDs    []string `json:"resourceIds"`
+}
+
+// Credentials encapsulates the maintenance configuration that a binding's
+// resources were assigned to. Operators can use the configuration ID to
+// assign still more resources, e.g. with the az maintenance CLI.
+type Credentials struct {
+	ConfigurationID  string   `json:"configurationId"`
+	MaintenanceScope string   `json:"maintenanceScope"`
+	ResourceIDs      []string `json:"resourceIds"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &maintenanceInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &maintenanceBindingDetails{}
+}
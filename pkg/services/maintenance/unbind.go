@@ -0,0 +1,31 @@
+package maintenance
+
+import (
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// Unbind removes the binding's assignments, which stops the configuration
+// from applying to the binding's resources. Assignments of the same resources
+// made by other bindings, or when the configuration was provisioned, remain.
+func (s *serviceManager) Unbind(
+	_ service.Instance,
+	bindingDetails service.BindingDetails,
+) error {
+	bd, ok := bindingDetails.(*maintenanceBindingDetails)
+	if !ok {
+		return errors.New(
+			"error casting bindingDetails as *maintenanceBindingDetails",
+		)
+	}
+	for _, resourceID := range bd.ResourceIDs {
+		if err := s.maintenanceManager.UnassignConfiguration(
+			resourceID,
+			bd.AssignmentName,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package managedhsm
+
+// nolint: lll
+var armTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "hsmName": {
+      "type": "string"
+    },
+    "skuName": {
+      "type": "string",
+      "allowedValues": [
+        "Standard_B1",
+        "Custom_B32"
+      ]
+    },
+    "tenantId": {
+      "type": "string"
+    },
+    "initialAdminObjectIds": {
+      "type": "array",
+      "metadata": {
+        "description": "Object IDs of the principals that administer the HSM's security domain and role assignments"
+      }
+    },
+    "softDeleteRetentionInDays": {
+      "type": "int"
+    },
+    "enablePurgeProtection": {
+      "type": "bool"
+    },
+    "tags": {
+      "type": "object"
+    }
+  },
+  "resources": [
+    {
+      "apiVersion": "2021-10-01",
+      "name": "[parameters('hsmName')]",
+      "type": "Microsoft.KeyVault/managedHSMs",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "sku": {
+        "family": "B",
+        "name": "[parameters('skuName')]"
+      },
+      "properties": {
+        "tenantId": "[parameters('tenantId')]",
+        "initialAdminObjectIds": "[parameters('initialAdminObjectIds')]",
+        "enableSoftDelete": true,
+        "softDeleteRetentionInDays": "[parameters('softDeleteRetentionInDays')]",
+        "enablePurgeProtection": "[parameters('enablePurgeProtection')]"
+      }
+    }
+  ],
+  "outputs": {
+    "hsmUri": {
+      "type": "string",
+      "value": "[reference(parameters('hsmName')).hsmUri]"
+    }
+  }
+}
+`)
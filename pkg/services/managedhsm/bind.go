@@ -0,0 +1,170 @@
+package managedhsm
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	defaultRole  = "Managed HSM Crypto User"
+	defaultScope = "/keys"
+)
+
+// roleDefinitionIDs maps the names of the built-in Managed HSM local RBAC roles
+// that a binding may assign to their role definition IDs
+var roleDefinitionIDs = map[string]string{
+	"Managed HSM Administrator":                  "a290e904-7015-4bba-90c8-60543313cdb4", // nolint: lll
+	"Managed HSM Crypto Officer":                 "515eb02d-2335-4d2d-92f2-b1cbdf9c3778", // nolint: lll
+	"Managed HSM Crypto User":                    "21dbd100-6940-42c2-9190-5d6cb909625b", // nolint: lll
+	"Managed HSM Policy Administrator":           "4bd23610-cdcf-4971-bdee-bdc562cc28e4", // nolint: lll
+	"Managed HSM Crypto Service Encryption User": "33413926-3206-4cdd-b39a-83574fe37a17", // nolint: lll
+	"Managed HSM Backup User":                    "7b127d3c-77bd-4e3e-bbe0-dbb8971fa7f8", // nolint: lll
+	"Managed HSM Crypto Auditor":                 "2c18b078-7c48-4d3a-af88-5a3a1b3f82b3", // nolint: lll
+}
+
+// scopeRegex matches the scopes at which roles may be assigned-- the whole
+// HSM, all of its keys, or a single key
+var scopeRegex = regexp.MustCompile(`^/(keys(/[0-9a-zA-Z-]{1,127})?)?$`)
+
+func (s *serviceManager) ValidateBindingParameters(
+	bindingParameters service.BindingParameters,
+) error {
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return errors.New(
+			"error casting bindingParameters as *managedhsm.BindingParameters",
+		)
+	}
+	if !isValidObjectID(bp.PrincipalID) {
+		return service.NewValidationError(
+			"principalId",
+			fmt.Sprintf(`invalid principalId: "%s"`, bp.PrincipalID),
+		)
+	}
+	for _, role := range bp.Roles {
+		if _, ok := roleDefinitionIDs[role]; !ok {
+			return service.NewValidationError(
+				"roles",
+				fmt.Sprintf(
+					`invalid role: "%s"; allowed values are: %s`,
+					role,
+					strings.Join(getRoleNames(), ", "),
+				),
+			)
+		}
+	}
+	if bp.Scope != "" && !scopeRegex.MatchString(bp.Scope) {
+		return service.NewValidationError(
+			"scope",
+			fmt.Sprintf(
+				`invalid scope: "%s"; scope must be "/", "/keys", or `+
+					`"/keys/<key name>"`,
+				bp.Scope,
+			),
+		)
+	}
+	return nil
+}
+
+// Bind assigns each requested role, at the requested scope, to the principal
+// named in the binding parameters
+func (s *serviceManager) Bind(
+	instance service.Instance,
+	bindingParameters service.BindingParameters,
+) (service.BindingDetails, error) {
+	dt, ok := instance.Details.(*managedHSMInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *managedHSMInstanceDetails",
+		)
+	}
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting bindingParameters as *managedhsm.BindingParameters",
+		)
+	}
+	bd := &managedHSMBindingDetails{
+		PrincipalID: bp.PrincipalID,
+		Roles:       bp.Roles,
+		Scope:       bp.Scope,
+	}
+	if len(bd.Roles) == 0 {
+		bd.Roles = []string{defaultRole}
+	}
+	if bd.Scope == "" {
+		bd.Scope = defaultScope
+	}
+	for _, role := range bd.Roles {
+		roleAssignmentName := uuid.NewV4().String()
+		if err := s.managedHSMManager.CreateRoleAssignment(
+			dt.HSMURI,
+			bd.Scope,
+			roleAssignmentName,
+			getRoleDefinitionID(role),
+			bd.PrincipalID,
+		); err != nil {
+			// Don't leave behind the assignments that were already made
+			for _, name := range bd.RoleAssignmentNames {
+				s.managedHSMManager.DeleteRoleAssignment( // nolint: errcheck
+					dt.HSMURI,
+					bd.Scope,
+					name,
+				)
+			}
+			return nil, err
+		}
+		bd.RoleAssignmentNames = append(bd.RoleAssignmentNames, roleAssignmentName)
+	}
+	return bd, nil
+}
+
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	binding service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*managedHSMInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *managedHSMInstanceDetails",
+		)
+	}
+	bd, ok := binding.Details.(*managedHSMBindingDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting binding.Details as *managedHSMBindingDetails",
+		)
+	}
+	return &Credentials{
+		HSMName:        dt.HSMName,
+		HSMURI:         dt.HSMURI,
+		PrincipalID:    bd.PrincipalID,
+		Roles:          bd.Roles,
+		Scope:          bd.Scope,
+		SecurityDomain: dt.SecurityDomain,
+	}, nil
+}
+
+// getRoleDefinitionID returns the fully qualified ID of the named built-in
+// role's definition
+func getRoleDefinitionID(role string) string {
+	return fmt.Sprintf(
+		"Microsoft.KeyVault/providers/Microsoft.Authorization/roleDefinitions/%s",
+		roleDefinitionIDs[role],
+	)
+}
+
+func getRoleNames() []string {
+	roles := make([]string, 0, len(roleDefinitionIDs))
+	for role := range roleDefinitionIDs {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles
+}
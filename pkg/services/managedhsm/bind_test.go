@@ -0,0 +1,40 @@
+package managedhsm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateBindingParameters(t *testing.T) {
+	m := &module{}
+	bp := &BindingParameters{}
+	err := m.serviceManager.ValidateBindingParameters(bp)
+	assert.NotNil(t, err)
+	bp.PrincipalID = "7c1e5a3b-9d2f-4b80-a6e4-3f5d8c2b1a09"
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.Nil(t, err)
+	bp.Roles = []string{"Managed HSM Crypto User", "Key Vault Reader"}
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.NotNil(t, err)
+	bp.Roles = []string{"Managed HSM Crypto User", "Managed HSM Crypto Auditor"}
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.Nil(t, err)
+}
+
+func TestValidateBindingParametersWithInvalidScope(t *testing.T) {
+	m := &module{}
+	bp := &BindingParameters{
+		PrincipalID: "7c1e5a3b-9d2f-4b80-a6e4-3f5d8c2b1a09",
+	}
+	for _, scope := range []string{"/", "/keys", "/keys/my-key"} {
+		bp.Scope = scope
+		err := m.serviceManager.ValidateBindingParameters(bp)
+		assert.Nil(t, err, scope)
+	}
+	for _, scope := range []string{"keys", "/secrets", "/keys/a/b"} {
+		bp.Scope = scope
+		err := m.serviceManager.ValidateBindingParameters(bp)
+		assert.NotNil(t, err, scope)
+	}
+}
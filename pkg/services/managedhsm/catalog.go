@@ -0,0 +1,24 @@
+package managedhsm
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:          "4d9e2b71-83c5-4a0f-9e6d-5b1c7a2f8e34",
+				Name:        "azure-managed-hsm",
+				Description: "Azure Managed HSM (Experimental)",
+				Bindable:    true,
+				Tags:        []string{"Azure", "Managed HSM", "Key Management"},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:          "a7c31f58-2e96-4b0d-8f14-6d3e9b5c0a27",
+				Name:        "standard",
+				Description: "A single-tenant, FIPS 140-2 Level 3 validated HSM pool",
+				Free:        false,
+			}),
+		),
+	}), nil
+}
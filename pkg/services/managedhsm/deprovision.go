@@ -0,0 +1,94 @@
+package managedhsm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) GetDeprovisioner(
+	service.Plan,
+) (service.Deprovisioner, error) {
+	return service.NewDeprovisioner(
+		service.NewDeprovisioningStep(
+			"deleteARMDeployment",
+			s.deleteARMDeployment,
+		),
+		service.NewDeprovisioningStep("deleteHSM", s.deleteHSM),
+		service.NewDeprovisioningStep("purgeHSM", s.purgeHSM),
+	)
+}
+
+func (s *serviceManager) deleteARMDeployment(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*managedHSMInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *managedHSMInstanceDetails",
+		)
+	}
+	if err := s.armDeployer.Delete(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, fmt.Errorf("error deleting ARM deployment: %s", err)
+	}
+	return dt, nil
+}
+
+// deleteHSM deletes the HSM. Soft-delete is always enabled for managed HSMs,
+// so the HSM is only marked as deleted and continues to exist (and be billed)
+// until it is purged or its retention period elapses.
+func (s *serviceManager) deleteHSM(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*managedHSMInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *managedHSMInstanceDetails",
+		)
+	}
+	if err := s.managedHSMManager.DeleteHSM(
+		dt.HSMName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+// purgeHSM permanently deletes the soft-deleted HSM if, and only if, purging
+// on deprovision was requested at provisioning
+func (s *serviceManager) purgeHSM(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*managedHSMInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *managedHSMInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*managedhsm.ProvisioningParameters",
+		)
+	}
+	if !pp.PurgeOnDeprovision {
+		return dt, nil
+	}
+	if err := s.managedHSMManager.PurgeDeletedHSM(
+		dt.HSMName,
+		instance.Location,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
@@ -0,0 +1,45 @@
+package managedhsm
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/managedhsm"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer       arm.Deployer
+	managedHSMManager managedhsm.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of provisioning Azure Managed HSM pools
+func New(
+	armDeployer arm.Deployer,
+	managedHSMManager managedhsm.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer:       armDeployer,
+			managedHSMManager: managedHSMManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "managedhsm"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{
+		"Microsoft.KeyVault",
+		"Microsoft.Authorization",
+	}
+}
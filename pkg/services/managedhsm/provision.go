@@ -0,0 +1,329 @@
+package managedhsm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/managedhsm"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	defaultSKU                     = "Standard_B1"
+	defaultSoftDeleteRetentionDays = 90
+	minSoftDeleteRetentionDays     = 7
+	maxSoftDeleteRetentionDays     = 90
+	// Activating an HSM requires that its security domain be encrypted to at
+	// least three, and at most ten, certificates
+	minSecurityDomainCertificates = 3
+	maxSecurityDomainCertificates = 10
+	defaultSecurityDomainQuorum   = 2
+)
+
+var skus = []string{"Standard_B1", "Custom_B32"}
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*managedhsm.ProvisioningParameters",
+		)
+	}
+	if pp.SKU != "" && !isValidSKU(pp.SKU) {
+		return service.NewValidationError(
+			"sku",
+			fmt.Sprintf(
+				`invalid sku: "%s"; allowed values are: %s`,
+				pp.SKU,
+				strings.Join(skus, ", "),
+			),
+		)
+	}
+	if len(pp.Administrators) == 0 {
+		return service.NewValidationError(
+			"administrators",
+			"at least one administrator object id must be specified",
+		)
+	}
+	for _, objectID := range pp.Administrators {
+		if !isValidObjectID(objectID) {
+			return service.NewValidationError(
+				"administrators",
+				fmt.Sprintf(`invalid administrator object id: "%s"`, objectID),
+			)
+		}
+	}
+	if pp.SoftDeleteRetentionDays != 0 &&
+		(pp.SoftDeleteRetentionDays < minSoftDeleteRetentionDays ||
+			pp.SoftDeleteRetentionDays > maxSoftDeleteRetentionDays) {
+		return service.NewValidationError(
+			"softDeleteRetentionDays",
+			fmt.Sprintf(
+				"invalid softDeleteRetentionDays: %d; must be between %d and %d",
+				pp.SoftDeleteRetentionDays,
+				minSoftDeleteRetentionDays,
+				maxSoftDeleteRetentionDays,
+			),
+		)
+	}
+	// Azure refuses to purge an HSM that has purge protection enabled
+	if pp.EnablePurgeProtection && pp.PurgeOnDeprovision {
+		return service.NewValidationError(
+			"purgeOnDeprovision",
+			"an HSM with purge protection enabled cannot be purged",
+		)
+	}
+	certCount := len(pp.SecurityDomainCertificates)
+	if certCount < minSecurityDomainCertificates ||
+		certCount > maxSecurityDomainCertificates {
+		return service.NewValidationError(
+			"securityDomainCertificates",
+			fmt.Sprintf(
+				"between %d and %d certificates must be specified",
+				minSecurityDomainCertificates,
+				maxSecurityDomainCertificates,
+			),
+		)
+	}
+	for i, cert := range pp.SecurityDomainCertificates {
+		if _, err := managedhsm.NewSecurityDomainCertificate(cert); err != nil {
+			return service.NewValidationError(
+				"securityDomainCertificates",
+				fmt.Sprintf("invalid certificate at index %d: %s", i, err),
+			)
+		}
+	}
+	if pp.SecurityDomainQuorum != 0 &&
+		(pp.SecurityDomainQuorum < 2 || pp.SecurityDomainQuorum > certCount) {
+		return service.NewValidationError(
+			"securityDomainQuorum",
+			fmt.Sprintf(
+				"invalid securityDomainQuorum: %d; must be at least 2 and no more "+
+					"than the number of certificates specified",
+				pp.SecurityDomainQuorum,
+			),
+		)
+	}
+	return nil
+}
+
+func (s *serviceManager) ApplyProvisioningParametersDefaults(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*managedhsm.ProvisioningParameters",
+		)
+	}
+	if pp.SKU == "" {
+		pp.SKU = defaultSKU
+	}
+	if pp.SoftDeleteRetentionDays == 0 {
+		pp.SoftDeleteRetentionDays = defaultSoftDeleteRetentionDays
+	}
+	if pp.SecurityDomainQuorum == 0 {
+		pp.SecurityDomainQuorum = defaultSecurityDomainQuorum
+	}
+	return nil
+}
+
+func (s *serviceManager) GetSKUUsages(
+	_ service.Plan,
+	provisioningParameters service.ProvisioningParameters,
+) ([]service.SKUUsage, error) {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting provisioningParameters as " +
+				"*managedhsm.ProvisioningParameters",
+		)
+	}
+	sku := pp.SKU
+	if sku == "" {
+		sku = defaultSKU
+	}
+	return []service.SKUUsage{
+		{
+			SKU:      sku,
+			Quantity: 1,
+		},
+	}, nil
+}
+
+// GetProvisioner returns a provisioner that deploys the HSM and then
+// activates it. An HSM can't be used until it is activated, which both
+// deploying and activating it may take several minutes to accomplish. Keeping
+// each in a step of its own means that, should the broker be restarted, the
+// async engine resumes where it left off instead of starting over-- in
+// particular, it never requests the security domain a second time.
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep("deployHSM", s.deployHSM),
+		service.NewProvisioningStep(
+			"downloadSecurityDomain",
+			s.downloadSecurityDomain,
+		),
+		service.NewProvisioningStep(
+			"waitForActivation",
+			s.waitForActivation,
+		),
+	)
+}
+
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*managedHSMInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *managedHSMInstanceDetails",
+		)
+	}
+	dt.ARMDeploymentName = uuid.NewV4().String()
+	// Managed HSM names must begin with a letter and be no more than 24
+	// characters long
+	dt.HSMName = "hsm" + strings.Replace(uuid.NewV4().String(), "-", "", -1)[:21]
+	return dt, nil
+}
+
+func (s *serviceManager) deployHSM(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*managedHSMInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *managedHSMInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*managedhsm.ProvisioningParameters",
+		)
+	}
+	sku := pp.SKU
+	if sku == "" {
+		sku = defaultSKU
+	}
+	softDeleteRetentionDays := pp.SoftDeleteRetentionDays
+	if softDeleteRetentionDays == 0 {
+		softDeleteRetentionDays = defaultSoftDeleteRetentionDays
+	}
+	outputs, err := s.armDeployer.Deploy(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateBytes,
+		nil, // Go template params
+		map[string]interface{}{ // ARM template params
+			"hsmName":                   dt.HSMName,
+			"skuName":                   sku,
+			"tenantId":                  s.managedHSMManager.GetTenantID(),
+			"initialAdminObjectIds":     pp.Administrators,
+			"softDeleteRetentionInDays": softDeleteRetentionDays,
+			"enablePurgeProtection":     pp.EnablePurgeProtection,
+		},
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+	hsmURI, ok := outputs["hsmUri"].(string)
+	if !ok {
+		return nil, errors.New("error retrieving HSM URI from deployment")
+	}
+	dt.HSMURI = hsmURI
+	return dt, nil
+}
+
+// downloadSecurityDomain begins activation of the HSM by requesting its
+// security domain, encrypted to the certificates supplied at provisioning
+func (s *serviceManager) downloadSecurityDomain(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*managedHSMInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *managedHSMInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*managedhsm.ProvisioningParameters",
+		)
+	}
+	certs := make(
+		[]managedhsm.SecurityDomainCertificate,
+		len(pp.SecurityDomainCertificates),
+	)
+	for i, pemCert := range pp.SecurityDomainCertificates {
+		cert, err := managedhsm.NewSecurityDomainCertificate(pemCert)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing certificate: %s", err)
+		}
+		certs[i] = cert
+	}
+	quorum := pp.SecurityDomainQuorum
+	if quorum == 0 {
+		quorum = defaultSecurityDomainQuorum
+	}
+	securityDomain, err := s.managedHSMManager.DownloadSecurityDomain(
+		dt.HSMURI,
+		certs,
+		quorum,
+	)
+	if err != nil {
+		return nil, err
+	}
+	dt.SecurityDomain = securityDomain
+	return dt, nil
+}
+
+func (s *serviceManager) waitForActivation(
+	ctx context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*managedHSMInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *managedHSMInstanceDetails",
+		)
+	}
+	if err := s.managedHSMManager.WaitForActivation(ctx, dt.HSMURI); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+func isValidSKU(sku string) bool {
+	for _, validSKU := range skus {
+		if sku == validSKU {
+			return true
+		}
+	}
+	return false
+}
+
+// isValidObjectID returns a bool indicating whether the given string is an
+// Azure Active Directory object ID-- a UUID in its canonical form
+func isValidObjectID(objectID string) bool {
+	id, err := uuid.FromString(objectID)
+	return err == nil && strings.EqualFold(id.String(), objectID)
+}
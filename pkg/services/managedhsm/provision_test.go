@@ -0,0 +1,152 @@
+package managedhsm
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateProvisioningParametersWithInvalidSKU(t *testing.T) {
+	m := &module{}
+	pp := getValidProvisioningParameters(t)
+	pp.SKU = "Premium"
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.SKU = "Custom_B32"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+	pp.SKU = ""
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidAdministrators(
+	t *testing.T,
+) {
+	m := &module{}
+	pp := getValidProvisioningParameters(t)
+	pp.Administrators = nil
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.Administrators = []string{
+		"2d3e0c4f-6b5a-4e1d-9c8b-7a6f5e4d3c2b",
+		"not-an-object-id",
+	}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	// Object IDs must be in their canonical, hyphenated form
+	pp.Administrators = []string{"2d3e0c4f6b5a4e1d9c8b7a6f5e4d3c2b"}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidSoftDeleteRetention(
+	t *testing.T,
+) {
+	m := &module{}
+	pp := getValidProvisioningParameters(t)
+	pp.SoftDeleteRetentionDays = 6
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.SoftDeleteRetentionDays = 91
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.SoftDeleteRetentionDays = 7
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithPurgeProtectionAndPurge(
+	t *testing.T,
+) {
+	m := &module{}
+	pp := getValidProvisioningParameters(t)
+	pp.EnablePurgeProtection = true
+	pp.PurgeOnDeprovision = true
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.PurgeOnDeprovision = false
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidSecurityDomain(
+	t *testing.T,
+) {
+	m := &module{}
+	pp := getValidProvisioningParameters(t)
+	certs := pp.SecurityDomainCertificates
+	pp.SecurityDomainCertificates = certs[:2]
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.SecurityDomainCertificates = append(
+		[]string{"not a certificate"},
+		certs...,
+	)
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	// Certificates must have RSA keys
+	pp.SecurityDomainCertificates = append(
+		[]string{getTestECDSACertificate(t)},
+		certs...,
+	)
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.SecurityDomainCertificates = certs
+	pp.SecurityDomainQuorum = 4
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.SecurityDomainQuorum = 3
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func getValidProvisioningParameters(t *testing.T) *ProvisioningParameters {
+	certs := make([]string, 3)
+	for i := range certs {
+		key, err := rsa.GenerateKey(rand.Reader, 1024)
+		assert.Nil(t, err)
+		certs[i] = getTestCertificate(t, &key.PublicKey, key)
+	}
+	return &ProvisioningParameters{
+		Administrators:             []string{"2d3e0c4f-6b5a-4e1d-9c8b-7a6f5e4d3c2b"},
+		SecurityDomainCertificates: certs,
+	}
+}
+
+func getTestECDSACertificate(t *testing.T) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	return getTestCertificate(t, &key.PublicKey, key)
+}
+
+func getTestCertificate(
+	t *testing.T,
+	publicKey interface{},
+	privateKey interface{},
+) string {
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "security-domain"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(
+		rand.Reader,
+		template,
+		template,
+		publicKey,
+		privateKey,
+	)
+	assert.Nil(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
@@ -0,0 +1,78 @@
+package managedhsm
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// ProvisioningParameters encapsulates Managed HSM-specific provisioning
+// options
+type ProvisioningParameters struct {
+	SKU                        string   `json:"sku"`
+	Administrators             []string `json:"administrators"`
+	SoftDeleteRetentionDays    int      `json:"softDeleteRetentionDays"`
+	EnablePurgeProtection      bool     `json:"enablePurgeProtection"`
+	PurgeOnDeprovision         bool     `json:"purgeOnDeprovision"`
+	SecurityDomainCertificates []string `json:"securityDomainCertificates"`
+	SecurityDomainQuorum       int      `json:"securityDomainQuorum"`
+}
+
+type managedHSMInstanceDetails struct {
+	ARMDeploymentName string `json:"armDeployment"`
+	HSMName           string `json:"hsmName"`
+	HSMURI            string `json:"hsmUri"`
+	// SecurityDomain is encrypted to the certificates supplied at provisioning
+	// and is needed to recover the HSM's keys
+	SecurityDomain string `json:"securityDomain" secret:"true"`
+}
+
+// UpdatingParameters encapsulates Managed HSM-specific updating options
+type UpdatingParameters struct {
+}
+
+// BindingParameters encapsulates Managed HSM-specific binding options
+type BindingParameters struct {
+	PrincipalID string   `json:"principalId"`
+	Roles       []string `json:"roles"`
+	Scope       string   `json:"scope"`
+}
+
+type managedHSMBindingDetails struct {
+	PrincipalID         string   `json:"principalId"`
+	Roles               []string `json:"roles"`
+	Scope               string   `json:"scope"`
+	RoleAssignmentNames []string `json:"roleAssignmentNames"`
+}
+
+// Credentials encapsulates Managed HSM-specific connection details
+type Credentials struct {
+	HSMName        string   `json:"hsmName"`
+	HSMURI         string   `json:"hsmUri"`
+	PrincipalID    string   `json:"principalId"`
+	Roles          []string `json:"roles"`
+	Scope          string   `json:"scope"`
+	SecurityDomain string   `json:"securityDomain"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &managedHSMInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &managedHSMBindingDetails{}
+}
@@ -0,0 +1,36 @@
+package managedhsm
+
+import (
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// Unbind removes the role assignments that were made when binding
+func (s *serviceManager) Unbind(
+	instance service.Instance,
+	bindingDetails service.BindingDetails,
+) error {
+	dt, ok := instance.Details.(*managedHSMInstanceDetails)
+	if !ok {
+		return errors.New(
+			"error casting instance.Details as *managedHSMInstanceDetails",
+		)
+	}
+	bd, ok := bindingDetails.(*managedHSMBindingDetails)
+	if !ok {
+		return errors.New(
+			"error casting bindingDetails as *managedHSMBindingDetails",
+		)
+	}
+	for _, roleAssignmentName := range bd.RoleAssignmentNames {
+		if err := s.managedHSMManager.DeleteRoleAssignment(
+			dt.HSMURI,
+			bd.Scope,
+			roleAssignmentName,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package managedlustre
+
+// nolint: lll
+var armTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "fileSystemName": {
+      "type": "string"
+    },
+    "subnetId": {
+      "type": "string",
+      "metadata": {
+        "description": "Resource ID of the subnet the file system is deployed into"
+      }
+    },
+    "sku": {
+      "type": "string",
+      "allowedValues": [
+        "AMLFS-Durable-Premium-40",
+        "AMLFS-Durable-Premium-125",
+        "AMLFS-Durable-Premium-250",
+        "AMLFS-Durable-Premium-500"
+      ]
+    },
+    "storageCapacityTiB": {
+      "type": "int"
+    },
+    "maintenanceDayOfWeek": {
+      "type": "string"
+    },
+    "maintenanceTimeOfDayUtc": {
+      "type": "string"
+    },
+    "tags": {
+      "type": "object"
+    }
+  },
+  "variables": {
+    "apiVersion": "2024-03-01"
+  },
+  "resources": [
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[parameters('fileSystemName')]",
+      "type": "Microsoft.StorageCache/amlFilesystems",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "sku": {
+        "name": "[parameters('sku')]"
+      },
+      {{- if .zone }}
+      "zones": [
+        "{{ .zone }}"
+      ],
+      {{- end }}
+      "properties": {
+        "storageCapacityTiB": "[parameters('storageCapacityTiB')]",
+        "filesystemSubnet": "[parameters('subnetId')]",
+        "maintenanceWindow": {
+          "dayOfWeek": "[parameters('maintenanceDayOfWeek')]",
+          "timeOfDayUTC": "[parameters('maintenanceTimeOfDayUtc')]"
+        }
+      }
+    }
+  ],
+  "outputs": {
+    "fileSystemId": {
+      "type": "string",
+      "value": "[resourceId('Microsoft.StorageCache/amlFilesystems', parameters('fileSystemName'))]"
+    },
+    "mgsAddress": {
+      "type": "string",
+      "value": "[reference(parameters('fileSystemName')).clientInfo.mgsAddress]"
+    },
+    "mountCommand": {
+      "type": "string",
+      "value": "[reference(parameters('fileSystemName')).clientInfo.mountCommand]"
+    },
+    "lustreVersion": {
+      "type": "string",
+      "value": "[reference(parameters('fileSystemName')).clientInfo.lustreVersion]"
+    }
+  }
+}
+`)
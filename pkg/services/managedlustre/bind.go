@@ -0,0 +1,53 @@
+package managedlustre
+
+import (
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) ValidateBindingParameters(
+	service.BindingParameters,
+) error {
+	// There are no parameters for binding to a Managed Lustre file system, so
+	// there is nothing to validate
+	return nil
+}
+
+func (s *serviceManager) Bind(
+	service.Instance,
+	service.BindingParameters,
+) (service.BindingDetails, error) {
+	// Lustre clients authenticate by network reachability alone, so binding
+	// only needs to hand out what the instance already knows
+	return &managedLustreBindingDetails{}, nil
+}
+
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	_ service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*managedLustreInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *managedLustreInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*managedlustre.ProvisioningParameters",
+		)
+	}
+	applyDefaults(pp)
+	return &managedLustreCredentials{
+		FileSystemName:     dt.FileSystemName,
+		FileSystemID:       dt.FileSystemID,
+		MGSAddress:         dt.MGSAddress,
+		MountCommand:       dt.MountCommand,
+		LustreVersion:      dt.LustreVersion,
+		SKU:                pp.SKU,
+		StorageCapacityTiB: pp.StorageCapacityTiB,
+	}, nil
+}
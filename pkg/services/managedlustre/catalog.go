@@ -0,0 +1,31 @@
+package managedlustre
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:          "a61df735-9596-4a99-89d2-b90a87f45407",
+				Name:        "azure-managed-lustre",
+				Description: "Azure Managed Lustre (Experimental)",
+				Bindable:    true,
+				Tags: []string{
+					"Azure",
+					"Managed Lustre",
+					"Lustre",
+					"HPC",
+					"File System",
+				},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:   "40ebd6f4-4561-4338-a5fd-f5a8ad9c0264",
+				Name: "durable-premium",
+				Description: "A Lustre file system in an existing virtual network, " +
+					"with a throughput tier and storage capacity of your choosing",
+				Free: false,
+			}),
+		),
+	}), nil
+}
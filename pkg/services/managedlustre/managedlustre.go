@@ -0,0 +1,42 @@
+package managedlustre
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/managedlustre"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer          arm.Deployer
+	managedLustreManager managedlustre.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of provisioning Azure Managed Lustre file systems
+func New(
+	armDeployer arm.Deployer,
+	managedLustreManager managedlustre.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer:          armDeployer,
+			managedLustreManager: managedLustreManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "managedlustre"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{"Microsoft.StorageCache"}
+}
@@ -0,0 +1,294 @@
+package managedlustre
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/generate"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	defaultSKU                     = "AMLFS-Durable-Premium-250"
+	defaultMaintenanceDayOfWeek    = "Sunday"
+	defaultMaintenanceTimeOfDayUTC = "00:00"
+)
+
+// capacityIncrementsTiB maps each SKU to the increment, in TiB, in which its
+// file systems' storage capacity is sold. The smallest file system of each
+// SKU has a single increment of capacity.
+var capacityIncrementsTiB = map[string]int{
+	"AMLFS-Durable-Premium-40":  48,
+	"AMLFS-Durable-Premium-125": 16,
+	"AMLFS-Durable-Premium-250": 8,
+	"AMLFS-Durable-Premium-500": 4,
+}
+
+var daysOfWeek = []string{
+	"Monday",
+	"Tuesday",
+	"Wednesday",
+	"Thursday",
+	"Friday",
+	"Saturday",
+	"Sunday",
+}
+
+var timeOfDayRegex = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*managedlustre.ProvisioningParameters",
+		)
+	}
+	if pp.SubnetID == "" {
+		return service.NewValidationError("subnetId", "subnetId is required")
+	}
+	if !az.IsValidSubnetID(pp.SubnetID) {
+		return service.NewValidationError(
+			"subnetId",
+			fmt.Sprintf(`invalid subnetId: "%s"`, pp.SubnetID),
+		)
+	}
+	if pp.SKU != "" {
+		if _, ok := capacityIncrementsTiB[pp.SKU]; !ok {
+			return service.NewValidationError(
+				"sku",
+				fmt.Sprintf(
+					`invalid sku: "%s"; allowed values are %s`,
+					pp.SKU,
+					strings.Join(getSKUs(), ", "),
+				),
+			)
+		}
+	}
+	if pp.StorageCapacityTiB != 0 {
+		sku := getSKU(pp)
+		increment := capacityIncrementsTiB[sku]
+		if pp.StorageCapacityTiB < 0 || pp.StorageCapacityTiB%increment != 0 {
+			return service.NewValidationError(
+				"storageCapacityTiB",
+				fmt.Sprintf(
+					"invalid storageCapacityTiB: %d; %s file systems are sold in "+
+						"increments of %d TiB",
+					pp.StorageCapacityTiB,
+					sku,
+					increment,
+				),
+			)
+		}
+	}
+	if pp.Zone != "" && pp.Zone != "1" && pp.Zone != "2" && pp.Zone != "3" {
+		return service.NewValidationError(
+			"zone",
+			fmt.Sprintf(
+				`invalid zone: "%s"; allowed values are 1, 2 and 3`,
+				pp.Zone,
+			),
+		)
+	}
+	if pp.MaintenanceDayOfWeek != "" &&
+		!isDayOfWeek(pp.MaintenanceDayOfWeek) {
+		return service.NewValidationError(
+			"maintenanceDayOfWeek",
+			fmt.Sprintf(
+				`invalid maintenanceDayOfWeek: "%s"; allowed values are %s`,
+				pp.MaintenanceDayOfWeek,
+				strings.Join(daysOfWeek, ", "),
+			),
+		)
+	}
+	if pp.MaintenanceTimeOfDayUTC != "" &&
+		!timeOfDayRegex.MatchString(pp.MaintenanceTimeOfDayUTC) {
+		return service.NewValidationError(
+			"maintenanceTimeOfDayUtc",
+			fmt.Sprintf(
+				`invalid maintenanceTimeOfDayUtc: "%s"; times must be given as `+
+					"HH:MM",
+				pp.MaintenanceTimeOfDayUTC,
+			),
+		)
+	}
+	return nil
+}
+
+func (s *serviceManager) ApplyProvisioningParametersDefaults(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*managedlustre.ProvisioningParameters",
+		)
+	}
+	applyDefaults(pp)
+	return nil
+}
+
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep("deployARMTemplate", s.deployARMTemplate),
+	)
+}
+
+// preProvision asks Azure whether the subnet the file system is to be
+// deployed into will do. That depends upon the subnet's size, what else is
+// using it, its location and the file system's SKU and capacity, none of
+// which can be known without looking the subnet up, so it is checked here
+// rather than when parameters are first validated.
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*managedLustreInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *managedLustreInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*managedlustre.ProvisioningParameters",
+		)
+	}
+	applyDefaults(pp)
+	reason, err := s.managedLustreManager.CheckSubnet(
+		pp.SubnetID,
+		instance.Location,
+		pp.SKU,
+		pp.StorageCapacityTiB,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if reason != "" {
+		return nil, service.NewValidationError(
+			"subnetId",
+			fmt.Sprintf(
+				`a %d TiB %s file system can't be deployed into subnet "%s": %s`,
+				pp.StorageCapacityTiB,
+				pp.SKU,
+				pp.SubnetID,
+				reason,
+			),
+		)
+	}
+	dt.ARMDeploymentName = uuid.NewV4().String()
+	dt.FileSystemName = generate.NewIdentifier()
+	return dt, nil
+}
+
+func (s *serviceManager) deployARMTemplate(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*managedLustreInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *managedLustreInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*managedlustre.ProvisioningParameters",
+		)
+	}
+	applyDefaults(pp)
+	outputs, err := s.armDeployer.Deploy(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateBytes,
+		map[string]interface{}{ // Go template params
+			"zone": pp.Zone,
+		},
+		map[string]interface{}{ // ARM template params
+			"fileSystemName":          dt.FileSystemName,
+			"subnetId":                pp.SubnetID,
+			"sku":                     pp.SKU,
+			"storageCapacityTiB":      pp.StorageCapacityTiB,
+			"maintenanceDayOfWeek":    pp.MaintenanceDayOfWeek,
+			"maintenanceTimeOfDayUtc": pp.MaintenanceTimeOfDayUTC,
+		},
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+	for output, field := range map[string]*string{
+		"fileSystemId":  &dt.FileSystemID,
+		"mgsAddress":    &dt.MGSAddress,
+		"mountCommand":  &dt.MountCommand,
+		"lustreVersion": &dt.LustreVersion,
+	} {
+		value, ok := outputs[output].(string)
+		if !ok {
+			return nil, fmt.Errorf(
+				"error retrieving %s from deployment",
+				output,
+			)
+		}
+		*field = value
+	}
+	return dt, nil
+}
+
+// applyDefaults sets, in place, each unspecified parameter that has a default
+// to that default. A file system's zone has none; it is only placed in one if
+// asked to be.
+func applyDefaults(pp *ProvisioningParameters) {
+	pp.SKU = getSKU(pp)
+	if pp.StorageCapacityTiB == 0 {
+		pp.StorageCapacityTiB = capacityIncrementsTiB[pp.SKU]
+	}
+	if pp.MaintenanceDayOfWeek == "" {
+		pp.MaintenanceDayOfWeek = defaultMaintenanceDayOfWeek
+	}
+	if pp.MaintenanceTimeOfDayUTC == "" {
+		pp.MaintenanceTimeOfDayUTC = defaultMaintenanceTimeOfDayUTC
+	}
+}
+
+func getSKU(pp *ProvisioningParameters) string {
+	if pp.SKU == "" {
+		return defaultSKU
+	}
+	return pp.SKU
+}
+
+func getSKUs() []string {
+	skus := make([]string, 0, len(capacityIncrementsTiB))
+	for sku := range capacityIncrementsTiB {
+		skus = append(skus, sku)
+	}
+	sort.Strings(skus)
+	return skus
+}
+
+func isDayOfWeek(day string) bool {
+	for _, dayOfWeek := range daysOfWeek {
+		if day == dayOfWeek {
+			return true
+		}
+	}
+	return false
+}
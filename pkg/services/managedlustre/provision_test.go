@@ -0,0 +1,131 @@
+package managedlustre
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testSubnetID = "/subscriptions/foo/resourceGroups/bar/providers/" +
+	"Microsoft.Network/virtualNetworks/baz/subnets/lustre"
+
+func TestValidateProvisioningParametersWithInvalidSubnetID(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.SubnetID = "/subscriptions/foo/resourceGroups/bar"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.SubnetID = testSubnetID
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidSKU(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		SubnetID: testSubnetID,
+		SKU:      "AMLFS-Durable-Premium-1000",
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.SKU = "AMLFS-Durable-Premium-40"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidCapacity(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		SubnetID:           testSubnetID,
+		StorageCapacityTiB: 12,
+	}
+	// The default SKU is sold in increments of 8 TiB
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.StorageCapacityTiB = 16
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+	pp.SKU = "AMLFS-Durable-Premium-40"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.StorageCapacityTiB = 96
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+	pp.StorageCapacityTiB = -48
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidPlacement(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		SubnetID: testSubnetID,
+		Zone:     "4",
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.Zone = "2"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidMaintenanceWindow(
+	t *testing.T,
+) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		SubnetID:             testSubnetID,
+		MaintenanceDayOfWeek: "Caturday",
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.MaintenanceDayOfWeek = "Wednesday"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+	for _, time := range []string{"24:00", "9:30", "09:60", "noon"} {
+		pp.MaintenanceTimeOfDayUTC = time
+		err = m.serviceManager.ValidateProvisioningParameters(pp)
+		assert.NotNil(t, err, time)
+	}
+	pp.MaintenanceTimeOfDayUTC = "23:30"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestApplyProvisioningParametersDefaults(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		SubnetID: testSubnetID,
+	}
+	err := m.serviceManager.ApplyProvisioningParametersDefaults(pp)
+	assert.Nil(t, err)
+	assert.Equal(t, defaultSKU, pp.SKU)
+	assert.Equal(t, 8, pp.StorageCapacityTiB)
+	assert.Equal(t, defaultMaintenanceDayOfWeek, pp.MaintenanceDayOfWeek)
+	assert.Equal(t, defaultMaintenanceTimeOfDayUTC, pp.MaintenanceTimeOfDayUTC)
+	assert.Empty(t, pp.Zone)
+	// The default capacity depends upon the SKU
+	pp = &ProvisioningParameters{
+		SubnetID: testSubnetID,
+		SKU:      "AMLFS-Durable-Premium-40",
+	}
+	err = m.serviceManager.ApplyProvisioningParametersDefaults(pp)
+	assert.Nil(t, err)
+	assert.Equal(t, 48, pp.StorageCapacityTiB)
+	// Parameters that were specified are left as they were
+	pp = &ProvisioningParameters{
+		SubnetID:                testSubnetID,
+		SKU:                     "AMLFS-Durable-Premium-500",
+		StorageCapacityTiB:      20,
+		MaintenanceDayOfWeek:    "Friday",
+		MaintenanceTimeOfDayUTC: "22:00",
+	}
+	err = m.serviceManager.ApplyProvisioningParametersDefaults(pp)
+	assert.Nil(t, err)
+	assert.Equal(t, "AMLFS-Durable-Premium-500", pp.SKU)
+	assert.Equal(t, 20, pp.StorageCapacityTiB)
+	assert.Equal(t, "Friday", pp.MaintenanceDayOfWeek)
+	assert.Equal(t, "22:00", pp.MaintenanceTimeOfDayUTC)
+}
@@ -0,0 +1,72 @@
+package managedlustre
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// ProvisioningParameters encapsulates Azure Managed Lustre-specific
+// provisioning options
+type ProvisioningParameters struct {
+	SubnetID                string `json:"subnetId"`
+	SKU                     string `json:"sku"`
+	StorageCapacityTiB      int    `json:"storageCapacityTiB"`
+	Zone                    string `json:"zone"`
+	MaintenanceDayOfWeek    string `json:"maintenanceDayOfWeek"`
+	MaintenanceTimeOfDayUTC string `json:"maintenanceTimeOfDayUtc"`
+}
+
+type managedLustreInstanceDetails struct {
+	ARMDeploymentName string `json:"armDeployment"`
+	FileSystemName    string `json:"fileSystemName"`
+	FileSystemID      string `json:"fileSystemId"`
+	MGSAddress        string `json:"mgsAddress"`
+	MountCommand      string `json:"mountCommand"`
+	LustreVersion     string `json:"lustreVersion"`
+}
+
+// UpdatingParameters encapsulates Azure Managed Lustre-specific updating
+// options
+type UpdatingParameters struct {
+}
+
+// BindingParameters encapsulates Azure Managed Lustre-specific binding
+// options
+type BindingParameters struct {
+}
+
+type managedLustreBindingDetails struct {
+}
+
+type managedLustreCredentials struct {
+	FileSystemName     string `json:"fileSystemName"`
+	FileSystemID       string `json:"fileSystemId"`
+	MGSAddress         string `json:"mgsAddress"`
+	MountCommand       string `json:"mountCommand"`
+	LustreVersion      string `json:"lustreVersion"`
+	SKU                string `json:"sku"`
+	StorageCapacityTiB int    `json:"storageCapacityTiB"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &managedLustreInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &managedLustreBindingDetails{}
+}
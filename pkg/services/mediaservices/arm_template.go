@@ -0,0 +1,99 @@
+package mediaservices
+
+// nolint: lll
+var armTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "mediaServicesName": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of the Media Services account"
+      }
+    },
+    {{- if .createStorageAccount }}
+    "storageAccountName": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of the storage account to create for the Media Services account"
+      }
+    },
+    {{- else }}
+    "storageAccountId": {
+      "type": "string",
+      "metadata": {
+        "description": "Resource ID of the existing storage account to use"
+      }
+    },
+    {{- end }}
+    "tags": {
+      "type": "object"
+    }
+  },
+  "variables": {
+    "apiVersion": "2023-01-01",
+    {{- if .createStorageAccount }}
+    "storageAccountId": "[resourceId('Microsoft.Storage/storageAccounts', parameters('storageAccountName'))]"
+    {{- else }}
+    "storageAccountId": "[parameters('storageAccountId')]"
+    {{- end }}
+  },
+  "resources": [
+    {{- if .createStorageAccount }}
+    {
+      "apiVersion": "2021-09-01",
+      "name": "[parameters('storageAccountName')]",
+      "type": "Microsoft.Storage/storageAccounts",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "kind": "StorageV2",
+      "sku": {
+        "name": "Standard_LRS"
+      },
+      "properties": {
+        "minimumTlsVersion": "TLS1_2",
+        "supportsHttpsTrafficOnly": true
+      }
+    },
+    {{- end }}
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[parameters('mediaServicesName')]",
+      "type": "Microsoft.Media/mediaservices",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      {{- if .createStorageAccount }}
+      "dependsOn": [
+        "[variables('storageAccountId')]"
+      ],
+      {{- end }}
+      "properties": {
+        "storageAccounts": [
+          {
+            "id": "[variables('storageAccountId')]",
+            "type": "Primary"
+          }
+        ]
+      }
+    }
+  ],
+  "outputs": {
+    "mediaServicesId": {
+      "type": "string",
+      "value": "[resourceId('Microsoft.Media/mediaservices', parameters('mediaServicesName'))]"
+    },
+    "accountId": {
+      "type": "string",
+      "value": "[reference(parameters('mediaServicesName')).mediaServiceId]"
+    },
+    "storageAccountId": {
+      "type": "string",
+      "value": "[variables('storageAccountId')]"
+    }
+  }
+}
+`)
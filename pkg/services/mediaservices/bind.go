@@ -0,0 +1,149 @@
+package mediaservices
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const defaultRole = "Media Services Account Administrator"
+
+// roleDefinitionIDs maps the names of the built-in Media Services roles that a
+// binding may assign to their role definition IDs
+var roleDefinitionIDs = map[string]string{
+	"Media Services Account Administrator":             "054126f8-9a2b-4f1c-a9ad-eca461f08466", // nolint: lll
+	"Media Services Live Events Administrator":         "532bc159-b25e-42c0-969e-a1d439f60d77", // nolint: lll
+	"Media Services Media Operator":                    "e4395492-1534-4db2-bedf-88c14621589c", // nolint: lll
+	"Media Services Policy Administrator":              "c4bba371-dacd-4a26-b320-7250bca963ae", // nolint: lll
+	"Media Services Streaming Endpoints Administrator": "99dba123-b5fe-44d5-874c-ced7199a5804", // nolint: lll
+}
+
+var objectIDRegex = regexp.MustCompile(
+	`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-` +
+		`[0-9a-fA-F]{12}$`,
+)
+
+func (s *serviceManager) ValidateBindingParameters(
+	bindingParameters service.BindingParameters,
+) error {
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return errors.New(
+			"error casting bindingParameters as *mediaservices.BindingParameters",
+		)
+	}
+	if !objectIDRegex.MatchString(bp.PrincipalID) {
+		return service.NewValidationError(
+			"principalId",
+			fmt.Sprintf(`invalid principalId: "%s"`, bp.PrincipalID),
+		)
+	}
+	if _, ok := roleDefinitionIDs[bp.Role]; bp.Role != "" && !ok {
+		return service.NewValidationError(
+			"role",
+			fmt.Sprintf(
+				`invalid role: "%s"; allowed values are: %s`,
+				bp.Role,
+				strings.Join(getRoleNames(), ", "),
+			),
+		)
+	}
+	return nil
+}
+
+// Bind assigns the requested Media Services role to the principal named in
+// the binding parameters, scoped to the Media Services account alone. The
+// principal authenticates with its own credentials, so the broker issues none.
+func (s *serviceManager) Bind(
+	instance service.Instance,
+	bindingParameters service.BindingParameters,
+) (service.BindingDetails, error) {
+	dt, ok := instance.Details.(*mediaServicesInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *mediaServicesInstanceDetails",
+		)
+	}
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting bindingParameters as *mediaservices.BindingParameters",
+		)
+	}
+	bd := &mediaServicesBindingDetails{
+		PrincipalID:        bp.PrincipalID,
+		Role:               bp.Role,
+		RoleAssignmentName: uuid.NewV4().String(),
+	}
+	if bd.Role == "" {
+		bd.Role = defaultRole
+	}
+	if err := s.mediaServicesManager.CreateRoleAssignment(
+		dt.MediaServicesID,
+		bd.RoleAssignmentName,
+		roleDefinitionIDs[bd.Role],
+		bd.PrincipalID,
+	); err != nil {
+		return nil, err
+	}
+	return bd, nil
+}
+
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	binding service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*mediaServicesInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *mediaServicesInstanceDetails",
+		)
+	}
+	bd, ok := binding.Details.(*mediaServicesBindingDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting binding.Details as *mediaServicesBindingDetails",
+		)
+	}
+	return &Credentials{
+		AccountName:    dt.MediaServicesName,
+		AccountID:      dt.AccountID,
+		SubscriptionID: getSubscriptionID(dt.MediaServicesID),
+		ResourceGroup:  instance.ResourceGroup,
+		TenantID:       s.mediaServicesManager.GetTenantID(),
+		APIEndpoint:    dt.Endpoints.APIEndpoint,
+		AADEndpoint:    dt.Endpoints.AADEndpoint,
+		Scope:          dt.MediaServicesID,
+		PrincipalID:    bd.PrincipalID,
+		Role:           bd.Role,
+		RoleAssignmentID: fmt.Sprintf(
+			"%s/providers/Microsoft.Authorization/roleAssignments/%s",
+			dt.MediaServicesID,
+			bd.RoleAssignmentName,
+		),
+	}, nil
+}
+
+func getRoleNames() []string {
+	roles := make([]string, 0, len(roleDefinitionIDs))
+	for role := range roleDefinitionIDs {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// getSubscriptionID returns the subscription that the resource with the given
+// ID belongs to
+func getSubscriptionID(resourceID string) string {
+	parts := strings.Split(resourceID, "/")
+	if len(parts) < 3 || !strings.EqualFold(parts[1], "subscriptions") {
+		return ""
+	}
+	return parts[2]
+}
@@ -0,0 +1,28 @@
+package mediaservices
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateBindingParameters(t *testing.T) {
+	m := &module{}
+	bp := &BindingParameters{}
+	err := m.serviceManager.ValidateBindingParameters(bp)
+	assert.NotNil(t, err)
+	bp.PrincipalID = "3f7b2c91-8e4d-4a06-b5c1-9d2e6f0a7b38"
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.Nil(t, err)
+	bp.Role = "Media Services Janitor"
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.NotNil(t, err)
+	bp.Role = "Media Services Media Operator"
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.Nil(t, err)
+}
+
+func TestGetSubscriptionID(t *testing.T) {
+	assert.Equal(t, "sub", getSubscriptionID(testStorageAccountID))
+	assert.Equal(t, "", getSubscriptionID("bogus"))
+}
@@ -0,0 +1,54 @@
+package mediaservices
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:          "c69861f3-ff06-4ea1-ab26-aa3d48c589f1",
+				Name:        "azure-media-services",
+				Description: "Azure Media Services (Experimental)",
+				Bindable:    true,
+				Tags:        []string{"Azure", "Media Services", "Video"},
+				// Azure Media Services is only offered in some regions
+				Locations: []string{
+					"australiaeast",
+					"brazilsouth",
+					"canadacentral",
+					"centralindia",
+					"centralus",
+					"eastasia",
+					"eastus",
+					"eastus2",
+					"francecentral",
+					"germanywestcentral",
+					"japaneast",
+					"koreacentral",
+					"northcentralus",
+					"northeurope",
+					"norwayeast",
+					"southafricanorth",
+					"southcentralus",
+					"southeastasia",
+					"switzerlandnorth",
+					"uaenorth",
+					"uksouth",
+					"westcentralus",
+					"westeurope",
+					"westus",
+					"westus2",
+					"westus3",
+				},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:   "adfe1708-5bb0-4276-92a1-5fe8e5f2d082",
+				Name: "standard",
+				Description: "Pay-as-you-go, billed per minute of encoding and " +
+					"streaming",
+				Free: false,
+			}),
+		),
+	}), nil
+}
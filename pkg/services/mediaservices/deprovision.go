@@ -0,0 +1,89 @@
+package mediaservices
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) GetDeprovisioner(
+	service.Plan,
+) (service.Deprovisioner, error) {
+	return service.NewDeprovisioner(
+		service.NewDeprovisioningStep("deleteARMDeployment", s.deleteARMDeployment),
+		service.NewDeprovisioningStep(
+			"deleteMediaServices",
+			s.deleteMediaServices,
+		),
+		service.NewDeprovisioningStep(
+			"deleteStorageAccount",
+			s.deleteStorageAccount,
+		),
+	)
+}
+
+func (s *serviceManager) deleteARMDeployment(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*mediaServicesInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *mediaServicesInstanceDetails",
+		)
+	}
+	if err := s.armDeployer.Delete(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, fmt.Errorf("error deleting ARM deployment: %s", err)
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deleteMediaServices(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*mediaServicesInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *mediaServicesInstanceDetails",
+		)
+	}
+	if err := s.mediaServicesManager.DeleteMediaServices(
+		dt.MediaServicesName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+// deleteStorageAccount deletes the storage account that the broker created for
+// the Media Services account, if it created one. A storage account that was
+// named in the provisioning parameters belongs to someone else and is never
+// deleted.
+func (s *serviceManager) deleteStorageAccount(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*mediaServicesInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *mediaServicesInstanceDetails",
+		)
+	}
+	if dt.StorageAccountName == "" {
+		return dt, nil
+	}
+	if err := s.storageManager.DeleteStorageAccount(
+		dt.StorageAccountName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
@@ -0,0 +1,50 @@
+package mediaservices
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/mediaservices"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/storage"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer          arm.Deployer
+	mediaServicesManager mediaservices.Manager
+	storageManager       storage.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of provisioning Azure Media Services accounts
+func New(
+	armDeployer arm.Deployer,
+	mediaServicesManager mediaservices.Manager,
+	storageManager storage.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer:          armDeployer,
+			mediaServicesManager: mediaServicesManager,
+			storageManager:       storageManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "mediaservices"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{
+		"Microsoft.Media",
+		"Microsoft.Storage",
+		"Microsoft.Authorization",
+	}
+}
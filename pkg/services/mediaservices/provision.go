@@ -0,0 +1,265 @@
+package mediaservices
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+// storageAccountIDRegex matches the resource IDs of storage accounts and
+// captures their subscription, resource group and name
+var storageAccountIDRegex = regexp.MustCompile(
+	`(?i)^/subscriptions/([^/]+)/resourceGroups/([^/]+)/providers/` +
+		`Microsoft\.Storage/storageAccounts/([^/]+)$`,
+)
+
+// storageAccountKinds are the kinds of storage account that Media Services
+// can use as an account's primary storage
+var storageAccountKinds = []string{"Storage", "StorageV2"}
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*mediaservices.ProvisioningParameters",
+		)
+	}
+	if pp.StorageAccountID != "" &&
+		!storageAccountIDRegex.MatchString(pp.StorageAccountID) {
+		return service.NewValidationError(
+			"storageAccountId",
+			fmt.Sprintf(
+				`invalid storage account resource id: "%s"`,
+				pp.StorageAccountID,
+			),
+		)
+	}
+	return nil
+}
+
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep(
+			"validateStorageAccount",
+			s.validateStorageAccount,
+		),
+		service.NewProvisioningStep("deployARMTemplate", s.deployARMTemplate),
+	)
+}
+
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*mediaServicesInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *mediaServicesInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*mediaservices.ProvisioningParameters",
+		)
+	}
+	dt.ARMDeploymentName = uuid.NewV4().String()
+	// Media Services account and storage account names are both limited to 24
+	// lowercase letters and numbers
+	dt.MediaServicesName = "ams" + getRandomName()[:21]
+	if pp.StorageAccountID == "" {
+		dt.StorageAccountName = "amssa" + getRandomName()[:19]
+	}
+	return dt, nil
+}
+
+// validateStorageAccount verifies that an existing storage account named in the
+// provisioning parameters exists, is of a kind Media Services can use, and is
+// in the same region as the Media Services account, as Azure requires. There
+// is nothing to verify if the broker is to create the storage account itself.
+func (s *serviceManager) validateStorageAccount(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*mediaservices.ProvisioningParameters",
+		)
+	}
+	if pp.StorageAccountID == "" {
+		return instance.Details, nil
+	}
+	matches := storageAccountIDRegex.FindStringSubmatch(pp.StorageAccountID)
+	if matches == nil {
+		return nil, fmt.Errorf(
+			`invalid storage account resource id: "%s"`,
+			pp.StorageAccountID,
+		)
+	}
+	storageAccount, found, err := s.mediaServicesManager.GetStorageAccount(
+		matches[1],
+		matches[2],
+		matches[3],
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf(
+			`storage account "%s" not found`,
+			pp.StorageAccountID,
+		)
+	}
+	if err := validateStorageAccountLinkage(
+		storageAccount.Kind,
+		storageAccount.Location,
+		instance.Location,
+	); err != nil {
+		return nil, fmt.Errorf(
+			`storage account "%s" cannot be used: %s`,
+			pp.StorageAccountID,
+			err,
+		)
+	}
+	return instance.Details, nil
+}
+
+func (s *serviceManager) deployARMTemplate(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*mediaServicesInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *mediaServicesInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*mediaservices.ProvisioningParameters",
+		)
+	}
+	goParams, armParams := buildARMTemplateParameters(dt, pp)
+	outputs, err := s.armDeployer.Deploy(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateBytes,
+		goParams,
+		armParams,
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+
+	mediaServicesID, ok := outputs["mediaServicesId"].(string)
+	if !ok {
+		return nil, errors.New(
+			"error retrieving Media Services account id from deployment",
+		)
+	}
+	dt.MediaServicesID = mediaServicesID
+
+	accountID, ok := outputs["accountId"].(string)
+	if !ok {
+		return nil, errors.New(
+			"error retrieving Media Services account's service id from deployment",
+		)
+	}
+	dt.AccountID = accountID
+
+	storageAccountID, ok := outputs["storageAccountId"].(string)
+	if !ok {
+		return nil, errors.New("error retrieving storage account id from deployment")
+	}
+	dt.StorageAccountID = storageAccountID
+
+	dt.Endpoints = s.mediaServicesManager.GetEndpoints(dt.MediaServicesID)
+
+	return dt, nil
+}
+
+// buildARMTemplateParameters returns the Go template parameters and the ARM
+// template parameters used to deploy a Media Services account, along with a
+// storage account for it if the broker is to create one
+func buildARMTemplateParameters(
+	dt *mediaServicesInstanceDetails,
+	pp *ProvisioningParameters,
+) (map[string]interface{}, map[string]interface{}) {
+	createStorageAccount := pp.StorageAccountID == ""
+	goParams := map[string]interface{}{
+		"createStorageAccount": createStorageAccount,
+	}
+	armParams := map[string]interface{}{
+		"mediaServicesName": dt.MediaServicesName,
+	}
+	if createStorageAccount {
+		armParams["storageAccountName"] = dt.StorageAccountName
+	} else {
+		armParams["storageAccountId"] = pp.StorageAccountID
+	}
+	return goParams, armParams
+}
+
+// validateStorageAccountLinkage returns an error if a storage account of the
+// given kind, in the given location, can't serve as the primary storage of a
+// Media Services account in the other given location
+func validateStorageAccountLinkage(
+	kind string,
+	storageAccountLocation string,
+	mediaServicesLocation string,
+) error {
+	if !isValidStorageAccountKind(kind) {
+		return fmt.Errorf(
+			`storage account kind "%s" is not supported; supported kinds are: %s`,
+			kind,
+			strings.Join(storageAccountKinds, ", "),
+		)
+	}
+	if normalizeLocation(storageAccountLocation) !=
+		normalizeLocation(mediaServicesLocation) {
+		return fmt.Errorf(
+			`storage account is in location "%s", but the Media Services account `+
+				`is to be provisioned in location "%s"`,
+			storageAccountLocation,
+			mediaServicesLocation,
+		)
+	}
+	return nil
+}
+
+func isValidStorageAccountKind(kind string) bool {
+	for _, k := range storageAccountKinds {
+		if kind == k {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeLocation converts a location's display name (e.g. "East US") to
+// its name (e.g. "eastus"), so that locations can be compared
+func normalizeLocation(location string) string {
+	return strings.ToLower(strings.Replace(location, " ", "", -1))
+}
+
+func getRandomName() string {
+	return strings.Replace(uuid.NewV4().String(), "-", "", -1)
+}
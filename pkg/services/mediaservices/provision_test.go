@@ -0,0 +1,60 @@
+package mediaservices
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testStorageAccountID = "/subscriptions/sub/resourceGroups/rg/" +
+	"providers/Microsoft.Storage/storageAccounts/sa"
+
+func TestValidateProvisioningParametersWithDefaults(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithStorageAccountID(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		StorageAccountID: "/subscriptions/sub/resourceGroups/rg/providers/" +
+			"Microsoft.KeyVault/vaults/kv",
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.StorageAccountID = testStorageAccountID
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestBuildARMTemplateParameters(t *testing.T) {
+	dt := &mediaServicesInstanceDetails{
+		MediaServicesName:  "ams",
+		StorageAccountName: "amssa",
+	}
+	goParams, armParams := buildARMTemplateParameters(
+		dt,
+		&ProvisioningParameters{},
+	)
+	assert.Equal(t, true, goParams["createStorageAccount"])
+	assert.Equal(t, "amssa", armParams["storageAccountName"])
+	assert.NotContains(t, armParams, "storageAccountId")
+	goParams, armParams = buildARMTemplateParameters(
+		&mediaServicesInstanceDetails{MediaServicesName: "ams"},
+		&ProvisioningParameters{StorageAccountID: testStorageAccountID},
+	)
+	assert.Equal(t, false, goParams["createStorageAccount"])
+	assert.Equal(t, testStorageAccountID, armParams["storageAccountId"])
+	assert.NotContains(t, armParams, "storageAccountName")
+}
+
+func TestValidateStorageAccountLinkage(t *testing.T) {
+	err := validateStorageAccountLinkage("StorageV2", "East US", "eastus")
+	assert.Nil(t, err)
+	err = validateStorageAccountLinkage("BlobStorage", "eastus", "eastus")
+	assert.NotNil(t, err)
+	err = validateStorageAccountLinkage("StorageV2", "westus", "eastus")
+	assert.NotNil(t, err)
+}
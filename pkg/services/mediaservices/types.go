@@ -0,0 +1,88 @@
+package mediaservices
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/mediaservices"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// ProvisioningParameters encapsulates Azure Media Services-specific
+// provisioning options
+type ProvisioningParameters struct {
+	// StorageAccountID is the resource ID of an existing storage account to use
+	// as the Media Services account's primary storage. If it is omitted, the
+	// broker creates a storage account for the Media Services account.
+	StorageAccountID string `json:"storageAccountId"`
+}
+
+type mediaServicesInstanceDetails struct {
+	ARMDeploymentName string `json:"armDeployment"`
+	MediaServicesName string `json:"mediaServicesName"`
+	MediaServicesID   string `json:"mediaServicesId"`
+	// AccountID is the ID that Media Services itself assigns the account
+	AccountID        string `json:"accountId"`
+	StorageAccountID string `json:"storageAccountId"`
+	// StorageAccountName is empty unless the broker created the storage account,
+	// in which case the broker also deletes it
+	StorageAccountName string                  `json:"storageAccountName"`
+	Endpoints          mediaservices.Endpoints `json:"endpoints"`
+}
+
+// UpdatingParameters encapsulates Azure Media Services-specific updating
+// options
+type UpdatingParameters struct {
+}
+
+// BindingParameters encapsulates Azure Media Services-specific binding options
+type BindingParameters struct {
+	PrincipalID string `json:"principalId"`
+	Role        string `json:"role"`
+}
+
+type mediaServicesBindingDetails struct {
+	PrincipalID        string `json:"principalId"`
+	Role               string `json:"role"`
+	RoleAssignmentName string `json:"roleAssignmentName"`
+}
+
+// Credentials encapsulates Azure Media Services-specific connection details
+type Credentials struct {
+	AccountName    string `json:"accountName"`
+	AccountID      string `json:"accountId"`
+	SubscriptionID string `json:"subscriptionId"`
+	ResourceGroup  string `json:"resourceGroup"`
+	TenantID       string `json:"tenantId"`
+	APIEndpoint    string `json:"apiEndpoint"`
+	AADEndpoint    string `json:"aadEndpoint"`
+	// Scope is the resource ID of the Media Services account, which is also the
+	// scope at which the principal's role was assigned
+	Scope            string `json:"scope"`
+	PrincipalID      string `json:"principalId"`
+	Role             string `json:"role"`
+	RoleAssignmentID string `json:"roleAssignmentId"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &mediaServicesInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &mediaServicesBindingDetails{}
+}
@@ -0,0 +1,29 @@
+package mediaservices
+
+import (
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) Unbind(
+	instance service.Instance,
+	bindingDetails service.BindingDetails,
+) error {
+	dt, ok := instance.Details.(*mediaServicesInstanceDetails)
+	if !ok {
+		return errors.New(
+			"error casting instance.Details as *mediaServicesInstanceDetails",
+		)
+	}
+	bd, ok := bindingDetails.(*mediaServicesBindingDetails)
+	if !ok {
+		return errors.New(
+			"error casting bindingDetails as *mediaServicesBindingDetails",
+		)
+	}
+	return s.mediaServicesManager.DeleteRoleAssignment(
+		dt.MediaServicesID,
+		bd.RoleAssignmentName,
+	)
+}
@@ -0,0 +1,105 @@
+package mongovcore
+
+// nolint: lll
+var armTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "clusterName": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of the cluster, which is unique across Azure"
+      }
+    },
+    "administratorLogin": {
+      "type": "string"
+    },
+    "administratorLoginPassword": {
+      "type": "securestring"
+    },
+    "serverVersion": {
+      "type": "string"
+    },
+    "tier": {
+      "type": "string"
+    },
+    "storageSizeGb": {
+      "type": "int"
+    },
+    "highAvailability": {
+      "type": "string",
+      "allowedValues": [
+        "Disabled",
+        "SameZone",
+        "ZoneRedundantPreferred"
+      ]
+    },
+    {{- if .firewallRule }}
+    "firewallStartIpAddress": {
+      "type": "string"
+    },
+    "firewallEndIpAddress": {
+      "type": "string"
+    },
+    {{- end }}
+    "tags": {
+      "type": "object"
+    }
+  },
+  "variables": {
+    "apiVersion": "2024-07-01"
+  },
+  "resources": [
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[parameters('clusterName')]",
+      "type": "Microsoft.DocumentDB/mongoClusters",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "properties": {
+        "administrator": {
+          "userName": "[parameters('administratorLogin')]",
+          "password": "[parameters('administratorLoginPassword')]"
+        },
+        "serverVersion": "[parameters('serverVersion')]",
+        "compute": {
+          "tier": "[parameters('tier')]"
+        },
+        "storage": {
+          "sizeGb": "[parameters('storageSizeGb')]"
+        },
+        "highAvailability": {
+          "targetMode": "[parameters('highAvailability')]"
+        },
+        "sharding": {
+          "shardCount": 1
+        },
+        "publicNetworkAccess": "Enabled"
+      }
+    }{{ if .firewallRule }},
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[concat(parameters('clusterName'), '/', 'firewallRule')]",
+      "type": "Microsoft.DocumentDB/mongoClusters/firewallRules",
+      "dependsOn": [
+        "[resourceId('Microsoft.DocumentDB/mongoClusters', parameters('clusterName'))]"
+      ],
+      "properties": {
+        "startIpAddress": "[parameters('firewallStartIpAddress')]",
+        "endIpAddress": "[parameters('firewallEndIpAddress')]"
+      }
+    }
+    {{- end }}
+  ],
+  "outputs": {
+    "connectionString": {
+      "type": "string",
+      "value": "[reference(resourceId('Microsoft.DocumentDB/mongoClusters', parameters('clusterName')), variables('apiVersion')).connectionString]"
+    }
+  }
+}
+`)
@@ -0,0 +1,40 @@
+package mongovcore
+
+import (
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) ValidateBindingParameters(
+	service.BindingParameters,
+) error {
+	// There are no parameters for binding to a MongoDB vCore cluster, so there
+	// is nothing to validate
+	return nil
+}
+
+func (s *serviceManager) Bind(
+	service.Instance,
+	service.BindingParameters,
+) (service.BindingDetails, error) {
+	return &mongoVCoreBindingDetails{}, nil
+}
+
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	_ service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*mongoVCoreInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *mongoVCoreInstanceDetails",
+		)
+	}
+	return &Credentials{
+		Host:             dt.Host,
+		Username:         dt.AdministratorLogin,
+		Password:         dt.AdministratorLoginPassword,
+		ConnectionString: dt.ConnectionString,
+	}, nil
+}
@@ -0,0 +1,61 @@
+package mongovcore
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:   "b2e84c17-6d3a-4f59-9e71-0c5a8d3f2b64",
+				Name: "azure-cosmos-mongo-vcore",
+				Description: "Azure Cosmos DB for MongoDB vCore (Experimental), " +
+					"a MongoDB-compatible database provisioned as a cluster",
+				Bindable: true,
+				Tags: []string{
+					"Azure",
+					"CosmosDB",
+					"Database",
+					"MongoDB",
+					"vCore",
+				},
+				// MongoDB vCore clusters are only offered in some regions
+				Locations: []string{
+					"australiaeast",
+					"brazilsouth",
+					"canadacentral",
+					"centralindia",
+					"centralus",
+					"eastasia",
+					"eastus",
+					"eastus2",
+					"francecentral",
+					"germanywestcentral",
+					"japaneast",
+					"koreacentral",
+					"northcentralus",
+					"northeurope",
+					"norwayeast",
+					"southafricanorth",
+					"southcentralus",
+					"southeastasia",
+					"swedencentral",
+					"switzerlandnorth",
+					"uaenorth",
+					"uksouth",
+					"westeurope",
+					"westus",
+					"westus2",
+					"westus3",
+				},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:   "5f0d9a62-c84b-4e13-a7b6-39e2f1c8d705",
+				Name: "vcore",
+				Description: "Billed per cluster by compute tier and provisioned " +
+					"storage; the tier can be changed after provisioning",
+				Free: false,
+			}),
+		),
+	}), nil
+}
@@ -0,0 +1,44 @@
+package mongovcore
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/mongovcore"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer    arm.Deployer
+	clusterManager mongovcore.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of provisioning Azure Cosmos DB for MongoDB vCore
+// clusters. Unlike the RU-based MongoDB API offered by the cosmosdb module,
+// these are provisioned as clusters of a chosen compute tier.
+func New(
+	armDeployer arm.Deployer,
+	clusterManager mongovcore.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer:    armDeployer,
+			clusterManager: clusterManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "mongovcore"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{"Microsoft.DocumentDB"}
+}
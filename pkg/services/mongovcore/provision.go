@@ -0,0 +1,434 @@
+package mongovcore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/generate"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	tierFree                      = "Free"
+	highAvailabilityDisabled      = "Disabled"
+	highAvailabilitySameZone      = "SameZone"
+	highAvailabilityZoneRedundant = "ZoneRedundantPreferred"
+	// freeTierStorageSizeGB is the only storage size the free tier offers
+	freeTierStorageSizeGB = 32
+)
+
+const (
+	defaultTier             = "M30"
+	defaultStorageSizeGB    = 128
+	defaultHighAvailability = highAvailabilityDisabled
+	defaultServerVersion    = "7.0"
+)
+
+var tiers = []string{
+	tierFree,
+	"M10",
+	"M20",
+	"M25",
+	"M30",
+	"M40",
+	"M50",
+	"M60",
+	"M80",
+	"M200",
+}
+
+var storageSizesGB = []int{
+	32,
+	64,
+	128,
+	256,
+	512,
+	1024,
+	2048,
+	4096,
+	8192,
+	16384,
+}
+
+var highAvailabilityModes = []string{
+	highAvailabilityDisabled,
+	highAvailabilitySameZone,
+	highAvailabilityZoneRedundant,
+}
+
+var serverVersions = []string{"5.0", "6.0", "7.0"}
+
+// freeTierLocations are the regions in which free tier clusters are offered
+var freeTierLocations = map[string]bool{
+	"australiaeast":      true,
+	"canadacentral":      true,
+	"centralus":          true,
+	"eastus":             true,
+	"eastus2":            true,
+	"francecentral":      true,
+	"germanywestcentral": true,
+	"japaneast":          true,
+	"northeurope":        true,
+	"southcentralus":     true,
+	"southeastasia":      true,
+	"uksouth":            true,
+	"westeurope":         true,
+	"westus2":            true,
+	"westus3":            true,
+}
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*mongovcore.ProvisioningParameters",
+		)
+	}
+	if err := validateTier("tier", pp.Tier); err != nil {
+		return err
+	}
+	tier := getTier(pp)
+	if pp.StorageSizeGB != 0 {
+		if !containsInt(storageSizesGB, pp.StorageSizeGB) {
+			return service.NewValidationError(
+				"storageSizeGb",
+				fmt.Sprintf(
+					"invalid storageSizeGb: %d; allowed values are: %s",
+					pp.StorageSizeGB,
+					joinInts(storageSizesGB),
+				),
+			)
+		}
+		if tier == tierFree && pp.StorageSizeGB != freeTierStorageSizeGB {
+			return service.NewValidationError(
+				"storageSizeGb",
+				fmt.Sprintf(
+					"invalid storageSizeGb: %d; the free tier only offers %d GB",
+					pp.StorageSizeGB,
+					freeTierStorageSizeGB,
+				),
+			)
+		}
+	}
+	if pp.HighAvailability != "" &&
+		!contains(highAvailabilityModes, pp.HighAvailability) {
+		return service.NewValidationError(
+			"highAvailability",
+			fmt.Sprintf(
+				`invalid highAvailability: "%s"; allowed values are: %s`,
+				pp.HighAvailability,
+				strings.Join(highAvailabilityModes, ", "),
+			),
+		)
+	}
+	if tier == tierFree && getHighAvailability(pp) != highAvailabilityDisabled {
+		return service.NewValidationError(
+			"highAvailability",
+			"high availability is not offered in the free tier",
+		)
+	}
+	if pp.ServerVersion != "" && !contains(serverVersions, pp.ServerVersion) {
+		return service.NewValidationError(
+			"serverVersion",
+			fmt.Sprintf(
+				`invalid serverVersion: "%s"; allowed values are: %s`,
+				pp.ServerVersion,
+				strings.Join(serverVersions, ", "),
+			),
+		)
+	}
+	return validateFirewallRule(pp)
+}
+
+func validateFirewallRule(pp *ProvisioningParameters) error {
+	if pp.FirewallIPStart == "" && pp.FirewallIPEnd == "" {
+		return nil
+	}
+	if pp.FirewallIPStart == "" {
+		return service.NewValidationError(
+			"firewallStartIPAddress",
+			"must be set when firewallEndIPAddress is set",
+		)
+	}
+	if pp.FirewallIPEnd == "" {
+		return service.NewValidationError(
+			"firewallEndIPAddress",
+			"must be set when firewallStartIPAddress is set",
+		)
+	}
+	startIP := net.ParseIP(pp.FirewallIPStart).To4()
+	if startIP == nil {
+		return service.NewValidationError(
+			"firewallStartIPAddress",
+			fmt.Sprintf(`invalid value: "%s"`, pp.FirewallIPStart),
+		)
+	}
+	endIP := net.ParseIP(pp.FirewallIPEnd).To4()
+	if endIP == nil {
+		return service.NewValidationError(
+			"firewallEndIPAddress",
+			fmt.Sprintf(`invalid value: "%s"`, pp.FirewallIPEnd),
+		)
+	}
+	if bytes.Compare(startIP, endIP) > 0 {
+		return service.NewValidationError(
+			"firewallEndIPAddress",
+			fmt.Sprintf(
+				`invalid value: "%s"; must be greater than or equal to `+
+					"firewallStartIPAddress",
+				pp.FirewallIPEnd,
+			),
+		)
+	}
+	return nil
+}
+
+// ValidateProvisioningParametersForLocation validates the cluster's tier
+// against what is offered in the region it is to be provisioned in
+func (s *serviceManager) ValidateProvisioningParametersForLocation(
+	provisioningParameters service.ProvisioningParameters,
+	location string,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*mongovcore.ProvisioningParameters",
+		)
+	}
+	location = strings.ToLower(location)
+	if getTier(pp) == tierFree && !freeTierLocations[location] {
+		return service.NewValidationError(
+			"tier",
+			fmt.Sprintf(
+				`tier "%s" is not available in location "%s"`,
+				tierFree,
+				location,
+			),
+		)
+	}
+	return nil
+}
+
+func (s *serviceManager) ApplyProvisioningParametersDefaults(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*mongovcore.ProvisioningParameters",
+		)
+	}
+	pp.Tier = getTier(pp)
+	if pp.StorageSizeGB == 0 {
+		pp.StorageSizeGB = defaultStorageSizeGB
+		if pp.Tier == tierFree {
+			pp.StorageSizeGB = freeTierStorageSizeGB
+		}
+	}
+	pp.HighAvailability = getHighAvailability(pp)
+	if pp.ServerVersion == "" {
+		pp.ServerVersion = defaultServerVersion
+	}
+	return nil
+}
+
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep("deployARMTemplate", s.deployARMTemplate),
+	)
+}
+
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*mongoVCoreInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *mongoVCoreInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*mongovcore.ProvisioningParameters",
+		)
+	}
+	dt.ARMDeploymentName = uuid.NewV4().String()
+	// Cluster names are limited to 40 characters
+	dt.ClusterName = "mongo-" +
+		strings.Replace(uuid.NewV4().String(), "-", "", -1)[:20]
+	dt.Tier = pp.Tier
+	dt.AdministratorLogin = generate.NewIdentifier()
+	dt.AdministratorLoginPassword = generate.NewPassword()
+	return dt, nil
+}
+
+func (s *serviceManager) deployARMTemplate(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*mongoVCoreInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *mongoVCoreInstanceDetails",
+		)
+	}
+	if err := s.deployCluster(instance, dt); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+// deployCluster deploys the cluster in the given details' tier, recording the
+// connection string Azure reports for it. Provisioning and scaling the
+// cluster both go through here.
+func (s *serviceManager) deployCluster(
+	instance service.Instance,
+	dt *mongoVCoreInstanceDetails,
+) error {
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*mongovcore.ProvisioningParameters",
+		)
+	}
+	goParams, armParams := buildARMTemplateParameters(pp, dt)
+	outputs, err := s.armDeployer.Deploy(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateBytes,
+		goParams,
+		armParams,
+		instance.Tags,
+	)
+	if err != nil {
+		return service.WrapError(err, "error deploying ARM template")
+	}
+	connectionString, ok := outputs["connectionString"].(string)
+	if !ok {
+		return errors.New("error retrieving connection string from deployment")
+	}
+	dt.ConnectionString, dt.Host, err = buildConnectionString(
+		connectionString,
+		dt.AdministratorLogin,
+		dt.AdministratorLoginPassword,
+	)
+	return err
+}
+
+func buildARMTemplateParameters(
+	pp *ProvisioningParameters,
+	dt *mongoVCoreInstanceDetails,
+) (map[string]interface{}, map[string]interface{}) {
+	goParams := map[string]interface{}{
+		"firewallRule": pp.FirewallIPStart != "",
+	}
+	armParams := map[string]interface{}{
+		"clusterName":                dt.ClusterName,
+		"administratorLogin":         dt.AdministratorLogin,
+		"administratorLoginPassword": dt.AdministratorLoginPassword,
+		"serverVersion":              pp.ServerVersion,
+		"tier":                       dt.Tier,
+		"storageSizeGb":              pp.StorageSizeGB,
+		"highAvailability":           pp.HighAvailability,
+	}
+	if pp.FirewallIPStart != "" {
+		armParams["firewallStartIpAddress"] = pp.FirewallIPStart
+		armParams["firewallEndIpAddress"] = pp.FirewallIPEnd
+	}
+	return goParams, armParams
+}
+
+// buildConnectionString fills the given credentials into the connection
+// string Azure reports for a cluster, which has placeholders in their stead,
+// and returns it along with the host it connects to
+func buildConnectionString(
+	connectionString string,
+	username string,
+	password string,
+) (string, string, error) {
+	connectionString = strings.NewReplacer(
+		"<user>", url.QueryEscape(username),
+		"<password>", url.QueryEscape(password),
+	).Replace(connectionString)
+	connectionURL, err := url.Parse(connectionString)
+	if err != nil || connectionURL.Hostname() == "" {
+		return "", "", errors.New(
+			"error parsing connection string reported for cluster",
+		)
+	}
+	return connectionString, connectionURL.Hostname(), nil
+}
+
+func validateTier(field string, tier string) error {
+	if tier != "" && !contains(tiers, tier) {
+		return service.NewValidationError(
+			field,
+			fmt.Sprintf(
+				`invalid tier: "%s"; allowed values are: %s`,
+				tier,
+				strings.Join(tiers, ", "),
+			),
+		)
+	}
+	return nil
+}
+
+func getTier(pp *ProvisioningParameters) string {
+	if pp.Tier == "" {
+		return defaultTier
+	}
+	return pp.Tier
+}
+
+func getHighAvailability(pp *ProvisioningParameters) string {
+	if pp.HighAvailability == "" {
+		return defaultHighAvailability
+	}
+	return pp.HighAvailability
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func joinInts(values []int) string {
+	strs := make([]string, len(values))
+	for i, value := range values {
+		strs[i] = strconv.Itoa(value)
+	}
+	return strings.Join(strs, ", ")
+}
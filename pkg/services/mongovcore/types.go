@@ -0,0 +1,75 @@
+package mongovcore
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// ProvisioningParameters encapsulates MongoDB vCore-specific provisioning
+// options
+type ProvisioningParameters struct {
+	// Tier is the compute tier of the cluster's nodes, e.g. "M30", or "Free"
+	Tier          string `json:"tier"`
+	StorageSizeGB int    `json:"storageSizeGb"`
+	// HighAvailability is one of "Disabled", "SameZone" or
+	// "ZoneRedundantPreferred"
+	HighAvailability string `json:"highAvailability"`
+	ServerVersion    string `json:"serverVersion"`
+	FirewallIPStart  string `json:"firewallStartIPAddress"`
+	FirewallIPEnd    string `json:"firewallEndIPAddress"`
+}
+
+type mongoVCoreInstanceDetails struct {
+	ARMDeploymentName          string `json:"armDeployment"`
+	ClusterName                string `json:"clusterName"`
+	Tier                       string `json:"tier"`
+	AdministratorLogin         string `json:"administratorLogin"`
+	AdministratorLoginPassword string `json:"administratorLoginPassword" secret:"true"` // nolint: lll
+	Host                       string `json:"host"`
+	ConnectionString           string `json:"connectionString" secret:"true"`
+}
+
+// UpdatingParameters encapsulates MongoDB vCore-specific updating options
+type UpdatingParameters struct {
+	// Tier, if specified, is the compute tier the cluster is scaled to
+	Tier string `json:"tier"`
+}
+
+// BindingParameters encapsulates MongoDB vCore-specific binding options
+type BindingParameters struct {
+}
+
+type mongoVCoreBindingDetails struct {
+}
+
+// Credentials encapsulates MongoDB vCore-specific connection details and
+// credentials
+type Credentials struct {
+	Host             string `json:"host"`
+	Username         string `json:"username"`
+	Password         string `json:"password"`
+	ConnectionString string `json:"connectionString"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &mongoVCoreInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &mongoVCoreBindingDetails{}
+}
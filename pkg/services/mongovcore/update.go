@@ -0,0 +1,64 @@
+package mongovcore
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) ValidateUpdatingParameters(
+	updatingParameters service.UpdatingParameters,
+) error {
+	up, ok := updatingParameters.(*UpdatingParameters)
+	if !ok {
+		return errors.New(
+			"error casting updatingParameters as *mongovcore.UpdatingParameters",
+		)
+	}
+	if err := validateTier("tier", up.Tier); err != nil {
+		return err
+	}
+	if up.Tier == tierFree {
+		return service.NewValidationError(
+			"tier",
+			"clusters cannot be scaled to the free tier",
+		)
+	}
+	return nil
+}
+
+func (s *serviceManager) GetUpdater(service.Plan) (service.Updater, error) {
+	return service.NewUpdater(
+		service.NewUpdatingStep("scaleCluster", s.scaleCluster),
+	)
+}
+
+// scaleCluster redeploys the cluster in the requested tier. The cluster's
+// storage, high availability mode and credentials are left as they are.
+func (s *serviceManager) scaleCluster(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*mongoVCoreInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *mongoVCoreInstanceDetails",
+		)
+	}
+	up, ok := instance.UpdatingParameters.(*UpdatingParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.UpdatingParameters as " +
+				"*mongovcore.UpdatingParameters",
+		)
+	}
+	if up.Tier == "" || up.Tier == dt.Tier {
+		return dt, nil
+	}
+	dt.Tier = up.Tier
+	if err := s.deployCluster(instance, dt); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
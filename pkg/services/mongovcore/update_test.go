@@ -0,0 +1,23 @@
+package mongovcore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateUpdatingParameters(t *testing.T) {
+	m := &module{}
+	up := &UpdatingParameters{}
+	err := m.serviceManager.ValidateUpdatingParameters(up)
+	assert.Nil(t, err)
+	up.Tier = "M15"
+	err = m.serviceManager.ValidateUpdatingParameters(up)
+	assert.NotNil(t, err)
+	up.Tier = tierFree
+	err = m.serviceManager.ValidateUpdatingParameters(up)
+	assert.NotNil(t, err)
+	up.Tier = "M50"
+	err = m.serviceManager.ValidateUpdatingParameters(up)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,137 @@
+package monitorworkspace
+
+// nolint: lll
+var armTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "workspaceName": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of the Azure Monitor workspace"
+      }
+    },
+    "publicNetworkAccess": {
+      "type": "string"
+    },
+    {{- if .aksCluster }}
+    "dataCollectionEndpointName": {
+      "type": "string"
+    },
+    "dataCollectionRuleName": {
+      "type": "string"
+    },
+    {{- end }}
+    "tags": {
+      "type": "object"
+    }
+  },
+  "variables": {
+    "apiVersion": "2023-04-03",
+    "insightsApiVersion": "2022-06-01"
+  },
+  "resources": [
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[parameters('workspaceName')]",
+      "type": "Microsoft.Monitor/accounts",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "properties": {
+        "publicNetworkAccess": "[parameters('publicNetworkAccess')]"
+      }
+    }
+    {{- if .aksCluster }},
+    {
+      "apiVersion": "[variables('insightsApiVersion')]",
+      "name": "[parameters('dataCollectionEndpointName')]",
+      "type": "Microsoft.Insights/dataCollectionEndpoints",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "kind": "Linux",
+      "properties": {}
+    },
+    {
+      "apiVersion": "[variables('insightsApiVersion')]",
+      "name": "[parameters('dataCollectionRuleName')]",
+      "type": "Microsoft.Insights/dataCollectionRules",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "kind": "Linux",
+      "dependsOn": [
+        "[resourceId('Microsoft.Monitor/accounts', parameters('workspaceName'))]",
+        "[resourceId('Microsoft.Insights/dataCollectionEndpoints', parameters('dataCollectionEndpointName'))]"
+      ],
+      "properties": {
+        "dataCollectionEndpointId": "[resourceId('Microsoft.Insights/dataCollectionEndpoints', parameters('dataCollectionEndpointName'))]",
+        "dataSources": {
+          "prometheusForwarder": [
+            {
+              "name": "PrometheusDataSource",
+              "streams": [
+                "Microsoft-PrometheusMetrics"
+              ],
+              "labelIncludeFilter": {}
+            }
+          ]
+        },
+        "destinations": {
+          "monitoringAccounts": [
+            {
+              "accountResourceId": "[resourceId('Microsoft.Monitor/accounts', parameters('workspaceName'))]",
+              "name": "MonitoringAccount"
+            }
+          ]
+        },
+        "dataFlows": [
+          {
+            "streams": [
+              "Microsoft-PrometheusMetrics"
+            ],
+            "destinations": [
+              "MonitoringAccount"
+            ]
+          }
+        ]
+      }
+    }
+    {{- end }}
+  ],
+  "outputs": {
+    "workspaceId": {
+      "type": "string",
+      "value": "[resourceId('Microsoft.Monitor/accounts', parameters('workspaceName'))]"
+    },
+    "queryEndpoint": {
+      "type": "string",
+      "value": "[reference(parameters('workspaceName')).metrics.prometheusQueryEndpoint]"
+    },
+    "dataCollectionRuleId": {
+      "type": "string",
+      "value": "[reference(parameters('workspaceName')).defaultIngestionSettings.dataCollectionRuleResourceId]"
+    },
+    "dataCollectionRuleImmutableId": {
+      "type": "string",
+      "value": "[reference(reference(parameters('workspaceName')).defaultIngestionSettings.dataCollectionRuleResourceId, variables('insightsApiVersion')).immutableId]"
+    },
+    {{- if .aksCluster }}
+    "clusterDataCollectionRuleId": {
+      "type": "string",
+      "value": "[resourceId('Microsoft.Insights/dataCollectionRules', parameters('dataCollectionRuleName'))]"
+    },
+    "clusterDataCollectionEndpointId": {
+      "type": "string",
+      "value": "[resourceId('Microsoft.Insights/dataCollectionEndpoints', parameters('dataCollectionEndpointName'))]"
+    },
+    {{- end }}
+    "metricsIngestionEndpoint": {
+      "type": "string",
+      "value": "[reference(reference(parameters('workspaceName')).defaultIngestionSettings.dataCollectionEndpointResourceId, variables('insightsApiVersion')).metricsIngestion.endpoint]"
+    }
+  }
+}
+`)
@@ -0,0 +1,157 @@
+package monitorworkspace
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const defaultRole = "Monitoring Data Reader"
+
+// remoteWritePathPattern is the path, beneath a metrics ingestion endpoint, to
+// which Prometheus metrics are remote-written through the data collection
+// rule with the given immutable ID
+const remoteWritePathPattern = "/dataCollectionRules/%s/streams/" +
+	"Microsoft-PrometheusMetrics/api/v1/write?api-version=2023-04-24"
+
+type role struct {
+	roleDefinitionID string
+	// publishesMetrics indicates whether the role is assigned at the scope of
+	// the workspace's data collection rule, rather than the workspace itself,
+	// because it grants permission to write metrics rather than read them
+	publishesMetrics bool
+}
+
+// roles maps the names of the built-in roles that a binding may assign to
+// their definitions
+var roles = map[string]role{
+	"Monitoring Data Reader": {
+		roleDefinitionID: "b0d8363b-8ddd-447d-831f-62ca05bff136",
+	},
+	"Monitoring Metrics Publisher": {
+		roleDefinitionID: "3913510d-42f4-4e42-8a64-420c390055eb",
+		publishesMetrics: true,
+	},
+}
+
+var objectIDRegex = regexp.MustCompile(
+	`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-` +
+		`[0-9a-fA-F]{12}$`,
+)
+
+func (s *serviceManager) ValidateBindingParameters(
+	bindingParameters service.BindingParameters,
+) error {
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return errors.New(
+			"error casting bindingParameters as " +
+				"*monitorworkspace.BindingParameters",
+		)
+	}
+	if !objectIDRegex.MatchString(bp.PrincipalID) {
+		return service.NewValidationError(
+			"principalId",
+			fmt.Sprintf(`invalid principalId: "%s"`, bp.PrincipalID),
+		)
+	}
+	if _, ok := roles[bp.Role]; bp.Role != "" && !ok {
+		return service.NewValidationError(
+			"role",
+			fmt.Sprintf(
+				`invalid role: "%s"; allowed values are: %s`,
+				bp.Role,
+				strings.Join(getRoleNames(), ", "),
+			),
+		)
+	}
+	return nil
+}
+
+// Bind assigns the requested role to the principal named in the binding
+// parameters, scoped to the workspace or to its data collection rule alone
+func (s *serviceManager) Bind(
+	instance service.Instance,
+	bindingParameters service.BindingParameters,
+) (service.BindingDetails, error) {
+	dt, ok := instance.Details.(*monitorWorkspaceInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *monitorWorkspaceInstanceDetails",
+		)
+	}
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting bindingParameters as " +
+				"*monitorworkspace.BindingParameters",
+		)
+	}
+	bd := &monitorWorkspaceBindingDetails{
+		PrincipalID:        bp.PrincipalID,
+		Role:               bp.Role,
+		Scope:              dt.WorkspaceID,
+		RoleAssignmentName: uuid.NewV4().String(),
+	}
+	if bd.Role == "" {
+		bd.Role = defaultRole
+	}
+	if roles[bd.Role].publishesMetrics {
+		bd.Scope = dt.DataCollectionRuleID
+	}
+	if err := s.workspaceManager.CreateRoleAssignment(
+		bd.Scope,
+		bd.RoleAssignmentName,
+		roles[bd.Role].roleDefinitionID,
+		bd.PrincipalID,
+	); err != nil {
+		return nil, err
+	}
+	return bd, nil
+}
+
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	binding service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*monitorWorkspaceInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *monitorWorkspaceInstanceDetails",
+		)
+	}
+	bd, ok := binding.Details.(*monitorWorkspaceBindingDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting binding.Details as *monitorWorkspaceBindingDetails",
+		)
+	}
+	return &Credentials{
+		QueryEndpoint:            dt.QueryEndpoint,
+		MetricsIngestionEndpoint: dt.MetricsIngestionEndpoint,
+		RemoteWriteURL: strings.TrimSuffix(dt.MetricsIngestionEndpoint, "/") +
+			fmt.Sprintf(remoteWritePathPattern, dt.DataCollectionRuleImmutableID),
+		Scope:       bd.Scope,
+		PrincipalID: bd.PrincipalID,
+		Role:        bd.Role,
+		RoleAssignmentID: fmt.Sprintf(
+			"%s/providers/Microsoft.Authorization/roleAssignments/%s",
+			bd.Scope,
+			bd.RoleAssignmentName,
+		),
+	}, nil
+}
+
+func getRoleNames() []string {
+	names := make([]string, 0, len(roles))
+	for name := range roles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
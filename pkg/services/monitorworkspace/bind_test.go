@@ -0,0 +1,56 @@
+package monitorworkspace
+
+import (
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateBindingParameters(t *testing.T) {
+	m := &module{}
+	bp := &BindingParameters{}
+	err := m.serviceManager.ValidateBindingParameters(bp)
+	assert.NotNil(t, err)
+	bp.PrincipalID = "2f8c3a4e-6b1d-4e0a-9c7f-5d3b2a1e0f96"
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.Nil(t, err)
+	bp.Role = "Monitoring Contributor"
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.NotNil(t, err)
+	bp.Role = "Monitoring Metrics Publisher"
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.Nil(t, err)
+}
+
+func TestGetCredentialsIncludesRemoteWriteURL(t *testing.T) {
+	m := &module{}
+	credentials, err := m.serviceManager.GetCredentials(
+		service.Instance{
+			Details: &monitorWorkspaceInstanceDetails{
+				MetricsIngestionEndpoint: "https://amw.eastus-1.metrics.ingest." +
+					"monitor.azure.com/",
+				DataCollectionRuleImmutableID: "dcr-0123456789abcdef",
+			},
+		},
+		service.Binding{
+			Details: &monitorWorkspaceBindingDetails{
+				Scope:              "/dcr",
+				RoleAssignmentName: "assignment",
+			},
+		},
+	)
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		"https://amw.eastus-1.metrics.ingest.monitor.azure.com"+
+			"/dataCollectionRules/dcr-0123456789abcdef/streams/"+
+			"Microsoft-PrometheusMetrics/api/v1/write?api-version=2023-04-24",
+		credentials.(*Credentials).RemoteWriteURL,
+	)
+	assert.Equal(
+		t,
+		"/dcr/providers/Microsoft.Authorization/roleAssignments/assignment",
+		credentials.(*Credentials).RoleAssignmentID,
+	)
+}
@@ -0,0 +1,61 @@
+package monitorworkspace
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:          "5c9e2f71-8a4d-4b36-a1e5-3f7b0d9c6a28",
+				Name:        "azure-monitor-workspace",
+				Description: "Azure Monitor Workspace (Experimental)",
+				Bindable:    true,
+				Tags: []string{
+					"Azure",
+					"Monitor",
+					"Prometheus",
+					"Metrics",
+				},
+				// Azure Monitor workspaces are only offered in some regions
+				Locations: []string{
+					"australiaeast",
+					"australiasoutheast",
+					"brazilsouth",
+					"canadacentral",
+					"centralindia",
+					"centralus",
+					"eastasia",
+					"eastus",
+					"eastus2",
+					"francecentral",
+					"germanywestcentral",
+					"japaneast",
+					"koreacentral",
+					"northcentralus",
+					"northeurope",
+					"norwayeast",
+					"southafricanorth",
+					"southcentralus",
+					"southeastasia",
+					"swedencentral",
+					"switzerlandnorth",
+					"uaenorth",
+					"uksouth",
+					"westcentralus",
+					"westeurope",
+					"westus",
+					"westus2",
+					"westus3",
+				},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:   "e83b6d0a-2f19-4c57-9b4e-7a1d5c8f3e60",
+				Name: "standard",
+				Description: "Pay-as-you-go, billed per metric sample ingested " +
+					"and per sample processed by queries",
+				Free: false,
+			}),
+		),
+	}), nil
+}
@@ -0,0 +1,133 @@
+package monitorworkspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) GetDeprovisioner(
+	service.Plan,
+) (service.Deprovisioner, error) {
+	return service.NewDeprovisioner(
+		service.NewDeprovisioningStep("unlinkCluster", s.unlinkCluster),
+		service.NewDeprovisioningStep("deleteARMDeployment", s.deleteARMDeployment),
+		service.NewDeprovisioningStep(
+			"deleteClusterDataCollectionRule",
+			s.deleteClusterDataCollectionRule,
+		),
+		service.NewDeprovisioningStep(
+			"deleteClusterDataCollectionEndpoint",
+			s.deleteClusterDataCollectionEndpoint,
+		),
+		service.NewDeprovisioningStep("deleteWorkspace", s.deleteWorkspace),
+	)
+}
+
+func (s *serviceManager) unlinkCluster(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*monitorWorkspaceInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *monitorWorkspaceInstanceDetails",
+		)
+	}
+	if dt.AKSClusterID == "" {
+		return dt, nil
+	}
+	if err := s.workspaceManager.UnlinkCluster(
+		dt.AKSClusterID,
+		dt.ClusterAssociationName,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deleteARMDeployment(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*monitorWorkspaceInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *monitorWorkspaceInstanceDetails",
+		)
+	}
+	if err := s.armDeployer.Delete(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, fmt.Errorf("error deleting ARM deployment: %s", err)
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deleteClusterDataCollectionRule(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*monitorWorkspaceInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *monitorWorkspaceInstanceDetails",
+		)
+	}
+	if dt.ClusterDataCollectionRuleName == "" {
+		return dt, nil
+	}
+	if err := s.workspaceManager.DeleteDataCollectionRule(
+		dt.ClusterDataCollectionRuleName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deleteClusterDataCollectionEndpoint(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*monitorWorkspaceInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *monitorWorkspaceInstanceDetails",
+		)
+	}
+	if dt.ClusterDataCollectionEndpointName == "" {
+		return dt, nil
+	}
+	if err := s.workspaceManager.DeleteDataCollectionEndpoint(
+		dt.ClusterDataCollectionEndpointName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+// deleteWorkspace deletes the workspace. Azure deletes the data collection
+// rule and endpoint it created along with the workspace itself.
+func (s *serviceManager) deleteWorkspace(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*monitorWorkspaceInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *monitorWorkspaceInstanceDetails",
+		)
+	}
+	if err := s.workspaceManager.DeleteWorkspace(
+		dt.WorkspaceName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
@@ -0,0 +1,47 @@
+package monitorworkspace
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/monitorworkspace"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer      arm.Deployer
+	workspaceManager monitorworkspace.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of provisioning Azure Monitor workspaces, which
+// store the Prometheus metrics collected by Azure Managed Prometheus
+func New(
+	armDeployer arm.Deployer,
+	workspaceManager monitorworkspace.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer:      armDeployer,
+			workspaceManager: workspaceManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "monitorworkspace"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{
+		"Microsoft.Monitor",
+		"Microsoft.Insights",
+		"Microsoft.Authorization",
+	}
+}
@@ -0,0 +1,231 @@
+package monitorworkspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	publicNetworkAccessEnabled  = "Enabled"
+	publicNetworkAccessDisabled = "Disabled"
+)
+
+var aksClusterIDRegex = regexp.MustCompile(
+	`(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+/providers/` +
+		`Microsoft\.ContainerService/managedClusters/[^/]+$`,
+)
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*monitorworkspace.ProvisioningParameters",
+		)
+	}
+	switch getPublicNetworkAccess(pp) {
+	case publicNetworkAccessEnabled, publicNetworkAccessDisabled:
+	default:
+		return service.NewValidationError(
+			"publicNetworkAccess",
+			fmt.Sprintf(
+				`invalid publicNetworkAccess: "%s"; allowed values are: %s, %s`,
+				pp.PublicNetworkAccess,
+				publicNetworkAccessEnabled,
+				publicNetworkAccessDisabled,
+			),
+		)
+	}
+	if pp.AKSClusterResourceID != "" &&
+		!aksClusterIDRegex.MatchString(pp.AKSClusterResourceID) {
+		return service.NewValidationError(
+			"aksClusterResourceId",
+			fmt.Sprintf(
+				`invalid AKS cluster resource id: "%s"`,
+				pp.AKSClusterResourceID,
+			),
+		)
+	}
+	return nil
+}
+
+func (s *serviceManager) ApplyProvisioningParametersDefaults(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*monitorworkspace.ProvisioningParameters",
+		)
+	}
+	pp.PublicNetworkAccess = getPublicNetworkAccess(pp)
+	return nil
+}
+
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep("deployARMTemplate", s.deployARMTemplate),
+		service.NewProvisioningStep("linkCluster", s.linkCluster),
+	)
+}
+
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*monitorWorkspaceInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *monitorWorkspaceInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*monitorworkspace.ProvisioningParameters",
+		)
+	}
+	dt.ARMDeploymentName = uuid.NewV4().String()
+	dt.WorkspaceName = "amw-" + uuid.NewV4().String()
+	if pp.AKSClusterResourceID != "" {
+		dt.AKSClusterID = pp.AKSClusterResourceID
+		dt.ClusterDataCollectionEndpointName = "dce-" + uuid.NewV4().String()
+		dt.ClusterDataCollectionRuleName = "dcr-" + uuid.NewV4().String()
+		dt.ClusterAssociationName = "dcra-" + uuid.NewV4().String()
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deployARMTemplate(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*monitorWorkspaceInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *monitorWorkspaceInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*monitorworkspace.ProvisioningParameters",
+		)
+	}
+	goParams, armParams := buildARMTemplateParameters(pp, dt)
+	outputs, err := s.armDeployer.Deploy(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateBytes,
+		goParams,
+		armParams,
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+	for output, field := range map[string]*string{
+		"workspaceId":                   &dt.WorkspaceID,
+		"queryEndpoint":                 &dt.QueryEndpoint,
+		"metricsIngestionEndpoint":      &dt.MetricsIngestionEndpoint,
+		"dataCollectionRuleId":          &dt.DataCollectionRuleID,
+		"dataCollectionRuleImmutableId": &dt.DataCollectionRuleImmutableID,
+	} {
+		value, ok := outputs[output].(string)
+		if !ok {
+			return nil, fmt.Errorf(
+				`error retrieving "%s" from deployment`,
+				output,
+			)
+		}
+		*field = value
+	}
+	if dt.AKSClusterID == "" {
+		return dt, nil
+	}
+	clusterDataCollectionRuleID, ok :=
+		outputs["clusterDataCollectionRuleId"].(string)
+	if !ok {
+		return nil, errors.New(
+			"error retrieving cluster data collection rule id from deployment",
+		)
+	}
+	dt.ClusterDataCollectionRuleID = clusterDataCollectionRuleID
+	clusterDataCollectionEndpointID, ok :=
+		outputs["clusterDataCollectionEndpointId"].(string)
+	if !ok {
+		return nil, errors.New(
+			"error retrieving cluster data collection endpoint id from deployment",
+		)
+	}
+	dt.ClusterDataCollectionEndpointID = clusterDataCollectionEndpointID
+	return dt, nil
+}
+
+// linkCluster associates the AKS cluster named in the provisioning
+// parameters, if any, with the data collection rule deployed for it. The
+// associations are extension resources of the cluster, which may be in
+// another resource group, so they aren't part of the deployment.
+func (s *serviceManager) linkCluster(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*monitorWorkspaceInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *monitorWorkspaceInstanceDetails",
+		)
+	}
+	if dt.AKSClusterID == "" {
+		return dt, nil
+	}
+	if err := s.workspaceManager.LinkCluster(
+		dt.AKSClusterID,
+		dt.ClusterAssociationName,
+		dt.ClusterDataCollectionRuleID,
+		dt.ClusterDataCollectionEndpointID,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+func buildARMTemplateParameters(
+	pp *ProvisioningParameters,
+	dt *monitorWorkspaceInstanceDetails,
+) (map[string]interface{}, map[string]interface{}) {
+	goParams := map[string]interface{}{
+		"aksCluster": dt.AKSClusterID != "",
+	}
+	armParams := map[string]interface{}{
+		"workspaceName":       dt.WorkspaceName,
+		"publicNetworkAccess": getPublicNetworkAccess(pp),
+	}
+	if dt.AKSClusterID != "" {
+		armParams["dataCollectionEndpointName"] =
+			dt.ClusterDataCollectionEndpointName
+		armParams["dataCollectionRuleName"] = dt.ClusterDataCollectionRuleName
+	}
+	return goParams, armParams
+}
+
+func getPublicNetworkAccess(pp *ProvisioningParameters) string {
+	if pp.PublicNetworkAccess == "" {
+		return publicNetworkAccessEnabled
+	}
+	return pp.PublicNetworkAccess
+}
@@ -0,0 +1,68 @@
+package monitorworkspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testAKSClusterID = "/subscriptions/sub/resourceGroups/rg/providers/" +
+	"Microsoft.ContainerService/managedClusters/cluster"
+
+func TestValidateProvisioningParametersWithDefaults(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidPublicNetworkAccess(
+	t *testing.T,
+) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		PublicNetworkAccess: "Sometimes",
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.PublicNetworkAccess = publicNetworkAccessDisabled
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithAKSCluster(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		AKSClusterResourceID: "/subscriptions/sub/resourceGroups/rg/providers/" +
+			"Microsoft.Compute/virtualMachines/vm",
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.AKSClusterResourceID = testAKSClusterID
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestBuildARMTemplateParametersWithAKSCluster(t *testing.T) {
+	dt := &monitorWorkspaceInstanceDetails{
+		WorkspaceName: "amw",
+	}
+	goParams, armParams := buildARMTemplateParameters(
+		&ProvisioningParameters{},
+		dt,
+	)
+	assert.Equal(t, false, goParams["aksCluster"])
+	assert.Equal(t, publicNetworkAccessEnabled, armParams["publicNetworkAccess"])
+	assert.NotContains(t, armParams, "dataCollectionRuleName")
+
+	dt.AKSClusterID = testAKSClusterID
+	dt.ClusterDataCollectionEndpointName = "dce"
+	dt.ClusterDataCollectionRuleName = "dcr"
+	goParams, armParams = buildARMTemplateParameters(
+		&ProvisioningParameters{AKSClusterResourceID: testAKSClusterID},
+		dt,
+	)
+	assert.Equal(t, true, goParams["aksCluster"])
+	assert.Equal(t, "dce", armParams["dataCollectionEndpointName"])
+	assert.Equal(t, "dcr", armParams["dataCollectionRuleName"])
+}
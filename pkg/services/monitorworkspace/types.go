@@ -0,0 +1,97 @@
+package monitorworkspace
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// ProvisioningParameters encapsulates Azure Monitor workspace-specific
+// provisioning options
+type ProvisioningParameters struct {
+	// PublicNetworkAccess is either "Enabled" or "Disabled"
+	PublicNetworkAccess string `json:"publicNetworkAccess"`
+	// AKSClusterResourceID is the resource ID of an AKS cluster whose
+	// Prometheus metrics are to be collected into the workspace. The cluster
+	// must be in the same region as the workspace.
+	AKSClusterResourceID string `json:"aksClusterResourceId"`
+}
+
+type monitorWorkspaceInstanceDetails struct {
+	ARMDeploymentName        string `json:"armDeployment"`
+	WorkspaceName            string `json:"workspaceName"`
+	WorkspaceID              string `json:"workspaceId"`
+	QueryEndpoint            string `json:"queryEndpoint"`
+	MetricsIngestionEndpoint string `json:"metricsIngestionEndpoint"`
+	// DataCollectionRuleID and DataCollectionRuleImmutableID identify the data
+	// collection rule Azure creates along with the workspace, through which
+	// metrics are remote-written to it
+	DataCollectionRuleID          string `json:"dataCollectionRuleId"`
+	DataCollectionRuleImmutableID string `json:"dataCollectionRuleImmutableId"`
+	// The cluster fields are empty unless the workspace was linked to an AKS
+	// cluster
+	AKSClusterID                      string `json:"aksClusterId"`
+	ClusterDataCollectionEndpointName string `json:"clusterDataCollectionEndpointName"` // nolint: lll
+	ClusterDataCollectionEndpointID   string `json:"clusterDataCollectionEndpointId"`   // nolint: lll
+	ClusterDataCollectionRuleName     string `json:"clusterDataCollectionRuleName"`     // nolint: lll
+	ClusterDataCollectionRuleID       string `json:"clusterDataCollectionRuleId"`       // nolint: lll
+	ClusterAssociationName            string `json:"clusterAssociationName"`
+}
+
+// UpdatingParameters encapsulates Azure Monitor workspace-specific updating
+// options
+type UpdatingParameters struct {
+}
+
+// BindingParameters encapsulates Azure Monitor workspace-specific binding
+// options
+type BindingParameters struct {
+	PrincipalID string `json:"principalId"`
+	Role        string `json:"role"`
+}
+
+type monitorWorkspaceBindingDetails struct {
+	PrincipalID        string `json:"principalId"`
+	Role               string `json:"role"`
+	Scope              string `json:"scope"`
+	RoleAssignmentName string `json:"roleAssignmentName"`
+}
+
+// Credentials encapsulates Azure Monitor workspace-specific connection details
+type Credentials struct {
+	// QueryEndpoint is the base URL of the workspace's Prometheus query API
+	QueryEndpoint            string `json:"queryEndpoint"`
+	MetricsIngestionEndpoint string `json:"metricsIngestionEndpoint"`
+	// RemoteWriteURL is the URL, beneath the metrics ingestion endpoint, that
+	// Prometheus remote-write clients send metrics to
+	RemoteWriteURL string `json:"remoteWriteUrl"`
+	// Scope is the resource ID of the resource at which the principal's role
+	// was assigned: the workspace for roles that query metrics, or its data
+	// collection rule for roles that publish them
+	Scope            string `json:"scope"`
+	PrincipalID      string `json:"principalId"`
+	Role             string `json:"role"`
+	RoleAssignmentID string `json:"roleAssignmentId"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &monitorWorkspaceInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &monitorWorkspaceBindingDetails{}
+}
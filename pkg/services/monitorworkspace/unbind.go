@@ -0,0 +1,23 @@
+package monitorworkspace
+
+import (
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) Unbind(
+	_ service.Instance,
+	bindingDetails service.BindingDetails,
+) error {
+	bd, ok := bindingDetails.(*monitorWorkspaceBindingDetails)
+	if !ok {
+		return errors.New(
+			"error casting bindingDetails as *monitorWorkspaceBindingDetails",
+		)
+	}
+	return s.workspaceManager.DeleteRoleAssignment(
+		bd.Scope,
+		bd.RoleAssignmentName,
+	)
+}
@@ -2,6 +2,7 @@ package mysqldb
 
 import (
 	"fmt"
+	"net/url"
 
 	"github.com/Azure/open-service-broker-azure/pkg/generate"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
@@ -86,11 +87,26 @@ func (s *serviceManager) GetCredentials(
 			"error casting binding.Details as *mysqlBindingDetails",
 		)
 	}
+	username := fmt.Sprintf("%s@%s", bd.LoginName, dt.ServerName)
+	uri := &url.URL{
+		Scheme: "mysql",
+		User:   url.UserPassword(username, bd.Password),
+		Host:   fmt.Sprintf("%s:%d", dt.FullyQualifiedDomainName, 3306),
+		Path:   "/" + dt.DatabaseName,
+	}
+	// Connection strings handed out for instances that enforce SSL should
+	// fail fast rather than attempt an unencrypted connection the server will
+	// refuse anyway
+	if dt.EnforceSSL {
+		uri.RawQuery = "ssl-mode=REQUIRED"
+	}
 	return &Credentials{
-		Host:     dt.FullyQualifiedDomainName,
-		Port:     3306,
-		Database: dt.DatabaseName,
-		Username: fmt.Sprintf("%s@%s", bd.LoginName, dt.ServerName),
-		Password: bd.Password,
+		Host:        dt.FullyQualifiedDomainName,
+		Port:        3306,
+		Database:    dt.DatabaseName,
+		Username:    username,
+		Password:    bd.Password,
+		SSLRequired: dt.EnforceSSL,
+		URI:         uri.String(),
 	}, nil
 }
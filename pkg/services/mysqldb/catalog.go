@@ -10,6 +10,7 @@ func (m *module) GetCatalog() (service.Catalog, error) {
 				Name:        "azure-mysqldb",
 				Description: "Azure Database for MySQL (Experimental)",
 				Bindable:    true,
+				Cloneable:   true,
 				Tags:        []string{"Azure", "MySQL", "Database"},
 			},
 			m.serviceManager,
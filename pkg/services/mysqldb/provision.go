@@ -75,6 +75,23 @@ func (s *serviceManager) ValidateProvisioningParameters(
 	return nil
 }
 
+func (s *serviceManager) ApplyProvisioningParametersDefaults(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as *mysql.ProvisioningParameters",
+		)
+	}
+	// SSL is enforced unless it's explicitly disabled
+	pp.SSLEnforcement = strings.ToLower(pp.SSLEnforcement)
+	if pp.SSLEnforcement == "" {
+		pp.SSLEnforcement = "enabled"
+	}
+	return nil
+}
+
 func (s *serviceManager) GetProvisioner(
 	service.Plan,
 ) (service.Provisioner, error) {
@@ -178,7 +195,7 @@ func (s *serviceManager) deployARMTemplate(
 		instance.Tags,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("error deploying ARM template: %s", err)
+		return nil, service.WrapError(err, "error deploying ARM template")
 	}
 
 	fullyQualifiedDomainName, ok := outputs["fullyQualifiedDomainName"].(string)
@@ -73,3 +73,54 @@ func TestValidateIncompleteIP(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, v.Field, "firewallStartIPAddress")
 }
+
+func TestApplyProvisioningParametersDefaults(t *testing.T) {
+	sm := &serviceManager{}
+	pp := &ProvisioningParameters{}
+	error := sm.ApplyProvisioningParametersDefaults(pp)
+	assert.Nil(t, error)
+	assert.Equal(t, "enabled", pp.SSLEnforcement)
+	pp = &ProvisioningParameters{
+		SSLEnforcement: "Disabled",
+	}
+	error = sm.ApplyProvisioningParametersDefaults(pp)
+	assert.Nil(t, error)
+	assert.Equal(t, "disabled", pp.SSLEnforcement)
+}
+
+func TestGetCredentialsHonorsSSLEnforcement(t *testing.T) {
+	sm := &serviceManager{}
+	dt := &mysqlInstanceDetails{
+		ServerName:                 "server",
+		AdministratorLoginPassword: "admin-password",
+		DatabaseName:               "db",
+		FullyQualifiedDomainName:   "server.mysql.database.azure.com",
+		EnforceSSL:                 true,
+	}
+	binding := service.Binding{
+		Details: &mysqlBindingDetails{
+			LoginName: "user",
+			Password:  "password",
+		},
+	}
+	creds, err := sm.GetCredentials(service.Instance{Details: dt}, binding)
+	assert.Nil(t, err)
+	c, ok := creds.(*Credentials)
+	assert.True(t, ok)
+	assert.True(t, c.SSLRequired)
+	assert.Equal(
+		t,
+		"mysql://user%40server:password@"+
+			"server.mysql.database.azure.com:3306/db?ssl-mode=REQUIRED",
+		c.URI,
+	)
+	assert.NotContains(t, c.URI, dt.AdministratorLoginPassword)
+
+	dt.EnforceSSL = false
+	creds, err = sm.GetCredentials(service.Instance{Details: dt}, binding)
+	assert.Nil(t, err)
+	c, ok = creds.(*Credentials)
+	assert.True(t, ok)
+	assert.False(t, c.SSLRequired)
+	assert.NotContains(t, c.URI, "ssl-mode")
+}
@@ -12,7 +12,7 @@ type ProvisioningParameters struct {
 type mysqlInstanceDetails struct {
 	ARMDeploymentName          string `json:"armDeployment"`
 	ServerName                 string `json:"server"`
-	AdministratorLoginPassword string `json:"administratorLoginPassword"`
+	AdministratorLoginPassword string `json:"administratorLoginPassword" secret:"true"` // nolint: lll
 	DatabaseName               string `json:"database"`
 	FullyQualifiedDomainName   string `json:"fullyQualifiedDomainName"`
 	EnforceSSL                 bool   `json:"enforceSSL"`
@@ -28,7 +28,7 @@ type BindingParameters struct {
 
 type mysqlBindingDetails struct {
 	LoginName string `json:"loginName"`
-	Password  string `json:"password"`
+	Password  string `json:"password" secret:"true"`
 }
 
 // Credentials encapsulates MySQL-specific coonection details and credentials.
@@ -38,6 +38,10 @@ type Credentials struct {
 	Database string `json:"database"`
 	Username string `json:"username"`
 	Password string `json:"password"`
+	// SSLRequired indicates whether the server rejects connections that are
+	// not secured by SSL
+	SSLRequired bool   `json:"sslRequired"`
+	URI         string `json:"uri"`
 }
 
 func (
@@ -0,0 +1,53 @@
+package mysqldb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// ValidateConnectivity connects to the new database using the administrator
+// credentials generated for it. If the server's firewall admits only the range
+// requested at provisioning time, the broker may well fall outside that range,
+// so failing to reach the server at all is not treated as an error in that
+// case.
+func (s *serviceManager) ValidateConnectivity(
+	ctx context.Context,
+	instance service.Instance,
+) (bool, error) {
+	dt, ok := instance.Details.(*mysqlInstanceDetails)
+	if !ok {
+		return false, errors.New(
+			"error casting instance.Details as *mysqlInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return false, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*mysql.ProvisioningParameters",
+		)
+	}
+	db, err := getDBConnection(dt)
+	if err != nil {
+		return false, err
+	}
+	defer db.Close() // nolint: errcheck
+	if _, err := db.ExecContext(ctx, "select 1"); err != nil {
+		if _, ok := err.(net.Error); ok && pp.FirewallIPStart != "" {
+			return false, nil
+		}
+		return false, service.NewCategorizedError(
+			service.ErrorCategoryTransient,
+			fmt.Errorf(
+				`error querying database "%s": %s`,
+				dt.DatabaseName,
+				err,
+			),
+		)
+	}
+	return true, nil
+}
@@ -0,0 +1,179 @@
+package netappfiles
+
+// nolint: lll
+var armTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "accountName": {
+      "type": "string"
+    },
+    "capacityPoolName": {
+      "type": "string"
+    },
+    "volumeName": {
+      "type": "string"
+    },
+    "filePath": {
+      "type": "string",
+      "metadata": {
+        "description": "The path the volume is mounted by, which is unique within the subscription and region"
+      }
+    },
+    "serviceLevel": {
+      "type": "string",
+      "allowedValues": [
+        "Standard",
+        "Premium",
+        "Ultra"
+      ]
+    },
+    {{- if .createPool }}
+    "poolSizeBytes": {
+      "type": "int"
+    },
+    {{- end }}
+    "volumeSizeBytes": {
+      "type": "int"
+    },
+    "subnetId": {
+      "type": "string",
+      "metadata": {
+        "description": "Resource ID of a subnet delegated to Microsoft.NetApp/volumes"
+      }
+    },
+    "protocolTypes": {
+      "type": "array"
+    },
+    {{- if .nfs }}
+    "allowedClients": {
+      "type": "string"
+    },
+    "nfsv3": {
+      "type": "bool"
+    },
+    "nfsv41": {
+      "type": "bool"
+    },
+    {{- end }}
+    {{- if .activeDirectory }}
+    "activeDirectoryDomain": {
+      "type": "string"
+    },
+    "activeDirectoryDns": {
+      "type": "string"
+    },
+    "activeDirectorySmbServerName": {
+      "type": "string"
+    },
+    "activeDirectoryUsername": {
+      "type": "string"
+    },
+    "activeDirectoryPassword": {
+      "type": "securestring"
+    },
+    "activeDirectoryOrganizationalUnit": {
+      "type": "string"
+    },
+    {{- end }}
+    "tags": {
+      "type": "object"
+    }
+  },
+  "variables": {
+    "apiVersion": "2023-07-01"
+  },
+  "resources": [
+    {{- if .createAccount }}
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[parameters('accountName')]",
+      "type": "Microsoft.NetApp/netAppAccounts",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "properties": {
+        {{- if .activeDirectory }}
+        "activeDirectories": [
+          {
+            "domain": "[parameters('activeDirectoryDomain')]",
+            "dns": "[parameters('activeDirectoryDns')]",
+            "smbServerName": "[parameters('activeDirectorySmbServerName')]",
+            "username": "[parameters('activeDirectoryUsername')]",
+            "password": "[parameters('activeDirectoryPassword')]",
+            "organizationalUnit": "[if(empty(parameters('activeDirectoryOrganizationalUnit')), 'CN=Computers', parameters('activeDirectoryOrganizationalUnit'))]"
+          }
+        ]
+        {{- end }}
+      }
+    },
+    {{- end }}
+    {{- if .createPool }}
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[concat(parameters('accountName'), '/', parameters('capacityPoolName'))]",
+      "type": "Microsoft.NetApp/netAppAccounts/capacityPools",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      {{- if .createAccount }}
+      "dependsOn": [
+        "[resourceId('Microsoft.NetApp/netAppAccounts', parameters('accountName'))]"
+      ],
+      {{- end }}
+      "properties": {
+        "serviceLevel": "[parameters('serviceLevel')]",
+        "size": "[parameters('poolSizeBytes')]",
+        "qosType": "Auto"
+      }
+    },
+    {{- end }}
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[concat(parameters('accountName'), '/', parameters('capacityPoolName'), '/', parameters('volumeName'))]",
+      "type": "Microsoft.NetApp/netAppAccounts/capacityPools/volumes",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      {{- if .createPool }}
+      "dependsOn": [
+        "[resourceId('Microsoft.NetApp/netAppAccounts/capacityPools', parameters('accountName'), parameters('capacityPoolName'))]"
+      ],
+      {{- end }}
+      "properties": {
+        "creationToken": "[parameters('filePath')]",
+        "serviceLevel": "[parameters('serviceLevel')]",
+        "usageThreshold": "[parameters('volumeSizeBytes')]",
+        "subnetId": "[parameters('subnetId')]",
+        {{- if .nfs }}
+        "exportPolicy": {
+          "rules": [
+            {
+              "ruleIndex": 1,
+              "allowedClients": "[parameters('allowedClients')]",
+              "unixReadOnly": false,
+              "unixReadWrite": true,
+              "cifs": false,
+              "nfsv3": "[parameters('nfsv3')]",
+              "nfsv41": "[parameters('nfsv41')]"
+            }
+          ]
+        },
+        {{- end }}
+        "protocolTypes": "[parameters('protocolTypes')]"
+      }
+    }
+  ],
+  "outputs": {
+    "volumeId": {
+      "type": "string",
+      "value": "[resourceId('Microsoft.NetApp/netAppAccounts/capacityPools/volumes', parameters('accountName'), parameters('capacityPoolName'), parameters('volumeName'))]"
+    },
+    "mountTargets": {
+      "type": "array",
+      "value": "[reference(resourceId('Microsoft.NetApp/netAppAccounts/capacityPools/volumes', parameters('accountName'), parameters('capacityPoolName'), parameters('volumeName'))).mountTargets]"
+    }
+  }
+}
+`)
@@ -0,0 +1,77 @@
+package netappfiles
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) ValidateBindingParameters(
+	service.BindingParameters,
+) error {
+	// There are no parameters for binding to a NetApp Files volume, so there is
+	// nothing to validate
+	return nil
+}
+
+func (s *serviceManager) Bind(
+	service.Instance,
+	service.BindingParameters,
+) (service.BindingDetails, error) {
+	// NFS clients are admitted by the volume's export policy and SMB clients by
+	// Active Directory, so binding only needs to hand out mount instructions
+	return &netAppFilesBindingDetails{}, nil
+}
+
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	_ service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*netAppFilesInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *netAppFilesInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*netappfiles.ProvisioningParameters",
+		)
+	}
+	if len(dt.MountTargets) == 0 {
+		return nil, errors.New("volume has no mount targets")
+	}
+	protocol := getProtocol(pp)
+	credentials := &Credentials{
+		Protocol:     protocol,
+		FilePath:     dt.FilePath,
+		MountTargets: dt.MountTargets,
+	}
+	mountTarget := dt.MountTargets[0]
+	switch protocol {
+	case protocolSMB:
+		credentials.UNCPath = fmt.Sprintf(
+			`\\%s\%s`,
+			mountTarget.SMBServerFQDN,
+			dt.FilePath,
+		)
+	default:
+		version := "3"
+		if protocol == protocolNFSv41 {
+			version = "4.1"
+		}
+		credentials.MountCommand = fmt.Sprintf(
+			"sudo mkdir -p /mnt/%s && sudo mount -t nfs -o "+
+				"rw,hard,rsize=262144,wsize=262144,vers=%s,tcp %s:/%s /mnt/%s",
+			dt.FilePath,
+			version,
+			mountTarget.IPAddress,
+			dt.FilePath,
+			dt.FilePath,
+		)
+	}
+	return credentials, nil
+}
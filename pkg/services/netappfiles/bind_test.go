@@ -0,0 +1,63 @@
+package netappfiles
+
+import (
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCredentialsForNFSVolume(t *testing.T) {
+	m := &module{}
+	credentials, err := m.serviceManager.GetCredentials(
+		service.Instance{
+			ProvisioningParameters: &ProvisioningParameters{
+				Protocol: "NFSv4.1",
+			},
+			Details: &netAppFilesInstanceDetails{
+				FilePath: "vol0123",
+				MountTargets: []MountTarget{
+					{IPAddress: "10.0.1.4"},
+				},
+			},
+		},
+		service.Binding{},
+	)
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		"sudo mkdir -p /mnt/vol0123 && sudo mount -t nfs -o "+
+			"rw,hard,rsize=262144,wsize=262144,vers=4.1,tcp "+
+			"10.0.1.4:/vol0123 /mnt/vol0123",
+		credentials.(*Credentials).MountCommand,
+	)
+	assert.Empty(t, credentials.(*Credentials).UNCPath)
+}
+
+func TestGetCredentialsForSMBVolume(t *testing.T) {
+	m := &module{}
+	credentials, err := m.serviceManager.GetCredentials(
+		service.Instance{
+			ProvisioningParameters: &ProvisioningParameters{
+				Protocol: "SMB",
+			},
+			Details: &netAppFilesInstanceDetails{
+				FilePath: "vol0123",
+				MountTargets: []MountTarget{
+					{
+						IPAddress:     "10.0.1.4",
+						SMBServerFQDN: "anf-1a2b.contoso.com",
+					},
+				},
+			},
+		},
+		service.Binding{},
+	)
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		`\\anf-1a2b.contoso.com\vol0123`,
+		credentials.(*Credentials).UNCPath,
+	)
+	assert.Empty(t, credentials.(*Credentials).MountCommand)
+}
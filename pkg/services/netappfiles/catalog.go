@@ -0,0 +1,31 @@
+package netappfiles
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:          "4b99f831-3276-403a-9618-1aef9cab364b",
+				Name:        "azure-netapp-files",
+				Description: "Azure NetApp Files (Experimental)",
+				Bindable:    true,
+				Tags: []string{
+					"Azure",
+					"NetApp Files",
+					"NFS",
+					"SMB",
+					"File System",
+				},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:   "79e96bae-4bad-4f4e-b877-f27e3340c86a",
+				Name: "volume",
+				Description: "An NFS or SMB volume in a delegated subnet, with a " +
+					"service level and capacity of your choosing",
+				Free: false,
+			}),
+		),
+	}), nil
+}
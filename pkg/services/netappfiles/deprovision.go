@@ -0,0 +1,180 @@
+package netappfiles
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) GetDeprovisioner(
+	service.Plan,
+) (service.Deprovisioner, error) {
+	return service.NewDeprovisioner(
+		service.NewDeprovisioningStep("deleteARMDeployment", s.deleteARMDeployment),
+		service.NewDeprovisioningStep("deleteVolume", s.deleteVolume),
+		service.NewDeprovisioningStepWithDependencies(
+			"deleteCapacityPool",
+			s.deleteCapacityPool,
+			"deleteVolume",
+		),
+		service.NewDeprovisioningStepWithDependencies(
+			"deleteAccount",
+			s.deleteAccount,
+			"deleteCapacityPool",
+		),
+	)
+}
+
+func (s *serviceManager) deleteARMDeployment(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*netAppFilesInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *netAppFilesInstanceDetails",
+		)
+	}
+	if err := s.armDeployer.Delete(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, fmt.Errorf("error deleting ARM deployment: %s", err)
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deleteVolume(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*netAppFilesInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *netAppFilesInstanceDetails",
+		)
+	}
+	if err := s.netAppFilesManager.DeleteVolume(
+		dt.AccountName,
+		dt.CapacityPoolName,
+		dt.VolumeName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+// deleteCapacityPool deletes the volume's capacity pool once no volumes are
+// carved out of it anymore. Only pools the broker created are deleted. Volumes
+// are counted afresh each time, so a pool shared by several instances is
+// deleted along with whichever instance's volume was the last in it.
+func (s *serviceManager) deleteCapacityPool(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*netAppFilesInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *netAppFilesInstanceDetails",
+		)
+	}
+	pool, ok, err := s.netAppFilesManager.GetCapacityPool(
+		dt.AccountName,
+		dt.CapacityPoolName,
+		instance.ResourceGroup,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !ok || pool.Tags[arm.HeritageTagName] != arm.HeritageTagValue {
+		return dt, nil
+	}
+	volumeNames, err := s.netAppFilesManager.GetVolumeNames(
+		dt.AccountName,
+		dt.CapacityPoolName,
+		instance.ResourceGroup,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if includesOthers(volumeNames, dt.VolumeName) {
+		return dt, nil
+	}
+	if err := s.netAppFilesManager.DeleteCapacityPool(
+		dt.AccountName,
+		dt.CapacityPoolName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+// deleteAccount deletes the volume's account once it has no capacity pools
+// anymore, counting them the same way deleteCapacityPool counts volumes
+func (s *serviceManager) deleteAccount(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*netAppFilesInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *netAppFilesInstanceDetails",
+		)
+	}
+	account, ok, err := s.netAppFilesManager.GetAccount(
+		dt.AccountName,
+		instance.ResourceGroup,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !ok || account.Tags[arm.HeritageTagName] != arm.HeritageTagValue {
+		return dt, nil
+	}
+	// The volume's own pool is retained while other volumes remain in it
+	_, ok, err = s.netAppFilesManager.GetCapacityPool(
+		dt.AccountName,
+		dt.CapacityPoolName,
+		instance.ResourceGroup,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return dt, nil
+	}
+	capacityPoolNames, err := s.netAppFilesManager.GetCapacityPoolNames(
+		dt.AccountName,
+		instance.ResourceGroup,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if includesOthers(capacityPoolNames, dt.CapacityPoolName) {
+		return dt, nil
+	}
+	if err := s.netAppFilesManager.DeleteAccount(
+		dt.AccountName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+// includesOthers returns a bool indicating whether the given names include
+// any but the given one. A resource this instance just deleted may briefly
+// still be listed.
+func includesOthers(names []string, name string) bool {
+	for _, n := range names {
+		if n != name {
+			return true
+		}
+	}
+	return false
+}
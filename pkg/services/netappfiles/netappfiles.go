@@ -0,0 +1,42 @@
+package netappfiles
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/netappfiles"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer        arm.Deployer
+	netAppFilesManager netappfiles.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of provisioning Azure NetApp Files volumes
+func New(
+	armDeployer arm.Deployer,
+	netAppFilesManager netappfiles.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer:        armDeployer,
+			netAppFilesManager: netAppFilesManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "netappfiles"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{"Microsoft.NetApp"}
+}
@@ -0,0 +1,522 @@
+package netappfiles
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	serviceLevelStandard = "Standard"
+	serviceLevelPremium  = "Premium"
+	serviceLevelUltra    = "Ultra"
+
+	protocolNFSv3  = "NFSv3"
+	protocolNFSv41 = "NFSv4.1"
+	protocolSMB    = "SMB"
+
+	defaultServiceLevel  = serviceLevelPremium
+	defaultProtocol      = protocolNFSv3
+	defaultPoolSizeTiB   = 1
+	defaultVolumeSizeGiB = 100
+
+	minPoolSizeTiB   = 1
+	maxPoolSizeTiB   = 2048
+	minVolumeSizeGiB = 100
+	maxVolumeSizeGiB = 102400
+
+	gibibyte = 1024 * 1024 * 1024
+	tebibyte = 1024 * gibibyte
+)
+
+var serviceLevels = []string{
+	serviceLevelStandard,
+	serviceLevelPremium,
+	serviceLevelUltra,
+}
+
+var protocols = []string{protocolNFSv3, protocolNFSv41, protocolSMB}
+
+// nameRegex matches the names Azure permits of accounts and capacity pools
+var nameRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]{0,63}$`)
+
+// smbServerNameRegex matches the prefixes Azure permits of the names of SMB
+// servers' computer accounts, which leave room for a suffix of Azure's own
+var smbServerNameRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9-]{0,9}$`)
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*netappfiles.ProvisioningParameters",
+		)
+	}
+	if pp.SubnetID == "" {
+		return service.NewValidationError("subnetId", "subnetId is required")
+	}
+	if !az.IsValidSubnetID(pp.SubnetID) {
+		return service.NewValidationError(
+			"subnetId",
+			fmt.Sprintf(`invalid subnetId: "%s"`, pp.SubnetID),
+		)
+	}
+	for field, name := range map[string]string{
+		"account":      pp.AccountName,
+		"capacityPool": pp.CapacityPoolName,
+	} {
+		if name != "" && !nameRegex.MatchString(name) {
+			return service.NewValidationError(
+				field,
+				fmt.Sprintf(
+					`invalid %s: "%s"; names must be 1 to 64 letters, digits, `+
+						"underscores and hyphens, beginning with a letter",
+					field,
+					name,
+				),
+			)
+		}
+	}
+	if pp.CapacityPoolName != "" && pp.AccountName == "" {
+		return service.NewValidationError(
+			"capacityPool",
+			"capacityPool may only be specified along with account",
+		)
+	}
+	if pp.ServiceLevel != "" && !contains(serviceLevels, pp.ServiceLevel) {
+		return service.NewValidationError(
+			"serviceLevel",
+			fmt.Sprintf(
+				`invalid serviceLevel: "%s"; allowed values are %s`,
+				pp.ServiceLevel,
+				strings.Join(serviceLevels, ", "),
+			),
+		)
+	}
+	if pp.PoolSizeTiB != 0 &&
+		(pp.PoolSizeTiB < minPoolSizeTiB || pp.PoolSizeTiB > maxPoolSizeTiB) {
+		return service.NewValidationError(
+			"poolSizeTiB",
+			fmt.Sprintf(
+				"invalid poolSizeTiB: %d; capacity pools may be %d to %d TiB",
+				pp.PoolSizeTiB,
+				minPoolSizeTiB,
+				maxPoolSizeTiB,
+			),
+		)
+	}
+	if pp.VolumeSizeGiB != 0 && (pp.VolumeSizeGiB < minVolumeSizeGiB ||
+		pp.VolumeSizeGiB > maxVolumeSizeGiB) {
+		return service.NewValidationError(
+			"volumeSizeGiB",
+			fmt.Sprintf(
+				"invalid volumeSizeGiB: %d; volumes may be %d to %d GiB",
+				pp.VolumeSizeGiB,
+				minVolumeSizeGiB,
+				maxVolumeSizeGiB,
+			),
+		)
+	}
+	// Whether an existing pool has room for the volume can't be known without
+	// looking the pool up, but a new pool must be at least as large as it
+	if pp.CapacityPoolName == "" {
+		if err := validateVolumeFitsPool(pp); err != nil {
+			return err
+		}
+	}
+	if pp.Protocol != "" && !contains(protocols, pp.Protocol) {
+		return service.NewValidationError(
+			"protocol",
+			fmt.Sprintf(
+				`invalid protocol: "%s"; allowed values are %s`,
+				pp.Protocol,
+				strings.Join(protocols, ", "),
+			),
+		)
+	}
+	if getProtocol(pp) == protocolSMB {
+		if len(pp.AllowedClients) > 0 {
+			return service.NewValidationError(
+				"allowedClients",
+				"allowedClients only applies to NFS volumes",
+			)
+		}
+		if pp.ActiveDirectory == nil && pp.AccountName == "" {
+			return service.NewValidationError(
+				"activeDirectory",
+				"activeDirectory is required of SMB volumes in a new account",
+			)
+		}
+	}
+	for _, client := range pp.AllowedClients {
+		if net.ParseIP(client) == nil {
+			if _, _, err := net.ParseCIDR(client); err != nil {
+				return service.NewValidationError(
+					"allowedClients",
+					fmt.Sprintf(
+						`invalid allowedClients: "%s" is neither an IP address nor a `+
+							"CIDR range",
+						client,
+					),
+				)
+			}
+		}
+	}
+	if pp.ActiveDirectory != nil {
+		return validateActiveDirectory(pp.ActiveDirectory)
+	}
+	return nil
+}
+
+func validateActiveDirectory(ad *ActiveDirectory) error {
+	for field, value := range map[string]string{
+		"domain":        ad.Domain,
+		"dns":           ad.DNS,
+		"smbServerName": ad.SMBServerName,
+		"username":      ad.Username,
+		"password":      ad.Password,
+	} {
+		if value == "" {
+			return service.NewValidationError(
+				"activeDirectory",
+				fmt.Sprintf("activeDirectory.%s is required", field),
+			)
+		}
+	}
+	for _, server := range strings.Split(ad.DNS, ",") {
+		if net.ParseIP(strings.TrimSpace(server)) == nil {
+			return service.NewValidationError(
+				"activeDirectory",
+				fmt.Sprintf(
+					`invalid activeDirectory.dns: "%s" is not an IP address`,
+					server,
+				),
+			)
+		}
+	}
+	if !smbServerNameRegex.MatchString(ad.SMBServerName) {
+		return service.NewValidationError(
+			"activeDirectory",
+			fmt.Sprintf(
+				`invalid activeDirectory.smbServerName: "%s"; names must be 1 to `+
+					"10 letters, digits and hyphens, beginning with a letter",
+				ad.SMBServerName,
+			),
+		)
+	}
+	return nil
+}
+
+func validateVolumeFitsPool(pp *ProvisioningParameters) error {
+	poolSizeTiB := getPoolSizeTiB(pp)
+	if getVolumeSizeGiB(pp) > poolSizeTiB*1024 {
+		return service.NewValidationError(
+			"volumeSizeGiB",
+			fmt.Sprintf(
+				"invalid volumeSizeGiB: %d; the volume doesn't fit in a %d TiB "+
+					"capacity pool",
+				getVolumeSizeGiB(pp),
+				poolSizeTiB,
+			),
+		)
+	}
+	return nil
+}
+
+func (s *serviceManager) ApplyProvisioningParametersDefaults(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*netappfiles.ProvisioningParameters",
+		)
+	}
+	pp.ServiceLevel = getServiceLevel(pp)
+	pp.PoolSizeTiB = getPoolSizeTiB(pp)
+	pp.VolumeSizeGiB = getVolumeSizeGiB(pp)
+	pp.Protocol = getProtocol(pp)
+	pp.AllowedClients = getAllowedClients(pp)
+	return nil
+}
+
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep("deployARMTemplate", s.deployARMTemplate),
+	)
+}
+
+// preProvision checks what can't be checked without looking things up in
+// Azure: that the subnet is delegated to Azure NetApp Files, and that an
+// existing capacity pool named by the instance suits its volume
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*netAppFilesInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *netAppFilesInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*netappfiles.ProvisioningParameters",
+		)
+	}
+	delegated, err := s.netAppFilesManager.IsSubnetDelegated(pp.SubnetID)
+	if err != nil {
+		return nil, err
+	}
+	if !delegated {
+		return nil, service.NewValidationError(
+			"subnetId",
+			fmt.Sprintf(
+				`subnet "%s" doesn't exist or isn't delegated to `+
+					"Microsoft.NetApp/volumes",
+				pp.SubnetID,
+			),
+		)
+	}
+	if pp.CapacityPoolName != "" {
+		pool, ok, err := s.netAppFilesManager.GetCapacityPool(
+			pp.AccountName,
+			pp.CapacityPoolName,
+			instance.ResourceGroup,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			if err := validateVolumeFitsPool(pp); err != nil {
+				return nil, err
+			}
+		} else if pool.Properties.ServiceLevel != getServiceLevel(pp) {
+			return nil, service.NewValidationError(
+				"serviceLevel",
+				fmt.Sprintf(
+					`capacity pool "%s" has the %s service level, not %s`,
+					pp.CapacityPoolName,
+					pool.Properties.ServiceLevel,
+					getServiceLevel(pp),
+				),
+			)
+		}
+	}
+	dt.ARMDeploymentName = uuid.NewV4().String()
+	dt.AccountName = pp.AccountName
+	if dt.AccountName == "" {
+		dt.AccountName = newName("anf")
+	}
+	dt.CapacityPoolName = pp.CapacityPoolName
+	if dt.CapacityPoolName == "" {
+		dt.CapacityPoolName = newName("pool")
+	}
+	dt.VolumeName = newName("vol")
+	// The file path that the volume is mounted by must be unique within the
+	// subscription and region, which the volume's random name is
+	dt.FilePath = dt.VolumeName
+	return dt, nil
+}
+
+func (s *serviceManager) deployARMTemplate(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*netAppFilesInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *netAppFilesInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*netappfiles.ProvisioningParameters",
+		)
+	}
+	// An account or pool that already exists is left as it is; redeploying it
+	// could alter it underneath the volumes already carved out of it
+	_, accountExists, err := s.netAppFilesManager.GetAccount(
+		dt.AccountName,
+		instance.ResourceGroup,
+	)
+	if err != nil {
+		return nil, err
+	}
+	poolExists := false
+	if accountExists {
+		if _, poolExists, err = s.netAppFilesManager.GetCapacityPool(
+			dt.AccountName,
+			dt.CapacityPoolName,
+			instance.ResourceGroup,
+		); err != nil {
+			return nil, err
+		}
+	}
+	goParams, armParams := buildARMTemplateParameters(
+		pp,
+		dt,
+		!accountExists,
+		!poolExists,
+	)
+	outputs, err := s.armDeployer.Deploy(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateBytes,
+		goParams,
+		armParams,
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+	volumeID, ok := outputs["volumeId"].(string)
+	if !ok {
+		return nil, errors.New("error retrieving volume id from deployment")
+	}
+	dt.VolumeID = volumeID
+	mountTargets, ok := outputs["mountTargets"].([]interface{})
+	if !ok {
+		return nil, errors.New("error retrieving mount targets from deployment")
+	}
+	dt.MountTargets = make([]MountTarget, 0, len(mountTargets))
+	for _, mt := range mountTargets {
+		mountTarget, ok := mt.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("error reading mount target from deployment")
+		}
+		ipAddress, _ := mountTarget["ipAddress"].(string)
+		smbServerFQDN, _ := mountTarget["smbServerFqdn"].(string)
+		dt.MountTargets = append(
+			dt.MountTargets,
+			MountTarget{
+				IPAddress:     ipAddress,
+				SMBServerFQDN: smbServerFQDN,
+			},
+		)
+	}
+	return dt, nil
+}
+
+func buildARMTemplateParameters(
+	pp *ProvisioningParameters,
+	dt *netAppFilesInstanceDetails,
+	createAccount bool,
+	createPool bool,
+) (map[string]interface{}, map[string]interface{}) {
+	protocol := getProtocol(pp)
+	// An account's Active Directory connection is only made when the account is
+	// created
+	activeDirectory := createAccount && pp.ActiveDirectory != nil
+	goParams := map[string]interface{}{
+		"createAccount":   createAccount,
+		"createPool":      createPool,
+		"activeDirectory": activeDirectory,
+		"nfs":             protocol != protocolSMB,
+	}
+	protocolTypes := []string{protocol}
+	if protocol == protocolSMB {
+		// Azure calls the SMB protocol by the name of its dialect
+		protocolTypes = []string{"CIFS"}
+	}
+	armParams := map[string]interface{}{
+		"accountName":      dt.AccountName,
+		"capacityPoolName": dt.CapacityPoolName,
+		"volumeName":       dt.VolumeName,
+		"filePath":         dt.FilePath,
+		"serviceLevel":     getServiceLevel(pp),
+		"volumeSizeBytes":  int64(getVolumeSizeGiB(pp)) * gibibyte,
+		"subnetId":         pp.SubnetID,
+		"protocolTypes":    protocolTypes,
+	}
+	if createPool {
+		armParams["poolSizeBytes"] = int64(getPoolSizeTiB(pp)) * tebibyte
+	}
+	if protocol != protocolSMB {
+		armParams["allowedClients"] = strings.Join(getAllowedClients(pp), ",")
+		armParams["nfsv3"] = protocol == protocolNFSv3
+		armParams["nfsv41"] = protocol == protocolNFSv41
+	}
+	if activeDirectory {
+		ad := pp.ActiveDirectory
+		armParams["activeDirectoryDomain"] = ad.Domain
+		armParams["activeDirectoryDns"] = ad.DNS
+		armParams["activeDirectorySmbServerName"] = ad.SMBServerName
+		armParams["activeDirectoryUsername"] = ad.Username
+		armParams["activeDirectoryPassword"] = ad.Password
+		armParams["activeDirectoryOrganizationalUnit"] = ad.OrganizationalUnit
+	}
+	return goParams, armParams
+}
+
+// newName returns a new, random name with the given prefix that Azure permits
+// of accounts, capacity pools, volumes and file paths alike
+func newName(prefix string) string {
+	return prefix + strings.Replace(uuid.NewV4().String(), "-", "", -1)[:20]
+}
+
+func getServiceLevel(pp *ProvisioningParameters) string {
+	if pp.ServiceLevel == "" {
+		return defaultServiceLevel
+	}
+	return pp.ServiceLevel
+}
+
+func getPoolSizeTiB(pp *ProvisioningParameters) int {
+	if pp.PoolSizeTiB == 0 {
+		return defaultPoolSizeTiB
+	}
+	return pp.PoolSizeTiB
+}
+
+func getVolumeSizeGiB(pp *ProvisioningParameters) int {
+	if pp.VolumeSizeGiB == 0 {
+		return defaultVolumeSizeGiB
+	}
+	return pp.VolumeSizeGiB
+}
+
+func getProtocol(pp *ProvisioningParameters) string {
+	if pp.Protocol == "" {
+		return defaultProtocol
+	}
+	return pp.Protocol
+}
+
+// getAllowedClients returns the clients that may mount an NFS volume. Unless
+// they're restricted, mounting is permitted from anywhere the volume can be
+// reached, which is only from within its virtual network (and networks peered
+// with it) anyway.
+func getAllowedClients(pp *ProvisioningParameters) []string {
+	if len(pp.AllowedClients) == 0 && getProtocol(pp) != protocolSMB {
+		return []string{"0.0.0.0/0"}
+	}
+	return pp.AllowedClients
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package netappfiles
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testSubnetID = "/subscriptions/foo/resourceGroups/bar/providers/" +
+	"Microsoft.Network/virtualNetworks/baz/subnets/anf"
+
+func TestValidateProvisioningParametersWithInvalidSubnetID(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.SubnetID = "/subscriptions/foo/resourceGroups/bar"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.SubnetID = testSubnetID
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidServiceLevel(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		SubnetID:     testSubnetID,
+		ServiceLevel: "Flexible",
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.ServiceLevel = "Ultra"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidCapacity(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		SubnetID:    testSubnetID,
+		PoolSizeTiB: 4096,
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.PoolSizeTiB = 2
+	pp.VolumeSizeGiB = 50
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	// The volume doesn't fit in the new pool
+	pp.VolumeSizeGiB = 4096
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.VolumeSizeGiB = 2048
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+	// Whether it fits in an existing pool is checked later
+	pp.AccountName = "account"
+	pp.CapacityPoolName = "pool"
+	pp.VolumeSizeGiB = 4096
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithPoolButNoAccount(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		SubnetID:         testSubnetID,
+		CapacityPoolName: "pool",
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.AccountName = "1account"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.AccountName = "account"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidAllowedClients(
+	t *testing.T,
+) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		SubnetID:       testSubnetID,
+		AllowedClients: []string{"10.0.0.0/16", "bogus"},
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.AllowedClients = []string{"10.0.0.0/16", "192.168.1.10"}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersForSMB(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		SubnetID: testSubnetID,
+		Protocol: "SMB",
+	}
+	// A new account needs an Active Directory to join
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.ActiveDirectory = &ActiveDirectory{
+		Domain:        "contoso.com",
+		DNS:           "10.0.0.4, 10.0.0.5",
+		SMBServerName: "anf",
+		Username:      "admin",
+	}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.ActiveDirectory.Password = "Passw0rd!"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+	pp.ActiveDirectory.SMBServerName = "much-too-long"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	// An existing account may already be joined to one
+	pp.ActiveDirectory = nil
+	pp.AccountName = "account"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+	pp.AllowedClients = []string{"10.0.0.0/16"}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+}
+
+func TestBuildARMTemplateParameters(t *testing.T) {
+	pp := &ProvisioningParameters{
+		SubnetID:    testSubnetID,
+		PoolSizeTiB: 4,
+		Protocol:    "NFSv4.1",
+	}
+	dt := &netAppFilesInstanceDetails{
+		AccountName:      "account",
+		CapacityPoolName: "pool",
+		VolumeName:       "volume",
+		FilePath:         "volume",
+	}
+	goParams, armParams := buildARMTemplateParameters(pp, dt, false, true)
+	assert.Equal(t, false, goParams["createAccount"])
+	assert.Equal(t, true, goParams["createPool"])
+	assert.Equal(t, true, goParams["nfs"])
+	assert.Equal(t, int64(4)*tebibyte, armParams["poolSizeBytes"])
+	assert.Equal(t, int64(100)*gibibyte, armParams["volumeSizeBytes"])
+	assert.Equal(t, []string{"NFSv4.1"}, armParams["protocolTypes"])
+	assert.Equal(t, "0.0.0.0/0", armParams["allowedClients"])
+	assert.Equal(t, false, armParams["nfsv3"])
+	assert.Equal(t, true, armParams["nfsv41"])
+	pp.Protocol = "SMB"
+	goParams, armParams = buildARMTemplateParameters(pp, dt, false, false)
+	assert.Equal(t, false, goParams["nfs"])
+	assert.Equal(t, []string{"CIFS"}, armParams["protocolTypes"])
+	assert.NotContains(t, armParams, "poolSizeBytes")
+	assert.NotContains(t, armParams, "allowedClients")
+}
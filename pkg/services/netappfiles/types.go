@@ -0,0 +1,115 @@
+package netappfiles
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// ProvisioningParameters encapsulates Azure NetApp Files-specific
+// provisioning options
+type ProvisioningParameters struct {
+	// AccountName, if specified, names a NetApp account in the instance's
+	// resource group for the volume's capacity pool to belong to, which is
+	// created if it doesn't already exist. Otherwise, a new account is created
+	// for the instance alone.
+	AccountName string `json:"account"`
+	// CapacityPoolName, if specified, names a capacity pool of the account for
+	// the volume to be carved out of, which is created if it doesn't already
+	// exist. Otherwise, a new pool is created for the instance alone.
+	CapacityPoolName string `json:"capacityPool"`
+	// ServiceLevel is "Standard", "Premium" or "Ultra". A volume has its
+	// capacity pool's service level, so an existing pool's must match.
+	ServiceLevel string `json:"serviceLevel"`
+	// PoolSizeTiB is the size of a new capacity pool. It is ignored if the pool
+	// already exists.
+	PoolSizeTiB   int    `json:"poolSizeTiB"`
+	VolumeSizeGiB int    `json:"volumeSizeGiB"`
+	Protocol      string `json:"protocol"`
+	// SubnetID is the resource ID of the subnet the volume is placed in, which
+	// must be delegated to Microsoft.NetApp/volumes
+	SubnetID string `json:"subnetId"`
+	// AllowedClients are the IP addresses and CIDR ranges from which an NFS
+	// volume may be mounted
+	AllowedClients []string `json:"allowedClients"`
+	// ActiveDirectory is the Active Directory that a new account's SMB volumes
+	// join. It is required of SMB volumes whose account doesn't already exist.
+	ActiveDirectory *ActiveDirectory `json:"activeDirectory"`
+}
+
+// ActiveDirectory encapsulates the options for joining an account's SMB
+// volumes to an Active Directory domain
+type ActiveDirectory struct {
+	Domain string `json:"domain"`
+	// DNS is a comma-delimited list of the IP addresses of the domain's DNS
+	// servers
+	DNS string `json:"dns"`
+	// SMBServerName is the prefix of the names of the computer accounts that
+	// Azure creates in the domain for the account's SMB volumes
+	SMBServerName      string `json:"smbServerName"`
+	Username           string `json:"username"`
+	Password           string `json:"password" secret:"true"`
+	OrganizationalUnit string `json:"organizationalUnit"`
+}
+
+type netAppFilesInstanceDetails struct {
+	ARMDeploymentName string        `json:"armDeployment"`
+	AccountName       string        `json:"accountName"`
+	CapacityPoolName  string        `json:"capacityPoolName"`
+	VolumeName        string        `json:"volumeName"`
+	VolumeID          string        `json:"volumeId"`
+	FilePath          string        `json:"filePath"`
+	MountTargets      []MountTarget `json:"mountTargets"`
+}
+
+// MountTarget encapsulates a single address at which a volume can be mounted
+type MountTarget struct {
+	IPAddress     string `json:"ipAddress"`
+	SMBServerFQDN string `json:"smbServerFqdn,omitempty"`
+}
+
+// UpdatingParameters encapsulates Azure NetApp Files-specific updating
+// options
+type UpdatingParameters struct {
+}
+
+// BindingParameters encapsulates Azure NetApp Files-specific binding options
+type BindingParameters struct {
+}
+
+type netAppFilesBindingDetails struct {
+}
+
+// Credentials encapsulates the details needed to mount an Azure NetApp Files
+// volume
+type Credentials struct {
+	Protocol     string        `json:"protocol"`
+	FilePath     string        `json:"filePath"`
+	MountTargets []MountTarget `json:"mountTargets"`
+	// MountCommand mounts an NFS volume at /mnt/<filePath> on Linux
+	MountCommand string `json:"mountCommand,omitempty"`
+	// UNCPath is the path at which an SMB volume is shared
+	UNCPath string `json:"uncPath,omitempty"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &netAppFilesInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &netAppFilesBindingDetails{}
+}
@@ -0,0 +1,101 @@
+package orbital
+
+// nolint: lll
+var armTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "spacecraftName": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of the spacecraft registration"
+      }
+    },
+    "noradId": {
+      "type": "string"
+    },
+    "titleLine": {
+      "type": "string"
+    },
+    "tleLine1": {
+      "type": "string"
+    },
+    "tleLine2": {
+      "type": "string"
+    },
+    "spacecraftLinks": {
+      "type": "array"
+    },
+    "contactProfileName": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of the contact profile"
+      }
+    },
+    "minimumViableContactDuration": {
+      "type": "string"
+    },
+    "autoTrackingConfiguration": {
+      "type": "string"
+    },
+    "subnetId": {
+      "type": "string"
+    },
+    "contactProfileLinks": {
+      "type": "array"
+    },
+    "tags": {
+      "type": "object"
+    }
+  },
+  "variables": {
+    "apiVersion": "2022-11-01"
+  },
+  "resources": [
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[parameters('spacecraftName')]",
+      "type": "Microsoft.Orbital/spacecrafts",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "properties": {
+        "noradId": "[parameters('noradId')]",
+        "titleLine": "[parameters('titleLine')]",
+        "tleLine1": "[parameters('tleLine1')]",
+        "tleLine2": "[parameters('tleLine2')]",
+        "links": "[parameters('spacecraftLinks')]"
+      }
+    },
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[parameters('contactProfileName')]",
+      "type": "Microsoft.Orbital/contactProfiles",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "properties": {
+        "minimumViableContactDuration": "[parameters('minimumViableContactDuration')]",
+        "minimumElevationDegrees": {{ .minimumElevationDegrees }},
+        "autoTrackingConfiguration": "[parameters('autoTrackingConfiguration')]",
+        "networkConfiguration": {
+          "subnetId": "[parameters('subnetId')]"
+        },
+        "links": "[parameters('contactProfileLinks')]"
+      }
+    }
+  ],
+  "outputs": {
+    "spacecraftId": {
+      "type": "string",
+      "value": "[resourceId('Microsoft.Orbital/spacecrafts', parameters('spacecraftName'))]"
+    },
+    "contactProfileId": {
+      "type": "string",
+      "value": "[resourceId('Microsoft.Orbital/contactProfiles', parameters('contactProfileName'))]"
+    }
+  }
+}
+`)
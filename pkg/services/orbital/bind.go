@@ -0,0 +1,43 @@
+package orbital
+
+import (
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) ValidateBindingParameters(
+	service.BindingParameters,
+) error {
+	// There are no parameters for binding to an Orbital instance, so there is
+	// nothing to validate
+	return nil
+}
+
+func (s *serviceManager) Bind(
+	service.Instance,
+	service.BindingParameters,
+) (service.BindingDetails, error) {
+	return &orbitalBindingDetails{}, nil
+}
+
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	_ service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*orbitalInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *orbitalInstanceDetails",
+		)
+	}
+	return &Credentials{
+		SpacecraftID:               dt.SpacecraftID,
+		ContactProfileID:           dt.ContactProfileID,
+		AvailableGroundStationsURL: s.orbitalManager.GetAvailableGroundStationsURL(),
+		AvailableContactsURL: s.orbitalManager.GetAvailableContactsURL(
+			dt.SpacecraftID,
+		),
+		ContactsURL: s.orbitalManager.GetContactsURL(dt.SpacecraftID),
+	}, nil
+}
@@ -0,0 +1,40 @@
+package orbital
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:          "5b0e3f8c-4d0a-4f7e-9a86-2f1c6d9e8b47",
+				Name:        "azure-orbital",
+				Description: "Azure Orbital Ground Station (Experimental)",
+				Bindable:    true,
+				Tags:        []string{"Azure", "Orbital", "Ground Station", "Satellite"},
+				// Spacecraft and contact profiles can only be created in some
+				// regions
+				Locations: []string{
+					"australiaeast",
+					"brazilsouth",
+					"eastus",
+					"eastus2",
+					"northeurope",
+					"southcentralus",
+					"southeastasia",
+					"swedencentral",
+					"uksouth",
+					"westeurope",
+					"westus2",
+					"westus3",
+				},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:          "c3a1d7e2-8f64-4b9c-a0e5-7d2b9f4c1e63",
+				Name:        "ground-station",
+				Description: "Billed per minute of each scheduled contact",
+				Free:        false,
+			}),
+		),
+	}), nil
+}
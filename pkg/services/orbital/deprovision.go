@@ -0,0 +1,81 @@
+package orbital
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) GetDeprovisioner(
+	service.Plan,
+) (service.Deprovisioner, error) {
+	return service.NewDeprovisioner(
+		service.NewDeprovisioningStep("deleteARMDeployment", s.deleteARMDeployment),
+		service.NewDeprovisioningStep(
+			"deleteContactProfile",
+			s.deleteContactProfile,
+		),
+		service.NewDeprovisioningStep("deleteSpacecraft", s.deleteSpacecraft),
+	)
+}
+
+func (s *serviceManager) deleteARMDeployment(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*orbitalInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *orbitalInstanceDetails",
+		)
+	}
+	if err := s.armDeployer.Delete(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, fmt.Errorf("error deleting ARM deployment: %s", err)
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deleteContactProfile(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*orbitalInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *orbitalInstanceDetails",
+		)
+	}
+	if err := s.orbitalManager.DeleteContactProfile(
+		dt.ContactProfileName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+// deleteSpacecraft deletes the spacecraft registration, along with any
+// contacts that remain scheduled with it
+func (s *serviceManager) deleteSpacecraft(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*orbitalInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *orbitalInstanceDetails",
+		)
+	}
+	if err := s.orbitalManager.DeleteSpacecraft(
+		dt.SpacecraftName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
@@ -0,0 +1,44 @@
+package orbital
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/orbital"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer    arm.Deployer
+	orbitalManager orbital.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of registering spacecraft with Azure Orbital, along
+// with a contact profile describing how contacts with each spacecraft are made
+// through Microsoft's ground stations
+func New(
+	armDeployer arm.Deployer,
+	orbitalManager orbital.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer:    armDeployer,
+			orbitalManager: orbitalManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "orbital"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{"Microsoft.Orbital"}
+}
@@ -0,0 +1,562 @@
+package orbital
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	directionUplink   = "Uplink"
+	directionDownlink = "Downlink"
+
+	autoTrackingDisabled = "disabled"
+	autoTrackingSBand    = "sBand"
+	autoTrackingXBand    = "xBand"
+
+	protocolTCP = "TCP"
+	protocolUDP = "UDP"
+
+	// tleLineLength is the length of each line of a two-line element set
+	tleLineLength = 69
+
+	defaultMinimumElevationDegrees       = 5
+	defaultMinimumContactDurationMinutes = 1
+	maxMinimumContactDurationMinutes     = 60
+)
+
+var polarizations = []string{
+	"RHCP",
+	"LHCP",
+	"linearVertical",
+	"linearHorizontal",
+}
+
+// frequencyBand describes a range of frequencies over which Microsoft's ground
+// stations can communicate with spacecraft in the given direction
+type frequencyBand struct {
+	name         string
+	direction    string
+	autoTracking string
+	minMHz       float64
+	maxMHz       float64
+}
+
+var frequencyBands = []frequencyBand{
+	{"S-band uplink", directionUplink, autoTrackingSBand, 2025, 2110},
+	{"S-band downlink", directionDownlink, autoTrackingSBand, 2200, 2290},
+	{"X-band downlink", directionDownlink, autoTrackingXBand, 8000, 8400},
+}
+
+var noradIDRegex = regexp.MustCompile(`^[0-9]{1,9}$`)
+
+var linkNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]{0,49}$`)
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*orbital.ProvisioningParameters",
+		)
+	}
+	if !noradIDRegex.MatchString(pp.NORADID) {
+		return service.NewValidationError(
+			"noradId",
+			fmt.Sprintf(
+				`invalid noradId: "%s"; the NORAD catalog number must be given as `+
+					"digits",
+				pp.NORADID,
+			),
+		)
+	}
+	if pp.TitleLine == "" || len(pp.TitleLine) > 24 {
+		return service.NewValidationError(
+			"titleLine",
+			fmt.Sprintf(
+				`invalid titleLine: "%s"; the title line must be 1 to 24 characters`,
+				pp.TitleLine,
+			),
+		)
+	}
+	if err := validateTLE(pp); err != nil {
+		return err
+	}
+	if err := validateLinks(pp.Links); err != nil {
+		return err
+	}
+	if !az.IsValidSubnetID(pp.SubnetID) {
+		return service.NewValidationError(
+			"subnetId",
+			fmt.Sprintf(`invalid subnet resource id: "%s"`, pp.SubnetID),
+		)
+	}
+	if pp.MinimumElevationDegrees != nil &&
+		(*pp.MinimumElevationDegrees < 0 || *pp.MinimumElevationDegrees > 90) {
+		return service.NewValidationError(
+			"minimumElevationDegrees",
+			fmt.Sprintf(
+				"invalid minimumElevationDegrees: %v; the minimum elevation must "+
+					"be between 0 and 90 degrees",
+				*pp.MinimumElevationDegrees,
+			),
+		)
+	}
+	if pp.MinimumContactDurationMinutes < 0 ||
+		pp.MinimumContactDurationMinutes > maxMinimumContactDurationMinutes {
+		return service.NewValidationError(
+			"minimumContactDurationMinutes",
+			fmt.Sprintf(
+				"invalid minimumContactDurationMinutes: %d; the minimum contact "+
+					"duration must be between 1 and %d minutes",
+				pp.MinimumContactDurationMinutes,
+				maxMinimumContactDurationMinutes,
+			),
+		)
+	}
+	return validateAutoTracking(pp)
+}
+
+// validateTLE validates that each line of the spacecraft's two-line element
+// set is well-formed, that its checksum is correct, and that both lines
+// describe the spacecraft having the given NORAD catalog number
+func validateTLE(pp *ProvisioningParameters) error {
+	for i, line := range []string{pp.TLELine1, pp.TLELine2} {
+		field := fmt.Sprintf("tleLine%d", i+1)
+		if len(line) != tleLineLength || line[0] != byte('1'+i) ||
+			line[1] != ' ' {
+			return service.NewValidationError(
+				field,
+				fmt.Sprintf(
+					`invalid %s: "%s"; the line must be %d characters, beginning `+
+						`with "%d "`,
+					field,
+					line,
+					tleLineLength,
+					i+1,
+				),
+			)
+		}
+		if line[tleLineLength-1] != getTLEChecksum(line) {
+			return service.NewValidationError(
+				field,
+				fmt.Sprintf(`invalid %s: "%s"; the checksum is incorrect`, field, line),
+			)
+		}
+		if catalogNumber := strings.TrimLeft(
+			strings.TrimSpace(line[2:7]),
+			"0",
+		); catalogNumber != strings.TrimLeft(pp.NORADID, "0") {
+			return service.NewValidationError(
+				field,
+				fmt.Sprintf(
+					`invalid %s: "%s"; the line describes a spacecraft other than `+
+						`the one having noradId "%s"`,
+					field,
+					line,
+					pp.NORADID,
+				),
+			)
+		}
+	}
+	return nil
+}
+
+// getTLEChecksum returns the checksum digit of the given line of a two-line
+// element set: the sum, modulo 10, of all but the line's last character, where
+// digits count as their value, minus signs count as one, and all else counts as
+// zero
+func getTLEChecksum(line string) byte {
+	sum := 0
+	for _, c := range line[:len(line)-1] {
+		switch {
+		case c >= '0' && c <= '9':
+			sum += int(c - '0')
+		case c == '-':
+			sum++
+		}
+	}
+	return byte('0' + sum%10)
+}
+
+func validateLinks(links []Link) error {
+	if len(links) == 0 {
+		return service.NewValidationError(
+			"links",
+			"at least one link must be specified",
+		)
+	}
+	linkNames := map[string]bool{}
+	for i, link := range links {
+		field := fmt.Sprintf("links[%d]", i)
+		if !linkNameRegex.MatchString(link.Name) {
+			return service.NewValidationError(
+				field+".name",
+				fmt.Sprintf(
+					`invalid link name: "%s"; names must be 1 to 50 letters, `+
+						"numbers, hyphens, and underscores, beginning with a letter or "+
+						"number",
+					link.Name,
+				),
+			)
+		}
+		if linkNames[link.Name] {
+			return service.NewValidationError(
+				field+".name",
+				fmt.Sprintf(`duplicate link name: "%s"`, link.Name),
+			)
+		}
+		linkNames[link.Name] = true
+		if link.Direction != directionUplink &&
+			link.Direction != directionDownlink {
+			return service.NewValidationError(
+				field+".direction",
+				fmt.Sprintf(
+					`invalid direction: "%s"; allowed values are: %s, %s`,
+					link.Direction,
+					directionUplink,
+					directionDownlink,
+				),
+			)
+		}
+		if !isPolarization(link.Polarization) {
+			return service.NewValidationError(
+				field+".polarization",
+				fmt.Sprintf(
+					`invalid polarization: "%s"; allowed values are: %s`,
+					link.Polarization,
+					strings.Join(polarizations, ", "),
+				),
+			)
+		}
+		if link.BandwidthMHz <= 0 {
+			return service.NewValidationError(
+				field+".bandwidthMHz",
+				fmt.Sprintf(
+					"invalid bandwidthMHz: %v; the bandwidth must be greater than 0",
+					link.BandwidthMHz,
+				),
+			)
+		}
+		if _, ok := getFrequencyBand(link); !ok {
+			return service.NewValidationError(
+				field+".centerFrequencyMHz",
+				fmt.Sprintf(
+					"invalid link: %v MHz wide at %v MHz; %s links must lie "+
+						"entirely within one of the bands: %s",
+					link.BandwidthMHz,
+					link.CenterFrequencyMHz,
+					strings.ToLower(link.Direction),
+					getFrequencyBandsDescription(link.Direction),
+				),
+			)
+		}
+		if err := validateEndpoint(field+".endpoint", link.Endpoint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateEndpoint(field string, endpoint *Endpoint) error {
+	if endpoint == nil {
+		return service.NewValidationError(
+			field,
+			"an endpoint must be specified for every link",
+		)
+	}
+	if ip := net.ParseIP(endpoint.IPAddress); ip == nil || ip.To4() == nil {
+		return service.NewValidationError(
+			field+".ipAddress",
+			fmt.Sprintf(
+				`invalid ipAddress: "%s"; the address must be an IPv4 address`,
+				endpoint.IPAddress,
+			),
+		)
+	}
+	if endpoint.Port < 1 || endpoint.Port > 65535 {
+		return service.NewValidationError(
+			field+".port",
+			fmt.Sprintf(
+				"invalid port: %d; the port must be between 1 and 65535",
+				endpoint.Port,
+			),
+		)
+	}
+	if endpoint.Protocol != "" && endpoint.Protocol != protocolTCP &&
+		endpoint.Protocol != protocolUDP {
+		return service.NewValidationError(
+			field+".protocol",
+			fmt.Sprintf(
+				`invalid protocol: "%s"; allowed values are: %s, %s`,
+				endpoint.Protocol,
+				protocolTCP,
+				protocolUDP,
+			),
+		)
+	}
+	return nil
+}
+
+// validateAutoTracking validates that, if the ground station is to track the
+// spacecraft's signal, the spacecraft has a link in the band that's tracked
+func validateAutoTracking(pp *ProvisioningParameters) error {
+	autoTracking := getAutoTracking(pp)
+	switch autoTracking {
+	case autoTrackingDisabled:
+		return nil
+	case autoTrackingSBand, autoTrackingXBand:
+	default:
+		return service.NewValidationError(
+			"autoTracking",
+			fmt.Sprintf(
+				`invalid autoTracking: "%s"; allowed values are: %s, %s, %s`,
+				pp.AutoTracking,
+				autoTrackingDisabled,
+				autoTrackingSBand,
+				autoTrackingXBand,
+			),
+		)
+	}
+	for _, link := range pp.Links {
+		if band, ok := getFrequencyBand(link); ok &&
+			band.autoTracking == autoTracking {
+			return nil
+		}
+	}
+	return service.NewValidationError(
+		"autoTracking",
+		fmt.Sprintf(
+			`invalid autoTracking: "%s"; the spacecraft has no link in that band`,
+			pp.AutoTracking,
+		),
+	)
+}
+
+func (s *serviceManager) ApplyProvisioningParametersDefaults(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*orbital.ProvisioningParameters",
+		)
+	}
+	minimumElevationDegrees := getMinimumElevationDegrees(pp)
+	pp.MinimumElevationDegrees = &minimumElevationDegrees
+	pp.MinimumContactDurationMinutes = getMinimumContactDurationMinutes(pp)
+	pp.AutoTracking = getAutoTracking(pp)
+	for i := range pp.Links {
+		if pp.Links[i].Endpoint != nil {
+			pp.Links[i].Endpoint.Protocol = getProtocol(pp.Links[i].Endpoint)
+		}
+	}
+	return nil
+}
+
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep("deployARMTemplate", s.deployARMTemplate),
+	)
+}
+
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*orbitalInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *orbitalInstanceDetails",
+		)
+	}
+	dt.ARMDeploymentName = uuid.NewV4().String()
+	dt.SpacecraftName = "sc-" + uuid.NewV4().String()
+	dt.ContactProfileName = "cp-" + uuid.NewV4().String()
+	return dt, nil
+}
+
+func (s *serviceManager) deployARMTemplate(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*orbitalInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *orbitalInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*orbital.ProvisioningParameters",
+		)
+	}
+	outputs, err := s.armDeployer.Deploy(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateBytes,
+		map[string]interface{}{ // Go template params
+			"minimumElevationDegrees": getMinimumElevationDegrees(pp),
+		},
+		map[string]interface{}{ // ARM template params
+			"spacecraftName":     dt.SpacecraftName,
+			"noradId":            pp.NORADID,
+			"titleLine":          pp.TitleLine,
+			"tleLine1":           pp.TLELine1,
+			"tleLine2":           pp.TLELine2,
+			"spacecraftLinks":    getSpacecraftLinks(pp.Links),
+			"contactProfileName": dt.ContactProfileName,
+			"minimumViableContactDuration": fmt.Sprintf(
+				"PT%dM",
+				getMinimumContactDurationMinutes(pp),
+			),
+			"autoTrackingConfiguration": getAutoTracking(pp),
+			"subnetId":                  pp.SubnetID,
+			"contactProfileLinks":       getContactProfileLinks(pp.Links),
+		},
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+	spacecraftID, ok := outputs["spacecraftId"].(string)
+	if !ok {
+		return nil, errors.New("error retrieving spacecraft id from deployment")
+	}
+	dt.SpacecraftID = spacecraftID
+	contactProfileID, ok := outputs["contactProfileId"].(string)
+	if !ok {
+		return nil, errors.New(
+			"error retrieving contact profile id from deployment",
+		)
+	}
+	dt.ContactProfileID = contactProfileID
+	return dt, nil
+}
+
+// getSpacecraftLinks returns the given links as the spacecraft registration
+// describes them
+func getSpacecraftLinks(links []Link) []map[string]interface{} {
+	spacecraftLinks := make([]map[string]interface{}, len(links))
+	for i, link := range links {
+		spacecraftLinks[i] = map[string]interface{}{
+			"name":               link.Name,
+			"direction":          link.Direction,
+			"polarization":       link.Polarization,
+			"centerFrequencyMHz": link.CenterFrequencyMHz,
+			"bandwidthMHz":       link.BandwidthMHz,
+		}
+	}
+	return spacecraftLinks
+}
+
+// getContactProfileLinks returns the given links as the contact profile
+// describes them. Each is carried by a single channel spanning the whole link,
+// which is relayed to or from the link's endpoint.
+func getContactProfileLinks(links []Link) []map[string]interface{} {
+	contactProfileLinks := make([]map[string]interface{}, len(links))
+	for i, link := range links {
+		contactProfileLinks[i] = map[string]interface{}{
+			"name":                link.Name,
+			"direction":           link.Direction,
+			"polarization":        link.Polarization,
+			"gainOverTemperature": link.GainOverTemperature,
+			"eirpdBW":             link.EIRPdBW,
+			"channels": []map[string]interface{}{
+				{
+					"name":               link.Name + "-channel",
+					"centerFrequencyMHz": link.CenterFrequencyMHz,
+					"bandwidthMHz":       link.BandwidthMHz,
+					"endPoint": map[string]interface{}{
+						"endPointName": link.Name + "-endpoint",
+						"ipAddress":    link.Endpoint.IPAddress,
+						"port":         strconv.FormatInt(link.Endpoint.Port, 10),
+						"protocol":     getProtocol(link.Endpoint),
+					},
+				},
+			},
+		}
+	}
+	return contactProfileLinks
+}
+
+// getFrequencyBand returns the band, in the link's direction, that the link
+// lies entirely within, if any
+func getFrequencyBand(link Link) (frequencyBand, bool) {
+	minMHz := link.CenterFrequencyMHz - link.BandwidthMHz/2
+	maxMHz := link.CenterFrequencyMHz + link.BandwidthMHz/2
+	for _, band := range frequencyBands {
+		if band.direction == link.Direction &&
+			minMHz >= band.minMHz && maxMHz <= band.maxMHz {
+			return band, true
+		}
+	}
+	return frequencyBand{}, false
+}
+
+func getFrequencyBandsDescription(direction string) string {
+	descriptions := []string{}
+	for _, band := range frequencyBands {
+		if band.direction == direction {
+			descriptions = append(
+				descriptions,
+				fmt.Sprintf("%v-%v MHz (%s)", band.minMHz, band.maxMHz, band.name),
+			)
+		}
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+func isPolarization(polarization string) bool {
+	for _, p := range polarizations {
+		if polarization == p {
+			return true
+		}
+	}
+	return false
+}
+
+func getMinimumElevationDegrees(pp *ProvisioningParameters) float64 {
+	if pp.MinimumElevationDegrees == nil {
+		return defaultMinimumElevationDegrees
+	}
+	return *pp.MinimumElevationDegrees
+}
+
+func getMinimumContactDurationMinutes(pp *ProvisioningParameters) int64 {
+	if pp.MinimumContactDurationMinutes == 0 {
+		return defaultMinimumContactDurationMinutes
+	}
+	return pp.MinimumContactDurationMinutes
+}
+
+func getAutoTracking(pp *ProvisioningParameters) string {
+	if pp.AutoTracking == "" {
+		return autoTrackingDisabled
+	}
+	return pp.AutoTracking
+}
+
+func getProtocol(endpoint *Endpoint) string {
+	if endpoint.Protocol == "" {
+		return protocolTCP
+	}
+	return endpoint.Protocol
+}
@@ -0,0 +1,160 @@
+package orbital
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testTLELine1 = "1 25544U 98067A   08264.51782528 " +
+		"-.00002182  00000-0 -11606-4 0  2927"
+	testTLELine2 = "2 25544  51.6416 247.4627 0006703 " +
+		"130.5360 325.0288 15.72125391563537"
+)
+
+func getTestProvisioningParameters() *ProvisioningParameters {
+	return &ProvisioningParameters{
+		NORADID:   "25544",
+		TitleLine: "ISS (ZARYA)",
+		TLELine1:  testTLELine1,
+		TLELine2:  testTLELine2,
+		Links: []Link{
+			{
+				Name:               "downlink",
+				Direction:          directionDownlink,
+				Polarization:       "RHCP",
+				CenterFrequencyMHz: 8160,
+				BandwidthMHz:       15,
+				Endpoint: &Endpoint{
+					IPAddress: "10.0.1.4",
+					Port:      50000,
+				},
+			},
+		},
+		SubnetID: "/subscriptions/sub/resourceGroups/rg/providers/" +
+			"Microsoft.Network/virtualNetworks/vnet/subnets/orbital",
+	}
+}
+
+func TestValidateProvisioningParameters(t *testing.T) {
+	m := &module{}
+	err := m.serviceManager.ValidateProvisioningParameters(
+		getTestProvisioningParameters(),
+	)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidTLE(t *testing.T) {
+	m := &module{}
+	for name, mutate := range map[string]func(*ProvisioningParameters){
+		"bad checksum": func(pp *ProvisioningParameters) {
+			pp.TLELine1 = testTLELine1[:68] + "8"
+		},
+		"too short": func(pp *ProvisioningParameters) {
+			pp.TLELine2 = testTLELine2[:60]
+		},
+		"lines swapped": func(pp *ProvisioningParameters) {
+			pp.TLELine1, pp.TLELine2 = pp.TLELine2, pp.TLELine1
+		},
+		"other spacecraft": func(pp *ProvisioningParameters) {
+			pp.NORADID = "25545"
+		},
+	} {
+		pp := getTestProvisioningParameters()
+		mutate(pp)
+		err := m.serviceManager.ValidateProvisioningParameters(pp)
+		assert.NotNil(t, err, name)
+	}
+}
+
+func TestValidateProvisioningParametersLinks(t *testing.T) {
+	m := &module{}
+	for _, tc := range []struct {
+		direction          string
+		centerFrequencyMHz float64
+		bandwidthMHz       float64
+		valid              bool
+	}{
+		{directionDownlink, 2250, 10, true},
+		{directionDownlink, 8400, 10, false},
+		{directionDownlink, 2050, 10, false},
+		{directionUplink, 2050, 10, true},
+		{directionUplink, 8160, 10, false},
+		{directionUplink, 2050, 0, false},
+		{"Sideways", 2050, 10, false},
+	} {
+		pp := getTestProvisioningParameters()
+		pp.Links[0].Direction = tc.direction
+		pp.Links[0].CenterFrequencyMHz = tc.centerFrequencyMHz
+		pp.Links[0].BandwidthMHz = tc.bandwidthMHz
+		err := m.serviceManager.ValidateProvisioningParameters(pp)
+		if tc.valid {
+			assert.Nil(t, err, "%v", tc)
+		} else {
+			assert.NotNil(t, err, "%v", tc)
+		}
+	}
+}
+
+func TestValidateProvisioningParametersWithDuplicateLinkNames(t *testing.T) {
+	m := &module{}
+	pp := getTestProvisioningParameters()
+	pp.Links = append(pp.Links, pp.Links[0])
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidEndpoint(t *testing.T) {
+	m := &module{}
+	for name, endpoint := range map[string]*Endpoint{
+		"missing":  nil,
+		"ipv6":     {IPAddress: "fd00::4", Port: 50000},
+		"bad port": {IPAddress: "10.0.1.4", Port: 70000},
+		"protocol": {IPAddress: "10.0.1.4", Port: 50000, Protocol: "SCTP"},
+	} {
+		pp := getTestProvisioningParameters()
+		pp.Links[0].Endpoint = endpoint
+		err := m.serviceManager.ValidateProvisioningParameters(pp)
+		assert.NotNil(t, err, name)
+	}
+}
+
+func TestValidateProvisioningParametersAutoTracking(t *testing.T) {
+	m := &module{}
+	pp := getTestProvisioningParameters()
+	pp.AutoTracking = autoTrackingXBand
+	assert.Nil(t, m.serviceManager.ValidateProvisioningParameters(pp))
+	// The spacecraft has no S-band link to track
+	pp.AutoTracking = autoTrackingSBand
+	assert.NotNil(t, m.serviceManager.ValidateProvisioningParameters(pp))
+}
+
+func TestApplyProvisioningParametersDefaults(t *testing.T) {
+	m := &module{}
+	pp := getTestProvisioningParameters()
+	err := m.serviceManager.ApplyProvisioningParametersDefaults(pp)
+	assert.Nil(t, err)
+	assert.Equal(t, float64(5), *pp.MinimumElevationDegrees)
+	assert.Equal(t, int64(1), pp.MinimumContactDurationMinutes)
+	assert.Equal(t, autoTrackingDisabled, pp.AutoTracking)
+	assert.Equal(t, protocolTCP, pp.Links[0].Endpoint.Protocol)
+}
+
+func TestGetContactProfileLinks(t *testing.T) {
+	links := getContactProfileLinks(getTestProvisioningParameters().Links)
+	assert.Equal(t, 1, len(links))
+	channels := links[0]["channels"].([]map[string]interface{})
+	assert.Equal(t, 1, len(channels))
+	assert.Equal(t, float64(8160), channels[0]["centerFrequencyMHz"])
+	assert.Equal(
+		t,
+		map[string]interface{}{
+			"endPointName": "downlink-endpoint",
+			"ipAddress":    "10.0.1.4",
+			"port":         "50000",
+			"protocol":     protocolTCP,
+		},
+		channels[0]["endPoint"],
+	)
+}
@@ -0,0 +1,109 @@
+package orbital
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// ProvisioningParameters encapsulates Azure Orbital-specific provisioning
+// options
+type ProvisioningParameters struct {
+	// NORADID is the spacecraft's NORAD catalog number, which must agree with
+	// the one given by its two-line element set
+	NORADID string `json:"noradId"`
+	// TitleLine is the name that precedes the spacecraft's two-line element set
+	TitleLine string `json:"titleLine"`
+	TLELine1  string `json:"tleLine1"`
+	TLELine2  string `json:"tleLine2"`
+	// Links are the spacecraft's radio links, each of which is received or
+	// transmitted by the ground station during a contact and relayed to or from
+	// an endpoint in the contact profile's subnet
+	Links []Link `json:"links"`
+	// SubnetID is the resource ID of the subnet, delegated to Azure Orbital,
+	// in which links' endpoints reside
+	SubnetID                string   `json:"subnetId"`
+	MinimumElevationDegrees *float64 `json:"minimumElevationDegrees"`
+	// MinimumContactDurationMinutes is the shortest contact that is worth
+	// scheduling; shorter passes aren't offered as available contacts
+	MinimumContactDurationMinutes int64 `json:"minimumContactDurationMinutes"`
+	// AutoTracking, if not "disabled", is the band in which the ground station
+	// tracks the spacecraft's signal, rather than following its predicted path
+	AutoTracking string `json:"autoTracking"`
+}
+
+// Link encapsulates the configuration of one of a spacecraft's radio links
+type Link struct {
+	Name               string  `json:"name"`
+	Direction          string  `json:"direction"`
+	Polarization       string  `json:"polarization"`
+	CenterFrequencyMHz float64 `json:"centerFrequencyMHz"`
+	BandwidthMHz       float64 `json:"bandwidthMHz"`
+	// GainOverTemperature is the ground station's required gain-to-noise
+	// temperature ratio, in dB/K, for a downlink
+	GainOverTemperature float64 `json:"gainOverTemperature"`
+	// EIRPdBW is the ground station's required effective isotropic radiated
+	// power, in dBW, for an uplink
+	EIRPdBW  float64   `json:"eirpdBW"`
+	Endpoint *Endpoint `json:"endpoint"`
+}
+
+// Endpoint encapsulates the address, within the contact profile's subnet, to
+// which a link's data is relayed, or from which it is received
+type Endpoint struct {
+	IPAddress string `json:"ipAddress"`
+	Port      int64  `json:"port"`
+	Protocol  string `json:"protocol"`
+}
+
+type orbitalInstanceDetails struct {
+	ARMDeploymentName  string `json:"armDeployment"`
+	SpacecraftName     string `json:"spacecraftName"`
+	SpacecraftID       string `json:"spacecraftId"`
+	ContactProfileName string `json:"contactProfileName"`
+	ContactProfileID   string `json:"contactProfileId"`
+}
+
+// UpdatingParameters encapsulates Azure Orbital-specific updating options
+type UpdatingParameters struct {
+}
+
+// BindingParameters encapsulates Azure Orbital-specific binding options
+type BindingParameters struct {
+}
+
+type orbitalBindingDetails struct {
+}
+
+// Credentials encapsulates the IDs of the spacecraft and contact profile, and
+// the Azure Resource Manager endpoints by which contacts with the spacecraft
+// are scheduled
+type Credentials struct {
+	SpacecraftID               string `json:"spacecraftId"`
+	ContactProfileID           string `json:"contactProfileId"`
+	AvailableGroundStationsURL string `json:"availableGroundStationsUrl"`
+	AvailableContactsURL       string `json:"availableContactsUrl"`
+	ContactsURL                string `json:"contactsUrl"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &orbitalInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &orbitalBindingDetails{}
+}
@@ -0,0 +1,14 @@
+package orbital
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// Unbind does nothing. Binding creates nothing; its credentials are merely the
+// endpoints by which contacts are scheduled with the instance's spacecraft.
+func (s *serviceManager) Unbind(
+	service.Instance,
+	service.BindingDetails,
+) error {
+	return nil
+}
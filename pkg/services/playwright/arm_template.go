@@ -0,0 +1,74 @@
+package playwright
+
+// nolint: lll
+var armTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "workspaceName": {
+      "type": "string"
+    },
+    "regionalAffinity": {
+      "type": "string",
+      "allowedValues": [
+        "Enabled",
+        "Disabled"
+      ]
+    },
+    "scalableExecution": {
+      "type": "string",
+      "allowedValues": [
+        "Enabled",
+        "Disabled"
+      ]
+    },
+    "reporting": {
+      "type": "string",
+      "allowedValues": [
+        "Enabled",
+        "Disabled"
+      ]
+    },
+    "tags": {
+      "type": "object"
+    }
+  },
+  "variables": {
+    "apiVersion": "2024-12-01",
+    "workspaceId": "[resourceId('Microsoft.AzurePlaywrightService/accounts', parameters('workspaceName'))]"
+  },
+  "resources": [
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[parameters('workspaceName')]",
+      "type": "Microsoft.AzurePlaywrightService/accounts",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "properties": {
+        "regionalAffinity": "[parameters('regionalAffinity')]",
+        "scalableExecution": "[parameters('scalableExecution')]",
+        "reporting": "[parameters('reporting')]",
+        "localAuth": "Enabled"
+      }
+    }
+  ],
+  "outputs": {
+    "workspaceId": {
+      "type": "string",
+      "value": "[reference(variables('workspaceId'), variables('apiVersion')).workspaceId]"
+    },
+    "serviceEndpoint": {
+      "type": "string",
+      "value": "[concat('wss://', parameters('location'), '.api.playwright.microsoft.com/accounts/', reference(variables('workspaceId'), variables('apiVersion')).workspaceId, '/browsers')]"
+    },
+    "dashboardUri": {
+      "type": "string",
+      "value": "[reference(variables('workspaceId'), variables('apiVersion')).dashboardUri]"
+    }
+  }
+}
+`)
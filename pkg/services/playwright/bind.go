@@ -0,0 +1,105 @@
+package playwright
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	defaultAccessTokenExpiryDays = 30
+	maxAccessTokenExpiryDays     = 90
+)
+
+func (s *serviceManager) ValidateBindingParameters(
+	bindingParameters service.BindingParameters,
+) error {
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return errors.New(
+			"error casting bindingParameters as *playwright.BindingParameters",
+		)
+	}
+	if bp.AccessTokenExpiryDays < 0 ||
+		bp.AccessTokenExpiryDays > maxAccessTokenExpiryDays {
+		return service.NewValidationError(
+			"accessTokenExpiryDays",
+			fmt.Sprintf(
+				"invalid accessTokenExpiryDays: %d; access tokens may remain valid "+
+					"for between 1 and %d days",
+				bp.AccessTokenExpiryDays,
+				maxAccessTokenExpiryDays,
+			),
+		)
+	}
+	return nil
+}
+
+// Bind creates an access token of the workspace for the binding alone. An
+// access token grants access to the workspace that it was created for and to
+// no other, and unbinding revokes it without affecting any other binding's.
+func (s *serviceManager) Bind(
+	instance service.Instance,
+	bindingParameters service.BindingParameters,
+) (service.BindingDetails, error) {
+	dt, ok := instance.Details.(*playwrightInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *playwrightInstanceDetails",
+		)
+	}
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting bindingParameters as *playwright.BindingParameters",
+		)
+	}
+	expiryDays := bp.AccessTokenExpiryDays
+	if expiryDays == 0 {
+		expiryDays = defaultAccessTokenExpiryDays
+	}
+	expiry := time.Now().AddDate(0, 0, int(expiryDays)).UTC().Truncate(
+		time.Second,
+	)
+	accessToken, err := s.playwrightManager.CreateAccessToken(
+		instance.Location,
+		dt.WorkspaceID,
+		"binding-"+uuid.NewV4().String(),
+		expiry,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &playwrightBindingDetails{
+		AccessTokenID:        accessToken.ID,
+		AccessToken:          accessToken.JWTToken,
+		AccessTokenExpiresAt: expiry.Format(time.RFC3339),
+	}, nil
+}
+
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	binding service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*playwrightInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *playwrightInstanceDetails",
+		)
+	}
+	bd, ok := binding.Details.(*playwrightBindingDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting binding.Details as *playwrightBindingDetails",
+		)
+	}
+	return &Credentials{
+		ServiceEndpoint:      dt.ServiceEndpoint,
+		AccessToken:          bd.AccessToken,
+		AccessTokenExpiresAt: bd.AccessTokenExpiresAt,
+		DashboardURI:         dt.DashboardURI,
+	}, nil
+}
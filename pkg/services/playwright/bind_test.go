@@ -0,0 +1,55 @@
+package playwright
+
+import (
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateBindingParameters(t *testing.T) {
+	m := &module{}
+	bp := &BindingParameters{}
+	err := m.serviceManager.ValidateBindingParameters(bp)
+	assert.Nil(t, err)
+	bp.AccessTokenExpiryDays = -1
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.NotNil(t, err)
+	bp.AccessTokenExpiryDays = maxAccessTokenExpiryDays + 1
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.NotNil(t, err)
+	bp.AccessTokenExpiryDays = maxAccessTokenExpiryDays
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.Nil(t, err)
+}
+
+func TestGetCredentials(t *testing.T) {
+	m := &module{}
+	credentials, err := m.serviceManager.GetCredentials(
+		service.Instance{
+			Details: &playwrightInstanceDetails{
+				ServiceEndpoint: "wss://eastus.api.playwright.microsoft.com/" +
+					"accounts/eastus_workspace/browsers",
+			},
+		},
+		service.Binding{
+			Details: &playwrightBindingDetails{
+				AccessToken:          "token",
+				AccessTokenExpiresAt: "2026-11-13T00:00:00Z",
+			},
+		},
+	)
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		"wss://eastus.api.playwright.microsoft.com/accounts/eastus_workspace/"+
+			"browsers",
+		credentials.(*Credentials).ServiceEndpoint,
+	)
+	assert.Equal(t, "token", credentials.(*Credentials).AccessToken)
+	assert.Equal(
+		t,
+		"2026-11-13T00:00:00Z",
+		credentials.(*Credentials).AccessTokenExpiresAt,
+	)
+}
@@ -0,0 +1,36 @@
+package playwright
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:          "3e7b9c41-5a2d-4f86-b0e3-8d1c6a4f9e27",
+				Name:        "azure-playwright-testing",
+				Description: "Azure Playwright Testing (Experimental)",
+				Bindable:    true,
+				Tags: []string{
+					"Azure",
+					"Playwright",
+					"Testing",
+				},
+				// Playwright Testing workspaces are only offered in some regions
+				Locations: []string{
+					"eastasia",
+					"eastus",
+					"westeurope",
+					"westus3",
+				},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:   "a6d2f08e-4c1b-4b7a-9e35-71f0c8b2d4e6",
+				Name: "workspace",
+				Description: "Billed by the minutes tests run on cloud-hosted " +
+					"browsers and by the results reported to the workspace",
+				Free: false,
+			}),
+		),
+	}), nil
+}
@@ -0,0 +1,58 @@
+package playwright
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) GetDeprovisioner(
+	service.Plan,
+) (service.Deprovisioner, error) {
+	return service.NewDeprovisioner(
+		service.NewDeprovisioningStep("deleteARMDeployment", s.deleteARMDeployment),
+		service.NewDeprovisioningStep("deleteWorkspace", s.deleteWorkspace),
+	)
+}
+
+func (s *serviceManager) deleteARMDeployment(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*playwrightInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *playwrightInstanceDetails",
+		)
+	}
+	if err := s.armDeployer.Delete(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, fmt.Errorf("error deleting ARM deployment: %s", err)
+	}
+	return dt, nil
+}
+
+// deleteWorkspace deletes the workspace, which revokes all of its access
+// tokens and discards the test results reported to it
+func (s *serviceManager) deleteWorkspace(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*playwrightInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *playwrightInstanceDetails",
+		)
+	}
+	if err := s.playwrightManager.DeleteWorkspace(
+		dt.WorkspaceName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
@@ -0,0 +1,43 @@
+package playwright
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/playwright"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer       arm.Deployer
+	playwrightManager playwright.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of provisioning Azure Playwright Testing
+// workspaces
+func New(
+	armDeployer arm.Deployer,
+	playwrightManager playwright.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer:       armDeployer,
+			playwrightManager: playwrightManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "playwright"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{"Microsoft.AzurePlaywrightService"}
+}
@@ -0,0 +1,161 @@
+package playwright
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	enabled  = "Enabled"
+	disabled = "Disabled"
+)
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*playwright.ProvisioningParameters",
+		)
+	}
+	if err := validateSetting(
+		"regionalAffinity",
+		pp.RegionalAffinity,
+	); err != nil {
+		return err
+	}
+	if err := validateSetting(
+		"scalableExecution",
+		pp.ScalableExecution,
+	); err != nil {
+		return err
+	}
+	return validateSetting("reporting", pp.Reporting)
+}
+
+// validateSetting validates the value of one of a workspace's settings, each
+// of which is either enabled or disabled
+func validateSetting(field string, value string) error {
+	if value != "" && value != enabled && value != disabled {
+		return service.NewValidationError(
+			field,
+			fmt.Sprintf(
+				`invalid %s: "%s"; allowed values are: %s, %s`,
+				field,
+				value,
+				enabled,
+				disabled,
+			),
+		)
+	}
+	return nil
+}
+
+func (s *serviceManager) ApplyProvisioningParametersDefaults(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*playwright.ProvisioningParameters",
+		)
+	}
+	pp.RegionalAffinity = getSetting(pp.RegionalAffinity)
+	pp.ScalableExecution = getSetting(pp.ScalableExecution)
+	pp.Reporting = getSetting(pp.Reporting)
+	return nil
+}
+
+// getSetting returns the given value of one of a workspace's settings, or the
+// default value, which enables it, if none was specified
+func getSetting(value string) string {
+	if value == "" {
+		return enabled
+	}
+	return value
+}
+
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep("deployARMTemplate", s.deployARMTemplate),
+	)
+}
+
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*playwrightInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *playwrightInstanceDetails",
+		)
+	}
+	dt.ARMDeploymentName = uuid.NewV4().String()
+	// Workspace names are limited to 24 characters and must begin with a letter
+	dt.WorkspaceName = "pw" +
+		strings.Replace(uuid.NewV4().String(), "-", "", -1)[:22]
+	return dt, nil
+}
+
+func (s *serviceManager) deployARMTemplate(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*playwrightInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *playwrightInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*playwright.ProvisioningParameters",
+		)
+	}
+	outputs, err := s.armDeployer.Deploy(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateBytes,
+		nil,
+		map[string]interface{}{
+			"workspaceName":     dt.WorkspaceName,
+			"regionalAffinity":  getSetting(pp.RegionalAffinity),
+			"scalableExecution": getSetting(pp.ScalableExecution),
+			"reporting":         getSetting(pp.Reporting),
+		},
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+	for output, field := range map[string]*string{
+		"workspaceId":     &dt.WorkspaceID,
+		"serviceEndpoint": &dt.ServiceEndpoint,
+		"dashboardUri":    &dt.DashboardURI,
+	} {
+		value, ok := outputs[output].(string)
+		if !ok {
+			return nil, fmt.Errorf(
+				`error retrieving "%s" from deployment`,
+				output,
+			)
+		}
+		*field = value
+	}
+	return dt, nil
+}
@@ -0,0 +1,80 @@
+package playwright
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// ProvisioningParameters encapsulates Azure Playwright Testing-specific
+// provisioning options. Each is either "Enabled" or "Disabled".
+type ProvisioningParameters struct {
+	// RegionalAffinity lets tests run on browsers in the region closest to the
+	// client, rather than only in the workspace's own region
+	RegionalAffinity string `json:"regionalAffinity"`
+	// ScalableExecution lets tests run on cloud-hosted browsers
+	ScalableExecution string `json:"scalableExecution"`
+	// Reporting lets test results and artifacts be published to the workspace
+	Reporting string `json:"reporting"`
+}
+
+type playwrightInstanceDetails struct {
+	ARMDeploymentName string `json:"armDeployment"`
+	WorkspaceName     string `json:"workspaceName"`
+	WorkspaceID       string `json:"workspaceId"`
+	// ServiceEndpoint is the endpoint at which Playwright clients connect to the
+	// workspace's browsers
+	ServiceEndpoint string `json:"serviceEndpoint"`
+	DashboardURI    string `json:"dashboardUri"`
+}
+
+// UpdatingParameters encapsulates Azure Playwright Testing-specific updating
+// options
+type UpdatingParameters struct {
+}
+
+// BindingParameters encapsulates Azure Playwright Testing-specific binding
+// options
+type BindingParameters struct {
+	// AccessTokenExpiryDays is how long the binding's access token remains
+	// valid
+	AccessTokenExpiryDays int64 `json:"accessTokenExpiryDays"`
+}
+
+type playwrightBindingDetails struct {
+	AccessTokenID        string `json:"accessTokenId"`
+	AccessToken          string `json:"accessToken" secret:"true"`
+	AccessTokenExpiresAt string `json:"accessTokenExpiresAt"`
+}
+
+// Credentials encapsulates the endpoint and access token with which
+// Playwright clients, such as @azure/microsoft-playwright-testing, run tests
+// on the workspace's browsers
+type Credentials struct {
+	ServiceEndpoint      string `json:"serviceEndpoint"`
+	AccessToken          string `json:"accessToken"`
+	AccessTokenExpiresAt string `json:"accessTokenExpiresAt"`
+	DashboardURI         string `json:"dashboardUri"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &playwrightInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &playwrightBindingDetails{}
+}
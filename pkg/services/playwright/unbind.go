@@ -0,0 +1,30 @@
+package playwright
+
+import (
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) Unbind(
+	instance service.Instance,
+	bindingDetails service.BindingDetails,
+) error {
+	dt, ok := instance.Details.(*playwrightInstanceDetails)
+	if !ok {
+		return errors.New(
+			"error casting instance.Details as *playwrightInstanceDetails",
+		)
+	}
+	bd, ok := bindingDetails.(*playwrightBindingDetails)
+	if !ok {
+		return errors.New(
+			"error casting bindingDetails as *playwrightBindingDetails",
+		)
+	}
+	return s.playwrightManager.DeleteAccessToken(
+		instance.Location,
+		dt.WorkspaceID,
+		bd.AccessTokenID,
+	)
+}
@@ -0,0 +1,45 @@
+package postgresqldb
+
+import (
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCredentialsHonorsSSLEnforcement(t *testing.T) {
+	sm := &serviceManager{}
+	dt := &postgresqlInstanceDetails{
+		ServerName:                 "server",
+		AdministratorLoginPassword: "admin-password",
+		DatabaseName:               "db",
+		FullyQualifiedDomainName:   "server.postgres.database.azure.com",
+		EnforceSSL:                 true,
+	}
+	binding := service.Binding{
+		Details: &postgresqlBindingDetails{
+			LoginName: "user",
+			Password:  "password",
+		},
+	}
+	creds, err := sm.GetCredentials(service.Instance{Details: dt}, binding)
+	assert.Nil(t, err)
+	c, ok := creds.(*Credentials)
+	assert.True(t, ok)
+	assert.True(t, c.SSLRequired)
+	assert.Equal(
+		t,
+		"postgres://user%40server:password@"+
+			"server.postgres.database.azure.com:5432/db?sslmode=require",
+		c.URI,
+	)
+	assert.NotContains(t, c.URI, dt.AdministratorLoginPassword)
+
+	dt.EnforceSSL = false
+	creds, err = sm.GetCredentials(service.Instance{Details: dt}, binding)
+	assert.Nil(t, err)
+	c, ok = creds.(*Credentials)
+	assert.True(t, ok)
+	assert.False(t, c.SSLRequired)
+	assert.NotContains(t, c.URI, "sslmode")
+}
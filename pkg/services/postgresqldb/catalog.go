@@ -10,6 +10,7 @@ func (m *module) GetCatalog() (service.Catalog, error) {
 				Name:        "azure-postgresqldb",
 				Description: "Azure Database for PostgreSQL (Experimental)",
 				Bindable:    true,
+				Cloneable:   true,
 				Tags:        []string{"Azure", "PostgreSQL", "Database"},
 			},
 			m.serviceManager,
@@ -0,0 +1,94 @@
+package postgresqldb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/postgresql"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+const (
+	// firewallRuleName is the name the ARM template gives the server's one
+	// firewall rule
+	firewallRuleName = "AllowAll"
+	// defaultFirewallIPAddress is the address the ARM template opens the
+	// firewall to when no range is given, which admits only Azure services
+	defaultFirewallIPAddress = "0.0.0.0"
+)
+
+// DetectDrift compares the server's SSL enforcement and firewall rule with
+// those the instance was provisioned with. Both are restored by the updater,
+// which redeploys the ARM template.
+func (s *serviceManager) DetectDrift(
+	_ context.Context,
+	instance service.Instance,
+) ([]service.Drift, error) {
+	dt, ok := instance.Details.(*postgresqlInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *postgresqlInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*postgresql.ProvisioningParameters",
+		)
+	}
+	state, err := s.postgresqlManager.GetServerState(
+		dt.ServerName,
+		instance.ResourceGroup,
+		firewallRuleName,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error reading server state: %s", err)
+	}
+	return getDrifts(dt, pp, state), nil
+}
+
+func getDrifts(
+	dt *postgresqlInstanceDetails,
+	pp *ProvisioningParameters,
+	state postgresql.ServerState,
+) []service.Drift {
+	drifts := []service.Drift{}
+	sslEnforcement := getSSLEnforcement(dt)
+	if state.SSLEnforcement != sslEnforcement {
+		drifts = append(drifts, service.Drift{
+			Resource: "server",
+			Property: "sslEnforcement",
+			Expected: sslEnforcement,
+			Actual:   state.SSLEnforcement,
+		})
+	}
+	startIPAddress := pp.FirewallIPStart
+	endIPAddress := pp.FirewallIPEnd
+	if startIPAddress == "" {
+		startIPAddress = defaultFirewallIPAddress
+		endIPAddress = defaultFirewallIPAddress
+	}
+	rule := state.FirewallRule
+	if rule == nil {
+		rule = &postgresql.FirewallRule{}
+	}
+	if rule.StartIPAddress != startIPAddress {
+		drifts = append(drifts, service.Drift{
+			Resource: "firewallRule",
+			Property: "startIpAddress",
+			Expected: startIPAddress,
+			Actual:   rule.StartIPAddress,
+		})
+	}
+	if rule.EndIPAddress != endIPAddress {
+		drifts = append(drifts, service.Drift{
+			Resource: "firewallRule",
+			Property: "endIpAddress",
+			Expected: endIPAddress,
+			Actual:   rule.EndIPAddress,
+		})
+	}
+	return drifts
+}
@@ -0,0 +1,62 @@
+package postgresqldb
+
+import (
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/postgresql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDriftsWithoutDrift(t *testing.T) {
+	drifts := getDrifts(
+		&postgresqlInstanceDetails{EnforceSSL: true},
+		&ProvisioningParameters{},
+		postgresql.ServerState{
+			SSLEnforcement: "Enabled",
+			FirewallRule: &postgresql.FirewallRule{
+				StartIPAddress: "0.0.0.0",
+				EndIPAddress:   "0.0.0.0",
+			},
+		},
+	)
+	assert.Empty(t, drifts)
+}
+
+func TestGetDriftsWithChangedSettings(t *testing.T) {
+	drifts := getDrifts(
+		&postgresqlInstanceDetails{EnforceSSL: true},
+		&ProvisioningParameters{
+			FirewallIPStart: "192.168.86.1",
+			FirewallIPEnd:   "192.168.86.100",
+		},
+		postgresql.ServerState{
+			SSLEnforcement: "Disabled",
+			FirewallRule: &postgresql.FirewallRule{
+				StartIPAddress: "192.168.86.1",
+				EndIPAddress:   "192.168.86.255",
+			},
+		},
+	)
+	assert.Equal(t, 2, len(drifts))
+	assert.Equal(t, "sslEnforcement", drifts[0].Property)
+	assert.Equal(t, "Enabled", drifts[0].Expected)
+	assert.Equal(t, "Disabled", drifts[0].Actual)
+	assert.Equal(t, "endIpAddress", drifts[1].Property)
+	assert.Equal(t, "192.168.86.100", drifts[1].Expected)
+	assert.Equal(t, "192.168.86.255", drifts[1].Actual)
+}
+
+func TestGetDriftsWithDeletedFirewallRule(t *testing.T) {
+	drifts := getDrifts(
+		&postgresqlInstanceDetails{},
+		&ProvisioningParameters{},
+		postgresql.ServerState{
+			SSLEnforcement: "Disabled",
+		},
+	)
+	assert.Equal(t, 2, len(drifts))
+	for _, drift := range drifts {
+		assert.Equal(t, "firewallRule", drift.Resource)
+		assert.Empty(t, drift.Actual)
+	}
+}
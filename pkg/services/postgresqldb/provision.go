@@ -77,6 +77,23 @@ func (s *serviceManager) ValidateProvisioningParameters(
 	return nil
 }
 
+func (s *serviceManager) ApplyProvisioningParametersDefaults(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as *postgresql.ProvisioningParameters",
+		)
+	}
+	// SSL is enforced unless it's explicitly disabled
+	pp.SSLEnforcement = strings.ToLower(pp.SSLEnforcement)
+	if pp.SSLEnforcement == "" {
+		pp.SSLEnforcement = "enabled"
+	}
+	return nil
+}
+
 func (s *serviceManager) GetProvisioner(
 	service.Plan,
 ) (service.Provisioner, error) {
@@ -127,12 +144,7 @@ func buildARMTemplateParameters(
 	details *postgresqlInstanceDetails,
 	provisioningParameters *ProvisioningParameters,
 ) map[string]interface{} {
-	var sslEnforcement string
-	if details.EnforceSSL {
-		sslEnforcement = "Enabled"
-	} else {
-		sslEnforcement = "Disabled"
-	}
+	sslEnforcement := getSSLEnforcement(details)
 	p := map[string]interface{}{ // ARM template params
 		"administratorLoginPassword": details.AdministratorLoginPassword,
 		"serverName":                 details.ServerName,
@@ -155,6 +167,13 @@ func buildARMTemplateParameters(
 	return p
 }
 
+func getSSLEnforcement(details *postgresqlInstanceDetails) string {
+	if details.EnforceSSL {
+		return "Enabled"
+	}
+	return "Disabled"
+}
+
 func (s *serviceManager) deployARMTemplate(
 	_ context.Context,
 	instance service.Instance,
@@ -183,7 +202,7 @@ func (s *serviceManager) deployARMTemplate(
 		instance.Tags,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("error deploying ARM template: %s", err)
+		return nil, service.WrapError(err, "error deploying ARM template")
 	}
 
 	fullyQualifiedDomainName, ok := outputs["fullyQualifiedDomainName"].(string)
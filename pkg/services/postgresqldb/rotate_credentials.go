@@ -0,0 +1,50 @@
+package postgresqldb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/generate"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// RotateCredentials gives the binding's role a new password. The role itself,
+// and so its grants and any objects it owns, is left as it is. Sessions opened
+// with the old password remain open until they are closed.
+func (s *serviceManager) RotateCredentials(
+	_ context.Context,
+	instance service.Instance,
+	binding service.Binding,
+) (service.BindingDetails, error) {
+	dt, ok := instance.Details.(*postgresqlInstanceDetails)
+	if !ok {
+		return nil, fmt.Errorf(
+			"error casting instance.Details as *postgresqlInstanceDetails",
+		)
+	}
+	bd, ok := binding.Details.(*postgresqlBindingDetails)
+	if !ok {
+		return nil, fmt.Errorf(
+			"error casting binding.Details as *postgresqlBindingDetails",
+		)
+	}
+	password := generate.NewPassword()
+	db, err := getDBConnection(dt, primaryDB)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close() // nolint: errcheck
+	if _, err = db.Exec(
+		fmt.Sprintf("alter role %s with password '%s'", bd.LoginName, password),
+	); err != nil {
+		return nil, fmt.Errorf(
+			`error changing password of role "%s": %s`,
+			bd.LoginName,
+			err,
+		)
+	}
+	return &postgresqlBindingDetails{
+		LoginName: bd.LoginName,
+		Password:  password,
+	}, nil
+}
@@ -13,7 +13,7 @@ type ProvisioningParameters struct {
 type postgresqlInstanceDetails struct {
 	ARMDeploymentName          string `json:"armDeployment"`
 	ServerName                 string `json:"server"`
-	AdministratorLoginPassword string `json:"administratorLoginPassword"`
+	AdministratorLoginPassword string `json:"administratorLoginPassword" secret:"true"` // nolint: lll
 	DatabaseName               string `json:"database"`
 	FullyQualifiedDomainName   string `json:"fullyQualifiedDomainName"`
 	EnforceSSL                 bool   `json:"enforceSSL"`
@@ -29,7 +29,7 @@ type BindingParameters struct {
 
 type postgresqlBindingDetails struct {
 	LoginName string `json:"loginName"`
-	Password  string `json:"password"`
+	Password  string `json:"password" secret:"true"`
 }
 
 // Credentials encapsulates PostgreSQL-specific coonection details and
@@ -40,6 +40,10 @@ type Credentials struct {
 	Database string `json:"database"`
 	Username string `json:"username"`
 	Password string `json:"password"`
+	// SSLRequired indicates whether the server rejects connections that are
+	// not secured by SSL
+	SSLRequired bool   `json:"sslRequired"`
+	URI         string `json:"uri"`
 }
 
 func (
@@ -10,6 +10,10 @@ func (s *serviceManager) ValidateUpdatingParameters(
 	return nil
 }
 
+// GetUpdater returns an updater that redeploys the ARM template, which
+// restores any of the server's settings that have been changed out of band
 func (s *serviceManager) GetUpdater(service.Plan) (service.Updater, error) {
-	return service.NewUpdater()
+	return service.NewUpdater(
+		service.NewUpdatingStep("deployARMTemplate", s.deployARMTemplate),
+	)
 }
@@ -0,0 +1,41 @@
+package postgresqldb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// ValidateConnectivity connects to the new database using the administrator
+// credentials generated for it. Earlier provisioning steps already required
+// the broker to reach the server, so firewall rules never leave it
+// unreachable here.
+func (s *serviceManager) ValidateConnectivity(
+	ctx context.Context,
+	instance service.Instance,
+) (bool, error) {
+	dt, ok := instance.Details.(*postgresqlInstanceDetails)
+	if !ok {
+		return false, errors.New(
+			"error casting instance.Details as *postgresqlInstanceDetails",
+		)
+	}
+	db, err := getDBConnection(dt, dt.DatabaseName)
+	if err != nil {
+		return false, err
+	}
+	defer db.Close() // nolint: errcheck
+	if _, err := db.ExecContext(ctx, "select 1"); err != nil {
+		return false, service.NewCategorizedError(
+			service.ErrorCategoryTransient,
+			fmt.Errorf(
+				`error querying database "%s": %s`,
+				dt.DatabaseName,
+				err,
+			),
+		)
+	}
+	return true, nil
+}
@@ -0,0 +1,57 @@
+package powerbiembedded
+
+// nolint: lll
+var armTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "capacityName": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of the Power BI Embedded capacity"
+      }
+    },
+    "sku": {
+      "type": "string"
+    },
+    "administrators": {
+      "type": "array"
+    },
+    "tags": {
+      "type": "object"
+    }
+  },
+  "variables": {
+    "apiVersion": "2021-01-01"
+  },
+  "resources": [
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[parameters('capacityName')]",
+      "type": "Microsoft.PowerBIDedicated/capacities",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "sku": {
+        "name": "[parameters('sku')]",
+        "tier": "PBIE_Azure"
+      },
+      "properties": {
+        "administration": {
+          "members": "[parameters('administrators')]"
+        },
+        "mode": "Gen2"
+      }
+    }
+  ],
+  "outputs": {
+    "capacityId": {
+      "type": "string",
+      "value": "[resourceId('Microsoft.PowerBIDedicated/capacities', parameters('capacityName'))]"
+    }
+  }
+}
+`)
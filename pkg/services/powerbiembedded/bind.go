@@ -0,0 +1,69 @@
+package powerbiembedded
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/powerbiembedded"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) ValidateBindingParameters(
+	service.BindingParameters,
+) error {
+	// There are no parameters for binding to,
+	// so there is nothing to validate
+	return nil
+}
+
+// Bind is a no-op. Nothing needs to be created in order for an application to
+// know which capacity to assign its workspaces to.
+func (s *serviceManager) Bind(
+	service.Instance,
+	service.BindingParameters,
+) (service.BindingDetails, error) {
+	return &powerBIEmbeddedBindingDetails{}, nil
+}
+
+// GetCredentials retrieves the capacity's current state from Azure, so that it
+// is always up to date, even if the capacity was paused, resumed or scaled out
+// of band
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	_ service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*powerBIEmbeddedInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *powerBIEmbeddedInstanceDetails",
+		)
+	}
+	capacity, ok, err := s.powerBIEmbeddedManager.GetCapacity(
+		dt.CapacityName,
+		instance.ResourceGroup,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf(
+			`Power BI Embedded capacity "%s" does not exist`,
+			dt.CapacityName,
+		)
+	}
+	return getCredentials(dt, capacity), nil
+}
+
+func getCredentials(
+	dt *powerBIEmbeddedInstanceDetails,
+	capacity powerbiembedded.Capacity,
+) *Credentials {
+	return &Credentials{
+		CapacityID:        dt.CapacityID,
+		CapacityName:      dt.CapacityName,
+		SKU:               capacity.SKU,
+		State:             capacity.State,
+		ProvisioningState: capacity.ProvisioningState,
+		Administrators:    capacity.Administrators,
+	}
+}
@@ -0,0 +1,24 @@
+package powerbiembedded
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:          "db167cc6-6e41-44dd-a5f1-51bf31fcf85c",
+				Name:        "azure-power-bi-embedded",
+				Description: "Azure Power BI Embedded (Experimental)",
+				Bindable:    true,
+				Tags:        []string{"Azure", "Power BI", "Analytics", "Reporting"},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:          "3f093922-62bb-4644-9272-b8fac67b0c65",
+				Name:        "dedicated",
+				Description: "A dedicated capacity, billed per hour while it isn't paused",
+				Free:        false,
+			}),
+		),
+	}), nil
+}
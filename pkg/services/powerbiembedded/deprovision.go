@@ -0,0 +1,56 @@
+package powerbiembedded
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) GetDeprovisioner(
+	service.Plan,
+) (service.Deprovisioner, error) {
+	return service.NewDeprovisioner(
+		service.NewDeprovisioningStep("deleteARMDeployment", s.deleteARMDeployment),
+		service.NewDeprovisioningStep("deleteCapacity", s.deleteCapacity),
+	)
+}
+
+func (s *serviceManager) deleteARMDeployment(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*powerBIEmbeddedInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *powerBIEmbeddedInstanceDetails",
+		)
+	}
+	if err := s.armDeployer.Delete(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, fmt.Errorf("error deleting ARM deployment: %s", err)
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deleteCapacity(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*powerBIEmbeddedInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *powerBIEmbeddedInstanceDetails",
+		)
+	}
+	if err := s.powerBIEmbeddedManager.DeleteCapacity(
+		dt.CapacityName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
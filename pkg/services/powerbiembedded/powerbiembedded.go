@@ -0,0 +1,42 @@
+package powerbiembedded
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/powerbiembedded"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer            arm.Deployer
+	powerBIEmbeddedManager powerbiembedded.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of provisioning Power BI Embedded capacities
+func New(
+	armDeployer arm.Deployer,
+	powerBIEmbeddedManager powerbiembedded.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer:            armDeployer,
+			powerBIEmbeddedManager: powerBIEmbeddedManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "powerbiembedded"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{"Microsoft.PowerBIDedicated"}
+}
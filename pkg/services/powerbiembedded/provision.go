@@ -0,0 +1,280 @@
+package powerbiembedded
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	defaultSKU = "A1"
+
+	stateActive = "Active"
+	statePaused = "Paused"
+
+	// azureStateActive and azureStatePaused are the states Azure reports an
+	// active or paused capacity as being in
+	azureStateActive = "Succeeded"
+	azureStatePaused = "Paused"
+)
+
+var skus = []string{"A1", "A2", "A3", "A4", "A5", "A6", "A7", "A8"}
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*powerbiembedded.ProvisioningParameters",
+		)
+	}
+	if pp.SKU != "" && !isValidSKU(pp.SKU) {
+		return service.NewValidationError(
+			"sku",
+			fmt.Sprintf(
+				`invalid sku: "%s"; allowed values are: %s`,
+				pp.SKU,
+				strings.Join(skus, ", "),
+			),
+		)
+	}
+	if len(pp.Administrators) == 0 {
+		return service.NewValidationError(
+			"administrators",
+			"at least one administrator must be specified",
+		)
+	}
+	administrators := map[string]bool{}
+	for i, administrator := range pp.Administrators {
+		field := fmt.Sprintf("administrators[%d]", i)
+		if !isValidAdministrator(administrator) {
+			return service.NewValidationError(
+				field,
+				fmt.Sprintf(
+					`invalid administrator: "%s"; must be a user principal name or `+
+						"a service principal's object ID",
+					administrator,
+				),
+			)
+		}
+		// Azure treats administrators case-insensitively
+		key := strings.ToLower(administrator)
+		if administrators[key] {
+			return service.NewValidationError(
+				field,
+				fmt.Sprintf(`duplicate administrator: "%s"`, administrator),
+			)
+		}
+		administrators[key] = true
+	}
+	return validateState(pp.State)
+}
+
+func (s *serviceManager) ApplyProvisioningParametersDefaults(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*powerbiembedded.ProvisioningParameters",
+		)
+	}
+	pp.SKU = getSKU(pp)
+	if pp.State == "" {
+		pp.State = stateActive
+	}
+	return nil
+}
+
+// GetSKUUsages maps a capacity to its SKU. A paused capacity isn't billed, but
+// estimates assume the capacity is active.
+func (s *serviceManager) GetSKUUsages(
+	_ service.Plan,
+	provisioningParameters service.ProvisioningParameters,
+) ([]service.SKUUsage, error) {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting provisioningParameters as " +
+				"*powerbiembedded.ProvisioningParameters",
+		)
+	}
+	return []service.SKUUsage{
+		{
+			SKU:      getSKU(pp),
+			Quantity: 1,
+		},
+	}, nil
+}
+
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep("deployARMTemplate", s.deployARMTemplate),
+		service.NewProvisioningStep("pauseCapacity", s.pauseCapacity),
+	)
+}
+
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*powerBIEmbeddedInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *powerBIEmbeddedInstanceDetails",
+		)
+	}
+	dt.ARMDeploymentName = uuid.NewV4().String()
+	// Capacity names may contain only lowercase letters and numbers
+	dt.CapacityName = "pbie" +
+		strings.Replace(uuid.NewV4().String(), "-", "", -1)
+	return dt, nil
+}
+
+func (s *serviceManager) deployARMTemplate(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*powerBIEmbeddedInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *powerBIEmbeddedInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*powerbiembedded.ProvisioningParameters",
+		)
+	}
+	outputs, err := s.armDeployer.Deploy(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateBytes,
+		nil,
+		map[string]interface{}{
+			"capacityName":   dt.CapacityName,
+			"sku":            getSKU(pp),
+			"administrators": pp.Administrators,
+		},
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+	capacityID, ok := outputs["capacityId"].(string)
+	if !ok {
+		return nil, errors.New(
+			"error retrieving Power BI Embedded capacity resource id from " +
+				"deployment",
+		)
+	}
+	dt.CapacityID = capacityID
+	return dt, nil
+}
+
+// pauseCapacity pauses the newly created capacity if it is to be left paused.
+// A capacity is created active, so there's nothing to do otherwise.
+func (s *serviceManager) pauseCapacity(
+	ctx context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*powerBIEmbeddedInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *powerBIEmbeddedInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*powerbiembedded.ProvisioningParameters",
+		)
+	}
+	if pp.State != statePaused {
+		return dt, nil
+	}
+	// Pausing a capacity that Azure doesn't yet report as existing would fail
+	found, err := az.ReadWithConsistencyRetry(ctx, func() (bool, error) {
+		_, ok, err := s.powerBIEmbeddedManager.GetCapacity(
+			dt.CapacityName,
+			instance.ResourceGroup,
+		)
+		return ok, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf(
+			`Power BI Embedded capacity "%s" does not exist`,
+			dt.CapacityName,
+		)
+	}
+	if err := s.powerBIEmbeddedManager.SuspendCapacity(
+		dt.CapacityName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+func getSKU(pp *ProvisioningParameters) string {
+	if pp.SKU == "" {
+		return defaultSKU
+	}
+	return pp.SKU
+}
+
+func isValidSKU(sku string) bool {
+	for _, s := range skus {
+		if sku == s {
+			return true
+		}
+	}
+	return false
+}
+
+// isValidAdministrator verifies that an administrator at least looks like a
+// user principal name or an object ID. Whether the user or service principal
+// exists is verified by Azure when the capacity is deployed.
+func isValidAdministrator(administrator string) bool {
+	if _, err := uuid.FromString(administrator); err == nil {
+		return true
+	}
+	at := strings.Index(administrator, "@")
+	return at > 0 &&
+		at < len(administrator)-1 &&
+		strings.Count(administrator, "@") == 1 &&
+		!strings.ContainsAny(administrator, " \t\n")
+}
+
+func validateState(state string) error {
+	if state != "" && state != stateActive && state != statePaused {
+		return service.NewValidationError(
+			"state",
+			fmt.Sprintf(
+				`invalid state: "%s"; allowed values are: %s, %s`,
+				state,
+				stateActive,
+				statePaused,
+			),
+		)
+	}
+	return nil
+}
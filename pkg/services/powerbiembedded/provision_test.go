@@ -0,0 +1,91 @@
+package powerbiembedded
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateProvisioningParametersWithValidCapacity(t *testing.T) {
+	m := &module{}
+	err := m.serviceManager.ValidateProvisioningParameters(
+		getTestProvisioningParameters(),
+	)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithSKU(t *testing.T) {
+	m := &module{}
+	pp := getTestProvisioningParameters()
+	for _, sku := range skus {
+		pp.SKU = sku
+		err := m.serviceManager.ValidateProvisioningParameters(pp)
+		assert.Nil(t, err, "sku: %s", sku)
+	}
+	for _, sku := range []string{"a1", "A0", "A9", "P1", "EM1"} {
+		pp.SKU = sku
+		err := m.serviceManager.ValidateProvisioningParameters(pp)
+		assert.NotNil(t, err, "sku: %s", sku)
+	}
+}
+
+func TestValidateProvisioningParametersWithoutAdministrators(t *testing.T) {
+	m := &module{}
+	pp := getTestProvisioningParameters()
+	pp.Administrators = nil
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidAdministrators(t *testing.T) {
+	m := &module{}
+	pp := getTestProvisioningParameters()
+	for _, administrator := range []string{
+		"",
+		"jdoe",
+		"@contoso.com",
+		"jdoe@",
+		"j doe@contoso.com",
+		"jdoe@contoso@contoso.com",
+	} {
+		pp.Administrators = []string{administrator}
+		err := m.serviceManager.ValidateProvisioningParameters(pp)
+		assert.NotNil(t, err, "administrator: %s", administrator)
+	}
+}
+
+func TestValidateProvisioningParametersWithDuplicateAdministrators(
+	t *testing.T,
+) {
+	m := &module{}
+	pp := getTestProvisioningParameters()
+	pp.Administrators = append(pp.Administrators, "JDoe@Contoso.com")
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidState(t *testing.T) {
+	m := &module{}
+	pp := getTestProvisioningParameters()
+	pp.State = "Suspended"
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+}
+
+func TestApplyProvisioningParametersDefaults(t *testing.T) {
+	m := &module{}
+	pp := getTestProvisioningParameters()
+	err := m.serviceManager.ApplyProvisioningParametersDefaults(pp)
+	assert.Nil(t, err)
+	assert.Equal(t, defaultSKU, pp.SKU)
+	assert.Equal(t, stateActive, pp.State)
+}
+
+func getTestProvisioningParameters() *ProvisioningParameters {
+	return &ProvisioningParameters{
+		Administrators: []string{
+			"jdoe@contoso.com",
+			"5f1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d",
+		},
+	}
+}
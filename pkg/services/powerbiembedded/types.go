@@ -0,0 +1,75 @@
+package powerbiembedded
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// ProvisioningParameters encapsulates Power BI Embedded-specific provisioning
+// options
+type ProvisioningParameters struct {
+	// SKU is one of the A SKUs, "A1" through "A8"
+	SKU string `json:"sku"`
+	// Administrators are the user principal names (e.g. "jdoe@contoso.com") of
+	// users, or the object IDs of service principals, that administer the
+	// capacity
+	Administrators []string `json:"administrators"`
+	// State is the state the capacity is to be left in once provisioned: either
+	// "Active" or "Paused"
+	State string `json:"state"`
+}
+
+type powerBIEmbeddedInstanceDetails struct {
+	ARMDeploymentName string `json:"armDeployment"`
+	CapacityName      string `json:"capacityName"`
+	CapacityID        string `json:"capacityId"`
+}
+
+// UpdatingParameters encapsulates Power BI Embedded-specific updating options
+type UpdatingParameters struct {
+	// State is the state the capacity is to be left in once updated: either
+	// "Active" or "Paused". If unspecified, the capacity's state is unchanged.
+	State string `json:"state"`
+}
+
+// BindingParameters encapsulates Power BI Embedded-specific binding options
+type BindingParameters struct {
+}
+
+type powerBIEmbeddedBindingDetails struct {
+}
+
+// Credentials encapsulates the status of a Power BI Embedded capacity. No
+// secrets are involved; workspaces are assigned to a capacity, and content is
+// embedded, by way of the Power BI service and its own authentication.
+type Credentials struct {
+	CapacityID        string   `json:"capacityId"`
+	CapacityName      string   `json:"capacityName"`
+	SKU               string   `json:"sku"`
+	State             string   `json:"state"`
+	ProvisioningState string   `json:"provisioningState"`
+	Administrators    []string `json:"administrators"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &powerBIEmbeddedInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &powerBIEmbeddedBindingDetails{}
+}
@@ -0,0 +1,90 @@
+package powerbiembedded
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) ValidateUpdatingParameters(
+	updatingParameters service.UpdatingParameters,
+) error {
+	up, ok := updatingParameters.(*UpdatingParameters)
+	if !ok {
+		return errors.New(
+			"error casting updatingParameters as " +
+				"*powerbiembedded.UpdatingParameters",
+		)
+	}
+	return validateState(up.State)
+}
+
+func (s *serviceManager) GetUpdater(service.Plan) (service.Updater, error) {
+	return service.NewUpdater(
+		service.NewUpdatingStep("setCapacityState", s.setCapacityState),
+	)
+}
+
+// setCapacityState pauses or resumes the capacity, as requested. Azure refuses
+// to pause a capacity that is already paused, or resume one that is already
+// active, so the capacity's current state is checked first.
+func (s *serviceManager) setCapacityState(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*powerBIEmbeddedInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *powerBIEmbeddedInstanceDetails",
+		)
+	}
+	up, ok := instance.UpdatingParameters.(*UpdatingParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.UpdatingParameters as " +
+				"*powerbiembedded.UpdatingParameters",
+		)
+	}
+	if up.State == "" {
+		return dt, nil
+	}
+	capacity, ok, err := s.powerBIEmbeddedManager.GetCapacity(
+		dt.CapacityName,
+		instance.ResourceGroup,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf(
+			`Power BI Embedded capacity "%s" does not exist`,
+			dt.CapacityName,
+		)
+	}
+	switch {
+	case capacity.State != azureStateActive && capacity.State != azureStatePaused:
+		return nil, fmt.Errorf(
+			`Power BI Embedded capacity "%s" can't be paused or resumed while its `+
+				`state is "%s"`,
+			dt.CapacityName,
+			capacity.State,
+		)
+	case up.State == stateActive && capacity.State == azureStatePaused:
+		if err := s.powerBIEmbeddedManager.ResumeCapacity(
+			dt.CapacityName,
+			instance.ResourceGroup,
+		); err != nil {
+			return nil, err
+		}
+	case up.State == statePaused && capacity.State == azureStateActive:
+		if err := s.powerBIEmbeddedManager.SuspendCapacity(
+			dt.CapacityName,
+			instance.ResourceGroup,
+		); err != nil {
+			return nil, err
+		}
+	}
+	return dt, nil
+}
@@ -0,0 +1,93 @@
+package powerbiembedded
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/powerbiembedded"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakePowerBIEmbeddedManager struct {
+	powerbiembedded.Manager
+	state   string
+	actions []string
+}
+
+func (f *fakePowerBIEmbeddedManager) GetCapacity(
+	string,
+	string,
+) (powerbiembedded.Capacity, bool, error) {
+	return powerbiembedded.Capacity{State: f.state}, true, nil
+}
+
+func (f *fakePowerBIEmbeddedManager) SuspendCapacity(string, string) error {
+	f.actions = append(f.actions, "suspend")
+	return nil
+}
+
+func (f *fakePowerBIEmbeddedManager) ResumeCapacity(string, string) error {
+	f.actions = append(f.actions, "resume")
+	return nil
+}
+
+func TestValidateUpdatingParameters(t *testing.T) {
+	m := &module{}
+	for _, state := range []string{"", stateActive, statePaused} {
+		err := m.serviceManager.ValidateUpdatingParameters(
+			&UpdatingParameters{
+				State: state,
+			},
+		)
+		assert.Nil(t, err, "state: %s", state)
+	}
+	err := m.serviceManager.ValidateUpdatingParameters(
+		&UpdatingParameters{
+			State: "Suspended",
+		},
+	)
+	assert.NotNil(t, err)
+}
+
+func TestSetCapacityState(t *testing.T) {
+	testCases := []struct {
+		requested string
+		current   string
+		actions   []string
+		fails     bool
+	}{
+		{requested: statePaused, current: azureStateActive, actions: []string{"suspend"}}, // nolint: lll
+		{requested: stateActive, current: azureStatePaused, actions: []string{"resume"}},  // nolint: lll
+		{requested: statePaused, current: azureStatePaused},
+		{requested: stateActive, current: azureStateActive},
+		{requested: "", current: "Resuming"},
+		{requested: statePaused, current: "Resuming", fails: true},
+	}
+	for _, testCase := range testCases {
+		manager := &fakePowerBIEmbeddedManager{state: testCase.current}
+		s := &serviceManager{powerBIEmbeddedManager: manager}
+		_, err := s.setCapacityState(
+			context.Background(),
+			service.Instance{
+				Details: &powerBIEmbeddedInstanceDetails{},
+				UpdatingParameters: &UpdatingParameters{
+					State: testCase.requested,
+				},
+			},
+		)
+		if testCase.fails {
+			assert.NotNil(t, err)
+		} else {
+			assert.Nil(t, err)
+		}
+		assert.Equal(
+			t,
+			testCase.actions,
+			manager.actions,
+			"requested: %s, current: %s",
+			testCase.requested,
+			testCase.current,
+		)
+	}
+}
@@ -0,0 +1,105 @@
+package quantum
+
+// nolint: lll
+var armTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "workspaceName": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of the Quantum workspace"
+      }
+    },
+    "providers": {
+      "type": "array"
+    },
+    {{- if .createStorageAccount }}
+    "storageAccountName": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of the storage account to create for the Quantum workspace"
+      }
+    },
+    {{- else }}
+    "storageAccountId": {
+      "type": "string",
+      "metadata": {
+        "description": "Resource ID of the existing storage account to use"
+      }
+    },
+    {{- end }}
+    "tags": {
+      "type": "object"
+    }
+  },
+  "variables": {
+    "apiVersion": "2022-01-10-preview",
+    {{- if .createStorageAccount }}
+    "storageAccountId": "[resourceId('Microsoft.Storage/storageAccounts', parameters('storageAccountName'))]"
+    {{- else }}
+    "storageAccountId": "[parameters('storageAccountId')]"
+    {{- end }}
+  },
+  "resources": [
+    {{- if .createStorageAccount }}
+    {
+      "apiVersion": "2021-09-01",
+      "name": "[parameters('storageAccountName')]",
+      "type": "Microsoft.Storage/storageAccounts",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "kind": "StorageV2",
+      "sku": {
+        "name": "Standard_LRS"
+      },
+      "properties": {
+        "minimumTlsVersion": "TLS1_2",
+        "supportsHttpsTrafficOnly": true
+      }
+    },
+    {{- end }}
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[parameters('workspaceName')]",
+      "type": "Microsoft.Quantum/workspaces",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      {{- if .createStorageAccount }}
+      "dependsOn": [
+        "[variables('storageAccountId')]"
+      ],
+      {{- end }}
+      "identity": {
+        "type": "SystemAssigned"
+      },
+      "properties": {
+        "providers": "[parameters('providers')]",
+        "storageAccount": "[variables('storageAccountId')]"
+      }
+    }
+  ],
+  "outputs": {
+    "workspaceId": {
+      "type": "string",
+      "value": "[resourceId('Microsoft.Quantum/workspaces', parameters('workspaceName'))]"
+    },
+    "endpoint": {
+      "type": "string",
+      "value": "[reference(parameters('workspaceName')).endpointUri]"
+    },
+    "principalId": {
+      "type": "string",
+      "value": "[reference(parameters('workspaceName'), variables('apiVersion'), 'Full').identity.principalId]"
+    },
+    "storageAccountId": {
+      "type": "string",
+      "value": "[variables('storageAccountId')]"
+    }
+  }
+}
+`)
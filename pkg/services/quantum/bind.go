@@ -0,0 +1,163 @@
+package quantum
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const defaultRole = "Contributor"
+
+// roleDefinitionIDs maps the names of the built-in roles that a binding may
+// assign to their role definition IDs. Contributors may submit jobs; readers
+// may only view them.
+var roleDefinitionIDs = map[string]string{
+	"Contributor": "b24988ac-6180-42a0-ab88-20f7382dd24c",
+	"Reader":      "acdd72a7-3385-48ef-bd42-f606fba81ae7",
+}
+
+var objectIDRegex = regexp.MustCompile(
+	`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-` +
+		`[0-9a-fA-F]{12}$`,
+)
+
+func (s *serviceManager) ValidateBindingParameters(
+	bindingParameters service.BindingParameters,
+) error {
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return errors.New(
+			"error casting bindingParameters as *quantum.BindingParameters",
+		)
+	}
+	if !objectIDRegex.MatchString(bp.PrincipalID) {
+		return service.NewValidationError(
+			"principalId",
+			fmt.Sprintf(`invalid principalId: "%s"`, bp.PrincipalID),
+		)
+	}
+	if _, ok := roleDefinitionIDs[bp.Role]; bp.Role != "" && !ok {
+		return service.NewValidationError(
+			"role",
+			fmt.Sprintf(
+				`invalid role: "%s"; allowed values are: %s`,
+				bp.Role,
+				strings.Join(getRoleNames(), ", "),
+			),
+		)
+	}
+	return nil
+}
+
+// Bind assigns the requested role to the principal named in the binding
+// parameters, scoped to the workspace alone. The principal authenticates with
+// its own credentials, so the broker issues none.
+func (s *serviceManager) Bind(
+	instance service.Instance,
+	bindingParameters service.BindingParameters,
+) (service.BindingDetails, error) {
+	dt, ok := instance.Details.(*quantumInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *quantumInstanceDetails",
+		)
+	}
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting bindingParameters as *quantum.BindingParameters",
+		)
+	}
+	bd := &quantumBindingDetails{
+		PrincipalID:        bp.PrincipalID,
+		Role:               bp.Role,
+		RoleAssignmentName: uuid.NewV4().String(),
+	}
+	if bd.Role == "" {
+		bd.Role = defaultRole
+	}
+	if err := s.quantumManager.CreateRoleAssignment(
+		dt.WorkspaceID,
+		bd.RoleAssignmentName,
+		roleDefinitionIDs[bd.Role],
+		bd.PrincipalID,
+	); err != nil {
+		return nil, err
+	}
+	return bd, nil
+}
+
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	binding service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*quantumInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *quantumInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*quantum.ProvisioningParameters",
+		)
+	}
+	bd, ok := binding.Details.(*quantumBindingDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting binding.Details as *quantumBindingDetails",
+		)
+	}
+	return &Credentials{
+		WorkspaceName:  dt.WorkspaceName,
+		SubscriptionID: getSubscriptionID(dt.WorkspaceID),
+		ResourceGroup:  instance.ResourceGroup,
+		Location:       instance.Location,
+		TenantID:       s.quantumManager.GetTenantID(),
+		Endpoint:       dt.Endpoint,
+		Targets:        getTargets(pp),
+		Scope:          dt.WorkspaceID,
+		PrincipalID:    bd.PrincipalID,
+		Role:           bd.Role,
+		RoleAssignmentID: fmt.Sprintf(
+			"%s/providers/Microsoft.Authorization/roleAssignments/%s",
+			dt.WorkspaceID,
+			bd.RoleAssignmentName,
+		),
+	}, nil
+}
+
+// getTargets returns the targets of all the workspace's providers
+func getTargets(pp *ProvisioningParameters) []string {
+	targets := []string{}
+	for _, provider := range pp.Providers {
+		targets = append(targets, provider.Targets...)
+	}
+	return targets
+}
+
+func getRoleNames() []string {
+	roles := make([]string, 0, len(roleDefinitionIDs))
+	for role := range roleDefinitionIDs {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// getSubscriptionID returns the subscription that the resource with the given
+// ID belongs to
+func getSubscriptionID(resourceID string) string {
+	parts := strings.Split(resourceID, "/")
+	if len(parts) < 3 || !strings.EqualFold(parts[1], "subscriptions") {
+		return ""
+	}
+	return parts[2]
+}
@@ -0,0 +1,42 @@
+package quantum
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateBindingParameters(t *testing.T) {
+	m := &module{}
+	bp := &BindingParameters{}
+	err := m.serviceManager.ValidateBindingParameters(bp)
+	assert.NotNil(t, err)
+	bp.PrincipalID = "3f7b2c91-8e4d-4a06-b5c1-9d2e6f0a7b38"
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.Nil(t, err)
+	bp.Role = "Owner"
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.NotNil(t, err)
+	bp.Role = "Reader"
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.Nil(t, err)
+}
+
+func TestGetTargets(t *testing.T) {
+	pp := &ProvisioningParameters{
+		Providers: []Provider{
+			{ID: "ionq", Targets: []string{"ionq.simulator"}},
+			{ID: "rigetti", Targets: []string{"rigetti.sim.qvm"}},
+		},
+	}
+	assert.Equal(
+		t,
+		[]string{"ionq.simulator", "rigetti.sim.qvm"},
+		getTargets(pp),
+	)
+}
+
+func TestGetSubscriptionID(t *testing.T) {
+	assert.Equal(t, "sub", getSubscriptionID(testStorageAccountID))
+	assert.Equal(t, "", getSubscriptionID("bogus"))
+}
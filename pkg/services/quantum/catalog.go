@@ -0,0 +1,38 @@
+package quantum
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:          "8d4f6a2e-3c71-4b95-a0e8-6f1b2d7c9e54",
+				Name:        "azure-quantum",
+				Description: "Azure Quantum (Experimental)",
+				Bindable:    true,
+				Tags:        []string{"Azure", "Quantum", "Quantum Computing"},
+				// Azure Quantum workspaces are only offered in some regions
+				Locations: []string{
+					"eastus",
+					"japaneast",
+					"japanwest",
+					"northeurope",
+					"uksouth",
+					"ukwest",
+					"westcentralus",
+					"westeurope",
+					"westus",
+					"westus2",
+				},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:   "f3b6c8d1-7e29-4a5f-b4c0-2d9e8a1f6b73",
+				Name: "standard",
+				Description: "A workspace whose jobs are billed by each provider " +
+					"according to the provider's plan",
+				Free: false,
+			}),
+		),
+	}), nil
+}
@@ -0,0 +1,114 @@
+package quantum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) GetDeprovisioner(
+	service.Plan,
+) (service.Deprovisioner, error) {
+	return service.NewDeprovisioner(
+		service.NewDeprovisioningStep("deleteARMDeployment", s.deleteARMDeployment),
+		service.NewDeprovisioningStep("deleteWorkspace", s.deleteWorkspace),
+		service.NewDeprovisioningStep(
+			"deleteStorageAccountRoleAssignment",
+			s.deleteStorageAccountRoleAssignment,
+		),
+		service.NewDeprovisioningStep(
+			"deleteStorageAccount",
+			s.deleteStorageAccount,
+		),
+	)
+}
+
+func (s *serviceManager) deleteARMDeployment(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*quantumInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *quantumInstanceDetails",
+		)
+	}
+	if err := s.armDeployer.Delete(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, fmt.Errorf("error deleting ARM deployment: %s", err)
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deleteWorkspace(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*quantumInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *quantumInstanceDetails",
+		)
+	}
+	if err := s.quantumManager.DeleteWorkspace(
+		dt.WorkspaceName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+// deleteStorageAccountRoleAssignment revokes the deleted workspace's access to
+// its storage account, which would otherwise outlive the workspace if the
+// storage account was named in the provisioning parameters
+func (s *serviceManager) deleteStorageAccountRoleAssignment(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*quantumInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *quantumInstanceDetails",
+		)
+	}
+	if dt.StorageAccountID == "" {
+		return dt, nil
+	}
+	if err := s.quantumManager.DeleteRoleAssignment(
+		dt.StorageAccountID,
+		dt.StorageRoleAssignmentName,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+// deleteStorageAccount deletes the storage account that the broker created for
+// the workspace, if it created one. A storage account that was named in the
+// provisioning parameters belongs to someone else and is never deleted.
+func (s *serviceManager) deleteStorageAccount(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*quantumInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *quantumInstanceDetails",
+		)
+	}
+	if dt.StorageAccountName == "" {
+		return dt, nil
+	}
+	if err := s.storageManager.DeleteStorageAccount(
+		dt.StorageAccountName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
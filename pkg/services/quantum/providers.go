@@ -0,0 +1,83 @@
+package quantum
+
+// providerInfo describes what a quantum computing provider offers through
+// Azure Quantum
+type providerInfo struct {
+	// skus are the provider's billing plans, the first of which is used if
+	// none is specified
+	skus []string
+	// targets are the provider's targets, all of which are offered if none
+	// are specified
+	targets []string
+	// locations are the regions in which the provider is offered, if it isn't
+	// offered everywhere Azure Quantum workspaces are
+	locations []string
+}
+
+// defaultProviderID identifies the provider a workspace is given if none is
+// specified. Its resource estimator is free of charge.
+const defaultProviderID = "microsoft-qc"
+
+var providers = map[string]providerInfo{
+	"microsoft-qc": {
+		skus:    []string{"learn-and-develop"},
+		targets: []string{"microsoft.estimator"},
+	},
+	"ionq": {
+		skus: []string{"pay-as-you-go-cred", "committed-subscription-2"},
+		targets: []string{
+			"ionq.simulator",
+			"ionq.qpu.aria-1",
+			"ionq.qpu.aria-2",
+		},
+	},
+	"quantinuum": {
+		skus: []string{"credits1", "premium1"},
+		targets: []string{
+			"quantinuum.sim.h1-1sc",
+			"quantinuum.sim.h1-1e",
+			"quantinuum.qpu.h1-1",
+		},
+	},
+	"rigetti": {
+		skus:    []string{"azure-quantum-credits"},
+		targets: []string{"rigetti.sim.qvm", "rigetti.qpu.ankaa-3"},
+		locations: []string{
+			"eastus",
+			"northeurope",
+			"uksouth",
+			"ukwest",
+			"westcentralus",
+			"westeurope",
+			"westus",
+			"westus2",
+		},
+	},
+	"pasqal": {
+		skus:    []string{"azure-quantum-credits"},
+		targets: []string{"pasqal.sim.emu-tn", "pasqal.qpu.fresnel"},
+		locations: []string{
+			"eastus",
+			"northeurope",
+			"uksouth",
+			"westeurope",
+			"westus",
+		},
+	},
+}
+
+func (p providerInfo) isOfferedIn(location string) bool {
+	if len(p.locations) == 0 {
+		return true
+	}
+	return containsString(p.locations, location)
+}
+
+func containsString(strs []string, str string) bool {
+	for _, s := range strs {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
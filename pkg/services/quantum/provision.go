@@ -0,0 +1,425 @@
+package quantum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+// storageAccountIDRegex matches the resource IDs of storage accounts and
+// captures their subscription, resource group and name
+var storageAccountIDRegex = regexp.MustCompile(
+	`(?i)^/subscriptions/([^/]+)/resourceGroups/([^/]+)/providers/` +
+		`Microsoft\.Storage/storageAccounts/([^/]+)$`,
+)
+
+// storageAccountKinds are the kinds of storage account that a Quantum
+// workspace can keep job data in
+var storageAccountKinds = []string{"Storage", "StorageV2"}
+
+// storageContributorRoleDefinitionID identifies the built-in Contributor role,
+// which a workspace's identity must hold on its storage account
+const storageContributorRoleDefinitionID = "b24988ac-6180-42a0-ab88-20f7382dd24c" // nolint: lll
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*quantum.ProvisioningParameters",
+		)
+	}
+	if pp.StorageAccountID != "" &&
+		!storageAccountIDRegex.MatchString(pp.StorageAccountID) {
+		return service.NewValidationError(
+			"storageAccountId",
+			fmt.Sprintf(
+				`invalid storage account resource id: "%s"`,
+				pp.StorageAccountID,
+			),
+		)
+	}
+	providerIDs := map[string]bool{}
+	for _, provider := range pp.Providers {
+		if err := validateProvider(provider); err != nil {
+			return err
+		}
+		if providerIDs[provider.ID] {
+			return service.NewValidationError(
+				"providers",
+				fmt.Sprintf(`provider "%s" is specified more than once`, provider.ID),
+			)
+		}
+		providerIDs[provider.ID] = true
+	}
+	return nil
+}
+
+func validateProvider(provider Provider) error {
+	info, ok := providers[provider.ID]
+	if !ok {
+		return service.NewValidationError(
+			"providers",
+			fmt.Sprintf(
+				`invalid provider id: "%s"; allowed values are: %s`,
+				provider.ID,
+				strings.Join(getProviderIDs(), ", "),
+			),
+		)
+	}
+	if provider.SKU != "" && !containsString(info.skus, provider.SKU) {
+		return service.NewValidationError(
+			"providers",
+			fmt.Sprintf(
+				`invalid sku "%s" for provider "%s"; allowed values are: %s`,
+				provider.SKU,
+				provider.ID,
+				strings.Join(info.skus, ", "),
+			),
+		)
+	}
+	targets := map[string]bool{}
+	for _, target := range provider.Targets {
+		if !containsString(info.targets, target) {
+			return service.NewValidationError(
+				"providers",
+				fmt.Sprintf(
+					`invalid target "%s" for provider "%s"; allowed values are: %s`,
+					target,
+					provider.ID,
+					strings.Join(info.targets, ", "),
+				),
+			)
+		}
+		if targets[target] {
+			return service.NewValidationError(
+				"providers",
+				fmt.Sprintf(`target "%s" is specified more than once`, target),
+			)
+		}
+		targets[target] = true
+	}
+	return nil
+}
+
+// ValidateProvisioningParametersForLocation validates that each of the
+// workspace's providers is offered in the region it is to be provisioned in
+func (s *serviceManager) ValidateProvisioningParametersForLocation(
+	provisioningParameters service.ProvisioningParameters,
+	location string,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*quantum.ProvisioningParameters",
+		)
+	}
+	location = strings.ToLower(location)
+	for _, provider := range pp.Providers {
+		if !providers[provider.ID].isOfferedIn(location) {
+			return service.NewValidationError(
+				"providers",
+				fmt.Sprintf(
+					`provider "%s" is not available in location "%s"`,
+					provider.ID,
+					location,
+				),
+			)
+		}
+	}
+	return nil
+}
+
+func (s *serviceManager) ApplyProvisioningParametersDefaults(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*quantum.ProvisioningParameters",
+		)
+	}
+	if len(pp.Providers) == 0 {
+		pp.Providers = []Provider{{ID: defaultProviderID}}
+	}
+	for i := range pp.Providers {
+		info := providers[pp.Providers[i].ID]
+		if pp.Providers[i].SKU == "" {
+			pp.Providers[i].SKU = info.skus[0]
+		}
+		if len(pp.Providers[i].Targets) == 0 {
+			pp.Providers[i].Targets = append([]string{}, info.targets...)
+		}
+	}
+	return nil
+}
+
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep(
+			"validateStorageAccount",
+			s.validateStorageAccount,
+		),
+		service.NewProvisioningStep("deployARMTemplate", s.deployARMTemplate),
+		service.NewProvisioningStep(
+			"assignStorageAccountRole",
+			s.assignStorageAccountRole,
+		),
+	)
+}
+
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*quantumInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *quantumInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*quantum.ProvisioningParameters",
+		)
+	}
+	dt.ARMDeploymentName = uuid.NewV4().String()
+	dt.WorkspaceName = "qws-" + uuid.NewV4().String()
+	// Storage account names are limited to 24 lowercase letters and numbers
+	if pp.StorageAccountID == "" {
+		dt.StorageAccountName = "qsa" + getRandomName()[:21]
+	}
+	dt.StorageRoleAssignmentName = uuid.NewV4().String()
+	return dt, nil
+}
+
+// validateStorageAccount verifies that an existing storage account named in the
+// provisioning parameters exists, is of a kind a Quantum workspace can use, and
+// is in the same region as the workspace. There is nothing to verify if the
+// broker is to create the storage account itself.
+func (s *serviceManager) validateStorageAccount(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*quantum.ProvisioningParameters",
+		)
+	}
+	if pp.StorageAccountID == "" {
+		return instance.Details, nil
+	}
+	matches := storageAccountIDRegex.FindStringSubmatch(pp.StorageAccountID)
+	if matches == nil {
+		return nil, fmt.Errorf(
+			`invalid storage account resource id: "%s"`,
+			pp.StorageAccountID,
+		)
+	}
+	storageAccount, found, err := s.quantumManager.GetStorageAccount(
+		matches[1],
+		matches[2],
+		matches[3],
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf(
+			`storage account "%s" not found`,
+			pp.StorageAccountID,
+		)
+	}
+	if err := validateStorageAccountLinkage(
+		storageAccount.Kind,
+		storageAccount.Location,
+		instance.Location,
+	); err != nil {
+		return nil, fmt.Errorf(
+			`storage account "%s" cannot be used: %s`,
+			pp.StorageAccountID,
+			err,
+		)
+	}
+	return instance.Details, nil
+}
+
+func (s *serviceManager) deployARMTemplate(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*quantumInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *quantumInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*quantum.ProvisioningParameters",
+		)
+	}
+	goParams, armParams := buildARMTemplateParameters(dt, pp)
+	outputs, err := s.armDeployer.Deploy(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateBytes,
+		goParams,
+		armParams,
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+
+	workspaceID, ok := outputs["workspaceId"].(string)
+	if !ok {
+		return nil, errors.New(
+			"error retrieving Quantum workspace id from deployment",
+		)
+	}
+	dt.WorkspaceID = workspaceID
+
+	endpoint, ok := outputs["endpoint"].(string)
+	if !ok {
+		return nil, errors.New(
+			"error retrieving Quantum workspace endpoint from deployment",
+		)
+	}
+	dt.Endpoint = endpoint
+
+	principalID, ok := outputs["principalId"].(string)
+	if !ok {
+		return nil, errors.New(
+			"error retrieving Quantum workspace principal id from deployment",
+		)
+	}
+	dt.PrincipalID = principalID
+
+	storageAccountID, ok := outputs["storageAccountId"].(string)
+	if !ok {
+		return nil, errors.New("error retrieving storage account id from deployment")
+	}
+	dt.StorageAccountID = storageAccountID
+
+	return dt, nil
+}
+
+// assignStorageAccountRole lets the workspace's identity read and write job
+// data in the workspace's storage account
+func (s *serviceManager) assignStorageAccountRole(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*quantumInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *quantumInstanceDetails",
+		)
+	}
+	if err := s.quantumManager.CreateRoleAssignment(
+		dt.StorageAccountID,
+		dt.StorageRoleAssignmentName,
+		storageContributorRoleDefinitionID,
+		dt.PrincipalID,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+// buildARMTemplateParameters returns the Go template parameters and the ARM
+// template parameters used to deploy a Quantum workspace, along with a storage
+// account for it if the broker is to create one
+func buildARMTemplateParameters(
+	dt *quantumInstanceDetails,
+	pp *ProvisioningParameters,
+) (map[string]interface{}, map[string]interface{}) {
+	createStorageAccount := pp.StorageAccountID == ""
+	goParams := map[string]interface{}{
+		"createStorageAccount": createStorageAccount,
+	}
+	workspaceProviders := make([]map[string]interface{}, len(pp.Providers))
+	for i, provider := range pp.Providers {
+		workspaceProviders[i] = map[string]interface{}{
+			"providerId":  provider.ID,
+			"providerSku": provider.SKU,
+		}
+	}
+	armParams := map[string]interface{}{
+		"workspaceName": dt.WorkspaceName,
+		"providers":     workspaceProviders,
+	}
+	if createStorageAccount {
+		armParams["storageAccountName"] = dt.StorageAccountName
+	} else {
+		armParams["storageAccountId"] = pp.StorageAccountID
+	}
+	return goParams, armParams
+}
+
+// validateStorageAccountLinkage returns an error if a storage account of the
+// given kind, in the given location, can't hold the job data of a Quantum
+// workspace in the other given location
+func validateStorageAccountLinkage(
+	kind string,
+	storageAccountLocation string,
+	workspaceLocation string,
+) error {
+	if !containsString(storageAccountKinds, kind) {
+		return fmt.Errorf(
+			`storage account kind "%s" is not supported; supported kinds are: %s`,
+			kind,
+			strings.Join(storageAccountKinds, ", "),
+		)
+	}
+	if normalizeLocation(storageAccountLocation) !=
+		normalizeLocation(workspaceLocation) {
+		return fmt.Errorf(
+			`storage account is in location "%s", but the Quantum workspace is `+
+				`to be provisioned in location "%s"`,
+			storageAccountLocation,
+			workspaceLocation,
+		)
+	}
+	return nil
+}
+
+// normalizeLocation converts a location's display name (e.g. "East US") to
+// its name (e.g. "eastus"), so that locations can be compared
+func normalizeLocation(location string) string {
+	return strings.ToLower(strings.Replace(location, " ", "", -1))
+}
+
+func getProviderIDs() []string {
+	providerIDs := make([]string, 0, len(providers))
+	for providerID := range providers {
+		providerIDs = append(providerIDs, providerID)
+	}
+	sort.Strings(providerIDs)
+	return providerIDs
+}
+
+func getRandomName() string {
+	return strings.Replace(uuid.NewV4().String(), "-", "", -1)
+}
@@ -0,0 +1,164 @@
+package quantum
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testStorageAccountID = "/subscriptions/sub/resourceGroups/rg/" +
+	"providers/Microsoft.Storage/storageAccounts/sa"
+
+func TestValidateProvisioningParametersWithDefaults(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithStorageAccountID(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		StorageAccountID: "/subscriptions/sub/resourceGroups/rg/providers/" +
+			"Microsoft.KeyVault/vaults/kv",
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.StorageAccountID = testStorageAccountID
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersProviders(t *testing.T) {
+	m := &module{}
+	for name, tc := range map[string]struct {
+		providers []Provider
+		valid     bool
+	}{
+		"sku and targets": {
+			providers: []Provider{
+				{
+					ID:      "ionq",
+					SKU:     "pay-as-you-go-cred",
+					Targets: []string{"ionq.simulator"},
+				},
+			},
+			valid: true,
+		},
+		"unknown provider": {
+			providers: []Provider{{ID: "acme"}},
+		},
+		"unknown sku": {
+			providers: []Provider{{ID: "ionq", SKU: "free-forever"}},
+		},
+		"other provider's target": {
+			providers: []Provider{
+				{ID: "ionq", Targets: []string{"rigetti.sim.qvm"}},
+			},
+		},
+		"duplicate target": {
+			providers: []Provider{
+				{
+					ID:      "ionq",
+					Targets: []string{"ionq.simulator", "ionq.simulator"},
+				},
+			},
+		},
+		"duplicate provider": {
+			providers: []Provider{{ID: "ionq"}, {ID: "ionq"}},
+		},
+	} {
+		pp := &ProvisioningParameters{Providers: tc.providers}
+		err := m.serviceManager.ValidateProvisioningParameters(pp)
+		if tc.valid {
+			assert.Nil(t, err, name)
+		} else {
+			assert.NotNil(t, err, name)
+		}
+	}
+}
+
+func TestValidateProvisioningParametersForLocation(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		Providers: []Provider{{ID: "ionq"}, {ID: "pasqal"}},
+	}
+	err := m.serviceManager.ValidateProvisioningParametersForLocation(
+		pp,
+		"eastus",
+	)
+	assert.Nil(t, err)
+	err = m.serviceManager.ValidateProvisioningParametersForLocation(
+		pp,
+		"japaneast",
+	)
+	assert.NotNil(t, err)
+}
+
+func TestApplyProvisioningParametersDefaults(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{}
+	err := m.serviceManager.ApplyProvisioningParametersDefaults(pp)
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		[]Provider{
+			{
+				ID:      defaultProviderID,
+				SKU:     "learn-and-develop",
+				Targets: []string{"microsoft.estimator"},
+			},
+		},
+		pp.Providers,
+	)
+	pp = &ProvisioningParameters{
+		Providers: []Provider{
+			{ID: "rigetti", Targets: []string{"rigetti.sim.qvm"}},
+		},
+	}
+	err = m.serviceManager.ApplyProvisioningParametersDefaults(pp)
+	assert.Nil(t, err)
+	assert.Equal(t, "azure-quantum-credits", pp.Providers[0].SKU)
+	assert.Equal(t, []string{"rigetti.sim.qvm"}, pp.Providers[0].Targets)
+}
+
+func TestBuildARMTemplateParameters(t *testing.T) {
+	dt := &quantumInstanceDetails{
+		WorkspaceName:      "qws",
+		StorageAccountName: "qsa",
+	}
+	pp := &ProvisioningParameters{
+		Providers: []Provider{{ID: "ionq", SKU: "pay-as-you-go-cred"}},
+	}
+	goParams, armParams := buildARMTemplateParameters(dt, pp)
+	assert.Equal(t, true, goParams["createStorageAccount"])
+	assert.Equal(t, "qsa", armParams["storageAccountName"])
+	assert.NotContains(t, armParams, "storageAccountId")
+	assert.Equal(
+		t,
+		[]map[string]interface{}{
+			{
+				"providerId":  "ionq",
+				"providerSku": "pay-as-you-go-cred",
+			},
+		},
+		armParams["providers"],
+	)
+	pp.StorageAccountID = testStorageAccountID
+	goParams, armParams = buildARMTemplateParameters(
+		&quantumInstanceDetails{WorkspaceName: "qws"},
+		pp,
+	)
+	assert.Equal(t, false, goParams["createStorageAccount"])
+	assert.Equal(t, testStorageAccountID, armParams["storageAccountId"])
+	assert.NotContains(t, armParams, "storageAccountName")
+}
+
+func TestValidateStorageAccountLinkage(t *testing.T) {
+	err := validateStorageAccountLinkage("StorageV2", "East US", "eastus")
+	assert.Nil(t, err)
+	err = validateStorageAccountLinkage("BlobStorage", "eastus", "eastus")
+	assert.NotNil(t, err)
+	err = validateStorageAccountLinkage("StorageV2", "westus", "eastus")
+	assert.NotNil(t, err)
+}
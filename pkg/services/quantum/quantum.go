@@ -0,0 +1,50 @@
+package quantum
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/quantum"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/storage"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer    arm.Deployer
+	quantumManager quantum.Manager
+	storageManager storage.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of provisioning Azure Quantum workspaces
+func New(
+	armDeployer arm.Deployer,
+	quantumManager quantum.Manager,
+	storageManager storage.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer:    armDeployer,
+			quantumManager: quantumManager,
+			storageManager: storageManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "quantum"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{
+		"Microsoft.Quantum",
+		"Microsoft.Storage",
+		"Microsoft.Authorization",
+	}
+}
@@ -0,0 +1,101 @@
+package quantum
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// ProvisioningParameters encapsulates Azure Quantum-specific provisioning
+// options
+type ProvisioningParameters struct {
+	// StorageAccountID is the resource ID of an existing storage account in
+	// which the workspace keeps job data. If it is omitted, the broker creates a
+	// storage account for the workspace.
+	StorageAccountID string `json:"storageAccountId"`
+	// Providers are the quantum computing providers whose targets the
+	// workspace's jobs may run on
+	Providers []Provider `json:"providers"`
+}
+
+// Provider encapsulates the configuration of one of a workspace's providers
+type Provider struct {
+	ID string `json:"id"`
+	// SKU is the provider's billing plan
+	SKU string `json:"sku"`
+	// Targets are the provider's targets (e.g. simulators or QPUs) that are
+	// offered to bound applications
+	Targets []string `json:"targets"`
+}
+
+type quantumInstanceDetails struct {
+	ARMDeploymentName string `json:"armDeployment"`
+	WorkspaceName     string `json:"workspaceName"`
+	WorkspaceID       string `json:"workspaceId"`
+	// Endpoint is the URL to which jobs are submitted
+	Endpoint string `json:"endpoint"`
+	// PrincipalID is the object ID of the workspace's managed identity, with
+	// which it accesses its storage account
+	PrincipalID      string `json:"principalId"`
+	StorageAccountID string `json:"storageAccountId"`
+	// StorageAccountName is empty unless the broker created the storage account,
+	// in which case the broker also deletes it
+	StorageAccountName        string `json:"storageAccountName"`
+	StorageRoleAssignmentName string `json:"storageRoleAssignmentName"`
+}
+
+// UpdatingParameters encapsulates Azure Quantum-specific updating options
+type UpdatingParameters struct {
+}
+
+// BindingParameters encapsulates Azure Quantum-specific binding options
+type BindingParameters struct {
+	PrincipalID string `json:"principalId"`
+	Role        string `json:"role"`
+}
+
+type quantumBindingDetails struct {
+	PrincipalID        string `json:"principalId"`
+	Role               string `json:"role"`
+	RoleAssignmentName string `json:"roleAssignmentName"`
+}
+
+// Credentials encapsulates Azure Quantum-specific connection details
+type Credentials struct {
+	WorkspaceName  string `json:"workspaceName"`
+	SubscriptionID string `json:"subscriptionId"`
+	ResourceGroup  string `json:"resourceGroup"`
+	Location       string `json:"location"`
+	TenantID       string `json:"tenantId"`
+	Endpoint       string `json:"endpoint"`
+	// Targets are the IDs of the targets that jobs may be submitted to
+	Targets []string `json:"targets"`
+	// Scope is the resource ID of the workspace, which is also the scope at
+	// which the principal's role was assigned
+	Scope            string `json:"scope"`
+	PrincipalID      string `json:"principalId"`
+	Role             string `json:"role"`
+	RoleAssignmentID string `json:"roleAssignmentId"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &quantumInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &quantumBindingDetails{}
+}
@@ -0,0 +1,29 @@
+package quantum
+
+import (
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) Unbind(
+	instance service.Instance,
+	bindingDetails service.BindingDetails,
+) error {
+	dt, ok := instance.Details.(*quantumInstanceDetails)
+	if !ok {
+		return errors.New(
+			"error casting instance.Details as *quantumInstanceDetails",
+		)
+	}
+	bd, ok := bindingDetails.(*quantumBindingDetails)
+	if !ok {
+		return errors.New(
+			"error casting bindingDetails as *quantumBindingDetails",
+		)
+	}
+	return s.quantumManager.DeleteRoleAssignment(
+		dt.WorkspaceID,
+		bd.RoleAssignmentName,
+	)
+}
@@ -10,6 +10,7 @@ func (m *module) GetCatalog() (service.Catalog, error) {
 				Name:        "azure-rediscache",
 				Description: "Azure Redis Cache (Experimental)",
 				Bindable:    true,
+				Cloneable:   true,
 				Tags:        []string{"Azure", "Redis", "Cache", "Database"},
 			},
 			m.serviceManager,
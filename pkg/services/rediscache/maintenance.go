@@ -0,0 +1,90 @@
+package rediscache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// ValidateMaintenanceWindow rejects a maintenance window for any plan other
+// than premium; only Premium caches have patch schedules
+func (s *serviceManager) ValidateMaintenanceWindow(
+	plan service.Plan,
+	_ string,
+	_ service.MaintenanceWindow,
+) error {
+	if sku := plan.GetProperties().Extended["redisCacheSKU"]; sku != "Premium" {
+		return service.NewValidationError(
+			"maintenanceWindow",
+			fmt.Sprintf(
+				"a maintenance window can't be set for %s caches; only Premium "+
+					"caches support one",
+				sku,
+			),
+		)
+	}
+	return nil
+}
+
+// applyMaintenanceWindow sets the server's patch schedule to the maintenance
+// window, if any, requested when provisioning
+func (s *serviceManager) applyMaintenanceWindow(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*rediscache.ProvisioningParameters",
+		)
+	}
+	return s.setPatchSchedule(instance, pp.MaintenanceWindow)
+}
+
+// updateMaintenanceWindow sets the server's patch schedule to the maintenance
+// window, if any, requested when updating. If none was, the window applied
+// previously remains in effect.
+func (s *serviceManager) updateMaintenanceWindow(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	up, ok := instance.UpdatingParameters.(*UpdatingParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.UpdatingParameters as " +
+				"*rediscache.UpdatingParameters",
+		)
+	}
+	return s.setPatchSchedule(instance, up.MaintenanceWindow)
+}
+
+// setPatchSchedule sets the server's patch schedule to the given maintenance
+// window and records the window in the instance's details. Given no window,
+// it does nothing.
+func (s *serviceManager) setPatchSchedule(
+	instance service.Instance,
+	window *service.MaintenanceWindow,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*redisInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *redisInstanceDetails",
+		)
+	}
+	if window == nil {
+		return dt, nil
+	}
+	if err := s.redisManager.SetPatchSchedule(
+		dt.ServerName,
+		instance.ResourceGroup,
+		window.DayOfWeek,
+		window.StartHourUTC,
+	); err != nil {
+		return nil, err
+	}
+	dt.MaintenanceWindow = window
+	return dt, nil
+}
@@ -0,0 +1,25 @@
+package rediscache
+
+import (
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateMaintenanceWindow(t *testing.T) {
+	m := &module{serviceManager: &serviceManager{}}
+	catalog, err := m.GetCatalog()
+	assert.Nil(t, err)
+	svc, ok := catalog.GetService("0346088a-d4b2-4478-aa32-f18e295ec1d9")
+	assert.True(t, ok)
+	window := service.MaintenanceWindow{DayOfWeek: "Sunday", StartHourUTC: 3}
+	for _, plan := range svc.GetPlans() {
+		err := m.serviceManager.ValidateMaintenanceWindow(plan, "eastus", window)
+		if plan.GetName() == "premium" {
+			assert.Nil(t, err)
+		} else {
+			assert.NotNil(t, err, plan.GetName())
+		}
+	}
+}
@@ -3,17 +3,37 @@ package rediscache
 import "github.com/Azure/open-service-broker-azure/pkg/service"
 
 // ProvisioningParameters encapsulates Redis-specific provisioning options
-type ProvisioningParameters struct{}
+type ProvisioningParameters struct {
+	MaintenanceWindow *service.MaintenanceWindow `json:"maintenanceWindow,omitempty"` // nolint: lll
+}
+
+// GetMaintenanceWindow returns the maintenance window requested, if any
+func (
+	p *ProvisioningParameters,
+) GetMaintenanceWindow() *service.MaintenanceWindow {
+	return p.MaintenanceWindow
+}
 
 type redisInstanceDetails struct {
 	ARMDeploymentName        string `json:"armDeployment"`
 	ServerName               string `json:"server"`
-	PrimaryKey               string `json:"primaryKey"`
+	PrimaryKey               string `json:"primaryKey" secret:"true"`
 	FullyQualifiedDomainName string `json:"fullyQualifiedDomainName"`
+	// MaintenanceWindow is the maintenance window most recently applied to the
+	// server, if any
+	MaintenanceWindow *service.MaintenanceWindow `json:"maintenanceWindow,omitempty"` // nolint: lll
 }
 
 // UpdatingParameters encapsulates Redis-specific updating options
 type UpdatingParameters struct {
+	MaintenanceWindow *service.MaintenanceWindow `json:"maintenanceWindow,omitempty"` // nolint: lll
+}
+
+// GetMaintenanceWindow returns the maintenance window requested, if any
+func (
+	u *UpdatingParameters,
+) GetMaintenanceWindow() *service.MaintenanceWindow {
+	return u.MaintenanceWindow
 }
 
 // BindingParameters encapsulates Redis-specific binding options
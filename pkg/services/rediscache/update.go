@@ -11,5 +11,10 @@ func (s *serviceManager) ValidateUpdatingParameters(
 }
 
 func (s *serviceManager) GetUpdater(service.Plan) (service.Updater, error) {
-	return service.NewUpdater()
+	return service.NewUpdater(
+		service.NewUpdatingStep(
+			"updateMaintenanceWindow",
+			s.updateMaintenanceWindow,
+		),
+	)
 }
@@ -0,0 +1,40 @@
+package rediscache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/go-redis/redis"
+)
+
+// ValidateConnectivity pings the new cache using the same host, port, and key
+// that bindings hand out
+func (s *serviceManager) ValidateConnectivity(
+	_ context.Context,
+	instance service.Instance,
+) (bool, error) {
+	dt, ok := instance.Details.(*redisInstanceDetails)
+	if !ok {
+		return false, errors.New(
+			"error casting instance.Details as *redisInstanceDetails",
+		)
+	}
+	client := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:6379", dt.FullyQualifiedDomainName),
+		Password: dt.PrimaryKey,
+	})
+	defer client.Close() // nolint: errcheck
+	if err := client.Ping().Err(); err != nil {
+		return false, service.NewCategorizedError(
+			service.ErrorCategoryTransient,
+			fmt.Errorf(
+				`error pinging redis cache "%s": %s`,
+				dt.ServerName,
+				err,
+			),
+		)
+	}
+	return true, nil
+}
@@ -0,0 +1,94 @@
+package redisenterprise
+
+// nolint: lll
+var armTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "clusterName": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of the cluster, which is unique across Azure"
+      }
+    },
+    "skuName": {
+      "type": "string"
+    },
+    {{- if .capacity }}
+    "capacity": {
+      "type": "int"
+    },
+    {{- end }}
+    "clusteringPolicy": {
+      "type": "string",
+      "allowedValues": [
+        "EnterpriseCluster",
+        "OSSCluster"
+      ]
+    },
+    "evictionPolicy": {
+      "type": "string"
+    },
+    "modules": {
+      "type": "array"
+    },
+    "tags": {
+      "type": "object"
+    }
+  },
+  "variables": {
+    "apiVersion": "2024-10-01"
+  },
+  "resources": [
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[parameters('clusterName')]",
+      "type": "Microsoft.Cache/redisEnterprise",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "sku": {
+        "name": "[parameters('skuName')]"
+        {{- if .capacity }},
+        "capacity": "[parameters('capacity')]"
+        {{- end }}
+      },
+      "properties": {
+        "minimumTlsVersion": "1.2"
+      }
+    },
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[concat(parameters('clusterName'), '/', 'default')]",
+      "type": "Microsoft.Cache/redisEnterprise/databases",
+      "dependsOn": [
+        "[resourceId('Microsoft.Cache/redisEnterprise', parameters('clusterName'))]"
+      ],
+      "properties": {
+        "clientProtocol": "Encrypted",
+        "port": 10000,
+        "clusteringPolicy": "[parameters('clusteringPolicy')]",
+        "evictionPolicy": "[parameters('evictionPolicy')]",
+        "modules": "[parameters('modules')]"
+      }
+    }
+  ],
+  "outputs": {
+    "hostName": {
+      "type": "string",
+      "value": "[reference(resourceId('Microsoft.Cache/redisEnterprise', parameters('clusterName')), variables('apiVersion')).hostName]"
+    },
+    "primaryKey": {
+      "type": "string",
+      "value": "[listKeys(resourceId('Microsoft.Cache/redisEnterprise/databases', parameters('clusterName'), 'default'), variables('apiVersion')).primaryKey]"
+    },
+    "secondaryKey": {
+      "type": "string",
+      "value": "[listKeys(resourceId('Microsoft.Cache/redisEnterprise/databases', parameters('clusterName'), 'default'), variables('apiVersion')).secondaryKey]"
+    }
+  }
+}
+`)
@@ -0,0 +1,54 @@
+package redisenterprise
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) ValidateBindingParameters(
+	service.BindingParameters,
+) error {
+	// There are no parameters for binding to Azure Managed Redis, so there is
+	// nothing to validate
+	return nil
+}
+
+func (s *serviceManager) Bind(
+	service.Instance,
+	service.BindingParameters,
+) (service.BindingDetails, error) {
+	return &redisEnterpriseBindingDetails{}, nil
+}
+
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	_ service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*redisEnterpriseInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *redisEnterpriseInstanceDetails",
+		)
+	}
+	return &Credentials{
+		Host:             dt.HostName,
+		Port:             dt.Port,
+		Password:         dt.PrimaryKey,
+		ConnectionString: buildConnectionString(dt),
+	}, nil
+}
+
+// buildConnectionString returns a URI for connecting to the cluster's
+// database over TLS with its primary key. The database has no users, so the
+// URI has none either.
+func buildConnectionString(dt *redisEnterpriseInstanceDetails) string {
+	connectionURL := url.URL{
+		Scheme: "rediss",
+		User:   url.UserPassword("", dt.PrimaryKey),
+		Host:   fmt.Sprintf("%s:%d", dt.HostName, dt.Port),
+	}
+	return connectionURL.String()
+}
@@ -0,0 +1,22 @@
+package redisenterprise
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildConnectionString(t *testing.T) {
+	connectionString := buildConnectionString(
+		&redisEnterpriseInstanceDetails{
+			HostName:   "redis-fake.eastus.redis.azure.net",
+			Port:       databasePort,
+			PrimaryKey: "a+b/c=",
+		},
+	)
+	assert.Equal(
+		t,
+		"rediss://:a+b%2Fc=@redis-fake.eastus.redis.azure.net:10000",
+		connectionString,
+	)
+}
@@ -0,0 +1,32 @@
+package redisenterprise
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:   "e1f6e893-fa7c-4369-aec0-e229d925de37",
+				Name: "azure-managed-redis",
+				Description: "Azure Managed Redis (Experimental), with support for " +
+					"Redis modules",
+				Bindable: true,
+				Tags: []string{
+					"Azure",
+					"Redis",
+					"Cache",
+					"Redis Enterprise",
+					"Database",
+				},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:   "235b1d72-69a1-462c-b6b4-4c29c976367f",
+				Name: "managed",
+				Description: "Billed per cluster by SKU; Redis modules, the SKU and " +
+					"the clustering policy are chosen when provisioning",
+				Free: false,
+			}),
+		),
+	}), nil
+}
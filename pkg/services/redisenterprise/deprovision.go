@@ -0,0 +1,57 @@
+package redisenterprise
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) GetDeprovisioner(
+	service.Plan,
+) (service.Deprovisioner, error) {
+	return service.NewDeprovisioner(
+		service.NewDeprovisioningStep("deleteARMDeployment", s.deleteARMDeployment),
+		service.NewDeprovisioningStep("deleteCluster", s.deleteCluster),
+	)
+}
+
+func (s *serviceManager) deleteARMDeployment(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*redisEnterpriseInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *redisEnterpriseInstanceDetails",
+		)
+	}
+	if err := s.armDeployer.Delete(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, fmt.Errorf("error deleting ARM deployment: %s", err)
+	}
+	return dt, nil
+}
+
+// deleteCluster deletes the cluster, which deletes its database along with it
+func (s *serviceManager) deleteCluster(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*redisEnterpriseInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *redisEnterpriseInstanceDetails",
+		)
+	}
+	if err := s.clusterManager.DeleteCluster(
+		dt.ClusterName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
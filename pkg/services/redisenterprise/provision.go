@@ -0,0 +1,408 @@
+package redisenterprise
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	clusteringPolicyEnterprise = "EnterpriseCluster"
+	clusteringPolicyOSS        = "OSSCluster"
+	moduleRediSearch           = "RediSearch"
+	moduleRedisJSON            = "RedisJSON"
+	moduleRedisBloom           = "RedisBloom"
+	moduleRedisTimeSeries      = "RedisTimeSeries"
+	// databasePort is the port the cluster's database listens on, over TLS
+	databasePort = 10000
+)
+
+const (
+	defaultSKU              = "Balanced_B1"
+	defaultClusteringPolicy = clusteringPolicyEnterprise
+)
+
+// Families of SKU, which prefix the SKUs' names
+const (
+	familyEnterprise      = "Enterprise"
+	familyEnterpriseFlash = "EnterpriseFlash"
+	familyFlashOptimized  = "FlashOptimized"
+)
+
+var skus = []string{
+	"Balanced_B0",
+	"Balanced_B1",
+	"Balanced_B3",
+	"Balanced_B5",
+	"Balanced_B10",
+	"Balanced_B20",
+	"Balanced_B50",
+	"Balanced_B100",
+	"Balanced_B150",
+	"Balanced_B250",
+	"Balanced_B350",
+	"Balanced_B500",
+	"Balanced_B700",
+	"Balanced_B1000",
+	"MemoryOptimized_M10",
+	"MemoryOptimized_M20",
+	"MemoryOptimized_M50",
+	"MemoryOptimized_M100",
+	"MemoryOptimized_M150",
+	"MemoryOptimized_M250",
+	"MemoryOptimized_M350",
+	"MemoryOptimized_M500",
+	"MemoryOptimized_M700",
+	"MemoryOptimized_M1000",
+	"MemoryOptimized_M1500",
+	"MemoryOptimized_M2000",
+	"ComputeOptimized_X3",
+	"ComputeOptimized_X5",
+	"ComputeOptimized_X10",
+	"ComputeOptimized_X20",
+	"ComputeOptimized_X50",
+	"ComputeOptimized_X100",
+	"ComputeOptimized_X150",
+	"ComputeOptimized_X250",
+	"ComputeOptimized_X350",
+	"ComputeOptimized_X500",
+	"ComputeOptimized_X700",
+	"FlashOptimized_A250",
+	"FlashOptimized_A500",
+	"FlashOptimized_A700",
+	"FlashOptimized_A1000",
+	"FlashOptimized_A1500",
+	"FlashOptimized_A2000",
+	"FlashOptimized_A4500",
+	"Enterprise_E1",
+	"Enterprise_E5",
+	"Enterprise_E10",
+	"Enterprise_E20",
+	"Enterprise_E50",
+	"Enterprise_E100",
+	"Enterprise_E200",
+	"Enterprise_E400",
+	"EnterpriseFlash_F300",
+	"EnterpriseFlash_F700",
+	"EnterpriseFlash_F1500",
+}
+
+// capacities are keyed by the families of SKU that are deployed with a
+// capacity, and are the capacities, in nodes, that they allow. The first is
+// the default.
+var capacities = map[string][]int{
+	familyEnterprise:      {2, 4, 6, 8, 10},
+	familyEnterpriseFlash: {3, 9, 15, 21, 27},
+}
+
+var clusteringPolicies = []string{
+	clusteringPolicyEnterprise,
+	clusteringPolicyOSS,
+}
+
+var redisModules = []string{
+	moduleRediSearch,
+	moduleRedisJSON,
+	moduleRedisBloom,
+	moduleRedisTimeSeries,
+}
+
+// flashModules are the only Redis modules that SKUs backed by flash storage
+// support
+var flashModules = []string{moduleRediSearch, moduleRedisJSON}
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*redisenterprise.ProvisioningParameters",
+		)
+	}
+	if pp.SKU != "" && !contains(skus, pp.SKU) {
+		return service.NewValidationError(
+			"sku",
+			fmt.Sprintf(
+				`invalid sku: "%s"; allowed values are: %s`,
+				pp.SKU,
+				strings.Join(skus, ", "),
+			),
+		)
+	}
+	sku := getSKU(pp)
+	if err := validateCapacity(sku, pp.Capacity); err != nil {
+		return err
+	}
+	if pp.ClusteringPolicy != "" &&
+		!contains(clusteringPolicies, pp.ClusteringPolicy) {
+		return service.NewValidationError(
+			"clusteringPolicy",
+			fmt.Sprintf(
+				`invalid clusteringPolicy: "%s"; allowed values are: %s`,
+				pp.ClusteringPolicy,
+				strings.Join(clusteringPolicies, ", "),
+			),
+		)
+	}
+	return validateModules(sku, getClusteringPolicy(pp), pp.Modules)
+}
+
+func validateCapacity(sku string, capacity int) error {
+	if capacity == 0 {
+		return nil
+	}
+	allowed, ok := capacities[getFamily(sku)]
+	if !ok {
+		return service.NewValidationError(
+			"capacity",
+			fmt.Sprintf(`sku "%s" does not accept a capacity`, sku),
+		)
+	}
+	if !containsInt(allowed, capacity) {
+		return service.NewValidationError(
+			"capacity",
+			fmt.Sprintf(
+				`invalid capacity: %d; allowed values for sku "%s" are: %s`,
+				capacity,
+				sku,
+				joinInts(allowed),
+			),
+		)
+	}
+	return nil
+}
+
+// validateModules validates the requested Redis modules against those that
+// the given SKU and clustering policy support
+func validateModules(
+	sku string,
+	clusteringPolicy string,
+	modules []string,
+) error {
+	family := getFamily(sku)
+	flash := family == familyEnterpriseFlash || family == familyFlashOptimized
+	requested := map[string]bool{}
+	for i, module := range modules {
+		field := fmt.Sprintf("modules[%d]", i)
+		if !contains(redisModules, module) {
+			return service.NewValidationError(
+				field,
+				fmt.Sprintf(
+					`invalid module: "%s"; allowed values are: %s`,
+					module,
+					strings.Join(redisModules, ", "),
+				),
+			)
+		}
+		if requested[module] {
+			return service.NewValidationError(
+				field,
+				fmt.Sprintf(`module "%s" is requested more than once`, module),
+			)
+		}
+		requested[module] = true
+		if flash && !contains(flashModules, module) {
+			return service.NewValidationError(
+				field,
+				fmt.Sprintf(
+					`module "%s" is not supported by sku "%s"; flash SKUs only `+
+						"support: %s",
+					module,
+					sku,
+					strings.Join(flashModules, ", "),
+				),
+			)
+		}
+		if module == moduleRediSearch &&
+			clusteringPolicy != clusteringPolicyEnterprise {
+			return service.NewValidationError(
+				field,
+				fmt.Sprintf(
+					`module "%s" requires clusteringPolicy "%s"`,
+					module,
+					clusteringPolicyEnterprise,
+				),
+			)
+		}
+	}
+	return nil
+}
+
+func (s *serviceManager) ApplyProvisioningParametersDefaults(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*redisenterprise.ProvisioningParameters",
+		)
+	}
+	pp.SKU = getSKU(pp)
+	if allowed, ok := capacities[getFamily(pp.SKU)]; ok && pp.Capacity == 0 {
+		pp.Capacity = allowed[0]
+	}
+	pp.ClusteringPolicy = getClusteringPolicy(pp)
+	if pp.Modules == nil {
+		pp.Modules = []string{}
+	}
+	return nil
+}
+
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep("deployARMTemplate", s.deployARMTemplate),
+	)
+}
+
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*redisEnterpriseInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *redisEnterpriseInstanceDetails",
+		)
+	}
+	dt.ARMDeploymentName = uuid.NewV4().String()
+	// Cluster names must begin with a letter
+	dt.ClusterName = "redis-" + uuid.NewV4().String()
+	return dt, nil
+}
+
+func (s *serviceManager) deployARMTemplate(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*redisEnterpriseInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *redisEnterpriseInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*redisenterprise.ProvisioningParameters",
+		)
+	}
+	goParams, armParams := buildARMTemplateParameters(pp, dt)
+	outputs, err := s.armDeployer.Deploy(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateBytes,
+		goParams,
+		armParams,
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+	for output, field := range map[string]*string{
+		"hostName":     &dt.HostName,
+		"primaryKey":   &dt.PrimaryKey,
+		"secondaryKey": &dt.SecondaryKey,
+	} {
+		value, ok := outputs[output].(string)
+		if !ok {
+			return nil, fmt.Errorf(
+				`error retrieving "%s" from deployment`,
+				output,
+			)
+		}
+		*field = value
+	}
+	dt.Port = databasePort
+	return dt, nil
+}
+
+func buildARMTemplateParameters(
+	pp *ProvisioningParameters,
+	dt *redisEnterpriseInstanceDetails,
+) (map[string]interface{}, map[string]interface{}) {
+	goParams := map[string]interface{}{
+		"capacity": pp.Capacity != 0,
+	}
+	modules := make([]map[string]interface{}, len(pp.Modules))
+	// RediSearch indexes are corrupted by evicting keys, so a database that
+	// loads it must not evict any
+	evictionPolicy := "VolatileLRU"
+	for i, module := range pp.Modules {
+		modules[i] = map[string]interface{}{
+			"name": module,
+		}
+		if module == moduleRediSearch {
+			evictionPolicy = "NoEviction"
+		}
+	}
+	armParams := map[string]interface{}{
+		"clusterName":      dt.ClusterName,
+		"skuName":          pp.SKU,
+		"clusteringPolicy": pp.ClusteringPolicy,
+		"evictionPolicy":   evictionPolicy,
+		"modules":          modules,
+	}
+	if pp.Capacity != 0 {
+		armParams["capacity"] = pp.Capacity
+	}
+	return goParams, armParams
+}
+
+func getSKU(pp *ProvisioningParameters) string {
+	if pp.SKU == "" {
+		return defaultSKU
+	}
+	return pp.SKU
+}
+
+// getFamily returns the family of the given SKU, e.g. "Balanced" for
+// "Balanced_B5"
+func getFamily(sku string) string {
+	return strings.SplitN(sku, "_", 2)[0]
+}
+
+func getClusteringPolicy(pp *ProvisioningParameters) string {
+	if pp.ClusteringPolicy == "" {
+		return defaultClusteringPolicy
+	}
+	return pp.ClusteringPolicy
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func joinInts(values []int) string {
+	strs := make([]string, len(values))
+	for i, value := range values {
+		strs[i] = strconv.Itoa(value)
+	}
+	return strings.Join(strs, ", ")
+}
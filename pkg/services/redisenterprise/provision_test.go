@@ -0,0 +1,120 @@
+package redisenterprise
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateProvisioningParametersWithDefaults(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidSKU(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		SKU: "Balanced_B2",
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.SKU = "Balanced_B5"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithCapacity(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		SKU:      "Balanced_B5",
+		Capacity: 2,
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.SKU = "EnterpriseFlash_F300"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.SKU = "Enterprise_E10"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithModules(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		Modules: []string{moduleRediSearch, "RedisGraph"},
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.Modules = []string{moduleRedisJSON, moduleRedisJSON}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.Modules = redisModules
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithRediSearchAndOSSCluster(
+	t *testing.T,
+) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		ClusteringPolicy: clusteringPolicyOSS,
+		Modules:          []string{moduleRedisBloom, moduleRediSearch},
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.Modules = []string{moduleRedisBloom, moduleRedisTimeSeries}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithFlashSKU(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		SKU:     "FlashOptimized_A250",
+		Modules: []string{moduleRedisTimeSeries},
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.Modules = []string{moduleRediSearch, moduleRedisJSON}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestApplyProvisioningParametersDefaults(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{}
+	err := m.serviceManager.ApplyProvisioningParametersDefaults(pp)
+	assert.Nil(t, err)
+	assert.Equal(t, defaultSKU, pp.SKU)
+	assert.Equal(t, 0, pp.Capacity)
+	assert.Equal(t, clusteringPolicyEnterprise, pp.ClusteringPolicy)
+	pp = &ProvisioningParameters{
+		SKU: "Enterprise_E10",
+	}
+	err = m.serviceManager.ApplyProvisioningParametersDefaults(pp)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, pp.Capacity)
+}
+
+func TestBuildARMTemplateParametersWithRediSearch(t *testing.T) {
+	pp := &ProvisioningParameters{
+		SKU:              defaultSKU,
+		ClusteringPolicy: clusteringPolicyEnterprise,
+		Modules:          []string{moduleRediSearch},
+	}
+	goParams, armParams := buildARMTemplateParameters(
+		pp,
+		&redisEnterpriseInstanceDetails{},
+	)
+	assert.Equal(t, false, goParams["capacity"])
+	assert.Equal(t, "NoEviction", armParams["evictionPolicy"])
+	assert.Equal(
+		t,
+		[]map[string]interface{}{{"name": moduleRediSearch}},
+		armParams["modules"],
+	)
+}
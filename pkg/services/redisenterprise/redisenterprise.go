@@ -0,0 +1,44 @@
+package redisenterprise
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/redisenterprise"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer    arm.Deployer
+	clusterManager redisenterprise.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of provisioning Azure Managed Redis, on Redis
+// Enterprise clusters, optionally with Redis modules such as RediSearch and
+// RedisJSON loaded into their databases
+func New(
+	armDeployer arm.Deployer,
+	clusterManager redisenterprise.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer:    armDeployer,
+			clusterManager: clusterManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "redisenterprise"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{"Microsoft.Cache"}
+}
@@ -0,0 +1,72 @@
+package redisenterprise
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// ProvisioningParameters encapsulates Redis Enterprise-specific provisioning
+// options
+type ProvisioningParameters struct {
+	// SKU is the cluster's SKU, e.g. "Balanced_B5" or "Enterprise_E10"
+	SKU string `json:"sku"`
+	// Capacity is the number of nodes given Enterprise and EnterpriseFlash
+	// SKUs. The other SKUs don't accept one.
+	Capacity int `json:"capacity"`
+	// ClusteringPolicy is either "EnterpriseCluster" or "OSSCluster"
+	ClusteringPolicy string `json:"clusteringPolicy"`
+	// Modules are the names of the Redis modules loaded into the database
+	Modules []string `json:"modules"`
+}
+
+type redisEnterpriseInstanceDetails struct {
+	ARMDeploymentName string `json:"armDeployment"`
+	ClusterName       string `json:"clusterName"`
+	HostName          string `json:"hostName"`
+	Port              int    `json:"port"`
+	PrimaryKey        string `json:"primaryKey" secret:"true"`
+	SecondaryKey      string `json:"secondaryKey" secret:"true"`
+}
+
+// UpdatingParameters encapsulates Redis Enterprise-specific updating options
+type UpdatingParameters struct {
+}
+
+// BindingParameters encapsulates Redis Enterprise-specific binding options
+type BindingParameters struct {
+}
+
+type redisEnterpriseBindingDetails struct {
+}
+
+// Credentials encapsulates Redis Enterprise-specific connection details and
+// credentials
+type Credentials struct {
+	Host             string `json:"host"`
+	Port             int    `json:"port"`
+	Password         string `json:"password"`
+	ConnectionString string `json:"connectionString"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &redisEnterpriseInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &redisEnterpriseBindingDetails{}
+}
@@ -10,6 +10,7 @@ func (m *module) GetCatalog() (service.Catalog, error) {
 				Name:        "azuresearch",
 				Description: "Azure Search (Experimental)",
 				Bindable:    true,
+				Cloneable:   true,
 				Tags:        []string{"Azure", "Search", "Elasticsearch"},
 			},
 			m.serviceManager,
@@ -9,7 +9,7 @@ type ProvisioningParameters struct{}
 type searchInstanceDetails struct {
 	ARMDeploymentName string `json:"armDeployment"`
 	ServiceName       string `json:"serviceName"`
-	APIKey            string `json:"apiKey"`
+	APIKey            string `json:"apiKey" secret:"true"`
 }
 
 // UpdatingParameters encapsulates search-specific updating options
@@ -10,6 +10,7 @@ func (m *module) GetCatalog() (service.Catalog, error) {
 				Name:        "azure-servicebus",
 				Description: "Azure Service Bus (Experimental)",
 				Bindable:    true,
+				Cloneable:   true,
 				Tags:        []string{"Azure", "Service", "Bus"},
 			},
 			m.serviceManager,
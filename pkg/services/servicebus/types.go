@@ -8,8 +8,8 @@ type ProvisioningParameters struct{}
 type serviceBusInstanceDetails struct {
 	ARMDeploymentName       string `json:"armDeployment"`
 	ServiceBusNamespaceName string `json:"serviceBusNamespaceName"`
-	ConnectionString        string `json:"connectionString"`
-	PrimaryKey              string `json:"primaryKey"`
+	ConnectionString        string `json:"connectionString" secret:"true"`
+	PrimaryKey              string `json:"primaryKey" secret:"true"`
 }
 
 // UpdatingParameters encapsulates servicebus-specific updating options
@@ -0,0 +1,200 @@
+package springapps
+
+// nolint: lll
+var armTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "serviceName": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of the Azure Spring Apps service, which is unique across Azure"
+      }
+    },
+    "skuName": {
+      "type": "string"
+    },
+    "skuTier": {
+      "type": "string"
+    },
+    {{- if .configServer }}
+    "configServerGitUri": {
+      "type": "string"
+    },
+    "configServerLabel": {
+      "type": "string"
+    },
+    "configServerSearchPaths": {
+      "type": "array"
+    },
+    {{- end }}
+    "agentPoolSize": {
+      "type": "string",
+      "allowedValues": [
+        "S1",
+        "S2",
+        "S3",
+        "S4",
+        "S5"
+      ]
+    },
+    "appName": {
+      "type": "string"
+    },
+    "instanceCount": {
+      "type": "int"
+    },
+    "cpu": {
+      "type": "string"
+    },
+    "memory": {
+      "type": "string"
+    },
+    "tags": {
+      "type": "object"
+    }
+  },
+  "variables": {
+    "apiVersion": "2023-12-01",
+    "serviceId": "[resourceId('Microsoft.AppPlatform/Spring', parameters('serviceName'))]",
+    "appId": "[resourceId('Microsoft.AppPlatform/Spring/apps', parameters('serviceName'), parameters('appName'))]"
+  },
+  "resources": [
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[parameters('serviceName')]",
+      "type": "Microsoft.AppPlatform/Spring",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "sku": {
+        "name": "[parameters('skuName')]",
+        "tier": "[parameters('skuTier')]"
+      },
+      "properties": {}
+    },
+    {{- if .serviceRegistry }}
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[concat(parameters('serviceName'), '/default')]",
+      "type": "Microsoft.AppPlatform/Spring/serviceRegistries",
+      "dependsOn": [
+        "[variables('serviceId')]"
+      ]
+    },
+    {{- end }}
+    {{- if .configServer }}
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[concat(parameters('serviceName'), '/default')]",
+      "type": "Microsoft.AppPlatform/Spring/configServers",
+      "dependsOn": [
+        "[variables('serviceId')]"
+      ],
+      "properties": {
+        "configServer": {
+          "gitProperty": {
+            "uri": "[parameters('configServerGitUri')]",
+            "label": "[parameters('configServerLabel')]",
+            "searchPaths": "[parameters('configServerSearchPaths')]"
+          }
+        }
+      }
+    },
+    {{- end }}
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[concat(parameters('serviceName'), '/default/default')]",
+      "type": "Microsoft.AppPlatform/Spring/buildServices/agentPools",
+      "dependsOn": [
+        "[variables('serviceId')]"
+      ],
+      "properties": {
+        "poolSize": {
+          "name": "[parameters('agentPoolSize')]"
+        }
+      }
+    },
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[concat(parameters('serviceName'), '/', parameters('appName'))]",
+      "type": "Microsoft.AppPlatform/Spring/apps",
+      "location": "[parameters('location')]",
+      "dependsOn": [
+        {{- if .serviceRegistry }}
+        "[resourceId('Microsoft.AppPlatform/Spring/serviceRegistries', parameters('serviceName'), 'default')]",
+        {{- end }}
+        {{- if .configServer }}
+        "[resourceId('Microsoft.AppPlatform/Spring/configServers', parameters('serviceName'), 'default')]",
+        {{- end }}
+        "[variables('serviceId')]"
+      ],
+      "properties": {
+        "public": true,
+        "httpsOnly": true,
+        "addonConfigs": {
+          {{- if .serviceRegistry }}
+          "serviceRegistry": {
+            "resourceId": "[resourceId('Microsoft.AppPlatform/Spring/serviceRegistries', parameters('serviceName'), 'default')]"
+          }
+          {{- end }}
+          {{- if and .serviceRegistry .configServer }},{{ end }}
+          {{- if .configServer }}
+          "configServer": {
+            "resourceId": "[resourceId('Microsoft.AppPlatform/Spring/configServers', parameters('serviceName'), 'default')]"
+          }
+          {{- end }}
+        }
+      }
+    },
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[concat(parameters('serviceName'), '/', parameters('appName'), '/default')]",
+      "type": "Microsoft.AppPlatform/Spring/apps/deployments",
+      "dependsOn": [
+        "[variables('appId')]",
+        "[resourceId('Microsoft.AppPlatform/Spring/buildServices/agentPools', parameters('serviceName'), 'default', 'default')]"
+      ],
+      "sku": {
+        "name": "[parameters('skuName')]",
+        "tier": "[parameters('skuTier')]",
+        "capacity": "[parameters('instanceCount')]"
+      },
+      "properties": {
+        "active": true,
+        "source": {
+          "type": "BuildResult",
+          "buildResultId": "<default>"
+        },
+        "deploymentSettings": {
+          "resourceRequests": {
+            "cpu": "[parameters('cpu')]",
+            "memory": "[parameters('memory')]"
+          }
+        }
+      }
+    }
+  ],
+  "outputs": {
+    "serviceId": {
+      "type": "string",
+      "value": "[variables('serviceId')]"
+    },
+    "appUrl": {
+      "type": "string",
+      "value": "[reference(variables('appId'), variables('apiVersion')).url]"
+    },
+    "serviceRegistryEndpoint": {
+      "type": "string",
+      {{- if .serviceRegistry }}
+      "value": "[concat('https://', replace(reference(variables('serviceId'), variables('apiVersion')).fqdn, '.azuremicroservices.io', '.svc.azuremicroservices.io'), '/eureka/default/eureka')]"
+      {{- else }}
+      "value": ""
+      {{- end }}
+    }
+  }
+}
+`)
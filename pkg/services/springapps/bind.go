@@ -0,0 +1,136 @@
+package springapps
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+// defaultRole is sufficient to deploy code to the service's apps, and to
+// create and scale further apps
+const defaultRole = "Contributor"
+
+// roles maps the names of the built-in roles that a binding may assign to
+// their definitions
+var roles = map[string]string{
+	"Contributor":                                "b24988ac-6180-42a0-ab88-20f7382dd24c", // nolint: lll
+	"Azure Spring Cloud Data Reader":             "b5537268-8956-4941-a8f0-646150406f0c", // nolint: lll
+	"Azure Spring Cloud Service Registry Reader": "cff1b556-2399-4e7e-856d-a8f754be7b65", // nolint: lll
+}
+
+var objectIDRegex = regexp.MustCompile(
+	`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-` +
+		`[0-9a-fA-F]{12}$`,
+)
+
+func (s *serviceManager) ValidateBindingParameters(
+	bindingParameters service.BindingParameters,
+) error {
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return errors.New(
+			"error casting bindingParameters as *springapps.BindingParameters",
+		)
+	}
+	if !objectIDRegex.MatchString(bp.PrincipalID) {
+		return service.NewValidationError(
+			"principalId",
+			fmt.Sprintf(`invalid principalId: "%s"`, bp.PrincipalID),
+		)
+	}
+	if _, ok := roles[bp.Role]; bp.Role != "" && !ok {
+		return service.NewValidationError(
+			"role",
+			fmt.Sprintf(
+				`invalid role: "%s"; allowed values are: %s`,
+				bp.Role,
+				strings.Join(getRoleNames(), ", "),
+			),
+		)
+	}
+	return nil
+}
+
+// Bind assigns the requested role on the service to the principal named in
+// the binding parameters
+func (s *serviceManager) Bind(
+	instance service.Instance,
+	bindingParameters service.BindingParameters,
+) (service.BindingDetails, error) {
+	dt, ok := instance.Details.(*springAppsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *springAppsInstanceDetails",
+		)
+	}
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting bindingParameters as *springapps.BindingParameters",
+		)
+	}
+	bd := &springAppsBindingDetails{
+		PrincipalID:        bp.PrincipalID,
+		Role:               bp.Role,
+		RoleAssignmentName: uuid.NewV4().String(),
+	}
+	if bd.Role == "" {
+		bd.Role = defaultRole
+	}
+	if err := s.springManager.CreateRoleAssignment(
+		dt.ServiceID,
+		bd.RoleAssignmentName,
+		roles[bd.Role],
+		bd.PrincipalID,
+	); err != nil {
+		return nil, err
+	}
+	return bd, nil
+}
+
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	binding service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*springAppsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *springAppsInstanceDetails",
+		)
+	}
+	bd, ok := binding.Details.(*springAppsBindingDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting binding.Details as *springAppsBindingDetails",
+		)
+	}
+	return &Credentials{
+		ServiceName:             dt.ServiceName,
+		ServiceID:               dt.ServiceID,
+		ResourceGroup:           instance.ResourceGroup,
+		AppName:                 dt.AppName,
+		AppURL:                  dt.AppURL,
+		ServiceRegistryEndpoint: dt.ServiceRegistryEndpoint,
+		PrincipalID:             bd.PrincipalID,
+		Role:                    bd.Role,
+		RoleAssignmentID: fmt.Sprintf(
+			"%s/providers/Microsoft.Authorization/roleAssignments/%s",
+			dt.ServiceID,
+			bd.RoleAssignmentName,
+		),
+	}, nil
+}
+
+func getRoleNames() []string {
+	names := make([]string, 0, len(roles))
+	for name := range roles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,49 @@
+package springapps
+
+import (
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateBindingParameters(t *testing.T) {
+	m := &module{}
+	bp := &BindingParameters{}
+	err := m.serviceManager.ValidateBindingParameters(bp)
+	assert.NotNil(t, err)
+	bp.PrincipalID = "7c1e3b5a-9d2f-4a6e-8b0c-4f5d6e7a8b9c"
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.Nil(t, err)
+	bp.Role = "Owner"
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.NotNil(t, err)
+	bp.Role = "Azure Spring Cloud Service Registry Reader"
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.Nil(t, err)
+}
+
+func TestGetCredentials(t *testing.T) {
+	m := &module{}
+	credentials, err := m.serviceManager.GetCredentials(
+		service.Instance{
+			ResourceGroup: "rg",
+			Details: &springAppsInstanceDetails{
+				ServiceID: "/asa",
+				AppURL:    "https://asa-default.azuremicroservices.io",
+			},
+		},
+		service.Binding{
+			Details: &springAppsBindingDetails{
+				RoleAssignmentName: "assignment",
+			},
+		},
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, "rg", credentials.(*Credentials).ResourceGroup)
+	assert.Equal(
+		t,
+		"/asa/providers/Microsoft.Authorization/roleAssignments/assignment",
+		credentials.(*Credentials).RoleAssignmentID,
+	)
+}
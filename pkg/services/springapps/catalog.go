@@ -0,0 +1,62 @@
+package springapps
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:          "3d7a9e25-4b1c-4f68-8e03-c6b2f15a9d47",
+				Name:        "azure-spring-apps",
+				Description: "Azure Spring Apps (Experimental)",
+				Bindable:    true,
+				Tags: []string{
+					"Azure",
+					"Spring",
+					"Java",
+					"Microservices",
+				},
+				// The Enterprise tier is only offered in some regions
+				Locations: []string{
+					"australiaeast",
+					"brazilsouth",
+					"canadacentral",
+					"centralindia",
+					"centralus",
+					"eastasia",
+					"eastus",
+					"eastus2",
+					"francecentral",
+					"germanywestcentral",
+					"japaneast",
+					"koreacentral",
+					"northcentralus",
+					"northeurope",
+					"southafricanorth",
+					"southcentralus",
+					"southeastasia",
+					"swedencentral",
+					"switzerlandnorth",
+					"uaenorth",
+					"uksouth",
+					"westeurope",
+					"westus",
+					"westus2",
+					"westus3",
+				},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:   "a6f1c83e-92d4-4b7a-b5e0-7d3c8f2e1b94",
+				Name: "enterprise",
+				Description: "Enterprise tier, with VMware Tanzu components " +
+					"including Service Registry and Build Service",
+				Free: false,
+				Extended: map[string]interface{}{
+					"skuName": "E0",
+					"skuTier": "Enterprise",
+				},
+			}),
+		),
+	}), nil
+}
@@ -0,0 +1,351 @@
+package springapps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Limits the Enterprise tier places on each app
+const (
+	maxInstanceCount = 500
+	maxCPUMillicores = 8000
+	minMemoryMiB     = 512
+	maxMemoryMiB     = 32 * 1024
+)
+
+const (
+	defaultAgentPoolSize = "S1"
+	defaultAppName       = "default"
+	defaultInstanceCount = 1
+	defaultCPU           = "1"
+	defaultMemory        = "2Gi"
+)
+
+var agentPoolSizes = []string{"S1", "S2", "S3", "S4", "S5"}
+
+// appNameRegex matches the names Azure Spring Apps allows of apps: 4 to 32
+// lower case letters, numbers and hyphens, beginning with a letter and not
+// ending with a hyphen
+var appNameRegex = regexp.MustCompile(`^[a-z][a-z0-9-]{2,30}[a-z0-9]$`)
+
+// gitURIRegex matches the HTTP(S) and SSH URIs Config Server can clone a
+// repository from
+var gitURIRegex = regexp.MustCompile(`^(https?://|git@|ssh://)\S+$`)
+
+var cpuRegex = regexp.MustCompile(`^([1-9][0-9]*)(m?)$`)
+
+var memoryRegex = regexp.MustCompile(`^([1-9][0-9]*)(Gi|Mi)$`)
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*springapps.ProvisioningParameters",
+		)
+	}
+	if err := validateConfigServer(pp.ConfigServer); err != nil {
+		return err
+	}
+	if pp.BuildService != nil && pp.BuildService.AgentPoolSize != "" &&
+		!contains(agentPoolSizes, pp.BuildService.AgentPoolSize) {
+		return service.NewValidationError(
+			"buildService.agentPoolSize",
+			fmt.Sprintf(
+				`invalid agentPoolSize: "%s"; allowed values are: %s`,
+				pp.BuildService.AgentPoolSize,
+				strings.Join(agentPoolSizes, ", "),
+			),
+		)
+	}
+	return validateApp(pp.App)
+}
+
+func validateConfigServer(configServer *ConfigServer) error {
+	if configServer == nil {
+		return nil
+	}
+	if !gitURIRegex.MatchString(configServer.GitURI) {
+		return service.NewValidationError(
+			"configServer.gitUri",
+			fmt.Sprintf(`invalid Git repository URI: "%s"`, configServer.GitURI),
+		)
+	}
+	for i, searchPath := range configServer.SearchPaths {
+		if strings.TrimSpace(searchPath) == "" {
+			return service.NewValidationError(
+				fmt.Sprintf("configServer.searchPaths[%d]", i),
+				"search paths must not be empty",
+			)
+		}
+	}
+	return nil
+}
+
+func validateApp(app *App) error {
+	if app == nil {
+		return nil
+	}
+	if app.Name != "" && !appNameRegex.MatchString(app.Name) {
+		return service.NewValidationError(
+			"app.name",
+			fmt.Sprintf(
+				`invalid app name: "%s"; app names must be 4 to 32 lower case `+
+					"letters, numbers and hyphens, beginning with a letter and "+
+					"not ending with a hyphen",
+				app.Name,
+			),
+		)
+	}
+	if app.InstanceCount < 0 || app.InstanceCount > maxInstanceCount {
+		return service.NewValidationError(
+			"app.instanceCount",
+			fmt.Sprintf(
+				"invalid instanceCount: %d; the Enterprise tier allows 1 to %d "+
+					"instances of an app",
+				app.InstanceCount,
+				maxInstanceCount,
+			),
+		)
+	}
+	if app.CPU != "" {
+		millicores, ok := parseCPU(app.CPU)
+		if !ok || millicores > maxCPUMillicores {
+			return service.NewValidationError(
+				"app.cpu",
+				fmt.Sprintf(
+					`invalid cpu: "%s"; the Enterprise tier allows up to %d cores `+
+						`per instance, given as e.g. "2" or "500m"`,
+					app.CPU,
+					maxCPUMillicores/1000,
+				),
+			)
+		}
+	}
+	if app.Memory != "" {
+		mebibytes, ok := parseMemory(app.Memory)
+		if !ok || mebibytes < minMemoryMiB || mebibytes > maxMemoryMiB {
+			return service.NewValidationError(
+				"app.memory",
+				fmt.Sprintf(
+					`invalid memory: "%s"; the Enterprise tier allows %dMi to %dGi `+
+						`per instance, given as e.g. "2Gi" or "512Mi"`,
+					app.Memory,
+					minMemoryMiB,
+					maxMemoryMiB/1024,
+				),
+			)
+		}
+	}
+	return nil
+}
+
+func (s *serviceManager) ApplyProvisioningParametersDefaults(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*springapps.ProvisioningParameters",
+		)
+	}
+	if pp.ServiceRegistry == nil {
+		pp.ServiceRegistry = &ServiceRegistry{}
+	}
+	if pp.ServiceRegistry.Enabled == nil {
+		enabled := true
+		pp.ServiceRegistry.Enabled = &enabled
+	}
+	if pp.BuildService == nil {
+		pp.BuildService = &BuildService{}
+	}
+	if pp.BuildService.AgentPoolSize == "" {
+		pp.BuildService.AgentPoolSize = defaultAgentPoolSize
+	}
+	if pp.App == nil {
+		pp.App = &App{}
+	}
+	if pp.App.Name == "" {
+		pp.App.Name = defaultAppName
+	}
+	if pp.App.InstanceCount == 0 {
+		pp.App.InstanceCount = defaultInstanceCount
+	}
+	if pp.App.CPU == "" {
+		pp.App.CPU = defaultCPU
+	}
+	if pp.App.Memory == "" {
+		pp.App.Memory = defaultMemory
+	}
+	return nil
+}
+
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep("deployARMTemplate", s.deployARMTemplate),
+	)
+}
+
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*springAppsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *springAppsInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*springapps.ProvisioningParameters",
+		)
+	}
+	dt.ARMDeploymentName = uuid.NewV4().String()
+	// Service names are limited to 32 characters and must begin with a letter
+	dt.ServiceName = "asa" +
+		strings.Replace(uuid.NewV4().String(), "-", "", -1)[:20]
+	dt.AppName = pp.App.Name
+	return dt, nil
+}
+
+func (s *serviceManager) deployARMTemplate(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*springAppsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *springAppsInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*springapps.ProvisioningParameters",
+		)
+	}
+	goParams, armParams := buildARMTemplateParameters(
+		instance.Plan.GetProperties().Extended,
+		pp,
+		dt,
+	)
+	outputs, err := s.armDeployer.Deploy(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateBytes,
+		goParams,
+		armParams,
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+	for output, field := range map[string]*string{
+		"serviceId":               &dt.ServiceID,
+		"appUrl":                  &dt.AppURL,
+		"serviceRegistryEndpoint": &dt.ServiceRegistryEndpoint,
+	} {
+		value, ok := outputs[output].(string)
+		if !ok {
+			return nil, fmt.Errorf(
+				`error retrieving "%s" from deployment`,
+				output,
+			)
+		}
+		*field = value
+	}
+	return dt, nil
+}
+
+func buildARMTemplateParameters(
+	planExtended map[string]interface{},
+	pp *ProvisioningParameters,
+	dt *springAppsInstanceDetails,
+) (map[string]interface{}, map[string]interface{}) {
+	goParams := map[string]interface{}{
+		"serviceRegistry": *pp.ServiceRegistry.Enabled,
+		"configServer":    pp.ConfigServer != nil,
+	}
+	armParams := map[string]interface{}{
+		"serviceName":   dt.ServiceName,
+		"skuName":       planExtended["skuName"],
+		"skuTier":       planExtended["skuTier"],
+		"agentPoolSize": pp.BuildService.AgentPoolSize,
+		"appName":       dt.AppName,
+		"instanceCount": pp.App.InstanceCount,
+		"cpu":           pp.App.CPU,
+		"memory":        pp.App.Memory,
+	}
+	if pp.ConfigServer != nil {
+		searchPaths := pp.ConfigServer.SearchPaths
+		if searchPaths == nil {
+			searchPaths = []string{}
+		}
+		armParams["configServerGitUri"] = pp.ConfigServer.GitURI
+		armParams["configServerLabel"] = pp.ConfigServer.Label
+		armParams["configServerSearchPaths"] = searchPaths
+	}
+	return goParams, armParams
+}
+
+// parseCPU returns the number of millicores the given CPU quantity amounts to
+func parseCPU(cpu string) (int, bool) {
+	matches := cpuRegex.FindStringSubmatch(cpu)
+	// Anything longer is out of range anyway, and could overflow
+	if matches == nil || len(matches[1]) > 6 {
+		return 0, false
+	}
+	quantity, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, false
+	}
+	if matches[2] == "m" {
+		return quantity, true
+	}
+	return quantity * 1000, true
+}
+
+// parseMemory returns the number of mebibytes the given memory quantity
+// amounts to
+func parseMemory(memory string) (int, bool) {
+	matches := memoryRegex.FindStringSubmatch(memory)
+	// Anything longer is out of range anyway, and could overflow
+	if matches == nil || len(matches[1]) > 6 {
+		return 0, false
+	}
+	quantity, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, false
+	}
+	if matches[2] == "Gi" {
+		return quantity * 1024, true
+	}
+	return quantity, true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package springapps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateProvisioningParametersWithDefaults(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithConfigServer(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		ConfigServer: &ConfigServer{},
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.ConfigServer.GitURI = "https://github.com/contoso/config.git"
+	pp.ConfigServer.SearchPaths = []string{"payments", " "}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.ConfigServer.SearchPaths = []string{"payments"}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidAgentPoolSize(
+	t *testing.T,
+) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		BuildService: &BuildService{
+			AgentPoolSize: "S6",
+		},
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.BuildService.AgentPoolSize = "S5"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithApp(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		App: &App{
+			Name: "Gateway",
+		},
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.App.Name = "gateway"
+	pp.App.InstanceCount = maxInstanceCount + 1
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.App.InstanceCount = 2
+	pp.App.CPU = "9"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.App.CPU = "500m"
+	pp.App.Memory = "256Mi"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.App.Memory = "33Gi"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.App.Memory = "4Gi"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestApplyProvisioningParametersDefaults(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{}
+	err := m.serviceManager.ApplyProvisioningParametersDefaults(pp)
+	assert.Nil(t, err)
+	assert.True(t, *pp.ServiceRegistry.Enabled)
+	assert.Equal(t, defaultAgentPoolSize, pp.BuildService.AgentPoolSize)
+	assert.Equal(t, defaultAppName, pp.App.Name)
+	assert.Equal(t, defaultInstanceCount, pp.App.InstanceCount)
+	assert.Equal(t, defaultCPU, pp.App.CPU)
+	assert.Equal(t, defaultMemory, pp.App.Memory)
+}
+
+func TestBuildARMTemplateParametersWithConfigServer(t *testing.T) {
+	enabled := false
+	pp := &ProvisioningParameters{
+		ServiceRegistry: &ServiceRegistry{
+			Enabled: &enabled,
+		},
+		ConfigServer: &ConfigServer{
+			GitURI: "https://github.com/contoso/config.git",
+		},
+		BuildService: &BuildService{
+			AgentPoolSize: defaultAgentPoolSize,
+		},
+		App: &App{},
+	}
+	goParams, armParams := buildARMTemplateParameters(
+		map[string]interface{}{
+			"skuName": "E0",
+			"skuTier": "Enterprise",
+		},
+		pp,
+		&springAppsInstanceDetails{},
+	)
+	assert.Equal(t, false, goParams["serviceRegistry"])
+	assert.Equal(t, true, goParams["configServer"])
+	assert.Equal(t, "E0", armParams["skuName"])
+	assert.Equal(t, []string{}, armParams["configServerSearchPaths"])
+}
@@ -0,0 +1,46 @@
+package springapps
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/springapps"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer   arm.Deployer
+	springManager springapps.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of provisioning Azure Spring Apps Enterprise
+// service instances, each with an initial app
+func New(
+	armDeployer arm.Deployer,
+	springManager springapps.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer:   armDeployer,
+			springManager: springManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "springapps"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{
+		"Microsoft.AppPlatform",
+		"Microsoft.Authorization",
+	}
+}
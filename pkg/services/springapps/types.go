@@ -0,0 +1,117 @@
+package springapps
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// ProvisioningParameters encapsulates Azure Spring Apps-specific provisioning
+// options
+type ProvisioningParameters struct {
+	ServiceRegistry *ServiceRegistry `json:"serviceRegistry"`
+	// ConfigServer, if specified, enables Spring Cloud Config Server, backed by
+	// the given Git repository
+	ConfigServer *ConfigServer `json:"configServer"`
+	BuildService *BuildService `json:"buildService"`
+	App          *App          `json:"app"`
+}
+
+// ServiceRegistry encapsulates the settings of the service's Tanzu Service
+// Registry, through which its apps discover one another
+type ServiceRegistry struct {
+	// Enabled defaults to true
+	Enabled *bool `json:"enabled"`
+}
+
+// ConfigServer encapsulates the Git repository that Spring Cloud Config
+// Server serves the apps' configuration from
+type ConfigServer struct {
+	GitURI string `json:"gitUri"`
+	// Label is the branch, tag or commit to read configuration from
+	Label       string   `json:"label"`
+	SearchPaths []string `json:"searchPaths"`
+}
+
+// BuildService encapsulates the settings of the service's Tanzu Build
+// Service, which builds apps' source code into container images
+type BuildService struct {
+	// AgentPoolSize is one of S1 through S5; larger pools build more
+	// concurrently
+	AgentPoolSize string `json:"agentPoolSize"`
+}
+
+// App encapsulates the initial app created in the service. It runs a sample
+// until the app's code is deployed to it.
+type App struct {
+	Name          string `json:"name"`
+	InstanceCount int    `json:"instanceCount"`
+	// CPU is a number of cores, or of millicores with the suffix "m"
+	CPU string `json:"cpu"`
+	// Memory is a number of gibibytes with the suffix "Gi", or of mebibytes
+	// with the suffix "Mi"
+	Memory string `json:"memory"`
+}
+
+type springAppsInstanceDetails struct {
+	ARMDeploymentName string `json:"armDeployment"`
+	ServiceName       string `json:"serviceName"`
+	ServiceID         string `json:"serviceId"`
+	AppName           string `json:"appName"`
+	AppURL            string `json:"appUrl"`
+	// ServiceRegistryEndpoint is empty unless the service registry was enabled
+	ServiceRegistryEndpoint string `json:"serviceRegistryEndpoint"`
+}
+
+// UpdatingParameters encapsulates Azure Spring Apps-specific updating options
+type UpdatingParameters struct {
+}
+
+// BindingParameters encapsulates Azure Spring Apps-specific binding options
+type BindingParameters struct {
+	PrincipalID string `json:"principalId"`
+	Role        string `json:"role"`
+}
+
+type springAppsBindingDetails struct {
+	PrincipalID        string `json:"principalId"`
+	Role               string `json:"role"`
+	RoleAssignmentName string `json:"roleAssignmentName"`
+}
+
+// Credentials encapsulates Azure Spring Apps-specific connection details. The
+// principal's role assignment on the service is what authorizes it to deploy
+// apps there, e.g. with "az spring app deploy".
+type Credentials struct {
+	ServiceName             string `json:"serviceName"`
+	ServiceID               string `json:"serviceId"`
+	ResourceGroup           string `json:"resourceGroup"`
+	AppName                 string `json:"appName"`
+	AppURL                  string `json:"appUrl"`
+	ServiceRegistryEndpoint string `json:"serviceRegistryEndpoint,omitempty"`
+	PrincipalID             string `json:"principalId"`
+	Role                    string `json:"role"`
+	RoleAssignmentID        string `json:"roleAssignmentId"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &springAppsInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &springAppsBindingDetails{}
+}
@@ -0,0 +1,29 @@
+package springapps
+
+import (
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) Unbind(
+	instance service.Instance,
+	bindingDetails service.BindingDetails,
+) error {
+	dt, ok := instance.Details.(*springAppsInstanceDetails)
+	if !ok {
+		return errors.New(
+			"error casting instance.Details as *springAppsInstanceDetails",
+		)
+	}
+	bd, ok := bindingDetails.(*springAppsBindingDetails)
+	if !ok {
+		return errors.New(
+			"error casting bindingDetails as *springAppsBindingDetails",
+		)
+	}
+	return s.springManager.DeleteRoleAssignment(
+		dt.ServiceID,
+		bd.RoleAssignmentName,
+	)
+}
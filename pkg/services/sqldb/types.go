@@ -18,7 +18,7 @@ type mssqlAllInOneInstanceDetails struct {
 	FullyQualifiedDomainName   string `json:"fullyQualifiedDomainName"`
 	ServerName                 string `json:"server"`
 	AdministratorLogin         string `json:"administratorLogin"`
-	AdministratorLoginPassword string `json:"administratorLoginPassword"`
+	AdministratorLoginPassword string `json:"administratorLoginPassword" secret:"true"` // nolint: lll
 	DatabaseName               string `json:"database"`
 }
 
@@ -27,7 +27,7 @@ type mssqlVMOnlyInstanceDetails struct {
 	FullyQualifiedDomainName   string `json:"fullyQualifiedDomainName"`
 	ServerName                 string `json:"server"`
 	AdministratorLogin         string `json:"administratorLogin"`
-	AdministratorLoginPassword string `json:"administratorLoginPassword"`
+	AdministratorLoginPassword string `json:"administratorLoginPassword" secret:"true"` // nolint: lll
 }
 
 type mssqlDBOnlyInstanceDetails struct {
@@ -46,7 +46,7 @@ type BindingParameters struct {
 
 type mssqlBindingDetails struct {
 	LoginName string `json:"loginName"`
-	Password  string `json:"password"`
+	Password  string `json:"password" secret:"true"`
 }
 
 // Credentials encapsulates MSSQL-specific coonection details and credentials.
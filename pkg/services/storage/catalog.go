@@ -14,6 +14,7 @@ func (m *module) GetCatalog() (service.Catalog, error) {
 				Name:        "azure-storage",
 				Description: "Azure Storage (Experimental)",
 				Bindable:    true,
+				Cloneable:   true,
 				Tags:        []string{"Azure", "Storage"},
 			},
 			m.serviceManager,
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+const (
+	publicNetworkAccessEnabled  = "Enabled"
+	publicNetworkAccessDisabled = "Disabled"
+)
+
+// Quarantine disables public network access to the storage account and
+// regenerates its access keys. Every binding shares the instance's access key,
+// so regenerating it is what revokes their credentials. The setting that was
+// in effect beforehand is recorded only once, so that a retried quarantine
+// doesn't record "Disabled" in its place.
+func (s *serviceManager) Quarantine(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*storageInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *storageInstanceDetails",
+		)
+	}
+	if dt.PublicNetworkAccessBeforeQuarantine == "" {
+		publicNetworkAccess, err := s.storageManager.GetPublicNetworkAccess(
+			dt.StorageAccountName,
+			instance.ResourceGroup,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if publicNetworkAccess == "" {
+			publicNetworkAccess = publicNetworkAccessEnabled
+		}
+		dt.PublicNetworkAccessBeforeQuarantine = publicNetworkAccess
+	}
+	if err := s.storageManager.SetPublicNetworkAccess(
+		dt.StorageAccountName,
+		instance.ResourceGroup,
+		publicNetworkAccessDisabled,
+	); err != nil {
+		return nil, err
+	}
+	accessKey, err := s.storageManager.RegenerateAccessKeys(
+		dt.StorageAccountName,
+		instance.ResourceGroup,
+	)
+	if err != nil {
+		return nil, err
+	}
+	dt.AccessKey = accessKey
+	return dt, nil
+}
+
+// Release restores the storage account's public network access setting to
+// what it was before the instance was quarantined
+func (s *serviceManager) Release(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*storageInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *storageInstanceDetails",
+		)
+	}
+	publicNetworkAccess := dt.PublicNetworkAccessBeforeQuarantine
+	if publicNetworkAccess == "" {
+		publicNetworkAccess = publicNetworkAccessEnabled
+	}
+	if err := s.storageManager.SetPublicNetworkAccess(
+		dt.StorageAccountName,
+		instance.ResourceGroup,
+		publicNetworkAccess,
+	); err != nil {
+		return nil, err
+	}
+	dt.PublicNetworkAccessBeforeQuarantine = ""
+	return dt, nil
+}
@@ -16,8 +16,12 @@ type ProvisioningParameters struct{}
 type storageInstanceDetails struct {
 	ARMDeploymentName  string `json:"armDeployment"`
 	StorageAccountName string `json:"storageAccountName"`
-	AccessKey          string `json:"accessKey"`
+	AccessKey          string `json:"accessKey" secret:"true"`
 	ContainerName      string `json:"containerName"`
+	// PublicNetworkAccessBeforeQuarantine records the storage account's public
+	// network access setting from before it was quarantined, so that releasing
+	// the instance can restore it
+	PublicNetworkAccessBeforeQuarantine string `json:"publicNetworkAccessBeforeQuarantine,omitempty"` // nolint: lll
 }
 
 // UpdatingParameters encapsulates Storage-specific updating options
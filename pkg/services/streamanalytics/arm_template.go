@@ -0,0 +1,70 @@
+package streamanalytics
+
+// nolint: lll
+var armTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "jobName": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of the Stream Analytics job"
+      }
+    },
+    "streamingUnits": {
+      "type": "int"
+    },
+    "query": {
+      "type": "string"
+    },
+    "inputs": {
+      "type": "array"
+    },
+    "outputs": {
+      "type": "array"
+    },
+    "tags": {
+      "type": "object"
+    }
+  },
+  "variables": {
+    "apiVersion": "2020-03-01"
+  },
+  "resources": [
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[parameters('jobName')]",
+      "type": "Microsoft.StreamAnalytics/streamingjobs",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "properties": {
+        "sku": {
+          "name": "Standard"
+        },
+        "eventsOutOfOrderPolicy": "Adjust",
+        "outputErrorPolicy": "Stop",
+        "compatibilityLevel": "1.2",
+        "inputs": "[parameters('inputs')]",
+        "outputs": "[parameters('outputs')]",
+        "transformation": {
+          "name": "Transformation",
+          "properties": {
+            "streamingUnits": "[parameters('streamingUnits')]",
+            "query": "[parameters('query')]"
+          }
+        }
+      }
+    }
+  ],
+  "outputs": {
+    "jobId": {
+      "type": "string",
+      "value": "[resourceId('Microsoft.StreamAnalytics/streamingjobs', parameters('jobName'))]"
+    }
+  }
+}
+`)
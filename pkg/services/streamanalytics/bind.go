@@ -0,0 +1,86 @@
+package streamanalytics
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/streamanalytics"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) ValidateBindingParameters(
+	service.BindingParameters,
+) error {
+	// There are no parameters for binding to,
+	// so there is nothing to validate
+	return nil
+}
+
+// Bind is a no-op. Nothing needs to be created in order for an application to
+// know the status of a job.
+func (s *serviceManager) Bind(
+	service.Instance,
+	service.BindingParameters,
+) (service.BindingDetails, error) {
+	return &streamAnalyticsBindingDetails{}, nil
+}
+
+// GetCredentials retrieves the job's current status from Azure, so that it is
+// always up to date, even if the job was started or stopped out of band
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	_ service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*streamAnalyticsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *streamAnalyticsInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*streamanalytics.ProvisioningParameters",
+		)
+	}
+	status, ok, err := s.streamAnalyticsManager.GetJobStatus(
+		dt.JobName,
+		instance.ResourceGroup,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf(
+			`Stream Analytics job "%s" does not exist`,
+			dt.JobName,
+		)
+	}
+	return getCredentials(dt, pp, status), nil
+}
+
+func getCredentials(
+	dt *streamAnalyticsInstanceDetails,
+	pp *ProvisioningParameters,
+	status streamanalytics.JobStatus,
+) *Credentials {
+	inputs := make([]string, len(pp.Inputs))
+	for i, input := range pp.Inputs {
+		inputs[i] = input.Name
+	}
+	outputs := make([]string, len(pp.Outputs))
+	for i, output := range pp.Outputs {
+		outputs[i] = output.Name
+	}
+	return &Credentials{
+		JobID:               dt.JobID,
+		JobName:             dt.JobName,
+		JobState:            status.JobState,
+		ProvisioningState:   status.ProvisioningState,
+		StreamingUnits:      getStreamingUnits(pp),
+		Inputs:              inputs,
+		Outputs:             outputs,
+		LastOutputEventTime: status.LastOutputEventTime,
+	}
+}
@@ -0,0 +1,24 @@
+package streamanalytics
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:          "5e3b8f1a-94c2-4d7e-b6a0-3f2c1d9e8a47",
+				Name:        "azure-stream-analytics",
+				Description: "Azure Stream Analytics (Experimental)",
+				Bindable:    true,
+				Tags:        []string{"Azure", "Stream Analytics", "Streaming"},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:          "b8d4a2e6-1c7f-4f39-8e5b-0a6d3c9f2e71",
+				Name:        "standard",
+				Description: "A Stream Analytics job, billed per streaming unit hour",
+				Free:        false,
+			}),
+		),
+	}), nil
+}
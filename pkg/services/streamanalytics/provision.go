@@ -0,0 +1,394 @@
+package streamanalytics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	defaultStreamingUnits = 3
+	maxStreamingUnits     = 192
+
+	inputTypeStream    = "Stream"
+	inputTypeReference = "Reference"
+
+	jobStateRunning = "Running"
+	jobStateStopped = "Stopped"
+
+	// streamingUnitSKU is the name by which streaming units are known in the
+	// pricing table
+	streamingUnitSKU = "streamingUnit"
+)
+
+// defaultSerialization is used for inputs and outputs whose serialization
+// isn't specified
+var defaultSerialization = map[string]interface{}{
+	"type": "Json",
+	"properties": map[string]interface{}{
+		"encoding": "UTF8",
+	},
+}
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*streamanalytics.ProvisioningParameters",
+		)
+	}
+	if pp.StreamingUnits != 0 && !isValidStreamingUnits(pp.StreamingUnits) {
+		return service.NewValidationError(
+			"streamingUnits",
+			fmt.Sprintf(
+				`invalid streamingUnits: %d; must be 1, 3, or a multiple of 6 up to %d`,
+				pp.StreamingUnits,
+				maxStreamingUnits,
+			),
+		)
+	}
+	if strings.TrimSpace(pp.Query) == "" {
+		return service.NewValidationError("query", "a query must be specified")
+	}
+	if len(pp.Inputs) == 0 {
+		return service.NewValidationError(
+			"inputs",
+			"at least one input must be specified",
+		)
+	}
+	inputNames := map[string]bool{}
+	for i, input := range pp.Inputs {
+		field := fmt.Sprintf("inputs[%d]", i)
+		if err := validateName(field, input.Name, inputNames); err != nil {
+			return err
+		}
+		if input.Type != "" &&
+			input.Type != inputTypeStream &&
+			input.Type != inputTypeReference {
+			return service.NewValidationError(
+				field+".type",
+				fmt.Sprintf(
+					`invalid input type: "%s"; allowed values are: %s, %s`,
+					input.Type,
+					inputTypeStream,
+					inputTypeReference,
+				),
+			)
+		}
+		if err := validateDatasource(field, input.Datasource); err != nil {
+			return err
+		}
+	}
+	if len(pp.Outputs) == 0 {
+		return service.NewValidationError(
+			"outputs",
+			"at least one output must be specified",
+		)
+	}
+	outputNames := map[string]bool{}
+	for i, output := range pp.Outputs {
+		field := fmt.Sprintf("outputs[%d]", i)
+		if err := validateName(field, output.Name, outputNames); err != nil {
+			return err
+		}
+		if err := validateDatasource(field, output.Datasource); err != nil {
+			return err
+		}
+	}
+	return validateJobState(pp.JobState)
+}
+
+func (s *serviceManager) ApplyProvisioningParametersDefaults(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*streamanalytics.ProvisioningParameters",
+		)
+	}
+	pp.StreamingUnits = getStreamingUnits(pp)
+	for i := range pp.Inputs {
+		if pp.Inputs[i].Type == "" {
+			pp.Inputs[i].Type = inputTypeStream
+		}
+		if pp.Inputs[i].Serialization == nil {
+			pp.Inputs[i].Serialization = defaultSerialization
+		}
+	}
+	for i := range pp.Outputs {
+		if pp.Outputs[i].Serialization == nil {
+			pp.Outputs[i].Serialization = defaultSerialization
+		}
+	}
+	if pp.JobState == "" {
+		pp.JobState = jobStateStopped
+	}
+	return nil
+}
+
+// GetSKUUsages maps a job to the number of streaming units it is allotted
+func (s *serviceManager) GetSKUUsages(
+	_ service.Plan,
+	provisioningParameters service.ProvisioningParameters,
+) ([]service.SKUUsage, error) {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting provisioningParameters as " +
+				"*streamanalytics.ProvisioningParameters",
+		)
+	}
+	return []service.SKUUsage{
+		{
+			SKU:      streamingUnitSKU,
+			Quantity: float64(getStreamingUnits(pp)),
+		},
+	}, nil
+}
+
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep("deployARMTemplate", s.deployARMTemplate),
+		service.NewProvisioningStep("startJob", s.startJob),
+	)
+}
+
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*streamAnalyticsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *streamAnalyticsInstanceDetails",
+		)
+	}
+	dt.ARMDeploymentName = uuid.NewV4().String()
+	dt.JobName = "asa-" + uuid.NewV4().String()
+	return dt, nil
+}
+
+func (s *serviceManager) deployARMTemplate(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*streamAnalyticsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *streamAnalyticsInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*streamanalytics.ProvisioningParameters",
+		)
+	}
+	armParams := buildARMTemplateParameters(pp)
+	armParams["jobName"] = dt.JobName
+	outputs, err := s.armDeployer.Deploy(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateBytes,
+		nil,
+		armParams,
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+	jobID, ok := outputs["jobId"].(string)
+	if !ok {
+		return nil, errors.New(
+			"error retrieving Stream Analytics job resource id from deployment",
+		)
+	}
+	dt.JobID = jobID
+	return dt, nil
+}
+
+// startJob starts the newly created job if it is to be left running. A job
+// is created stopped, so there's nothing to do otherwise.
+func (s *serviceManager) startJob(
+	ctx context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*streamAnalyticsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *streamAnalyticsInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*streamanalytics.ProvisioningParameters",
+		)
+	}
+	if pp.JobState != jobStateRunning {
+		return dt, nil
+	}
+	// Starting a job that Azure doesn't yet report as existing would fail
+	found, err := az.ReadWithConsistencyRetry(ctx, func() (bool, error) {
+		_, ok, err := s.streamAnalyticsManager.GetJobStatus(
+			dt.JobName,
+			instance.ResourceGroup,
+		)
+		return ok, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf(
+			`Stream Analytics job "%s" does not exist`,
+			dt.JobName,
+		)
+	}
+	if err := s.streamAnalyticsManager.StartJob(
+		dt.JobName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+// buildARMTemplateParameters returns the ARM template parameters used to
+// deploy a job as the given provisioning parameters describe
+func buildARMTemplateParameters(
+	pp *ProvisioningParameters,
+) map[string]interface{} {
+	inputs := make([]interface{}, len(pp.Inputs))
+	for i, input := range pp.Inputs {
+		inputType := input.Type
+		if inputType == "" {
+			inputType = inputTypeStream
+		}
+		inputs[i] = map[string]interface{}{
+			"name": input.Name,
+			"properties": map[string]interface{}{
+				"type":          inputType,
+				"datasource":    input.Datasource,
+				"serialization": getSerialization(input.Serialization),
+			},
+		}
+	}
+	outputs := make([]interface{}, len(pp.Outputs))
+	for i, output := range pp.Outputs {
+		outputs[i] = map[string]interface{}{
+			"name": output.Name,
+			"properties": map[string]interface{}{
+				"datasource":    output.Datasource,
+				"serialization": getSerialization(output.Serialization),
+			},
+		}
+	}
+	return map[string]interface{}{
+		"streamingUnits": getStreamingUnits(pp),
+		"query":          pp.Query,
+		"inputs":         inputs,
+		"outputs":        outputs,
+	}
+}
+
+func getStreamingUnits(pp *ProvisioningParameters) int {
+	if pp.StreamingUnits == 0 {
+		return defaultStreamingUnits
+	}
+	return pp.StreamingUnits
+}
+
+func getSerialization(
+	serialization map[string]interface{},
+) map[string]interface{} {
+	if serialization == nil {
+		return defaultSerialization
+	}
+	return serialization
+}
+
+func isValidStreamingUnits(streamingUnits int) bool {
+	return streamingUnits == 1 ||
+		streamingUnits == 3 ||
+		(streamingUnits > 0 &&
+			streamingUnits <= maxStreamingUnits &&
+			streamingUnits%6 == 0)
+}
+
+// validateName verifies that an input or output has a name that no other
+// input or output of the same kind (as recorded in names) has. Stream Analytics
+// treats names case-insensitively.
+func validateName(field string, name string, names map[string]bool) error {
+	if strings.TrimSpace(name) == "" {
+		return service.NewValidationError(
+			field+".name",
+			"a name must be specified",
+		)
+	}
+	key := strings.ToLower(name)
+	if names[key] {
+		return service.NewValidationError(
+			field+".name",
+			fmt.Sprintf(`duplicate name: "%s"`, name),
+		)
+	}
+	names[key] = true
+	return nil
+}
+
+// validateDatasource verifies that an input or output's datasource at least
+// names the kind of datasource it is. Beyond that, datasources are validated
+// by Azure when the job is deployed.
+func validateDatasource(
+	field string,
+	datasource map[string]interface{},
+) error {
+	if datasource == nil {
+		return service.NewValidationError(
+			field+".datasource",
+			"a datasource must be specified",
+		)
+	}
+	if datasourceType, ok := datasource["type"].(string); !ok ||
+		datasourceType == "" {
+		return service.NewValidationError(
+			field+".datasource.type",
+			"the datasource's type must be specified",
+		)
+	}
+	return nil
+}
+
+func validateJobState(jobState string) error {
+	if jobState != "" &&
+		jobState != jobStateRunning &&
+		jobState != jobStateStopped {
+		return service.NewValidationError(
+			"jobState",
+			fmt.Sprintf(
+				`invalid jobState: "%s"; allowed values are: %s, %s`,
+				jobState,
+				jobStateRunning,
+				jobStateStopped,
+			),
+		)
+	}
+	return nil
+}
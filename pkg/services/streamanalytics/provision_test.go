@@ -0,0 +1,153 @@
+package streamanalytics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateProvisioningParametersWithValidJob(t *testing.T) {
+	m := &module{}
+	err := m.serviceManager.ValidateProvisioningParameters(
+		getTestProvisioningParameters(),
+	)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithStreamingUnits(t *testing.T) {
+	m := &module{}
+	pp := getTestProvisioningParameters()
+	for _, streamingUnits := range []int{1, 3, 6, 48, 192} {
+		pp.StreamingUnits = streamingUnits
+		err := m.serviceManager.ValidateProvisioningParameters(pp)
+		assert.Nil(t, err, "streamingUnits: %d", streamingUnits)
+	}
+	for _, streamingUnits := range []int{-6, 2, 4, 9, 198} {
+		pp.StreamingUnits = streamingUnits
+		err := m.serviceManager.ValidateProvisioningParameters(pp)
+		assert.NotNil(t, err, "streamingUnits: %d", streamingUnits)
+	}
+}
+
+func TestValidateProvisioningParametersWithoutQuery(t *testing.T) {
+	m := &module{}
+	pp := getTestProvisioningParameters()
+	pp.Query = " "
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+}
+
+func TestValidateProvisioningParametersWithoutInputsOrOutputs(t *testing.T) {
+	m := &module{}
+	pp := getTestProvisioningParameters()
+	pp.Inputs = nil
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp = getTestProvisioningParameters()
+	pp.Outputs = nil
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+}
+
+func TestValidateProvisioningParametersWithDuplicateNames(t *testing.T) {
+	m := &module{}
+	pp := getTestProvisioningParameters()
+	input := pp.Inputs[0]
+	input.Name = "INPUT"
+	pp.Inputs = append(pp.Inputs, input)
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	// An input and an output may share a name
+	pp = getTestProvisioningParameters()
+	pp.Outputs[0].Name = pp.Inputs[0].Name
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidInput(t *testing.T) {
+	m := &module{}
+	pp := getTestProvisioningParameters()
+	pp.Inputs[0].Type = "Trickle"
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp = getTestProvisioningParameters()
+	pp.Inputs[0].Datasource = map[string]interface{}{}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidJobState(t *testing.T) {
+	m := &module{}
+	pp := getTestProvisioningParameters()
+	pp.JobState = "Paused"
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+}
+
+func TestApplyProvisioningParametersDefaults(t *testing.T) {
+	m := &module{}
+	pp := getTestProvisioningParameters()
+	err := m.serviceManager.ApplyProvisioningParametersDefaults(pp)
+	assert.Nil(t, err)
+	assert.Equal(t, defaultStreamingUnits, pp.StreamingUnits)
+	assert.Equal(t, inputTypeStream, pp.Inputs[0].Type)
+	assert.Equal(t, defaultSerialization, pp.Inputs[0].Serialization)
+	assert.Equal(t, defaultSerialization, pp.Outputs[0].Serialization)
+	assert.Equal(t, jobStateStopped, pp.JobState)
+}
+
+func TestBuildARMTemplateParameters(t *testing.T) {
+	pp := getTestProvisioningParameters()
+	armParams := buildARMTemplateParameters(pp)
+	assert.Equal(t, defaultStreamingUnits, armParams["streamingUnits"])
+	assert.Equal(t, pp.Query, armParams["query"])
+	assert.Equal(
+		t,
+		[]interface{}{
+			map[string]interface{}{
+				"name": "input",
+				"properties": map[string]interface{}{
+					"type":          inputTypeStream,
+					"datasource":    pp.Inputs[0].Datasource,
+					"serialization": defaultSerialization,
+				},
+			},
+		},
+		armParams["inputs"],
+	)
+	assert.Equal(
+		t,
+		[]interface{}{
+			map[string]interface{}{
+				"name": "output",
+				"properties": map[string]interface{}{
+					"datasource":    pp.Outputs[0].Datasource,
+					"serialization": defaultSerialization,
+				},
+			},
+		},
+		armParams["outputs"],
+	)
+}
+
+func getTestProvisioningParameters() *ProvisioningParameters {
+	return &ProvisioningParameters{
+		Query: "SELECT * INTO [output] FROM [input]",
+		Inputs: []JobInput{
+			{
+				Name: "input",
+				Datasource: map[string]interface{}{
+					"type": "Microsoft.ServiceBus/EventHub",
+				},
+			},
+		},
+		Outputs: []JobOutput{
+			{
+				Name: "output",
+				Datasource: map[string]interface{}{
+					"type": "Microsoft.Storage/Blob",
+				},
+			},
+		},
+	}
+}
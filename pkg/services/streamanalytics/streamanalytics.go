@@ -0,0 +1,42 @@
+package streamanalytics
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/streamanalytics"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer            arm.Deployer
+	streamAnalyticsManager streamanalytics.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of provisioning Azure Stream Analytics jobs
+func New(
+	armDeployer arm.Deployer,
+	streamAnalyticsManager streamanalytics.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer:            armDeployer,
+			streamAnalyticsManager: streamAnalyticsManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "streamanalytics"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{"Microsoft.StreamAnalytics"}
+}
@@ -0,0 +1,102 @@
+package streamanalytics
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// ProvisioningParameters encapsulates Azure Stream Analytics-specific
+// provisioning options
+type ProvisioningParameters struct {
+	StreamingUnits int `json:"streamingUnits"`
+	// Query is the job's transformation, written in the Stream Analytics query
+	// language. It refers to inputs and outputs by name.
+	Query   string      `json:"query"`
+	Inputs  []JobInput  `json:"inputs"`
+	Outputs []JobOutput `json:"outputs"`
+	// JobState is the state the job is to be left in once provisioned: either
+	// "Running" or "Stopped"
+	JobState string `json:"jobState"`
+}
+
+// JobInput defines a source of the events a Stream Analytics job processes.
+// Datasources and serializations are specified exactly as they are in Azure
+// Resource Manager templates.
+type JobInput struct {
+	Name string `json:"name"`
+	// Type is either "Stream" or "Reference"
+	Type string `json:"type"`
+	// Datasource typically includes credentials for accessing the source
+	Datasource    map[string]interface{} `json:"datasource" secret:"true"`
+	Serialization map[string]interface{} `json:"serialization"`
+}
+
+// JobOutput defines a sink for the results of a Stream Analytics job's query.
+// Datasources and serializations are specified exactly as they are in Azure
+// Resource Manager templates.
+type JobOutput struct {
+	Name string `json:"name"`
+	// Datasource typically includes credentials for accessing the sink
+	Datasource    map[string]interface{} `json:"datasource" secret:"true"`
+	Serialization map[string]interface{} `json:"serialization"`
+}
+
+type streamAnalyticsInstanceDetails struct {
+	ARMDeploymentName string `json:"armDeployment"`
+	JobName           string `json:"jobName"`
+	JobID             string `json:"jobId"`
+}
+
+// UpdatingParameters encapsulates Azure Stream Analytics-specific updating
+// options
+type UpdatingParameters struct {
+	// JobState is the state the job is to be left in once updated: either
+	// "Running" or "Stopped". If unspecified, the job's state is unchanged.
+	JobState string `json:"jobState"`
+}
+
+// BindingParameters encapsulates Azure Stream Analytics-specific binding
+// options
+type BindingParameters struct {
+}
+
+type streamAnalyticsBindingDetails struct {
+}
+
+// Credentials encapsulates the status of an Azure Stream Analytics job. No
+// secrets are involved, since applications interact with a job only by way of
+// its inputs and outputs.
+type Credentials struct {
+	JobID             string   `json:"jobId"`
+	JobName           string   `json:"jobName"`
+	JobState          string   `json:"jobState"`
+	ProvisioningState string   `json:"provisioningState"`
+	StreamingUnits    int      `json:"streamingUnits"`
+	Inputs            []string `json:"inputs"`
+	Outputs           []string `json:"outputs"`
+	// LastOutputEventTime is omitted if the job has never produced output
+	LastOutputEventTime string `json:"lastOutputEventTime,omitempty"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &streamAnalyticsInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &streamAnalyticsBindingDetails{}
+}
@@ -0,0 +1,66 @@
+package streamanalytics
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) ValidateUpdatingParameters(
+	updatingParameters service.UpdatingParameters,
+) error {
+	up, ok := updatingParameters.(*UpdatingParameters)
+	if !ok {
+		return errors.New(
+			"error casting updatingParameters as " +
+				"*streamanalytics.UpdatingParameters",
+		)
+	}
+	return validateJobState(up.JobState)
+}
+
+func (s *serviceManager) GetUpdater(service.Plan) (service.Updater, error) {
+	return service.NewUpdater(
+		service.NewUpdatingStep("setJobState", s.setJobState),
+	)
+}
+
+// setJobState starts or stops the job, as requested. Starting a job that is
+// already running, or stopping one that is already stopped, is left to Azure,
+// which treats either as a no-op.
+func (s *serviceManager) setJobState(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*streamAnalyticsInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *streamAnalyticsInstanceDetails",
+		)
+	}
+	up, ok := instance.UpdatingParameters.(*UpdatingParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.UpdatingParameters as " +
+				"*streamanalytics.UpdatingParameters",
+		)
+	}
+	switch up.JobState {
+	case jobStateRunning:
+		if err := s.streamAnalyticsManager.StartJob(
+			dt.JobName,
+			instance.ResourceGroup,
+		); err != nil {
+			return nil, err
+		}
+	case jobStateStopped:
+		if err := s.streamAnalyticsManager.StopJob(
+			dt.JobName,
+			instance.ResourceGroup,
+		); err != nil {
+			return nil, err
+		}
+	}
+	return dt, nil
+}
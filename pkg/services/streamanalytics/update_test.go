@@ -0,0 +1,25 @@
+package streamanalytics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateUpdatingParameters(t *testing.T) {
+	m := &module{}
+	for _, jobState := range []string{"", jobStateRunning, jobStateStopped} {
+		err := m.serviceManager.ValidateUpdatingParameters(
+			&UpdatingParameters{
+				JobState: jobState,
+			},
+		)
+		assert.Nil(t, err, "jobState: %s", jobState)
+	}
+	err := m.serviceManager.ValidateUpdatingParameters(
+		&UpdatingParameters{
+			JobState: "Paused",
+		},
+	)
+	assert.NotNil(t, err)
+}
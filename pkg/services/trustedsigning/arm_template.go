@@ -0,0 +1,103 @@
+package trustedsigning
+
+// nolint: lll
+var armTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "accountName": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of the code signing account, which is unique across Azure"
+      }
+    },
+    "skuName": {
+      "type": "string",
+      "allowedValues": [
+        "Basic",
+        "Premium"
+      ]
+    },
+    "certificateProfileName": {
+      "type": "string"
+    },
+    "profileType": {
+      "type": "string"
+    },
+    "identityValidationId": {
+      "type": "string"
+    },
+    "includeStreetAddress": {
+      "type": "bool"
+    },
+    "includeCity": {
+      "type": "bool"
+    },
+    "includeState": {
+      "type": "bool"
+    },
+    "includeCountry": {
+      "type": "bool"
+    },
+    "includePostalCode": {
+      "type": "bool"
+    },
+    "tags": {
+      "type": "object"
+    }
+  },
+  "variables": {
+    "apiVersion": "2024-09-30-preview",
+    "accountId": "[resourceId('Microsoft.CodeSigning/codeSigningAccounts', parameters('accountName'))]"
+  },
+  "resources": [
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[parameters('accountName')]",
+      "type": "Microsoft.CodeSigning/codeSigningAccounts",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "properties": {
+        "sku": {
+          "name": "[parameters('skuName')]"
+        }
+      }
+    },
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[concat(parameters('accountName'), '/', parameters('certificateProfileName'))]",
+      "type": "Microsoft.CodeSigning/codeSigningAccounts/certificateProfiles",
+      "dependsOn": [
+        "[variables('accountId')]"
+      ],
+      "properties": {
+        "profileType": "[parameters('profileType')]",
+        "identityValidationId": "[parameters('identityValidationId')]",
+        "includeStreetAddress": "[parameters('includeStreetAddress')]",
+        "includeCity": "[parameters('includeCity')]",
+        "includeState": "[parameters('includeState')]",
+        "includeCountry": "[parameters('includeCountry')]",
+        "includePostalCode": "[parameters('includePostalCode')]"
+      }
+    }
+  ],
+  "outputs": {
+    "accountId": {
+      "type": "string",
+      "value": "[variables('accountId')]"
+    },
+    "accountEndpoint": {
+      "type": "string",
+      "value": "[reference(variables('accountId'), variables('apiVersion')).accountUri]"
+    },
+    "certificateProfileId": {
+      "type": "string",
+      "value": "[resourceId('Microsoft.CodeSigning/codeSigningAccounts/certificateProfiles', parameters('accountName'), parameters('certificateProfileName'))]"
+    }
+  }
+}
+`)
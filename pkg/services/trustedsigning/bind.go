@@ -0,0 +1,145 @@
+package trustedsigning
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	signerRole           = "Trusted Signing Certificate Profile Signer"
+	identityVerifierRole = "Trusted Signing Identity Verifier"
+)
+
+// defaultRole is sufficient to sign code with the instance's certificate
+// profile
+const defaultRole = signerRole
+
+// roles maps the names of the built-in roles that a binding may assign to
+// their definitions
+var roles = map[string]string{
+	signerRole:           "2837e146-70d7-4cfd-ad55-7efa6464f958",
+	identityVerifierRole: "4339b7cf-9826-4e41-b4ed-c7f4505dac08",
+}
+
+var objectIDRegex = regexp.MustCompile(
+	`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-` +
+		`[0-9a-fA-F]{12}$`,
+)
+
+func (s *serviceManager) ValidateBindingParameters(
+	bindingParameters service.BindingParameters,
+) error {
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return errors.New(
+			"error casting bindingParameters as *trustedsigning.BindingParameters",
+		)
+	}
+	if !objectIDRegex.MatchString(bp.PrincipalID) {
+		return service.NewValidationError(
+			"principalId",
+			fmt.Sprintf(`invalid principalId: "%s"`, bp.PrincipalID),
+		)
+	}
+	if _, ok := roles[bp.Role]; bp.Role != "" && !ok {
+		return service.NewValidationError(
+			"role",
+			fmt.Sprintf(
+				`invalid role: "%s"; allowed values are: %s`,
+				bp.Role,
+				strings.Join(getRoleNames(), ", "),
+			),
+		)
+	}
+	return nil
+}
+
+// Bind assigns the requested role to the principal named in the binding
+// parameters. The signer role is assigned on the certificate profile alone, so
+// that the principal can sign with no other profile the account may come to
+// hold; the identity verifier role manages identity validations, which belong
+// to the account, so it is assigned on the account.
+func (s *serviceManager) Bind(
+	instance service.Instance,
+	bindingParameters service.BindingParameters,
+) (service.BindingDetails, error) {
+	dt, ok := instance.Details.(*trustedSigningInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *trustedSigningInstanceDetails",
+		)
+	}
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting bindingParameters as *trustedsigning.BindingParameters",
+		)
+	}
+	bd := &trustedSigningBindingDetails{
+		PrincipalID:        bp.PrincipalID,
+		Role:               bp.Role,
+		Scope:              dt.CertificateProfileID,
+		RoleAssignmentName: uuid.NewV4().String(),
+	}
+	if bd.Role == "" {
+		bd.Role = defaultRole
+	}
+	if bd.Role == identityVerifierRole {
+		bd.Scope = dt.AccountID
+	}
+	if err := s.signingManager.CreateRoleAssignment(
+		bd.Scope,
+		bd.RoleAssignmentName,
+		roles[bd.Role],
+		bd.PrincipalID,
+	); err != nil {
+		return nil, err
+	}
+	return bd, nil
+}
+
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	binding service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*trustedSigningInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *trustedSigningInstanceDetails",
+		)
+	}
+	bd, ok := binding.Details.(*trustedSigningBindingDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting binding.Details as *trustedSigningBindingDetails",
+		)
+	}
+	return &Credentials{
+		Endpoint:               dt.AccountEndpoint,
+		CodeSigningAccountName: dt.AccountName,
+		CertificateProfileName: dt.CertificateProfileName,
+		ResourceGroup:          instance.ResourceGroup,
+		PrincipalID:            bd.PrincipalID,
+		Role:                   bd.Role,
+		RoleAssignmentID: fmt.Sprintf(
+			"%s/providers/Microsoft.Authorization/roleAssignments/%s",
+			bd.Scope,
+			bd.RoleAssignmentName,
+		),
+	}, nil
+}
+
+func getRoleNames() []string {
+	names := make([]string, 0, len(roles))
+	for name := range roles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,57 @@
+package trustedsigning
+
+import (
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateBindingParameters(t *testing.T) {
+	m := &module{}
+	bp := &BindingParameters{}
+	err := m.serviceManager.ValidateBindingParameters(bp)
+	assert.NotNil(t, err)
+	bp.PrincipalID = "7c1e3b5a-9d2f-4a6e-8b0c-4f5d6e7a8b9c"
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.Nil(t, err)
+	bp.Role = "Contributor"
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.NotNil(t, err)
+	bp.Role = identityVerifierRole
+	err = m.serviceManager.ValidateBindingParameters(bp)
+	assert.Nil(t, err)
+}
+
+func TestGetCredentials(t *testing.T) {
+	m := &module{}
+	credentials, err := m.serviceManager.GetCredentials(
+		service.Instance{
+			ResourceGroup: "rg",
+			Details: &trustedSigningInstanceDetails{
+				AccountName:            "signer",
+				AccountEndpoint:        "https://eus.codesigning.azure.net/",
+				CertificateProfileName: "default",
+			},
+		},
+		service.Binding{
+			Details: &trustedSigningBindingDetails{
+				Scope:              "/signer/certificateProfiles/default",
+				RoleAssignmentName: "assignment",
+			},
+		},
+	)
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		"https://eus.codesigning.azure.net/",
+		credentials.(*Credentials).Endpoint,
+	)
+	assert.Equal(t, "signer", credentials.(*Credentials).CodeSigningAccountName)
+	assert.Equal(
+		t,
+		"/signer/certificateProfiles/default/providers/"+
+			"Microsoft.Authorization/roleAssignments/assignment",
+		credentials.(*Credentials).RoleAssignmentID,
+	)
+}
@@ -0,0 +1,51 @@
+package trustedsigning
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:          "8c3f5a1d-6e2b-4d97-a0f4-b19e7c52d830",
+				Name:        "azure-trusted-signing",
+				Description: "Azure Trusted Signing (Experimental)",
+				Bindable:    true,
+				Tags: []string{
+					"Azure",
+					"Trusted Signing",
+					"Code Signing",
+				},
+				// Trusted Signing is only offered in some regions
+				Locations: []string{
+					"eastus",
+					"northeurope",
+					"westcentralus",
+					"westeurope",
+					"westus",
+					"westus2",
+				},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:   "e4a72c96-0b3d-4f18-9c5e-2d7b6f1a8e43",
+				Name: "basic",
+				Description: "Basic tier, for signing at modest volumes, with one " +
+					"certificate profile of each type per account",
+				Free: false,
+				Extended: map[string]interface{}{
+					"skuName": "Basic",
+				},
+			}),
+			service.NewPlan(&service.PlanProperties{
+				ID:   "1b9d4e7f-3a60-4c2e-8f15-a6c08d93b27e",
+				Name: "premium",
+				Description: "Premium tier, for signing at high volumes, with up to " +
+					"ten certificate profiles of each type per account",
+				Free: false,
+				Extended: map[string]interface{}{
+					"skuName": "Premium",
+				},
+			}),
+		),
+	}), nil
+}
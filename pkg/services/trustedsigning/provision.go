@@ -0,0 +1,243 @@
+package trustedsigning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	defaultCertificateProfileName = "default"
+	defaultProfileType            = "PublicTrust"
+)
+
+var profileTypes = []string{
+	"PublicTrust",
+	"PublicTrustTest",
+	"PrivateTrust",
+	"PrivateTrustCIPolicy",
+	"VBSEnclave",
+}
+
+// subjectlessProfileTypes are the profile types whose certificates identify
+// a policy or enclave rather than an organization, so the identity's address
+// can't be included in their subjects
+var subjectlessProfileTypes = []string{
+	"PrivateTrustCIPolicy",
+	"VBSEnclave",
+}
+
+// certificateProfileNameRegex matches the names Trusted Signing allows of
+// certificate profiles: 5 to 100 letters, numbers and hyphens, beginning with
+// a letter, ending with a letter or number and without consecutive hyphens
+var certificateProfileNameRegex = regexp.MustCompile(
+	`^[A-Za-z](?:[A-Za-z0-9]|-[A-Za-z0-9]){4,99}$`,
+)
+
+var identityValidationIDRegex = regexp.MustCompile(
+	`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-` +
+		`[0-9a-fA-F]{12}$`,
+)
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*trustedsigning.ProvisioningParameters",
+		)
+	}
+	profile := pp.CertificateProfile
+	if profile == nil {
+		return service.NewValidationError(
+			"certificateProfile",
+			"a certificate profile is required",
+		)
+	}
+	if profile.Name != "" && !certificateProfileNameRegex.MatchString(
+		profile.Name,
+	) {
+		return service.NewValidationError(
+			"certificateProfile.name",
+			fmt.Sprintf(
+				`invalid certificate profile name: "%s"; names must be 5 to 100 `+
+					"letters, numbers and hyphens, beginning with a letter, ending "+
+					"with a letter or number and without consecutive hyphens",
+				profile.Name,
+			),
+		)
+	}
+	if profile.ProfileType != "" && !contains(profileTypes, profile.ProfileType) {
+		return service.NewValidationError(
+			"certificateProfile.profileType",
+			fmt.Sprintf(
+				`invalid profileType: "%s"; allowed values are: %s`,
+				profile.ProfileType,
+				strings.Join(profileTypes, ", "),
+			),
+		)
+	}
+	if !identityValidationIDRegex.MatchString(profile.IdentityValidationID) {
+		return service.NewValidationError(
+			"certificateProfile.identityValidationId",
+			fmt.Sprintf(
+				`invalid identityValidationId: "%s"`,
+				profile.IdentityValidationID,
+			),
+		)
+	}
+	if contains(subjectlessProfileTypes, profile.ProfileType) &&
+		(profile.IncludeStreetAddress || profile.IncludeCity ||
+			profile.IncludeState || profile.IncludeCountry ||
+			profile.IncludePostalCode) {
+		return service.NewValidationError(
+			"certificateProfile",
+			fmt.Sprintf(
+				"the subjects of %s certificates can't include an address",
+				profile.ProfileType,
+			),
+		)
+	}
+	return nil
+}
+
+func (s *serviceManager) ApplyProvisioningParametersDefaults(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*trustedsigning.ProvisioningParameters",
+		)
+	}
+	if pp.CertificateProfile == nil {
+		pp.CertificateProfile = &CertificateProfile{}
+	}
+	if pp.CertificateProfile.Name == "" {
+		pp.CertificateProfile.Name = defaultCertificateProfileName
+	}
+	if pp.CertificateProfile.ProfileType == "" {
+		pp.CertificateProfile.ProfileType = defaultProfileType
+	}
+	return nil
+}
+
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep("deployARMTemplate", s.deployARMTemplate),
+	)
+}
+
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*trustedSigningInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *trustedSigningInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*trustedsigning.ProvisioningParameters",
+		)
+	}
+	dt.ARMDeploymentName = uuid.NewV4().String()
+	// Account names are limited to 24 characters and must begin with a letter
+	dt.AccountName = "sign" +
+		strings.Replace(uuid.NewV4().String(), "-", "", -1)[:20]
+	dt.CertificateProfileName = pp.CertificateProfile.Name
+	return dt, nil
+}
+
+func (s *serviceManager) deployARMTemplate(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*trustedSigningInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *trustedSigningInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*trustedsigning.ProvisioningParameters",
+		)
+	}
+	outputs, err := s.armDeployer.Deploy(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateBytes,
+		nil,
+		buildARMTemplateParameters(
+			instance.Plan.GetProperties().Extended,
+			pp.CertificateProfile,
+			dt,
+		),
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+	for output, field := range map[string]*string{
+		"accountId":            &dt.AccountID,
+		"accountEndpoint":      &dt.AccountEndpoint,
+		"certificateProfileId": &dt.CertificateProfileID,
+	} {
+		value, ok := outputs[output].(string)
+		if !ok {
+			return nil, fmt.Errorf(
+				`error retrieving "%s" from deployment`,
+				output,
+			)
+		}
+		*field = value
+	}
+	return dt, nil
+}
+
+func buildARMTemplateParameters(
+	planExtended map[string]interface{},
+	profile *CertificateProfile,
+	dt *trustedSigningInstanceDetails,
+) map[string]interface{} {
+	return map[string]interface{}{
+		"accountName":            dt.AccountName,
+		"skuName":                planExtended["skuName"],
+		"certificateProfileName": dt.CertificateProfileName,
+		"profileType":            profile.ProfileType,
+		"identityValidationId":   profile.IdentityValidationID,
+		"includeStreetAddress":   profile.IncludeStreetAddress,
+		"includeCity":            profile.IncludeCity,
+		"includeState":           profile.IncludeState,
+		"includeCountry":         profile.IncludeCountry,
+		"includePostalCode":      profile.IncludePostalCode,
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
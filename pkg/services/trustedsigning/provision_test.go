@@ -0,0 +1,81 @@
+package trustedsigning
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testIdentityValidationID = "5d1c9f2e-7b4a-4e3d-a6f8-0c2b9e1d7a53"
+
+func TestValidateProvisioningParametersWithoutCertificateProfile(
+	t *testing.T,
+) {
+	m := &module{}
+	pp := &ProvisioningParameters{}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.CertificateProfile = &CertificateProfile{}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.CertificateProfile.IdentityValidationID = testIdentityValidationID
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithCertificateProfileName(
+	t *testing.T,
+) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		CertificateProfile: &CertificateProfile{
+			Name:                 "rel",
+			IdentityValidationID: testIdentityValidationID,
+		},
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.CertificateProfile.Name = "release--signing"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.CertificateProfile.Name = "release-signing-"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.CertificateProfile.Name = "release-signing"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithProfileType(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		CertificateProfile: &CertificateProfile{
+			ProfileType:          "EVTrust",
+			IdentityValidationID: testIdentityValidationID,
+			IncludeCity:          true,
+		},
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.CertificateProfile.ProfileType = "VBSEnclave"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.CertificateProfile.ProfileType = "PrivateTrust"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestApplyProvisioningParametersDefaults(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		CertificateProfile: &CertificateProfile{
+			IdentityValidationID: testIdentityValidationID,
+		},
+	}
+	err := m.serviceManager.ApplyProvisioningParametersDefaults(pp)
+	assert.Nil(t, err)
+	assert.Equal(t, defaultCertificateProfileName, pp.CertificateProfile.Name)
+	assert.Equal(t, defaultProfileType, pp.CertificateProfile.ProfileType)
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
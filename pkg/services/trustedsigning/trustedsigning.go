@@ -0,0 +1,46 @@
+package trustedsigning
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/trustedsigning"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer    arm.Deployer
+	signingManager trustedsigning.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of provisioning Azure Trusted Signing accounts,
+// each with a certificate profile
+func New(
+	armDeployer arm.Deployer,
+	signingManager trustedsigning.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer:    armDeployer,
+			signingManager: signingManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "trustedsigning"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{
+		"Microsoft.CodeSigning",
+		"Microsoft.Authorization",
+	}
+}
@@ -0,0 +1,95 @@
+package trustedsigning
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// ProvisioningParameters encapsulates Azure Trusted Signing-specific
+// provisioning options
+type ProvisioningParameters struct {
+	CertificateProfile *CertificateProfile `json:"certificateProfile"`
+}
+
+// CertificateProfile encapsulates the certificate profile created in the
+// account, from which code is signed
+type CertificateProfile struct {
+	Name string `json:"name"`
+	// ProfileType is one of PublicTrust, PublicTrustTest, PrivateTrust,
+	// PrivateTrustCIPolicy and VBSEnclave
+	ProfileType string `json:"profileType"`
+	// IdentityValidationID identifies the validated identity of the
+	// organization or individual the profile's certificates are issued to. The
+	// identity must have been validated in the account's tenant beforehand.
+	IdentityValidationID string `json:"identityValidationId"`
+	// The following determine which parts of the validated identity's address
+	// are included in the subject of the profile's certificates
+	IncludeStreetAddress bool `json:"includeStreetAddress"`
+	IncludeCity          bool `json:"includeCity"`
+	IncludeState         bool `json:"includeState"`
+	IncludeCountry       bool `json:"includeCountry"`
+	IncludePostalCode    bool `json:"includePostalCode"`
+}
+
+type trustedSigningInstanceDetails struct {
+	ARMDeploymentName      string `json:"armDeployment"`
+	AccountName            string `json:"accountName"`
+	AccountID              string `json:"accountId"`
+	AccountEndpoint        string `json:"accountEndpoint"`
+	CertificateProfileName string `json:"certificateProfileName"`
+	CertificateProfileID   string `json:"certificateProfileId"`
+}
+
+// UpdatingParameters encapsulates Azure Trusted Signing-specific updating
+// options
+type UpdatingParameters struct {
+}
+
+// BindingParameters encapsulates Azure Trusted Signing-specific binding options
+type BindingParameters struct {
+	PrincipalID string `json:"principalId"`
+	Role        string `json:"role"`
+}
+
+type trustedSigningBindingDetails struct {
+	PrincipalID        string `json:"principalId"`
+	Role               string `json:"role"`
+	Scope              string `json:"scope"`
+	RoleAssignmentName string `json:"roleAssignmentName"`
+}
+
+// Credentials encapsulates Azure Trusted Signing-specific connection details.
+// The endpoint, account name and profile name are what signing tools, such as
+// SignTool with the Trusted Signing dlib, are configured with.
+type Credentials struct {
+	Endpoint               string `json:"endpoint"`
+	CodeSigningAccountName string `json:"codeSigningAccountName"`
+	CertificateProfileName string `json:"certificateProfileName"`
+	ResourceGroup          string `json:"resourceGroup"`
+	PrincipalID            string `json:"principalId"`
+	Role                   string `json:"role"`
+	RoleAssignmentID       string `json:"roleAssignmentId"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &trustedSigningInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &trustedSigningBindingDetails{}
+}
@@ -0,0 +1,23 @@
+package trustedsigning
+
+import (
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) Unbind(
+	_ service.Instance,
+	bindingDetails service.BindingDetails,
+) error {
+	bd, ok := bindingDetails.(*trustedSigningBindingDetails)
+	if !ok {
+		return errors.New(
+			"error casting bindingDetails as *trustedSigningBindingDetails",
+		)
+	}
+	return s.signingManager.DeleteRoleAssignment(
+		bd.Scope,
+		bd.RoleAssignmentName,
+	)
+}
@@ -0,0 +1,25 @@
+package virtualmachine
+
+import (
+	"context"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// Deactivate deallocates a newly provisioned virtual machine whose activation
+// is deferred, exactly as Suspend does for an idle one, so that compute isn't
+// billed until activation
+func (s *serviceManager) Deactivate(
+	ctx context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	return s.Suspend(ctx, instance)
+}
+
+// Activate starts a virtual machine that was deallocated by Deactivate
+func (s *serviceManager) Activate(
+	ctx context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	return s.Resume(ctx, instance)
+}
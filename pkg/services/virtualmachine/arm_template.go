@@ -0,0 +1,248 @@
+package virtualmachine
+
+// nolint: lll
+var armTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "vmName": {
+      "type": "string"
+    },
+    "vmSize": {
+      "type": "string"
+    },
+    "networkInterfaceName": {
+      "type": "string"
+    },
+    "osDiskName": {
+      "type": "string"
+    },
+    "osDiskStorageAccountType": {
+      "type": "string"
+    },
+    {{- if .setOSDiskSize }}
+    "osDiskSizeGb": {
+      "type": "int"
+    },
+    {{- end }}
+    "imagePublisher": {
+      "type": "string"
+    },
+    "imageOffer": {
+      "type": "string"
+    },
+    "imageSku": {
+      "type": "string"
+    },
+    "imageVersion": {
+      "type": "string"
+    },
+    "adminUsername": {
+      "type": "string"
+    },
+    {{- if .useSSHPublicKey }}
+    "sshPublicKey": {
+      "type": "string"
+    },
+    {{- else }}
+    "adminPassword": {
+      "type": "securestring"
+    },
+    {{- end }}
+    {{- if .createPublicIPAddress }}
+    "publicIPAddressName": {
+      "type": "string"
+    },
+    {{- end }}
+    {{- if .createVirtualNetwork }}
+    "virtualNetworkName": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of the virtual network created for the virtual machine"
+      }
+    },
+    {{- else }}
+    "subnetId": {
+      "type": "string",
+      "metadata": {
+        "description": "Resource ID of the subnet the virtual machine is attached to"
+      }
+    },
+    {{- end }}
+    "tags": {
+      "type": "object"
+    }
+  },
+  "variables": {
+    {{- if .createVirtualNetwork }}
+    "subnetId": "[resourceId('Microsoft.Network/virtualNetworks/subnets', parameters('virtualNetworkName'), 'default')]",
+    {{- else }}
+    "subnetId": "[parameters('subnetId')]",
+    {{- end }}
+    "vmId": "[resourceId('Microsoft.Compute/virtualMachines', parameters('vmName'))]",
+    "networkInterfaceId": "[resourceId('Microsoft.Network/networkInterfaces', parameters('networkInterfaceName'))]"
+  },
+  "resources": [
+    {{- if .createVirtualNetwork }}
+    {
+      "apiVersion": "2021-05-01",
+      "name": "[parameters('virtualNetworkName')]",
+      "type": "Microsoft.Network/virtualNetworks",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "properties": {
+        "addressSpace": {
+          "addressPrefixes": [
+            "10.0.0.0/16"
+          ]
+        },
+        "subnets": [
+          {
+            "name": "default",
+            "properties": {
+              "addressPrefix": "10.0.0.0/24"
+            }
+          }
+        ]
+      }
+    },
+    {{- end }}
+    {{- if .createPublicIPAddress }}
+    {
+      "apiVersion": "2021-05-01",
+      "name": "[parameters('publicIPAddressName')]",
+      "type": "Microsoft.Network/publicIPAddresses",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "sku": {
+        "name": "Standard"
+      },
+      "properties": {
+        "publicIPAllocationMethod": "Static",
+        "dnsSettings": {
+          "domainNameLabel": "[parameters('vmName')]"
+        }
+      }
+    },
+    {{- end }}
+    {
+      "apiVersion": "2021-05-01",
+      "name": "[parameters('networkInterfaceName')]",
+      "type": "Microsoft.Network/networkInterfaces",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      {{- if or .createVirtualNetwork .createPublicIPAddress }}
+      "dependsOn": [
+        {{- if .createVirtualNetwork }}
+        "[resourceId('Microsoft.Network/virtualNetworks', parameters('virtualNetworkName'))]"{{ if .createPublicIPAddress }},{{ end }}
+        {{- end }}
+        {{- if .createPublicIPAddress }}
+        "[resourceId('Microsoft.Network/publicIPAddresses', parameters('publicIPAddressName'))]"
+        {{- end }}
+      ],
+      {{- end }}
+      "properties": {
+        "ipConfigurations": [
+          {
+            "name": "ipconfig1",
+            "properties": {
+              "privateIPAllocationMethod": "Dynamic",
+              {{- if .createPublicIPAddress }}
+              "publicIPAddress": {
+                "id": "[resourceId('Microsoft.Network/publicIPAddresses', parameters('publicIPAddressName'))]"
+              },
+              {{- end }}
+              "subnet": {
+                "id": "[variables('subnetId')]"
+              }
+            }
+          }
+        ]
+      }
+    },
+    {
+      "apiVersion": "2021-07-01",
+      "name": "[parameters('vmName')]",
+      "type": "Microsoft.Compute/virtualMachines",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "dependsOn": [
+        "[variables('networkInterfaceId')]"
+      ],
+      "properties": {
+        "hardwareProfile": {
+          "vmSize": "[parameters('vmSize')]"
+        },
+        "storageProfile": {
+          "imageReference": {
+            "publisher": "[parameters('imagePublisher')]",
+            "offer": "[parameters('imageOffer')]",
+            "sku": "[parameters('imageSku')]",
+            "version": "[parameters('imageVersion')]"
+          },
+          "osDisk": {
+            "name": "[parameters('osDiskName')]",
+            "createOption": "FromImage",
+            {{- if .setOSDiskSize }}
+            "diskSizeGB": "[parameters('osDiskSizeGb')]",
+            {{- end }}
+            "managedDisk": {
+              "storageAccountType": "[parameters('osDiskStorageAccountType')]"
+            }
+          }
+        },
+        "osProfile": {
+          "computerName": "[parameters('vmName')]",
+          "adminUsername": "[parameters('adminUsername')]",
+          {{- if .useSSHPublicKey }}
+          "linuxConfiguration": {
+            "disablePasswordAuthentication": true,
+            "ssh": {
+              "publicKeys": [
+                {
+                  "path": "[concat('/home/', parameters('adminUsername'), '/.ssh/authorized_keys')]",
+                  "keyData": "[parameters('sshPublicKey')]"
+                }
+              ]
+            }
+          }
+          {{- else }}
+          "adminPassword": "[parameters('adminPassword')]"
+          {{- end }}
+        },
+        "networkProfile": {
+          "networkInterfaces": [
+            {
+              "id": "[variables('networkInterfaceId')]"
+            }
+          ]
+        }
+      }
+    }
+  ],
+  "outputs": {
+    "vmId": {
+      "type": "string",
+      "value": "[variables('vmId')]"
+    },
+    "privateIPAddress": {
+      "type": "string",
+      "value": "[reference(variables('networkInterfaceId'), '2021-05-01').ipConfigurations[0].properties.privateIPAddress]"
+    }
+    {{- if .createPublicIPAddress }},
+    "publicIPAddress": {
+      "type": "string",
+      "value": "[reference(resourceId('Microsoft.Network/publicIPAddresses', parameters('publicIPAddressName')), '2021-05-01').ipAddress]"
+    },
+    "fqdn": {
+      "type": "string",
+      "value": "[reference(resourceId('Microsoft.Network/publicIPAddresses', parameters('publicIPAddressName')), '2021-05-01').dnsSettings.fqdn]"
+    }
+    {{- end }}
+  }
+}
+`)
@@ -0,0 +1,54 @@
+package virtualmachine
+
+import (
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) ValidateBindingParameters(
+	service.BindingParameters,
+) error {
+	// There are no parameters for binding to a virtual machine, so there is
+	// nothing to validate
+	return nil
+}
+
+func (s *serviceManager) Bind(
+	service.Instance,
+	service.BindingParameters,
+) (service.BindingDetails, error) {
+	return &vmBindingDetails{}, nil
+}
+
+// GetCredentials returns the virtual machine's identifiers and what's needed
+// to connect to it-- ssh for Linux and rdp for Windows. No password is
+// returned if the administrator authenticates using an SSH public key.
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	_ service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*vmInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *vmInstanceDetails",
+		)
+	}
+	credentials := &vmCredentials{
+		VMName:           dt.VMName,
+		VMID:             dt.VMID,
+		PrivateIPAddress: dt.PrivateIPAddress,
+		PublicIPAddress:  dt.PublicIPAddress,
+		FQDN:             dt.FQDN,
+		Username:         dt.AdminUsername,
+		Password:         dt.AdminPassword,
+	}
+	if dt.Image != nil && dt.Image.OSType == osTypeWindows {
+		credentials.Protocol = "rdp"
+		credentials.Port = 3389
+	} else {
+		credentials.Protocol = "ssh"
+		credentials.Port = 22
+	}
+	return credentials, nil
+}
@@ -0,0 +1,24 @@
+package virtualmachine
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:          "8c731216-4fdd-4687-8623-6403a7cb9201",
+				Name:        "azure-virtual-machine",
+				Description: "Azure Virtual Machine (Experimental)",
+				Bindable:    true,
+				Tags:        []string{"Azure", "Virtual Machine", "VM", "Compute"},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:          "3c572919-720f-4d9e-a217-564f0744b000",
+				Name:        "standard",
+				Description: "A virtual machine created from a marketplace image",
+				Free:        false,
+			}),
+		),
+	}), nil
+}
@@ -0,0 +1,160 @@
+package virtualmachine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+func (s *serviceManager) GetDeprovisioner(
+	service.Plan,
+) (service.Deprovisioner, error) {
+	return service.NewDeprovisioner(
+		service.NewDeprovisioningStep("deleteARMDeployment", s.deleteARMDeployment),
+		service.NewDeprovisioningStep(
+			"deleteVirtualMachine",
+			s.deleteVirtualMachine,
+		),
+		// The virtual machine's dependent resources can't be deleted while the
+		// virtual machine still uses them
+		service.NewDeprovisioningStep(
+			"deleteNetworkInterface",
+			s.deleteNetworkInterface,
+		),
+		service.NewDeprovisioningStep("deleteOSDisk", s.deleteOSDisk),
+		service.NewDeprovisioningStep(
+			"deletePublicIPAddress",
+			s.deletePublicIPAddress,
+		),
+		service.NewDeprovisioningStep(
+			"deleteVirtualNetwork",
+			s.deleteVirtualNetwork,
+		),
+	)
+}
+
+func (s *serviceManager) deleteARMDeployment(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*vmInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *vmInstanceDetails",
+		)
+	}
+	if err := s.armDeployer.Delete(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, fmt.Errorf("error deleting ARM deployment: %s", err)
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deleteVirtualMachine(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*vmInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *vmInstanceDetails",
+		)
+	}
+	if err := s.vmManager.DeleteVirtualMachine(
+		dt.VMName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deleteNetworkInterface(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*vmInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *vmInstanceDetails",
+		)
+	}
+	if err := s.vmManager.DeleteNetworkInterface(
+		dt.NetworkInterfaceName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+// deleteOSDisk deletes the virtual machine's OS disk, which, being a managed
+// disk, outlives the virtual machine itself
+func (s *serviceManager) deleteOSDisk(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*vmInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *vmInstanceDetails",
+		)
+	}
+	if err := s.vmManager.DeleteDisk(
+		dt.OSDiskName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deletePublicIPAddress(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*vmInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *vmInstanceDetails",
+		)
+	}
+	if dt.PublicIPAddressName == "" {
+		return dt, nil
+	}
+	if err := s.vmManager.DeletePublicIPAddress(
+		dt.PublicIPAddressName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+// deleteVirtualNetwork deletes the virtual network that was created for the
+// virtual machine if, and only if, a subnet wasn't supplied at provisioning
+func (s *serviceManager) deleteVirtualNetwork(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*vmInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *vmInstanceDetails",
+		)
+	}
+	if dt.VirtualNetworkName == "" {
+		return dt, nil
+	}
+	if err := s.vmManager.DeleteVirtualNetwork(
+		dt.VirtualNetworkName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
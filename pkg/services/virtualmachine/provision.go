@@ -0,0 +1,544 @@
+package virtualmachine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	vm "github.com/Azure/open-service-broker-azure/pkg/azure/virtualmachine"
+	"github.com/Azure/open-service-broker-azure/pkg/generate"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	defaultVMSize                   = "Standard_B2s"
+	defaultImageVersion             = "latest"
+	defaultOSDiskStorageAccountType = "StandardSSD_LRS"
+	defaultAdminUsername            = "osbaadmin"
+	osTypeLinux                     = "Linux"
+	osTypeWindows                   = "Windows"
+	minOSDiskSizeGB                 = 30
+	maxOSDiskSizeGB                 = 4095
+	minAdminPasswordLength          = 12
+	maxAdminPasswordLength          = 72
+)
+
+// defaultImage is the image virtual machines are created from if the
+// provisioning request doesn't say otherwise
+var defaultImage = ImageParameters{
+	Publisher: "Canonical",
+	Offer:     "0001-com-ubuntu-server-focal",
+	SKU:       "20_04-lts-gen2",
+	Version:   defaultImageVersion,
+	OSType:    osTypeLinux,
+}
+
+var osDiskStorageAccountTypes = []string{
+	"Standard_LRS",
+	"StandardSSD_LRS",
+	"Premium_LRS",
+}
+
+var (
+	// Usernames may contain letters, numbers, hyphens, underscores, and
+	// periods, but may not begin with a hyphen or period or exceed 20
+	// characters
+	adminUsernameRegex = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9_.-]{0,19}$`)
+	sshPublicKeyRegex  = regexp.MustCompile(`^ssh-rsa AAAA[0-9A-Za-z+/]+=*( .*)?$`)
+)
+
+// reservedAdminUsernames are names that Azure refuses to create virtual
+// machines' administrators with
+var reservedAdminUsernames = []string{
+	"admin",
+	"administrator",
+	"guest",
+	"root",
+	"test",
+	"user",
+}
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*virtualmachine.ProvisioningParameters",
+		)
+	}
+	if pp.Size != "" && strings.TrimSpace(pp.Size) == "" {
+		return service.NewValidationError("size", "size must not be blank")
+	}
+	if pp.Image != nil {
+		if err := validateImageParameters(pp.Image); err != nil {
+			return err
+		}
+	}
+	if pp.OSDisk != nil {
+		if err := validateOSDiskParameters(pp.OSDisk); err != nil {
+			return err
+		}
+	}
+	if pp.SubnetID != "" && !az.IsValidSubnetID(pp.SubnetID) {
+		return service.NewValidationError(
+			"subnetId",
+			fmt.Sprintf(`invalid subnetId: "%s"`, pp.SubnetID),
+		)
+	}
+	return validateCredentialParameters(pp)
+}
+
+func validateImageParameters(image *ImageParameters) error {
+	if image.Publisher == "" {
+		return service.NewValidationError(
+			"image.publisher",
+			"publisher must be specified",
+		)
+	}
+	if image.Offer == "" {
+		return service.NewValidationError(
+			"image.offer",
+			"offer must be specified",
+		)
+	}
+	if image.SKU == "" {
+		return service.NewValidationError(
+			"image.sku",
+			"sku must be specified",
+		)
+	}
+	if image.OSType != osTypeLinux && image.OSType != osTypeWindows {
+		return service.NewValidationError(
+			"image.osType",
+			fmt.Sprintf(
+				`invalid osType: "%s"; allowed values are %s and %s`,
+				image.OSType,
+				osTypeLinux,
+				osTypeWindows,
+			),
+		)
+	}
+	return nil
+}
+
+func validateOSDiskParameters(osDisk *OSDiskParameters) error {
+	if osDisk.SizeGB != 0 &&
+		(osDisk.SizeGB < minOSDiskSizeGB || osDisk.SizeGB > maxOSDiskSizeGB) {
+		return service.NewValidationError(
+			"osDisk.sizeGb",
+			fmt.Sprintf(
+				"invalid sizeGb: %d; sizeGb must be between %d and %d",
+				osDisk.SizeGB,
+				minOSDiskSizeGB,
+				maxOSDiskSizeGB,
+			),
+		)
+	}
+	if osDisk.StorageAccountType != "" &&
+		!isValidOSDiskStorageAccountType(osDisk.StorageAccountType) {
+		return service.NewValidationError(
+			"osDisk.storageAccountType",
+			fmt.Sprintf(
+				`invalid storageAccountType: "%s"; allowed values are: %s`,
+				osDisk.StorageAccountType,
+				strings.Join(osDiskStorageAccountTypes, ", "),
+			),
+		)
+	}
+	return nil
+}
+
+func validateCredentialParameters(pp *ProvisioningParameters) error {
+	if pp.AdminUsername != "" {
+		if !adminUsernameRegex.MatchString(pp.AdminUsername) {
+			return service.NewValidationError(
+				"adminUsername",
+				fmt.Sprintf(`invalid adminUsername: "%s"`, pp.AdminUsername),
+			)
+		}
+		for _, reserved := range reservedAdminUsernames {
+			if strings.EqualFold(pp.AdminUsername, reserved) {
+				return service.NewValidationError(
+					"adminUsername",
+					fmt.Sprintf(`adminUsername "%s" is reserved`, pp.AdminUsername),
+				)
+			}
+		}
+	}
+	if pp.SSHPublicKey != "" {
+		if getImage(pp).OSType != osTypeLinux {
+			return service.NewValidationError(
+				"sshPublicKey",
+				"an SSH public key may only be specified for Linux virtual machines",
+			)
+		}
+		if pp.AdminPassword != "" {
+			return service.NewValidationError(
+				"sshPublicKey",
+				"only one of sshPublicKey or adminPassword may be specified",
+			)
+		}
+		if !sshPublicKeyRegex.MatchString(strings.TrimSpace(pp.SSHPublicKey)) {
+			return service.NewValidationError(
+				"sshPublicKey",
+				"sshPublicKey must be an RSA public key in OpenSSH format",
+			)
+		}
+	}
+	if pp.AdminPassword != "" && !isValidAdminPassword(pp.AdminPassword) {
+		// Don't include the password itself in the error
+		return service.NewValidationError(
+			"adminPassword",
+			fmt.Sprintf(
+				"adminPassword must be between %d and %d characters long and "+
+					"contain three of the following: a lowercase letter, an "+
+					"uppercase letter, a number, and a special character",
+				minAdminPasswordLength,
+				maxAdminPasswordLength,
+			),
+		)
+	}
+	return nil
+}
+
+func (s *serviceManager) ApplyProvisioningParametersDefaults(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*virtualmachine.ProvisioningParameters",
+		)
+	}
+	if pp.Size == "" {
+		pp.Size = defaultVMSize
+	}
+	image := getImage(pp)
+	pp.Image = &image
+	osDisk := getOSDisk(pp)
+	pp.OSDisk = &osDisk
+	if pp.AdminUsername == "" {
+		pp.AdminUsername = defaultAdminUsername
+	}
+	return nil
+}
+
+// GetSKUUsages maps a virtual machine to its size. Disks and public IP
+// addresses cost comparatively little and aren't accounted for.
+func (s *serviceManager) GetSKUUsages(
+	_ service.Plan,
+	provisioningParameters service.ProvisioningParameters,
+) ([]service.SKUUsage, error) {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting provisioningParameters as " +
+				"*virtualmachine.ProvisioningParameters",
+		)
+	}
+	size := pp.Size
+	if size == "" {
+		size = defaultVMSize
+	}
+	return []service.SKUUsage{
+		{
+			SKU:      size,
+			Quantity: 1,
+		},
+	}, nil
+}
+
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep("deployARMTemplate", s.deployARMTemplate),
+		service.NewProvisioningStep("waitForRunning", s.waitForRunning),
+	)
+}
+
+// preProvision generates names for new resources and settles on the size,
+// image, and credentials the virtual machine will be created with. Whether
+// the size and image are available can only be determined once the location is
+// known, so that is checked here rather than when parameters are first
+// validated.
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*vmInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *vmInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*virtualmachine.ProvisioningParameters",
+		)
+	}
+	dt.VMSize = pp.Size
+	if dt.VMSize == "" {
+		dt.VMSize = defaultVMSize
+	}
+	available, err := s.vmManager.IsVMSizeAvailable(
+		instance.Location,
+		dt.VMSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !available {
+		return nil, service.NewValidationError(
+			"size",
+			fmt.Sprintf(
+				`vm size "%s" is not available in location "%s"`,
+				dt.VMSize,
+				instance.Location,
+			),
+		)
+	}
+	image := getImage(pp)
+	available, err = s.vmManager.IsImageAvailable(
+		instance.Location,
+		vm.ImageReference{
+			Publisher: image.Publisher,
+			Offer:     image.Offer,
+			SKU:       image.SKU,
+			Version:   image.Version,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !available {
+		return nil, service.NewValidationError(
+			"image",
+			fmt.Sprintf(
+				`image "%s:%s:%s:%s" is not available in location "%s"`,
+				image.Publisher,
+				image.Offer,
+				image.SKU,
+				image.Version,
+				instance.Location,
+			),
+		)
+	}
+	dt.Image = &image
+	dt.ARMDeploymentName = uuid.NewV4().String()
+	dt.VMName = generate.NewIdentifier()
+	dt.NetworkInterfaceName = dt.VMName + "-nic"
+	dt.OSDiskName = dt.VMName + "-osdisk"
+	if !pp.DisablePublicIPAddress {
+		dt.PublicIPAddressName = dt.VMName + "-ip"
+	}
+	// If no subnet was specified, a virtual network is created for the
+	// virtual machine's exclusive use
+	if pp.SubnetID == "" {
+		dt.VirtualNetworkName = dt.VMName + "-vnet"
+	}
+	dt.AdminUsername = pp.AdminUsername
+	if dt.AdminUsername == "" {
+		dt.AdminUsername = defaultAdminUsername
+	}
+	if pp.SSHPublicKey == "" {
+		dt.AdminPassword = pp.AdminPassword
+		if dt.AdminPassword == "" {
+			dt.AdminPassword = generate.NewPassword()
+		}
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) deployARMTemplate(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*vmInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *vmInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*virtualmachine.ProvisioningParameters",
+		)
+	}
+	osDisk := getOSDisk(pp)
+	armParams := map[string]interface{}{
+		"vmName":                   dt.VMName,
+		"vmSize":                   dt.VMSize,
+		"networkInterfaceName":     dt.NetworkInterfaceName,
+		"osDiskName":               dt.OSDiskName,
+		"osDiskStorageAccountType": osDisk.StorageAccountType,
+		"imagePublisher":           dt.Image.Publisher,
+		"imageOffer":               dt.Image.Offer,
+		"imageSku":                 dt.Image.SKU,
+		"imageVersion":             dt.Image.Version,
+		"adminUsername":            dt.AdminUsername,
+	}
+	if osDisk.SizeGB != 0 {
+		armParams["osDiskSizeGb"] = osDisk.SizeGB
+	}
+	if dt.PublicIPAddressName != "" {
+		armParams["publicIPAddressName"] = dt.PublicIPAddressName
+	}
+	if dt.VirtualNetworkName != "" {
+		armParams["virtualNetworkName"] = dt.VirtualNetworkName
+	} else {
+		armParams["subnetId"] = pp.SubnetID
+	}
+	if dt.AdminPassword != "" {
+		armParams["adminPassword"] = dt.AdminPassword
+	} else {
+		armParams["sshPublicKey"] = strings.TrimSpace(pp.SSHPublicKey)
+	}
+	outputs, err := s.armDeployer.Deploy(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateBytes,
+		map[string]interface{}{ // Go template params
+			"createVirtualNetwork":  dt.VirtualNetworkName != "",
+			"createPublicIPAddress": dt.PublicIPAddressName != "",
+			"useSSHPublicKey":       dt.AdminPassword == "",
+			"setOSDiskSize":         osDisk.SizeGB != 0,
+		},
+		armParams,
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+	vmID, ok := outputs["vmId"].(string)
+	if !ok {
+		return nil, errors.New("error retrieving vm id from deployment")
+	}
+	dt.VMID = vmID
+	privateIPAddress, ok := outputs["privateIPAddress"].(string)
+	if !ok {
+		return nil, errors.New(
+			"error retrieving private IP address from deployment",
+		)
+	}
+	dt.PrivateIPAddress = privateIPAddress
+	if dt.PublicIPAddressName == "" {
+		return dt, nil
+	}
+	publicIPAddress, ok := outputs["publicIPAddress"].(string)
+	if !ok {
+		return nil, errors.New(
+			"error retrieving public IP address from deployment",
+		)
+	}
+	dt.PublicIPAddress = publicIPAddress
+	fqdn, ok := outputs["fqdn"].(string)
+	if !ok {
+		return nil, errors.New(
+			"error retrieving fully qualified domain name from deployment",
+		)
+	}
+	dt.FQDN = fqdn
+	return dt, nil
+}
+
+// waitForRunning waits for the virtual machine to start. Deployment completes
+// once the virtual machine has been created, which may be before it is
+// running.
+func (s *serviceManager) waitForRunning(
+	ctx context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*vmInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *vmInstanceDetails",
+		)
+	}
+	if err := s.vmManager.WaitForRunning(
+		ctx,
+		dt.VMName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, service.WrapError(
+			err,
+			"error waiting for virtual machine to start",
+		)
+	}
+	return dt, nil
+}
+
+func getImage(pp *ProvisioningParameters) ImageParameters {
+	if pp.Image == nil {
+		return defaultImage
+	}
+	image := *pp.Image
+	if image.Version == "" {
+		image.Version = defaultImageVersion
+	}
+	return image
+}
+
+func getOSDisk(pp *ProvisioningParameters) OSDiskParameters {
+	var osDisk OSDiskParameters
+	if pp.OSDisk != nil {
+		osDisk = *pp.OSDisk
+	}
+	if osDisk.StorageAccountType == "" {
+		osDisk.StorageAccountType = defaultOSDiskStorageAccountType
+	}
+	return osDisk
+}
+
+func isValidOSDiskStorageAccountType(storageAccountType string) bool {
+	for _, t := range osDiskStorageAccountTypes {
+		if storageAccountType == t {
+			return true
+		}
+	}
+	return false
+}
+
+// isValidAdminPassword returns a bool indicating whether the given password
+// meets Azure's length and complexity requirements for virtual machine
+// administrators
+func isValidAdminPassword(password string) bool {
+	if len(password) < minAdminPasswordLength ||
+		len(password) > maxAdminPasswordLength {
+		return false
+	}
+	var hasLower, hasUpper, hasNumber, hasSpecial bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsDigit(r):
+			hasNumber = true
+		default:
+			hasSpecial = true
+		}
+	}
+	classes := 0
+	for _, has := range []bool{hasLower, hasUpper, hasNumber, hasSpecial} {
+		if has {
+			classes++
+		}
+	}
+	return classes >= 3
+}
@@ -0,0 +1,167 @@
+package virtualmachine
+
+import (
+	"testing"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSSHPublicKey = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQC7 foo@bar"
+
+func TestValidateProvisioningParametersWithDefaults(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidImage(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		Image: &ImageParameters{
+			Publisher: "MicrosoftWindowsServer",
+			Offer:     "WindowsServer",
+			OSType:    osTypeWindows,
+		},
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.Image.SKU = "2019-Datacenter"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+	pp.Image.OSType = "BeOS"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidOSDisk(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		OSDisk: &OSDiskParameters{
+			SizeGB: minOSDiskSizeGB - 1,
+		},
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.OSDisk.SizeGB = maxOSDiskSizeGB + 1
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.OSDisk.SizeGB = 128
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+	pp.OSDisk.StorageAccountType = "UltraSSD_LRS"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.OSDisk.StorageAccountType = "Premium_LRS"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidSubnetID(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		SubnetID: "/subscriptions/foo/resourceGroups/bar",
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.SubnetID = "/subscriptions/foo/resourceGroups/bar/providers/" +
+		"Microsoft.Network/virtualNetworks/baz/subnets/default"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidAdminUsername(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		AdminUsername: "-foo",
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.AdminUsername = "Administrator"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.AdminUsername = "foo.bar"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithSSHPublicKey(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		SSHPublicKey: "foo",
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.SSHPublicKey = testSSHPublicKey
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+	// A password and an SSH public key can't both be specified
+	pp.AdminPassword = "Sup3r$ecretPassw0rd"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	// Windows virtual machines don't support SSH public keys
+	pp.AdminPassword = ""
+	pp.Image = &ImageParameters{
+		Publisher: "MicrosoftWindowsServer",
+		Offer:     "WindowsServer",
+		SKU:       "2019-Datacenter",
+		OSType:    osTypeWindows,
+	}
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidAdminPassword(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		// Too short
+		AdminPassword: "Sh0rt$",
+	}
+	err := m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	// Only two kinds of character
+	pp.AdminPassword = "alllowercase12345"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.NotNil(t, err)
+	pp.AdminPassword = "NotAllLowercase12345"
+	err = m.serviceManager.ValidateProvisioningParameters(pp)
+	assert.Nil(t, err)
+}
+
+func TestApplyProvisioningParametersDefaults(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{
+		OSDisk: &OSDiskParameters{
+			SizeGB: 64,
+		},
+	}
+	err := m.serviceManager.ApplyProvisioningParametersDefaults(pp)
+	assert.Nil(t, err)
+	assert.Equal(t, defaultVMSize, pp.Size)
+	assert.Equal(t, defaultImage, *pp.Image)
+	assert.Equal(t, 64, pp.OSDisk.SizeGB)
+	assert.Equal(
+		t,
+		defaultOSDiskStorageAccountType,
+		pp.OSDisk.StorageAccountType,
+	)
+	assert.Equal(t, defaultAdminUsername, pp.AdminUsername)
+}
+
+func TestGetSKUUsages(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{}
+	skuUsages, err := m.serviceManager.GetSKUUsages(nil, pp)
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		[]service.SKUUsage{
+			{
+				SKU:      defaultVMSize,
+				Quantity: 1,
+			},
+		},
+		skuUsages,
+	)
+}
@@ -0,0 +1,93 @@
+package virtualmachine
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/metrics"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+const (
+	cpuMetricName = "Percentage CPU"
+	// idleCPUPercentage is the hourly average CPU utilization below which a
+	// virtual machine is considered idle
+	idleCPUPercentage = 5
+)
+
+// IsIdle returns a bool indicating whether the virtual machine's average CPU
+// utilization stayed below idleCPUPercentage in every hour of the given
+// period. A virtual machine for which no utilization was recorded is not
+// considered idle, since there's nothing to go by.
+func (s *serviceManager) IsIdle(
+	_ context.Context,
+	instance service.Instance,
+	period time.Duration,
+) (bool, error) {
+	dt, ok := instance.Details.(*vmInstanceDetails)
+	if !ok {
+		return false, errors.New(
+			"error casting instance.Details as *vmInstanceDetails",
+		)
+	}
+	values, err := s.metricsManager.GetMetricValues(
+		dt.VMID,
+		cpuMetricName,
+		metrics.AggregationAverage,
+		metrics.IntervalHour,
+		period,
+	)
+	if err != nil {
+		return false, err
+	}
+	if len(values) == 0 {
+		return false, nil
+	}
+	for _, value := range values {
+		if value >= idleCPUPercentage {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Suspend deallocates the virtual machine. Its disks, network interface, and
+// (static) public IP address are retained, so it can be restarted as it was.
+func (s *serviceManager) Suspend(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*vmInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *vmInstanceDetails",
+		)
+	}
+	if err := s.vmManager.DeallocateVirtualMachine(
+		dt.VMName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+func (s *serviceManager) Resume(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*vmInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *vmInstanceDetails",
+		)
+	}
+	if err := s.vmManager.StartVirtualMachine(
+		dt.VMName,
+		instance.ResourceGroup,
+	); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
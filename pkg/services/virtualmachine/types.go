@@ -0,0 +1,107 @@
+package virtualmachine
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// ProvisioningParameters encapsulates virtual machine-specific provisioning
+// options
+type ProvisioningParameters struct {
+	Size   string            `json:"size"`
+	Image  *ImageParameters  `json:"image"`
+	OSDisk *OSDiskParameters `json:"osDisk"`
+	// SubnetID identifies an existing subnet to attach the virtual machine to.
+	// If it is unspecified, a virtual network is created for the virtual
+	// machine's exclusive use.
+	SubnetID               string `json:"subnetId"`
+	DisablePublicIPAddress bool   `json:"disablePublicIpAddress"`
+	AdminUsername          string `json:"adminUsername"`
+	AdminPassword          string `json:"adminPassword" secret:"true"`
+	SSHPublicKey           string `json:"sshPublicKey"`
+}
+
+// ImageParameters identifies the marketplace image a virtual machine is
+// created from
+type ImageParameters struct {
+	Publisher string `json:"publisher"`
+	Offer     string `json:"offer"`
+	SKU       string `json:"sku"`
+	Version   string `json:"version"`
+	OSType    string `json:"osType"`
+}
+
+// OSDiskParameters encapsulates options for a virtual machine's OS disk
+type OSDiskParameters struct {
+	SizeGB             int    `json:"sizeGb"`
+	StorageAccountType string `json:"storageAccountType"`
+}
+
+type vmInstanceDetails struct {
+	ARMDeploymentName    string           `json:"armDeployment"`
+	VMName               string           `json:"vmName"`
+	VMID                 string           `json:"vmId"`
+	VMSize               string           `json:"vmSize"`
+	Image                *ImageParameters `json:"image"`
+	NetworkInterfaceName string           `json:"networkInterfaceName"`
+	OSDiskName           string           `json:"osDiskName"`
+	// PublicIPAddressName is empty if the virtual machine has no public IP
+	// address
+	PublicIPAddressName string `json:"publicIPAddressName"`
+	// VirtualNetworkName is empty unless a virtual network was created for the
+	// virtual machine
+	VirtualNetworkName string `json:"virtualNetworkName"`
+	PrivateIPAddress   string `json:"privateIPAddress"`
+	PublicIPAddress    string `json:"publicIPAddress"`
+	FQDN               string `json:"fqdn"`
+	AdminUsername      string `json:"adminUsername"`
+	// AdminPassword is empty if the virtual machine authenticates its
+	// administrator using an SSH public key
+	AdminPassword string `json:"adminPassword" secret:"true"`
+}
+
+// UpdatingParameters encapsulates virtual machine-specific updating options
+type UpdatingParameters struct {
+}
+
+// BindingParameters encapsulates virtual machine-specific binding options
+type BindingParameters struct {
+}
+
+type vmBindingDetails struct {
+}
+
+type vmCredentials struct {
+	VMName           string `json:"vmName"`
+	VMID             string `json:"vmId"`
+	PrivateIPAddress string `json:"privateIpAddress"`
+	PublicIPAddress  string `json:"publicIpAddress,omitempty"`
+	FQDN             string `json:"fqdn,omitempty"`
+	Protocol         string `json:"protocol"`
+	Port             int    `json:"port"`
+	Username         string `json:"username"`
+	Password         string `json:"password,omitempty"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &vmInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &vmBindingDetails{}
+}
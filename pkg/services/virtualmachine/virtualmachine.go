@@ -0,0 +1,50 @@
+package virtualmachine
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/metrics"
+	vm "github.com/Azure/open-service-broker-azure/pkg/azure/virtualmachine"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer    arm.Deployer
+	vmManager      vm.Manager
+	metricsManager metrics.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of provisioning Azure virtual machines
+func New(
+	armDeployer arm.Deployer,
+	vmManager vm.Manager,
+	metricsManager metrics.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer:    armDeployer,
+			vmManager:      vmManager,
+			metricsManager: metricsManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "virtualmachine"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{
+		"Microsoft.Compute",
+		"Microsoft.Network",
+		"Microsoft.Insights",
+	}
+}
@@ -0,0 +1,62 @@
+package webpubsub
+
+// nolint: lll
+var armTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "serviceName": {
+      "type": "string",
+      "metadata": {
+        "description": "Name of the Web PubSub service"
+      }
+    },
+    "skuName": {
+      "type": "string"
+    },
+    "unitCount": {
+      "type": "int"
+    },
+    "tags": {
+      "type": "object"
+    }
+  },
+  "variables": {
+    "apiVersion": "2023-02-01"
+  },
+  "resources": [
+    {
+      "apiVersion": "[variables('apiVersion')]",
+      "name": "[parameters('serviceName')]",
+      "type": "Microsoft.SignalRService/webPubSub",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "sku": {
+        "name": "[parameters('skuName')]",
+        "capacity": "[parameters('unitCount')]"
+      },
+      "properties": {
+        "publicNetworkAccess": "Enabled"
+      }
+    }
+  ],
+  "outputs": {
+    "hostName": {
+      "type": "string",
+      "value": "[reference(parameters('serviceName')).hostName]"
+    },
+    "primaryKey": {
+      "type": "string",
+      "value": "[listKeys(resourceId('Microsoft.SignalRService/webPubSub', parameters('serviceName')), variables('apiVersion')).primaryKey]"
+    },
+    "secondaryKey": {
+      "type": "string",
+      "value": "[listKeys(resourceId('Microsoft.SignalRService/webPubSub', parameters('serviceName')), variables('apiVersion')).secondaryKey]"
+    }
+  }
+}
+`)
@@ -0,0 +1,87 @@
+package webpubsub
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+var hubRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]{0,127}$`)
+
+func (s *serviceManager) ValidateBindingParameters(
+	bindingParameters service.BindingParameters,
+) error {
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return errors.New(
+			"error casting bindingParameters as *webpubsub.BindingParameters",
+		)
+	}
+	if !hubRegex.MatchString(bp.Hub) {
+		return service.NewValidationError(
+			"hub",
+			fmt.Sprintf(
+				`invalid hub: "%s"; hub names must be 1-128 letters, numbers and `+
+					"underscores and must begin with a letter",
+				bp.Hub,
+			),
+		)
+	}
+	return nil
+}
+
+func (s *serviceManager) Bind(
+	_ service.Instance,
+	bindingParameters service.BindingParameters,
+) (service.BindingDetails, error) {
+	bp, ok := bindingParameters.(*BindingParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting bindingParameters as *webpubsub.BindingParameters",
+		)
+	}
+	return &webPubSubBindingDetails{
+		Hub: bp.Hub,
+	}, nil
+}
+
+func (s *serviceManager) GetCredentials(
+	instance service.Instance,
+	binding service.Binding,
+) (service.Credentials, error) {
+	dt, ok := instance.Details.(*webPubSubInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *webPubSubInstanceDetails",
+		)
+	}
+	bd, ok := binding.Details.(*webPubSubBindingDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting binding.Details as *webPubSubBindingDetails",
+		)
+	}
+	return getCredentials(dt, bd.Hub), nil
+}
+
+// getCredentials returns the connection details for the given hub of the
+// service. Web PubSub's own connection strings don't name a hub, so the hub is
+// appended to the connection string, scoping it, and is also returned on its
+// own for SDKs that take the hub separately.
+func getCredentials(dt *webPubSubInstanceDetails, hub string) *Credentials {
+	endpoint := fmt.Sprintf("https://%s", dt.HostName)
+	return &Credentials{
+		Endpoint:  endpoint,
+		HostName:  dt.HostName,
+		Hub:       hub,
+		AccessKey: dt.PrimaryKey,
+		ConnectionString: fmt.Sprintf(
+			"Endpoint=%s;AccessKey=%s;Version=1.0;Hub=%s;",
+			endpoint,
+			dt.PrimaryKey,
+			hub,
+		),
+	}
+}
@@ -0,0 +1,48 @@
+package webpubsub
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+func (m *module) GetCatalog() (service.Catalog, error) {
+	return service.NewCatalog([]service.Service{
+		service.NewService(
+			&service.ServiceProperties{
+				ID:          "29147d8e-5ccd-4aa0-b4b1-2491b966c240",
+				Name:        "azure-web-pubsub",
+				Description: "Azure Web PubSub (Experimental)",
+				Bindable:    true,
+				Tags:        []string{"Azure", "Web PubSub", "WebSockets"},
+				// Azure Web PubSub is only offered in some regions
+				Locations: []string{
+					"australiaeast",
+					"brazilsouth",
+					"canadacentral",
+					"centralindia",
+					"centralus",
+					"eastasia",
+					"eastus",
+					"eastus2",
+					"francecentral",
+					"germanywestcentral",
+					"japaneast",
+					"koreacentral",
+					"northeurope",
+					"southcentralus",
+					"southeastasia",
+					"swedencentral",
+					"uksouth",
+					"westeurope",
+					"westus",
+					"westus2",
+					"westus3",
+				},
+			},
+			m.serviceManager,
+			service.NewPlan(&service.PlanProperties{
+				ID:          "604bea5f-d52c-4916-a334-f8ceea2539bc",
+				Name:        "web-pubsub",
+				Description: "Billed by tier and number of units, per day",
+				Free:        false,
+			}),
+		),
+	}), nil
+}
@@ -0,0 +1,203 @@
+package webpubsub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	skuFree     = "Free_F1"
+	skuStandard = "Standard_S1"
+	skuPremium  = "Premium_P1"
+	// skuPremiumLarge is the premium SKU for services of 100 units or more
+	skuPremiumLarge = "Premium_P2"
+)
+
+var standardUnitCounts = []int64{
+	1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 20, 30, 40, 50, 60, 70, 80, 90, 100,
+}
+
+// unitCountsBySKU maps each SKU, in the order they're listed in validation
+// errors, to the unit counts that Azure allows a service of that SKU to be
+// scaled to
+var unitCountsBySKU = []struct {
+	sku        string
+	unitCounts []int64
+}{
+	{skuFree, []int64{1}},
+	{skuStandard, standardUnitCounts},
+	{skuPremium, standardUnitCounts},
+	{skuPremiumLarge, []int64{100, 200, 300, 400, 500, 600, 700, 800, 900, 1000}},
+}
+
+func (s *serviceManager) ValidateProvisioningParameters(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*webpubsub.ProvisioningParameters",
+		)
+	}
+	sku := getSKU(pp)
+	unitCounts, ok := getUnitCounts(sku)
+	if !ok {
+		skus := make([]string, len(unitCountsBySKU))
+		for i, s := range unitCountsBySKU {
+			skus[i] = s.sku
+		}
+		return service.NewValidationError(
+			"sku",
+			fmt.Sprintf(
+				`invalid sku: "%s"; allowed values are: %s`,
+				pp.SKU,
+				strings.Join(skus, ", "),
+			),
+		)
+	}
+	if pp.UnitCount == 0 {
+		return nil
+	}
+	for _, unitCount := range unitCounts {
+		if pp.UnitCount == unitCount {
+			return nil
+		}
+	}
+	allowed := make([]string, len(unitCounts))
+	for i, unitCount := range unitCounts {
+		allowed[i] = fmt.Sprintf("%d", unitCount)
+	}
+	return service.NewValidationError(
+		"unitCount",
+		fmt.Sprintf(
+			`invalid unitCount: %d; allowed values for sku "%s" are: %s`,
+			pp.UnitCount,
+			sku,
+			strings.Join(allowed, ", "),
+		),
+	)
+}
+
+func (s *serviceManager) ApplyProvisioningParametersDefaults(
+	provisioningParameters service.ProvisioningParameters,
+) error {
+	pp, ok := provisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return errors.New(
+			"error casting provisioningParameters as " +
+				"*webpubsub.ProvisioningParameters",
+		)
+	}
+	pp.SKU = getSKU(pp)
+	pp.UnitCount = getUnitCount(pp)
+	return nil
+}
+
+func (s *serviceManager) GetProvisioner(
+	service.Plan,
+) (service.Provisioner, error) {
+	return service.NewProvisioner(
+		service.NewProvisioningStep("preProvision", s.preProvision),
+		service.NewProvisioningStep("deployARMTemplate", s.deployARMTemplate),
+	)
+}
+
+func (s *serviceManager) preProvision(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*webPubSubInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *webPubSubInstanceDetails",
+		)
+	}
+	dt.ARMDeploymentName = uuid.NewV4().String()
+	dt.ServiceName = "wps-" + uuid.NewV4().String()
+	return dt, nil
+}
+
+func (s *serviceManager) deployARMTemplate(
+	_ context.Context,
+	instance service.Instance,
+) (service.InstanceDetails, error) {
+	dt, ok := instance.Details.(*webPubSubInstanceDetails)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.Details as *webPubSubInstanceDetails",
+		)
+	}
+	pp, ok := instance.ProvisioningParameters.(*ProvisioningParameters)
+	if !ok {
+		return nil, errors.New(
+			"error casting instance.ProvisioningParameters as " +
+				"*webpubsub.ProvisioningParameters",
+		)
+	}
+	outputs, err := s.armDeployer.Deploy(
+		dt.ARMDeploymentName,
+		instance.ResourceGroup,
+		instance.Location,
+		armTemplateBytes,
+		nil, // Go template params
+		map[string]interface{}{ // ARM template params
+			"serviceName": dt.ServiceName,
+			"skuName":     getSKU(pp),
+			"unitCount":   getUnitCount(pp),
+		},
+		instance.Tags,
+	)
+	if err != nil {
+		return nil, service.WrapError(err, "error deploying ARM template")
+	}
+	hostName, ok := outputs["hostName"].(string)
+	if !ok {
+		return nil, errors.New("error retrieving host name from deployment")
+	}
+	dt.HostName = hostName
+	primaryKey, ok := outputs["primaryKey"].(string)
+	if !ok {
+		return nil, errors.New("error retrieving primary key from deployment")
+	}
+	dt.PrimaryKey = primaryKey
+	secondaryKey, ok := outputs["secondaryKey"].(string)
+	if !ok {
+		return nil, errors.New("error retrieving secondary key from deployment")
+	}
+	dt.SecondaryKey = secondaryKey
+	return dt, nil
+}
+
+func getSKU(pp *ProvisioningParameters) string {
+	if pp.SKU == "" {
+		return skuStandard
+	}
+	return pp.SKU
+}
+
+func getUnitCount(pp *ProvisioningParameters) int64 {
+	if pp.UnitCount != 0 {
+		return pp.UnitCount
+	}
+	// The smallest count allowed for the SKU, which, for all but
+	// skuPremiumLarge, is a single unit
+	if unitCounts, ok := getUnitCounts(getSKU(pp)); ok {
+		return unitCounts[0]
+	}
+	return 1
+}
+
+func getUnitCounts(sku string) ([]int64, bool) {
+	for _, s := range unitCountsBySKU {
+		if s.sku == sku {
+			return s.unitCounts, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,100 @@
+package webpubsub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateProvisioningParametersWithDefaults(t *testing.T) {
+	m := &module{}
+	err := m.serviceManager.ValidateProvisioningParameters(
+		&ProvisioningParameters{},
+	)
+	assert.Nil(t, err)
+}
+
+func TestValidateProvisioningParametersWithInvalidSKU(t *testing.T) {
+	m := &module{}
+	err := m.serviceManager.ValidateProvisioningParameters(
+		&ProvisioningParameters{SKU: "Basic_B1"},
+	)
+	assert.NotNil(t, err)
+}
+
+func TestValidateProvisioningParametersUnitCounts(t *testing.T) {
+	m := &module{}
+	for _, tc := range []struct {
+		sku       string
+		unitCount int64
+		valid     bool
+	}{
+		{skuFree, 1, true},
+		{skuFree, 2, false},
+		{skuStandard, 10, true},
+		{skuStandard, 15, false},
+		{skuPremium, 100, true},
+		{skuPremium, 200, false},
+		{skuPremiumLarge, 200, true},
+		{skuPremiumLarge, 50, false},
+	} {
+		err := m.serviceManager.ValidateProvisioningParameters(
+			&ProvisioningParameters{
+				SKU:       tc.sku,
+				UnitCount: tc.unitCount,
+			},
+		)
+		if tc.valid {
+			assert.Nil(t, err, "%s x %d", tc.sku, tc.unitCount)
+		} else {
+			assert.NotNil(t, err, "%s x %d", tc.sku, tc.unitCount)
+		}
+	}
+}
+
+func TestApplyProvisioningParametersDefaults(t *testing.T) {
+	m := &module{}
+	pp := &ProvisioningParameters{}
+	err := m.serviceManager.ApplyProvisioningParametersDefaults(pp)
+	assert.Nil(t, err)
+	assert.Equal(t, skuStandard, pp.SKU)
+	assert.Equal(t, int64(1), pp.UnitCount)
+	pp = &ProvisioningParameters{SKU: skuPremiumLarge}
+	err = m.serviceManager.ApplyProvisioningParametersDefaults(pp)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(100), pp.UnitCount)
+}
+
+func TestValidateBindingParameters(t *testing.T) {
+	m := &module{}
+	for _, hub := range []string{"chat", "Chat_Room1"} {
+		err := m.serviceManager.ValidateBindingParameters(
+			&BindingParameters{Hub: hub},
+		)
+		assert.Nil(t, err, hub)
+	}
+	for _, hub := range []string{"", "1chat", "chat-room"} {
+		err := m.serviceManager.ValidateBindingParameters(
+			&BindingParameters{Hub: hub},
+		)
+		assert.NotNil(t, err, hub)
+	}
+}
+
+func TestGetCredentials(t *testing.T) {
+	credentials := getCredentials(
+		&webPubSubInstanceDetails{
+			HostName:   "wps.webpubsub.azure.com",
+			PrimaryKey: "key",
+		},
+		"chat",
+	)
+	assert.Equal(t, "https://wps.webpubsub.azure.com", credentials.Endpoint)
+	assert.Equal(t, "chat", credentials.Hub)
+	assert.Equal(
+		t,
+		"Endpoint=https://wps.webpubsub.azure.com;AccessKey=key;Version=1.0;"+
+			"Hub=chat;",
+		credentials.ConnectionString,
+	)
+}
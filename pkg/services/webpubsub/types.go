@@ -0,0 +1,71 @@
+package webpubsub
+
+import "github.com/Azure/open-service-broker-azure/pkg/service"
+
+// ProvisioningParameters encapsulates Azure Web PubSub-specific provisioning
+// options
+type ProvisioningParameters struct {
+	SKU string `json:"sku"`
+	// UnitCount is the number of units, each allowing a fixed number of
+	// concurrent connections, that the service is scaled to. The counts allowed
+	// depend on the SKU.
+	UnitCount int64 `json:"unitCount"`
+}
+
+type webPubSubInstanceDetails struct {
+	ARMDeploymentName string `json:"armDeployment"`
+	ServiceName       string `json:"serviceName"`
+	HostName          string `json:"hostName"`
+	PrimaryKey        string `json:"primaryKey" secret:"true"`
+	SecondaryKey      string `json:"secondaryKey" secret:"true"`
+}
+
+// UpdatingParameters encapsulates Azure Web PubSub-specific updating options
+type UpdatingParameters struct {
+}
+
+// BindingParameters encapsulates Azure Web PubSub-specific binding options
+type BindingParameters struct {
+	// Hub is the hub, within the service, that the binding's connection string
+	// is scoped to. Hubs exist implicitly; they needn't be created first.
+	Hub string `json:"hub"`
+}
+
+type webPubSubBindingDetails struct {
+	Hub string `json:"hub"`
+}
+
+// Credentials encapsulates Azure Web PubSub-specific connection details
+type Credentials struct {
+	Endpoint         string `json:"endpoint"`
+	HostName         string `json:"hostName"`
+	Hub              string `json:"hub"`
+	AccessKey        string `json:"accessKey"`
+	ConnectionString string `json:"connectionString"`
+}
+
+func (
+	s *serviceManager,
+) GetEmptyProvisioningParameters() service.ProvisioningParameters {
+	return &ProvisioningParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyUpdatingParameters() service.UpdatingParameters {
+	return &UpdatingParameters{}
+}
+
+func (
+	s *serviceManager,
+) GetEmptyInstanceDetails() service.InstanceDetails {
+	return &webPubSubInstanceDetails{}
+}
+
+func (s *serviceManager) GetEmptyBindingParameters() service.BindingParameters {
+	return &BindingParameters{}
+}
+
+func (s *serviceManager) GetEmptyBindingDetails() service.BindingDetails {
+	return &webPubSubBindingDetails{}
+}
@@ -0,0 +1,14 @@
+package webpubsub
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+// Unbind does nothing. Every binding shares the service's primary key, which
+// is left as it is so that the credentials of other bindings remain valid.
+func (s *serviceManager) Unbind(
+	service.Instance,
+	service.BindingDetails,
+) error {
+	return nil
+}
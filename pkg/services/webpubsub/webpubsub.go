@@ -0,0 +1,42 @@
+package webpubsub
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/webpubsub"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+)
+
+type module struct {
+	serviceManager *serviceManager
+}
+
+type serviceManager struct {
+	armDeployer      arm.Deployer
+	webPubSubManager webpubsub.Manager
+}
+
+// New returns a new instance of a type that fulfills the service.Module
+// interface and is capable of provisioning Azure Web PubSub services
+func New(
+	armDeployer arm.Deployer,
+	webPubSubManager webpubsub.Manager,
+) service.Module {
+	return &module{
+		serviceManager: &serviceManager{
+			armDeployer:      armDeployer,
+			webPubSubManager: webPubSubManager,
+		},
+	}
+}
+
+func (m *module) GetName() string {
+	return "webpubsub"
+}
+
+func (m *module) GetStability() service.Stability {
+	return service.StabilityExperimental
+}
+
+func (m *module) GetResourceProviders() []string {
+	return []string{"Microsoft.SignalRService"}
+}
@@ -2,7 +2,9 @@ package memory
 
 import (
 	"fmt"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/Azure/open-service-broker-azure/pkg/crypto"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
@@ -10,13 +12,62 @@ import (
 )
 
 type store struct {
-	catalog                       service.Catalog
-	codec                         crypto.Codec
-	instances                     map[string][]byte
-	instanceAliases               map[string]string
-	bindings                      map[string][]byte
-	instanceAliasChildCounts      map[string]int64
-	instanceAliasChildCountsMutex sync.Mutex
+	catalog                        service.Catalog
+	codec                          crypto.Codec
+	instances                      map[string][]byte
+	instanceAliases                map[string]string
+	bindings                       map[string][]byte
+	instanceAliasChildCounts       map[string]int64
+	instanceAliasChildCountsMutex  sync.Mutex
+	instanceDependents             map[string]map[string]bool
+	instanceDependentsMutex        sync.Mutex
+	provisioningRequests           map[string]provisioningRequest
+	provisioningRequestsMutex      sync.Mutex
+	provisioningCapacity           map[string]*provisioningCapacityPool
+	provisioningCapacityMutex      sync.Mutex
+	provisioningSLAOutcomes        map[string]service.ProvisioningSLAOutcomes
+	provisioningSLAOutcomesMutex   sync.Mutex
+	provisioningStepDurations      map[string]map[string]stepDurations
+	provisioningStepDurationsMutex sync.Mutex
+	keyRotationProgress            *service.KeyRotationProgress
+	keyRotationProgressMutex       sync.Mutex
+	stepTraces                     map[string]stepTraces
+	stepTracesMutex                sync.Mutex
+}
+
+// stepTraces are the step traces kept for a single instance
+type stepTraces struct {
+	traces  []service.StepTrace
+	expires time.Time
+}
+
+// stepDurations totals the durations recorded for a single provisioning step
+type stepDurations struct {
+	total time.Duration
+	count int64
+}
+
+type provisioningRequest struct {
+	fingerprint string
+	expires     time.Time
+}
+
+// provisioningCapacityPool records which instances hold one of a pool's
+// provisioning slots, and which are queued, in order, awaiting one
+type provisioningCapacityPool struct {
+	slots map[string]bool
+	queue []string
+}
+
+// getPosition returns the given instance id's position in the queue, or zero
+// if it isn't queued
+func (p *provisioningCapacityPool) getPosition(instanceID string) int64 {
+	for i, id := range p.queue {
+		if id == instanceID {
+			return int64(i + 1)
+		}
+	}
+	return 0
 }
 
 // NewStore returns a new memory-based implementation of the storage.Store used
@@ -29,6 +80,14 @@ func NewStore(catalog service.Catalog, codec crypto.Codec) storage.Store {
 		instanceAliases:          make(map[string]string),
 		bindings:                 make(map[string][]byte),
 		instanceAliasChildCounts: make(map[string]int64),
+		instanceDependents:       make(map[string]map[string]bool),
+		provisioningRequests:     make(map[string]provisioningRequest),
+		provisioningCapacity:     make(map[string]*provisioningCapacityPool),
+		provisioningSLAOutcomes: make(
+			map[string]service.ProvisioningSLAOutcomes,
+		),
+		provisioningStepDurations: make(map[string]map[string]stepDurations),
+		stepTraces:                make(map[string]stepTraces),
 	}
 }
 
@@ -46,6 +105,16 @@ func (s *store) WriteInstance(instance service.Instance) error {
 		defer s.instanceAliasChildCountsMutex.Unlock()
 		s.instanceAliasChildCounts[instance.ParentAlias]++
 	}
+	if len(instance.DependsOn) > 0 {
+		s.instanceDependentsMutex.Lock()
+		defer s.instanceDependentsMutex.Unlock()
+		for _, dependencyID := range instance.DependsOn {
+			if s.instanceDependents[dependencyID] == nil {
+				s.instanceDependents[dependencyID] = map[string]bool{}
+			}
+			s.instanceDependents[dependencyID][instance.InstanceID] = true
+		}
+	}
 	return nil
 }
 
@@ -123,6 +192,13 @@ func (s *store) DeleteInstance(instanceID string) (bool, error) {
 		defer s.instanceAliasChildCountsMutex.Unlock()
 		s.instanceAliasChildCounts[instance.ParentAlias]--
 	}
+	if len(instance.DependsOn) > 0 {
+		s.instanceDependentsMutex.Lock()
+		defer s.instanceDependentsMutex.Unlock()
+		for _, dependencyID := range instance.DependsOn {
+			delete(s.instanceDependents[dependencyID], instance.InstanceID)
+		}
+	}
 	return true, nil
 }
 
@@ -132,6 +208,25 @@ func (s *store) GetInstanceChildCountByAlias(alias string) (int64, error) {
 	return s.instanceAliasChildCounts[alias], nil
 }
 
+func (s *store) GetInstanceDependentIDs(instanceID string) ([]string, error) {
+	s.instanceDependentsMutex.Lock()
+	defer s.instanceDependentsMutex.Unlock()
+	dependentIDs := make([]string, 0, len(s.instanceDependents[instanceID]))
+	for dependentID := range s.instanceDependents[instanceID] {
+		dependentIDs = append(dependentIDs, dependentID)
+	}
+	sort.Strings(dependentIDs)
+	return dependentIDs, nil
+}
+
+func (s *store) GetInstanceIDs() ([]string, error) {
+	instanceIDs := make([]string, 0, len(s.instances))
+	for instanceID := range s.instances {
+		instanceIDs = append(instanceIDs, instanceID)
+	}
+	return instanceIDs, nil
+}
+
 func (s *store) WriteBinding(binding service.Binding) error {
 	json, err := binding.ToJSON(s.codec)
 	if err != nil {
@@ -178,6 +273,249 @@ func (s *store) DeleteBinding(bindingID string) (bool, error) {
 	return true, nil
 }
 
+func (s *store) GetBindingIDs(instanceID string) ([]string, error) {
+	bindingIDs := []string{}
+	for bindingID, json := range s.bindings {
+		binding, err := service.NewBindingFromJSON(json, nil, nil, s.codec)
+		if err != nil {
+			return nil, err
+		}
+		if binding.InstanceID == instanceID {
+			bindingIDs = append(bindingIDs, bindingID)
+		}
+	}
+	return bindingIDs, nil
+}
+
+func (s *store) ClaimProvisioningRequest(
+	instanceID string,
+	fingerprint string,
+	window time.Duration,
+) (bool, string, error) {
+	s.provisioningRequestsMutex.Lock()
+	defer s.provisioningRequestsMutex.Unlock()
+	if request, ok := s.provisioningRequests[instanceID]; ok &&
+		time.Now().Before(request.expires) {
+		return false, request.fingerprint, nil
+	}
+	s.provisioningRequests[instanceID] = provisioningRequest{
+		fingerprint: fingerprint,
+		expires:     time.Now().Add(window),
+	}
+	return true, "", nil
+}
+
+func (s *store) ReleaseProvisioningRequest(instanceID string) error {
+	s.provisioningRequestsMutex.Lock()
+	defer s.provisioningRequestsMutex.Unlock()
+	delete(s.provisioningRequests, instanceID)
+	return nil
+}
+
+func (s *store) ReserveProvisioningCapacity(
+	pool string,
+	instanceID string,
+	maxConcurrent int,
+	maxQueueDepth int,
+) (bool, int64, error) {
+	s.provisioningCapacityMutex.Lock()
+	defer s.provisioningCapacityMutex.Unlock()
+	p := s.getProvisioningCapacityPool(pool)
+	if p.slots[instanceID] {
+		return true, 0, nil
+	}
+	if position := p.getPosition(instanceID); position > 0 {
+		return true, position, nil
+	}
+	if len(p.queue) == 0 && len(p.slots) < maxConcurrent {
+		p.slots[instanceID] = true
+		return true, 0, nil
+	}
+	if len(p.queue) >= maxQueueDepth {
+		return false, int64(len(p.queue)), nil
+	}
+	p.queue = append(p.queue, instanceID)
+	return true, int64(len(p.queue)), nil
+}
+
+func (s *store) ClaimProvisioningCapacity(
+	pool string,
+	instanceID string,
+	maxConcurrent int,
+) (bool, int64, error) {
+	s.provisioningCapacityMutex.Lock()
+	defer s.provisioningCapacityMutex.Unlock()
+	p := s.getProvisioningCapacityPool(pool)
+	if p.slots[instanceID] {
+		return true, 0, nil
+	}
+	position := p.getPosition(instanceID)
+	if position == 0 {
+		p.queue = append(p.queue, instanceID)
+		position = int64(len(p.queue))
+	}
+	if position == 1 && len(p.slots) < maxConcurrent {
+		p.queue = p.queue[1:]
+		p.slots[instanceID] = true
+		return true, 0, nil
+	}
+	return false, position, nil
+}
+
+func (s *store) GetProvisioningQueuePosition(
+	pool string,
+	instanceID string,
+) (int64, error) {
+	s.provisioningCapacityMutex.Lock()
+	defer s.provisioningCapacityMutex.Unlock()
+	return s.getProvisioningCapacityPool(pool).getPosition(instanceID), nil
+}
+
+func (s *store) ReleaseProvisioningCapacity(
+	pool string,
+	instanceID string,
+) error {
+	s.provisioningCapacityMutex.Lock()
+	defer s.provisioningCapacityMutex.Unlock()
+	p := s.getProvisioningCapacityPool(pool)
+	delete(p.slots, instanceID)
+	if position := p.getPosition(instanceID); position > 0 {
+		p.queue = append(p.queue[:position-1], p.queue[position:]...)
+	}
+	return nil
+}
+
+// getProvisioningCapacityPool returns the named pool, creating it if need be.
+// The caller must hold provisioningCapacityMutex.
+func (s *store) getProvisioningCapacityPool(
+	pool string,
+) *provisioningCapacityPool {
+	p, ok := s.provisioningCapacity[pool]
+	if !ok {
+		p = &provisioningCapacityPool{
+			slots: map[string]bool{},
+		}
+		s.provisioningCapacity[pool] = p
+	}
+	return p
+}
+
+func (s *store) RecordProvisioningSLAOutcome(
+	planID string,
+	breached bool,
+) error {
+	s.provisioningSLAOutcomesMutex.Lock()
+	defer s.provisioningSLAOutcomesMutex.Unlock()
+	outcomes := s.provisioningSLAOutcomes[planID]
+	if breached {
+		outcomes.Breached++
+	} else {
+		outcomes.WithinTarget++
+	}
+	s.provisioningSLAOutcomes[planID] = outcomes
+	return nil
+}
+
+func (s *store) GetProvisioningSLAOutcomes() (
+	map[string]service.ProvisioningSLAOutcomes,
+	error,
+) {
+	s.provisioningSLAOutcomesMutex.Lock()
+	defer s.provisioningSLAOutcomesMutex.Unlock()
+	outcomes := make(
+		map[string]service.ProvisioningSLAOutcomes,
+		len(s.provisioningSLAOutcomes),
+	)
+	for planID, planOutcomes := range s.provisioningSLAOutcomes {
+		outcomes[planID] = planOutcomes
+	}
+	return outcomes, nil
+}
+
+func (s *store) RecordProvisioningStepDuration(
+	planID string,
+	stepName string,
+	duration time.Duration,
+) error {
+	s.provisioningStepDurationsMutex.Lock()
+	defer s.provisioningStepDurationsMutex.Unlock()
+	planDurations, ok := s.provisioningStepDurations[planID]
+	if !ok {
+		planDurations = map[string]stepDurations{}
+		s.provisioningStepDurations[planID] = planDurations
+	}
+	durations := planDurations[stepName]
+	durations.total += duration
+	durations.count++
+	planDurations[stepName] = durations
+	return nil
+}
+
+func (s *store) GetProvisioningStepDurations(
+	planID string,
+) (map[string]time.Duration, error) {
+	s.provisioningStepDurationsMutex.Lock()
+	defer s.provisioningStepDurationsMutex.Unlock()
+	averages := map[string]time.Duration{}
+	for stepName, durations := range s.provisioningStepDurations[planID] {
+		averages[stepName] = durations.total / time.Duration(durations.count)
+	}
+	return averages, nil
+}
+
+func (s *store) WriteKeyRotationProgress(
+	progress service.KeyRotationProgress,
+) error {
+	s.keyRotationProgressMutex.Lock()
+	defer s.keyRotationProgressMutex.Unlock()
+	s.keyRotationProgress = &progress
+	return nil
+}
+
+func (s *store) GetKeyRotationProgress() (
+	service.KeyRotationProgress,
+	bool,
+	error,
+) {
+	s.keyRotationProgressMutex.Lock()
+	defer s.keyRotationProgressMutex.Unlock()
+	if s.keyRotationProgress == nil {
+		return service.KeyRotationProgress{}, false, nil
+	}
+	return *s.keyRotationProgress, true, nil
+}
+
+func (s *store) AppendStepTrace(
+	instanceID string,
+	trace service.StepTrace,
+	maxTraces int,
+	ttl time.Duration,
+) error {
+	s.stepTracesMutex.Lock()
+	defer s.stepTracesMutex.Unlock()
+	traces := s.stepTraces[instanceID]
+	if time.Now().After(traces.expires) {
+		traces.traces = nil
+	}
+	traces.traces = append(traces.traces, trace)
+	if len(traces.traces) > maxTraces {
+		traces.traces = traces.traces[len(traces.traces)-maxTraces:]
+	}
+	traces.expires = time.Now().Add(ttl)
+	s.stepTraces[instanceID] = traces
+	return nil
+}
+
+func (s *store) GetStepTraces(instanceID string) ([]service.StepTrace, error) {
+	s.stepTracesMutex.Lock()
+	defer s.stepTracesMutex.Unlock()
+	traces, ok := s.stepTraces[instanceID]
+	if !ok || time.Now().After(traces.expires) {
+		return []service.StepTrace{}, nil
+	}
+	return append([]service.StepTrace{}, traces.traces...), nil
+}
+
 func (s *store) TestConnection() error {
 	return nil
 }
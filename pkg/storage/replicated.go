@@ -0,0 +1,358 @@
+package storage
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	"github.com/Azure/open-service-broker-azure/pkg/service"
+	log "github.com/Sirupsen/logrus"
+	"github.com/go-redis/redis"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	// ReplicateInstanceJobName is the name of the async job that copies an
+	// instance from the primary store to the secondary store
+	ReplicateInstanceJobName = "replicateInstance"
+	// ReplicateBindingJobName is the name of the async job that copies a
+	// binding from the primary store to the secondary store
+	ReplicateBindingJobName = "replicateBinding"
+	// replicationPendingKey names a sorted set of the keys of records written
+	// to the primary store that have yet to be replicated, each scored by the
+	// time, in microseconds, of its oldest unreplicated write
+	replicationPendingKey = "replication:pending"
+	// replicationLatestKey names a hash of the time, in microseconds, of the
+	// latest write of each record having yet to be replicated
+	replicationLatestKey = "replication:latest"
+	// replicationLockTTL is how long a record's replication lock is held, at
+	// most, should the process holding it fail to release it
+	replicationLockTTL = time.Minute
+)
+
+// ReplicatedStore is an interface to be implemented by types that persist
+// instances and bindings to a primary store and replicate them, asynchronously,
+// to a secondary store that can be promoted should the primary be lost
+type ReplicatedStore interface {
+	Store
+	// ReplicateInstance copies the instance having the given instance id, as it
+	// currently is in the primary store, to the secondary store. An instance
+	// that no longer exists in the primary store is deleted from the secondary.
+	ReplicateInstance(instanceID string) error
+	// ReplicateBinding copies the binding having the given binding id, as it
+	// currently is in the primary store, to the secondary store. A binding that
+	// no longer exists in the primary store is deleted from the secondary.
+	ReplicateBinding(bindingID string) error
+	// GetReplicationLag returns how long ago the oldest write to the primary
+	// store that has yet to be replicated was made, or zero if there is none
+	GetReplicationLag() (time.Duration, error)
+}
+
+var markReplicationPendingScript = redis.NewScript(`
+redis.call("ZADD", KEYS[1], "NX", ARGV[2], ARGV[1])
+redis.call("HSET", KEYS[2], ARGV[1], ARGV[2])
+return 1
+`)
+
+// markReplicatedScript forgets a pending record unless it was written again
+// after replication read it, in which case the record remains pending as of
+// that later write
+var markReplicatedScript = redis.NewScript(`
+local latest = redis.call("HGET", KEYS[2], ARGV[1])
+if latest == false then
+  return 0
+end
+if tonumber(latest) <= tonumber(ARGV[2]) then
+  redis.call("ZREM", KEYS[1], ARGV[1])
+  redis.call("HDEL", KEYS[2], ARGV[1])
+  return 1
+end
+redis.call("ZADD", KEYS[1], "XX", latest, ARGV[1])
+return 0
+`)
+
+// releaseReplicationLockScript releases a record's replication lock only if
+// it's still held by the replication that acquired it, rather than by another
+// that acquired it after it expired
+var releaseReplicationLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+  return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+type replicatedStore struct {
+	Store
+	secondary   Store
+	redisClient *redis.Client
+	asyncEngine async.Engine
+}
+
+// NewReplicatedStore returns a new implementation of the ReplicatedStore
+// interface. Everything is read from and written to the given primary store.
+// Writes and deletions of instances and bindings are additionally replicated
+// to the given secondary store by tasks submitted to the given async engine.
+// Records awaiting replication are tracked using the given Redis client, which
+// should be that of the primary store.
+func NewReplicatedStore(
+	primary Store,
+	secondary Store,
+	redisClient *redis.Client,
+	asyncEngine async.Engine,
+) ReplicatedStore {
+	return &replicatedStore{
+		Store:       primary,
+		secondary:   secondary,
+		redisClient: redisClient,
+		asyncEngine: asyncEngine,
+	}
+}
+
+func (r *replicatedStore) WriteInstance(instance service.Instance) error {
+	if err := r.Store.WriteInstance(instance); err != nil {
+		return err
+	}
+	r.enqueueReplication(
+		getInstanceKey(instance.InstanceID),
+		ReplicateInstanceJobName,
+		"instanceID",
+		instance.InstanceID,
+	)
+	return nil
+}
+
+func (r *replicatedStore) DeleteInstance(instanceID string) (bool, error) {
+	ok, err := r.Store.DeleteInstance(instanceID)
+	if err != nil || !ok {
+		return ok, err
+	}
+	r.enqueueReplication(
+		getInstanceKey(instanceID),
+		ReplicateInstanceJobName,
+		"instanceID",
+		instanceID,
+	)
+	return true, nil
+}
+
+func (r *replicatedStore) WriteBinding(binding service.Binding) error {
+	if err := r.Store.WriteBinding(binding); err != nil {
+		return err
+	}
+	r.enqueueReplication(
+		getBindingKey(binding.BindingID),
+		ReplicateBindingJobName,
+		"bindingID",
+		binding.BindingID,
+	)
+	return nil
+}
+
+func (r *replicatedStore) DeleteBinding(bindingID string) (bool, error) {
+	ok, err := r.Store.DeleteBinding(bindingID)
+	if err != nil || !ok {
+		return ok, err
+	}
+	r.enqueueReplication(
+		getBindingKey(bindingID),
+		ReplicateBindingJobName,
+		"bindingID",
+		bindingID,
+	)
+	return true, nil
+}
+
+// enqueueReplication records that the record having the given key awaits
+// replication and submits a task to replicate it. The write to the primary
+// store has already succeeded by the time this is called, so failures are
+// logged rather than returned. A record that was never replicated continues to
+// count toward the replication lag until it is next written.
+func (r *replicatedStore) enqueueReplication(
+	key string,
+	jobName string,
+	argName string,
+	id string,
+) {
+	logFields := log.Fields{
+		"key": key,
+	}
+	if err := r.markPending(key, time.Now()); err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error(
+			"replication error: error recording write awaiting replication",
+		)
+	}
+	err := r.asyncEngine.SubmitTask(
+		async.NewTask(jobName, map[string]string{argName: id}),
+	)
+	if err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error(
+			"replication error: error submitting replication task",
+		)
+	}
+}
+
+func (r *replicatedStore) ReplicateInstance(instanceID string) error {
+	return r.replicate(getInstanceKey(instanceID), func() error {
+		instance, ok, err := r.Store.GetInstance(instanceID)
+		if err != nil {
+			return fmt.Errorf(
+				`error loading instance "%s" from primary store: %s`,
+				instanceID,
+				err,
+			)
+		}
+		if ok {
+			err = r.secondary.WriteInstance(instance)
+		} else {
+			_, err = r.secondary.DeleteInstance(instanceID)
+		}
+		if err != nil {
+			return fmt.Errorf(
+				`error replicating instance "%s" to secondary store: %s`,
+				instanceID,
+				err,
+			)
+		}
+		return nil
+	})
+}
+
+func (r *replicatedStore) ReplicateBinding(bindingID string) error {
+	return r.replicate(getBindingKey(bindingID), func() error {
+		binding, ok, err := r.Store.GetBinding(bindingID)
+		if err != nil {
+			return fmt.Errorf(
+				`error loading binding "%s" from primary store: %s`,
+				bindingID,
+				err,
+			)
+		}
+		if ok {
+			err = r.secondary.WriteBinding(binding)
+		} else {
+			_, err = r.secondary.DeleteBinding(bindingID)
+		}
+		if err != nil {
+			return fmt.Errorf(
+				`error replicating binding "%s" to secondary store: %s`,
+				bindingID,
+				err,
+			)
+		}
+		return nil
+	})
+}
+
+// replicate copies the record having the given key from the primary store to
+// the secondary using the given function. Replications of the same record are
+// serialized, since one that read the record before another could otherwise
+// overwrite the secondary's copy with what it read after the other has
+// replicated a later write. A record whose replication is already in
+// progress elsewhere isn't replicated, and an error is returned so that it is
+// retried.
+func (r *replicatedStore) replicate(
+	key string,
+	replicateFn func() error,
+) error {
+	lockKey := getReplicationLockKey(key)
+	token := uuid.NewV4().String()
+	locked, err :=
+		r.redisClient.SetNX(lockKey, token, replicationLockTTL).Result()
+	if err != nil {
+		return fmt.Errorf(
+			`error acquiring replication lock for "%s": %s`,
+			key,
+			err,
+		)
+	}
+	if !locked {
+		return fmt.Errorf(`replication of "%s" is already in progress`, key)
+	}
+	defer func() {
+		if err := releaseReplicationLockScript.Run(
+			r.redisClient,
+			[]string{lockKey},
+			token,
+		).Err(); err != nil && err != redis.Nil {
+			log.WithFields(log.Fields{
+				"key":   key,
+				"error": err,
+			}).Error("replication error: error releasing replication lock")
+		}
+	}()
+	readAt := time.Now()
+	if err := replicateFn(); err != nil {
+		return err
+	}
+	return r.markReplicated(key, readAt)
+}
+
+func (r *replicatedStore) GetReplicationLag() (time.Duration, error) {
+	oldest, err := r.redisClient.ZRangeWithScores(
+		replicationPendingKey,
+		0,
+		0,
+	).Result()
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving oldest pending write: %s", err)
+	}
+	if len(oldest) == 0 {
+		return 0, nil
+	}
+	lag := time.Since(fromMicroseconds(oldest[0].Score))
+	if lag < 0 {
+		return 0, nil
+	}
+	return lag, nil
+}
+
+func (r *replicatedStore) TestConnection() error {
+	if err := r.Store.TestConnection(); err != nil {
+		return err
+	}
+	if err := r.secondary.TestConnection(); err != nil {
+		return fmt.Errorf("error connecting to secondary store: %s", err)
+	}
+	return nil
+}
+
+func (r *replicatedStore) markPending(key string, writtenAt time.Time) error {
+	return markReplicationPendingScript.Run(
+		r.redisClient,
+		[]string{replicationPendingKey, replicationLatestKey},
+		key,
+		toMicroseconds(writtenAt),
+	).Err()
+}
+
+func (r *replicatedStore) markReplicated(key string, readAt time.Time) error {
+	err := markReplicatedScript.Run(
+		r.redisClient,
+		[]string{replicationPendingKey, replicationLatestKey},
+		key,
+		toMicroseconds(readAt),
+	).Err()
+	if err != nil && err != redis.Nil {
+		return fmt.Errorf(
+			`error recording replication of "%s": %s`,
+			key,
+			err,
+		)
+	}
+	return nil
+}
+
+func getReplicationLockKey(key string) string {
+	return fmt.Sprintf("replication:lock:%s", key)
+}
+
+func toMicroseconds(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano()/int64(time.Microsecond), 10)
+}
+
+func fromMicroseconds(microseconds float64) time.Time {
+	return time.Unix(0, int64(microseconds)*int64(time.Microsecond))
+}
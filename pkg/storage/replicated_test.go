@@ -0,0 +1,205 @@
+package storage
+
+import (
+	"log"
+	"testing"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/async"
+	fakeAsync "github.com/Azure/open-service-broker-azure/pkg/async/fake"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fake"
+	"github.com/go-redis/redis"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var secondaryRedisClient = redis.NewClient(&redis.Options{
+	Addr: "redis:6379",
+	DB:   2,
+})
+
+func TestReplicatedStoreWriteInstance(t *testing.T) {
+	store, engine := getTestReplicatedStore()
+	instance := getTestInstance()
+	err := store.WriteInstance(instance)
+	assert.Nil(t, err)
+	// The instance is written to the primary only until it is replicated
+	_, ok, err := testStore.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(
+		t,
+		redis.Nil,
+		secondaryRedisClient.Get(getInstanceKey(instance.InstanceID)).Err(),
+	)
+	task := getSubmittedReplicationTask(
+		engine,
+		ReplicateInstanceJobName,
+		"instanceID",
+		instance.InstanceID,
+	)
+	assert.NotNil(t, task)
+	lag, err := store.GetReplicationLag()
+	assert.Nil(t, err)
+	assert.True(t, lag > 0)
+	err = store.ReplicateInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.Nil(
+		t,
+		secondaryRedisClient.Get(getInstanceKey(instance.InstanceID)).Err(),
+	)
+	lag, err = store.GetReplicationLag()
+	assert.Nil(t, err)
+	assert.Equal(t, time.Duration(0), lag)
+}
+
+func TestReplicatedStoreDeleteInstance(t *testing.T) {
+	store, engine := getTestReplicatedStore()
+	instance := getTestInstance()
+	assert.Nil(t, store.WriteInstance(instance))
+	assert.Nil(t, store.ReplicateInstance(instance.InstanceID))
+	engine.SubmittedTasks = map[string]async.Task{}
+	ok, err := store.DeleteInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.NotNil(
+		t,
+		getSubmittedReplicationTask(
+			engine,
+			ReplicateInstanceJobName,
+			"instanceID",
+			instance.InstanceID,
+		),
+	)
+	// The instance no longer exists in the primary, so replicating it deletes
+	// it from the secondary
+	assert.Nil(t, store.ReplicateInstance(instance.InstanceID))
+	assert.Equal(
+		t,
+		redis.Nil,
+		secondaryRedisClient.Get(getInstanceKey(instance.InstanceID)).Err(),
+	)
+	lag, err := store.GetReplicationLag()
+	assert.Nil(t, err)
+	assert.Equal(t, time.Duration(0), lag)
+}
+
+func TestReplicatedStoreDeleteNonExistingInstance(t *testing.T) {
+	store, engine := getTestReplicatedStore()
+	ok, err := store.DeleteInstance(uuid.NewV4().String())
+	assert.Nil(t, err)
+	assert.False(t, ok)
+	assert.Empty(t, engine.SubmittedTasks)
+}
+
+func TestReplicatedStoreWriteAndDeleteBinding(t *testing.T) {
+	store, engine := getTestReplicatedStore()
+	binding := getTestBinding()
+	assert.Nil(t, store.WriteBinding(binding))
+	assert.NotNil(
+		t,
+		getSubmittedReplicationTask(
+			engine,
+			ReplicateBindingJobName,
+			"bindingID",
+			binding.BindingID,
+		),
+	)
+	assert.Nil(t, store.ReplicateBinding(binding.BindingID))
+	assert.Nil(
+		t,
+		secondaryRedisClient.Get(getBindingKey(binding.BindingID)).Err(),
+	)
+	ok, err := store.DeleteBinding(binding.BindingID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Nil(t, store.ReplicateBinding(binding.BindingID))
+	assert.Equal(
+		t,
+		redis.Nil,
+		secondaryRedisClient.Get(getBindingKey(binding.BindingID)).Err(),
+	)
+}
+
+func TestReplicationOfRecordAlreadyBeingReplicatedFails(t *testing.T) {
+	store, _ := getTestReplicatedStore()
+	instance := getTestInstance()
+	assert.Nil(t, store.WriteInstance(instance))
+	// Simulate replication of the same instance that is in progress elsewhere
+	lockKey := getReplicationLockKey(getInstanceKey(instance.InstanceID))
+	assert.Nil(t, redisClient.Set(lockKey, "other", time.Minute).Err())
+	assert.NotNil(t, store.ReplicateInstance(instance.InstanceID))
+	assert.Equal(
+		t,
+		redis.Nil,
+		secondaryRedisClient.Get(getInstanceKey(instance.InstanceID)).Err(),
+	)
+	// The replication that holds the lock isn't interfered with
+	assert.Equal(t, "other", redisClient.Get(lockKey).Val())
+	assert.Nil(t, redisClient.Del(lockKey).Err())
+	assert.Nil(t, store.ReplicateInstance(instance.InstanceID))
+	assert.Nil(
+		t,
+		secondaryRedisClient.Get(getInstanceKey(instance.InstanceID)).Err(),
+	)
+	// Nor is the lock left held once replication completes
+	assert.Equal(t, redis.Nil, redisClient.Get(lockKey).Err())
+}
+
+func TestReplicationLag(t *testing.T) {
+	store, _ := getTestReplicatedStore()
+	r := store.(*replicatedStore)
+	key := getInstanceKey(uuid.NewV4().String())
+	firstWrite := time.Now().Add(-time.Hour)
+	assert.Nil(t, r.markPending(key, firstWrite))
+	// A second write doesn't reset how long the record has been pending
+	assert.Nil(t, r.markPending(key, firstWrite.Add(30*time.Minute)))
+	lag, err := store.GetReplicationLag()
+	assert.Nil(t, err)
+	assert.True(t, lag >= time.Hour)
+	// Replication that read the record before the second write leaves the
+	// record pending as of the second write
+	assert.Nil(t, r.markReplicated(key, firstWrite.Add(time.Minute)))
+	lag, err = store.GetReplicationLag()
+	assert.Nil(t, err)
+	assert.True(t, lag >= 30*time.Minute)
+	assert.True(t, lag < time.Hour)
+	assert.Nil(t, r.markReplicated(key, time.Now()))
+	lag, err = store.GetReplicationLag()
+	assert.Nil(t, err)
+	assert.Equal(t, time.Duration(0), lag)
+}
+
+func getTestReplicatedStore() (ReplicatedStore, *fakeAsync.Engine) {
+	fakeModule, err := fake.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	fakeCatalog, err := fakeModule.GetCatalog()
+	if err != nil {
+		log.Fatal(err)
+	}
+	engine := fakeAsync.NewEngine()
+	return NewReplicatedStore(
+		testStore,
+		NewStore(secondaryRedisClient, fakeCatalog, noopCodec),
+		redisClient,
+		engine,
+	), engine
+}
+
+// getSubmittedReplicationTask returns the task submitted to the given engine
+// for replicating the record having the given id, or nil if there is none
+func getSubmittedReplicationTask(
+	engine *fakeAsync.Engine,
+	jobName string,
+	argName string,
+	id string,
+) async.Task {
+	for _, task := range engine.SubmittedTasks {
+		if task.GetJobName() == jobName && task.GetArgs()[argName] == id {
+			return task
+		}
+	}
+	return nil
+}
@@ -1,13 +1,29 @@
 package storage
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Azure/open-service-broker-azure/pkg/crypto"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
 	"github.com/go-redis/redis"
 )
 
+const (
+	// provisioningSLAWithinTargetKey and provisioningSLABreachedKey name hashes
+	// of per-plan counts of instances provisioned within, and not within, their
+	// provisioning SLA targets
+	provisioningSLAWithinTargetKey = "provisioning-sla:within-target"
+	provisioningSLABreachedKey     = "provisioning-sla:breached"
+	// keyRotationProgressKey names the record of how far the re-encryption of
+	// stored records with the current key version has got
+	keyRotationProgressKey = "key-rotation:progress"
+)
+
 // Store is an interface to be implemented by types capable of handling
 // persistence for other broker-related types
 type Store interface {
@@ -21,6 +37,11 @@ type Store interface {
 	GetInstanceByAlias(alias string) (service.Instance, bool, error)
 	// GetInstanceChildCountByAlias returns the number of child instances
 	GetInstanceChildCountByAlias(alias string) (int64, error)
+	// GetInstanceDependentIDs returns the ids of the persisted instances that
+	// were provisioned to depend upon the instance having the given instance id
+	GetInstanceDependentIDs(instanceID string) ([]string, error)
+	// GetInstanceIDs returns the ids of all persisted instances
+	GetInstanceIDs() ([]string, error)
 	// DeleteInstance deletes a persisted instance from the underlying storage by
 	// instance id
 	DeleteInstance(instanceID string) (bool, error)
@@ -32,6 +53,91 @@ type Store interface {
 	// DeleteBinding deletes a persisted binding from the underlying storage by
 	// binding id
 	DeleteBinding(bindingID string) (bool, error)
+	// GetBindingIDs returns the ids of all persisted bindings to the instance
+	// having the given instance id
+	GetBindingIDs(instanceID string) ([]string, error)
+	// ClaimProvisioningRequest records that a provisioning request having the
+	// given fingerprint was accepted for the given instance id, unless another
+	// was recorded for the same instance id within the given window. It returns
+	// a bool indicating whether the claim succeeded and, if it didn't, the
+	// fingerprint of the request that was recorded earlier.
+	ClaimProvisioningRequest(
+		instanceID string,
+		fingerprint string,
+		window time.Duration,
+	) (bool, string, error)
+	// ReleaseProvisioningRequest forgets any provisioning request recorded for
+	// the given instance id
+	ReleaseProvisioningRequest(instanceID string) error
+	// ReserveProvisioningCapacity reserves, for the given instance id, one of
+	// the named pool's limited number of provisioning slots or, if all of them
+	// are taken or others are already waiting for one, a place at the back of
+	// the pool's queue, unless the queue is already maxQueueDepth long.
+	// Reserving for an instance id that already holds a slot or a place
+	// succeeds without taking another. It returns a bool indicating whether the
+	// reservation succeeded and the instance id's position in the queue, which
+	// is zero if it holds a slot.
+	ReserveProvisioningCapacity(
+		pool string,
+		instanceID string,
+		maxConcurrent int,
+		maxQueueDepth int,
+	) (bool, int64, error)
+	// ClaimProvisioningCapacity obtains one of the named pool's provisioning
+	// slots for the given instance id, if it doesn't already hold one, once the
+	// instance id is at the front of the pool's queue and a slot is free. An
+	// instance id without a place in the queue is given one at the back. It
+	// returns a bool indicating whether the instance id holds a slot and, if it
+	// doesn't, its position in the queue.
+	ClaimProvisioningCapacity(
+		pool string,
+		instanceID string,
+		maxConcurrent int,
+	) (bool, int64, error)
+	// GetProvisioningQueuePosition returns the given instance id's position in
+	// the named pool's queue, or zero if it isn't queued
+	GetProvisioningQueuePosition(pool string, instanceID string) (int64, error)
+	// ReleaseProvisioningCapacity gives up the slot or place in the queue, if
+	// any, that the given instance id holds in the named pool
+	ReleaseProvisioningCapacity(pool string, instanceID string) error
+	// RecordProvisioningSLAOutcome counts one instance of the given plan as
+	// having been provisioned either within its provisioning SLA target or not
+	RecordProvisioningSLAOutcome(planID string, breached bool) error
+	// GetProvisioningSLAOutcomes returns, keyed by plan id, the counts of
+	// instances provisioned within and not within their provisioning SLA
+	// targets. Plans for which nothing was counted are omitted.
+	GetProvisioningSLAOutcomes() (map[string]service.ProvisioningSLAOutcomes, error) // nolint: lll
+	// RecordProvisioningStepDuration counts the given duration towards the
+	// average time taken by the named provisioning step for the given plan
+	RecordProvisioningStepDuration(
+		planID string,
+		stepName string,
+		duration time.Duration,
+	) error
+	// GetProvisioningStepDurations returns, keyed by step name, the average
+	// time taken by each of the given plan's provisioning steps. Steps for which
+	// nothing was recorded are omitted.
+	GetProvisioningStepDurations(planID string) (map[string]time.Duration, error)
+	// WriteKeyRotationProgress persists the progress of the rotation of the
+	// encryption key, replacing any that was persisted earlier
+	WriteKeyRotationProgress(progress service.KeyRotationProgress) error
+	// GetKeyRotationProgress retrieves the persisted progress of the rotation
+	// of the encryption key. It returns a bool indicating whether any was
+	// found.
+	GetKeyRotationProgress() (service.KeyRotationProgress, bool, error)
+	// AppendStepTrace adds the given step trace to those persisted for the
+	// instance having the given instance id. Only the most recent maxTraces are
+	// kept, and all of them are forgotten once ttl has passed without another
+	// being added.
+	AppendStepTrace(
+		instanceID string,
+		trace service.StepTrace,
+		maxTraces int,
+		ttl time.Duration,
+	) error
+	// GetStepTraces returns the step traces persisted for the instance having
+	// the given instance id, oldest first
+	GetStepTraces(instanceID string) ([]service.StepTrace, error)
 	// TestConnection tests the connection to the underlying database (if there
 	// is one)
 	TestConnection() error
@@ -40,7 +146,11 @@ type Store interface {
 type store struct {
 	redisClient *redis.Client
 	catalog     service.Catalog
-	codec       crypto.Codec
+	// subscriptionCatalogs is keyed by Azure subscription ID and holds the
+	// catalogs whose services manage resources in subscriptions other than the
+	// broker's own
+	subscriptionCatalogs map[string]service.Catalog
+	codec                crypto.Codec
 }
 
 // NewStore returns a new Redis-based implementation of the Store interface
@@ -48,12 +158,36 @@ func NewStore(
 	redisClient *redis.Client,
 	catalog service.Catalog,
 	codec crypto.Codec,
+) Store {
+	return NewMultiSubscriptionStore(redisClient, catalog, nil, codec)
+}
+
+// NewMultiSubscriptionStore returns a new Redis-based implementation of the
+// Store interface that resolves the services of instances provisioned into
+// other Azure subscriptions from the catalogs, keyed by subscription ID, that
+// manage resources in those subscriptions. Instances provisioned into any
+// subscription not among them are resolved from the given catalog.
+func NewMultiSubscriptionStore(
+	redisClient *redis.Client,
+	catalog service.Catalog,
+	subscriptionCatalogs map[string]service.Catalog,
+	codec crypto.Codec,
 ) Store {
 	return &store{
-		redisClient: redisClient,
-		catalog:     catalog,
-		codec:       codec,
+		redisClient:          redisClient,
+		catalog:              catalog,
+		subscriptionCatalogs: subscriptionCatalogs,
+		codec:                codec,
+	}
+}
+
+// getCatalog returns the catalog whose services manage resources in the
+// specified subscription
+func (s *store) getCatalog(subscriptionID string) service.Catalog {
+	if catalog, ok := s.subscriptionCatalogs[subscriptionID]; ok {
+		return catalog
 	}
+	return s.catalog
 }
 
 func (s *store) WriteInstance(instance service.Instance) error {
@@ -72,6 +206,9 @@ func (s *store) WriteInstance(instance service.Instance) error {
 		parentAliasChildrenKey := getInstanceAliasChildrenKey(instance.ParentAlias)
 		pipeline.SAdd(parentAliasChildrenKey, instance.InstanceID)
 	}
+	for _, dependencyID := range instance.DependsOn {
+		pipeline.SAdd(getInstanceDependentsKey(dependencyID), instance.InstanceID)
+	}
 	_, err = pipeline.Exec()
 	if err != nil {
 		return fmt.Errorf(
@@ -99,7 +236,9 @@ func (s *store) GetInstance(instanceID string) (service.Instance, bool, error) {
 	if err != nil {
 		return instance, false, err
 	}
-	svc, ok := s.catalog.GetService(instance.ServiceID)
+	svc, ok := s.getCatalog(instance.SubscriptionID).GetService(
+		instance.ServiceID,
+	)
 	if !ok {
 		return instance,
 			false,
@@ -180,6 +319,9 @@ func (s *store) DeleteInstance(instanceID string) (bool, error) {
 		parentAliasChildrenKey := getInstanceAliasChildrenKey(instance.ParentAlias)
 		pipeline.SRem(parentAliasChildrenKey, instance.InstanceID)
 	}
+	for _, dependencyID := range instance.DependsOn {
+		pipeline.SRem(getInstanceDependentsKey(dependencyID), instance.InstanceID)
+	}
 	_, err = pipeline.Exec()
 	if err != nil {
 		return false, fmt.Errorf(
@@ -196,6 +338,45 @@ func (s *store) GetInstanceChildCountByAlias(alias string) (int64, error) {
 	return s.redisClient.SCard(aliasChildrenKey).Result()
 }
 
+func (s *store) GetInstanceDependentIDs(instanceID string) ([]string, error) {
+	dependentIDs, err :=
+		s.redisClient.SMembers(getInstanceDependentsKey(instanceID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error listing dependents of instance "%s": %s`,
+			instanceID,
+			err,
+		)
+	}
+	sort.Strings(dependentIDs)
+	return dependentIDs, nil
+}
+
+func (s *store) GetInstanceIDs() ([]string, error) {
+	instanceKeyPrefix := getInstanceKey("")
+	aliasKeyPrefix := getInstanceAliasKey("")
+	instanceIDs := []string{}
+	// A scan may return the same key more than once
+	seen := map[string]bool{}
+	iter := s.redisClient.Scan(0, getInstanceKey("*"), 0).Iterator()
+	for iter.Next() {
+		key := iter.Val()
+		// Alias keys share the prefix of instance keys
+		if seen[key] || strings.HasPrefix(key, aliasKeyPrefix) {
+			continue
+		}
+		seen[key] = true
+		instanceIDs = append(
+			instanceIDs,
+			strings.TrimPrefix(key, instanceKeyPrefix),
+		)
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("error listing instances: %s", err)
+	}
+	return instanceIDs, nil
+}
+
 func getInstanceKey(instanceID string) string {
 	return fmt.Sprintf("instances:%s", instanceID)
 }
@@ -208,6 +389,13 @@ func getInstanceAliasChildrenKey(alias string) string {
 	return fmt.Sprintf("instances:aliases:%s:children", alias)
 }
 
+// getInstanceDependentsKey returns the key of the set of ids of instances that
+// depend upon the instance having the given id. It doesn't share the prefix of
+// instance keys, so that it isn't mistaken for one when instances are listed.
+func getInstanceDependentsKey(instanceID string) string {
+	return fmt.Sprintf("instance-dependents:%s", instanceID)
+}
+
 func (s *store) WriteBinding(binding service.Binding) error {
 	key := getBindingKey(binding.BindingID)
 	json, err := binding.ToJSON(s.codec)
@@ -266,10 +454,494 @@ func (s *store) DeleteBinding(bindingID string) (bool, error) {
 	return true, nil
 }
 
+// GetBindingIDs scans every persisted binding, since bindings aren't indexed by
+// instance. It's meant for infrequent, administrative use.
+func (s *store) GetBindingIDs(instanceID string) ([]string, error) {
+	bindingKeyPrefix := getBindingKey("")
+	bindingIDs := []string{}
+	// A scan may return the same key more than once
+	seen := map[string]bool{}
+	iter := s.redisClient.Scan(0, getBindingKey("*"), 0).Iterator()
+	for iter.Next() {
+		key := iter.Val()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		bytes, err := s.redisClient.Get(key).Bytes()
+		if err == redis.Nil {
+			// The binding was deleted since the scan found it
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf(`error retrieving binding "%s": %s`, key, err)
+		}
+		// Only the binding's unencrypted fields are needed
+		binding, err := service.NewBindingFromJSON(bytes, nil, nil, s.codec)
+		if err != nil {
+			return nil, err
+		}
+		if binding.InstanceID == instanceID {
+			bindingIDs = append(
+				bindingIDs,
+				strings.TrimPrefix(key, bindingKeyPrefix),
+			)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("error listing bindings: %s", err)
+	}
+	return bindingIDs, nil
+}
+
 func getBindingKey(bindingID string) string {
 	return fmt.Sprintf("bindings:%s", bindingID)
 }
 
+func (s *store) ClaimProvisioningRequest(
+	instanceID string,
+	fingerprint string,
+	window time.Duration,
+) (bool, string, error) {
+	key := getProvisioningRequestKey(instanceID)
+	// The earlier claim can expire between the two commands, so try again once
+	// if it does
+	for i := 0; i < 2; i++ {
+		claimed, err := s.redisClient.SetNX(key, fingerprint, window).Result()
+		if err != nil {
+			return false, "", fmt.Errorf(
+				`error claiming provisioning request for instance "%s": %s`,
+				instanceID,
+				err,
+			)
+		}
+		if claimed {
+			return true, "", nil
+		}
+		existingFingerprint, err := s.redisClient.Get(key).Result()
+		if err == redis.Nil {
+			continue
+		} else if err != nil {
+			return false, "", fmt.Errorf(
+				`error retrieving provisioning request for instance "%s": %s`,
+				instanceID,
+				err,
+			)
+		}
+		return false, existingFingerprint, nil
+	}
+	return false, "", fmt.Errorf(
+		`error claiming provisioning request for instance "%s"`,
+		instanceID,
+	)
+}
+
+func (s *store) ReleaseProvisioningRequest(instanceID string) error {
+	key := getProvisioningRequestKey(instanceID)
+	if err := s.redisClient.Del(key).Err(); err != nil {
+		return fmt.Errorf(
+			`error releasing provisioning request for instance "%s": %s`,
+			instanceID,
+			err,
+		)
+	}
+	return nil
+}
+
+func getProvisioningRequestKey(instanceID string) string {
+	return fmt.Sprintf("provisioning-requests:%s", instanceID)
+}
+
+// reserveProvisioningCapacityScript atomically checks a pool's slots and queue
+// before recording a new reservation. KEYS[1] names the set of instance ids
+// holding slots, and KEYS[2] the queue, which is a sorted set of instance ids
+// scored by the time they joined it.
+var reserveProvisioningCapacityScript = redis.NewScript(`
+if redis.call("SISMEMBER", KEYS[1], ARGV[1]) == 1 then
+  return {1, 0}
+end
+local rank = redis.call("ZRANK", KEYS[2], ARGV[1])
+if rank then
+  return {1, rank + 1}
+end
+local queued = redis.call("ZCARD", KEYS[2])
+if queued == 0 and redis.call("SCARD", KEYS[1]) < tonumber(ARGV[2]) then
+  redis.call("SADD", KEYS[1], ARGV[1])
+  return {1, 0}
+end
+if queued >= tonumber(ARGV[3]) then
+  return {0, queued}
+end
+redis.call("ZADD", KEYS[2], ARGV[4], ARGV[1])
+return {1, queued + 1}
+`)
+
+// claimProvisioningCapacityScript atomically exchanges a place at the front of
+// a pool's queue for a free slot. Its keys are as for
+// reserveProvisioningCapacityScript.
+var claimProvisioningCapacityScript = redis.NewScript(`
+if redis.call("SISMEMBER", KEYS[1], ARGV[1]) == 1 then
+  return {1, 0}
+end
+redis.call("ZADD", KEYS[2], "NX", ARGV[3], ARGV[1])
+local rank = redis.call("ZRANK", KEYS[2], ARGV[1])
+if rank == 0 and redis.call("SCARD", KEYS[1]) < tonumber(ARGV[2]) then
+  redis.call("ZREM", KEYS[2], ARGV[1])
+  redis.call("SADD", KEYS[1], ARGV[1])
+  return {1, 0}
+end
+return {0, rank + 1}
+`)
+
+func (s *store) ReserveProvisioningCapacity(
+	pool string,
+	instanceID string,
+	maxConcurrent int,
+	maxQueueDepth int,
+) (bool, int64, error) {
+	reserved, position, err := runProvisioningCapacityScript(
+		s.redisClient,
+		reserveProvisioningCapacityScript,
+		pool,
+		instanceID,
+		maxConcurrent,
+		maxQueueDepth,
+		time.Now().UnixNano(),
+	)
+	if err != nil {
+		return false, 0, fmt.Errorf(
+			`error reserving provisioning capacity in pool "%s" for instance `+
+				`"%s": %s`,
+			pool,
+			instanceID,
+			err,
+		)
+	}
+	return reserved, position, nil
+}
+
+func (s *store) ClaimProvisioningCapacity(
+	pool string,
+	instanceID string,
+	maxConcurrent int,
+) (bool, int64, error) {
+	claimed, position, err := runProvisioningCapacityScript(
+		s.redisClient,
+		claimProvisioningCapacityScript,
+		pool,
+		instanceID,
+		maxConcurrent,
+		time.Now().UnixNano(),
+	)
+	if err != nil {
+		return false, 0, fmt.Errorf(
+			`error claiming provisioning capacity in pool "%s" for instance `+
+				`"%s": %s`,
+			pool,
+			instanceID,
+			err,
+		)
+	}
+	return claimed, position, nil
+}
+
+// runProvisioningCapacityScript runs one of the scripts that manage a
+// provisioning capacity pool's slots and queue, each of which returns a flag
+// and a queue position
+func runProvisioningCapacityScript(
+	redisClient *redis.Client,
+	script *redis.Script,
+	pool string,
+	instanceID string,
+	args ...interface{},
+) (bool, int64, error) {
+	res, err := script.Run(
+		redisClient,
+		[]string{
+			getProvisioningCapacitySlotsKey(pool),
+			getProvisioningCapacityQueueKey(pool),
+		},
+		append([]interface{}{instanceID}, args...)...,
+	).Result()
+	if err != nil {
+		return false, 0, err
+	}
+	vals, ok := res.([]interface{})
+	if !ok || len(vals) != 2 {
+		return false, 0, fmt.Errorf("unexpected result: %v", res)
+	}
+	flag, _ := vals[0].(int64)
+	position, _ := vals[1].(int64)
+	return flag == 1, position, nil
+}
+
+func (s *store) GetProvisioningQueuePosition(
+	pool string,
+	instanceID string,
+) (int64, error) {
+	rank, err := s.redisClient.ZRank(
+		getProvisioningCapacityQueueKey(pool),
+		instanceID,
+	).Result()
+	if err == redis.Nil {
+		return 0, nil
+	} else if err != nil {
+		return 0, fmt.Errorf(
+			`error retrieving position of instance "%s" in provisioning queue `+
+				`"%s": %s`,
+			instanceID,
+			pool,
+			err,
+		)
+	}
+	return rank + 1, nil
+}
+
+func (s *store) ReleaseProvisioningCapacity(
+	pool string,
+	instanceID string,
+) error {
+	pipeline := s.redisClient.TxPipeline()
+	pipeline.SRem(getProvisioningCapacitySlotsKey(pool), instanceID)
+	pipeline.ZRem(getProvisioningCapacityQueueKey(pool), instanceID)
+	if _, err := pipeline.Exec(); err != nil {
+		return fmt.Errorf(
+			`error releasing provisioning capacity in pool "%s" for instance `+
+				`"%s": %s`,
+			pool,
+			instanceID,
+			err,
+		)
+	}
+	return nil
+}
+
+func getProvisioningCapacitySlotsKey(pool string) string {
+	return fmt.Sprintf("provisioning-capacity:%s:slots", pool)
+}
+
+func getProvisioningCapacityQueueKey(pool string) string {
+	return fmt.Sprintf("provisioning-capacity:%s:queue", pool)
+}
+
+func (s *store) RecordProvisioningSLAOutcome(
+	planID string,
+	breached bool,
+) error {
+	key := provisioningSLAWithinTargetKey
+	if breached {
+		key = provisioningSLABreachedKey
+	}
+	if err := s.redisClient.HIncrBy(key, planID, 1).Err(); err != nil {
+		return fmt.Errorf(
+			`error recording provisioning SLA outcome for plan "%s": %s`,
+			planID,
+			err,
+		)
+	}
+	return nil
+}
+
+func (s *store) GetProvisioningSLAOutcomes() (
+	map[string]service.ProvisioningSLAOutcomes,
+	error,
+) {
+	outcomes := map[string]service.ProvisioningSLAOutcomes{}
+	for _, key := range []string{
+		provisioningSLAWithinTargetKey,
+		provisioningSLABreachedKey,
+	} {
+		counts, err := s.redisClient.HGetAll(key).Result()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"error retrieving provisioning SLA outcomes: %s",
+				err,
+			)
+		}
+		for planID, countStr := range counts {
+			count, err := strconv.ParseInt(countStr, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf(
+					`error parsing provisioning SLA outcomes for plan "%s": %s`,
+					planID,
+					err,
+				)
+			}
+			planOutcomes := outcomes[planID]
+			if key == provisioningSLABreachedKey {
+				planOutcomes.Breached = count
+			} else {
+				planOutcomes.WithinTarget = count
+			}
+			outcomes[planID] = planOutcomes
+		}
+	}
+	return outcomes, nil
+}
+
+func (s *store) RecordProvisioningStepDuration(
+	planID string,
+	stepName string,
+	duration time.Duration,
+) error {
+	key := getProvisioningStepDurationsKey(planID)
+	pipeline := s.redisClient.TxPipeline()
+	pipeline.HIncrByFloat(key, stepName+":seconds", duration.Seconds())
+	pipeline.HIncrBy(key, stepName+":count", 1)
+	if _, err := pipeline.Exec(); err != nil {
+		return fmt.Errorf(
+			`error recording duration of provisioning step "%s" for plan "%s": %s`,
+			stepName,
+			planID,
+			err,
+		)
+	}
+	return nil
+}
+
+func (s *store) GetProvisioningStepDurations(
+	planID string,
+) (map[string]time.Duration, error) {
+	fields, err := s.redisClient.HGetAll(
+		getProvisioningStepDurationsKey(planID),
+	).Result()
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error retrieving provisioning step durations for plan "%s": %s`,
+			planID,
+			err,
+		)
+	}
+	durations := map[string]time.Duration{}
+	for field, secondsStr := range fields {
+		if !strings.HasSuffix(field, ":seconds") {
+			continue
+		}
+		stepName := strings.TrimSuffix(field, ":seconds")
+		seconds, err := strconv.ParseFloat(secondsStr, 64)
+		if err != nil {
+			return nil, fmt.Errorf(
+				`error parsing duration of provisioning step "%s" for plan "%s": %s`,
+				stepName,
+				planID,
+				err,
+			)
+		}
+		count, err := strconv.ParseInt(fields[stepName+":count"], 10, 64)
+		if err != nil || count <= 0 {
+			continue
+		}
+		durations[stepName] =
+			time.Duration(seconds / float64(count) * float64(time.Second))
+	}
+	return durations, nil
+}
+
+func getProvisioningStepDurationsKey(planID string) string {
+	return fmt.Sprintf("provisioning-step-durations:%s", planID)
+}
+
+func (s *store) WriteKeyRotationProgress(
+	progress service.KeyRotationProgress,
+) error {
+	progressJSON, err := json.Marshal(progress)
+	if err != nil {
+		return err
+	}
+	if err := s.redisClient.Set(
+		keyRotationProgressKey,
+		progressJSON,
+		0,
+	).Err(); err != nil {
+		return fmt.Errorf("error writing key rotation progress: %s", err)
+	}
+	return nil
+}
+
+func (s *store) GetKeyRotationProgress() (
+	service.KeyRotationProgress,
+	bool,
+	error,
+) {
+	progress := service.KeyRotationProgress{}
+	progressJSON, err := s.redisClient.Get(keyRotationProgressKey).Bytes()
+	if err == redis.Nil {
+		return progress, false, nil
+	} else if err != nil {
+		return progress, false, fmt.Errorf(
+			"error retrieving key rotation progress: %s",
+			err,
+		)
+	}
+	if err := json.Unmarshal(progressJSON, &progress); err != nil {
+		return progress, false, fmt.Errorf(
+			"error parsing key rotation progress: %s",
+			err,
+		)
+	}
+	return progress, true, nil
+}
+
+func (s *store) AppendStepTrace(
+	instanceID string,
+	trace service.StepTrace,
+	maxTraces int,
+	ttl time.Duration,
+) error {
+	traceJSON, err := json.Marshal(trace)
+	if err != nil {
+		return fmt.Errorf(
+			`error marshaling trace of step "%s" for instance "%s": %s`,
+			trace.StepName,
+			instanceID,
+			err,
+		)
+	}
+	key := getStepTracesKey(instanceID)
+	pipeline := s.redisClient.TxPipeline()
+	pipeline.RPush(key, traceJSON)
+	pipeline.LTrim(key, int64(-maxTraces), -1)
+	pipeline.Expire(key, ttl)
+	if _, err := pipeline.Exec(); err != nil {
+		return fmt.Errorf(
+			`error persisting trace of step "%s" for instance "%s": %s`,
+			trace.StepName,
+			instanceID,
+			err,
+		)
+	}
+	return nil
+}
+
+func (s *store) GetStepTraces(instanceID string) ([]service.StepTrace, error) {
+	tracesJSON, err := s.redisClient.LRange(
+		getStepTracesKey(instanceID),
+		0,
+		-1,
+	).Result()
+	if err != nil {
+		return nil, fmt.Errorf(
+			`error retrieving step traces for instance "%s": %s`,
+			instanceID,
+			err,
+		)
+	}
+	traces := make([]service.StepTrace, len(tracesJSON))
+	for i, traceJSON := range tracesJSON {
+		if err := json.Unmarshal([]byte(traceJSON), &traces[i]); err != nil {
+			return nil, fmt.Errorf(
+				`error parsing step traces for instance "%s": %s`,
+				instanceID,
+				err,
+			)
+		}
+	}
+	return traces, nil
+}
+
+func getStepTracesKey(instanceID string) string {
+	return fmt.Sprintf("step-traces:%s", instanceID)
+}
+
 func (s *store) TestConnection() error {
 	return s.redisClient.Ping().Err()
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"testing"
+	"time"
 
 	"github.com/Azure/open-service-broker-azure/pkg/crypto/noop"
 	"github.com/Azure/open-service-broker-azure/pkg/service"
@@ -18,13 +19,14 @@ var (
 	redisClient = redis.NewClient(&redis.Options{
 		Addr: "redis:6379",
 	})
+	fakeModule         *fake.Module
 	fakeServiceManager service.ServiceManager
 	testStore          Store
 )
 
 func init() {
 	var err error
-	fakeModule, err := fake.New()
+	fakeModule, err = fake.New()
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -185,6 +187,49 @@ func TestGetExistingInstanceWithParent(t *testing.T) {
 	assert.Equal(t, instance, retrievedInstance)
 }
 
+func TestGetExistingInstanceInOtherSubscription(t *testing.T) {
+	otherFakeModule, err := fake.New()
+	assert.Nil(t, err)
+	otherFakeCatalog, err := otherFakeModule.GetCatalog()
+	assert.Nil(t, err)
+	fakeCatalog, err := fakeModule.GetCatalog()
+	assert.Nil(t, err)
+	multiSubscriptionStore := NewMultiSubscriptionStore(
+		redisClient,
+		fakeCatalog,
+		map[string]service.Catalog{"other-subscription": otherFakeCatalog},
+		noopCodec,
+	)
+	instance := getTestInstance()
+	instance.SubscriptionID = "other-subscription"
+	err = multiSubscriptionStore.WriteInstance(instance)
+	assert.Nil(t, err)
+	retrievedInstance, ok, err :=
+		multiSubscriptionStore.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	// The instance's service is the one that manages resources in the
+	// subscription the instance was provisioned into
+	assert.True(
+		t,
+		retrievedInstance.Service.GetServiceManager() ==
+			otherFakeModule.ServiceManager,
+	)
+	// An instance provisioned into the broker's own subscription is resolved
+	// from the broker's own catalog
+	instance = getTestInstance()
+	err = multiSubscriptionStore.WriteInstance(instance)
+	assert.Nil(t, err)
+	retrievedInstance, ok, err =
+		multiSubscriptionStore.GetInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.True(
+		t,
+		retrievedInstance.Service.GetServiceManager() == fakeServiceManager,
+	)
+}
+
 func TestGetNonExistingInstanceByAlias(t *testing.T) {
 	alias := uuid.NewV4().String()
 	aliasKey := getInstanceAliasKey(alias)
@@ -333,6 +378,46 @@ func TestGetInstanceChildCountByAlias(t *testing.T) {
 	}
 }
 
+func TestGetInstanceDependentIDs(t *testing.T) {
+	dependencyID := uuid.NewV4().String()
+	instance := getTestInstance()
+	instance.DependsOn = []string{dependencyID}
+	// Store the dependent instance
+	err := testStore.WriteInstance(instance)
+	assert.Nil(t, err)
+	// Assert that it's listed as a dependent
+	dependentIDs, err := testStore.GetInstanceDependentIDs(dependencyID)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{instance.InstanceID}, dependentIDs)
+	// And that the index isn't mistaken for an instance
+	instanceIDs, err := testStore.GetInstanceIDs()
+	assert.Nil(t, err)
+	for _, instanceID := range instanceIDs {
+		assert.NotContains(t, instanceID, dependencyID)
+	}
+	// Delete the dependent instance
+	_, err = testStore.DeleteInstance(instance.InstanceID)
+	assert.Nil(t, err)
+	// Assert that it's no longer listed as a dependent
+	dependentIDs, err = testStore.GetInstanceDependentIDs(dependencyID)
+	assert.Nil(t, err)
+	assert.Empty(t, dependentIDs)
+}
+
+func TestGetInstanceIDs(t *testing.T) {
+	instance := getTestInstance()
+	instance.Alias = uuid.NewV4().String()
+	// Store the instance
+	err := testStore.WriteInstance(instance)
+	assert.Nil(t, err)
+	// List the instances
+	instanceIDs, err := testStore.GetInstanceIDs()
+	assert.Nil(t, err)
+	// Assert that the instance is listed and its alias isn't
+	assert.Contains(t, instanceIDs, instance.InstanceID)
+	assert.NotContains(t, instanceIDs, "aliases:"+instance.Alias)
+}
+
 func TestWriteBinding(t *testing.T) {
 	binding := getTestBinding()
 	key := getBindingKey(binding.BindingID)
@@ -409,6 +494,136 @@ func TestDeleteExistingBinding(t *testing.T) {
 	assert.Equal(t, redis.Nil, strCmd.Err())
 }
 
+func TestGetBindingIDs(t *testing.T) {
+	binding := getTestBinding()
+	otherBinding := getTestBinding()
+	// Store the bindings
+	err := testStore.WriteBinding(binding)
+	assert.Nil(t, err)
+	err = testStore.WriteBinding(otherBinding)
+	assert.Nil(t, err)
+	// List the instance's bindings
+	bindingIDs, err := testStore.GetBindingIDs(binding.InstanceID)
+	assert.Nil(t, err)
+	// Assert that only the instance's binding is listed
+	assert.Equal(t, []string{binding.BindingID}, bindingIDs)
+}
+
+func TestClaimProvisioningRequest(t *testing.T) {
+	instanceID := uuid.NewV4().String()
+	claimed, _, err :=
+		testStore.ClaimProvisioningRequest(instanceID, "foo", time.Minute)
+	assert.Nil(t, err)
+	assert.True(t, claimed)
+	// A second claim within the window fails and reports the first fingerprint
+	claimed, fingerprint, err :=
+		testStore.ClaimProvisioningRequest(instanceID, "bar", time.Minute)
+	assert.Nil(t, err)
+	assert.False(t, claimed)
+	assert.Equal(t, "foo", fingerprint)
+	// Once released, the instance id can be claimed again
+	err = testStore.ReleaseProvisioningRequest(instanceID)
+	assert.Nil(t, err)
+	claimed, _, err =
+		testStore.ClaimProvisioningRequest(instanceID, "bar", time.Minute)
+	assert.Nil(t, err)
+	assert.True(t, claimed)
+}
+
+func TestReserveProvisioningCapacity(t *testing.T) {
+	pool := uuid.NewV4().String()
+	// The first instance takes the only slot, the second is queued, and the
+	// third is refused because the queue is full
+	reserved, position, err :=
+		testStore.ReserveProvisioningCapacity(pool, "a", 1, 1)
+	assert.Nil(t, err)
+	assert.True(t, reserved)
+	assert.Equal(t, int64(0), position)
+	reserved, position, err =
+		testStore.ReserveProvisioningCapacity(pool, "b", 1, 1)
+	assert.Nil(t, err)
+	assert.True(t, reserved)
+	assert.Equal(t, int64(1), position)
+	reserved, _, err = testStore.ReserveProvisioningCapacity(pool, "c", 1, 1)
+	assert.Nil(t, err)
+	assert.False(t, reserved)
+	// Reserving again doesn't take another place
+	reserved, position, err =
+		testStore.ReserveProvisioningCapacity(pool, "b", 1, 1)
+	assert.Nil(t, err)
+	assert.True(t, reserved)
+	assert.Equal(t, int64(1), position)
+	// The queued instance can't claim a slot until the first releases it
+	claimed, position, err := testStore.ClaimProvisioningCapacity(pool, "b", 1)
+	assert.Nil(t, err)
+	assert.False(t, claimed)
+	assert.Equal(t, int64(1), position)
+	err = testStore.ReleaseProvisioningCapacity(pool, "a")
+	assert.Nil(t, err)
+	claimed, _, err = testStore.ClaimProvisioningCapacity(pool, "b", 1)
+	assert.Nil(t, err)
+	assert.True(t, claimed)
+	position, err = testStore.GetProvisioningQueuePosition(pool, "b")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), position)
+}
+
+func TestGetProvisioningStepDurations(t *testing.T) {
+	planID := uuid.NewV4().String()
+	err := testStore.RecordProvisioningStepDuration(planID, "deploy", time.Minute)
+	assert.Nil(t, err)
+	err = testStore.RecordProvisioningStepDuration(
+		planID,
+		"deploy",
+		3*time.Minute,
+	)
+	assert.Nil(t, err)
+	durations, err := testStore.GetProvisioningStepDurations(planID)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]time.Duration{"deploy": 2 * time.Minute}, durations)
+}
+
+func TestWriteKeyRotationProgress(t *testing.T) {
+	progress := service.KeyRotationProgress{
+		KeyVersion:     "2",
+		RunID:          uuid.NewV4().String(),
+		Started:        time.Now().UTC(),
+		Updated:        time.Now().UTC(),
+		LastInstanceID: uuid.NewV4().String(),
+		Rotated:        1,
+	}
+	err := testStore.WriteKeyRotationProgress(progress)
+	assert.Nil(t, err)
+	retrievedProgress, ok, err := testStore.GetKeyRotationProgress()
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, progress.RunID, retrievedProgress.RunID)
+	assert.Equal(t, progress.LastInstanceID, retrievedProgress.LastInstanceID)
+	assert.True(t, progress.Started.Equal(retrievedProgress.Started))
+	assert.Equal(t, progress.Rotated, retrievedProgress.Rotated)
+}
+
+func TestAppendStepTraceKeepsMostRecentTraces(t *testing.T) {
+	instanceID := uuid.NewV4().String()
+	for _, stepName := range []string{"a", "b", "c"} {
+		err := testStore.AppendStepTrace(
+			instanceID,
+			service.StepTrace{StepName: stepName},
+			2,
+			time.Hour,
+		)
+		assert.Nil(t, err)
+	}
+	traces, err := testStore.GetStepTraces(instanceID)
+	assert.Nil(t, err)
+	assert.Len(t, traces, 2)
+	assert.Equal(t, "b", traces[0].StepName)
+	assert.Equal(t, "c", traces[1].StepName)
+	ttl, err := redisClient.TTL(getStepTracesKey(instanceID)).Result()
+	assert.Nil(t, err)
+	assert.True(t, ttl > 0 && ttl <= time.Hour)
+}
+
 func TestGetInstanceKey(t *testing.T) {
 	const rawKey = "foo"
 	expected := fmt.Sprintf("instances:%s", rawKey)
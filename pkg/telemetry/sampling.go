@@ -0,0 +1,163 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+const (
+	defaultReloadInterval = 30 * time.Second
+	// OtherLabelValue is the label value of the series that aggregates those
+	// series of a metric beyond its cardinality limit
+	OtherLabelValue = "other"
+)
+
+// Sampling represents how much of the broker's telemetry is kept. Tracing and
+// metrics are both considerably heavier than logging, so high-volume
+// deployments may want to keep only part of them.
+type Sampling struct {
+	// TraceSampleRate is the fraction, between 0 and 1, of instances whose
+	// steps are traced when every instance's steps may be. Failed steps are
+	// traced regardless.
+	TraceSampleRate float64 `json:"traceSampleRate"`
+	// MaxSeriesPerMetric caps the number of series reported for each metric
+	// whose labels aren't bounded by the catalog. Zero means there is no cap.
+	MaxSeriesPerMetric int `json:"maxSeriesPerMetric"`
+}
+
+// Validate returns an error if the sampling is invalid
+func (s Sampling) Validate() error {
+	if s.TraceSampleRate < 0 || s.TraceSampleRate > 1 ||
+		math.IsNaN(s.TraceSampleRate) {
+		return fmt.Errorf(
+			"invalid trace sample rate %g; it must be between 0 and 1",
+			s.TraceSampleRate,
+		)
+	}
+	if s.MaxSeriesPerMetric < 0 {
+		return fmt.Errorf(
+			"invalid max series per metric %d; it may not be negative",
+			s.MaxSeriesPerMetric,
+		)
+	}
+	return nil
+}
+
+// SamplingFn is the signature for functions that retrieve the current
+// sampling, e.g. from a file that operators may edit while the broker runs
+type SamplingFn func() (Sampling, error)
+
+// Sampler applies the current sampling to the broker's traces and metrics.
+// It is safe for concurrent use.
+type Sampler interface {
+	// IsTraceSampled returns a bool indicating whether the steps of the
+	// instance with the given ID are sampled for tracing. The same instance is
+	// always sampled the same way, so that its traces aren't missing steps.
+	IsTraceSampled(instanceID string) bool
+	// LimitSeries returns, of the given label values of a metric's series, in
+	// order, those whose series are reported individually and those whose
+	// series are aggregated into a single series labelled OtherLabelValue
+	LimitSeries(labelValues []string) (kept []string, aggregated []string)
+	// Run reloads the sampling periodically until the given context is
+	// canceled. Failure to reload it is logged and the sampling is left as it
+	// is until the next interval; it isn't fatal.
+	Run(ctx context.Context)
+}
+
+type sampler struct {
+	getSampling    SamplingFn
+	reloadInterval time.Duration
+	sampling       Sampling
+	mutex          sync.RWMutex
+}
+
+// NewSampler returns a new Sampler that applies the given sampling and, if
+// getSampling is non-nil, replaces it with whatever getSampling returns every
+// reloadInterval
+func NewSampler(
+	sampling Sampling,
+	getSampling SamplingFn,
+	reloadInterval time.Duration,
+) (Sampler, error) {
+	if err := sampling.Validate(); err != nil {
+		return nil, err
+	}
+	if reloadInterval <= 0 {
+		reloadInterval = defaultReloadInterval
+	}
+	return &sampler{
+		getSampling:    getSampling,
+		reloadInterval: reloadInterval,
+		sampling:       sampling,
+	}, nil
+}
+
+func (s *sampler) getCurrentSampling() Sampling {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.sampling
+}
+
+func (s *sampler) IsTraceSampled(instanceID string) bool {
+	rate := s.getCurrentSampling().TraceSampleRate
+	if rate >= 1 {
+		return true
+	}
+	if rate <= 0 {
+		return false
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(instanceID))
+	return float64(hash.Sum32())/float64(math.MaxUint32+1) < rate
+}
+
+func (s *sampler) LimitSeries(
+	labelValues []string,
+) ([]string, []string) {
+	maxSeries := s.getCurrentSampling().MaxSeriesPerMetric
+	if maxSeries <= 0 || len(labelValues) <= maxSeries {
+		return labelValues, nil
+	}
+	// One series is given over to those aggregated
+	kept := maxSeries - 1
+	return labelValues[:kept], labelValues[kept:]
+}
+
+func (s *sampler) Run(ctx context.Context) {
+	if s.getSampling == nil {
+		return
+	}
+	ticker := time.NewTicker(s.reloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			log.Debug("context canceled; telemetry sampling reloader stopping")
+			return
+		}
+		s.reload()
+	}
+}
+
+func (s *sampler) reload() {
+	sampling, err := s.getSampling()
+	if err == nil {
+		err = sampling.Validate()
+	}
+	if err != nil {
+		log.WithField("error", err).Error(
+			"error reloading telemetry sampling; leaving sampling unchanged",
+		)
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.sampling = sampling
+}
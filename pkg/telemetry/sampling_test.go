@@ -0,0 +1,97 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSamplerRejectsInvalidSampling(t *testing.T) {
+	for _, sampling := range []Sampling{
+		{TraceSampleRate: -0.1},
+		{TraceSampleRate: 1.1},
+		{TraceSampleRate: 1, MaxSeriesPerMetric: -1},
+	} {
+		_, err := NewSampler(sampling, nil, 0)
+		assert.NotNil(t, err)
+	}
+}
+
+func TestIsTraceSampled(t *testing.T) {
+	all, err := NewSampler(Sampling{TraceSampleRate: 1}, nil, 0)
+	assert.Nil(t, err)
+	none, err := NewSampler(Sampling{TraceSampleRate: 0}, nil, 0)
+	assert.Nil(t, err)
+	half, err := NewSampler(Sampling{TraceSampleRate: 0.5}, nil, 0)
+	assert.Nil(t, err)
+	var sampled int
+	for i := 0; i < 1000; i++ {
+		instanceID := fmt.Sprintf("instance-%d", i)
+		assert.True(t, all.IsTraceSampled(instanceID))
+		assert.False(t, none.IsTraceSampled(instanceID))
+		if half.IsTraceSampled(instanceID) {
+			sampled++
+			// The same instance is always sampled the same way
+			assert.True(t, half.IsTraceSampled(instanceID))
+		}
+	}
+	assert.InDelta(t, 500, sampled, 100)
+}
+
+func TestLimitSeries(t *testing.T) {
+	labelValues := []string{"a", "b", "c", "d"}
+	unlimited, err := NewSampler(Sampling{TraceSampleRate: 1}, nil, 0)
+	assert.Nil(t, err)
+	kept, aggregated := unlimited.LimitSeries(labelValues)
+	assert.Equal(t, labelValues, kept)
+	assert.Empty(t, aggregated)
+	limited, err := NewSampler(
+		Sampling{TraceSampleRate: 1, MaxSeriesPerMetric: 3},
+		nil,
+		0,
+	)
+	assert.Nil(t, err)
+	kept, aggregated = limited.LimitSeries(labelValues)
+	assert.Equal(t, []string{"a", "b"}, kept)
+	assert.Equal(t, []string{"c", "d"}, aggregated)
+	kept, aggregated = limited.LimitSeries(labelValues[:3])
+	assert.Equal(t, labelValues[:3], kept)
+	assert.Empty(t, aggregated)
+}
+
+func TestSamplerReloadsSampling(t *testing.T) {
+	samplings := make(chan Sampling, 1)
+	s, err := NewSampler(
+		Sampling{TraceSampleRate: 1},
+		func() (Sampling, error) {
+			select {
+			case sampling := <-samplings:
+				return sampling, nil
+			default:
+				return Sampling{}, errors.New("error reading sampling")
+			}
+		},
+		time.Millisecond,
+	)
+	assert.Nil(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+	// Failure to reload leaves the sampling as it was
+	time.Sleep(10 * time.Millisecond)
+	assert.True(t, s.IsTraceSampled("foo"))
+	samplings <- Sampling{TraceSampleRate: 0}
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline) &&
+		s.IsTraceSampled("foo"); {
+		time.Sleep(time.Millisecond)
+	}
+	assert.False(t, s.IsTraceSampled("foo"))
+	// Invalid sampling is also left unapplied
+	samplings <- Sampling{TraceSampleRate: 2}
+	time.Sleep(10 * time.Millisecond)
+	assert.False(t, s.IsTraceSampled("foo"))
+}
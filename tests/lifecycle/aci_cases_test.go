@@ -12,7 +12,7 @@ func getACICases(
 	armDeployer arm.Deployer,
 	resourceGroup string,
 ) ([]serviceLifecycleTestCase, error) {
-	aciManager, err := ac.NewManager()
+	aciManager, err := ac.NewManager("")
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,44 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"os"
+
+	ar "github.com/Azure/open-service-broker-azure/pkg/azure/arc"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/services/arc"
+)
+
+func getArcCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	// Binding assigns a role to an existing principal, whose object ID must be
+	// supplied. Only the server plan is tested, since a Kubernetes cluster can't
+	// be bound to until the cluster itself has been connected.
+	principalID := os.Getenv("TEST_ARC_PRINCIPAL_ID")
+	if principalID == "" {
+		return nil, nil
+	}
+
+	arcManager, err := ar.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module:    arc.New(armDeployer, arcManager),
+			serviceID: "57f88747-5e4f-48e9-92a5-fa299336c52f",
+			planID:    "bfb51b44-b864-4050-a8bb-dff7ea1000ec",
+			location:  "eastus",
+			provisioningParameters: &arc.ProvisioningParameters{
+				Name: "osba-test-server",
+			},
+			bindingParameters: &arc.BindingParameters{
+				PrincipalID: principalID,
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,43 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	aa "github.com/Azure/open-service-broker-azure/pkg/azure/automation"
+	"github.com/Azure/open-service-broker-azure/pkg/services/automation"
+)
+
+func getAutomationCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	automationManager, err := aa.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module:    automation.New(armDeployer, automationManager),
+			serviceID: "3429d285-86b4-44c0-a494-f7736c226921",
+			planID:    "05c0c4f6-f6e7-4f3d-b883-2d1ce4a59622",
+			location:  "eastus",
+			provisioningParameters: &automation.ProvisioningParameters{
+				Runbooks: []automation.Runbook{
+					{
+						Name: "Hello-World",
+						Type: "PowerShell",
+						ContentURI: "https://raw.githubusercontent.com/Azure/" +
+							"azure-quickstart-templates/master/quickstarts/" +
+							"microsoft.automation/101-automation/scripts/" +
+							"AzureAutomationTutorial.ps1",
+					},
+				},
+			},
+			bindingParameters: &automation.BindingParameters{
+				WebhookExpiryDays: 1,
+			},
+		},
+	}, nil
+}
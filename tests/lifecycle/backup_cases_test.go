@@ -0,0 +1,47 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"os"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	bk "github.com/Azure/open-service-broker-azure/pkg/azure/backup"
+	"github.com/Azure/open-service-broker-azure/pkg/services/backup"
+)
+
+func getBackupCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	// Provisioning enrolls an existing virtual machine in backup protection,
+	// whose ID must be supplied. The machine must be in eastus.
+	resourceID := os.Getenv("TEST_BACKUP_VM_RESOURCE_ID")
+	if resourceID == "" {
+		return nil, nil
+	}
+
+	backupManager, err := bk.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module:    backup.New(armDeployer, backupManager),
+			serviceID: "19c9fbf1-f31a-42ba-b15e-defa4c323fdf",
+			planID:    "91ff692f-b6fe-4d30-8eef-43bdb1a07728",
+			location:  "eastus",
+			provisioningParameters: &backup.ProvisioningParameters{
+				ResourceID: resourceID,
+				Policy: &backup.Policy{
+					Schedule: &backup.Schedule{
+						Frequency: "Weekly",
+						Days:      []string{"Sunday"},
+					},
+				},
+			},
+			bindingParameters: &backup.BindingParameters{},
+		},
+	}, nil
+}
@@ -0,0 +1,102 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	ba "github.com/Azure/open-service-broker-azure/pkg/azure/bastion"
+	"github.com/Azure/open-service-broker-azure/pkg/services/bastion"
+	uuid "github.com/satori/go.uuid"
+)
+
+const bastionTestLocation = "southcentralus"
+
+// nolint: lll
+var bastionTestVirtualNetworkARMTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "tags": {
+      "type": "object"
+    }
+  },
+  "resources": [
+    {
+      "apiVersion": "2021-05-01",
+      "name": "bastion-test-vnet",
+      "type": "Microsoft.Network/virtualNetworks",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "properties": {
+        "addressSpace": {
+          "addressPrefixes": [
+            "10.0.0.0/16"
+          ]
+        },
+        "subnets": [
+          {
+            "name": "AzureBastionSubnet",
+            "properties": {
+              "addressPrefix": "10.0.1.0/26"
+            }
+          }
+        ]
+      }
+    }
+  ],
+  "outputs": {
+    "subnetId": {
+      "type": "string",
+      "value": "[resourceId('Microsoft.Network/virtualNetworks/subnets', 'bastion-test-vnet', 'AzureBastionSubnet')]"
+    }
+  }
+}
+`)
+
+func getBastionCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	bastionManager, err := ba.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	// Bastion hosts are deployed into an existing virtual network, so one is
+	// created for them in the test resource group
+	outputs, err := armDeployer.Deploy(
+		uuid.NewV4().String(),
+		resourceGroup,
+		bastionTestLocation,
+		bastionTestVirtualNetworkARMTemplateBytes,
+		nil,
+		map[string]interface{}{},
+		map[string]string{},
+	)
+	if err != nil {
+		return nil, err
+	}
+	subnetID, ok := outputs["subnetId"].(string)
+	if !ok {
+		return nil, errors.New("error retrieving subnet id from deployment")
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module:    bastion.New(armDeployer, bastionManager),
+			serviceID: "b3e81f2a-6c4d-4a97-9e25-1d7f0c8b5a36",
+			planID:    "6f2a9c04-8e1b-4d53-b7a6-3c5e9d1f0b82",
+			location:  bastionTestLocation,
+			provisioningParameters: &bastion.ProvisioningParameters{
+				SubnetID: subnetID,
+			},
+			bindingParameters: &bastion.BindingParameters{},
+		},
+	}, nil
+}
@@ -0,0 +1,49 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"os"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	ch "github.com/Azure/open-service-broker-azure/pkg/azure/chaosstudio"
+	"github.com/Azure/open-service-broker-azure/pkg/services/chaosstudio"
+)
+
+func getChaosStudioCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	// Experiments target an existing virtual machine that has already been
+	// enabled for Chaos Studio, whose resource ID must be supplied
+	targetResourceID := os.Getenv("TEST_CHAOS_STUDIO_TARGET_RESOURCE_ID")
+	if targetResourceID == "" {
+		return nil, nil
+	}
+
+	chaosStudioManager, err := ch.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module:    chaosstudio.New(armDeployer, chaosStudioManager),
+			serviceID: "23f1fc1b-4ade-4853-a5c2-40284a2389d0",
+			planID:    "6d9c88da-72b8-4d61-9822-30d15c29870b",
+			location:  "eastus",
+			provisioningParameters: &chaosstudio.ProvisioningParameters{
+				TargetType:        "Microsoft-VirtualMachine",
+				TargetResourceIDs: []string{targetResourceID},
+				Fault: &chaosstudio.Fault{
+					URN:      "urn:csci:microsoft:virtualMachine:shutdown/1.0",
+					Duration: "PT10M",
+					Parameters: map[string]string{
+						"abruptShutdown": "false",
+					},
+				},
+			},
+			bindingParameters: &chaosstudio.BindingParameters{},
+		},
+	}, nil
+}
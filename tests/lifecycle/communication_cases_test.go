@@ -0,0 +1,32 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	ac "github.com/Azure/open-service-broker-azure/pkg/azure/communication"
+	"github.com/Azure/open-service-broker-azure/pkg/services/communication"
+)
+
+func getCommunicationCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	communicationManager, err := ac.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module:    communication.New(armDeployer, communicationManager),
+			serviceID: "e7c2a5b1-8d3f-4c62-9a7e-3f1b6d4c8e21",
+			planID:    "5a9f3c17-2b4e-4d8a-b6c1-7e0d2f9a4b53",
+			location:  "southcentralus",
+			provisioningParameters: &communication.ProvisioningParameters{
+				DataLocation: "United States",
+			},
+			bindingParameters: &communication.BindingParameters{},
+		},
+	}, nil
+}
@@ -0,0 +1,48 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"os"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	cl "github.com/Azure/open-service-broker-azure/pkg/azure/confidentialledger"
+	"github.com/Azure/open-service-broker-azure/pkg/services/confidentialledger"
+)
+
+func getConfidentialLedgerCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	// A ledger must have an administrator, and binding grants a role to another
+	// existing principal, so the object IDs of both must be supplied
+	administratorObjectID :=
+		os.Getenv("TEST_CONFIDENTIAL_LEDGER_ADMINISTRATOR_OBJECT_ID")
+	principalObjectID := os.Getenv("TEST_CONFIDENTIAL_LEDGER_PRINCIPAL_OBJECT_ID")
+	if administratorObjectID == "" || principalObjectID == "" {
+		return nil, nil
+	}
+
+	ledgerManager, err := cl.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module:    confidentialledger.New(armDeployer, ledgerManager),
+			serviceID: "0b8f6c1e-5d27-4a93-9e1f-3c6a8d2b7e45",
+			planID:    "d4a9e3f2-7b16-4c58-a0e2-9f3d5b8c1a67",
+			location:  "eastus",
+			provisioningParameters: &confidentialledger.ProvisioningParameters{
+				Administrators: []confidentialledger.Administrator{
+					{PrincipalID: administratorObjectID},
+				},
+			},
+			bindingParameters: &confidentialledger.BindingParameters{
+				PrincipalID: principalObjectID,
+				Role:        "Reader",
+			},
+		},
+	}, nil
+}
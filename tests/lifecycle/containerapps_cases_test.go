@@ -0,0 +1,45 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	ca "github.com/Azure/open-service-broker-azure/pkg/azure/containerapps"
+	"github.com/Azure/open-service-broker-azure/pkg/services/containerapps"
+)
+
+func getContainerAppsCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	containerAppsManager, err := ca.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module:    containerapps.New(armDeployer, containerAppsManager),
+			serviceID: "c70c9e9d-cf59-40d3-b93e-a24d9a909fd8",
+			planID:    "43e8ba7d-8a8e-4813-8c43-bc408058733b",
+			location:  "eastus",
+			provisioningParameters: &containerapps.ProvisioningParameters{
+				Image: "mcr.microsoft.com/azuredocs/containerapps-helloworld:latest",
+				Ingress: &containerapps.Ingress{
+					External:   true,
+					TargetPort: 80,
+				},
+				EnvironmentVariables: []containerapps.EnvironmentVariable{
+					{
+						Name:      "GREETING",
+						SecretRef: "greeting",
+					},
+				},
+				Secrets: map[string]string{
+					"greeting": "hello",
+				},
+			},
+			bindingParameters: &containerapps.BindingParameters{},
+		},
+	}, nil
+}
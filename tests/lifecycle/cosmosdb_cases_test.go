@@ -21,7 +21,7 @@ func getCosmosdbCases(
 	armDeployer arm.Deployer,
 	resourceGroup string,
 ) ([]serviceLifecycleTestCase, error) {
-	cosmosdbManager, err := cd.NewManager()
+	cosmosdbManager, err := cd.NewManager("")
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,43 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"os"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	db "github.com/Azure/open-service-broker-azure/pkg/azure/devbox"
+	"github.com/Azure/open-service-broker-azure/pkg/services/devbox"
+)
+
+func getDevBoxCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	// Binding creates a dev box on behalf of a user, whose object ID can't be
+	// derived from the broker's configuration
+	userObjectID := os.Getenv("TEST_DEV_BOX_USER_OBJECT_ID")
+	if userObjectID == "" {
+		return nil, nil
+	}
+
+	devBoxManager, err := db.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module:    devbox.New(armDeployer, devBoxManager),
+			serviceID: "8b1f4e62-93d7-4c0a-b5e8-2f6a91d3c74e",
+			planID:    "d5c7a0e3-1b84-4f29-8e6d-7a3c52f9b018",
+			location:  "eastus",
+			provisioningParameters: &devbox.ProvisioningParameters{
+				MaxDevBoxesPerUser: 1,
+			},
+			bindingParameters: &devbox.BindingParameters{
+				UserID: userObjectID,
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,41 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	dl "github.com/Azure/open-service-broker-azure/pkg/azure/devtestlabs"
+	"github.com/Azure/open-service-broker-azure/pkg/services/devtestlabs"
+)
+
+func getDevTestLabsCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	devTestLabsManager, err := dl.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module:    devtestlabs.New(armDeployer, devTestLabsManager),
+			serviceID: "4c7a2e9d-3b58-4f16-a0d2-8e5b1c6f7a34",
+			planID:    "9e1d6b3a-7f24-4c85-b2e9-5a0c8d4f1e67",
+			location:  "southcentralus",
+			provisioningParameters: &devtestlabs.ProvisioningParameters{
+				AllowedVMSizes:   []string{"Standard_DS1_v2"},
+				AutoShutdownTime: "18:30",
+				VM: &devtestlabs.VMParameters{
+					Image: &devtestlabs.ImageParameters{
+						Publisher: "Canonical",
+						Offer:     "UbuntuServer",
+						SKU:       "16.04-LTS",
+						OSType:    "Linux",
+					},
+				},
+			},
+			bindingParameters: &devtestlabs.BindingParameters{},
+		},
+	}, nil
+}
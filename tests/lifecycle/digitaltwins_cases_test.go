@@ -0,0 +1,44 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"os"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	dt "github.com/Azure/open-service-broker-azure/pkg/azure/digitaltwins"
+	"github.com/Azure/open-service-broker-azure/pkg/services/digitaltwins"
+)
+
+func getDigitalTwinsCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	// Binding assigns a role to an existing principal, whose object ID must be
+	// supplied
+	principalObjectID := os.Getenv("TEST_DIGITAL_TWINS_PRINCIPAL_OBJECT_ID")
+	if principalObjectID == "" {
+		return nil, nil
+	}
+
+	digitalTwinsManager, err := dt.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module:    digitaltwins.New(armDeployer, digitalTwinsManager),
+			serviceID: "6b0e8f3a-92d4-4c17-a5e6-3f1d7c98b2a4",
+			planID:    "e2c47a19-5d83-4f6b-9b0e-7a4d1c3f8e65",
+			location:  "eastus",
+			provisioningParameters: &digitaltwins.ProvisioningParameters{
+				IdentityType: "SystemAssigned",
+			},
+			bindingParameters: &digitaltwins.BindingParameters{
+				PrincipalID: principalObjectID,
+				Role:        "Azure Digital Twins Data Reader",
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,35 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	dm "github.com/Azure/open-service-broker-azure/pkg/azure/dms"
+	"github.com/Azure/open-service-broker-azure/pkg/services/dms"
+)
+
+func getDMSCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	dmsManager, err := dm.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module:    dms.New(armDeployer, dmsManager),
+			serviceID: "0c7f2d64-3b1a-4e59-8d2c-6a9e1f5b7c43",
+			planID:    "8e4b1a95-7d2c-4f36-b0e8-2c5d9a3f6b17",
+			location:  "southcentralus",
+			provisioningParameters: &dms.ProvisioningParameters{
+				Project: &dms.ProjectParameters{
+					SourcePlatform: "SQL",
+					TargetPlatform: "SQLDB",
+				},
+			},
+			bindingParameters: &dms.BindingParameters{},
+		},
+	}, nil
+}
@@ -0,0 +1,43 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	es "github.com/Azure/open-service-broker-azure/pkg/azure/elasticsan"
+	"github.com/Azure/open-service-broker-azure/pkg/services/elasticsan"
+)
+
+func getElasticSANCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	elasticSANManager, err := es.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module:    elasticsan.New(armDeployer, elasticSANManager),
+			serviceID: "bef43128-d580-4141-8615-ce6c853a6345",
+			planID:    "e7187f14-f99a-45a8-ab1e-2e33d403e74c",
+			location:  "eastus",
+			provisioningParameters: &elasticsan.ProvisioningParameters{
+				BaseSizeTiB: 1,
+				VolumeGroups: []elasticsan.VolumeGroup{
+					{
+						Name: "test-group",
+						Volumes: []elasticsan.Volume{
+							{
+								Name:    "test-volume",
+								SizeGiB: 10,
+							},
+						},
+					},
+				},
+			},
+			bindingParameters: &elasticsan.BindingParameters{},
+		},
+	}, nil
+}
@@ -12,7 +12,7 @@ func getEventhubCases(
 	armDeployer arm.Deployer,
 	resourceGroup string,
 ) ([]serviceLifecycleTestCase, error) {
-	eventHubManager, err := eh.NewManager()
+	eventHubManager, err := eh.NewManager("")
 	if err != nil {
 		return nil, err
 	}
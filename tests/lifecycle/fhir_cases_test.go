@@ -0,0 +1,32 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	fh "github.com/Azure/open-service-broker-azure/pkg/azure/fhir"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fhir"
+)
+
+func getFHIRCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	fhirManager, err := fh.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module:    fhir.New(armDeployer, fhirManager),
+			serviceID: "3d419172-f23d-4c1b-8dc6-066aaae3ae63",
+			planID:    "9ef5a33f-e6fc-47cb-b33a-4e0fd60ae54d",
+			location:  "eastus",
+			provisioningParameters: &fhir.ProvisioningParameters{
+				Kind: "fhir-R4",
+			},
+			bindingParameters: &fhir.BindingParameters{},
+		},
+	}, nil
+}
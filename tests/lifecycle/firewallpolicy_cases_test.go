@@ -0,0 +1,48 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	fp "github.com/Azure/open-service-broker-azure/pkg/azure/firewallpolicy"
+	"github.com/Azure/open-service-broker-azure/pkg/services/firewallpolicy"
+)
+
+func getFirewallPolicyCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	firewallPolicyManager, err := fp.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module:    firewallpolicy.New(armDeployer, firewallPolicyManager),
+			serviceID: "b49d9ed4-0104-49fb-98d5-4952958799de",
+			planID:    "32662a0b-1da6-4663-837f-21fa82b27fab",
+			location:  "eastus",
+			provisioningParameters: &firewallpolicy.ProvisioningParameters{
+				RuleCollections: []firewallpolicy.RuleCollection{
+					{
+						Name:     "allow-dns",
+						Priority: 100,
+						Type:     "network",
+						Action:   "Allow",
+						Rules: []firewallpolicy.Rule{
+							{
+								Name:                 "dns",
+								SourceAddresses:      []string{"10.0.0.0/16"},
+								DestinationAddresses: []string{"*"},
+								DestinationPorts:     []string{"53"},
+								Protocols:            []string{"UDP"},
+							},
+						},
+					},
+				},
+			},
+			bindingParameters: &firewallpolicy.BindingParameters{},
+		},
+	}, nil
+}
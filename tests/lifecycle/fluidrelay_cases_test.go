@@ -0,0 +1,32 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	fr "github.com/Azure/open-service-broker-azure/pkg/azure/fluidrelay"
+	"github.com/Azure/open-service-broker-azure/pkg/services/fluidrelay"
+)
+
+func getFluidRelayCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	fluidRelayManager, err := fr.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module:    fluidrelay.New(armDeployer, fluidRelayManager),
+			serviceID: "45ced992-d43b-4be8-bb50-56e0910c44c7",
+			planID:    "e137b7e8-4d8a-4d99-a615-b569a8e0d351",
+			location:  "eastus",
+			provisioningParameters: &fluidrelay.ProvisioningParameters{
+				StorageSKU: "basic",
+			},
+			bindingParameters: &fluidrelay.BindingParameters{},
+		},
+	}, nil
+}
@@ -0,0 +1,48 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"os"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	dps "github.com/Azure/open-service-broker-azure/pkg/azure/iotdps"
+	"github.com/Azure/open-service-broker-azure/pkg/services/iotdps"
+)
+
+func getIoTDPSCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	// The DPS is linked to an existing IoT hub, whose resource ID must be
+	// supplied
+	iotHubID := os.Getenv("TEST_IOTDPS_IOT_HUB_ID")
+	if iotHubID == "" {
+		return nil, nil
+	}
+
+	dpsManager, err := dps.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module:    iotdps.New(armDeployer, dpsManager),
+			serviceID: "ee64c01a-f7f9-4587-aea7-827445139817",
+			planID:    "6eba6087-fbd9-49bd-97b1-73aaa689497c",
+			location:  "eastus",
+			provisioningParameters: &iotdps.ProvisioningParameters{
+				IoTHubs: []iotdps.IoTHubLink{
+					{ResourceID: iotHubID},
+				},
+				EnrollmentGroups: []iotdps.EnrollmentGroup{
+					{ID: "lifecycle-test"},
+				},
+			},
+			bindingParameters: &iotdps.BindingParameters{
+				Rights: []string{"EnrollmentRead", "EnrollmentWrite"},
+			},
+		},
+	}, nil
+}
@@ -12,7 +12,7 @@ func getKeyvaultCases(
 	armDeployer arm.Deployer,
 	resourceGroup string,
 ) ([]serviceLifecycleTestCase, error) {
-	keyvaultManager, err := kv.NewManager()
+	keyvaultManager, err := kv.NewManager("")
 	if err != nil {
 		return nil, err
 	}
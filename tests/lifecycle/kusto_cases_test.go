@@ -0,0 +1,69 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	az "github.com/Azure/open-service-broker-azure/pkg/azure"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	as "github.com/Azure/open-service-broker-azure/pkg/azure/autoscale"
+	ak "github.com/Azure/open-service-broker-azure/pkg/azure/kusto"
+	"github.com/Azure/open-service-broker-azure/pkg/services/kusto"
+)
+
+func getKustoCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	azureConfig, err := az.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	kustoManager, err := ak.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+	autoscaleManager, err := as.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module: kusto.New(
+				armDeployer,
+				kustoManager,
+				autoscaleManager,
+			),
+			serviceID: "b3f0c6d2-7a41-4e8b-9c25-1d6e8f4a2b97",
+			planID:    "0c8d2e7f-5b16-4a93-8e4d-6f2a9b1c3d58",
+			location:  "southcentralus",
+			provisioningParameters: &kusto.ProvisioningParameters{
+				Autoscale: &as.Parameters{
+					TimeZone: "Central Standard Time",
+					Profiles: []as.ProfileParameters{
+						{
+							Name:      "business-hours",
+							Days:      []string{"Monday", "Tuesday", "Wednesday"},
+							StartTime: "08:00",
+							Minimum:   2,
+							Maximum:   4,
+							Default:   4,
+						},
+						{
+							Name:      "off-hours",
+							Days:      []string{"Monday", "Tuesday", "Wednesday"},
+							StartTime: "18:00",
+							Minimum:   2,
+							Maximum:   2,
+							Default:   2,
+						},
+					},
+				},
+			},
+			bindingParameters: &kusto.BindingParameters{
+				// The lifecycle tests' own service principal
+				PrincipalID: azureConfig.ClientID,
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,105 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	lb "github.com/Azure/open-service-broker-azure/pkg/azure/loadbalancer"
+	"github.com/Azure/open-service-broker-azure/pkg/services/loadbalancer"
+	uuid "github.com/satori/go.uuid"
+)
+
+const loadBalancerTestLocation = "southcentralus"
+
+// nolint: lll
+var loadBalancerTestVirtualNetworkARMTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "tags": {
+      "type": "object"
+    }
+  },
+  "resources": [
+    {
+      "apiVersion": "2021-05-01",
+      "name": "lb-test-vnet",
+      "type": "Microsoft.Network/virtualNetworks",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "properties": {
+        "addressSpace": {
+          "addressPrefixes": [
+            "10.0.0.0/16"
+          ]
+        },
+        "subnets": [
+          {
+            "name": "default",
+            "properties": {
+              "addressPrefix": "10.0.1.0/24"
+            }
+          }
+        ]
+      }
+    }
+  ],
+  "outputs": {
+    "subnetId": {
+      "type": "string",
+      "value": "[resourceId('Microsoft.Network/virtualNetworks/subnets', 'lb-test-vnet', 'default')]"
+    }
+  }
+}
+`)
+
+func getLoadBalancerCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	loadBalancerManager, err := lb.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	// Load balancers are deployed into an existing virtual network, so one is
+	// created for them in the test resource group
+	outputs, err := armDeployer.Deploy(
+		uuid.NewV4().String(),
+		resourceGroup,
+		loadBalancerTestLocation,
+		loadBalancerTestVirtualNetworkARMTemplateBytes,
+		nil,
+		map[string]interface{}{},
+		map[string]string{},
+	)
+	if err != nil {
+		return nil, err
+	}
+	subnetID, ok := outputs["subnetId"].(string)
+	if !ok {
+		return nil, errors.New("error retrieving subnet id from deployment")
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module:    loadbalancer.New(armDeployer, loadBalancerManager),
+			serviceID: "d3a6f1c8-27b4-4e95-9c0d-5b8e7a2f4c61",
+			planID:    "8c4e2b7a-91d3-4f6e-a058-3e7d9b1c2a46",
+			location:  loadBalancerTestLocation,
+			provisioningParameters: &loadbalancer.ProvisioningParameters{
+				SubnetID: subnetID,
+			},
+			bindingParameters: &loadbalancer.BindingParameters{
+				IPAddress: "10.0.1.10",
+				Port:      80,
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,45 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"os"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	lt "github.com/Azure/open-service-broker-azure/pkg/azure/loadtesting"
+	"github.com/Azure/open-service-broker-azure/pkg/services/loadtesting"
+)
+
+func getLoadTestingCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	// Binding assigns a role to an existing principal, whose object ID must be
+	// supplied
+	principalObjectID := os.Getenv("TEST_LOAD_TESTING_PRINCIPAL_OBJECT_ID")
+	if principalObjectID == "" {
+		return nil, nil
+	}
+
+	loadTestingManager, err := lt.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module:    loadtesting.New(armDeployer, loadTestingManager),
+			serviceID: "d1a7e4c9-3b62-4f58-8e0a-9c5b2f7d6e14",
+			planID:    "7f2c9b48-e51d-4a36-b0c7-2d8e6a1f4b93",
+			location:  "eastus",
+			provisioningParameters: &loadtesting.ProvisioningParameters{
+				Description:  "Lifecycle test",
+				IdentityType: "SystemAssigned",
+			},
+			bindingParameters: &loadtesting.BindingParameters{
+				PrincipalID: principalObjectID,
+				Role:        "Load Test Reader",
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,34 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	mc "github.com/Azure/open-service-broker-azure/pkg/azure/maintenance"
+	"github.com/Azure/open-service-broker-azure/pkg/services/maintenance"
+)
+
+func getMaintenanceCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	maintenanceManager, err := mc.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module:    maintenance.New(armDeployer, maintenanceManager),
+			serviceID: "7c4e2a90-3b6f-4d18-a5e1-9f0b2c8d6e43",
+			planID:    "e1b85f37-6a2c-4e09-8d4b-3c7f9a0e5b12",
+			location:  "eastus",
+			provisioningParameters: &maintenance.ProvisioningParameters{
+				Schedule: &maintenance.Schedule{
+					StartDateTime: "2030-01-01 02:00",
+					RecurEvery:    "Month Second Tuesday",
+				},
+			},
+		},
+	}, nil
+}
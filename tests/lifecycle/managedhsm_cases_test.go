@@ -0,0 +1,90 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	mh "github.com/Azure/open-service-broker-azure/pkg/azure/managedhsm"
+	"github.com/Azure/open-service-broker-azure/pkg/services/managedhsm"
+)
+
+func getManagedHSMCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	// Binding assigns roles within the HSM, which only its administrators may
+	// do, so the broker's own service principal must be the administrator. Its
+	// object ID can't be derived from the broker's configuration.
+	administratorObjectID := os.Getenv("TEST_MANAGED_HSM_ADMINISTRATOR_OBJECT_ID")
+	if administratorObjectID == "" {
+		return nil, nil
+	}
+
+	managedHSMManager, err := mh.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	certificates := make([]string, 3)
+	for i := range certificates {
+		if certificates[i], err = getSecurityDomainCertificate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module:    managedhsm.New(armDeployer, managedHSMManager),
+			serviceID: "4d9e2b71-83c5-4a0f-9e6d-5b1c7a2f8e34",
+			planID:    "a7c31f58-2e96-4b0d-8f14-6d3e9b5c0a27",
+			location:  "southcentralus",
+			provisioningParameters: &managedhsm.ProvisioningParameters{
+				Administrators:             []string{administratorObjectID},
+				SoftDeleteRetentionDays:    7,
+				PurgeOnDeprovision:         true,
+				SecurityDomainCertificates: certificates,
+			},
+			bindingParameters: &managedhsm.BindingParameters{
+				PrincipalID: administratorObjectID,
+			},
+		},
+	}, nil
+}
+
+// getSecurityDomainCertificate returns a new, PEM-encoded, self-signed
+// certificate that an HSM's security domain can be encrypted to. The private
+// key is discarded because the tests never recover the security domain.
+func getSecurityDomainCertificate() (string, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return "", err
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "osba-lifecycle-test"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(
+		rand.Reader,
+		template,
+		template,
+		&key.PublicKey,
+		key,
+	)
+	if err != nil {
+		return "", err
+	}
+	return string(
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	), nil
+}
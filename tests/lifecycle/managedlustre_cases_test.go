@@ -0,0 +1,102 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	ml "github.com/Azure/open-service-broker-azure/pkg/azure/managedlustre"
+	"github.com/Azure/open-service-broker-azure/pkg/services/managedlustre"
+	uuid "github.com/satori/go.uuid"
+)
+
+const managedLustreTestLocation = "eastus"
+
+// nolint: lll
+var managedLustreTestVirtualNetworkARMTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "tags": {
+      "type": "object"
+    }
+  },
+  "resources": [
+    {
+      "apiVersion": "2021-05-01",
+      "name": "lustre-test-vnet",
+      "type": "Microsoft.Network/virtualNetworks",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "properties": {
+        "addressSpace": {
+          "addressPrefixes": [
+            "10.0.0.0/16"
+          ]
+        },
+        "subnets": [
+          {
+            "name": "lustre",
+            "properties": {
+              "addressPrefix": "10.0.1.0/24"
+            }
+          }
+        ]
+      }
+    }
+  ],
+  "outputs": {
+    "subnetId": {
+      "type": "string",
+      "value": "[resourceId('Microsoft.Network/virtualNetworks/subnets', 'lustre-test-vnet', 'lustre')]"
+    }
+  }
+}
+`)
+
+func getManagedLustreCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	managedLustreManager, err := ml.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	// File systems are deployed into an existing virtual network, so one is
+	// created for them in the test resource group
+	outputs, err := armDeployer.Deploy(
+		uuid.NewV4().String(),
+		resourceGroup,
+		managedLustreTestLocation,
+		managedLustreTestVirtualNetworkARMTemplateBytes,
+		nil,
+		map[string]interface{}{},
+		map[string]string{},
+	)
+	if err != nil {
+		return nil, err
+	}
+	subnetID, ok := outputs["subnetId"].(string)
+	if !ok {
+		return nil, errors.New("error retrieving subnet id from deployment")
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module:    managedlustre.New(armDeployer, managedLustreManager),
+			serviceID: "a61df735-9596-4a99-89d2-b90a87f45407",
+			planID:    "40ebd6f4-4561-4338-a5fd-f5a8ad9c0264",
+			location:  managedLustreTestLocation,
+			provisioningParameters: &managedlustre.ProvisioningParameters{
+				SubnetID: subnetID,
+			},
+			bindingParameters: &managedlustre.BindingParameters{},
+		},
+	}, nil
+}
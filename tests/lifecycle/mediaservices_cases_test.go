@@ -0,0 +1,51 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"os"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	ms "github.com/Azure/open-service-broker-azure/pkg/azure/mediaservices"
+	sa "github.com/Azure/open-service-broker-azure/pkg/azure/storage"
+	"github.com/Azure/open-service-broker-azure/pkg/services/mediaservices"
+)
+
+func getMediaServicesCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	// Binding assigns a role to an existing principal, whose object ID must be
+	// supplied
+	principalObjectID := os.Getenv("TEST_MEDIA_SERVICES_PRINCIPAL_OBJECT_ID")
+	if principalObjectID == "" {
+		return nil, nil
+	}
+
+	mediaServicesManager, err := ms.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+	storageManager, err := sa.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module: mediaservices.New(
+				armDeployer,
+				mediaServicesManager,
+				storageManager,
+			),
+			serviceID:              "c69861f3-ff06-4ea1-ab26-aa3d48c589f1",
+			planID:                 "adfe1708-5bb0-4276-92a1-5fe8e5f2d082",
+			location:               "eastus",
+			provisioningParameters: &mediaservices.ProvisioningParameters{},
+			bindingParameters: &mediaservices.BindingParameters{
+				PrincipalID: principalObjectID,
+				Role:        "Media Services Media Operator",
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,32 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	mv "github.com/Azure/open-service-broker-azure/pkg/azure/mongovcore"
+	"github.com/Azure/open-service-broker-azure/pkg/services/mongovcore"
+)
+
+func getMongoVCoreCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	clusterManager, err := mv.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module:    mongovcore.New(armDeployer, clusterManager),
+			serviceID: "b2e84c17-6d3a-4f59-9e71-0c5a8d3f2b64",
+			planID:    "5f0d9a62-c84b-4e13-a7b6-39e2f1c8d705",
+			location:  "eastus",
+			provisioningParameters: &mongovcore.ProvisioningParameters{
+				Tier: "M10",
+			},
+			bindingParameters: &mongovcore.BindingParameters{},
+		},
+	}, nil
+}
@@ -0,0 +1,42 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"os"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	mw "github.com/Azure/open-service-broker-azure/pkg/azure/monitorworkspace"
+	"github.com/Azure/open-service-broker-azure/pkg/services/monitorworkspace"
+)
+
+func getMonitorWorkspaceCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	// Binding assigns a role to an existing principal, whose object ID must be
+	// supplied
+	principalObjectID := os.Getenv("TEST_MONITOR_WORKSPACE_PRINCIPAL_OBJECT_ID")
+	if principalObjectID == "" {
+		return nil, nil
+	}
+
+	workspaceManager, err := mw.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module:    monitorworkspace.New(armDeployer, workspaceManager),
+			serviceID: "5c9e2f71-8a4d-4b36-a1e5-3f7b0d9c6a28",
+			planID:    "e83b6d0a-2f19-4c57-9b4e-7a1d5c8f3e60",
+			location:  "eastus",
+			provisioningParameters: &monitorworkspace.ProvisioningParameters{},
+			bindingParameters: &monitorworkspace.BindingParameters{
+				PrincipalID: principalObjectID,
+				Role:        "Monitoring Metrics Publisher",
+			},
+		},
+	}, nil
+}
@@ -18,7 +18,7 @@ func getMssqlCases(
 	armDeployer arm.Deployer,
 	resourceGroup string,
 ) ([]serviceLifecycleTestCase, error) {
-	msSQLManager, err := ss.NewManager()
+	msSQLManager, err := ss.NewManager("")
 	if err != nil {
 		return nil, err
 	}
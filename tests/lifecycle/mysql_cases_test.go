@@ -12,7 +12,7 @@ func getMysqlCases(
 	armDeployer arm.Deployer,
 	resourceGroup string,
 ) ([]serviceLifecycleTestCase, error) {
-	mySQLManager, err := mg.NewManager()
+	mySQLManager, err := mg.NewManager("")
 	if err != nil {
 		return nil, err
 	}
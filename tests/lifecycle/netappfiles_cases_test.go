@@ -0,0 +1,111 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"errors"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	anf "github.com/Azure/open-service-broker-azure/pkg/azure/netappfiles"
+	"github.com/Azure/open-service-broker-azure/pkg/services/netappfiles"
+	uuid "github.com/satori/go.uuid"
+)
+
+const netAppFilesTestLocation = "eastus"
+
+// nolint: lll
+var netAppFilesTestVirtualNetworkARMTemplateBytes = []byte(`
+{
+  "$schema": "http://schema.management.azure.com/schemas/2015-01-01/deploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "location": {
+      "type": "string"
+    },
+    "tags": {
+      "type": "object"
+    }
+  },
+  "resources": [
+    {
+      "apiVersion": "2021-05-01",
+      "name": "anf-test-vnet",
+      "type": "Microsoft.Network/virtualNetworks",
+      "location": "[parameters('location')]",
+      "tags": "[parameters('tags')]",
+      "properties": {
+        "addressSpace": {
+          "addressPrefixes": [
+            "10.0.0.0/16"
+          ]
+        },
+        "subnets": [
+          {
+            "name": "anf",
+            "properties": {
+              "addressPrefix": "10.0.1.0/24",
+              "delegations": [
+                {
+                  "name": "netapp",
+                  "properties": {
+                    "serviceName": "Microsoft.NetApp/volumes"
+                  }
+                }
+              ]
+            }
+          }
+        ]
+      }
+    }
+  ],
+  "outputs": {
+    "subnetId": {
+      "type": "string",
+      "value": "[resourceId('Microsoft.Network/virtualNetworks/subnets', 'anf-test-vnet', 'anf')]"
+    }
+  }
+}
+`)
+
+func getNetAppFilesCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	netAppFilesManager, err := anf.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	// Volumes are placed in a subnet delegated to Azure NetApp Files, so one
+	// is created for them in the test resource group
+	outputs, err := armDeployer.Deploy(
+		uuid.NewV4().String(),
+		resourceGroup,
+		netAppFilesTestLocation,
+		netAppFilesTestVirtualNetworkARMTemplateBytes,
+		nil,
+		map[string]interface{}{},
+		map[string]string{},
+	)
+	if err != nil {
+		return nil, err
+	}
+	subnetID, ok := outputs["subnetId"].(string)
+	if !ok {
+		return nil, errors.New("error retrieving subnet id from deployment")
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module:    netappfiles.New(armDeployer, netAppFilesManager),
+			serviceID: "4b99f831-3276-403a-9618-1aef9cab364b",
+			planID:    "79e96bae-4bad-4f4e-b877-f27e3340c86a",
+			location:  netAppFilesTestLocation,
+			provisioningParameters: &netappfiles.ProvisioningParameters{
+				SubnetID:     subnetID,
+				ServiceLevel: "Standard",
+			},
+			bindingParameters: &netappfiles.BindingParameters{},
+		},
+	}, nil
+}
@@ -0,0 +1,59 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"os"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	ob "github.com/Azure/open-service-broker-azure/pkg/azure/orbital"
+	"github.com/Azure/open-service-broker-azure/pkg/services/orbital"
+)
+
+func getOrbitalCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	// A contact profile's endpoints reside in a subnet that must already be
+	// delegated to Azure Orbital
+	subnetID := os.Getenv("TEST_ORBITAL_SUBNET_ID")
+	if subnetID == "" {
+		return nil, nil
+	}
+
+	orbitalManager, err := ob.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module:    orbital.New(armDeployer, orbitalManager),
+			serviceID: "5b0e3f8c-4d0a-4f7e-9a86-2f1c6d9e8b47",
+			planID:    "c3a1d7e2-8f64-4b9c-a0e5-7d2b9f4c1e63",
+			location:  "westus2",
+			provisioningParameters: &orbital.ProvisioningParameters{
+				NORADID:   "27424",
+				TitleLine: "AQUA",
+				TLELine1: "1 27424U 02022A   24001.50000000  .00000000  " +
+					"00000-0  00000-0 0  9997",
+				TLELine2: "2 27424  98.2000 000.0000 0001000 000.0000 " +
+					"000.0000 14.57000000000008",
+				Links: []orbital.Link{
+					{
+						Name:               "downlink",
+						Direction:          "Downlink",
+						Polarization:       "RHCP",
+						CenterFrequencyMHz: 8160,
+						BandwidthMHz:       15,
+						Endpoint: &orbital.Endpoint{
+							IPAddress: "10.0.1.4",
+							Port:      50000,
+						},
+					},
+				},
+				SubnetID: subnetID,
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,34 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	pw "github.com/Azure/open-service-broker-azure/pkg/azure/playwright"
+	"github.com/Azure/open-service-broker-azure/pkg/services/playwright"
+)
+
+func getPlaywrightCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	playwrightManager, err := pw.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module:    playwright.New(armDeployer, playwrightManager),
+			serviceID: "3e7b9c41-5a2d-4f86-b0e3-8d1c6a4f9e27",
+			planID:    "a6d2f08e-4c1b-4b7a-9e35-71f0c8b2d4e6",
+			location:  "eastus",
+			provisioningParameters: &playwright.ProvisioningParameters{
+				Reporting: "Disabled",
+			},
+			bindingParameters: &playwright.BindingParameters{
+				AccessTokenExpiryDays: 1,
+			},
+		},
+	}, nil
+}
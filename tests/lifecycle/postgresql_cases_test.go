@@ -12,7 +12,7 @@ func getPostgresqlCases(
 	armDeployer arm.Deployer,
 	resourceGroup string,
 ) ([]serviceLifecycleTestCase, error) {
-	postgreSQLManager, err := pg.NewManager()
+	postgreSQLManager, err := pg.NewManager("")
 	if err != nil {
 		return nil, err
 	}
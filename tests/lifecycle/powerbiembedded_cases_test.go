@@ -0,0 +1,42 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"os"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	pb "github.com/Azure/open-service-broker-azure/pkg/azure/powerbiembedded"
+	"github.com/Azure/open-service-broker-azure/pkg/services/powerbiembedded"
+)
+
+func getPowerBIEmbeddedCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	// A capacity must be administered by an existing user or service
+	// principal, whose user principal name or object ID must be supplied
+	administrator := os.Getenv("TEST_POWER_BI_EMBEDDED_ADMINISTRATOR")
+	if administrator == "" {
+		return nil, nil
+	}
+
+	powerBIEmbeddedManager, err := pb.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module:    powerbiembedded.New(armDeployer, powerBIEmbeddedManager),
+			serviceID: "db167cc6-6e41-44dd-a5f1-51bf31fcf85c",
+			planID:    "3f093922-62bb-4644-9272-b8fac67b0c65",
+			location:  "eastus",
+			provisioningParameters: &powerbiembedded.ProvisioningParameters{
+				SKU:            "A1",
+				Administrators: []string{administrator},
+			},
+			bindingParameters: &powerbiembedded.BindingParameters{},
+		},
+	}, nil
+}
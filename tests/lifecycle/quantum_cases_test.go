@@ -0,0 +1,53 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"os"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	qt "github.com/Azure/open-service-broker-azure/pkg/azure/quantum"
+	sa "github.com/Azure/open-service-broker-azure/pkg/azure/storage"
+	"github.com/Azure/open-service-broker-azure/pkg/services/quantum"
+)
+
+func getQuantumCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	// Binding assigns a role to an existing principal, whose object ID must be
+	// supplied
+	principalObjectID := os.Getenv("TEST_QUANTUM_PRINCIPAL_OBJECT_ID")
+	if principalObjectID == "" {
+		return nil, nil
+	}
+
+	quantumManager, err := qt.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+	storageManager, err := sa.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module:    quantum.New(armDeployer, quantumManager, storageManager),
+			serviceID: "8d4f6a2e-3c71-4b95-a0e8-6f1b2d7c9e54",
+			planID:    "f3b6c8d1-7e29-4a5f-b4c0-2d9e8a1f6b73",
+			location:  "eastus",
+			provisioningParameters: &quantum.ProvisioningParameters{
+				Providers: []quantum.Provider{
+					{
+						ID:      "ionq",
+						Targets: []string{"ionq.simulator"},
+					},
+				},
+			},
+			bindingParameters: &quantum.BindingParameters{
+				PrincipalID: principalObjectID,
+			},
+		},
+	}, nil
+}
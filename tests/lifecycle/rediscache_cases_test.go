@@ -12,7 +12,7 @@ func getRediscacheCases(
 	armDeployer arm.Deployer,
 	resourceGroup string,
 ) ([]serviceLifecycleTestCase, error) {
-	redisManager, err := rc.NewManager()
+	redisManager, err := rc.NewManager("")
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,32 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	re "github.com/Azure/open-service-broker-azure/pkg/azure/redisenterprise"
+	"github.com/Azure/open-service-broker-azure/pkg/services/redisenterprise"
+)
+
+func getRedisEnterpriseCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	clusterManager, err := re.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module:    redisenterprise.New(armDeployer, clusterManager),
+			serviceID: "e1f6e893-fa7c-4369-aec0-e229d925de37",
+			planID:    "235b1d72-69a1-462c-b6b4-4c29c976367f",
+			location:  "eastus",
+			provisioningParameters: &redisenterprise.ProvisioningParameters{
+				Modules: []string{"RediSearch", "RedisJSON"},
+			},
+			bindingParameters: &redisenterprise.BindingParameters{},
+		},
+	}, nil
+}
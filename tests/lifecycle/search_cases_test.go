@@ -12,7 +12,7 @@ func getSearchCases(
 	armDeployer arm.Deployer,
 	resourceGroup string,
 ) ([]serviceLifecycleTestCase, error) {
-	searchManager, err := as.NewManager()
+	searchManager, err := as.NewManager("")
 	if err != nil {
 		return nil, err
 	}
@@ -12,7 +12,7 @@ func getServicebusCases(
 	armDeployer arm.Deployer,
 	resourceGroup string,
 ) ([]serviceLifecycleTestCase, error) {
-	serviceBusManager, err := sb.NewManager()
+	serviceBusManager, err := sb.NewManager("")
 	if err != nil {
 		return nil, err
 	}
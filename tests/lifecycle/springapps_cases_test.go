@@ -0,0 +1,45 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"os"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	spa "github.com/Azure/open-service-broker-azure/pkg/azure/springapps"
+	"github.com/Azure/open-service-broker-azure/pkg/services/springapps"
+)
+
+func getSpringAppsCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	// Binding assigns a role to an existing principal, whose object ID must be
+	// supplied
+	principalObjectID := os.Getenv("TEST_SPRING_APPS_PRINCIPAL_OBJECT_ID")
+	if principalObjectID == "" {
+		return nil, nil
+	}
+
+	springAppsManager, err := spa.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module:    springapps.New(armDeployer, springAppsManager),
+			serviceID: "3d7a9e25-4b1c-4f68-8e03-c6b2f15a9d47",
+			planID:    "a6f1c83e-92d4-4b7a-b5e0-7d3c8f2e1b94",
+			location:  "eastus",
+			provisioningParameters: &springapps.ProvisioningParameters{
+				App: &springapps.App{
+					Name: "gateway",
+				},
+			},
+			bindingParameters: &springapps.BindingParameters{
+				PrincipalID: principalObjectID,
+			},
+		},
+	}, nil
+}
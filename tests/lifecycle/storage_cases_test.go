@@ -12,7 +12,7 @@ func getStorageCases(
 	armDeployer arm.Deployer,
 	resourceGroup string,
 ) ([]serviceLifecycleTestCase, error) {
-	storageManager, err := sa.NewManager()
+	storageManager, err := sa.NewManager("")
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,73 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"os"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	asa "github.com/Azure/open-service-broker-azure/pkg/azure/streamanalytics"
+	"github.com/Azure/open-service-broker-azure/pkg/services/streamanalytics"
+)
+
+func getStreamAnalyticsCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	// A job needs somewhere to read events from and write results to; an
+	// existing storage account serves as both
+	accountName := os.Getenv("TEST_STREAM_ANALYTICS_STORAGE_ACCOUNT_NAME")
+	accountKey := os.Getenv("TEST_STREAM_ANALYTICS_STORAGE_ACCOUNT_KEY")
+	if accountName == "" || accountKey == "" {
+		return nil, nil
+	}
+
+	streamAnalyticsManager, err := asa.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	getBlobDatasource := func(container string) map[string]interface{} {
+		return map[string]interface{}{
+			"type": "Microsoft.Storage/Blob",
+			"properties": map[string]interface{}{
+				"storageAccounts": []interface{}{
+					map[string]interface{}{
+						"accountName": accountName,
+						"accountKey":  accountKey,
+					},
+				},
+				"container":   container,
+				"pathPattern": "{date}",
+				"dateFormat":  "yyyy/MM/dd",
+			},
+		}
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module:    streamanalytics.New(armDeployer, streamAnalyticsManager),
+			serviceID: "5e3b8f1a-94c2-4d7e-b6a0-3f2c1d9e8a47",
+			planID:    "b8d4a2e6-1c7f-4f39-8e5b-0a6d3c9f2e71",
+			location:  "eastus",
+			provisioningParameters: &streamanalytics.ProvisioningParameters{
+				StreamingUnits: 1,
+				Query:          "SELECT * INTO [output] FROM [input]",
+				Inputs: []streamanalytics.JobInput{
+					{
+						Name:       "input",
+						Datasource: getBlobDatasource("input"),
+					},
+				},
+				Outputs: []streamanalytics.JobOutput{
+					{
+						Name:       "output",
+						Datasource: getBlobDatasource("output"),
+					},
+				},
+				JobState: "Running",
+			},
+			bindingParameters: &streamanalytics.BindingParameters{},
+		},
+	}, nil
+}
@@ -11,7 +11,7 @@ import (
 )
 
 func getTestCases(resourceGroup string) ([]serviceLifecycleTestCase, error) {
-	armDeployer, err := arm.NewDeployer()
+	armDeployer, err := arm.NewDeployer("")
 	if err != nil {
 		return nil, err
 	}
@@ -24,15 +24,52 @@ func getTestCases(resourceGroup string) ([]serviceLifecycleTestCase, error) {
 	) ([]serviceLifecycleTestCase, error){
 		getRediscacheCases,
 		getACICases,
+		getArcCases,
+		getBackupCases,
+		getBastionCases,
+		getChaosStudioCases,
+		getCommunicationCases,
+		getContainerAppsCases,
 		getCosmosdbCases,
+		getDevTestLabsCases,
+		getDigitalTwinsCases,
+		getDMSCases,
+		getElasticSANCases,
 		getEventhubCases,
+		getFHIRCases,
+		getFluidRelayCases,
 		getKeyvaultCases,
+		getKustoCases,
+		getLoadBalancerCases,
+		getLoadTestingCases,
+		getManagedHSMCases,
+		getManagedLustreCases,
+		getMediaServicesCases,
 		getMssqlCases,
 		getMysqlCases,
 		getPostgresqlCases,
+		getPowerBIEmbeddedCases,
 		getSearchCases,
 		getServicebusCases,
 		getStorageCases,
+		getStreamAnalyticsCases,
+		getVirtualMachineCases,
+		getWebPubSubCases,
+		getDevBoxCases,
+		getOrbitalCases,
+		getQuantumCases,
+		getIoTDPSCases,
+		getAutomationCases,
+		getConfidentialLedgerCases,
+		getMonitorWorkspaceCases,
+		getNetAppFilesCases,
+		getFirewallPolicyCases,
+		getSpringAppsCases,
+		getMongoVCoreCases,
+		getRedisEnterpriseCases,
+		getTrustedSigningCases,
+		getPlaywrightCases,
+		getMaintenanceCases,
 	}
 
 	testFilters := getTestFilters()
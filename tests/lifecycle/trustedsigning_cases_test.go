@@ -0,0 +1,48 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"os"
+
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	ts "github.com/Azure/open-service-broker-azure/pkg/azure/trustedsigning"
+	"github.com/Azure/open-service-broker-azure/pkg/services/trustedsigning"
+)
+
+func getTrustedSigningCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	// A certificate profile can only be created for an identity that was
+	// validated beforehand, and binding assigns a role to an existing
+	// principal, so both must be supplied
+	identityValidationID :=
+		os.Getenv("TEST_TRUSTED_SIGNING_IDENTITY_VALIDATION_ID")
+	principalObjectID := os.Getenv("TEST_TRUSTED_SIGNING_PRINCIPAL_OBJECT_ID")
+	if identityValidationID == "" || principalObjectID == "" {
+		return nil, nil
+	}
+
+	trustedSigningManager, err := ts.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module:    trustedsigning.New(armDeployer, trustedSigningManager),
+			serviceID: "8c3f5a1d-6e2b-4d97-a0f4-b19e7c52d830",
+			planID:    "e4a72c96-0b3d-4f18-9c5e-2d7b6f1a8e43",
+			location:  "eastus",
+			provisioningParameters: &trustedsigning.ProvisioningParameters{
+				CertificateProfile: &trustedsigning.CertificateProfile{
+					IdentityValidationID: identityValidationID,
+				},
+			},
+			bindingParameters: &trustedsigning.BindingParameters{
+				PrincipalID: principalObjectID,
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,64 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	"github.com/Azure/open-service-broker-azure/pkg/azure/metrics"
+	vm "github.com/Azure/open-service-broker-azure/pkg/azure/virtualmachine"
+	"github.com/Azure/open-service-broker-azure/pkg/services/virtualmachine"
+)
+
+func getVirtualMachineCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	virtualMachineManager, err := vm.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+	metricsManager, err := metrics.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	return []serviceLifecycleTestCase{
+		{ // Linux virtual machine in a virtual network of its own
+			module: virtualmachine.New(
+				armDeployer,
+				virtualMachineManager,
+				metricsManager,
+			),
+			serviceID: "8c731216-4fdd-4687-8623-6403a7cb9201",
+			planID:    "3c572919-720f-4d9e-a217-564f0744b000",
+			location:  "southcentralus",
+			provisioningParameters: &virtualmachine.ProvisioningParameters{
+				Size: "Standard_B1s",
+			},
+			bindingParameters: &virtualmachine.BindingParameters{},
+		},
+		{ // Windows virtual machine without a public IP address
+			module: virtualmachine.New(
+				armDeployer,
+				virtualMachineManager,
+				metricsManager,
+			),
+			serviceID: "8c731216-4fdd-4687-8623-6403a7cb9201",
+			planID:    "3c572919-720f-4d9e-a217-564f0744b000",
+			location:  "southcentralus",
+			provisioningParameters: &virtualmachine.ProvisioningParameters{
+				Image: &virtualmachine.ImageParameters{
+					Publisher: "MicrosoftWindowsServer",
+					Offer:     "WindowsServer",
+					SKU:       "2019-Datacenter",
+					OSType:    "Windows",
+				},
+				OSDisk: &virtualmachine.OSDiskParameters{
+					SizeGB: 128,
+				},
+				DisablePublicIPAddress: true,
+			},
+			bindingParameters: &virtualmachine.BindingParameters{},
+		},
+	}, nil
+}
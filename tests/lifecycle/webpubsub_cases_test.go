@@ -0,0 +1,35 @@
+// +build !unit
+
+package lifecycle
+
+import (
+	"github.com/Azure/open-service-broker-azure/pkg/azure/arm"
+	wp "github.com/Azure/open-service-broker-azure/pkg/azure/webpubsub"
+	"github.com/Azure/open-service-broker-azure/pkg/services/webpubsub"
+)
+
+func getWebPubSubCases(
+	armDeployer arm.Deployer,
+	resourceGroup string,
+) ([]serviceLifecycleTestCase, error) {
+	webPubSubManager, err := wp.NewManager("")
+	if err != nil {
+		return nil, err
+	}
+
+	return []serviceLifecycleTestCase{
+		{
+			module:    webpubsub.New(armDeployer, webPubSubManager),
+			serviceID: "29147d8e-5ccd-4aa0-b4b1-2491b966c240",
+			planID:    "604bea5f-d52c-4916-a334-f8ceea2539bc",
+			location:  "eastus",
+			provisioningParameters: &webpubsub.ProvisioningParameters{
+				SKU:       "Free_F1",
+				UnitCount: 1,
+			},
+			bindingParameters: &webpubsub.BindingParameters{
+				Hub: "lifecycle",
+			},
+		},
+	}, nil
+}